@@ -0,0 +1,163 @@
+"""
+Agent Deck client: a thin wrapper around the stubs generated from
+proto/agentdeck/v1/deck.proto ("make clients").
+
+    from agentdeck_client import DeckClient
+
+    with DeckClient("127.0.0.1:8421", token="my-secret") as deck:
+        for item in deck.menu().items:
+            if item.HasField("session"):
+                print(item.session.title, item.session.status)
+        deck.send("api-server-id", "run the tests")
+
+With an ``OfflineQueue``, ``submit`` queues batches while the server is
+unreachable and ``flush_queue`` replays them once it is back:
+
+    deck = DeckClient(queue=OfflineQueue("default"))
+    deck.submit([pb.SessionOp(op="stop", id="api-server-id")])  # None if queued
+    deck.flush_queue()
+
+The generated messages are re-exported as ``agentdeck_client.pb`` for
+building requests and reading responses.
+"""
+
+from typing import Iterator, List, Optional, Sequence
+
+import grpc
+
+from agentdeck.v1 import deck_pb2 as pb
+from agentdeck.v1 import deck_pb2_grpc
+
+from .offline import OfflineQueue, QueuedBatch, new_request_id
+
+__all__ = ["DeckClient", "OfflineQueue", "PROTOCOL_VERSION", "QueuedBatch", "new_request_id", "pb"]
+
+# Status codes meaning the server could not be reached: submit queues these.
+_UNREACHABLE = (grpc.StatusCode.UNAVAILABLE, grpc.StatusCode.DEADLINE_EXCEEDED)
+
+# PROTOCOL_VERSION is the web API protocol version this client speaks.
+PROTOCOL_VERSION = 1
+
+
+class DeckClient:
+    """Client for ``agent-deck web --grpc-listen``.
+
+    ``token`` is the server's --token, sent as "authorization: Bearer" metadata.
+    ``require`` lists capabilities every call needs; a server lacking one fails
+    the call with UNIMPLEMENTED instead of running it. Pass ``credentials`` (grpc.ssl_channel_credentials()) when the server is
+    behind TLS; the default is a plaintext channel. ``queue`` is where
+    ``submit`` keeps batches while the server is unreachable.
+    """
+
+    def __init__(
+        self,
+        target: str = "127.0.0.1:8421",
+        token: Optional[str] = None,
+        require: Sequence[str] = (),
+        credentials: Optional[grpc.ChannelCredentials] = None,
+        timeout: Optional[float] = 10.0,
+        queue: Optional[OfflineQueue] = None,
+    ):
+        if credentials is None:
+            self._channel = grpc.insecure_channel(target)
+        else:
+            self._channel = grpc.secure_channel(target, credentials)
+        self._stub = deck_pb2_grpc.DeckServiceStub(self._channel)
+        self._metadata = [("x-agent-deck-protocol", str(PROTOCOL_VERSION))]
+        if token:
+            self._metadata.append(("authorization", f"Bearer {token}"))
+        if require:
+            self._metadata.append(("x-agent-deck-require", ",".join(require)))
+        self._timeout = timeout
+        self._capabilities: Optional[frozenset] = None
+        self._queue = queue
+
+    def close(self) -> None:
+        self._channel.close()
+
+    def __enter__(self) -> "DeckClient":
+        return self
+
+    def __exit__(self, *exc) -> None:
+        self.close()
+
+    def health(self) -> pb.HealthResponse:
+        return self._stub.Health(pb.HealthRequest(), metadata=self._metadata, timeout=self._timeout)
+
+    def capabilities(self) -> frozenset:
+        """What the server supports (empty for servers predating negotiation)."""
+        if self._capabilities is None:
+            self._capabilities = frozenset(self.health().capabilities)
+        return self._capabilities
+
+    def supports(self, capability: str) -> bool:
+        return capability in self.capabilities()
+
+    def menu(self) -> pb.MenuSnapshot:
+        return self._stub.GetMenu(pb.GetMenuRequest(), metadata=self._metadata, timeout=self._timeout).snapshot
+
+    def session(self, session_id: str) -> pb.GetSessionResponse:
+        return self._stub.GetSession(
+            pb.GetSessionRequest(id=session_id), metadata=self._metadata, timeout=self._timeout
+        )
+
+    def watch_menu(self) -> Iterator[pb.MenuSnapshot]:
+        """Yield the menu snapshot, then a new one whenever it changes."""
+        for resp in self._stub.WatchMenu(pb.WatchMenuRequest(), metadata=self._metadata):
+            yield resp.snapshot
+
+    def batch(self, ops: Sequence[pb.SessionOp], request_id: str = "") -> pb.BatchResponse:
+        """Run several ops in one call; one failing op does not abort the rest.
+
+        A batch repeating the ``request_id`` of one already applied returns
+        that batch's response (``replayed`` set) instead of running again.
+        """
+        return self._stub.Batch(
+            pb.BatchRequest(ops=ops, request_id=request_id), metadata=self._metadata, timeout=self._timeout
+        )
+
+    def submit(self, ops: Sequence[pb.SessionOp]) -> Optional[pb.BatchResponse]:
+        """Run a batch after replaying queued ones, or queue it when the server
+        is unreachable and return None. Needs a ``queue``."""
+        if self._queue is None:
+            raise ValueError("submit needs a DeckClient created with queue=")
+        request_id = new_request_id()
+        try:
+            self.flush_queue()
+            return self.batch(ops, request_id=request_id)
+        except grpc.RpcError as err:
+            if err.code() not in _UNREACHABLE:
+                raise
+        self._queue.enqueue(ops, request_id=request_id)
+        return None
+
+    def flush_queue(self) -> List[pb.BatchResponse]:
+        """Replay queued batches, oldest first. Batches the server answered,
+        even with an error, leave the queue; an unreachable server raises and
+        leaves the rest queued."""
+        responses: List[pb.BatchResponse] = []
+        if self._queue is None:
+            return responses
+        for queued in self._queue.list():
+            try:
+                responses.append(self.batch(queued.ops, request_id=queued.request_id))
+            except grpc.RpcError as err:
+                if err.code() in _UNREACHABLE:
+                    raise
+            self._queue.remove(queued.request_id)
+        return responses
+
+    def stop(self, session_id: str) -> pb.BatchResult:
+        return self._one(pb.SessionOp(op="stop", id=session_id))
+
+    def restart(self, session_id: str) -> pb.BatchResult:
+        return self._one(pb.SessionOp(op="restart", id=session_id))
+
+    def send(self, session_id: str, text: str) -> pb.BatchResult:
+        return self._one(pb.SessionOp(op="send", id=session_id, text=text))
+
+    def macro(self, session_id: str, macro: str) -> pb.BatchResult:
+        return self._one(pb.SessionOp(op="macro", id=session_id, macro=macro))
+
+    def _one(self, op: pb.SessionOp) -> pb.BatchResult:
+        return self.batch([op]).results[0]
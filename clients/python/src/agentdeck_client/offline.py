@@ -0,0 +1,87 @@
+"""
+Offline queue for batches submitted while the server is unreachable.
+
+The queue uses the same files as "agent-deck queue": one JSON file per batch
+in ~/.agent-deck/profiles/<profile>/offline-queue, named after the batch's
+request ID. The server replays them when it starts, and so do
+``DeckClient.flush_queue`` and "agent-deck queue flush"; the request ID makes
+a batch replayed twice apply once.
+"""
+
+import json
+import os
+import secrets
+import time
+from datetime import datetime, timezone
+from typing import List, Optional, Sequence
+
+from google.protobuf import json_format
+
+from agentdeck.v1 import deck_pb2 as pb
+
+__all__ = ["OfflineQueue", "QueuedBatch", "new_request_id"]
+
+
+def new_request_id() -> str:
+    """A request ID that sorts by creation time and won't collide across
+    processes or machines (same format as the Go client)."""
+    return f"{time.time_ns():016x}-{secrets.token_hex(8)}"
+
+
+class QueuedBatch:
+    def __init__(self, request_id: str, queued_at: str, ops: List[pb.SessionOp]):
+        self.request_id = request_id
+        self.queued_at = queued_at
+        self.ops = ops
+
+
+class OfflineQueue:
+    def __init__(self, profile: str = "default", directory: Optional[str] = None):
+        if directory is None:
+            directory = os.path.join(
+                os.path.expanduser("~"), ".agent-deck", "profiles", profile, "offline-queue"
+            )
+        self.directory = directory
+
+    def enqueue(self, ops: Sequence[pb.SessionOp], request_id: Optional[str] = None) -> str:
+        request_id = request_id or new_request_id()
+        os.makedirs(self.directory, mode=0o700, exist_ok=True)
+        entry = {
+            "id": request_id,
+            "queuedAt": datetime.now(timezone.utc).isoformat().replace("+00:00", "Z"),
+            "ops": [json_format.MessageToDict(op) for op in ops],
+        }
+        path = os.path.join(self.directory, request_id + ".json")
+        # Written aside and renamed so concurrent replays never read half a file
+        tmp = f"{path}.tmp.{os.getpid()}"
+        with open(tmp, "w", encoding="utf-8") as f:
+            json.dump(entry, f, indent=2)
+        os.chmod(tmp, 0o600)
+        os.replace(tmp, path)
+        return request_id
+
+    def list(self) -> List[QueuedBatch]:
+        """Queued batches, oldest first."""
+        try:
+            names = os.listdir(self.directory)
+        except FileNotFoundError:
+            return []
+        batches = []
+        for name in names:
+            if not name.endswith(".json") or name.startswith("."):
+                continue
+            try:
+                with open(os.path.join(self.directory, name), encoding="utf-8") as f:
+                    entry = json.load(f)
+            except (OSError, ValueError):
+                continue
+            ops = [json_format.ParseDict(op, pb.SessionOp()) for op in entry.get("ops", [])]
+            batches.append(QueuedBatch(entry["id"], entry.get("queuedAt", ""), ops))
+        batches.sort(key=lambda b: b.request_id)
+        return batches
+
+    def remove(self, request_id: str) -> None:
+        try:
+            os.remove(os.path.join(self.directory, request_id + ".json"))
+        except FileNotFoundError:
+            pass  # Another client replayed it first
@@ -0,0 +1,159 @@
+// Agent Deck client: a thin wrapper around the code generated from
+// proto/agentdeck/v1/deck.proto ("make clients").
+//
+//   const deck = createDeckClient({ baseUrl: "http://127.0.0.1:8421", token: "my-secret" });
+//   const menu = await deck.menu();
+//   for await (const snapshot of deck.watchMenu()) { ... }
+//
+// With a queue, submit queues batches while the server is unreachable and
+// flushQueue replays them once it is back:
+//
+//   const deck = createDeckClient({ baseUrl, queue: new OfflineQueue("default") });
+//   await deck.submit([{ op: "stop", id: "api-server-id" }]); // undefined if queued
+
+import type { MessageInitShape } from "@bufbuild/protobuf";
+import { Code, ConnectError, createClient, type Client, type Interceptor } from "@connectrpc/connect";
+import { createGrpcTransport } from "@connectrpc/connect-node";
+
+import {
+  DeckService,
+  SessionOpSchema,
+  type BatchResponse,
+  type BatchResult,
+  type GetSessionResponse,
+  type HealthResponse,
+  type MenuSnapshot,
+} from "./gen/agentdeck/v1/deck_pb.js";
+
+import { newRequestId, type OfflineQueue, type QueuedOp } from "./offline.js";
+
+export * from "./gen/agentdeck/v1/deck_pb.js";
+export * from "./offline.js";
+
+// PROTOCOL_VERSION is the web API protocol version this client speaks.
+export const PROTOCOL_VERSION = 1;
+
+export type SessionOpInit = MessageInitShape<typeof SessionOpSchema>;
+
+export interface DeckClientOptions {
+  // baseUrl is the --grpc-listen address, e.g. "http://127.0.0.1:8421"
+  // ("https://" when the server is behind TLS).
+  baseUrl: string;
+  // token is the server's --token, sent as "authorization: Bearer" metadata.
+  token?: string;
+  // require lists capabilities every call needs; a server lacking one fails
+  // the call with Code.Unimplemented instead of running it.
+  require?: string[];
+  // timeoutMs applies to every call but watchMenu (default 10s).
+  timeoutMs?: number;
+  // queue is where submit keeps batches while the server is unreachable.
+  queue?: OfflineQueue;
+}
+
+export interface DeckClient {
+  // service is the generated client, for calls the helpers don't cover.
+  service: Client<typeof DeckService>;
+  health(): Promise<HealthResponse>;
+  // supports reports whether the server advertises a capability (servers
+  // predating negotiation advertise none).
+  supports(capability: string): Promise<boolean>;
+  menu(): Promise<MenuSnapshot>;
+  session(id: string): Promise<GetSessionResponse>;
+  // watchMenu yields the menu snapshot, then a new one whenever it changes.
+  watchMenu(signal?: AbortSignal): AsyncIterable<MenuSnapshot>;
+  // batch runs several ops in one call; one failing op does not abort the rest.
+  // A batch repeating the requestId of one already applied returns that
+  // batch's response (replayed set) instead of running again.
+  batch(ops: SessionOpInit[], requestId?: string): Promise<BatchResponse>;
+  // submit runs a batch after replaying queued ones, or queues it when the
+  // server is unreachable and resolves to undefined. Needs a queue.
+  submit(ops: QueuedOp[]): Promise<BatchResponse | undefined>;
+  // flushQueue replays queued batches, oldest first. Batches the server
+  // answered, even with an error, leave the queue; an unreachable server
+  // rejects and leaves the rest queued.
+  flushQueue(): Promise<BatchResponse[]>;
+  stop(id: string): Promise<BatchResult>;
+  restart(id: string): Promise<BatchResult>;
+  send(id: string, text: string): Promise<BatchResult>;
+  macro(id: string, macro: string): Promise<BatchResult>;
+}
+
+export function createDeckClient(options: DeckClientOptions): DeckClient {
+  const interceptors: Interceptor[] = [
+    (next) => (req) => {
+      req.header.set("x-agent-deck-protocol", String(PROTOCOL_VERSION));
+      if (options.token) {
+        req.header.set("authorization", `Bearer ${options.token}`);
+      }
+      if (options.require?.length) {
+        req.header.set("x-agent-deck-require", options.require.join(","));
+      }
+      return next(req);
+    },
+  ];
+  const service = createClient(DeckService, createGrpcTransport({ baseUrl: options.baseUrl, interceptors }));
+  const timeoutMs = options.timeoutMs ?? 10_000;
+  let capabilities: Promise<Set<string>> | undefined;
+
+  const batch = (ops: SessionOpInit[], requestId = "") => service.batch({ ops, requestId }, { timeoutMs });
+  const one = async (op: SessionOpInit) => (await batch([op])).results[0];
+
+  const flushQueue = async () => {
+    const responses: BatchResponse[] = [];
+    for (const queued of (await options.queue?.list()) ?? []) {
+      try {
+        responses.push(await batch(queued.ops, queued.id));
+      } catch (err) {
+        if (unreachable(err)) throw err;
+      }
+      await options.queue!.remove(queued.id);
+    }
+    return responses;
+  };
+
+  return {
+    service,
+    health: () => service.health({}, { timeoutMs }),
+    supports: async (capability) => {
+      capabilities ??= service.health({}, { timeoutMs }).then((h) => new Set(h.capabilities));
+      try {
+        return (await capabilities).has(capability);
+      } catch (err) {
+        capabilities = undefined; // Retry on the next call
+        throw err;
+      }
+    },
+    menu: async () => (await service.getMenu({}, { timeoutMs })).snapshot!,
+    session: (id) => service.getSession({ id }, { timeoutMs }),
+    watchMenu: async function* (signal) {
+      for await (const resp of service.watchMenu({}, { signal })) {
+        yield resp.snapshot!;
+      }
+    },
+    batch,
+    submit: async (ops) => {
+      if (!options.queue) throw new Error("submit needs a client created with a queue");
+      const requestId = newRequestId();
+      try {
+        await flushQueue();
+        return await batch(ops, requestId);
+      } catch (err) {
+        if (!unreachable(err)) throw err;
+      }
+      await options.queue.enqueue(ops, requestId);
+      return undefined;
+    },
+    flushQueue,
+    stop: (id) => one({ op: "stop", id }),
+    restart: (id) => one({ op: "restart", id }),
+    send: (id, text) => one({ op: "send", id, text }),
+    macro: (id, macro) => one({ op: "macro", id, macro }),
+  };
+}
+
+// unreachable reports whether a call failed because the server could not be
+// reached; submit queues these.
+function unreachable(err: unknown): boolean {
+  const code = ConnectError.from(err).code;
+  return code === Code.Unavailable || code === Code.DeadlineExceeded;
+}
@@ -0,0 +1,77 @@
+// Offline queue for batches submitted while the server is unreachable.
+//
+// The queue uses the same files as "agent-deck queue": one JSON file per
+// batch in ~/.agent-deck/profiles/<profile>/offline-queue, named after the
+// batch's request ID. The server replays them when it starts, and so do
+// DeckClient.flushQueue and "agent-deck queue flush"; the request ID makes a
+// batch replayed twice apply once.
+
+import { randomBytes } from "node:crypto";
+import { mkdir, readFile, readdir, rename, rm, writeFile } from "node:fs/promises";
+import { homedir } from "node:os";
+import { join } from "node:path";
+
+export interface QueuedOp {
+  op: string;
+  id: string;
+  text?: string;
+  macro?: string;
+}
+
+export interface QueuedBatch {
+  id: string;
+  queuedAt: string;
+  ops: QueuedOp[];
+}
+
+// newRequestId returns a request ID that sorts by creation time and won't
+// collide across processes or machines (same format as the Go client).
+export function newRequestId(): string {
+  const nanos = BigInt(Date.now()) * 1_000_000n;
+  return `${nanos.toString(16).padStart(16, "0")}-${randomBytes(8).toString("hex")}`;
+}
+
+export class OfflineQueue {
+  readonly directory: string;
+
+  constructor(profile = "default", directory?: string) {
+    this.directory = directory ?? join(homedir(), ".agent-deck", "profiles", profile, "offline-queue");
+  }
+
+  async enqueue(ops: QueuedOp[], requestId = newRequestId()): Promise<string> {
+    await mkdir(this.directory, { recursive: true, mode: 0o700 });
+    const entry: QueuedBatch = { id: requestId, queuedAt: new Date().toISOString(), ops };
+    const path = join(this.directory, `${requestId}.json`);
+    // Written aside and renamed so concurrent replays never read half a file
+    const tmp = `${path}.tmp.${process.pid}`;
+    await writeFile(tmp, JSON.stringify(entry, null, 2), { mode: 0o600 });
+    await rename(tmp, path);
+    return requestId;
+  }
+
+  // list returns the queued batches, oldest first.
+  async list(): Promise<QueuedBatch[]> {
+    let names: string[];
+    try {
+      names = await readdir(this.directory);
+    } catch (err) {
+      if ((err as NodeJS.ErrnoException).code === "ENOENT") return [];
+      throw err;
+    }
+    const batches: QueuedBatch[] = [];
+    for (const name of names) {
+      if (!name.endsWith(".json") || name.startsWith(".")) continue;
+      try {
+        batches.push(JSON.parse(await readFile(join(this.directory, name), "utf8")) as QueuedBatch);
+      } catch {
+        // Skip unreadable entries
+      }
+    }
+    return batches.sort((a, b) => (a.id < b.id ? -1 : a.id > b.id ? 1 : 0));
+  }
+
+  // remove drops a batch; one already gone was replayed by another client.
+  async remove(requestId: string): Promise<void> {
+    await rm(join(this.directory, `${requestId}.json`), { force: true });
+  }
+}
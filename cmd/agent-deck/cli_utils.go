@@ -2,13 +2,16 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
 )
 
 // normalizeArgs reorders args so flags come before positional arguments.
@@ -133,8 +136,21 @@ const (
 	ErrCodeInvalidOperation = "INVALID_OPERATION"
 	ErrCodeGroupNotEmpty    = "GROUP_NOT_EMPTY"
 	ErrCodeMCPNotAvailable  = "MCP_NOT_AVAILABLE"
+	ErrCodeSpawnDenied      = "SPAWN_DENIED"
+	ErrCodeQuotaExceeded    = "QUOTA_EXCEEDED"
+	ErrCodeConflict         = "CONFLICT"
 )
 
+// metaSaveErrCode returns the error code for a failed meta.json save:
+// ErrCodeConflict when another process got there first, so callers know a
+// retry may succeed
+func metaSaveErrCode(err error) string {
+	if errors.Is(err, session.ErrConductorMetaConflict) || errors.Is(err, session.ErrConductorMetaLocked) {
+		return ErrCodeConflict
+	}
+	return ErrCodeInvalidOperation
+}
+
 // ResolveSession finds a session by flexible matching (title, ID prefix, or path)
 // Returns the matched session or nil with an error message
 func ResolveSession(identifier string, instances []*session.Instance) (*session.Instance, string, string) {
@@ -243,20 +259,14 @@ func ResolveSessionOrCurrent(identifier string, instances []*session.Instance) (
 	return ResolveSession(identifier, instances)
 }
 
-// StatusSymbol returns the symbol for a status
+// StatusSymbol returns the symbol for a status, themed by [output] in config
 func StatusSymbol(status session.Status) string {
-	switch status {
-	case session.StatusRunning:
-		return "●"
-	case session.StatusWaiting:
-		return "◐"
-	case session.StatusIdle:
-		return "○"
-	case session.StatusError:
-		return "✕"
-	default:
-		return "?"
-	}
+	return cliStatusTheme().Glyph(status)
+}
+
+// StatusLabel returns "<symbol> text" for a status, or just text in screen-reader mode
+func StatusLabel(status session.Status, text string) string {
+	return cliStatusTheme().Label(status, text)
 }
 
 // StatusString returns the string representation of a status
@@ -270,11 +280,30 @@ func StatusString(status session.Status) string {
 		return "idle"
 	case session.StatusError:
 		return "error"
+	case session.StatusThrottled:
+		return "throttled"
 	default:
 		return "unknown"
 	}
 }
 
+// formatStateDuration formats how long a session has been in its status
+// compactly ("45s", "12m", "3h5m", "2d4h"); "-" when unknown.
+func formatStateDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
 // TruncateID returns a shortened ID for display
 func TruncateID(id string) string {
 	if len(id) > 12 {
@@ -294,3 +323,80 @@ func FormatPath(path string) string {
 	}
 	return path
 }
+
+// resolveStartReason parses a --reason flag value, defaulting to the reason
+// implied by the environment (conductor or manual)
+func resolveStartReason(value string) (kind, ref string, err error) {
+	if value == "" {
+		kind, ref = session.DefaultStartReason()
+		return kind, ref, nil
+	}
+	return session.ParseStartReason(value)
+}
+
+// checkSpawnPolicy applies the [spawn] policy when this command runs inside
+// a conductor session. It returns the spawning conductor ("" outside
+// conductor sessions) to pass to recordSpawn once the session is saved.
+func checkSpawnPolicy(storage *session.Storage, instances []*session.Instance, tool, reason string) (string, error) {
+	conductor := session.SpawningConductor(instances)
+	if conductor == "" {
+		return "", nil
+	}
+	req := session.SpawnRequest{Conductor: conductor, Tool: tool}
+	if kind, ref, err := session.ParseStartReason(reason); err == nil && kind == session.StartReasonTemplate {
+		req.Template = ref
+	}
+	err := session.CheckSpawn(storage.GetDB(), session.GetSpawnPolicySettings(), req, instances, time.Now())
+	if errors.Is(err, session.ErrSpawnDenied) {
+		_ = session.RecordSpawnDenied(storage.GetDB(), err, time.Now())
+	}
+	return conductor, err
+}
+
+// reportSpawnError prints a checkSpawnPolicy error. In JSON mode a used-up
+// quota is reported with its name, limit and usage so callers such as the
+// conductor bridge can back off.
+func reportSpawnError(out *CLIOutput, err error) {
+	var quotaErr *session.QuotaError
+	switch {
+	case errors.As(err, &quotaErr) && out.jsonMode:
+		out.printJSON(map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+			"code":    ErrCodeQuotaExceeded,
+			"quota":   quotaErr,
+		})
+	case errors.Is(err, session.ErrSpawnDenied):
+		out.Error(err.Error(), ErrCodeSpawnDenied)
+	default:
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+	}
+}
+
+// recordSpawn counts a session created by a conductor against its limits
+func recordSpawn(storage *session.Storage, conductor string, inst *session.Instance) {
+	if err := session.RecordSpawn(storage.GetDB(), conductor, inst, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record spawn: %v\n", err)
+	}
+}
+
+// resolveNetworkPolicy parses --network and --allow-host flag values into a
+// stored network policy ("" for open)
+func resolveNetworkPolicy(mode, allowHosts string) (string, error) {
+	return session.ParseNetworkPolicy(mode, splitList(allowHosts))
+}
+
+// recordNetworkPolicy adds a restricted session's network policy to the audit
+// log so reviewers can see which sessions ran sandboxed and how
+func recordNetworkPolicy(storage *session.Storage, inst *session.Instance) {
+	if inst.NetworkPolicy == "" {
+		return
+	}
+	db := storage.GetDB()
+	if db == nil {
+		return
+	}
+	if err := db.AppendAudit(inst.ID, inst.Title, statedb.AuditNetworkPolicy, inst.DescribeNetworkPolicy(), time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record network policy: %v\n", err)
+	}
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"flag"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
 func TestNormalizeArgs(t *testing.T) {
@@ -210,3 +214,42 @@ func TestNormalizeArgsIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatStateDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "-"},
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{3*time.Hour + 5*time.Minute, "3h5m"},
+		{52 * time.Hour, "2d4h"},
+	}
+	for _, tt := range tests {
+		if got := formatStateDuration(tt.d); got != tt.want {
+			t.Errorf("formatStateDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestStatusThemeRendering(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	plain := newStatusTheme(session.OutputSettings{}, false)
+	if got := plain.Glyph(session.StatusRunning); got != "●" {
+		t.Errorf("plain glyph = %q, want uncolored ●", got)
+	}
+
+	colored := newStatusTheme(session.OutputSettings{Color: "always"}, false)
+	if got := colored.Glyph(session.StatusRunning); got == "●" || !strings.Contains(got, "●") || !strings.Contains(got, "\x1b[") {
+		t.Errorf("color = always should wrap glyph in ANSI codes, got %q", got)
+	}
+
+	reader := newStatusTheme(session.OutputSettings{ScreenReader: true}, true)
+	if got := reader.Label(session.StatusWaiting, "waiting"); got != "waiting" {
+		t.Errorf("screen reader label = %q, want plain word", got)
+	}
+	if got := plain.Label(session.StatusWaiting, "waiting"); got != "◐ waiting" {
+		t.Errorf("label = %q, want glyph and word", got)
+	}
+}
@@ -3,17 +3,24 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/logging"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
+var heartbeatLog = logging.ForComponent(logging.CompHeartbeat)
+
 // handleConductor dispatches conductor subcommands
 func handleConductor(profile string, args []string) {
 	if len(args) == 0 {
@@ -30,6 +37,36 @@ func handleConductor(profile string, args []string) {
 		handleConductorStatus(profile, args[1:])
 	case "list":
 		handleConductorList(profile, args[1:])
+	case "report":
+		handleConductorReport(profile, args[1:])
+	case "history":
+		handleConductorHistory(args[1:])
+	case "heartbeat-run":
+		handleConductorHeartbeatRun(args[1:])
+	case "fleet":
+		handleConductorFleet(profile, args[1:])
+	case "standup":
+		handleConductorStandup(args[1:])
+	case "reconcile":
+		handleConductorReconcile(args[1:])
+	case "schedule":
+		handleConductorSchedule(args[1:])
+	case "identity":
+		handleConductorIdentity(args[1:])
+	case "runbook":
+		handleConductorRunbook(args[1:])
+	case "notify":
+		handleConductorNotify(args[1:])
+	case "skills":
+		handleConductorSkills(args[1:])
+	case "group":
+		handleConductorGroup(args[1:])
+	case "task":
+		handleConductorTask(args[1:])
+	case "registry":
+		handleConductorRegistry(args[1:])
+	case "bridge":
+		handleConductorBridge(args[1:])
 	case "help", "--help", "-h":
 		printConductorHelp()
 	default:
@@ -62,6 +99,21 @@ func runAutoMigration(jsonOutput bool) {
 	}
 }
 
+// syncHeartbeatTimers reinstalls heartbeat timers whose settings changed
+// (e.g. [conductor] heartbeat_intervals) and prints what it updated
+func syncHeartbeatTimers(jsonOutput bool) {
+	updated, err := session.SyncHeartbeatTimers()
+	if jsonOutput {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: heartbeat timer update failed: %v\n", err)
+	}
+	for _, name := range updated {
+		fmt.Printf("  [updated] Heartbeat timer: %s\n", name)
+	}
+}
+
 // parseConductorSetupArgs parses setup flags and returns the conductor name and any extra positional args.
 func parseConductorSetupArgs(fs *flag.FlagSet, args []string) (string, []string, error) {
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -84,6 +136,9 @@ func handleConductorSetup(profile string, args []string) {
 	policyMD := fs.String("policy-md", "", "Custom POLICY.md for this conductor (e.g., ~/docs/my-policy.md)")
 	sharedClaudeMD := fs.String("shared-claude-md", "", "Custom path for shared CLAUDE.md (e.g., ~/docs/conductor-shared.md)")
 	sharedPolicyMD := fs.String("shared-policy-md", "", "Custom path for shared POLICY.md (e.g., ~/docs/conductor-policy.md)")
+	timezone := fs.String("timezone", "", "IANA timezone for this conductor's times (e.g., Europe/Berlin)")
+	language := fs.String("language", "", "Language for the conductor's replies and reports (e.g., German)")
+	heartbeatSchedule := fs.String("heartbeat-schedule", "", "Heartbeat schedule instead of a fixed interval: OnCalendar or cron (e.g., \"5 9-17 * * 1-5\")")
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 
 	fs.Usage = func() {
@@ -102,12 +157,19 @@ func handleConductorSetup(profile string, args []string) {
 		fmt.Println("        Enable heartbeat for this conductor (default)")
 		fmt.Println("  -no-heartbeat")
 		fmt.Println("        Disable heartbeat for this conductor")
+		fmt.Println("  -heartbeat-schedule string")
+		fmt.Println("        Heartbeat schedule instead of a fixed interval: OnCalendar or cron (e.g., \"5 9-17 * * 1-5\")")
+		fmt.Println("  -timezone string")
+		fmt.Println("        IANA timezone for this conductor's times (e.g., Europe/Berlin)")
+		fmt.Println("  -language string")
+		fmt.Println("        Language for the conductor's replies and reports (e.g., German)")
 		fmt.Println()
 		fmt.Println("Conductor-specific files:")
 		fmt.Println("  -claude-md string")
 		fmt.Println("        Custom CLAUDE.md for this conductor (e.g., ~/docs/conductor-ryan.md)")
 		fmt.Println("  -policy-md string")
 		fmt.Println("        Custom POLICY.md for this conductor (e.g., ~/docs/my-policy.md)")
+		fmt.Println("  Both also accept a registry template, e.g. registry://sre-oncall@v2")
 		fmt.Println()
 		fmt.Println("Shared files (all conductors):")
 		fmt.Println("  -shared-claude-md string")
@@ -139,6 +201,20 @@ func handleConductorSetup(profile string, args []string) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if err := session.ValidateTimezone(*timezone); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := session.ValidateResponseLanguage(*language); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *heartbeatSchedule != "" {
+		if _, err := session.ValidateHeartbeatSchedule(*heartbeatSchedule, *timezone); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	resolvedProfile := session.GetEffectiveProfile(profile)
 
 	// Auto-migrate legacy conductors
@@ -295,6 +371,27 @@ func handleConductorSetup(profile string, args []string) {
 		fmt.Fprintf(os.Stderr, "Error setting up conductor %s: %v\n", name, err)
 		os.Exit(1)
 	}
+	if *timezone != "" || *language != "" || *heartbeatSchedule != "" {
+		_, err := session.UpdateConductorMeta(name, func(meta *session.ConductorMeta) error {
+			if *timezone != "" {
+				meta.Timezone = *timezone
+			}
+			if *language != "" {
+				meta.Language = *language
+			}
+			if *heartbeatSchedule != "" {
+				meta.HeartbeatSchedule = *heartbeatSchedule
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving conductor settings for %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		if err := session.SyncConductorIdentity(name); err != nil && !errors.Is(err, session.ErrConductorClaudeMDLinked) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update identity card: %v\n", err)
+		}
+	}
 	if !*jsonOutput {
 		fmt.Printf("  [ok] Directory, CLAUDE.md, and meta.json created\n")
 	}
@@ -333,6 +430,7 @@ func handleConductorSetup(profile string, args []string) {
 	} else {
 		dir, _ := session.ConductorNameDir(name)
 		newInst := session.NewInstanceWithGroupAndTool(sessionTitle, dir, "conductor", "claude")
+		newInst.SetStartReason(session.StartReasonConductor, name)
 		newInst.Command = "claude"
 		instances = append(instances, newInst)
 
@@ -354,13 +452,22 @@ func handleConductorSetup(profile string, args []string) {
 
 	// Step 6: Install heartbeat timer (if heartbeat enabled)
 	if heartbeatEnabled {
-		interval := settings.GetHeartbeatInterval()
+		interval := settings.GetHeartbeatInterval(resolvedProfile)
+		meta, _ := session.LoadConductorMeta(name)
+		if meta != nil {
+			interval = session.HeartbeatIntervalFor(meta)
+		}
+		schedule := session.EffectiveHeartbeatSchedule(meta)
 		if err := session.InstallHeartbeatScript(name, resolvedProfile); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to install heartbeat script: %v\n", err)
-		} else if err := session.InstallHeartbeatDaemon(name, resolvedProfile, interval); err != nil {
+		} else if err := session.InstallHeartbeatDaemon(name, resolvedProfile, interval, schedule); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to install heartbeat daemon: %v\n", err)
 		} else if !*jsonOutput {
-			fmt.Printf("  [ok] Heartbeat timer installed (every %d min)\n", interval)
+			if schedule != "" {
+				fmt.Printf("  [ok] Heartbeat timer installed (%s)\n", schedule)
+			} else {
+				fmt.Printf("  [ok] Heartbeat timer installed (every %d min)\n", interval)
+			}
 		}
 	}
 
@@ -384,7 +491,11 @@ func handleConductorSetup(profile string, args []string) {
 
 		// Install daemon (platform-aware: launchd on macOS, systemd on Linux)
 		daemonPath, err := session.InstallBridgeDaemon()
-		if err != nil {
+		var verifyErr *session.BridgeDaemonError
+		if errors.As(err, &verifyErr) {
+			plistPath = daemonPath
+			fmt.Fprintf(os.Stderr, "Warning: bridge daemon installed but not healthy: %v\n", err)
+		} else if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to install bridge daemon: %v\n", err)
 			condDir, _ := session.ConductorDir()
 			fmt.Fprintf(os.Stderr, "Run manually: python3 %s/bridge.py\n", condDir)
@@ -569,8 +680,11 @@ func handleConductorTeardown(_ string, args []string) {
 			} else if !*jsonOutput {
 				fmt.Printf("  [ok] Removed directory for %s\n", meta.Name)
 			}
+			if err := session.RemoveConductorFromGroups(meta.Name); err != nil && !*jsonOutput {
+				fmt.Fprintf(os.Stderr, "  Warning: failed to remove %s from its groups: %v\n", meta.Name, err)
+			}
 
-			// Remove session from storage
+			// Remove session from storage (kept in trash when enabled)
 			if storage != nil {
 				instances, groups, err := storage.LoadWithGroups()
 				if err == nil {
@@ -578,6 +692,7 @@ func handleConductorTeardown(_ string, args []string) {
 					sessionRemoved := false
 					for _, inst := range instances {
 						if inst.Title == sessionTitle {
+							_ = storage.TrashInstance(inst.ID)
 							sessionRemoved = true
 							continue
 						}
@@ -636,12 +751,15 @@ func handleConductorTeardown(_ string, args []string) {
 func handleConductorStatus(_ string, args []string) {
 	fs := flag.NewFlagSet("conductor status", flag.ExitOnError)
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	all := fs.Bool("all", false, "Show a health table of every conductor: session, timer, last and next heartbeat")
+	absolute := fs.Bool("absolute", false, "Show absolute timestamps instead of relative ones (with --all)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck conductor status [name] [options]")
 		fmt.Println()
 		fmt.Println("Show conductor health status. If name is given, show that conductor only.")
-		fmt.Println("Otherwise show all conductors.")
+		fmt.Println("Otherwise show all conductors. --all prints one table covering every")
+		fmt.Println("conductor directory, whether or not [conductor] is enabled.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -663,6 +781,14 @@ func handleConductorStatus(_ string, args []string) {
 	if err := fs.Parse(normalizeArgs(fs, flagArgs)); err != nil {
 		os.Exit(1)
 	}
+	if *all {
+		if name != "" {
+			fmt.Fprintln(os.Stderr, "Error: --all cannot be combined with a conductor name")
+			os.Exit(1)
+		}
+		printConductorHealth(*jsonOutput, *absolute, nil)
+		return
+	}
 
 	settings := session.GetConductorSettings()
 	if !settings.Enabled {
@@ -677,6 +803,7 @@ func handleConductorStatus(_ string, args []string) {
 
 	// Auto-migrate before status check
 	runAutoMigration(*jsonOutput)
+	syncHeartbeatTimers(*jsonOutput)
 
 	// Get conductors to display
 	var conductors []session.ConductorMeta
@@ -705,6 +832,10 @@ func handleConductorStatus(_ string, args []string) {
 		Running     bool   `json:"running"`
 		Heartbeat   bool   `json:"heartbeat"`
 		Description string `json:"description,omitempty"`
+		// HeartbeatLock is the lease when [conductor] heartbeat_lock is on
+		HeartbeatLock *session.HeartbeatLock `json:"heartbeat_lock,omitempty"`
+		// HeartbeatDaemon is the heartbeat timer's state with its backend
+		HeartbeatDaemon *session.HeartbeatDaemonState `json:"heartbeat_daemon,omitempty"`
 	}
 	var statuses []conductorStatus
 
@@ -716,6 +847,14 @@ func handleConductorStatus(_ string, args []string) {
 			Heartbeat:   meta.HeartbeatEnabled,
 			Description: meta.Description,
 		}
+		if settings.HeartbeatLock {
+			cs.HeartbeatLock, _ = session.ReadHeartbeatLock(meta.Name)
+		}
+		if meta.HeartbeatEnabled {
+			if state, err := session.HeartbeatDaemonStatus(meta.Name); err == nil {
+				cs.HeartbeatDaemon = &state
+			}
+		}
 
 		// Check session
 		sessionTitle := session.ConductorSessionTitle(meta.Name)
@@ -728,7 +867,7 @@ func handleConductorStatus(_ string, args []string) {
 						cs.SessionID = inst.ID
 						cs.SessionDone = true
 						_ = inst.UpdateStatus()
-						cs.Running = inst.Status == session.StatusRunning || inst.Status == session.StatusWaiting || inst.Status == session.StatusIdle
+						cs.Running = inst.Status == session.StatusRunning || inst.Status == session.StatusWaiting || inst.Status == session.StatusIdle || inst.Status == session.StatusThrottled
 						break
 					}
 				}
@@ -779,10 +918,10 @@ func handleConductorStatus(_ string, args []string) {
 			statusIcon = "!"
 			statusText = "no session"
 		case cs.Running:
-			statusIcon = "●"
+			statusIcon = StatusSymbol(session.StatusRunning)
 			statusText = "running"
 		default:
-			statusIcon = "○"
+			statusIcon = StatusSymbol(session.StatusIdle)
 			statusText = "stopped"
 		}
 
@@ -790,6 +929,21 @@ func handleConductorStatus(_ string, args []string) {
 		if !cs.Heartbeat {
 			hb = "off"
 		}
+		if d := cs.HeartbeatDaemon; cs.Heartbeat && d != nil && d.Backend != "" {
+			hb += ", " + d.Backend
+			if !d.Installed {
+				hb += " not installed"
+			} else if !d.Active {
+				hb += " inactive"
+			}
+		}
+		if lock := cs.HeartbeatLock; cs.Heartbeat && lock != nil {
+			if lock.Expired(time.Now()) {
+				hb += ", lock expired"
+			} else {
+				hb += ", lock " + lock.Host
+			}
+		}
 
 		desc := ""
 		if cs.Description != "" {
@@ -806,16 +960,149 @@ func handleConductorStatus(_ string, args []string) {
 	}
 }
 
+// conductorHealth is one row of "conductor status --all"
+type conductorHealth struct {
+	Name            string                        `json:"name"`
+	Profile         string                        `json:"profile"`
+	SessionID       string                        `json:"session_id,omitempty"`
+	SessionStatus   string                        `json:"session_status"` // the session's status, "no session" or "not setup"
+	Heartbeat       bool                          `json:"heartbeat"`
+	HeartbeatDaemon *session.HeartbeatDaemonState `json:"heartbeat_daemon,omitempty"`
+	session.HeartbeatTiming
+}
+
+// printConductorHealth prints every conductor's session, heartbeat timer and
+// last/next heartbeat as one table. A non-nil only narrows it to those
+// conductors, in that order.
+func printConductorHealth(jsonOutput, absolute bool, only []string) {
+	conductors, err := session.ListConductors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing conductors: %v\n", err)
+		os.Exit(1)
+	}
+	if only != nil {
+		byName := make(map[string]session.ConductorMeta, len(conductors))
+		for _, meta := range conductors {
+			byName[meta.Name] = meta
+		}
+		conductors = conductors[:0]
+		for _, name := range only {
+			if meta, ok := byName[name]; ok {
+				conductors = append(conductors, meta)
+			}
+		}
+	}
+
+	now := time.Now()
+	rows := make([]conductorHealth, 0, len(conductors))
+	for _, meta := range conductors {
+		row := conductorHealth{
+			Name:          meta.Name,
+			Profile:       meta.Profile,
+			SessionStatus: "not setup",
+			Heartbeat:     meta.HeartbeatEnabled,
+		}
+		if session.IsConductorSetup(meta.Name) {
+			row.SessionID, row.SessionStatus = conductorSessionStatus(&meta)
+		}
+		if meta.HeartbeatEnabled {
+			if state, err := session.HeartbeatDaemonStatus(meta.Name); err == nil {
+				row.HeartbeatDaemon = &state
+			}
+		}
+		var state session.HeartbeatDaemonState
+		if row.HeartbeatDaemon != nil {
+			state = *row.HeartbeatDaemon
+		}
+		row.HeartbeatTiming = session.ConductorHeartbeatTiming(&meta, state, now)
+		rows = append(rows, row)
+	}
+
+	if jsonOutput {
+		output, _ := json.MarshalIndent(map[string]any{
+			"conductors":     rows,
+			"daemon_running": session.IsBridgeDaemonRunning(),
+		}, "", "  ")
+		fmt.Println(string(output))
+		return
+	}
+	if len(rows) == 0 {
+		fmt.Println("No conductors configured.")
+		fmt.Println("Run 'agent-deck conductor setup <name>' to create one.")
+		return
+	}
+
+	timeFmt := session.NewTimeFormatter(absolute)
+	fmt.Printf("%-14s %-10s %-11s %-22s %-20s %s\n", "CONDUCTOR", "PROFILE", "SESSION", "TIMER", "LAST RUN", "NEXT RUN")
+	for _, row := range rows {
+		timer := "off"
+		if d := row.HeartbeatDaemon; row.Heartbeat && d != nil {
+			switch {
+			case d.Backend == "":
+				timer = "no scheduler"
+			case !d.Installed:
+				timer = d.Backend + " not installed"
+			case !d.Active:
+				timer = d.Backend + " inactive"
+			default:
+				timer = d.Backend + " active"
+			}
+		}
+		last := formatHealthTime(timeFmt, row.LastRun, now)
+		if row.LastResult != "" && row.LastResult != session.HeartbeatSent {
+			last += " (" + row.LastResult + ")"
+		}
+		fmt.Printf("%-14s %-10s %-11s %-22s %-20s %s\n", row.Name, row.Profile, row.SessionStatus, timer, last,
+			formatHealthTime(timeFmt, row.NextRun, now))
+	}
+}
+
+// formatHealthTime renders a health table time ("-" when unknown)
+func formatHealthTime(f session.TimeFormatter, t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return f.Format(t, now)
+}
+
+// conductorSessionStatus returns the conductor session's ID and status, or
+// "no session" when its profile has none
+func conductorSessionStatus(meta *session.ConductorMeta) (string, string) {
+	storage, err := session.NewStorageWithProfile(meta.Profile)
+	if err != nil {
+		return "", "no session"
+	}
+	instances, _, err := storage.LoadWithGroups()
+	if err != nil {
+		return "", "no session"
+	}
+	title := session.ConductorSessionTitle(meta.Name)
+	for _, inst := range instances {
+		if inst.Title == title {
+			_ = inst.UpdateStatus()
+			return inst.ID, string(inst.Status)
+		}
+	}
+	return "", "no session"
+}
+
 // handleConductorList lists all conductors
 func handleConductorList(profile string, args []string) {
 	fs := flag.NewFlagSet("conductor list", flag.ExitOnError)
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 	filterProfile := fs.String("profile", "", "Filter by profile")
+	absolute := fs.Bool("absolute", false, "Show absolute creation times (in each conductor's timezone)")
+	sortKey := fs.String("sort", session.ConductorSortName, "Sort by "+strings.Join(session.ConductorSortKeys, ", "))
+	reverse := fs.Bool("reverse", false, "Reverse the sort order")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck conductor list [options]")
 		fmt.Println()
-		fmt.Println("List all configured conductors.")
+		fmt.Println("List all configured conductors with their session status, task queue depth,")
+		fmt.Println("last heartbeat result and estimated spend today (conductor plus its workers).")
+		fmt.Println()
+		fmt.Println("Sort keys: name (default), profile, status (stopped first), queue (deepest")
+		fmt.Println("first), heartbeat (failed, then stalest first), spend (highest first).")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -824,9 +1111,14 @@ func handleConductorList(profile string, args []string) {
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
 		os.Exit(1)
 	}
+	if !slices.Contains(session.ConductorSortKeys, *sortKey) {
+		fmt.Fprintf(os.Stderr, "Error: unknown sort key %q (use %s)\n", *sortKey, strings.Join(session.ConductorSortKeys, ", "))
+		os.Exit(1)
+	}
 
 	// Auto-migrate
 	runAutoMigration(*jsonOutput)
+	syncHeartbeatTimers(*jsonOutput)
 
 	var conductors []session.ConductorMeta
 	var err error
@@ -843,15 +1135,19 @@ func handleConductorList(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	now := time.Now()
+	rows := session.BuildConductorOverviews(conductors, now)
+	_ = session.SortConductorOverviews(rows, *sortKey, *reverse)
+
 	if *jsonOutput {
 		output, _ := json.MarshalIndent(map[string]any{
-			"conductors": conductors,
+			"conductors": rows,
 		}, "", "  ")
 		fmt.Println(string(output))
 		return
 	}
 
-	if len(conductors) == 0 {
+	if len(rows) == 0 {
 		fmt.Println("No conductors configured.")
 		fmt.Println("Run 'agent-deck conductor setup <name>' to create one.")
 		return
@@ -860,48 +1156,415 @@ func handleConductorList(profile string, args []string) {
 	fmt.Println("Conductors:")
 	fmt.Println()
 
-	for _, meta := range conductors {
-		// Check session status
-		var statusText string
-		sessionTitle := session.ConductorSessionTitle(meta.Name)
-		storage, err := session.NewStorageWithProfile(meta.Profile)
-		if err == nil {
-			instances, _, err := storage.LoadWithGroups()
-			if err == nil {
-				found := false
-				for _, inst := range instances {
-					if inst.Title == sessionTitle {
-						found = true
-						_ = inst.UpdateStatus()
-						if inst.Status == session.StatusRunning || inst.Status == session.StatusWaiting || inst.Status == session.StatusIdle {
-							statusText = "running"
-						} else {
-							statusText = "stopped"
-						}
-						break
-					}
-				}
-				if !found {
-					statusText = "no session"
-				}
-			}
-		}
-
+	timeFmt := session.NewTimeFormatter(*absolute)
+	for _, row := range rows {
 		hb := "on"
-		if !meta.HeartbeatEnabled {
+		if !row.HeartbeatEnabled {
 			hb = "off"
 		}
 
+		statusText := strings.ReplaceAll(row.Status, "_", " ")
+		if statusText == "" {
+			statusText = "unknown"
+		}
+
+		lastRun := "-"
+		if run := row.LastHeartbeat; run != nil {
+			lastRun = run.Result + " " + timeFmt.InTimezone(row.Timezone).Format(run.Time, now)
+		}
+
+		queue := fmt.Sprintf("%d", row.QueueDepth)
+		if row.ActiveTasks > 0 {
+			queue += fmt.Sprintf("+%d", row.ActiveTasks)
+		}
+
 		desc := ""
-		if meta.Description != "" {
-			desc = fmt.Sprintf("  %q", meta.Description)
+		if row.Description != "" {
+			desc = fmt.Sprintf("  %q", row.Description)
+		}
+
+		created := ""
+		if t, err := time.Parse(time.RFC3339, row.CreatedAt); err == nil {
+			created = "  created " + timeFmt.InTimezone(row.Timezone).Format(t, now)
 		}
 
-		fmt.Printf("  %-12s [%s]  heartbeat:%-3s  %-10s%s\n", meta.Name, meta.Profile, hb, statusText, desc)
+		fmt.Printf("  %-12s [%s]  heartbeat:%-3s  %-10s  queue:%-5s  last:%-18s  today:$%.2f%s%s\n",
+			row.Name, row.Profile, hb, statusText, queue, lastRun, row.SpendToday, created, desc)
 	}
 	fmt.Println()
 }
 
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// handleConductorIdentity shows a conductor's identity card, or updates the
+// identity fields in meta.json and regenerates the card in CLAUDE.md
+func handleConductorIdentity(args []string) {
+	fs := flag.NewFlagSet("conductor identity", flag.ExitOnError)
+	responsibilities := fs.String("responsibilities", "", "Comma-separated responsibilities (\"-\" to clear)")
+	contacts := fs.String("contacts", "", "Comma-separated escalation contacts (\"-\" to clear)")
+	quietHours := fs.String("quiet-hours", "", "Quiet hours as HH:MM-HH:MM in the conductor's timezone (\"-\" to clear)")
+	language := fs.String("language", "", "Language for replies, heartbeat reports and summaries, e.g. German (\"-\" to clear)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor identity <name> [options]")
+		fmt.Println()
+		fmt.Println("Show the identity card generated from meta.json, or update its fields.")
+		fmt.Println("The card is kept in IDENTITY.md and in a managed section of CLAUDE.md")
+		fmt.Println("(where a {IDENTITY} placeholder is expanded in place).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Example:")
+		fmt.Println("  agent-deck conductor identity ops --responsibilities \"deploys,incident triage\" \\")
+		fmt.Println("      --contacts \"@alice (Slack),oncall@example.com\" --quiet-hours 22:00-07:00")
+		fmt.Println("  agent-deck conductor identity berlin --language German")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	meta, err := session.LoadConductorMeta(name)
+	if err != nil {
+		out.Error(fmt.Sprintf("conductor %q not found", name), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	orClear := func(value string) string {
+		if value == "-" {
+			return ""
+		}
+		return strings.TrimSpace(value)
+	}
+	if set["responsibilities"] {
+		meta.Responsibilities = splitList(orClear(*responsibilities))
+	}
+	if set["contacts"] {
+		meta.EscalationContacts = splitList(orClear(*contacts))
+	}
+	if set["quiet-hours"] {
+		meta.QuietHours = orClear(*quietHours)
+		if meta.QuietHours != "" {
+			if _, _, err := session.ParseQuietHours(meta.QuietHours); err != nil {
+				out.Error(err.Error(), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+	}
+	if set["language"] {
+		meta.Language = orClear(*language)
+		if err := session.ValidateResponseLanguage(meta.Language); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+	changed := set["responsibilities"] || set["contacts"] || set["quiet-hours"] || set["language"]
+
+	if changed {
+		if err := session.SaveConductorMeta(meta); err != nil {
+			out.Error(fmt.Sprintf("failed to save meta.json: %v", err), metaSaveErrCode(err))
+			os.Exit(1)
+		}
+	}
+	warning := ""
+	if err := session.SyncConductorIdentity(name); err != nil {
+		if !errors.Is(err, session.ErrConductorClaudeMDLinked) {
+			out.Error(fmt.Sprintf("failed to update identity card: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		warning = err.Error()
+	}
+
+	card := session.RenderConductorIdentity(meta)
+	if *jsonOutput {
+		out.Print("", map[string]any{
+			"name":                name,
+			"responsibilities":    meta.Responsibilities,
+			"escalation_contacts": meta.EscalationContacts,
+			"quiet_hours":         meta.QuietHours,
+			"language":            meta.Language,
+			"card":                card,
+			"warning":             warning,
+		})
+		return
+	}
+	if changed {
+		fmt.Printf("[ok] Updated identity for conductor %s (takes effect on its next restart)\n\n", name)
+	}
+	if warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n\n", warning)
+	}
+	fmt.Print(card)
+}
+
+// handleConductorRunbook regenerates and prints a conductor's RUNBOOK.md
+func handleConductorRunbook(args []string) {
+	fs := flag.NewFlagSet("conductor runbook", flag.ExitOnError)
+	pathOnly := fs.Bool("path", false, "Print only the runbook's path")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor runbook <name> [options]")
+		fmt.Println()
+		fmt.Println("Show the operator runbook of a conductor: what it does, who to contact,")
+		fmt.Println("how to check on, attach to and pause it, and where its files and logs")
+		fmt.Println("are. It is kept in RUNBOOK.md in the conductor directory and rewritten")
+		fmt.Println("whenever the conductor's settings change, for teammates covering for")
+		fmt.Println("its owner.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	meta, err := session.LoadConductorMeta(name)
+	if err != nil {
+		out.Error(fmt.Sprintf("conductor %q not found", name), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	if err := session.WriteConductorRunbook(meta); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	path, _ := session.ConductorRunbookPath(name)
+
+	runbook := session.RenderConductorRunbook(meta)
+	switch {
+	case *jsonOutput:
+		out.Print("", map[string]any{"name": name, "path": path, "runbook": runbook})
+	case *pathOnly:
+		fmt.Println(path)
+	default:
+		fmt.Print(runbook)
+	}
+}
+
+// handleConductorSchedule shows a conductor's heartbeat schedule, or sets it
+// (OnCalendar or cron) and reinstalls the heartbeat timer
+func handleConductorSchedule(args []string) {
+	fs := flag.NewFlagSet("conductor schedule", flag.ExitOnError)
+	clearSchedule := fs.Bool("clear", false, "Remove the schedule and go back to the fixed heartbeat interval")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor schedule <name> [expression] [options]")
+		fmt.Println()
+		fmt.Println("Show or set when a conductor's heartbeat runs. The expression is a systemd")
+		fmt.Println("OnCalendar expression or a five-field cron string; cron strings also work")
+		fmt.Println("with launchd on macOS. Expressions are checked with systemd-analyze when")
+		fmt.Println("it is installed.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck conductor schedule ops \"5 9-17 * * 1-5\"              # hourly at :05, business hours")
+		fmt.Println("  agent-deck conductor schedule ops \"Mon..Fri *-*-* 09..17:05:00\"")
+		fmt.Println("  agent-deck conductor schedule ops --clear")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() < 1 || fs.NArg() > 2 || (*clearSchedule && fs.NArg() == 2) {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	meta, err := session.LoadConductorMeta(name)
+	if err != nil {
+		out.Error(fmt.Sprintf("conductor %q not found", name), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	changed := false
+	switch {
+	case *clearSchedule:
+		meta.HeartbeatSchedule = ""
+		changed = true
+	case fs.NArg() == 2:
+		if _, err := session.ValidateHeartbeatSchedule(fs.Arg(1), meta.Timezone); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		meta.HeartbeatSchedule = strings.TrimSpace(fs.Arg(1))
+		changed = true
+	}
+
+	interval := session.HeartbeatIntervalFor(meta)
+	schedule := session.EffectiveHeartbeatSchedule(meta)
+
+	if changed {
+		if err := session.SaveConductorMeta(meta); err != nil {
+			out.Error(fmt.Sprintf("failed to save meta.json: %v", err), metaSaveErrCode(err))
+			os.Exit(1)
+		}
+		if meta.HeartbeatEnabled && session.HeartbeatScriptInstalled(name) {
+			if err := session.InstallHeartbeatDaemon(name, meta.Profile, interval, schedule); err != nil {
+				out.Error(fmt.Sprintf("schedule saved but failed to reinstall heartbeat timer: %v", err), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+		if err := session.SyncConductorIdentity(name); err != nil && !errors.Is(err, session.ErrConductorClaudeMDLinked) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update identity card: %v\n", err)
+		}
+	}
+
+	var next, calendar string
+	if schedule != "" {
+		next, _ = session.ValidateHeartbeatSchedule(schedule, meta.Timezone)
+		if sched, err := session.ParseHeartbeatSchedule(schedule); err == nil {
+			calendar, _ = sched.OnCalendar(meta.Timezone)
+		}
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]any{
+			"name":               name,
+			"heartbeat_enabled":  meta.HeartbeatEnabled,
+			"heartbeat_schedule": schedule,
+			"on_calendar":        calendar,
+			"interval_minutes":   interval,
+			"next_run":           next,
+		})
+		return
+	}
+	if changed {
+		fmt.Printf("[ok] Updated heartbeat schedule for conductor %s\n", name)
+	}
+	if !meta.HeartbeatEnabled {
+		fmt.Println("Heartbeat: disabled")
+	}
+	if schedule == "" {
+		fmt.Printf("Schedule: every %d min\n", interval)
+		return
+	}
+	fmt.Printf("Schedule: %s\n", schedule)
+	if calendar != schedule {
+		fmt.Printf("OnCalendar: %s\n", calendar)
+	}
+	if next != "" {
+		fmt.Printf("Next run: %s\n", next)
+	}
+}
+
+// handleConductorReconcile re-renders heartbeat scripts and timers that
+// drifted from the conductor settings
+func handleConductorReconcile(args []string) {
+	fs := flag.NewFlagSet("conductor reconcile", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Report drift without changing anything")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor reconcile [name...] [options]")
+		fmt.Println()
+		fmt.Println("Compare each conductor's heartbeat.sh and timer (launchd plist, systemd")
+		fmt.Println("units or crontab line) with what its current settings render, and")
+		fmt.Println("re-render and reload only the ones that drifted. Use after changing")
+		fmt.Println("heartbeat intervals, schedules, timezones or the heartbeat backend.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck conductor reconcile")
+		fmt.Println("  agent-deck conductor reconcile ops --dry-run")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		conductors, err := session.ListConductors()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing conductors: %v\n", err)
+			os.Exit(1)
+		}
+		for _, meta := range conductors {
+			names = append(names, meta.Name)
+		}
+	}
+
+	reconcile := session.ReconcileConductor
+	if *dryRun {
+		reconcile = session.HeartbeatDrift
+	}
+	type reconcileEntry struct {
+		*session.ReconcileResult
+		Error string `json:"error,omitempty"`
+	}
+	entries := make([]reconcileEntry, 0, len(names))
+	failed := false
+	for _, name := range names {
+		result, err := reconcile(name)
+		entry := reconcileEntry{ReconcileResult: result}
+		if err != nil {
+			entry.Error = err.Error()
+			failed = true
+		}
+		entries = append(entries, entry)
+	}
+
+	if *jsonOutput {
+		output, _ := json.MarshalIndent(map[string]any{"dry_run": *dryRun, "conductors": entries}, "", "  ")
+		fmt.Println(string(output))
+	} else {
+		if len(entries) == 0 {
+			fmt.Println("No conductors configured.")
+		}
+		for _, entry := range entries {
+			switch {
+			case entry.Error != "":
+				fmt.Printf("  [error] %s: %s\n", entry.Name, entry.Error)
+			case len(entry.Drift) == 0:
+				fmt.Printf("  [ok] %s: in sync\n", entry.Name)
+			case entry.Updated:
+				fmt.Printf("  [updated] %s (%s)\n", entry.Name, entry.Backend)
+			default:
+				fmt.Printf("  [drift] %s (%s)\n", entry.Name, entry.Backend)
+			}
+			for _, drift := range entry.Drift {
+				fmt.Printf("      %s\n", drift)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
 // installPythonDeps installs Python dependencies for the bridge
 func installPythonDeps() {
 	config, err := session.LoadUserConfig()
@@ -946,6 +1609,21 @@ func printConductorHelp() {
 	fmt.Println("  teardown <name>  Stop and optionally remove a conductor (or --all)")
 	fmt.Println("  status [name]    Show conductor health (all or specific)")
 	fmt.Println("  list             List all configured conductors")
+	fmt.Println("  report [name]    Show tasks/day and completion latency")
+	fmt.Println("  history <name>   Show recent heartbeat runs: result, prompt, status before/after")
+	fmt.Println("  heartbeat-run <name>  Run one heartbeat now, as the heartbeat timer does")
+	fmt.Println("  fleet            Roll up conductors, escalations and spend per profile")
+	fmt.Println("  standup [name]   Compile (and --post) a done/doing/blocked standup across conductors")
+	fmt.Println("  identity <name>  Show or edit a conductor's identity card")
+	fmt.Println("  runbook <name>   Show the operator runbook (RUNBOOK.md) for teammates covering")
+	fmt.Println("  schedule <name>  Show or set a conductor's heartbeat schedule (OnCalendar or cron)")
+	fmt.Println("  notify <name>    Send heartbeat failures and worker errors to Slack or Discord")
+	fmt.Println("  reconcile [name] Re-render heartbeat timers that drifted from the settings")
+	fmt.Println("  skills <cmd>     Install skill packs and attach them to conductors")
+	fmt.Println("  group <cmd>      Start, stop and check groups of cooperating conductors")
+	fmt.Println("  task <cmd>       Queue prompts for worker sessions, dispatched on heartbeats")
+	fmt.Println("  registry <cmd>   Fetch and verify templates and skill packs from a registry")
+	fmt.Println("  bridge <cmd>     Verify, upgrade, or uninstall the bridge daemon")
 	fmt.Println("  help             Show this help")
 	fmt.Println()
 	fmt.Println("Examples:")
@@ -953,6 +1631,498 @@ func printConductorHelp() {
 	fmt.Println("  agent-deck -p work conductor setup infra --no-heartbeat")
 	fmt.Println("  agent-deck conductor list")
 	fmt.Println("  agent-deck conductor status")
+	fmt.Println("  agent-deck conductor report --days 14")
+	fmt.Println("  agent-deck conductor history ops --limit 50")
+	fmt.Println("  agent-deck conductor heartbeat-run ops")
+	fmt.Println("  agent-deck conductor fleet --short")
+	fmt.Println("  agent-deck conductor standup --post")
+	fmt.Println("  agent-deck conductor reconcile --dry-run")
+	fmt.Println("  agent-deck conductor notify ops --slack https://hooks.slack.com/services/...")
+	fmt.Println("  agent-deck conductor skills attach ryan incident-response")
+	fmt.Println("  agent-deck conductor group start oncall")
+	fmt.Println("  agent-deck conductor task add ops api-server \"Run the tests\"")
 	fmt.Println("  agent-deck conductor teardown infra --remove")
 	fmt.Println("  agent-deck conductor teardown --all --remove")
+	fmt.Println("  agent-deck conductor bridge upgrade")
+}
+
+// handleConductorBridge dispatches bridge daemon maintenance subcommands
+func handleConductorBridge(args []string) {
+	fs := flag.NewFlagSet("conductor bridge", flag.ExitOnError)
+	force := fs.Bool("force", false, "Restart even if nothing changed (upgrade only)")
+	noRestart := fs.Bool("no-restart", false, "Refresh files without restarting the service (upgrade only)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor bridge <status|upgrade|uninstall> [options]")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  status      Verify the bridge daemon (unit, enabled, running, version)")
+		fmt.Println("  upgrade     Refresh bridge.py and the unit, restarting if agent-deck changed")
+		fmt.Println("  uninstall   Stop and remove the bridge daemon")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	sub := args[0]
+	if err := fs.Parse(normalizeArgs(fs, args[1:])); err != nil {
+		os.Exit(1)
+	}
+
+	switch sub {
+	case "status", "verify":
+		checks, err := session.VerifyBridgeDaemon()
+		if *jsonOutput {
+			output, _ := json.MarshalIndent(map[string]any{
+				"success": err == nil,
+				"version": session.BridgeScriptVersion(),
+				"checks":  checks,
+			}, "", "  ")
+			fmt.Println(string(output))
+		} else {
+			for _, c := range checks {
+				mark := "[ok]  "
+				if !c.OK {
+					mark = "[fail]"
+				}
+				fmt.Printf("%s %s", mark, c.Name)
+				if c.Detail != "" && !strings.Contains(c.Detail, "\n") {
+					fmt.Printf(": %s", c.Detail)
+				}
+				fmt.Println()
+				if !c.OK {
+					if strings.Contains(c.Detail, "\n") {
+						for _, line := range strings.Split(c.Detail, "\n") {
+							fmt.Printf("         | %s\n", line)
+						}
+					}
+					if c.Remedy != "" {
+						fmt.Printf("       fix: %s\n", c.Remedy)
+					}
+				}
+			}
+		}
+		if err != nil {
+			os.Exit(1)
+		}
+
+	case "upgrade":
+		restarted, reasons, err := session.UpgradeBridgeDaemon(*force, !*noRestart)
+		if *jsonOutput {
+			data := map[string]any{
+				"success":   err == nil,
+				"restarted": restarted,
+				"reasons":   reasons,
+			}
+			if err != nil {
+				data["error"] = err.Error()
+			}
+			output, _ := json.MarshalIndent(data, "", "  ")
+			fmt.Println(string(output))
+		} else {
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			case restarted && *noRestart:
+				fmt.Printf("[ok] Bridge files refreshed (%s)\n", strings.Join(reasons, "; "))
+			case restarted:
+				fmt.Printf("[ok] Bridge daemon restarted (%s)\n", strings.Join(reasons, "; "))
+			default:
+				fmt.Println("[ok] Bridge daemon is up to date")
+			}
+		}
+		if err != nil {
+			os.Exit(1)
+		}
+
+	case "uninstall":
+		if err := session.UninstallBridgeDaemon(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *jsonOutput {
+			fmt.Println(`{"success": true}`)
+		} else {
+			fmt.Println("[ok] Bridge daemon stopped and removed")
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown bridge command: %s\n", sub)
+		fs.Usage()
+		os.Exit(1)
+	}
+}
+
+// handleConductorReport prints per-day throughput for conductors
+func handleConductorReport(_ string, args []string) {
+	fs := flag.NewFlagSet("conductor report", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	days := fs.Int("days", 7, "Number of days to include (including today)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor report [name] [options]")
+		fmt.Println()
+		fmt.Println("Show tasks enqueued, started and completed per day, duplicates merged,")
+		fmt.Println("median time from enqueue to done, and median heartbeat-to-idle time for")
+		fmt.Println("each conductor.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	var conductors []session.ConductorMeta
+	if name != "" {
+		meta, err := session.LoadConductorMeta(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: conductor %q not found: %v\n", name, err)
+			os.Exit(1)
+		}
+		conductors = []session.ConductorMeta{*meta}
+	} else {
+		var err error
+		conductors, err = session.ListConductors()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing conductors: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	rows, err := session.ConductorLoadReportFor(conductors, *days, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		output, _ := json.MarshalIndent(map[string]any{
+			"days": *days,
+			"rows": rows,
+		}, "", "  ")
+		fmt.Println(string(output))
+		return
+	}
+
+	if len(rows) == 0 {
+		fmt.Printf("No conductor tasks recorded in the last %d day(s).\n", *days)
+		return
+	}
+
+	fmt.Printf("%-14s %-10s %8s %8s %9s %7s %10s %12s\n", "CONDUCTOR", "DAY", "ENQUEUED", "STARTED", "COMPLETED", "MERGED", "MEDIAN", "HB->IDLE")
+	for _, row := range rows {
+		fmt.Printf("%-14s %-10s %8d %8d %9d %7d %10s %12s\n",
+			row.Conductor, row.Day, row.Enqueued, row.Started, row.Completed, row.Merged,
+			formatReportDuration(row.MedianLatency), formatReportDuration(row.MedianHeartbeatIdle))
+	}
+}
+
+// handleConductorHeartbeatRun runs one heartbeat for a conductor: refresh
+// the status export and due standup, then send the [HEARTBEAT] check-in when
+// the conductor is idle or waiting. Timers (systemd, launchd, cron) run this
+// directly.
+func handleConductorHeartbeatRun(args []string) {
+	fs := flag.NewFlagSet("conductor heartbeat-run", flag.ExitOnError)
+	quiet := fs.Bool("q", false, "Quiet mode")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor heartbeat-run <name> [options]")
+		fmt.Println()
+		fmt.Println("Run one heartbeat for a conductor, as its heartbeat timer does: refresh")
+		fmt.Println("the [status_export] snapshot, post the standup when due, and send the")
+		fmt.Println("[HEARTBEAT] check-in if the conductor session is idle or waiting.")
+		fmt.Println("Queued tasks are dispatched to idle workers first (see 'conductor task').")
+		fmt.Println("Nothing runs during a maintenance window.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+	meta, err := session.LoadConductorMeta(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: conductor %q not found: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	// Heartbeats are paused during maintenance windows
+	if session.ActiveMaintenanceWindow(time.Now()) != nil {
+		heartbeatLog.Info("heartbeat_run_skipped", slog.String("conductor", name), slog.String("reason", "maintenance"))
+		return
+	}
+
+	// Low on battery, only every few heartbeats go out
+	saver := session.ActiveBatterySaver()
+	if saver != nil {
+		var last time.Time
+		if result, _ := session.ReadHeartbeatResult(name); result != nil {
+			last = result.Time
+		}
+		interval := time.Duration(session.HeartbeatIntervalFor(meta)) * time.Minute
+		if !saver.HeartbeatDue(last, interval, time.Now()) {
+			heartbeatLog.Info("heartbeat_run_skipped",
+				slog.String("conductor", name),
+				slog.String("reason", "battery"),
+				slog.Int("battery_percent", saver.Percent),
+			)
+			if !*quiet {
+				fmt.Printf("Skipped heartbeat for '%s': %s\n", session.ConductorSessionTitle(name), saver.Describe())
+			}
+			return
+		}
+	}
+
+	// Best effort, like the rest of the heartbeat: a failure here must not
+	// stop the check-in
+	if self, err := os.Executable(); err == nil {
+		_ = exec.Command(self, "status", "export", "-q").Run()
+		_ = exec.Command(self, "conductor", "standup", "--post", "--if-due", "-q").Run()
+	}
+
+	_, instances, _, err := loadSessionData(meta.Profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	title := session.ConductorSessionTitle(name)
+	var conductor *session.Instance
+	for _, inst := range instances {
+		_ = inst.UpdateStatus()
+		if inst.Title == title {
+			conductor = inst
+		}
+	}
+	if conductor == nil {
+		fmt.Fprintf(os.Stderr, "Error: conductor session %q not found in profile %s\n", title, meta.Profile)
+		os.Exit(1)
+	}
+
+	// Hand queued tasks to free workers and collect the finished ones, even
+	// when the conductor itself is too busy for a check-in. The battery saver
+	// holds them until back on AC power.
+	var dispatch *session.TaskDispatchReport
+	if saver != nil && saver.PauseDispatch {
+		heartbeatLog.Info("task_dispatch_paused", slog.String("conductor", name), slog.Int("battery_percent", saver.Percent))
+		if !*quiet {
+			fmt.Printf("Task dispatch paused for '%s': %s\n", title, saver.Describe())
+		}
+	} else if dispatch, err = session.DispatchConductorTasks(name, instances, sendTaskPrompt); err != nil {
+		heartbeatLog.Warn("task_dispatch_failed", slog.String("conductor", name), slog.String("error", err.Error()))
+		fmt.Fprintf(os.Stderr, "Warning: task dispatch failed: %v\n", err)
+	} else if !*quiet && len(dispatch.Dispatched) > 0 {
+		fmt.Printf("Dispatched %d task(s) for '%s'\n", len(dispatch.Dispatched), title)
+	}
+
+	// Only send if the session is free to take it
+	if status := conductor.GetStatusThreadSafe(); status != session.StatusIdle && status != session.StatusWaiting {
+		heartbeatLog.Info("heartbeat_run_skipped",
+			slog.String("conductor", name),
+			slog.String("reason", "busy"),
+			slog.String("status", string(status)),
+		)
+		if !*quiet {
+			fmt.Printf("Skipped heartbeat for '%s': session is %s\n", title, status)
+		}
+		return
+	}
+
+	message := session.BuildHeartbeatMessage(meta, instances, time.Now())
+	if summary := dispatch.Summary(); summary != "" {
+		message += " " + summary
+	}
+	sendArgs := []string{title, message}
+	if *quiet {
+		sendArgs = append(sendArgs, "-q")
+	}
+	handleSessionSend(meta.Profile, sendArgs)
+}
+
+// handleConductorHistory shows a conductor's recorded heartbeat runs
+func handleConductorHistory(args []string) {
+	fs := flag.NewFlagSet("conductor history", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "Maximum number of runs (0 = all kept)")
+	absolute := fs.Bool("absolute", false, "Show absolute timestamps instead of relative ones")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor history <name> [options]")
+		fmt.Println()
+		fmt.Println("Show a conductor's recent heartbeat runs, newest first: whether the")
+		fmt.Println("heartbeat was sent, skipped or failed, the prompt injected, and the")
+		fmt.Println("conductor session's status before and after. The last [conductor]")
+		fmt.Println("heartbeat_history runs (default 500) are kept in heartbeats.jsonl.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+	if _, err := session.LoadConductorMeta(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: conductor %q not found: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	runs, err := session.LoadHeartbeatHistory(name, *limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading heartbeat history: %v\n", err)
+		os.Exit(1)
+	}
+	slices.Reverse(runs)
+
+	if *jsonOutput {
+		if runs == nil {
+			runs = []session.HeartbeatRun{}
+		}
+		output, _ := json.MarshalIndent(map[string]any{"name": name, "runs": runs}, "", "  ")
+		fmt.Println(string(output))
+		return
+	}
+	if len(runs) == 0 {
+		fmt.Printf("No heartbeat runs recorded for %s.\n", name)
+		return
+	}
+
+	timeFmt := session.NewTimeFormatter(*absolute)
+	now := time.Now()
+	fmt.Printf("%-16s %-8s %-20s %s\n", "TIME", "RESULT", "STATUS", "PROMPT")
+	for _, run := range runs {
+		status := "-"
+		if run.StatusBefore != "" {
+			status = run.StatusBefore + " -> " + run.StatusAfter
+		}
+		detail := truncate(strings.Join(strings.Fields(run.Prompt), " "), 60)
+		if run.Error != "" {
+			detail = run.Error
+		}
+		fmt.Printf("%-16s %-8s %-20s %s\n", timeFmt.Format(run.Time, now), run.Result, status, detail)
+	}
+}
+
+// formatReportDuration renders a median for the report table ("-" when unknown)
+func formatReportDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
+
+// handleConductorFleet prints the per-profile fleet rollup
+func handleConductorFleet(_ string, args []string) {
+	fs := flag.NewFlagSet("conductor fleet", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	short := fs.Bool("short", false, "Print a single line (for status bars)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor fleet [options]")
+		fmt.Println()
+		fmt.Println("Show conductors grouped by profile: counts by state, the oldest")
+		fmt.Println("un-acknowledged waiting session, and estimated spend today.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	summary, err := session.BuildFleetSummary(now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		output, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Println(string(output))
+		return
+	}
+
+	if *short {
+		fmt.Println(formatFleetShort(summary, now))
+		return
+	}
+
+	if len(summary.Profiles) == 0 {
+		fmt.Println("No conductors configured.")
+		fmt.Println("Run 'agent-deck conductor setup <name>' to create one.")
+		return
+	}
+
+	for _, fp := range summary.Profiles {
+		fmt.Printf("%s  (%d conductor(s))\n", fp.Profile, fp.Conductors)
+		fmt.Printf("  states:     %s\n", formatFleetStates(fp.States))
+		if fp.OldestEscalation != nil {
+			fmt.Printf("  unacked:    %d, oldest %q waiting %s\n", fp.UnackedEscalations,
+				fp.OldestEscalation.Title, formatStateDuration(now.Sub(fp.OldestEscalation.Since)))
+		} else {
+			fmt.Println("  unacked:    0")
+		}
+		fmt.Printf("  spend:      $%.2f today\n", fp.SpendToday)
+		fmt.Println()
+	}
+}
+
+// fleetStateOrder fixes the display order of conductor states
+var fleetStateOrder = []string{
+	string(session.StatusRunning),
+	string(session.StatusThrottled),
+	string(session.StatusWaiting),
+	string(session.StatusIdle),
+	string(session.StatusStarting),
+	string(session.StatusError),
+	session.FleetStateStopped,
+	session.FleetStateNoSession,
+}
+
+// formatFleetStates renders non-zero state counts in a stable order
+func formatFleetStates(states map[string]int) string {
+	var parts []string
+	for _, state := range fleetStateOrder {
+		if n := states[state]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, state))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatFleetShort renders the whole fleet on one line, e.g.
+// "work 2 running 1 waiting !3 (12m) $4.20 | personal 1 idle $0.00"
+func formatFleetShort(summary *session.FleetSummary, now time.Time) string {
+	var parts []string
+	for _, fp := range summary.Profiles {
+		line := fp.Profile + " " + strings.ReplaceAll(formatFleetStates(fp.States), ", ", " ")
+		if fp.OldestEscalation != nil {
+			line += fmt.Sprintf(" !%d (%s)", fp.UnackedEscalations, formatStateDuration(now.Sub(fp.OldestEscalation.Since)))
+		}
+		line += fmt.Sprintf(" $%.2f", fp.SpendToday)
+		parts = append(parts, line)
+	}
+	return strings.Join(parts, " | ")
 }
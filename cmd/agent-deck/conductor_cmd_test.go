@@ -4,6 +4,9 @@ import (
 	"flag"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
 func newConductorSetupFlagSet() *flag.FlagSet {
@@ -85,3 +88,21 @@ func TestParseConductorSetupArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatFleetShort(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	summary := &session.FleetSummary{Profiles: []session.FleetProfile{
+		{
+			Profile:            "work",
+			States:             map[string]int{"waiting": 1, "running": 2},
+			UnackedEscalations: 3,
+			OldestEscalation:   &session.FleetEscalation{Since: now.Add(-12 * time.Minute)},
+			SpendToday:         4.2,
+		},
+		{Profile: "personal", States: map[string]int{"idle": 1}},
+	}}
+	want := "work 2 running 1 waiting !3 (12m) $4.20 | personal 1 idle $0.00"
+	if got := formatFleetShort(summary, now); got != want {
+		t.Errorf("formatFleetShort = %q, want %q", got, want)
+	}
+}
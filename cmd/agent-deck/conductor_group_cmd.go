@@ -0,0 +1,452 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleConductorGroup dispatches conductor group subcommands
+func handleConductorGroup(args []string) {
+	if len(args) == 0 {
+		printConductorGroupHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "create":
+		handleConductorGroupCreate(args[1:])
+	case "delete", "rm":
+		handleConductorGroupDelete(args[1:])
+	case "list", "ls":
+		handleConductorGroupList(args[1:])
+	case "start":
+		handleConductorGroupStart(args[1:])
+	case "stop":
+		handleConductorGroupStop(args[1:])
+	case "status":
+		handleConductorGroupStatus(args[1:])
+	case "help", "-h", "--help":
+		printConductorGroupHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown group command '%s'\n", args[0])
+		printConductorGroupHelp()
+		os.Exit(1)
+	}
+}
+
+func printConductorGroupHelp() {
+	fmt.Println("Usage: agent-deck conductor group <command> [options]")
+	fmt.Println()
+	fmt.Println("Manage groups of cooperating conductors (e.g. SRE, QA and release) that")
+	fmt.Println("start, stop and report together. A group with a heartbeat interval")
+	fmt.Println("staggers its members' heartbeats so they do not all fire at once.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  create <group>   Create a group from existing conductors")
+	fmt.Println("  delete <group>   Delete a group (its conductors are kept)")
+	fmt.Println("  list             List groups")
+	fmt.Println("  start <group>    Start every member's session; all or none")
+	fmt.Println("  stop <group>     Stop every member's session")
+	fmt.Println("  status <group>   Show the members' sessions and heartbeats")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck conductor group create oncall --members sre,qa,release --interval 15")
+	fmt.Println("  agent-deck conductor group start oncall")
+	fmt.Println("  agent-deck conductor group status oncall")
+}
+
+func handleConductorGroupCreate(args []string) {
+	fs := flag.NewFlagSet("conductor group create", flag.ExitOnError)
+	members := fs.String("members", "", "Comma-separated member conductors, in heartbeat order")
+	description := fs.String("description", "", "Description for this group")
+	interval := fs.Int("interval", 0, "Group heartbeat interval in minutes (divides an hour or a day); 0 keeps members' own")
+	stagger := fs.Int("stagger", 0, "Minutes between members' heartbeats (default: spread evenly over the interval)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor group create <group> --members a,b,c [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	group := &session.ConductorGroup{
+		Name:              fs.Arg(0),
+		Members:           splitList(*members),
+		Description:       *description,
+		HeartbeatInterval: *interval,
+		Stagger:           *stagger,
+	}
+	if err := session.CreateConductorGroup(group); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Created group %s: %s (heartbeats: %s)", group.Name, strings.Join(group.Members, ", "), group.HeartbeatPlan()), map[string]any{
+		"success": true,
+		"group":   group,
+	})
+}
+
+func handleConductorGroupDelete(args []string) {
+	fs := flag.NewFlagSet("conductor group delete", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor group delete <group> [options]")
+		fmt.Println()
+		fmt.Println("Delete a group. Its conductors, sessions and heartbeats are left as they are.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	if err := session.DeleteConductorGroup(fs.Arg(0)); err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrConductorGroupNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Deleted group %s", fs.Arg(0)), map[string]any{"success": true, "group": fs.Arg(0)})
+}
+
+func handleConductorGroupList(args []string) {
+	fs := flag.NewFlagSet("conductor group list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor group list [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	groups, err := session.LoadConductorGroups()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *jsonOutput {
+		if groups == nil {
+			groups = []session.ConductorGroup{}
+		}
+		out.Print("", map[string]any{"groups": groups})
+		return
+	}
+	if len(groups) == 0 {
+		fmt.Println("No conductor groups.")
+		fmt.Println("Create one with: agent-deck conductor group create <group> --members a,b")
+		return
+	}
+	fmt.Printf("%-14s %-32s %s\n", "GROUP", "MEMBERS", "HEARTBEATS")
+	for _, g := range groups {
+		fmt.Printf("%-14s %-32s %s\n", g.Name, strings.Join(g.Members, ","), g.HeartbeatPlan())
+	}
+}
+
+// groupMember is a member conductor with its session, loaded before a group
+// operation changes anything
+type groupMember struct {
+	meta *session.ConductorMeta
+	inst *session.Instance
+}
+
+// groupSessions holds the loaded sessions of one profile, saved once after
+// a group operation
+type groupSessions struct {
+	storage   *session.Storage
+	instances []*session.Instance
+}
+
+// loadGroupMembers resolves every member's meta.json and session, failing if
+// any is missing so that start and stop act on all members or none
+func loadGroupMembers(group *session.ConductorGroup) ([]groupMember, map[string]*groupSessions, error) {
+	members := make([]groupMember, 0, len(group.Members))
+	profiles := make(map[string]*groupSessions)
+	for _, name := range group.Members {
+		meta, err := session.LoadConductorMeta(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		sessions, ok := profiles[meta.Profile]
+		if !ok {
+			storage, instances, _, err := loadSessionData(meta.Profile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("profile %s: %w", meta.Profile, err)
+			}
+			sessions = &groupSessions{storage: storage, instances: instances}
+			profiles[meta.Profile] = sessions
+		}
+		title := session.ConductorSessionTitle(name)
+		var inst *session.Instance
+		for _, candidate := range sessions.instances {
+			if candidate.Title == title {
+				inst = candidate
+				break
+			}
+		}
+		if inst == nil {
+			return nil, nil, fmt.Errorf("conductor %s has no session '%s' in profile %s; re-run 'agent-deck conductor setup %s'", name, title, meta.Profile, name)
+		}
+		members = append(members, groupMember{meta: meta, inst: inst})
+	}
+	return members, profiles, nil
+}
+
+// saveGroupSessions saves every profile a group operation touched
+func saveGroupSessions(profiles map[string]*groupSessions) error {
+	var errs []error
+	for profile, sessions := range profiles {
+		if err := saveSessionData(sessions.storage, sessions.instances); err != nil {
+			errs = append(errs, fmt.Errorf("profile %s: %w", profile, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadGroupOrExit loads the group named by the only positional argument
+func loadGroupOrExit(fs *flag.FlagSet, out *CLIOutput) *session.ConductorGroup {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	group, err := session.LoadConductorGroup(fs.Arg(0))
+	if err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrConductorGroupNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	return group
+}
+
+func handleConductorGroupStart(args []string) {
+	fs := flag.NewFlagSet("conductor group start", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor group start <group> [options]")
+		fmt.Println()
+		fmt.Println("Start every member's session. If one fails to start, the members started")
+		fmt.Println("by this run are stopped again. A group with a heartbeat interval then")
+		fmt.Println("installs the members' staggered heartbeat schedules.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	group := loadGroupOrExit(fs, out)
+
+	members, profiles, err := loadGroupMembers(group)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	var started, running []string
+	var startedMembers []groupMember
+	for _, m := range members {
+		if m.inst.Exists() {
+			running = append(running, m.meta.Name)
+			continue
+		}
+		if err := m.inst.Start(); err != nil {
+			for _, s := range startedMembers {
+				_ = s.inst.Kill()
+			}
+			_ = saveGroupSessions(profiles)
+			out.Error(fmt.Sprintf("failed to start %s: %v (stopped the %d member(s) this run had started)", m.meta.Name, err, len(startedMembers)), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		startedMembers = append(startedMembers, m)
+		started = append(started, m.meta.Name)
+	}
+	for _, m := range startedMembers {
+		m.inst.PostStartSync(3 * time.Second)
+	}
+	if err := saveGroupSessions(profiles); err != nil {
+		out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	// Stagger the heartbeats; a timer that fails to install leaves its
+	// member running, so this only warns
+	schedules, _ := group.HeartbeatSchedules()
+	heartbeats := make(map[string]string)
+	for _, m := range members {
+		if !m.meta.HeartbeatEnabled {
+			continue
+		}
+		if schedule, ok := schedules[m.meta.Name]; ok && m.meta.HeartbeatSchedule != schedule {
+			m.meta.HeartbeatSchedule = schedule
+			if err := session.SaveConductorMeta(m.meta); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save heartbeat schedule for %s: %v\n", m.meta.Name, err)
+				continue
+			}
+		}
+		schedule := session.EffectiveHeartbeatSchedule(m.meta)
+		if err := session.InstallHeartbeatScript(m.meta.Name, m.meta.Profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to install heartbeat script for %s: %v\n", m.meta.Name, err)
+		} else if err := session.InstallHeartbeatDaemon(m.meta.Name, m.meta.Profile, session.HeartbeatIntervalFor(m.meta), schedule); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to install heartbeat timer for %s: %v\n", m.meta.Name, err)
+		} else if schedule != "" {
+			heartbeats[m.meta.Name] = schedule
+		} else {
+			heartbeats[m.meta.Name] = fmt.Sprintf("every %d min", session.HeartbeatIntervalFor(m.meta))
+		}
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]any{
+			"success":         true,
+			"group":           group.Name,
+			"started":         started,
+			"already_running": running,
+			"heartbeats":      heartbeats,
+		})
+		return
+	}
+	if *quiet {
+		return
+	}
+	fmt.Printf("Group %s:\n", group.Name)
+	for _, m := range members {
+		state := "started"
+		if !slices.Contains(started, m.meta.Name) {
+			state = "already running"
+		}
+		line := fmt.Sprintf("  [ok] %-14s %s", m.meta.Name, state)
+		if hb, ok := heartbeats[m.meta.Name]; ok {
+			line += ", heartbeat " + hb
+		}
+		fmt.Println(line)
+	}
+}
+
+func handleConductorGroupStop(args []string) {
+	fs := flag.NewFlagSet("conductor group stop", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor group stop <group> [options]")
+		fmt.Println()
+		fmt.Println("Stop every member's session. Heartbeat timers stay installed and skip")
+		fmt.Println("stopped conductors until the group is started again.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	group := loadGroupOrExit(fs, out)
+
+	members, profiles, err := loadGroupMembers(group)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	var stopped, notRunning []string
+	var errs []error
+	for _, m := range members {
+		if !m.inst.Exists() {
+			notRunning = append(notRunning, m.meta.Name)
+			continue
+		}
+		if err := m.inst.Kill(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.meta.Name, err))
+			continue
+		}
+		stopped = append(stopped, m.meta.Name)
+	}
+	if err := saveGroupSessions(profiles); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		out.Error(fmt.Sprintf("failed to stop group %s: %v", group.Name, err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]any{
+			"success":     true,
+			"group":       group.Name,
+			"stopped":     stopped,
+			"not_running": notRunning,
+		})
+		return
+	}
+	if *quiet {
+		return
+	}
+	fmt.Printf("Group %s:\n", group.Name)
+	for _, m := range members {
+		state := "stopped"
+		if slices.Contains(notRunning, m.meta.Name) {
+			state = "not running"
+		}
+		fmt.Printf("  [ok] %-14s %s\n", m.meta.Name, state)
+	}
+}
+
+func handleConductorGroupStatus(args []string) {
+	fs := flag.NewFlagSet("conductor group status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	absolute := fs.Bool("absolute", false, "Show absolute times instead of relative ones")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor group status <group> [options]")
+		fmt.Println()
+		fmt.Println("Show each member's session, heartbeat timer and last/next heartbeat.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	group := loadGroupOrExit(fs, out)
+
+	if !*jsonOutput {
+		fmt.Printf("Group %s (heartbeats: %s)\n", group.Name, group.HeartbeatPlan())
+		if group.Description != "" {
+			fmt.Println(group.Description)
+		}
+		fmt.Println()
+	}
+	printConductorHealth(*jsonOutput, *absolute, group.Members)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleConductorNotify shows or sets a conductor's Slack and Discord
+// notifications, and sends test messages
+func handleConductorNotify(args []string) {
+	fs := flag.NewFlagSet("conductor notify", flag.ExitOnError)
+	slack := fs.String("slack", "", "Slack incoming webhook URL (\"-\" to remove)")
+	discord := fs.String("discord", "", "Discord webhook URL (\"-\" to remove)")
+	events := fs.String("events", "", "Comma-separated events: "+strings.Join(session.ConductorNotifyEvents, ", ")+" (\"-\" for the default)")
+	tmpl := fs.String("template", "", "Message template (text/template), or @file to read it from a file (\"-\" for the default)")
+	clearAll := fs.Bool("clear", false, "Remove all notification settings")
+	test := fs.Bool("test", false, "Send a test message to the configured webhooks")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor notify <name> [options]")
+		fmt.Println()
+		fmt.Println("Show or set where a conductor reports trouble. Messages go to Slack and")
+		fmt.Println("Discord webhooks when one of its heartbeats fails or one of its workers")
+		fmt.Println("errors; worker_idle also reports workers that finished working. Worker")
+		fmt.Println("events are raised while the TUI or web UI is running.")
+		fmt.Println()
+		fmt.Println("Settings are kept in the conductor's meta.json. Webhook URLs contain")
+		fmt.Println("their credentials and are only ever shown masked.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck conductor notify ops --slack https://hooks.slack.com/services/T0/B0/XXX")
+		fmt.Println("  agent-deck conductor notify ops --events heartbeat_failed,worker_error,worker_idle")
+		fmt.Println("  agent-deck conductor notify ops --template '{{.Conductor}}: {{.Event}} {{.Title}}'")
+		fmt.Println("  agent-deck conductor notify ops --test")
+		fmt.Println("  agent-deck conductor notify ops --clear")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	meta, err := session.LoadConductorMeta(name)
+	if err != nil {
+		out.Error(fmt.Sprintf("conductor %q not found", name), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	orClear := func(value string) string {
+		if value == "-" {
+			return ""
+		}
+		return strings.TrimSpace(value)
+	}
+
+	settings := session.ConductorNotifications{}
+	if meta.Notifications != nil && !*clearAll {
+		settings = *meta.Notifications
+	}
+	if set["slack"] {
+		settings.SlackWebhook = orClear(*slack)
+	}
+	if set["discord"] {
+		settings.DiscordWebhook = orClear(*discord)
+	}
+	if set["events"] {
+		settings.Events = splitList(orClear(*events))
+	}
+	if set["template"] {
+		settings.Template = orClear(*tmpl)
+		if path, ok := strings.CutPrefix(settings.Template, "@"); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				out.Error(fmt.Sprintf("failed to read template: %v", err), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+			settings.Template = string(data)
+		}
+	}
+	if err := settings.Validate(); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	changed := *clearAll || set["slack"] || set["discord"] || set["events"] || set["template"]
+	if changed {
+		if settings.SlackWebhook == "" && settings.DiscordWebhook == "" && len(settings.Events) == 0 && settings.Template == "" {
+			meta.Notifications = nil
+		} else {
+			meta.Notifications = &settings
+		}
+		if err := session.SaveConductorMeta(meta); err != nil {
+			out.Error(fmt.Sprintf("failed to save meta.json: %v", err), metaSaveErrCode(err))
+			os.Exit(1)
+		}
+	}
+
+	var sent []string
+	if *test {
+		senders := settings.Senders()
+		if len(senders) == 0 {
+			out.Error(fmt.Sprintf("conductor %s has no Slack or Discord webhook", name), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		notice := &session.ConductorNotice{
+			HookEvent: session.HookEvent{
+				Event:     session.HookEventHeartbeat,
+				Time:      time.Now(),
+				Profile:   meta.Profile,
+				Conductor: name,
+				Result:    session.HeartbeatFailed,
+				Error:     "test message from agent-deck conductor notify",
+			},
+			Event: session.ConductorNotifyHeartbeatFailed,
+		}
+		if err := session.SendConductorNotice(&settings, notice, 10*time.Second); err != nil {
+			out.Error(fmt.Sprintf("test message failed: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		for _, sender := range senders {
+			sent = append(sent, sender.Name())
+		}
+	}
+
+	shownEvents := settings.Events
+	if len(shownEvents) == 0 {
+		shownEvents = session.DefaultConductorNotifyEvents
+	}
+	if *jsonOutput {
+		out.Print("", map[string]any{
+			"name":            name,
+			"slack_webhook":   session.MaskWebhookURL(settings.SlackWebhook),
+			"discord_webhook": session.MaskWebhookURL(settings.DiscordWebhook),
+			"events":          shownEvents,
+			"template":        settings.Template,
+			"test_sent":       sent,
+		})
+		return
+	}
+	if changed {
+		fmt.Printf("[ok] Updated notifications for conductor %s\n", name)
+	}
+	if len(sent) > 0 {
+		fmt.Printf("[ok] Test message sent to %s\n", strings.Join(sent, " and "))
+	}
+	orNone := func(value string) string {
+		if value == "" {
+			return "(none)"
+		}
+		return value
+	}
+	fmt.Printf("Slack:    %s\n", orNone(session.MaskWebhookURL(settings.SlackWebhook)))
+	fmt.Printf("Discord:  %s\n", orNone(session.MaskWebhookURL(settings.DiscordWebhook)))
+	fmt.Printf("Events:   %s\n", strings.Join(shownEvents, ", "))
+	if settings.Template == "" {
+		fmt.Println("Template: (default)")
+	} else {
+		fmt.Printf("Template:\n%s\n", settings.Template)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleConductorRegistry dispatches template/skill pack registry subcommands
+func handleConductorRegistry(args []string) {
+	fs := flag.NewFlagSet("conductor registry", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor registry <command> [options]")
+		fmt.Println()
+		fmt.Println("Fetch and verify conductor templates and skill packs from the registry")
+		fmt.Println("configured in [registry] (HTTP base URL or git repository).")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  list                  List registry entries and versions")
+		fmt.Println("  update                Refresh the registry index")
+		fmt.Println("  fetch <ref>           Download and verify registry://name[@version]")
+		fmt.Println("  verify <ref>          Re-check a fetched entry against its checksum/signature")
+		fmt.Println()
+		fmt.Println("Use fetched entries with:")
+		fmt.Println("  agent-deck conductor setup ops --claude-md registry://sre-oncall@v2")
+		fmt.Println("  agent-deck conductor skills install registry://incident-response")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	subcmd := args[0]
+	if subcmd == "help" || subcmd == "-h" || subcmd == "--help" {
+		fs.Usage()
+		return
+	}
+	if err := fs.Parse(normalizeArgs(fs, args[1:])); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	client, err := session.NewRegistryClient()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	parseRef := func() session.RegistryRef {
+		ref, ok := session.ParseRegistryRef(fs.Arg(0))
+		if !ok {
+			out.Error(fmt.Sprintf("usage: agent-deck conductor registry %s registry://name[@version]", subcmd), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		return ref
+	}
+	fail := func(err error) {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrRegistryEntryNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+
+	switch subcmd {
+	case "list", "ls", "update":
+		var idx *session.RegistryIndex
+		if subcmd == "update" {
+			idx, err = client.UpdateIndex()
+		} else {
+			idx, err = client.Index()
+		}
+		if err != nil {
+			fail(err)
+		}
+		if *jsonOutput {
+			out.Print("", map[string]any{"registry": client.URL, "entries": idx.Entries})
+			return
+		}
+		if subcmd == "update" {
+			fmt.Printf("[ok] Registry index updated (%d entries)\n", len(idx.Entries))
+			return
+		}
+		if len(idx.Entries) == 0 {
+			fmt.Println("Registry has no entries.")
+			return
+		}
+		for _, entry := range idx.Entries {
+			versions := make([]string, 0, len(entry.Versions))
+			for _, v := range entry.Versions {
+				versions = append(versions, v.Version)
+			}
+			fmt.Printf("  %-24s %-11s %s\n", entry.Name, entry.Kind, strings.Join(versions, ", "))
+			if entry.Description != "" {
+				fmt.Printf("    %s\n", entry.Description)
+			}
+		}
+
+	case "fetch":
+		ref := parseRef()
+		dir, entry, err := client.Fetch(ref)
+		if err != nil {
+			fail(err)
+		}
+		out.Success(fmt.Sprintf("Fetched %s %s to %s", entry.Kind, ref, dir), map[string]any{
+			"ref":  ref.String(),
+			"kind": entry.Kind,
+			"path": dir,
+		})
+
+	case "verify":
+		ref := parseRef()
+		if err := client.Verify(ref); err != nil {
+			fail(err)
+		}
+		out.Success(fmt.Sprintf("%s verified", ref), map[string]any{"ref": ref.String(), "verified": true})
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown registry command: %s\n", subcmd)
+		fs.Usage()
+		os.Exit(1)
+	}
+}
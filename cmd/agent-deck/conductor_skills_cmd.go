@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleConductorSkills dispatches skill pack subcommands
+func handleConductorSkills(args []string) {
+	if len(args) == 0 {
+		printConductorSkillsHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleConductorSkillsList(args[1:])
+	case "install":
+		handleConductorSkillsInstall(args[1:])
+	case "update":
+		handleConductorSkillsUpdate(args[1:])
+	case "remove", "rm":
+		handleConductorSkillsRemove(args[1:])
+	case "attach":
+		handleConductorSkillsAttach(args[1:], true)
+	case "detach":
+		handleConductorSkillsAttach(args[1:], false)
+	case "help", "-h", "--help":
+		printConductorSkillsHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown skills command '%s'\n", args[0])
+		printConductorSkillsHelp()
+		os.Exit(1)
+	}
+}
+
+func printConductorSkillsHelp() {
+	fmt.Println("Usage: agent-deck conductor skills <command> [options]")
+	fmt.Println()
+	fmt.Println("Manage skill packs: reusable instruction fragments, canned prompts and hook")
+	fmt.Println("scripts that are composed into a conductor's CLAUDE.md.")
+	fmt.Println()
+	fmt.Println("A pack is a directory with an optional pack.toml (name, version, description)")
+	fmt.Println("and instructions/*.md, prompts/*.md and hooks/* files.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list [conductor]          List installed packs (or those attached to a conductor)")
+	fmt.Println("  install <dir|ref>         Install or replace a pack from a directory or registry://name@version")
+	fmt.Println("  update <pack> [dir]       Reinstall a pack from its source (or a new directory)")
+	fmt.Println("  remove <pack>             Remove an installed pack that is not attached")
+	fmt.Println("  attach <conductor> <pack> Attach a pack to a conductor")
+	fmt.Println("  detach <conductor> <pack> Detach a pack from a conductor")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck conductor skills install ~/src/team-packs/incident-response")
+	fmt.Println("  agent-deck conductor skills attach ops incident-response")
+	fmt.Println("  agent-deck conductor skills update incident-response")
+}
+
+func handleConductorSkillsList(args []string) {
+	fs := flag.NewFlagSet("conductor skills list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor skills list [conductor] [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	packs, err := session.ListSkillPacks()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	// Count attachments, or narrow to one conductor's packs
+	users := make(map[string][]string)
+	conductors, _ := session.ListConductors()
+	for _, meta := range conductors {
+		for _, name := range meta.SkillPacks {
+			users[name] = append(users[name], meta.Name)
+		}
+	}
+	if conductor := fs.Arg(0); conductor != "" {
+		meta, err := session.LoadConductorMeta(conductor)
+		if err != nil {
+			out.Error(fmt.Sprintf("conductor %q not found", conductor), ErrCodeNotFound)
+			os.Exit(1)
+		}
+		attached := make([]session.SkillPack, 0, len(meta.SkillPacks))
+		for _, name := range meta.SkillPacks {
+			pack, err := session.GetSkillPack(name)
+			if err != nil {
+				pack = &session.SkillPack{Name: name}
+			}
+			attached = append(attached, *pack)
+		}
+		packs = attached
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]any{"skill_packs": packs, "attached_to": users})
+		return
+	}
+	if len(packs) == 0 {
+		fmt.Println("No skill packs.")
+		fmt.Println("Install one with: agent-deck conductor skills install <dir>")
+		return
+	}
+	for _, pack := range packs {
+		version := ""
+		if pack.Version != "" {
+			version = " v" + strings.TrimPrefix(pack.Version, "v")
+		}
+		fmt.Printf("  %-24s%-10s %d instructions, %d prompts, %d hooks", pack.Name, version,
+			len(pack.Instructions), len(pack.Prompts), len(pack.Hooks))
+		if len(users[pack.Name]) > 0 {
+			fmt.Printf("  [%s]", strings.Join(users[pack.Name], ", "))
+		}
+		if pack.Path == "" {
+			fmt.Print("  (missing)")
+		}
+		fmt.Println()
+		if pack.Description != "" {
+			fmt.Printf("    %s\n", pack.Description)
+		}
+	}
+}
+
+func handleConductorSkillsInstall(args []string) {
+	fs := flag.NewFlagSet("conductor skills install", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck conductor skills install <dir|registry://name[@version]>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	pack, err := session.InstallSkillPack(fs.Arg(0))
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to install skill pack: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Installed skill pack %s", pack.Name), map[string]any{"skill_pack": pack})
+}
+
+func handleConductorSkillsUpdate(args []string) {
+	fs := flag.NewFlagSet("conductor skills update", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		out.Error("usage: agent-deck conductor skills update <pack> [dir]", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	pack, err := session.UpdateSkillPack(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrSkillPackNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(fmt.Sprintf("failed to update skill pack: %v", err), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Updated skill pack %s from %s", pack.Name, pack.Source), map[string]any{"skill_pack": pack})
+}
+
+func handleConductorSkillsRemove(args []string) {
+	fs := flag.NewFlagSet("conductor skills remove", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck conductor skills remove <pack>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if err := session.RemoveSkillPack(fs.Arg(0)); err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrSkillPackNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Removed skill pack %s", fs.Arg(0)), map[string]any{"name": fs.Arg(0)})
+}
+
+// handleConductorSkillsAttach attaches (or detaches) a pack and recomposes the conductor's CLAUDE.md
+func handleConductorSkillsAttach(args []string, attach bool) {
+	verb := "attach"
+	if !attach {
+		verb = "detach"
+	}
+	fs := flag.NewFlagSet("conductor skills "+verb, flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 2 {
+		out.Error(fmt.Sprintf("usage: agent-deck conductor skills %s <conductor> <pack>", verb), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	conductor, name := fs.Arg(0), fs.Arg(1)
+
+	var err error
+	if attach {
+		err = session.AttachSkillPack(conductor, name)
+	} else {
+		err = session.DetachSkillPack(conductor, name)
+	}
+	warning := ""
+	if errors.Is(err, session.ErrConductorClaudeMDLinked) {
+		// meta.json and SKILLS.md are updated; only the import needs a manual edit
+		warning = err.Error()
+		err = nil
+	}
+	if err != nil {
+		code := ErrCodeInvalidOperation
+		switch {
+		case errors.Is(err, session.ErrSkillPackNotFound):
+			code = ErrCodeNotFound
+		case errors.Is(err, session.ErrSkillPackAttached):
+			code = ErrCodeAlreadyExists
+		}
+		out.Error(fmt.Sprintf("failed to %s skill pack: %v", verb, err), code)
+		os.Exit(1)
+	}
+
+	msg := fmt.Sprintf("Attached %s to conductor %s", name, conductor)
+	if !attach {
+		msg = fmt.Sprintf("Detached %s from conductor %s", name, conductor)
+	}
+	if warning != "" && !*jsonOutput {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	out.Success(msg+" (takes effect on the conductor's next restart)", map[string]any{
+		"conductor": conductor,
+		"pack":      name,
+		"attached":  attach,
+		"warning":   warning,
+	})
+}
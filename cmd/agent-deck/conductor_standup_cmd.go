@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleConductorStandup compiles the cross-conductor standup and optionally
+// posts it to the [conductor.standup] sinks
+func handleConductorStandup(args []string) {
+	fs := flag.NewFlagSet("conductor standup", flag.ExitOnError)
+	post := fs.Bool("post", false, "Post the standup to the [conductor.standup] sinks")
+	ifDue := fs.Bool("if-due", false, "Only run when [conductor.standup] schedule is due (used by heartbeats)")
+	hours := fs.Int("hours", 0, "Hours of task-log.md counted as done (default: [conductor.standup] hours, then 24)")
+	summarize := fs.Bool("summarize", false, "Condense the standup with the [summarizer] backend (default: [conductor.standup] summarize)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor standup [name...] [options]")
+		fmt.Println()
+		fmt.Println("Compile a standup across conductors from their memory: done (task-log.md")
+		fmt.Println("entries in the window), doing (state.json session summaries) and blocked")
+		fmt.Println("(escalated sessions and NEED: lines). With [conductor.standup] schedule set,")
+		fmt.Println("heartbeats post it to the configured sinks on schedule.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck conductor standup")
+		fmt.Println("  agent-deck conductor standup ops triage --post")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	quietMode := *quiet || *quietShort
+	conductorSettings := session.GetConductorSettings()
+	settings := conductorSettings.Standup
+	now := time.Now()
+
+	if *ifDue {
+		if settings.Schedule == "" {
+			return
+		}
+		last := session.LastStandup()
+		due, err := session.StandupDue(settings.Schedule, last, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: [conductor.standup] schedule: %v\n", err)
+			os.Exit(1)
+		}
+		if !due {
+			return
+		}
+		// Another conductor's heartbeat may be posting the same standup
+		if claimed, err := session.ClaimStandup(last, now); err != nil || !claimed {
+			return
+		}
+	}
+	if *post && !settings.HasSink() {
+		fmt.Fprintln(os.Stderr, "Error: no standup sink configured; set webhook_url, command, slack or telegram in [conductor.standup]")
+		os.Exit(1)
+	}
+
+	conductors, err := session.ListConductors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing conductors: %v\n", err)
+		os.Exit(1)
+	}
+	if names := fs.Args(); len(names) > 0 {
+		conductors = slices.DeleteFunc(conductors, func(meta session.ConductorMeta) bool {
+			return !slices.Contains(names, meta.Name)
+		})
+	}
+
+	window := *hours
+	if window <= 0 {
+		window = settings.GetHours()
+	}
+	standup := session.BuildStandup(conductors, window, now)
+	text := standup.Markdown()
+	summarizedBy := ""
+	if *summarize || settings.Summarize {
+		header, body, _ := strings.Cut(text, "\n")
+		summary, backend, err := session.Summarize(context.Background(), "this team standup", body)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the %s summary\n", err, backend)
+		}
+		text = header + "\n" + summary + "\n"
+		summarizedBy = backend
+	}
+
+	if *post {
+		if err := session.PostStandup(settings, conductorSettings, text); err != nil {
+			fmt.Fprintf(os.Stderr, "Error posting standup: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	switch {
+	case *jsonOutput:
+		output, _ := json.MarshalIndent(map[string]any{
+			"standup":       standup,
+			"posted":        *post,
+			"summarized_by": summarizedBy,
+			"text":          text,
+		}, "", "  ")
+		fmt.Println(string(output))
+	case quietMode:
+	default:
+		fmt.Print(text)
+		if *post {
+			fmt.Println()
+			fmt.Println("[ok] Standup posted")
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleConductorTask dispatches conductor task queue subcommands
+func handleConductorTask(args []string) {
+	if len(args) == 0 {
+		printConductorTaskHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "add":
+		handleConductorTaskAdd(args[1:])
+	case "list", "ls":
+		handleConductorTaskList(args[1:])
+	case "show":
+		handleConductorTaskShow(args[1:])
+	case "dispatch":
+		handleConductorTaskDispatch(args[1:])
+	case "cancel":
+		handleConductorTaskCancel(args[1:])
+	case "help", "-h", "--help":
+		printConductorTaskHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown task command '%s'\n", args[0])
+		printConductorTaskHelp()
+		os.Exit(1)
+	}
+}
+
+func printConductorTaskHelp() {
+	fmt.Println("Usage: agent-deck conductor task <command> [options]")
+	fmt.Println()
+	fmt.Println("Hand work from a conductor to worker sessions. Queued tasks wait in")
+	fmt.Println("~/.agent-deck/conductor/<name>/tasks/; each heartbeat injects the oldest")
+	fmt.Println("task of every idle worker into its session and completes the tasks whose")
+	fmt.Println("worker has finished, reporting them in the heartbeat message.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  add <conductor> <worker> <prompt>  Queue a prompt for a worker session")
+	fmt.Println("  list <conductor>                   List queued, active and finished tasks")
+	fmt.Println("  show <conductor> <id>              Show a task and the worker's reply")
+	fmt.Println("  dispatch <conductor>               Dispatch and complete tasks now")
+	fmt.Println("  cancel <conductor> <id>            Cancel a task, interrupting its worker if it is running")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck conductor task add ops api-server \"Run the test suite and fix failures\"")
+	fmt.Println("  agent-deck conductor task list ops --state active")
+	fmt.Println("  agent-deck conductor task dispatch ops")
+	fmt.Println("  agent-deck conductor task cancel ops 20260309T090000.000000000-a1b2c3 --summarize")
+}
+
+func handleConductorTaskAdd(args []string) {
+	fs := flag.NewFlagSet("conductor task add", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	dispatch := fs.Bool("now", false, "Dispatch right away instead of at the next heartbeat")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor task add <conductor> <worker> <prompt> [options]")
+		fmt.Println()
+		fmt.Println("Queue a prompt for a worker session (title or ID).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() < 3 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	task, err := session.EnqueueTask(fs.Arg(0), fs.Arg(1), strings.Join(fs.Args()[2:], " "))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *dispatch {
+		if _, err := runConductorDispatch(task.Conductor); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if tasks, err := session.ListTasks(task.Conductor, ""); err == nil {
+			for i := range tasks {
+				if tasks[i].ID == task.ID {
+					task = &tasks[i]
+				}
+			}
+		}
+	}
+	out.Success(fmt.Sprintf("Task %s for %s is %s", task.ID, task.Worker, task.State), map[string]any{
+		"success": true,
+		"task":    task,
+	})
+}
+
+func handleConductorTaskList(args []string) {
+	fs := flag.NewFlagSet("conductor task list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	state := fs.String("state", "", "Only tasks in this state (pending, active, done, failed, cancelled)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor task list <conductor> [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	switch *state {
+	case "", session.TaskPending, session.TaskActive, session.TaskDone, session.TaskFailed, session.TaskCancelled:
+	default:
+		out.Error(fmt.Sprintf("unknown task state %q", *state), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	tasks, err := session.ListTasks(fs.Arg(0), *state)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *jsonOutput {
+		if tasks == nil {
+			tasks = []session.ConductorTask{}
+		}
+		out.Print("", map[string]any{"tasks": tasks})
+		return
+	}
+	if len(tasks) == 0 {
+		fmt.Println("No tasks.")
+		return
+	}
+	now := time.Now()
+	formatter := session.NewTimeFormatter(false)
+	fmt.Printf("%-36s %-8s %-20s %-14s %s\n", "ID", "STATE", "WORKER", "QUEUED", "PROMPT")
+	for _, task := range tasks {
+		fmt.Printf("%-36s %-8s %-20s %-14s %s\n", task.ID, task.State, truncate(task.Worker, 20),
+			formatter.Format(task.CreatedAt, now), truncate(task.Prompt, 50))
+	}
+}
+
+func handleConductorTaskShow(args []string) {
+	fs := flag.NewFlagSet("conductor task show", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor task show <conductor> <id> [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	tasks, err := session.ListTasks(fs.Arg(0), "")
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var task *session.ConductorTask
+	for i := range tasks {
+		if tasks[i].ID == fs.Arg(1) {
+			task = &tasks[i]
+		}
+	}
+	if task == nil {
+		out.Error(fmt.Sprintf("%v: %s", session.ErrTaskNotFound, fs.Arg(1)), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	if *jsonOutput {
+		out.Print("", task)
+		return
+	}
+	fmt.Printf("Task:    %s\n", task.ID)
+	fmt.Printf("Worker:  %s\n", task.Worker)
+	fmt.Printf("State:   %s\n", task.State)
+	fmt.Printf("Queued:  %s\n", task.CreatedAt.Local().Format(time.RFC3339))
+	if !task.ClaimedAt.IsZero() {
+		fmt.Printf("Sent:    %s\n", task.ClaimedAt.Local().Format(time.RFC3339))
+	}
+	if !task.CompletedAt.IsZero() {
+		fmt.Printf("Done:    %s\n", task.CompletedAt.Local().Format(time.RFC3339))
+	}
+	if task.Error != "" {
+		fmt.Printf("Error:   %s\n", task.Error)
+	}
+	fmt.Println()
+	fmt.Println(task.Prompt)
+	if task.Result != "" {
+		fmt.Println()
+		fmt.Println("Reply:")
+		fmt.Println(task.Result)
+	}
+}
+
+func handleConductorTaskDispatch(args []string) {
+	fs := flag.NewFlagSet("conductor task dispatch", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor task dispatch <conductor> [options]")
+		fmt.Println()
+		fmt.Println("Run the heartbeat's task pass now: complete the tasks whose worker has")
+		fmt.Println("finished, then inject the next task of every idle worker.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	report, err := runConductorDispatch(fs.Arg(0))
+	if err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, os.ErrNotExist) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Dispatched %d task(s), %d finished", len(report.Dispatched), len(report.Completed)), map[string]any{
+		"success":    true,
+		"dispatched": report.Dispatched,
+		"completed":  report.Completed,
+	})
+}
+
+func handleConductorTaskCancel(args []string) {
+	fs := flag.NewFlagSet("conductor task cancel", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	summarize := fs.Bool("summarize", false, "After interrupting, ask the worker to summarize where it was")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor task cancel <conductor> <id> [options]")
+		fmt.Println()
+		fmt.Println("Cancel a queued task, or stop a running one: the worker gets its tool's")
+		fmt.Println("interrupt keys (the \"interrupt\" macro) and the task is marked cancelled.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	meta, err := session.LoadConductorMeta(fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	_, instances, _, err := loadSessionData(meta.Profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	followUp := ""
+	if *summarize {
+		followUp = session.InterruptSummaryPrompt
+	}
+	task, err := session.CancelTask(fs.Arg(0), fs.Arg(1), instances, followUp)
+	if task == nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrTaskNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: task cancelled, but %v\n", err)
+	}
+	out.Success(fmt.Sprintf("Cancelled task %s for %s", task.ID, task.Worker), map[string]any{
+		"success": true,
+		"task":    task,
+	})
+}
+
+// runConductorDispatch runs one task pass for a conductor against the
+// current sessions of its profile
+func runConductorDispatch(name string) (*session.TaskDispatchReport, error) {
+	meta, err := session.LoadConductorMeta(name)
+	if err != nil {
+		return nil, err
+	}
+	_, instances, _, err := loadSessionData(meta.Profile)
+	if err != nil {
+		return nil, err
+	}
+	for _, inst := range instances {
+		_ = inst.UpdateStatus()
+	}
+	return session.DispatchConductorTasks(name, instances, sendTaskPrompt)
+}
+
+// sendTaskPrompt injects a task prompt into a worker session, retrying Enter
+// until the agent starts on it like session send does
+func sendTaskPrompt(inst *session.Instance, prompt string) error {
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil || !inst.Exists() {
+		return fmt.Errorf("session '%s' is not running", inst.Title)
+	}
+	return sendWithRetry(tmuxSess, prompt, false)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// handleCost dispatches cost subcommands
+func handleCost(profile string, args []string) {
+	if len(args) == 0 {
+		printCostHelp()
+		return
+	}
+
+	switch args[0] {
+	case "export":
+		handleCostExport(profile, args[1:])
+	case "help", "--help", "-h":
+		printCostHelp()
+	default:
+		fmt.Printf("Unknown cost command: %s\n", args[0])
+		fmt.Println()
+		printCostHelp()
+		os.Exit(1)
+	}
+}
+
+// printCostHelp prints usage for cost commands
+func printCostHelp() {
+	fmt.Println("Usage: agent-deck cost <command> [options]")
+	fmt.Println()
+	fmt.Println("Per-session cost and utilization, for spreadsheets and finance dashboards.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  export    Write daily token usage, busy time, tasks completed and cost as CSV")
+}
+
+// handleCostExport writes the per-session, per-day cost report
+func handleCostExport(profile string, args []string) {
+	fs := flag.NewFlagSet("cost export", flag.ExitOnError)
+	from := fs.String("from", "", "First day to include, YYYY-MM-DD (default: --days before --to)")
+	to := fs.String("to", "", "Last day to include, YYYY-MM-DD (default: today)")
+	days := fs.Int("days", 7, "Number of days to include when --from is not given")
+	format := fs.String("format", "csv", "Output format: csv or json")
+	output := fs.String("o", "", "Write to this file instead of stdout")
+	allProfiles := fs.Bool("all-profiles", false, "Include every profile, not just the current one")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck cost export [options]")
+		fmt.Println()
+		fmt.Println("Write one row per session and day with token usage, busy time, tasks")
+		fmt.Println("completed and estimated cost. Tokens and cost are read from Claude and")
+		fmt.Println("Codex transcripts; busy time is the time the session was seen running")
+		fmt.Println("while agent-deck was polling. Days a session did nothing are left out.")
+		fmt.Println()
+		fmt.Println("Columns: day, profile, session_id, title, tool, group, input_tokens,")
+		fmt.Println("output_tokens, cache_read_tokens, cache_write_tokens, total_tokens,")
+		fmt.Println("busy_seconds, tasks_completed, cost_usd, conductor")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck cost export                        # Last 7 days as CSV")
+		fmt.Println("  agent-deck cost export --from 2026-01-01 --to 2026-01-31 -o jan.csv")
+		fmt.Println("  agent-deck cost export --all-profiles --format json | jq .")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (use csv or json)\n", *format)
+		os.Exit(1)
+	}
+
+	fromDay, toDay, err := reportDayRange(*from, *to, *days)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	rows, err := costReportRows(profile, *allProfiles, fromDay, toDay)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var buf bytes.Buffer
+	if *format == "json" {
+		data, _ := json.MarshalIndent(map[string]any{
+			"from": fromDay,
+			"to":   toDay,
+			"rows": rows,
+		}, "", "  ")
+		buf.Write(data)
+		buf.WriteByte('\n')
+	} else if err := session.WriteCostReportCSV(&buf, rows); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		_, _ = os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("[ok] %d row(s) for %s to %s written to %s\n", len(rows), fromDay, toDay, *output)
+}
+
+// reportDayRange resolves --from/--to/--days into inclusive DayKeys: to
+// defaults to today and from to days before it
+func reportDayRange(from, to string, days int) (string, string, error) {
+	if to == "" {
+		to = statedb.DayKey(time.Now())
+	}
+	if from != "" {
+		return from, to, nil
+	}
+	end, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid --to %q: want YYYY-MM-DD", to)
+	}
+	if days < 1 {
+		return "", "", fmt.Errorf("--days must be at least 1")
+	}
+	return statedb.DayKey(end.AddDate(0, 0, -(days - 1))), to, nil
+}
+
+// costReportRows returns the cost report of profile, or of every profile
+func costReportRows(profile string, allProfiles bool, fromDay, toDay string) ([]session.CostReportRow, error) {
+	profiles := []string{profile}
+	if allProfiles {
+		var err error
+		if profiles, err = session.ListProfiles(); err != nil {
+			return nil, fmt.Errorf("failed to list profiles: %w", err)
+		}
+	}
+	rows := []session.CostReportRow{}
+	for _, p := range profiles {
+		profileRows, err := session.CostReport(p, fromDay, toDay)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, profileRows...)
+	}
+	return rows, nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
+)
+
+// extractDeckFlag pulls the global --deck flag out of args, like
+// extractProfileFlag does for -p
+func extractDeckFlag(args []string) (string, []string) {
+	var name string
+	var remaining []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "--deck=") {
+			name = strings.TrimPrefix(arg, "--deck=")
+			continue
+		}
+		if arg == "--deck" && i+1 < len(args) {
+			name = args[i+1]
+			i++
+			continue
+		}
+		remaining = append(remaining, arg)
+	}
+	return name, remaining
+}
+
+// currentDeckName returns the deck name for display ("default" for the default deck)
+func currentDeckName() string {
+	if name := deck.Name(); name != "" {
+		return name
+	}
+	return deck.DefaultName
+}
+
+// handleDeck dispatches deck subcommands
+func handleDeck(args []string) {
+	if len(args) == 0 {
+		handleDeckList(nil)
+		return
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleDeckList(args[1:])
+	case "current":
+		handleDeckCurrent(args[1:])
+	case "help", "--help", "-h":
+		printDeckHelp()
+	default:
+		fmt.Printf("Unknown deck command: %s\n", args[0])
+		fmt.Println()
+		printDeckHelp()
+		os.Exit(1)
+	}
+}
+
+// printDeckHelp prints usage for deck commands
+func printDeckHelp() {
+	fmt.Println("Usage: agent-deck deck <command> [options]")
+	fmt.Println()
+	fmt.Println("Decks keep separate workspaces (e.g. work, personal, oss) in one account.")
+	fmt.Println("Each deck has its own sessions, profiles, config, conductors and heartbeat")
+	fmt.Println("schedulers in ~/.agent-deck-<name>, and its own tmux server. The default")
+	fmt.Println("deck is ~/.agent-deck.")
+	fmt.Println()
+	fmt.Println("Select a deck with --deck <name> on any command, or AGENT_DECK_NAME=<name>.")
+	fmt.Println("A deck is created the first time it is used.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list       List decks with their session counts (default)")
+	fmt.Println("  current    Show the selected deck")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck --deck work                 # Open the TUI on the work deck")
+	fmt.Println("  agent-deck --deck oss add . -c claude  # Add a session to the oss deck")
+	fmt.Println("  agent-deck list --all-decks            # Sessions of every deck")
+}
+
+// deckSession is a session as listed across decks
+type deckSession struct {
+	Deck        string    `json:"deck"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Path        string    `json:"path"`
+	Group       string    `json:"group"`
+	Tool        string    `json:"tool"`
+	Command     string    `json:"command,omitempty"`
+	Profile     string    `json:"profile"`
+	CreatedAt   time.Time `json:"created_at"`
+	StartReason string    `json:"start_reason,omitempty"`
+}
+
+// loadDeckSessions lists a deck's sessions in all its profiles. It runs
+// "list --all --json" against the deck in a child process, so config and
+// state caches of the current deck are never mixed with another deck's.
+func loadDeckSessions(d deck.Info) ([]deckSession, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(exe, "list", "--all", "--json")
+	cmd.Env = append(os.Environ(), deck.EnvName+"="+d.Name, deck.EnvHome+"="+d.Home)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("deck %s: %w", d.Name, err)
+	}
+	// "No profiles found." for a deck that was never used
+	if !strings.HasPrefix(strings.TrimSpace(string(out)), "[") {
+		return nil, nil
+	}
+	var sessions []deckSession
+	if err := json.Unmarshal(out, &sessions); err != nil {
+		return nil, fmt.Errorf("deck %s: failed to parse sessions: %w", d.Name, err)
+	}
+	for i := range sessions {
+		sessions[i].Deck = d.Name
+	}
+	return sessions, nil
+}
+
+func handleDeckList(args []string) {
+	fs := flag.NewFlagSet("deck list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	decks, err := deck.List()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	type deckJSON struct {
+		deck.Info
+		Sessions int    `json:"sessions"`
+		Error    string `json:"error,omitempty"`
+	}
+	rows := make([]deckJSON, len(decks))
+	for i, d := range decks {
+		rows[i].Info = d
+		sessions, err := loadDeckSessions(d)
+		if err != nil {
+			rows[i].Error = err.Error()
+		}
+		rows[i].Sessions = len(sessions)
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]any{"current": currentDeckName(), "decks": rows})
+		return
+	}
+	for _, row := range rows {
+		marker := " "
+		if row.Current {
+			marker = "*"
+		}
+		count := fmt.Sprintf("%d sessions", row.Sessions)
+		if row.Error != "" {
+			count = "unreadable"
+		}
+		fmt.Printf("%s %-14s %-14s %s\n", marker, row.Name, count, row.Home)
+	}
+}
+
+func handleDeckCurrent(args []string) {
+	fs := flag.NewFlagSet("deck current", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	home, err := deck.Home()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	socket := deck.TmuxSocket()
+	if *jsonOutput {
+		out.Print("", map[string]any{"name": currentDeckName(), "home": home, "tmux_socket": socket})
+		return
+	}
+	if socket == "" {
+		socket = "default"
+	}
+	fmt.Printf("%s (%s, tmux socket %s)\n", currentDeckName(), home, socket)
+}
+
+// handleListAllDecks lists the sessions of every deck, grouped by deck
+func handleListAllDecks(jsonOutput bool) {
+	decks, err := deck.List()
+	if err != nil {
+		fmt.Printf("Error: failed to list decks: %v\n", err)
+		os.Exit(1)
+	}
+
+	var all []deckSession
+	total := 0
+	for _, d := range decks {
+		sessions, err := loadDeckSessions(d)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		if jsonOutput {
+			all = append(all, sessions...)
+			continue
+		}
+		if len(sessions) == 0 {
+			continue
+		}
+		fmt.Printf("\n═══ Deck: %s ═══\n\n", d.Name)
+		fmt.Printf("%-*s %-*s %-*s %-*s %s\n", tableColTitle, "TITLE", tableColGroup, "PROFILE/GROUP", tableColPath, "PATH", tableColIDDisplay, "ID", "REASON")
+		fmt.Println(strings.Repeat("-", tableColTitle+tableColGroup+tableColPath+tableColIDDisplay+tableColReason+6))
+		for _, s := range sessions {
+			idDisplay := s.ID
+			if len(idDisplay) > tableColIDDisplay {
+				idDisplay = idDisplay[:tableColIDDisplay]
+			}
+			reason := "-"
+			if s.StartReason != "" {
+				reason = truncate(s.StartReason, tableColReason)
+			}
+			group := s.Profile
+			if s.Group != "" {
+				group += "/" + s.Group
+			}
+			fmt.Printf("%-*s %-*s %-*s %-*s %s\n", tableColTitle, truncate(s.Title, tableColTitle), tableColGroup, truncate(group, tableColGroup), tableColPath, truncate(s.Path, tableColPath), tableColIDDisplay, idDisplay, reason)
+		}
+		fmt.Printf("(%d sessions)\n", len(sessions))
+		total += len(sessions)
+	}
+
+	if jsonOutput {
+		if all == nil {
+			all = []deckSession{}
+		}
+		output, err := json.MarshalIndent(all, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: failed to format JSON output: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(output))
+		return
+	}
+	fmt.Printf("\n═══════════════════════════════════════\n")
+	fmt.Printf("Total: %d sessions across %d decks\n", total, len(decks))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleEmergencyStop stops, resumes or reports the deck's emergency stop
+func handleEmergencyStop(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "resume":
+			handleEmergencyResume(args[1:])
+			return
+		case "status":
+			handleEmergencyStatus(args[1:])
+			return
+		case "help", "--help", "-h":
+			printEmergencyStopHelp()
+			return
+		}
+	}
+
+	fs := flag.NewFlagSet("emergency-stop", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	by := fs.String("by", "cli", "Where the stop came from (recorded with it)")
+	fs.Usage = printEmergencyStopHelp
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	state, err := session.EmergencyStop(strings.Join(fs.Args(), " "), *by)
+	if state == nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	for _, e := range state.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: could not interrupt %s\n", e)
+	}
+	out.Success(fmt.Sprintf("Emergency stop: interrupted %d session(s); run 'agent-deck emergency-stop resume' to continue",
+		len(state.Interrupted)), map[string]any{
+		"success":        true,
+		"emergency_stop": state,
+	})
+}
+
+func printEmergencyStopHelp() {
+	fmt.Println("Usage: agent-deck emergency-stop [reason] [options]")
+	fmt.Println("       agent-deck emergency-stop resume | status")
+	fmt.Println()
+	fmt.Println("The big red button: interrupt every busy session in every profile and")
+	fmt.Println("pause the deck. Until it is resumed, sessions take no messages and")
+	fmt.Println("heartbeats, conductor task dispatch and auto-restarts are paused.")
+	fmt.Println("Also available as Ctrl+X in the TUI, /stop in the conductor bridge and")
+	fmt.Println("POST /api/emergency-stop in the web server.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  [reason]   Stop the deck now (default)")
+	fmt.Println("  resume     Lift the stop")
+	fmt.Println("  status     Show whether the deck is stopped (-q exits 0 only when stopped)")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck emergency-stop \"agent is deleting files\"")
+	fmt.Println("  agent-deck emergency-stop status")
+	fmt.Println("  agent-deck emergency-stop resume")
+}
+
+func handleEmergencyResume(args []string) {
+	fs := flag.NewFlagSet("emergency-stop resume", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	state, err := session.ResumeEmergencyStop()
+	if err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrNotEmergencyStopped) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Resumed the deck (stopped %s)", session.NewTimeFormatter(false).Format(state.StoppedAt, time.Now())),
+		map[string]any{
+			"success":        true,
+			"emergency_stop": state,
+		})
+}
+
+func handleEmergencyStatus(args []string) {
+	fs := flag.NewFlagSet("emergency-stop status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "No output; exit 0 when stopped, 1 otherwise")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	state := session.LoadEmergencyStop()
+	switch {
+	case *quiet:
+		if state == nil {
+			os.Exit(1)
+		}
+	case *jsonOutput:
+		NewCLIOutput(true, false).Print("", map[string]any{"stopped": state != nil, "emergency_stop": state})
+	case state == nil:
+		fmt.Println("The deck is running.")
+	default:
+		fmt.Println(state.Describe())
+		if len(state.Interrupted) > 0 {
+			fmt.Printf("Interrupted: %s\n", strings.Join(state.Interrupted, ", "))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleEvents runs until interrupted, writing status changes, escalations
+// and heartbeat results to stdout as newline-delimited JSON
+func handleEvents(profile string, args []string) {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	interval := fs.Duration("interval", defaultStatusMaxAge, "How often to poll session statuses")
+	allProfiles := fs.Bool("all", false, "Watch sessions of all profiles")
+	initial := fs.Bool("initial", false, "Emit the current status of every session on start")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck --events [options]")
+		fmt.Println()
+		fmt.Println("Stream events as newline-delimited JSON on stdout until interrupted, for")
+		fmt.Println("piping into jq, log shippers or custom supervisors without the bridge.")
+		fmt.Println()
+		fmt.Println("Event types:")
+		fmt.Println("  status_changed   A session's status changed (status, prev_status)")
+		fmt.Println("  escalation       A non-conductor session started waiting without an ack, or its")
+		fmt.Println("                   risk rose to high or critical (risk, reason)")
+		fmt.Println("  heartbeat        A conductor heartbeat was sent, skipped or failed (result, error)")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck --events | jq -c 'select(.type == \"escalation\")'")
+		fmt.Println("  agent-deck --events --all --initial >> ~/agent-deck-events.jsonl")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+		os.Exit(1)
+	}
+
+	profiles := []string{session.GetEffectiveProfile(profile)}
+	if *allProfiles {
+		all, err := session.ListProfiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to list profiles: %v\n", err)
+			os.Exit(1)
+		}
+		profiles = all
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	storages := make(map[string]*session.Storage, len(profiles))
+	defer func() {
+		for _, storage := range storages {
+			_ = storage.Close()
+		}
+	}()
+
+	stream := session.NewEventStream()
+	stream.Initial = *initial
+	enc := json.NewEncoder(os.Stdout)
+	emit := func(events []session.StreamEvent) {
+		for _, event := range events {
+			_ = enc.Encode(event)
+		}
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		for _, p := range profiles {
+			storage, ok := storages[p]
+			if !ok {
+				var err error
+				if storage, err = session.NewStorageWithProfile(p); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: profile %s: %v\n", p, err)
+					continue
+				}
+				storages[p] = storage
+			}
+			emit(pollEventStatuses(stream, storage, *interval))
+		}
+		if results, err := session.ReadHeartbeatResults(); err == nil {
+			emit(stream.ObserveHeartbeats(results, first))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollEventStatuses reloads one profile's sessions, refreshes their statuses
+// and returns what changed since the previous poll
+func pollEventStatuses(stream *session.EventStream, storage *session.Storage, maxAge time.Duration) []session.StreamEvent {
+	instances, _, err := storage.LoadWithGroups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: profile %s: %v\n", storage.Profile(), err)
+		return nil
+	}
+	acked := make(map[string]bool)
+	if db := storage.GetDB(); db != nil {
+		if rows, err := db.ReadAllStatuses(); err == nil {
+			for id, row := range rows {
+				acked[id] = row.Acknowledged
+			}
+		}
+	}
+	refreshStatuses(storage.Profile(), instances, maxAge)
+	session.AssessRisks(instances)
+	return stream.ObserveStatuses(storage.Profile(), instances, acked, time.Now())
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleFeatures dispatches feature flag subcommands
+func handleFeatures(profile string, args []string) {
+	if len(args) == 0 {
+		handleFeaturesList(profile, nil)
+		return
+	}
+
+	switch args[0] {
+	case "list":
+		handleFeaturesList(profile, args[1:])
+	case "enable":
+		handleFeaturesSet(profile, "enable", args[1:])
+	case "disable":
+		handleFeaturesSet(profile, "disable", args[1:])
+	case "reset":
+		handleFeaturesSet(profile, "reset", args[1:])
+	case "help", "--help", "-h":
+		printFeaturesHelp()
+	default:
+		fmt.Printf("Unknown features command: %s\n", args[0])
+		fmt.Println()
+		printFeaturesHelp()
+		os.Exit(1)
+	}
+}
+
+// printFeaturesHelp prints usage for feature flag commands
+func printFeaturesHelp() {
+	fmt.Println("Usage: agent-deck features <command> [options]")
+	fmt.Println()
+	fmt.Println("Feature flags gate experimental subsystems; all are off by default.")
+	fmt.Println("A session's own value wins over [profiles.<name>.features], which wins")
+	fmt.Println("over [features] in config.toml.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                 Show flags and their effective values (default)")
+	fmt.Println("  enable <flag>        Turn a flag on for the deck, or one session with --session")
+	fmt.Println("  disable <flag>       Turn a flag off for the deck, or one session with --session")
+	fmt.Println("  reset <flag>         Remove the deck (or session) value so it falls back")
+	fmt.Println()
+	fmt.Println("Flags:")
+	for _, f := range session.FeatureFlags {
+		fmt.Printf("  %-14s %s\n", f.Name, f.Description)
+	}
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck features")
+	fmt.Println("  agent-deck features enable auto-restart")
+	fmt.Println("  agent-deck features enable auto-restart --session my-project")
+}
+
+func handleFeaturesList(profile string, args []string) {
+	fs := flag.NewFlagSet("features list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	sessionRef := fs.String("session", "", "Show the flags of this session (id or title)")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	var overrides map[string]bool
+	if *sessionRef != "" {
+		_, instances, _, err := loadSessionData(profile)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(1)
+		}
+		inst, errMsg, errCode := ResolveSession(*sessionRef, instances)
+		if inst == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(1)
+			return // unreachable, satisfies staticcheck SA5011
+		}
+		overrides = inst.Features
+	}
+
+	states := session.ResolveFeatures(profile, overrides)
+	if *jsonOutput {
+		out.Print("", map[string]any{"profile": session.GetEffectiveProfile(profile), "features": states})
+		return
+	}
+	for _, s := range states {
+		value := "off"
+		if s.Enabled {
+			value = "on"
+		}
+		fmt.Printf("  %-14s %-4s %-8s %s\n", s.Name, value, s.Source, s.Description)
+	}
+}
+
+func handleFeaturesSet(profile, action string, args []string) {
+	fs := flag.NewFlagSet("features "+action, flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	sessionRef := fs.String("session", "", "Change the flag for this session only (id or title)")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	if fs.NArg() != 1 {
+		out.Error(fmt.Sprintf("usage: agent-deck features %s <flag> [--session <id>]", action), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	var value *bool
+	if action != "reset" {
+		enabled := action == "enable"
+		value = &enabled
+	}
+
+	fail := func(err error) {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrUnknownFeature) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+
+	scope := "deck"
+	if *sessionRef == "" {
+		if err := session.SetDeckFeature(name, value); err != nil {
+			fail(err)
+		}
+	} else {
+		storage, instances, _, err := loadSessionData(profile)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(1)
+		}
+		inst, errMsg, errCode := ResolveSession(*sessionRef, instances)
+		if inst == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(1)
+			return // unreachable, satisfies staticcheck SA5011
+		}
+		if err := inst.SetFeatureOverride(name, value); err != nil {
+			fail(err)
+		}
+		if err := saveSessionData(storage, instances); err != nil {
+			out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		scope = fmt.Sprintf("session '%s'", inst.Title)
+	}
+
+	verb := map[string]string{"enable": "Enabled", "disable": "Disabled", "reset": "Reset"}[action]
+	out.Success(fmt.Sprintf("%s %s for %s", verb, name, scope), map[string]any{
+		"success": true,
+		"feature": name,
+		"action":  action,
+		"session": *sessionRef,
+	})
+}
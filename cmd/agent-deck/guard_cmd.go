@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleGuard dispatches command guard subcommands
+func handleGuard(profile string, args []string) {
+	if len(args) == 0 {
+		handleGuardStatus(profile, nil)
+		return
+	}
+
+	switch args[0] {
+	case "status":
+		handleGuardStatus(profile, args[1:])
+	case "list", "ls":
+		handleGuardList(args[1:])
+	case "approve":
+		handleGuardDecide(args[1:], true)
+	case "deny":
+		handleGuardDecide(args[1:], false)
+	case "exec":
+		handleGuardExec(profile, args[1:])
+	case "help", "--help", "-h":
+		printGuardHelp()
+	default:
+		fmt.Printf("Unknown guard command: %s\n", args[0])
+		fmt.Println()
+		printGuardHelp()
+		os.Exit(1)
+	}
+}
+
+// printGuardHelp prints usage for guard commands
+func printGuardHelp() {
+	fmt.Println("Usage: agent-deck guard <command> [options]")
+	fmt.Println()
+	fmt.Println("The command guard puts a shim directory first on PATH inside sessions, so")
+	fmt.Println("dangerous binaries (kubectl, terraform, aws by default) run through agent-deck.")
+	fmt.Println("In log mode they run and are recorded in the audit log; in approve mode they")
+	fmt.Println("wait until approved here. Configure [guard] or [profiles.<name>.guard] in")
+	fmt.Println("config.toml; sessions pick the shims up when they (re)start.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  status          Show the profile's guard mode and guarded commands (default)")
+	fmt.Println("  list            List commands waiting for approval (--all for recent decisions)")
+	fmt.Println("  approve <id>    Let a waiting command run")
+	fmt.Println("  deny <id>       Refuse a waiting command")
+	fmt.Println("  exec <cmd> ...  Run a guarded command (used by the shims)")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck guard list")
+	fmt.Println("  agent-deck guard approve 3f9a01c2")
+	fmt.Println("  agent-deck -p work guard status")
+}
+
+func handleGuardStatus(profile string, args []string) {
+	fs := flag.NewFlagSet("guard status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	profile = session.GetEffectiveProfile(profile)
+	config, _ := session.LoadUserConfig()
+	settings := config.GetGuardSettings(profile)
+	shimDir, _ := session.GuardShimDir(profile)
+	pending, _ := session.ListGuardRequests(true)
+
+	if *jsonOutput {
+		NewCLIOutput(true, false).Print("", map[string]any{
+			"profile":          profile,
+			"mode":             settings.GetMode(),
+			"commands":         settings.GetCommands(),
+			"approval_timeout": settings.GetApprovalTimeout().String(),
+			"shim_dir":         shimDir,
+			"pending":          len(pending),
+		})
+		return
+	}
+	fmt.Printf("Profile:  %s\n", profile)
+	fmt.Printf("Mode:     %s\n", settings.GetMode())
+	if settings.GetMode() == session.GuardOff {
+		return
+	}
+	fmt.Printf("Commands: %s\n", strings.Join(settings.GetCommands(), ", "))
+	fmt.Printf("Shims:    %s\n", shimDir)
+	if settings.GetMode() == session.GuardApprove {
+		fmt.Printf("Timeout:  %s\n", settings.GetApprovalTimeout())
+		fmt.Printf("Pending:  %d\n", len(pending))
+	}
+}
+
+func handleGuardList(args []string) {
+	fs := flag.NewFlagSet("guard list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	all := fs.Bool("all", false, "Include decided requests from the last day")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	requests, err := session.ListGuardRequests(!*all)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *jsonOutput {
+		if requests == nil {
+			requests = []*session.GuardRequest{}
+		}
+		out.Print("", map[string]any{"requests": requests})
+		return
+	}
+	if len(requests) == 0 {
+		fmt.Println("No commands waiting for approval.")
+		return
+	}
+	formatter := session.NewTimeFormatter(false)
+	now := time.Now()
+	for _, req := range requests {
+		where := req.Profile
+		if req.Title != "" {
+			where += "/" + req.Title
+		}
+		fmt.Printf("  %s  %-9s %-24s %s\n", req.ID, req.State, where, formatter.Format(req.CreatedAt, now))
+		fmt.Printf("    %s\n", req.CommandLine())
+	}
+}
+
+func handleGuardDecide(args []string, approve bool) {
+	name := "guard deny"
+	if approve {
+		name = "guard approve"
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	by := fs.String("by", "cli", "Who decided (recorded in the audit log)")
+	fs.Usage = printGuardHelp
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error(fmt.Sprintf("usage: agent-deck %s <id>", name), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	req, err := session.DecideGuardRequest(fs.Arg(0), approve, *by)
+	if err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrGuardRequestNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("%s: %s", strings.ToUpper(req.State[:1])+req.State[1:], req.CommandLine()), map[string]any{
+		"success": true,
+		"request": req,
+	})
+}
+
+// handleGuardExec runs a guarded command for a shim. Arguments after the
+// command name are passed through untouched, so no flags are parsed.
+func handleGuardExec(profile string, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: agent-deck guard exec <command> [args...]")
+		os.Exit(2)
+	}
+	name, cmdArgs := args[0], args[1:]
+
+	profile = session.GetEffectiveProfile(profile)
+	config, _ := session.LoadUserConfig()
+	settings := config.GetGuardSettings(profile)
+	shimDir, _ := session.GuardShimDir(profile)
+	bin, err := session.FindGuardedBinary(name, shimDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "agent-deck guard: %v\n", err)
+		os.Exit(127)
+	}
+
+	if mode := settings.GetMode(); mode != session.GuardOff && settings.Guards(name) {
+		req := session.NewGuardRequest(profile, mode, name, cmdArgs)
+		if mode == session.GuardApprove {
+			req = waitGuardApproval(req, settings.GetApprovalTimeout())
+		}
+		recordGuardedCommand(profile, req)
+		if mode == session.GuardApprove && req.State != session.GuardApproved {
+			fmt.Fprintf(os.Stderr, "agent-deck guard: %s was %s\n", req.CommandLine(), req.State)
+			os.Exit(126)
+		}
+	}
+
+	cmd := exec.Command(bin, cmdArgs...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	// Ctrl+C reaches the command itself; let it decide how to exit
+	signal.Ignore(os.Interrupt)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "agent-deck guard: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// waitGuardApproval saves req for the deck and blocks until it is decided,
+// returning it denied when it cannot be saved
+func waitGuardApproval(req *session.GuardRequest, timeout time.Duration) *session.GuardRequest {
+	if err := session.SaveGuardRequest(req); err != nil {
+		fmt.Fprintf(os.Stderr, "agent-deck guard: %v\n", err)
+		req.State = session.GuardDenied
+		return req
+	}
+	fmt.Fprintf(os.Stderr, "agent-deck guard: %s needs approval (waiting up to %s)\n", req.CommandLine(), timeout)
+	fmt.Fprintf(os.Stderr, "  approve: agent-deck guard approve %s\n", req.ID)
+	fmt.Fprintf(os.Stderr, "  deny:    agent-deck guard deny %s\n", req.ID)
+
+	decided, err := session.WaitGuardDecision(req, timeout, 500*time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "agent-deck guard: %v\n", err)
+		req.State = session.GuardDenied
+		return req
+	}
+	return decided
+}
+
+// recordGuardedCommand adds the run to the profile's audit log. Failures
+// only warn: the command's own output matters more to the agent.
+func recordGuardedCommand(profile string, req *session.GuardRequest) {
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "agent-deck guard: warning: audit log unavailable: %v\n", err)
+		return
+	}
+	defer func() { _ = storage.Close() }()
+	if err := session.RecordGuardedCommand(storage.GetDB(), req); err != nil {
+		fmt.Fprintf(os.Stderr, "agent-deck guard: warning: %v\n", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
@@ -132,11 +133,11 @@ func writeHookStatus(instanceID, status, sessionID, event string) {
 
 // getHooksDir returns the path to the hooks status directory.
 func getHooksDir() string {
-	home, err := os.UserHomeDir()
+	home, err := deck.Home()
 	if err != nil {
 		return filepath.Join(os.TempDir(), ".agent-deck", "hooks")
 	}
-	return filepath.Join(home, ".agent-deck", "hooks")
+	return filepath.Join(home, "hooks")
 }
 
 // cleanStaleHookFiles removes hook status files older than 24 hours.
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleInit runs the guided first-time setup flow
+func handleInit(profile string, args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	tool := fs.String("tool", "", "Default AI tool for new sessions (default: auto-detect)")
+	conductorName := fs.String("conductor", "", "Create a first conductor with this name")
+	yes := fs.Bool("yes", false, "Accept defaults without prompting")
+	yesShort := fs.Bool("y", false, "Accept defaults without prompting (short)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON (implies --yes)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck [-p profile] init [options]")
+		fmt.Println()
+		fmt.Println("Guided first-time setup: checks tmux, systemd/launchd and installed tools,")
+		fmt.Println("creates ~/.agent-deck, configures the default profile and notification")
+		fmt.Println("sink, and optionally creates a first conductor. Safe to re-run.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck init")
+		fmt.Println("  agent-deck -p work init --tool codex --yes")
+		fmt.Println("  agent-deck init --conductor ops -y")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	interactive := !*yes && !*yesShort && !*jsonOutput
+	reader := bufio.NewReader(os.Stdin)
+	ask := func(prompt, def string) string {
+		if !interactive {
+			return def
+		}
+		if def != "" {
+			fmt.Printf("%s [%s]: ", prompt, def)
+		} else {
+			fmt.Printf("%s: ", prompt)
+		}
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return def
+		}
+		return answer
+	}
+	confirm := func(prompt string) bool {
+		answer := strings.ToLower(ask(prompt+" (y/N)", ""))
+		return answer == "y" || answer == "yes"
+	}
+
+	// Step 1: Detect environment
+	env := session.DetectSetupEnvironment()
+	blocking, warnings := env.Problems()
+
+	if !*jsonOutput {
+		fmt.Println("Agent Deck Setup")
+		fmt.Println("================")
+		fmt.Println()
+		if env.TmuxPath != "" {
+			fmt.Printf("  [ok] %s (%s)\n", env.TmuxVersion, env.TmuxPath)
+		}
+		if env.ServiceManager != "" {
+			fmt.Printf("  [ok] Service manager: %s\n", env.ServiceManager)
+		}
+		for _, name := range env.DetectedToolNames() {
+			fmt.Printf("  [ok] %s: %s\n", name, env.Tools[name])
+		}
+		for _, w := range warnings {
+			fmt.Printf("  [warn] %s\n", w)
+		}
+		for _, b := range blocking {
+			fmt.Printf("  [error] %s\n", b)
+		}
+		fmt.Println()
+	}
+
+	if len(blocking) > 0 {
+		if *jsonOutput {
+			output, _ := json.MarshalIndent(map[string]any{
+				"success":     false,
+				"environment": env,
+				"errors":      blocking,
+			}, "", "  ")
+			fmt.Println(string(output))
+		} else {
+			fmt.Fprintln(os.Stderr, "Fix the errors above and re-run 'agent-deck init'.")
+		}
+		os.Exit(1)
+	}
+
+	// Step 2: Profile and default tool
+	opts := session.SetupOptions{
+		Profile: ask("Default profile", session.GetEffectiveProfile(profile)),
+	}
+	defaultTool := *tool
+	if defaultTool == "" {
+		defaultTool = ask("Default tool for new sessions", env.SuggestedDefaultTool())
+	}
+	opts.DefaultTool = defaultTool
+
+	// Step 3: Notification sink (conductor bridge)
+	if interactive && confirm("Connect a Telegram bot for notifications?") {
+		token := ask("Telegram bot token (from @BotFather)", "")
+		userID, err := strconv.ParseInt(ask("Your Telegram user ID (from @userinfobot)", ""), 10, 64)
+		if token == "" || err != nil || userID == 0 {
+			fmt.Fprintln(os.Stderr, "Error: a bot token and numeric user ID are required")
+			os.Exit(1)
+		}
+		opts.Telegram = session.TelegramSettings{Token: token, UserID: userID}
+	}
+	if interactive && confirm("Connect a Slack bot for notifications?") {
+		opts.Slack = session.SlackSettings{
+			BotToken:  ask("Slack bot token (xoxb-...)", ""),
+			AppToken:  ask("Slack app token (xapp-...)", ""),
+			ChannelID: ask("Slack channel ID (C01234...)", ""),
+		}
+		if opts.Slack.BotToken == "" || opts.Slack.AppToken == "" || opts.Slack.ChannelID == "" {
+			fmt.Fprintln(os.Stderr, "Error: bot token, app token and channel ID are required")
+			os.Exit(1)
+		}
+	}
+
+	// Step 4: First conductor
+	firstConductor := *conductorName
+	if firstConductor == "" && interactive && confirm("Create a first conductor now?") {
+		firstConductor = ask("Conductor name", "main")
+	}
+	if firstConductor != "" {
+		if err := session.ValidateConductorName(firstConductor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts.EnableConductor = true
+	}
+
+	// Step 5: Write layout and config
+	result, err := session.RunSetup(opts)
+	if err != nil {
+		if *jsonOutput {
+			output, _ := json.MarshalIndent(map[string]any{"success": false, "error": err.Error()}, "", "  ")
+			fmt.Println(string(output))
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+
+	if *jsonOutput && firstConductor == "" {
+		output, _ := json.MarshalIndent(map[string]any{
+			"success":      true,
+			"environment":  env,
+			"setup":        result,
+			"default_tool": opts.DefaultTool,
+		}, "", "  ")
+		fmt.Println(string(output))
+	} else if !*jsonOutput {
+		for _, dir := range result.CreatedDirs {
+			fmt.Printf("[ok] Created %s\n", dir)
+		}
+		fmt.Printf("[ok] Profile '%s' ready (default)\n", result.Profile)
+		if result.ConfigCreated {
+			fmt.Println("[ok] config.toml created")
+		}
+		if result.ConfigUpdated {
+			fmt.Println("[ok] config.toml updated")
+		}
+	}
+
+	// Step 6: Hand off to conductor setup, which registers the session and daemons.
+	// In JSON mode its output is the single JSON document for the whole run.
+	if firstConductor != "" {
+		if !*jsonOutput {
+			fmt.Println()
+		}
+		conductorArgs := []string{firstConductor}
+		if *jsonOutput {
+			conductorArgs = append(conductorArgs, "--json")
+		}
+		handleConductorSetup(result.Profile, conductorArgs)
+		return
+	}
+
+	if !*jsonOutput {
+		fmt.Println()
+		fmt.Println("Setup complete! Next steps:")
+		fmt.Println("  agent-deck                    # Start the TUI")
+		fmt.Println("  agent-deck add .              # Add the current directory as a session")
+		fmt.Println("  agent-deck conductor setup <name>")
+	}
+}
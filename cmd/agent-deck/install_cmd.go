@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// completionCommands lists the top-level commands offered by shell completion
+var completionCommands = []string{
+	"add", "completion", "conductor", "cost", "deck", "emergency-stop", "events", "features", "group", "guard", "help", "init", "install", "launch",
+	"list", "logs", "maintenance", "mcp", "patterns", "profile", "queue", "remote", "remove", "rename", "review", "search", "session", "skill",
+	"stats", "status", "tmux", "trash", "try", "uninstall", "update", "usage", "version", "web", "worktree",
+}
+
+// completionSubcommands lists the subcommands completed after a top-level command
+var completionSubcommands = map[string][]string{
+	"session": {"start", "stop", "restart", "restore", "fork", "attach", "show", "current",
+		"set", "send", "receipt", "interrupt", "output", "macro", "audit", "set-parent", "unset-parent", "pin", "unpin", "notify", "adopt"},
+	"conductor":      {"setup", "teardown", "status", "list", "report", "history", "heartbeat-run", "fleet", "standup", "schedule", "reconcile", "identity", "runbook", "notify", "skills", "group", "task", "registry", "bridge"},
+	"group":          {"list", "create", "update", "delete", "move"},
+	"mcp":            {"list", "attached", "attach", "detach", "server"},
+	"skill":          {"list", "attached", "attach", "detach", "source"},
+	"profile":        {"list", "create", "delete", "default", "proxy"},
+	"maintenance":    {"status", "list", "start", "schedule", "stop", "remove"},
+	"emergency-stop": {"resume", "status"},
+	"guard":          {"status", "list", "approve", "deny"},
+	"worktree":       {"list", "info", "cleanup", "finish"},
+	"completion":     {"bash", "zsh", "fish"},
+	"cost":           {"export"},
+	"stats":          {"show", "enable", "disable", "export", "reset"},
+	"tmux":           {"check"},
+	"features":       {"list", "enable", "disable", "reset"},
+	"deck":           {"list", "current"},
+	"patterns":       {"list", "export", "import", "remove"},
+	"status":         {"export"},
+	"queue":          {"submit", "list", "flush", "clear"},
+	"remote":         {"hosts", "list", "attach", "launch", "status", "run", "disconnect"},
+}
+
+// completionShells are the shells completion scripts are generated for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionScript returns the completion script for a shell
+func completionScript(shell string) (string, error) {
+	parents := make([]string, 0, len(completionSubcommands))
+	for cmd := range completionSubcommands {
+		parents = append(parents, cmd)
+	}
+	sort.Strings(parents)
+
+	var b strings.Builder
+	switch shell {
+	case "bash":
+		b.WriteString("# bash completion for agent-deck\n")
+		b.WriteString("_agent_deck() {\n")
+		b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+		b.WriteString("    if [ \"$COMP_CWORD\" -eq 1 ]; then\n")
+		fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(completionCommands, " "))
+		b.WriteString("        return\n    fi\n")
+		b.WriteString("    if [ \"$COMP_CWORD\" -eq 2 ]; then\n")
+		b.WriteString("        case \"${COMP_WORDS[1]}\" in\n")
+		for _, cmd := range parents {
+			fmt.Fprintf(&b, "            %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", cmd, strings.Join(completionSubcommands[cmd], " "))
+		}
+		b.WriteString("        esac\n    fi\n}\n")
+		b.WriteString("complete -o default -F _agent_deck agent-deck\n")
+	case "zsh":
+		b.WriteString("#compdef agent-deck\n")
+		b.WriteString("# zsh completion for agent-deck\n")
+		b.WriteString("_agent_deck() {\n")
+		b.WriteString("    if (( CURRENT == 2 )); then\n")
+		fmt.Fprintf(&b, "        compadd -- %s\n", strings.Join(completionCommands, " "))
+		b.WriteString("        return\n    fi\n")
+		b.WriteString("    if (( CURRENT == 3 )); then\n")
+		b.WriteString("        case \"${words[2]}\" in\n")
+		for _, cmd := range parents {
+			fmt.Fprintf(&b, "            %s) compadd -- %s ;;\n", cmd, strings.Join(completionSubcommands[cmd], " "))
+		}
+		b.WriteString("            *) _files ;;\n")
+		b.WriteString("        esac\n        return\n    fi\n")
+		b.WriteString("    _files\n}\n")
+		b.WriteString("_agent_deck \"$@\"\n")
+	case "fish":
+		b.WriteString("# fish completion for agent-deck\n")
+		fmt.Fprintf(&b, "complete -c agent-deck -n __fish_use_subcommand -f -a %q\n", strings.Join(completionCommands, " "))
+		for _, cmd := range parents {
+			fmt.Fprintf(&b, "complete -c agent-deck -n '__fish_seen_subcommand_from %s' -f -a %q\n", cmd, strings.Join(completionSubcommands[cmd], " "))
+		}
+	default:
+		return "", fmt.Errorf("unsupported shell %q (use bash, zsh or fish)", shell)
+	}
+	return b.String(), nil
+}
+
+// handleCompletion prints the completion script for a shell
+func handleCompletion(args []string) {
+	if len(args) != 1 || args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		fmt.Println("Usage: agent-deck completion <bash|zsh|fish>")
+		fmt.Println()
+		fmt.Println("Print a shell completion script. \"agent-deck install\" installs them for you.")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  source <(agent-deck completion bash)")
+		fmt.Println("  agent-deck completion fish > ~/.config/fish/completions/agent-deck.fish")
+		if len(args) != 1 {
+			os.Exit(1)
+		}
+		return
+	}
+	script, err := completionScript(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(script)
+}
+
+// handleInstall installs every agent-deck artifact in one step
+func handleInstall(profile string, args []string) {
+	fs := flag.NewFlagSet("install", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	noCompletions := fs.Bool("no-completions", false, "Skip shell completions")
+	noBoot := fs.Bool("no-boot", false, "Skip the restore-on-boot unit")
+	bridge := fs.String("bridge", "auto", "Install the conductor bridge: auto (when Telegram/Slack is configured), yes, no")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck install [options]")
+		fmt.Println()
+		fmt.Println("Install everything agent-deck needs in one step: the default config,")
+		fmt.Println("shell completions (bash, zsh, fish), a unit that restores sessions after")
+		fmt.Println("a reboot, and the conductor bridge daemon. If any step fails, everything")
+		fmt.Println("installed so far is rolled back. Undo with: agent-deck uninstall")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	opts := session.InstallOptions{
+		Profile:       session.GetEffectiveProfile(profile),
+		RestoreOnBoot: !*noBoot,
+	}
+	switch *bridge {
+	case "auto":
+		settings := session.GetConductorSettings()
+		opts.Bridge = settings.Telegram.Token != "" || settings.Slack.BotToken != ""
+	case "yes", "true":
+		opts.Bridge = true
+	case "no", "false":
+	default:
+		out.Error(fmt.Sprintf("invalid --bridge %q: use auto, yes or no", *bridge), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if !*noCompletions {
+		opts.Completions = make(map[string]string, len(completionShells))
+		for _, shell := range completionShells {
+			script, _ := completionScript(shell)
+			opts.Completions[shell] = script
+		}
+	}
+
+	artifacts, err := session.InstallAll(opts)
+	if err != nil {
+		out.Error(fmt.Sprintf("install failed, rolled back: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]any{"success": true, "installed": artifacts})
+		return
+	}
+	if len(artifacts) == 0 {
+		fmt.Println("Nothing to install.")
+		return
+	}
+	for _, a := range artifacts {
+		fmt.Printf("[ok] %-16s %s\n", a.Component, a.Path)
+		if a.Note != "" {
+			fmt.Printf("     %s\n", a.Note)
+		}
+	}
+}
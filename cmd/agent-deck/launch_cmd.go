@@ -23,6 +23,9 @@ func handleLaunch(profile string, args []string) {
 	command := fs.String("cmd", "", "Command to run (e.g., 'claude', 'gemini')")
 	commandShort := fs.String("c", "", "Command to run (short)")
 	wrapper := fs.String("wrapper", "", "Wrapper command (use {command} to include tool command)")
+	reason := fs.String("reason", "", "Why the session exists: kind[:ref], e.g. webhook:gh-1234 or template:review (default: manual)")
+	network := fs.String("network", "", "Network access for the agent: open, offline or restricted (default: open)")
+	allowHosts := fs.String("allow-host", "", "Hosts reachable with --network restricted, comma-separated (tool API hosts are always allowed)")
 	message := fs.String("message", "", "Initial message to send once agent is ready")
 	messageShort := fs.String("m", "", "Initial message to send (short)")
 	noWait := fs.Bool("no-wait", false, "Don't wait for agent to be ready before sending message")
@@ -195,6 +198,11 @@ func handleLaunch(profile string, args []string) {
 		out.Error(err.Error(), ErrCodeNotFound)
 		os.Exit(1)
 	}
+	spawnConductor, err := checkSpawnPolicy(storage, instances, detectTool(sessionCommand), *reason)
+	if err != nil {
+		reportSpawnError(out, err)
+		os.Exit(1)
+	}
 
 	// Resolve parent session if specified
 	var parentInstance *session.Instance
@@ -244,6 +252,10 @@ func handleLaunch(profile string, args []string) {
 	}
 
 	if sessionCommand != "" {
+		if err := session.ToolDefError(sessionCommand); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
 		newInstance.Tool = detectTool(sessionCommand)
 		if toolDef := session.GetToolDef(newInstance.Tool); toolDef != nil {
 			newInstance.Command = toolDef.Command
@@ -256,6 +268,20 @@ func handleLaunch(profile string, args []string) {
 		newInstance.Wrapper = *wrapper
 	}
 
+	reasonKind, reasonRef, err := resolveStartReason(*reason)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	newInstance.SetStartReason(reasonKind, reasonRef)
+
+	networkPolicy, err := resolveNetworkPolicy(*network, *allowHosts)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	newInstance.NetworkPolicy = networkPolicy
+
 	if worktreePath != "" {
 		newInstance.WorktreePath = worktreePath
 		newInstance.WorktreeRepoRoot = worktreeRepoRoot
@@ -288,6 +314,8 @@ func handleLaunch(profile string, args []string) {
 		out.Error(fmt.Sprintf("failed to save session: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	recordNetworkPolicy(storage, newInstance)
+	recordSpawn(storage, spawnConductor, newInstance)
 
 	// Attach MCPs if specified
 	if len(mcpFlags) > 0 {
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleLogs prints (and follows) a session's transcript
+func handleLogs(profile string, args []string) {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	follow := fs.Bool("follow", false, "Keep printing output as it is captured")
+	followShort := fs.Bool("f", false, "Keep printing output as it is captured (short)")
+	tail := fs.Int("tail", 0, "Print only the last N lines (0 = all)")
+	raw := fs.Bool("raw", false, "Print the output as captured, with escape sequences")
+	pathOnly := fs.Bool("path", false, "Print only the transcript directory")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck logs <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Print a session's transcript: its output as captured continuously by")
+		fmt.Println("[transcripts] in config.toml, across rotated files. Transcripts of removed")
+		fmt.Println("sessions can still be read by session ID until they expire.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck logs api-server --tail 200")
+		fmt.Println("  agent-deck logs api-server -f")
+		fmt.Println("  agent-deck logs api-server --raw > api-server.log")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	out := NewCLIOutput(false, false)
+
+	sessionID, title := fs.Arg(0), fs.Arg(0)
+	if _, instances, _, err := loadSessionData(profile); err == nil {
+		if inst, _, _ := ResolveSession(fs.Arg(0), instances); inst != nil {
+			sessionID, title = inst.ID, inst.Title
+		}
+	}
+	dir, err := session.TranscriptDir(sessionID)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	files, err := session.TranscriptFiles(dir)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read transcripts: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *pathOnly {
+		fmt.Println(dir)
+		return
+	}
+	if len(files) == 0 && !*follow && !*followShort {
+		hint := ""
+		if !session.GetTranscriptSettings().Enabled {
+			hint = " (enable [transcripts] in config.toml and restart the session)"
+		}
+		out.Error(fmt.Sprintf("no transcript for %s%s", title, hint), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	printer := &transcriptPrinter{w: w, raw: *raw}
+	var content strings.Builder
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		content.Write(data)
+	}
+	text := content.String()
+	if *tail > 0 {
+		text = lastLines(text, *tail, *raw)
+	}
+	printer.write([]byte(text))
+	_ = w.Flush()
+
+	if !*follow && !*followShort {
+		printer.flush()
+		_ = w.Flush()
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := followTranscript(ctx, filepath.Join(dir, "current.log"), printer, w); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// transcriptPrinter writes transcript output, cleaned up one complete line
+// at a time unless raw, so escape sequences split across reads survive
+type transcriptPrinter struct {
+	w       io.Writer
+	raw     bool
+	pending []byte
+}
+
+func (p *transcriptPrinter) write(data []byte) {
+	if p.raw {
+		_, _ = p.w.Write(data)
+		return
+	}
+	p.pending = append(p.pending, data...)
+	end := strings.LastIndexByte(string(p.pending), '\n')
+	if end < 0 {
+		return
+	}
+	fmt.Fprint(p.w, session.PlainTranscript(string(p.pending[:end+1])))
+	p.pending = append(p.pending[:0], p.pending[end+1:]...)
+}
+
+// flush writes a trailing partial line
+func (p *transcriptPrinter) flush() {
+	if len(p.pending) > 0 {
+		fmt.Fprintln(p.w, session.PlainTranscript(string(p.pending)))
+		p.pending = nil
+	}
+}
+
+// lastLines returns the last n lines of text. Raw text is counted in
+// captured lines; the cleaned count can differ slightly.
+func lastLines(text string, n int, raw bool) string {
+	if !raw {
+		text = session.PlainTranscript(text)
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "")
+}
+
+// followTranscript prints what is appended to the current transcript file
+// until ctx ends, reopening it when it is rotated or created
+func followTranscript(ctx context.Context, path string, printer *transcriptPrinter, w *bufio.Writer) error {
+	var (
+		file   *os.File
+		offset int64
+	)
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	buf := make([]byte, 32*1024)
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if file == nil {
+			if f, err := os.Open(path); err == nil {
+				file = f
+				if _, err := file.Seek(offset, io.SeekStart); err != nil {
+					return err
+				}
+			}
+		}
+		if file != nil {
+			// Rotated: the path names a new file. The old one is complete, so
+			// finish it and read the new one from its start.
+			opened, _ := file.Stat()
+			current, err := os.Stat(path)
+			rotated := err != nil || !os.SameFile(opened, current)
+			for {
+				n, err := file.Read(buf)
+				if n > 0 {
+					printer.write(buf[:n])
+					offset += int64(n)
+				}
+				if err != nil || n == 0 {
+					break
+				}
+			}
+			_ = w.Flush()
+			if rotated {
+				file.Close()
+				file, offset = nil, 0
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			printer.flush()
+			return w.Flush()
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleTranscriptPipe copies a session's pane output, piped by tmux
+// pipe-pane, into its transcript directory. Started by agent-deck only.
+func handleTranscriptPipe(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: agent-deck transcript-pipe <transcript-dir>")
+		os.Exit(1)
+	}
+	if err := session.CopyTranscript(os.Stdin, args[0], session.GetTranscriptSettings()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -11,6 +12,7 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -21,6 +23,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 
+	"github.com/asheshgoplani/agent-deck/internal/chaos"
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
 	"github.com/asheshgoplani/agent-deck/internal/session"
@@ -38,6 +42,7 @@ const (
 	tableColGroup     = 15
 	tableColPath      = 40
 	tableColIDDisplay = 12
+	tableColReason    = 24
 )
 
 // init sets up color profile for consistent terminal colors across environments
@@ -104,7 +109,7 @@ func promptForUpdate() bool {
 	}
 
 	fmt.Println()
-	if err := update.PerformUpdate(info.DownloadURL); err != nil {
+	if _, err := applyUpdate(info); err != nil {
 		fmt.Fprintf(os.Stderr, "Update failed: %v\n", err)
 		return false
 	}
@@ -116,6 +121,13 @@ func promptForUpdate() bool {
 // initColorProfile configures lipgloss color profile based on terminal capabilities.
 // Prefers TrueColor for best visuals, falls back to ANSI256 for compatibility.
 func initColorProfile() {
+	// NO_COLOR (no-color.org), [output] color = "never" and screen_reader
+	// disable color entirely
+	if !session.GetOutputSettings().ColorEnabled(true) {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		return
+	}
+
 	// Allow user override via environment variable
 	// AGENTDECK_COLOR: truecolor, 256, 16, none
 	if colorEnv := os.Getenv("AGENTDECK_COLOR"); colorEnv != "" {
@@ -182,13 +194,27 @@ func initColorProfile() {
 }
 
 func main() {
+	// Extract global --deck flag first: it selects where all state lives
+	deckName, args := extractDeckFlag(os.Args[1:])
+	if deckName != "" {
+		if err := deck.Use(deckName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Extract global -p/--profile flag before subcommand dispatch
-	profile, args := extractProfileFlag(os.Args[1:])
+	profile, args := extractProfileFlag(args)
 	if profile != "" {
 		// Propagate explicit profile selection so config lookups (e.g., per-profile Claude config)
 		// resolve consistently across all command paths in this process.
 		_ = os.Setenv("AGENTDECK_PROFILE", profile)
 	}
+	// Export the profile's [proxy] settings so agent-deck's own requests and
+	// headless runs go through the proxy too
+	session.ApplyProxyEnv()
+	recordCommandUsage(args)
+	initCLILogging()
 
 	var webEnabled bool
 	var webArgs []string
@@ -198,11 +224,18 @@ func main() {
 		switch args[0] {
 		case "version", "--version", "-v":
 			fmt.Printf("Agent Deck v%s\n", Version)
+			if !deck.IsDefault() {
+				home, _ := deck.Home()
+				fmt.Printf("Deck: %s (%s, tmux socket %s)\n", deck.Name(), home, deck.TmuxSocket())
+			}
 			return
 		case "help", "--help", "-h":
 			printHelp()
 			return
-		case "add":
+		case "init":
+			handleInit(profile, args[1:])
+			return
+		case "add", "new":
 			handleAdd(profile, args[1:])
 			return
 		case "list", "ls":
@@ -214,6 +247,18 @@ func main() {
 		case "rename", "mv":
 			handleRename(profile, args[1:])
 			return
+		case "trash":
+			handleTrash(args[1:])
+			return
+		case "maintenance":
+			handleMaintenance(args[1:])
+			return
+		case "emergency-stop", "estop":
+			handleEmergencyStop(args[1:])
+			return
+		case "guard":
+			handleGuard(profile, args[1:])
+			return
 		case "status":
 			handleStatus(profile, args[1:])
 			return
@@ -248,6 +293,9 @@ func main() {
 		case "launch":
 			handleLaunch(profile, args[1:])
 			return
+		case "review":
+			handleReview(profile, args[1:])
+			return
 		case "conductor":
 			handleConductor(profile, args[1:])
 			return
@@ -255,12 +303,65 @@ func main() {
 			handleWorktree(profile, args[1:])
 			return
 		case "web":
+			if len(args) > 1 && args[1] == "daemon" {
+				handleWebDaemon(profile, args[2:])
+				return
+			}
+			if webHeadlessRequested(args[1:]) {
+				handleWebHeadless(profile, args[1:])
+				return
+			}
 			webEnabled = true
 			webArgs = append(webArgs, args[1:]...)
 			// fall through to TUI launch below
+		case "install":
+			handleInstall(profile, args[1:])
+			return
+		case "completion":
+			handleCompletion(args[1:])
+			return
+		case "stats":
+			handleStats(args[1:])
+			return
+		case "cost":
+			handleCost(profile, args[1:])
+			return
+		case "usage":
+			handleUsage(profile, args[1:])
+			return
+		case "queue":
+			handleQueue(profile, args[1:])
+			return
+		case "tmux":
+			handleTmux(args[1:])
+			return
+		case "features":
+			handleFeatures(profile, args[1:])
+			return
+		case "deck":
+			handleDeck(args[1:])
+			return
+		case "remote":
+			handleRemote(args[1:])
+			return
+		case "patterns":
+			handlePatterns(args[1:])
+			return
+		case "events", "--events":
+			handleEvents(profile, args[1:])
+			return
 		case "uninstall":
 			handleUninstall(args[1:])
 			return
+		case "logs":
+			handleLogs(profile, args[1:])
+			return
+		case "search":
+			handleSearch(profile, args[1:])
+			return
+		case "transcript-pipe":
+			handleTranscriptPipe(args[1:])
+			return
 		case "hook-handler":
 			handleHookHandler()
 			return
@@ -313,6 +414,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Warn about (or fix) tmux options that break capture or input injection
+	checkTmuxOptionsAtStartup()
+
+	// Chaos mode fails things on purpose; make sure it was not left on by accident
+	if chaos.Current().Enabled {
+		fmt.Fprintln(os.Stderr, "Warning: chaos mode is on: captures, tool startup and heartbeats fail at random ([chaos] or AGENTDECK_CHAOS)")
+	}
+
 	// Create storage early to register instance via SQLite
 	earlyStorage, err := session.NewStorageWithProfile(profile)
 	if err == nil {
@@ -365,37 +474,12 @@ func main() {
 			AggregateIntervalSecs: 30,
 		}
 
-		// Override defaults from user config if available
+		// Override defaults from user config if available. Stderr output
+		// would draw over the TUI, so [logs] output is ignored here.
 		if userCfg, err := session.LoadUserConfig(); err == nil {
-			ls := userCfg.Logs
-			if ls.DebugLevel != "" {
-				logCfg.Level = ls.DebugLevel
-			}
-			if ls.DebugFormat != "" {
-				logCfg.Format = ls.DebugFormat
-			}
-			if ls.DebugMaxMB > 0 {
-				logCfg.MaxSizeMB = ls.DebugMaxMB
-			}
-			if ls.DebugBackups > 0 {
-				logCfg.MaxBackups = ls.DebugBackups
-			}
-			if ls.DebugRetentionDays > 0 {
-				logCfg.MaxAgeDays = ls.DebugRetentionDays
-			}
-			if ls.DebugCompress {
-				logCfg.Compress = ls.DebugCompress
-			}
-			if ls.RingBufferMB > 0 {
-				logCfg.RingBufferSize = ls.RingBufferMB * 1024 * 1024
-			}
-			if ls.PprofEnabled {
-				logCfg.PprofEnabled = ls.PprofEnabled
-			}
-			if ls.AggregateIntervalS > 0 {
-				logCfg.AggregateIntervalSecs = ls.AggregateIntervalS
-			}
+			applyLogSettings(&logCfg, userCfg.Logs)
 		}
+		applyLogEnv(&logCfg)
 
 		logging.Init(logCfg)
 		defer logging.Shutdown()
@@ -444,6 +528,12 @@ func main() {
 			}
 		}()
 		fmt.Printf("Web server: http://%s\n", server.Addr())
+		if addr := server.GRPCAddr(); addr != "" {
+			fmt.Printf("gRPC:       %s\n", addr)
+		}
+		if addr := server.MetricsAddr(); addr != "" {
+			fmt.Printf("Metrics:    http://%s/metrics\n", addr)
+		}
 		defer func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
@@ -470,6 +560,91 @@ func main() {
 	}
 }
 
+// initCLILogging sets up logging for CLI commands and daemons (web
+// --headless, conductor heartbeats). Logs go to stderr or debug.log when
+// [logs] output or AGENTDECK_LOG_OUTPUT asks for it, and to journald or
+// syslog when [logs] system_sink is set; otherwise they are discarded. The
+// TUI re-initializes logging with its debug.log file on top.
+func initCLILogging() {
+	logCfg := logging.Config{Level: "info"}
+	var output string
+	if userCfg, err := session.LoadUserConfig(); err == nil && userCfg != nil {
+		applyLogSettings(&logCfg, userCfg.Logs)
+		output = userCfg.Logs.Output
+	}
+	applyLogEnv(&logCfg)
+	if env := os.Getenv("AGENTDECK_LOG_OUTPUT"); env != "" {
+		output = env
+	}
+
+	if logCfg.System != "" && !logging.ValidSystemSink(logCfg.System) {
+		fmt.Fprintf(os.Stderr, "Warning: [logs] system_sink = %q is not journald or syslog\n", logCfg.System)
+		logCfg.System = ""
+	}
+	switch output {
+	case "":
+	case logging.OutputStderr:
+		logCfg.Output = output
+	case logging.OutputFile:
+		dir, err := session.GetAgentDeckDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: log file unavailable: %v\n", err)
+			break
+		}
+		logCfg.LogDir = dir
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: log output %q is not stderr or file\n", output)
+	}
+	if logCfg.Output == "" && logCfg.LogDir == "" && logCfg.System == "" {
+		return
+	}
+	logging.Init(logCfg)
+}
+
+// applyLogSettings overrides logging defaults with the [logs] settings
+func applyLogSettings(logCfg *logging.Config, ls session.LogSettings) {
+	if ls.DebugLevel != "" {
+		logCfg.Level = ls.DebugLevel
+	}
+	if ls.DebugFormat != "" {
+		logCfg.Format = ls.DebugFormat
+	}
+	if ls.DebugMaxMB > 0 {
+		logCfg.MaxSizeMB = ls.DebugMaxMB
+	}
+	if ls.DebugBackups > 0 {
+		logCfg.MaxBackups = ls.DebugBackups
+	}
+	if ls.DebugRetentionDays > 0 {
+		logCfg.MaxAgeDays = ls.DebugRetentionDays
+	}
+	if ls.DebugCompress {
+		logCfg.Compress = ls.DebugCompress
+	}
+	if ls.RingBufferMB > 0 {
+		logCfg.RingBufferSize = ls.RingBufferMB * 1024 * 1024
+	}
+	if ls.PprofEnabled {
+		logCfg.PprofEnabled = ls.PprofEnabled
+	}
+	if ls.AggregateIntervalS > 0 {
+		logCfg.AggregateIntervalSecs = ls.AggregateIntervalS
+	}
+	logCfg.System = ls.SystemSink
+	logCfg.SystemLevel = ls.SystemLevel
+}
+
+// applyLogEnv applies the AGENTDECK_LOG_LEVEL and AGENTDECK_LOG_FORMAT
+// overrides, for debugging one run without editing config.toml
+func applyLogEnv(logCfg *logging.Config) {
+	if level := os.Getenv("AGENTDECK_LOG_LEVEL"); level != "" {
+		logCfg.Level = level
+	}
+	if format := os.Getenv("AGENTDECK_LOG_FORMAT"); format != "" {
+		logCfg.Format = format
+	}
+}
+
 // extractProfileFlag extracts -p or --profile from args, returning the profile and remaining args
 func extractProfileFlag(args []string) (string, []string) {
 	var profile string
@@ -526,6 +701,9 @@ func reorderArgsForFlagParsing(args []string) []string {
 		"-w":        true, "--worktree": true,
 		"--location":       true,
 		"--resume-session": true,
+		"--reason":         true,
+		"--network":        true,
+		"--allow-host":     true,
 	}
 
 	var flags []string
@@ -641,7 +819,11 @@ func handleAdd(profile string, args []string) {
 	groupShort := fs.String("g", "", "Group path (short)")
 	command := fs.String("cmd", "", "Command to run (e.g., 'claude', 'opencode')")
 	commandShort := fs.String("c", "", "Command to run (short)")
+	tool := fs.String("tool", "", "Tool to run, same as --cmd (e.g., 'codex')")
 	wrapper := fs.String("wrapper", "", "Wrapper command (use {command} to include tool command, e.g., 'nvim +\"terminal {command}\"')")
+	reason := fs.String("reason", "", "Why the session exists: kind[:ref], e.g. webhook:gh-1234 or template:review (default: manual)")
+	network := fs.String("network", "", "Network access for the agent: open, offline or restricted (default: open)")
+	allowHosts := fs.String("allow-host", "", "Hosts reachable with --network restricted, comma-separated (tool API hosts are always allowed)")
 	parent := fs.String("parent", "", "Parent session (creates sub-session, inherits group)")
 	parentShort := fs.String("p", "", "Parent session (short)")
 	quickCreate := fs.Bool("quick", false, "Auto-generate session name (adjective-noun)")
@@ -683,11 +865,14 @@ func handleAdd(profile string, args []string) {
 		fmt.Println("  agent-deck add /path/to/project")
 		fmt.Println("  agent-deck add -t \"My Project\" -g \"work\"")
 		fmt.Println("  agent-deck add -c claude .")
+		fmt.Println("  agent-deck new --tool codex .")
 		fmt.Println("  agent-deck -p work add               # Add to 'work' profile")
 		fmt.Println("  agent-deck add -t \"Sub-task\" --parent \"Main Project\"  # Create sub-session")
 		fmt.Println("  agent-deck add -t \"Research\" -c claude --mcp memory --mcp sequential-thinking /tmp/x")
 		fmt.Println("  agent-deck add -c opencode --wrapper \"nvim +'terminal {command}' +'startinsert'\" .")
 		fmt.Println("  agent-deck add --quick -c claude .   # Auto-generated name")
+		fmt.Println("  agent-deck add -c claude --network offline .")
+		fmt.Println("  agent-deck add -c claude --network restricted --allow-host github.com,proxy.golang.org .")
 		fmt.Println()
 		fmt.Println("Worktree Examples:")
 		fmt.Println("  agent-deck add -w feature/login .    # Create worktree for existing branch")
@@ -720,7 +905,7 @@ func handleAdd(profile string, args []string) {
 	// Merge short and long flags
 	sessionTitle := mergeFlags(*title, *titleShort)
 	sessionGroup := mergeFlags(*group, *groupShort)
-	sessionCommand := mergeFlags(*command, *commandShort)
+	sessionCommand := mergeFlags(mergeFlags(*command, *commandShort), *tool)
 	sessionParent := mergeFlags(*parent, *parentShort)
 
 	// Validate --resume-session requires Claude
@@ -810,6 +995,13 @@ func handleAdd(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	// A conductor creating sessions is subject to the [spawn] policy
+	spawnConductor, err := checkSpawnPolicy(storage, instances, detectTool(sessionCommand), *reason)
+	if err != nil {
+		reportSpawnError(NewCLIOutput(*jsonOutput, *quiet || *quietShort), err)
+		os.Exit(1)
+	}
+
 	// Handle worktree creation
 	var worktreePath, worktreeRepoRoot string
 	if wtBranch != "" {
@@ -922,6 +1114,11 @@ func handleAdd(profile string, args []string) {
 
 	// Set command if provided
 	if sessionCommand != "" {
+		// A custom tool rejected at config load would otherwise run as a raw command
+		if err := session.ToolDefError(sessionCommand); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		newInstance.Tool = detectTool(sessionCommand)
 		// For custom tools, resolve the actual shell command (e.g. "glm" → "claude")
 		if toolDef := session.GetToolDef(newInstance.Tool); toolDef != nil {
@@ -936,6 +1133,20 @@ func handleAdd(profile string, args []string) {
 		newInstance.Wrapper = *wrapper
 	}
 
+	reasonKind, reasonRef, err := resolveStartReason(*reason)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	newInstance.SetStartReason(reasonKind, reasonRef)
+
+	networkPolicy, err := resolveNetworkPolicy(*network, *allowHosts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	newInstance.NetworkPolicy = networkPolicy
+
 	// Set worktree fields if created
 	if worktreePath != "" {
 		newInstance.WorktreePath = worktreePath
@@ -974,6 +1185,8 @@ func handleAdd(profile string, args []string) {
 		fmt.Printf("Error: failed to save session: %v\n", err)
 		os.Exit(1)
 	}
+	recordNetworkPolicy(storage, newInstance)
+	recordSpawn(storage, spawnConductor, newInstance)
 
 	// Attach MCPs if specified
 	if len(mcpFlags) > 0 {
@@ -1065,11 +1278,28 @@ func handleAdd(profile string, args []string) {
 	}
 }
 
+// listSortSettings returns the configured sort settings with the mode
+// optionally overridden by a --sort flag value
+func listSortSettings(mode string) (session.SortSettings, error) {
+	settings := session.GetSortSettings()
+	if mode == "" {
+		return settings, nil
+	}
+	if !slices.Contains(session.ValidSortModes, mode) {
+		return settings, fmt.Errorf("invalid --sort %q (valid: %s)", mode, strings.Join(session.ValidSortModes, ", "))
+	}
+	settings.Mode = mode
+	return settings, nil
+}
+
 // handleList lists all sessions
 func handleList(profile string, args []string) {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 	allProfiles := fs.Bool("all", false, "List sessions from all profiles")
+	allDecks := fs.Bool("all-decks", false, "List sessions from all profiles of every deck")
+	maxAge := fs.Duration("max-age", defaultStatusMaxAge, "Accept status cached by a running web server up to this old (0 = poll tmux directly)")
+	sortMode := fs.String("sort", "", "Order sessions: manual, activity or severity (default: [sort] mode in config)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck list [options]")
@@ -1083,14 +1313,26 @@ func handleList(profile string, args []string) {
 		fmt.Println("  agent-deck list                    # List from default profile")
 		fmt.Println("  agent-deck -p work list            # List from 'work' profile")
 		fmt.Println("  agent-deck list --all              # List from all profiles")
+		fmt.Println("  agent-deck list --all-decks        # List from every deck")
+		fmt.Println("  agent-deck list --sort severity    # Errors and waiting sessions first")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
 		os.Exit(1)
 	}
 
+	sortSettings, err := listSortSettings(*sortMode)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *allDecks {
+		handleListAllDecks(*jsonOutput)
+		return
+	}
 	if *allProfiles {
-		handleListAllProfiles(*jsonOutput)
+		handleListAllProfiles(*jsonOutput, sortSettings)
 		return
 	}
 
@@ -1111,6 +1353,12 @@ func handleList(profile string, args []string) {
 		return
 	}
 
+	// JSON output always carries status; the table only needs it to sort by severity
+	if *jsonOutput || sortSettings.GetMode() == session.SortModeSeverity {
+		refreshStatuses(storage.Profile(), instances, *maxAge)
+	}
+	session.SortInstances(instances, sortSettings)
+
 	if *jsonOutput {
 		// JSON output for scripting
 		type sessionJSON struct {
@@ -1123,20 +1371,33 @@ func handleList(profile string, args []string) {
 			Status    string    `json:"status"`
 			Profile   string    `json:"profile"`
 			CreatedAt time.Time `json:"created_at"`
-		}
+			// How long the session has been in its status, and time spent running today
+			StateSince    *time.Time `json:"state_since,omitempty"`
+			StateSecs     int64      `json:"state_secs,omitempty"`
+			BusyTodaySecs int64      `json:"busy_today_secs,omitempty"`
+			Pinned        bool       `json:"pinned,omitempty"`
+			StartReason   string     `json:"start_reason,omitempty"`
+		}
+		now := time.Now()
 		sessions := make([]sessionJSON, len(instances))
 		for i, inst := range instances {
-			_ = inst.UpdateStatus()
 			sessions[i] = sessionJSON{
-				ID:        inst.ID,
-				Title:     inst.Title,
-				Path:      inst.ProjectPath,
-				Group:     inst.GroupPath,
-				Tool:      inst.Tool,
-				Command:   inst.Command,
-				Status:    StatusString(inst.Status),
-				Profile:   storage.Profile(),
-				CreatedAt: inst.CreatedAt,
+				ID:            inst.ID,
+				Title:         inst.Title,
+				Path:          inst.ProjectPath,
+				Group:         inst.GroupPath,
+				Tool:          inst.Tool,
+				Command:       inst.Command,
+				Status:        StatusString(inst.Status),
+				Profile:       storage.Profile(),
+				CreatedAt:     inst.CreatedAt,
+				StateSecs:     int64(inst.StateDuration(now).Seconds()),
+				BusyTodaySecs: int64(inst.BusyToday().Seconds()),
+				Pinned:        sortSettings.IsPinned(inst),
+				StartReason:   inst.StartReason,
+			}
+			if since := inst.StateSince(); !since.IsZero() {
+				sessions[i].StateSince = &since
 			}
 		}
 		output, err := json.MarshalIndent(sessions, "", "  ")
@@ -1149,9 +1410,10 @@ func handleList(profile string, args []string) {
 	}
 
 	// Table output
+	printMaintenanceBanner(session.ActiveMaintenanceWindow(time.Now()))
 	fmt.Printf("Profile: %s\n\n", storage.Profile())
-	fmt.Printf("%-*s %-*s %-*s %s\n", tableColTitle, "TITLE", tableColGroup, "GROUP", tableColPath, "PATH", "ID")
-	fmt.Println(strings.Repeat("-", tableColTitle+tableColGroup+tableColPath+tableColIDDisplay+5))
+	fmt.Printf("%-*s %-*s %-*s %-*s %s\n", tableColTitle, "TITLE", tableColGroup, "GROUP", tableColPath, "PATH", tableColIDDisplay, "ID", "REASON")
+	fmt.Println(strings.Repeat("-", tableColTitle+tableColGroup+tableColPath+tableColIDDisplay+tableColReason+6))
 	for _, inst := range instances {
 		title := truncate(inst.Title, tableColTitle)
 		group := truncate(inst.GroupPath, tableColGroup)
@@ -1161,7 +1423,7 @@ func handleList(profile string, args []string) {
 		if len(idDisplay) > tableColIDDisplay {
 			idDisplay = idDisplay[:tableColIDDisplay]
 		}
-		fmt.Printf("%-*s %-*s %-*s %s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, idDisplay)
+		fmt.Printf("%-*s %-*s %-*s %-*s %s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, tableColIDDisplay, idDisplay, listReason(inst))
 	}
 	fmt.Printf("\nTotal: %d sessions\n", len(instances))
 
@@ -1169,8 +1431,16 @@ func handleList(profile string, args []string) {
 	printUpdateNotice()
 }
 
+// listReason returns the start reason column for the list table
+func listReason(inst *session.Instance) string {
+	if inst.StartReason == "" {
+		return "-"
+	}
+	return truncate(inst.StartReason, tableColReason)
+}
+
 // handleListAllProfiles lists sessions from all profiles
-func handleListAllProfiles(jsonOutput bool) {
+func handleListAllProfiles(jsonOutput bool, sortSettings session.SortSettings) {
 	profiles, err := session.ListProfiles()
 	if err != nil {
 		fmt.Printf("Error: failed to list profiles: %v\n", err)
@@ -1184,14 +1454,15 @@ func handleListAllProfiles(jsonOutput bool) {
 
 	if jsonOutput {
 		type sessionJSON struct {
-			ID        string    `json:"id"`
-			Title     string    `json:"title"`
-			Path      string    `json:"path"`
-			Group     string    `json:"group"`
-			Tool      string    `json:"tool"`
-			Command   string    `json:"command,omitempty"`
-			Profile   string    `json:"profile"`
-			CreatedAt time.Time `json:"created_at"`
+			ID          string    `json:"id"`
+			Title       string    `json:"title"`
+			Path        string    `json:"path"`
+			Group       string    `json:"group"`
+			Tool        string    `json:"tool"`
+			Command     string    `json:"command,omitempty"`
+			Profile     string    `json:"profile"`
+			CreatedAt   time.Time `json:"created_at"`
+			StartReason string    `json:"start_reason,omitempty"`
 		}
 		var allSessions []sessionJSON
 
@@ -1204,16 +1475,18 @@ func handleListAllProfiles(jsonOutput bool) {
 			if err != nil {
 				continue
 			}
+			session.SortInstances(instances, sortSettings)
 			for _, inst := range instances {
 				allSessions = append(allSessions, sessionJSON{
-					ID:        inst.ID,
-					Title:     inst.Title,
-					Path:      inst.ProjectPath,
-					Group:     inst.GroupPath,
-					Tool:      inst.Tool,
-					Command:   inst.Command,
-					Profile:   profileName,
-					CreatedAt: inst.CreatedAt,
+					ID:          inst.ID,
+					Title:       inst.Title,
+					Path:        inst.ProjectPath,
+					Group:       inst.GroupPath,
+					Tool:        inst.Tool,
+					Command:     inst.Command,
+					Profile:     profileName,
+					CreatedAt:   inst.CreatedAt,
+					StartReason: inst.StartReason,
 				})
 			}
 		}
@@ -1228,6 +1501,7 @@ func handleListAllProfiles(jsonOutput bool) {
 	}
 
 	// Table output grouped by profile
+	printMaintenanceBanner(session.ActiveMaintenanceWindow(time.Now()))
 	totalSessions := 0
 	for _, profileName := range profiles {
 		storage, err := session.NewStorageWithProfile(profileName)
@@ -1242,10 +1516,11 @@ func handleListAllProfiles(jsonOutput bool) {
 		if len(instances) == 0 {
 			continue
 		}
+		session.SortInstances(instances, sortSettings)
 
 		fmt.Printf("\n═══ Profile: %s ═══\n\n", profileName)
-		fmt.Printf("%-*s %-*s %-*s %s\n", tableColTitle, "TITLE", tableColGroup, "GROUP", tableColPath, "PATH", "ID")
-		fmt.Println(strings.Repeat("-", tableColTitle+tableColGroup+tableColPath+tableColIDDisplay+5))
+		fmt.Printf("%-*s %-*s %-*s %-*s %s\n", tableColTitle, "TITLE", tableColGroup, "GROUP", tableColPath, "PATH", tableColIDDisplay, "ID", "REASON")
+		fmt.Println(strings.Repeat("-", tableColTitle+tableColGroup+tableColPath+tableColIDDisplay+tableColReason+6))
 
 		for _, inst := range instances {
 			title := truncate(inst.Title, tableColTitle)
@@ -1255,7 +1530,7 @@ func handleListAllProfiles(jsonOutput bool) {
 			if len(idDisplay) > tableColIDDisplay {
 				idDisplay = idDisplay[:tableColIDDisplay]
 			}
-			fmt.Printf("%-*s %-*s %-*s %s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, idDisplay)
+			fmt.Printf("%-*s %-*s %-*s %-*s %s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, tableColIDDisplay, idDisplay, listReason(inst))
 		}
 		fmt.Printf("(%d sessions)\n", len(instances))
 		totalSessions += len(instances)
@@ -1342,7 +1617,8 @@ func handleRemove(profile string, args []string) {
 	// Direct SQL DELETE first to prevent resurrection by concurrent TUI force saves.
 	// The TUI's forceSaveInstances() can race with CLI deletion and re-insert the session.
 	// By deleting the row directly, we ensure it's gone even if SaveWithGroups races.
-	if err := storage.DeleteInstance(removedID); err != nil {
+	// TrashInstance keeps a copy in the trash so the session can be restored.
+	if err := storage.TrashInstance(removedID); err != nil {
 		if !*jsonOutput {
 			fmt.Printf("Warning: direct delete failed: %v\n", err)
 		}
@@ -1459,18 +1735,18 @@ func handleRename(profile string, args []string) {
 
 // statusCounts holds session counts by status
 type statusCounts struct {
-	running int
-	waiting int
-	idle    int
-	err     int
-	total   int
+	running   int
+	waiting   int
+	idle      int
+	err       int
+	throttled int
+	total     int
 }
 
 // countByStatus counts sessions by their status
 func countByStatus(instances []*session.Instance) statusCounts {
 	var counts statusCounts
 	for _, inst := range instances {
-		_ = inst.UpdateStatus() // Refresh status from tmux
 		switch inst.Status {
 		case session.StatusRunning:
 			counts.running++
@@ -1480,6 +1756,8 @@ func countByStatus(instances []*session.Instance) statusCounts {
 			counts.idle++
 		case session.StatusError:
 			counts.err++
+		case session.StatusThrottled:
+			counts.throttled++
 		}
 		counts.total++
 	}
@@ -1488,12 +1766,19 @@ func countByStatus(instances []*session.Instance) statusCounts {
 
 // handleStatus shows session status summary
 func handleStatus(profile string, args []string) {
+	if len(args) > 0 && args[0] == "export" {
+		handleStatusExport(args[1:])
+		return
+	}
+
 	fs := flag.NewFlagSet("status", flag.ExitOnError)
 	verbose := fs.Bool("verbose", false, "Show detailed session list")
 	verboseShort := fs.Bool("v", false, "Show detailed session list (short)")
 	quiet := fs.Bool("quiet", false, "Only output waiting count (for scripts)")
 	quietShort := fs.Bool("q", false, "Only output waiting count (short)")
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	maxAge := fs.Duration("max-age", defaultStatusMaxAge, "Accept status cached by a running web server up to this old (0 = poll tmux directly)")
+	at := fs.String("at", "", "Show the recorded deck state at a past time: HH:MM, \"YYYY-MM-DD HH:MM\", RFC3339 or a duration ago (2h)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck status [options]")
@@ -1508,6 +1793,9 @@ func handleStatus(profile string, args []string) {
 		fmt.Println("  agent-deck status -v           # Detailed list")
 		fmt.Println("  agent-deck status -q           # Just waiting count")
 		fmt.Println("  agent-deck -p work status      # Status for 'work' profile")
+		fmt.Println("  agent-deck status --max-age 0  # Skip the web server cache")
+		fmt.Println("  agent-deck status --at 03:00   # What was running at 3am")
+		fmt.Println("  agent-deck status export       # Write the [status_export] JSON snapshot")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -1521,6 +1809,11 @@ func handleStatus(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	if *at != "" {
+		printStateAt(storage, *at, *jsonOutput)
+		return
+	}
+
 	instances, _, err := storage.LoadWithGroups()
 	if err != nil {
 		fmt.Printf("Error: failed to load sessions: %v\n", err)
@@ -1529,7 +1822,7 @@ func handleStatus(profile string, args []string) {
 
 	if len(instances) == 0 {
 		if *jsonOutput {
-			fmt.Println(`{"waiting": 0, "running": 0, "idle": 0, "error": 0, "total": 0}`)
+			fmt.Println(`{"waiting": 0, "running": 0, "idle": 0, "error": 0, "throttled": 0, "total": 0}`)
 		} else if *quiet || *quietShort {
 			fmt.Println("0")
 		} else {
@@ -1539,7 +1832,9 @@ func handleStatus(profile string, args []string) {
 	}
 
 	// Count by status
+	refreshStatuses(storage.Profile(), instances, *maxAge)
 	counts := countByStatus(instances)
+	maintenance := session.ActiveMaintenanceWindow(time.Now())
 
 	// Output based on flags
 	if *jsonOutput {
@@ -1548,20 +1843,28 @@ func handleStatus(profile string, args []string) {
 			Running int `json:"running"`
 			Idle    int `json:"idle"`
 			Error   int `json:"error"`
-			Total   int `json:"total"`
+			// Throttled sessions are backing off from a provider rate limit
+			Throttled int `json:"throttled"`
+			Total     int `json:"total"`
+			// Maintenance is the active maintenance window, if any
+			Maintenance *session.ActiveMaintenance `json:"maintenance,omitempty"`
 		}
 		output, _ := json.Marshal(statusJSON{
-			Waiting: counts.waiting,
-			Running: counts.running,
-			Idle:    counts.idle,
-			Error:   counts.err,
-			Total:   counts.total,
+			Waiting:     counts.waiting,
+			Running:     counts.running,
+			Idle:        counts.idle,
+			Error:       counts.err,
+			Throttled:   counts.throttled,
+			Total:       counts.total,
+			Maintenance: maintenance,
 		})
 		fmt.Println(string(output))
 	} else if *quiet || *quietShort {
 		fmt.Println(counts.waiting)
 	} else if *verbose || *verboseShort {
 		// Detailed output grouped by status
+		printMaintenanceBanner(maintenance)
+		now := time.Now()
 		printStatusGroup := func(label, symbol string, status session.Status) {
 			var matching []*session.Instance
 			for _, inst := range instances {
@@ -1579,21 +1882,29 @@ func handleStatus(profile string, args []string) {
 				if strings.HasPrefix(path, home) {
 					path = "~" + path[len(home):]
 				}
-				fmt.Printf("  %s %-16s %-10s %s\n", symbol, inst.Title, inst.Tool, path)
+				fmt.Printf("  %s %-16s %-10s %-8s %s\n", symbol, inst.Title, inst.Tool, formatStateDuration(inst.StateDuration(now)), path)
 			}
 			fmt.Println()
 		}
 
-		printStatusGroup("WAITING", "◐", session.StatusWaiting)
-		printStatusGroup("RUNNING", "●", session.StatusRunning)
-		printStatusGroup("IDLE", "○", session.StatusIdle)
-		printStatusGroup("ERROR", "✕", session.StatusError)
+		printStatusGroup("WAITING", StatusSymbol(session.StatusWaiting), session.StatusWaiting)
+		printStatusGroup("RUNNING", StatusSymbol(session.StatusRunning), session.StatusRunning)
+		printStatusGroup("THROTTLED", StatusSymbol(session.StatusThrottled), session.StatusThrottled)
+		printStatusGroup("IDLE", StatusSymbol(session.StatusIdle), session.StatusIdle)
+		printStatusGroup("ERROR", StatusSymbol(session.StatusError), session.StatusError)
 
 		fmt.Printf("Total: %d sessions in profile '%s'\n", counts.total, storage.Profile())
 	} else {
 		// Compact output
-		fmt.Printf("%d waiting • %d running • %d idle\n",
+		fmt.Printf("%d waiting • %d running • %d idle",
 			counts.waiting, counts.running, counts.idle)
+		if counts.throttled > 0 {
+			fmt.Printf(" • %d throttled", counts.throttled)
+		}
+		if maintenance != nil {
+			fmt.Printf(" • maintenance (%s)", maintenance.Window.Name)
+		}
+		fmt.Println()
 	}
 
 	// Show update notice if available (skip for JSON/quiet output)
@@ -1602,6 +1913,52 @@ func handleStatus(profile string, args []string) {
 	}
 }
 
+// printStateAt prints the deck state recorded at a past time (status --at)
+func printStateAt(storage *session.Storage, when string, jsonOutput bool) {
+	at, err := session.ParseStateTime(when, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	db := storage.GetDB()
+	if db == nil {
+		fmt.Fprintln(os.Stderr, "Error: state database not available")
+		os.Exit(1)
+	}
+	state, err := session.StateAt(db, at)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read state history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		output, _ := json.MarshalIndent(state, "", "  ")
+		fmt.Println(string(output))
+		return
+	}
+	if state.Snapshot == nil {
+		fmt.Printf("No deck state recorded before %s in profile '%s'.\n", at.Format("2006-01-02 15:04"), storage.Profile())
+		fmt.Println("The TUI records it every [state_history] interval while it runs.")
+		return
+	}
+
+	fmt.Printf("Deck state at %s (snapshot %s), profile '%s':\n\n",
+		at.Format("2006-01-02 15:04"), state.Snapshot.Time.Format("15:04:05"), storage.Profile())
+	if len(state.Snapshot.Sessions) == 0 {
+		fmt.Println("  No sessions.")
+	}
+	for _, row := range state.Snapshot.Sessions {
+		fmt.Printf("  %s %-20s %-8s %-8s %s\n", StatusSymbol(session.Status(row.Status)), row.Title, row.Status, row.Tool, row.Group)
+	}
+	if len(state.Changes) > 0 {
+		fmt.Println()
+		fmt.Println("Changes since the snapshot:")
+		for _, change := range state.Changes {
+			fmt.Printf("  %s %-10s %s\n", change.Time.Format("15:04:05"), change.Action, change.Title)
+		}
+	}
+}
+
 // handleProfile manages profiles (list, create, delete, default)
 func handleProfile(args []string) {
 	// Extract --json and -q/--quiet flags from anywhere in args
@@ -1678,6 +2035,16 @@ func handleProfile(args []string) {
 			return
 		}
 		handleProfileSetDefault(out, filteredArgs[1])
+	case "proxy":
+		name := session.GetEffectiveProfile("")
+		if len(filteredArgs) >= 2 {
+			if isHelpArg(filteredArgs[1]) {
+				printProfileProxyHelp()
+				return
+			}
+			name = filteredArgs[1]
+		}
+		handleProfileProxy(out, jsonMode, name)
 	default:
 		out.Error(fmt.Sprintf("unknown profile command: %s", filteredArgs[0]), ErrCodeInvalidOperation)
 		if !jsonMode {
@@ -1698,6 +2065,7 @@ func printProfileHelp() {
 	fmt.Println("  create <name>     Create a new profile")
 	fmt.Println("  delete <name>     Delete a profile")
 	fmt.Println("  default [name]    Show or set default profile")
+	fmt.Println("  proxy [name]      Show the proxy and CA bundle injected into sessions")
 }
 
 func printProfileCreateHelp() {
@@ -1712,6 +2080,13 @@ func printProfileDefaultHelp() {
 	fmt.Println("Usage: agent-deck profile default [name]")
 }
 
+func printProfileProxyHelp() {
+	fmt.Println("Usage: agent-deck profile proxy [name]")
+	fmt.Println()
+	fmt.Println("Shows the effective [proxy] settings for a profile ([profiles.<name>.proxy]")
+	fmt.Println("layered over [proxy] in config.toml) and checks the CA bundle exists.")
+}
+
 func isHelpArg(arg string) bool {
 	return arg == "help" || arg == "--help" || arg == "-h"
 }
@@ -1775,6 +2150,46 @@ func handleProfileCreate(out *CLIOutput, name string) {
 	})
 }
 
+func handleProfileProxy(out *CLIOutput, jsonMode bool, name string) {
+	config, err := session.LoadUserConfig()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	settings := config.GetProxySettings(name)
+	validateErr := settings.Validate()
+
+	if jsonMode {
+		data := map[string]interface{}{
+			"profile": name,
+			"proxy":   settings.ProxyEnv(),
+			"ca":      settings.CAEnv(),
+		}
+		if validateErr != nil {
+			data["error"] = validateErr.Error()
+		}
+		out.Print("", data)
+	} else if settings.IsZero() {
+		fmt.Printf("No proxy configured for profile %s.\n", name)
+	} else {
+		fmt.Printf("Proxy for profile %s:\n", name)
+		for _, key := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"} {
+			if v := settings.ProxyEnv()[key]; v != "" {
+				fmt.Printf("  %-12s %s\n", key, v)
+			}
+		}
+		if settings.CABundle != "" {
+			fmt.Printf("  %-12s %s\n", "CA bundle", session.ExpandPath(settings.CABundle))
+		}
+	}
+	if validateErr != nil {
+		if !jsonMode {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", validateErr)
+		}
+		os.Exit(1)
+	}
+}
+
 func handleProfileDelete(out *CLIOutput, jsonMode bool, name string) {
 	// Skip confirmation in JSON mode (for automation)
 	if !jsonMode {
@@ -1817,6 +2232,7 @@ func handleProfileSetDefault(out *CLIOutput, name string) {
 func handleUpdate(args []string) {
 	fs := flag.NewFlagSet("update", flag.ExitOnError)
 	checkOnly := fs.Bool("check", false, "Only check for updates, don't install")
+	refresh := fs.Bool("refresh-artifacts", false, "Regenerate installed artifacts (bridge, heartbeats, units, completions) and restart daemons")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck update [options]")
@@ -1829,12 +2245,23 @@ func handleUpdate(args []string) {
 		fmt.Println("Examples:")
 		fmt.Println("  agent-deck update           # Check and install if available")
 		fmt.Println("  agent-deck update --check   # Only check, don't install")
+		fmt.Println()
+		fmt.Println("Updates are downloaded, checked against the release checksums, swapped in")
+		fmt.Println("atomically, then the new binary refreshes bridge.py, heartbeat timers,")
+		fmt.Println("units and completions and restarts daemons. If that last step fails the")
+		fmt.Println("previous binary is restored. Package-managed installs (Homebrew, nix,")
+		fmt.Println("apt/dnf) must be upgraded with their package manager.")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
 		os.Exit(1)
 	}
 
+	if *refresh {
+		handleRefreshArtifacts()
+		return
+	}
+
 	fmt.Printf("Agent Deck v%s\n", Version)
 	fmt.Println("Checking for updates...")
 
@@ -1875,21 +2302,57 @@ func handleUpdate(args []string) {
 
 	// Perform update
 	fmt.Println()
-	if err := update.PerformUpdate(info.DownloadURL); err != nil {
+	if _, err := applyUpdate(info); err != nil {
 		fmt.Printf("Error installing update: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Update bridge.py if conductor is installed
-	if err := update.UpdateBridgePy(); err != nil {
-		fmt.Printf("Warning: Failed to update bridge.py: %v\n", err)
-		fmt.Println("  You can manually refresh it with: agent-deck conductor setup <name>")
-	}
-
 	fmt.Printf("\n✓ Updated to v%s\n", info.LatestVersion)
 	fmt.Println("  Restart agent-deck to use the new version.")
 }
 
+// applyUpdate runs the staged self-update and prints each stage's outcome
+func applyUpdate(info *update.UpdateInfo) (*update.ApplyReport, error) {
+	fmt.Printf("Downloading v%s...\n", info.LatestVersion)
+	report, err := update.Apply(info)
+	for _, stage := range report.Stages {
+		mark := "✓"
+		if !stage.OK {
+			mark = "✗"
+		}
+		fmt.Printf("%s %-9s %s\n", mark, stage.Stage, strings.ReplaceAll(stage.Detail, "\n", "\n            "))
+	}
+	var pm *update.PackageManagerError
+	if errors.As(err, &pm) {
+		return report, fmt.Errorf("agent-deck was installed by %s and must be upgraded with it:\n  %s", pm.Manager, pm.Command)
+	}
+	if report.RolledBack {
+		fmt.Printf("Rolled back to v%s.\n", info.CurrentVersion)
+	}
+	return report, err
+}
+
+// handleRefreshArtifacts regenerates installed artifacts from this binary's
+// templates. "agent-deck update" runs it from the freshly installed binary.
+func handleRefreshArtifacts() {
+	completions := make(map[string]string, len(completionShells))
+	for _, shell := range completionShells {
+		completions[shell], _ = completionScript(shell)
+	}
+	refreshed, err := session.RefreshArtifacts(completions)
+	for _, a := range refreshed {
+		line := fmt.Sprintf("refreshed %s %s", a.Component, a.Path)
+		if a.Note != "" {
+			line += " (" + a.Note + ")"
+		}
+		fmt.Println(line)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 // displayChangelog fetches and displays changelog between versions
 func displayChangelog(currentVersion, latestVersion string) {
 	changelog, err := update.FetchChangelog()
@@ -1938,19 +2401,25 @@ func printHelp() {
 	fmt.Printf("Agent Deck v%s\n", Version)
 	fmt.Println("Terminal session manager for AI coding agents")
 	fmt.Println()
-	fmt.Println("Usage: agent-deck [-p profile] [command]")
+	fmt.Println("Usage: agent-deck [--deck name] [-p profile] [command]")
 	fmt.Println()
 	fmt.Println("Global Options:")
 	fmt.Println("  -p, --profile <name>   Use specific profile (default: 'default')")
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  (none)           Start the TUI")
-	fmt.Println("  add <path>       Add a new session")
+	fmt.Println("  init             Guided first-time setup")
+	fmt.Println("  add, new <path>  Add a new session")
 	fmt.Println("  launch [path]    Add, start, and optionally send a message in one step")
 	fmt.Println("  try <name>       Quick experiment (create/find dated folder + session)")
+	fmt.Println("  review [branch]  Start a review session preloaded with a branch's diff")
 	fmt.Println("  list, ls         List all sessions")
 	fmt.Println("  remove, rm       Remove a session")
 	fmt.Println("  rename, mv       Rename a session")
+	fmt.Println("  trash            List or restore removed sessions and conductors")
+	fmt.Println("  maintenance      Pause heartbeats and auto-restarts during maintenance windows")
+	fmt.Println("  emergency-stop   Interrupt every busy session and pause the deck until resumed")
+	fmt.Println("  guard            Log or require approval for kubectl, terraform, aws in sessions")
 	fmt.Println("  status           Show session status summary")
 	fmt.Println("  session          Manage session lifecycle")
 	fmt.Println("  mcp              Manage MCP servers")
@@ -1962,6 +2431,20 @@ func printHelp() {
 	fmt.Println("  conductor        Manage conductor meta-agent orchestration")
 	fmt.Println("  profile          Manage profiles")
 	fmt.Println("  update           Check for and install updates")
+	fmt.Println("  install          Install config, completions, restore-on-boot and bridge")
+	fmt.Println("  completion       Print a shell completion script (bash, zsh, fish)")
+	fmt.Println("  stats            Opt-in local usage counters (show, enable, export)")
+	fmt.Println("  cost export      Daily token usage, busy time, tasks and cost per session as CSV")
+	fmt.Println("  usage            Token usage and cost per day/week by session, conductor or profile")
+	fmt.Println("  tmux check       Check tmux options agent-deck depends on (--fix to set them)")
+	fmt.Println("  features         Feature flags for experimental subsystems (per deck or session)")
+	fmt.Println("  deck             List decks (separate workspaces, selected with --deck)")
+	fmt.Println("  remote           List, launch, attach and watch sessions on hosts over ssh")
+	fmt.Println("  patterns         Import/export status detection pattern packs per tool")
+	fmt.Println("  logs <id>        Print (or -f follow) a session's captured transcript")
+	fmt.Println("  search <query>   Search all sessions' transcripts and Claude conversations")
+	fmt.Println("  queue            Send session ops via the web server, queued while it is down")
+	fmt.Println("  --events         Stream status changes, escalations and heartbeats as JSON lines")
 	fmt.Println("  uninstall        Uninstall Agent Deck")
 	fmt.Println("  version          Show version")
 	fmt.Println("  help             Show this help")
@@ -1970,6 +2453,7 @@ func printHelp() {
 	fmt.Println("  session start <id>        Start a session's tmux process")
 	fmt.Println("  session stop <id>         Stop session process")
 	fmt.Println("  session restart <id>      Restart session (reload MCPs)")
+	fmt.Println("  session restore           Restart sessions lost in a reboot")
 	fmt.Println("  session fork <id>         Fork Claude session with context")
 	fmt.Println("  session attach <id>       Attach to session interactively")
 	fmt.Println("  session show [id]         Show session details")
@@ -2030,11 +2514,16 @@ func printHelp() {
 	fmt.Println("  agent-deck web --listen :9000         # TUI + web on custom port")
 	fmt.Println("  agent-deck web --read-only            # TUI + web in read-only mode")
 	fmt.Println("  agent-deck web --token secret         # TUI + web with auth token")
+	fmt.Println("  agent-deck web --headless             # Web server only, no TUI")
+	fmt.Println("  agent-deck web daemon install         # Socket-activated web server (systemd)")
 	fmt.Println("  agent-deck web --help                 # Show web command flags")
 	fmt.Println()
 	fmt.Println("Environment Variables:")
 	fmt.Println("  AGENTDECK_PROFILE    Default profile to use")
 	fmt.Println("  AGENTDECK_COLOR      Color mode: truecolor, 256, 16, none")
+	fmt.Println("  AGENT_DECK_HOME      Data directory of a separate deck (default ~/.agent-deck)")
+	fmt.Println("  AGENT_DECK_NAME      Run a separate deck side by side: own data directory,")
+	fmt.Println("                       tmux socket and systemd/launchd unit names")
 	fmt.Println()
 	fmt.Println("Keyboard shortcuts (in TUI):")
 	fmt.Println("  n          New session")
@@ -2088,6 +2577,10 @@ func detectTool(cmd string) string {
 		return "gemini"
 	case strings.Contains(cmd, "codex"):
 		return "codex"
+	case strings.Contains(cmd, "ollama"):
+		return "ollama"
+	case strings.Contains(cmd, "aider"):
+		return "aider"
 	case strings.Contains(cmd, "cursor"):
 		return "cursor"
 	default:
@@ -2136,7 +2629,10 @@ func handleUninstall(args []string) {
 	}
 
 	homeDir, _ := os.UserHomeDir()
-	dataDir := filepath.Join(homeDir, ".agent-deck")
+	dataDir, err := deck.Home()
+	if err != nil {
+		dataDir = filepath.Join(homeDir, ".agent-deck")
+	}
 
 	// Track what we find
 	type foundItem struct {
@@ -2283,6 +2779,7 @@ func handleUninstall(args []string) {
 		}
 	}
 
+	fmt.Println("  • Background services (bridge, heartbeats, restore-on-boot) and shell completions")
 	fmt.Println()
 
 	// Confirm unless -y flag
@@ -2365,7 +2862,17 @@ func handleUninstall(args []string) {
 		}
 	}
 
-	// 3. tmux config
+	// 3. Services and shell completions (data dir is handled below)
+	fmt.Println("Removing background services and shell completions...")
+	removed, err := session.UninstallAll(false)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	for _, path := range removed {
+		fmt.Printf("✓ Removed: %s\n", path)
+	}
+
+	// 4. tmux config
 	if !*keepTmuxConfig {
 		for _, item := range foundItems {
 			if item.itemType != "tmux" {
@@ -2418,7 +2925,7 @@ func handleUninstall(args []string) {
 		}
 	}
 
-	// 4. Data directory
+	// 5. Data directory
 	if !*keepData {
 		for _, item := range foundItems {
 			if item.itemType != "data" {
@@ -2437,7 +2944,7 @@ func handleUninstall(args []string) {
 					)
 					fmt.Printf("Creating backup at %s...\n", backupFile)
 
-					cmd := exec.Command("tar", "-czf", backupFile, "-C", homeDir, ".agent-deck")
+					cmd := exec.Command("tar", "-czf", backupFile, "-C", filepath.Dir(dataDir), filepath.Base(dataDir))
 					if err := cmd.Run(); err != nil {
 						fmt.Printf("Warning: failed to create backup: %v\n", err)
 					} else {
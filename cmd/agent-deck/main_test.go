@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"testing"
 
+	"github.com/asheshgoplani/agent-deck/internal/logging"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 	"github.com/asheshgoplani/agent-deck/internal/ui"
 )
@@ -105,6 +106,19 @@ func TestNestedSessionAllowsCLICommands(t *testing.T) {
 			t.Errorf("expected empty args for TUI mode with profile flag, got %v", args)
 		}
 	})
+
+	// Deck flag is extracted before the profile flag, in either form
+	t.Run("deck_flag_with_profile_and_subcommand", func(t *testing.T) {
+		deckName, args := extractDeckFlag([]string{"--deck", "work", "-p", "ops", "list"})
+		profile, args := extractProfileFlag(args)
+		if deckName != "work" || profile != "ops" || len(args) != 1 || args[0] != "list" {
+			t.Errorf("got deck=%q profile=%q args=%v", deckName, profile, args)
+		}
+		deckName, args = extractDeckFlag([]string{"list", "--deck=oss"})
+		if deckName != "oss" || len(args) != 1 || args[0] != "list" {
+			t.Errorf("got deck=%q args=%v for --deck=oss", deckName, args)
+		}
+	})
 }
 
 func TestIsDuplicateSession(t *testing.T) {
@@ -177,3 +191,22 @@ func TestIsDuplicateSession(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyLogSettingsAndEnv(t *testing.T) {
+	logCfg := logging.Config{Level: "info", Format: "json", MaxSizeMB: 10}
+	applyLogSettings(&logCfg, session.LogSettings{
+		DebugLevel:  "debug",
+		DebugFormat: "text",
+		SystemSink:  logging.SinkJournald,
+	})
+	if logCfg.Level != "debug" || logCfg.Format != "text" || logCfg.MaxSizeMB != 10 || logCfg.System != logging.SinkJournald {
+		t.Fatalf("settings not applied: %+v", logCfg)
+	}
+
+	t.Setenv("AGENTDECK_LOG_LEVEL", "warn")
+	t.Setenv("AGENTDECK_LOG_FORMAT", "json")
+	applyLogEnv(&logCfg)
+	if logCfg.Level != "warn" || logCfg.Format != "json" {
+		t.Errorf("env overrides not applied: level=%q format=%q", logCfg.Level, logCfg.Format)
+	}
+}
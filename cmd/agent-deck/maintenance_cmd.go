@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleMaintenance dispatches maintenance window subcommands
+func handleMaintenance(args []string) {
+	if len(args) == 0 {
+		handleMaintenanceStatus(nil)
+		return
+	}
+
+	switch args[0] {
+	case "status":
+		handleMaintenanceStatus(args[1:])
+	case "list", "ls":
+		handleMaintenanceList(args[1:])
+	case "start":
+		handleMaintenanceStart(args[1:])
+	case "schedule":
+		handleMaintenanceSchedule(args[1:])
+	case "stop", "end":
+		handleMaintenanceStop(args[1:])
+	case "remove", "rm":
+		handleMaintenanceRemove(args[1:])
+	case "help", "--help", "-h":
+		printMaintenanceHelp()
+	default:
+		fmt.Printf("Unknown maintenance command: %s\n", args[0])
+		fmt.Println()
+		printMaintenanceHelp()
+		os.Exit(1)
+	}
+}
+
+// printMaintenanceHelp prints usage for maintenance commands
+func printMaintenanceHelp() {
+	fmt.Println("Usage: agent-deck maintenance <command> [options]")
+	fmt.Println()
+	fmt.Println("Maintenance windows pause heartbeats, conductor task dispatch and automatic")
+	fmt.Println("conductor restarts. Everything resumes when the window ends. Windows come")
+	fmt.Println("from [[maintenance.windows]] in config.toml or are added here.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  status            Show the active window (default; -q exits 0 only when active)")
+	fmt.Println("  list              List configured windows")
+	fmt.Println("  start             Start a one-off window now (--for 1h)")
+	fmt.Println("  schedule <name>   Add a one-off (--start/--end) or recurring (--from/--to) window")
+	fmt.Println("  stop              End active one-off windows started from the CLI")
+	fmt.Println("  remove <name>     Remove a window added from the CLI")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck maintenance start --for 2h --reason \"db migration\"")
+	fmt.Println("  agent-deck maintenance schedule upgrade --start \"2026-11-01 22:00\" --end \"2026-11-02 01:00\"")
+	fmt.Println("  agent-deck maintenance schedule nightly --from 02:00 --to 03:00 --days mon,tue,wed,thu,fri")
+	fmt.Println("  agent-deck maintenance stop")
+}
+
+func handleMaintenanceStatus(args []string) {
+	fs := flag.NewFlagSet("maintenance status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "No output; exit 0 when a window is active, 1 otherwise")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	active := session.ActiveMaintenanceWindow(time.Now())
+	switch {
+	case *quiet:
+		if active == nil {
+			os.Exit(1)
+		}
+	case *jsonOutput:
+		out := NewCLIOutput(true, false)
+		out.Print("", map[string]any{"active": active != nil, "maintenance": active})
+	case active == nil:
+		fmt.Println("No maintenance window active.")
+	default:
+		fmt.Println(active.Describe())
+	}
+}
+
+func handleMaintenanceList(args []string) {
+	fs := flag.NewFlagSet("maintenance list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	entries, err := session.ListMaintenanceWindows()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *jsonOutput {
+		out.Print("", map[string]any{"windows": entries})
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Println("No maintenance windows.")
+		return
+	}
+	now := time.Now()
+	for _, e := range entries {
+		when := fmt.Sprintf("%s -> %s", e.Start, e.End)
+		if e.Recurring() {
+			days := "daily"
+			if len(e.Days) > 0 {
+				days = strings.Join(e.Days, ",")
+			}
+			when = fmt.Sprintf("%s %s-%s", days, e.From, e.To)
+		}
+		if e.Timezone != "" {
+			when += " " + e.Timezone
+		}
+		state := ""
+		if _, _, ok := e.ActiveAt(now); ok {
+			state = "  [active]"
+		}
+		fmt.Printf("  %-20s %-6s %s%s\n", e.Name, e.Source, when, state)
+		if e.Reason != "" {
+			fmt.Printf("    %s\n", e.Reason)
+		}
+	}
+}
+
+func handleMaintenanceStart(args []string) {
+	fs := flag.NewFlagSet("maintenance start", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	duration := fs.Duration("for", time.Hour, "How long the window lasts")
+	name := fs.String("name", "adhoc", "Window name")
+	reason := fs.String("reason", "", "Why the deck is paused (shown in banners)")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if *duration <= 0 {
+		out.Error("--for must be positive", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	window := session.MaintenanceWindow{
+		Name:   *name,
+		Reason: *reason,
+		Start:  now.Format(time.RFC3339),
+		End:    now.Add(*duration).Format(time.RFC3339),
+	}
+	if err := session.AddMaintenanceWindow(window, now); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Maintenance window %q started, ends %s", window.Name,
+		session.NewTimeFormatter(true).FormatAbsolute(now.Add(*duration))), map[string]any{"window": window})
+}
+
+func handleMaintenanceSchedule(args []string) {
+	fs := flag.NewFlagSet("maintenance schedule", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	start := fs.String("start", "", "One-off start (RFC3339 or \"YYYY-MM-DD HH:MM\")")
+	end := fs.String("end", "", "One-off end (RFC3339 or \"YYYY-MM-DD HH:MM\")")
+	from := fs.String("from", "", "Recurring start time (HH:MM)")
+	to := fs.String("to", "", "Recurring end time (HH:MM, may be past midnight)")
+	days := fs.String("days", "", "Recurring days, comma-separated (mon..sun; default: every day)")
+	timezone := fs.String("timezone", "", "IANA timezone for the window (default: [time] timezone)")
+	reason := fs.String("reason", "", "Why the deck is paused (shown in banners)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck maintenance schedule <name> [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck maintenance schedule <name> (--start/--end | --from/--to)", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	window := session.MaintenanceWindow{
+		Name:     fs.Arg(0),
+		Reason:   *reason,
+		Start:    *start,
+		End:      *end,
+		Days:     splitList(*days),
+		From:     *from,
+		To:       *to,
+		Timezone: *timezone,
+	}
+	if err := session.AddMaintenanceWindow(window, time.Now()); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Scheduled maintenance window %q", window.Name), map[string]any{"window": window})
+}
+
+func handleMaintenanceStop(args []string) {
+	fs := flag.NewFlagSet("maintenance stop", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	ended, err := session.EndMaintenance(time.Now())
+	if err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrNoMaintenanceWindow) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	msg := fmt.Sprintf("Ended maintenance window %s", strings.Join(ended, ", "))
+	if active := session.ActiveMaintenanceWindow(time.Now()); active != nil {
+		msg += fmt.Sprintf(" (%q is still active)", active.Window.Name)
+	}
+	out.Success(msg, map[string]any{"ended": ended})
+}
+
+func handleMaintenanceRemove(args []string) {
+	fs := flag.NewFlagSet("maintenance remove", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck maintenance remove <name>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := session.RemoveMaintenanceWindow(fs.Arg(0), time.Now()); err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Removed maintenance window %q", fs.Arg(0)), map[string]any{"name": fs.Arg(0)})
+}
+
+// printMaintenanceBanner prints the active maintenance window (if any) above listings
+func printMaintenanceBanner(active *session.ActiveMaintenance) {
+	if active != nil {
+		fmt.Printf("!! %s\n\n", active.Describe())
+	}
+}
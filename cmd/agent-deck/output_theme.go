@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// statusTheme renders status glyphs for human-readable CLI output according
+// to [output] in config.toml and NO_COLOR.
+type statusTheme struct {
+	settings session.OutputSettings
+	renderer *lipgloss.Renderer
+	color    bool
+}
+
+func newStatusTheme(settings session.OutputSettings, isTerminal bool) *statusTheme {
+	t := &statusTheme{
+		settings: settings,
+		renderer: lipgloss.NewRenderer(os.Stdout),
+		color:    settings.ColorEnabled(isTerminal),
+	}
+	switch {
+	case !t.color:
+		t.renderer.SetColorProfile(termenv.Ascii)
+	case t.renderer.ColorProfile() == termenv.Ascii:
+		// color = "always" into a pipe: detection says no color, so pick a safe profile
+		t.renderer.SetColorProfile(termenv.ANSI256)
+	}
+	return t
+}
+
+// cliStatusTheme is built once per process from the user config
+var cliStatusTheme = sync.OnceValue(func() *statusTheme {
+	return newStatusTheme(session.GetOutputSettings(), term.IsTerminal(int(os.Stdout.Fd())))
+})
+
+// Glyph returns the (possibly colored) symbol for status
+func (t *statusTheme) Glyph(status session.Status) string {
+	glyph := t.settings.StatusGlyph(string(status))
+	if !t.color {
+		return glyph
+	}
+	c := t.settings.StatusColor(string(status))
+	if c == "" {
+		return glyph
+	}
+	return t.renderer.NewStyle().Foreground(lipgloss.Color(c)).Render(glyph)
+}
+
+// Label returns the glyph followed by text, or just text in screen-reader
+// mode where the glyph already is the status word.
+func (t *statusTheme) Label(status session.Status, text string) string {
+	if t.settings.ScreenReader {
+		return text
+	}
+	return t.Glyph(status) + " " + text
+}
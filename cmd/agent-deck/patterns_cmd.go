@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handlePatterns dispatches pattern pack subcommands
+func handlePatterns(args []string) {
+	if len(args) == 0 {
+		handlePatternsList(nil)
+		return
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handlePatternsList(args[1:])
+	case "export":
+		handlePatternsExport(args[1:])
+	case "import":
+		handlePatternsImport(args[1:])
+	case "remove", "rm":
+		handlePatternsRemove(args[1:])
+	case "help", "--help", "-h":
+		printPatternsHelp()
+	default:
+		fmt.Printf("Unknown patterns command: %s\n", args[0])
+		fmt.Println()
+		printPatternsHelp()
+		os.Exit(1)
+	}
+}
+
+// printPatternsHelp prints usage for pattern pack commands
+func printPatternsHelp() {
+	fmt.Println("Usage: agent-deck patterns <command> [options]")
+	fmt.Println()
+	fmt.Println("Pattern packs are shareable status detection patterns (busy/prompt patterns,")
+	fmt.Println("spinner chars) for one tool, targeting a range of tool versions. An installed")
+	fmt.Println("pack replaces the built-in patterns it sets while the installed tool version")
+	fmt.Println("is in its range; [tools.<tool>] settings in config.toml still apply on top,")
+	fmt.Println("and a project's .agent-deck/patterns.toml on top of those for its sessions.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list [tool]            List installed packs and which one is active (default)")
+	fmt.Println("  export <tool>          Write the tool's effective patterns as a pack")
+	fmt.Println("  import <file|url>      Validate and install a pack (replaces one of the same name)")
+	fmt.Println("  remove <tool> <name>   Uninstall a pack")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck patterns export claude -o claude-patterns.toml")
+	fmt.Println("  agent-deck patterns export codex --name codex-tuned --tool-versions '>=0.40, <1'")
+	fmt.Println("  agent-deck patterns import https://example.com/packs/gemini-0.9.toml")
+}
+
+func handlePatternsList(args []string) {
+	fs := flag.NewFlagSet("patterns list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	packs, err := session.ListPatternPacks(fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	active := make(map[string]string)
+	for _, pack := range packs {
+		if _, ok := active[pack.Tool]; ok {
+			continue
+		}
+		active[pack.Tool] = ""
+		if a := session.ActivePatternPack(pack.Tool); a != nil {
+			active[pack.Tool] = a.Name
+		}
+	}
+
+	if *jsonOutput {
+		if packs == nil {
+			packs = []*session.PatternPack{}
+		}
+		out.Print("", map[string]any{"pattern_packs": packs, "active": active})
+		return
+	}
+	if len(packs) == 0 {
+		fmt.Println("No pattern packs.")
+		fmt.Println("Import one with: agent-deck patterns import <file|url>")
+		return
+	}
+	for _, pack := range packs {
+		marker := " "
+		if active[pack.Tool] == pack.Name {
+			marker = "*"
+		}
+		targets := pack.ToolVersions
+		if targets == "" {
+			targets = "any version"
+		}
+		fmt.Printf("%s %-10s %-24s v%-8s %s\n", marker, pack.Tool, pack.Name, pack.Version, targets)
+		if pack.Description != "" {
+			fmt.Printf("    %s\n", pack.Description)
+		}
+	}
+	fmt.Println()
+	fmt.Println("* = active for the installed tool version")
+}
+
+func handlePatternsExport(args []string) {
+	fs := flag.NewFlagSet("patterns export", flag.ExitOnError)
+	output := fs.String("o", "", "Write to this file instead of stdout")
+	name := fs.String("name", "", "Pack name (default: <tool>-local)")
+	version := fs.String("version", "1", "Pack version")
+	description := fs.String("description", "", "Pack description")
+	toolVersions := fs.String("tool-versions", "", "Tool versions the pack targets, e.g. '>=2.1, <3' (default: installed version and newer)")
+	project := fs.String("project", "", "Also apply this project's "+session.ProjectPatternsFile)
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck patterns export <tool> [options]")
+		fmt.Println()
+		fmt.Println("Export the tool's effective patterns: built-in defaults, the active pack and")
+		fmt.Println("[tools.<tool>] overrides and extras from config.toml, merged. With --project,")
+		fmt.Println("the project's " + session.ProjectPatternsFile + " is applied on top.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	projectPath := *project
+	if projectPath != "" {
+		projectPath = session.ExpandPath(projectPath)
+		if _, err := session.LoadProjectPatterns(projectPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	pack, err := session.ExportPatternPack(fs.Arg(0), *name, *version, *toolVersions, projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	pack.Description = *description
+	data, err := pack.Encode()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("[ok] Pattern pack %s for %s written to %s\n", pack.Name, pack.Tool, *output)
+}
+
+func handlePatternsImport(args []string) {
+	fs := flag.NewFlagSet("patterns import", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck patterns import <file|url>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	pack, replaced, err := session.ImportPatternPack(fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	msg := fmt.Sprintf("Installed pattern pack %s v%s for %s", pack.Name, pack.Version, pack.Tool)
+	if replaced != "" {
+		msg = fmt.Sprintf("Replaced pattern pack %s v%s with v%s for %s", pack.Name, replaced, pack.Version, pack.Tool)
+	}
+	active := session.ActivePatternPack(pack.Tool)
+	switch {
+	case active == nil:
+		msg += fmt.Sprintf(" (inactive: installed %s version is outside %s)", pack.Tool, pack.ToolVersions)
+	case active.Name != pack.Name:
+		msg += fmt.Sprintf(" (inactive: %s v%s takes precedence)", active.Name, active.Version)
+	}
+	out.Success(msg, map[string]any{"pattern_pack": pack, "replaced": replaced, "active": active != nil && active.Name == pack.Name})
+}
+
+func handlePatternsRemove(args []string) {
+	fs := flag.NewFlagSet("patterns remove", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 2 {
+		out.Error("usage: agent-deck patterns remove <tool> <name>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if err := session.RemovePatternPack(fs.Arg(0), fs.Arg(1)); err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrPatternPackNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Removed pattern pack %s for %s", fs.Arg(1), fs.Arg(0)), map[string]any{"tool": fs.Arg(0), "name": fs.Arg(1)})
+}
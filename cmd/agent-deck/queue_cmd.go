@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/web"
+)
+
+// queueSubmitTimeout bounds each request to the web server, so a suspended
+// or wedged server queues instead of hanging the CLI
+const queueSubmitTimeout = 10 * time.Second
+
+// handleQueue dispatches queue subcommands
+func handleQueue(profile string, args []string) {
+	if len(args) == 0 {
+		printQueueHelp()
+		return
+	}
+
+	switch args[0] {
+	case "submit":
+		handleQueueSubmit(profile, args[1:])
+	case "list":
+		handleQueueList(profile, args[1:])
+	case "flush":
+		handleQueueFlush(profile, args[1:])
+	case "clear":
+		handleQueueClear(profile, args[1:])
+	case "help", "--help", "-h":
+		printQueueHelp()
+	default:
+		fmt.Printf("Unknown queue command: %s\n", args[0])
+		fmt.Println()
+		printQueueHelp()
+		os.Exit(1)
+	}
+}
+
+// printQueueHelp prints usage for queue commands
+func printQueueHelp() {
+	fmt.Println("Usage: agent-deck queue <command> [options]")
+	fmt.Println()
+	fmt.Println("Send session operations through the profile's web server, queueing them")
+	fmt.Println("locally while it is unreachable (e.g. restarting after suspend/resume).")
+	fmt.Println("Queued operations are replayed, oldest first, by the next submit or flush")
+	fmt.Println("and by the web server when it starts. Each carries a unique request ID,")
+	fmt.Println("so one replayed twice is applied once.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  submit <op> <id|title> [text]  Run stop, restart, send or macro, or queue it")
+	fmt.Println("  list                           Show queued operations")
+	fmt.Println("  flush                          Replay queued operations now")
+	fmt.Println("  clear                          Drop queued operations without running them")
+}
+
+// handleQueueSubmit runs one session op through the web server, queueing it
+// when the server can't be reached
+func handleQueueSubmit(profile string, args []string) {
+	fs := flag.NewFlagSet("queue submit", flag.ExitOnError)
+	macro := fs.String("macro", "", "Macro name (for op macro; may also be given as the third argument)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck queue submit <op> <id|title> [text|macro] [options]")
+		fmt.Println()
+		fmt.Println("Run a session operation through the web server. When the server is")
+		fmt.Println("unreachable the operation is queued and replayed later. Operations queued")
+		fmt.Println("earlier are replayed first.")
+		fmt.Println()
+		fmt.Println("Ops: stop, restart, send <text>, macro <name>")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck queue submit send my-project \"run the tests\"")
+		fmt.Println("  agent-deck queue submit restart my-project --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	op := web.SessionOp{Op: fs.Arg(0), Macro: *macro}
+	switch op.Op {
+	case web.SessionOpStop, web.SessionOpRestart:
+	case web.SessionOpSend:
+		op.Text = fs.Arg(2)
+		if op.Text == "" {
+			out.Error("send needs text", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	case web.SessionOpMacro:
+		if op.Macro == "" {
+			op.Macro = fs.Arg(2)
+		}
+		if op.Macro == "" {
+			out.Error("macro needs a macro name", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	default:
+		out.Error(fmt.Sprintf("unknown op %q (valid: stop, restart, send, macro)", op.Op), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	// Resolved locally, so titles work while the server is down
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(fs.Arg(1), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+	op.ID = inst.ID
+
+	queue, err := web.NewOfflineQueue(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	batch := web.BatchRequest{RequestID: web.NewRequestID(), Ops: []web.SessionOp{op}}
+
+	ep, flushed, err := flushOfflineQueue(profile, queue)
+	var resp *web.BatchResponse
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), queueSubmitTimeout)
+		resp, err = web.SubmitBatch(ctx, ep, batch)
+		cancel()
+	}
+	var unreachable *web.UnreachableError
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.As(err, &unreachable) {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err != nil {
+		id, qerr := queue.Enqueue(batch)
+		if qerr != nil {
+			out.Error(qerr.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		out.Success(fmt.Sprintf("Web server unreachable, queued %s %s (%s)", op.Op, inst.Title, id), map[string]any{
+			"success":   true,
+			"queued":    true,
+			"requestId": id,
+			"op":        op.Op,
+			"id":        inst.ID,
+			"title":     inst.Title,
+			"replayed":  len(flushed),
+		})
+		return
+	}
+
+	result := resp.Results[0]
+	if !result.OK {
+		msg := "operation failed"
+		if result.Error != nil {
+			msg = result.Error.Message
+		}
+		out.Error(fmt.Sprintf("%s %s: %s", op.Op, inst.Title, msg), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("%s %s", op.Op, inst.Title), map[string]any{
+		"success":   true,
+		"queued":    false,
+		"requestId": batch.RequestID,
+		"op":        op.Op,
+		"id":        inst.ID,
+		"title":     inst.Title,
+		"replayed":  len(flushed),
+	})
+}
+
+// flushOfflineQueue replays the profile's queued batches on its web server.
+// Errors are os.ErrNotExist when no server is running and
+// *web.UnreachableError when it can't be reached.
+func flushOfflineQueue(profile string, queue *web.OfflineQueue) (*session.WebEndpoint, []web.ReplayedBatch, error) {
+	ep, err := session.ReadWebEndpoint(profile)
+	if err != nil {
+		return nil, nil, os.ErrNotExist
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), queueSubmitTimeout)
+	defer cancel()
+	replayed, err := queue.Replay(ctx, ep)
+	return ep, replayed, err
+}
+
+// handleQueueList shows the queued batches
+func handleQueueList(profile string, args []string) {
+	fs := flag.NewFlagSet("queue list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	queue, err := web.NewOfflineQueue(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	batches, err := queue.List()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *jsonOutput {
+		if batches == nil {
+			batches = []web.QueuedBatch{}
+		}
+		out.Print("", map[string]any{"queued": batches})
+		return
+	}
+	if len(batches) == 0 {
+		fmt.Println("No queued operations")
+		return
+	}
+	for _, batch := range batches {
+		for _, op := range batch.Ops {
+			detail := op.Text
+			if op.Op == web.SessionOpMacro {
+				detail = op.Macro
+			}
+			fmt.Printf("%s  %s  %-8s %s %s\n", batch.QueuedAt.Local().Format("2006-01-02 15:04:05"), batch.ID, op.Op, op.ID, truncate(detail, 40))
+		}
+	}
+}
+
+// handleQueueFlush replays the queued batches now
+func handleQueueFlush(profile string, args []string) {
+	fs := flag.NewFlagSet("queue flush", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	queue, err := web.NewOfflineQueue(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	_, replayed, err := flushOfflineQueue(profile, queue)
+	pending, _ := queue.List()
+	if errors.Is(err, os.ErrNotExist) {
+		out.Error(fmt.Sprintf("no web server running for this profile (%d queued)", len(pending)), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	if err != nil {
+		out.Error(fmt.Sprintf("%v (%d replayed, %d still queued)", err, len(replayed), len(pending)), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *jsonOutput {
+		if replayed == nil {
+			replayed = []web.ReplayedBatch{}
+		}
+		out.Print("", map[string]any{"replayed": replayed})
+		return
+	}
+	for _, r := range replayed {
+		status := "ok"
+		if r.Error != "" {
+			status = "rejected: " + r.Error
+		} else if failed := failedOps(r.Response); failed > 0 {
+			status = fmt.Sprintf("%d op(s) failed", failed)
+		}
+		fmt.Printf("%s  %s\n", r.ID, status)
+	}
+	fmt.Printf("[ok] %d queued batch(es) replayed\n", len(replayed))
+}
+
+// failedOps counts the ops of a batch response that did not succeed
+func failedOps(resp *web.BatchResponse) int {
+	failed := 0
+	for _, result := range resp.Results {
+		if !result.OK {
+			failed++
+		}
+	}
+	return failed
+}
+
+// handleQueueClear drops the queued batches
+func handleQueueClear(profile string, args []string) {
+	fs := flag.NewFlagSet("queue clear", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	queue, err := web.NewOfflineQueue(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	n, err := queue.Clear()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Dropped %d queued batch(es)", n), map[string]any{"success": true, "dropped": n})
+}
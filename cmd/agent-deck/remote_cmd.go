@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/remote"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// remoteTimeout bounds one request to a remote host, connection included
+const remoteTimeout = 30 * time.Second
+
+// handleRemote dispatches remote subcommands
+func handleRemote(args []string) {
+	if len(args) == 0 {
+		handleRemoteHosts(nil)
+		return
+	}
+
+	switch args[0] {
+	case "hosts":
+		handleRemoteHosts(args[1:])
+	case "list", "ls":
+		handleRemoteList(args[1:])
+	case "attach":
+		handleRemoteAttach(args[1:])
+	case "launch", "new":
+		handleRemotePassthrough("launch", args[1:])
+	case "run":
+		handleRemotePassthrough("", args[1:])
+	case "status":
+		handleRemoteStatus(args[1:])
+	case "disconnect":
+		handleRemoteDisconnect(args[1:])
+	case "help", "--help", "-h":
+		printRemoteHelp()
+	default:
+		fmt.Printf("Unknown remote command: %s\n", args[0])
+		fmt.Println()
+		printRemoteHelp()
+		os.Exit(1)
+	}
+}
+
+// printRemoteHelp prints usage for remote commands
+func printRemoteHelp() {
+	fmt.Println("Usage: agent-deck remote <command> [options]")
+	fmt.Println()
+	fmt.Println("Manage sessions on other machines over ssh. Hosts are defined in config.toml")
+	fmt.Println("as [remotes.<name>] and need agent-deck and tmux installed. Commands to a")
+	fmt.Println("host share one multiplexed ssh connection.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  hosts                          List configured hosts (default)")
+	fmt.Println("  list [host]                    List sessions with status on a host, or all hosts")
+	fmt.Println("  attach <host> <session>        Attach to a session's tmux pane (ssh -t)")
+	fmt.Println("  launch <host> [options] <path> Create and start a session (agent-deck launch)")
+	fmt.Println("  status <host> [--watch]        Status counts; --watch prints changes as they happen")
+	fmt.Println("  run <host> <args...>           Run any agent-deck command on the host")
+	fmt.Println("  disconnect <host>              Close the host's shared ssh connection")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck remote list box")
+	fmt.Println("  agent-deck remote launch box -c claude -t api /srv/api")
+	fmt.Println("  agent-deck remote attach box api")
+	fmt.Println("  agent-deck remote status box --watch --interval 5s")
+	fmt.Println("  agent-deck remote run box session send api \"run the tests\"")
+}
+
+// remoteHost resolves a configured host or exits with an error
+func remoteHost(out *CLIOutput, name string) *remote.Host {
+	host, err := session.GetRemote(name)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	return host
+}
+
+func handleRemoteHosts(args []string) {
+	fs := flag.NewFlagSet("remote hosts", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	type hostJSON struct {
+		Name    string `json:"name"`
+		Host    string `json:"host"`
+		Deck    string `json:"deck,omitempty"`
+		Profile string `json:"profile,omitempty"`
+	}
+	hosts := []hostJSON{}
+	for _, name := range session.RemoteNames() {
+		host := remoteHost(out, name)
+		hosts = append(hosts, hostJSON{Name: name, Host: host.Dest, Deck: host.Deck, Profile: host.Profile})
+	}
+	if *jsonOutput {
+		out.Print("", hosts)
+		return
+	}
+	if len(hosts) == 0 {
+		fmt.Println("No remote hosts configured. Add one to config.toml:")
+		fmt.Println()
+		fmt.Println("  [remotes.box]")
+		fmt.Println("  host = \"me@build-box\"")
+		return
+	}
+	for _, h := range hosts {
+		where := h.Host
+		if h.Deck != "" {
+			where += " (deck " + h.Deck + ")"
+		}
+		fmt.Printf("%-14s %s\n", h.Name, where)
+	}
+}
+
+func handleRemoteList(args []string) {
+	fs := flag.NewFlagSet("remote list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = session.RemoteNames()
+	}
+	type hostSessions struct {
+		Host     string           `json:"host"`
+		Sessions []remote.Session `json:"sessions"`
+		Error    string           `json:"error,omitempty"`
+	}
+	results := make([]hostSessions, 0, len(names))
+	failed := false
+	for _, name := range names {
+		host := remoteHost(out, name)
+		ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+		sessions, err := host.ListSessions(ctx)
+		cancel()
+		result := hostSessions{Host: name, Sessions: sessions}
+		if result.Sessions == nil {
+			result.Sessions = []remote.Session{}
+		}
+		if err != nil {
+			result.Error = err.Error()
+			failed = true
+		}
+		results = append(results, result)
+	}
+
+	if *jsonOutput {
+		out.Print("", results)
+	} else {
+		for _, r := range results {
+			fmt.Printf("\n═══ Remote: %s ═══\n\n", r.Host)
+			if r.Error != "" {
+				fmt.Printf("Error: %s\n", r.Error)
+				continue
+			}
+			if len(r.Sessions) == 0 {
+				fmt.Println("No sessions.")
+				continue
+			}
+			fmt.Printf("%-*s %-10s %-8s %-*s %s\n", tableColTitle, "TITLE", "STATUS", "TOOL", tableColPath, "PATH", "ID")
+			fmt.Println(strings.Repeat("-", tableColTitle+tableColPath+tableColIDDisplay+22))
+			for _, s := range r.Sessions {
+				fmt.Printf("%-*s %-10s %-8s %-*s %s\n", tableColTitle, truncate(s.Title, tableColTitle),
+					StatusLabel(session.Status(s.Status), s.Status), truncate(s.Tool, 8),
+					tableColPath, truncate(s.Path, tableColPath), TruncateID(s.ID))
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func handleRemoteAttach(args []string) {
+	out := NewCLIOutput(false, false)
+	if len(args) != 2 {
+		out.Error("usage: agent-deck remote attach <host> <session>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	host := remoteHost(out, args[0])
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	tmuxName, err := host.TmuxSessionName(ctx, args[1])
+	cancel()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	cmd := host.AttachCommand(tmuxName)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Exit(exitCode(err))
+	}
+}
+
+// handleRemotePassthrough runs an agent-deck command on a host with the
+// terminal connected, exiting with its status. subcommand, if set, is
+// prepended to the arguments after the host.
+func handleRemotePassthrough(subcommand string, args []string) {
+	out := NewCLIOutput(false, false)
+	if len(args) < 1 || (subcommand == "" && len(args) < 2) {
+		usage := "usage: agent-deck remote run <host> <args...>"
+		if subcommand != "" {
+			usage = fmt.Sprintf("usage: agent-deck remote %s <host> [options]", subcommand)
+		}
+		out.Error(usage, ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	host := remoteHost(out, args[0])
+	remoteArgs := args[1:]
+	if subcommand != "" {
+		remoteArgs = append([]string{subcommand}, remoteArgs...)
+	}
+	cmd := host.AgentDeckCommand(context.Background(), remoteArgs...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Exit(exitCode(err))
+	}
+}
+
+func handleRemoteStatus(args []string) {
+	fs := flag.NewFlagSet("remote status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON (one object per poll with --watch)")
+	watch := fs.Bool("watch", false, "Keep polling and print status changes")
+	interval := fs.Duration("interval", 5*time.Second, "Polling interval for --watch")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck remote status <host> [--watch] [--interval 5s]", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	host := remoteHost(out, fs.Arg(0))
+
+	if !*watch {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+		sessions, err := host.ListSessions(ctx)
+		cancel()
+		if err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		counts := remoteStatusCounts(sessions)
+		if *jsonOutput {
+			out.Print("", map[string]any{"host": host.Name, "total": len(sessions), "counts": counts})
+			return
+		}
+		fmt.Printf("%s: %s\n", host.Name, formatRemoteCounts(counts, len(sessions)))
+		return
+	}
+
+	if *interval < time.Second {
+		*interval = time.Second
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var prev []remote.Session
+	first := true
+	host.Watch(ctx, *interval, func(sessions []remote.Session, err error) {
+		now := time.Now().Format("15:04:05")
+		if err != nil {
+			if *jsonOutput {
+				out.Print("", map[string]any{"host": host.Name, "time": now, "error": err.Error()})
+			} else {
+				fmt.Fprintf(os.Stderr, "%s %s: %v\n", now, host.Name, err)
+			}
+			return
+		}
+		changes := remote.Diff(prev, sessions)
+		prev = sessions
+		if *jsonOutput {
+			out.Print("", map[string]any{"host": host.Name, "time": now, "sessions": sessions, "changes": changes})
+			return
+		}
+		if first {
+			first = false
+			fmt.Printf("%s %s: %s\n", now, host.Name, formatRemoteCounts(remoteStatusCounts(sessions), len(sessions)))
+			return
+		}
+		for _, c := range changes {
+			switch {
+			case c.From == "":
+				fmt.Printf("%s %s: %s new (%s)\n", now, host.Name, c.Session.Title, c.To)
+			case c.To == "":
+				fmt.Printf("%s %s: %s removed\n", now, host.Name, c.Session.Title)
+			default:
+				fmt.Printf("%s %s: %s %s -> %s\n", now, host.Name, c.Session.Title, c.From, c.To)
+			}
+		}
+	})
+}
+
+func handleRemoteDisconnect(args []string) {
+	out := NewCLIOutput(false, false)
+	if len(args) != 1 {
+		out.Error("usage: agent-deck remote disconnect <host>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	host := remoteHost(out, args[0])
+	if err := host.Disconnect(); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Disconnected from %s", host.Name), nil)
+}
+
+// remoteStatusCounts counts sessions per status
+func remoteStatusCounts(sessions []remote.Session) map[string]int {
+	counts := map[string]int{}
+	for _, s := range sessions {
+		counts[s.Status]++
+	}
+	return counts
+}
+
+// formatRemoteCounts renders counts like "3 sessions: 1 running, 2 waiting"
+func formatRemoteCounts(counts map[string]int, total int) string {
+	var parts []string
+	for _, status := range []string{"running", "waiting", "idle", "error", "throttled"} {
+		if n := counts[status]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	summary := fmt.Sprintf("%d sessions", total)
+	if len(parts) > 0 {
+		summary += ": " + strings.Join(parts, ", ")
+	}
+	return summary
+}
+
+// exitCode returns the exit status of a failed command, 1 if it has none
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleReview starts a review session on a branch, pull request or another
+// session's work and preloads it with the diff and the review rubric
+func handleReview(profile string, args []string) {
+	fs := flag.NewFlagSet("review", flag.ExitOnError)
+	pr := fs.Int("pr", 0, "Review GitHub pull request number (fetched from origin)")
+	of := fs.String("session", "", "Review the work of an existing session (its worktree or project path)")
+	base := fs.String("base", "", "Branch to diff against (default: [review] base_branch, then the repo's default branch)")
+	title := fs.String("title", "", "Session title (default: review <branch>)")
+	titleShort := fs.String("t", "", "Session title (short)")
+	group := fs.String("group", "", "Group path")
+	groupShort := fs.String("g", "", "Group path (short)")
+	command := fs.String("cmd", "", "Tool to review with (default: [review] tool, then claude)")
+	commandShort := fs.String("c", "", "Tool to review with (short)")
+	noSend := fs.Bool("no-send", false, "Start the session without sending the diff and rubric")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck review [branch] [repo-path] [options]")
+		fmt.Println()
+		fmt.Println("Start a review session on a branch. The branch is opened in a worktree, its")
+		fmt.Println("diff against the base branch is sent in chunks, then the review rubric. The")
+		fmt.Println("review starts with a tagged verdict line ('[review] <branch> verdict: ...')")
+		fmt.Println("that 'session output --json' reports as \"review\".")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck review feature/login")
+		fmt.Println("  agent-deck review --pr 123 ~/src/app")
+		fmt.Println("  agent-deck review --session worker-3 -c codex")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+	settings := session.GetReviewSettings()
+	sessionGroup := mergeFlags(*group, *groupShort)
+
+	storage, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	// Work out what is reviewed, and where
+	var workDir, ref, worktreePath, worktreeRepoRoot string
+	if *of != "" {
+		target, errMsg, errCode := ResolveSession(*of, instances)
+		if target == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(1)
+			return // unreachable, satisfies staticcheck SA5011
+		}
+		workDir = target.ProjectPath
+		if target.WorktreePath != "" {
+			workDir = target.WorktreePath
+		}
+		ref = target.WorktreeBranch
+		if ref == "" {
+			if ref, err = git.GetCurrentBranch(workDir); err != nil {
+				out.Error(fmt.Sprintf("session '%s' is not in a git repository", target.Title), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+		if sessionGroup == "" {
+			sessionGroup = target.GroupPath
+		}
+	} else {
+		repoDir := fs.Arg(1)
+		if repoDir == "" {
+			repoDir = "."
+		}
+		repoRoot, err := git.GetWorktreeBaseRoot(repoDir)
+		if err != nil {
+			out.Error(fmt.Sprintf("%s is not a git repository", repoDir), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+
+		ref = fs.Arg(0)
+		if *pr > 0 {
+			ref = fmt.Sprintf("pr-%d", *pr)
+			if err := git.FetchPullRequest(repoRoot, *pr, ref); err != nil {
+				out.Error(err.Error(), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+		if ref == "" {
+			out.Error("branch, --pr or --session is required", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if !git.BranchExists(repoRoot, ref) {
+			out.Error(fmt.Sprintf("branch '%s' does not exist", ref), ErrCodeNotFound)
+			os.Exit(1)
+		}
+
+		// Reuse the branch's worktree (e.g. the implementer's), else make one
+		if existing, err := git.GetWorktreeForBranch(repoRoot, ref); err == nil && existing != "" {
+			workDir = existing
+		} else {
+			wtSettings := session.GetWorktreeSettings()
+			worktreePath = git.WorktreePath(git.WorktreePathOptions{
+				Branch:    ref,
+				Location:  wtSettings.DefaultLocation,
+				RepoDir:   repoRoot,
+				SessionID: git.GeneratePathID(),
+				Template:  wtSettings.Template(),
+			})
+			if err := os.MkdirAll(filepath.Dir(worktreePath), 0o755); err != nil {
+				out.Error(fmt.Sprintf("failed to create parent directory: %v", err), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+			if err := git.CreateWorktree(repoRoot, worktreePath, ref); err != nil {
+				out.Error(fmt.Sprintf("failed to create worktree: %v", err), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+			workDir = worktreePath
+			worktreeRepoRoot = repoRoot
+		}
+	}
+
+	baseBranch := *base
+	if baseBranch == "" {
+		baseBranch = settings.BaseBranch
+	}
+	if baseBranch == "" {
+		if baseBranch, err = git.GetDefaultBranch(workDir); err != nil {
+			out.Error(fmt.Sprintf("%v; pass --base", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+	diff, err := git.DiffAgainstBase(workDir, baseBranch)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if strings.TrimSpace(diff) == "" {
+		out.Error(fmt.Sprintf("%s has no changes against %s", ref, baseBranch), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	rubric, err := settings.LoadRubric()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	tool := mergeFlags(*command, *commandShort)
+	if tool == "" {
+		tool = settings.GetTool()
+	}
+	spawnConductor, err := checkSpawnPolicy(storage, instances, detectTool(tool), "")
+	if err != nil {
+		reportSpawnError(out, err)
+		os.Exit(1)
+	}
+
+	sessionTitle := mergeFlags(*title, *titleShort)
+	if sessionTitle == "" {
+		sessionTitle = generateUniqueTitle(instances, "review "+ref, workDir)
+	}
+	var inst *session.Instance
+	if sessionGroup != "" {
+		inst = session.NewInstanceWithGroup(sessionTitle, workDir, sessionGroup)
+	} else {
+		inst = session.NewInstance(sessionTitle, workDir)
+	}
+	inst.Tool = detectTool(tool)
+	if toolDef := session.GetToolDef(inst.Tool); toolDef != nil {
+		inst.Command = toolDef.Command
+	} else {
+		inst.Command = tool
+	}
+	inst.SetStartReason(session.StartReasonReview, ref)
+	if worktreePath != "" {
+		inst.WorktreePath = worktreePath
+		inst.WorktreeRepoRoot = worktreeRepoRoot
+		inst.WorktreeBranch = ref
+	}
+
+	instances = append(instances, inst)
+	groupTree := session.NewGroupTreeWithGroups(instances, groups)
+	if inst.GroupPath != "" {
+		groupTree.CreateGroup(inst.GroupPath)
+	}
+	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+		out.Error(fmt.Sprintf("failed to save session: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	recordSpawn(storage, spawnConductor, inst)
+
+	if err := inst.Start(); err != nil {
+		out.Error(fmt.Sprintf("failed to start session: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	inst.PostStartSync(3 * time.Second)
+	if err := saveSessionData(storage, instances); err != nil {
+		out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	messages := session.ReviewMessages(ref, diff, rubric, settings.GetChunkSize())
+	if !*noSend {
+		progress := func(n int) {
+			if !*jsonOutput && !quietMode {
+				fmt.Printf("Sent part %d of %d\n", n, len(messages))
+			}
+		}
+		if err := sendReviewMessages(inst, messages, progress); err != nil {
+			out.Error(fmt.Sprintf("failed to send review: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	out.Success(
+		fmt.Sprintf("Review session started: %s (%s) on %s against %s", inst.Title, TruncateID(inst.ID), ref, baseBranch),
+		map[string]interface{}{
+			"success":  true,
+			"id":       inst.ID,
+			"title":    inst.Title,
+			"path":     workDir,
+			"tool":     inst.Tool,
+			"ref":      ref,
+			"base":     baseBranch,
+			"messages": len(messages),
+			"sent":     !*noSend,
+			"profile":  storage.Profile(),
+		},
+	)
+}
+
+// sendReviewMessages sends the diff chunks and rubric one at a time, waiting
+// for the agent to finish with each before sending the next
+func sendReviewMessages(inst *session.Instance, messages []string, progress func(n int)) error {
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil {
+		return fmt.Errorf("session has no tmux session")
+	}
+	if err := waitForAgentReady(tmuxSess, inst.Tool); err != nil {
+		return err
+	}
+	for n, message := range messages {
+		if err := sendWithRetry(tmuxSess, message, false); err != nil {
+			return err
+		}
+		progress(n + 1)
+		if n == len(messages)-1 {
+			break
+		}
+		if _, err := waitForCompletion(tmuxSess, 5*time.Minute); err != nil {
+			return err
+		}
+	}
+	return nil
+}
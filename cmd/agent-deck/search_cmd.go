@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSearch searches the history of all sessions
+func handleSearch(profile string, args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "Maximum number of matches")
+	sessionFilter := fs.String("session", "", "Search only this session (id or title)")
+	since := fs.Duration("since", 0, "Only matches written within this long (e.g. 24h); 0 = all")
+	source := fs.String("source", "", "Search only transcript or claude history")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck search <query> [options]")
+		fmt.Println()
+		fmt.Println("Search the history of every session: transcripts captured by [transcripts]")
+		fmt.Println("in config.toml, and Claude's conversation logs. Matching is case-insensitive")
+		fmt.Println("and newest matches come first. Transcripts of removed sessions are searched")
+		fmt.Println("too, shown by session ID.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck search \"connection refused\"")
+		fmt.Println("  agent-deck search migration --session api-server --since 48h")
+		fmt.Println("  agent-deck search TODO --source claude --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if query == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *source != "" && *source != session.HistorySourceTranscript && *source != session.HistorySourceClaude {
+		out.Error(fmt.Sprintf("unknown source %q (valid: transcript, claude)", *source), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	files := session.HistoryFiles(instances)
+	if *sessionFilter != "" {
+		inst, errMsg, errCode := ResolveSession(*sessionFilter, instances)
+		if inst == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(1)
+		}
+		files = filterHistoryFiles(files, func(f session.HistoryFile) bool { return f.SessionID == inst.ID })
+	}
+	if *source != "" {
+		files = filterHistoryFiles(files, func(f session.HistoryFile) bool { return f.Source == *source })
+	}
+
+	q := session.HistoryQuery{Text: query, Limit: *limit}
+	if *since > 0 {
+		q.Since = time.Now().Add(-*since)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	matches := session.SearchHistory(ctx, files, q)
+
+	if *jsonOutput {
+		if matches == nil {
+			matches = []session.HistoryMatch{}
+		}
+		out.Print("", map[string]any{"query": query, "matches": matches})
+		return
+	}
+	if len(matches) == 0 {
+		hint := ""
+		if !session.GetTranscriptSettings().Enabled {
+			hint = " (enable [transcripts] in config.toml to search all tools' output)"
+		}
+		fmt.Printf("No matches for %q%s\n", query, hint)
+		os.Exit(2)
+	}
+	for _, m := range matches {
+		fmt.Printf("%s  %s  [%s]\n", historyMatchName(m), m.Time.Local().Format("2006-01-02 15:04"), m.Source)
+		fmt.Printf("    %s\n", m.Snippet)
+	}
+}
+
+func filterHistoryFiles(files []session.HistoryFile, keep func(session.HistoryFile) bool) []session.HistoryFile {
+	var kept []session.HistoryFile
+	for _, f := range files {
+		if keep(f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// historyMatchName names a match's session: its title, or its ID once the
+// session has been removed
+func historyMatchName(m session.HistoryMatch) string {
+	if m.Title != "" {
+		return m.Title
+	}
+	return m.SessionID
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSessionAdopt adopts untracked tmux sessions, resolving title
+// collisions by prompt or by --on-conflict
+func handleSessionAdopt(profile string, args []string) {
+	fs := flag.NewFlagSet("session adopt", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	onConflict := fs.String("on-conflict", "", "Policy for titles already in use: rename, merge or skip (default: ask on a terminal, otherwise skip)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be adopted without saving")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session adopt [options]")
+		fmt.Println()
+		fmt.Println("Track tmux sessions that agent-deck does not know about yet, such as")
+		fmt.Println("sessions started by hand or orphaned agent-deck sessions. When a session's")
+		fmt.Println("title is already in use, it can be:")
+		fmt.Println("  rename   adopted as \"title (2)\"")
+		fmt.Println("  merge    attached to the existing session, if that one has no live tmux")
+		fmt.Println("           session (otherwise it is renamed)")
+		fmt.Println("  skip     left untracked")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session adopt                      # Ask for each collision")
+		fmt.Println("  agent-deck session adopt --on-conflict merge  # Reattach orphans, no prompts")
+		fmt.Println("  agent-deck session adopt --dry-run --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	if *onConflict != "" && !session.IsValidConflictPolicy(*onConflict) {
+		out.Error(fmt.Sprintf("invalid --on-conflict %q: use rename, merge or skip", *onConflict), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, groupsData, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	candidates, err := session.DiscoverTmuxCandidates(instances)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to list tmux sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var resolve session.ConflictResolver
+	switch {
+	case *onConflict != "":
+		resolve = conflictPolicyResolver(*onConflict)
+	case !*jsonOutput && !*quiet && !*quietShort && term.IsTerminal(int(os.Stdin.Fd())):
+		resolve = promptConflictResolver(bufio.NewReader(os.Stdin))
+	default:
+		resolve = conflictPolicyResolver(session.ConflictSkip)
+	}
+	outcomes := session.ResolveAdoptConflicts(instances, candidates, resolve)
+
+	changed := false
+	for _, o := range outcomes {
+		if o.Action != session.AdoptSkipped {
+			changed = true
+		}
+	}
+	if changed && !*dryRun {
+		instances = append(instances, session.AdoptedInstances(outcomes)...)
+		groupTree := session.NewGroupTreeWithGroups(instances, groupsData)
+		for _, inst := range session.AdoptedInstances(outcomes) {
+			if inst.GroupPath != "" {
+				groupTree.CreateGroup(inst.GroupPath)
+			}
+		}
+		if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+			out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	type adoptJSON struct {
+		Title  string `json:"title"`
+		Action string `json:"action"`
+		ID     string `json:"id,omitempty"`
+		AsName string `json:"as,omitempty"`
+		Note   string `json:"note,omitempty"`
+	}
+	rows := make([]adoptJSON, 0, len(outcomes))
+	var lines []string
+	counts := map[string]int{}
+	for _, o := range outcomes {
+		row := adoptJSON{Title: o.Title, Action: o.Action, Note: o.Note}
+		line := fmt.Sprintf("  %-8s %s", o.Action, o.Title)
+		switch o.Action {
+		case session.AdoptAdded:
+			row.ID = o.Instance.ID
+		case session.AdoptRenamed:
+			row.ID, row.AsName = o.Instance.ID, o.Instance.Title
+			line += fmt.Sprintf(" -> %s", o.Instance.Title)
+		case session.AdoptMerged:
+			row.ID, row.AsName = o.Instance.ID, o.Instance.Title
+			line += fmt.Sprintf(" -> existing %s (%s)", o.Instance.Title, TruncateID(o.Instance.ID))
+		}
+		if o.Note != "" {
+			line += " (" + o.Note + ")"
+		}
+		counts[o.Action]++
+		rows = append(rows, row)
+		lines = append(lines, line)
+	}
+
+	verb := "Adopted"
+	if *dryRun {
+		verb = "Would adopt"
+	}
+	summary := fmt.Sprintf("%s %d session(s): %d added, %d renamed, %d merged, %d skipped",
+		verb, len(outcomes)-counts[session.AdoptSkipped],
+		counts[session.AdoptAdded], counts[session.AdoptRenamed], counts[session.AdoptMerged], counts[session.AdoptSkipped])
+	if len(outcomes) == 0 {
+		summary = "No untracked tmux sessions found"
+	}
+	if len(lines) > 0 {
+		summary += "\n" + strings.Join(lines, "\n")
+	}
+	out.Success(summary, map[string]interface{}{
+		"success":  true,
+		"dry_run":  *dryRun,
+		"sessions": rows,
+	})
+}
+
+// conflictPolicyResolver applies one policy to every collision
+func conflictPolicyResolver(policy string) session.ConflictResolver {
+	return func(session.AdoptConflict) string { return policy }
+}
+
+// promptConflictResolver asks how to resolve each collision; an upper-case
+// answer applies to all the remaining ones
+func promptConflictResolver(reader *bufio.Reader) session.ConflictResolver {
+	remembered := ""
+	return func(c session.AdoptConflict) string {
+		if remembered != "" {
+			return remembered
+		}
+		fmt.Printf("\n'%s' (tmux %s, %s) collides with existing session %s",
+			c.Candidate.Title, c.Candidate.GetTmuxSession().Name, c.Candidate.ProjectPath, TruncateID(c.Existing.ID))
+		if !c.Mergeable {
+			fmt.Print(", which is still running")
+		}
+		fmt.Println(".")
+		for {
+			fmt.Print("[r]ename, [m]erge, [s]kip (R/M/S for all remaining): ")
+			answer, err := reader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			policy := map[string]string{"r": session.ConflictRename, "m": session.ConflictMerge, "s": session.ConflictSkip}[strings.ToLower(answer)]
+			if err != nil && policy == "" {
+				return session.ConflictSkip
+			}
+			if policy == "" {
+				continue
+			}
+			if answer != strings.ToLower(answer) {
+				remembered = policy
+			}
+			return policy
+		}
+	}
+}
@@ -2,18 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/chaos"
 	"github.com/asheshgoplani/agent-deck/internal/clipboard"
 	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/profile"
 	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
 	"github.com/asheshgoplani/agent-deck/internal/tmux"
 )
 
@@ -31,6 +38,8 @@ func handleSession(profile string, args []string) {
 		handleSessionStop(profile, args[1:])
 	case "restart":
 		handleSessionRestart(profile, args[1:])
+	case "restore":
+		handleSessionRestore(profile, args[1:])
 	case "fork":
 		handleSessionFork(profile, args[1:])
 	case "attach":
@@ -43,12 +52,28 @@ func handleSession(profile string, args []string) {
 		handleSessionSetParent(profile, args[1:])
 	case "unset-parent":
 		handleSessionUnsetParent(profile, args[1:])
+	case "pin":
+		handleSessionPin(profile, args[1:], true)
+	case "unpin":
+		handleSessionPin(profile, args[1:], false)
+	case "notify":
+		handleSessionNotify(profile, args[1:])
+	case "adopt":
+		handleSessionAdopt(profile, args[1:])
 	case "set":
 		handleSessionSet(profile, args[1:])
 	case "send":
 		handleSessionSend(profile, args[1:])
 	case "output":
 		handleSessionOutput(profile, args[1:])
+	case "macro":
+		handleSessionMacro(profile, args[1:])
+	case "receipt":
+		handleSessionReceipt(profile, args[1:])
+	case "interrupt":
+		handleSessionInterrupt(profile, args[1:])
+	case "audit":
+		handleSessionAudit(profile, args[1:])
 	case "help", "--help", "-h":
 		printSessionHelp()
 	default:
@@ -68,15 +93,24 @@ func printSessionHelp() {
 	fmt.Println("  start <id>              Start a session's tmux process")
 	fmt.Println("  stop <id>               Stop/kill session process")
 	fmt.Println("  restart <id>            Restart session (Claude: reload MCPs)")
-	fmt.Println("  fork <id>               Fork Claude session with context")
+	fmt.Println("  restore                 Restart sessions whose tmux session is gone (after reboot)")
+	fmt.Println("  fork <id>               Fork session with conversation context")
 	fmt.Println("  attach <id>             Attach to session interactively")
 	fmt.Println("  show [id]               Show session details (auto-detect current if no id)")
 	fmt.Println("  current                 Show current session and profile (auto-detect)")
 	fmt.Println("  set <id> <field> <value>  Update session property")
 	fmt.Println("  send <id> <message>     Send a message to a running session")
+	fmt.Println("  receipt <receipt-id>    Show the delivery state of a sent message")
+	fmt.Println("  interrupt <id>          Stop what the agent is doing (Esc/Ctrl-C per tool)")
 	fmt.Println("  output <id>             Get the last response from a session")
+	fmt.Println("  macro <id> [name]       Run a tool macro (compact, model, ...); lists them without a name")
+	fmt.Println("  audit [id]              Show session creations and removals with their reasons")
 	fmt.Println("  set-parent <id> <parent>  Link session as sub-session of parent")
 	fmt.Println("  unset-parent <id>       Remove sub-session link")
+	fmt.Println("  pin <id>                List session first in its group")
+	fmt.Println("  unpin <id>              Remove a session's pin")
+	fmt.Println("  notify <id> <on|off|default>  Desktop notifications when the session finishes")
+	fmt.Println("  adopt                   Track untracked tmux sessions, resolving title collisions")
 	fmt.Println()
 	fmt.Println("Global Options:")
 	fmt.Println("  -p, --profile <name>   Use specific profile")
@@ -349,7 +383,99 @@ func handleSessionRestart(profile string, args []string) {
 	})
 }
 
-// handleSessionFork forks a Claude session
+// handleSessionRestore restarts sessions that were live when tmux went away,
+// typically after a reboot. Stopped and errored sessions are left alone. The
+// restore-on-boot unit installed by "agent-deck install" runs this at login.
+func handleSessionRestore(profile string, args []string) {
+	fs := flag.NewFlagSet("session restore", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	allProfiles := fs.Bool("all-profiles", false, "Restore sessions in every profile")
+	dryRun := fs.Bool("dry-run", false, "List sessions that would be restored")
+	force := fs.Bool("force", false, "Restore even during a maintenance window")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session restore [options]")
+		fmt.Println()
+		fmt.Println("Restart sessions whose tmux session no longer exists, e.g. after a reboot.")
+		fmt.Println("Sessions that were stopped or in error are not restored.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	if active := session.ActiveMaintenanceWindow(time.Now()); active != nil && !*force {
+		out.Success(fmt.Sprintf("Skipped restore: %s", active.Describe()), map[string]interface{}{
+			"success":     true,
+			"restored":    []string{},
+			"maintenance": active,
+		})
+		return
+	}
+
+	profiles := []string{session.GetEffectiveProfile(profile)}
+	if *allProfiles {
+		all, err := session.ListProfiles()
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to list profiles: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		profiles = all
+	}
+
+	restored := []string{}
+	var failures []string
+	for _, p := range profiles {
+		storage, instances, _, err := loadSessionData(p)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", p, err))
+			continue
+		}
+		changed := false
+		for _, inst := range instances {
+			if inst.Status == session.StatusError || inst.Exists() {
+				continue
+			}
+			if *dryRun {
+				restored = append(restored, inst.Title)
+				continue
+			}
+			if err := inst.Restart(); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", inst.Title, err))
+				continue
+			}
+			restored = append(restored, inst.Title)
+			changed = true
+		}
+		if changed {
+			if err := saveSessionData(storage, instances); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: failed to save session state: %v", p, err))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		out.Error(fmt.Sprintf("restored %d session(s), %d failed: %s", len(restored), len(failures), strings.Join(failures, "; ")), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	verb := "Restored"
+	if *dryRun {
+		verb = "Would restore"
+	}
+	out.Success(fmt.Sprintf("%s %d session(s)", verb, len(restored)), map[string]interface{}{
+		"success":  true,
+		"restored": restored,
+		"dry_run":  *dryRun,
+	})
+}
+
+// handleSessionFork forks a Claude, Codex, Gemini or aider session
 func handleSessionFork(profile string, args []string) {
 	fs := flag.NewFlagSet("session fork", flag.ExitOnError)
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
@@ -367,7 +493,7 @@ func handleSessionFork(profile string, args []string) {
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session fork <id|title> [options]")
 		fmt.Println()
-		fmt.Println("Fork a Claude session with conversation context.")
+		fmt.Println("Fork a Claude, Codex, Gemini or aider session with conversation context.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -410,24 +536,24 @@ func handleSessionFork(profile string, args []string) {
 		return // unreachable, satisfies staticcheck SA5011
 	}
 
-	// Verify it's a Claude session
-	if inst.Tool != "claude" {
+	// Verify its tool can fork (OpenCode forks from the TUI)
+	if inst.Tool == "opencode" || !session.ToolSupportsFork(inst.Tool) {
 		out.Error(
-			fmt.Sprintf("session '%s' is not a Claude session (tool: %s)", inst.Title, inst.Tool),
+			fmt.Sprintf("session '%s' cannot be forked (tool: %s)", inst.Title, inst.Tool),
 			ErrCodeInvalidOperation,
 		)
 		os.Exit(1)
 	}
 
 	// Try to capture session ID from tmux if missing (handles pre-fix sessions)
-	if inst.ClaudeSessionID == "" && inst.Exists() {
+	if inst.Tool == "claude" && inst.ClaudeSessionID == "" && inst.Exists() {
 		inst.PostStartSync(2 * time.Second)
 	}
 
 	// Verify it can be forked
 	if !inst.CanFork() {
 		out.Error(
-			fmt.Sprintf("session '%s' cannot be forked: no active Claude session ID", inst.Title),
+			fmt.Sprintf("session '%s' cannot be forked: no active %s conversation", inst.Title, inst.Tool),
 			ErrCodeInvalidOperation,
 		)
 		os.Exit(1)
@@ -450,6 +576,12 @@ func handleSessionFork(profile string, args []string) {
 	}
 	createNewBranch := *newBranch || *newBranchLong
 
+	spawnConductor, err := checkSpawnPolicy(storage, instances, inst.Tool, "")
+	if err != nil {
+		reportSpawnError(out, err)
+		os.Exit(1)
+	}
+
 	// Handle worktree creation
 	var opts *session.ClaudeOptions
 	if wtBranch != "" {
@@ -530,6 +662,11 @@ func handleSessionFork(profile string, args []string) {
 		out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	recordSpawn(storage, spawnConductor, forkedInst)
+
+	ev := session.NewHookEvent(session.HookEventFork, profile, forkedInst, time.Now())
+	ev.ParentID = inst.ID
+	session.RunEventHooks(ev)
 
 	// Output success
 	out.Success(
@@ -606,6 +743,7 @@ func handleSessionShow(profile string, args []string) {
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 	quiet := fs.Bool("quiet", false, "Minimal output")
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	absolute := fs.Bool("absolute", false, "Show absolute timestamps instead of relative ones")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session show [id|title] [options]")
@@ -625,7 +763,7 @@ func handleSessionShow(profile string, args []string) {
 	out := NewCLIOutput(*jsonOutput, quietMode)
 
 	// Load sessions
-	_, instances, _, err := loadSessionData(profile)
+	storage, instances, _, err := loadSessionData(profile)
 	if err != nil {
 		out.Error(err.Error(), ErrCodeNotFound)
 		os.Exit(1)
@@ -665,6 +803,19 @@ func handleSessionShow(profile string, args []string) {
 	// Update status
 	_ = inst.UpdateStatus()
 
+	// The bridge polls conductors through session show, so record what it sees
+	// to advance their task ledger even when no TUI is running.
+	if _, ok := session.ConductorNameFromTitle(inst.Title); ok && storage.Profile() == session.GetEffectiveProfile(profile) {
+		if db := storage.GetDB(); db != nil {
+			if ev, _ := session.RecordStatusTransition(db, profile, inst, time.Now()); ev != nil {
+				session.RunEventHooks(*ev)
+			}
+		}
+	}
+
+	// Score the session's recent tool calls
+	risk := inst.AssessRisk()
+
 	// Get MCP info if Claude session
 	var mcpInfo *session.MCPInfo
 	if inst.Tool == "claude" {
@@ -686,6 +837,14 @@ func handleSessionShow(profile string, args []string) {
 	if inst.Command != "" {
 		jsonData["command"] = inst.Command
 	}
+	if inst.StartReason != "" {
+		jsonData["start_reason"] = inst.StartReason
+	}
+	if inst.NetworkPolicy != "" {
+		mode, _ := inst.NetworkMode()
+		jsonData["network_policy"] = inst.NetworkPolicy
+		jsonData["network_enforcement"] = session.NetworkEnforcement(mode)
+	}
 
 	if inst.Tool == "claude" {
 		jsonData["claude_session_id"] = inst.ClaudeSessionID
@@ -697,12 +856,34 @@ func handleSessionShow(profile string, args []string) {
 		}
 	}
 
+	// aider commits every edit; those commits are the session's checkpoints
+	var aiderCheckpoints []git.Commit
+	if inst.Tool == "aider" {
+		aiderCheckpoints, _ = session.AiderCheckpoints(inst)
+		checkpoints := make([]map[string]string, 0, len(aiderCheckpoints))
+		for _, c := range aiderCheckpoints {
+			checkpoints = append(checkpoints, map[string]string{
+				"commit":  c.Hash,
+				"subject": c.Subject,
+				"time":    c.Time.Format(time.RFC3339),
+			})
+		}
+		jsonData["checkpoints"] = checkpoints
+	}
+
 	if inst.Exists() {
 		tmuxSession := inst.GetTmuxSession()
 		if tmuxSession != nil {
 			jsonData["tmux_session"] = tmuxSession.Name
 		}
 	}
+	if risk != nil {
+		jsonData["risk"] = risk
+	}
+	statusDetail, hasStatusDetail := inst.StatusDetail()
+	if hasStatusDetail {
+		jsonData["status_detail"] = statusDetail
+	}
 
 	// Build human-readable output
 	var sb strings.Builder
@@ -710,7 +891,13 @@ func handleSessionShow(profile string, args []string) {
 	sb.WriteString(fmt.Sprintf("Session: %s\n", inst.Title))
 	sb.WriteString(fmt.Sprintf("Profile: %s\n", profile))
 	sb.WriteString(fmt.Sprintf("ID:      %s\n", inst.ID))
-	sb.WriteString(fmt.Sprintf("Status:  %s %s\n", StatusSymbol(inst.Status), StatusString(inst.Status)))
+	sb.WriteString(fmt.Sprintf("Status:  %s\n", StatusLabel(inst.Status, StatusString(inst.Status))))
+	if hasStatusDetail {
+		sb.WriteString(fmt.Sprintf("Signal:  %s (confidence %.2f)\n", statusDetail.Reason, statusDetail.Confidence))
+		for _, line := range statusDetail.Evidence {
+			sb.WriteString(fmt.Sprintf("         > %s\n", line))
+		}
+	}
 	sb.WriteString(fmt.Sprintf("Path:    %s\n", FormatPath(inst.ProjectPath)))
 
 	if inst.GroupPath != "" {
@@ -753,10 +940,31 @@ func handleSessionShow(profile string, args []string) {
 		}
 	}
 
-	sb.WriteString(fmt.Sprintf("Created: %s\n", inst.CreatedAt.Format("2006-01-02 15:04:05")))
+	if n := len(aiderCheckpoints); n > 0 {
+		latest := aiderCheckpoints[0]
+		sb.WriteString(fmt.Sprintf("Aider:   %d commit(s) since start, latest %s %s (/undo rolls back)\n", n, latest.Hash[:7], latest.Subject))
+	}
+
+	timeFmt := session.NewTimeFormatter(*absolute)
+	now := time.Now()
+	sb.WriteString(fmt.Sprintf("Created: %s\n", timeFmt.Format(inst.CreatedAt, now)))
+	sb.WriteString(fmt.Sprintf("Started: %s\n", session.DescribeStartReason(inst.StartReason, func(id string) string {
+		for _, candidate := range instances {
+			if candidate.ID == id {
+				return candidate.Title
+			}
+		}
+		return ""
+	})))
+	if inst.NetworkPolicy != "" {
+		sb.WriteString(fmt.Sprintf("Network: %s\n", inst.DescribeNetworkPolicy()))
+	}
+	if desc := risk.Describe(); desc != "" {
+		sb.WriteString(fmt.Sprintf("Risk:    %s\n", desc))
+	}
 
 	if !inst.LastAccessedAt.IsZero() {
-		sb.WriteString(fmt.Sprintf("Accessed: %s\n", inst.LastAccessedAt.Format("2006-01-02 15:04:05")))
+		sb.WriteString(fmt.Sprintf("Accessed: %s\n", timeFmt.Format(inst.LastAccessedAt, now)))
 	}
 
 	if inst.Exists() {
@@ -893,7 +1101,7 @@ func handleSessionSet(profile string, args []string) {
 		inst.ClaudeDetectedAt = time.Now()
 		// Also update tmux environment if session is running
 		if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil && tmuxSess.Exists() {
-			_ = exec.Command("tmux", "set-environment", "-t", tmuxSess.Name, "CLAUDE_SESSION_ID", value).Run()
+			_ = tmux.Command("set-environment", "-t", tmuxSess.Name, "CLAUDE_SESSION_ID", value).Run()
 		}
 	case "gemini-session-id":
 		oldValue = inst.GeminiSessionID
@@ -901,7 +1109,7 @@ func handleSessionSet(profile string, args []string) {
 		inst.GeminiDetectedAt = time.Now()
 		// Also update tmux environment if session is running
 		if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil && tmuxSess.Exists() {
-			_ = exec.Command("tmux", "set-environment", "-t", tmuxSess.Name, "GEMINI_SESSION_ID", value).Run()
+			_ = tmux.Command("set-environment", "-t", tmuxSess.Name, "GEMINI_SESSION_ID", value).Run()
 		}
 	}
 
@@ -1271,6 +1479,322 @@ func handleSessionUnsetParent(profile string, args []string) {
 
 // handleSessionSend sends a message to a running session
 // Waits for the agent to be ready before sending (Claude, Gemini, etc.)
+// handleSessionMacro runs a named macro of the session's tool, or lists the
+// available macros when no name is given
+func handleSessionMacro(profile string, args []string) {
+	fs := flag.NewFlagSet("session macro", flag.ExitOnError)
+	fs.SetOutput(os.Stdout)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session macro <id|title> [name] [options]")
+		fmt.Println()
+		fmt.Println("Run a named in-tool action (slash command or key presses) in a running")
+		fmt.Println("session. Without a name, list the macros for the session's tool.")
+		fmt.Println("Add your own under [tools.<tool>.macros.<name>] in config.toml.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session macro my-project")
+		fmt.Println("  agent-deck session macro my-project compact")
+		fmt.Println("  agent-deck session macro my-project cycle-mode")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	remaining := fs.Args()
+
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	if len(remaining) < 1 {
+		fs.Usage()
+		out.Error("session is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(remaining[0], instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	if len(remaining) == 1 {
+		macros := session.ListMacros(inst.Tool)
+		if *jsonOutput {
+			out.Print("", map[string]interface{}{"tool": inst.Tool, "macros": macros})
+			return
+		}
+		if len(macros) == 0 {
+			fmt.Printf("No macros for tool %q (add them under [tools.%s.macros] in config.toml)\n", inst.Tool, inst.Tool)
+			return
+		}
+		fmt.Printf("Macros for %s:\n", inst.Tool)
+		for _, m := range macros {
+			action := m.Text
+			if len(m.Keys) > 0 {
+				action = strings.TrimSpace("keys: " + strings.Join(m.Keys, " ") + " " + m.Text)
+			}
+			fmt.Printf("  %-14s %-28s %s\n", m.Name, action, m.Description)
+		}
+		return
+	}
+
+	name := remaining[1]
+	if err := inst.RunMacro(name); err != nil {
+		code := ErrCodeInvalidOperation
+		if errors.Is(err, session.ErrMacroNotFound) {
+			code = ErrCodeNotFound
+		}
+		out.Error(err.Error(), code)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Ran macro '%s' in '%s'", name, inst.Title), map[string]interface{}{
+		"success":    true,
+		"session_id": inst.ID,
+		"tool":       inst.Tool,
+		"macro":      name,
+	})
+}
+
+// handleSessionInterrupt stops a runaway agent with its tool's interrupt
+// keys, optionally asking it where it got to
+func handleSessionInterrupt(profile string, args []string) {
+	fs := flag.NewFlagSet("session interrupt", flag.ExitOnError)
+	fs.SetOutput(os.Stdout)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	summarize := fs.Bool("summarize", false, "Then ask the agent to summarize where it was")
+	message := fs.String("message", "", "Then send this message instead of the summary request")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session interrupt <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Press the session tool's interrupt keys (its \"interrupt\" macro: Escape for")
+		fmt.Println("Claude, Codex and Gemini, Ctrl-C for aider and ollama; Ctrl-C otherwise).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session interrupt my-project")
+		fmt.Println("  agent-deck session interrupt my-project --summarize")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		out.Error("session is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	followUp := *message
+	if followUp == "" && *summarize {
+		followUp = session.InterruptSummaryPrompt
+	}
+	if err := inst.Interrupt(followUp); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Interrupted '%s'", inst.Title), map[string]interface{}{
+		"success":       true,
+		"session_id":    inst.ID,
+		"session_title": inst.Title,
+		"follow_up":     followUp,
+	})
+}
+
+// handleSessionReceipt shows the delivery state of a prompt sent with
+// session send, optionally waiting until it completes or fails
+func handleSessionReceipt(profile string, args []string) {
+	fs := flag.NewFlagSet("session receipt", flag.ExitOnError)
+	fs.SetOutput(os.Stdout)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	wait := fs.Bool("wait", false, "Block until the prompt is completed or failed")
+	timeout := fs.Duration("timeout", 10*time.Minute, "Max time to wait (used with --wait)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session receipt <receipt-id> [options]")
+		fmt.Println()
+		fmt.Println("Show where a prompt sent with 'session send' is: queued, delivered,")
+		fmt.Println("acknowledged (the session started on it), completed (the session went")
+		fmt.Println("idle again) or failed. 'session send --json' returns the receipt_id.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session receipt rcpt-3f9a0c2e71d4b856 --json")
+		fmt.Println("  agent-deck session receipt rcpt-3f9a0c2e71d4b856 --wait --timeout 30m")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		out.Error("receipt id is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	db := storage.GetDB()
+	if db == nil {
+		out.Error("state database is not available", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	deadline := time.Now().Add(*timeout)
+	receipt, err := session.RefreshReceipt(db, instances, fs.Arg(0))
+	for err == nil && *wait && !receipt.Finished() && time.Now().Before(deadline) {
+		time.Sleep(2 * time.Second)
+		receipt, err = session.RefreshReceipt(db, instances, fs.Arg(0))
+	}
+	if err != nil {
+		if errors.Is(err, statedb.ErrReceiptNotFound) {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *wait && !receipt.Finished() {
+		out.Error(fmt.Sprintf("timeout waiting for receipt %s (still %s)", receipt.ID, receipt.State), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		out.Print("", receipt)
+	} else if !*quiet {
+		fmt.Printf("Receipt:   %s\n", receipt.ID)
+		fmt.Printf("Session:   %s\n", receipt.Title)
+		fmt.Printf("State:     %s\n", receipt.State)
+		for _, step := range []struct {
+			label string
+			at    time.Time
+		}{
+			{"Queued:", receipt.Queued},
+			{"Delivered:", receipt.Delivered},
+			{"Acked:", receipt.Acknowledged},
+			{"Completed:", receipt.Completed},
+			{"Failed:", receipt.Failed},
+		} {
+			if !step.at.IsZero() {
+				fmt.Printf("%-10s %s\n", step.label, step.at.Local().Format(time.RFC3339))
+			}
+		}
+		if receipt.Error != "" {
+			fmt.Printf("Error:     %s\n", receipt.Error)
+		}
+	}
+	if receipt.State == statedb.ReceiptFailed {
+		os.Exit(1)
+	}
+}
+
+// heartbeatLockHeldElsewhere takes or renews the conductor's heartbeat lock
+// when [conductor] heartbeat_lock is on, and reports whether another machine
+// holds it. Lock errors are reported and the heartbeat is delivered anyway:
+// a duplicate heartbeat is cheaper than none.
+func heartbeatLockHeldElsewhere(conductor string) (*session.HeartbeatLock, bool) {
+	settings := session.GetConductorSettings()
+	if !settings.HeartbeatLock {
+		return nil, false
+	}
+	interval := 0
+	if meta, err := session.LoadConductorMeta(conductor); err == nil {
+		interval = session.HeartbeatIntervalFor(meta)
+	}
+	lock, err := session.AcquireHeartbeatLock(conductor, settings.GetHeartbeatLockTTL(interval), time.Now())
+	if errors.Is(err, session.ErrHeartbeatLockHeld) && lock != nil {
+		return lock, true
+	}
+	if err != nil {
+		heartbeatLog.Warn("heartbeat_lock_failed", slog.String("conductor", conductor), slog.String("error", err.Error()))
+		fmt.Fprintf(os.Stderr, "Warning: heartbeat lock for %s: %v\n", conductor, err)
+	}
+	return lock, false
+}
+
+// recordHeartbeatResult notes the outcome of a heartbeat sent to a conductor
+// for "agent-deck --events" and the conductor's heartbeat history, with the
+// session status before and after it. Other messages are ignored.
+func recordHeartbeatResult(profile string, inst *session.Instance, message, result string, before session.Status, err error) {
+	name, ok := session.ConductorNameFromTitle(inst.Title)
+	if !ok || session.ConductorTaskKind(message) != statedb.ConductorTaskHeartbeat {
+		return
+	}
+	hb := session.HeartbeatResult{
+		Conductor: name,
+		Profile:   profile,
+		SessionID: inst.ID,
+		Result:    result,
+		Time:      time.Now(),
+	}
+	if err != nil {
+		hb.Error = err.Error()
+	}
+	_ = session.WriteHeartbeatResult(hb)
+
+	after := before
+	if result == session.HeartbeatSent {
+		_ = inst.UpdateStatus()
+		after = inst.GetStatusThreadSafe()
+	}
+	if err := session.AppendHeartbeatHistory(name, session.HeartbeatRun{
+		Time:         hb.Time,
+		Profile:      profile,
+		SessionID:    inst.ID,
+		Result:       result,
+		Error:        hb.Error,
+		Prompt:       message,
+		StatusBefore: string(before),
+		StatusAfter:  string(after),
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record heartbeat history: %v\n", err)
+	}
+
+	ev := session.NewHookEvent(session.HookEventHeartbeat, profile, inst, hb.Time)
+	ev.Conductor, ev.Result, ev.Error = name, result, hb.Error
+	session.RunEventHooks(ev)
+}
+
 func handleSessionSend(profile string, args []string) {
 	fs := flag.NewFlagSet("session send", flag.ExitOnError)
 	fs.SetOutput(os.Stdout)
@@ -1279,6 +1803,9 @@ func handleSessionSend(profile string, args []string) {
 	noWait := fs.Bool("no-wait", false, "Don't wait for agent to be ready (send immediately)")
 	wait := fs.Bool("wait", false, "Block until agent finishes processing, then print output")
 	timeout := fs.Duration("timeout", 10*time.Minute, "Max time to wait for completion (used with --wait)")
+	dedupeKey := fs.String("dedupe-key", "", "Collapse sends to a conductor with this key within the [conductor] dedupe_window (default: the message text)")
+	noDedupe := fs.Bool("no-dedupe", false, "Send to a conductor even if the same task was sent recently")
+	force := fs.Bool("force", false, "Send to a conductor even during a maintenance window")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session send <id|title> <message> [options]")
@@ -1292,6 +1819,7 @@ func handleSessionSend(profile string, args []string) {
 		fmt.Println("  agent-deck session send my-project \"Summarize recent changes\"")
 		fmt.Println("  agent-deck session send my-project \"run tests\" --wait")
 		fmt.Println("  agent-deck session send my-project \"quick ping\" --no-wait")
+		fmt.Println("  agent-deck session send conductor-ops \"PR #12 failed CI\" --dedupe-key ci-pr-12")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -1311,7 +1839,7 @@ func handleSessionSend(profile string, args []string) {
 	message := strings.Join(remaining[1:], " ")
 
 	// Load sessions
-	_, instances, _, err := loadSessionData(profile)
+	storage, instances, _, err := loadSessionData(profile)
 	if err != nil {
 		out.Error(err.Error(), ErrCodeNotFound)
 		os.Exit(1)
@@ -1341,9 +1869,105 @@ func handleSessionSend(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	// The heartbeat history records the conductor's status around each heartbeat
+	var statusBefore session.Status
+	if _, isConductor := session.ConductorNameFromTitle(inst.Title); isConductor &&
+		session.ConductorTaskKind(message) == statedb.ConductorTaskHeartbeat {
+		_ = inst.UpdateStatus()
+		statusBefore = inst.GetStatusThreadSafe()
+	}
+
+	// Maintenance windows pause work dispatched to conductors: heartbeats are
+	// dropped (the next one after the window covers them), other tasks are refused.
+	if _, isConductor := session.ConductorNameFromTitle(inst.Title); isConductor && !*force {
+		if active := session.ActiveMaintenanceWindow(time.Now()); active != nil {
+			if session.ConductorTaskKind(message) == statedb.ConductorTaskHeartbeat {
+				recordHeartbeatResult(storage.Profile(), inst, message, session.HeartbeatSkipped, statusBefore, nil)
+				out.Success(fmt.Sprintf("Skipped heartbeat for '%s': %s", inst.Title, active.Describe()), map[string]interface{}{
+					"success":       true,
+					"session_id":    inst.ID,
+					"session_title": inst.Title,
+					"skipped":       true,
+					"maintenance":   active,
+				})
+				return
+			}
+			out.Error(fmt.Sprintf("%s; use --force to send anyway", active.Describe()), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	// An emergency stop refuses every message, --force included; heartbeats
+	// to conductors were already recorded as skipped above
+	if stop := session.LoadEmergencyStop(); stop != nil {
+		out.Error(fmt.Sprintf("%s; run 'agent-deck emergency-stop resume' first", stop.Describe()), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	// With a shared home directory every machine's timer fires; only the
+	// machine holding the conductor's heartbeat lock delivers the heartbeat
+	if name, isConductor := session.ConductorNameFromTitle(inst.Title); isConductor && !*force &&
+		session.ConductorTaskKind(message) == statedb.ConductorTaskHeartbeat {
+		if lock, held := heartbeatLockHeldElsewhere(name); held {
+			msg := fmt.Sprintf("Skipped heartbeat for '%s': lock held by %s until %s", inst.Title, lock.Holder, lock.ExpiresAt.Format(time.RFC3339))
+			recordHeartbeatResult(storage.Profile(), inst, message, session.HeartbeatSkipped, statusBefore, errors.New(msg))
+			out.Success(msg, map[string]interface{}{
+				"success":        true,
+				"session_id":     inst.ID,
+				"session_title":  inst.Title,
+				"skipped":        true,
+				"heartbeat_lock": lock,
+			})
+			return
+		}
+	}
+
+	// Chaos mode simulates heartbeats that fail to reach the conductor
+	if session.ConductorTaskKind(message) == statedb.ConductorTaskHeartbeat {
+		if err := chaos.Error(chaos.HeartbeatFailure); err != nil {
+			recordHeartbeatResult(storage.Profile(), inst, message, session.HeartbeatFailed, statusBefore, err)
+			out.Error(fmt.Sprintf("failed to send message: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	// Conductors get the same task from repeated webhooks or retries; collapse
+	// duplicates sent within the dedupe window instead of doing the work twice.
+	if !*noDedupe {
+		dedupe, err := session.DedupeConductorTask(storage.GetDB(), inst, message, *dedupeKey, time.Now())
+		if err == nil && dedupe.Duplicate {
+			out.Success(fmt.Sprintf("Skipped duplicate task for '%s' (same task sent %s; %d merged)",
+				inst.Title, session.NewTimeFormatter(false).Format(dedupe.FirstAt, time.Now()), dedupe.Merged),
+				map[string]interface{}{
+					"success":       true,
+					"session_id":    inst.ID,
+					"session_title": inst.Title,
+					"message":       message,
+					"deduplicated":  true,
+					"merged":        dedupe.Merged,
+					"first_sent_at": dedupe.FirstAt.Format(time.RFC3339),
+				})
+			return
+		}
+	}
+
+	// The receipt lets the caller follow the prompt after this command returns
+	// (queued -> delivered -> acknowledged -> completed)
+	receiptID, err := session.NewDeliveryReceipt(storage.GetDB(), inst, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create delivery receipt: %v\n", err)
+	}
+	failReceipt := func(cause error) {
+		if receiptID != "" {
+			_ = storage.GetDB().FailReceipt(receiptID, cause, time.Now())
+		}
+	}
+
 	// Wait for agent to be ready (unless --no-wait is specified)
 	if !*noWait {
 		if err := waitForAgentReady(tmuxSess, inst.Tool); err != nil {
+			recordHeartbeatResult(storage.Profile(), inst, message, session.HeartbeatFailed, statusBefore, err)
+			failReceipt(err)
 			out.Error(fmt.Sprintf("timeout waiting for agent: %v", err), ErrCodeInvalidOperation)
 			os.Exit(1)
 		}
@@ -1354,22 +1978,43 @@ func handleSessionSend(profile string, args []string) {
 	// Otherwise: retry Enter if the agent doesn't start processing promptly.
 	if *noWait {
 		if err := tmuxSess.SendKeysAndEnter(message); err != nil {
+			recordHeartbeatResult(storage.Profile(), inst, message, session.HeartbeatFailed, statusBefore, err)
+			failReceipt(err)
 			out.Error(fmt.Sprintf("failed to send message: %v", err), ErrCodeInvalidOperation)
 			os.Exit(1)
 		}
 	} else {
 		if err := sendWithRetry(tmuxSess, message, false); err != nil {
+			recordHeartbeatResult(storage.Profile(), inst, message, session.HeartbeatFailed, statusBefore, err)
+			failReceipt(err)
 			out.Error(fmt.Sprintf("failed to send message: %v", err), ErrCodeInvalidOperation)
 			os.Exit(1)
 		}
 	}
 
-	out.Success(fmt.Sprintf("Sent message to '%s'", inst.Title), map[string]interface{}{
+	// Conductor sessions feed the load report (tasks/day, latency)
+	sentAt := time.Now()
+	_ = session.RecordConductorTask(storage.GetDB(), inst, message, sentAt)
+	if err := session.RecordDelivery(storage.GetDB(), inst, message, sentAt); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to sign delivery: %v\n", err)
+	}
+	recordHeartbeatResult(storage.Profile(), inst, message, session.HeartbeatSent, statusBefore, nil)
+
+	result := map[string]interface{}{
 		"success":       true,
 		"session_id":    inst.ID,
 		"session_title": inst.Title,
 		"message":       message,
-	})
+	}
+	sentMsg := fmt.Sprintf("Sent message to '%s'", inst.Title)
+	if receiptID != "" {
+		if err := storage.GetDB().MarkReceiptDelivered(receiptID, sentAt); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update delivery receipt: %v\n", err)
+		}
+		result["receipt_id"] = receiptID
+		sentMsg += fmt.Sprintf(" (receipt %s)", receiptID)
+	}
+	out.Success(sentMsg, result)
 
 	// If --wait, block until the agent finishes processing, then print output
 	if *wait {
@@ -1586,11 +2231,14 @@ func handleSessionOutput(profile string, args []string) {
 	quiet := fs.Bool("quiet", false, "Minimal output")
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
 	copyFlag := fs.Bool("copy", false, "Copy output to system clipboard")
+	summarize := fs.Bool("summarize", false, "Condense the response with the [summarizer] backend (for handoffs)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session output [id|title] [options]")
 		fmt.Println()
 		fmt.Println("Get the last response from a session. If no ID is provided, auto-detects current session.")
+		fmt.Println("--summarize condenses it with [summarizer] in config.toml: the heuristic")
+		fmt.Println("extractor by default, or a local ollama model or API model.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -1628,6 +2276,14 @@ func handleSessionOutput(profile string, args []string) {
 		out.Error(fmt.Sprintf("failed to get response: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	summarizedBy := ""
+	if *summarize {
+		summary, backend, err := session.Summarize(context.Background(), fmt.Sprintf("the latest output of agent session %q", inst.Title), response.Content)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the %s summary\n", err, backend)
+		}
+		response.Content, summarizedBy = summary, backend
+	}
 
 	// Copy to clipboard mode
 	if *copyFlag {
@@ -1668,6 +2324,15 @@ func handleSessionOutput(profile string, args []string) {
 		"content":       response.Content,
 		"timestamp":     response.Timestamp,
 	}
+	// Review sessions tag their verdict for digests
+	if inst.StartReasonKind() == session.StartReasonReview {
+		if verdict, ok := session.ParseReviewVerdict(response.Content); ok {
+			jsonData["review"] = verdict
+		}
+	}
+	if summarizedBy != "" {
+		jsonData["summarized_by"] = summarizedBy
+	}
 	// Add tool-specific conversation session ID
 	if response.SessionID != "" {
 		switch response.Tool {
@@ -1686,6 +2351,9 @@ func handleSessionOutput(profile string, args []string) {
 	if response.Timestamp != "" {
 		sb.WriteString(fmt.Sprintf("Time: %s\n", response.Timestamp))
 	}
+	if summarizedBy != "" {
+		sb.WriteString(fmt.Sprintf("Summary: %s\n", summarizedBy))
+	}
 	sb.WriteString("---\n")
 	sb.WriteString(response.Content)
 
@@ -1780,7 +2448,7 @@ func handleSessionCurrent(profileArg string, args []string) {
 	sb.WriteString(fmt.Sprintf("Session: %s\n", instData.Title))
 	sb.WriteString(fmt.Sprintf("Profile: %s\n", detectedProfile))
 	sb.WriteString(fmt.Sprintf("ID:      %s\n", instData.ID))
-	sb.WriteString(fmt.Sprintf("Status:  %s %s\n", StatusSymbol(instData.Status), status))
+	sb.WriteString(fmt.Sprintf("Status:  %s\n", StatusLabel(instData.Status, status)))
 	sb.WriteString(fmt.Sprintf("Path:    %s\n", FormatPath(instData.ProjectPath)))
 	if instData.GroupPath != "" {
 		sb.WriteString(fmt.Sprintf("Group:   %s\n", instData.GroupPath))
@@ -1849,3 +2517,303 @@ func matchInstanceDataByTmuxName(instances []*session.InstanceData, tmuxSessionN
 	}
 	return nil
 }
+
+// handleSessionPin pins or unpins a session so it sorts first in its group
+func handleSessionPin(profile string, args []string, pin bool) {
+	name := "pin"
+	if !pin {
+		name = "unpin"
+	}
+	fs := flag.NewFlagSet("session "+name, flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Printf("Usage: agent-deck session %s <session>\n", name)
+		fmt.Println()
+		if pin {
+			fmt.Println("Pin a session so it is listed first in its group (TUI, web and CLI).")
+		} else {
+			fmt.Println("Remove a session's pin.")
+		}
+		fmt.Println("Pins are stored in [sort] pinned in config.toml.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		os.Exit(2)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	if err := session.SetPinned(inst, pin); err != nil {
+		out.Error(fmt.Sprintf("failed to save config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	verb := "Pinned"
+	if !pin {
+		verb = "Unpinned"
+	}
+	out.Success(fmt.Sprintf("%s '%s'", verb, inst.Title), map[string]interface{}{
+		"success":       true,
+		"session_id":    inst.ID,
+		"session_title": inst.Title,
+		"pinned":        pin,
+	})
+}
+
+// handleSessionNotify sets a session's desktop notification override
+func handleSessionNotify(profile string, args []string) {
+	fs := flag.NewFlagSet("session notify", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	idle := fs.Int("idle", 0, "Seconds the session must stay quiet before notifying (default: [notifications.desktop] idle_seconds)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session notify <session> <on|off|default> [options]")
+		fmt.Println()
+		fmt.Println("Turn desktop notifications for a finished session on or off, whatever")
+		fmt.Println("the global setting; 'default' removes the override. Overrides are stored")
+		fmt.Println("in [notifications.desktop.sessions] in config.toml.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session notify api-server on --idle 60")
+		fmt.Println("  agent-deck session notify scratch off")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	var enabled *bool
+	switch mode := fs.Arg(1); mode {
+	case "on", "off":
+		on := mode == "on"
+		enabled = &on
+	case "default":
+		if *idle > 0 {
+			out.Error("--idle cannot be combined with default", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	default:
+		out.Error(fmt.Sprintf("invalid mode %q: use on, off or default", mode), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var idleSeconds *int
+	if *idle > 0 {
+		idleSeconds = idle
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		os.Exit(2)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	if err := session.SetDesktopNotify(inst, enabled, idleSeconds); err != nil {
+		out.Error(fmt.Sprintf("failed to save config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	on, after := session.GetDesktopNotifySettings().For(inst.ID, inst.Title, inst.Tool)
+	msg := fmt.Sprintf("Desktop notifications for '%s' are off", inst.Title)
+	if on {
+		msg = fmt.Sprintf("Desktop notifications for '%s' are on (after %s quiet)", inst.Title, after)
+	}
+	out.Success(msg, map[string]interface{}{
+		"success":       true,
+		"session_id":    inst.ID,
+		"session_title": inst.Title,
+		"enabled":       on,
+		"idle_seconds":  int(after / time.Second),
+	})
+}
+
+// handleSessionAudit prints the audit log of session creations and removals
+func handleSessionAudit(profile string, args []string) {
+	fs := flag.NewFlagSet("session audit", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	limit := fs.Int("limit", 50, "Maximum number of entries (0 = all)")
+	absolute := fs.Bool("absolute", false, "Show absolute timestamps instead of relative ones")
+	verify := fs.Bool("verify", false, "Verify the signatures of delivered heartbeats and prompts")
+	publicKey := fs.Bool("public-key", false, "Print the deck public key that signs deliveries")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session audit [id|title] [options]")
+		fmt.Println()
+		fmt.Println("Show when sessions were created or removed, and why they were created.")
+		fmt.Println("Removed sessions can be selected by their full ID. With [audit]")
+		fmt.Println("sign_deliveries set, heartbeats and prompts sent to conductors are")
+		fmt.Println("recorded and signed with the deck key; --verify checks them.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	var pub ed25519.PublicKey
+	if *verify || *publicKey {
+		keyPath, err := session.GetAuditSettings().GetKeyPath()
+		if err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		key, err := session.LoadDeckKey(keyPath, false)
+		if err != nil {
+			out.Error(fmt.Sprintf("no deck key: %v", err), ErrCodeNotFound)
+			os.Exit(1)
+		}
+		pub = key.Public().(ed25519.PublicKey)
+	}
+	if *publicKey {
+		encoded := base64.StdEncoding.EncodeToString(pub)
+		out.Success(encoded, map[string]any{"public_key": encoded, "key_id": session.DeckKeyID(pub)})
+		return
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	db := storage.GetDB()
+	if db == nil {
+		out.Error("audit log is not available for this profile", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	sessionID := ""
+	if identifier := fs.Arg(0); identifier != "" {
+		// Fall back to the raw identifier so removed sessions can be looked up
+		sessionID = identifier
+		if inst, _, _ := ResolveSession(identifier, instances); inst != nil {
+			sessionID = inst.ID
+		}
+	}
+
+	entries, err := db.ListAudit(sessionID, *limit)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read audit log: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	// Verification errors by entry; unsigned kinds of entries are skipped
+	invalid := map[int64]error{}
+	verified := 0
+	if *verify {
+		for _, e := range entries {
+			if e.Action != statedb.AuditDelivered {
+				continue
+			}
+			if _, err := session.VerifyDelivery(e, pub); err != nil {
+				invalid[e.Seq] = err
+			} else {
+				verified++
+			}
+		}
+	}
+	if *jsonOutput {
+		result := map[string]any{"profile": storage.Profile(), "entries": entries}
+		if *verify {
+			failures := make([]map[string]any, 0, len(invalid))
+			for _, e := range entries {
+				if err, ok := invalid[e.Seq]; ok {
+					failures = append(failures, map[string]any{"seq": e.Seq, "error": err.Error()})
+				}
+			}
+			result["verified"] = verified
+			result["invalid"] = failures
+		}
+		out.Print("", result)
+		if len(invalid) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Println("No audit entries.")
+		return
+	}
+
+	titles := make(map[string]string, len(instances))
+	for _, inst := range instances {
+		titles[inst.ID] = inst.Title
+	}
+	timeFmt := session.NewTimeFormatter(*absolute)
+	now := time.Now()
+	for _, e := range entries {
+		id := e.SessionID
+		if len(id) > tableColIDDisplay {
+			id = id[:tableColIDDisplay]
+		}
+		line := fmt.Sprintf("  %-16s %-9s %-*s %s", timeFmt.Format(e.Time, now), e.Action, tableColIDDisplay, id, e.Title)
+		switch {
+		case e.Action == statedb.AuditCreated:
+			line += "  (" + session.DescribeStartReason(e.Detail, func(id string) string { return titles[id] }) + ")"
+		case e.Action == statedb.AuditDelivered:
+			var delivery session.SignedDelivery
+			_ = json.Unmarshal([]byte(e.Detail), &delivery)
+			status := delivery.Kind
+			if *verify {
+				if _, bad := invalid[e.Seq]; bad {
+					status += ", SIGNATURE INVALID"
+				} else {
+					status += ", verified"
+				}
+			}
+			line += fmt.Sprintf("  (%s) %q", status, truncate(delivery.Text, 60))
+		case e.Detail != "":
+			line += "  (" + e.Detail + ")"
+		}
+		fmt.Println(line)
+	}
+	if *verify {
+		fmt.Printf("\n%d deliveries verified, %d invalid\n", verified, len(invalid))
+		if len(invalid) > 0 {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// commandAliases maps command aliases to the name usage is counted under
+var commandAliases = map[string]string{"ls": "list", "rm": "remove", "mv": "rename", "wt": "worktree"}
+
+// recordCommandUsage counts the command being run when usage stats are
+// enabled. Only known command and subcommand names are counted, never
+// arguments, so paths and session titles cannot end up in the stats.
+func recordCommandUsage(args []string) {
+	if len(args) == 0 {
+		session.RecordUsage("tui")
+		return
+	}
+	cmd := args[0]
+	if alias, ok := commandAliases[cmd]; ok {
+		cmd = alias
+	}
+	if !slices.Contains(completionCommands, cmd) {
+		return
+	}
+	features := []string{cmd}
+	if len(args) > 1 && slices.Contains(completionSubcommands[cmd], args[1]) {
+		features = append(features, cmd+" "+args[1])
+	}
+	session.RecordUsage(features...)
+}
+
+// handleStats dispatches usage stats subcommands
+func handleStats(args []string) {
+	if len(args) == 0 {
+		handleStatsShow(nil)
+		return
+	}
+
+	switch args[0] {
+	case "show":
+		handleStatsShow(args[1:])
+	case "enable":
+		handleStatsToggle(args[1:], true)
+	case "disable":
+		handleStatsToggle(args[1:], false)
+	case "export":
+		handleStatsExport(args[1:])
+	case "reset":
+		handleStatsReset(args[1:])
+	case "help", "--help", "-h":
+		printStatsHelp()
+	default:
+		fmt.Printf("Unknown stats command: %s\n", args[0])
+		fmt.Println()
+		printStatsHelp()
+		os.Exit(1)
+	}
+}
+
+// printStatsHelp prints usage for stats commands
+func printStatsHelp() {
+	fmt.Println("Usage: agent-deck stats <command> [options]")
+	fmt.Println()
+	fmt.Println("Opt-in usage counters: how often each command is used. They are kept in")
+	fmt.Println("~/.agent-deck/usage-stats.json and never sent anywhere. Export them to share")
+	fmt.Println("what you rely on when filing an issue.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  show      Show the counters (default)")
+	fmt.Println("  enable    Start counting ([usage_stats] enabled = true)")
+	fmt.Println("  disable   Stop counting (existing counters are kept)")
+	fmt.Println("  export    Print the counters as shareable JSON (-o file to write them)")
+	fmt.Println("  reset     Delete the counters")
+}
+
+func handleStatsShow(args []string) {
+	fs := flag.NewFlagSet("stats show", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	stats, err := session.LoadUsageStats()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	enabled := session.UsageStatsEnabled()
+	if *jsonOutput {
+		out.Print("", map[string]any{"enabled": enabled, "stats": stats})
+		return
+	}
+
+	if enabled {
+		fmt.Println("Usage stats: enabled (local only)")
+	} else {
+		fmt.Println("Usage stats: disabled (enable with: agent-deck stats enable)")
+	}
+	if len(stats.Counts) == 0 {
+		fmt.Println("No usage recorded.")
+		return
+	}
+	fmt.Printf("Since %s\n\n", stats.Since.Format("2006-01-02"))
+	for _, name := range stats.SortedFeatures() {
+		fmt.Printf("  %6d  %s\n", stats.Counts[name], name)
+	}
+}
+
+func handleStatsToggle(args []string, enable bool) {
+	fs := flag.NewFlagSet("stats enable", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	if err := session.SetUsageStatsEnabled(enable); err != nil {
+		out.Error(fmt.Sprintf("failed to update config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	msg := "Usage stats disabled"
+	if enable {
+		path, _ := session.UsageStatsPath()
+		msg = fmt.Sprintf("Usage stats enabled; counters are kept locally in %s", path)
+	}
+	out.Success(msg, map[string]any{"enabled": enable})
+}
+
+func handleStatsExport(args []string) {
+	fs := flag.NewFlagSet("stats export", flag.ExitOnError)
+	output := fs.String("o", "", "Write to this file instead of stdout")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	stats, err := session.LoadUsageStats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := json.MarshalIndent(stats.Export(Version, time.Now()), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("[ok] Usage stats written to %s\n", *output)
+}
+
+func handleStatsReset(args []string) {
+	fs := flag.NewFlagSet("stats reset", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	if err := session.ResetUsageStats(); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success("Usage stats reset", map[string]any{"reset": true})
+}
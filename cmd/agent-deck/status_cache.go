@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/web"
+)
+
+// defaultStatusMaxAge is how stale a web server's cached status may be before
+// list/status ask it to re-poll. Overridden with --max-age.
+const defaultStatusMaxAge = 2 * time.Second
+
+// statusFetchTimeout bounds the round trip to the web server. A socket-activated
+// server may need to start and poll all panes on its first request.
+const statusFetchTimeout = 3 * time.Second
+
+// refreshStatuses updates instance statuses, preferring the cached view of a
+// running web server for the profile. maxAge <= 0 always polls tmux directly.
+func refreshStatuses(profile string, instances []*session.Instance, maxAge time.Duration) {
+	if maxAge > 0 && applyServerStatuses(profile, instances, maxAge) {
+		return
+	}
+	for _, inst := range instances {
+		_ = inst.UpdateStatus()
+	}
+}
+
+// applyServerStatuses copies statuses from the web server's snapshot. It returns
+// false (leaving instances untouched) when no server is reachable or the
+// snapshot doesn't cover every instance, e.g. a session created since it polled.
+func applyServerStatuses(profile string, instances []*session.Instance, maxAge time.Duration) bool {
+	ep, err := session.ReadWebEndpoint(profile)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), statusFetchTimeout)
+	defer cancel()
+	snapshot, err := web.FetchMenuSnapshot(ctx, ep, maxAge)
+	if err != nil {
+		return false
+	}
+
+	statuses := make(map[string]session.Status, snapshot.TotalSessions)
+	for _, item := range snapshot.Items {
+		if item.Type == web.MenuItemTypeSession && item.Session != nil {
+			statuses[item.Session.ID] = item.Session.Status
+		}
+	}
+	for _, inst := range instances {
+		if _, ok := statuses[inst.ID]; !ok {
+			return false
+		}
+	}
+	for _, inst := range instances {
+		inst.Status = statuses[inst.ID]
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleStatusExport writes the [status_export] snapshot once, or repeatedly
+// with --watch
+func handleStatusExport(args []string) {
+	fs := flag.NewFlagSet("status export", flag.ExitOnError)
+	path := fs.String("path", "", "Write here instead of [status_export] path (- for stdout)")
+	watch := fs.Bool("watch", false, "Keep rewriting the snapshot every [status_export] interval")
+	quiet := fs.Bool("q", false, "Quiet mode: no output, and no error when no path is configured")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck status export [options]")
+		fmt.Println()
+		fmt.Println("Write a compact JSON snapshot of every profile's session statuses to a")
+		fmt.Println("static file, for viewing from elsewhere (a web server, Dropbox sync).")
+		fmt.Println("The conductor bridge rewrites it every [status_export] interval.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck status export")
+		fmt.Println("  agent-deck status export --path /var/www/html/deck.json --watch")
+		fmt.Println("  agent-deck status export --path - | jq .totals")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	settings := session.GetStatusExportSettings()
+	if *path != "" {
+		settings.Path = session.ExpandPath(*path)
+	}
+	if settings.Path == "" {
+		if *quiet {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "Error: no export path; set [status_export] path in config.toml or pass --path")
+		os.Exit(1)
+	}
+
+	export := func() {
+		snapshot, err := buildStatusExport(settings.HideSessions)
+		if err == nil {
+			if settings.Path == "-" {
+				err = json.NewEncoder(os.Stdout).Encode(snapshot)
+			} else {
+				err = session.WriteStatusExport(settings.Path, snapshot)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: status export: %v\n", err)
+			if !*watch {
+				os.Exit(1)
+			}
+			return
+		}
+		if !*quiet && settings.Path != "-" && !*watch {
+			fmt.Printf("[ok] Status snapshot written to %s\n", settings.Path)
+		}
+	}
+
+	export()
+	if !*watch {
+		return
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(settings.GetInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			export()
+		}
+	}
+}
+
+// buildStatusExport snapshots every profile of the deck
+func buildStatusExport(hideSessions bool) (*session.StatusExport, error) {
+	profiles, err := session.ListProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	snapshot := session.NewStatusExport(time.Now())
+	for _, profile := range profiles {
+		storage, err := session.NewStorageWithProfile(profile)
+		if err != nil {
+			continue
+		}
+		instances, _, err := storage.LoadWithGroups()
+		if err == nil {
+			refreshStatuses(profile, instances, defaultStatusMaxAge)
+			snapshot.AddProfile(profile, instances, hideSessions)
+		}
+		_ = storage.Close()
+	}
+	return snapshot, nil
+}
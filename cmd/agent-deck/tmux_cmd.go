@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// handleTmux dispatches tmux subcommands
+func handleTmux(args []string) {
+	if len(args) == 0 {
+		handleTmuxCheck(nil)
+		return
+	}
+
+	switch args[0] {
+	case "check":
+		handleTmuxCheck(args[1:])
+	case "help", "--help", "-h":
+		printTmuxHelp()
+	default:
+		fmt.Printf("Unknown tmux command: %s\n", args[0])
+		fmt.Println()
+		printTmuxHelp()
+		os.Exit(1)
+	}
+}
+
+// printTmuxHelp prints usage for tmux commands
+func printTmuxHelp() {
+	fmt.Println("Usage: agent-deck tmux <command> [options]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  check     Check tmux options agent-deck depends on (default; --fix sets them)")
+	fmt.Println()
+	fmt.Println("Checked options: history-limit, escape-time, default-terminal, focus-events.")
+	fmt.Println("The TUI runs the same check at startup; [tmux] check_options = \"warn\" | \"fix\" | \"off\".")
+}
+
+// tmuxOptionIssues runs the tmux option check against the user's config
+func tmuxOptionIssues() ([]tmux.OptionIssue, error) {
+	global, err := tmux.GlobalOptions()
+	if err != nil {
+		return nil, err
+	}
+	overrides := session.GetTmuxSettings().Options
+	return tmux.CheckOptions(global, overrides, len(tmux.DeckSessions()) > 0), nil
+}
+
+func handleTmuxCheck(args []string) {
+	fs := flag.NewFlagSet("tmux check", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fix := fs.Bool("fix", false, "Set options to the values agent-deck needs")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	issues, err := tmuxOptionIssues()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	fixed := false
+	if *fix && len(issues) > 0 {
+		if err := tmux.FixOptions(issues); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		fixed = true
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]any{"ok": len(issues) == 0, "issues": issues, "fixed": fixed})
+		return
+	}
+	if len(issues) == 0 {
+		fmt.Println("[ok] tmux options are compatible with agent-deck")
+		return
+	}
+	for _, issue := range issues {
+		fmt.Printf("!! %s\n", issue)
+	}
+	switch {
+	case fixed:
+		fmt.Println("[ok] Set tmux options (config.toml [tmux] options must be edited by hand)")
+	default:
+		fmt.Println("Run 'agent-deck tmux check --fix' to set them, or adjust tmux.conf / [tmux] options.")
+		os.Exit(1)
+	}
+}
+
+// checkTmuxOptionsAtStartup runs the [tmux] check_options check before the
+// TUI starts: warnings go to stderr, and "fix" mode sets the options too.
+func checkTmuxOptionsAtStartup() {
+	mode := session.GetTmuxSettings().GetCheckOptions()
+	if mode == "off" {
+		return
+	}
+	issues, err := tmuxOptionIssues()
+	if err != nil || len(issues) == 0 {
+		return
+	}
+	if mode == "fix" && tmux.FixOptions(issues) == nil {
+		// Only config.toml overrides are left; they must be edited by hand
+		remaining := issues[:0]
+		for _, issue := range issues {
+			if issue.Source == "config.toml" {
+				remaining = append(remaining, issue)
+			}
+		}
+		if issues = remaining; len(issues) == 0 {
+			return
+		}
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "Warning: tmux %s\n", issue)
+	}
+	fmt.Fprintln(os.Stderr, "  Fix with: agent-deck tmux check --fix (or [tmux] check_options = \"fix\")")
+}
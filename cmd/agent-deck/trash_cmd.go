@@ -0,0 +1,226 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleTrash dispatches trash subcommands
+func handleTrash(args []string) {
+	if len(args) == 0 {
+		// Default to list
+		handleTrashList(nil)
+		return
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleTrashList(args[1:])
+	case "restore", "undelete":
+		handleTrashRestore(args[1:])
+	case "purge":
+		handleTrashPurge(args[1:])
+	case "help", "--help", "-h":
+		printTrashHelp()
+		return
+	default:
+		fmt.Printf("Unknown trash command: %s\n", args[0])
+		fmt.Println()
+		printTrashHelp()
+		os.Exit(1)
+	}
+}
+
+// printTrashHelp prints usage for trash commands
+func printTrashHelp() {
+	fmt.Println("Usage: agent-deck trash <command> [options]")
+	fmt.Println()
+	fmt.Println("Removed sessions and torn-down conductors are kept in the trash")
+	fmt.Println("for a retention window ([trash] retention_days, default 7).")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list              List trashed sessions and conductors")
+	fmt.Println("  restore <id>      Restore a trashed item")
+	fmt.Println("  purge [id]        Permanently delete expired items (or one item, or --all)")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck trash list")
+	fmt.Println("  agent-deck trash restore abc12345")
+	fmt.Println("  agent-deck trash purge --all")
+}
+
+// handleTrashList lists trash entries
+func handleTrashList(args []string) {
+	fs := flag.NewFlagSet("trash list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	absolute := fs.Bool("absolute", false, "Show absolute expiry times instead of relative ones")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck trash list [options]")
+		fmt.Println()
+		fmt.Println("List trashed sessions and conductors.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+
+	_, _ = session.PurgeExpiredTrash()
+	entries, err := session.ListTrash()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to list trash: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		type trashJSON struct {
+			ID        string    `json:"id"`
+			Kind      string    `json:"kind"`
+			Name      string    `json:"name"`
+			Profile   string    `json:"profile,omitempty"`
+			DeletedAt time.Time `json:"deleted_at"`
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+		items := make([]trashJSON, 0, len(entries))
+		for _, e := range entries {
+			items = append(items, trashJSON{
+				ID:        e.ID,
+				Kind:      string(e.Kind),
+				Name:      e.Name,
+				Profile:   e.Profile,
+				DeletedAt: e.DeletedAt,
+				ExpiresAt: e.ExpiresAt,
+			})
+		}
+		out.Print("", items)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Trash is empty.")
+		return
+	}
+
+	fmt.Printf("%-36s %-10s %-24s %-12s %s\n", "ID", "KIND", "NAME", "PROFILE", "EXPIRES")
+	fmt.Println(strings.Repeat("-", 96))
+	timeFmt := session.NewTimeFormatter(*absolute)
+	now := time.Now()
+	for _, e := range entries {
+		fmt.Printf("%-36s %-10s %-24s %-12s %s\n",
+			e.ID, e.Kind, truncate(e.Name, 24), e.Profile, timeFmt.Format(e.ExpiresAt, now))
+	}
+	fmt.Printf("\nTotal: %d item(s)\n", len(entries))
+}
+
+// handleTrashRestore restores a trash entry
+func handleTrashRestore(args []string) {
+	fs := flag.NewFlagSet("trash restore", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck trash restore <id>")
+		fmt.Println()
+		fmt.Println("Restore a trashed session or conductor.")
+		fmt.Println("Conductor heartbeats are not reinstalled; re-run 'conductor setup' if needed.")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+
+	id := fs.Arg(0)
+	if id == "" {
+		out.Error("trash id is required", ErrCodeNotFound)
+		if !*jsonOutput {
+			fs.Usage()
+		}
+		os.Exit(1)
+	}
+
+	entry, err := session.Undelete(id)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	out.Success(
+		fmt.Sprintf("Restored %s: %s", entry.Kind, entry.Name),
+		map[string]interface{}{
+			"success": true,
+			"id":      entry.ID,
+			"kind":    string(entry.Kind),
+			"name":    entry.Name,
+			"profile": entry.Profile,
+		},
+	)
+}
+
+// handleTrashPurge permanently removes trash entries
+func handleTrashPurge(args []string) {
+	fs := flag.NewFlagSet("trash purge", flag.ExitOnError)
+	all := fs.Bool("all", false, "Purge every item, not just expired ones")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck trash purge [id] [options]")
+		fmt.Println()
+		fmt.Println("Permanently delete trashed items. Without arguments, only expired items are removed.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+
+	purged := 0
+	switch {
+	case fs.Arg(0) != "":
+		if err := session.PurgeTrashEntry(fs.Arg(0)); err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(1)
+		}
+		purged = 1
+	case *all:
+		entries, err := session.ListTrash()
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to list trash: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		for _, e := range entries {
+			if err := session.PurgeTrashEntry(e.ID); err == nil {
+				purged++
+			}
+		}
+	default:
+		n, err := session.PurgeExpiredTrash()
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to purge trash: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		purged = n
+	}
+
+	out.Success(
+		fmt.Sprintf("Purged %d item(s) from trash", purged),
+		map[string]interface{}{
+			"success": true,
+			"purged":  purged,
+		},
+	)
+}
@@ -142,6 +142,12 @@ func handleTry(profile string, args []string) {
 		}
 	}
 
+	spawnConductor, err := checkSpawnPolicy(storage, instances, detectTool(selectedTool), "")
+	if err != nil {
+		reportSpawnError(out, err)
+		os.Exit(1)
+	}
+
 	// Create new session
 	newInst := session.NewInstanceWithGroup(exp.Name, exp.Path, "experiments")
 	newInst.Command = selectedTool
@@ -154,6 +160,7 @@ func handleTry(profile string, args []string) {
 		out.Error(err.Error(), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	recordSpawn(storage, spawnConductor, newInst)
 
 	// Start the session
 	if err := newInst.Start(); err != nil {
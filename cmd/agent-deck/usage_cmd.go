@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleUsage prints token usage and estimated cost per session, conductor
+// or profile, broken down by day or week
+func handleUsage(profile string, args []string) {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	period := fs.String("period", session.UsagePeriodDay, "Breakdown period: day or week")
+	by := fs.String("by", session.UsageBySession, "Group by session, conductor or profile")
+	from := fs.String("from", "", "First day to include, YYYY-MM-DD (default: --days before --to)")
+	to := fs.String("to", "", "Last day to include, YYYY-MM-DD (default: today)")
+	days := fs.Int("days", 0, "Number of days to include when --from is not given (default: 7, or 28 by week)")
+	allProfiles := fs.Bool("all-profiles", false, "Include every profile, not just the current one")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck usage [options]")
+		fmt.Println()
+		fmt.Println("Show token usage and estimated cost per day or week, by session, by")
+		fmt.Println("conductor (a conductor and the workers it created) or by profile. Tokens")
+		fmt.Println("are read from Claude and Codex transcripts; cost is estimated at the rates")
+		fmt.Println("of the analytics panel. Weeks start on Monday.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck usage                               # Per session, last 7 days")
+		fmt.Println("  agent-deck usage --by conductor --period week  # Per conductor, last 4 weeks")
+		fmt.Println("  agent-deck usage --by profile --all-profiles --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if *period != session.UsagePeriodDay && *period != session.UsagePeriodWeek {
+		out.Error(fmt.Sprintf("unknown period %q (valid: day, week)", *period), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *days == 0 {
+		*days = 7
+		if *period == session.UsagePeriodWeek {
+			*days = 28
+		}
+	}
+
+	fromDay, toDay, err := reportDayRange(*from, *to, *days)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	rows, err := costReportRows(profile, *allProfiles, fromDay, toDay)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	usage, err := session.SummarizeUsage(rows, *period, *by)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var total session.TokenUsage
+	for _, u := range usage {
+		total.Add(u.TokenUsage)
+	}
+	if *jsonOutput {
+		out.Print("", map[string]any{
+			"from":   fromDay,
+			"to":     toDay,
+			"period": *period,
+			"by":     *by,
+			"rows":   usage,
+			"total":  total,
+		})
+		return
+	}
+
+	if len(usage) == 0 {
+		fmt.Printf("No token usage from %s to %s\n", fromDay, toDay)
+		return
+	}
+	header := "Day"
+	if *period == session.UsagePeriodWeek {
+		header = "Week of"
+	}
+	lastPeriod := ""
+	for _, u := range usage {
+		if u.Period != lastPeriod {
+			if lastPeriod != "" {
+				fmt.Println()
+			}
+			fmt.Printf("%s %s\n", header, u.Period)
+			lastPeriod = u.Period
+		}
+		fmt.Printf("  %-32s %14s tokens  %9s\n", usageRowName(u, *by, *allProfiles), formatTokenCount(u.TotalTokens()), fmt.Sprintf("$%.2f", u.Cost))
+	}
+	fmt.Println()
+	fmt.Printf("  %-32s %14s tokens  %9s\n", fmt.Sprintf("Total (%s to %s)", fromDay, toDay), formatTokenCount(total.TotalTokens()), fmt.Sprintf("$%.2f", total.Cost))
+}
+
+// usageRowName labels a usage row: its group, with the session count for
+// conductors and the profile when several are shown
+func usageRowName(u session.UsageRow, by string, allProfiles bool) string {
+	name := u.Key
+	switch by {
+	case session.UsageByConductor:
+		if name == "" {
+			name = "(no conductor)"
+		}
+		name = fmt.Sprintf("%s (%d sessions)", name, u.Sessions)
+	case session.UsageByProfile:
+		name = fmt.Sprintf("%s (%d sessions)", name, u.Sessions)
+		allProfiles = false
+	}
+	if allProfiles {
+		name = u.Profile + "/" + name
+	}
+	return truncate(name, 32)
+}
+
+// formatTokenCount formats n with thousands separators
+func formatTokenCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
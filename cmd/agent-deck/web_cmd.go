@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/session"
 	"github.com/asheshgoplani/agent-deck/internal/web"
@@ -20,6 +25,10 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader)
 	pushEnabled := fs.Bool("push", false, "Enable web push notifications (auto-generates VAPID keys per profile)")
 	pushVAPIDSubject := fs.String("push-vapid-subject", "mailto:agentdeck@localhost", "VAPID subject used for web push notifications")
 	pushTestEvery := fs.Duration("push-test-every", 0, "Send periodic push test notifications at this interval (e.g. 10s, 1m); 0 disables")
+	headless := fs.Bool("headless", false, "Run only the web server, without the TUI (for daemons and socket activation)")
+	idleExit := fs.Duration("idle-exit", 0, "Exit after this long without requests (e.g. 10m); 0 runs until stopped")
+	grpcListen := fs.String("grpc-listen", "", "Also serve the API over gRPC on this address (e.g. 127.0.0.1:8421)")
+	metricsAddr := fs.String("metrics-addr", "", "Serve Prometheus metrics at /metrics on this address (e.g. 127.0.0.1:9420)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck web [options]")
@@ -35,6 +44,10 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader)
 		fmt.Println("  agent-deck web --read-only")
 		fmt.Println("  agent-deck web --push")
 		fmt.Println("  agent-deck web --push --push-test-every 10s")
+		fmt.Println("  agent-deck web --headless --idle-exit 10m")
+		fmt.Println("  agent-deck web --token secret --grpc-listen 127.0.0.1:8421")
+		fmt.Println("  agent-deck web --headless --metrics-addr 127.0.0.1:9420")
+		fmt.Println("  agent-deck web daemon install          # systemd socket activation")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -52,6 +65,23 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader)
 	if *pushTestEvery > 0 && !*pushEnabled {
 		return nil, fmt.Errorf("--push-test-every requires --push")
 	}
+	if *idleExit < 0 {
+		return nil, fmt.Errorf("--idle-exit must be >= 0")
+	}
+
+	// Under systemd socket activation the listening socket is inherited and
+	// --listen is ignored (the .socket unit owns the address).
+	listeners, err := web.ActivationListeners()
+	if err != nil {
+		return nil, fmt.Errorf("socket activation: %w", err)
+	}
+	var listener net.Listener
+	if len(listeners) > 0 {
+		listener = listeners[0]
+		for _, extra := range listeners[1:] {
+			_ = extra.Close()
+		}
+	}
 
 	effectiveProfile := session.GetEffectiveProfile(profile)
 
@@ -82,7 +112,115 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader)
 		PushVAPIDPrivateKey: resolvedPushPrivate,
 		PushVAPIDSubject:    resolvedPushSubject,
 		PushTestInterval:    *pushTestEvery,
+		Listener:            listener,
+		IdleTimeout:         *idleExit,
+		GRPCListenAddr:      *grpcListen,
+		MetricsListenAddr:   *metricsAddr,
+		WatchPower:          *headless,
 	})
 
 	return server, nil
 }
+
+// webHeadlessRequested reports whether "agent-deck web" args ask for server-only mode.
+func webHeadlessRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == "--headless" || arg == "-headless" || arg == "--headless=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWebHeadless runs the web server in the foreground without the TUI.
+// It returns when the server is stopped by a signal or exits on idle.
+func handleWebHeadless(profile string, args []string) {
+	server, err := buildWebServer(profile, args, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: web server setup failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		if _, ok := <-sigCh; !ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	fmt.Printf("Web server: http://%s\n", server.Addr())
+	if addr := server.GRPCAddr(); addr != "" {
+		fmt.Printf("gRPC:       %s\n", addr)
+	}
+	if addr := server.MetricsAddr(); addr != "" {
+		fmt.Printf("Metrics:    http://%s/metrics\n", addr)
+	}
+	if err := server.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: web server: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handleWebDaemon manages the socket-activated web daemon (systemd only).
+func handleWebDaemon(profile string, args []string) {
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		printWebDaemonHelp()
+		return
+	}
+
+	switch args[0] {
+	case "install":
+		fs := flag.NewFlagSet("web daemon install", flag.ExitOnError)
+		listenAddr := fs.String("listen", "127.0.0.1:8420", "Address the socket unit listens on")
+		idleExit := fs.Duration("idle-exit", session.DefaultWebIdleExit, "Stop the server after this long without requests")
+		fs.Usage = printWebDaemonHelp
+		if err := fs.Parse(normalizeArgs(fs, args[1:])); err != nil {
+			os.Exit(1)
+		}
+		path, err := session.InstallWebSocketDaemon(profile, *listenAddr, *idleExit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("  [ok] Socket unit installed: %s\n", path)
+		fmt.Printf("  [ok] Listening on %s (server starts on first request, exits after %s idle)\n", *listenAddr, idleExit.String())
+	case "uninstall":
+		if err := session.UninstallWebSocketDaemon(profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("  [ok] Web daemon removed")
+	case "status":
+		socketActive, serviceActive := session.WebSocketDaemonState(profile)
+		socketState, serviceState := "inactive", "stopped (starts on first request)"
+		if socketActive {
+			socketState = "listening"
+		}
+		if serviceActive {
+			serviceState = "running"
+		}
+		fmt.Printf("Socket:  %s\n", socketState)
+		fmt.Printf("Server:  %s\n", serviceState)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown web daemon command: %s\n", args[0])
+		printWebDaemonHelp()
+		os.Exit(1)
+	}
+}
+
+func printWebDaemonHelp() {
+	fmt.Println("Usage: agent-deck web daemon <install|uninstall|status> [options]")
+	fmt.Println()
+	fmt.Println("Run the web server on demand via systemd socket activation.")
+	fmt.Println("The socket unit holds the address; the server starts on the first")
+	fmt.Println("connection and exits after --idle-exit without requests.")
+	fmt.Println()
+	fmt.Println("Options (install):")
+	fmt.Println("  --listen <addr>       Address to listen on (default 127.0.0.1:8420)")
+	fmt.Println("  --idle-exit <dur>     Idle time before the server exits (default 10m)")
+}
@@ -0,0 +1,165 @@
+// Package chaos injects simulated failures at configurable rates: tmux
+// capture errors, slow tool startup and failed heartbeats. It is a testing
+// aid, off unless [chaos] is enabled in config.toml or AGENTDECK_CHAOS is
+// set, for validating hooks and alerting and for exercising retry logic.
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+)
+
+// Fault is a kind of simulated failure
+type Fault string
+
+const (
+	// CaptureFailure makes tmux pane captures fail
+	CaptureFailure Fault = "capture"
+	// SlowStart delays the tool command of a new session
+	SlowStart Fault = "slow_start"
+	// HeartbeatFailure makes heartbeat delivery to conductors fail
+	HeartbeatFailure Fault = "heartbeat"
+)
+
+// Faults lists every fault kind
+var Faults = []Fault{CaptureFailure, SlowStart, HeartbeatFailure}
+
+// EnvVar overrides the configured settings, e.g.
+// AGENTDECK_CHAOS="capture=0.2,heartbeat=0.5,slow_start=1,delay=20s".
+// "off" disables chaos even when config.toml enables it.
+const EnvVar = "AGENTDECK_CHAOS"
+
+// DefaultSlowStartDelay is how long SlowStart holds back a tool
+const DefaultSlowStartDelay = 15 * time.Second
+
+// ErrInjected is wrapped by every simulated failure
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Config is the chaos settings: a probability in [0, 1] per fault
+type Config struct {
+	Enabled        bool
+	Rates          map[Fault]float64
+	SlowStartDelay time.Duration
+}
+
+var chaosLog = logging.ForComponent(logging.CompChaos)
+
+var (
+	mu     sync.RWMutex
+	source func() Config
+	roll   = rand.Float64
+)
+
+// SetSource registers the function that reads the configured settings. The
+// session package registers config.toml's [chaos] section.
+func SetSource(fn func() Config) {
+	mu.Lock()
+	source = fn
+	mu.Unlock()
+}
+
+// Current returns the active settings: AGENTDECK_CHAOS when set, otherwise
+// the registered source
+func Current() Config {
+	if env, ok := os.LookupEnv(EnvVar); ok && env != "" {
+		cfg, err := ParseSpec(env)
+		if err != nil {
+			chaosLog.Warn("chaos_env_invalid", slog.String("value", env), slog.String("error", err.Error()))
+			return Config{}
+		}
+		return cfg
+	}
+	mu.RLock()
+	fn := source
+	mu.RUnlock()
+	if fn == nil {
+		return Config{}
+	}
+	return fn()
+}
+
+// ParseSpec parses an AGENTDECK_CHAOS value: comma-separated fault=rate
+// pairs plus an optional delay=<duration> for SlowStart
+func ParseSpec(spec string) (Config, error) {
+	cfg := Config{Rates: make(map[Fault]float64), SlowStartDelay: DefaultSlowStartDelay}
+	spec = strings.TrimSpace(spec)
+	if spec == "off" || spec == "0" {
+		return Config{}, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Config{}, fmt.Errorf("expected key=value, got %q", part)
+		}
+		if key == "delay" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return Config{}, fmt.Errorf("invalid delay %q", value)
+			}
+			cfg.SlowStartDelay = d
+			continue
+		}
+		if !validFault(Fault(key)) {
+			return Config{}, fmt.Errorf("unknown fault %q (want capture, slow_start, heartbeat or delay)", key)
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return Config{}, fmt.Errorf("rate for %s must be between 0 and 1, got %q", key, value)
+		}
+		cfg.Rates[Fault(key)] = rate
+	}
+	cfg.Enabled = true
+	return cfg, nil
+}
+
+func validFault(f Fault) bool {
+	for _, known := range Faults {
+		if f == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Hit reports whether fault should be simulated now, logging when it is
+func Hit(fault Fault) bool {
+	cfg := Current()
+	if !cfg.Enabled {
+		return false
+	}
+	rate := cfg.Rates[fault]
+	if rate <= 0 || roll() >= rate {
+		return false
+	}
+	chaosLog.Info("chaos_injected", slog.String("fault", string(fault)))
+	return true
+}
+
+// Error returns an injected error for fault when it hits, nil otherwise
+func Error(fault Fault) error {
+	if !Hit(fault) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInjected, fault)
+}
+
+// StartDelay returns how long to hold back a new session's tool: the
+// SlowStart delay when that fault hits, 0 otherwise
+func StartDelay() time.Duration {
+	if !Hit(SlowStart) {
+		return 0
+	}
+	if d := Current().SlowStartDelay; d > 0 {
+		return d
+	}
+	return DefaultSlowStartDelay
+}
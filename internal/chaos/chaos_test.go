@@ -0,0 +1,69 @@
+package chaos
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	cfg, err := ParseSpec("capture=0.2, heartbeat=1,delay=20s")
+	if err != nil {
+		t.Fatalf("ParseSpec: %v", err)
+	}
+	if !cfg.Enabled || cfg.Rates[CaptureFailure] != 0.2 || cfg.Rates[HeartbeatFailure] != 1 || cfg.Rates[SlowStart] != 0 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.SlowStartDelay != 20*time.Second {
+		t.Errorf("delay = %v, want 20s", cfg.SlowStartDelay)
+	}
+
+	if cfg, err := ParseSpec("off"); err != nil || cfg.Enabled {
+		t.Errorf("off: %+v, %v", cfg, err)
+	}
+	for _, bad := range []string{"capture", "capture=2", "network=0.5", "delay=-1s", "heartbeat=x"} {
+		if _, err := ParseSpec(bad); err == nil {
+			t.Errorf("ParseSpec(%q) should fail", bad)
+		}
+	}
+}
+
+func TestHitAndError(t *testing.T) {
+	t.Setenv(EnvVar, "")
+	defer SetSource(nil)
+	defer func(orig func() float64) { roll = orig }(roll)
+
+	SetSource(func() Config {
+		return Config{Enabled: true, Rates: map[Fault]float64{CaptureFailure: 0.5}}
+	})
+
+	roll = func() float64 { return 0.4 }
+	if err := Error(CaptureFailure); !errors.Is(err, ErrInjected) {
+		t.Errorf("roll below rate should inject, got %v", err)
+	}
+	if Hit(HeartbeatFailure) {
+		t.Error("fault with rate 0 must never hit")
+	}
+
+	roll = func() float64 { return 0.6 }
+	if Hit(CaptureFailure) {
+		t.Error("roll above rate should not inject")
+	}
+
+	// AGENTDECK_CHAOS wins over the configured source
+	roll = func() float64 { return 0 }
+	t.Setenv(EnvVar, "off")
+	if Hit(CaptureFailure) {
+		t.Error("AGENTDECK_CHAOS=off should disable chaos")
+	}
+	t.Setenv(EnvVar, "slow_start=1,delay=3s")
+	if d := StartDelay(); d != 3*time.Second {
+		t.Errorf("StartDelay = %v, want 3s", d)
+	}
+
+	SetSource(nil)
+	t.Setenv(EnvVar, "")
+	if Hit(CaptureFailure) {
+		t.Error("no source and no env: chaos must be off")
+	}
+}
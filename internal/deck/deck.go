@@ -0,0 +1,203 @@
+// Package deck resolves which agent-deck installation ("deck") the process
+// belongs to. The default deck lives in ~/.agent-deck and uses tmux's default
+// server. A named deck gets its own data directory, tmux socket and
+// systemd/launchd unit names, so a second agent-deck version can run side by
+// side without touching the sessions and conductors of the first.
+package deck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// EnvHome overrides the data directory (default ~/.agent-deck)
+	EnvHome = "AGENT_DECK_HOME"
+	// EnvName names the deck. When only EnvHome is set, the name is derived
+	// from the directory name.
+	EnvName = "AGENT_DECK_NAME"
+
+	// DefaultName selects the default deck in Use and --deck
+	DefaultName = "default"
+
+	defaultDirName = ".agent-deck"
+)
+
+// Info describes a deck found on disk
+type Info struct {
+	Name    string `json:"name"` // "default" for the default deck
+	Home    string `json:"home"`
+	Current bool   `json:"current"`
+}
+
+// Name returns the deck name, "" for the default deck
+func Name() string {
+	if name := sanitize(os.Getenv(EnvName)); name != "" {
+		if name == DefaultName {
+			return ""
+		}
+		return name
+	}
+	home := strings.TrimSpace(os.Getenv(EnvHome))
+	if home == "" {
+		return ""
+	}
+	if userHome, err := os.UserHomeDir(); err == nil && filepath.Clean(home) == filepath.Join(userHome, defaultDirName) {
+		return ""
+	}
+	// ~/.agent-deck-v2 -> "v2", /srv/deck-next -> "deck-next"
+	base := strings.TrimPrefix(filepath.Base(filepath.Clean(home)), ".")
+	if rest := strings.TrimPrefix(base, "agent-deck"); rest != base {
+		base = strings.TrimLeft(rest, "-_.")
+	}
+	if name := sanitize(base); name != "" {
+		return name
+	}
+	return "custom"
+}
+
+// IsDefault reports whether this is the default deck
+func IsDefault() bool {
+	return Name() == ""
+}
+
+// Home returns the deck's data directory: AGENT_DECK_HOME when set,
+// ~/.agent-deck-<name> for a named deck, ~/.agent-deck otherwise
+func Home() (string, error) {
+	if home := strings.TrimSpace(os.Getenv(EnvHome)); home != "" {
+		return filepath.Abs(home)
+	}
+	userHome, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	if name := Name(); name != "" {
+		return filepath.Join(userHome, defaultDirName+"-"+name), nil
+	}
+	return filepath.Join(userHome, defaultDirName), nil
+}
+
+// TmuxSocket returns the tmux socket name (tmux -L) of the deck, "" for the
+// default deck, which uses tmux's default server
+func TmuxSocket() string {
+	if name := Name(); name != "" {
+		return "agent-deck-" + name
+	}
+	return ""
+}
+
+// UnitPrefix returns the prefix of systemd unit names: "agent-deck" or
+// "agent-deck-<name>"
+func UnitPrefix() string {
+	if name := Name(); name != "" {
+		return "agent-deck-" + name
+	}
+	return "agent-deck"
+}
+
+// LabelPrefix returns the prefix of launchd labels: "com.agentdeck" or
+// "com.agentdeck.<name>"
+func LabelPrefix() string {
+	if name := Name(); name != "" {
+		return "com.agentdeck." + name
+	}
+	return "com.agentdeck"
+}
+
+// Env returns the variables ("KEY=value") that select this deck, for
+// daemons and hooks started outside this process. Empty for the default deck.
+func Env() []string {
+	name := Name()
+	if name == "" {
+		return nil
+	}
+	env := []string{EnvName + "=" + name}
+	if home, err := Home(); err == nil {
+		env = append(env, EnvHome+"="+home)
+	}
+	return env
+}
+
+// Use selects the deck for this process and the commands and daemons it
+// starts, the same as setting AGENT_DECK_NAME. "default" (or "") selects the
+// default deck. It must run before any state or config is loaded.
+func Use(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == DefaultName {
+		_ = os.Unsetenv(EnvName)
+		return os.Unsetenv(EnvHome)
+	}
+	if sanitize(name) != name {
+		return fmt.Errorf("invalid deck name %q: use letters, digits, '-' and '_'", name)
+	}
+	if err := os.Unsetenv(EnvHome); err != nil {
+		return err
+	}
+	return os.Setenv(EnvName, name)
+}
+
+// List returns the decks on disk: the default deck (~/.agent-deck) and the
+// named decks in ~/.agent-deck-<name>, plus the current deck when its
+// AGENT_DECK_HOME is elsewhere. Decks are sorted by name, default first.
+func List() ([]Info, error) {
+	userHome, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	current, err := Home()
+	if err != nil {
+		return nil, err
+	}
+	var decks []Info
+	add := func(name, home string) {
+		decks = append(decks, Info{Name: name, Home: home, Current: home == current})
+	}
+	if info, err := os.Stat(filepath.Join(userHome, defaultDirName)); err == nil && info.IsDir() {
+		add(DefaultName, filepath.Join(userHome, defaultDirName))
+	}
+	entries, err := os.ReadDir(userHome)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", userHome, err)
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), defaultDirName+"-")
+		if !ok || !entry.IsDir() || sanitize(name) != name || name == DefaultName {
+			continue
+		}
+		add(name, filepath.Join(userHome, entry.Name()))
+	}
+	if !slices.ContainsFunc(decks, func(d Info) bool { return d.Current }) {
+		name := Name()
+		if name == "" {
+			name = DefaultName
+		}
+		add(name, current)
+	}
+	slices.SortStableFunc(decks, func(a, b Info) int {
+		if (a.Name == DefaultName) != (b.Name == DefaultName) {
+			if a.Name == DefaultName {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return decks, nil
+}
+
+// sanitize keeps names usable in socket, unit and label names
+func sanitize(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
@@ -0,0 +1,135 @@
+package deck
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDefaultDeck(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvHome, "")
+	t.Setenv(EnvName, "")
+
+	if !IsDefault() {
+		t.Fatalf("Name() = %q, want default deck", Name())
+	}
+	dir, err := Home()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".agent-deck"); dir != want {
+		t.Errorf("Home() = %q, want %q", dir, want)
+	}
+	if TmuxSocket() != "" || UnitPrefix() != "agent-deck" || LabelPrefix() != "com.agentdeck" || Env() != nil {
+		t.Errorf("default deck must keep the default socket and unit names: %q %q %q %v", TmuxSocket(), UnitPrefix(), LabelPrefix(), Env())
+	}
+
+	// Pointing AGENT_DECK_HOME at the default directory is still the default deck
+	t.Setenv(EnvHome, filepath.Join(home, ".agent-deck"))
+	if !IsDefault() {
+		t.Errorf("Name() = %q for the default directory, want default deck", Name())
+	}
+}
+
+func TestNamedDeck(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvHome, "")
+	t.Setenv(EnvName, "v2")
+
+	dir, err := Home()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".agent-deck-v2"); dir != want {
+		t.Errorf("Home() = %q, want %q", dir, want)
+	}
+	if got := TmuxSocket(); got != "agent-deck-v2" {
+		t.Errorf("TmuxSocket() = %q", got)
+	}
+	if got := UnitPrefix(); got != "agent-deck-v2" {
+		t.Errorf("UnitPrefix() = %q", got)
+	}
+	if got := LabelPrefix(); got != "com.agentdeck.v2" {
+		t.Errorf("LabelPrefix() = %q", got)
+	}
+	env := Env()
+	if len(env) != 2 || env[0] != "AGENT_DECK_NAME=v2" || env[1] != "AGENT_DECK_HOME="+dir {
+		t.Errorf("Env() = %v", env)
+	}
+}
+
+func TestNameFromHome(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvName, "")
+
+	tests := []struct {
+		home string
+		want string
+	}{
+		{"/srv/.agent-deck-next", "next"},
+		{"/srv/agent-deck_beta", "beta"},
+		{"/srv/deck two", "deck-two"},
+		{"/srv/.agent-deck.d", "d"},
+		{"/srv/agent-deck", "custom"},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvHome, tt.home)
+		if got := Name(); got != tt.want {
+			t.Errorf("Name() with %s=%q = %q, want %q", EnvHome, tt.home, got, tt.want)
+		}
+	}
+
+	// An explicit name wins and is sanitized
+	t.Setenv(EnvName, "my deck/1")
+	if got := Name(); got != "my-deck-1" {
+		t.Errorf("Name() = %q, want %q", got, "my-deck-1")
+	}
+}
+
+func TestUseAndList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvHome, "/elsewhere/.agent-deck-old")
+	t.Setenv(EnvName, "")
+	for _, dir := range []string{".agent-deck", ".agent-deck-work", ".agent-deck-oss", ".agent-deck-bad name", ".agent-deck-default"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Use("bad/name"); err == nil {
+		t.Error("Use should reject names that are not usable in socket and unit names")
+	}
+	if err := Use("work"); err != nil {
+		t.Fatal(err)
+	}
+	if Name() != "work" || os.Getenv(EnvHome) != "" {
+		t.Fatalf("Use(work): Name() = %q, %s = %q", Name(), EnvHome, os.Getenv(EnvHome))
+	}
+
+	decks, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range decks {
+		got = append(got, d.Name)
+		if d.Current != (d.Name == "work") {
+			t.Errorf("deck %s: Current = %v", d.Name, d.Current)
+		}
+	}
+	if want := []string{"default", "oss", "work"}; !slices.Equal(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+
+	if err := Use(DefaultName); err != nil {
+		t.Fatal(err)
+	}
+	if !IsDefault() {
+		t.Errorf("Use(default): Name() = %q", Name())
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Worktree represents a git worktree
@@ -426,3 +428,69 @@ func PruneWorktrees(repoDir string) error {
 	}
 	return nil
 }
+
+// DiffAgainstBase returns the changes in dir (committed and uncommitted) since
+// it diverged from baseBranch, as a unified diff
+func DiffAgainstBase(dir, baseBranch string) (string, error) {
+	cmd := exec.Command("git", "-C", dir, "merge-base", baseBranch, "HEAD")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base with %s: %s: %w", baseBranch, strings.TrimSpace(string(output)), err)
+	}
+	mergeBase := strings.TrimSpace(string(output))
+
+	cmd = exec.Command("git", "-C", dir, "diff", mergeBase)
+	output, err = cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff against %s: %w", baseBranch, err)
+	}
+	return string(output), nil
+}
+
+// FetchPullRequest fetches GitHub pull request number from origin into the
+// local branch, replacing it if it exists
+func FetchPullRequest(repoDir string, number int, branchName string) error {
+	refspec := fmt.Sprintf("+pull/%d/head:%s", number, branchName)
+	cmd := exec.Command("git", "-C", repoDir, "fetch", "origin", refspec)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to fetch pull request #%d: %s: %w", number, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// Commit is one commit as listed by CommitsSince
+type Commit struct {
+	Hash    string
+	Author  string
+	Time    time.Time
+	Subject string
+}
+
+// CommitsSince returns the commits reachable from HEAD in dir that were
+// committed after since, newest first
+func CommitsSince(dir string, since time.Time) ([]Commit, error) {
+	cmd := exec.Command("git", "-C", dir, "log", "--since=@"+strconv.FormatInt(since.Unix(), 10),
+		"--format=%H%x1f%an%x1f%ct%x1f%s")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	return parseCommitLog(string(output)), nil
+}
+
+func parseCommitLog(output string) []Commit {
+	var commits []Commit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		secs, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, Commit{Hash: fields[0], Author: fields[1], Time: time.Unix(secs, 0), Subject: fields[3]})
+	}
+	return commits
+}
@@ -1155,3 +1155,45 @@ func TestIntegration_WorktreeNesting(t *testing.T) {
 	t.Logf("Correct path:  %s", actualWt2)
 	t.Logf("Wrong path:    %s (would have been nested)", wrongWt2)
 }
+
+func TestDiffAgainstBase(t *testing.T) {
+	dir := t.TempDir()
+	createTestRepo(t, dir)
+	base, err := GetCurrentBranch(dir)
+	if err != nil {
+		t.Fatalf("failed to get branch: %v", err)
+	}
+
+	cmd := exec.Command("git", "checkout", "-b", "feature-diff")
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "committed.txt"), []byte("committed\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	cmd = exec.Command("git", "add", ".")
+	cmd.Dir = dir
+	_ = cmd.Run()
+	cmd = exec.Command("git", "commit", "-m", "feature commit")
+	cmd.Dir = dir
+	_ = cmd.Run()
+	// Uncommitted work is part of what gets reviewed
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	diff, err := DiffAgainstBase(dir, base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"diff --git a/committed.txt", "+# Changed"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+
+	if _, err := DiffAgainstBase(dir, "no-such-branch"); err == nil {
+		t.Error("expected error for unknown base branch")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"sync"
 
@@ -12,18 +13,38 @@ import (
 
 // Component constants for structured logging.
 const (
-	CompStatus  = "status"
-	CompMCP     = "mcp"
-	CompNotif   = "notif"
-	CompPerf    = "perf"
-	CompUI      = "ui"
-	CompSession = "session"
-	CompStorage = "storage"
-	CompPool    = "pool"
-	CompHTTP    = "http"
-	CompWeb     = "web"
+	CompStatus    = "status"
+	CompMCP       = "mcp"
+	CompNotif     = "notif"
+	CompPerf      = "perf"
+	CompUI        = "ui"
+	CompSession   = "session"
+	CompStorage   = "storage"
+	CompPool      = "pool"
+	CompHTTP      = "http"
+	CompWeb       = "web"
+	CompChaos     = "chaos"
+	CompConductor = "conductor"
+	CompTmux      = "tmux"
+	CompPipe      = "pipe"
+	CompHeartbeat = "heartbeat"
+	CompPower     = "power"
 )
 
+// Log outputs, set with Config.Output
+const (
+	OutputFile   = "file"
+	OutputStderr = "stderr"
+)
+
+// ValidOutput reports whether output names a supported log output
+func ValidOutput(output string) bool {
+	return output == OutputFile || output == OutputStderr
+}
+
+// stderr is where OutputStderr writes (a var for tests)
+var stderr io.Writer = os.Stderr
+
 // Config holds logging configuration.
 type Config struct {
 	// LogDir is the directory for log files (e.g. ~/.agent-deck)
@@ -35,6 +56,11 @@ type Config struct {
 	// Format is "json" (default) or "text"
 	Format string
 
+	// Output is "file" (default: LogDir/debug.log) or "stderr". Stderr
+	// output works without Debug or LogDir, e.g. for daemons under a
+	// supervisor that collects it.
+	Output string
+
 	// MaxSizeMB is the max size in MB before rotation (default: 10)
 	MaxSizeMB int
 
@@ -58,6 +84,13 @@ type Config struct {
 
 	// Debug indicates whether debug mode is active
 	Debug bool
+
+	// System also sends logs to the host's log service: "journald" or "syslog".
+	// It works without Debug or LogDir, e.g. for CLI commands.
+	System string
+
+	// SystemLevel is the minimum level sent to System (default: "info")
+	SystemLevel string
 }
 
 var (
@@ -66,6 +99,7 @@ var (
 	globalAgg    *Aggregator
 	globalMu     sync.RWMutex
 	lumberjackW  *lumberjack.Logger
+	systemOut    systemEmitter
 )
 
 // Init initializes the global logging system.
@@ -91,40 +125,65 @@ func Init(cfg Config) {
 		cfg.AggregateIntervalSecs = 30
 	}
 
-	// Parse level
-	level := slog.LevelInfo
-	switch cfg.Level {
-	case "debug":
-		level = slog.LevelDebug
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
+	level := parseLevel(cfg.Level)
+
+	// Re-initializing (the TUI does, on top of the CLI setup) replaces the
+	// previous writers
+	if globalAgg != nil {
+		globalAgg.Stop()
+		globalAgg = nil
+	}
+	if lumberjackW != nil {
+		lumberjackW.Close()
+		lumberjackW = nil
+	}
+
+	// The system sink is optional: when the log service is unreachable,
+	// logging carries on with the file alone
+	var sysHandler slog.Handler
+	if systemOut != nil {
+		_ = systemOut.Close()
+		systemOut = nil
+	}
+	if cfg.System != "" {
+		if out, err := openSystemSink(cfg.System); err == nil {
+			systemOut = out
+			sysHandler = newSystemHandler(out, parseLevel(cfg.SystemLevel))
+		}
 	}
 
 	// If not in debug mode and no explicit log dir, discard everything
-	if !cfg.Debug && cfg.LogDir == "" {
+	// except what goes to the system sink
+	toStderr := cfg.Output == OutputStderr
+	if !toStderr && !cfg.Debug && cfg.LogDir == "" {
 		globalLogger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+		if sysHandler != nil {
+			globalLogger = slog.New(sysHandler)
+		}
 		globalRing = NewRingBuffer(1024) // minimal
 		globalAgg = NewAggregator(nil, cfg.AggregateIntervalSecs)
 		return
 	}
 
-	// Set up lumberjack for rotation
-	logPath := filepath.Join(cfg.LogDir, "debug.log")
-	lumberjackW = &lumberjack.Logger{
-		Filename:   logPath,
-		MaxSize:    cfg.MaxSizeMB,
-		MaxBackups: cfg.MaxBackups,
-		MaxAge:     cfg.MaxAgeDays,
-		Compress:   cfg.Compress,
-	}
-
 	// Ring buffer for crash dumps
 	globalRing = NewRingBuffer(cfg.RingBufferSize)
 
-	// MultiWriter: lumberjack + ring buffer
-	multi := io.MultiWriter(lumberjackW, globalRing)
+	// MultiWriter: lumberjack (or stderr) + ring buffer
+	var multi io.Writer
+	if toStderr {
+		multi = io.MultiWriter(stderr, globalRing)
+	} else {
+		// Set up lumberjack for rotation
+		logPath := filepath.Join(cfg.LogDir, "debug.log")
+		lumberjackW = &lumberjack.Logger{
+			Filename:   logPath,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+			Compress:   cfg.Compress,
+		}
+		multi = io.MultiWriter(lumberjackW, globalRing)
+	}
 
 	// Create handler
 	handlerOpts := &slog.HandlerOptions{
@@ -137,6 +196,9 @@ func Init(cfg Config) {
 	} else {
 		handler = slog.NewJSONHandler(multi, handlerOpts)
 	}
+	if sysHandler != nil {
+		handler = fanoutHandler{handler, sysHandler}
+	}
 
 	globalLogger = slog.New(handler)
 
@@ -150,6 +212,20 @@ func Init(cfg Config) {
 	}
 }
 
+// parseLevel maps "debug", "warn" and "error" to their levels; anything
+// else is info
+func parseLevel(name string) slog.Level {
+	switch name {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
 // Logger returns the global logger. Safe to call before Init (returns default).
 func Logger() *slog.Logger {
 	globalMu.RLock()
@@ -243,6 +319,10 @@ func Shutdown() {
 		lumberjackW.Close()
 		lumberjackW = nil
 	}
+	if systemOut != nil {
+		_ = systemOut.Close()
+		systemOut = nil
+	}
 	globalLogger = nil
 	globalRing = nil
 }
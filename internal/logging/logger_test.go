@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -171,6 +172,35 @@ func TestTextFormat(t *testing.T) {
 	}
 }
 
+func TestStderrOutput(t *testing.T) {
+	Shutdown()
+
+	var buf bytes.Buffer
+	old := stderr
+	stderr = &buf
+	defer func() { stderr = old }()
+
+	// Stderr output needs neither Debug nor LogDir
+	Init(Config{Output: OutputStderr, Level: "info"})
+	defer Shutdown()
+
+	log := ForComponent(CompHeartbeat)
+	log.Debug("filtered_out")
+	log.Info("heartbeat", "conductor", "ops")
+
+	data := buf.Bytes()
+	if containsMsg(data, "filtered_out") {
+		t.Error("debug record written at info level")
+	}
+	var record map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(data), &record); err != nil {
+		t.Fatalf("expected one JSON record on stderr, got %q: %v", data, err)
+	}
+	if record["component"] != CompHeartbeat || record["conductor"] != "ops" {
+		t.Errorf("unexpected record %v", record)
+	}
+}
+
 func TestDumpRingBuffer(t *testing.T) {
 	Shutdown()
 
@@ -0,0 +1,40 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"log/syslog"
+)
+
+// syslogWriter sends records to the local syslog daemon
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+func dialSyslog() (systemEmitter, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, SyslogIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("connect to syslog: %w", err)
+	}
+	return &syslogWriter{w: w}, nil
+}
+
+func (s *syslogWriter) emit(level slog.Level, msg string, fields []systemField) error {
+	line := formatSyslogLine(msg, fields)
+	switch syslogPriority(level) {
+	case 3:
+		return s.w.Err(line)
+	case 4:
+		return s.w.Warning(line)
+	case 6:
+		return s.w.Info(line)
+	default:
+		return s.w.Debug(line)
+	}
+}
+
+func (s *syslogWriter) Close() error {
+	return s.w.Close()
+}
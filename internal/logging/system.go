@@ -0,0 +1,262 @@
+package logging
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+)
+
+// System log sinks, set with [logs] system_sink
+const (
+	SinkJournald = "journald"
+	SinkSyslog   = "syslog"
+)
+
+// SyslogIdentifier tags every system log entry, so
+// "journalctl --user -t agent-deck" finds them
+const SyslogIdentifier = "agent-deck"
+
+// journalSocket is journald's native protocol socket (a var for tests)
+var journalSocket = "/run/systemd/journal/socket"
+
+// ValidSystemSink reports whether sink names a supported system log sink
+func ValidSystemSink(sink string) bool {
+	return sink == SinkJournald || sink == SinkSyslog
+}
+
+// systemField is one flattened record attribute
+type systemField struct {
+	key   string
+	value string
+}
+
+// systemEmitter delivers a record to the host's log service
+type systemEmitter interface {
+	io.Closer
+	emit(level slog.Level, msg string, fields []systemField) error
+}
+
+// openSystemSink connects to the host's log service
+func openSystemSink(sink string) (systemEmitter, error) {
+	switch sink {
+	case SinkJournald:
+		return dialJournal()
+	case SinkSyslog:
+		return dialSyslog()
+	default:
+		return nil, fmt.Errorf("unknown system log sink %q (want %s or %s)", sink, SinkJournald, SinkSyslog)
+	}
+}
+
+// systemHandler is a slog.Handler that flattens records into key/value
+// fields for a systemEmitter. Groups become dotted key prefixes.
+type systemHandler struct {
+	level  slog.Leveler
+	out    systemEmitter
+	fields []systemField
+	prefix string
+}
+
+func newSystemHandler(out systemEmitter, level slog.Leveler) *systemHandler {
+	return &systemHandler{level: level, out: out}
+}
+
+func (h *systemHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *systemHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]systemField, len(h.fields), len(h.fields)+r.NumAttrs())
+	copy(fields, h.fields)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSystemFields(fields, h.prefix, a)
+		return true
+	})
+	return h.out.emit(r.Level, r.Message, fields)
+}
+
+func (h *systemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]systemField, len(h.fields), len(h.fields)+len(attrs))
+	copy(fields, h.fields)
+	for _, a := range attrs {
+		fields = appendSystemFields(fields, h.prefix, a)
+	}
+	return &systemHandler{level: h.level, out: h.out, fields: fields, prefix: h.prefix}
+}
+
+func (h *systemHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &systemHandler{level: h.level, out: h.out, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+func appendSystemFields(fields []systemField, prefix string, a slog.Attr) []systemField {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		sub := prefix
+		if a.Key != "" {
+			sub += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSystemFields(fields, sub, ga)
+		}
+		return fields
+	}
+	if a.Key == "" {
+		return fields
+	}
+	return append(fields, systemField{key: prefix + a.Key, value: a.Value.String()})
+}
+
+// fanoutHandler sends each record to every handler that accepts its level
+type fanoutHandler []slog.Handler
+
+func (f fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanoutHandler) WithGroup(name string) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// journalWriter speaks journald's native protocol, so attributes arrive as
+// structured fields (COMPONENT, SESSION_ID, ...) rather than message text
+type journalWriter struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func dialJournal() (systemEmitter, error) {
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return nil, fmt.Errorf("connect to journald: %w", err)
+	}
+	return &journalWriter{conn: conn}, nil
+}
+
+func (w *journalWriter) emit(level slog.Level, msg string, fields []systemField) error {
+	var b strings.Builder
+	writeJournalField(&b, "MESSAGE", msg)
+	writeJournalField(&b, "PRIORITY", fmt.Sprint(syslogPriority(level)))
+	writeJournalField(&b, "SYSLOG_IDENTIFIER", SyslogIdentifier)
+	for _, f := range fields {
+		if key := journalFieldName(f.key); key != "" {
+			writeJournalField(&b, key, f.value)
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.conn.Write([]byte(b.String()))
+	return err
+}
+
+func (w *journalWriter) Close() error {
+	return w.conn.Close()
+}
+
+// writeJournalField appends KEY=value, or the length-prefixed form the
+// protocol requires for values containing newlines
+func writeJournalField(b *strings.Builder, key, value string) {
+	if !strings.Contains(value, "\n") {
+		b.WriteString(key + "=" + value + "\n")
+		return
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	b.WriteString(key + "\n")
+	b.Write(size[:])
+	b.WriteString(value + "\n")
+}
+
+// journalFieldName maps an attribute key to a journald field name: upper
+// case letters, digits and underscores, not starting with an underscore
+// (reserved for trusted fields) or a digit
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" {
+		return ""
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "F_" + name
+	}
+	switch name {
+	case "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER":
+		name = "ATTR_" + name
+	}
+	return name
+}
+
+// syslogPriority maps a slog level to a syslog severity
+func syslogPriority(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// formatSyslogLine renders a record as "msg key=value ..." for syslog,
+// which has no structured fields
+func formatSyslogLine(msg string, fields []systemField) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		value := f.value
+		if value == "" || strings.ContainsAny(value, " \"=\n") {
+			value = fmt.Sprintf("%q", value)
+		}
+		b.WriteString(" " + f.key + "=" + value)
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournaldSink(t *testing.T) {
+	dir, err := os.MkdirTemp("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sock, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	orig := journalSocket
+	journalSocket = sock
+	defer func() { journalSocket = orig }()
+
+	// No debug and no log dir: only the system sink receives records
+	Shutdown()
+	Init(Config{System: SinkJournald, SystemLevel: "info"})
+	defer Shutdown()
+
+	log := ForComponent(CompConductor)
+	log.Debug("below_level")
+	log.Warn("heartbeat", slog.String("conductor", "ops"), slog.Group("run", slog.Int("n", 2)), slog.String("error", "line1\nline2"))
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("no journal entry: %v", err)
+	}
+	entry := string(buf[:n])
+	for _, want := range []string{"MESSAGE=heartbeat\n", "PRIORITY=4\n", "SYSLOG_IDENTIFIER=agent-deck\n", "COMPONENT=conductor\n", "CONDUCTOR=ops\n", "RUN_N=2\n", "ERROR\n"} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("journal entry missing %q:\n%q", want, entry)
+		}
+	}
+	if strings.Contains(entry, "below_level") {
+		t.Error("debug record sent below system_level")
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	for key, want := range map[string]string{
+		"session_id": "SESSION_ID",
+		"run.n":      "RUN_N",
+		"_private":   "PRIVATE",
+		"2fa":        "F_2FA",
+		"message":    "ATTR_MESSAGE",
+		"__":         "",
+	} {
+		if got := journalFieldName(key); got != want {
+			t.Errorf("journalFieldName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestUnknownSystemSinkFallsBack(t *testing.T) {
+	Shutdown()
+	Init(Config{Debug: true, LogDir: t.TempDir(), System: "eventlog"})
+	defer Shutdown()
+	Logger().Info("still_logged")
+	if systemOut != nil {
+		t.Error("unknown sink should not be opened")
+	}
+}
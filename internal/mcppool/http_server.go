@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
 )
 
@@ -141,7 +142,8 @@ func (s *HTTPServer) Start() error {
 	s.mu.Unlock()
 
 	// Create log file
-	logDir := filepath.Join(os.Getenv("HOME"), ".agent-deck", "logs", "http-servers")
+	deckDir, _ := deck.Home()
+	logDir := filepath.Join(deckDir, "logs", "http-servers")
 	_ = os.MkdirAll(logDir, 0755)
 	s.logFile = filepath.Join(logDir, fmt.Sprintf("%s.log", s.name))
 
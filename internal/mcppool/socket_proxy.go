@@ -15,6 +15,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
 )
 
@@ -145,7 +146,8 @@ func (p *SocketProxy) Start() error {
 		return nil
 	}
 
-	logDir := filepath.Join(os.Getenv("HOME"), ".agent-deck", "logs", "mcppool")
+	deckDir, _ := deck.Home()
+	logDir := filepath.Join(deckDir, "logs", "mcppool")
 	_ = os.MkdirAll(logDir, 0755)
 	p.logFile = filepath.Join(logDir, fmt.Sprintf("%s_socket.log", p.name))
 
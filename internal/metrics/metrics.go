@@ -0,0 +1,191 @@
+// Package metrics writes metrics in the Prometheus text exposition format
+// (version 0.0.4) without pulling in a client library. Collectors run at
+// scrape time, so gauges read current state instead of being kept up to
+// date; the few in-process measurements (e.g. status detection latency)
+// live in package-level Histograms.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Label is a metric label. Samples keep labels in the order given.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one value of a metric family.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Writer renders metric families. Write errors are remembered and returned
+// by Flush, so collectors needn't check each call.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter returns a Writer rendering to w; call Flush when done.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Gauge writes a gauge family.
+func (w *Writer) Gauge(name, help string, samples ...Sample) {
+	w.family(name, help, "gauge", samples)
+}
+
+// Counter writes a counter family. By convention name ends in "_total".
+func (w *Writer) Counter(name, help string, samples ...Sample) {
+	w.family(name, help, "counter", samples)
+}
+
+func (w *Writer) family(name, help, kind string, samples []Sample) {
+	w.header(name, help, kind)
+	for _, s := range samples {
+		w.sample(name, s.Labels, s.Value)
+	}
+}
+
+func (w *Writer) header(name, help, kind string) {
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", name, helpEscaper.Replace(help), name, kind)
+}
+
+func (w *Writer) sample(name string, labels []Label, value float64) {
+	w.printf("%s%s %s\n", name, formatLabels(labels), formatValue(value))
+}
+
+func (w *Writer) printf(format string, args ...any) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, args...)
+	}
+}
+
+// Flush writes any buffered output and returns the first error.
+func (w *Writer) Flush() error {
+	if w.err == nil {
+		w.err = w.w.Flush()
+	}
+	return w.err
+}
+
+// Collector writes some metric families at scrape time.
+type Collector func(w *Writer)
+
+// Handler serves the metrics of collectors, in order.
+func Handler(collectors ...Collector) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", ContentType)
+		w := NewWriter(rw)
+		for _, collect := range collectors {
+			collect(w)
+		}
+		_ = w.Flush()
+	})
+}
+
+// Histogram counts observations into cumulative buckets. Safe for
+// concurrent use.
+type Histogram struct {
+	mu      sync.Mutex
+	bounds  []float64
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// NewHistogram returns a histogram with the given bucket upper bounds (in
+// any order; +Inf is implied).
+func NewHistogram(bounds ...float64) *Histogram {
+	bounds = append([]float64(nil), bounds...)
+	sort.Float64s(bounds)
+	return &Histogram{bounds: bounds, buckets: make([]uint64, len(bounds))}
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.buckets[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Write writes the histogram as a family named name.
+func (h *Histogram) Write(w *Writer, name, help string) {
+	h.mu.Lock()
+	buckets := append([]uint64(nil), h.buckets...)
+	count, sum := h.count, h.sum
+	h.mu.Unlock()
+
+	w.header(name, help, "histogram")
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += buckets[i]
+		w.sample(name+"_bucket", []Label{{"le", formatValue(bound)}}, float64(cumulative))
+	}
+	w.sample(name+"_bucket", []Label{{"le", "+Inf"}}, float64(count))
+	w.sample(name+"_sum", nil, sum)
+	w.sample(name+"_count", nil, float64(count))
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l.Name)
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(l.Value))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerWritesFamilies(t *testing.T) {
+	h := NewHistogram(0.1, 0.01)
+	h.Observe(0.005)
+	h.Observe(0.05)
+	h.Observe(2)
+
+	handler := Handler(func(w *Writer) {
+		w.Gauge("test_sessions", "Sessions by status.",
+			Sample{Labels: []Label{{"status", "running"}}, Value: 2},
+			Sample{Labels: []Label{{"status", `we"ird`}}, Value: 0},
+		)
+		w.Counter("test_forks_total", "Forks.\nSecond line.", Sample{Value: 3})
+		h.Write(w, "test_latency_seconds", "Latency.")
+	})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rr.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	want := `# HELP test_sessions Sessions by status.
+# TYPE test_sessions gauge
+test_sessions{status="running"} 2
+test_sessions{status="we\"ird"} 0
+# HELP test_forks_total Forks.\nSecond line.
+# TYPE test_forks_total counter
+test_forks_total 3
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.01"} 1
+test_latency_seconds_bucket{le="0.1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 2.055
+test_latency_seconds_count 3
+`
+	if got := rr.Body.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
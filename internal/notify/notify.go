@@ -0,0 +1,136 @@
+// Package notify sends short chat messages to Slack and Discord incoming
+// webhooks. Messages are plain text rendered from text/template templates,
+// so callers decide what an event looks like and senders only deliver it.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+// Sender delivers a rendered message
+type Sender interface {
+	// Name identifies the sender in errors ("slack", "discord")
+	Name() string
+	Send(ctx context.Context, text string) error
+}
+
+// Slack posts to a Slack incoming webhook (https://hooks.slack.com/services/...)
+type Slack struct {
+	URL    string
+	Client *http.Client // nil = http.DefaultClient
+}
+
+// Name implements Sender
+func (s *Slack) Name() string { return "slack" }
+
+// Send implements Sender
+func (s *Slack) Send(ctx context.Context, text string) error {
+	return postJSON(ctx, s.Client, s.Name(), s.URL, map[string]string{"text": text})
+}
+
+// Discord posts to a Discord webhook (https://discord.com/api/webhooks/...)
+type Discord struct {
+	URL    string
+	Client *http.Client // nil = http.DefaultClient
+}
+
+// discordMaxContent is the longest message Discord accepts
+const discordMaxContent = 2000
+
+// Name implements Sender
+func (d *Discord) Name() string { return "discord" }
+
+// Send implements Sender
+func (d *Discord) Send(ctx context.Context, text string) error {
+	if len([]rune(text)) > discordMaxContent {
+		text = string([]rune(text)[:discordMaxContent-1]) + "…"
+	}
+	return postJSON(ctx, d.Client, d.Name(), d.URL, map[string]any{
+		"content":  text,
+		"username": "agent-deck",
+		// Never ping @everyone or roles from event text
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	})
+}
+
+// ValidateWebhookURL checks that raw is an https URL with a host
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("webhook URL must be https://host/...")
+	}
+	return nil
+}
+
+// postJSON POSTs body and fails on a non-2xx status. Webhook URLs embed
+// their secret, so errors name the host only.
+func postJSON(ctx context.Context, client *http.Client, name, target string, body any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: invalid webhook URL", name)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "agent-deck")
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s webhook to %s: %w", name, req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s webhook to %s: HTTP %d", name, req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+// Render executes a text/template with data. Missing keys render as
+// "<no value>" rather than failing, so a template written for one event
+// still works for another.
+func Render(tmpl string, data any) (string, error) {
+	t, err := Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Parse compiles a message template, with the helper functions available
+// to templates: upper, lower and truncate N
+func Parse(tmpl string) (*template.Template, error) {
+	t, err := template.New("message").Funcs(template.FuncMap{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"truncate": func(n int, s string) string {
+			if r := []rune(s); len(r) > n {
+				return string(r[:n]) + "…"
+			}
+			return s
+		},
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+	return t, nil
+}
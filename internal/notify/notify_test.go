@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendersPostTheirPayloads(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := (&Slack{URL: srv.URL}).Send(context.Background(), "hello"); err != nil {
+		t.Fatalf("slack: %v", err)
+	}
+	if got["text"] != "hello" {
+		t.Errorf("slack payload = %v", got)
+	}
+
+	if err := (&Discord{URL: srv.URL}).Send(context.Background(), strings.Repeat("x", 3000)); err != nil {
+		t.Fatalf("discord: %v", err)
+	}
+	content, _ := got["content"].(string)
+	if n := len([]rune(content)); n != discordMaxContent {
+		t.Errorf("discord content has %d runes, want it cut to %d", n, discordMaxContent)
+	}
+	if _, ok := got["allowed_mentions"]; !ok {
+		t.Error("discord payload should disable mentions")
+	}
+}
+
+func TestSendErrorHidesWebhookToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := (&Slack{URL: srv.URL + "/services/T0/B0/secret-token"}).Send(context.Background(), "hi")
+	if err == nil || !strings.Contains(err.Error(), "HTTP 403") {
+		t.Fatalf("expected an HTTP 403 error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks the webhook token: %v", err)
+	}
+
+	err = (&Discord{URL: "http://127.0.0.1:1/api/webhooks/1/secret-token"}).Send(context.Background(), "hi")
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("connection error should fail without the token, got %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	data := struct{ Name, Text string }{"ops", "a very long message"}
+	got, err := Render(`{{upper .Name}}: {{truncate 6 .Text}}`, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "OPS: a very…" {
+		t.Errorf("Render = %q", got)
+	}
+	if _, err := Render(`{{.Name`, data); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	for raw, ok := range map[string]bool{
+		"https://hooks.slack.com/services/T0/B0/X": true,
+		"http://hooks.slack.com/services/T0/B0/X":  false,
+		"hooks.slack.com/services":                 false,
+		"https://":                                 false,
+	} {
+		if err := ValidateWebhookURL(raw); (err == nil) != ok {
+			t.Errorf("ValidateWebhookURL(%q) = %v", raw, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package power
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Battery is the machine's power source and charge
+type Battery struct {
+	// OnBattery is true when no external power is connected
+	OnBattery bool `json:"on_battery"`
+
+	// Percent is the remaining charge, 0-100
+	Percent int `json:"percent"`
+}
+
+// ReadBattery returns the power source and charge. ok is false on machines
+// without a battery (desktops, servers, VMs) or where it can't be read.
+func ReadBattery() (battery Battery, ok bool) {
+	return readBattery()
+}
+
+// pmsetPercent matches the charge in "pmset -g batt" output
+var pmsetPercent = regexp.MustCompile(`(\d+)%;`)
+
+// parsePmset reads "pmset -g batt" output:
+//
+//	Now drawing from 'Battery Power'
+//	 -InternalBattery-0 (id=4653155)	85%; discharging; 4:12 remaining present: true
+func parsePmset(out string) (Battery, bool) {
+	lines := strings.Split(out, "\n")
+	for _, line := range lines {
+		if !strings.Contains(line, "InternalBattery") {
+			continue
+		}
+		m := pmsetPercent.FindStringSubmatch(line)
+		if m == nil {
+			return Battery{}, false
+		}
+		percent, _ := strconv.Atoi(m[1])
+		return Battery{
+			OnBattery: strings.Contains(lines[0], "'Battery Power'"),
+			Percent:   percent,
+		}, true
+	}
+	return Battery{}, false
+}
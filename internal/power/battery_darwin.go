@@ -0,0 +1,14 @@
+//go:build darwin
+
+package power
+
+import "os/exec"
+
+// readBattery asks pmset, which reports the internal battery only
+func readBattery() (Battery, bool) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return Battery{}, false
+	}
+	return parsePmset(string(out))
+}
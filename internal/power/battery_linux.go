@@ -0,0 +1,59 @@
+//go:build linux
+
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// powerSupplyDir is where the kernel lists power supplies (a var for tests)
+var powerSupplyDir = "/sys/class/power_supply"
+
+// readBattery reads the system batteries and AC adapters from sysfs.
+// Device-scoped batteries (mice, keyboards) are ignored. With several
+// batteries the charge is their average.
+func readBattery() (Battery, bool) {
+	entries, err := os.ReadDir(powerSupplyDir)
+	if err != nil {
+		return Battery{}, false
+	}
+	var acOnline, discharging bool
+	var total, batteries int
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyDir, entry.Name())
+		switch readSupplyFile(dir, "type") {
+		case "Mains", "USB":
+			if readSupplyFile(dir, "online") == "1" {
+				acOnline = true
+			}
+		case "Battery":
+			if readSupplyFile(dir, "scope") == "Device" {
+				continue
+			}
+			capacity, err := strconv.Atoi(readSupplyFile(dir, "capacity"))
+			if err != nil {
+				continue
+			}
+			total += capacity
+			batteries++
+			if readSupplyFile(dir, "status") == "Discharging" {
+				discharging = true
+			}
+		}
+	}
+	if batteries == 0 {
+		return Battery{}, false
+	}
+	return Battery{OnBattery: discharging && !acOnline, Percent: total / batteries}, true
+}
+
+func readSupplyFile(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
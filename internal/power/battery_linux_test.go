@@ -0,0 +1,48 @@
+//go:build linux
+
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSupply(t *testing.T, dir, name string, files map[string]string) {
+	t.Helper()
+	supply := filepath.Join(dir, name)
+	if err := os.MkdirAll(supply, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for file, value := range files {
+		if err := os.WriteFile(filepath.Join(supply, file), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadBatterySysfs(t *testing.T) {
+	dir := t.TempDir()
+	old := powerSupplyDir
+	powerSupplyDir = dir
+	defer func() { powerSupplyDir = old }()
+
+	if _, ok := readBattery(); ok {
+		t.Fatal("expected no battery in an empty power_supply dir")
+	}
+
+	writeSupply(t, dir, "AC", map[string]string{"type": "Mains", "online": "0"})
+	writeSupply(t, dir, "BAT0", map[string]string{"type": "Battery", "capacity": "18", "status": "Discharging"})
+	writeSupply(t, dir, "hidpp_battery_0", map[string]string{"type": "Battery", "scope": "Device", "capacity": "90", "status": "Discharging"})
+
+	battery, ok := readBattery()
+	if !ok || battery != (Battery{OnBattery: true, Percent: 18}) {
+		t.Fatalf("readBattery = %+v, %v; want on battery at 18%%", battery, ok)
+	}
+
+	writeSupply(t, dir, "AC", map[string]string{"online": "1"})
+	writeSupply(t, dir, "BAT0", map[string]string{"status": "Charging"})
+	if battery, _ := readBattery(); battery.OnBattery {
+		t.Errorf("expected AC power, got %+v", battery)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package power
+
+// readBattery has no source on this platform
+func readBattery() (Battery, bool) {
+	return Battery{}, false
+}
@@ -0,0 +1,37 @@
+package power
+
+import "testing"
+
+func TestParsePmset(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want Battery
+		ok   bool
+	}{
+		{
+			name: "on battery",
+			out:  "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t22%; discharging; 1:40 remaining present: true\n",
+			want: Battery{OnBattery: true, Percent: 22},
+			ok:   true,
+		},
+		{
+			name: "charging",
+			out:  "Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t85%; charging; 0:45 remaining present: true\n",
+			want: Battery{Percent: 85},
+			ok:   true,
+		},
+		{
+			name: "desktop",
+			out:  "Now drawing from 'AC Power'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parsePmset(tt.out)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parsePmset = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
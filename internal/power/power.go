@@ -0,0 +1,80 @@
+// Package power reports system suspend and resume (laptop sleep), so status
+// tracking and heartbeats can tell a sleeping machine from idle sessions,
+// and the battery state, so background work can back off on battery.
+// On Linux it follows logind's PrepareForSleep signal; elsewhere, or without
+// a system bus, it notices the wall clock running ahead of the monotonic
+// clock, which stops while the machine sleeps.
+package power
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// clockInterval is how often the clock fallback compares the clocks
+	clockInterval = 5 * time.Second
+
+	// minSleep is the shortest clock jump taken for a suspend; shorter
+	// ones are scheduling delays or NTP adjustments
+	minSleep = 30 * time.Second
+)
+
+// Sleep is one suspend, from when the machine went to sleep until it
+// resumed. Times carry no monotonic reading, so they compare as wall times.
+type Sleep struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns how long the machine slept
+func (s Sleep) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Watch calls onResume after each suspend until ctx is done. It blocks, so
+// run it in a goroutine.
+func Watch(ctx context.Context, onResume func(Sleep)) {
+	if watchPlatform(ctx, onResume) {
+		return
+	}
+	watchClock(ctx, onResume)
+}
+
+// watchClock is the portable fallback: the monotonic clock does not advance
+// during suspend on Linux or macOS, so a tick whose wall time ran ahead of
+// it spans a sleep
+func watchClock(ctx context.Context, onResume func(Sleep)) {
+	start := time.Now()
+	clock := clockWatch{wall: start.Round(0)}
+	ticker := time.NewTicker(clockInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if sleep, ok := clock.check(now.Round(0), now.Sub(start)); ok {
+				onResume(sleep)
+			}
+		}
+	}
+}
+
+// clockWatch remembers the previous tick's wall time and monotonic offset
+type clockWatch struct {
+	wall time.Time
+	mono time.Duration
+}
+
+// check records a tick at wall time wall, mono after the watch started, and
+// returns the sleep since the previous tick, if any. The sleep is taken to
+// end at this tick, since timers fire right after resume.
+func (c *clockWatch) check(wall time.Time, mono time.Duration) (Sleep, bool) {
+	slept := wall.Sub(c.wall) - (mono - c.mono)
+	c.wall, c.mono = wall, mono
+	if slept < minSleep {
+		return Sleep{}, false
+	}
+	return Sleep{Start: wall.Add(-slept), End: wall}, true
+}
@@ -0,0 +1,85 @@
+//go:build linux
+
+package power
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	logindDest      = "org.freedesktop.login1"
+	logindPath      = "/org/freedesktop/login1"
+	logindInterface = "org.freedesktop.login1.Manager"
+)
+
+// watchPlatform follows logind's PrepareForSleep signal, holding a delay
+// inhibitor lock so the suspend time is noted before the machine sleeps. It
+// returns false when logind is unreachable (containers, WSL, no system bus).
+func watchPlatform(ctx context.Context, onResume func(Sleep)) bool {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface(logindInterface),
+		dbus.WithMatchMember("PrepareForSleep"),
+	); err != nil {
+		return false
+	}
+	signals := make(chan *dbus.Signal, 4)
+	conn.Signal(signals)
+
+	lock := inhibitSleep(conn)
+	defer func() { releaseLock(lock) }()
+
+	var suspended time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case sig, ok := <-signals:
+			if !ok {
+				return true
+			}
+			if sig.Name != logindInterface+".PrepareForSleep" || len(sig.Body) == 0 {
+				continue
+			}
+			sleeping, _ := sig.Body[0].(bool)
+			now := time.Now().Round(0)
+			if sleeping {
+				suspended = now
+				releaseLock(lock)
+				lock = -1
+				continue
+			}
+			if !suspended.IsZero() {
+				onResume(Sleep{Start: suspended, End: now})
+			}
+			suspended = time.Time{}
+			lock = inhibitSleep(conn)
+		}
+	}
+}
+
+// inhibitSleep takes a logind delay lock, returning its fd or -1
+func inhibitSleep(conn *dbus.Conn) int {
+	var fd dbus.UnixFD
+	err := conn.Object(logindDest, logindPath).Call(logindInterface+".Inhibit", 0,
+		"sleep", "agent-deck", "Record suspend time for status tracking", "delay").Store(&fd)
+	if err != nil {
+		return -1
+	}
+	return int(fd)
+}
+
+// releaseLock lets a pending suspend proceed
+func releaseLock(fd int) {
+	if fd >= 0 {
+		_ = syscall.Close(fd)
+	}
+}
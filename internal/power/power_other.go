@@ -0,0 +1,11 @@
+//go:build !linux
+
+package power
+
+import "context"
+
+// watchPlatform has no native source here (IOKit needs cgo), so Watch
+// falls back to comparing clocks
+func watchPlatform(ctx context.Context, onResume func(Sleep)) bool {
+	return false
+}
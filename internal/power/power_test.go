@@ -0,0 +1,35 @@
+package power
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockWatchDetectsSleep(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	clock := clockWatch{wall: start}
+
+	// Regular tick: both clocks advance together
+	if _, ok := clock.check(start.Add(5*time.Second), 5*time.Second); ok {
+		t.Fatal("regular tick reported as sleep")
+	}
+	// A few seconds of drift are not a sleep
+	if _, ok := clock.check(start.Add(12*time.Second), 10*time.Second); ok {
+		t.Fatal("small drift reported as sleep")
+	}
+
+	// Next tick: the wall clock ran an hour ahead of the monotonic clock
+	woke := start.Add(time.Hour + 17*time.Second)
+	sleep, ok := clock.check(woke, 15*time.Second)
+	if !ok {
+		t.Fatal("sleep not detected")
+	}
+	if sleep.Duration() != time.Hour || !sleep.End.Equal(woke) {
+		t.Errorf("sleep = %v (%v), want 1h ending at %v", sleep, sleep.Duration(), woke)
+	}
+
+	// Clocks agree again afterwards
+	if _, ok := clock.check(woke.Add(5*time.Second), 20*time.Second); ok {
+		t.Error("tick after resume reported as sleep")
+	}
+}
@@ -0,0 +1,292 @@
+// Package remote manages agent-deck sessions on other machines over SSH.
+// Every request runs the remote host's own agent-deck (or tmux) through ssh,
+// sharing one multiplexed connection per host (ControlMaster), so listing,
+// creating and polling sessions costs a round trip, not a handshake.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultControlPersist keeps an idle multiplexed connection open between
+// commands
+const defaultControlPersist = 10 * time.Minute
+
+// Host is a machine running agent-deck, reached with ssh
+type Host struct {
+	// Name is the host's name in config.toml
+	Name string
+	// Dest is the ssh destination: "box", "me@box" or an ~/.ssh/config alias
+	Dest string
+	// Port and IdentityFile are passed to ssh when set
+	Port         int
+	IdentityFile string
+	// AgentDeck is the remote agent-deck binary (default "agent-deck"); a
+	// leading ~/ is the remote home
+	AgentDeck string
+	// Deck and Profile select the remote deck and profile (default: the
+	// remote's defaults)
+	Deck    string
+	Profile string
+	// TmuxSocket overrides the remote tmux server for attaching: a socket
+	// name (tmux -L) or, with a slash, a path (tmux -S). Default: the deck's
+	// socket
+	TmuxSocket string
+	// ControlDir holds the multiplexing sockets; empty disables multiplexing
+	ControlDir string
+	// ControlPersist is how long an idle connection stays open
+	ControlPersist time.Duration
+
+	ssh string // ssh binary, replaced in tests
+}
+
+// Session is a session on a remote host, as its "agent-deck list --json"
+// reports it
+type Session struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Path      string    `json:"path"`
+	Group     string    `json:"group"`
+	Tool      string    `json:"tool"`
+	Status    string    `json:"status"`
+	Profile   string    `json:"profile"`
+	CreatedAt time.Time `json:"created_at"`
+	StateSecs int64     `json:"state_secs,omitempty"`
+}
+
+// sshArgs returns the options shared by every connection to the host
+func (h *Host) sshArgs() []string {
+	args := []string{"-o", "ConnectTimeout=10"}
+	if h.ControlDir != "" {
+		persist := h.ControlPersist
+		if persist <= 0 {
+			persist = defaultControlPersist
+		}
+		args = append(args,
+			"-o", "ControlMaster=auto",
+			"-o", "ControlPath="+filepath.Join(h.ControlDir, "%C"),
+			"-o", fmt.Sprintf("ControlPersist=%d", int(persist.Seconds())),
+		)
+	}
+	if h.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(h.Port))
+	}
+	if h.IdentityFile != "" {
+		args = append(args, "-i", h.IdentityFile)
+	}
+	return args
+}
+
+// command returns ssh running the remote shell command line; tty requests
+// a terminal for interactive use
+func (h *Host) command(ctx context.Context, tty bool, remoteCmd string) *exec.Cmd {
+	if h.ControlDir != "" {
+		// ssh does not create the directory of its control sockets
+		_ = os.MkdirAll(h.ControlDir, 0o700)
+	}
+	args := h.sshArgs()
+	if tty {
+		args = append(args, "-t")
+	}
+	args = append(args, "--", h.Dest, remoteCmd)
+	bin := h.ssh
+	if bin == "" {
+		bin = "ssh"
+	}
+	return exec.CommandContext(ctx, bin, args...)
+}
+
+// AgentDeckCommand returns ssh running the remote agent-deck with args, in
+// the host's deck and profile
+func (h *Host) AgentDeckCommand(ctx context.Context, args ...string) *exec.Cmd {
+	return h.command(ctx, false, h.agentDeckLine(args))
+}
+
+func (h *Host) agentDeckLine(args []string) string {
+	bin := h.AgentDeck
+	if bin == "" {
+		bin = "agent-deck"
+	}
+	words := []string{remotePath(bin)}
+	if h.Deck != "" {
+		words = append(words, shellQuote("--deck="+h.Deck))
+	}
+	if h.Profile != "" {
+		words = append(words, shellQuote("--profile="+h.Profile))
+	}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// AttachCommand returns ssh attaching the terminal to a remote tmux session
+func (h *Host) AttachCommand(tmuxSession string) *exec.Cmd {
+	words := []string{"tmux"}
+	if socket := h.tmuxSocket(); strings.Contains(socket, "/") {
+		words = append(words, "-S", remotePath(socket))
+	} else if socket != "" {
+		words = append(words, "-L", shellQuote(socket))
+	}
+	words = append(words, "attach-session", "-t", shellQuote("="+tmuxSession))
+	return h.command(context.Background(), true, strings.Join(words, " "))
+}
+
+// tmuxSocket returns the remote tmux server: TmuxSocket, else the deck's
+// socket (see deck.TmuxSocket), else "" for tmux's default server
+func (h *Host) tmuxSocket() string {
+	if h.TmuxSocket != "" {
+		return h.TmuxSocket
+	}
+	if h.Deck != "" && h.Deck != "default" {
+		return "agent-deck-" + h.Deck
+	}
+	return ""
+}
+
+// run runs the remote agent-deck and returns its output; a failure carries
+// the remote error message
+func (h *Host) run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := h.AgentDeckCommand(ctx, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(string(out))
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", h.Name, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", h.Name, err)
+	}
+	return out, nil
+}
+
+// ListSessions returns the sessions on the host with their status
+func (h *Host) ListSessions(ctx context.Context) ([]Session, error) {
+	out, err := h.run(ctx, "list", "--json")
+	if err != nil {
+		return nil, err
+	}
+	// "No sessions found." on a host that has none
+	if !bytes.HasPrefix(bytes.TrimSpace(out), []byte("[")) {
+		return nil, nil
+	}
+	var sessions []Session
+	if err := json.Unmarshal(out, &sessions); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse sessions: %w", h.Name, err)
+	}
+	return sessions, nil
+}
+
+// TmuxSessionName resolves a session (title, ID or ID prefix) on the host to
+// the name of its running tmux session
+func (h *Host) TmuxSessionName(ctx context.Context, identifier string) (string, error) {
+	out, err := h.run(ctx, "session", "show", identifier, "--json")
+	if err != nil {
+		return "", err
+	}
+	var shown struct {
+		Title       string `json:"title"`
+		TmuxSession string `json:"tmux_session"`
+	}
+	if err := json.Unmarshal(out, &shown); err != nil {
+		return "", fmt.Errorf("%s: failed to parse session: %w", h.Name, err)
+	}
+	if shown.TmuxSession == "" {
+		return "", fmt.Errorf("%s: session %q is not running (start it with: agent-deck remote run %s session start %s)",
+			h.Name, identifier, h.Name, identifier)
+	}
+	return shown.TmuxSession, nil
+}
+
+// Disconnect closes the host's multiplexed connection, if one is open
+func (h *Host) Disconnect() error {
+	if h.ControlDir == "" {
+		return nil
+	}
+	bin := h.ssh
+	if bin == "" {
+		bin = "ssh"
+	}
+	args := append(h.sshArgs(), "-O", "exit", "--", h.Dest)
+	out, err := exec.Command(bin, args...).CombinedOutput()
+	if err != nil && !strings.Contains(string(out), "No such file") && !strings.Contains(string(out), "Control socket connect") {
+		return fmt.Errorf("%s: %s", h.Name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Change is a remote session's status change between two polls
+type Change struct {
+	Session Session `json:"session"`
+	From    string  `json:"from,omitempty"` // "" for a new session
+	To      string  `json:"to,omitempty"`   // "" for a removed session
+}
+
+// Diff returns the status changes from prev to next, in next's order with
+// removed sessions last
+func Diff(prev, next []Session) []Change {
+	before := make(map[string]Session, len(prev))
+	for _, s := range prev {
+		before[s.ID] = s
+	}
+	var changes []Change
+	for _, s := range next {
+		old, ok := before[s.ID]
+		delete(before, s.ID)
+		if !ok || old.Status != s.Status {
+			changes = append(changes, Change{Session: s, From: old.Status, To: s.Status})
+		}
+	}
+	for _, s := range prev {
+		if _, gone := before[s.ID]; gone {
+			changes = append(changes, Change{Session: s, From: s.Status})
+		}
+	}
+	return changes
+}
+
+// Watch polls the host's sessions every interval until ctx is done, calling
+// fn with each result. Polls share the multiplexed connection.
+func (h *Host) Watch(ctx context.Context, interval time.Duration, fn func([]Session, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		sessions, err := h.ListSessions(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		fn(sessions, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shellQuote single-quotes s for the remote shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
+// remotePath quotes a remote path, leaving a leading ~/ to the remote
+// shell's home
+func remotePath(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		return `"$HOME"/` + shellQuote(rest)
+	}
+	return shellQuote(p)
+}
@@ -0,0 +1,104 @@
+package remote
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeHost returns a host whose ssh runs the remote command line locally,
+// with a fake agent-deck that answers list and session show
+func fakeHost(t *testing.T) *Host {
+	t.Helper()
+	dir := t.TempDir()
+	ssh := filepath.Join(dir, "ssh")
+	// Skip options up to "--" and the destination, then run the command line
+	script := `#!/bin/sh
+while [ "$1" != "--" ]; do shift; done
+shift 2
+exec sh -c "$1"
+`
+	agentDeck := `#!/bin/sh
+echo "$@" >> "$(dirname "$0")/calls"
+case "$3" in
+list) echo '[{"id":"a1","title":"api","tool":"claude","status":"running"},{"id":"b2","title":"it'"'"'s","tool":"codex","status":"waiting"}]' ;;
+session) [ "$5" = api ] && echo '{"title":"api","tmux_session":"agentdeck_api_1234"}' || echo '{"title":"docs"}' ;;
+esac
+`
+	for name, body := range map[string]string{"ssh": script, "agent-deck": agentDeck} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &Host{Name: "box", Dest: "me@box", AgentDeck: filepath.Join(dir, "agent-deck"), Deck: "work", Profile: "ops", ssh: ssh}
+}
+
+func TestListSessions(t *testing.T) {
+	h := fakeHost(t)
+	sessions, err := h.ListSessions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].Status != "running" || sessions[1].Title != "it's" {
+		t.Errorf("sessions = %+v", sessions)
+	}
+	calls, _ := os.ReadFile(filepath.Join(filepath.Dir(h.AgentDeck), "calls"))
+	if got := strings.TrimSpace(string(calls)); got != "--deck=work --profile=ops list --json" {
+		t.Errorf("remote agent-deck called with %q", got)
+	}
+}
+
+func TestTmuxSessionName(t *testing.T) {
+	h := fakeHost(t)
+	name, err := h.TmuxSessionName(context.Background(), "api")
+	if err != nil || name != "agentdeck_api_1234" {
+		t.Errorf("TmuxSessionName(api) = %q, %v", name, err)
+	}
+	if _, err := h.TmuxSessionName(context.Background(), "docs"); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Errorf("TmuxSessionName(docs) err = %v, want not running", err)
+	}
+}
+
+func TestCommandLines(t *testing.T) {
+	h := &Host{Name: "box", Dest: "me@box", Port: 2222, AgentDeck: "~/bin/agent-deck", ControlDir: t.TempDir()}
+	if got := h.agentDeckLine([]string{"launch", "-t", "it's"}); got != `"$HOME"/'bin/agent-deck' 'launch' '-t' 'it'\''s'` {
+		t.Errorf("agentDeckLine = %s", got)
+	}
+
+	args := h.AttachCommand("agentdeck_api_1234").Args
+	for _, want := range []string{"-t", "-p", "ControlMaster=auto", "me@box"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("attach args %q lack %q", args, want)
+		}
+	}
+	if last := args[len(args)-1]; last != `tmux attach-session -t '=agentdeck_api_1234'` {
+		t.Errorf("attach runs %q", last)
+	}
+
+	h.Deck = "work"
+	if last := h.AttachCommand("s").Args; !strings.HasPrefix(last[len(last)-1], "tmux -L 'agent-deck-work' ") {
+		t.Errorf("deck attach runs %q", last[len(last)-1])
+	}
+	h.TmuxSocket = "/tmp/tmux-1000/custom"
+	if last := h.AttachCommand("s").Args; !strings.HasPrefix(last[len(last)-1], "tmux -S '/tmp/tmux-1000/custom' ") {
+		t.Errorf("socket path attach runs %q", last[len(last)-1])
+	}
+}
+
+func TestDiff(t *testing.T) {
+	prev := []Session{{ID: "a", Status: "running"}, {ID: "b", Status: "idle"}, {ID: "c", Status: "waiting"}}
+	next := []Session{{ID: "a", Status: "waiting"}, {ID: "b", Status: "idle"}, {ID: "d", Status: "running"}}
+	changes := Diff(prev, next)
+	want := []struct{ id, from, to string }{{"a", "running", "waiting"}, {"d", "", "running"}, {"c", "waiting", ""}}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v", changes)
+	}
+	for i, w := range want {
+		if c := changes[i]; c.Session.ID != w.id || c.From != w.from || c.To != w.to {
+			t.Errorf("change %d = %+v, want %+v", i, c, w)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Policies for an adopted session whose title is already in use
+const (
+	ConflictRename = "rename" // adopt it under a free title, "title (2)"
+	ConflictMerge  = "merge"  // attach its tmux session to the existing session
+	ConflictSkip   = "skip"   // leave it untracked
+)
+
+// ValidConflictPolicies lists the accepted conflict policies
+var ValidConflictPolicies = []string{ConflictRename, ConflictMerge, ConflictSkip}
+
+// What happened to each adopted session (see AdoptOutcome.Action)
+const (
+	AdoptAdded   = "added"
+	AdoptRenamed = "renamed"
+	AdoptMerged  = "merged"
+	AdoptSkipped = "skipped"
+)
+
+// AdoptConflict is a session to adopt whose title is already taken
+type AdoptConflict struct {
+	Candidate *Instance
+	Existing  *Instance
+	// Mergeable is false when Existing still has a live tmux session (or
+	// was merged into already), in which case merge falls back to rename
+	Mergeable bool
+}
+
+// ConflictResolver picks a policy (ConflictRename, ConflictMerge or
+// ConflictSkip) for one conflict; unknown answers skip
+type ConflictResolver func(AdoptConflict) string
+
+// AdoptOutcome reports what became of one session to adopt
+type AdoptOutcome struct {
+	// Instance is the adopted session, or the existing one it was merged into
+	Instance *Instance
+	// Title is the title the session was discovered with
+	Title  string
+	Action string
+	// Note explains a fallback, e.g. a merge that became a rename
+	Note string
+}
+
+// ResolveAdoptConflicts decides, in order, how each candidate joins
+// existing. Candidates with a free title are added; for the others resolve
+// picks rename, merge or skip, so one duplicate never blocks the rest.
+// Titles taken by earlier candidates count as conflicts too. Merging moves
+// the candidate's tmux session into the existing instance, which is modified
+// in place; nothing else is changed, and callers save the result.
+func ResolveAdoptConflicts(existing, candidates []*Instance, resolve ConflictResolver) []AdoptOutcome {
+	byTitle := make(map[string]*Instance, len(existing)+len(candidates))
+	for _, inst := range existing {
+		if _, ok := byTitle[inst.Title]; !ok {
+			byTitle[inst.Title] = inst
+		}
+	}
+
+	merged := make(map[*Instance]bool)
+
+	outcomes := make([]AdoptOutcome, 0, len(candidates))
+	for _, cand := range candidates {
+		outcome := AdoptOutcome{Instance: cand, Title: cand.Title, Action: AdoptAdded}
+		if taken := byTitle[cand.Title]; taken != nil {
+			conflict := AdoptConflict{Candidate: cand, Existing: taken, Mergeable: !merged[taken] && canMergeInto(taken)}
+			policy := resolve(conflict)
+			if policy == ConflictMerge && !conflict.Mergeable {
+				policy = ConflictRename
+				outcome.Note = fmt.Sprintf("%q already has a tmux session", taken.Title)
+			}
+			switch policy {
+			case ConflictRename:
+				cand.Title = freeTitle(byTitle, cand.Title)
+				outcome.Action = AdoptRenamed
+			case ConflictMerge:
+				taken.tmuxSession = cand.tmuxSession
+				taken.lastErrorCheck = time.Time{} // recheck a ghost session now
+				_ = taken.UpdateStatus()
+				merged[taken] = true
+				outcome.Instance = taken
+				outcome.Action = AdoptMerged
+			default:
+				outcome.Action = AdoptSkipped
+			}
+		}
+		if outcome.Action == AdoptAdded || outcome.Action == AdoptRenamed {
+			byTitle[cand.Title] = cand
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes
+}
+
+// AdoptedInstances returns the new instances (added or renamed) of outcomes
+func AdoptedInstances(outcomes []AdoptOutcome) []*Instance {
+	var adopted []*Instance
+	for _, o := range outcomes {
+		if o.Action == AdoptAdded || o.Action == AdoptRenamed {
+			adopted = append(adopted, o.Instance)
+		}
+	}
+	return adopted
+}
+
+// IsValidConflictPolicy reports whether policy is one of ValidConflictPolicies
+func IsValidConflictPolicy(policy string) bool {
+	return slices.Contains(ValidConflictPolicies, policy)
+}
+
+// canMergeInto reports whether inst has no live tmux session to lose
+func canMergeInto(inst *Instance) bool {
+	tmuxSess := inst.GetTmuxSession()
+	return tmuxSess == nil || !tmuxSess.Exists()
+}
+
+// freeTitle returns the first of "title (2)", "title (3)", ... not taken
+func freeTitle(byTitle map[string]*Instance, title string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", title, n)
+		if byTitle[candidate] == nil {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func TestResolveAdoptConflicts(t *testing.T) {
+	newCandidates := func() []*Instance {
+		return []*Instance{
+			{ID: "c1", Title: "fresh", tmuxSession: &tmux.Session{Name: "fresh"}},
+			{ID: "c2", Title: "api", tmuxSession: &tmux.Session{Name: "agentdeck_api_1234abcd"}},
+			{ID: "c3", Title: "api", tmuxSession: &tmux.Session{Name: "api"}},
+		}
+	}
+
+	tests := []struct {
+		policy      string
+		wantActions []string
+		wantTitles  []string
+	}{
+		{ConflictSkip, []string{AdoptAdded, AdoptSkipped, AdoptSkipped}, []string{"fresh"}},
+		{ConflictRename, []string{AdoptAdded, AdoptRenamed, AdoptRenamed}, []string{"fresh", "api (2)", "api (3)"}},
+		// The second candidate is merged into the existing session; the third
+		// cannot be merged into it as well and is renamed
+		{ConflictMerge, []string{AdoptAdded, AdoptMerged, AdoptRenamed}, []string{"fresh", "api (2)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			existing := []*Instance{{ID: "e1", Title: "api"}}
+			outcomes := ResolveAdoptConflicts(existing, newCandidates(), func(AdoptConflict) string { return tt.policy })
+			for i, o := range outcomes {
+				if o.Action != tt.wantActions[i] {
+					t.Errorf("outcome %d (%s): action %s, want %s", i, o.Title, o.Action, tt.wantActions[i])
+				}
+			}
+			var titles []string
+			for _, inst := range AdoptedInstances(outcomes) {
+				titles = append(titles, inst.Title)
+			}
+			if len(titles) != len(tt.wantTitles) {
+				t.Fatalf("adopted %v, want %v", titles, tt.wantTitles)
+			}
+			for i := range titles {
+				if titles[i] != tt.wantTitles[i] {
+					t.Errorf("adopted %v, want %v", titles, tt.wantTitles)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveAdoptConflictsMergeMovesTmuxSession(t *testing.T) {
+	existing := &Instance{ID: "e1", Title: "api", ProjectPath: "/src/api"}
+	cand := &Instance{ID: "c1", Title: "api", tmuxSession: &tmux.Session{Name: "agentdeck_api_1234abcd"}}
+
+	outcomes := ResolveAdoptConflicts([]*Instance{existing}, []*Instance{cand}, func(c AdoptConflict) string {
+		if !c.Mergeable || c.Existing != existing {
+			t.Errorf("conflict = %+v, want a mergeable conflict with e1", c)
+		}
+		return ConflictMerge
+	})
+	if len(outcomes) != 1 || outcomes[0].Action != AdoptMerged || outcomes[0].Instance != existing {
+		t.Fatalf("outcomes = %+v, want e1 merged", outcomes)
+	}
+	if got := existing.GetTmuxSession(); got == nil || got.Name != "agentdeck_api_1234abcd" {
+		t.Errorf("existing tmux session = %v, want the adopted one", got)
+	}
+	if existing.ProjectPath != "/src/api" {
+		t.Errorf("merge changed the project path to %q", existing.ProjectPath)
+	}
+	if len(AdoptedInstances(outcomes)) != 0 {
+		t.Error("a merged session should not be added as a new instance")
+	}
+}
@@ -0,0 +1,255 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+// AiderSettings defines aider session configuration
+type AiderSettings struct {
+	// Model is passed as --model to new aider sessions (e.g. "sonnet",
+	// "gpt-4o"). If empty, aider picks from the API keys it finds.
+	Model string `toml:"model"`
+
+	// AutoCommits controls aider's commit after every edit. Those commits
+	// are the session's checkpoints (see AiderCheckpoints); false passes
+	// --no-auto-commits. Default: true (nil = aider's default)
+	AutoCommits *bool `toml:"auto_commits"`
+
+	// EnvFile is a .env file specific to aider sessions
+	// Sourced AFTER global [shell].env_files
+	EnvFile string `toml:"env_file"`
+}
+
+// GetAiderSettings returns [aider] from config.toml
+func GetAiderSettings() AiderSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return AiderSettings{}
+	}
+	return config.Aider
+}
+
+// buildAiderCommand builds the command for an aider session
+// aider has no session IDs: its conversation lives in the chat history file
+// (.aider.chat.history.md, or --chat-history-file), which
+// --restore-chat-history replays. A session restores the history once it
+// has written to it, so a new session in a project aider was used in
+// before starts clean.
+// Also sources .env files from [shell].env_files
+func (i *Instance) buildAiderCommand(baseCommand string) string {
+	if i.Tool != "aider" {
+		return baseCommand
+	}
+
+	envPrefix := i.buildEnvSourceCommand()
+
+	// If baseCommand is just "aider", add model, commit and restore flags
+	if baseCommand == "aider" {
+		settings := GetAiderSettings()
+		cmd := "aider"
+		if settings.Model != "" {
+			cmd += " --model " + settings.Model
+		}
+		if settings.AutoCommits != nil && !*settings.AutoCommits {
+			cmd += " --no-auto-commits"
+		}
+		if i.aiderHistoryIsOwn() {
+			cmd += " --restore-chat-history"
+		}
+		return envPrefix + cmd
+	}
+
+	// For custom commands (e.g., forks with their own --chat-history-file), return as-is
+	return envPrefix + baseCommand
+}
+
+// aiderHistoryIsOwn reports whether the chat history was written since the
+// session was created, i.e. holds this session's conversation
+func (i *Instance) aiderHistoryIsOwn() bool {
+	info, err := os.Stat(aiderHistoryFile(i))
+	return err == nil && info.ModTime().After(i.CreatedAt)
+}
+
+// AiderChatMessage is one message of an aider chat history
+type AiderChatMessage struct {
+	Role    string `json:"role"` // user, assistant or tool (aider's own output)
+	Content string `json:"content"`
+}
+
+// ParseAiderChatHistory splits aider's markdown chat history into messages.
+// aider writes the user's input as "#### " lines, its own output (added
+// files, token counts, commits) as "> " quotes and the model's reply as
+// plain markdown; "# aider chat started at ..." opens each run.
+func ParseAiderChatHistory(data string) []AiderChatMessage {
+	var messages []AiderChatMessage
+	var role string
+	var lines []string
+	flush := func() {
+		if content := strings.TrimSpace(strings.Join(lines, "\n")); content != "" {
+			messages = append(messages, AiderChatMessage{Role: role, Content: content})
+		}
+		lines = nil
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		lineRole, text := "assistant", line
+		switch {
+		case strings.HasPrefix(line, "# aider chat started at"):
+			flush()
+			role = ""
+			continue
+		case line == "####" || strings.HasPrefix(line, "#### "):
+			lineRole, text = "user", strings.TrimPrefix(strings.TrimPrefix(line, "####"), " ")
+		case line == ">" || strings.HasPrefix(line, "> "):
+			lineRole, text = "tool", strings.TrimPrefix(strings.TrimPrefix(line, ">"), " ")
+		case strings.TrimSpace(line) == "":
+			// Blank lines belong to whatever message they sit in
+			lines = append(lines, "")
+			continue
+		}
+		if lineRole != role {
+			flush()
+			role = lineRole
+		}
+		lines = append(lines, text)
+	}
+	flush()
+	return messages
+}
+
+// getAiderLastResponse returns the last model reply from the chat history
+func (i *Instance) getAiderLastResponse() (*ResponseOutput, error) {
+	data, err := os.ReadFile(aiderHistoryFile(i))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aider chat history: %w", err)
+	}
+	messages := ParseAiderChatHistory(string(data))
+	for n := len(messages) - 1; n >= 0; n-- {
+		if messages[n].Role == "assistant" {
+			return &ResponseOutput{Tool: "aider", Role: "assistant", Content: messages[n].Content}, nil
+		}
+	}
+	return nil, fmt.Errorf("no assistant response found in chat history")
+}
+
+// AiderCheckpoints returns the commits aider made in the session's project
+// since the session was created, newest first. aider commits after every
+// edit (authored "<name> (aider)"), so each is a checkpoint that /undo or
+// git can roll back to.
+func AiderCheckpoints(inst *Instance) ([]git.Commit, error) {
+	if !git.IsGitRepo(inst.ProjectPath) {
+		return nil, nil
+	}
+	commits, err := git.CommitsSince(inst.ProjectPath, inst.CreatedAt.Add(-time.Second))
+	if err != nil {
+		return nil, err
+	}
+	var checkpoints []git.Commit
+	for _, c := range commits {
+		if strings.HasSuffix(c.Author, "(aider)") || strings.HasPrefix(c.Subject, "aider: ") {
+			checkpoints = append(checkpoints, c)
+		}
+	}
+	return checkpoints, nil
+}
+
+// aiderDefaultHistoryFile is where aider keeps the chat history unless told
+// otherwise, relative to the directory it runs in
+const aiderDefaultHistoryFile = ".aider.chat.history.md"
+
+// aiderHistoryFile returns the chat history aider writes for a session,
+// resolved the way aider resolves its options: the last --chat-history-file
+// of the command, else $AIDER_CHAT_HISTORY_FILE, else chat-history-file in
+// .aider.conf.yml (the project's over the git root's over the home
+// directory's), else .aider.chat.history.md in the project
+func aiderHistoryFile(inst *Instance) string {
+	fields := strings.Fields(inst.Command)
+	path := ""
+	for n, field := range fields {
+		if value, ok := strings.CutPrefix(field, "--chat-history-file="); ok {
+			path = strings.Trim(value, `'"`)
+		}
+		if field == "--chat-history-file" && n+1 < len(fields) {
+			path = strings.Trim(fields[n+1], `'"`)
+		}
+	}
+	if path == "" {
+		path = os.Getenv("AIDER_CHAT_HISTORY_FILE")
+	}
+	if path == "" {
+		path = aiderConfHistoryFile(inst.ProjectPath)
+	}
+	if path == "" {
+		path = aiderDefaultHistoryFile
+	}
+	return resolveAiderPath(inst.ProjectPath, path)
+}
+
+// aiderConfHistoryFile returns chat-history-file from the .aider.conf.yml
+// files aider reads for a project, or "" when none sets it
+func aiderConfHistoryFile(projectPath string) string {
+	dirs := []string{projectPath}
+	if root := aiderGitRoot(projectPath); root != "" && root != projectPath {
+		dirs = append(dirs, root)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(dir, ".aider.conf.yml"))
+		if err != nil {
+			continue
+		}
+		if path := aiderConfValue(string(data), "chat-history-file"); path != "" {
+			return path
+		}
+	}
+	return ""
+}
+
+// aiderConfValue reads a top-level scalar from an aider YAML config. The
+// options agent-deck needs are plain "key: value" lines, so no YAML parser
+// is pulled in for them.
+func aiderConfValue(data, key string) string {
+	for _, line := range strings.Split(data, "\n") {
+		value, ok := strings.CutPrefix(line, key+":")
+		if !ok {
+			continue
+		}
+		if hash := strings.Index(value, " #"); hash >= 0 {
+			value = value[:hash]
+		}
+		return strings.Trim(strings.TrimSpace(value), `'"`)
+	}
+	return ""
+}
+
+// aiderGitRoot finds the git work tree containing dir by looking for .git,
+// without running git: history lookups happen while rendering
+func aiderGitRoot(dir string) string {
+	for dir != "" {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+	return ""
+}
+
+func resolveAiderPath(projectPath, path string) string {
+	path = ExpandPath(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(projectPath, path)
+}
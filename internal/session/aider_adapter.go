@@ -0,0 +1,43 @@
+package session
+
+import (
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// aiderAdapter is the ToolAdapter of aider. aider has no session IDs: a
+// session's conversation is its chat history file (see aiderHistoryFile),
+// which restarts replay with --restore-chat-history, so a session survives
+// a restart of its pane or of tmux itself.
+type aiderAdapter struct{}
+
+func (aiderAdapter) BuildStartCommand(inst *Instance, baseCommand string) string {
+	return inst.buildAiderCommand(baseCommand)
+}
+
+// BuildResumeCommand always restarts in place: buildAiderCommand restores
+// the chat history once the session has written to it
+func (aiderAdapter) BuildResumeCommand(inst *Instance) string {
+	return inst.buildAiderCommand(inst.Command)
+}
+
+// CanResume is always true: a session without history restarts fresh
+func (aiderAdapter) CanResume(inst *Instance) bool {
+	return true
+}
+
+func (aiderAdapter) BusyPatterns() []string {
+	return tmux.DefaultRawPatterns("aider").BusyPatterns
+}
+
+func (aiderAdapter) PromptReadyPatterns() []string {
+	return tmux.DefaultRawPatterns("aider").PromptPatterns
+}
+
+// SupportsFork copies the chat history into a new session (see
+// aiderForkStrategy)
+func (aiderAdapter) SupportsFork(inst *Instance) bool {
+	if inst == nil {
+		return true
+	}
+	return aiderForkStrategy{}.CanFork(inst)
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const aiderHistorySample = `
+# aider chat started at 2026-03-09 09:00:00
+
+> Add main.go to the chat? (Y)es/(N)o [Yes]: y
+
+#### add a --verbose flag
+
+I'll add the flag to main.go.
+
+main.go
+` + "```go" + `
+var verbose = flag.Bool("verbose", false, "verbose output")
+` + "```" + `
+
+> Tokens: 2.1k sent, 120 received.
+> Applied edit to main.go
+> Commit 1a2b3c4 feat: Add --verbose flag
+
+#### now document it
+#### in the README
+
+Added a section to README.md.
+`
+
+func TestParseAiderChatHistory(t *testing.T) {
+	messages := ParseAiderChatHistory(aiderHistorySample)
+	var roles []string
+	for _, m := range messages {
+		roles = append(roles, m.Role)
+	}
+	if got := strings.Join(roles, ","); got != "tool,user,assistant,tool,user,assistant" {
+		t.Fatalf("roles = %s", got)
+	}
+	if messages[1].Content != "add a --verbose flag" {
+		t.Errorf("user message = %q", messages[1].Content)
+	}
+	if !strings.HasPrefix(messages[2].Content, "I'll add the flag") || !strings.Contains(messages[2].Content, "flag.Bool") {
+		t.Errorf("reply = %q", messages[2].Content)
+	}
+	if messages[4].Content != "now document it\nin the README" {
+		t.Errorf("multi-line user message = %q", messages[4].Content)
+	}
+}
+
+func TestAiderSession_CommandAndTranscript(t *testing.T) {
+	writeMacroConfig(t, "[aider]\nmodel = \"sonnet\"\nauto_commits = false\n")
+	project := t.TempDir()
+	inst := &Instance{Tool: "aider", ProjectPath: project, CreatedAt: time.Now().Add(-time.Minute)}
+
+	// A history from before the session is not restored
+	history := filepath.Join(project, ".aider.chat.history.md")
+	if err := os.WriteFile(history, []byte(aiderHistorySample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(history, old, old)
+	if got := inst.buildAiderCommand("aider"); !strings.HasSuffix(got, "aider --model sonnet --no-auto-commits") {
+		t.Errorf("fresh command = %q", got)
+	}
+
+	_ = os.Chtimes(history, time.Now(), time.Now())
+	if got := inst.buildAiderCommand("aider"); !strings.HasSuffix(got, "aider --model sonnet --no-auto-commits --restore-chat-history") {
+		t.Errorf("resume command = %q", got)
+	}
+	if got := inst.buildAiderCommand("aider --chat-history-file x.md"); !strings.HasSuffix(got, "aider --chat-history-file x.md") {
+		t.Errorf("custom command = %q", got)
+	}
+
+	resp, err := inst.GetLastResponse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Tool != "aider" || resp.Content != "Added a section to README.md." {
+		t.Errorf("last response = %+v", resp)
+	}
+}
+
+func TestAiderCheckpoints(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	project := t.TempDir()
+	run := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", project}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	author := func(name string) []string {
+		return []string{"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=dev@example.com",
+			"GIT_COMMITTER_NAME=dev", "GIT_COMMITTER_EMAIL=dev@example.com"}
+	}
+	run(nil, "init", "-q")
+	run(author("dev"), "commit", "-q", "--allow-empty", "-m", "initial")
+	run(author("dev (aider)"), "commit", "-q", "--allow-empty", "-m", "feat: Add --verbose flag")
+	run(author("dev"), "commit", "-q", "--allow-empty", "-m", "manual fix")
+
+	inst := &Instance{Tool: "aider", ProjectPath: project, CreatedAt: time.Now().Add(-time.Minute)}
+	checkpoints, err := AiderCheckpoints(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 1 || checkpoints[0].Subject != "feat: Add --verbose flag" || len(checkpoints[0].Hash) != 40 {
+		t.Errorf("checkpoints = %+v", checkpoints)
+	}
+}
+
+func TestAiderHistoryFile_Discovery(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AIDER_CHAT_HISTORY_FILE", "")
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(repo, "svc")
+	if err := os.Mkdir(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	inst := &Instance{Tool: "aider", ProjectPath: project, Command: "aider"}
+	writeConf := func(dir, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, ".aider.conf.yml"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := aiderHistoryFile(inst); got != filepath.Join(project, ".aider.chat.history.md") {
+		t.Errorf("default history = %q", got)
+	}
+	writeConf(home, "model: sonnet\nchat-history-file: ~/aider-history.md\n")
+	if got := aiderHistoryFile(inst); got != filepath.Join(home, "aider-history.md") {
+		t.Errorf("home config history = %q", got)
+	}
+	writeConf(repo, "chat-history-file: \"repo-history.md\"  # shared\n")
+	if got := aiderHistoryFile(inst); got != filepath.Join(project, "repo-history.md") {
+		t.Errorf("git root config history = %q", got)
+	}
+	writeConf(project, "chat-history-file: .aider/history.md\n")
+	if got := aiderHistoryFile(inst); got != filepath.Join(project, ".aider/history.md") {
+		t.Errorf("project config history = %q", got)
+	}
+	t.Setenv("AIDER_CHAT_HISTORY_FILE", "/var/tmp/env-history.md")
+	if got := aiderHistoryFile(inst); got != "/var/tmp/env-history.md" {
+		t.Errorf("env history = %q", got)
+	}
+	// A fork's command names the parent's file, then its own; aider takes the last
+	inst.Command = "aider --chat-history-file a.md --chat-history-file='b.md' --restore-chat-history"
+	if got := aiderHistoryFile(inst); got != filepath.Join(project, "b.md") {
+		t.Errorf("flag history = %q", got)
+	}
+}
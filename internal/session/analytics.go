@@ -6,6 +6,8 @@ import (
 	"os"
 	"sort"
 	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
 )
 
 // SessionAnalytics holds parsed session metrics from Claude JSONL files
@@ -263,3 +265,86 @@ func CalculateBillingBlocks(timestamps []time.Time, windowSize time.Duration) []
 
 	return blocks
 }
+
+// SpendSince estimates the cost of assistant turns in a Claude session JSONL
+// file at or after since, using default pricing like the analytics panel.
+func SpendSince(path string, since time.Time) (float64, error) {
+	usage, err := usageSince(path, since)
+	if err != nil {
+		return 0, err
+	}
+	return usage.CalculateCost("default"), nil
+}
+
+// TokensSince sums the tokens of assistant turns in a Claude session JSONL
+// file at or after since
+func TokensSince(path string, since time.Time) (int, error) {
+	usage, err := usageSince(path, since)
+	if err != nil {
+		return 0, err
+	}
+	return usage.TotalTokens(), nil
+}
+
+// usageSince accumulates the token usage of assistant turns at or after since
+func usageSince(path string, since time.Time) (*SessionAnalytics, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var usage SessionAnalytics
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 1024*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		var entry jsonlEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Type != "assistant" || entry.Timestamp.Before(since) {
+			continue
+		}
+		usage.InputTokens += entry.Message.Usage.InputTokens
+		usage.OutputTokens += entry.Message.Usage.OutputTokens
+		usage.CacheReadTokens += entry.Message.Usage.CacheReadInputTokens
+		usage.CacheWriteTokens += entry.Message.Usage.CacheCreationInputTokens
+	}
+	return &usage, scanner.Err()
+}
+
+// dailyUsage accumulates the token usage of assistant turns in [from, to) per
+// local day (statedb.DayKey)
+func dailyUsage(path string, from, to time.Time) (map[string]*SessionAnalytics, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	days := make(map[string]*SessionAnalytics)
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 1024*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+	for scanner.Scan() {
+		var entry jsonlEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Type != "assistant" || entry.Timestamp.Before(from) || !entry.Timestamp.Before(to) {
+			continue
+		}
+		day := statedb.DayKey(entry.Timestamp)
+		usage := days[day]
+		if usage == nil {
+			usage = &SessionAnalytics{}
+			days[day] = usage
+		}
+		usage.InputTokens += entry.Message.Usage.InputTokens
+		usage.OutputTokens += entry.Message.Usage.OutputTokens
+		usage.CacheReadTokens += entry.Message.Usage.CacheReadInputTokens
+		usage.CacheWriteTokens += entry.Message.Usage.CacheCreationInputTokens
+	}
+	return days, scanner.Err()
+}
@@ -0,0 +1,113 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/power"
+)
+
+// BatterySettings slows background work down on a laptop running low on
+// battery ([battery]). Each deck has its own config.toml, so decks can
+// differ.
+type BatterySettings struct {
+	// Enabled turns the battery saver on. Default: false
+	Enabled bool `toml:"enabled"`
+
+	// Threshold is the charge in percent below which, while on battery, the
+	// saver applies. 100 applies it whenever on battery. Default: 30
+	Threshold int `toml:"threshold"`
+
+	// HeartbeatFactor stretches conductor heartbeat intervals while saving:
+	// 4 sends only every fourth heartbeat. 1 keeps the normal rate. Default: 4
+	HeartbeatFactor int `toml:"heartbeat_factor"`
+
+	// PauseDispatch holds queued conductor tasks for the worker pool while
+	// saving; they are dispatched once back on AC power. Default: true
+	PauseDispatch *bool `toml:"pause_dispatch"`
+}
+
+// GetBatterySettings returns [battery] from config.toml
+func GetBatterySettings() BatterySettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return BatterySettings{}
+	}
+	return config.Battery
+}
+
+// GetThreshold returns the charge threshold in percent, defaulting to 30
+func (b *BatterySettings) GetThreshold() int {
+	switch {
+	case b.Threshold <= 0:
+		return 30
+	case b.Threshold > 100:
+		return 100
+	}
+	return b.Threshold
+}
+
+// GetHeartbeatFactor returns the heartbeat slowdown, defaulting to 4
+func (b *BatterySettings) GetHeartbeatFactor() int {
+	if b.HeartbeatFactor <= 0 {
+		return 4
+	}
+	return b.HeartbeatFactor
+}
+
+// GetPauseDispatch returns whether task dispatch pauses, defaulting to true
+func (b *BatterySettings) GetPauseDispatch() bool {
+	if b.PauseDispatch == nil {
+		return true
+	}
+	return *b.PauseDispatch
+}
+
+// BatterySaver is the battery policy in effect
+type BatterySaver struct {
+	Percent         int  `json:"percent"`
+	Threshold       int  `json:"threshold"`
+	HeartbeatFactor int  `json:"heartbeat_factor"`
+	PauseDispatch   bool `json:"pause_dispatch"`
+}
+
+// ActiveBatterySaver returns the battery saver in effect now, or nil when
+// it is disabled, the machine is on AC power or has no battery, or the
+// charge is at or above the threshold
+func ActiveBatterySaver() *BatterySaver {
+	settings := GetBatterySettings()
+	if !settings.Enabled {
+		return nil
+	}
+	battery, ok := power.ReadBattery()
+	return batterySaverFor(settings, battery, ok)
+}
+
+// batterySaverFor applies settings to a battery reading
+func batterySaverFor(settings BatterySettings, battery power.Battery, ok bool) *BatterySaver {
+	if !settings.Enabled || !ok || !battery.OnBattery || battery.Percent >= settings.GetThreshold() {
+		return nil
+	}
+	return &BatterySaver{
+		Percent:         battery.Percent,
+		Threshold:       settings.GetThreshold(),
+		HeartbeatFactor: settings.GetHeartbeatFactor(),
+		PauseDispatch:   settings.GetPauseDispatch(),
+	}
+}
+
+// Describe explains the saver for CLI output
+func (b *BatterySaver) Describe() string {
+	return fmt.Sprintf("battery saver: on battery at %d%% (below %d%%)", b.Percent, b.Threshold)
+}
+
+// HeartbeatDue reports whether a heartbeat last sent at last should go out
+// now, with heartbeats stretched to HeartbeatFactor intervals. Half an
+// interval of slack keeps timer jitter from skipping the run that is due.
+func (b *BatterySaver) HeartbeatDue(last time.Time, interval time.Duration, now time.Time) bool {
+	if b.HeartbeatFactor <= 1 || last.IsZero() {
+		return true
+	}
+	stretched := time.Duration(b.HeartbeatFactor)*interval - interval/2
+	return now.Sub(last) >= stretched
+}
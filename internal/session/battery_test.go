@@ -0,0 +1,55 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/power"
+)
+
+func TestBatterySaverFor(t *testing.T) {
+	enabled := BatterySettings{Enabled: true}
+	low := power.Battery{OnBattery: true, Percent: 20}
+
+	if saver := batterySaverFor(BatterySettings{}, low, true); saver != nil {
+		t.Errorf("disabled settings gave %+v", saver)
+	}
+	if saver := batterySaverFor(enabled, power.Battery{Percent: 20}, true); saver != nil {
+		t.Errorf("AC power gave %+v", saver)
+	}
+	if saver := batterySaverFor(enabled, power.Battery{OnBattery: true, Percent: 30}, true); saver != nil {
+		t.Errorf("charge at the threshold gave %+v", saver)
+	}
+	if saver := batterySaverFor(enabled, low, false); saver != nil {
+		t.Errorf("no battery gave %+v", saver)
+	}
+
+	saver := batterySaverFor(enabled, low, true)
+	if saver == nil {
+		t.Fatal("expected the saver on battery below the threshold")
+	}
+	want := BatterySaver{Percent: 20, Threshold: 30, HeartbeatFactor: 4, PauseDispatch: true}
+	if *saver != want {
+		t.Errorf("saver = %+v, want %+v", *saver, want)
+	}
+}
+
+func TestBatterySaverHeartbeatDue(t *testing.T) {
+	saver := &BatterySaver{HeartbeatFactor: 4}
+	last := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	interval := 15 * time.Minute
+
+	if !saver.HeartbeatDue(time.Time{}, interval, last) {
+		t.Error("first heartbeat should be due")
+	}
+	if saver.HeartbeatDue(last, interval, last.Add(45*time.Minute)) {
+		t.Error("third interval should be skipped")
+	}
+	// Timer jitter: the fourth run fires a few seconds early
+	if !saver.HeartbeatDue(last, interval, last.Add(60*time.Minute-5*time.Second)) {
+		t.Error("fourth interval should be due")
+	}
+	if !(&BatterySaver{HeartbeatFactor: 1}).HeartbeatDue(last, interval, last.Add(interval)) {
+		t.Error("factor 1 should keep the normal rate")
+	}
+}
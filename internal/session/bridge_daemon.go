@@ -0,0 +1,413 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/platform"
+)
+
+// bridgeStampFile records what the installed bridge daemon was started from,
+// so upgrades can tell when the script or the agent-deck binary changed.
+const bridgeStampFile = "bridge.stamp.json"
+
+// bridgeVerifyTimeout is how long InstallBridgeDaemon waits for the service to come up
+const bridgeVerifyTimeout = 5 * time.Second
+
+// BridgeDaemonError is an actionable bridge install/verify failure.
+// Check names the failed step; Remedy is a command or hint the user can run.
+type BridgeDaemonError struct {
+	Check  string
+	Detail string
+	Remedy string
+}
+
+func (e *BridgeDaemonError) Error() string {
+	msg := fmt.Sprintf("bridge daemon %s check failed: %s", e.Check, e.Detail)
+	if e.Remedy != "" {
+		msg += "\n  fix: " + e.Remedy
+	}
+	return msg
+}
+
+// BridgeCheck is the outcome of a single verification step.
+type BridgeCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	Remedy string `json:"remedy,omitempty"`
+}
+
+// bridgeStamp fingerprints the bridge script and agent-deck binary at install time
+type bridgeStamp struct {
+	ScriptHash    string    `json:"script_hash"`
+	AgentDeckPath string    `json:"agent_deck_path,omitempty"`
+	AgentDeckSize int64     `json:"agent_deck_size,omitempty"`
+	AgentDeckMod  time.Time `json:"agent_deck_mtime,omitempty"`
+	InstalledAt   time.Time `json:"installed_at"`
+}
+
+// BridgeScriptVersion returns a short content hash of the embedded bridge.py.
+// It changes whenever a new agent-deck release ships a different bridge.
+func BridgeScriptVersion() string {
+	return hashBridgeScript([]byte(conductorBridgePy))
+}
+
+func hashBridgeScript(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// currentBridgeStamp builds a stamp from the embedded script and the agent-deck binary on disk
+func currentBridgeStamp() bridgeStamp {
+	stamp := bridgeStamp{
+		ScriptHash:  BridgeScriptVersion(),
+		InstalledAt: time.Now(),
+	}
+	if path := findAgentDeck(); path != "" {
+		stamp.AgentDeckPath = path
+		if info, err := os.Stat(path); err == nil {
+			stamp.AgentDeckSize = info.Size()
+			stamp.AgentDeckMod = info.ModTime()
+		}
+	}
+	return stamp
+}
+
+// staleReasons lists why an installed stamp no longer matches the current one
+func (s bridgeStamp) staleReasons(current bridgeStamp) []string {
+	var reasons []string
+	if s.ScriptHash != current.ScriptHash {
+		reasons = append(reasons, fmt.Sprintf("bridge.py changed (%s -> %s)", s.ScriptHash, current.ScriptHash))
+	}
+	if s.AgentDeckPath != current.AgentDeckPath {
+		reasons = append(reasons, fmt.Sprintf("agent-deck moved (%s -> %s)", s.AgentDeckPath, current.AgentDeckPath))
+	} else if s.AgentDeckSize != current.AgentDeckSize || !s.AgentDeckMod.Equal(current.AgentDeckMod) {
+		reasons = append(reasons, "agent-deck binary was updated")
+	}
+	return reasons
+}
+
+func bridgeStampPath() (string, error) {
+	dir, err := ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, bridgeStampFile), nil
+}
+
+func writeBridgeStamp(stamp bridgeStamp) error {
+	path, err := bridgeStampPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stamp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bridge stamp: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write bridge stamp: %w", err)
+	}
+	return nil
+}
+
+func readBridgeStamp() (*bridgeStamp, error) {
+	path, err := bridgeStampPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var stamp bridgeStamp
+	if err := json.Unmarshal(data, &stamp); err != nil {
+		return nil, fmt.Errorf("failed to parse bridge stamp: %w", err)
+	}
+	return &stamp, nil
+}
+
+func removeBridgeStamp() {
+	if path, err := bridgeStampPath(); err == nil {
+		_ = os.Remove(path)
+	}
+}
+
+// bridgeUnitPath returns the platform unit/plist path for the bridge daemon
+func bridgeUnitPath() (string, error) {
+	if platform.Detect() == platform.PlatformMacOS {
+		return LaunchdPlistPath()
+	}
+	return SystemdBridgeServicePath()
+}
+
+// bridgeLogTail returns the last n lines of bridge.log for error details
+func bridgeLogTail(n int) string {
+	condDir, err := ConductorDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(condDir, "bridge.log"))
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isBridgeDaemonEnabled reports whether the service is registered to start at login
+func isBridgeDaemonEnabled() bool {
+	switch platform.Detect() {
+	case platform.PlatformMacOS:
+		return exec.Command("launchctl", "list", LaunchdPlistName()).Run() == nil
+	case platform.PlatformLinux, platform.PlatformWSL2:
+		return exec.Command("systemctl", "--user", "is-enabled", "--quiet", systemdBridgeServiceName()).Run() == nil
+	default:
+		return false
+	}
+}
+
+// VerifyBridgeDaemon runs all post-install checks and returns every result.
+// The returned error is the first failing check as a *BridgeDaemonError.
+//
+// The bridge talks to Telegram/Slack outbound and has no local socket, so
+// reachability is judged by the service manager reporting it active. The
+// version handshake compares the installed bridge.py and agent-deck binary
+// against the stamp written at install time.
+func VerifyBridgeDaemon() ([]BridgeCheck, error) {
+	var checks []BridgeCheck
+	condDir, _ := ConductorDir()
+	macOS := platform.Detect() == platform.PlatformMacOS
+
+	unitPath, err := bridgeUnitPath()
+	unit := BridgeCheck{Name: "unit", OK: err == nil}
+	if err == nil {
+		if _, statErr := os.Stat(unitPath); statErr != nil {
+			unit.OK = false
+		}
+		unit.Detail = unitPath
+	}
+	if !unit.OK {
+		unit.Remedy = "agent-deck conductor setup <name> (with [conductor.telegram] or [conductor.slack] configured)"
+	}
+	checks = append(checks, unit)
+
+	enabled := BridgeCheck{Name: "enabled", OK: isBridgeDaemonEnabled()}
+	if !enabled.OK {
+		if macOS {
+			enabled.Remedy = fmt.Sprintf("launchctl load %s", unitPath)
+		} else {
+			enabled.Remedy = "systemctl --user enable --now " + systemdBridgeServiceName()
+		}
+	}
+	checks = append(checks, enabled)
+
+	running := BridgeCheck{Name: "running", OK: IsBridgeDaemonRunning()}
+	if !running.OK {
+		running.Detail = bridgeLogTail(5)
+		if macOS {
+			running.Remedy = fmt.Sprintf("tail -n 50 %s/bridge.log", condDir)
+		} else {
+			running.Remedy = "journalctl --user -u " + systemdBridgeServiceName() + " -n 50"
+		}
+	}
+	checks = append(checks, running)
+
+	version := BridgeCheck{Name: "version", OK: true, Detail: BridgeScriptVersion()}
+	installed, readErr := os.ReadFile(filepath.Join(condDir, "bridge.py"))
+	switch {
+	case readErr != nil:
+		version.OK = false
+		version.Detail = "bridge.py missing"
+	case hashBridgeScript(installed) != BridgeScriptVersion():
+		version.OK = false
+		version.Detail = fmt.Sprintf("installed %s, expected %s", hashBridgeScript(installed), BridgeScriptVersion())
+	default:
+		if stamp, err := readBridgeStamp(); err == nil {
+			if reasons := stamp.staleReasons(currentBridgeStamp()); len(reasons) > 0 {
+				version.OK = false
+				version.Detail = "running daemon is stale: " + strings.Join(reasons, "; ")
+			}
+		}
+	}
+	if !version.OK {
+		version.Remedy = "agent-deck conductor bridge upgrade"
+	}
+	checks = append(checks, version)
+
+	for _, c := range checks {
+		if !c.OK {
+			return checks, &BridgeDaemonError{Check: c.Name, Detail: c.Detail, Remedy: c.Remedy}
+		}
+	}
+	return checks, nil
+}
+
+// BridgeDaemonUptime returns how long the bridge daemon's process has been
+// running, from its service manager's PID and ps. ok is false when it is
+// not running or the platform has no service manager.
+func BridgeDaemonUptime() (uptime time.Duration, ok bool) {
+	var pid string
+	switch platform.Detect() {
+	case platform.PlatformMacOS:
+		out, err := exec.Command("launchctl", "list", LaunchdPlistName()).Output()
+		if err != nil {
+			return 0, false
+		}
+		// launchctl list <label> prints a plist-like dict with "PID" = 123;
+		for _, line := range strings.Split(string(out), "\n") {
+			if rest, found := strings.CutPrefix(strings.TrimSpace(line), `"PID" = `); found {
+				pid = strings.TrimSuffix(rest, ";")
+			}
+		}
+	case platform.PlatformLinux, platform.PlatformWSL2:
+		out, err := exec.Command("systemctl", "--user", "show", "--property=MainPID", "--value", systemdBridgeServiceName()).Output()
+		if err != nil {
+			return 0, false
+		}
+		pid = strings.TrimSpace(string(out))
+	default:
+		return 0, false
+	}
+	if pid == "" || pid == "0" {
+		return 0, false
+	}
+	out, err := exec.Command("ps", "-o", "etime=", "-p", pid).Output()
+	if err != nil {
+		return 0, false
+	}
+	return parsePSElapsed(strings.TrimSpace(string(out)))
+}
+
+// parsePSElapsed parses ps etime output: [[dd-]hh:]mm:ss
+func parsePSElapsed(s string) (time.Duration, bool) {
+	var days int
+	if d, rest, found := strings.Cut(s, "-"); found {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, false
+		}
+		days, s = n, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var seconds int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(days)*24*time.Hour + time.Duration(seconds)*time.Second, true
+}
+
+// waitForBridgeDaemon polls until the daemon is active or the timeout expires,
+// then runs the full verification.
+func waitForBridgeDaemon(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for !IsBridgeDaemonRunning() && time.Now().Before(deadline) {
+		time.Sleep(250 * time.Millisecond)
+	}
+	_, err := VerifyBridgeDaemon()
+	return err
+}
+
+// restartBridgeDaemon restarts an already-installed bridge service in place
+func restartBridgeDaemon() error {
+	switch platform.Detect() {
+	case platform.PlatformMacOS:
+		plistPath, err := LaunchdPlistPath()
+		if err != nil {
+			return err
+		}
+		_ = exec.Command("launchctl", "unload", plistPath).Run()
+		if err := exec.Command("launchctl", "load", plistPath).Run(); err != nil {
+			return fmt.Errorf("failed to reload bridge daemon: %w", err)
+		}
+	case platform.PlatformLinux, platform.PlatformWSL2:
+		_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+		if err := exec.Command("systemctl", "--user", "restart", systemdBridgeServiceName()).Run(); err != nil {
+			return fmt.Errorf("failed to restart bridge daemon: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported platform %s for daemon management", platform.Detect())
+	}
+	return nil
+}
+
+// UpgradeBridgeDaemon refreshes bridge.py and the unit file and restarts the
+// service when the embedded script or the agent-deck binary changed since the
+// last install. With force, it refreshes regardless. With restart false, files
+// and the install stamp are refreshed but the service is left running; the
+// bridge uses this before re-executing itself. Returns whether anything was
+// refreshed and why.
+func UpgradeBridgeDaemon(force, restart bool) (bool, []string, error) {
+	unitPath, err := bridgeUnitPath()
+	if err != nil {
+		return false, nil, err
+	}
+	if _, err := os.Stat(unitPath); os.IsNotExist(err) {
+		return false, nil, &BridgeDaemonError{
+			Check:  "unit",
+			Detail: "bridge daemon is not installed",
+			Remedy: "agent-deck conductor setup <name>",
+		}
+	}
+
+	current := currentBridgeStamp()
+	var reasons []string
+	if stamp, err := readBridgeStamp(); err == nil {
+		reasons = stamp.staleReasons(current)
+	} else {
+		reasons = []string{"no install record (installed by an older agent-deck)"}
+	}
+	if force && len(reasons) == 0 {
+		reasons = []string{"forced"}
+	}
+	if len(reasons) == 0 {
+		return false, nil, nil
+	}
+
+	if err := InstallBridgeScript(); err != nil {
+		return false, reasons, err
+	}
+	var unitContent string
+	if platform.Detect() == platform.PlatformMacOS {
+		unitContent, err = GenerateLaunchdPlist()
+	} else {
+		unitContent, err = GenerateSystemdBridgeService()
+	}
+	if err != nil {
+		return false, reasons, fmt.Errorf("failed to regenerate bridge unit: %w", err)
+	}
+	if err := os.WriteFile(unitPath, []byte(unitContent), 0o644); err != nil {
+		return false, reasons, fmt.Errorf("failed to write bridge unit: %w", err)
+	}
+	if !restart {
+		if platform.Detect() != platform.PlatformMacOS {
+			_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+		}
+		return true, reasons, writeBridgeStamp(current)
+	}
+	if err := restartBridgeDaemon(); err != nil {
+		return false, reasons, err
+	}
+	if err := writeBridgeStamp(current); err != nil {
+		return true, reasons, err
+	}
+	return true, reasons, waitForBridgeDaemon(bridgeVerifyTimeout)
+}
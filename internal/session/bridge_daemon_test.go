@@ -0,0 +1,134 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBridgeScriptVersion(t *testing.T) {
+	v := BridgeScriptVersion()
+	if len(v) != 12 {
+		t.Fatalf("version %q should be 12 hex chars", v)
+	}
+	if v != hashBridgeScript([]byte(conductorBridgePy)) {
+		t.Error("version should hash the embedded bridge script")
+	}
+	if hashBridgeScript([]byte("other")) == v {
+		t.Error("different content should produce a different version")
+	}
+}
+
+func TestBridgeStampStaleReasons(t *testing.T) {
+	mod := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := bridgeStamp{
+		ScriptHash:    "aaa",
+		AgentDeckPath: "/usr/local/bin/agent-deck",
+		AgentDeckSize: 100,
+		AgentDeckMod:  mod,
+	}
+
+	tests := []struct {
+		name    string
+		current bridgeStamp
+		want    string
+	}{
+		{"unchanged", base, ""},
+		{"script changed", bridgeStamp{ScriptHash: "bbb", AgentDeckPath: base.AgentDeckPath, AgentDeckSize: 100, AgentDeckMod: mod}, "bridge.py changed"},
+		{"binary updated", bridgeStamp{ScriptHash: "aaa", AgentDeckPath: base.AgentDeckPath, AgentDeckSize: 200, AgentDeckMod: mod}, "binary was updated"},
+		{"binary moved", bridgeStamp{ScriptHash: "aaa", AgentDeckPath: "/opt/homebrew/bin/agent-deck", AgentDeckSize: 100, AgentDeckMod: mod}, "agent-deck moved"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := base.staleReasons(tt.current)
+			if tt.want == "" {
+				if len(reasons) != 0 {
+					t.Errorf("expected no reasons, got %v", reasons)
+				}
+				return
+			}
+			if len(reasons) == 0 || !strings.Contains(strings.Join(reasons, ";"), tt.want) {
+				t.Errorf("reasons %v should mention %q", reasons, tt.want)
+			}
+		})
+	}
+}
+
+func TestBridgeStampRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := InstallBridgeScript(); err != nil {
+		t.Fatalf("InstallBridgeScript: %v", err)
+	}
+
+	stamp := currentBridgeStamp()
+	if err := writeBridgeStamp(stamp); err != nil {
+		t.Fatalf("writeBridgeStamp: %v", err)
+	}
+	loaded, err := readBridgeStamp()
+	if err != nil {
+		t.Fatalf("readBridgeStamp: %v", err)
+	}
+	if reasons := loaded.staleReasons(currentBridgeStamp()); len(reasons) != 0 {
+		t.Errorf("fresh stamp should not be stale: %v", reasons)
+	}
+
+	removeBridgeStamp()
+	if _, err := readBridgeStamp(); err == nil {
+		t.Error("stamp should be removed")
+	}
+}
+
+func TestBridgeDaemonErrorIncludesRemedy(t *testing.T) {
+	err := &BridgeDaemonError{Check: "running", Detail: "inactive", Remedy: "journalctl --user -u x"}
+	msg := err.Error()
+	if !strings.Contains(msg, "running") || !strings.Contains(msg, "fix: journalctl") {
+		t.Errorf("unexpected message: %q", msg)
+	}
+}
+
+func TestBridgeTemplate_SelfUpgrade(t *testing.T) {
+	template := conductorBridgePy
+
+	patterns := []string{
+		"async def upgrade_watch_loop():",
+		"file_fingerprint(shutil.which(\"agent-deck\"))",
+		"while _inflight_count > 0 and time.monotonic() < deadline:",
+		"run_cli(\"conductor\", \"bridge\", \"upgrade\", \"--no-restart\", timeout=60)",
+		"os.execv(sys.executable, [sys.executable, bridge_path] + sys.argv[1:])",
+		"tasks = [heartbeat_task, asyncio.create_task(upgrade_watch_loop())]",
+	}
+	for _, pattern := range patterns {
+		if !strings.Contains(template, pattern) {
+			t.Errorf("template should contain self-upgrade pattern: %q", pattern)
+		}
+	}
+
+	// Both platforms must refuse new work while draining and count in-flight requests
+	if got := strings.Count(template, "if is_draining():"); got != 2 {
+		t.Errorf("expected drain checks in Telegram and Slack handlers, got %d", got)
+	}
+	if got := strings.Count(template, "async with inflight():"); got != 2 {
+		t.Errorf("expected in-flight tracking in Telegram and Slack handlers, got %d", got)
+	}
+}
+
+func TestParsePSElapsed(t *testing.T) {
+	cases := map[string]time.Duration{
+		"00:05":       5 * time.Second,
+		"12:34":       12*time.Minute + 34*time.Second,
+		"01:02:03":    time.Hour + 2*time.Minute + 3*time.Second,
+		"3-04:00:10":  3*24*time.Hour + 4*time.Hour + 10*time.Second,
+		"10-00:00:00": 10 * 24 * time.Hour,
+	}
+	for in, want := range cases {
+		got, ok := parsePSElapsed(in)
+		if !ok || got != want {
+			t.Errorf("parsePSElapsed(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	for _, bad := range []string{"", "5", "a:b", "1:2:3:4", "x-01:00"} {
+		if _, ok := parsePSElapsed(bad); ok {
+			t.Errorf("parsePSElapsed(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/chaos"
+)
+
+// ChaosSettings configures simulated failures (see package chaos). Meant for
+// testing hooks, alerting and retry behavior, never for everyday use.
+type ChaosSettings struct {
+	// Enabled turns failure injection on
+	// Default: false
+	Enabled bool `toml:"enabled"`
+
+	// CaptureFailureRate is the probability (0-1) that a tmux pane capture fails
+	CaptureFailureRate float64 `toml:"capture_failure_rate"`
+
+	// SlowStartRate is the probability (0-1) that a new session's tool starts late
+	SlowStartRate float64 `toml:"slow_start_rate"`
+
+	// SlowStartDelay is how late a slow start is (Go duration, default: "15s")
+	SlowStartDelay string `toml:"slow_start_delay"`
+
+	// HeartbeatFailureRate is the probability (0-1) that delivering a heartbeat
+	// to a conductor fails
+	HeartbeatFailureRate float64 `toml:"heartbeat_failure_rate"`
+}
+
+// chaosConfig converts the settings for package chaos
+func (c ChaosSettings) chaosConfig() chaos.Config {
+	delay, err := time.ParseDuration(c.SlowStartDelay)
+	if err != nil || delay <= 0 {
+		delay = chaos.DefaultSlowStartDelay
+	}
+	return chaos.Config{
+		Enabled: c.Enabled,
+		Rates: map[chaos.Fault]float64{
+			chaos.CaptureFailure:   c.CaptureFailureRate,
+			chaos.SlowStart:        c.SlowStartRate,
+			chaos.HeartbeatFailure: c.HeartbeatFailureRate,
+		},
+		SlowStartDelay: delay,
+	}
+}
+
+func init() {
+	chaos.SetSource(func() chaos.Config {
+		config, err := LoadUserConfig()
+		if err != nil || config == nil {
+			return chaos.Config{}
+		}
+		return config.Chaos.chaosConfig()
+	})
+}
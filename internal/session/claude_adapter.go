@@ -0,0 +1,58 @@
+package session
+
+import (
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// claudeForkWindow is how long after its session ID was detected a Claude
+// session can be forked
+const claudeForkWindow = 5 * time.Minute
+
+// claudeAdapter is the ToolAdapter of Claude Code. Sessions start with a
+// known session ID (captured into the tmux environment), which restart
+// resumes and fork branches with --fork-session.
+type claudeAdapter struct{}
+
+func (claudeAdapter) BuildStartCommand(inst *Instance, baseCommand string) string {
+	return inst.buildClaudeCommand(baseCommand)
+}
+
+func (claudeAdapter) BuildResumeCommand(inst *Instance) string {
+	if inst.ClaudeSessionID == "" {
+		return ""
+	}
+	return inst.buildClaudeResumeCommand()
+}
+
+func (claudeAdapter) CanResume(inst *Instance) bool {
+	return inst.ClaudeSessionID != ""
+}
+
+func (claudeAdapter) BusyPatterns() []string {
+	return tmux.DefaultRawPatterns("claude").BusyPatterns
+}
+
+// PromptReadyPatterns returns Claude's default prompt patterns, which are
+// empty: its prompt is recognised by the PromptDetector, which also tells
+// permission dialogs from the input box
+func (claudeAdapter) PromptReadyPatterns() []string {
+	return tmux.DefaultRawPatterns("claude").PromptPatterns
+}
+
+func (claudeAdapter) SupportsFork(inst *Instance) bool {
+	if inst == nil {
+		return true
+	}
+	return inst.ClaudeSessionID != "" && time.Since(inst.ClaudeDetectedAt) < claudeForkWindow
+}
+
+// SyncSessionID picks up the session /clear switched to
+func (claudeAdapter) SyncSessionID(inst *Instance) {
+	inst.syncClaudeSessionFromDisk()
+}
+
+// CaptureSessionID does nothing: agent-deck chooses Claude's session ID
+// when it builds the start command
+func (claudeAdapter) CaptureSessionID(*Instance) {}
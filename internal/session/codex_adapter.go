@@ -0,0 +1,69 @@
+package session
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// codexAdapter is the ToolAdapter of the OpenAI Codex CLI. Codex picks its
+// own session ID, so it is read from the rollout files in ~/.codex/sessions
+// after the session starts and stored in the tmux environment
+// (CODEX_SESSION_ID); restart resumes it with "codex resume <id>".
+type codexAdapter struct{}
+
+// BuildStartCommand also records the start time: session ID capture only
+// considers rollouts written after it
+func (codexAdapter) BuildStartCommand(inst *Instance, baseCommand string) string {
+	inst.CodexStartedAt = time.Now().UnixMilli()
+	return inst.buildCodexCommand(baseCommand)
+}
+
+// BuildResumeCommand always restarts in place: "codex resume" when the
+// session ID is known, else a fresh codex whose ID is captured again
+func (codexAdapter) BuildResumeCommand(inst *Instance) string {
+	if inst.CodexSessionID == "" {
+		inst.CodexStartedAt = time.Now().UnixMilli()
+	}
+	return inst.buildCodexCommand("codex")
+}
+
+func (codexAdapter) CanResume(inst *Instance) bool {
+	return inst.CodexSessionID != ""
+}
+
+func (codexAdapter) BusyPatterns() []string {
+	return tmux.DefaultRawPatterns("codex").BusyPatterns
+}
+
+func (codexAdapter) PromptReadyPatterns() []string {
+	return tmux.DefaultRawPatterns("codex").PromptPatterns
+}
+
+// SupportsFork copies the rollout into a new session (see codexForkStrategy)
+func (codexAdapter) SupportsFork(inst *Instance) bool {
+	if inst == nil {
+		return true
+	}
+	return codexForkStrategy{}.CanFork(inst)
+}
+
+// SyncSessionID always rescans, even with a known ID: the user may have
+// started a new conversation inside codex. The tmux environment is the
+// fallback for an ID detected but not yet saved.
+func (codexAdapter) SyncSessionID(inst *Instance) {
+	inst.UpdateCodexSession(nil)
+	if inst.CodexSessionID != "" || inst.tmuxSession == nil {
+		return
+	}
+	if envID, err := inst.tmuxSession.GetEnvironment("CODEX_SESSION_ID"); err == nil && envID != "" {
+		inst.CodexSessionID = envID
+		inst.CodexDetectedAt = time.Now()
+		sessionLog.Info("restart_codex_recovered_id", slog.String("session_id", envID))
+	}
+}
+
+func (codexAdapter) CaptureSessionID(inst *Instance) {
+	inst.detectCodexSessionAsync()
+}
@@ -0,0 +1,268 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// CodexSessionAnalytics holds metrics for a Codex session, parsed from its
+// rollout file (~/.codex/sessions/YYYY/MM/DD/rollout-<ts>-<uuid>.jsonl)
+type CodexSessionAnalytics struct {
+	// Token usage (cumulative, as last reported by Codex)
+	InputTokens       int `json:"input_tokens"`
+	CachedInputTokens int `json:"cached_input_tokens"` // part of InputTokens
+	OutputTokens      int `json:"output_tokens"`
+	ReasoningTokens   int `json:"reasoning_output_tokens"` // part of OutputTokens
+
+	// Current context size (last turn's input) and the model's window
+	CurrentContextTokens int `json:"current_context_tokens"`
+	ContextWindow        int `json:"context_window,omitempty"`
+
+	// Session metrics
+	TotalTurns int           `json:"total_turns"`
+	Duration   time.Duration `json:"duration"`
+	StartTime  time.Time     `json:"start_time"`
+	LastActive time.Time     `json:"last_active"`
+
+	// Cost estimation
+	EstimatedCost float64 `json:"estimated_cost"`
+
+	// Model from the rollout's turn context
+	Model string `json:"model,omitempty"`
+
+	// In-memory cache: rollout file and its last modification time (skip
+	// the directory walk and re-parse if unchanged)
+	rolloutPath     string
+	LastFileModTime time.Time `json:"-"`
+}
+
+// TotalTokens returns the sum of input and output tokens
+func (a *CodexSessionAnalytics) TotalTokens() int {
+	return a.InputTokens + a.OutputTokens
+}
+
+// CodexModelPricing holds pricing per million tokens
+type CodexModelPricing struct {
+	Input       float64
+	CachedInput float64
+	Output      float64
+}
+
+// codexPricing contains pricing per million tokens for each model family,
+// matched by longest prefix
+var codexPricing = map[string]CodexModelPricing{
+	"gpt-5":      {Input: 1.25, CachedInput: 0.125, Output: 10.00},
+	"gpt-5-mini": {Input: 0.25, CachedInput: 0.025, Output: 2.00},
+	"gpt-5-nano": {Input: 0.05, CachedInput: 0.005, Output: 0.40},
+	"gpt-4.1":    {Input: 2.00, CachedInput: 0.50, Output: 8.00},
+	"o3":         {Input: 2.00, CachedInput: 0.50, Output: 8.00},
+	"o4-mini":    {Input: 1.10, CachedInput: 0.275, Output: 4.40},
+	"codex-mini": {Input: 1.50, CachedInput: 0.375, Output: 6.00},
+	// Fallback
+	"default": {Input: 1.25, CachedInput: 0.125, Output: 10.00},
+}
+
+// codexPricingFor returns the pricing of the longest model prefix that matches
+func codexPricingFor(model string) CodexModelPricing {
+	best := ""
+	for prefix := range codexPricing {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		best = "default"
+	}
+	return codexPricing[best]
+}
+
+// CalculateCost estimates session cost based on token usage and model pricing.
+// Cached input is billed at the cached rate, the rest of the input at the full one.
+func (a *CodexSessionAnalytics) CalculateCost(model string) float64 {
+	pricing := codexPricingFor(model)
+	uncached := a.InputTokens - a.CachedInputTokens
+	if uncached < 0 {
+		uncached = 0
+	}
+	return float64(uncached)/1_000_000*pricing.Input +
+		float64(a.CachedInputTokens)/1_000_000*pricing.CachedInput +
+		float64(a.OutputTokens)/1_000_000*pricing.Output
+}
+
+// codexTokenUsage is Codex's token usage record
+type codexTokenUsage struct {
+	InputTokens       int `json:"input_tokens"`
+	CachedInputTokens int `json:"cached_input_tokens"`
+	OutputTokens      int `json:"output_tokens"`
+	ReasoningTokens   int `json:"reasoning_output_tokens"`
+}
+
+// codexRolloutLine is one record of a rollout file
+type codexRolloutLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"` // session_meta, turn_context, event_msg, response_item
+	Payload   struct {
+		Type  string `json:"type"`  // event_msg: token_count, user_message, ...
+		Model string `json:"model"` // turn_context
+		Info  *struct {
+			Total         codexTokenUsage `json:"total_token_usage"`
+			Last          codexTokenUsage `json:"last_token_usage"`
+			ContextWindow int             `json:"model_context_window"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+// ParseCodexRollout parses a Codex rollout file into analytics. Token counts
+// come from the last token_count event, which carries cumulative totals.
+func ParseCodexRollout(path string) (*CodexSessionAnalytics, error) {
+	return parseCodexRolloutSince(path, time.Time{})
+}
+
+// parseCodexRolloutSince parses a rollout file, counting only usage reported
+// at or after since. Totals are cumulative, so usage since is the last total
+// minus the last total reported before since.
+func parseCodexRolloutSince(path string, since time.Time) (*CodexSessionAnalytics, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	analytics := &CodexSessionAnalytics{}
+	var before codexTokenUsage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var line codexRolloutLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if !line.Timestamp.IsZero() {
+			if analytics.StartTime.IsZero() {
+				analytics.StartTime = line.Timestamp
+			}
+			analytics.LastActive = line.Timestamp
+		}
+		switch {
+		case line.Type == "turn_context" && line.Payload.Model != "":
+			analytics.Model = line.Payload.Model
+		case line.Type == "event_msg" && line.Payload.Type == "user_message":
+			if !line.Timestamp.Before(since) {
+				analytics.TotalTurns++
+			}
+		case line.Type == "event_msg" && line.Payload.Type == "token_count" && line.Payload.Info != nil:
+			info := line.Payload.Info
+			if line.Timestamp.Before(since) {
+				before = info.Total
+				continue
+			}
+			analytics.InputTokens = info.Total.InputTokens - before.InputTokens
+			analytics.CachedInputTokens = info.Total.CachedInputTokens - before.CachedInputTokens
+			analytics.OutputTokens = info.Total.OutputTokens - before.OutputTokens
+			analytics.ReasoningTokens = info.Total.ReasoningTokens - before.ReasoningTokens
+			analytics.CurrentContextTokens = info.Last.InputTokens
+			analytics.ContextWindow = info.ContextWindow
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read codex rollout: %w", err)
+	}
+	if !analytics.StartTime.IsZero() {
+		analytics.Duration = analytics.LastActive.Sub(analytics.StartTime)
+	}
+	analytics.EstimatedCost = analytics.CalculateCost(analytics.Model)
+	return analytics, nil
+}
+
+// CodexSpendSince estimates the cost of a Codex rollout's usage at or after since
+func CodexSpendSince(path string, since time.Time) (float64, error) {
+	analytics, err := parseCodexRolloutSince(path, since)
+	if err != nil {
+		return 0, err
+	}
+	return analytics.EstimatedCost, nil
+}
+
+// codexDailyUsage splits a rollout's usage reported in [from, to) by local
+// day (statedb.DayKey). Each day's cost uses the session's last model.
+func codexDailyUsage(path string, from, to time.Time) (map[string]*CodexSessionAnalytics, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	days := make(map[string]*CodexSessionAnalytics)
+	var model string
+	var prev codexTokenUsage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var line codexRolloutLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		switch {
+		case line.Type == "turn_context" && line.Payload.Model != "":
+			model = line.Payload.Model
+		case line.Type == "event_msg" && line.Payload.Type == "token_count" && line.Payload.Info != nil:
+			total := line.Payload.Info.Total
+			if !line.Timestamp.Before(from) && line.Timestamp.Before(to) {
+				day := statedb.DayKey(line.Timestamp)
+				usage := days[day]
+				if usage == nil {
+					usage = &CodexSessionAnalytics{}
+					days[day] = usage
+				}
+				usage.InputTokens += total.InputTokens - prev.InputTokens
+				usage.CachedInputTokens += total.CachedInputTokens - prev.CachedInputTokens
+				usage.OutputTokens += total.OutputTokens - prev.OutputTokens
+				usage.ReasoningTokens += total.ReasoningTokens - prev.ReasoningTokens
+			}
+			prev = total
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read codex rollout: %w", err)
+	}
+	for _, usage := range days {
+		usage.Model = model
+		usage.EstimatedCost = usage.CalculateCost(model)
+	}
+	return days, nil
+}
+
+// UpdateCodexAnalyticsFromDisk refreshes analytics from the session's rollout
+// file. Uses mtime caching to skip re-parsing unchanged files.
+func UpdateCodexAnalyticsFromDisk(sessionID string, analytics *CodexSessionAnalytics) error {
+	if sessionID == "" {
+		return fmt.Errorf("invalid session ID")
+	}
+	path := analytics.rolloutPath
+	info, err := os.Stat(path)
+	if path == "" || !strings.HasSuffix(path, sessionID+".jsonl") || err != nil {
+		if path = findCodexRollout(sessionID); path == "" {
+			return fmt.Errorf("rollout file not found")
+		}
+		if info, err = os.Stat(path); err != nil {
+			return err
+		}
+	}
+	if !analytics.LastFileModTime.IsZero() && info.ModTime().Equal(analytics.LastFileModTime) {
+		return nil
+	}
+	parsed, err := ParseCodexRollout(path)
+	if err != nil {
+		return err
+	}
+	*analytics = *parsed
+	analytics.rolloutPath = path
+	analytics.LastFileModTime = info.ModTime()
+	return nil
+}
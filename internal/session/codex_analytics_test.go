@@ -0,0 +1,105 @@
+package session
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const codexTestRollout = `{"timestamp":"2026-03-09T09:00:00Z","type":"session_meta","payload":{"id":"11111111-2222-4333-8444-555555555555","cwd":"/tmp"}}
+{"timestamp":"2026-03-09T09:00:01Z","type":"turn_context","payload":{"cwd":"/tmp","model":"gpt-5-codex"}}
+{"timestamp":"2026-03-09T09:00:02Z","type":"event_msg","payload":{"type":"user_message","message":"fix the tests"}}
+{"timestamp":"2026-03-09T09:01:00Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000000,"cached_input_tokens":0,"output_tokens":100000,"reasoning_output_tokens":40000},"last_token_usage":{"input_tokens":1000000},"model_context_window":272000}}}
+{"timestamp":"2026-03-10T08:00:00Z","type":"event_msg","payload":{"type":"user_message","message":"now the docs"}}
+{"timestamp":"2026-03-10T08:05:00Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":3000000,"cached_input_tokens":1000000,"output_tokens":200000,"reasoning_output_tokens":60000},"last_token_usage":{"input_tokens":45000},"model_context_window":272000}}}
+{"timestamp":"2026-03-10T08:05:01Z","type":"event_msg","payload":{"type":"token_count","info":null}}
+`
+
+func TestParseCodexRollout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rollout.jsonl")
+	if err := os.WriteFile(path, []byte(codexTestRollout+"{torn"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := ParseCodexRollout(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Model != "gpt-5-codex" || a.TotalTurns != 2 {
+		t.Errorf("model/turns = %s/%d", a.Model, a.TotalTurns)
+	}
+	if a.InputTokens != 3000000 || a.CachedInputTokens != 1000000 || a.OutputTokens != 200000 || a.ReasoningTokens != 60000 {
+		t.Errorf("tokens = %+v", a)
+	}
+	if a.CurrentContextTokens != 45000 || a.ContextWindow != 272000 {
+		t.Errorf("context = %d of %d", a.CurrentContextTokens, a.ContextWindow)
+	}
+	if a.Duration != 23*time.Hour+5*time.Minute+time.Second {
+		t.Errorf("duration = %v", a.Duration)
+	}
+	// 2M uncached at 1.25, 1M cached at 0.125, 0.2M output at 10 (gpt-5 family)
+	if want := 2.5 + 0.125 + 2.0; math.Abs(a.EstimatedCost-want) > 1e-9 {
+		t.Errorf("cost = %v, want %v", a.EstimatedCost, want)
+	}
+
+	// Spend since the second day only counts the usage reported that day
+	spend, err := CodexSpendSince(path, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 1.25 + 0.125 + 1.0; math.Abs(spend-want) > 1e-9 {
+		t.Errorf("spend since = %v, want %v", spend, want)
+	}
+
+	if p := codexPricingFor("gpt-5-mini-2025"); p.Input != 0.25 {
+		t.Errorf("gpt-5-mini pricing = %+v", p)
+	}
+}
+
+func TestUpdateCodexAnalyticsFromDisk(t *testing.T) {
+	codexHome := t.TempDir()
+	t.Setenv("CODEX_HOME", codexHome)
+	id := "11111111-2222-4333-8444-555555555555"
+	dir := filepath.Join(codexHome, "sessions", "2026", "03", "09")
+	_ = os.MkdirAll(dir, 0o755)
+	path := filepath.Join(dir, "rollout-2026-03-09T09-00-00-"+id+".jsonl")
+	if err := os.WriteFile(path, []byte(codexTestRollout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	inst := NewInstanceWithTool("codex-cost", "/tmp", "codex")
+	inst.CodexSessionID = id
+	inst.updateCodexAnalytics()
+	if inst.CodexAnalytics == nil || inst.CodexAnalytics.TotalTokens() != 3200000 {
+		t.Fatalf("analytics = %+v", inst.CodexAnalytics)
+	}
+
+	// Unchanged file: the cached parse is kept
+	inst.CodexAnalytics.TotalTurns = 99
+	inst.updateCodexAnalytics()
+	if inst.CodexAnalytics.TotalTurns != 99 {
+		t.Error("unchanged rollout should not be re-parsed")
+	}
+	later := time.Now().Add(time.Minute)
+	_ = os.Chtimes(path, later, later)
+	inst.updateCodexAnalytics()
+	if inst.CodexAnalytics.TotalTurns != 2 {
+		t.Errorf("touched rollout should be re-parsed, turns = %d", inst.CodexAnalytics.TotalTurns)
+	}
+}
+
+func TestBuildCodexCommand_Model(t *testing.T) {
+	writeMacroConfig(t, "[codex]\ndefault_model = \"gpt-5-codex\"\n")
+	inst := NewInstanceWithTool("codex-model", "/tmp", "codex")
+	if got := inst.buildCodexCommand("codex"); !strings.HasSuffix(got, "codex -m gpt-5-codex") {
+		t.Errorf("default model command = %q", got)
+	}
+	_ = inst.SetCodexOptions(&CodexOptions{Model: "o4-mini"})
+	inst.CodexSessionID = "abc"
+	if got := inst.buildCodexCommand("codex"); !strings.HasSuffix(got, "codex -m o4-mini resume abc") {
+		t.Errorf("per-session model resume command = %q", got)
+	}
+}
@@ -0,0 +1,427 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// Command guard modes (GuardSettings.Mode)
+const (
+	GuardOff     = "off"     // no shims (default)
+	GuardLog     = "log"     // guarded commands run and are recorded in the audit log
+	GuardApprove = "approve" // guarded commands wait for 'agent-deck guard approve'
+)
+
+// Guard request states
+const (
+	GuardPending  = "pending"
+	GuardApproved = "approved"
+	GuardDenied   = "denied"
+	GuardExpired  = "expired"
+)
+
+// defaultGuardCommands are the binaries guarded when [guard] lists none
+var defaultGuardCommands = []string{"kubectl", "terraform", "aws"}
+
+// guardCommandRegex limits guarded commands to plain binary names, which
+// become shim file names
+var guardCommandRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+// guardRequestRetention is how long decided approval requests are kept
+const guardRequestRetention = 24 * time.Hour
+
+// ErrGuardRequestNotFound is returned when deciding an unknown request
+var ErrGuardRequestNotFound = errors.New("guard request not found")
+
+// GuardSettings configures the command guard: a shim directory prepended to
+// PATH inside sessions, where each guarded binary is a small script that
+// runs it through 'agent-deck guard exec'. It is a guardrail against
+// accidents, not a sandbox: an agent can still call the real binary by its
+// full path. Set globally in [guard] or per profile in [profiles.<name>.guard];
+// profile values override global ones field by field.
+type GuardSettings struct {
+	// Mode is off (default), log or approve
+	Mode string `toml:"mode"`
+
+	// Commands lists the guarded binaries (default: kubectl, terraform, aws)
+	Commands []string `toml:"commands"`
+
+	// ApprovalTimeout is how long, in seconds, a command in approve mode waits
+	// for a decision before it is denied (default: 300)
+	ApprovalTimeout int `toml:"approval_timeout"`
+}
+
+// GetMode returns the guard mode, off when unset or unknown
+func (g GuardSettings) GetMode() string {
+	switch g.Mode {
+	case GuardLog, GuardApprove:
+		return g.Mode
+	}
+	return GuardOff
+}
+
+// GetCommands returns the guarded binaries, skipping names that are not
+// plain binary names
+func (g GuardSettings) GetCommands() []string {
+	if len(g.Commands) == 0 {
+		return defaultGuardCommands
+	}
+	commands := make([]string, 0, len(g.Commands))
+	for _, name := range g.Commands {
+		if !guardCommandRegex.MatchString(name) {
+			sessionLog.Warn("guard_command_invalid", slog.String("command", name))
+			continue
+		}
+		commands = append(commands, name)
+	}
+	return commands
+}
+
+// GetApprovalTimeout returns how long a command waits for approval
+func (g GuardSettings) GetApprovalTimeout() time.Duration {
+	if g.ApprovalTimeout <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(g.ApprovalTimeout) * time.Second
+}
+
+// Guards reports whether name is one of the guarded binaries
+func (g GuardSettings) Guards(name string) bool {
+	for _, c := range g.GetCommands() {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGuardSettings returns the guard settings for a profile: the profile's
+// [profiles.<name>.guard] values layered over the global [guard] section.
+func (c *UserConfig) GetGuardSettings(profile string) GuardSettings {
+	if c == nil {
+		return GuardSettings{}
+	}
+	settings := c.Guard
+	if profile == "" || c.Profiles == nil {
+		return settings
+	}
+	override := c.Profiles[profile].Guard
+	if override.Mode != "" {
+		settings.Mode = override.Mode
+	}
+	if len(override.Commands) > 0 {
+		settings.Commands = override.Commands
+	}
+	if override.ApprovalTimeout > 0 {
+		settings.ApprovalTimeout = override.ApprovalTimeout
+	}
+	return settings
+}
+
+func guardDir() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "guard"), nil
+}
+
+// GuardShimDir returns the directory holding a profile's shims
+func GuardShimDir(profile string) (string, error) {
+	dir, err := guardDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shims", profile), nil
+}
+
+// EnsureGuardShims writes a shim for every guarded binary of the profile and
+// removes shims for binaries no longer guarded. It returns the shim directory.
+func EnsureGuardShims(profile string, settings GuardSettings) (string, error) {
+	dir, err := GuardShimDir(profile)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	// Prefer the running binary, so shims match the deck that started the session
+	bin := agentDeckBinary()
+	if bin == "" {
+		return "", errors.New("agent-deck binary not found for guard shims")
+	}
+
+	guarded := make(map[string]bool)
+	for _, name := range settings.GetCommands() {
+		guarded[name] = true
+		script := fmt.Sprintf("#!/bin/sh\n# agent-deck command guard: runs %s through 'agent-deck guard exec'\nAGENTDECK_PROFILE=%s exec %s guard exec %s \"$@\"\n",
+			name, shellQuote(profile), shellQuote(bin), name)
+		path := filepath.Join(dir, name)
+		if existing, err := os.ReadFile(path); err == nil && string(existing) == script {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			return "", fmt.Errorf("failed to write guard shim %s: %w", name, err)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if !guarded[entry.Name()] {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+	return dir, nil
+}
+
+// getGuardEnv returns the shell commands that put the profile's guard shims
+// first on PATH, or "" when the guard is off. It runs after env files and
+// init scripts so they cannot put the real binaries back in front.
+func (i *Instance) getGuardEnv() string {
+	config, _ := LoadUserConfig()
+	profile := GetEffectiveProfile("")
+	settings := config.GetGuardSettings(profile)
+	if settings.GetMode() == GuardOff {
+		return ""
+	}
+	dir, err := EnsureGuardShims(profile, settings)
+	if err != nil {
+		sessionLog.Warn("guard_shims_failed", slog.String("profile", profile), slog.String("error", err.Error()))
+		return ""
+	}
+	return fmt.Sprintf("export PATH=%s:\"$PATH\" && %s", shellQuote(dir), exportEnv(map[string]string{
+		"AGENTDECK_INSTANCE_ID": i.ID,
+		"AGENTDECK_TITLE":       i.Title,
+	}))
+}
+
+// FindGuardedBinary looks name up on PATH the way the shell would, skipping
+// the guard's shim directory
+func FindGuardedBinary(name, shimDir string) (string, error) {
+	shimDir = filepath.Clean(shimDir)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || filepath.Clean(dir) == shimDir {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+			continue
+		}
+		return path, nil
+	}
+	return "", fmt.Errorf("%s: command not found", name)
+}
+
+// GuardRequest is one run of a guarded command. In approve mode it is saved
+// until the deck decides on it.
+type GuardRequest struct {
+	ID        string    `json:"id"`
+	Profile   string    `json:"profile"`
+	SessionID string    `json:"session_id,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Command   string    `json:"command"`
+	Args      []string  `json:"args"`
+	Dir       string    `json:"dir,omitempty"`
+	Mode      string    `json:"mode"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+	DecidedAt time.Time `json:"decided_at,omitempty"`
+	DecidedBy string    `json:"decided_by,omitempty"`
+}
+
+// CommandLine renders the command and its arguments for display
+func (r *GuardRequest) CommandLine() string {
+	parts := []string{r.Command}
+	for _, arg := range r.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"$`\\|&;<>()*?") {
+			arg = shellQuote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// NewGuardRequest describes a guarded command run from the current process,
+// taking the session from the environment the shims run in
+func NewGuardRequest(profile, mode, command string, args []string) *GuardRequest {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	dir, _ := os.Getwd()
+	return &GuardRequest{
+		ID:        hex.EncodeToString(b),
+		Profile:   profile,
+		SessionID: os.Getenv("AGENTDECK_INSTANCE_ID"),
+		Title:     os.Getenv("AGENTDECK_TITLE"),
+		Command:   command,
+		Args:      args,
+		Dir:       dir,
+		Mode:      mode,
+		State:     GuardPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+func guardRequestsDir() (string, error) {
+	dir, err := guardDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "requests"), nil
+}
+
+func guardRequestPath(id string) (string, error) {
+	if !guardCommandRegex.MatchString(id) {
+		return "", fmt.Errorf("%w: %s", ErrGuardRequestNotFound, id)
+	}
+	dir, err := guardRequestsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+// SaveGuardRequest writes the request so the deck can list and decide it
+func SaveGuardRequest(req *GuardRequest) error {
+	path, err := guardRequestPath(req.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write guard request: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write guard request: %w", err)
+	}
+	return nil
+}
+
+// LoadGuardRequest reads a saved request
+func LoadGuardRequest(id string) (*GuardRequest, error) {
+	path, err := guardRequestPath(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrGuardRequestNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var req GuardRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("guard request %s: %w", id, err)
+	}
+	return &req, nil
+}
+
+// ListGuardRequests returns saved requests, oldest first, optionally only
+// the pending ones. Decided requests past their retention are removed.
+func ListGuardRequests(pendingOnly bool) ([]*GuardRequest, error) {
+	dir, err := guardRequestsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var requests []*GuardRequest
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		req, err := LoadGuardRequest(id)
+		if err != nil {
+			continue
+		}
+		if req.State != GuardPending && time.Since(req.DecidedAt) > guardRequestRetention {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+			continue
+		}
+		if pendingOnly && req.State != GuardPending {
+			continue
+		}
+		requests = append(requests, req)
+	}
+	sort.Slice(requests, func(a, b int) bool {
+		return requests[a].CreatedAt.Before(requests[b].CreatedAt)
+	})
+	return requests, nil
+}
+
+// DecideGuardRequest approves or denies a pending request
+func DecideGuardRequest(id string, approve bool, by string) (*GuardRequest, error) {
+	req, err := LoadGuardRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.State != GuardPending {
+		return req, fmt.Errorf("guard request %s is already %s", id, req.State)
+	}
+	req.State = GuardDenied
+	if approve {
+		req.State = GuardApproved
+	}
+	req.DecidedAt = time.Now()
+	req.DecidedBy = by
+	return req, SaveGuardRequest(req)
+}
+
+// WaitGuardDecision polls a saved request until it is decided or timeout
+// passes, in which case it is marked expired
+func WaitGuardDecision(req *GuardRequest, timeout, poll time.Duration) (*GuardRequest, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		current, err := LoadGuardRequest(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if current.State != GuardPending {
+			return current, nil
+		}
+		time.Sleep(poll)
+	}
+	req.State = GuardExpired
+	req.DecidedAt = time.Now()
+	return req, SaveGuardRequest(req)
+}
+
+// RecordGuardedCommand adds a guarded command run to the audit log
+func RecordGuardedCommand(db *statedb.StateDB, req *GuardRequest) error {
+	if db == nil {
+		return nil
+	}
+	detail := fmt.Sprintf("%s: %s", req.State, req.CommandLine())
+	if req.Mode == GuardLog {
+		detail = "logged: " + req.CommandLine()
+	}
+	if req.DecidedBy != "" {
+		detail += " (by " + req.DecidedBy + ")"
+	}
+	return db.AppendAudit(req.SessionID, req.Title, statedb.AuditGuardedCommand, detail, time.Now())
+}
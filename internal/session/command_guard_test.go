@@ -0,0 +1,134 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGuardSettingsProfileOverride(t *testing.T) {
+	writeMacroConfig(t, `
+[guard]
+mode = "log"
+approval_timeout = 60
+
+[profiles.work.guard]
+mode = "approve"
+commands = ["kubectl", "helm", "bad/name"]
+`)
+	config, err := LoadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	global := config.GetGuardSettings("default")
+	if global.GetMode() != GuardLog || strings.Join(global.GetCommands(), ",") != "kubectl,terraform,aws" {
+		t.Errorf("global guard = %q %v", global.GetMode(), global.GetCommands())
+	}
+	work := config.GetGuardSettings("work")
+	if work.GetMode() != GuardApprove || work.GetApprovalTimeout() != time.Minute {
+		t.Errorf("work guard = %q %s", work.GetMode(), work.GetApprovalTimeout())
+	}
+	if got := strings.Join(work.GetCommands(), ","); got != "kubectl,helm" {
+		t.Errorf("work commands = %q, want invalid names skipped", got)
+	}
+	if (GuardSettings{Mode: "sandbox"}).GetMode() != GuardOff {
+		t.Error("unknown mode should be off")
+	}
+}
+
+func TestGuardEnvPrependsShims(t *testing.T) {
+	writeMacroConfig(t, `
+[guard]
+mode = "log"
+commands = ["kubectl"]
+`)
+	t.Setenv("AGENTDECK_PROFILE", "work")
+	inst := &Instance{ID: "s1", Title: "api", Tool: "shell", ProjectPath: t.TempDir()}
+
+	env := inst.buildEnvSourceCommand()
+	shimDir, _ := GuardShimDir("work")
+	if !strings.Contains(env, "export PATH='"+shimDir+"':\"$PATH\"") || !strings.Contains(env, "AGENTDECK_INSTANCE_ID='s1'") {
+		t.Fatalf("env = %q", env)
+	}
+	script, err := os.ReadFile(filepath.Join(shimDir, "kubectl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(script), "guard exec kubectl \"$@\"") || !strings.Contains(string(script), "AGENTDECK_PROFILE='work'") {
+		t.Errorf("shim = %q", script)
+	}
+
+	// A command dropped from the list loses its shim
+	if _, err := EnsureGuardShims("work", GuardSettings{Mode: GuardLog, Commands: []string{"aws"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(shimDir, "kubectl")); !os.IsNotExist(err) {
+		t.Error("stale kubectl shim was kept")
+	}
+}
+
+func TestFindGuardedBinarySkipsShims(t *testing.T) {
+	shimDir, realDir := t.TempDir(), t.TempDir()
+	for _, dir := range []string{shimDir, realDir} {
+		if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", shimDir+string(os.PathListSeparator)+realDir)
+	got, err := FindGuardedBinary("kubectl", shimDir)
+	if err != nil || got != filepath.Join(realDir, "kubectl") {
+		t.Errorf("FindGuardedBinary = %q, %v", got, err)
+	}
+	if _, err := FindGuardedBinary("terraform", shimDir); err == nil {
+		t.Error("missing binary was found")
+	}
+}
+
+func TestGuardRequestLifecycle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AGENT_DECK_HOME", "")
+
+	req := NewGuardRequest("default", GuardApprove, "kubectl", []string{"delete", "pod", "api 1"})
+	if got := req.CommandLine(); got != "kubectl delete pod 'api 1'" {
+		t.Errorf("CommandLine = %q", got)
+	}
+	if err := SaveGuardRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := ListGuardRequests(true)
+	if err != nil || len(pending) != 1 || pending[0].ID != req.ID {
+		t.Fatalf("pending = %v, %v", pending, err)
+	}
+
+	done := make(chan *GuardRequest)
+	go func() {
+		decided, _ := WaitGuardDecision(req, 5*time.Second, 10*time.Millisecond)
+		done <- decided
+	}()
+	if _, err := DecideGuardRequest(req.ID, true, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if decided := <-done; decided == nil || decided.State != GuardApproved || decided.DecidedBy != "tester" {
+		t.Fatalf("decided = %+v", decided)
+	}
+	if _, err := DecideGuardRequest(req.ID, false, "tester"); err == nil {
+		t.Error("a decided request was decided again")
+	}
+	if _, err := DecideGuardRequest("nope", true, "tester"); !errors.Is(err, ErrGuardRequestNotFound) {
+		t.Errorf("unknown request error = %v", err)
+	}
+	if pending, _ := ListGuardRequests(true); len(pending) != 0 {
+		t.Errorf("approved request still pending: %v", pending)
+	}
+
+	expiring := NewGuardRequest("default", GuardApprove, "aws", nil)
+	if err := SaveGuardRequest(expiring); err != nil {
+		t.Fatal(err)
+	}
+	if decided, err := WaitGuardDecision(expiring, 30*time.Millisecond, 10*time.Millisecond); err != nil || decided.State != GuardExpired {
+		t.Errorf("timed out request = %+v, %v", decided, err)
+	}
+}
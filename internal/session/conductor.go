@@ -2,15 +2,20 @@ package session
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/platform"
 )
 
@@ -23,6 +28,43 @@ type ConductorSettings struct {
 	// Default: 15
 	HeartbeatInterval int `toml:"heartbeat_interval"`
 
+	// HeartbeatIntervals overrides HeartbeatInterval per profile, e.g.
+	// work = 5, personal = 60. Changes are applied to installed timers by
+	// SyncHeartbeatTimers.
+	HeartbeatIntervals map[string]int `toml:"heartbeat_intervals"`
+
+	// HeartbeatSchedule replaces the fixed interval with a systemd OnCalendar
+	// expression or a cron string (e.g. "5 9-17 * * 1-5": hourly at :05 during
+	// business hours). Conductors can override it in meta.json.
+	HeartbeatSchedule string `toml:"heartbeat_schedule"`
+
+	// DedupeWindow is the window in minutes within which identical tasks sent
+	// to a conductor (same text or dedupe key) are collapsed into one.
+	// Default: 10. A negative value disables deduplication.
+	DedupeWindow int `toml:"dedupe_window"`
+
+	// HeartbeatLock makes machines sharing a home directory (e.g. over NFS)
+	// take a lease before delivering a heartbeat, so only one machine's timer
+	// reaches the shared conductor. Default: false
+	HeartbeatLock bool `toml:"heartbeat_lock"`
+
+	// HeartbeatLockTTL is how long a heartbeat lease lasts in minutes before
+	// another machine may take over. Default: twice the heartbeat interval
+	HeartbeatLockTTL int `toml:"heartbeat_lock_ttl"`
+
+	// HeartbeatBackend picks the Linux heartbeat scheduler: "systemd" or
+	// "cron". Default: systemd when a user session is usable, else cron
+	HeartbeatBackend string `toml:"heartbeat_backend"`
+
+	// HeartbeatHistory is how many heartbeat runs each conductor keeps in
+	// heartbeats.jsonl. Default: 500. A negative value disables the history.
+	HeartbeatHistory int `toml:"heartbeat_history"`
+
+	// HeartbeatOnResume decides what happens to heartbeats that fell due
+	// while the machine slept: "missed" sends them once on resume, "skip"
+	// waits for the next timer run. Default: "missed"
+	HeartbeatOnResume string `toml:"heartbeat_on_resume"`
+
 	// Profiles is the list of agent-deck profiles to manage
 	// Kept for backward compat but ignored after migration to meta.json-based discovery
 	Profiles []string `toml:"profiles"`
@@ -32,6 +74,9 @@ type ConductorSettings struct {
 
 	// Slack defines Slack bot integration settings
 	Slack SlackSettings `toml:"slack"`
+
+	// Standup configures the scheduled cross-conductor standup
+	Standup StandupSettings `toml:"standup"`
 }
 
 // TelegramSettings defines Telegram bot configuration for the conductor bridge
@@ -66,25 +111,89 @@ type SlackSettings struct {
 
 // ConductorMeta holds metadata for a named conductor instance
 type ConductorMeta struct {
-	Name              string `json:"name"`
-	Profile           string `json:"profile"`
-	HeartbeatEnabled  bool   `json:"heartbeat_enabled"`
-	HeartbeatInterval int    `json:"heartbeat_interval"` // 0 = use global default
-	Description       string `json:"description,omitempty"`
-	CreatedAt         string `json:"created_at"`
+	Name              string   `json:"name"`
+	Profile           string   `json:"profile"`
+	HeartbeatEnabled  bool     `json:"heartbeat_enabled"`
+	HeartbeatInterval int      `json:"heartbeat_interval"`           // 0 = use global default
+	HeartbeatSchedule string   `json:"heartbeat_schedule,omitempty"` // OnCalendar or cron; overrides the interval
+	Description       string   `json:"description,omitempty"`
+	Timezone          string   `json:"timezone,omitempty"`    // IANA zone for this conductor's times (empty = [time] timezone)
+	SkillPacks        []string `json:"skill_packs,omitempty"` // attached skill packs, composed into CLAUDE.md
+	// Identity card fields, rendered into CLAUDE.md (see RenderConductorIdentity)
+	Responsibilities   []string `json:"responsibilities,omitempty"`
+	EscalationContacts []string `json:"escalation_contacts,omitempty"`
+	QuietHours         string   `json:"quiet_hours,omitempty"` // "22:00-07:00" in the conductor's timezone
+	Language           string   `json:"language,omitempty"`    // response language for replies and reports (empty = English)
+	// Notifications sends heartbeat failures and worker errors to Slack or Discord
+	Notifications *ConductorNotifications `json:"notifications,omitempty"`
+	CreatedAt     string                  `json:"created_at"`
+	// Revision counts saves; a save whose revision is behind meta.json's
+	// was loaded before another process saved and is refused
+	Revision int `json:"revision"`
+	// SchemaVersion is the meta.json schema the file was written with (see
+	// ConductorMetaSchemaVersion); older files are upgraded on load
+	SchemaVersion int `json:"schema_version"`
 }
 
 // conductorNameRegex validates conductor names: starts with alphanumeric, then alphanumeric/._-
 var conductorNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
 
-// GetHeartbeatInterval returns the heartbeat interval, defaulting to 15 minutes
-func (c *ConductorSettings) GetHeartbeatInterval() int {
+// GetHeartbeatInterval returns the heartbeat interval for conductors in
+// profile: its heartbeat_intervals entry, else heartbeat_interval, else 15
+// minutes
+func (c *ConductorSettings) GetHeartbeatInterval(profile string) int {
+	if interval := c.HeartbeatIntervals[normalizeConductorProfile(profile)]; interval > 0 {
+		return interval
+	}
 	if c.HeartbeatInterval <= 0 {
 		return 15
 	}
 	return c.HeartbeatInterval
 }
 
+// HeartbeatIntervalFor returns a conductor's heartbeat interval: meta.json's
+// heartbeat_interval, else its profile's from [conductor]
+func HeartbeatIntervalFor(meta *ConductorMeta) int {
+	if meta.HeartbeatInterval > 0 {
+		return meta.HeartbeatInterval
+	}
+	settings := GetConductorSettings()
+	return settings.GetHeartbeatInterval(meta.Profile)
+}
+
+// GetDedupeWindow returns the task deduplication window, defaulting to 10
+// minutes. Zero means deduplication is disabled.
+func (c *ConductorSettings) GetDedupeWindow() time.Duration {
+	switch {
+	case c.DedupeWindow < 0:
+		return 0
+	case c.DedupeWindow == 0:
+		return 10 * time.Minute
+	}
+	return time.Duration(c.DedupeWindow) * time.Minute
+}
+
+// GetHeartbeatHistory returns how many heartbeat runs are kept per
+// conductor, defaulting to 500. Zero means the history is disabled.
+func (c *ConductorSettings) GetHeartbeatHistory() int {
+	switch {
+	case c.HeartbeatHistory < 0:
+		return 0
+	case c.HeartbeatHistory == 0:
+		return 500
+	}
+	return c.HeartbeatHistory
+}
+
+// GetHeartbeatOnResume returns the heartbeat-on-resume policy, defaulting
+// to ResumeHeartbeatMissed
+func (c *ConductorSettings) GetHeartbeatOnResume() string {
+	if c.HeartbeatOnResume == ResumeHeartbeatSkip {
+		return ResumeHeartbeatSkip
+	}
+	return ResumeHeartbeatMissed
+}
+
 // GetProfiles returns the configured profiles, defaulting to ["default"]
 func (c *ConductorSettings) GetProfiles() []string {
 	if len(c.Profiles) == 0 {
@@ -131,6 +240,15 @@ func ConductorSessionTitle(name string) string {
 	return fmt.Sprintf("conductor-%s", name)
 }
 
+// ConductorNameFromTitle returns the conductor name for a conductor session title
+func ConductorNameFromTitle(title string) (string, bool) {
+	name, ok := strings.CutPrefix(title, "conductor-")
+	if !ok || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
 // ValidateConductorName checks that a conductor name is valid
 func ValidateConductorName(name string) error {
 	if name == "" {
@@ -169,18 +287,21 @@ func LoadConductorMeta(name string) (*ConductorMeta, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read meta.json for conductor %q: %w", name, err)
 	}
-	var meta ConductorMeta
-	if err := json.Unmarshal(data, &meta); err != nil {
+	meta, err := decodeConductorMeta(data, name)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse meta.json for conductor %q: %w", name, err)
 	}
 	if meta.Name == "" {
 		meta.Name = name
 	}
 	meta.Profile = normalizeConductorProfile(meta.Profile)
-	return &meta, nil
+	return meta, nil
 }
 
-// SaveConductorMeta writes meta.json for a conductor
+// SaveConductorMeta writes meta.json for a conductor and regenerates its
+// RUNBOOK.md. It returns a ConductorMetaConflictError if another process
+// saved meta.json after meta was loaded; use UpdateConductorMeta for
+// read-modify-write changes that should not fail that way.
 func SaveConductorMeta(meta *ConductorMeta) error {
 	if meta == nil {
 		return fmt.Errorf("conductor metadata cannot be nil")
@@ -194,16 +315,18 @@ func SaveConductorMeta(meta *ConductorMeta) error {
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("failed to create conductor dir: %w", err)
+	unlock, err := lockConductorMeta(dir)
+	if err != nil {
+		return err
 	}
-	data, err := json.MarshalIndent(meta, "", "  ")
+	err = writeConductorMeta(dir, meta)
+	unlock()
 	if err != nil {
-		return fmt.Errorf("failed to marshal meta.json: %w", err)
+		return err
 	}
-	metaPath := filepath.Join(dir, "meta.json")
-	if err := os.WriteFile(metaPath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write meta.json: %w", err)
+	// Keep the runbook in step with the settings it describes
+	if err := WriteConductorRunbook(meta); err != nil {
+		conductorLog.Warn("conductor_runbook_write_failed", slog.String("conductor", meta.Name), slog.String("error", err.Error()))
 	}
 	return nil
 }
@@ -231,15 +354,15 @@ func ListConductors() ([]ConductorMeta, error) {
 		if err != nil {
 			continue // skip dirs without meta.json
 		}
-		var meta ConductorMeta
-		if err := json.Unmarshal(data, &meta); err != nil {
+		meta, err := decodeConductorMeta(data, entry.Name())
+		if err != nil {
 			continue
 		}
 		if meta.Name == "" {
 			meta.Name = entry.Name()
 		}
 		meta.Profile = normalizeConductorProfile(meta.Profile)
-		conductors = append(conductors, meta)
+		conductors = append(conductors, *meta)
 	}
 	return conductors, nil
 }
@@ -286,10 +409,13 @@ func SetupConductor(name, profile string, heartbeatEnabled bool, description str
 	}
 	profile = normalizeConductorProfile(profile)
 
+	// Settings managed outside setup survive a re-run
+	var kept ConductorMeta
 	if existing, err := LoadConductorMeta(name); err == nil {
 		if existing.Profile != profile {
 			return fmt.Errorf("conductor %q already exists for profile %q (requested profile: %q)", name, existing.Profile, profile)
 		}
+		kept = *existing
 	}
 
 	dir, err := ConductorNameDir(name)
@@ -326,41 +452,125 @@ func SetupConductor(name, profile string, heartbeatEnabled bool, description str
 
 	// Write meta.json
 	meta := &ConductorMeta{
-		Name:             name,
-		Profile:          profile,
-		HeartbeatEnabled: heartbeatEnabled,
-		Description:      description,
-		CreatedAt:        time.Now().UTC().Format(time.RFC3339),
+		Name:               name,
+		Profile:            profile,
+		HeartbeatEnabled:   heartbeatEnabled,
+		HeartbeatInterval:  kept.HeartbeatInterval,
+		HeartbeatSchedule:  kept.HeartbeatSchedule,
+		Description:        description,
+		Timezone:           kept.Timezone,
+		SkillPacks:         kept.SkillPacks,
+		Responsibilities:   kept.Responsibilities,
+		EscalationContacts: kept.EscalationContacts,
+		QuietHours:         kept.QuietHours,
+		Language:           kept.Language,
+		Notifications:      kept.Notifications,
+		CreatedAt:          time.Now().UTC().Format(time.RFC3339),
+		Revision:           kept.Revision,
 	}
 	if err := SaveConductorMeta(meta); err != nil {
 		return fmt.Errorf("failed to write meta.json: %w", err)
 	}
 
+	if err := SyncConductorIdentity(name); err != nil && !errors.Is(err, ErrConductorClaudeMDLinked) {
+		return fmt.Errorf("failed to write identity card: %w", err)
+	}
+	// A freshly written template has no skills block; put it back
+	if len(kept.SkillPacks) > 0 {
+		if err := ComposeConductorSkills(name); err != nil {
+			return fmt.Errorf("failed to compose skill packs: %w", err)
+		}
+	}
+
 	return nil
 }
 
 // InstallHeartbeatScript writes the heartbeat.sh script for a conductor.
-// This is a standalone heartbeat that works without Telegram.
+// Timers run "agent-deck conductor heartbeat-run" directly; the script is a
+// thin wrapper kept for running a heartbeat by hand and marks the heartbeat
+// as installed.
 func InstallHeartbeatScript(name, profile string) error {
 	dir, err := ConductorNameDir(name)
 	if err != nil {
 		return err
 	}
-	profile = normalizeConductorProfile(profile)
+	scriptPath := filepath.Join(dir, "heartbeat.sh")
+	return os.WriteFile(scriptPath, []byte(renderHeartbeatScript(name, profile)), 0o755)
+}
 
+// renderHeartbeatScript returns heartbeat.sh for a conductor
+func renderHeartbeatScript(name, profile string) string {
+	profile = normalizeConductorProfile(profile)
 	script := strings.ReplaceAll(conductorHeartbeatScript, "{NAME}", name)
-	script = strings.ReplaceAll(script, "{PROFILE}", profile)
-	if profile == DefaultProfile {
-		// For default profile, omit -p flag entirely
-		script = strings.ReplaceAll(script, `-p "$PROFILE" `, "")
+	return strings.ReplaceAll(script, "{PROFILE}", profile)
+}
+
+// BuildHeartbeatMessage returns the [HEARTBEAT] check-in for a conductor in
+// the bridge's format: status counts across the profile's sessions, the
+// conductor's local time and language, and the sessions needing attention.
+// Callers refresh the instances' status first.
+func BuildHeartbeatMessage(meta *ConductorMeta, instances []*Instance, now time.Time) string {
+	counts := make(map[Status]int)
+	var waiting, errored []string
+	for _, inst := range instances {
+		status := inst.GetStatusThreadSafe()
+		counts[status]++
+		if _, isConductor := ConductorNameFromTitle(inst.Title); isConductor {
+			continue
+		}
+		detail := fmt.Sprintf("%s (project: %s)", inst.Title, inst.ProjectPath)
+		switch status {
+		case StatusWaiting:
+			waiting = append(waiting, detail)
+		case StatusError:
+			errored = append(errored, detail)
+		}
 	}
-	scriptPath := filepath.Join(dir, "heartbeat.sh")
-	return os.WriteFile(scriptPath, []byte(script), 0o755)
+
+	parts := []string{fmt.Sprintf("%s [%s] Status: %d waiting, %d running, %d idle, %d error.", heartbeatMessagePrefix,
+		meta.Name, counts[StatusWaiting], counts[StatusRunning], counts[StatusIdle], counts[StatusError])}
+	if meta.Timezone != "" {
+		if loc, err := time.LoadLocation(meta.Timezone); err == nil {
+			parts = append(parts, fmt.Sprintf("Local time: %s %s.", now.In(loc).Format("Mon 2006-01-02 15:04"), meta.Timezone))
+		}
+	}
+	if meta.Language != "" {
+		parts = append(parts, LanguageInstruction(meta.Language))
+	}
+	if len(waiting) > 0 {
+		parts = append(parts, fmt.Sprintf("Waiting sessions: %s.", strings.Join(waiting, ", ")))
+	}
+	if len(errored) > 0 {
+		parts = append(parts, fmt.Sprintf("Error sessions: %s.", strings.Join(errored, ", ")))
+	}
+	parts = append(parts, "Check if any need auto-response or user attention.")
+	return strings.Join(parts, " ")
+}
+
+// HeartbeatCommand returns the command timers run for a conductor's
+// heartbeat: the agent-deck binary itself, so no shell script or particular
+// bash is involved
+func HeartbeatCommand(name string) []string {
+	bin := findAgentDeck()
+	if bin == "" {
+		bin, _ = os.Executable()
+	}
+	return []string{bin, "conductor", "heartbeat-run", name}
+}
+
+// HeartbeatScriptInstalled reports whether a conductor has a heartbeat.sh
+func HeartbeatScriptInstalled(name string) bool {
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, "heartbeat.sh"))
+	return err == nil
 }
 
 // HeartbeatPlistLabel returns the launchd label for a conductor's heartbeat
 func HeartbeatPlistLabel(name string) string {
-	return fmt.Sprintf("com.agentdeck.conductor-heartbeat.%s", name)
+	return fmt.Sprintf("%s.conductor-heartbeat.%s", deck.LabelPrefix(), name)
 }
 
 // GenerateHeartbeatPlist returns a launchd plist for a conductor's heartbeat timer
@@ -375,7 +585,6 @@ func GenerateHeartbeatPlist(name string, intervalMinutes int) (string, error) {
 		return "", fmt.Errorf("agent-deck not found in PATH")
 	}
 
-	scriptPath := filepath.Join(dir, "heartbeat.sh")
 	logPath := filepath.Join(dir, "heartbeat.log")
 	label := HeartbeatPlistLabel(name)
 	intervalSeconds := intervalMinutes * 60
@@ -385,16 +594,51 @@ func GenerateHeartbeatPlist(name string, intervalMinutes int) (string, error) {
 		return "", err
 	}
 
-	plist := strings.ReplaceAll(conductorHeartbeatPlistTemplate, "__LABEL__", label)
-	plist = strings.ReplaceAll(plist, "__SCRIPT_PATH__", scriptPath)
+	plist := strings.ReplaceAll(withDeckEnv(conductorHeartbeatPlistTemplate), "__LABEL__", label)
+	var args strings.Builder
+	for _, arg := range append([]string{agentDeckPath}, HeartbeatCommand(name)[1:]...) {
+		fmt.Fprintf(&args, "\n        <string>%s</string>", html.EscapeString(arg))
+	}
+	plist = strings.ReplaceAll(plist, "__PROGRAM_ARGUMENTS__", args.String())
 	plist = strings.ReplaceAll(plist, "__LOG_PATH__", logPath)
 	plist = strings.ReplaceAll(plist, "__HOME__", homeDir)
-	plist = strings.ReplaceAll(plist, "__INTERVAL__", fmt.Sprintf("%d", intervalSeconds))
+	plist = strings.ReplaceAll(plist, "__SCHEDULE__", fmt.Sprintf("<key>StartInterval</key>\n    <integer>%d</integer>", intervalSeconds))
 	plist = strings.ReplaceAll(plist, "__PATH__", buildDaemonPath(agentDeckPath))
 
 	return plist, nil
 }
 
+// GenerateHeartbeatCalendarPlist returns a launchd plist that runs a
+// conductor's heartbeat on a cron schedule (StartCalendarInterval)
+func GenerateHeartbeatCalendarPlist(name, schedule string) (string, error) {
+	sched, err := ParseHeartbeatSchedule(schedule)
+	if err != nil {
+		return "", err
+	}
+	intervals, err := sched.LaunchdIntervals()
+	if err != nil {
+		return "", err
+	}
+	plist, err := GenerateHeartbeatPlist(name, 0)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("<key>StartCalendarInterval</key>\n    <array>\n")
+	for _, interval := range intervals {
+		b.WriteString("        <dict>\n")
+		for _, key := range []string{"Minute", "Hour", "Day", "Month", "Weekday"} {
+			if v, ok := interval[key]; ok {
+				fmt.Fprintf(&b, "            <key>%s</key>\n            <integer>%d</integer>\n", key, v)
+			}
+		}
+		b.WriteString("        </dict>\n")
+	}
+	b.WriteString("    </array>")
+	return strings.Replace(plist, "<key>StartInterval</key>\n    <integer>0</integer>", b.String(), 1), nil
+}
+
 // HeartbeatPlistPath returns the path where a conductor's heartbeat plist should be installed
 func HeartbeatPlistPath(name string) (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -436,6 +680,18 @@ func findAgentDeck() string {
 	return ""
 }
 
+// agentDeckBinary returns the running agent-deck binary, or the installed one
+// when running as something else (e.g. a test binary)
+func agentDeckBinary() string {
+	exe, _ := os.Executable()
+	if filepath.Base(exe) != "agent-deck" {
+		if installed := findAgentDeck(); installed != "" {
+			return installed
+		}
+	}
+	return exe
+}
+
 // buildDaemonPath returns a PATH string suitable for daemon environments.
 // If agentDeckPath is non-empty, its parent directory is prepended so daemon
 // processes (launchd, systemd) that don't inherit the user's shell PATH can
@@ -453,20 +709,32 @@ func buildDaemonPath(agentDeckPath string) string {
 	return dir + ":" + base
 }
 
+// withDeckEnv adds the variables selecting a named deck (deck.Env) next to
+// HOME in a systemd unit or launchd plist template, so the daemon talks to
+// the deck that installed it. Templates are unchanged for the default deck.
+func withDeckEnv(tmpl string) string {
+	env := deck.Env()
+	if len(env) == 0 {
+		return tmpl
+	}
+	const systemdHome = "Environment=HOME=__HOME__\n"
+	const plistHome = "        <key>HOME</key>\n        <string>__HOME__</string>\n"
+	var systemd, plist strings.Builder
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		fmt.Fprintf(&systemd, "Environment=%s\n", kv)
+		fmt.Fprintf(&plist, "        <key>%s</key>\n        <string>%s</string>\n", key, value)
+	}
+	tmpl = strings.Replace(tmpl, systemdHome, systemdHome+systemd.String(), 1)
+	return strings.Replace(tmpl, plistHome, plistHome+plist.String(), 1)
+}
+
 // conductorHeartbeatScript is the shell script that sends a heartbeat to a conductor session
 const conductorHeartbeatScript = `#!/bin/bash
 # Heartbeat for conductor: {NAME} (profile: {PROFILE})
-# Sends a check-in message to the conductor session
-
-SESSION="conductor-{NAME}"
-PROFILE="{PROFILE}"
-
-# Only send if the session is running
-STATUS=$(agent-deck -p "$PROFILE" session show "$SESSION" --json 2>/dev/null | tr -d '\n' | sed -n 's/.*"status"[[:space:]]*:[[:space:]]*"\([^"]*\)".*/\1/p')
-
-if [ "$STATUS" = "idle" ] || [ "$STATUS" = "waiting" ]; then
-    agent-deck -p "$PROFILE" session send "$SESSION" "Heartbeat: Check all sessions in the {PROFILE} profile. List any waiting sessions, auto-respond where safe, and report what needs my attention."
-fi
+# Timers run "agent-deck conductor heartbeat-run {NAME}" directly; this
+# script only runs a heartbeat by hand.
+exec agent-deck conductor heartbeat-run {NAME} "$@"
 `
 
 // conductorHeartbeatPlistTemplate is the launchd plist for a per-conductor heartbeat timer
@@ -478,13 +746,10 @@ const conductorHeartbeatPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
     <string>__LABEL__</string>
 
     <key>ProgramArguments</key>
-    <array>
-        <string>/bin/bash</string>
-        <string>__SCRIPT_PATH__</string>
+    <array>__PROGRAM_ARGUMENTS__
     </array>
 
-    <key>StartInterval</key>
-    <integer>__INTERVAL__</integer>
+    __SCHEDULE__
 
     <key>StandardOutPath</key>
     <string>__LOG_PATH__</string>
@@ -517,8 +782,17 @@ func SetupConductorProfile(profile string) error {
 
 // createSymlinkWithExpansion creates a symlink from target to source, with ~ expansion and validation.
 // target: the symlink path (e.g., ~/.agent-deck/conductor/CLAUDE.md)
-// source: the user's custom file path (e.g., ~/my/custom.md)
+// source: the user's custom file path (e.g., ~/my/custom.md), or a registry
+// template reference (registry://sre-oncall@v2) providing a file named like target
 func createSymlinkWithExpansion(target, source string) error {
+	if ref, ok := ParseRegistryRef(source); ok {
+		resolved, err := resolveRegistryTemplateFile(ref, filepath.Base(target))
+		if err != nil {
+			return err
+		}
+		source = resolved
+	}
+
 	// Expand environment variables and ~ in source path
 	source = ExpandPath(source)
 
@@ -602,6 +876,8 @@ func InstallPolicyMD(customPath string) error {
 }
 
 // TeardownConductor removes the conductor directory for a named conductor.
+// When trash is enabled the directory is moved to the trash so it can be
+// restored with Undelete; otherwise it is removed immediately.
 // It does NOT remove the session from storage (that's done by the CLI handler).
 func TeardownConductor(name string) error {
 	dir, err := ConductorNameDir(name)
@@ -611,6 +887,9 @@ func TeardownConductor(name string) error {
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		return nil // Already removed
 	}
+	if GetTrashSettings().GetEnabled() {
+		return trashConductorDir(name, dir)
+	}
 	return os.RemoveAll(dir)
 }
 
@@ -760,8 +1039,10 @@ func GetConductorSettings() ConductorSettings {
 	return config.Conductor
 }
 
-// LaunchdPlistName is the launchd label for the conductor bridge daemon
-const LaunchdPlistName = "com.agentdeck.conductor-bridge"
+// LaunchdPlistName returns the launchd label for the conductor bridge daemon
+func LaunchdPlistName() string {
+	return deck.LabelPrefix() + ".conductor-bridge"
+}
 
 // GenerateLaunchdPlist returns a launchd plist with paths substituted
 func GenerateLaunchdPlist() (string, error) {
@@ -784,7 +1065,8 @@ func GenerateLaunchdPlist() (string, error) {
 	bridgePath := filepath.Join(condDir, "bridge.py")
 	logPath := filepath.Join(condDir, "bridge.log")
 
-	plist := strings.ReplaceAll(conductorPlistTemplate, "__PYTHON3__", python3Path)
+	plist := strings.ReplaceAll(withDeckEnv(conductorPlistTemplate), "__LABEL__", LaunchdPlistName())
+	plist = strings.ReplaceAll(plist, "__PYTHON3__", python3Path)
 	plist = strings.ReplaceAll(plist, "__BRIDGE_PATH__", bridgePath)
 	plist = strings.ReplaceAll(plist, "__LOG_PATH__", logPath)
 	plist = strings.ReplaceAll(plist, "__HOME__", homeDir)
@@ -800,7 +1082,7 @@ func LaunchdPlistPath() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(homeDir, "Library", "LaunchAgents", LaunchdPlistName+".plist"), nil
+	return filepath.Join(homeDir, "Library", "LaunchAgents", LaunchdPlistName()+".plist"), nil
 }
 
 // findPython3 resolves python3 for daemon configs.
@@ -834,7 +1116,7 @@ const conductorPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 <plist version="1.0">
 <dict>
     <key>Label</key>
-    <string>com.agentdeck.conductor-bridge</string>
+    <string>__LABEL__</string>
 
     <key>ProgramArguments</key>
     <array>
@@ -906,12 +1188,25 @@ OnUnitActiveSec=__INTERVAL__s
 WantedBy=timers.target
 `
 
+// systemdHeartbeatCalendarTimerTemplate fires on an OnCalendar schedule.
+// AccuracySec=1s keeps "at :05" from drifting by systemd's default minute.
+const systemdHeartbeatCalendarTimerTemplate = `[Unit]
+Description=Agent Deck Conductor Heartbeat Timer (__NAME__)
+
+[Timer]
+OnCalendar=__CALENDAR__
+AccuracySec=1s
+
+[Install]
+WantedBy=timers.target
+`
+
 const systemdHeartbeatServiceTemplate = `[Unit]
 Description=Agent Deck Conductor Heartbeat (__NAME__)
 
 [Service]
 Type=oneshot
-ExecStart=/bin/bash __SCRIPT_PATH__
+ExecStart=__EXEC_START__
 WorkingDirectory=__HOME__
 Environment=PATH=__PATH__
 Environment=HOME=__HOME__
@@ -919,7 +1214,9 @@ Environment=HOME=__HOME__
 
 // --- Systemd path helpers ---
 
-const systemdBridgeServiceName = "agent-deck-conductor-bridge.service"
+func systemdBridgeServiceName() string {
+	return deck.UnitPrefix() + "-conductor-bridge.service"
+}
 
 // SystemdUserDir returns the systemd user unit directory (~/.config/systemd/user/)
 func SystemdUserDir() (string, error) {
@@ -936,17 +1233,17 @@ func SystemdBridgeServicePath() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, systemdBridgeServiceName), nil
+	return filepath.Join(dir, systemdBridgeServiceName()), nil
 }
 
 // SystemdHeartbeatServiceName returns the systemd service name for a conductor heartbeat
 func SystemdHeartbeatServiceName(name string) string {
-	return fmt.Sprintf("agent-deck-conductor-heartbeat-%s.service", name)
+	return fmt.Sprintf("%s-conductor-heartbeat-%s.service", deck.UnitPrefix(), name)
 }
 
 // SystemdHeartbeatTimerName returns the systemd timer name for a conductor heartbeat
 func SystemdHeartbeatTimerName(name string) string {
-	return fmt.Sprintf("agent-deck-conductor-heartbeat-%s.timer", name)
+	return fmt.Sprintf("%s-conductor-heartbeat-%s.timer", deck.UnitPrefix(), name)
 }
 
 // SystemdHeartbeatServicePath returns the full path to a heartbeat systemd service
@@ -986,7 +1283,7 @@ func GenerateSystemdBridgeService() (string, error) {
 	bridgePath := filepath.Join(condDir, "bridge.py")
 	logPath := filepath.Join(condDir, "bridge.log")
 
-	unit := strings.ReplaceAll(systemdBridgeServiceTemplate, "__PYTHON3__", python3Path)
+	unit := strings.ReplaceAll(withDeckEnv(systemdBridgeServiceTemplate), "__PYTHON3__", python3Path)
 	unit = strings.ReplaceAll(unit, "__BRIDGE_PATH__", bridgePath)
 	unit = strings.ReplaceAll(unit, "__LOG_PATH__", logPath)
 	unit = strings.ReplaceAll(unit, "__HOME__", homeDir)
@@ -1003,19 +1300,41 @@ func GenerateSystemdHeartbeatTimer(name string, intervalMinutes int) string {
 	return unit
 }
 
+// GenerateSystemdHeartbeatCalendarTimer returns a systemd timer unit that runs
+// a conductor heartbeat on a schedule (OnCalendar or cron, see
+// ParseHeartbeatSchedule), in the given timezone when one is set
+func GenerateSystemdHeartbeatCalendarTimer(name, schedule, timezone string) (string, error) {
+	sched, err := ParseHeartbeatSchedule(schedule)
+	if err != nil {
+		return "", err
+	}
+	calendar, err := sched.OnCalendar(timezone)
+	if err != nil {
+		return "", err
+	}
+	unit := strings.ReplaceAll(systemdHeartbeatCalendarTimerTemplate, "__NAME__", name)
+	unit = strings.ReplaceAll(unit, "__CALENDAR__", calendar)
+	return unit, nil
+}
+
 // GenerateSystemdHeartbeatService returns a systemd service unit for a conductor heartbeat
 func GenerateSystemdHeartbeatService(name string) (string, error) {
-	dir, err := ConductorNameDir(name)
-	if err != nil {
+	if _, err := ConductorNameDir(name); err != nil {
 		return "", err
 	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	scriptPath := filepath.Join(dir, "heartbeat.sh")
-	unit := strings.ReplaceAll(systemdHeartbeatServiceTemplate, "__NAME__", name)
-	unit = strings.ReplaceAll(unit, "__SCRIPT_PATH__", scriptPath)
+	unit := strings.ReplaceAll(withDeckEnv(systemdHeartbeatServiceTemplate), "__NAME__", name)
+	var execStart []string
+	for _, arg := range HeartbeatCommand(name) {
+		if strings.ContainsAny(arg, " \t\"'\\") {
+			arg = strconv.Quote(arg)
+		}
+		execStart = append(execStart, arg)
+	}
+	unit = strings.ReplaceAll(unit, "__EXEC_START__", strings.Join(execStart, " "))
 	unit = strings.ReplaceAll(unit, "__HOME__", homeDir)
 	agentDeckPath := findAgentDeck()
 	unit = strings.ReplaceAll(unit, "__PATH__", buildDaemonPath(agentDeckPath))
@@ -1047,20 +1366,31 @@ func systemdUserAvailable() bool {
 	return true
 }
 
-// InstallBridgeDaemon installs and starts the bridge daemon.
+// InstallBridgeDaemon installs and starts the bridge daemon, then verifies it
+// came up (see VerifyBridgeDaemon). A verification failure is returned as a
+// *BridgeDaemonError alongside the unit path.
 // macOS: launchd plist; Linux: systemd user service.
 // Returns the unit/plist file path on success.
 func InstallBridgeDaemon() (string, error) {
+	var unitPath string
+	var err error
 	plat := platform.Detect()
 	switch plat {
 	case platform.PlatformMacOS:
-		return installBridgeDaemonLaunchd()
+		unitPath, err = installBridgeDaemonLaunchd()
 	case platform.PlatformLinux, platform.PlatformWSL2:
-		return installBridgeDaemonSystemd()
+		unitPath, err = installBridgeDaemonSystemd()
 	default:
 		condDir, _ := ConductorDir()
 		return "", fmt.Errorf("unsupported platform %s for daemon management; run manually: python3 %s/bridge.py", plat, condDir)
 	}
+	if err != nil {
+		return unitPath, err
+	}
+	if err := writeBridgeStamp(currentBridgeStamp()); err != nil {
+		return unitPath, err
+	}
+	return unitPath, waitForBridgeDaemon(bridgeVerifyTimeout)
 }
 
 func installBridgeDaemonLaunchd() (string, error) {
@@ -1112,7 +1442,7 @@ func installBridgeDaemonSystemd() (string, error) {
 		condDir, _ := ConductorDir()
 		return "", fmt.Errorf("systemd user session not available (common in containers/VMs without lingering); run manually: python3 %s/bridge.py", condDir)
 	}
-	if err := exec.Command("systemctl", "--user", "enable", "--now", systemdBridgeServiceName).Run(); err != nil {
+	if err := exec.Command("systemctl", "--user", "enable", "--now", systemdBridgeServiceName()).Run(); err != nil {
 		return unitPath, fmt.Errorf("unit written but enable failed: %w", err)
 	}
 	return unitPath, nil
@@ -1120,6 +1450,7 @@ func installBridgeDaemonSystemd() (string, error) {
 
 // UninstallBridgeDaemon stops and removes the bridge daemon.
 func UninstallBridgeDaemon() error {
+	removeBridgeStamp()
 	plat := platform.Detect()
 	switch plat {
 	case platform.PlatformMacOS:
@@ -1144,7 +1475,7 @@ func uninstallBridgeDaemonLaunchd() error {
 }
 
 func uninstallBridgeDaemonSystemd() error {
-	_ = exec.Command("systemctl", "--user", "disable", "--now", systemdBridgeServiceName).Run()
+	_ = exec.Command("systemctl", "--user", "disable", "--now", systemdBridgeServiceName()).Run()
 	unitPath, err := SystemdBridgeServicePath()
 	if err != nil {
 		return err
@@ -1164,10 +1495,10 @@ func IsBridgeDaemonRunning() bool {
 	plat := platform.Detect()
 	switch plat {
 	case platform.PlatformMacOS:
-		out, err := exec.Command("launchctl", "list", LaunchdPlistName).Output()
+		out, err := exec.Command("launchctl", "list", LaunchdPlistName()).Output()
 		return err == nil && len(out) > 0
 	case platform.PlatformLinux, platform.PlatformWSL2:
-		err := exec.Command("systemctl", "--user", "is-active", "--quiet", systemdBridgeServiceName).Run()
+		err := exec.Command("systemctl", "--user", "is-active", "--quiet", systemdBridgeServiceName()).Run()
 		return err == nil
 	default:
 		return false
@@ -1194,7 +1525,7 @@ func BridgeDaemonHint() string {
 		unitPath, err := SystemdBridgeServicePath()
 		if err == nil {
 			if _, err := os.Stat(unitPath); err == nil {
-				return "Start daemon with: systemctl --user start agent-deck-conductor-bridge"
+				return "Start daemon with: systemctl --user start " + systemdBridgeServiceName()
 			}
 		}
 		return "Run 'agent-deck conductor setup <name>' to install the daemon"
@@ -1205,21 +1536,38 @@ func BridgeDaemonHint() string {
 }
 
 // InstallHeartbeatDaemon installs and starts the heartbeat timer for a conductor.
-// macOS: launchd plist; Linux: systemd timer/service pair.
-func InstallHeartbeatDaemon(name, profile string, intervalMinutes int) error {
+// macOS: launchd plist; Linux: systemd timer/service pair, or a crontab line
+// where there is no systemd user session (see HeartbeatBackend). A non-empty
+// schedule (OnCalendar or cron) replaces the fixed interval.
+func InstallHeartbeatDaemon(name, profile string, intervalMinutes int, schedule string) error {
+	var err error
 	plat := platform.Detect()
 	switch plat {
 	case platform.PlatformMacOS:
-		return installHeartbeatDaemonLaunchd(name, intervalMinutes)
+		err = installHeartbeatDaemonLaunchd(name, intervalMinutes, schedule)
 	case platform.PlatformLinux, platform.PlatformWSL2:
-		return installHeartbeatDaemonSystemd(name, intervalMinutes)
+		if HeartbeatBackend() == HeartbeatBackendCron {
+			err = installHeartbeatDaemonCron(name, intervalMinutes, schedule)
+		} else {
+			err = installHeartbeatDaemonSystemd(name, intervalMinutes, schedule)
+		}
 	default:
-		return fmt.Errorf("unsupported platform %s for heartbeat daemon; run heartbeat.sh manually via cron", plat)
+		return fmt.Errorf("unsupported platform %s for heartbeat daemon; run 'agent-deck conductor heartbeat-run %s' from cron", plat, name)
 	}
+	if err != nil {
+		return err
+	}
+	return writeHeartbeatStamp(name, currentHeartbeatStamp(name, intervalMinutes, schedule))
 }
 
-func installHeartbeatDaemonLaunchd(name string, intervalMinutes int) error {
-	plistContent, err := GenerateHeartbeatPlist(name, intervalMinutes)
+func installHeartbeatDaemonLaunchd(name string, intervalMinutes int, schedule string) error {
+	var plistContent string
+	var err error
+	if schedule != "" {
+		plistContent, err = GenerateHeartbeatCalendarPlist(name, schedule)
+	} else {
+		plistContent, err = GenerateHeartbeatPlist(name, intervalMinutes)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to generate heartbeat plist: %w", err)
 	}
@@ -1242,7 +1590,7 @@ func installHeartbeatDaemonLaunchd(name string, intervalMinutes int) error {
 	return nil
 }
 
-func installHeartbeatDaemonSystemd(name string, intervalMinutes int) error {
+func installHeartbeatDaemonSystemd(name string, intervalMinutes int, schedule string) error {
 	dir, err := SystemdUserDir()
 	if err != nil {
 		return err
@@ -1264,6 +1612,15 @@ func installHeartbeatDaemonSystemd(name string, intervalMinutes int) error {
 	}
 
 	timerContent := GenerateSystemdHeartbeatTimer(name, intervalMinutes)
+	if schedule != "" {
+		timezone := ""
+		if meta, err := LoadConductorMeta(name); err == nil {
+			timezone = meta.Timezone
+		}
+		if timerContent, err = GenerateSystemdHeartbeatCalendarTimer(name, schedule, timezone); err != nil {
+			return fmt.Errorf("failed to generate heartbeat timer: %w", err)
+		}
+	}
 	timerPath, err := SystemdHeartbeatTimerPath(name)
 	if err != nil {
 		return err
@@ -1273,29 +1630,119 @@ func installHeartbeatDaemonSystemd(name string, intervalMinutes int) error {
 	}
 
 	if !systemdUserAvailable() {
-		condDir, _ := ConductorNameDir(name)
-		return fmt.Errorf("systemd user session not available; run heartbeat manually via cron or: bash %s/heartbeat.sh", condDir)
+		return fmt.Errorf("systemd user session not available and crontab not found; run heartbeat manually: agent-deck conductor heartbeat-run %s", name)
 	}
 	timerName := SystemdHeartbeatTimerName(name)
+	// Reload and restart so a changed schedule replaces a running timer
+	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
 	if err := exec.Command("systemctl", "--user", "enable", "--now", timerName).Run(); err != nil {
 		return fmt.Errorf("failed to enable heartbeat timer: %w", err)
 	}
+	_ = exec.Command("systemctl", "--user", "restart", timerName).Run()
 	return nil
 }
 
 // UninstallHeartbeatDaemon stops and removes the heartbeat timer for a conductor.
 func UninstallHeartbeatDaemon(name string) error {
+	if path, err := heartbeatStampPath(name); err == nil {
+		_ = os.Remove(path)
+	}
 	plat := platform.Detect()
 	switch plat {
 	case platform.PlatformMacOS:
 		return uninstallHeartbeatDaemonLaunchd(name)
 	case platform.PlatformLinux, platform.PlatformWSL2:
+		// Remove both so switching backends leaves nothing behind
+		if cronAvailable() {
+			if err := uninstallHeartbeatDaemonCron(name); err != nil {
+				return err
+			}
+		}
 		return uninstallHeartbeatDaemonSystemd(name)
 	default:
 		return nil
 	}
 }
 
+// heartbeatStamp records the settings a conductor's heartbeat timer was
+// installed with, so SyncHeartbeatTimers can tell when they changed
+type heartbeatStamp struct {
+	Backend  string `json:"backend"`
+	Interval int    `json:"interval"`
+	Schedule string `json:"schedule,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+func currentHeartbeatStamp(name string, intervalMinutes int, schedule string) heartbeatStamp {
+	stamp := heartbeatStamp{Backend: HeartbeatBackend(), Interval: intervalMinutes, Schedule: schedule}
+	if meta, err := LoadConductorMeta(name); err == nil {
+		stamp.Timezone = meta.Timezone
+	}
+	return stamp
+}
+
+func heartbeatStampPath(name string) (string, error) {
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "heartbeat.stamp.json"), nil
+}
+
+func writeHeartbeatStamp(name string, stamp heartbeatStamp) error {
+	path, err := heartbeatStampPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(stamp)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func readHeartbeatStamp(name string) (heartbeatStamp, bool) {
+	var stamp heartbeatStamp
+	path, err := heartbeatStampPath(name)
+	if err != nil {
+		return stamp, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return stamp, false
+	}
+	return stamp, json.Unmarshal(data, &stamp) == nil
+}
+
+// SyncHeartbeatTimers reinstalls the heartbeat timers whose interval,
+// schedule, timezone or backend no longer match the current settings (e.g.
+// after [conductor] heartbeat_intervals changed) and returns the names of
+// the conductors it updated. Timers installed before stamps were recorded
+// are reinstalled once.
+func SyncHeartbeatTimers() ([]string, error) {
+	conductors, err := ListConductors()
+	if err != nil {
+		return nil, err
+	}
+	var updated []string
+	var errs []error
+	for _, meta := range conductors {
+		if !meta.HeartbeatEnabled || !HeartbeatScriptInstalled(meta.Name) {
+			continue
+		}
+		interval, schedule := HeartbeatIntervalFor(&meta), EffectiveHeartbeatSchedule(&meta)
+		if stamp, ok := readHeartbeatStamp(meta.Name); ok && stamp == currentHeartbeatStamp(meta.Name, interval, schedule) {
+			continue
+		}
+		if err := InstallHeartbeatDaemon(meta.Name, meta.Profile, interval, schedule); err != nil {
+			errs = append(errs, fmt.Errorf("heartbeat %s: %w", meta.Name, err))
+			continue
+		}
+		updated = append(updated, meta.Name)
+	}
+	return updated, errors.Join(errs...)
+}
+
 func uninstallHeartbeatDaemonLaunchd(name string) error {
 	hbPlistPath, err := HeartbeatPlistPath(name)
 	if err != nil {
@@ -0,0 +1,426 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dispatch task states. Each state is a directory under the conductor's
+// tasks/ queue, so claiming or completing a task is a rename that only one
+// process can win.
+const (
+	TaskPending   = "pending"   // queued, waiting for the worker to be free
+	TaskActive    = "active"    // prompt injected into the worker session
+	TaskDone      = "done"      // worker finished; Result holds its last reply
+	TaskFailed    = "failed"    // could not be delivered or the worker errored
+	TaskCancelled = "cancelled" // cancelled before or while the worker ran it
+)
+
+// taskResultMaxLen caps the worker reply kept on a completed task
+const taskResultMaxLen = 4000
+
+var ErrTaskNotFound = errors.New("task not found")
+
+// ConductorTask is a prompt a conductor hands to a worker session
+type ConductorTask struct {
+	ID          string    `json:"id"`
+	Conductor   string    `json:"conductor"`
+	Worker      string    `json:"worker"` // worker session title or ID
+	Prompt      string    `json:"prompt"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	ClaimedAt   time.Time `json:"claimed_at,omitzero"`
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	Result      string    `json:"result,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ConductorTasksDir returns a conductor's task queue directory
+// (~/.agent-deck/conductor/<name>/tasks)
+func ConductorTasksDir(conductor string) (string, error) {
+	dir, err := ConductorNameDir(conductor)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tasks"), nil
+}
+
+// taskStateDir maps a task state to its queue directory; failed and
+// cancelled tasks are kept with the done ones
+func taskStateDir(conductor, state string) (string, error) {
+	base, err := ConductorTasksDir(conductor)
+	if err != nil {
+		return "", err
+	}
+	if state == TaskFailed || state == TaskCancelled {
+		state = TaskDone
+	}
+	return filepath.Join(base, state), nil
+}
+
+// EnqueueTask queues a prompt for a worker session. The conductor's next
+// heartbeat injects it once the worker is idle.
+func EnqueueTask(conductor, worker, prompt string) (*ConductorTask, error) {
+	if !IsConductorSetup(conductor) {
+		return nil, fmt.Errorf("conductor %q is not set up", conductor)
+	}
+	if strings.TrimSpace(worker) == "" || strings.TrimSpace(prompt) == "" {
+		return nil, fmt.Errorf("task needs a worker session and a prompt")
+	}
+	now := time.Now().UTC()
+	task := &ConductorTask{
+		// Time-ordered IDs keep each queue directory in FIFO order
+		ID:        fmt.Sprintf("%s-%s", now.Format("20060102T150405.000000000"), randomString(6)),
+		Conductor: conductor,
+		Worker:    worker,
+		Prompt:    prompt,
+		State:     TaskPending,
+		CreatedAt: now,
+	}
+	if err := writeConductorTask(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// ListTasks returns a conductor's tasks in the given state (all states when
+// empty), oldest first
+func ListTasks(conductor, state string) ([]ConductorTask, error) {
+	states := []string{TaskPending, TaskActive, TaskDone}
+	if state != "" {
+		states = []string{state}
+	}
+	var tasks []ConductorTask
+	for _, s := range states {
+		dir, err := taskStateDir(conductor, s)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read task queue: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+				continue
+			}
+			task, err := readConductorTask(filepath.Join(dir, e.Name()))
+			if err != nil {
+				continue // a task being written or renamed
+			}
+			if state == "" || task.State == state {
+				tasks = append(tasks, *task)
+			}
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
+}
+
+// ClaimTask moves a pending task to active. Only one caller can claim a
+// task; the others get ErrTaskNotFound.
+func ClaimTask(conductor, id string) (*ConductorTask, error) {
+	pendingDir, err := taskStateDir(conductor, TaskPending)
+	if err != nil {
+		return nil, err
+	}
+	activeDir, err := taskStateDir(conductor, TaskActive)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(activeDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create task queue: %w", err)
+	}
+	activePath := filepath.Join(activeDir, id+".json")
+	if err := os.Rename(filepath.Join(pendingDir, id+".json"), activePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: no pending task %s", ErrTaskNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+	task, err := readConductorTask(activePath)
+	if err != nil {
+		return nil, err
+	}
+	task.State = TaskActive
+	task.ClaimedAt = time.Now().UTC()
+	if err := writeConductorTask(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// CompleteTask finishes an active task with the worker's reply, or marks it
+// failed when taskErr is set
+func CompleteTask(conductor, id, result string, taskErr error) (*ConductorTask, error) {
+	activeDir, err := taskStateDir(conductor, TaskActive)
+	if err != nil {
+		return nil, err
+	}
+	activePath := filepath.Join(activeDir, id+".json")
+	task, err := readConductorTask(activePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: no active task %s", ErrTaskNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	task.State = TaskDone
+	task.CompletedAt = time.Now().UTC()
+	if len(result) > taskResultMaxLen {
+		result = result[:taskResultMaxLen] + "…"
+	}
+	task.Result = result
+	if taskErr != nil {
+		task.State = TaskFailed
+		task.Error = taskErr.Error()
+	}
+	if err := writeConductorTask(task); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(activePath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove active task: %w", err)
+	}
+	return task, nil
+}
+
+// CancelTask cancels a pending or active task. A pending task is simply
+// never dispatched; for an active one the worker is interrupted (see
+// Instance.Interrupt) and, when followUp is set, sent it afterwards (e.g.
+// InterruptSummaryPrompt). The task is cancelled even if the interrupt
+// fails, which is returned alongside it.
+func CancelTask(conductor, id string, instances []*Instance, followUp string) (*ConductorTask, error) {
+	doneDir, err := taskStateDir(conductor, TaskCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(doneDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create task queue: %w", err)
+	}
+	donePath := filepath.Join(doneDir, id+".json")
+
+	// Moving the pending file out first keeps a concurrent dispatch from
+	// claiming it
+	pendingDir, err := taskStateDir(conductor, TaskPending)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Rename(filepath.Join(pendingDir, id+".json"), donePath)
+	if err == nil {
+		task, err := readConductorTask(donePath)
+		if err != nil {
+			return nil, err
+		}
+		return task, finishCancelledTask(task)
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to cancel task: %w", err)
+	}
+
+	activeDir, err := taskStateDir(conductor, TaskActive)
+	if err != nil {
+		return nil, err
+	}
+	activePath := filepath.Join(activeDir, id+".json")
+	task, err := readConductorTask(activePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: no pending or active task %s", ErrTaskNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Interrupt before giving the file up, so the heartbeat cannot complete
+	// the task with a half-written reply in between
+	var interruptErr error
+	worker := findTaskWorker(instances, task.Worker)
+	if worker != nil && worker.Exists() {
+		interruptErr = worker.Interrupt("")
+	}
+	if interruptErr != nil {
+		task.Error = interruptErr.Error()
+	}
+	if err := finishCancelledTask(task); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(activePath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove active task: %w", err)
+	}
+	if interruptErr == nil && followUp != "" && worker != nil && worker.Exists() {
+		interruptErr = worker.sendAfterInterrupt(followUp)
+	}
+	return task, interruptErr
+}
+
+// finishCancelledTask records a task as cancelled in the done queue
+func finishCancelledTask(task *ConductorTask) error {
+	task.State = TaskCancelled
+	task.CompletedAt = time.Now().UTC()
+	return writeConductorTask(task)
+}
+
+// TaskDispatchReport is what one DispatchConductorTasks pass did
+type TaskDispatchReport struct {
+	Dispatched []ConductorTask `json:"dispatched"`
+	Completed  []ConductorTask `json:"completed"` // done or failed
+}
+
+// DispatchConductorTasks runs one pass over a conductor's queue, as each
+// heartbeat does: active tasks whose worker has gone idle are completed with
+// its last reply, then the oldest pending task of each free worker is
+// claimed and its prompt injected with send (tmux send-keys when nil). A
+// worker runs one task at a time. Instance statuses must be current.
+// Nothing moves while the deck is emergency-stopped.
+func DispatchConductorTasks(conductor string, instances []*Instance, send func(inst *Instance, prompt string) error) (*TaskDispatchReport, error) {
+	if send == nil {
+		send = injectTaskPrompt
+	}
+	report := &TaskDispatchReport{}
+	if LoadEmergencyStop() != nil {
+		return report, nil
+	}
+
+	active, err := ListTasks(conductor, TaskActive)
+	if err != nil {
+		return nil, err
+	}
+	busy := make(map[*Instance]bool)
+	for _, task := range active {
+		worker := findTaskWorker(instances, task.Worker)
+		var result string
+		var taskErr error
+		switch {
+		case worker == nil:
+			taskErr = fmt.Errorf("worker session %q no longer exists", task.Worker)
+		case worker.GetStatusThreadSafe() == StatusError:
+			taskErr = fmt.Errorf("worker session %q is in error state", task.Worker)
+		case !taskWorkerFree(worker):
+			busy[worker] = true
+			continue
+		default:
+			if resp, err := worker.GetLastResponse(); err == nil {
+				result = resp.Content
+			}
+		}
+		done, err := CompleteTask(conductor, task.ID, result, taskErr)
+		if err != nil {
+			continue // completed by a concurrent pass
+		}
+		report.Completed = append(report.Completed, *done)
+	}
+
+	pending, err := ListTasks(conductor, TaskPending)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range pending {
+		worker := findTaskWorker(instances, task.Worker)
+		if worker == nil || busy[worker] || !taskWorkerFree(worker) {
+			continue // stays queued until the worker exists and is free
+		}
+		busy[worker] = true
+		claimed, err := ClaimTask(conductor, task.ID)
+		if err != nil {
+			continue
+		}
+		if err := send(worker, claimed.Prompt); err != nil {
+			if failed, cerr := CompleteTask(conductor, claimed.ID, "", fmt.Errorf("failed to send prompt: %w", err)); cerr == nil {
+				report.Completed = append(report.Completed, *failed)
+			}
+			continue
+		}
+		report.Dispatched = append(report.Dispatched, *claimed)
+	}
+	return report, nil
+}
+
+// injectTaskPrompt types a task prompt into the worker's tmux pane
+func injectTaskPrompt(inst *Instance, prompt string) error {
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil || !inst.Exists() {
+		return fmt.Errorf("session %q is not running", inst.Title)
+	}
+	return tmuxSess.SendKeysAndEnter(prompt)
+}
+
+// taskWorkerFree reports whether a worker session can take a task: running
+// and not busy
+func taskWorkerFree(inst *Instance) bool {
+	status := inst.GetStatusThreadSafe()
+	return status == StatusIdle || status == StatusWaiting
+}
+
+// findTaskWorker resolves a task's worker by session ID or title
+func findTaskWorker(instances []*Instance, worker string) *Instance {
+	for _, inst := range instances {
+		if inst.ID == worker {
+			return inst
+		}
+	}
+	for _, inst := range instances {
+		if inst.Title == worker {
+			return inst
+		}
+	}
+	return nil
+}
+
+func readConductorTask(path string) (*ConductorTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task: %w", err)
+	}
+	var task ConductorTask
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse task %s: %w", filepath.Base(path), err)
+	}
+	return &task, nil
+}
+
+// writeConductorTask writes a task into its state directory through a temp
+// file, so readers never see a partial task
+func writeConductorTask(task *ConductorTask) error {
+	dir, err := taskStateDir(task.Conductor, task.State)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create task queue: %w", err)
+	}
+	data, err := json.MarshalIndent(task, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	tmp := filepath.Join(dir, "."+task.ID+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, task.ID+".json")); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+	return nil
+}
+
+// Summary tells the conductor which of its tasks finished, for the heartbeat
+// message. Empty when none did.
+func (r *TaskDispatchReport) Summary() string {
+	if r == nil || len(r.Completed) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(r.Completed))
+	for _, task := range r.Completed {
+		parts = append(parts, fmt.Sprintf("%s on %s %s", task.ID, task.Worker, task.State))
+	}
+	return fmt.Sprintf("Tasks finished: %s (see 'agent-deck conductor task show %s <id>').",
+		strings.Join(parts, ", "), r.Completed[0].Conductor)
+}
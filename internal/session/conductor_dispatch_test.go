@@ -0,0 +1,210 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConductorTaskQueue_ClaimAndComplete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := EnqueueTask("ops", "api", "run tests"); err == nil {
+		t.Error("enqueue for a missing conductor should fail")
+	}
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := EnqueueTask("ops", "api", "run tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := EnqueueTask("ops", "web", "fix lint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := ListTasks("ops", TaskPending)
+	if len(pending) != 2 || pending[0].ID != first.ID || pending[1].ID != second.ID {
+		t.Fatalf("pending = %+v", pending)
+	}
+
+	claimed, err := ClaimTask("ops", first.ID)
+	if err != nil || claimed.State != TaskActive || claimed.ClaimedAt.IsZero() {
+		t.Fatalf("claim = %+v, %v", claimed, err)
+	}
+	if _, err := ClaimTask("ops", first.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("second claim = %v", err)
+	}
+
+	done, err := CompleteTask("ops", first.ID, "all green", nil)
+	if err != nil || done.State != TaskDone || done.Result != "all green" {
+		t.Fatalf("complete = %+v, %v", done, err)
+	}
+	if _, err := CompleteTask("ops", first.ID, "", nil); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("second complete = %v", err)
+	}
+	if _, err := ClaimTask("ops", second.ID); err != nil {
+		t.Fatal(err)
+	}
+	failed, err := CompleteTask("ops", second.ID, "", errors.New("worker gone"))
+	if err != nil || failed.State != TaskFailed || failed.Error != "worker gone" {
+		t.Fatalf("fail = %+v, %v", failed, err)
+	}
+
+	all, _ := ListTasks("ops", "")
+	if len(all) != 2 {
+		t.Errorf("all tasks = %+v", all)
+	}
+	if f, _ := ListTasks("ops", TaskFailed); len(f) != 1 || f[0].ID != second.ID {
+		t.Errorf("failed tasks = %+v", f)
+	}
+}
+
+func TestDispatchConductorTasks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	project := t.TempDir()
+	api := &Instance{ID: "api-1", Title: "api", Tool: "aider", ProjectPath: project, Status: StatusIdle}
+	web := &Instance{ID: "web-1", Title: "web", Tool: "shell", Status: StatusRunning}
+	instances := []*Instance{api, web}
+
+	var sent []string
+	send := func(inst *Instance, prompt string) error {
+		sent = append(sent, inst.Title+": "+prompt)
+		return nil
+	}
+	first, _ := EnqueueTask("ops", "api", "run tests")
+	_, _ = EnqueueTask("ops", "api-1", "then deploy")
+	_, _ = EnqueueTask("ops", "web", "fix lint")
+	orphan, _ := EnqueueTask("ops", "gone", "never sent")
+
+	// One task per free worker; busy and missing workers keep theirs queued
+	report, err := DispatchConductorTasks("ops", instances, send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0] != "api: run tests" || len(report.Dispatched) != 1 || len(report.Completed) != 0 {
+		t.Fatalf("first pass sent %v, report %+v", sent, report)
+	}
+
+	// Still working: nothing completes and nothing new is sent
+	api.Status = StatusRunning
+	if report, _ = DispatchConductorTasks("ops", instances, send); len(report.Completed) != 0 || len(sent) != 1 {
+		t.Fatalf("busy pass: sent %v, report %+v", sent, report)
+	}
+
+	// Finished: the task completes with the last reply and the next one goes out
+	history := "# aider chat started at 2026-03-09 09:00:00\n\n#### run tests\n\nAll 42 tests pass.\n"
+	if err := os.WriteFile(filepath.Join(project, ".aider.chat.history.md"), []byte(history), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	api.Status = StatusWaiting
+	report, err = DispatchConductorTasks("ops", instances, send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Completed) != 1 || report.Completed[0].ID != first.ID || report.Completed[0].Result != "All 42 tests pass." {
+		t.Fatalf("completed = %+v", report.Completed)
+	}
+	if len(sent) != 2 || sent[1] != "api: then deploy" {
+		t.Errorf("sent = %v", sent)
+	}
+	if summary := report.Summary(); summary == "" {
+		t.Error("summary should list the finished task")
+	}
+
+	// A failed injection fails the task instead of leaving it active
+	web.Status = StatusIdle
+	api.Status = StatusRunning
+	report, _ = DispatchConductorTasks("ops", instances, func(*Instance, string) error { return errors.New("pane closed") })
+	if len(report.Completed) != 1 || report.Completed[0].State != TaskFailed {
+		t.Errorf("failed send = %+v", report.Completed)
+	}
+	if pending, _ := ListTasks("ops", TaskPending); len(pending) != 1 || pending[0].ID != orphan.ID {
+		t.Errorf("pending = %+v", pending)
+	}
+}
+
+func TestCancelTask(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	queued, _ := EnqueueTask("ops", "api", "run tests")
+	cancelled, err := CancelTask("ops", queued.ID, nil, InterruptSummaryPrompt)
+	if err != nil || cancelled.State != TaskCancelled || cancelled.CompletedAt.IsZero() {
+		t.Fatalf("cancel pending = %+v, %v", cancelled, err)
+	}
+	if _, err := ClaimTask("ops", queued.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("a cancelled task must not be claimed, got %v", err)
+	}
+
+	// An active task whose worker is gone is cancelled without an interrupt
+	running, _ := EnqueueTask("ops", "web", "fix lint")
+	if _, err := ClaimTask("ops", running.ID); err != nil {
+		t.Fatal(err)
+	}
+	cancelled, err = CancelTask("ops", running.ID, nil, "")
+	if err != nil || cancelled.State != TaskCancelled {
+		t.Fatalf("cancel active = %+v, %v", cancelled, err)
+	}
+	if active, _ := ListTasks("ops", TaskActive); len(active) != 0 {
+		t.Errorf("active tasks after cancel = %+v", active)
+	}
+	if c, _ := ListTasks("ops", TaskCancelled); len(c) != 2 {
+		t.Errorf("cancelled tasks = %+v", c)
+	}
+	if _, err := CancelTask("ops", running.ID, nil, ""); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("second cancel = %v", err)
+	}
+}
+
+func TestCancelTask_InterruptsWorker(t *testing.T) {
+	skipIfNoTmuxServer(t)
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	worker := NewInstanceWithTool("cancel-worker", t.TempDir(), "shell")
+	worker.Command = "sleep 300"
+	if err := worker.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = worker.Kill() }()
+
+	task, _ := EnqueueTask("ops", worker.Title, "long job")
+	if _, err := ClaimTask("ops", task.ID); err != nil {
+		t.Fatal(err)
+	}
+	cancelled, err := CancelTask("ops", task.ID, []*Instance{worker}, "")
+	if err != nil || cancelled.State != TaskCancelled || cancelled.Error != "" {
+		t.Fatalf("cancel = %+v, %v", cancelled, err)
+	}
+
+	// shell has no interrupt macro, so the worker got Ctrl-C
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		content, _ := worker.GetTmuxSession().CapturePane()
+		if strings.Contains(content, "^C") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no ^C in the worker pane:\n%s", content)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestInterrupt_NotRunning(t *testing.T) {
+	inst := &Instance{ID: "x", Title: "stopped", Tool: "claude"}
+	if err := inst.Interrupt(""); err == nil {
+		t.Error("interrupting a session that is not running should fail")
+	}
+}
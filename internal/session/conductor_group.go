@@ -0,0 +1,232 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+// conductorGroupsFileName holds every conductor group, next to the
+// conductor directories (a file, so it cannot clash with a conductor name)
+const conductorGroupsFileName = "groups.json"
+
+var (
+	ErrConductorGroupNotFound = errors.New("conductor group not found")
+	ErrConductorGroupExists   = errors.New("conductor group already exists")
+)
+
+// ConductorGroup is a set of cooperating conductors (e.g. SRE, QA and
+// release) that start, stop and heartbeat together
+type ConductorGroup struct {
+	Name        string   `json:"name"`
+	Members     []string `json:"members"` // conductor names, in heartbeat order
+	Description string   `json:"description,omitempty"`
+	// HeartbeatInterval is the group-wide heartbeat period in minutes. When
+	// set, starting the group staggers the members' heartbeats across it;
+	// 0 leaves each member's own interval or schedule alone.
+	HeartbeatInterval int `json:"heartbeat_interval,omitempty"`
+	// Stagger is the minutes between consecutive members' heartbeats
+	// (0 = spread evenly over the interval)
+	Stagger   int    `json:"stagger,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ConductorGroupsPath returns the path of groups.json
+func ConductorGroupsPath() (string, error) {
+	base, err := ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, conductorGroupsFileName), nil
+}
+
+// LoadConductorGroups returns every conductor group, sorted by name
+func LoadConductorGroups() ([]ConductorGroup, error) {
+	path, err := ConductorGroupsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", conductorGroupsFileName, err)
+	}
+	var groups []ConductorGroup
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", conductorGroupsFileName, err)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// LoadConductorGroup returns the named conductor group
+func LoadConductorGroup(name string) (*ConductorGroup, error) {
+	groups, err := LoadConductorGroups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		if groups[i].Name == name {
+			return &groups[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrConductorGroupNotFound, name)
+}
+
+// CreateConductorGroup validates and saves a new conductor group
+func CreateConductorGroup(group *ConductorGroup) error {
+	if err := group.Validate(); err != nil {
+		return err
+	}
+	groups, err := LoadConductorGroups()
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if g.Name == group.Name {
+			return fmt.Errorf("%w: %s", ErrConductorGroupExists, group.Name)
+		}
+	}
+	if group.CreatedAt == "" {
+		group.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	return saveConductorGroups(append(groups, *group))
+}
+
+// DeleteConductorGroup removes a conductor group. Its members are left as
+// they are.
+func DeleteConductorGroup(name string) error {
+	groups, err := LoadConductorGroups()
+	if err != nil {
+		return err
+	}
+	kept := slices.DeleteFunc(groups, func(g ConductorGroup) bool { return g.Name == name })
+	if len(kept) == len(groups) {
+		return fmt.Errorf("%w: %s", ErrConductorGroupNotFound, name)
+	}
+	return saveConductorGroups(kept)
+}
+
+// RemoveConductorFromGroups drops a conductor from every group it belongs
+// to, e.g. when it is torn down
+func RemoveConductorFromGroups(conductor string) error {
+	groups, err := LoadConductorGroups()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i := range groups {
+		members := slices.DeleteFunc(slices.Clone(groups[i].Members), func(m string) bool { return m == conductor })
+		if len(members) != len(groups[i].Members) {
+			groups[i].Members = members
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return saveConductorGroups(groups)
+}
+
+func saveConductorGroups(groups []ConductorGroup) error {
+	path, err := ConductorGroupsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create conductor dir: %w", err)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	data, err := json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", conductorGroupsFileName, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", conductorGroupsFileName, err)
+	}
+	return nil
+}
+
+// Validate checks the group's name, members and heartbeat stagger
+func (g *ConductorGroup) Validate() error {
+	if err := ValidateConductorName(g.Name); err != nil {
+		return fmt.Errorf("invalid group name: %w", err)
+	}
+	if len(g.Members) == 0 {
+		return fmt.Errorf("group %q has no members", g.Name)
+	}
+	seen := make(map[string]bool, len(g.Members))
+	for _, member := range g.Members {
+		if seen[member] {
+			return fmt.Errorf("conductor %q is listed twice in group %q", member, g.Name)
+		}
+		seen[member] = true
+		if !IsConductorSetup(member) {
+			return fmt.Errorf("conductor %q is not set up", member)
+		}
+	}
+	if g.HeartbeatInterval < 0 || g.Stagger < 0 {
+		return fmt.Errorf("heartbeat interval and stagger cannot be negative")
+	}
+	if g.Stagger > 0 && g.HeartbeatInterval == 0 {
+		return fmt.Errorf("a stagger needs a group heartbeat interval")
+	}
+	_, err := g.HeartbeatSchedules()
+	return err
+}
+
+// HeartbeatSchedules returns each member's staggered heartbeat schedule as a
+// cron string: member i fires i*stagger minutes into every interval. The
+// interval must divide an hour (5, 10, 15, 20, 30, 60) or a day in whole
+// hours, so every period lines up on the clock. Returns nil when the group
+// has no heartbeat interval.
+func (g *ConductorGroup) HeartbeatSchedules() (map[string]string, error) {
+	interval := g.HeartbeatInterval
+	if interval == 0 {
+		return nil, nil
+	}
+	if !(interval <= 60 && 60%interval == 0) && !(interval%60 == 0 && (24*60)%interval == 0) {
+		return nil, fmt.Errorf("group heartbeat interval %d must divide an hour or a day in whole hours", interval)
+	}
+	stagger := g.stagger()
+	schedules := make(map[string]string, len(g.Members))
+	for i, member := range g.Members {
+		offset := (i * stagger) % interval
+		var expr string
+		switch {
+		case interval < 60:
+			expr = fmt.Sprintf("%d-59/%d * * * *", offset, interval)
+		case interval == 60:
+			expr = fmt.Sprintf("%d * * * *", offset)
+		default:
+			hours := interval / 60
+			expr = fmt.Sprintf("%d %d-23/%d * * *", offset%60, offset/60, hours)
+		}
+		schedules[member] = expr
+	}
+	return schedules, nil
+}
+
+// HeartbeatPlan renders the group's heartbeat stagger for listings
+func (g *ConductorGroup) HeartbeatPlan() string {
+	if g.HeartbeatInterval == 0 {
+		return "members' own"
+	}
+	return fmt.Sprintf("every %d min, %d min apart", g.HeartbeatInterval, g.stagger())
+}
+
+// stagger returns the minutes between members' heartbeats, spreading them
+// evenly over the interval unless set
+func (g *ConductorGroup) stagger() int {
+	if g.Stagger > 0 {
+		return g.Stagger
+	}
+	return max(g.HeartbeatInterval/max(len(g.Members), 1), 1)
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestConductorGroup_CreateAndRemoveMember(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, name := range []string{"sre", "qa", "release"} {
+		if err := SetupConductor(name, "work", true, "", "", ""); err != nil {
+			t.Fatalf("SetupConductor(%s): %v", name, err)
+		}
+	}
+
+	group := &ConductorGroup{Name: "oncall", Members: []string{"sre", "qa", "release"}, HeartbeatInterval: 15}
+	if err := CreateConductorGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateConductorGroup(group); !errors.Is(err, ErrConductorGroupExists) {
+		t.Errorf("duplicate create = %v", err)
+	}
+	for _, bad := range []*ConductorGroup{
+		{Name: "empty"},
+		{Name: "ghost", Members: []string{"sre", "nope"}},
+		{Name: "twice", Members: []string{"qa", "qa"}},
+		{Name: "odd", Members: []string{"qa"}, HeartbeatInterval: 25},
+		{Name: "loose", Members: []string{"qa"}, Stagger: 5},
+	} {
+		if err := CreateConductorGroup(bad); err == nil {
+			t.Errorf("group %q should be rejected", bad.Name)
+		}
+	}
+
+	if err := RemoveConductorFromGroups("qa"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadConductorGroup("oncall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.Members, []string{"sre", "release"}) || loaded.CreatedAt == "" {
+		t.Errorf("group after removing qa = %+v", loaded)
+	}
+
+	if err := DeleteConductorGroup("oncall"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConductorGroup("oncall"); !errors.Is(err, ErrConductorGroupNotFound) {
+		t.Errorf("load after delete = %v", err)
+	}
+}
+
+func TestConductorGroup_HeartbeatSchedules(t *testing.T) {
+	tests := []struct {
+		group ConductorGroup
+		want  map[string]string
+	}{
+		{
+			// Spread evenly: 15 minutes over three members
+			ConductorGroup{Members: []string{"sre", "qa", "release"}, HeartbeatInterval: 15},
+			map[string]string{"sre": "0-59/15 * * * *", "qa": "5-59/15 * * * *", "release": "10-59/15 * * * *"},
+		},
+		{
+			ConductorGroup{Members: []string{"sre", "qa"}, HeartbeatInterval: 60, Stagger: 2},
+			map[string]string{"sre": "0 * * * *", "qa": "2 * * * *"},
+		},
+		{
+			// Offsets wrap around the interval and past the hour
+			ConductorGroup{Members: []string{"a", "b", "c"}, HeartbeatInterval: 120, Stagger: 70},
+			map[string]string{"a": "0 0-23/2 * * *", "b": "10 1-23/2 * * *", "c": "20 0-23/2 * * *"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := tt.group.HeartbeatSchedules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for member, want := range tt.want {
+			if got[member] != want {
+				t.Errorf("interval %d, stagger %d: %s = %q, want %q", tt.group.HeartbeatInterval, tt.group.Stagger, member, got[member], want)
+			}
+			if _, err := ParseHeartbeatSchedule(got[member]); err != nil {
+				t.Errorf("schedule %q does not parse: %v", got[member], err)
+			}
+		}
+	}
+
+	if got, err := (&ConductorGroup{Members: []string{"sre"}}).HeartbeatSchedules(); got != nil || err != nil {
+		t.Errorf("no interval = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HeartbeatTiming is when a conductor's heartbeat last ran and runs next
+type HeartbeatTiming struct {
+	LastRun    time.Time `json:"last_run,omitzero"`
+	LastResult string    `json:"last_result,omitempty"` // sent, skipped or failed; empty when only heartbeat.log was seen
+	NextRun    time.Time `json:"next_run,omitzero"`
+}
+
+// ConductorHeartbeatTiming returns when a conductor's heartbeat last ran (the
+// last recorded heartbeat result, else heartbeat.log's modification time) and
+// when its timer fires next. NextRun is zero when the timer is not active or
+// the scheduler cannot tell.
+func ConductorHeartbeatTiming(meta *ConductorMeta, state HeartbeatDaemonState, now time.Time) HeartbeatTiming {
+	var timing HeartbeatTiming
+	if result, err := ReadHeartbeatResult(meta.Name); err == nil && result != nil {
+		timing.LastRun, timing.LastResult = result.Time, result.Result
+	} else if dir, err := ConductorNameDir(meta.Name); err == nil {
+		if info, err := os.Stat(filepath.Join(dir, "heartbeat.log")); err == nil {
+			timing.LastRun = info.ModTime()
+		}
+	}
+	if state.Active {
+		timing.NextRun = nextHeartbeatRun(meta, state.Backend, timing.LastRun, now)
+	}
+	return timing
+}
+
+// nextHeartbeatRun asks systemd for the timer's next elapse, and otherwise
+// works it out from the schedule (cron) or the last run and the interval
+func nextHeartbeatRun(meta *ConductorMeta, backend string, lastRun, now time.Time) time.Time {
+	if backend == HeartbeatBackendSystemd {
+		if next, ok := systemdTimerNextElapse(SystemdHeartbeatTimerName(meta.Name)); ok {
+			return next
+		}
+	}
+
+	schedule := EffectiveHeartbeatSchedule(meta)
+	if schedule == "" && backend == HeartbeatBackendCron {
+		// Cron runs intervals as */N fields, aligned to the clock
+		schedule, _ = HeartbeatCronSchedule(HeartbeatIntervalFor(meta), "")
+	}
+	if schedule == "" {
+		if lastRun.IsZero() {
+			return time.Time{}
+		}
+		return lastRun.Add(time.Duration(HeartbeatIntervalFor(meta)) * time.Minute)
+	}
+
+	sched, err := ParseHeartbeatSchedule(schedule)
+	if err != nil || sched.Kind != ScheduleCron {
+		return time.Time{}
+	}
+	// Only systemd timers carry the timezone; cron and launchd use local time
+	if meta.Timezone != "" && backend == HeartbeatBackendSystemd {
+		if loc, err := time.LoadLocation(meta.Timezone); err == nil {
+			now = now.In(loc)
+		}
+	}
+	next, _ := sched.NextAfter(now)
+	return next
+}
+
+// systemdTimerNextElapse returns when a user timer fires next
+func systemdTimerNextElapse(timer string) (time.Time, bool) {
+	out, err := exec.Command("systemctl", "--user", "show", timer, "--property=NextElapseUSecRealtime", "--value").Output()
+	if err != nil {
+		return time.Time{}, false
+	}
+	next, err := time.ParseInLocation("Mon 2006-01-02 15:04:05 MST", strings.TrimSpace(string(out)), time.Local)
+	return next, err == nil
+}
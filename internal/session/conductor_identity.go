@@ -0,0 +1,176 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	conductorIdentityFileName = "IDENTITY.md"
+	conductorIdentityStart    = "<!-- agent-deck:identity -->"
+	conductorIdentityEnd      = "<!-- /agent-deck:identity -->"
+
+	// ConductorIdentityVar is replaced with the identity card in generated CLAUDE.md files
+	ConductorIdentityVar = "{IDENTITY}"
+)
+
+// responseLanguageRegex allows language names and tags ("German", "pt-BR",
+// "Chinese (Simplified)"). The name ends up in shell and Python string
+// literals, so quotes and other punctuation are rejected.
+var responseLanguageRegex = regexp.MustCompile(`^[\p{L}][\p{L} ()_-]{0,39}$`)
+
+// ValidateResponseLanguage checks a conductor response language ("" is valid)
+func ValidateResponseLanguage(language string) error {
+	if language == "" || responseLanguageRegex.MatchString(language) {
+		return nil
+	}
+	return fmt.Errorf("invalid language %q: use a language name or tag, e.g. German or pt-BR", language)
+}
+
+// LanguageInstruction is the sentence appended to messages and prompts for
+// a conductor with a response language
+func LanguageInstruction(language string) string {
+	return fmt.Sprintf("Reply in %s.", language)
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM" into minutes after midnight. The end
+// may be earlier than the start for windows that span midnight.
+func ParseQuietHours(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", s)
+	}
+	parse := func(v string) (int, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", s)
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if start, err = parse(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parse(to); err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q: start and end are equal", s)
+	}
+	return start, end, nil
+}
+
+// InQuietHours reports whether now falls in the conductor's quiet hours,
+// evaluated in the conductor's timezone.
+func (m *ConductorMeta) InQuietHours(now time.Time) bool {
+	start, end, err := ParseQuietHours(m.QuietHours)
+	if m.QuietHours == "" || err != nil {
+		return false
+	}
+	if m.Timezone != "" {
+		if loc, err := time.LoadLocation(m.Timezone); err == nil {
+			now = now.In(loc)
+		}
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// RenderConductorIdentity returns the identity card for a conductor: the
+// facts from meta.json that its prompts should agree with.
+func RenderConductorIdentity(meta *ConductorMeta) string {
+	var b strings.Builder
+	b.WriteString("## Identity Card\n\n")
+	b.WriteString("Generated from meta.json by agent-deck; change it with 'agent-deck conductor identity'.\n\n")
+	fmt.Fprintf(&b, "- **Name:** %s (session %s)\n", meta.Name, ConductorSessionTitle(meta.Name))
+	fmt.Fprintf(&b, "- **Profile:** %s\n", normalizeConductorProfile(meta.Profile))
+	if meta.Description != "" {
+		fmt.Fprintf(&b, "- **Purpose:** %s\n", meta.Description)
+	}
+	if schedule := EffectiveHeartbeatSchedule(meta); meta.HeartbeatEnabled && schedule != "" {
+		fmt.Fprintf(&b, "- **Heartbeat:** on schedule %s\n", schedule)
+	} else if meta.HeartbeatEnabled {
+		fmt.Fprintf(&b, "- **Heartbeat:** every %d minutes\n", HeartbeatIntervalFor(meta))
+	} else {
+		b.WriteString("- **Heartbeat:** disabled\n")
+	}
+	if meta.Timezone != "" {
+		fmt.Fprintf(&b, "- **Timezone:** %s\n", meta.Timezone)
+	}
+	if len(meta.Responsibilities) > 0 {
+		b.WriteString("- **Responsibilities:**\n")
+		for _, r := range meta.Responsibilities {
+			fmt.Fprintf(&b, "  - %s\n", r)
+		}
+	}
+	if len(meta.EscalationContacts) > 0 {
+		fmt.Fprintf(&b, "- **Escalation contacts:** %s\n", strings.Join(meta.EscalationContacts, ", "))
+	} else {
+		b.WriteString("- **Escalation contacts:** the user, via the bridge (NEED: lines)\n")
+	}
+	if meta.QuietHours != "" {
+		zone := meta.Timezone
+		if zone == "" {
+			zone = "local time"
+		}
+		fmt.Fprintf(&b, "- **Quiet hours:** %s (%s). Only escalate urgent issues during quiet hours; hold the rest until they end.\n", meta.QuietHours, zone)
+	}
+	if meta.Language != "" {
+		fmt.Fprintf(&b, "- **Response language:** %s. Write replies to the user, heartbeat reports, digests and session summaries in %s. "+
+			"Keep commands, session titles, file paths and protocol keywords (NEED:, [HEARTBEAT], state.json keys) in English.\n", meta.Language, meta.Language)
+	}
+	return b.String()
+}
+
+// SyncConductorIdentity writes IDENTITY.md and refreshes the identity block in
+// the conductor's CLAUDE.md: a {IDENTITY} placeholder is expanded in place,
+// otherwise the block is appended (or updated where it already is). A
+// symlinked (custom) CLAUDE.md is left alone and ErrConductorClaudeMDLinked
+// is returned after IDENTITY.md is written.
+func SyncConductorIdentity(name string) error {
+	meta, err := LoadConductorMeta(name)
+	if err != nil {
+		return err
+	}
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return err
+	}
+	card := RenderConductorIdentity(meta)
+	if err := os.WriteFile(filepath.Join(dir, conductorIdentityFileName), []byte(card), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", conductorIdentityFileName, err)
+	}
+
+	claudePath := filepath.Join(dir, "CLAUDE.md")
+	info, err := os.Lstat(claudePath)
+	if err != nil {
+		return fmt.Errorf("failed to read CLAUDE.md: %w", err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("%w: add '@%s' to it to load the identity card", ErrConductorClaudeMDLinked, conductorIdentityFileName)
+	}
+	data, err := os.ReadFile(claudePath)
+	if err != nil {
+		return fmt.Errorf("failed to read CLAUDE.md: %w", err)
+	}
+	content := string(data)
+	if strings.Contains(content, ConductorIdentityVar) {
+		block := conductorIdentityStart + "\n" + strings.TrimRight(card, "\n") + "\n" + conductorIdentityEnd
+		content = strings.Replace(content, ConductorIdentityVar, block, 1)
+	} else {
+		content = setManagedBlock(content, conductorIdentityStart, conductorIdentityEnd, card)
+	}
+	if content == string(data) {
+		return nil
+	}
+	if err := os.WriteFile(claudePath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write CLAUDE.md: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	start, end, err := ParseQuietHours("22:00-07:30")
+	if err != nil || start != 22*60 || end != 7*60+30 {
+		t.Errorf("got %d, %d, %v", start, end, err)
+	}
+	for _, bad := range []string{"22:00", "25:00-07:00", "09:00-09:00", "late-early"} {
+		if _, _, err := ParseQuietHours(bad); err == nil {
+			t.Errorf("ParseQuietHours(%q) should fail", bad)
+		}
+	}
+}
+
+func TestConductorMetaInQuietHours(t *testing.T) {
+	meta := &ConductorMeta{QuietHours: "22:00-07:00", Timezone: "Europe/Berlin"}
+	// 21:30 UTC is 23:30 in Berlin (CEST)
+	if !meta.InQuietHours(time.Date(2026, 7, 1, 21, 30, 0, 0, time.UTC)) {
+		t.Error("23:30 Berlin should be quiet")
+	}
+	if meta.InQuietHours(time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Error("12:00 Berlin should not be quiet")
+	}
+	if (&ConductorMeta{}).InQuietHours(time.Now()) {
+		t.Error("no quiet hours configured should never be quiet")
+	}
+}
+
+func TestSyncConductorIdentity(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "work", true, "Production on-call", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	dir, _ := ConductorNameDir("ops")
+	claudePath := filepath.Join(dir, "CLAUDE.md")
+
+	claude, _ := os.ReadFile(claudePath)
+	if strings.Count(string(claude), conductorIdentityStart) != 1 || !strings.Contains(string(claude), "**Purpose:** Production on-call") {
+		t.Fatalf("setup should append the identity card:\n%s", claude)
+	}
+
+	meta, _ := LoadConductorMeta("ops")
+	meta.Responsibilities = []string{"deploys", "incident triage"}
+	meta.EscalationContacts = []string{"@alice"}
+	meta.QuietHours = "22:00-07:00"
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := SyncConductorIdentity("ops"); err != nil {
+		t.Fatalf("SyncConductorIdentity: %v", err)
+	}
+	claude, _ = os.ReadFile(claudePath)
+	if strings.Count(string(claude), conductorIdentityStart) != 1 {
+		t.Errorf("sync should update the block in place:\n%s", claude)
+	}
+	for _, want := range []string{"  - incident triage", "**Escalation contacts:** @alice", "**Quiet hours:** 22:00-07:00"} {
+		if !strings.Contains(string(claude), want) {
+			t.Errorf("CLAUDE.md missing %q", want)
+		}
+	}
+	if card, _ := os.ReadFile(filepath.Join(dir, conductorIdentityFileName)); !strings.Contains(string(card), "@alice") {
+		t.Errorf("IDENTITY.md should carry the card:\n%s", card)
+	}
+
+	// A {IDENTITY} placeholder is expanded where it appears
+	if err := os.WriteFile(claudePath, []byte("# Ops\n\n"+ConductorIdentityVar+"\n\n## Rules\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SyncConductorIdentity("ops"); err != nil {
+		t.Fatalf("SyncConductorIdentity: %v", err)
+	}
+	claude, _ = os.ReadFile(claudePath)
+	if !strings.HasPrefix(string(claude), "# Ops\n\n"+conductorIdentityStart) || !strings.HasSuffix(string(claude), "## Rules\n") {
+		t.Errorf("placeholder should be expanded in place:\n%s", claude)
+	}
+
+	// Custom symlinked CLAUDE.md files are not modified
+	custom := filepath.Join(t.TempDir(), "custom.md")
+	_ = os.WriteFile(custom, []byte("# Custom\n"), 0o644)
+	_ = os.Remove(claudePath)
+	_ = os.Symlink(custom, claudePath)
+	if err := SyncConductorIdentity("ops"); !errors.Is(err, ErrConductorClaudeMDLinked) {
+		t.Errorf("expected ErrConductorClaudeMDLinked, got %v", err)
+	}
+	if data, _ := os.ReadFile(custom); string(data) != "# Custom\n" {
+		t.Errorf("custom file was modified: %q", data)
+	}
+}
+
+func TestConductorResponseLanguage(t *testing.T) {
+	for _, valid := range []string{"", "German", "pt-BR", "Chinese (Simplified)", "日本語"} {
+		if err := ValidateResponseLanguage(valid); err != nil {
+			t.Errorf("ValidateResponseLanguage(%q) = %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"German\"; rm -rf ~", "$(whoami)", "-de"} {
+		if ValidateResponseLanguage(invalid) == nil {
+			t.Errorf("ValidateResponseLanguage(%q) should fail", invalid)
+		}
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("berlin", "work", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	meta, _ := LoadConductorMeta("berlin")
+	meta.Language = "German"
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+	if card := RenderConductorIdentity(meta); !strings.Contains(card, "**Response language:** German") {
+		t.Errorf("identity card missing response language:\n%s", card)
+	}
+
+	// A plain re-run of setup keeps the language
+	if err := SetupConductor("berlin", "work", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor re-run: %v", err)
+	}
+	if meta, _ = LoadConductorMeta("berlin"); meta.Language != "German" {
+		t.Errorf("language after re-run = %q, want German", meta.Language)
+	}
+
+	if err := InstallHeartbeatScript("berlin", "work"); err != nil {
+		t.Fatalf("InstallHeartbeatScript: %v", err)
+	}
+	if msg := BuildHeartbeatMessage(meta, nil, time.Now()); !strings.Contains(msg, "Reply in German.") {
+		t.Errorf("heartbeat should ask for German replies: %q", msg)
+	}
+	if !HeartbeatScriptInstalled("berlin") {
+		t.Error("HeartbeatScriptInstalled should see heartbeat.sh")
+	}
+}
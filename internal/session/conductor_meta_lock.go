@@ -0,0 +1,154 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// ErrConductorMetaConflict means meta.json was saved by another process
+// (a heartbeat run, the TUI, another CLI command) after it was loaded.
+// ConductorMetaConflictErrors match it.
+var ErrConductorMetaConflict = errors.New("conductor meta.json changed since it was loaded")
+
+// ErrConductorMetaLocked means another process held meta.json's lock for
+// longer than metaLockTimeout
+var ErrConductorMetaLocked = errors.New("conductor meta.json is locked by another process")
+
+// metaLockTimeout bounds the wait for another writer; saves take milliseconds
+const metaLockTimeout = 5 * time.Second
+
+// ConductorMetaConflictError reports a save of a stale meta.json: Revision is
+// the one loaded, OnDisk the one another process has written since
+type ConductorMetaConflictError struct {
+	Conductor string
+	Revision  int
+	OnDisk    int
+}
+
+func (e *ConductorMetaConflictError) Error() string {
+	return fmt.Sprintf("%v: conductor %s loaded revision %d, now at %d; reload and retry",
+		ErrConductorMetaConflict, e.Conductor, e.Revision, e.OnDisk)
+}
+
+// Is makes errors.Is match ErrConductorMetaConflict
+func (e *ConductorMetaConflictError) Is(target error) bool {
+	return target == ErrConductorMetaConflict
+}
+
+// UpdateConductorMeta loads a conductor's meta.json, applies fn and saves it,
+// holding the lock throughout so no other writer can slip in between. An
+// error from fn aborts the update.
+func UpdateConductorMeta(name string, fn func(*ConductorMeta) error) (*ConductorMeta, error) {
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockConductorMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	meta, err := LoadConductorMeta(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(meta); err != nil {
+		return nil, err
+	}
+	meta.Profile = normalizeConductorProfile(meta.Profile)
+	if err := writeConductorMeta(dir, meta); err != nil {
+		return nil, err
+	}
+	if err := WriteConductorRunbook(meta); err != nil {
+		conductorLog.Warn("conductor_runbook_write_failed", slog.String("conductor", meta.Name), slog.String("error", err.Error()))
+	}
+	return meta, nil
+}
+
+// lockConductorMeta takes an exclusive flock on the conductor's
+// meta.json.lock, returning the function that releases it. The lock file is
+// separate from meta.json, which is replaced on every save.
+func lockConductorMeta(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create conductor dir: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "meta.json.lock"), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open meta.json lock: %w", err)
+	}
+	deadline := time.Now().Add(metaLockTimeout)
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) || time.Now().After(deadline) {
+			f.Close()
+			if errors.Is(err, syscall.EWOULDBLOCK) {
+				return nil, fmt.Errorf("%w: %s", ErrConductorMetaLocked, filepath.Base(dir))
+			}
+			return nil, fmt.Errorf("failed to lock meta.json: %w", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// diskMetaHeader returns the revision and schema version of the meta.json
+// currently on disk, 0 if there is none or it predates them
+func diskMetaHeader(metaPath string) (revision, schemaVersion int) {
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return 0, 0
+	}
+	var onDisk struct {
+		Revision      int `json:"revision"`
+		SchemaVersion int `json:"schema_version"`
+	}
+	_ = json.Unmarshal(data, &onDisk)
+	return onDisk.Revision, onDisk.SchemaVersion
+}
+
+// writeConductorMeta bumps meta's revision, stamps the current schema version
+// and replaces meta.json through a temp file, so readers never see a partial
+// write. A meta.json from a newer agent-deck is never overwritten, as that
+// would drop the fields this binary doesn't know. The caller holds the lock.
+func writeConductorMeta(dir string, meta *ConductorMeta) error {
+	metaPath := filepath.Join(dir, "meta.json")
+	onDisk, onDiskSchema := diskMetaHeader(metaPath)
+	if err := conductorMetaTooNew(meta.Name, max(onDiskSchema, meta.SchemaVersion)); err != nil {
+		return err
+	}
+	if onDisk != meta.Revision {
+		return &ConductorMetaConflictError{Conductor: meta.Name, Revision: meta.Revision, OnDisk: onDisk}
+	}
+
+	next := *meta
+	next.Revision++
+	next.SchemaVersion = ConductorMetaSchemaVersion
+	data, err := json.MarshalIndent(&next, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal meta.json: %w", err)
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", metaPath, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write meta.json: %w", err)
+	}
+	if err := os.Rename(tmp, metaPath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write meta.json: %w", err)
+	}
+	meta.Revision = next.Revision
+	meta.SchemaVersion = next.SchemaVersion
+	return nil
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSaveConductorMetaRejectsStaleWrite(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+
+	// The CLI and a heartbeat run both load the same revision
+	cli, err := LoadConductorMeta("ops")
+	if err != nil {
+		t.Fatalf("LoadConductorMeta: %v", err)
+	}
+	heartbeat, err := LoadConductorMeta("ops")
+	if err != nil {
+		t.Fatalf("LoadConductorMeta: %v", err)
+	}
+
+	heartbeat.Description = "from heartbeat"
+	if err := SaveConductorMeta(heartbeat); err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+	cli.Language = "German"
+	err = SaveConductorMeta(cli)
+	var conflict *ConductorMetaConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConductorMetaConflict) {
+		t.Fatalf("stale save err = %v, want ConductorMetaConflictError", err)
+	}
+	if conflict.OnDisk != cli.Revision+1 {
+		t.Errorf("conflict = %+v, want on-disk revision %d", conflict, cli.Revision+1)
+	}
+
+	meta, err := LoadConductorMeta("ops")
+	if err != nil {
+		t.Fatalf("LoadConductorMeta: %v", err)
+	}
+	if meta.Description != "from heartbeat" || meta.Language != "" {
+		t.Errorf("meta = %+v, want the heartbeat's save only", meta)
+	}
+}
+
+func TestUpdateConductorMetaSerializesWriters(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := UpdateConductorMeta("ops", func(meta *ConductorMeta) error {
+				meta.Responsibilities = append(meta.Responsibilities, "task")
+				return nil
+			})
+			if err != nil {
+				t.Errorf("UpdateConductorMeta: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	meta, err := LoadConductorMeta("ops")
+	if err != nil {
+		t.Fatalf("LoadConductorMeta: %v", err)
+	}
+	if len(meta.Responsibilities) != 8 {
+		t.Errorf("got %d responsibilities, want 8: no update may be lost", len(meta.Responsibilities))
+	}
+}
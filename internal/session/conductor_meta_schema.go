@@ -0,0 +1,98 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// ConductorMetaSchemaVersion is the meta.json schema this binary reads and
+// writes. Bump it when a field is renamed or changes shape, and add the
+// upgrade step to conductorMetaMigrations.
+const ConductorMetaSchemaVersion = 1
+
+// conductorMetaMigration upgrades a decoded meta.json by one schema version.
+// dirName is the conductor's directory name.
+type conductorMetaMigration func(raw map[string]any, dirName string) error
+
+// conductorMetaMigrations upgrades older meta.json files on load, keyed by
+// the version each step produces
+var conductorMetaMigrations = map[int]conductorMetaMigration{
+	1: migrateConductorMetaV1,
+}
+
+// migrateConductorMetaV1 upgrades files from before schema versioning,
+// which could lack a name or profile
+func migrateConductorMetaV1(raw map[string]any, dirName string) error {
+	if name, _ := raw["name"].(string); name == "" {
+		raw["name"] = dirName
+	}
+	if profile, _ := raw["profile"].(string); profile == "" {
+		raw["profile"] = DefaultProfile
+	}
+	return nil
+}
+
+// decodeConductorMeta parses a meta.json, upgrading it to
+// ConductorMetaSchemaVersion. The upgrade is saved with the next write. A
+// file from a newer agent-deck is decoded as far as this binary understands
+// it and keeps its SchemaVersion, so writing it back is refused.
+func decodeConductorMeta(data []byte, dirName string) (*ConductorMeta, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	version := rawSchemaVersion(raw)
+	if version < ConductorMetaSchemaVersion {
+		if err := migrateConductorMeta(raw, dirName, version, ConductorMetaSchemaVersion, conductorMetaMigrations); err != nil {
+			return nil, err
+		}
+		upgraded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		data = upgraded
+	}
+	var meta ConductorMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// migrateConductorMeta runs steps from (exclusive) up to to and stamps raw
+// with the resulting version
+func migrateConductorMeta(raw map[string]any, dirName string, from, to int, steps map[int]conductorMetaMigration) error {
+	for v := from + 1; v <= to; v++ {
+		step, ok := steps[v]
+		if !ok {
+			return fmt.Errorf("no meta.json migration to schema version %d", v)
+		}
+		if err := step(raw, dirName); err != nil {
+			return fmt.Errorf("migrate meta.json to schema version %d: %w", v, err)
+		}
+	}
+	raw["schema_version"] = to
+	return nil
+}
+
+// rawSchemaVersion returns a decoded meta.json's schema_version, 0 for files
+// from before schema versioning
+func rawSchemaVersion(raw map[string]any) int {
+	v, _ := raw["schema_version"].(float64)
+	return int(v)
+}
+
+// conductorMetaTooNew returns the error for a write to a meta.json at a
+// schema newer than this binary's, nil otherwise
+func conductorMetaTooNew(name string, version int) error {
+	if version <= ConductorMetaSchemaVersion {
+		return nil
+	}
+	return &statedb.SchemaTooNewError{
+		What:      fmt.Sprintf("conductor %s meta.json", name),
+		Version:   version,
+		Supported: ConductorMetaSchemaVersion,
+	}
+}
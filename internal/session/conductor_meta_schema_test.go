@@ -0,0 +1,81 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func writeRawConductorMeta(t *testing.T, name, data string) string {
+	t.Helper()
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		t.Fatalf("ConductorNameDir: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "meta.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConductorMetaUpgradesUnversionedFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeRawConductorMeta(t, "ops", `{"heartbeat_enabled": true, "heartbeat_interval": 10}`)
+
+	meta, err := LoadConductorMeta("ops")
+	if err != nil {
+		t.Fatalf("LoadConductorMeta: %v", err)
+	}
+	if meta.SchemaVersion != ConductorMetaSchemaVersion || meta.Name != "ops" || meta.Profile != DefaultProfile || meta.HeartbeatInterval != 10 {
+		t.Errorf("meta = %+v, want upgraded to schema %d", meta, ConductorMetaSchemaVersion)
+	}
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatalf("SaveConductorMeta: %v", err)
+	}
+	reloaded, err := LoadConductorMeta("ops")
+	if err != nil {
+		t.Fatalf("LoadConductorMeta: %v", err)
+	}
+	if reloaded.SchemaVersion != ConductorMetaSchemaVersion || reloaded.Revision != 1 {
+		t.Errorf("saved meta = %+v, want schema %d at revision 1", reloaded, ConductorMetaSchemaVersion)
+	}
+}
+
+func TestSaveConductorMetaRefusesNewerSchema(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := writeRawConductorMeta(t, "ops",
+		`{"name": "ops", "profile": "default", "revision": 3, "schema_version": 99, "future_field": {"kept": true}}`)
+
+	meta, err := LoadConductorMeta("ops")
+	if err != nil {
+		t.Fatalf("LoadConductorMeta: %v", err)
+	}
+	if meta.SchemaVersion != 99 {
+		t.Errorf("SchemaVersion = %d, want the file's 99", meta.SchemaVersion)
+	}
+	meta.Description = "edited by an older binary"
+	err = SaveConductorMeta(meta)
+	var tooNew *statedb.SchemaTooNewError
+	if !errors.As(err, &tooNew) || !errors.Is(err, statedb.ErrSchemaTooNew) {
+		t.Fatalf("SaveConductorMeta err = %v, want SchemaTooNewError", err)
+	}
+	if _, err := UpdateConductorMeta("ops", func(*ConductorMeta) error { return nil }); !errors.Is(err, statedb.ErrSchemaTooNew) {
+		t.Errorf("UpdateConductorMeta err = %v, want ErrSchemaTooNew", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "future_field") || !strings.Contains(string(data), `"schema_version": 99`) {
+		t.Errorf("meta.json was rewritten: %s", data)
+	}
+}
@@ -0,0 +1,213 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/notify"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// Conductor notification events (see ConductorNotifications.Events)
+const (
+	ConductorNotifyHeartbeatFailed = "heartbeat_failed" // a heartbeat could not be delivered
+	ConductorNotifyWorkerError     = "worker_error"     // a worker (or the conductor) errored
+	ConductorNotifyWorkerIdle      = "worker_idle"      // a worker finished working
+)
+
+// ConductorNotifyEvents lists the accepted conductor notification events
+var ConductorNotifyEvents = []string{ConductorNotifyHeartbeatFailed, ConductorNotifyWorkerError, ConductorNotifyWorkerIdle}
+
+// DefaultConductorNotifyEvents are notified when Events is empty
+var DefaultConductorNotifyEvents = []string{ConductorNotifyHeartbeatFailed, ConductorNotifyWorkerError}
+
+// DefaultConductorNotifyTemplate renders a ConductorNotice when no template is set
+const DefaultConductorNotifyTemplate = `{{if eq .Event "heartbeat_failed"}}:warning: Conductor *{{.Conductor}}*: heartbeat failed{{if .Error}}: {{.Error}}{{end}}` +
+	`{{else if eq .Event "worker_error"}}:x: Conductor *{{.Conductor}}*: {{if .IsConductor}}conductor{{else}}worker *{{.Title}}*{{end}} errored{{if .PrevStatus}} (was {{.PrevStatus}}){{end}}` +
+	`{{else}}:white_check_mark: Conductor *{{.Conductor}}*: worker *{{.Title}}* finished working{{end}}` +
+	`{{if .Path}}` + "\n" + `{{.Path}}{{end}}`
+
+// ConductorNotifications is the "notifications" block of a conductor's
+// meta.json: chat webhooks told about its failed heartbeats and its workers'
+// errors.
+//
+// The webhook URLs carry their credentials; they are never printed in full.
+type ConductorNotifications struct {
+	SlackWebhook   string `json:"slack_webhook,omitempty"`
+	DiscordWebhook string `json:"discord_webhook,omitempty"`
+	// Events to notify (default: heartbeat_failed, worker_error)
+	Events []string `json:"events,omitempty"`
+	// Template is a text/template over ConductorNotice (default:
+	// DefaultConductorNotifyTemplate)
+	Template string `json:"template,omitempty"`
+}
+
+// Validate checks the webhook URLs, events and template
+func (n *ConductorNotifications) Validate() error {
+	for _, hook := range []struct{ name, url string }{
+		{"slack", n.SlackWebhook}, {"discord", n.DiscordWebhook},
+	} {
+		if hook.url == "" {
+			continue
+		}
+		if err := notify.ValidateWebhookURL(hook.url); err != nil {
+			return fmt.Errorf("%s: %w", hook.name, err)
+		}
+	}
+	for _, event := range n.Events {
+		if !slices.Contains(ConductorNotifyEvents, event) {
+			return fmt.Errorf("unknown event %q (valid: %s)", event, strings.Join(ConductorNotifyEvents, ", "))
+		}
+	}
+	if n.Template != "" {
+		if _, err := notify.Parse(n.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Wants reports whether event is notified
+func (n *ConductorNotifications) Wants(event string) bool {
+	if len(n.Events) == 0 {
+		return slices.Contains(DefaultConductorNotifyEvents, event)
+	}
+	return slices.Contains(n.Events, event)
+}
+
+// Senders returns a sender per configured webhook
+func (n *ConductorNotifications) Senders() []notify.Sender {
+	var senders []notify.Sender
+	if n.SlackWebhook != "" {
+		senders = append(senders, &notify.Slack{URL: n.SlackWebhook})
+	}
+	if n.DiscordWebhook != "" {
+		senders = append(senders, &notify.Discord{URL: n.DiscordWebhook})
+	}
+	return senders
+}
+
+// ConductorNotice is the data a notification template renders
+type ConductorNotice struct {
+	HookEvent
+	// Event is the conductor notification event, e.g. "worker_error"
+	Event string
+	// IsConductor is set when the session is the conductor itself
+	IsConductor bool
+}
+
+// ConductorNoticeFor maps a hook event to the conductor notice it raises, or
+// nil. Heartbeat events name their conductor; for status events lookup
+// returns the conductor that spawned the session ("" for none).
+func ConductorNoticeFor(ev HookEvent, lookup func(sessionID string) string) *ConductorNotice {
+	notice := &ConductorNotice{HookEvent: ev}
+	switch ev.Event {
+	case HookEventHeartbeat:
+		if ev.Result != HeartbeatFailed || ev.Conductor == "" {
+			return nil
+		}
+		notice.Event = ConductorNotifyHeartbeatFailed
+		return notice
+	case HookEventError:
+		notice.Event = ConductorNotifyWorkerError
+	case HookEventIdle:
+		notice.Event = ConductorNotifyWorkerIdle
+	default:
+		return nil
+	}
+	if name, ok := ConductorNameFromTitle(ev.Title); ok {
+		notice.Conductor, notice.IsConductor = name, true
+	} else {
+		notice.Conductor = lookup(ev.SessionID)
+	}
+	if notice.Conductor == "" {
+		return nil
+	}
+	return notice
+}
+
+// RenderConductorNotice renders notice with the conductor's template
+func RenderConductorNotice(settings *ConductorNotifications, notice *ConductorNotice) (string, error) {
+	tmpl := settings.Template
+	if tmpl == "" {
+		tmpl = DefaultConductorNotifyTemplate
+	}
+	return notify.Render(tmpl, notice)
+}
+
+// SendConductorNotice renders notice and sends it to every webhook of
+// settings, bounded by timeout
+func SendConductorNotice(settings *ConductorNotifications, notice *ConductorNotice, timeout time.Duration) error {
+	text, err := RenderConductorNotice(settings, notice)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var errs []error
+	for _, sender := range settings.Senders() {
+		if err := sender.Send(ctx, text); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notifyConductor sends the notification ev raises for its conductor, if
+// that conductor has notifications for it
+func notifyConductor(ev HookEvent, timeout time.Duration) error {
+	notice := ConductorNoticeFor(ev, spawningConductor)
+	if notice == nil {
+		return nil
+	}
+	meta, err := LoadConductorMeta(notice.Conductor)
+	if err != nil || meta.Notifications == nil || !meta.Notifications.Wants(notice.Event) {
+		return nil
+	}
+	if err := SendConductorNotice(meta.Notifications, notice, timeout); err != nil {
+		eventHookLog.Warn("conductor_notify_failed",
+			slog.String("conductor", notice.Conductor),
+			slog.String("event", notice.Event),
+			slog.String("error", err.Error()))
+		return fmt.Errorf("conductor %s notification: %w", notice.Conductor, err)
+	}
+	return nil
+}
+
+// spawningConductor returns the conductor that created a session, from the
+// spawn audit log, or ""
+func spawningConductor(sessionID string) string {
+	db := statedb.GetGlobal()
+	if db == nil || sessionID == "" {
+		return ""
+	}
+	rows, err := db.ListAudit(sessionID, 0)
+	if err != nil {
+		return ""
+	}
+	for _, row := range rows {
+		if row.Action == statedb.AuditSpawned {
+			return row.Detail
+		}
+	}
+	return ""
+}
+
+// MaskWebhookURL shows a webhook URL's scheme and host only, hiding the
+// token in its path
+func MaskWebhookURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return "***"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host + "/***"
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConductorNoticeFor(t *testing.T) {
+	lookup := func(id string) string {
+		if id == "w1" {
+			return "ops"
+		}
+		return ""
+	}
+	cases := []struct {
+		name      string
+		ev        HookEvent
+		event     string
+		conductor string
+	}{
+		{"failed heartbeat", HookEvent{Event: HookEventHeartbeat, Conductor: "ops", Result: HeartbeatFailed}, ConductorNotifyHeartbeatFailed, "ops"},
+		{"sent heartbeat", HookEvent{Event: HookEventHeartbeat, Conductor: "ops", Result: HeartbeatSent}, "", ""},
+		{"worker error", HookEvent{Event: HookEventError, SessionID: "w1", Title: "api"}, ConductorNotifyWorkerError, "ops"},
+		{"worker idle", HookEvent{Event: HookEventIdle, SessionID: "w1", Title: "api"}, ConductorNotifyWorkerIdle, "ops"},
+		{"conductor error", HookEvent{Event: HookEventError, SessionID: "c1", Title: "conductor-infra"}, ConductorNotifyWorkerError, "infra"},
+		{"unrelated session", HookEvent{Event: HookEventError, SessionID: "x", Title: "scratch"}, "", ""},
+		{"busy", HookEvent{Event: HookEventBusy, SessionID: "w1"}, "", ""},
+	}
+	for _, tc := range cases {
+		notice := ConductorNoticeFor(tc.ev, lookup)
+		if tc.event == "" {
+			if notice != nil {
+				t.Errorf("%s: expected no notice, got %+v", tc.name, notice)
+			}
+			continue
+		}
+		if notice == nil || notice.Event != tc.event || notice.Conductor != tc.conductor {
+			t.Errorf("%s: got %+v, want %s for %s", tc.name, notice, tc.event, tc.conductor)
+		}
+	}
+}
+
+func TestConductorNotificationsValidateAndWants(t *testing.T) {
+	n := &ConductorNotifications{SlackWebhook: "https://hooks.slack.com/services/T0/B0/X"}
+	if err := n.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !n.Wants(ConductorNotifyWorkerError) || n.Wants(ConductorNotifyWorkerIdle) {
+		t.Error("default events should be heartbeat_failed and worker_error")
+	}
+	n.Events = []string{ConductorNotifyWorkerIdle}
+	if n.Wants(ConductorNotifyWorkerError) || !n.Wants(ConductorNotifyWorkerIdle) {
+		t.Error("explicit events should replace the defaults")
+	}
+
+	for _, bad := range []*ConductorNotifications{
+		{DiscordWebhook: "http://discord.com/api/webhooks/1/x"},
+		{Events: []string{"worker_exploded"}},
+		{Template: "{{.Title"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestRenderConductorNoticeDefaultTemplate(t *testing.T) {
+	settings := &ConductorNotifications{}
+	got, err := RenderConductorNotice(settings, &ConductorNotice{
+		HookEvent: HookEvent{Conductor: "ops", Title: "api", PrevStatus: "running", Path: "/src/api"},
+		Event:     ConductorNotifyWorkerError,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ":x: Conductor *ops*: worker *api* errored (was running)\n/src/api"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	settings.Template = "{{.Conductor}} {{.Event}} {{.Error}}"
+	got, _ = RenderConductorNotice(settings, &ConductorNotice{
+		HookEvent: HookEvent{Conductor: "ops", Error: "timeout"},
+		Event:     ConductorNotifyHeartbeatFailed,
+	})
+	if got != "ops heartbeat_failed timeout" {
+		t.Errorf("custom template rendered %q", got)
+	}
+}
+
+func TestRunEventHooksNotifiesConductor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	received := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Text string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received <- body.Text
+	}))
+	defer srv.Close()
+
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	meta, _ := LoadConductorMeta("ops")
+	meta.Notifications = &ConductorNotifications{SlackWebhook: srv.URL}
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := HookEvent{Event: HookEventHeartbeat, Time: time.Now(), Conductor: "ops", Result: HeartbeatSent}
+	if errs := RunEventHooks(ev); len(errs) != 0 {
+		t.Fatalf("RunEventHooks: %v", errs)
+	}
+	ev.Result, ev.Error = HeartbeatFailed, "session not running"
+	if errs := RunEventHooks(ev); len(errs) != 0 {
+		t.Fatalf("RunEventHooks: %v", errs)
+	}
+	select {
+	case text := <-received:
+		if !strings.Contains(text, "heartbeat failed: session not running") {
+			t.Errorf("unexpected message %q", text)
+		}
+	default:
+		t.Fatal("failed heartbeat was not sent to Slack")
+	}
+	if len(received) != 0 {
+		t.Error("a successful heartbeat should not be notified")
+	}
+}
+
+func TestSetupConductorKeepsNotifications(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	meta, _ := LoadConductorMeta("ops")
+	meta.Notifications = &ConductorNotifications{
+		SlackWebhook: "https://hooks.slack.com/services/T0/B0/x",
+		Events:       []string{"worker_error"},
+	}
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	// A re-run must not silently stop the notifications
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor re-run: %v", err)
+	}
+	meta, _ = LoadConductorMeta("ops")
+	if n := meta.Notifications; n == nil || n.SlackWebhook != "https://hooks.slack.com/services/T0/B0/x" || len(n.Events) != 1 {
+		t.Errorf("after re-run: notifications = %+v, want the Slack webhook for worker_error", n)
+	}
+}
+
+func TestMaskWebhookURL(t *testing.T) {
+	got := MaskWebhookURL("https://hooks.slack.com/services/T0/B0/secret")
+	if got != "https://hooks.slack.com/***" {
+		t.Errorf("MaskWebhookURL = %q", got)
+	}
+	if MaskWebhookURL("") != "" {
+		t.Error("empty URL should stay empty")
+	}
+}
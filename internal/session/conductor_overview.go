@@ -0,0 +1,227 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// Sort keys for ConductorOverview rows (conductor list --sort)
+const (
+	ConductorSortName      = "name"      // by name (default)
+	ConductorSortProfile   = "profile"   // by profile, then name
+	ConductorSortStatus    = "status"    // stopped and missing sessions first
+	ConductorSortQueue     = "queue"     // deepest task queue first
+	ConductorSortHeartbeat = "heartbeat" // failed, then stalest heartbeat first
+	ConductorSortSpend     = "spend"     // highest spend today first
+)
+
+// ConductorSortKeys lists the accepted sort keys
+var ConductorSortKeys = []string{
+	ConductorSortName, ConductorSortProfile, ConductorSortStatus,
+	ConductorSortQueue, ConductorSortHeartbeat, ConductorSortSpend,
+}
+
+// Conductor session states in the overview
+const (
+	ConductorStateRunning = "running"
+	ConductorStateStopped = "stopped"
+)
+
+// ConductorOverview is one conductor with the numbers worth checking each
+// morning: whether its session runs, how much work is queued, how its last
+// heartbeat went and what it has spent today
+type ConductorOverview struct {
+	ConductorMeta
+	// Status is running, stopped or no_session
+	Status string `json:"status"`
+	// QueueDepth counts dispatch tasks waiting for their worker
+	QueueDepth int `json:"queue_depth"`
+	// ActiveTasks counts dispatch tasks delivered and not yet finished
+	ActiveTasks int `json:"active_tasks"`
+	// LastHeartbeat is the most recent heartbeat run, if any was recorded
+	LastHeartbeat *HeartbeatRun `json:"last_heartbeat,omitempty"`
+	// SpendToday is the estimated USD spend since local midnight of the
+	// conductor session and the live workers it created
+	SpendToday float64 `json:"spend_today"`
+}
+
+// BuildConductorOverviews gathers the overview of each conductor, loading
+// each profile's sessions once. A profile that fails to load leaves its
+// conductors' status empty rather than failing the whole list.
+func BuildConductorOverviews(conductors []ConductorMeta, now time.Time) []ConductorOverview {
+	rows := make([]ConductorOverview, len(conductors))
+	byProfile := make(map[string][]int)
+	for n, meta := range conductors {
+		rows[n] = ConductorOverview{ConductorMeta: meta}
+		row := &rows[n]
+		if pending, err := ListTasks(meta.Name, TaskPending); err == nil {
+			row.QueueDepth = len(pending)
+		}
+		if active, err := ListTasks(meta.Name, TaskActive); err == nil {
+			row.ActiveTasks = len(active)
+		}
+		if runs, err := LoadHeartbeatHistory(meta.Name, 1); err == nil && len(runs) == 1 {
+			row.LastHeartbeat = &runs[0]
+		}
+		profile := normalizeConductorProfile(meta.Profile)
+		byProfile[profile] = append(byProfile[profile], n)
+	}
+	for profile, idx := range byProfile {
+		fillConductorOverviews(profile, rows, idx, now)
+	}
+	return rows
+}
+
+// fillConductorOverviews sets status and spend of the rows at idx from the
+// profile's sessions
+func fillConductorOverviews(profile string, rows []ConductorOverview, idx []int, now time.Time) {
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return
+	}
+	defer storage.Close()
+	instances, _, err := storage.LoadWithGroups()
+	if err != nil {
+		return
+	}
+	byTitle := make(map[string]*Instance, len(instances))
+	byID := make(map[string]*Instance, len(instances))
+	for _, inst := range instances {
+		byTitle[inst.Title] = inst
+		byID[inst.ID] = inst
+	}
+	db := storage.GetDB()
+	midnight := startOfDay(now)
+
+	for _, n := range idx {
+		row := &rows[n]
+		inst := byTitle[ConductorSessionTitle(row.Name)]
+		if inst == nil {
+			row.Status = FleetStateNoSession
+			continue
+		}
+		_ = inst.UpdateStatus()
+		switch inst.GetStatusThreadSafe() {
+		case StatusRunning, StatusWaiting, StatusIdle, StatusThrottled:
+			row.Status = ConductorStateRunning
+		default:
+			row.Status = ConductorStateStopped
+		}
+		row.SpendToday = instanceSpendSince(inst, midnight)
+		if db == nil {
+			continue
+		}
+		spawned, err := db.ListAuditByAction(statedb.AuditSpawned, row.Name, time.Time{})
+		if err != nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, entry := range spawned {
+			if worker := byID[entry.SessionID]; worker != nil && !seen[worker.ID] {
+				seen[worker.ID] = true
+				row.SpendToday += instanceSpendSince(worker, midnight)
+			}
+		}
+	}
+}
+
+// instanceSpendSince estimates a session's USD spend since the given time
+// from its transcript (Claude and Codex sessions; 0 for other tools)
+func instanceSpendSince(inst *Instance, since time.Time) float64 {
+	if inst.Tool == "codex" {
+		if inst.CodexSessionID == "" {
+			return 0
+		}
+		path := findCodexRollout(inst.CodexSessionID)
+		if path == "" {
+			return 0
+		}
+		cost, _ := CodexSpendSince(path, since)
+		return cost
+	}
+	path := inst.GetJSONLPath()
+	if path == "" {
+		return 0
+	}
+	cost, err := SpendSince(path, since)
+	if err != nil {
+		return 0
+	}
+	return cost
+}
+
+// SortConductorOverviews sorts rows by key (see ConductorSortKeys); ties
+// keep name order. reverse flips the order.
+func SortConductorOverviews(rows []ConductorOverview, key string, reverse bool) error {
+	var less func(a, b *ConductorOverview) bool
+	switch key {
+	case "", ConductorSortName:
+		less = func(a, b *ConductorOverview) bool { return false }
+	case ConductorSortProfile:
+		less = func(a, b *ConductorOverview) bool { return a.Profile < b.Profile }
+	case ConductorSortStatus:
+		less = func(a, b *ConductorOverview) bool {
+			return conductorStatusRank(a.Status) < conductorStatusRank(b.Status)
+		}
+	case ConductorSortQueue:
+		less = func(a, b *ConductorOverview) bool {
+			if a.QueueDepth != b.QueueDepth {
+				return a.QueueDepth > b.QueueDepth
+			}
+			return a.ActiveTasks > b.ActiveTasks
+		}
+	case ConductorSortHeartbeat:
+		less = func(a, b *ConductorOverview) bool {
+			if fa, fb := heartbeatFailed(a.LastHeartbeat), heartbeatFailed(b.LastHeartbeat); fa != fb {
+				return fa
+			}
+			return heartbeatTime(a.LastHeartbeat).Before(heartbeatTime(b.LastHeartbeat))
+		}
+	case ConductorSortSpend:
+		less = func(a, b *ConductorOverview) bool { return a.SpendToday > b.SpendToday }
+	default:
+		return fmt.Errorf("unknown sort key %q (use %s)", key, strings.Join(ConductorSortKeys, ", "))
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := &rows[i], &rows[j]
+		if reverse {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.Name < b.Name
+	})
+	return nil
+}
+
+// conductorStatusRank orders statuses by how much attention they need
+func conductorStatusRank(status string) int {
+	switch status {
+	case FleetStateNoSession:
+		return 0
+	case ConductorStateStopped:
+		return 1
+	case ConductorStateRunning:
+		return 3
+	}
+	return 2 // unknown: the profile failed to load
+}
+
+func heartbeatFailed(run *HeartbeatRun) bool {
+	return run != nil && run.Result == HeartbeatFailed
+}
+
+func heartbeatTime(run *HeartbeatRun) time.Time {
+	if run == nil {
+		return time.Time{}
+	}
+	return run.Time
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBuildConductorOverviews(t *testing.T) {
+	writeMacroConfig(t, "")
+	now := time.Now()
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, prompt := range []string{"a", "b"} {
+		if _, err := EnqueueTask("ops", "worker", prompt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AppendHeartbeatHistory("ops", HeartbeatRun{Time: now.Add(-time.Hour), Result: HeartbeatSent}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendHeartbeatHistory("ops", HeartbeatRun{Time: now, Result: HeartbeatFailed, Error: "pane gone"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := BuildConductorOverviews([]ConductorMeta{{Name: "ops", Profile: "default"}, {Name: "idle", Profile: "default"}}, now)
+	if rows[0].QueueDepth != 2 || rows[0].LastHeartbeat == nil || rows[0].LastHeartbeat.Result != HeartbeatFailed {
+		t.Errorf("ops overview = %+v", rows[0])
+	}
+	if rows[1].QueueDepth != 0 || rows[1].LastHeartbeat != nil || rows[1].Status != FleetStateNoSession {
+		t.Errorf("idle overview = %+v", rows[1])
+	}
+}
+
+func TestSortConductorOverviews(t *testing.T) {
+	now := time.Now()
+	rows := []ConductorOverview{
+		{ConductorMeta: ConductorMeta{Name: "c"}, Status: ConductorStateRunning, QueueDepth: 1, SpendToday: 2,
+			LastHeartbeat: &HeartbeatRun{Time: now, Result: HeartbeatSent}},
+		{ConductorMeta: ConductorMeta{Name: "a"}, Status: FleetStateNoSession, QueueDepth: 5, SpendToday: 0.5},
+		{ConductorMeta: ConductorMeta{Name: "b"}, Status: ConductorStateStopped, QueueDepth: 1, SpendToday: 7,
+			LastHeartbeat: &HeartbeatRun{Time: now, Result: HeartbeatFailed}},
+	}
+	names := func() []string {
+		var out []string
+		for _, row := range rows {
+			out = append(out, row.Name)
+		}
+		return out
+	}
+
+	cases := []struct {
+		key     string
+		reverse bool
+		want    []string
+	}{
+		{ConductorSortName, false, []string{"a", "b", "c"}},
+		{ConductorSortName, true, []string{"c", "b", "a"}},
+		{ConductorSortQueue, false, []string{"a", "b", "c"}},
+		{ConductorSortSpend, false, []string{"b", "c", "a"}},
+		{ConductorSortStatus, false, []string{"a", "b", "c"}},
+		{ConductorSortHeartbeat, false, []string{"b", "a", "c"}},
+	}
+	for _, tc := range cases {
+		if err := SortConductorOverviews(rows, tc.key, tc.reverse); err != nil {
+			t.Fatal(err)
+		}
+		if got := names(); !slices.Equal(got, tc.want) {
+			t.Errorf("sort %s reverse=%v = %v, want %v", tc.key, tc.reverse, got, tc.want)
+		}
+	}
+	if err := SortConductorOverviews(rows, "mood", false); err == nil {
+		t.Error("unknown sort key accepted")
+	}
+}
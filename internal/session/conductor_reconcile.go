@@ -0,0 +1,202 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReconcileResult describes how a conductor's installed heartbeat differed
+// from its settings and whether ReconcileConductor updated it
+type ReconcileResult struct {
+	Name    string   `json:"name"`
+	Backend string   `json:"backend"`
+	Drift   []string `json:"drift,omitempty"`
+	Updated bool     `json:"updated"`
+}
+
+// heartbeatDrift is what differs, split by what fixing it takes
+type heartbeatDrift struct {
+	script    bool // heartbeat.sh is rewritten in place
+	timer     bool // the timer is re-rendered and reloaded
+	backend   bool // the old backend's timer is removed first
+	uninstall bool // heartbeats are disabled but a timer is installed
+}
+
+// HeartbeatDrift compares a conductor's installed heartbeat.sh and timer
+// (plist, systemd units or crontab line) with what its current settings
+// render, without changing anything
+func HeartbeatDrift(name string) (*ReconcileResult, error) {
+	result, _, err := diffHeartbeat(name)
+	return result, err
+}
+
+// ReconcileConductor re-renders a conductor's heartbeat script and timer when
+// they drifted from its settings (interval, schedule, timezone, profile,
+// backend) and reloads the scheduler; nothing is touched when they match
+func ReconcileConductor(name string) (*ReconcileResult, error) {
+	result, drift, err := diffHeartbeat(name)
+	if err != nil || len(result.Drift) == 0 {
+		return result, err
+	}
+	meta, err := LoadConductorMeta(name)
+	if err != nil {
+		return result, err
+	}
+
+	if drift.uninstall {
+		if err := UninstallHeartbeatDaemon(name); err != nil {
+			return result, fmt.Errorf("failed to remove heartbeat timer: %w", err)
+		}
+		result.Updated = true
+		return result, nil
+	}
+	if drift.script {
+		if err := InstallHeartbeatScript(name, meta.Profile); err != nil {
+			return result, fmt.Errorf("failed to write heartbeat.sh: %w", err)
+		}
+		result.Updated = true
+	}
+	if drift.backend {
+		if err := UninstallHeartbeatDaemon(name); err != nil {
+			return result, fmt.Errorf("failed to remove old heartbeat timer: %w", err)
+		}
+	}
+	if drift.timer || drift.backend {
+		if err := InstallHeartbeatDaemon(name, meta.Profile, HeartbeatIntervalFor(meta), EffectiveHeartbeatSchedule(meta)); err != nil {
+			return result, err
+		}
+		result.Updated = true
+	}
+	return result, nil
+}
+
+func diffHeartbeat(name string) (*ReconcileResult, heartbeatDrift, error) {
+	var drift heartbeatDrift
+	result := &ReconcileResult{Name: name, Backend: HeartbeatBackend()}
+	meta, err := LoadConductorMeta(name)
+	if err != nil {
+		return result, drift, err
+	}
+
+	if !meta.HeartbeatEnabled {
+		state, err := HeartbeatDaemonStatus(name)
+		if err != nil {
+			return result, drift, err
+		}
+		if state.Installed {
+			result.Drift = append(result.Drift, "heartbeat disabled but timer installed")
+			drift.uninstall = true
+		}
+		return result, drift, nil
+	}
+	if result.Backend == "" {
+		return result, drift, fmt.Errorf("no heartbeat scheduler available (launchd, systemd or crontab)")
+	}
+
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return result, drift, err
+	}
+	if reason := fileDrift(filepath.Join(dir, "heartbeat.sh"), renderHeartbeatScript(name, meta.Profile)); reason != "" {
+		result.Drift = append(result.Drift, reason)
+		drift.script = true
+	}
+	if stamp, ok := readHeartbeatStamp(name); ok && stamp.Backend != "" && stamp.Backend != result.Backend {
+		result.Drift = append(result.Drift, fmt.Sprintf("backend changed from %s to %s", stamp.Backend, result.Backend))
+		drift.backend = true
+	}
+
+	interval, schedule := HeartbeatIntervalFor(meta), EffectiveHeartbeatSchedule(meta)
+	reasons, err := timerDrift(name, result.Backend, interval, schedule, meta.Timezone)
+	if err != nil {
+		return result, drift, err
+	}
+	if len(reasons) > 0 {
+		result.Drift = append(result.Drift, reasons...)
+		drift.timer = true
+	}
+	return result, drift, nil
+}
+
+// timerDrift compares the installed timer for backend with the one the
+// interval, schedule and timezone render
+func timerDrift(name, backend string, interval int, schedule, timezone string) ([]string, error) {
+	var reasons []string
+	switch backend {
+	case HeartbeatBackendLaunchd:
+		var plist string
+		var err error
+		if schedule != "" {
+			plist, err = GenerateHeartbeatCalendarPlist(name, schedule)
+		} else {
+			plist, err = GenerateHeartbeatPlist(name, interval)
+		}
+		if err != nil {
+			return nil, err
+		}
+		path, err := HeartbeatPlistPath(name)
+		if err != nil {
+			return nil, err
+		}
+		if reason := fileDrift(path, plist); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	case HeartbeatBackendSystemd:
+		service, err := GenerateSystemdHeartbeatService(name)
+		if err != nil {
+			return nil, err
+		}
+		timer := GenerateSystemdHeartbeatTimer(name, interval)
+		if schedule != "" {
+			if timer, err = GenerateSystemdHeartbeatCalendarTimer(name, schedule, timezone); err != nil {
+				return nil, err
+			}
+		}
+		servicePath, err := SystemdHeartbeatServicePath(name)
+		if err != nil {
+			return nil, err
+		}
+		timerPath, err := SystemdHeartbeatTimerPath(name)
+		if err != nil {
+			return nil, err
+		}
+		if reason := fileDrift(servicePath, service); reason != "" {
+			reasons = append(reasons, reason)
+		}
+		if reason := fileDrift(timerPath, timer); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	case HeartbeatBackendCron:
+		want, err := GenerateHeartbeatCronLine(name, interval, schedule)
+		if err != nil {
+			return nil, err
+		}
+		crontab, err := readCrontab()
+		if err != nil {
+			return nil, err
+		}
+		switch line, ok := findCrontabLine(crontab, heartbeatCronMarker(name)); {
+		case !ok:
+			reasons = append(reasons, "crontab line missing")
+		case line != strings.TrimSpace(want):
+			reasons = append(reasons, "crontab line differs")
+		}
+	}
+	return reasons, nil
+}
+
+// fileDrift returns why the file at path does not hold want, or ""
+func fileDrift(path, want string) string {
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return path + " missing"
+	case err != nil:
+		return fmt.Sprintf("%s unreadable: %v", path, err)
+	case string(data) != want:
+		return path + " differs"
+	}
+	return ""
+}
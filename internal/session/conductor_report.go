@@ -0,0 +1,126 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+var conductorLog = logging.ForComponent(logging.CompConductor)
+
+// heartbeatMessagePrefix starts every heartbeat the bridge sends to a conductor
+const heartbeatMessagePrefix = "[HEARTBEAT]"
+
+// conductorTaskHistoryDays is how long conductor task history is kept
+const conductorTaskHistoryDays = 90
+
+// ConductorTaskKind classifies a message sent to a conductor session
+func ConductorTaskKind(message string) string {
+	if strings.HasPrefix(strings.TrimSpace(message), heartbeatMessagePrefix) {
+		return statedb.ConductorTaskHeartbeat
+	}
+	return statedb.ConductorTaskMessage
+}
+
+// RecordConductorTask logs a message sent to inst when inst is a conductor
+// session. Other sessions are ignored.
+func RecordConductorTask(db *statedb.StateDB, inst *Instance, message string, at time.Time) error {
+	if db == nil || inst == nil {
+		return nil
+	}
+	name, ok := ConductorNameFromTitle(inst.Title)
+	if !ok {
+		return nil
+	}
+	conductorLog.Info("conductor_task",
+		slog.String("conductor", name),
+		slog.String("session_id", inst.ID),
+		slog.String("kind", ConductorTaskKind(message)),
+	)
+	if err := db.EnqueueConductorTask(name, inst.ID, ConductorTaskKind(message), at); err != nil {
+		return err
+	}
+	return db.PruneConductorTasks(at.AddDate(0, 0, -conductorTaskHistoryDays))
+}
+
+// ConductorTaskDedupeKey derives the default dedupe key for a message: a hash
+// of its text with whitespace collapsed.
+func ConductorTaskDedupeKey(message string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(message), " ")))
+	return "sha256:" + hex.EncodeToString(sum[:16])
+}
+
+// DedupeConductorTask reports whether message (or an explicit key) was already
+// sent to inst within the [conductor] dedupe window, recording the merge if
+// so. Heartbeats and non-conductor sessions are never deduplicated.
+func DedupeConductorTask(db *statedb.StateDB, inst *Instance, message, key string, at time.Time) (statedb.TaskDedupeResult, error) {
+	if db == nil || inst == nil || ConductorTaskKind(message) == statedb.ConductorTaskHeartbeat {
+		return statedb.TaskDedupeResult{}, nil
+	}
+	name, ok := ConductorNameFromTitle(inst.Title)
+	if !ok {
+		return statedb.TaskDedupeResult{}, nil
+	}
+	settings := GetConductorSettings()
+	window := settings.GetDedupeWindow()
+	if window == 0 {
+		return statedb.TaskDedupeResult{}, nil
+	}
+	if key == "" {
+		key = ConductorTaskDedupeKey(message)
+	}
+	return db.DedupeConductorTask(name, inst.ID, key, window, at)
+}
+
+// ConductorLoadReport returns per-conductor, per-day throughput for profile
+// over the last days days (including today).
+func ConductorLoadReport(profile string, days int, now time.Time) ([]statedb.ConductorLoadRow, error) {
+	if days <= 0 {
+		days = 1
+	}
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer storage.Close()
+	db := storage.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("state database not available for profile %s", profile)
+	}
+	return db.ConductorLoadReport(statedb.DayKey(now.AddDate(0, 0, -(days-1))), statedb.DayKey(now))
+}
+
+// ConductorLoadReportFor returns load rows for the given conductors, reading
+// each profile's database once. Rows for conductors not in the list are dropped.
+func ConductorLoadReportFor(conductors []ConductorMeta, days int, now time.Time) ([]statedb.ConductorLoadRow, error) {
+	byProfile := make(map[string]map[string]bool)
+	var profiles []string
+	for _, meta := range conductors {
+		profile := normalizeConductorProfile(meta.Profile)
+		if byProfile[profile] == nil {
+			byProfile[profile] = make(map[string]bool)
+			profiles = append(profiles, profile)
+		}
+		byProfile[profile][meta.Name] = true
+	}
+
+	var result []statedb.ConductorLoadRow
+	for _, profile := range profiles {
+		rows, err := ConductorLoadReport(profile, days, now)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile, err)
+		}
+		for _, row := range rows {
+			if byProfile[profile][row.Conductor] {
+				result = append(result, row)
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// conductorRunbookFileName is the operator runbook in each conductor directory
+const conductorRunbookFileName = "RUNBOOK.md"
+
+// ConductorRunbookPath returns the path of a conductor's RUNBOOK.md
+func ConductorRunbookPath(name string) (string, error) {
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, conductorRunbookFileName), nil
+}
+
+// RenderConductorRunbook returns the runbook for a conductor: how to check
+// on, attach to, pause and find the files of a conductor, for teammates
+// covering for its owner. Where the identity card tells the conductor who it
+// is, the runbook tells people how to operate it.
+func RenderConductorRunbook(meta *ConductorMeta) string {
+	profile := normalizeConductorProfile(meta.Profile)
+	title := ConductorSessionTitle(meta.Name)
+	cli := "agent-deck"
+	if profile != DefaultProfile {
+		cli = "agent-deck -p " + profile
+	}
+	dir, _ := ConductorNameDir(meta.Name)
+	base, _ := ConductorDir()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Runbook: conductor %s\n\n", meta.Name)
+	fmt.Fprintf(&b, "Generated by agent-deck from meta.json and rewritten whenever the conductor changes. "+
+		"Change the conductor ('agent-deck conductor identity %s', 'agent-deck conductor schedule %s') rather than editing this file.\n\n", meta.Name, meta.Name)
+
+	b.WriteString("## At a glance\n\n")
+	if meta.Description != "" {
+		fmt.Fprintf(&b, "- **Purpose:** %s\n", meta.Description)
+	}
+	fmt.Fprintf(&b, "- **Profile:** %s\n", profile)
+	fmt.Fprintf(&b, "- **Session:** %s\n", title)
+	if schedule := EffectiveHeartbeatSchedule(meta); meta.HeartbeatEnabled && schedule != "" {
+		fmt.Fprintf(&b, "- **Heartbeat:** on schedule %s\n", schedule)
+	} else if meta.HeartbeatEnabled {
+		fmt.Fprintf(&b, "- **Heartbeat:** every %d minutes\n", HeartbeatIntervalFor(meta))
+	} else {
+		b.WriteString("- **Heartbeat:** disabled\n")
+	}
+	if meta.Timezone != "" {
+		fmt.Fprintf(&b, "- **Timezone:** %s\n", meta.Timezone)
+	}
+	if meta.QuietHours != "" {
+		fmt.Fprintf(&b, "- **Quiet hours:** %s (only urgent issues are escalated)\n", meta.QuietHours)
+	}
+	if meta.Language != "" {
+		fmt.Fprintf(&b, "- **Replies in:** %s\n", meta.Language)
+	}
+	if len(meta.SkillPacks) > 0 {
+		fmt.Fprintf(&b, "- **Skill packs:** %s\n", strings.Join(meta.SkillPacks, ", "))
+	}
+	if n := meta.Notifications; n != nil && len(n.Senders()) > 0 {
+		var channels, events []string
+		for _, sender := range n.Senders() {
+			channels = append(channels, sender.Name())
+		}
+		for _, event := range ConductorNotifyEvents {
+			if n.Wants(event) {
+				events = append(events, event)
+			}
+		}
+		fmt.Fprintf(&b, "- **Alerts:** %s on %s\n", strings.Join(channels, " and "), strings.Join(events, ", "))
+	}
+	if meta.CreatedAt != "" {
+		fmt.Fprintf(&b, "- **Created:** %s\n", meta.CreatedAt)
+	}
+
+	if len(meta.Responsibilities) > 0 {
+		b.WriteString("\n## Responsibilities\n\n")
+		for _, r := range meta.Responsibilities {
+			fmt.Fprintf(&b, "- %s\n", r)
+		}
+	}
+
+	b.WriteString("\n## Contacts\n\n")
+	if len(meta.EscalationContacts) > 0 {
+		for _, c := range meta.EscalationContacts {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	} else {
+		fmt.Fprintf(&b, "No escalation contacts set. Add them with '%s conductor identity %s --contacts ...'.\n", cli, meta.Name)
+	}
+
+	b.WriteString("\n## Check on it\n\n```bash\n")
+	fmt.Fprintf(&b, "agent-deck conductor status %s        # session, heartbeat and bridge health\n", meta.Name)
+	fmt.Fprintf(&b, "agent-deck conductor history %s       # recent heartbeat runs\n", meta.Name)
+	fmt.Fprintf(&b, "agent-deck conductor task list %s     # queued and running worker tasks\n", meta.Name)
+	fmt.Fprintf(&b, "%s session output %s   # its last reply\n", cli, title)
+	b.WriteString("```\n")
+
+	b.WriteString("\n## Attach\n\n```bash\n")
+	fmt.Fprintf(&b, "%s session attach %s\n", cli, title)
+	b.WriteString("```\n\nPress Ctrl+Q to detach; the conductor keeps running. ")
+	fmt.Fprintf(&b, "To hand it a message without attaching: '%s session send %s \"...\"'.\n", cli, title)
+
+	b.WriteString("\n## Pause and resume\n\n```bash\n")
+	b.WriteString("agent-deck maintenance start --for 2h --reason \"...\"   # pause heartbeats and restarts deck-wide\n")
+	b.WriteString("agent-deck maintenance stop\n")
+	fmt.Fprintf(&b, "%s session stop %s    # stop this conductor only\n", cli, title)
+	fmt.Fprintf(&b, "%s session start %s\n", cli, title)
+	b.WriteString("agent-deck emergency-stop                   # interrupt every busy session\n")
+	b.WriteString("```\n")
+
+	b.WriteString("\n## Files and logs\n\n")
+	fmt.Fprintf(&b, "- `%s`: this conductor's directory\n", dir)
+	b.WriteString("  - `meta.json`: settings this runbook is generated from\n")
+	b.WriteString("  - `CLAUDE.md`, `POLICY.md`, `IDENTITY.md`: the conductor's instructions\n")
+	b.WriteString("  - `heartbeat.log`: output of the heartbeat timer\n")
+	b.WriteString("  - `heartbeats.jsonl`: heartbeat run history\n")
+	b.WriteString("  - `tasks/`: worker task queue\n")
+	fmt.Fprintf(&b, "- `%s`: Telegram/Slack bridge log, shared by all conductors\n", filepath.Join(base, "bridge.log"))
+	return b.String()
+}
+
+// WriteConductorRunbook writes RUNBOOK.md into the conductor's directory
+func WriteConductorRunbook(meta *ConductorMeta) error {
+	path, err := ConductorRunbookPath(meta.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(RenderConductorRunbook(meta)), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", conductorRunbookFileName, err)
+	}
+	return nil
+}
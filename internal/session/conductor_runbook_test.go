@@ -0,0 +1,47 @@
+package session
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConductorRunbookFollowsMeta(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "work", true, "Production on-call", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	path, _ := ConductorRunbookPath("ops")
+	runbook, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("setup should write the runbook: %v", err)
+	}
+	for _, want := range []string{
+		"**Purpose:** Production on-call",
+		"agent-deck -p work session attach conductor-ops",
+		"No escalation contacts set",
+		"heartbeat.log",
+	} {
+		if !strings.Contains(string(runbook), want) {
+			t.Errorf("runbook lacks %q:\n%s", want, runbook)
+		}
+	}
+
+	meta, _ := LoadConductorMeta("ops")
+	meta.EscalationContacts = []string{"@alice (Slack)"}
+	meta.HeartbeatEnabled = false
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+	runbook, _ = os.ReadFile(path)
+	if !strings.Contains(string(runbook), "- @alice (Slack)") || !strings.Contains(string(runbook), "**Heartbeat:** disabled") {
+		t.Errorf("runbook not rewritten on meta change:\n%s", runbook)
+	}
+}
+
+func TestConductorRunbookDefaultProfileOmitsFlag(t *testing.T) {
+	runbook := RenderConductorRunbook(&ConductorMeta{Name: "ops", Profile: DefaultProfile})
+	if strings.Contains(runbook, "-p default") || !strings.Contains(runbook, "agent-deck session attach conductor-ops") {
+		t.Errorf("default profile commands should not pass -p:\n%s", runbook)
+	}
+}
@@ -28,6 +28,17 @@ Each conductor has its own identity in its subdirectory and its own policy in PO
 |---------|-------------|
 | ` + "`" + `agent-deck -p <PROFILE> session send <id_or_title> "message"` + "`" + ` | Send a message. Has built-in 60s wait for agent readiness. |
 | ` + "`" + `agent-deck -p <PROFILE> session send <id_or_title> "message" --no-wait` + "`" + ` | Send immediately without waiting for ready state. |
+| ` + "`" + `agent-deck -p <PROFILE> session receipt <receipt_id> --json` + "`" + ` | Check a sent message: queued, delivered, acknowledged, completed or failed. ` + "`" + `session send --json` + "`" + ` returns the ` + "`" + `receipt_id` + "`" + `; add ` + "`" + `--wait` + "`" + ` to block until it completes. |
+
+### Handing Off Tasks
+Queue a task instead of sending directly when the worker may be busy: each heartbeat injects the oldest task of every idle worker, and the heartbeat message lists tasks that finished.
+
+| Command | Description |
+|---------|-------------|
+| ` + "`" + `agent-deck conductor task add <NAME> <id_or_title> "prompt"` + "`" + ` | Queue a prompt for a worker session (` + "`" + `--now` + "`" + ` dispatches immediately) |
+| ` + "`" + `agent-deck conductor task list <NAME>` + "`" + ` | Show pending, active, done and failed tasks |
+| ` + "`" + `agent-deck conductor task show <NAME> <task_id>` + "`" + ` | Show a finished task with the worker's reply |
+| ` + "`" + `agent-deck conductor task cancel <NAME> <task_id> --summarize` + "`" + ` | Cancel a task; a running worker is interrupted and asked where it got to |
 
 ### Session Control
 | Command | Description |
@@ -267,6 +278,7 @@ import json
 import logging
 import os
 import re
+import shutil
 import subprocess
 import sys
 import time
@@ -296,7 +308,8 @@ except ImportError:
 # Configuration
 # ---------------------------------------------------------------------------
 
-AGENT_DECK_DIR = Path.home() / ".agent-deck"
+# AGENT_DECK_HOME is set for named decks running side by side with the default one
+AGENT_DECK_DIR = Path(os.environ.get("AGENT_DECK_HOME") or Path.home() / ".agent-deck")
 CONFIG_PATH = AGENT_DECK_DIR / "config.toml"
 CONDUCTOR_DIR = AGENT_DECK_DIR / "conductor"
 LOG_PATH = CONDUCTOR_DIR / "bridge.log"
@@ -313,6 +326,15 @@ RESPONSE_TIMEOUT = 300
 # Poll interval when waiting for conductor response (seconds)
 POLL_INTERVAL = 2
 
+# How often to check whether agent-deck or bridge.py changed on disk (seconds)
+UPGRADE_CHECK_INTERVAL = 30
+
+# Max time to wait for in-flight requests before re-exec (seconds)
+DRAIN_TIMEOUT = RESPONSE_TIMEOUT + 30
+
+# Reply sent for messages that arrive while the bridge drains for an upgrade
+DRAINING_REPLY = "[Bridge is restarting for an upgrade. Please resend in a minute.]"
+
 # ---------------------------------------------------------------------------
 # Logging
 # ---------------------------------------------------------------------------
@@ -387,6 +409,7 @@ def load_config() -> dict:
             "configured": sl_configured,
         },
         "heartbeat_interval": conductor_cfg.get("heartbeat_interval", 15),
+        "status_export": config.get("status_export", {}),
     }
 
 
@@ -418,6 +441,19 @@ def conductor_session_title(name: str) -> str:
     return f"conductor-{name}"
 
 
+def conductor_local_time(conductor: dict) -> str:
+    """Current time in the conductor's timezone (meta.json "timezone"), or ""."""
+    tz = conductor.get("timezone", "")
+    if not tz:
+        return ""
+    try:
+        from datetime import datetime
+        from zoneinfo import ZoneInfo
+        return datetime.now(ZoneInfo(tz)).strftime("%a %Y-%m-%d %H:%M ") + tz
+    except Exception:
+        return ""
+
+
 def get_conductor_names() -> list[str]:
     """Get list of all conductor names."""
     return [c["name"] for c in discover_conductors()]
@@ -442,6 +478,9 @@ def select_heartbeat_conductors(conductors: list[dict]) -> list[dict]:
     for c in conductors:
         if not c.get("heartbeat_enabled", True):
             continue
+        # Scheduled heartbeats are run by the conductor's own timer
+        if c.get("heartbeat_schedule"):
+            continue
         profile = c.get("profile") or "default"
         current = selected.get(profile)
         if current is None:
@@ -516,12 +555,17 @@ def get_session_output(session: str, profile: str | None = None) -> str:
 
 
 def send_to_conductor(
-    session: str, message: str, profile: str | None = None
+    session: str, message: str, profile: str | None = None, interactive: bool = False
 ) -> bool:
-    """Send a message to the conductor session. Returns True on success."""
-    result = run_cli(
-        "session", "send", session, message, "--no-wait", profile=profile, timeout=30
-    )
+    """Send a message to the conductor session. Returns True on success.
+
+    Interactive (chat) messages skip deduplication, since a user repeating
+    themselves means it, and are delivered during maintenance windows.
+    """
+    args = ["session", "send", session, message, "--no-wait"]
+    if interactive:
+        args += ["--no-dedupe", "--force"]
+    result = run_cli(*args, profile=profile, timeout=30)
     if result.returncode != 0:
         log.error(
             "Failed to send to conductor: %s", result.stderr.strip()
@@ -578,12 +622,60 @@ def get_sessions_list_all(profiles: list[str]) -> list[tuple[str, dict]]:
     return all_sessions
 
 
-def ensure_conductor_running(name: str, profile: str) -> bool:
-    """Ensure the conductor session exists and is running."""
+def active_maintenance() -> dict | None:
+    """Return the active agent-deck maintenance window, or None."""
+    result = run_cli("maintenance", "status", "--json", timeout=30)
+    if result.returncode != 0:
+        return None
+    try:
+        data = json.loads(result.stdout)
+    except json.JSONDecodeError:
+        return None
+    return data.get("maintenance") if data.get("active") else None
+
+
+def emergency_stop(stop: bool, reason: str = "") -> str:
+    """Stop or resume the whole deck and return a reply for the chat."""
+    if stop:
+        args = ["emergency-stop", "--by", "bridge", "--json"]
+        if reason:
+            args.append(reason)
+    else:
+        args = ["emergency-stop", "resume", "--json"]
+    result = run_cli(*args, timeout=120)
+    try:
+        data = json.loads(result.stdout)
+    except json.JSONDecodeError:
+        data = {}
+    if result.returncode != 0:
+        return f"Failed: {data.get('error') or result.stderr.strip()}"
+    if not stop:
+        return "Deck resumed. Sessions accept messages again."
+    interrupted = data.get("emergency_stop", {}).get("interrupted") or []
+    lines = [f"EMERGENCY STOP. Interrupted {len(interrupted)} session(s)."]
+    lines += [f"  {title}" for title in interrupted]
+    lines.append("Nothing runs until /resume.")
+    return "\n".join(lines)
+
+
+def ensure_conductor_running(name: str, profile: str, auto: bool = True) -> bool:
+    """Ensure the conductor session exists and is running.
+
+    Automatic restarts (auto=True) are skipped during maintenance windows.
+    """
     profile = profile or "default"
     session_title = conductor_session_title(name)
     status = get_session_status(session_title, profile=profile)
 
+    if status == "error" and auto:
+        window = active_maintenance()
+        if window:
+            log.info(
+                "Conductor %s not running; maintenance window %s active, not restarting",
+                name, window.get("window", {}).get("name", ""),
+            )
+            return False
+
     if status == "error":
         log.info(
             "Conductor %s not running, attempting to start...", name,
@@ -767,7 +859,7 @@ def create_telegram_bot(config: dict):
         await message.answer(
             "Conductor bridge active.\n"
             f"Conductors: {', '.join(names) if names else 'none'}\n"
-            "Commands: /status /sessions /help /restart\n"
+            "Commands: /status /sessions /help /restart /stop /resume\n"
             f"Route to conductor: <name>: <message>\n"
             f"Default conductor: {default}"
         )
@@ -838,6 +930,8 @@ def create_telegram_bot(config: dict):
             "/status    - Aggregated status across all profiles\n"
             "/sessions  - List all sessions (all profiles)\n"
             "/restart   - Restart a conductor (specify name)\n"
+            "/stop      - Emergency stop: interrupt every busy session\n"
+            "/resume    - Resume after an emergency stop\n"
             "/help      - This message\n\n"
             f"Conductors: {', '.join(names) if names else 'none'}\n"
             f"Route: <name>: <message>\n"
@@ -884,14 +978,23 @@ def create_telegram_bot(config: dict):
                 f"Restart failed: {result.stderr.strip()}"
             )
 
-    @dp.message()
-    async def handle_message(message: types.Message):
-        """Forward any text message to the conductor and return its response."""
+    @dp.message(Command("stop"))
+    async def cmd_stop(message: types.Message):
         if not is_authorized(message):
             return
-        if not message.text:
+        # Optional reason: /stop deleting the wrong files
+        parts = message.text.strip().split(None, 1)
+        reason = parts[1] if len(parts) > 1 else ""
+        await message.answer(emergency_stop(True, reason))
+
+    @dp.message(Command("resume"))
+    async def cmd_resume(message: types.Message):
+        if not is_authorized(message):
             return
+        await message.answer(emergency_stop(False))
 
+    async def forward_message(message: types.Message):
+        """Forward a text message to the conductor and return its response."""
         conductor_names = get_conductor_names()
         conductors = discover_conductors()
 
@@ -919,7 +1022,7 @@ def create_telegram_bot(config: dict):
         profile = target["profile"]
 
         # Ensure conductor is running
-        if not ensure_conductor_running(target["name"], profile):
+        if not ensure_conductor_running(target["name"], profile, auto=False):
             await message.answer(
                 f"[Could not start conductor {target['name']}. Check agent-deck.]"
             )
@@ -930,7 +1033,7 @@ def create_telegram_bot(config: dict):
             "User message -> [%s]: %s", target["name"], cleaned_msg[:100]
         )
         if not send_to_conductor(
-            session_title, cleaned_msg, profile=profile
+            session_title, cleaned_msg, profile=profile, interactive=True
         ):
             await message.answer(
                 f"[Failed to send message to conductor {target['name']}.]"
@@ -952,6 +1055,19 @@ def create_telegram_bot(config: dict):
             prefixed = f"{name_tag}{chunk}" if name_tag else chunk
             await message.answer(prefixed)
 
+    @dp.message()
+    async def handle_message(message: types.Message):
+        """Forward any text message to the conductor and return its response."""
+        if not is_authorized(message):
+            return
+        if not message.text:
+            return
+        if is_draining():
+            await message.answer(DRAINING_REPLY)
+            return
+        async with inflight():
+            await forward_message(message)
+
     return bot, dp
 
 
@@ -1036,6 +1152,14 @@ def create_slack_app(config: dict):
 
     async def _handle_slack_text(text: str, say, thread_ts: str = None):
         """Shared handler for Slack messages and mentions."""
+        if is_draining():
+            await _safe_say(say, text=DRAINING_REPLY, thread_ts=thread_ts)
+            return
+        async with inflight():
+            await _forward_slack_text(text, say, thread_ts)
+
+    async def _forward_slack_text(text: str, say, thread_ts: str = None):
+        """Forward a Slack message to the conductor and post its response."""
         conductor_names = get_conductor_names()
         conductors = discover_conductors()
 
@@ -1063,7 +1187,7 @@ def create_slack_app(config: dict):
         session_title = conductor_session_title(target["name"])
         profile = target["profile"]
 
-        if not ensure_conductor_running(target["name"], profile):
+        if not ensure_conductor_running(target["name"], profile, auto=False):
             await _safe_say(
                 say,
                 text=f"[Could not start conductor {target['name']}. Check agent-deck.]",
@@ -1072,7 +1196,9 @@ def create_slack_app(config: dict):
             return
 
         log.info("Slack message -> [%s]: %s", target["name"], cleaned_msg[:100])
-        if not send_to_conductor(session_title, cleaned_msg, profile=profile):
+        if not send_to_conductor(
+            session_title, cleaned_msg, profile=profile, interactive=True
+        ):
             await _safe_say(
                 say,
                 text=f"[Failed to send message to conductor {target['name']}.]",
@@ -1237,6 +1363,32 @@ def create_slack_app(config: dict):
         else:
             await respond(f"Restart failed: {result.stderr.strip()}")
 
+    @app.command("/ad-stop")
+    async def slack_cmd_stop(ack, respond, command):
+        """Handle /ad-stop slash command."""
+        await ack()
+
+        # Authorization check
+        user_id = command.get("user_id", "")
+        if not is_slack_authorized(user_id):
+            await respond("⛔ Unauthorized. Contact your administrator.")
+            return
+
+        await respond(emergency_stop(True, command.get("text", "").strip()))
+
+    @app.command("/ad-resume")
+    async def slack_cmd_resume(ack, respond, command):
+        """Handle /ad-resume slash command."""
+        await ack()
+
+        # Authorization check
+        user_id = command.get("user_id", "")
+        if not is_slack_authorized(user_id):
+            await respond("⛔ Unauthorized. Contact your administrator.")
+            return
+
+        await respond(emergency_stop(False))
+
     @app.command("/ad-help")
     async def slack_cmd_help(ack, respond, command):
         """Handle /ad-help slash command."""
@@ -1255,6 +1407,8 @@ def create_slack_app(config: dict):
             "/ad-status    - Aggregated status across all profiles\n"
             "/ad-sessions  - List all sessions (all profiles)\n"
             "/ad-restart   - Restart a conductor (specify name)\n"
+            "/ad-stop      - Emergency stop: interrupt every busy session\n"
+            "/ad-resume    - Resume after an emergency stop\n"
             "/ad-help      - This message\n\n"
             f"Conductors: {', '.join(names) if names else 'none'}\n"
             f"Route: <name>: <message>\n"
@@ -1285,6 +1439,14 @@ async def heartbeat_loop(config: dict, telegram_bot=None, slack_app=None, slack_
     while True:
         await asyncio.sleep(interval_seconds)
 
+        window = active_maintenance()
+        if window:
+            log.info(
+                "Heartbeat skipped: maintenance window %s active",
+                window.get("window", {}).get("name", ""),
+            )
+            continue
+
         all_conductors = discover_conductors()
         conductors = select_heartbeat_conductors(all_conductors)
         for conductor in conductors:
@@ -1336,6 +1498,12 @@ async def heartbeat_loop(config: dict, telegram_bot=None, slack_app=None, slack_
                     f"[HEARTBEAT] [{name}] Status: {waiting} waiting, "
                     f"{running} running, {idle} idle, {error} error."
                 ]
+                local_time = conductor_local_time(conductor)
+                if local_time:
+                    parts.append(f"Local time: {local_time}.")
+                language = conductor.get("language", "")
+                if language:
+                    parts.append(f"Reply in {language}.")
                 if waiting_details:
                     parts.append(
                         f"Waiting sessions: {', '.join(waiting_details)}."
@@ -1410,6 +1578,106 @@ async def heartbeat_loop(config: dict, telegram_bot=None, slack_app=None, slack_
                 log.error("Heartbeat [%s] error: %s", conductor.get("name", "?"), e)
 
 
+# ---------------------------------------------------------------------------
+# Self-upgrade
+# ---------------------------------------------------------------------------
+
+_inflight_count = 0
+_draining = False
+
+
+def is_draining() -> bool:
+    """True once an upgrade was detected and new requests should be refused."""
+    return _draining
+
+
+class inflight:
+    """Async context manager counting requests the bridge is still serving."""
+
+    async def __aenter__(self):
+        global _inflight_count
+        _inflight_count += 1
+
+    async def __aexit__(self, *exc):
+        global _inflight_count
+        _inflight_count -= 1
+        return False
+
+
+def file_fingerprint(path: str | None) -> tuple | None:
+    """Identify a file version by inode, size and mtime (None if missing)."""
+    if not path:
+        return None
+    try:
+        st = os.stat(path)
+    except OSError:
+        return None
+    return (st.st_ino, st.st_size, st.st_mtime_ns)
+
+
+async def status_export_loop(config: dict):
+    """Rewrite the [status_export] JSON snapshot every interval seconds."""
+    export_cfg = config.get("status_export", {})
+    if not export_cfg.get("path"):
+        return
+    interval = export_cfg.get("interval", 60)
+    if interval <= 0:
+        interval = 60
+
+    log.info("Status export started (every %d seconds to %s)", interval, export_cfg["path"])
+    while True:
+        result = run_cli("status", "export", "-q", timeout=60)
+        if result.returncode != 0:
+            log.warning("status export failed: %s", (result.stderr or result.stdout).strip())
+        await asyncio.sleep(interval)
+
+
+async def upgrade_watch_loop():
+    """Re-exec the bridge when agent-deck or bridge.py is replaced on disk.
+
+    In-flight requests are drained first (new ones get DRAINING_REPLY), then
+    a changed binary is asked to refresh bridge.py before os.execv so the
+    restarted bridge runs the script shipped with the new agent-deck.
+    """
+    global _draining
+    bridge_path = os.path.abspath(__file__)
+    start_binary = file_fingerprint(shutil.which("agent-deck"))
+    start_script = file_fingerprint(bridge_path)
+
+    while True:
+        await asyncio.sleep(UPGRADE_CHECK_INTERVAL)
+        binary = file_fingerprint(shutil.which("agent-deck"))
+        script = file_fingerprint(bridge_path)
+        # A missing file usually means an install is mid-copy; check again later
+        if binary is None or script is None:
+            continue
+        binary_changed = binary != start_binary
+        script_changed = script != start_script
+        if not binary_changed and not script_changed:
+            continue
+
+        log.info(
+            "Upgrade detected (binary=%s, script=%s); draining %d request(s)",
+            binary_changed, script_changed, _inflight_count,
+        )
+        _draining = True
+        deadline = time.monotonic() + DRAIN_TIMEOUT
+        while _inflight_count > 0 and time.monotonic() < deadline:
+            await asyncio.sleep(1)
+        if _inflight_count > 0:
+            log.warning("Drain timed out with %d request(s) in flight", _inflight_count)
+
+        if binary_changed:
+            result = run_cli("conductor", "bridge", "upgrade", "--no-restart", timeout=60)
+            if result.returncode != 0:
+                log.warning("bridge upgrade failed: %s", (result.stderr or result.stdout).strip())
+
+        log.info("Re-executing bridge")
+        for handler in logging.getLogger().handlers:
+            handler.flush()
+        os.execv(sys.executable, [sys.executable, bridge_path] + sys.argv[1:])
+
+
 # ---------------------------------------------------------------------------
 # Main
 # ---------------------------------------------------------------------------
@@ -1482,7 +1750,9 @@ async def main():
     )
 
     # Run both concurrently
-    tasks = [heartbeat_task]
+    tasks = [heartbeat_task, asyncio.create_task(upgrade_watch_loop())]
+    if config["status_export"].get("path"):
+        tasks.append(asyncio.create_task(status_export_loop(config)))
     if telegram_dp and telegram_bot:
         tasks.append(asyncio.create_task(telegram_dp.start_polling(telegram_bot)))
         log.info("Telegram bot polling started")
@@ -3,9 +3,14 @@ package session
 import (
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
 )
 
 // --- Systemd template generation tests ---
@@ -79,8 +84,8 @@ func TestGenerateSystemdHeartbeatService(t *testing.T) {
 	if strings.Contains(svc, "__NAME__") {
 		t.Error("service output still contains __NAME__ placeholder")
 	}
-	if strings.Contains(svc, "__SCRIPT_PATH__") {
-		t.Error("service output still contains __SCRIPT_PATH__ placeholder")
+	if strings.Contains(svc, "__EXEC_START__") {
+		t.Error("service output still contains __EXEC_START__ placeholder")
 	}
 	if strings.Contains(svc, "__HOME__") {
 		t.Error("service output still contains __HOME__ placeholder")
@@ -96,8 +101,11 @@ func TestGenerateSystemdHeartbeatService(t *testing.T) {
 	if !strings.Contains(svc, "Type=oneshot") {
 		t.Error("heartbeat service should be Type=oneshot")
 	}
-	if !strings.Contains(svc, "heartbeat.sh") {
-		t.Error("service should reference heartbeat.sh script")
+	if !strings.Contains(svc, "conductor heartbeat-run test-conductor") {
+		t.Error("service should run the heartbeat-run subcommand")
+	}
+	if strings.Contains(svc, "/bin/bash") {
+		t.Error("service should not go through a shell script")
 	}
 	if !strings.Contains(svc, "test-conductor") {
 		t.Error("service should contain conductor name in description")
@@ -297,12 +305,187 @@ func TestGetHeartbeatInterval(t *testing.T) {
 
 	for _, tt := range tests {
 		settings := &ConductorSettings{HeartbeatInterval: tt.interval}
-		if got := settings.GetHeartbeatInterval(); got != tt.expected {
+		if got := settings.GetHeartbeatInterval(""); got != tt.expected {
 			t.Errorf("GetHeartbeatInterval() with %d = %d, want %d", tt.interval, got, tt.expected)
 		}
 	}
 }
 
+func TestGetHeartbeatInterval_PerProfile(t *testing.T) {
+	settings := &ConductorSettings{
+		HeartbeatInterval:  20,
+		HeartbeatIntervals: map[string]int{"work": 5, "default": 30, "broken": -1},
+	}
+	for profile, want := range map[string]int{"work": 5, "": 30, "default": 30, "personal": 20, "broken": 20} {
+		if got := settings.GetHeartbeatInterval(profile); got != want {
+			t.Errorf("GetHeartbeatInterval(%q) = %d, want %d", profile, got, want)
+		}
+	}
+	if got := HeartbeatIntervalFor(&ConductorMeta{Profile: "work", HeartbeatInterval: 7}); got != 7 {
+		t.Errorf("meta.json interval should win, got %d", got)
+	}
+}
+
+func TestSyncHeartbeatTimers_ReinstallsOnChange(t *testing.T) {
+	writeMacroConfig(t, "[conductor]\nheartbeat_backend = \"cron\"\n[conductor.heartbeat_intervals]\nwork = 5\n")
+	store := fakeCrontab(t)
+	if err := SaveConductorMeta(&ConductorMeta{Name: "ops", Profile: "work", HeartbeatEnabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InstallHeartbeatScript("ops", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("cron backend is Linux only")
+	}
+
+	// No stamp yet: the timer is (re)installed once
+	updated, err := SyncHeartbeatTimers()
+	if err != nil || len(updated) != 1 {
+		t.Fatalf("first sync = %v, %v; want [ops]", updated, err)
+	}
+	if updated, _ := SyncHeartbeatTimers(); len(updated) != 0 {
+		t.Errorf("unchanged settings reinstalled %v", updated)
+	}
+	if data, _ := os.ReadFile(store); !strings.HasPrefix(string(data), "*/5 * * * *") {
+		t.Errorf("crontab = %q, want every 5 minutes", data)
+	}
+
+	config := filepath.Join(os.Getenv("HOME"), ".agent-deck", "config.toml")
+	if err := os.WriteFile(config, []byte("[conductor]\nheartbeat_backend = \"cron\"\n[conductor.heartbeat_intervals]\nwork = 30\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+	updated, err = SyncHeartbeatTimers()
+	if err != nil || len(updated) != 1 {
+		t.Fatalf("sync after change = %v, %v; want [ops]", updated, err)
+	}
+	if data, _ := os.ReadFile(store); !strings.HasPrefix(string(data), "*/30 * * * *") {
+		t.Errorf("crontab = %q, want every 30 minutes", data)
+	}
+}
+
+func TestReconcileConductor_OnlyRerendersOnDrift(t *testing.T) {
+	writeMacroConfig(t, "[conductor]\nheartbeat_backend = \"cron\"\n")
+	store := fakeCrontab(t)
+	if err := SaveConductorMeta(&ConductorMeta{Name: "ops", Profile: "work", HeartbeatEnabled: true, HeartbeatInterval: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InstallHeartbeatScript("ops", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("cron backend is Linux only")
+	}
+	if err := InstallHeartbeatDaemon("ops", "work", 10, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ReconcileConductor("ops")
+	if err != nil || len(result.Drift) != 0 || result.Updated {
+		t.Fatalf("in sync: ReconcileConductor() = %+v, %v", result, err)
+	}
+
+	meta, _ := LoadConductorMeta("ops")
+	meta.HeartbeatInterval = 20
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+	dir, _ := ConductorNameDir("ops")
+	if err := os.WriteFile(filepath.Join(dir, "heartbeat.sh"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	result, err = HeartbeatDrift("ops")
+	if err != nil || len(result.Drift) != 2 {
+		t.Fatalf("HeartbeatDrift() = %+v, %v; want script and crontab drift", result, err)
+	}
+	if data, _ := os.ReadFile(store); !strings.HasPrefix(string(data), "*/10 * * * *") {
+		t.Errorf("dry run changed the crontab: %q", data)
+	}
+
+	result, err = ReconcileConductor("ops")
+	if err != nil || !result.Updated {
+		t.Fatalf("ReconcileConductor() = %+v, %v", result, err)
+	}
+	if data, _ := os.ReadFile(store); !strings.HasPrefix(string(data), "*/20 * * * *") {
+		t.Errorf("crontab = %q, want every 20 minutes", data)
+	}
+	if result, _ := HeartbeatDrift("ops"); len(result.Drift) != 0 {
+		t.Errorf("drift after reconcile: %v", result.Drift)
+	}
+
+	// Disabling heartbeats removes the installed timer
+	meta.HeartbeatEnabled = false
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := ReconcileConductor("ops"); err != nil || !result.Updated {
+		t.Fatalf("disabled: ReconcileConductor() = %+v, %v", result, err)
+	}
+	if data, _ := os.ReadFile(store); strings.Contains(string(data), heartbeatCronMarker("ops")) {
+		t.Errorf("crontab still has the heartbeat: %q", data)
+	}
+}
+
+func TestConductorHeartbeatTiming(t *testing.T) {
+	writeMacroConfig(t, "[conductor]\nheartbeat_backend = \"cron\"\n")
+	meta := &ConductorMeta{Name: "ops", Profile: "work", HeartbeatEnabled: true, HeartbeatInterval: 15}
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 9, 10, 7, 0, 0, time.Local)
+	cron := HeartbeatDaemonState{Backend: HeartbeatBackendCron, Installed: true, Active: true}
+
+	timing := ConductorHeartbeatTiming(meta, cron, now)
+	if !timing.LastRun.IsZero() || !timing.NextRun.Equal(now.Add(8*time.Minute)) {
+		t.Errorf("cron timing = %+v, want no last run and next at 10:15", timing)
+	}
+
+	last := now.Add(-3 * time.Minute)
+	if err := WriteHeartbeatResult(HeartbeatResult{Conductor: "ops", Result: HeartbeatSkipped, Time: last}); err != nil {
+		t.Fatal(err)
+	}
+	launchd := HeartbeatDaemonState{Backend: HeartbeatBackendLaunchd, Installed: true, Active: true}
+	timing = ConductorHeartbeatTiming(meta, launchd, now)
+	if !timing.LastRun.Equal(last) || timing.LastResult != HeartbeatSkipped || !timing.NextRun.Equal(last.Add(15*time.Minute)) {
+		t.Errorf("interval timing = %+v", timing)
+	}
+
+	launchd.Active = false
+	if timing := ConductorHeartbeatTiming(meta, launchd, now); !timing.NextRun.IsZero() {
+		t.Errorf("inactive timer has next run %v", timing.NextRun)
+	}
+}
+
+func TestGetDedupeWindow(t *testing.T) {
+	tests := []struct {
+		window   int
+		expected time.Duration
+	}{
+		{0, 10 * time.Minute},  // default
+		{-1, 0},                // disabled
+		{30, 30 * time.Minute}, // custom
+	}
+
+	for _, tt := range tests {
+		settings := &ConductorSettings{DedupeWindow: tt.window}
+		if got := settings.GetDedupeWindow(); got != tt.expected {
+			t.Errorf("GetDedupeWindow() with %d = %v, want %v", tt.window, got, tt.expected)
+		}
+	}
+}
+
+func TestConductorTaskDedupeKey(t *testing.T) {
+	a := ConductorTaskDedupeKey("PR #12 failed CI\n")
+	b := ConductorTaskDedupeKey("  PR #12   failed CI")
+	if a != b {
+		t.Errorf("whitespace changed the key: %q vs %q", a, b)
+	}
+	if a == ConductorTaskDedupeKey("PR #13 failed CI") {
+		t.Error("different messages share a key")
+	}
+}
+
 func TestGetProfiles(t *testing.T) {
 	// Empty profiles should return default
 	settings := &ConductorSettings{}
@@ -549,22 +732,92 @@ func TestBridgeTemplate_HeartbeatSelectsOnePerProfile(t *testing.T) {
 	}
 }
 
+// runBridgeSendToConductor runs the bridge's send_to_conductor with run_cli
+// stubbed out, passing kwargs, and returns the CLI arguments it would have
+// passed
+func runBridgeSendToConductor(t *testing.T, kwargs string) []string {
+	t.Helper()
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found in PATH")
+	}
+	start := strings.Index(conductorBridgePy, "def send_to_conductor(")
+	if start < 0 {
+		t.Fatal("bridge template has no send_to_conductor")
+	}
+	fn := conductorBridgePy[start:]
+	if end := strings.Index(fn, "\n\n\ndef "); end >= 0 {
+		fn = fn[:end]
+	}
+
+	script := `import json, logging, types
+log = logging.getLogger("bridge")
+calls = []
+def run_cli(*args, profile=None, timeout=None):
+    calls.append(list(args))
+    return types.SimpleNamespace(returncode=0, stdout="", stderr="")
+` + fn + `
+assert send_to_conductor("conductor-ops", "hello", profile="work"` + kwargs + `)
+print(json.dumps(calls[0]))
+`
+	out, err := exec.Command(python, "-c", script).CombinedOutput()
+	if err != nil {
+		t.Fatalf("send_to_conductor failed: %v\n%s", err, out)
+	}
+	var args []string
+	if err := json.Unmarshal(out, &args); err != nil {
+		t.Fatalf("unexpected output %q: %v", out, err)
+	}
+	return args
+}
+
 func TestBridgeTemplate_SendToConductorUsesNoWait(t *testing.T) {
-	template := conductorBridgePy
-	newPattern := `"session", "send", session, message, "--no-wait", profile=profile, timeout=30`
-	oldPattern := `"session", "send", session, message, profile=profile, timeout=120`
+	args := runBridgeSendToConductor(t, "")
+	want := []string{"session", "send", "conductor-ops", "hello", "--no-wait"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("send args = %q, want %q", args, want)
+	}
+}
 
-	if !strings.Contains(template, newPattern) {
-		t.Fatalf("template should use --no-wait send pattern: %q", newPattern)
+func TestBridgeTemplate_InteractiveSendSkipsDedupe(t *testing.T) {
+	args := runBridgeSendToConductor(t, ", interactive=True")
+	want := []string{"session", "send", "conductor-ops", "hello", "--no-wait", "--no-dedupe", "--force"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("send args = %q, want %q", args, want)
 	}
-	if strings.Contains(template, oldPattern) {
-		t.Fatalf("template should not contain blocking send pattern: %q", oldPattern)
+}
+
+func TestConductorHeartbeatScript_ExecsHeartbeatRun(t *testing.T) {
+	script := renderHeartbeatScript("ops", "work")
+	if !strings.Contains(script, `exec agent-deck conductor heartbeat-run ops "$@"`) {
+		t.Fatalf("heartbeat.sh should hand off to heartbeat-run:\n%s", script)
 	}
 }
 
-func TestConductorHeartbeatScript_StatusParsingHandlesWhitespace(t *testing.T) {
-	if !strings.Contains(conductorHeartbeatScript, `"status"[[:space:]]*:[[:space:]]*"`) {
-		t.Fatal("heartbeat status parser should tolerate JSON whitespace around ':'")
+func TestBuildHeartbeatMessage(t *testing.T) {
+	meta := &ConductorMeta{Name: "ops", Profile: "work", Timezone: "Europe/Berlin", Language: "German"}
+	instances := []*Instance{
+		{Title: ConductorSessionTitle("ops"), Status: StatusIdle},
+		{Title: "api", Status: StatusWaiting},
+		{Title: "web", Status: StatusRunning},
+		{Title: "db", Status: StatusError},
+	}
+	now := time.Date(2026, 3, 9, 8, 30, 0, 0, time.UTC)
+	msg := BuildHeartbeatMessage(meta, instances, now)
+
+	if ConductorTaskKind(msg) != statedb.ConductorTaskHeartbeat {
+		t.Errorf("message should classify as a heartbeat: %q", msg)
+	}
+	for _, want := range []string{
+		"[HEARTBEAT] [ops] Status: 1 waiting, 1 running, 1 idle, 1 error.",
+		"09:30",
+		"Reply in German.",
+		"Waiting sessions: api",
+		"Error sessions: db",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q: %q", want, msg)
+		}
 	}
 }
 
@@ -1355,3 +1608,66 @@ func TestMigrateConductorPolicySplit_PreservesSymlinkedClaudeMD(t *testing.T) {
 		t.Fatalf("symlink destination changed to %q, want %q", linkDest, customPath)
 	}
 }
+
+func TestConductorNameFromTitleAndTaskKind(t *testing.T) {
+	if name, ok := ConductorNameFromTitle(ConductorSessionTitle("ops")); !ok || name != "ops" {
+		t.Errorf("ConductorNameFromTitle = %q/%v, want ops/true", name, ok)
+	}
+	for _, title := range []string{"conductor-", "my-project"} {
+		if _, ok := ConductorNameFromTitle(title); ok {
+			t.Errorf("%q should not be a conductor title", title)
+		}
+	}
+	if got := ConductorTaskKind("[HEARTBEAT] [ops] Status: 1 waiting"); got != statedb.ConductorTaskHeartbeat {
+		t.Errorf("heartbeat kind = %q", got)
+	}
+	if got := ConductorTaskKind("check the deploy"); got != statedb.ConductorTaskMessage {
+		t.Errorf("message kind = %q", got)
+	}
+}
+
+func TestNamedDeckUnits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AGENT_DECK_HOME", "")
+	t.Setenv("AGENT_DECK_NAME", "v2")
+
+	names := map[string]string{
+		"bridge label":     LaunchdPlistName(),
+		"heartbeat label":  HeartbeatPlistLabel("ops"),
+		"bridge unit":      systemdBridgeServiceName(),
+		"heartbeat unit":   SystemdHeartbeatServiceName("ops"),
+		"heartbeat timer":  SystemdHeartbeatTimerName("ops"),
+		"restore unit":     systemdRestoreServiceName(),
+		"restore label":    restoreLaunchdLabel(),
+		"web socket unit":  SystemdWebSocketName("work"),
+		"web service unit": SystemdWebServiceName("work"),
+	}
+	for what, name := range names {
+		if !strings.Contains(name, "agent-deck-v2-") && !strings.HasPrefix(name, "com.agentdeck.v2.") {
+			t.Errorf("%s %q is not qualified by the deck name", what, name)
+		}
+	}
+
+	svc, err := GenerateSystemdHeartbeatService("ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Environment=AGENT_DECK_NAME=v2\n", "Environment=AGENT_DECK_HOME="} {
+		if !strings.Contains(svc, want) {
+			t.Errorf("heartbeat service should contain %q:\n%s", want, svc)
+		}
+	}
+	plist := withDeckEnv(conductorHeartbeatPlistTemplate)
+	if !strings.Contains(plist, "<key>AGENT_DECK_NAME</key>\n        <string>v2</string>") {
+		t.Errorf("heartbeat plist should set AGENT_DECK_NAME:\n%s", plist)
+	}
+
+	// The default deck keeps its unit names and templates
+	t.Setenv("AGENT_DECK_NAME", "")
+	if got := systemdBridgeServiceName(); got != "agent-deck-conductor-bridge.service" {
+		t.Errorf("default bridge unit = %q", got)
+	}
+	if withDeckEnv(systemdHeartbeatServiceTemplate) != systemdHeartbeatServiceTemplate {
+		t.Error("default deck must not change unit templates")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 )
 
 const (
@@ -31,13 +33,10 @@ type Config struct {
 	Version int `json:"version"`
 }
 
-// GetAgentDeckDir returns the base agent-deck directory (~/.agent-deck)
+// GetAgentDeckDir returns the base agent-deck directory (~/.agent-deck, or
+// the deck's own directory, see deck.Home)
 func GetAgentDeckDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-	return filepath.Join(homeDir, ".agent-deck"), nil
+	return deck.Home()
 }
 
 // GetConfigPath returns the path to the global config file
@@ -0,0 +1,244 @@
+package session
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// CostReportRow is one session's usage on one day. Tokens and cost come from
+// the session's transcript (Claude and Codex), busy time from status
+// tracking, and tasks from the conductor task log and dispatch queues.
+type CostReportRow struct {
+	Day       string `json:"day"`
+	Profile   string `json:"profile"`
+	SessionID string `json:"session_id"`
+	// Title, Tool and Group are empty for sessions deleted since
+	Title            string `json:"title"`
+	Tool             string `json:"tool"`
+	Group            string `json:"group"`
+	InputTokens      int    `json:"input_tokens"`
+	OutputTokens     int    `json:"output_tokens"`
+	CacheReadTokens  int    `json:"cache_read_tokens"`
+	CacheWriteTokens int    `json:"cache_write_tokens"`
+	// BusyTime is how long the session was seen running
+	BusyTime time.Duration `json:"busy_ns"`
+	// TasksCompleted counts conductor messages handled (conductor sessions)
+	// and dispatched tasks finished (workers)
+	TasksCompleted int `json:"tasks_completed"`
+	// Cost is the estimated USD cost, at the rates of the analytics panel
+	Cost float64 `json:"cost_usd"`
+	// Conductor is the conductor the session works for ("" for none)
+	Conductor string `json:"conductor"`
+}
+
+// TotalTokens returns the sum of all token types
+func (r *CostReportRow) TotalTokens() int {
+	return r.InputTokens + r.OutputTokens + r.CacheReadTokens + r.CacheWriteTokens
+}
+
+// costReportColumns is the CSV header of WriteCostReportCSV
+var costReportColumns = []string{
+	"day", "profile", "session_id", "title", "tool", "group",
+	"input_tokens", "output_tokens", "cache_read_tokens", "cache_write_tokens", "total_tokens",
+	"busy_seconds", "tasks_completed", "cost_usd", "conductor",
+}
+
+// CostReport returns per-session, per-day usage of profile for days in
+// [fromDay, toDay] (inclusive DayKeys), ordered by day then title. Days on
+// which a session did nothing are left out.
+func CostReport(profile, fromDay, toDay string) ([]CostReportRow, error) {
+	from, err := time.ParseInLocation("2006-01-02", fromDay, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from day %q: %w", fromDay, err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", toDay, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to day %q: %w", toDay, err)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("to day %s is before from day %s", toDay, fromDay)
+	}
+	end := to.AddDate(0, 0, 1)
+
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+	instances, _, err := storage.LoadWithGroups()
+	if err != nil {
+		return nil, err
+	}
+	profile = normalizeConductorProfile(profile)
+	db := storage.GetDB()
+
+	rows := make(map[[2]string]*CostReportRow)
+	byID := make(map[string]*Instance, len(instances))
+	conductors := make(map[string]string)
+	row := func(day, id string) *CostReportRow {
+		key := [2]string{day, id}
+		if r := rows[key]; r != nil {
+			return r
+		}
+		r := &CostReportRow{Day: day, Profile: profile, SessionID: id}
+		inst := byID[id]
+		if inst != nil {
+			r.Title, r.Tool, r.Group = inst.Title, inst.Tool, inst.GroupPath
+		}
+		conductor, ok := conductors[id]
+		if !ok {
+			conductor = conductorOf(db, id, inst)
+			conductors[id] = conductor
+		}
+		r.Conductor = conductor
+		rows[key] = r
+		return r
+	}
+
+	for _, inst := range instances {
+		byID[inst.ID] = inst
+	}
+	for _, inst := range instances {
+		for day, usage := range instanceDailyUsage(inst, from, end) {
+			r := row(day, inst.ID)
+			r.InputTokens += usage.InputTokens
+			r.OutputTokens += usage.OutputTokens
+			r.CacheReadTokens += usage.CacheReadTokens
+			r.CacheWriteTokens += usage.CacheWriteTokens
+			r.Cost += usage.EstimatedCost
+		}
+	}
+
+	if db != nil {
+		busy, err := db.ReadBusyDaily(fromDay, toDay)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range busy {
+			row(b.Day, b.ID).BusyTime += b.Busy
+		}
+		done, err := db.ReadTasksDoneDaily(fromDay, toDay)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range done {
+			row(d.Day, d.ID).TasksCompleted += d.Done
+		}
+	}
+
+	metas, err := ListConductors()
+	if err == nil {
+		byTitle := make(map[string]*Instance, len(instances))
+		for _, inst := range instances {
+			byTitle[inst.Title] = inst
+		}
+		for _, meta := range metas {
+			if normalizeConductorProfile(meta.Profile) != profile {
+				continue
+			}
+			tasks, err := ListTasks(meta.Name, TaskDone)
+			if err != nil {
+				continue
+			}
+			for _, task := range tasks {
+				if task.State != TaskDone || task.CompletedAt.Before(from) || !task.CompletedAt.Before(end) {
+					continue
+				}
+				worker := byID[task.Worker]
+				if worker == nil {
+					worker = byTitle[task.Worker]
+				}
+				if worker != nil {
+					row(statedb.DayKey(task.CompletedAt), worker.ID).TasksCompleted++
+				}
+			}
+		}
+	}
+
+	result := make([]CostReportRow, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := &result[i], &result[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.SessionID < b.SessionID
+	})
+	return result, nil
+}
+
+// instanceDailyUsage reads a session's token usage and cost in [from, to)
+// per day from its transcript (Claude and Codex sessions; nil for other tools)
+func instanceDailyUsage(inst *Instance, from, to time.Time) map[string]*SessionAnalytics {
+	if inst.Tool == "codex" {
+		if inst.CodexSessionID == "" {
+			return nil
+		}
+		path := findCodexRollout(inst.CodexSessionID)
+		if path == "" {
+			return nil
+		}
+		days, err := codexDailyUsage(path, from, to)
+		if err != nil {
+			return nil
+		}
+		result := make(map[string]*SessionAnalytics, len(days))
+		for day, usage := range days {
+			uncached := max(usage.InputTokens-usage.CachedInputTokens, 0)
+			result[day] = &SessionAnalytics{
+				InputTokens:     uncached,
+				OutputTokens:    usage.OutputTokens,
+				CacheReadTokens: usage.CachedInputTokens,
+				EstimatedCost:   usage.EstimatedCost,
+			}
+		}
+		return result
+	}
+	path := inst.GetJSONLPath()
+	if path == "" {
+		return nil
+	}
+	days, err := dailyUsage(path, from, to)
+	if err != nil {
+		return nil
+	}
+	for _, usage := range days {
+		usage.EstimatedCost = usage.CalculateCost("default")
+	}
+	return days
+}
+
+// WriteCostReportCSV writes rows as CSV with a header line. Busy time is in
+// whole seconds and cost in USD with four decimals.
+func WriteCostReportCSV(w io.Writer, rows []CostReportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(costReportColumns); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write([]string{
+			r.Day, r.Profile, r.SessionID, r.Title, r.Tool, r.Group,
+			strconv.Itoa(r.InputTokens), strconv.Itoa(r.OutputTokens),
+			strconv.Itoa(r.CacheReadTokens), strconv.Itoa(r.CacheWriteTokens), strconv.Itoa(r.TotalTokens()),
+			strconv.FormatInt(int64(r.BusyTime/time.Second), 10),
+			strconv.Itoa(r.TasksCompleted),
+			strconv.FormatFloat(r.Cost, 'f', 4, 64),
+			r.Conductor,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
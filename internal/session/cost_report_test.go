@@ -0,0 +1,84 @@
+package session
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestDailyUsageSplitsByDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	content := `{"type":"assistant","timestamp":"2026-03-09T10:00:00Z","message":{"usage":{"input_tokens":100,"output_tokens":10}}}
+{"type":"user","timestamp":"2026-03-09T10:01:00Z","message":{"usage":{"input_tokens":999}}}
+{"type":"assistant","timestamp":"2026-03-10T10:00:00Z","message":{"usage":{"input_tokens":200,"output_tokens":20,"cache_read_input_tokens":5}}}
+{"type":"assistant","timestamp":"2026-03-12T10:00:00Z","message":{"usage":{"input_tokens":400}}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	days, err := dailyUsage(path, from, from.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("dailyUsage: %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("days = %v, want 2 (the 12th is out of range)", days)
+	}
+	first := days[statedb.DayKey(time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC))]
+	if first == nil || first.InputTokens != 100 || first.OutputTokens != 10 {
+		t.Errorf("first day = %+v", first)
+	}
+	second := days[statedb.DayKey(time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC))]
+	if second == nil || second.TotalTokens() != 225 {
+		t.Errorf("second day = %+v", second)
+	}
+}
+
+func TestCodexDailyUsageUsesDeltas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rollout.jsonl")
+	if err := os.WriteFile(path, []byte(codexTestRollout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	days, err := codexDailyUsage(path, from, from.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatalf("codexDailyUsage: %v", err)
+	}
+	second := days[statedb.DayKey(time.Date(2026, 3, 10, 8, 5, 0, 0, time.UTC))]
+	if second == nil || second.InputTokens != 2000000 || second.CachedInputTokens != 1000000 || second.OutputTokens != 100000 {
+		t.Fatalf("second day = %+v, want the increase over the first day's totals", second)
+	}
+	if second.EstimatedCost <= 0 || second.Model != "gpt-5-codex" {
+		t.Errorf("second day cost/model = %v/%q", second.EstimatedCost, second.Model)
+	}
+}
+
+func TestWriteCostReportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	rows := []CostReportRow{{
+		Day: "2026-03-09", Profile: "default", SessionID: "id-1", Title: "api, v2", Tool: "claude",
+		InputTokens: 100, OutputTokens: 10, CacheReadTokens: 5,
+		BusyTime: 90*time.Second + 500*time.Millisecond, TasksCompleted: 2, Cost: 0.123456,
+	}}
+	if err := WriteCostReportCSV(&buf, rows); err != nil {
+		t.Fatalf("WriteCostReportCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 2 || len(records[0]) != len(costReportColumns) {
+		t.Fatalf("records = %v", records)
+	}
+	want := []string{"2026-03-09", "default", "id-1", "api, v2", "claude", "", "100", "10", "5", "0", "115", "90", "2", "0.1235"}
+	for n, field := range want {
+		if records[1][n] != field {
+			t.Errorf("%s = %q, want %q", costReportColumns[n], records[1][n], field)
+		}
+	}
+}
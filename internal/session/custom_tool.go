@@ -0,0 +1,240 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+// Working directory behaviors of a custom tool (ToolDef.WorkingDir). Any
+// other value is a directory: absolute, ~ or $VAR, or relative to the project.
+const (
+	ToolWorkDirProject = "project"  // the session's project path (default)
+	ToolWorkDirGitRoot = "git_root" // the root of the project's git repository
+	ToolWorkDirHome    = "home"     // the user's home directory
+)
+
+// builtinToolNames are the tools agent-deck knows without config. A
+// [tools.<name>] entry for one of them only tunes it (patterns, env, macros)
+// and is not a custom tool.
+var builtinToolNames = map[string]bool{
+	"claude": true, "gemini": true, "opencode": true,
+	"codex": true, "ollama": true, "shell": true, "cursor": true, "aider": true,
+}
+
+// toolPlaceholderRegex finds {name} placeholders in command templates; a
+// match starting with "$" is a shell ${var} expansion and left alone
+var toolPlaceholderRegex = regexp.MustCompile(`\$?\{([a-z_]+)\}`)
+
+// Placeholders allowed in command and resume_command
+var (
+	commandPlaceholders = map[string]bool{"path": true, "title": true, "id": true}
+	resumePlaceholders  = map[string]bool{"path": true, "title": true, "id": true, "session_id": true, "command": true}
+)
+
+// IsBuiltinTool reports whether tool is one agent-deck supports without a
+// [tools] entry in config.toml
+func IsBuiltinTool(tool string) bool {
+	return builtinToolNames[tool]
+}
+
+// validateToolDef checks a [tools.<name>] entry: templates only use known
+// placeholders, working_dir is usable, and every pattern compiles
+func validateToolDef(def *ToolDef) error {
+	if err := checkToolTemplate("command", def.Command, commandPlaceholders); err != nil {
+		return err
+	}
+	if def.ResumeCommand != "" {
+		if err := checkToolTemplate("resume_command", def.ResumeCommand, resumePlaceholders); err != nil {
+			return err
+		}
+		if def.SessionIDEnv == "" {
+			return errors.New("resume_command needs session_id_env to know which session to resume")
+		}
+		if !strings.Contains(def.ResumeCommand, "{session_id}") {
+			return errors.New("resume_command must contain {session_id}")
+		}
+	}
+	switch def.WorkingDir {
+	case "", ToolWorkDirProject, ToolWorkDirGitRoot, ToolWorkDirHome:
+	default:
+		if strings.ContainsAny(def.WorkingDir, "\n\x00") {
+			return fmt.Errorf("working_dir %q is not a valid path", def.WorkingDir)
+		}
+	}
+	for _, field := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"busy_patterns", def.BusyPatterns},
+		{"prompt_patterns", def.PromptPatterns},
+		{"busy_patterns_extra", def.BusyPatternsExtra},
+		{"prompt_patterns_extra", def.PromptPatternsExtra},
+	} {
+		for n, pattern := range field.patterns {
+			if err := validatePattern(pattern); err != nil {
+				return fmt.Errorf("%s[%d]: %w", field.name, n, err)
+			}
+		}
+	}
+	if err := validateBannerEnd(def.BannerEnd); err != nil {
+		return err
+	}
+	if err := def.Classifier.validate(); err != nil {
+		return err
+	}
+	for n, pattern := range def.DetectPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("detect_patterns[%d]: invalid regex %q: %w", n, pattern, err)
+		}
+	}
+	return nil
+}
+
+// validateBannerEnd checks that a "re:" banner_end compiles
+func validateBannerEnd(bannerEnd string) error {
+	if expr, ok := strings.CutPrefix(bannerEnd, "re:"); ok {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("banner_end: invalid regex %q: %w", bannerEnd, err)
+		}
+	}
+	return nil
+}
+
+// checkToolTemplate rejects placeholders a template field does not support,
+// which would otherwise reach the shell verbatim
+func checkToolTemplate(field, template string, allowed map[string]bool) error {
+	for _, m := range toolPlaceholderRegex.FindAllStringSubmatch(template, -1) {
+		if !strings.HasPrefix(m[0], "$") && !allowed[m[1]] {
+			return fmt.Errorf("%s: unknown placeholder {%s}", field, m[1])
+		}
+	}
+	return nil
+}
+
+// validateToolDefs drops the invalid entries of config.Tools and returns
+// their errors by tool name, so one broken tool does not take the rest of
+// config.toml down with it
+func validateToolDefs(config *UserConfig) map[string]error {
+	var errs map[string]error
+	for name, def := range config.Tools {
+		if err := validateToolDef(&def); err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[name] = fmt.Errorf("config.toml [tools.%s]: %w", name, err)
+			delete(config.Tools, name)
+			sessionLog.Warn("tool_def_invalid", slog.String("tool", name), slog.String("error", err.Error()))
+		}
+	}
+	return errs
+}
+
+// ToolDefError returns why the [tools.<tool>] entry in config.toml was
+// rejected, or nil when it loaded (or does not exist)
+func ToolDefError(tool string) error {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return nil
+	}
+	return config.toolErrors[tool]
+}
+
+// ToolDefErrors returns every rejected [tools] entry, sorted by tool name
+func ToolDefErrors() []error {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil || len(config.toolErrors) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(config.toolErrors))
+	for name := range config.toolErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, config.toolErrors[name])
+	}
+	return errs
+}
+
+// expandToolTemplate fills in a command template's placeholders. {path} and
+// {title} are shell-quoted; {session_id} and {command} are inserted as given,
+// since they may be shell expressions themselves.
+func (i *Instance) expandToolTemplate(template string, extra map[string]string) string {
+	values := map[string]string{
+		"path":  shellQuote(i.ProjectPath),
+		"title": shellQuote(i.Title),
+		"id":    i.ID,
+	}
+	for k, v := range extra {
+		values[k] = v
+	}
+	return toolPlaceholderRegex.ReplaceAllStringFunc(template, func(m string) string {
+		v, ok := values[strings.Trim(m, "{}")]
+		if !ok {
+			return m
+		}
+		return v
+	})
+}
+
+// shellQuote single-quotes s for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
+// genericResumeCommand returns the command that resumes sessionID of a
+// custom tool: its resume_command, or the command followed by resume_flag.
+// sessionID may be a shell expression such as "$session_id".
+func (i *Instance) genericResumeCommand(def *ToolDef, baseCommand, sessionID string) string {
+	dangerousFlag := ""
+	if def.DangerousMode && def.DangerousFlag != "" {
+		dangerousFlag = " " + def.DangerousFlag
+	}
+	if def.ResumeCommand == "" {
+		return fmt.Sprintf("%s %s %s%s", baseCommand, def.ResumeFlag, sessionID, dangerousFlag)
+	}
+	return i.expandToolTemplate(def.ResumeCommand, map[string]string{
+		"command":    baseCommand,
+		"session_id": sessionID,
+	}) + dangerousFlag
+}
+
+// toolWorkDir returns the directory a session of tool starts in: the
+// project path, unless the tool's working_dir says otherwise
+func toolWorkDir(tool, projectPath string) string {
+	def := GetToolDef(tool)
+	if def == nil {
+		return projectPath
+	}
+	switch def.WorkingDir {
+	case "", ToolWorkDirProject:
+		return projectPath
+	case ToolWorkDirGitRoot:
+		if root, err := git.GetRepoRoot(projectPath); err == nil {
+			return root
+		}
+		return projectPath
+	case ToolWorkDirHome:
+		if home, err := os.UserHomeDir(); err == nil {
+			return home
+		}
+		return projectPath
+	}
+	return resolvePath(def.WorkingDir, projectPath)
+}
+
+// applyToolWorkDir points a not yet started tmux session at the tool's
+// working directory
+func (i *Instance) applyToolWorkDir() {
+	if i.tmuxSession != nil && !IsBuiltinTool(i.Tool) {
+		i.tmuxSession.WorkDir = toolWorkDir(i.Tool, i.ProjectPath)
+	}
+}
@@ -0,0 +1,114 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCustomToolFromConfig(t *testing.T) {
+	writeMacroConfig(t, `
+[tools.mytool]
+command = "mytool --project {path}"
+busy_patterns = ["re:thinking\\.{3}", "esc to stop"]
+prompt_patterns = ["re:(?m)^mytool> $"]
+session_id_env = "MYTOOL_SESSION"
+resume_command = "{command} chat --continue {session_id}"
+working_dir = "home"
+`)
+	if err := ToolDefError("mytool"); err != nil {
+		t.Fatalf("valid tool rejected: %v", err)
+	}
+
+	inst := NewInstanceWithTool("x", "/tmp/my project", "mytool")
+	if inst.Command != "mytool --project {path}" {
+		t.Errorf("Command = %q, want the configured template", inst.Command)
+	}
+	if got, want := inst.buildStartCommand(), "mytool --project '/tmp/my project'"; !strings.HasSuffix(got, want) {
+		t.Errorf("start command = %q, want suffix %q", got, want)
+	}
+	def := GetToolDef("mytool")
+	if got, want := inst.genericResumeCommand(def, "mytool", "abc"), "mytool chat --continue abc"; got != want {
+		t.Errorf("resume command = %q, want %q", got, want)
+	}
+
+	raw := MergeToolPatterns("mytool")
+	if raw == nil || len(raw.BusyPatterns) != 2 || len(raw.PromptPatterns) != 1 {
+		t.Fatalf("patterns not loaded: %+v", raw)
+	}
+
+	home, _ := os.UserHomeDir()
+	inst.applyToolWorkDir()
+	if got := inst.tmuxSession.WorkDir; got != home {
+		t.Errorf("work dir = %q, want home %q", got, home)
+	}
+}
+
+func TestCustomToolWorkDir(t *testing.T) {
+	writeMacroConfig(t, `
+[tools.rel]
+command = "rel"
+working_dir = "sub/dir"
+
+[tools.plain]
+command = "plain"
+`)
+	if got, want := toolWorkDir("rel", "/proj"), filepath.Join("/proj", "sub", "dir"); got != want {
+		t.Errorf("relative working_dir = %q, want %q", got, want)
+	}
+	if got := toolWorkDir("plain", "/proj"); got != "/proj" {
+		t.Errorf("default working_dir = %q, want the project", got)
+	}
+}
+
+func TestCustomToolValidation(t *testing.T) {
+	writeMacroConfig(t, `
+[tools.good]
+command = "good ${HOME}"
+
+[tools.badregex]
+command = "bad"
+busy_patterns = ["ok", "re:([unclosed"]
+
+[tools.badplaceholder]
+command = "tool {project}"
+
+[tools.noenv]
+command = "tool"
+resume_command = "tool --resume {session_id}"
+
+[tools.baddetect]
+command = "tool"
+detect_patterns = ["(("]
+
+[tools.claude]
+busy_patterns_extra = ["re:*bad"]
+`)
+	if GetToolDef("good") == nil {
+		t.Fatal("valid tool dropped")
+	}
+	cases := map[string]string{
+		"badregex":       "busy_patterns[1]",
+		"badplaceholder": "unknown placeholder {project}",
+		"noenv":          "session_id_env",
+		"baddetect":      "detect_patterns[0]",
+		"claude":         "busy_patterns_extra[0]",
+	}
+	for name, want := range cases {
+		err := ToolDefError(name)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if !strings.Contains(err.Error(), want) || !strings.Contains(err.Error(), "[tools."+name+"]") {
+			t.Errorf("%s: error %q should name the tool and mention %q", name, err, want)
+		}
+		if GetToolDef(name) != nil {
+			t.Errorf("%s: invalid tool should be dropped", name)
+		}
+	}
+	if got := len(ToolDefErrors()); got != len(cases) {
+		t.Errorf("ToolDefErrors() has %d errors, want %d", got, len(cases))
+	}
+}
@@ -0,0 +1,60 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// receiptHistoryDays is how long delivery receipts are kept
+const receiptHistoryDays = 7
+
+// NewDeliveryReceipt records a queued prompt for inst and returns its
+// receipt ID. Callers mark it delivered or failed once the send finishes;
+// status polling advances it to acknowledged and completed.
+func NewDeliveryReceipt(db *statedb.StateDB, inst *Instance, at time.Time) (string, error) {
+	if db == nil || inst == nil {
+		return "", nil
+	}
+	id := "rcpt-" + randomString(16)
+	if err := db.CreateReceipt(id, inst.ID, inst.Title, at); err != nil {
+		return "", err
+	}
+	return id, db.PruneReceipts(at.AddDate(0, 0, -receiptHistoryDays))
+}
+
+// RefreshReceipt returns a receipt after checking its session's current
+// status, so it is up to date even when no TUI is polling. A receipt whose
+// session is gone or in error fails.
+func RefreshReceipt(db *statedb.StateDB, instances []*Instance, id string) (*statedb.DeliveryReceipt, error) {
+	receipt, err := db.GetReceipt(id)
+	if err != nil || receipt.Finished() || receipt.State == statedb.ReceiptQueued {
+		return receipt, err
+	}
+
+	now := time.Now()
+	var inst *Instance
+	for _, candidate := range instances {
+		if candidate.ID == receipt.SessionID {
+			inst = candidate
+			break
+		}
+	}
+	switch {
+	case inst == nil:
+		err = db.FailReceipt(id, fmt.Errorf("session %q no longer exists", receipt.Title), now)
+	default:
+		_ = inst.UpdateStatus()
+		status := inst.GetStatusThreadSafe()
+		if status == StatusError {
+			err = db.FailReceipt(id, fmt.Errorf("session %q is in error state", inst.Title), now)
+		} else {
+			err = db.RecordStatus(inst.ID, string(status), now)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return db.GetReceipt(id)
+}
@@ -0,0 +1,42 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestRefreshReceipt_FailsWhenSessionIsGone(t *testing.T) {
+	db := newTestStorage(t).GetDB()
+	inst := &Instance{ID: "worker-1", Title: "worker"}
+
+	id, err := NewDeliveryReceipt(db, inst, time.Now())
+	if err != nil || !strings.HasPrefix(id, "rcpt-") {
+		t.Fatalf("NewDeliveryReceipt = %q, %v", id, err)
+	}
+
+	// Queued receipts are left alone: the send is still in progress
+	receipt, err := RefreshReceipt(db, nil, id)
+	if err != nil || receipt.State != statedb.ReceiptQueued {
+		t.Fatalf("queued receipt = %+v, %v", receipt, err)
+	}
+
+	if err := db.MarkReceiptDelivered(id, time.Now()); err != nil {
+		t.Fatalf("MarkReceiptDelivered: %v", err)
+	}
+	receipt, err = RefreshReceipt(db, nil, id)
+	if err != nil {
+		t.Fatalf("RefreshReceipt: %v", err)
+	}
+	if receipt.State != statedb.ReceiptFailed || !strings.Contains(receipt.Error, "no longer exists") {
+		t.Errorf("receipt for a removed session = %+v, want failed", receipt)
+	}
+}
+
+func TestNewDeliveryReceipt_NoDB(t *testing.T) {
+	if id, err := NewDeliveryReceipt(nil, &Instance{ID: "x"}, time.Now()); id != "" || err != nil {
+		t.Errorf("NewDeliveryReceipt(nil db) = %q, %v; want no receipt", id, err)
+	}
+}
@@ -0,0 +1,184 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// ErrDeliverySignature means a delivery record's text, time or session does
+// not match its signature
+var ErrDeliverySignature = errors.New("delivery signature does not verify")
+
+// AuditSettings configures the session audit log ([audit])
+type AuditSettings struct {
+	// SignDeliveries records every heartbeat and prompt delivered to a
+	// conductor in the audit log, signed with the deck key, so the log proves
+	// what text reached the conductor and when. Default: false
+	SignDeliveries bool `toml:"sign_deliveries"`
+
+	// KeyPath is the deck's ed25519 signing key, created on first use.
+	// Default: ~/.agent-deck/deck.key
+	KeyPath string `toml:"key_path"`
+}
+
+// GetAuditSettings returns [audit] from config.toml
+func GetAuditSettings() AuditSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return AuditSettings{}
+	}
+	return config.Audit
+}
+
+// GetKeyPath returns the signing key path, defaulting to ~/.agent-deck/deck.key
+func (a AuditSettings) GetKeyPath() (string, error) {
+	if a.KeyPath != "" {
+		return ExpandPath(a.KeyPath), nil
+	}
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "deck.key"), nil
+}
+
+// LoadDeckKey reads the deck signing key (a base64 ed25519 seed), creating
+// it when create is set and it does not exist yet
+func LoadDeckKey(path string, create bool) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid deck key %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return nil, fmt.Errorf("read deck key: %w", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(key.Seed()) + "\n"
+	// O_EXCL: a concurrent first delivery keeps the key written first
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if os.IsExist(err) {
+		return LoadDeckKey(path, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("write deck key: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(encoded); err != nil {
+		return nil, fmt.Errorf("write deck key: %w", err)
+	}
+	return key, nil
+}
+
+// DeckKeyID is a short fingerprint of a deck public key
+func DeckKeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SignedDelivery is the detail of an AuditDelivered audit entry
+type SignedDelivery struct {
+	Kind      string `json:"kind"` // heartbeat or message
+	Text      string `json:"text"`
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"` // base64 ed25519 over deliveryPayload
+}
+
+// deliveryPayload is what a delivery signature covers: the time to the
+// nanosecond (as stored in the audit log), the session, and the text hash
+func deliveryPayload(at time.Time, sessionID, title, kind, text string) []byte {
+	sum := sha256.Sum256([]byte(text))
+	return []byte(strings.Join([]string{
+		"agent-deck-delivery/v1",
+		fmt.Sprintf("%d", at.UnixNano()),
+		sessionID,
+		title,
+		kind,
+		hex.EncodeToString(sum[:]),
+	}, "\n"))
+}
+
+// RecordSignedDelivery appends a signed record of text delivered to inst
+func RecordSignedDelivery(db *statedb.StateDB, key ed25519.PrivateKey, inst *Instance, text string, at time.Time) error {
+	kind := ConductorTaskKind(text)
+	delivery := SignedDelivery{
+		Kind:      kind,
+		Text:      text,
+		KeyID:     DeckKeyID(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, deliveryPayload(at, inst.ID, inst.Title, kind, text))),
+	}
+	detail, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	return db.AppendAudit(inst.ID, inst.Title, statedb.AuditDelivered, string(detail), at)
+}
+
+// RecordDelivery signs text delivered to a conductor session into the audit
+// log when [audit] sign_deliveries is set; other sessions are not recorded
+func RecordDelivery(db *statedb.StateDB, inst *Instance, text string, at time.Time) error {
+	if db == nil || inst == nil {
+		return nil
+	}
+	settings := GetAuditSettings()
+	if !settings.SignDeliveries {
+		return nil
+	}
+	if _, ok := ConductorNameFromTitle(inst.Title); !ok {
+		return nil
+	}
+	path, err := settings.GetKeyPath()
+	if err != nil {
+		return err
+	}
+	key, err := LoadDeckKey(path, true)
+	if err != nil {
+		return err
+	}
+	return RecordSignedDelivery(db, key, inst, text, at)
+}
+
+// VerifyDelivery checks a delivered audit entry against the deck public key
+// and returns its record
+func VerifyDelivery(row statedb.AuditRow, pub ed25519.PublicKey) (*SignedDelivery, error) {
+	if row.Action != statedb.AuditDelivered {
+		return nil, fmt.Errorf("audit entry %d is not a delivery", row.Seq)
+	}
+	var delivery SignedDelivery
+	if err := json.Unmarshal([]byte(row.Detail), &delivery); err != nil {
+		return nil, fmt.Errorf("audit entry %d: %w", row.Seq, err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(delivery.Signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return &delivery, fmt.Errorf("%w: entry %d has a malformed signature", ErrDeliverySignature, row.Seq)
+	}
+	if delivery.KeyID != DeckKeyID(pub) {
+		return &delivery, fmt.Errorf("%w: entry %d was signed by key %s", ErrDeliverySignature, row.Seq, delivery.KeyID)
+	}
+	if !ed25519.Verify(pub, deliveryPayload(row.Time, row.SessionID, row.Title, delivery.Kind, delivery.Text), sig) {
+		return &delivery, fmt.Errorf("%w: entry %d", ErrDeliverySignature, row.Seq)
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestRecordDelivery_SignsConductorDeliveries(t *testing.T) {
+	writeMacroConfig(t, "[audit]\nsign_deliveries = true\n")
+	db := newTestStorage(t).GetDB()
+	conductor := &Instance{ID: "c1", Title: "conductor-ops"}
+	worker := &Instance{ID: "w1", Title: "frontend"}
+	at := time.Date(2026, 3, 9, 9, 0, 0, 123456789, time.Local)
+
+	if err := RecordDelivery(db, conductor, "[HEARTBEAT] check sessions", at); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordDelivery(db, worker, "fix the tests", at); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.ListAudit("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].SessionID != "c1" || rows[0].Action != statedb.AuditDelivered {
+		t.Fatalf("audit rows = %+v, want one delivery to the conductor", rows)
+	}
+
+	path, _ := GetAuditSettings().GetKeyPath()
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("deck key %s: %v %v", path, info, err)
+	}
+	key, err := LoadDeckKey(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivery, err := VerifyDelivery(rows[0], key.Public().(ed25519.PublicKey))
+	if err != nil {
+		t.Fatalf("VerifyDelivery() = %v", err)
+	}
+	if delivery.Kind != statedb.ConductorTaskHeartbeat || delivery.Text != "[HEARTBEAT] check sessions" {
+		t.Errorf("delivery = %+v", delivery)
+	}
+}
+
+func TestRecordDelivery_DisabledByDefault(t *testing.T) {
+	writeMacroConfig(t, "")
+	db := newTestStorage(t).GetDB()
+	if err := RecordDelivery(db, &Instance{ID: "c1", Title: "conductor-ops"}, "hi", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := db.ListAudit("", 0); len(rows) != 0 {
+		t.Errorf("recorded %d entries with signing off", len(rows))
+	}
+}
+
+func TestVerifyDelivery_DetectsTampering(t *testing.T) {
+	db := newTestStorage(t).GetDB()
+	key, err := LoadDeckKey(filepath.Join(t.TempDir(), "deck.key"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	at := time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local)
+	if err := RecordSignedDelivery(db, key, &Instance{ID: "c1", Title: "conductor-ops"}, "deploy staging", at); err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := db.ListAudit("", 0)
+	row := rows[0]
+
+	tampered := map[string]func(r statedb.AuditRow) statedb.AuditRow{
+		"text": func(r statedb.AuditRow) statedb.AuditRow {
+			r.Detail = strings.Replace(r.Detail, "deploy staging", "deploy production", 1)
+			return r
+		},
+		"time": func(r statedb.AuditRow) statedb.AuditRow {
+			r.Time = r.Time.Add(time.Minute)
+			return r
+		},
+		"session": func(r statedb.AuditRow) statedb.AuditRow {
+			r.SessionID = "c2"
+			return r
+		},
+	}
+	for name, tamper := range tampered {
+		if _, err := VerifyDelivery(tamper(row), pub); !errors.Is(err, ErrDeliverySignature) {
+			t.Errorf("tampered %s: VerifyDelivery() = %v, want ErrDeliverySignature", name, err)
+		}
+	}
+
+	other, _ := LoadDeckKey(filepath.Join(t.TempDir(), "other.key"), true)
+	if _, err := VerifyDelivery(row, other.Public().(ed25519.PublicKey)); !errors.Is(err, ErrDeliverySignature) {
+		t.Errorf("other key: VerifyDelivery() = %v, want ErrDeliverySignature", err)
+	}
+	if _, err := VerifyDelivery(row, pub); err != nil {
+		t.Errorf("untampered: VerifyDelivery() = %v", err)
+	}
+}
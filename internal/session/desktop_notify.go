@@ -0,0 +1,292 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// Desktop notification backends (see DesktopNotifySettings.Backend)
+const (
+	NotifyBackendAuto             = "auto"
+	NotifyBackendNotifySend       = "notify-send"
+	NotifyBackendOsascript        = "osascript"
+	NotifyBackendTerminalNotifier = "terminal-notifier"
+	NotifyBackendNone             = "none"
+)
+
+// ValidNotifyBackends lists the accepted values of [notifications.desktop] backend
+var ValidNotifyBackends = []string{
+	NotifyBackendAuto, NotifyBackendNotifySend, NotifyBackendOsascript, NotifyBackendTerminalNotifier, NotifyBackendNone,
+}
+
+// DesktopNotifySettings configures desktop notifications for sessions that
+// finished working. A session that goes from running to waiting or idle and
+// stays quiet for IdleSeconds raises one notification:
+//
+//	[notifications.desktop]
+//	enabled = true
+//	idle_seconds = 30
+//
+//	[notifications.desktop.sessions.api-server]
+//	enabled = false
+//
+// Like event hooks, notifications are raised by the process polling statuses
+// (the TUI or web UI).
+type DesktopNotifySettings struct {
+	// Enabled turns desktop notifications on (default: false)
+	Enabled bool `toml:"enabled"`
+
+	// IdleSeconds is how long a session must stay quiet after working before
+	// it is reported as finished, so pauses between tool calls are not.
+	// Default: 30
+	IdleSeconds int `toml:"idle_seconds"`
+
+	// Backend is auto (terminal-notifier or osascript on macOS, notify-send
+	// elsewhere), notify-send, osascript, terminal-notifier or none
+	Backend string `toml:"backend"`
+
+	// WebhookURL, when set, also receives each notification as the idle
+	// event JSON of [event_hooks] webhooks
+	WebhookURL string `toml:"webhook_url"`
+
+	// Tools limits notifications to sessions of these tools. Default: ["claude"]
+	Tools []string `toml:"tools"`
+
+	// Sessions overrides the settings per session, keyed by ID or title
+	Sessions map[string]DesktopNotifyOverride `toml:"sessions"`
+}
+
+// DesktopNotifyOverride is a [notifications.desktop.sessions.<id|title>] entry
+type DesktopNotifyOverride struct {
+	// Enabled turns notifications on or off for the session, whatever the
+	// global setting and tool filter
+	Enabled *bool `toml:"enabled"`
+
+	// IdleSeconds replaces the global idle_seconds for the session
+	IdleSeconds *int `toml:"idle_seconds"`
+}
+
+// GetDesktopNotifySettings returns [notifications.desktop] with defaults applied
+func GetDesktopNotifySettings() DesktopNotifySettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return DesktopNotifySettings{}
+	}
+	settings := config.Notifications.Desktop
+	if settings.Backend == "" {
+		settings.Backend = NotifyBackendAuto
+	} else if !slices.Contains(ValidNotifyBackends, settings.Backend) {
+		sessionLog.Warn("desktop_notify_invalid_backend", slog.String("backend", settings.Backend))
+		settings.Backend = NotifyBackendAuto
+	}
+	if len(settings.Tools) == 0 {
+		settings.Tools = []string{"claude"}
+	}
+	return settings
+}
+
+// override returns the per-session entry for a session, preferring its ID
+func (s DesktopNotifySettings) override(id, title string) (DesktopNotifyOverride, bool) {
+	if o, ok := s.Sessions[id]; ok && id != "" {
+		return o, true
+	}
+	o, ok := s.Sessions[title]
+	return o, ok && title != ""
+}
+
+// For returns whether a session's finished work is notified, and after how
+// long a quiet period
+func (s DesktopNotifySettings) For(id, title, tool string) (bool, time.Duration) {
+	enabled := s.Enabled && slices.Contains(s.Tools, tool)
+	idle := s.IdleSeconds
+	if o, ok := s.override(id, title); ok {
+		if o.Enabled != nil {
+			enabled = *o.Enabled
+		}
+		if o.IdleSeconds != nil {
+			idle = *o.IdleSeconds
+		}
+	}
+	if idle <= 0 {
+		idle = 30
+	}
+	return enabled, time.Duration(idle) * time.Second
+}
+
+// SetDesktopNotify sets the per-session override of inst (stored under its
+// ID) and saves the config. A nil enabled and idle remove the override.
+func SetDesktopNotify(inst *Instance, enabled *bool, idle *int) error {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+	desktop := &config.Notifications.Desktop
+	delete(desktop.Sessions, inst.Title)
+	if enabled == nil && idle == nil {
+		delete(desktop.Sessions, inst.ID)
+	} else {
+		if desktop.Sessions == nil {
+			desktop.Sessions = make(map[string]DesktopNotifyOverride)
+		}
+		desktop.Sessions[inst.ID] = DesktopNotifyOverride{Enabled: enabled, IdleSeconds: idle}
+	}
+	return SaveUserConfig(config)
+}
+
+// desktopNotifier turns idle events into notifications once the session has
+// stayed quiet for its idle period
+type desktopNotifier struct {
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+
+	settings func() DesktopNotifySettings
+	// status reports a session's current status, false if unknown
+	status func(id string) (Status, bool)
+	send   func(DesktopNotifySettings, HookEvent) error
+}
+
+var defaultDesktopNotifier = &desktopNotifier{
+	settings: GetDesktopNotifySettings,
+	status:   sharedStatus,
+	send:     sendDesktopNotification,
+}
+
+// sharedStatus reads a session's status as last written by any agent-deck process
+func sharedStatus(id string) (Status, bool) {
+	db := statedb.GetGlobal()
+	if db == nil {
+		return "", false
+	}
+	statuses, err := db.ReadAllStatuses()
+	if err != nil {
+		return "", false
+	}
+	row, ok := statuses[id]
+	return Status(row.Status), ok
+}
+
+// observe schedules a notification for an idle event and cancels the pending
+// one of a session that is busy or errored again
+func (n *desktopNotifier) observe(ev HookEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if timer := n.pending[ev.SessionID]; timer != nil {
+		timer.Stop()
+		delete(n.pending, ev.SessionID)
+	}
+	if ev.Event != HookEventIdle {
+		return
+	}
+	settings := n.settings()
+	enabled, idle := settings.For(ev.SessionID, ev.Title, ev.Tool)
+	if !enabled {
+		return
+	}
+	if n.pending == nil {
+		n.pending = make(map[string]*time.Timer)
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(idle, func() {
+		n.mu.Lock()
+		current := n.pending[ev.SessionID] == timer
+		if current {
+			delete(n.pending, ev.SessionID)
+		}
+		n.mu.Unlock()
+		if !current {
+			return
+		}
+		// Another process may have seen the session start working again
+		if status, ok := n.status(ev.SessionID); ok && status != StatusWaiting && status != StatusIdle {
+			return
+		}
+		if err := n.send(settings, ev); err != nil {
+			sessionLog.Warn("desktop_notify_failed",
+				slog.String("session", ev.Title),
+				slog.String("error", err.Error()))
+		}
+	})
+	n.pending[ev.SessionID] = timer
+}
+
+// notifyDesktop passes a status event to the desktop notifier
+func notifyDesktop(ev HookEvent) {
+	defaultDesktopNotifier.observe(ev)
+}
+
+// sendDesktopNotification shows ev on the desktop and posts it to the
+// notification webhook, if any
+func sendDesktopNotification(settings DesktopNotifySettings, ev HookEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	title := "agent-deck: " + ev.Title
+	body := "Finished working"
+	if ev.Status == string(StatusWaiting) {
+		body = "Finished working, waiting for input"
+	}
+	if ev.Group != "" {
+		body += " (" + ev.Group + ")"
+	}
+
+	var errs []string
+	if cmd := desktopNotifyCommand(ctx, settings.Backend, title, body); cmd != nil {
+		if out, err := cmd.CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v %s", cmd.Args[0], err, strings.TrimSpace(string(out))))
+		}
+	}
+	if settings.WebhookURL != "" {
+		if err := postHookEvent(ctx, settings.WebhookURL, &ev); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// desktopNotifyCommand returns the command showing a notification with the
+// given backend, or nil when there is none
+func desktopNotifyCommand(ctx context.Context, backend, title, body string) *exec.Cmd {
+	if backend == NotifyBackendAuto {
+		backend = autoNotifyBackend()
+	}
+	switch backend {
+	case NotifyBackendNotifySend:
+		return exec.CommandContext(ctx, "notify-send", "--app-name=agent-deck", title, body)
+	case NotifyBackendTerminalNotifier:
+		return exec.CommandContext(ctx, "terminal-notifier", "-title", title, "-message", body, "-group", "agent-deck")
+	case NotifyBackendOsascript:
+		// Pass the text as arguments so quotes in titles need no escaping
+		return exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	}
+	return nil
+}
+
+// autoNotifyBackend picks the notifier available on this system
+func autoNotifyBackend() string {
+	if runtime.GOOS == "darwin" {
+		if _, err := exec.LookPath("terminal-notifier"); err == nil {
+			return NotifyBackendTerminalNotifier
+		}
+		return NotifyBackendOsascript
+	}
+	if _, err := exec.LookPath("notify-send"); err == nil {
+		return NotifyBackendNotifySend
+	}
+	return NotifyBackendNone
+}
@@ -0,0 +1,122 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDesktopNotifySettingsFor(t *testing.T) {
+	off, idle := false, 120
+	settings := DesktopNotifySettings{
+		Enabled:     true,
+		IdleSeconds: 10,
+		Tools:       []string{"claude"},
+		Sessions: map[string]DesktopNotifyOverride{
+			"muted":   {Enabled: &off},
+			"id-slow": {IdleSeconds: &idle},
+		},
+	}
+	tests := []struct {
+		id, title, tool string
+		wantOn          bool
+		wantAfter       time.Duration
+	}{
+		{"1", "api", "claude", true, 10 * time.Second},
+		{"2", "shell", "shell", false, 10 * time.Second},
+		{"3", "muted", "claude", false, 10 * time.Second},
+		{"id-slow", "slow", "claude", true, 120 * time.Second},
+	}
+	for _, tt := range tests {
+		on, after := settings.For(tt.id, tt.title, tt.tool)
+		if on != tt.wantOn || after != tt.wantAfter {
+			t.Errorf("For(%s) = %v, %v; want %v, %v", tt.title, on, after, tt.wantOn, tt.wantAfter)
+		}
+	}
+
+	if _, after := (DesktopNotifySettings{}).For("1", "api", "claude"); after != 30*time.Second {
+		t.Errorf("default idle = %v, want 30s", after)
+	}
+}
+
+func TestSetDesktopNotify(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	inst := &Instance{ID: "abc", Title: "api", Tool: "shell"}
+	on, idle := true, 60
+	if err := SetDesktopNotify(inst, &on, &idle); err != nil {
+		t.Fatalf("SetDesktopNotify: %v", err)
+	}
+	enabled, after := GetDesktopNotifySettings().For(inst.ID, inst.Title, inst.Tool)
+	if !enabled || after != time.Minute {
+		t.Fatalf("after override: %v, %v; want true, 1m", enabled, after)
+	}
+
+	if err := SetDesktopNotify(inst, nil, nil); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if enabled, _ := GetDesktopNotifySettings().For(inst.ID, inst.Title, inst.Tool); enabled {
+		t.Error("expected notifications off once the override is removed")
+	}
+}
+
+func TestDesktopNotifierWaitsForQuietPeriod(t *testing.T) {
+	var mu sync.Mutex
+	status := map[string]Status{"a": StatusWaiting, "b": StatusWaiting}
+	sent := make(chan string, 4)
+	idle := 1
+	n := &desktopNotifier{
+		settings: func() DesktopNotifySettings {
+			return DesktopNotifySettings{Enabled: true, IdleSeconds: idle, Tools: []string{"claude"}}
+		},
+		status: func(id string) (Status, bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			s, ok := status[id]
+			return s, ok
+		},
+		send: func(_ DesktopNotifySettings, ev HookEvent) error {
+			sent <- ev.SessionID
+			return nil
+		},
+	}
+
+	// a finishes and stays quiet: notified once
+	n.observe(HookEvent{Event: HookEventIdle, SessionID: "a", Tool: "claude"})
+	// b finishes but starts working again before the quiet period ends
+	n.observe(HookEvent{Event: HookEventIdle, SessionID: "b", Tool: "claude"})
+	n.observe(HookEvent{Event: HookEventBusy, SessionID: "b", Tool: "claude"})
+	// c is not a claude session
+	n.observe(HookEvent{Event: HookEventIdle, SessionID: "c", Tool: "shell"})
+
+	select {
+	case id := <-sent:
+		if id != "a" {
+			t.Fatalf("notified %s, want a", id)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no notification for a")
+	}
+	select {
+	case id := <-sent:
+		t.Fatalf("unexpected notification for %s", id)
+	case <-time.After(1500 * time.Millisecond):
+	}
+}
+
+func TestDesktopNotifyCommand(t *testing.T) {
+	ctx := context.Background()
+	if cmd := desktopNotifyCommand(ctx, NotifyBackendNone, "t", "b"); cmd != nil {
+		t.Errorf("none backend returned %v", cmd.Args)
+	}
+	cmd := desktopNotifyCommand(ctx, NotifyBackendOsascript, `say "hi"`, "done")
+	if cmd == nil || cmd.Args[len(cmd.Args)-2] != `say "hi"` {
+		t.Errorf("osascript should pass the title as an argument, got %v", cmd)
+	}
+	if cmd := desktopNotifyCommand(ctx, NotifyBackendNotifySend, "t", "b"); cmd == nil || cmd.Args[0] != "notify-send" {
+		t.Errorf("notify-send backend returned %v", cmd)
+	}
+}
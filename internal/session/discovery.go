@@ -7,28 +7,39 @@ import (
 	"github.com/asheshgoplani/agent-deck/internal/tmux"
 )
 
-// DiscoverExistingTmuxSessions finds all tmux sessions and converts them to instances
+// DiscoverExistingTmuxSessions finds all untracked tmux sessions and converts
+// them to instances. Sessions whose title is already in use are skipped; use
+// DiscoverTmuxCandidates with ResolveAdoptConflicts to rename or merge them.
 func DiscoverExistingTmuxSessions(existingInstances []*Instance) ([]*Instance, error) {
+	candidates, err := DiscoverTmuxCandidates(existingInstances)
+	if err != nil {
+		return nil, err
+	}
+	outcomes := ResolveAdoptConflicts(existingInstances, candidates, func(AdoptConflict) string { return ConflictSkip })
+	return AdoptedInstances(outcomes), nil
+}
+
+// DiscoverTmuxCandidates finds all tmux sessions not tracked by
+// existingInstances and converts them to instances, whatever their title
+func DiscoverTmuxCandidates(existingInstances []*Instance) ([]*Instance, error) {
 	// Get all tmux sessions
 	tmuxSessions, err := tmux.DiscoverAllTmuxSessions()
 	if err != nil {
 		return nil, err
 	}
 
-	// Build a map of existing sessions by tmux name
-	existingMap := make(map[string]bool)
+	// Build a set of tracked sessions by tmux name
+	tracked := make(map[string]bool)
 	for _, inst := range existingInstances {
 		if inst.GetTmuxSession() != nil {
-			existingMap[inst.GetTmuxSession().Name] = true
+			tracked[inst.GetTmuxSession().Name] = true
 		}
-		// Also track by title
-		existingMap[inst.Title] = true
 	}
 
 	var discovered []*Instance
 	for _, sess := range tmuxSessions {
 		// Skip if already tracked
-		if existingMap[sess.Name] || existingMap[sess.DisplayName] {
+		if tracked[sess.Name] {
 			continue
 		}
 
@@ -73,6 +84,7 @@ func DiscoverExistingTmuxSessions(existingInstances []*Instance) ([]*Instance, e
 			GroupPath:   groupPath,
 			Status:      StatusIdle,
 			Tool:        tool,
+			StartReason: StartReasonDiscovered,
 			tmuxSession: sess,
 		}
 		_ = inst.UpdateStatus()
@@ -96,7 +108,7 @@ func GroupByProject(instances []*Instance) map[string][]*Instance {
 }
 
 // FilterByQuery filters sessions by title, project path, tool, or status
-// Supports status filters: "waiting", "running", "idle", "error"
+// Supports status filters: "waiting", "running", "idle", "error", "throttled"
 func FilterByQuery(instances []*Instance, query string) []*Instance {
 	if query == "" {
 		return instances
@@ -106,10 +118,11 @@ func FilterByQuery(instances []*Instance, query string) []*Instance {
 
 	// Check for status filters
 	statusFilters := map[string]Status{
-		"waiting": StatusWaiting,
-		"running": StatusRunning,
-		"idle":    StatusIdle,
-		"error":   StatusError,
+		"waiting":   StatusWaiting,
+		"running":   StatusRunning,
+		"idle":      StatusIdle,
+		"error":     StatusError,
+		"throttled": StatusThrottled,
 	}
 
 	// If query matches a status filter exactly, filter by status
@@ -158,6 +171,12 @@ func detectToolFromName(name string) string {
 	if strings.Contains(nameLower, "codex") {
 		return "codex"
 	}
+	if strings.Contains(nameLower, "ollama") {
+		return "ollama"
+	}
+	if strings.Contains(nameLower, "aider") {
+		return "aider"
+	}
 
 	return "shell"
 }
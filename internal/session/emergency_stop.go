@@ -0,0 +1,171 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// emergencyStopFile marks the deck as emergency-stopped, next to config.toml
+const emergencyStopFile = "emergency-stop.json"
+
+// ErrNotEmergencyStopped is returned when resuming a deck that is not stopped
+var ErrNotEmergencyStopped = errors.New("the deck is not emergency-stopped")
+
+// EmergencyStopState records an emergency stop. While it exists the deck
+// behaves as in a maintenance window that never ends (see
+// ActiveMaintenanceWindow) and sessions accept no messages, until
+// ResumeEmergencyStop removes it.
+type EmergencyStopState struct {
+	StoppedAt time.Time `json:"stopped_at"`
+	Reason    string    `json:"reason,omitempty"`
+	// By names where the stop came from (cli, tui, bridge, web)
+	By string `json:"by,omitempty"`
+	// Interrupted lists the busy sessions that were interrupted, as
+	// "<profile>/<title>"
+	Interrupted []string `json:"interrupted"`
+	// Errors lists sessions that could not be interrupted
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Describe renders the stop for banners
+func (s *EmergencyStopState) Describe() string {
+	msg := fmt.Sprintf("EMERGENCY STOP since %s", NewTimeFormatter(true).FormatAbsolute(s.StoppedAt))
+	if s.Reason != "" {
+		msg += ": " + s.Reason
+	}
+	return msg + " (sessions take no messages; heartbeats, task dispatch and auto-restarts paused until resumed)"
+}
+
+func emergencyStopPath() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, emergencyStopFile), nil
+}
+
+// LoadEmergencyStop returns the deck's emergency stop, or nil when the deck
+// is running normally. An unreadable stop file still counts as stopped.
+func LoadEmergencyStop() *EmergencyStopState {
+	path, err := emergencyStopPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	var state EmergencyStopState
+	if err != nil || json.Unmarshal(data, &state) != nil {
+		return &EmergencyStopState{Reason: "unreadable " + emergencyStopFile}
+	}
+	return &state
+}
+
+// EmergencyStop pauses the whole deck and interrupts every busy session in
+// every profile. The pause is written first, so heartbeats and queued tasks
+// stop even while sessions are still being interrupted. Stopping a stopped
+// deck interrupts again and keeps the original stop time.
+func EmergencyStop(reason, by string) (*EmergencyStopState, error) {
+	state := &EmergencyStopState{StoppedAt: time.Now(), Reason: reason, By: by, Interrupted: []string{}}
+	if prev := LoadEmergencyStop(); prev != nil && !prev.StoppedAt.IsZero() {
+		state.StoppedAt = prev.StoppedAt
+		if reason == "" {
+			state.Reason = prev.Reason
+		}
+	}
+	if err := saveEmergencyStop(state); err != nil {
+		return nil, err
+	}
+	sessionLog.Warn("emergency_stop", slog.String("reason", reason), slog.String("by", by))
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		return state, fmt.Errorf("deck paused, but listing profiles failed: %w", err)
+	}
+	for _, profile := range profiles {
+		storage, err := NewStorageWithProfile(profile)
+		if err != nil {
+			state.Errors = append(state.Errors, fmt.Sprintf("%s: %v", profile, err))
+			continue
+		}
+		instances, _, err := storage.LoadWithGroups()
+		_ = storage.Close()
+		if err != nil {
+			state.Errors = append(state.Errors, fmt.Sprintf("%s: %v", profile, err))
+			continue
+		}
+		interrupted, errs := interruptBusySessions(instances)
+		for _, title := range interrupted {
+			state.Interrupted = append(state.Interrupted, profile+"/"+title)
+		}
+		for _, e := range errs {
+			state.Errors = append(state.Errors, profile+"/"+e)
+		}
+	}
+	return state, saveEmergencyStop(state)
+}
+
+// interruptBusySessions interrupts the running sessions among instances and
+// returns the titles interrupted and "<title>: <error>" for failures
+func interruptBusySessions(instances []*Instance) (interrupted, errs []string) {
+	for _, inst := range instances {
+		if !inst.Exists() {
+			continue
+		}
+		_ = inst.UpdateStatus()
+		if status := inst.GetStatusThreadSafe(); status != StatusRunning && status != StatusThrottled {
+			continue
+		}
+		if err := inst.Interrupt(""); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", inst.Title, err))
+			continue
+		}
+		interrupted = append(interrupted, inst.Title)
+	}
+	return interrupted, errs
+}
+
+// ResumeEmergencyStop lifts the emergency stop and returns it
+func ResumeEmergencyStop() (*EmergencyStopState, error) {
+	state := LoadEmergencyStop()
+	if state == nil {
+		return nil, ErrNotEmergencyStopped
+	}
+	path, err := emergencyStopPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to resume: %w", err)
+	}
+	sessionLog.Info("emergency_stop_resumed", slog.Duration("stopped_for", time.Since(state.StoppedAt)))
+	return state, nil
+}
+
+func saveEmergencyStop(state *EmergencyStopState) error {
+	path, err := emergencyStopPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write emergency stop: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write emergency stop: %w", err)
+	}
+	return nil
+}
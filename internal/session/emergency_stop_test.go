@@ -0,0 +1,68 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEmergencyStop(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "default", true, "", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if LoadEmergencyStop() != nil {
+		t.Fatal("deck should start running")
+	}
+	if _, err := ResumeEmergencyStop(); !errors.Is(err, ErrNotEmergencyStopped) {
+		t.Fatalf("resume of a running deck = %v, want ErrNotEmergencyStopped", err)
+	}
+
+	state, err := EmergencyStop("runaway agent", "cli")
+	if err != nil || state == nil || state.Reason != "runaway agent" || state.By != "cli" {
+		t.Fatalf("EmergencyStop = %+v, %v", state, err)
+	}
+
+	// The stop is an open-ended maintenance window
+	active := ActiveMaintenanceWindow(time.Now())
+	if active == nil || active.Source != MaintenanceSourceEmergency || active.EmergencyStop == nil {
+		t.Fatalf("active window = %+v, want the emergency stop", active)
+	}
+
+	// Queued tasks stay queued
+	if _, err := EnqueueTask("ops", "api", "run tests"); err != nil {
+		t.Fatal(err)
+	}
+	worker := &Instance{ID: "api-1", Title: "api", Tool: "shell", Status: StatusIdle}
+	send := func(*Instance, string) error {
+		t.Error("task sent during an emergency stop")
+		return nil
+	}
+	if report, err := DispatchConductorTasks("ops", []*Instance{worker}, send); err != nil || len(report.Dispatched) != 0 {
+		t.Fatalf("dispatch during stop = %+v, %v", report, err)
+	}
+
+	// Stopping again keeps the original stop time and reason
+	again, err := EmergencyStop("", "tui")
+	if err != nil || !again.StoppedAt.Equal(state.StoppedAt) || again.Reason != "runaway agent" {
+		t.Fatalf("second stop = %+v, %v", again, err)
+	}
+
+	if _, err := ResumeEmergencyStop(); err != nil {
+		t.Fatal(err)
+	}
+	if LoadEmergencyStop() != nil || ActiveMaintenanceWindow(time.Now()) != nil {
+		t.Fatal("deck still stopped after resume")
+	}
+
+	// A stop file that cannot be parsed must not un-stop the deck
+	dir, _ := GetAgentDeckDir()
+	if err := os.WriteFile(filepath.Join(dir, emergencyStopFile), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if LoadEmergencyStop() == nil {
+		t.Error("unreadable stop file should count as stopped")
+	}
+}
@@ -11,6 +11,7 @@ import (
 // buildEnvSourceCommand builds shell commands to source .env files before the main command.
 // Returns empty string if no env files are configured.
 // Order of sourcing (later overrides earlier):
+//  0. Proxy and CA bundle from [proxy] / [profiles.<name>.proxy]
 //  1. Global [shell].env_files (in order)
 //  2. [shell].init_script (for direnv, nvm, etc.)
 //  3. Tool-specific env_file ([claude].env_file, [gemini].env_file, [tools.X].env_file)
@@ -24,6 +25,11 @@ func (i *Instance) buildEnvSourceCommand() string {
 
 	ignoreMissing := config.Shell.GetIgnoreMissingEnvFiles()
 
+	// 0. Proxy and CA bundle for the profile (env files may override)
+	if proxyEnv := i.getProxyEnv(); proxyEnv != "" {
+		sources = append(sources, proxyEnv)
+	}
+
 	// 1. Global env_files from [shell] section
 	for _, envFile := range config.Shell.EnvFiles {
 		resolved := resolvePath(envFile, i.ProjectPath)
@@ -54,6 +60,11 @@ func (i *Instance) buildEnvSourceCommand() string {
 		sources = append(sources, inlineEnv)
 	}
 
+	// 5. Command guard shims, first on PATH after everything above
+	if guardEnv := i.getGuardEnv(); guardEnv != "" {
+		sources = append(sources, guardEnv)
+	}
+
 	if len(sources) == 0 {
 		return ""
 	}
@@ -131,9 +142,14 @@ func (i *Instance) getToolInlineEnv() string {
 		return ""
 	}
 
-	// Sort keys for deterministic ordering
-	keys := make([]string, 0, len(def.Env))
-	for k := range def.Env {
+	return exportEnv(def.Env)
+}
+
+// exportEnv renders env vars as export statements joined with &&, sorted by
+// key, with single quotes in values escaped.
+func exportEnv(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
@@ -141,9 +157,8 @@ func (i *Instance) getToolInlineEnv() string {
 	// Build export statements with single-quote escaping
 	exports := make([]string, 0, len(keys))
 	for _, k := range keys {
-		v := def.Env[k]
 		// Escape single quotes: replace ' with '\'' (end quote, escaped quote, start quote)
-		escaped := strings.ReplaceAll(v, "'", "'\\''")
+		escaped := strings.ReplaceAll(env[k], "'", "'\\''")
 		exports = append(exports, fmt.Sprintf("export %s='%s'", k, escaped))
 	}
 
@@ -162,6 +177,10 @@ func (i *Instance) getToolEnvFile() string {
 		return config.Claude.EnvFile
 	case "gemini":
 		return config.Gemini.EnvFile
+	case "ollama":
+		return config.Ollama.EnvFile
+	case "aider":
+		return config.Aider.EnvFile
 	default:
 		// Check custom tools
 		if def := GetToolDef(i.Tool); def != nil {
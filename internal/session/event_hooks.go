@@ -0,0 +1,325 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+var eventHookLog = logging.ForComponent(logging.CompSession)
+
+// Events that [[event_hooks.hook]] entries can run on
+const (
+	HookEventIdle      = "idle"      // a session stopped working (running -> waiting or idle)
+	HookEventBusy      = "busy"      // a session started working
+	HookEventError     = "error"     // a session errored or its tmux session vanished
+	HookEventHeartbeat = "heartbeat" // a heartbeat was sent to (or skipped for) a conductor
+	HookEventFork      = "fork"      // a session was forked
+)
+
+// HookEventNames lists the accepted hook events
+var HookEventNames = []string{HookEventIdle, HookEventBusy, HookEventError, HookEventHeartbeat, HookEventFork}
+
+// EventHooksSettings configures commands and webhooks run on session events
+// so notifications and automation work without patching agent-deck:
+//
+//	[[event_hooks.hook]]
+//	event = "idle"
+//	command = "notify-send agent-deck \"$AGENTDECK_TITLE is done\""
+//
+//	[[event_hooks.hook]]
+//	event = "error"
+//	url = "https://ci.example.com/hooks/agent-deck"
+//
+// Status events are fired by the process polling statuses (the TUI or web
+// UI); when several run, the first to see a change fires its hooks.
+type EventHooksSettings struct {
+	// Timeout bounds each command or webhook, in seconds. Default: 10
+	Timeout int `toml:"timeout"`
+
+	// Hooks run in parallel for each matching event
+	Hooks []EventHook `toml:"hook"`
+}
+
+// EventHook is one [[event_hooks.hook]] entry
+type EventHook struct {
+	// Event is one of HookEventNames, or "*" for all of them
+	Event string `toml:"event"`
+
+	// Command runs with sh -c in the session's project directory, with the
+	// event in AGENTDECK_* environment variables (see HookEvent.Env)
+	Command string `toml:"command"`
+
+	// URL receives the event as a JSON POST (set Command or URL, not both)
+	URL string `toml:"url"`
+
+	// Profiles and Tools narrow the hook to sessions of these profiles and
+	// tools (empty = all)
+	Profiles []string `toml:"profiles"`
+	Tools    []string `toml:"tools"`
+}
+
+// validate checks the event name and that exactly one action is set
+func (h *EventHook) validate() error {
+	if h.Event != "*" && !slices.Contains(HookEventNames, h.Event) {
+		return fmt.Errorf("unknown event %q (use %s or *)", h.Event, strings.Join(HookEventNames, ", "))
+	}
+	if (h.Command == "") == (h.URL == "") {
+		return fmt.Errorf("set either command or url")
+	}
+	if h.URL != "" {
+		u, err := url.Parse(h.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid url: want http(s)://host/...")
+		}
+	}
+	return nil
+}
+
+// matches reports whether the hook runs for ev
+func (h *EventHook) matches(ev *HookEvent) bool {
+	if h.Event != "*" && h.Event != ev.Event {
+		return false
+	}
+	if len(h.Profiles) > 0 && !slices.Contains(h.Profiles, ev.Profile) {
+		return false
+	}
+	return len(h.Tools) == 0 || slices.Contains(h.Tools, ev.Tool)
+}
+
+// GetTimeout returns the per-hook timeout, defaulting to 10 seconds
+func (s EventHooksSettings) GetTimeout() time.Duration {
+	if s.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(s.Timeout) * time.Second
+}
+
+// GetEventHooksSettings returns [event_hooks] from config.toml without the
+// invalid entries, which are logged
+func GetEventHooksSettings() EventHooksSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return EventHooksSettings{}
+	}
+	settings := config.EventHooks
+	valid := settings.Hooks[:0:0]
+	for n, hook := range settings.Hooks {
+		if err := hook.validate(); err != nil {
+			eventHookLog.Warn("event_hook_invalid", slog.Int("index", n), slog.String("error", err.Error()))
+			continue
+		}
+		valid = append(valid, hook)
+	}
+	settings.Hooks = valid
+	return settings
+}
+
+// HookEvent describes an event to hooks: as JSON to webhooks, as AGENTDECK_*
+// variables to commands
+type HookEvent struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"ts"`
+	Profile    string    `json:"profile,omitempty"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Tool       string    `json:"tool,omitempty"`
+	Group      string    `json:"group,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	PrevStatus string    `json:"prev_status,omitempty"`
+	// Conductor and Result describe heartbeats
+	Conductor string `json:"conductor,omitempty"`
+	Result    string `json:"result,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// ParentID is the forked session (fork events)
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// NewHookEvent describes event for a session
+func NewHookEvent(event, profile string, inst *Instance, now time.Time) HookEvent {
+	return HookEvent{
+		Event:     event,
+		Time:      now,
+		Profile:   normalizeConductorProfile(profile),
+		SessionID: inst.ID,
+		Title:     inst.Title,
+		Tool:      inst.Tool,
+		Group:     inst.GroupPath,
+		Path:      inst.ProjectPath,
+		Status:    string(inst.GetStatusThreadSafe()),
+	}
+}
+
+// Env returns the event as environment variables for hook commands
+func (e *HookEvent) Env() []string {
+	vars := []struct{ name, value string }{
+		{"AGENTDECK_EVENT", e.Event},
+		{"AGENTDECK_TIME", e.Time.Format(time.RFC3339)},
+		{"AGENTDECK_PROFILE", e.Profile},
+		{"AGENTDECK_SESSION_ID", e.SessionID},
+		{"AGENTDECK_TITLE", e.Title},
+		{"AGENTDECK_TOOL", e.Tool},
+		{"AGENTDECK_GROUP", e.Group},
+		{"AGENTDECK_PATH", e.Path},
+		{"AGENTDECK_STATUS", e.Status},
+		{"AGENTDECK_PREV_STATUS", e.PrevStatus},
+		{"AGENTDECK_CONDUCTOR", e.Conductor},
+		{"AGENTDECK_RESULT", e.Result},
+		{"AGENTDECK_ERROR", e.Error},
+		{"AGENTDECK_PARENT_ID", e.ParentID},
+	}
+	env := make([]string, 0, len(vars))
+	for _, v := range vars {
+		env = append(env, v.name+"="+v.value)
+	}
+	return env
+}
+
+// StatusHookEvent maps a status change to the hook event it fires, or ""
+func StatusHookEvent(prev, status Status) string {
+	if prev == status || prev == "" {
+		return ""
+	}
+	switch {
+	case status == StatusRunning:
+		return HookEventBusy
+	case status == StatusError:
+		return HookEventError
+	case prev == StatusRunning && (status == StatusWaiting || status == StatusIdle):
+		return HookEventIdle
+	}
+	return ""
+}
+
+// RecordStatusTransition records inst's status (statedb.RecordStatus) and
+// returns the hook event its change fires, or nil. Only the first agent-deck
+// process to record a change sees it, so hooks fire once per change.
+func RecordStatusTransition(db *statedb.StateDB, profile string, inst *Instance, now time.Time) (*HookEvent, error) {
+	status := inst.GetStatusThreadSafe()
+	prev, err := db.RecordStatusTransition(inst.ID, string(status), now)
+	if err != nil {
+		return nil, err
+	}
+	event := StatusHookEvent(Status(prev), status)
+	if event == "" {
+		return nil, nil
+	}
+	ev := NewHookEvent(event, profile, inst, now)
+	ev.PrevStatus = prev
+	return &ev, nil
+}
+
+// RecordStatusWithHooks is RecordStatusTransition running the fired hooks in
+// the background and passing them to the desktop notifier, for long-lived
+// processes
+func RecordStatusWithHooks(db *statedb.StateDB, profile string, inst *Instance, now time.Time) error {
+	ev, err := RecordStatusTransition(db, profile, inst, now)
+	if ev != nil {
+		notifyDesktop(*ev)
+		go RunEventHooks(*ev)
+	}
+	return err
+}
+
+// RunEventHooks runs the hooks matching ev in parallel and waits for them,
+// each bounded by the [event_hooks] timeout, along with the Slack or Discord
+// notification ev raises for its conductor. Failures are logged and returned.
+func RunEventHooks(ev HookEvent) []error {
+	settings := GetEventHooksSettings()
+	var hooks []EventHook
+	for _, hook := range settings.Hooks {
+		if hook.matches(&ev) {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	errs := make([]error, len(hooks)+1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[len(hooks)] = notifyConductor(ev, settings.GetTimeout())
+	}()
+	for n, hook := range hooks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), settings.GetTimeout())
+			defer cancel()
+			if hook.URL != "" {
+				errs[n] = postHookEvent(ctx, hook.URL, &ev)
+			} else {
+				errs[n] = runHookCommand(ctx, hook.Command, &ev)
+			}
+			if errs[n] != nil {
+				eventHookLog.Warn("event_hook_failed",
+					slog.String("event", ev.Event),
+					slog.String("session", ev.Title),
+					slog.String("error", errs[n].Error()))
+			}
+		}()
+	}
+	wg.Wait()
+	return slices.DeleteFunc(errs, func(err error) bool { return err == nil })
+}
+
+// runHookCommand runs a hook command with the event in its environment, in
+// the session's project directory when it exists
+func runHookCommand(ctx context.Context, command string, ev *HookEvent) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), ev.Env()...)
+	if info, err := os.Stat(ev.Path); ev.Path != "" && err == nil && info.IsDir() {
+		cmd.Dir = ev.Path
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if len(msg) > 200 {
+			msg = msg[:200] + "..."
+		}
+		if msg != "" {
+			return fmt.Errorf("command %q: %w: %s", command, err, msg)
+		}
+		return fmt.Errorf("command %q: %w", command, err)
+	}
+	return nil
+}
+
+// postHookEvent POSTs the event as JSON and fails on a non-2xx status
+func postHookEvent(ctx context.Context, target string, ev *HookEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	// Webhook URLs often embed a token; errors name the host only
+	host := req.URL.Host
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "agent-deck")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook to %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook to %s: HTTP %d", host, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventHookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    EventHook
+		wantErr bool
+	}{
+		{"command", EventHook{Event: "idle", Command: "true"}, false},
+		{"url", EventHook{Event: "*", URL: "https://example.com/hook"}, false},
+		{"unknown event", EventHook{Event: "done", Command: "true"}, true},
+		{"no action", EventHook{Event: "idle"}, true},
+		{"both actions", EventHook{Event: "idle", Command: "true", URL: "https://example.com"}, true},
+		{"bad url", EventHook{Event: "idle", URL: "ftp://example.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hook.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventHookMatches(t *testing.T) {
+	ev := &HookEvent{Event: HookEventIdle, Profile: "work", Tool: "claude"}
+	tests := []struct {
+		hook EventHook
+		want bool
+	}{
+		{EventHook{Event: "idle"}, true},
+		{EventHook{Event: "*"}, true},
+		{EventHook{Event: "error"}, false},
+		{EventHook{Event: "idle", Profiles: []string{"work"}}, true},
+		{EventHook{Event: "idle", Profiles: []string{"default"}}, false},
+		{EventHook{Event: "idle", Tools: []string{"codex"}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.hook.matches(ev); got != tt.want {
+			t.Errorf("%+v matches = %v, want %v", tt.hook, got, tt.want)
+		}
+	}
+}
+
+func TestStatusHookEvent(t *testing.T) {
+	tests := []struct {
+		prev, status Status
+		want         string
+	}{
+		{"", StatusRunning, ""},
+		{StatusRunning, StatusRunning, ""},
+		{StatusWaiting, StatusRunning, HookEventBusy},
+		{StatusRunning, StatusWaiting, HookEventIdle},
+		{StatusRunning, StatusIdle, HookEventIdle},
+		{StatusWaiting, StatusIdle, ""},
+		{StatusIdle, StatusError, HookEventError},
+	}
+	for _, tt := range tests {
+		if got := StatusHookEvent(tt.prev, tt.status); got != tt.want {
+			t.Errorf("StatusHookEvent(%q, %q) = %q, want %q", tt.prev, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRunEventHooksCommandAndWebhook(t *testing.T) {
+	received := make(chan HookEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev HookEvent
+		_ = json.NewDecoder(r.Body).Decode(&ev)
+		received <- ev
+	}))
+	defer srv.Close()
+
+	out := filepath.Join(t.TempDir(), "hook.out")
+	writeMacroConfig(t, `
+[[event_hooks.hook]]
+event = "idle"
+command = "printf '%s %s' \"$AGENTDECK_TITLE\" \"$AGENTDECK_PREV_STATUS\" > `+out+`"
+
+[[event_hooks.hook]]
+event = "idle"
+url = "`+srv.URL+`"
+
+[[event_hooks.hook]]
+event = "error"
+command = "exit 1"
+
+[[event_hooks.hook]]
+event = "bogus"
+command = "true"
+`)
+	if hooks := GetEventHooksSettings().Hooks; len(hooks) != 3 {
+		t.Fatalf("expected the invalid hook to be dropped, got %d hooks", len(hooks))
+	}
+
+	ev := HookEvent{Event: HookEventIdle, Time: time.Now(), Title: "api", PrevStatus: "running", Status: "waiting"}
+	if errs := RunEventHooks(ev); len(errs) != 0 {
+		t.Fatalf("RunEventHooks: %v", errs)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("command did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "api running" {
+		t.Errorf("command output = %q, want %q", got, "api running")
+	}
+	select {
+	case got := <-received:
+		if got.Event != HookEventIdle || got.Title != "api" {
+			t.Errorf("webhook got %+v", got)
+		}
+	default:
+		t.Error("webhook not called")
+	}
+
+	if errs := RunEventHooks(HookEvent{Event: HookEventError, Time: time.Now()}); len(errs) != 1 {
+		t.Errorf("expected the failing command to be reported, got %v", errs)
+	}
+}
@@ -0,0 +1,245 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Event types emitted by "agent-deck --events"
+const (
+	StreamEventStatusChanged = "status_changed"
+	StreamEventEscalation    = "escalation"
+	StreamEventHeartbeat     = "heartbeat"
+)
+
+// Heartbeat results recorded by "session send"
+const (
+	HeartbeatSent    = "sent"
+	HeartbeatSkipped = "skipped"
+	HeartbeatFailed  = "failed"
+)
+
+// StreamEvent is one line of the newline-delimited JSON event stream
+type StreamEvent struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"ts"`
+	Profile    string    `json:"profile,omitempty"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Tool       string    `json:"tool,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	PrevStatus string    `json:"prev_status,omitempty"`
+	Conductor  string    `json:"conductor,omitempty"`
+	Result     string    `json:"result,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	// Risk and Reason explain an escalation raised by risky tool calls
+	Risk   string `json:"risk,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HeartbeatResult is the outcome of the last heartbeat sent to a conductor
+type HeartbeatResult struct {
+	Conductor string    `json:"conductor"`
+	Profile   string    `json:"profile"`
+	SessionID string    `json:"session_id"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"ts"`
+}
+
+// heartbeatResultsDir keeps one file per conductor. It is a subdirectory so
+// StatusEventWatcher, which watches the events directory itself, ignores it.
+func heartbeatResultsDir() string {
+	return filepath.Join(GetEventsDir(), "heartbeats")
+}
+
+// WriteHeartbeatResult atomically records the outcome of a heartbeat
+func WriteHeartbeatResult(result HeartbeatResult) error {
+	level := slog.LevelInfo
+	if result.Result == HeartbeatFailed {
+		level = slog.LevelWarn
+	}
+	countEvent(HeartbeatCounterName(result.Result, result.Conductor))
+	heartbeatLog.Log(context.Background(), level, "heartbeat",
+		slog.String("conductor", result.Conductor),
+		slog.String("profile", result.Profile),
+		slog.String("session_id", result.SessionID),
+		slog.String("result", result.Result),
+		slog.String("error", result.Error),
+	)
+
+	dir := heartbeatResultsDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create heartbeat results dir: %w", err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal heartbeat result: %w", err)
+	}
+	path := filepath.Join(dir, result.Conductor+".json")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("write heartbeat result: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ReadHeartbeatResult returns the last recorded heartbeat of a conductor,
+// nil when none was recorded
+func ReadHeartbeatResult(conductor string) (*HeartbeatResult, error) {
+	data, err := os.ReadFile(filepath.Join(heartbeatResultsDir(), conductor+".json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var result HeartbeatResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse heartbeat result: %w", err)
+	}
+	return &result, nil
+}
+
+// ReadHeartbeatResults returns the last recorded heartbeat of every conductor
+func ReadHeartbeatResults() ([]HeartbeatResult, error) {
+	entries, err := os.ReadDir(heartbeatResultsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []HeartbeatResult
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(heartbeatResultsDir(), entry.Name()))
+		if err != nil {
+			continue
+		}
+		var result HeartbeatResult
+		if err := json.Unmarshal(data, &result); err != nil || result.Conductor == "" {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// EventStream turns successive status snapshots and heartbeat results into
+// stream events. The first snapshot of a profile is the baseline and emits
+// nothing unless Initial is set; sessions added later are reported with an
+// empty prev_status.
+type EventStream struct {
+	// Initial reports every session's status on the first snapshot
+	Initial bool
+
+	statuses   map[string]map[string]Status    // profile -> session ID -> status
+	risks      map[string]map[string]RiskLevel // profile -> session ID -> risk level
+	heartbeats map[string]time.Time            // conductor -> last reported heartbeat
+}
+
+// NewEventStream creates an event stream with no baseline
+func NewEventStream() *EventStream {
+	return &EventStream{
+		statuses:   make(map[string]map[string]Status),
+		risks:      make(map[string]map[string]RiskLevel),
+		heartbeats: make(map[string]time.Time),
+	}
+}
+
+// ObserveStatuses diffs a profile's refreshed instances against the previous
+// snapshot. A non-conductor session that starts waiting without being
+// acknowledged is also reported as an escalation, like in the fleet view,
+// and so is a session whose risk rises to high or critical.
+func (s *EventStream) ObserveStatuses(profile string, instances []*Instance, acked map[string]bool, now time.Time) []StreamEvent {
+	prev, seen := s.statuses[profile]
+	current := make(map[string]Status, len(instances))
+	prevRisks := s.risks[profile]
+	currentRisks := make(map[string]RiskLevel, len(instances))
+	var events []StreamEvent
+	for _, inst := range instances {
+		status := inst.GetStatusThreadSafe()
+		current[inst.ID] = status
+
+		if risk := inst.Risk(); risk != nil {
+			currentRisks[inst.ID] = risk.Level
+			if risk.Level.Rank() >= RiskHigh.Rank() && risk.Level.Rank() > prevRisks[inst.ID].Rank() && (seen || s.Initial) {
+				events = append(events, StreamEvent{
+					Type:      StreamEventEscalation,
+					Time:      now,
+					Profile:   profile,
+					SessionID: inst.ID,
+					Title:     inst.Title,
+					Tool:      inst.Tool,
+					Status:    string(status),
+					Risk:      string(risk.Level),
+					Reason:    risk.Describe(),
+				})
+			}
+		} else if level, ok := prevRisks[inst.ID]; ok {
+			currentRisks[inst.ID] = level
+		}
+
+		before, known := prev[inst.ID]
+		if seen && known && before == status {
+			continue
+		}
+		if !seen && !s.Initial {
+			continue
+		}
+		event := StreamEvent{
+			Type:       StreamEventStatusChanged,
+			Time:       now,
+			Profile:    profile,
+			SessionID:  inst.ID,
+			Title:      inst.Title,
+			Tool:       inst.Tool,
+			Status:     string(status),
+			PrevStatus: string(before),
+		}
+		events = append(events, event)
+
+		if _, isConductor := ConductorNameFromTitle(inst.Title); status == StatusWaiting && !isConductor && !acked[inst.ID] {
+			event.Type = StreamEventEscalation
+			events = append(events, event)
+		}
+	}
+	s.statuses[profile] = current
+	s.risks[profile] = currentRisks
+	return events
+}
+
+// ObserveHeartbeats reports heartbeat results recorded since the last call.
+// Results that existed before the first call are the baseline.
+func (s *EventStream) ObserveHeartbeats(results []HeartbeatResult, first bool) []StreamEvent {
+	var events []StreamEvent
+	for _, r := range results {
+		last, ok := s.heartbeats[r.Conductor]
+		if ok && !r.Time.After(last) {
+			continue
+		}
+		s.heartbeats[r.Conductor] = r.Time
+		if first {
+			continue
+		}
+		events = append(events, StreamEvent{
+			Type:      StreamEventHeartbeat,
+			Time:      r.Time,
+			Profile:   r.Profile,
+			SessionID: r.SessionID,
+			Conductor: r.Conductor,
+			Result:    r.Result,
+			Error:     r.Error,
+		})
+	}
+	return events
+}
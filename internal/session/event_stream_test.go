@@ -0,0 +1,87 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventStreamStatuses(t *testing.T) {
+	now := time.Now()
+	stream := NewEventStream()
+	conductor := &Instance{ID: "c1", Title: ConductorSessionTitle("ops"), Status: StatusRunning}
+	api := &Instance{ID: "s1", Title: "api", Tool: "claude", Status: StatusRunning}
+	acked := &Instance{ID: "s2", Title: "acked", Status: StatusRunning}
+	instances := []*Instance{conductor, api, acked}
+
+	if events := stream.ObserveStatuses("work", instances, nil, now); len(events) != 0 {
+		t.Fatalf("baseline emitted %v", events)
+	}
+
+	conductor.Status = StatusWaiting
+	api.Status = StatusWaiting
+	acked.Status = StatusWaiting
+	web := &Instance{ID: "s3", Title: "web", Status: StatusIdle}
+	instances = append(instances, web)
+	events := stream.ObserveStatuses("work", instances, map[string]bool{"s2": true}, now)
+
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type+":"+e.SessionID)
+	}
+	want := []string{"status_changed:c1", "status_changed:s1", "escalation:s1", "status_changed:s2", "status_changed:s3"}
+	if len(types) != len(want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("events = %v, want %v", types, want)
+		}
+	}
+	if e := events[1]; e.PrevStatus != "running" || e.Status != "waiting" || e.Profile != "work" || e.Tool != "claude" {
+		t.Errorf("status_changed = %+v", e)
+	}
+	if events[4].PrevStatus != "" {
+		t.Errorf("new session prev_status = %q, want empty", events[4].PrevStatus)
+	}
+
+	if events := stream.ObserveStatuses("work", instances, nil, now); len(events) != 0 {
+		t.Errorf("unchanged statuses emitted %v", events)
+	}
+
+	initial := NewEventStream()
+	initial.Initial = true
+	if events := initial.ObserveStatuses("work", []*Instance{web}, nil, now); len(events) != 1 {
+		t.Errorf("Initial emitted %v, want the current status", events)
+	}
+}
+
+func TestEventStreamHeartbeats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AGENT_DECK_HOME", "")
+	t.Setenv("AGENT_DECK_NAME", "")
+
+	start := time.Now().Add(-time.Minute).Truncate(time.Second)
+	if err := WriteHeartbeatResult(HeartbeatResult{Conductor: "ops", Result: HeartbeatSent, Time: start}); err != nil {
+		t.Fatal(err)
+	}
+	stream := NewEventStream()
+	results, err := ReadHeartbeatResults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events := stream.ObserveHeartbeats(results, true); len(events) != 0 {
+		t.Fatalf("baseline emitted %v", events)
+	}
+
+	if err := WriteHeartbeatResult(HeartbeatResult{Conductor: "ops", Profile: "work", Result: HeartbeatFailed, Error: "timeout", Time: start.Add(30 * time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	results, _ = ReadHeartbeatResults()
+	events := stream.ObserveHeartbeats(results, false)
+	if len(events) != 1 || events[0].Type != StreamEventHeartbeat || events[0].Result != HeartbeatFailed || events[0].Error != "timeout" {
+		t.Fatalf("events = %+v", events)
+	}
+	if events := stream.ObserveHeartbeats(results, false); len(events) != 0 {
+		t.Errorf("repeated result emitted %v", events)
+	}
+}
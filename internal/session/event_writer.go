@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 )
 
 // StatusEvent represents a session status change event.
@@ -23,11 +25,11 @@ type StatusEvent struct {
 
 // GetEventsDir returns the path to the events directory.
 func GetEventsDir() string {
-	home, err := os.UserHomeDir()
+	home, err := deck.Home()
 	if err != nil {
 		return filepath.Join(os.TempDir(), ".agent-deck", "events")
 	}
-	return filepath.Join(home, ".agent-deck", "events")
+	return filepath.Join(home, "events")
 }
 
 // WriteStatusEvent atomically writes a status event to the events directory.
@@ -0,0 +1,147 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// Feature flags gate experimental subsystems so they can ship dark and be
+// turned on selectively. A flag is resolved from, in order of precedence:
+// the session's own override, [profiles.<name>.features], [features], and
+// finally the default (off).
+const (
+	// FeatureAutoRestart restarts sessions whose tmux session died
+	FeatureAutoRestart = "auto-restart"
+)
+
+// Feature flag sources, reported by ResolveFeatures
+const (
+	FeatureSourceDefault = "default"
+	FeatureSourceDeck    = "deck"
+	FeatureSourceProfile = "profile"
+	FeatureSourceSession = "session"
+)
+
+// ErrUnknownFeature is returned for names not in FeatureFlags
+var ErrUnknownFeature = errors.New("unknown feature flag")
+
+// FeatureFlag describes an experimental subsystem behind a flag
+type FeatureFlag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// FeatureFlags lists every known flag
+var FeatureFlags = []FeatureFlag{
+	{Name: FeatureAutoRestart, Description: "Restart sessions whose tmux session died (TUI, outside maintenance windows)"},
+}
+
+// FeatureState is a flag's effective value and where it came from
+type FeatureState struct {
+	FeatureFlag
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
+}
+
+// ValidateFeature returns ErrUnknownFeature for names not in FeatureFlags
+func ValidateFeature(name string) error {
+	for _, f := range FeatureFlags {
+		if f.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFeature, name)
+}
+
+// ResolveFeatures returns the effective state of every flag for profile,
+// with overrides (a session's own flags, may be nil) taking precedence
+func ResolveFeatures(profile string, overrides map[string]bool) []FeatureState {
+	var deck, profileFlags map[string]bool
+	if config, err := LoadUserConfig(); err == nil && config != nil {
+		deck = config.Features
+		if p, ok := config.Profiles[GetEffectiveProfile(profile)]; ok {
+			profileFlags = p.Features
+		}
+	}
+
+	states := make([]FeatureState, len(FeatureFlags))
+	for i, f := range FeatureFlags {
+		state := FeatureState{FeatureFlag: f, Source: FeatureSourceDefault}
+		if v, ok := deck[f.Name]; ok {
+			state.Enabled, state.Source = v, FeatureSourceDeck
+		}
+		if v, ok := profileFlags[f.Name]; ok {
+			state.Enabled, state.Source = v, FeatureSourceProfile
+		}
+		if v, ok := overrides[f.Name]; ok {
+			state.Enabled, state.Source = v, FeatureSourceSession
+		}
+		states[i] = state
+	}
+	return states
+}
+
+// FeatureEnabled reports whether a flag is on for profile
+func FeatureEnabled(profile, name string) bool {
+	return featureEnabled(ResolveFeatures(profile, nil), name)
+}
+
+// FeatureEnabled reports whether a flag is on for this session
+func (i *Instance) FeatureEnabled(name string) bool {
+	return featureEnabled(ResolveFeatures("", i.Features), name)
+}
+
+func featureEnabled(states []FeatureState, name string) bool {
+	for _, s := range states {
+		if s.Name == name {
+			return s.Enabled
+		}
+	}
+	return false
+}
+
+// SetFeatureOverride sets (value non-nil) or clears (nil) the session's own
+// value for a flag; the caller saves the instance
+func (i *Instance) SetFeatureOverride(name string, value *bool) error {
+	if err := ValidateFeature(name); err != nil {
+		return err
+	}
+	if value == nil {
+		delete(i.Features, name)
+		if len(i.Features) == 0 {
+			i.Features = nil
+		}
+		return nil
+	}
+	if i.Features == nil {
+		i.Features = make(map[string]bool)
+	}
+	i.Features[name] = *value
+	return nil
+}
+
+// SetDeckFeature sets (value non-nil) or clears (nil) a flag in [features]
+func SetDeckFeature(name string, value *bool) error {
+	if err := ValidateFeature(name); err != nil {
+		return err
+	}
+	config, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		config = &UserConfig{}
+	}
+	features := maps.Clone(config.Features)
+	if value == nil {
+		delete(features, name)
+	} else {
+		if features == nil {
+			features = make(map[string]bool)
+		}
+		features[name] = *value
+	}
+	config.Features = features
+	return SaveUserConfig(config)
+}
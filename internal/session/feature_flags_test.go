@@ -0,0 +1,104 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFeatureConfig(t *testing.T, content string) {
+	t.Helper()
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".agent-deck")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+}
+
+func featureState(t *testing.T, states []FeatureState, name string) FeatureState {
+	t.Helper()
+	for _, s := range states {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("feature %s missing from %+v", name, states)
+	return FeatureState{}
+}
+
+func TestResolveFeatures_Precedence(t *testing.T) {
+	writeFeatureConfig(t, `
+[features]
+auto-restart = true
+
+[profiles.work.features]
+auto-restart = false
+`)
+
+	if s := featureState(t, ResolveFeatures("default", nil), FeatureAutoRestart); !s.Enabled || s.Source != FeatureSourceDeck {
+		t.Errorf("default profile: auto-restart = %+v, want on from deck", s)
+	}
+	if s := featureState(t, ResolveFeatures("work", nil), FeatureAutoRestart); s.Enabled || s.Source != FeatureSourceProfile {
+		t.Errorf("work profile: auto-restart = %+v, want off from profile", s)
+	}
+	states := ResolveFeatures("work", map[string]bool{FeatureAutoRestart: true})
+	if s := featureState(t, states, FeatureAutoRestart); !s.Enabled || s.Source != FeatureSourceSession {
+		t.Errorf("session override: auto-restart = %+v, want on from session", s)
+	}
+
+	if !FeatureEnabled("default", FeatureAutoRestart) {
+		t.Error("auto-restart should be on for profiles without an override")
+	}
+	if FeatureEnabled("work", FeatureAutoRestart) {
+		t.Error("auto-restart should be off for the work profile")
+	}
+}
+
+func TestSetFeatureOverride(t *testing.T) {
+	writeFeatureConfig(t, "")
+
+	inst := NewInstance("flags", t.TempDir())
+	on := true
+	if err := inst.SetFeatureOverride(FeatureAutoRestart, &on); err != nil {
+		t.Fatal(err)
+	}
+	if !inst.FeatureEnabled(FeatureAutoRestart) {
+		t.Error("session override should enable auto-restart")
+	}
+	if err := inst.SetFeatureOverride(FeatureAutoRestart, nil); err != nil {
+		t.Fatal(err)
+	}
+	if inst.Features != nil || inst.FeatureEnabled(FeatureAutoRestart) {
+		t.Errorf("reset should clear the override, got %v", inst.Features)
+	}
+	if err := inst.SetFeatureOverride("warp-drive", &on); !errors.Is(err, ErrUnknownFeature) {
+		t.Errorf("unknown flag: err = %v", err)
+	}
+}
+
+func TestSetDeckFeature(t *testing.T) {
+	writeFeatureConfig(t, "")
+
+	on := true
+	if err := SetDeckFeature(FeatureAutoRestart, &on); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+	if !FeatureEnabled("", FeatureAutoRestart) {
+		t.Fatal("auto-restart should be on after SetDeckFeature")
+	}
+	if err := SetDeckFeature(FeatureAutoRestart, nil); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+	if s := featureState(t, ResolveFeatures("", nil), FeatureAutoRestart); s.Source != FeatureSourceDefault {
+		t.Errorf("after reset: %+v", s)
+	}
+}
@@ -0,0 +1,153 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Conductor states reported in the fleet view beyond the session statuses
+const (
+	FleetStateStopped   = "stopped"    // session registered but not running
+	FleetStateNoSession = "no_session" // meta.json exists but no session was registered
+)
+
+// FleetEscalation is a session waiting on the operator that nobody has acknowledged
+type FleetEscalation struct {
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title"`
+	Since     time.Time `json:"since"`
+}
+
+// FleetProfile rolls up the conductors and sessions of one profile
+type FleetProfile struct {
+	Profile    string         `json:"profile"`
+	Conductors int            `json:"conductors"`
+	States     map[string]int `json:"states"`
+	// UnackedEscalations counts non-conductor sessions waiting without an ack
+	UnackedEscalations int              `json:"unacked_escalations"`
+	OldestEscalation   *FleetEscalation `json:"oldest_escalation,omitempty"`
+	// SpendToday is the estimated USD spend since local midnight (Claude sessions only)
+	SpendToday float64 `json:"spend_today"`
+}
+
+// FleetSummary is the at-a-glance view across every profile that has conductors
+type FleetSummary struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Profiles    []FleetProfile `json:"profiles"`
+}
+
+// BuildFleetSummary loads each conductor profile and summarizes it. Profiles
+// that fail to load are reported with an "error" state rather than aborting.
+func BuildFleetSummary(now time.Time) (*FleetSummary, error) {
+	conductors, err := ListConductors()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conductors: %w", err)
+	}
+
+	byProfile := make(map[string][]ConductorMeta)
+	for _, meta := range conductors {
+		profile := normalizeConductorProfile(meta.Profile)
+		byProfile[profile] = append(byProfile[profile], meta)
+	}
+	profiles := make([]string, 0, len(byProfile))
+	for profile := range byProfile {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	summary := &FleetSummary{GeneratedAt: now}
+	for _, profile := range profiles {
+		fp, err := loadFleetProfile(profile, byProfile[profile], now)
+		if err != nil {
+			fp = FleetProfile{
+				Profile:    profile,
+				Conductors: len(byProfile[profile]),
+				States:     map[string]int{string(StatusError): len(byProfile[profile])},
+			}
+		}
+		summary.Profiles = append(summary.Profiles, fp)
+	}
+	return summary, nil
+}
+
+func loadFleetProfile(profile string, conductors []ConductorMeta, now time.Time) (FleetProfile, error) {
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return FleetProfile{}, err
+	}
+	defer storage.Close()
+	instances, _, err := storage.LoadWithGroups()
+	if err != nil {
+		return FleetProfile{}, err
+	}
+
+	acked := make(map[string]bool)
+	if db := storage.GetDB(); db != nil {
+		if rows, err := db.ReadAllStatuses(); err == nil {
+			for id, row := range rows {
+				acked[id] = row.Acknowledged
+			}
+		}
+	}
+	for _, inst := range instances {
+		_ = inst.UpdateStatus()
+	}
+
+	midnight := startOfDay(now)
+	spend := func(inst *Instance) float64 { return instanceSpendSince(inst, midnight) }
+	return summarizeFleetProfile(profile, conductors, instances, acked, now, spend), nil
+}
+
+// summarizeFleetProfile builds the rollup for one profile from loaded data
+func summarizeFleetProfile(profile string, conductors []ConductorMeta, instances []*Instance, acked map[string]bool, now time.Time, spend func(*Instance) float64) FleetProfile {
+	fp := FleetProfile{
+		Profile:    profile,
+		Conductors: len(conductors),
+		States:     make(map[string]int),
+	}
+
+	byTitle := make(map[string]*Instance, len(instances))
+	for _, inst := range instances {
+		byTitle[inst.Title] = inst
+	}
+	for _, meta := range conductors {
+		inst := byTitle[ConductorSessionTitle(meta.Name)]
+		switch {
+		case inst == nil:
+			fp.States[FleetStateNoSession]++
+		case inst.GetStatusThreadSafe() == StatusError:
+			// Matches conductor list: an errored conductor session is a dead tmux session
+			fp.States[FleetStateStopped]++
+		default:
+			fp.States[string(inst.GetStatusThreadSafe())]++
+		}
+	}
+
+	for _, inst := range instances {
+		if spend != nil {
+			fp.SpendToday += spend(inst)
+		}
+		if _, isConductor := ConductorNameFromTitle(inst.Title); isConductor {
+			continue
+		}
+		if inst.GetStatusThreadSafe() != StatusWaiting || acked[inst.ID] {
+			continue
+		}
+		fp.UnackedEscalations++
+		since := inst.StateSince()
+		if since.IsZero() {
+			since = now
+		}
+		if fp.OldestEscalation == nil || since.Before(fp.OldestEscalation.Since) {
+			fp.OldestEscalation = &FleetEscalation{SessionID: inst.ID, Title: inst.Title, Since: since}
+		}
+	}
+	return fp
+}
+
+// startOfDay returns local midnight for t
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
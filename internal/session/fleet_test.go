@@ -0,0 +1,68 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummarizeFleetProfile(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	conductors := []ConductorMeta{{Name: "ops"}, {Name: "infra"}, {Name: "docs"}}
+	instances := []*Instance{
+		{ID: "c1", Title: ConductorSessionTitle("ops"), Status: StatusRunning},
+		{ID: "c2", Title: ConductorSessionTitle("infra"), Status: StatusError},
+		{ID: "s1", Title: "api", Status: StatusWaiting, stateSince: now.Add(-40 * time.Minute)},
+		{ID: "s2", Title: "web", Status: StatusWaiting, stateSince: now.Add(-10 * time.Minute)},
+		{ID: "s3", Title: "acked", Status: StatusWaiting, stateSince: now.Add(-2 * time.Hour)},
+		{ID: "s4", Title: "busy", Status: StatusRunning},
+	}
+	acked := map[string]bool{"s3": true}
+	spend := func(inst *Instance) float64 {
+		if inst.ID == "s4" {
+			return 1.5
+		}
+		return 0.25
+	}
+
+	fp := summarizeFleetProfile("work", conductors, instances, acked, now, spend)
+	if fp.Conductors != 3 {
+		t.Errorf("conductors = %d, want 3", fp.Conductors)
+	}
+	if fp.States["running"] != 1 || fp.States[FleetStateStopped] != 1 || fp.States[FleetStateNoSession] != 1 {
+		t.Errorf("unexpected states: %v", fp.States)
+	}
+	if fp.UnackedEscalations != 2 {
+		t.Errorf("unacked = %d, want 2", fp.UnackedEscalations)
+	}
+	if fp.OldestEscalation == nil || fp.OldestEscalation.SessionID != "s1" {
+		t.Errorf("oldest escalation = %+v, want s1", fp.OldestEscalation)
+	}
+	if fp.SpendToday != 1.5+5*0.25 {
+		t.Errorf("spend = %v, want 2.75", fp.SpendToday)
+	}
+}
+
+func TestSpendSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	lines := `{"type":"assistant","timestamp":"2026-03-09T23:00:00Z","message":{"usage":{"output_tokens":1000000}}}
+{"type":"assistant","timestamp":"2026-03-10T08:00:00Z","message":{"usage":{"input_tokens":1000000}}}
+{"type":"user","timestamp":"2026-03-10T08:01:00Z","message":{"usage":{"input_tokens":1000000}}}
+not json
+`
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cost, err := SpendSince(path, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("SpendSince: %v", err)
+	}
+	if want := modelPricing["default"].Input; cost != want {
+		t.Errorf("cost = %v, want %v (one million input tokens today)", cost, want)
+	}
+	tokens, err := TokensSince(path, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil || tokens != 1000000 {
+		t.Errorf("TokensSince = %d, %v; want 1000000", tokens, err)
+	}
+}
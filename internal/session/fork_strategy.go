@@ -0,0 +1,241 @@
+package session
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ForkStrategy forks the conversation of a tool that has no native fork
+// command. Claude (--fork-session, see its ToolAdapter) and OpenCode
+// (export/import) are built in.
+type ForkStrategy interface {
+	// CanFork reports whether parent has a conversation that can be forked
+	CanFork(parent *Instance) bool
+
+	// Fork copies parent's conversation for forked, a new instance of the
+	// same tool in its project path, and sets forked's command to resume it
+	Fork(parent, forked *Instance) error
+}
+
+var (
+	forkStrategiesMu sync.RWMutex
+	forkStrategies   = map[string]ForkStrategy{
+		"codex":  codexForkStrategy{},
+		"gemini": geminiForkStrategy{},
+		"aider":  aiderForkStrategy{},
+	}
+)
+
+// RegisterForkStrategy sets the fork strategy of a tool, replacing any
+// existing one. A nil strategy makes the tool unforkable.
+func RegisterForkStrategy(tool string, strategy ForkStrategy) {
+	forkStrategiesMu.Lock()
+	defer forkStrategiesMu.Unlock()
+	if strategy == nil {
+		delete(forkStrategies, tool)
+		return
+	}
+	forkStrategies[tool] = strategy
+}
+
+func forkStrategyFor(tool string) ForkStrategy {
+	forkStrategiesMu.RLock()
+	defer forkStrategiesMu.RUnlock()
+	return forkStrategies[tool]
+}
+
+// ToolSupportsFork reports whether sessions of tool can be forked at all
+func ToolSupportsFork(tool string) bool {
+	if adapter := toolAdapterFor(tool); adapter != nil {
+		return adapter.SupportsFork(nil)
+	}
+	return tool == "opencode" || forkStrategyFor(tool) != nil
+}
+
+// createForkedInstanceWithStrategy forks a session through its tool's
+// ForkStrategy. opts only supplies the worktree to fork into.
+func (i *Instance) createForkedInstanceWithStrategy(strategy ForkStrategy, newTitle, newGroupPath string, opts *ClaudeOptions) (*Instance, string, error) {
+	if !strategy.CanFork(i) {
+		return nil, "", fmt.Errorf("cannot fork: no %s conversation to fork", i.Tool)
+	}
+
+	projectPath := i.ProjectPath
+	if opts != nil && opts.WorkDir != "" {
+		projectPath = opts.WorkDir
+	}
+	forked := NewInstanceWithTool(newTitle, projectPath, i.Tool)
+	if newGroupPath != "" {
+		forked.GroupPath = newGroupPath
+	} else {
+		forked.GroupPath = i.GroupPath
+	}
+	forked.Wrapper = i.Wrapper
+	forked.ToolOptionsJSON = i.ToolOptionsJSON
+	forked.SetStartReason(StartReasonFork, i.ID)
+	countFork()
+	forked.NetworkPolicy = i.NetworkPolicy // a fork must not escape the parent's sandbox
+	forked.Features = maps.Clone(i.Features)
+	if opts != nil && opts.WorktreePath != "" {
+		forked.WorktreePath = opts.WorktreePath
+		forked.WorktreeRepoRoot = opts.WorktreeRepoRoot
+		forked.WorktreeBranch = opts.WorktreeBranch
+	}
+
+	if err := strategy.Fork(i, forked); err != nil {
+		return nil, "", fmt.Errorf("fork %s session: %w", i.Tool, err)
+	}
+	return forked, forked.Command, nil
+}
+
+// newSessionUUID returns a random (version 4) UUID, the session ID format
+// of Codex and Gemini CLI
+func newSessionUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// copyWithReplace writes src to dst with every occurrence of oldID (if set)
+// replaced by newID
+func copyWithReplace(src, dst, oldID, newID string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if oldID != "" {
+		data = []byte(strings.ReplaceAll(string(data), oldID, newID))
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o600)
+}
+
+// codexForkStrategy copies the parent's rollout file
+// (~/.codex/sessions/YYYY/MM/DD/rollout-<ts>-<uuid>.jsonl) under a new
+// session ID, which the fork then resumes with the parent's command line and
+// "resume <id>"
+type codexForkStrategy struct{}
+
+func (codexForkStrategy) CanFork(parent *Instance) bool {
+	return parent.CodexSessionID != "" && findCodexRollout(parent.CodexSessionID) != ""
+}
+
+func (codexForkStrategy) Fork(parent, forked *Instance) error {
+	src := findCodexRollout(parent.CodexSessionID)
+	if src == "" {
+		return fmt.Errorf("codex session %s not found", parent.CodexSessionID)
+	}
+	now := time.Now()
+	newID := newSessionUUID()
+	dst := filepath.Join(getCodexHomeDir(), "sessions", now.Format("2006/01/02"),
+		fmt.Sprintf("rollout-%s-%s.jsonl", now.Format("2006-01-02T15-04-05"), newID))
+	if err := copyWithReplace(src, dst, parent.CodexSessionID, newID); err != nil {
+		return err
+	}
+	// Plain "codex" is resumed by buildCodexCommand with the session's flags;
+	// a customised command line keeps its own and resumes explicitly
+	forked.Command = "codex"
+	if base := strings.TrimSpace(parent.Command); base != "" && base != "codex" {
+		forked.Command = fmt.Sprintf("%s resume %s", base, newID)
+	}
+	forked.CodexSessionID = newID
+	forked.CodexDetectedAt = now
+	return nil
+}
+
+// findCodexRollout returns the rollout file of a Codex session, or ""
+func findCodexRollout(sessionID string) string {
+	var found string
+	_ = filepath.WalkDir(filepath.Join(getCodexHomeDir(), "sessions"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), sessionID+".jsonl") {
+			found = path
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// geminiForkStrategy copies the parent's chat file
+// (~/.gemini/tmp/<project_hash>/chats/session-<ts>-<uuid8>.json) into the
+// fork's project under a new session ID, resumed with "gemini --resume <id>"
+type geminiForkStrategy struct{}
+
+func (geminiForkStrategy) CanFork(parent *Instance) bool {
+	return parent.GeminiSessionID != "" && findGeminiSessionFile(parent) != ""
+}
+
+func (geminiForkStrategy) Fork(parent, forked *Instance) error {
+	src := findGeminiSessionFile(parent)
+	if src == "" {
+		return fmt.Errorf("gemini session %s not found", parent.GeminiSessionID)
+	}
+	sessionsDir := GetGeminiSessionsDir(forked.ProjectPath)
+	if sessionsDir == "" {
+		return fmt.Errorf("cannot determine gemini sessions dir for %s", forked.ProjectPath)
+	}
+	now := time.Now()
+	newID := newSessionUUID()
+	dst := filepath.Join(sessionsDir, fmt.Sprintf("session-%s-%s.json", now.Format("2006-01-02T15-04"), newID[:8]))
+	if err := copyWithReplace(src, dst, parent.GeminiSessionID, newID); err != nil {
+		return err
+	}
+	forked.Command = "gemini"
+	forked.GeminiSessionID = newID
+	forked.GeminiDetectedAt = now
+	forked.GeminiYoloMode = parent.GeminiYoloMode
+	forked.GeminiModel = parent.GeminiModel
+	return nil
+}
+
+// findGeminiSessionFile returns the chat file of a Gemini session, looking in
+// its project first and then in every project
+func findGeminiSessionFile(inst *Instance) string {
+	if len(inst.GeminiSessionID) < 8 {
+		return ""
+	}
+	if dir := GetGeminiSessionsDir(inst.ProjectPath); dir != "" {
+		if path, _ := findNewestFile(filepath.Join(dir, "session-*-"+inst.GeminiSessionID[:8]+".json")); path != "" {
+			return path
+		}
+	}
+	return findGeminiSessionInAllProjects(inst.GeminiSessionID)
+}
+
+// aiderForkStrategy copies the parent's chat history
+// (.aider.chat.history.md, or its --chat-history-file) to a history file of
+// the fork's own, which aider replays with --restore-chat-history. The copy
+// matches the .aider* pattern aider adds to .gitignore.
+type aiderForkStrategy struct{}
+
+func (aiderForkStrategy) CanFork(parent *Instance) bool {
+	_, err := os.Stat(aiderHistoryFile(parent))
+	return err == nil
+}
+
+func (aiderForkStrategy) Fork(parent, forked *Instance) error {
+	dst := filepath.Join(forked.ProjectPath, ".aider.chat.history.fork-"+forked.ID[:8]+".md")
+	if err := copyWithReplace(aiderHistoryFile(parent), dst, "", ""); err != nil {
+		return err
+	}
+	base := strings.TrimSpace(parent.Command)
+	if base == "" {
+		base = "aider"
+	}
+	// aider takes the last --chat-history-file, overriding the parent's
+	forked.Command = fmt.Sprintf("%s --chat-history-file '%s' --restore-chat-history", base, dst)
+	return nil
+}
@@ -0,0 +1,198 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForkStrategy_Codex(t *testing.T) {
+	codexHome := t.TempDir()
+	t.Setenv("CODEX_HOME", codexHome)
+
+	parentID := "11111111-2222-4333-8444-555555555555"
+	rolloutDir := filepath.Join(codexHome, "sessions", "2026", "01", "02")
+	if err := os.MkdirAll(rolloutDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	rollout := `{"type":"session_meta","payload":{"id":"` + parentID + `","cwd":"/tmp"}}` + "\n"
+	if err := os.WriteFile(filepath.Join(rolloutDir, "rollout-2026-01-02T10-00-00-"+parentID+".jsonl"), []byte(rollout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parent := NewInstanceWithTool("fork-parent", "/tmp", "codex")
+	if parent.CanFork() {
+		t.Fatal("Codex session without a session ID should not be forkable")
+	}
+	parent.CodexSessionID = parentID
+	parent.CodexDetectedAt = time.Now()
+	if !parent.CanFork() {
+		t.Fatal("Codex session with a rollout file should be forkable")
+	}
+
+	forked, cmd, err := parent.CreateForkedInstance("fork-child", "")
+	if err != nil {
+		t.Fatalf("CreateForkedInstance failed: %v", err)
+	}
+	if forked.Tool != "codex" || cmd != "codex" {
+		t.Errorf("forked tool/command = %s/%q, want codex/codex", forked.Tool, cmd)
+	}
+	if forked.CodexSessionID == "" || forked.CodexSessionID == parentID {
+		t.Fatalf("forked session ID = %q, want a new ID", forked.CodexSessionID)
+	}
+
+	// The fork resumes its own copy of the rollout
+	copyPath := findCodexRollout(forked.CodexSessionID)
+	if copyPath == "" {
+		t.Fatal("forked rollout file not written")
+	}
+	data, _ := os.ReadFile(copyPath)
+	if strings.Contains(string(data), parentID) || !strings.Contains(string(data), forked.CodexSessionID) {
+		t.Errorf("forked rollout should carry only the new ID: %s", data)
+	}
+	if got := forked.buildCodexCommand(forked.Command); !strings.Contains(got, "resume "+forked.CodexSessionID) {
+		t.Errorf("forked start command should resume the fork: %s", got)
+	}
+}
+
+func TestForkStrategy_CodexKeepsParentCommand(t *testing.T) {
+	codexHome := t.TempDir()
+	t.Setenv("CODEX_HOME", codexHome)
+
+	parentID := "11111111-2222-4333-8444-666666666666"
+	rolloutDir := filepath.Join(codexHome, "sessions", "2026", "01", "02")
+	if err := os.MkdirAll(rolloutDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	rollout := `{"type":"session_meta","payload":{"id":"` + parentID + `","cwd":"/tmp"}}` + "\n"
+	if err := os.WriteFile(filepath.Join(rolloutDir, "rollout-2026-01-02T10-00-00-"+parentID+".jsonl"), []byte(rollout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parent := NewInstanceWithTool("fork-parent", "/tmp", "codex")
+	parent.Command = "codex --search -c model_reasoning_effort=high"
+	parent.Wrapper = "nice -n 10 {command}"
+	parent.CodexSessionID = parentID
+	parent.CodexDetectedAt = time.Now()
+
+	forked, cmd, err := parent.CreateForkedInstance("fork-child", "")
+	if err != nil {
+		t.Fatalf("CreateForkedInstance failed: %v", err)
+	}
+	want := "codex --search -c model_reasoning_effort=high resume " + forked.CodexSessionID
+	if cmd != want {
+		t.Errorf("fork command = %q, want %q", cmd, want)
+	}
+	if forked.Wrapper != parent.Wrapper {
+		t.Errorf("fork wrapper = %q, want the parent's %q", forked.Wrapper, parent.Wrapper)
+	}
+	if got := forked.buildCodexCommand(forked.Command); !strings.HasSuffix(got, want) {
+		t.Errorf("forked start command should run the parent's command line: %s", got)
+	}
+}
+
+func TestForkStrategy_Gemini(t *testing.T) {
+	geminiConfigDirOverride = t.TempDir()
+	defer func() { geminiConfigDirOverride = "" }()
+
+	projectPath := t.TempDir()
+	parentID := "abcd1234-2222-4333-8444-555555555555"
+	chats := GetGeminiSessionsDir(projectPath)
+	if err := os.MkdirAll(chats, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	chat := `{"sessionId":"` + parentID + `","startTime":"2026-01-02T10:00:00Z","messages":[{}]}`
+	if err := os.WriteFile(filepath.Join(chats, "session-2026-01-02T10-00-abcd1234.json"), []byte(chat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parent := NewInstanceWithTool("fork-parent", projectPath, "gemini")
+	parent.GeminiSessionID = parentID
+	parent.GeminiModel = "gemini-2.5-pro"
+
+	forked, _, err := parent.CreateForkedInstance("fork-child", "")
+	if err != nil {
+		t.Fatalf("CreateForkedInstance failed: %v", err)
+	}
+	if forked.Tool != "gemini" || forked.Command != "gemini" {
+		t.Errorf("forked tool/command = %s/%q, want gemini/gemini", forked.Tool, forked.Command)
+	}
+	if forked.GeminiSessionID == "" || forked.GeminiSessionID == parentID {
+		t.Fatalf("forked session ID = %q, want a new ID", forked.GeminiSessionID)
+	}
+	if forked.GeminiModel != parent.GeminiModel {
+		t.Errorf("forked model = %q, want %q", forked.GeminiModel, parent.GeminiModel)
+	}
+
+	sessions, err := ListGeminiSessions(projectPath)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("ListGeminiSessions() = %v, %v; want parent and fork", sessions, err)
+	}
+	if got := forked.buildGeminiCommand(forked.Command); !strings.Contains(got, "--resume "+forked.GeminiSessionID) {
+		t.Errorf("forked start command should resume the fork: %s", got)
+	}
+}
+
+func TestForkStrategy_Aider(t *testing.T) {
+	projectPath := t.TempDir()
+	parent := NewInstanceWithTool("fork-parent", projectPath, "aider")
+	parent.Command = "aider --model sonnet"
+	if parent.CanFork() {
+		t.Fatal("aider session without chat history should not be forkable")
+	}
+	history := "# aider chat started\n\n#### fix the tests\n"
+	if err := os.WriteFile(filepath.Join(projectPath, ".aider.chat.history.md"), []byte(history), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !parent.CanFork() {
+		t.Fatal("aider session with chat history should be forkable")
+	}
+
+	forked, cmd, err := parent.CreateForkedInstance("fork-child", "")
+	if err != nil {
+		t.Fatalf("CreateForkedInstance failed: %v", err)
+	}
+	if !strings.HasPrefix(cmd, "aider --model sonnet ") || !strings.Contains(cmd, "--restore-chat-history") {
+		t.Errorf("fork command should keep the parent's flags and restore history: %s", cmd)
+	}
+	forkHistory := aiderHistoryFile(forked)
+	if forkHistory == filepath.Join(projectPath, ".aider.chat.history.md") {
+		t.Fatal("fork should get its own chat history file")
+	}
+	data, err := os.ReadFile(forkHistory)
+	if err != nil || string(data) != history {
+		t.Errorf("fork history = %q, %v; want the parent's", data, err)
+	}
+}
+
+type fakeForkStrategy struct{}
+
+func (fakeForkStrategy) CanFork(parent *Instance) bool { return true }
+
+func (fakeForkStrategy) Fork(parent, forked *Instance) error {
+	forked.Command = "fake --resume-copy"
+	return nil
+}
+
+func TestRegisterForkStrategy(t *testing.T) {
+	if ToolSupportsFork("fake") {
+		t.Fatal("unregistered tool should not support fork")
+	}
+	RegisterForkStrategy("fake", fakeForkStrategy{})
+	defer RegisterForkStrategy("fake", nil)
+
+	parent := NewInstanceWithTool("fork-parent", t.TempDir(), "fake")
+	parent.NetworkPolicy = "offline"
+	forked, cmd, err := parent.CreateForkedInstance("fork-child", "workers")
+	if err != nil {
+		t.Fatalf("CreateForkedInstance failed: %v", err)
+	}
+	if cmd != "fake --resume-copy" || forked.Tool != "fake" || forked.GroupPath != "workers" {
+		t.Errorf("forked = %s/%q in %s", forked.Tool, cmd, forked.GroupPath)
+	}
+	if forked.NetworkPolicy != parent.NetworkPolicy {
+		t.Errorf("fork must keep the parent's network policy, got %q", forked.NetworkPolicy)
+	}
+}
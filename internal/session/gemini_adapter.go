@@ -0,0 +1,93 @@
+package session
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// geminiAdapter is the ToolAdapter of the Gemini CLI. The session ID is
+// found in Gemini's chat files under ~/.gemini/tmp once the user has sent a
+// message (see UpdateGeminiSession); restart resumes it with
+// "gemini --resume <id>".
+type geminiAdapter struct{}
+
+func (geminiAdapter) BuildStartCommand(inst *Instance, baseCommand string) string {
+	return inst.buildGeminiCommand(baseCommand)
+}
+
+// BuildResumeCommand resumes the known session; without one the session
+// starts fresh
+func (geminiAdapter) BuildResumeCommand(inst *Instance) string {
+	if inst.GeminiSessionID == "" {
+		return ""
+	}
+	return inst.buildGeminiCommand("gemini")
+}
+
+func (geminiAdapter) CanResume(inst *Instance) bool {
+	return inst.GeminiSessionID != ""
+}
+
+func (geminiAdapter) BusyPatterns() []string {
+	return tmux.DefaultRawPatterns("gemini").BusyPatterns
+}
+
+func (geminiAdapter) PromptReadyPatterns() []string {
+	return tmux.DefaultRawPatterns("gemini").PromptPatterns
+}
+
+// SupportsFork copies the chat file into a new session (see geminiForkStrategy)
+func (geminiAdapter) SupportsFork(inst *Instance) bool {
+	if inst == nil {
+		return true
+	}
+	return geminiForkStrategy{}.CanFork(inst)
+}
+
+// SyncSessionID always rescans: the user may have started a new chat
+// inside gemini since the ID was last seen
+func (geminiAdapter) SyncSessionID(inst *Instance) {
+	inst.UpdateGeminiSession(nil)
+}
+
+// CaptureSessionID has nothing to do: a fresh gemini has no chat file until
+// the first message, and UpdateStatus picks it up from then on
+func (geminiAdapter) CaptureSessionID(*Instance) {}
+
+// geminiThrottlePatterns match what the Gemini CLI prints while it backs off
+// after a rate limit (HTTP 429, RESOURCE_EXHAUSTED) or has run out of quota
+var geminiThrottlePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Attempt \d+ failed with status 429`),
+	regexp.MustCompile(`(?i)retrying (with|after) backoff`),
+	regexp.MustCompile(`RESOURCE_EXHAUSTED`),
+	regexp.MustCompile(`(?i)quota exceeded`),
+	regexp.MustCompile(`(?i)reached your daily .*quota limit`),
+	regexp.MustCompile(`(?i)rate limit(ing)? (detected|exceeded)`),
+	regexp.MustCompile(`(?i)possible quota limitations`),
+	regexp.MustCompile(`\b429 Too Many Requests\b`),
+}
+
+// geminiThrottleLines is how far up the pane a throttle message still counts;
+// older ones have scrolled past a later, successful turn
+const geminiThrottleLines = 12
+
+// ThrottleMessage returns the rate-limit or backoff line at the bottom of a
+// gemini pane
+func (geminiAdapter) ThrottleMessage(pane string) string {
+	lines := strings.Split(tmux.StripANSI(pane), "\n")
+	for i, seen := len(lines)-1, 0; i >= 0 && seen < geminiThrottleLines; i-- {
+		line := strings.Trim(lines[i], " │|╭╮╰╯─")
+		if line == "" {
+			continue
+		}
+		seen++
+		for _, re := range geminiThrottlePatterns {
+			if re.MatchString(line) {
+				return line
+			}
+		}
+	}
+	return ""
+}
@@ -457,3 +457,34 @@ func TestGetAvailableGeminiModels_UpdatedFallback(t *testing.T) {
 		}
 	}
 }
+
+func TestInstance_buildGeminiCommand_ProfileOverrides(t *testing.T) {
+	writeMacroConfig(t, `[gemini]
+default_model = "gemini-2.5-pro"
+checkpointing = true
+
+[profiles.work.gemini]
+default_model = "gemini-2.5-flash"
+yolo_mode = true
+`)
+	inst := &Instance{Tool: "gemini"}
+
+	t.Setenv("AGENTDECK_PROFILE", "personal")
+	got := inst.buildGeminiCommand("gemini")
+	if !strings.HasSuffix(got, "gemini --model gemini-2.5-pro --checkpointing") {
+		t.Errorf("global settings command = %q", got)
+	}
+
+	t.Setenv("AGENTDECK_PROFILE", "work")
+	got = inst.buildGeminiCommand("gemini")
+	if !strings.HasSuffix(got, "gemini --yolo --model gemini-2.5-flash --checkpointing") {
+		t.Errorf("profile override command = %q", got)
+	}
+
+	// Resumes keep the conversation's model but still checkpoint
+	inst.GeminiSessionID = "session-abc-123"
+	got = inst.buildGeminiCommand("gemini")
+	if !strings.HasSuffix(got, "gemini --resume session-abc-123 --yolo --checkpointing") {
+		t.Errorf("resume command = %q", got)
+	}
+}
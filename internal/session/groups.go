@@ -384,6 +384,13 @@ func (t *GroupTree) Flatten() []Item {
 				}
 			}
 
+			// Apply pins and the configured sort mode (manual keeps persisted Order)
+			sortSettings := GetSortSettings()
+			SortInstances(parentSessions, sortSettings)
+			for _, subs := range subSessionsByParent {
+				SortInstances(subs, sortSettings)
+			}
+
 			// Count total top-level items (parent sessions + orphan sub-sessions whose parent is in different group)
 			// For determining IsLastInGroup, we need to know how many top-level items there are
 			topLevelCount := len(parentSessions)
@@ -0,0 +1,266 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
+	"github.com/asheshgoplani/agent-deck/internal/platform"
+)
+
+// Heartbeat scheduler backends
+const (
+	HeartbeatBackendLaunchd = "launchd"
+	HeartbeatBackendSystemd = "systemd"
+	HeartbeatBackendCron    = "cron"
+)
+
+// crontabCommand is the crontab binary; tests point it at a fake
+var crontabCommand = "crontab"
+
+// cronAvailable reports whether a user crontab can be edited
+func cronAvailable() bool {
+	_, err := exec.LookPath(crontabCommand)
+	return err == nil
+}
+
+// HeartbeatBackend returns the scheduler conductor heartbeats are installed
+// with: launchd on macOS; on Linux, [conductor] heartbeat_backend when set,
+// else systemd when a user session is usable and cron otherwise (containers,
+// shared hosts without a user bus). Empty when none is available.
+func HeartbeatBackend() string {
+	switch platform.Detect() {
+	case platform.PlatformMacOS:
+		return HeartbeatBackendLaunchd
+	case platform.PlatformLinux, platform.PlatformWSL2:
+	default:
+		return ""
+	}
+	settings := GetConductorSettings()
+	switch settings.HeartbeatBackend {
+	case HeartbeatBackendSystemd, HeartbeatBackendCron:
+		return settings.HeartbeatBackend
+	}
+	if systemdUserAvailable() {
+		return HeartbeatBackendSystemd
+	}
+	if cronAvailable() {
+		return HeartbeatBackendCron
+	}
+	return ""
+}
+
+// heartbeatCronMarker ends a conductor's crontab line so it can be found and
+// replaced. It carries the deck's label so named decks keep separate lines.
+func heartbeatCronMarker(name string) string {
+	return "# " + HeartbeatPlistLabel(name)
+}
+
+// HeartbeatCronSchedule returns the five cron fields for a heartbeat: the
+// schedule when set (cron only; OnCalendar needs systemd), else the interval
+func HeartbeatCronSchedule(intervalMinutes int, schedule string) (string, error) {
+	if schedule != "" {
+		sched, err := ParseHeartbeatSchedule(schedule)
+		if err != nil {
+			return "", err
+		}
+		if sched.Kind != ScheduleCron {
+			return "", fmt.Errorf("OnCalendar schedules need systemd; use a cron expression (e.g. \"5 9-17 * * 1-5\") with the cron backend")
+		}
+		return sched.Expr, nil
+	}
+	switch {
+	case intervalMinutes <= 0:
+		return "", fmt.Errorf("invalid heartbeat interval %d", intervalMinutes)
+	case intervalMinutes < 60:
+		return fmt.Sprintf("*/%d * * * *", intervalMinutes), nil
+	case intervalMinutes%60 == 0 && intervalMinutes < 24*60:
+		return fmt.Sprintf("0 */%d * * *", intervalMinutes/60), nil
+	case intervalMinutes == 24*60:
+		return "0 0 * * *", nil
+	}
+	return "", fmt.Errorf("a %d minute heartbeat interval cannot be expressed in cron; use a heartbeat schedule", intervalMinutes)
+}
+
+// GenerateHeartbeatCronLine returns the crontab line that runs a conductor's
+// heartbeat (agent-deck conductor heartbeat-run), with the same PATH, HOME
+// and deck variables as the launchd and systemd units. Cron uses the system
+// timezone.
+func GenerateHeartbeatCronLine(name string, intervalMinutes int, schedule string) (string, error) {
+	fields, err := HeartbeatCronSchedule(intervalMinutes, schedule)
+	if err != nil {
+		return "", err
+	}
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return "", err
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	env := append([]string{
+		"PATH=" + buildDaemonPath(findAgentDeck()),
+		"HOME=" + homeDir,
+	}, deck.Env()...)
+	var b strings.Builder
+	b.WriteString(fields)
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		fmt.Fprintf(&b, " %s=%s", key, cronQuote(value))
+	}
+	b.WriteString(" ")
+	for _, arg := range HeartbeatCommand(name) {
+		b.WriteString(cronQuote(arg) + " ")
+	}
+	fmt.Fprintf(&b, ">> %s 2>&1 %s",
+		cronQuote(filepath.Join(dir, "heartbeat.log")),
+		heartbeatCronMarker(name))
+	return b.String(), nil
+}
+
+// cronQuote single-quotes a value for the shell cron runs commands with and
+// escapes %, which cron otherwise turns into a newline
+func cronQuote(s string) string {
+	s = "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+	return strings.ReplaceAll(s, "%", "\\%")
+}
+
+// readCrontab returns the user's crontab, empty when there is none
+func readCrontab() (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(crontabCommand, "-l")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// crontab -l fails when the user has no crontab yet
+		if strings.Contains(strings.ToLower(stderr.String()), "no crontab") {
+			return "", nil
+		}
+		return "", fmt.Errorf("crontab -l: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// writeCrontab replaces the user's crontab
+func writeCrontab(content string) error {
+	cmd := exec.Command(crontabCommand, "-")
+	cmd.Stdin = strings.NewReader(content)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("crontab: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// setCrontabLine replaces the line ending in marker with line, appending it
+// when absent; an empty line removes it
+func setCrontabLine(crontab, marker, line string) string {
+	var lines []string
+	replaced := false
+	for _, l := range strings.Split(strings.TrimRight(crontab, "\n"), "\n") {
+		if strings.HasSuffix(strings.TrimSpace(l), marker) {
+			if line != "" && !replaced {
+				lines = append(lines, line)
+			}
+			replaced = true
+			continue
+		}
+		if l != "" || len(lines) > 0 {
+			lines = append(lines, l)
+		}
+	}
+	if line != "" && !replaced {
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// findCrontabLine returns the line ending in marker, if any
+func findCrontabLine(crontab, marker string) (string, bool) {
+	for _, l := range strings.Split(crontab, "\n") {
+		if strings.HasSuffix(strings.TrimSpace(l), marker) {
+			return strings.TrimSpace(l), true
+		}
+	}
+	return "", false
+}
+
+func installHeartbeatDaemonCron(name string, intervalMinutes int, schedule string) error {
+	line, err := GenerateHeartbeatCronLine(name, intervalMinutes, schedule)
+	if err != nil {
+		return fmt.Errorf("failed to generate heartbeat crontab line: %w", err)
+	}
+	crontab, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	if err := writeCrontab(setCrontabLine(crontab, heartbeatCronMarker(name), line)); err != nil {
+		return fmt.Errorf("failed to install heartbeat crontab line: %w", err)
+	}
+	return nil
+}
+
+func uninstallHeartbeatDaemonCron(name string) error {
+	crontab, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	marker := heartbeatCronMarker(name)
+	if _, ok := findCrontabLine(crontab, marker); !ok {
+		return nil
+	}
+	return writeCrontab(setCrontabLine(crontab, marker, ""))
+}
+
+// HeartbeatDaemonState describes a conductor's installed heartbeat timer
+type HeartbeatDaemonState struct {
+	Backend   string `json:"backend"`            // launchd, systemd or cron ("" when none is available)
+	Installed bool   `json:"installed"`          // the plist, timer unit or crontab line exists
+	Active    bool   `json:"active"`             // the scheduler has it loaded
+	Schedule  string `json:"schedule,omitempty"` // cron fields, for the cron backend
+}
+
+// HeartbeatDaemonStatus reports whether a conductor's heartbeat timer is
+// installed and active with the current backend
+func HeartbeatDaemonStatus(name string) (HeartbeatDaemonState, error) {
+	state := HeartbeatDaemonState{Backend: HeartbeatBackend()}
+	switch state.Backend {
+	case HeartbeatBackendLaunchd:
+		path, err := HeartbeatPlistPath(name)
+		if err != nil {
+			return state, err
+		}
+		_, err = os.Stat(path)
+		state.Installed = err == nil
+		state.Active = state.Installed && exec.Command("launchctl", "list", HeartbeatPlistLabel(name)).Run() == nil
+	case HeartbeatBackendSystemd:
+		path, err := SystemdHeartbeatTimerPath(name)
+		if err != nil {
+			return state, err
+		}
+		_, err = os.Stat(path)
+		state.Installed = err == nil
+		state.Active = state.Installed && exec.Command("systemctl", "--user", "is-active", "--quiet", SystemdHeartbeatTimerName(name)).Run() == nil
+	case HeartbeatBackendCron:
+		crontab, err := readCrontab()
+		if err != nil {
+			return state, err
+		}
+		line, ok := findCrontabLine(crontab, heartbeatCronMarker(name))
+		state.Installed, state.Active = ok, ok
+		if ok {
+			if fields := strings.Fields(line); len(fields) >= 5 {
+				state.Schedule = strings.Join(fields[:5], " ")
+			}
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeCrontab points crontabCommand at a script that keeps the crontab in a
+// file, and returns that file's path
+func fakeCrontab(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	store := filepath.Join(dir, "crontab.txt")
+	script := `#!/bin/sh
+if [ "$1" = "-l" ]; then
+    [ -f "` + store + `" ] || { echo "no crontab for user" >&2; exit 1; }
+    cat "` + store + `"
+else
+    cat > "` + store + `"
+fi
+`
+	bin := filepath.Join(dir, "crontab")
+	if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := crontabCommand
+	crontabCommand = bin
+	t.Cleanup(func() { crontabCommand = old })
+	return store
+}
+
+func TestHeartbeatCronSchedule(t *testing.T) {
+	tests := []struct {
+		interval int
+		schedule string
+		want     string
+		wantErr  bool
+	}{
+		{interval: 15, want: "*/15 * * * *"},
+		{interval: 120, want: "0 */2 * * *"},
+		{interval: 1440, want: "0 0 * * *"},
+		{interval: 90, wantErr: true},
+		{interval: 0, wantErr: true},
+		{schedule: "5 9-17 * * 1-5", want: "5 9-17 * * 1-5"},
+		{schedule: "@hourly", want: "0 * * * *"},
+		{schedule: "Mon..Fri *-*-* 09:00:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := HeartbeatCronSchedule(tt.interval, tt.schedule)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("HeartbeatCronSchedule(%d, %q) = %q, %v; want %q (error %v)", tt.interval, tt.schedule, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSetCrontabLine_KeepsOtherEntries(t *testing.T) {
+	crontab := "MAILTO=me\n0 3 * * * backup.sh\n*/15 * * * * old # marker\n"
+
+	got := setCrontabLine(crontab, "# marker", "*/5 * * * * new # marker")
+	want := "MAILTO=me\n0 3 * * * backup.sh\n*/5 * * * * new # marker\n"
+	if got != want {
+		t.Errorf("replace:\n%s\nwant:\n%s", got, want)
+	}
+	if got := setCrontabLine(crontab, "# marker", ""); got != "MAILTO=me\n0 3 * * * backup.sh\n" {
+		t.Errorf("remove: %q", got)
+	}
+	if got := setCrontabLine("", "# marker", "* * * * * x # marker"); got != "* * * * * x # marker\n" {
+		t.Errorf("append to empty: %q", got)
+	}
+}
+
+func TestHeartbeatCron_InstallStatusUninstall(t *testing.T) {
+	writeMacroConfig(t, "[conductor]\nheartbeat_backend = \"cron\"\n")
+	store := fakeCrontab(t)
+	if err := os.WriteFile(store, []byte("0 3 * * * backup.sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := installHeartbeatDaemonCron("ops", 10, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Reinstalling with a schedule replaces the line rather than adding one
+	if err := installHeartbeatDaemonCron("ops", 10, "5 9-17 * * 1-5"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(store)
+	crontab := string(data)
+	if strings.Count(crontab, heartbeatCronMarker("ops")) != 1 || !strings.HasPrefix(crontab, "0 3 * * * backup.sh\n") {
+		t.Fatalf("crontab:\n%s", crontab)
+	}
+	line, _ := findCrontabLine(crontab, heartbeatCronMarker("ops"))
+	if !strings.HasPrefix(line, "5 9-17 * * 1-5 PATH=") || !strings.Contains(line, "'conductor' 'heartbeat-run' 'ops' >> ") {
+		t.Errorf("heartbeat line = %q", line)
+	}
+
+	if runtime.GOOS == "linux" {
+		state, err := HeartbeatDaemonStatus("ops")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := HeartbeatDaemonState{Backend: HeartbeatBackendCron, Installed: true, Active: true, Schedule: "5 9-17 * * 1-5"}
+		if state != want {
+			t.Errorf("status = %+v, want %+v", state, want)
+		}
+	}
+
+	if err := uninstallHeartbeatDaemonCron("ops"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(store)
+	if string(data) != "0 3 * * * backup.sh\n" {
+		t.Errorf("after uninstall:\n%s", data)
+	}
+}
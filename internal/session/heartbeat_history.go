@@ -0,0 +1,137 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+)
+
+var heartbeatLog = logging.ForComponent(logging.CompHeartbeat)
+
+// HeartbeatRun is one heartbeat delivery attempt in a conductor's
+// heartbeats.jsonl
+type HeartbeatRun struct {
+	Time         time.Time `json:"ts"`
+	Profile      string    `json:"profile,omitempty"`
+	SessionID    string    `json:"session_id,omitempty"`
+	Result       string    `json:"result"` // sent, skipped or failed
+	Error        string    `json:"error,omitempty"`
+	Prompt       string    `json:"prompt"`
+	StatusBefore string    `json:"status_before,omitempty"`
+	StatusAfter  string    `json:"status_after,omitempty"`
+}
+
+// heartbeatHistoryPath returns a conductor's heartbeats.jsonl
+func heartbeatHistoryPath(name string) (string, error) {
+	dir, err := ConductorNameDir(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "heartbeats.jsonl"), nil
+}
+
+// AppendHeartbeatHistory records a heartbeat run for a conductor, keeping
+// only the last [conductor] heartbeat_history runs
+func AppendHeartbeatHistory(name string, run HeartbeatRun) error {
+	heartbeatLog.Debug("heartbeat_run",
+		slog.String("conductor", name),
+		slog.String("session_id", run.SessionID),
+		slog.String("result", run.Result),
+		slog.String("status_before", run.StatusBefore),
+		slog.String("status_after", run.StatusAfter),
+		slog.Int("prompt_len", len(run.Prompt)),
+	)
+	settings := GetConductorSettings()
+	keep := settings.GetHeartbeatHistory()
+	if keep == 0 {
+		return nil
+	}
+	path, err := heartbeatHistoryPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	line, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open heartbeat history: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write heartbeat history: %w", err)
+	}
+	return trimHeartbeatHistory(path, keep)
+}
+
+// trimHeartbeatHistory drops the oldest runs once the file holds a tenth
+// more than keep, so the file is not rewritten on every heartbeat
+func trimHeartbeatHistory(path string, keep int) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= keep+keep/10 {
+		return nil
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, bytes.Join(lines[len(lines)-keep:], nil), 0o644); err != nil {
+		return fmt.Errorf("trim heartbeat history: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// LoadHeartbeatHistory returns a conductor's recorded heartbeat runs, oldest
+// first. limit > 0 returns only the most recent limit runs. Lines that do
+// not parse (e.g. a write cut short) are skipped.
+func LoadHeartbeatHistory(name string, limit int) ([]HeartbeatRun, error) {
+	path, err := heartbeatHistoryPath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []HeartbeatRun
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var run HeartbeatRun
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read heartbeat history: %w", err)
+	}
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+	return runs, nil
+}
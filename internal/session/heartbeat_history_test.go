@@ -0,0 +1,62 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatHistory_RingBuffer(t *testing.T) {
+	writeMacroConfig(t, "[conductor]\nheartbeat_history = 10\n")
+	start := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	for i := range 12 {
+		run := HeartbeatRun{
+			Time:         start.Add(time.Duration(i) * time.Minute),
+			Result:       HeartbeatSent,
+			Prompt:       "Heartbeat: check sessions",
+			StatusBefore: string(StatusIdle),
+			StatusAfter:  string(StatusRunning),
+		}
+		if err := AppendHeartbeatHistory("ops", run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 11 runs fit within the slack; the 12th trims back to 10
+	runs, err := LoadHeartbeatHistory("ops", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 10 || !runs[0].Time.Equal(start.Add(2*time.Minute)) || !runs[9].Time.Equal(start.Add(11*time.Minute)) {
+		t.Fatalf("got %d runs from %v to %v, want the last 10", len(runs), runs[0].Time, runs[len(runs)-1].Time)
+	}
+	if runs[9].StatusBefore != "idle" || runs[9].StatusAfter != "running" || runs[9].Prompt == "" {
+		t.Errorf("run = %+v", runs[9])
+	}
+
+	recent, _ := LoadHeartbeatHistory("ops", 3)
+	if len(recent) != 3 || !recent[2].Time.Equal(start.Add(11*time.Minute)) {
+		t.Errorf("limit 3 = %+v", recent)
+	}
+
+	// A torn last line is skipped rather than failing the whole history
+	path, _ := heartbeatHistoryPath("ops")
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = f.WriteString(`{"ts": "2026-03`)
+	_ = f.Close()
+	if runs, err := LoadHeartbeatHistory("ops", 0); err != nil || len(runs) != 10 {
+		t.Errorf("with torn line: %d runs, %v", len(runs), err)
+	}
+}
+
+func TestHeartbeatHistory_Disabled(t *testing.T) {
+	writeMacroConfig(t, "[conductor]\nheartbeat_history = -1\n")
+	if err := AppendHeartbeatHistory("ops", HeartbeatRun{Result: HeartbeatSent}); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := heartbeatHistoryPath("ops")
+	if data, err := os.ReadFile(path); err == nil || len(bytes.TrimSpace(data)) > 0 {
+		t.Errorf("history written while disabled: %q", data)
+	}
+}
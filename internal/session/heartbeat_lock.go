@@ -0,0 +1,168 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// ErrHeartbeatLockHeld means another machine holds a conductor's heartbeat lock
+var ErrHeartbeatLockHeld = errors.New("heartbeat lock held by another machine")
+
+// HeartbeatLock is a lease on delivering a conductor's heartbeats. With the
+// home directory on shared storage (e.g. NFS across login nodes) every
+// machine's timer fires; only the lease holder's heartbeat is delivered. The
+// holder renews the lease with each heartbeat; when it stops (machine down,
+// timer removed) the lease expires and the next machine to fire takes over.
+type HeartbeatLock struct {
+	Holder     string    `json:"holder"` // user@host:pid of the last renewal
+	Host       string    `json:"host"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the lease has lapsed at now
+func (l *HeartbeatLock) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// GetHeartbeatLockTTL returns how long a heartbeat lease lasts for a conductor
+// whose heartbeat runs every intervalMinutes: heartbeat_lock_ttl, defaulting
+// to two intervals so one missed heartbeat does not hand the lease over
+func (c *ConductorSettings) GetHeartbeatLockTTL(intervalMinutes int) time.Duration {
+	if c.HeartbeatLockTTL > 0 {
+		return time.Duration(c.HeartbeatLockTTL) * time.Minute
+	}
+	if intervalMinutes <= 0 {
+		intervalMinutes = c.GetHeartbeatInterval("")
+	}
+	return 2 * time.Duration(intervalMinutes) * time.Minute
+}
+
+// heartbeatLockPath returns ~/.agent-deck/conductor/<name>/heartbeat.lock
+func heartbeatLockPath(conductor string) (string, error) {
+	dir, err := ConductorNameDir(conductor)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "heartbeat.lock"), nil
+}
+
+// ReadHeartbeatLock returns a conductor's heartbeat lock, or nil if none was taken
+func ReadHeartbeatLock(conductor string) (*HeartbeatLock, error) {
+	path, err := heartbeatLockPath(conductor)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lock HeartbeatLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid heartbeat lock %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// AcquireHeartbeatLock takes or renews a conductor's heartbeat lease for this
+// machine. It returns the current lock and ErrHeartbeatLockHeld while another
+// machine's lease is valid.
+func AcquireHeartbeatLock(conductor string, ttl time.Duration, now time.Time) (*HeartbeatLock, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("heartbeat lock: %w", err)
+	}
+	current, err := ReadHeartbeatLock(conductor)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.Host != host && !current.Expired(now) {
+		return current, ErrHeartbeatLockHeld
+	}
+
+	lock := &HeartbeatLock{
+		Holder:     heartbeatLockHolder(host),
+		Host:       host,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if current != nil && current.Host == host && !current.Expired(now) {
+		lock.AcquiredAt = current.AcquiredAt
+	}
+	if err := writeHeartbeatLock(conductor, host, lock); err != nil {
+		return nil, err
+	}
+
+	// Two machines taking over an expired lease at the same moment both
+	// write; rename is atomic on NFS, so reading back tells who won
+	written, err := ReadHeartbeatLock(conductor)
+	if err != nil {
+		return nil, err
+	}
+	if written == nil || written.Host != host {
+		return written, ErrHeartbeatLockHeld
+	}
+	return written, nil
+}
+
+// ReleaseHeartbeatLock drops this machine's lease so another machine can take
+// over at its next heartbeat. A lease held by another machine is left alone.
+func ReleaseHeartbeatLock(conductor string) error {
+	host, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	current, err := ReadHeartbeatLock(conductor)
+	if err != nil || current == nil {
+		return err
+	}
+	if current.Host != host {
+		return ErrHeartbeatLockHeld
+	}
+	path, err := heartbeatLockPath(conductor)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func writeHeartbeatLock(conductor, host string, lock *HeartbeatLock) error {
+	path, err := heartbeatLockPath(conductor)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The temp file name is unique per machine and process so concurrent
+	// writers never share it
+	tmp := fmt.Sprintf("%s.%s.%d.tmp", path, host, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write heartbeat lock: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write heartbeat lock: %w", err)
+	}
+	return nil
+}
+
+func heartbeatLockHolder(host string) string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return fmt.Sprintf("%s@%s:%d", name, host, os.Getpid())
+}
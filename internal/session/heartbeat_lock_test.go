@@ -0,0 +1,72 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatLock(t *testing.T) {
+	writeMacroConfig(t, "")
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skip(err)
+	}
+	now := time.Now().Truncate(time.Second)
+
+	lock, err := AcquireHeartbeatLock("ops", 30*time.Minute, now)
+	if err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+	if lock.Host != host || !lock.ExpiresAt.Equal(now.Add(30*time.Minute)) {
+		t.Errorf("lock = %+v", lock)
+	}
+
+	// Renewing keeps the acquisition time and extends the lease
+	renewed, err := AcquireHeartbeatLock("ops", 30*time.Minute, now.Add(15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renewed.AcquiredAt.Equal(now) || !renewed.ExpiresAt.Equal(now.Add(45*time.Minute)) {
+		t.Errorf("renewed lock = %+v", renewed)
+	}
+
+	// Another machine's valid lease blocks this one until it expires
+	other := &HeartbeatLock{Holder: "me@login2:42", Host: "login2", AcquiredAt: now, ExpiresAt: now.Add(time.Hour)}
+	if err := writeHeartbeatLock("ops", "login2", other); err != nil {
+		t.Fatal(err)
+	}
+	held, err := AcquireHeartbeatLock("ops", 30*time.Minute, now.Add(30*time.Minute))
+	if !errors.Is(err, ErrHeartbeatLockHeld) || held.Host != "login2" {
+		t.Fatalf("acquire while held = %+v, %v", held, err)
+	}
+	if err := ReleaseHeartbeatLock("ops"); !errors.Is(err, ErrHeartbeatLockHeld) {
+		t.Errorf("releasing another machine's lock = %v", err)
+	}
+	taken, err := AcquireHeartbeatLock("ops", 30*time.Minute, now.Add(time.Hour))
+	if err != nil || taken.Host != host || !taken.AcquiredAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("takeover after expiry = %+v, %v", taken, err)
+	}
+
+	if err := ReleaseHeartbeatLock("ops"); err != nil {
+		t.Fatal(err)
+	}
+	if lock, err := ReadHeartbeatLock("ops"); lock != nil || err != nil {
+		t.Errorf("after release = %+v, %v", lock, err)
+	}
+}
+
+func TestHeartbeatLockTTL(t *testing.T) {
+	settings := ConductorSettings{HeartbeatInterval: 10}
+	if got := settings.GetHeartbeatLockTTL(0); got != 20*time.Minute {
+		t.Errorf("default TTL = %v, want twice the global interval", got)
+	}
+	if got := settings.GetHeartbeatLockTTL(60); got != 2*time.Hour {
+		t.Errorf("TTL for a 60 minute conductor = %v", got)
+	}
+	settings.HeartbeatLockTTL = 5
+	if got := settings.GetHeartbeatLockTTL(60); got != 5*time.Minute {
+		t.Errorf("configured TTL = %v", got)
+	}
+}
@@ -0,0 +1,408 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Heartbeat schedule kinds. A schedule is either a systemd OnCalendar
+// expression or a five-field cron string; cron strings also work with launchd
+// and are converted to OnCalendar for systemd.
+const (
+	ScheduleOnCalendar = "oncalendar"
+	ScheduleCron       = "cron"
+)
+
+// maxLaunchdIntervals caps the StartCalendarInterval entries a cron schedule
+// may expand to on macOS
+const maxLaunchdIntervals = 1000
+
+var (
+	cronFieldRegex = regexp.MustCompile(`^[0-9A-Za-z*,/-]+$`)
+
+	cronMacros = map[string]string{
+		"@hourly":   "0 * * * *",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@weekly":   "0 0 * * 0",
+		"@monthly":  "0 0 1 * *",
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+	}
+
+	cronMonthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronDayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	calendarDays   = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+)
+
+// HeartbeatSchedule is a parsed heartbeat schedule
+type HeartbeatSchedule struct {
+	Kind string // ScheduleOnCalendar or ScheduleCron
+	Expr string // as written (cron macros expanded)
+}
+
+// ParseHeartbeatSchedule detects and checks the syntax of a schedule: cron
+// strings ("5 9-17 * * 1-5", "@hourly") are parsed fully, anything else is
+// taken as an OnCalendar expression ("Mon..Fri *-*-* 09..17:05:00").
+func ParseHeartbeatSchedule(s string) (HeartbeatSchedule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return HeartbeatSchedule{}, errors.New("empty heartbeat schedule")
+	}
+	if strings.ContainsAny(s, "\n\r\t'\"\\`$") {
+		return HeartbeatSchedule{}, fmt.Errorf("invalid heartbeat schedule %q", s)
+	}
+	if expanded, ok := cronMacros[strings.ToLower(s)]; ok {
+		s = expanded
+	} else if strings.HasPrefix(s, "@") {
+		return HeartbeatSchedule{}, fmt.Errorf("unknown cron macro %q", s)
+	}
+	if isCronExpr(s) {
+		sched := HeartbeatSchedule{Kind: ScheduleCron, Expr: s}
+		if _, err := sched.cronFields(); err != nil {
+			return HeartbeatSchedule{}, err
+		}
+		return sched, nil
+	}
+	return HeartbeatSchedule{Kind: ScheduleOnCalendar, Expr: s}, nil
+}
+
+func isCronExpr(s string) bool {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return false
+	}
+	for _, f := range fields {
+		if !cronFieldRegex.MatchString(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateHeartbeatSchedule parses a schedule and, when systemd-analyze is
+// installed, checks its OnCalendar form with it. It returns the next run as
+// reported by systemd-analyze ("" when unavailable).
+func ValidateHeartbeatSchedule(s, timezone string) (next string, err error) {
+	sched, err := ParseHeartbeatSchedule(s)
+	if err != nil {
+		return "", err
+	}
+	calendar, err := sched.OnCalendar(timezone)
+	if err != nil {
+		return "", err
+	}
+	if _, err := exec.LookPath("systemd-analyze"); err != nil {
+		return "", nil
+	}
+	out, err := exec.Command("systemd-analyze", "calendar", calendar).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("invalid OnCalendar expression %q: %s", calendar, strings.TrimSpace(string(out)))
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if label, value, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && label == "Next elapse" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", nil
+}
+
+// String renders the schedule for listings
+func (h HeartbeatSchedule) String() string {
+	if h.Kind == ScheduleCron {
+		return "cron " + h.Expr
+	}
+	return "OnCalendar " + h.Expr
+}
+
+// cronField is one comma-separated item of a cron field
+type cronField struct {
+	lo, hi, step int
+	star         bool
+}
+
+// cronSpec describes the bounds and names of a cron field
+type cronSpec struct {
+	name   string
+	min    int
+	max    int
+	names  []string // names[i] is value i+offset
+	offset int
+}
+
+var cronSpecs = []cronSpec{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: cronMonthNames, offset: 1},
+	{name: "day of week", min: 0, max: 7, names: cronDayNames},
+}
+
+func (spec cronSpec) value(s string) (int, error) {
+	for i, n := range spec.names {
+		if strings.EqualFold(s, n) {
+			return i + spec.offset, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < spec.min || v > spec.max {
+		return 0, fmt.Errorf("invalid cron %s %q (%d-%d)", spec.name, s, spec.min, spec.max)
+	}
+	return v, nil
+}
+
+func (spec cronSpec) parse(field string) ([]cronField, error) {
+	var items []cronField
+	for _, part := range strings.Split(field, ",") {
+		item := cronField{step: 1}
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			step, err := strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid cron %s step %q", spec.name, stepPart)
+			}
+			item.step = step
+		}
+		switch lo, hi, isRange := strings.Cut(rangePart, "-"); {
+		case rangePart == "*":
+			item.star = true
+			item.lo, item.hi = spec.min, spec.max
+		case isRange:
+			var err error
+			if item.lo, err = spec.value(lo); err != nil {
+				return nil, err
+			}
+			if item.hi, err = spec.value(hi); err != nil {
+				return nil, err
+			}
+			if item.hi < item.lo {
+				return nil, fmt.Errorf("invalid cron %s range %q", spec.name, rangePart)
+			}
+		default:
+			v, err := spec.value(rangePart)
+			if err != nil {
+				return nil, err
+			}
+			item.lo, item.hi = v, v
+			if hasStep {
+				item.hi = spec.max // "5/15" means from 5 to the end, every 15
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// cronFields parses the five fields of a cron schedule
+func (h HeartbeatSchedule) cronFields() ([][]cronField, error) {
+	parts := strings.Fields(h.Expr)
+	fields := make([][]cronField, len(parts))
+	for i, part := range parts {
+		items, err := cronSpecs[i].parse(part)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = items
+	}
+	// cron fires when either day field matches if both are restricted,
+	// OnCalendar and launchd require both; refuse rather than misfire
+	if !isStar(fields[2]) && !isStar(fields[4]) {
+		return nil, fmt.Errorf("cron schedule %q restricts both day of month and day of week; use an OnCalendar expression", h.Expr)
+	}
+	return fields, nil
+}
+
+func isStar(items []cronField) bool {
+	return len(items) == 1 && items[0].star && items[0].step == 1
+}
+
+// expandCronField returns the values a cron field matches, or nil for "*"
+func expandCronField(items []cronField, dayOfWeek bool) []int {
+	if isStar(items) {
+		return nil
+	}
+	seen := make(map[int]bool)
+	for _, item := range items {
+		for v := item.lo; v <= item.hi; v += item.step {
+			if dayOfWeek {
+				seen[v%7] = true // 7 is Sunday too
+			} else {
+				seen[v] = true
+			}
+		}
+	}
+	values := make([]int, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	return values
+}
+
+// OnCalendar returns the schedule as a systemd OnCalendar expression. Cron
+// schedules are converted and, when timezone is set, pinned to it.
+func (h HeartbeatSchedule) OnCalendar(timezone string) (string, error) {
+	if h.Kind != ScheduleCron {
+		return h.Expr, nil
+	}
+	fields, err := h.cronFields()
+	if err != nil {
+		return "", err
+	}
+	calendarField := func(items []cronField, min int) string {
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			var s string
+			switch {
+			case item.star && item.step == 1:
+				s = "*"
+			case item.star:
+				s = fmt.Sprintf("%d/%d", min, item.step)
+			case item.lo == item.hi:
+				s = strconv.Itoa(item.lo)
+			default:
+				s = fmt.Sprintf("%d..%d", item.lo, item.hi)
+				if item.step > 1 {
+					s += fmt.Sprintf("/%d", item.step)
+				}
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	var b strings.Builder
+	if days := expandCronField(fields[4], true); days != nil {
+		// Collapse runs of consecutive days: Mon..Fri rather than Mon,Tue,...
+		var names []string
+		for i := 0; i < len(days); {
+			j := i
+			for j+1 < len(days) && days[j+1] == days[j]+1 {
+				j++
+			}
+			if j-i >= 2 {
+				names = append(names, calendarDays[days[i]]+".."+calendarDays[days[j]])
+			} else {
+				for k := i; k <= j; k++ {
+					names = append(names, calendarDays[days[k]])
+				}
+			}
+			i = j + 1
+		}
+		b.WriteString(strings.Join(names, ",") + " ")
+	}
+	fmt.Fprintf(&b, "*-%s-%s %s:%s:00",
+		calendarField(fields[3], 1), calendarField(fields[2], 1),
+		calendarField(fields[1], 0), calendarField(fields[0], 0))
+	if timezone != "" {
+		b.WriteString(" " + timezone)
+	}
+	return b.String(), nil
+}
+
+// LaunchdIntervals returns the schedule as launchd StartCalendarInterval
+// entries (keys Minute, Hour, Day, Month, Weekday). Only cron schedules can be
+// converted; launchd always uses the system timezone.
+func (h HeartbeatSchedule) LaunchdIntervals() ([]map[string]int, error) {
+	if h.Kind != ScheduleCron {
+		return nil, fmt.Errorf("OnCalendar schedules need systemd; use a cron expression (e.g. \"5 9-17 * * 1-5\") on macOS")
+	}
+	fields, err := h.cronFields()
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{"Minute", "Hour", "Day", "Month", "Weekday"}
+	intervals := []map[string]int{{}}
+	for i, key := range keys {
+		values := expandCronField(fields[i], key == "Weekday")
+		if values == nil {
+			continue
+		}
+		next := make([]map[string]int, 0, len(intervals)*len(values))
+		for _, base := range intervals {
+			for _, v := range values {
+				entry := make(map[string]int, len(base)+1)
+				for k, bv := range base {
+					entry[k] = bv
+				}
+				entry[key] = v
+				next = append(next, entry)
+			}
+		}
+		if len(next) > maxLaunchdIntervals {
+			return nil, fmt.Errorf("cron schedule %q expands to more than %d launchd intervals", h.Expr, maxLaunchdIntervals)
+		}
+		intervals = next
+	}
+	return intervals, nil
+}
+
+// EffectiveHeartbeatSchedule returns the conductor's heartbeat schedule:
+// meta.json's heartbeat_schedule, else [conductor].heartbeat_schedule. Empty
+// means the fixed heartbeat interval is used.
+func EffectiveHeartbeatSchedule(meta *ConductorMeta) string {
+	if meta != nil && meta.HeartbeatSchedule != "" {
+		return meta.HeartbeatSchedule
+	}
+	settings := GetConductorSettings()
+	return settings.HeartbeatSchedule
+}
+
+// FiredBetween reports whether a cron schedule fires at a minute in
+// (after, until]. Only the last eight days are checked.
+func (h HeartbeatSchedule) FiredBetween(after, until time.Time) (bool, error) {
+	if h.Kind != ScheduleCron {
+		return false, fmt.Errorf("only cron schedules can be checked here, not %q", h.Expr)
+	}
+	fields, err := h.cronFields()
+	if err != nil {
+		return false, err
+	}
+	if earliest := until.Add(-8 * 24 * time.Hour); after.Before(earliest) {
+		after = earliest
+	}
+	for t := until.Truncate(time.Minute); t.After(after); t = t.Add(-time.Minute) {
+		if cronMatches(fields, t) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NextAfter returns the first minute after t at which a cron schedule fires,
+// in t's location. Only the next eight days are checked; zero when it does
+// not fire within them.
+func (h HeartbeatSchedule) NextAfter(t time.Time) (time.Time, error) {
+	if h.Kind != ScheduleCron {
+		return time.Time{}, fmt.Errorf("only cron schedules can be checked here, not %q", h.Expr)
+	}
+	fields, err := h.cronFields()
+	if err != nil {
+		return time.Time{}, err
+	}
+	limit := t.Add(8 * 24 * time.Hour)
+	for next := t.Truncate(time.Minute).Add(time.Minute); !next.After(limit); next = next.Add(time.Minute) {
+		if cronMatches(fields, next) {
+			return next, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// cronMatches reports whether the cron fields fire at t's minute
+func cronMatches(fields [][]cronField, t time.Time) bool {
+	matches := func(items []cronField, v int, dayOfWeek bool) bool {
+		values := expandCronField(items, dayOfWeek)
+		return values == nil || slices.Contains(values, v)
+	}
+	return matches(fields[0], t.Minute(), false) && matches(fields[1], t.Hour(), false) &&
+		matches(fields[2], t.Day(), false) && matches(fields[3], int(t.Month()), false) &&
+		matches(fields[4], int(t.Weekday()), true)
+}
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHeartbeatSchedule(t *testing.T) {
+	tests := []struct {
+		in       string
+		kind     string
+		calendar string
+		wantErr  bool
+	}{
+		{in: "5 9-17 * * 1-5", kind: ScheduleCron, calendar: "Mon..Fri *-*-* 9..17:5:00"},
+		{in: "*/15 * * * *", kind: ScheduleCron, calendar: "*-*-* *:0/15:00"},
+		{in: "0 8 1 * *", kind: ScheduleCron, calendar: "*-*-1 8:0:00"},
+		{in: "30 7 * * sat,sun", kind: ScheduleCron, calendar: "Sun,Sat *-*-* 7:30:00"},
+		{in: "@hourly", kind: ScheduleCron, calendar: "*-*-* *:0:00"},
+		{in: "Mon..Fri *-*-* 09..17:05:00", kind: ScheduleOnCalendar, calendar: "Mon..Fri *-*-* 09..17:05:00"},
+		{in: "hourly", kind: ScheduleOnCalendar, calendar: "hourly"},
+		{in: "61 * * * *", wantErr: true},
+		{in: "0 9 1 * 1", wantErr: true}, // day of month and day of week both restricted
+		{in: "@often", wantErr: true},
+		{in: "$(reboot)", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		sched, err := ParseHeartbeatSchedule(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseHeartbeatSchedule(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if sched.Kind != tt.kind {
+			t.Errorf("ParseHeartbeatSchedule(%q).Kind = %q, want %q", tt.in, sched.Kind, tt.kind)
+		}
+		if got, _ := sched.OnCalendar(""); got != tt.calendar {
+			t.Errorf("OnCalendar(%q) = %q, want %q", tt.in, got, tt.calendar)
+		}
+	}
+}
+
+func TestHeartbeatScheduleLaunchdIntervals(t *testing.T) {
+	sched, err := ParseHeartbeatSchedule("5 9-17 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intervals, err := sched.LaunchdIntervals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 9*5 {
+		t.Fatalf("got %d intervals, want 45", len(intervals))
+	}
+	first := intervals[0]
+	if first["Minute"] != 5 || first["Hour"] != 9 || first["Weekday"] != 1 {
+		t.Errorf("first interval = %v", first)
+	}
+	if _, ok := first["Day"]; ok {
+		t.Error("unrestricted fields must be omitted")
+	}
+
+	onCalendar, _ := ParseHeartbeatSchedule("Mon *-*-* 09:00:00")
+	if _, err := onCalendar.LaunchdIntervals(); err == nil {
+		t.Error("OnCalendar schedules cannot run under launchd")
+	}
+}
+
+func TestGenerateSystemdHeartbeatCalendarTimer(t *testing.T) {
+	timer, err := GenerateSystemdHeartbeatCalendarTimer("ops", "5 9-17 * * 1-5", "Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"OnCalendar=Mon..Fri *-*-* 9..17:5:00 Europe/Berlin", "AccuracySec=1s"} {
+		if !strings.Contains(timer, want) {
+			t.Errorf("timer missing %q:\n%s", want, timer)
+		}
+	}
+	if strings.Contains(timer, "OnUnitActiveSec") {
+		t.Error("calendar timer should not use a fixed interval")
+	}
+}
+
+func TestSetupConductorKeepsHeartbeatSchedule(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "work", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	meta, _ := LoadConductorMeta("ops")
+	meta.HeartbeatSchedule = "0 9 * * 1-5"
+	meta.HeartbeatInterval = 30
+	if err := SaveConductorMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	// A re-run must not drop the schedule the installed timer still uses
+	if err := SetupConductor("ops", "work", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor re-run: %v", err)
+	}
+	meta, _ = LoadConductorMeta("ops")
+	if meta.HeartbeatSchedule != "0 9 * * 1-5" || meta.HeartbeatInterval != 30 {
+		t.Errorf("after re-run: schedule %q, interval %d; want \"0 9 * * 1-5\", 30", meta.HeartbeatSchedule, meta.HeartbeatInterval)
+	}
+}
+
+func TestHeartbeatScheduleNextAfter(t *testing.T) {
+	sched, _ := ParseHeartbeatSchedule("5 9-17 * * 1-5")
+	friday := time.Date(2026, 3, 13, 17, 5, 0, 0, time.UTC)
+	tests := []struct {
+		from, want time.Time
+	}{
+		{friday.Add(-time.Hour - 30*time.Second), friday.Add(-time.Hour)},
+		{friday, time.Date(2026, 3, 16, 9, 5, 0, 0, time.UTC)}, // strictly after, over the weekend
+	}
+	for _, tt := range tests {
+		if got, err := sched.NextAfter(tt.from); err != nil || !got.Equal(tt.want) {
+			t.Errorf("NextAfter(%v) = %v, %v; want %v", tt.from, got, err, tt.want)
+		}
+	}
+
+	onCalendar, _ := ParseHeartbeatSchedule("Mon *-*-* 09:00:00")
+	if _, err := onCalendar.NextAfter(friday); err == nil {
+		t.Error("OnCalendar schedules are left to systemd")
+	}
+}
@@ -0,0 +1,210 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// History sources searched by SearchHistory
+const (
+	HistorySourceTranscript = "transcript" // pane output captured by [transcripts]
+	HistorySourceClaude     = "claude"     // Claude's conversation log (JSONL)
+)
+
+// HistoryFile is one file of a session's history
+type HistoryFile struct {
+	SessionID string
+	Title     string // "" for transcripts of removed sessions
+	Source    string
+	Path      string
+}
+
+// HistoryMatch is one line of a session's history matching a search
+type HistoryMatch struct {
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title,omitempty"`
+	Source    string    `json:"source"`
+	Time      time.Time `json:"time"`
+	Snippet   string    `json:"snippet"`
+	Path      string    `json:"path"`
+}
+
+// HistoryQuery selects what SearchHistory returns
+type HistoryQuery struct {
+	// Text is matched case-insensitively against each line
+	Text string
+	// Since drops matches written before it (zero = no limit)
+	Since time.Time
+	// Limit caps the number of matches, newest first (0 = 100)
+	Limit int
+}
+
+// historySnippetWindow is the context kept on each side of a match
+const historySnippetWindow = 60
+
+// HistoryFiles lists the history of instances: their transcripts and, for
+// Claude sessions, their conversation logs. Transcripts of sessions no
+// longer in instances are included too, titled by nothing but their ID.
+func HistoryFiles(instances []*Instance) []HistoryFile {
+	var files []HistoryFile
+	known := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		known[inst.ID] = true
+		if dir, err := TranscriptDir(inst.ID); err == nil {
+			paths, _ := TranscriptFiles(dir)
+			for _, path := range paths {
+				files = append(files, HistoryFile{SessionID: inst.ID, Title: inst.Title, Source: HistorySourceTranscript, Path: path})
+			}
+		}
+		if path := inst.GetJSONLPath(); path != "" {
+			files = append(files, HistoryFile{SessionID: inst.ID, Title: inst.Title, Source: HistorySourceClaude, Path: path})
+		}
+	}
+
+	root, err := TranscriptsDir()
+	if err != nil {
+		return files
+	}
+	entries, _ := os.ReadDir(root)
+	for _, e := range entries {
+		if !e.IsDir() || known[e.Name()] {
+			continue
+		}
+		paths, _ := TranscriptFiles(filepath.Join(root, e.Name()))
+		for _, path := range paths {
+			files = append(files, HistoryFile{SessionID: e.Name(), Source: HistorySourceTranscript, Path: path})
+		}
+	}
+	return files
+}
+
+// SearchHistory scans files for query, in parallel, and returns the matches
+// newest first. Files are read on demand rather than indexed, so memory stays
+// flat however much history there is. A line repeated within a session (a
+// redrawn screen) is reported once.
+func SearchHistory(ctx context.Context, files []HistoryFile, query HistoryQuery) []HistoryMatch {
+	needle := strings.ToLower(strings.TrimSpace(query.Text))
+	if needle == "" {
+		return nil
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	jobs := make(chan HistoryFile)
+	var (
+		mu      sync.Mutex
+		matches []HistoryMatch
+		wg      sync.WaitGroup
+	)
+	for range min(8, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				found := searchHistoryFile(ctx, file, needle, query.Since)
+				mu.Lock()
+				matches = append(matches, found...)
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Time.After(matches[j].Time)
+	})
+	seen := make(map[[2]string]bool)
+	out := matches[:0]
+	for _, m := range matches {
+		key := [2]string{m.SessionID, m.Snippet}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, m)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// searchHistoryFile returns the matching lines of one file. Claude log lines
+// carry their own time; transcript lines are dated by their file's last write.
+func searchHistoryFile(ctx context.Context, file HistoryFile, needle string, since time.Time) []HistoryMatch {
+	info, err := os.Stat(file.Path)
+	if err != nil || info.ModTime().Before(since) {
+		return nil
+	}
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	// Most Claude log lines don't contain the query at all; skip decoding
+	// them unless JSON escaping could hide a match
+	prefilter := file.Source == HistorySourceClaude && !strings.ContainsAny(needle, `"\`)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	var matches []HistoryMatch
+	for n := 0; scanner.Scan(); n++ {
+		if n%1000 == 0 && ctx.Err() != nil {
+			break
+		}
+		raw := scanner.Bytes()
+		if prefilter && !strings.Contains(strings.ToLower(string(raw)), needle) {
+			continue
+		}
+		text, when := historyLine(file.Source, raw, info.ModTime())
+		if text == "" || when.Before(since) || !strings.Contains(strings.ToLower(text), needle) {
+			continue
+		}
+		matches = append(matches, HistoryMatch{
+			SessionID: file.SessionID,
+			Title:     file.Title,
+			Source:    file.Source,
+			Time:      when,
+			Snippet:   strings.Join(strings.Fields(snippetFromText(text, needle, historySnippetWindow)), " "),
+			Path:      file.Path,
+		})
+	}
+	return matches
+}
+
+// historyLine returns the searchable text of a line and when it was written
+func historyLine(source string, raw []byte, modTime time.Time) (string, time.Time) {
+	if source == HistorySourceTranscript {
+		return strings.TrimSpace(PlainTranscript(string(raw))), modTime
+	}
+	var record claudeJSONLRecord
+	if err := json.Unmarshal(raw, &record); err != nil || len(record.Message) == 0 {
+		return "", modTime
+	}
+	var msg claudeMessage
+	if err := json.Unmarshal(record.Message, &msg); err != nil {
+		return "", modTime
+	}
+	when := modTime
+	if t, err := time.Parse(time.RFC3339Nano, record.Timestamp); err == nil {
+		when = t
+	}
+	return formatMessageContent(msg), when
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeHistoryFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchHistoryTranscriptsAndClaude(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	transcript := filepath.Join(dir, "current.log")
+	writeHistoryFile(t, transcript,
+		"\x1b[31mError: connection refused\x1b[0m\r\n"+
+			"progress 10%\rError: connection refused\r\n"+
+			"all good\r\n", old)
+	claude := filepath.Join(dir, "claude.jsonl")
+	writeHistoryFile(t, claude,
+		`{"type":"user","timestamp":"2026-02-01T09:00:00Z","message":{"role":"user","content":"why was the CONNECTION REFUSED?"}}`+"\n"+
+			`{"type":"assistant","timestamp":"2026-02-01T09:01:00Z","message":{"role":"assistant","content":[{"type":"text","text":"The port was closed."}]}}`+"\n"+
+			"not json\n", old)
+
+	files := []HistoryFile{
+		{SessionID: "s1", Title: "api", Source: HistorySourceTranscript, Path: transcript},
+		{SessionID: "s2", Title: "web", Source: HistorySourceClaude, Path: claude},
+	}
+	matches := SearchHistory(context.Background(), files, HistoryQuery{Text: "connection refused"})
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches (redrawn line reported once), got %+v", matches)
+	}
+
+	// Claude matches are dated by their record, so they sort first here
+	if matches[0].SessionID != "s2" || matches[0].Source != HistorySourceClaude {
+		t.Errorf("newest match should be the Claude one: %+v", matches[0])
+	}
+	if want := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC); !matches[0].Time.Equal(want) {
+		t.Errorf("claude match time = %v, want %v", matches[0].Time, want)
+	}
+	if matches[0].Snippet != "User: why was the CONNECTION REFUSED?" {
+		t.Errorf("claude snippet = %q", matches[0].Snippet)
+	}
+	if matches[1].Snippet != "Error: connection refused" || !matches[1].Time.Equal(old) {
+		t.Errorf("transcript match should be cleaned and dated by the file: %+v", matches[1])
+	}
+
+	since := SearchHistory(context.Background(), files, HistoryQuery{Text: "connection refused", Since: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)})
+	if len(since) != 0 {
+		t.Errorf("files last written before Since should be skipped, got %+v", since)
+	}
+
+	limited := SearchHistory(context.Background(), files, HistoryQuery{Text: "o", Limit: 1})
+	if len(limited) != 1 {
+		t.Errorf("limit should cap matches, got %d", len(limited))
+	}
+	if got := SearchHistory(context.Background(), files, HistoryQuery{Text: "  "}); got != nil {
+		t.Errorf("blank query should match nothing, got %+v", got)
+	}
+}
+
+func TestHistoryFilesIncludesRemovedSessions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	for _, id := range []string{"live-1", "gone-1"} {
+		dir, err := TranscriptDir(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeHistoryFile(t, filepath.Join(dir, transcriptCurrent), "hello\n", now)
+	}
+
+	files := HistoryFiles([]*Instance{{ID: "live-1", Title: "Live", Tool: "shell"}})
+	if len(files) != 2 {
+		t.Fatalf("expected 2 history files, got %+v", files)
+	}
+	titles := map[string]string{}
+	for _, f := range files {
+		titles[f.SessionID] = f.Title
+	}
+	if titles["live-1"] != "Live" || titles["gone-1"] != "" {
+		t.Errorf("unexpected titles: %v", titles)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
 )
 
@@ -203,9 +204,9 @@ func (w *StatusFileWatcher) processFile(filePath string) {
 
 // GetHooksDir returns the path to the hooks status directory.
 func GetHooksDir() string {
-	home, err := os.UserHomeDir()
+	home, err := deck.Home()
 	if err != nil {
 		return filepath.Join(os.TempDir(), ".agent-deck", "hooks")
 	}
-	return filepath.Join(home, ".agent-deck", "hooks")
+	return filepath.Join(home, "hooks")
 }
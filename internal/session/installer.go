@@ -0,0 +1,456 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
+	"github.com/asheshgoplani/agent-deck/internal/platform"
+)
+
+// Installable components, in install order
+const (
+	InstallComponentConfig      = "config"
+	InstallComponentCompletions = "completions"
+	InstallComponentRestore     = "restore-on-boot"
+	InstallComponentBridge      = "bridge"
+)
+
+// systemdRestoreServiceName is the user unit that restores sessions after boot
+func systemdRestoreServiceName() string {
+	return deck.UnitPrefix() + "-restore.service"
+}
+
+// restoreLaunchdLabel is the launchd label of the restore-on-login agent
+func restoreLaunchdLabel() string {
+	return deck.LabelPrefix() + ".restore"
+}
+
+// systemdRestoreServiceTemplate restarts sessions whose tmux sessions were
+// lost in a reboot, once per login session
+const systemdRestoreServiceTemplate = `[Unit]
+Description=Agent Deck Session Restore
+After=default.target
+
+[Service]
+Type=oneshot
+ExecStart=__AGENT_DECK__ session restore --all-profiles
+WorkingDirectory=__HOME__
+Environment=PATH=__PATH__
+Environment=HOME=__HOME__
+
+[Install]
+WantedBy=default.target
+`
+
+// restorePlistTemplate runs the restore once at login (RunAtLoad). It is
+// written but not loaded, so installing does not restore anything right away.
+const restorePlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+    <key>Label</key>
+    <string>__LABEL__</string>
+
+    <key>ProgramArguments</key>
+    <array>
+        <string>__AGENT_DECK__</string>
+        <string>session</string>
+        <string>restore</string>
+        <string>--all-profiles</string>
+    </array>
+
+    <key>RunAtLoad</key>
+    <true/>
+
+    <key>StandardOutPath</key>
+    <string>__LOG_PATH__</string>
+
+    <key>StandardErrorPath</key>
+    <string>__LOG_PATH__</string>
+
+    <key>EnvironmentVariables</key>
+    <dict>
+        <key>PATH</key>
+        <string>__PATH__</string>
+        <key>HOME</key>
+        <string>__HOME__</string>
+    </dict>
+</dict>
+</plist>
+`
+
+// InstallOptions selects what InstallAll installs
+type InstallOptions struct {
+	// Profile to initialize ("" = default profile)
+	Profile string
+
+	// Completions maps a shell (bash, zsh, fish) to its completion script;
+	// empty skips shell completions
+	Completions map[string]string
+
+	// RestoreOnBoot installs the unit that restores sessions after a reboot
+	RestoreOnBoot bool
+
+	// Bridge installs bridge.py and its daemon (needs Telegram or Slack)
+	Bridge bool
+}
+
+// InstalledArtifact is one file or service written by InstallAll
+type InstalledArtifact struct {
+	Component string `json:"component"`
+	Path      string `json:"path"`
+	Note      string `json:"note,omitempty"`
+}
+
+// installTx records how to undo each install step so a failed InstallAll
+// leaves the machine as it found it
+type installTx struct {
+	undo      []func()
+	artifacts []InstalledArtifact
+}
+
+// writeFile writes a file, remembering its previous content (or absence)
+func (tx *installTx) writeFile(component, path string, data []byte, perm os.FileMode) error {
+	prev, readErr := os.ReadFile(path)
+	existed := readErr == nil
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	tx.onRollback(func() {
+		if existed {
+			_ = os.WriteFile(path, prev, perm)
+		} else {
+			_ = os.Remove(path)
+		}
+	})
+	tx.artifacts = append(tx.artifacts, InstalledArtifact{Component: component, Path: path})
+	return nil
+}
+
+func (tx *installTx) onRollback(fn func()) {
+	tx.undo = append(tx.undo, fn)
+}
+
+func (tx *installTx) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+}
+
+// InstallAll installs the default config, shell completions, the
+// restore-on-boot unit and the conductor bridge in one go. If any step fails,
+// everything written so far is rolled back and the error is returned.
+func InstallAll(opts InstallOptions) ([]InstalledArtifact, error) {
+	tx := &installTx{}
+	steps := []func(*installTx, InstallOptions) error{
+		installConfig, installCompletions, installRestoreUnit, installBridge,
+	}
+	for _, step := range steps {
+		if err := step(tx, opts); err != nil {
+			tx.rollback()
+			return nil, err
+		}
+	}
+	return tx.artifacts, nil
+}
+
+func installConfig(tx *installTx, opts InstallOptions) error {
+	result, err := RunSetup(SetupOptions{Profile: opts.Profile})
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	configPath, _ := GetUserConfigPath()
+	if result.ConfigCreated {
+		tx.onRollback(func() { _ = os.Remove(configPath) })
+		tx.artifacts = append(tx.artifacts, InstalledArtifact{Component: InstallComponentConfig, Path: configPath})
+	}
+	// Directories setup created did not exist before, so all they hold
+	// (state.db, config.json) is ours to remove
+	for _, dir := range result.CreatedDirs {
+		tx.onRollback(func() { _ = os.RemoveAll(dir) })
+	}
+	return nil
+}
+
+// CompletionPath returns where a shell's completion script is installed
+func CompletionPath(shell string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	switch shell {
+	case "bash":
+		return filepath.Join(dataHome, "bash-completion", "completions", "agent-deck"), nil
+	case "zsh":
+		return filepath.Join(dataHome, "zsh", "site-functions", "_agent-deck"), nil
+	case "fish":
+		return filepath.Join(configHome, "fish", "completions", "agent-deck.fish"), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (use bash, zsh or fish)", shell)
+}
+
+func installCompletions(tx *installTx, opts InstallOptions) error {
+	shells := make([]string, 0, len(opts.Completions))
+	for shell := range opts.Completions {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	for _, shell := range shells {
+		path, err := CompletionPath(shell)
+		if err != nil {
+			return fmt.Errorf("completions: %w", err)
+		}
+		if err := tx.writeFile(InstallComponentCompletions, path, []byte(opts.Completions[shell]), 0o644); err != nil {
+			return fmt.Errorf("completions: %w", err)
+		}
+		if shell == "zsh" {
+			tx.artifacts[len(tx.artifacts)-1].Note = "add fpath+=(" + filepath.Dir(path) + ") before compinit"
+		}
+	}
+	return nil
+}
+
+// RestoreUnitPath returns the path of the restore-on-boot unit for this platform
+func RestoreUnitPath() (string, error) {
+	switch platform.Detect() {
+	case platform.PlatformMacOS:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "LaunchAgents", restoreLaunchdLabel()+".plist"), nil
+	case platform.PlatformLinux, platform.PlatformWSL2:
+		dir, err := SystemdUserDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, systemdRestoreServiceName()), nil
+	}
+	return "", fmt.Errorf("unsupported platform %s for restore on boot", platform.Detect())
+}
+
+// GenerateRestoreUnit returns the restore-on-boot unit (systemd service or
+// launchd plist) for this platform
+func GenerateRestoreUnit() (string, error) {
+	agentDeckPath := findAgentDeck()
+	if agentDeckPath == "" {
+		return "", fmt.Errorf("agent-deck not found in PATH")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	tmpl := systemdRestoreServiceTemplate
+	if platform.Detect() == platform.PlatformMacOS {
+		tmpl = restorePlistTemplate
+	}
+	logDir, _ := GetAgentDeckDir()
+	unit := strings.ReplaceAll(withDeckEnv(tmpl), "__AGENT_DECK__", agentDeckPath)
+	unit = strings.ReplaceAll(unit, "__LABEL__", restoreLaunchdLabel())
+	unit = strings.ReplaceAll(unit, "__LOG_PATH__", filepath.Join(logDir, "logs", "restore.log"))
+	unit = strings.ReplaceAll(unit, "__HOME__", home)
+	unit = strings.ReplaceAll(unit, "__PATH__", buildDaemonPath(agentDeckPath))
+	return unit, nil
+}
+
+func installRestoreUnit(tx *installTx, opts InstallOptions) error {
+	if !opts.RestoreOnBoot {
+		return nil
+	}
+	path, err := RestoreUnitPath()
+	if err != nil {
+		return fmt.Errorf("restore on boot: %w", err)
+	}
+	unit, err := GenerateRestoreUnit()
+	if err != nil {
+		return fmt.Errorf("restore on boot: %w", err)
+	}
+	if err := tx.writeFile(InstallComponentRestore, path, []byte(unit), 0o644); err != nil {
+		return fmt.Errorf("restore on boot: %w", err)
+	}
+	if platform.Detect() == platform.PlatformMacOS {
+		tx.artifacts[len(tx.artifacts)-1].Note = "runs at next login"
+		return nil
+	}
+	if !systemdUserAvailable() {
+		tx.artifacts[len(tx.artifacts)-1].Note = "systemd user session not available; enable it later with: systemctl --user enable " + systemdRestoreServiceName()
+		return nil
+	}
+	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+	// enable without --now: restoring at install time would revive stopped sessions
+	if err := exec.Command("systemctl", "--user", "enable", systemdRestoreServiceName()).Run(); err != nil {
+		return fmt.Errorf("restore on boot: failed to enable %s: %w", systemdRestoreServiceName(), err)
+	}
+	tx.onRollback(func() {
+		_ = exec.Command("systemctl", "--user", "disable", systemdRestoreServiceName()).Run()
+	})
+	return nil
+}
+
+func installBridge(tx *installTx, opts InstallOptions) error {
+	if !opts.Bridge {
+		return nil
+	}
+	dir, err := ConductorDir()
+	if err != nil {
+		return fmt.Errorf("bridge: %w", err)
+	}
+	if err := tx.writeFile(InstallComponentBridge, filepath.Join(dir, "bridge.py"), []byte(conductorBridgePy), 0o755); err != nil {
+		return fmt.Errorf("bridge: %w", err)
+	}
+	tx.onRollback(func() { _ = UninstallBridgeDaemon() })
+	unitPath, err := InstallBridgeDaemon()
+	if err != nil {
+		return fmt.Errorf("bridge: %w", err)
+	}
+	tx.artifacts = append(tx.artifacts, InstalledArtifact{Component: InstallComponentBridge, Path: unitPath})
+	return nil
+}
+
+// UninstallAll stops and removes everything InstallAll and conductor setup
+// install: the bridge daemon, heartbeat timers, the restore-on-boot unit and
+// shell completions. With purge, ~/.agent-deck (sessions, config, logs) is
+// removed too. It keeps going past failures and returns the removed paths
+// along with the joined errors.
+func UninstallAll(purge bool) ([]string, error) {
+	var removed []string
+	var errs []error
+	remove := func(path string) {
+		if err := os.Remove(path); err == nil {
+			removed = append(removed, path)
+		} else if !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	if conductors, err := ListConductors(); err == nil {
+		for _, c := range conductors {
+			if err := UninstallHeartbeatDaemon(c.Name); err != nil {
+				errs = append(errs, fmt.Errorf("heartbeat %s: %w", c.Name, err))
+			}
+		}
+	}
+	if isBridgeDaemonEnabled() {
+		removed = append(removed, "bridge daemon")
+	}
+	if err := UninstallBridgeDaemon(); err != nil {
+		errs = append(errs, fmt.Errorf("bridge: %w", err))
+	}
+
+	if path, err := RestoreUnitPath(); err == nil {
+		if platform.Detect() != platform.PlatformMacOS {
+			_ = exec.Command("systemctl", "--user", "disable", systemdRestoreServiceName()).Run()
+		}
+		remove(path)
+	}
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		if path, err := CompletionPath(shell); err == nil {
+			remove(path)
+		}
+	}
+
+	if purge {
+		dir, err := GetAgentDeckDir()
+		if err != nil {
+			errs = append(errs, err)
+		} else if _, err := os.Stat(dir); err == nil {
+			if err := os.RemoveAll(dir); err != nil {
+				errs = append(errs, err)
+			} else {
+				removed = append(removed, dir)
+			}
+		}
+	}
+	return removed, errors.Join(errs...)
+}
+
+// RefreshArtifacts regenerates every templated artifact that is already
+// installed from the templates embedded in this binary and restarts the
+// daemons that run them: bridge.py and its daemon, conductor heartbeat
+// scripts and timers, the restore-on-boot unit and the given shell
+// completions. It runs after a self-update, from the new binary. Nothing is
+// installed that was not there before.
+func RefreshArtifacts(completions map[string]string) ([]InstalledArtifact, error) {
+	var refreshed []InstalledArtifact
+	var errs []error
+
+	if unitPath, err := bridgeUnitPath(); err == nil {
+		if _, err := os.Stat(unitPath); err == nil {
+			if _, _, err := UpgradeBridgeDaemon(true, true); err != nil {
+				errs = append(errs, fmt.Errorf("bridge: %w", err))
+			} else {
+				refreshed = append(refreshed, InstalledArtifact{Component: InstallComponentBridge, Path: unitPath, Note: "restarted"})
+			}
+		}
+	}
+
+	conductors, _ := ListConductors()
+	for _, meta := range conductors {
+		if !HeartbeatScriptInstalled(meta.Name) {
+			continue
+		}
+		if err := InstallHeartbeatScript(meta.Name, meta.Profile); err != nil {
+			errs = append(errs, fmt.Errorf("heartbeat %s: %w", meta.Name, err))
+			continue
+		}
+		dir, _ := ConductorNameDir(meta.Name)
+		artifact := InstalledArtifact{Component: "heartbeat", Path: filepath.Join(dir, "heartbeat.sh")}
+		if meta.HeartbeatEnabled {
+			if err := InstallHeartbeatDaemon(meta.Name, meta.Profile, HeartbeatIntervalFor(&meta), EffectiveHeartbeatSchedule(&meta)); err != nil {
+				errs = append(errs, fmt.Errorf("heartbeat %s: %w", meta.Name, err))
+				continue
+			}
+			artifact.Note = "timer restarted"
+		}
+		refreshed = append(refreshed, artifact)
+	}
+
+	if path, err := RestoreUnitPath(); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			if unit, err := GenerateRestoreUnit(); err != nil {
+				errs = append(errs, fmt.Errorf("restore on boot: %w", err))
+			} else if err := os.WriteFile(path, []byte(unit), 0o644); err != nil {
+				errs = append(errs, fmt.Errorf("restore on boot: %w", err))
+			} else {
+				if platform.Detect() != platform.PlatformMacOS {
+					_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+				}
+				refreshed = append(refreshed, InstalledArtifact{Component: InstallComponentRestore, Path: path})
+			}
+		}
+	}
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		path, err := CompletionPath(shell)
+		if err != nil || completions[shell] == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(completions[shell]), 0o644); err != nil {
+			errs = append(errs, fmt.Errorf("completions: %w", err))
+			continue
+		}
+		refreshed = append(refreshed, InstalledArtifact{Component: InstallComponentCompletions, Path: path})
+	}
+
+	return refreshed, errors.Join(errs...)
+}
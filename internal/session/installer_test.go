@@ -0,0 +1,70 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallAll_WritesCompletions(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	artifacts, err := InstallAll(InstallOptions{
+		Completions: map[string]string{"bash": "# bash\n", "fish": "# fish\n"},
+	})
+	if err != nil {
+		t.Fatalf("InstallAll: %v", err)
+	}
+
+	want := map[string]string{
+		filepath.Join(tmpHome, ".local", "share", "bash-completion", "completions", "agent-deck"): "# bash\n",
+		filepath.Join(tmpHome, ".config", "fish", "completions", "agent-deck.fish"):               "# fish\n",
+	}
+	for path, content := range want {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q (err=%v), want %q", path, data, err, content)
+		}
+	}
+	if len(artifacts) != 3 || artifacts[0].Component != InstallComponentConfig {
+		t.Errorf("artifacts = %+v, want config and two completions", artifacts)
+	}
+}
+
+func TestInstallAll_RollsBackOnFailure(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	// An existing completion file must be restored, not deleted
+	bashPath, _ := CompletionPath("bash")
+	if err := os.MkdirAll(filepath.Dir(bashPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bashPath, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// "tcsh" sorts after bash, so bash is written before the failure
+	_, err := InstallAll(InstallOptions{
+		Completions: map[string]string{"bash": "new", "tcsh": "x"},
+	})
+	if err == nil {
+		t.Fatal("expected error for unsupported shell")
+	}
+
+	if data, _ := os.ReadFile(bashPath); string(data) != "old" {
+		t.Errorf("bash completion = %q, want restored %q", data, "old")
+	}
+	if _, err := os.Stat(filepath.Join(tmpHome, ".agent-deck")); !os.IsNotExist(err) {
+		t.Errorf("expected ~/.agent-deck to be rolled back, stat err = %v", err)
+	}
+}
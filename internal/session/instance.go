@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -34,6 +35,9 @@ const (
 	StatusIdle     Status = "idle"
 	StatusError    Status = "error"
 	StatusStarting Status = "starting" // Session is being created (tmux initializing)
+	// StatusThrottled means the tool is backing off from a provider rate
+	// limit or quota (see ToolThrottleDetector), not doing real work
+	StatusThrottled Status = "throttled"
 )
 
 const wrapperPlaceholder = "{command}"
@@ -85,10 +89,11 @@ type Instance struct {
 	OpenCodeStartedAt  int64     `json:"-"` // Unix millis when we started OpenCode (for session matching, not persisted)
 
 	// Codex CLI integration
-	CodexSessionID  string    `json:"codex_session_id,omitempty"`
-	CodexDetectedAt time.Time `json:"codex_detected_at,omitempty"`
-	CodexStartedAt  int64     `json:"-"` // Unix millis when we started Codex (for session matching, not persisted)
-	lastCodexScanAt time.Time // Rate-limits expensive ~/.codex/sessions scans
+	CodexSessionID  string                 `json:"codex_session_id,omitempty"`
+	CodexDetectedAt time.Time              `json:"codex_detected_at,omitempty"`
+	CodexStartedAt  int64                  `json:"-"`                         // Unix millis when we started Codex (for session matching, not persisted)
+	CodexAnalytics  *CodexSessionAnalytics `json:"codex_analytics,omitempty"` // Per-session analytics
+	lastCodexScanAt time.Time              // Rate-limits expensive ~/.codex/sessions scans
 
 	// Latest user input for context (extracted from session files)
 	LatestPrompt      string    `json:"latest_prompt,omitempty"`
@@ -107,6 +112,17 @@ type Instance struct {
 	// JSON structure: {"tool": "claude", "options": {...}}
 	ToolOptionsJSON json.RawMessage `json:"tool_options,omitempty"`
 
+	// StartReason records why the session exists: "manual", "fork:<id>",
+	// "heartbeat:<task>", "webhook:<id>", "template:<name>" (see provenance.go)
+	StartReason string `json:"start_reason,omitempty"`
+
+	// NetworkPolicy restricts the agent's network access: "" (open), "offline"
+	// or "restricted:host,..." (see network_policy.go)
+	NetworkPolicy string `json:"network_policy,omitempty"`
+
+	// Features holds this session's feature flag overrides (see feature_flags.go)
+	Features map[string]bool `json:"features,omitempty"`
+
 	tmuxSession *tmux.Session // Internal tmux session
 
 	// Hook-based status detection (set by StatusFileWatcher from Claude Code hooks)
@@ -128,6 +144,23 @@ type Instance struct {
 	lastIdleCheck     time.Time // When we last did a full check for an idle session
 	lastKnownActivity int64     // Last window_activity timestamp seen
 
+	// State durations (not serialized; loaded from status_tracking/busy_daily)
+	stateSince time.Time     // when Status last changed
+	busyToday  time.Duration // time spent running today, as of load
+
+	// Hysteresis: a newly detected status must be seen on consecutive polls
+	// before it replaces Status (see [status] dwell_polls)
+	pendingStatus Status
+	pendingPolls  int
+
+	// throttleMessage is the rate-limit line behind StatusThrottled
+	throttleMessage string
+
+	// Risk scoring of recent tool calls (see AssessRisk); not serialized
+	risk          *SessionRisk
+	riskCheckedAt time.Time
+	riskChecking  bool
+
 	// lastStartTime tracks when Start() was called
 	// Used to provide grace period for tmux session creation (prevents error flash)
 	// Not serialized - only relevant for current TUI session
@@ -148,6 +181,31 @@ func (inst *Instance) GetStatusThreadSafe() Status {
 	return s
 }
 
+// ThrottleMessage returns the tool's rate-limit or backoff message while the
+// session is StatusThrottled, else "".
+func (inst *Instance) ThrottleMessage() string {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	if inst.Status != StatusThrottled {
+		return ""
+	}
+	return inst.throttleMessage
+}
+
+// StatusDetail returns the status engine's verdict from the last pane poll:
+// confidence, the signal behind it and the matched lines. ok is false when
+// the status was never read from the pane (no tmux session, or hook-driven).
+func (inst *Instance) StatusDetail() (detail tmux.StatusResult, ok bool) {
+	inst.mu.RLock()
+	tmuxSession := inst.tmuxSession
+	inst.mu.RUnlock()
+	if tmuxSession == nil {
+		return tmux.StatusResult{}, false
+	}
+	detail = tmuxSession.LastStatusResult()
+	return detail, detail.State != ""
+}
+
 // SetStatusThreadSafe sets the session status with write-lock protection.
 func (inst *Instance) SetStatusThreadSafe(s Status) {
 	inst.mu.Lock()
@@ -155,6 +213,33 @@ func (inst *Instance) SetStatusThreadSafe(s Status) {
 	inst.mu.Unlock()
 }
 
+// StateDuration returns how long the session has been in its current status
+// (0 if unknown).
+func (inst *Instance) StateDuration(now time.Time) time.Duration {
+	inst.mu.RLock()
+	since := inst.stateSince
+	inst.mu.RUnlock()
+	if since.IsZero() || now.Before(since) {
+		return 0
+	}
+	return now.Sub(since)
+}
+
+// StateSince returns when the session entered its current status (zero if unknown).
+func (inst *Instance) StateSince() time.Time {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.stateSince
+}
+
+// BusyToday returns time spent running today as recorded when the session was
+// loaded. It is only accumulated by the TUI's status sync, so it may lag.
+func (inst *Instance) BusyToday() time.Duration {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.busyToday
+}
+
 // GetToolThreadSafe returns the tool name with read-lock protection.
 func (inst *Instance) GetToolThreadSafe() string {
 	inst.mu.RLock()
@@ -244,6 +329,7 @@ func NewInstance(title, projectPath string) *Instance {
 		Tool:        "shell",
 		Status:      StatusIdle,
 		CreatedAt:   time.Now(),
+		StartReason: StartReasonManual,
 		tmuxSession: tmuxSess,
 	}
 }
@@ -270,12 +356,18 @@ func NewInstanceWithTool(title, projectPath, tool string) *Instance {
 		Tool:        tool,
 		Status:      StatusIdle,
 		CreatedAt:   time.Now(),
+		StartReason: StartReasonManual,
 		tmuxSession: tmuxSess,
 	}
 
 	// Claude session ID will be detected from files Claude creates
 	// No pre-assignment needed
 
+	// Custom tools from config.toml run their configured command
+	if def := GetToolDef(tool); def != nil && !IsBuiltinTool(tool) {
+		inst.Command = def.Command
+	}
+
 	return inst
 }
 
@@ -483,16 +575,16 @@ func (i *Instance) buildGeminiCommand(baseCommand string) string {
 
 	envPrefix := i.buildEnvSourceCommand()
 
-	// Determine if YOLO mode is enabled (per-session overrides global config)
-	yoloMode := false
+	// [gemini] with the active profile's [profiles.<name>.gemini] overrides
+	settings := GeminiSettings{}
+	if userConfig, _ := LoadUserConfig(); userConfig != nil {
+		settings = userConfig.GetProfileGeminiSettings(GetEffectiveProfile(""))
+	}
+
+	// Determine if YOLO mode is enabled (per-session overrides config)
+	yoloMode := settings.YoloMode
 	if i.GeminiYoloMode != nil {
 		yoloMode = *i.GeminiYoloMode
-	} else {
-		// Check global config
-		userConfig, _ := LoadUserConfig()
-		if userConfig != nil {
-			yoloMode = userConfig.Gemini.YoloMode
-		}
 	}
 
 	yoloFlag := ""
@@ -506,25 +598,29 @@ func (i *Instance) buildGeminiCommand(baseCommand string) string {
 	modelFlag := ""
 	if i.GeminiModel != "" {
 		modelFlag = " --model " + i.GeminiModel
-	} else if i.GeminiSessionID == "" {
+	} else if i.GeminiSessionID == "" && settings.DefaultModel != "" {
 		// Only apply default model for NEW sessions (not resumes)
-		userConfig, _ := LoadUserConfig()
-		if userConfig != nil && userConfig.Gemini.DefaultModel != "" {
-			modelFlag = " --model " + userConfig.Gemini.DefaultModel
-		}
+		modelFlag = " --model " + settings.DefaultModel
+	}
+
+	// Checkpointing applies to resumes too: /restore only sees snapshots
+	// taken while it was on
+	checkpointFlag := ""
+	if settings.Checkpointing {
+		checkpointFlag = " --checkpointing"
 	}
 
 	// If baseCommand is just "gemini", handle specially
 	if baseCommand == "gemini" {
 		// If we already have a session ID, use simple resume
 		if i.GeminiSessionID != "" {
-			return envPrefix + fmt.Sprintf("tmux set-environment GEMINI_YOLO_MODE %s; tmux set-environment GEMINI_SESSION_ID %s; gemini --resume %s%s%s", yoloEnv, i.GeminiSessionID, i.GeminiSessionID, yoloFlag, modelFlag)
+			return envPrefix + fmt.Sprintf("tmux set-environment GEMINI_YOLO_MODE %s; tmux set-environment GEMINI_SESSION_ID %s; gemini --resume %s%s%s%s", yoloEnv, i.GeminiSessionID, i.GeminiSessionID, yoloFlag, modelFlag, checkpointFlag)
 		}
 
 		// Start Gemini fresh - session ID will be captured when user interacts
 		// The previous capture-resume approach (gemini --output-format json ".") would hang
 		// because Gemini processes the "." prompt which takes too long
-		return envPrefix + fmt.Sprintf(`tmux set-environment GEMINI_YOLO_MODE %s; gemini%s%s`, yoloEnv, yoloFlag, modelFlag)
+		return envPrefix + fmt.Sprintf(`tmux set-environment GEMINI_YOLO_MODE %s; gemini%s%s%s`, yoloEnv, yoloFlag, modelFlag, checkpointFlag)
 	}
 
 	// For custom commands (e.g., resume commands), return as-is
@@ -613,6 +709,18 @@ func (i *Instance) resolveCodexYoloFlag() string {
 	return ""
 }
 
+// resolveCodexModelFlag returns " -m <model>" for the per-session model, else
+// [codex] default_model, or "" to let Codex pick.
+func (i *Instance) resolveCodexModelFlag() string {
+	if opts := i.GetCodexOptions(); opts != nil && opts.Model != "" {
+		return " -m " + opts.Model
+	}
+	if config, err := LoadUserConfig(); err == nil && config != nil && config.Codex.DefaultModel != "" {
+		return " -m " + config.Codex.DefaultModel
+	}
+	return ""
+}
+
 // Codex stores sessions in ~/.codex/sessions/YYYY/MM/DD/*.jsonl
 // Resume: codex resume <session-id> or codex resume --last
 // Also sources .env files from [shell].env_files
@@ -626,18 +734,18 @@ func (i *Instance) buildCodexCommand(baseCommand string) string {
 		i.ID, i.Title, i.Tool)
 	envPrefix += agentdeckEnvPrefix
 
-	yoloFlag := i.resolveCodexYoloFlag()
+	flags := i.resolveCodexYoloFlag() + i.resolveCodexModelFlag()
 
 	// If baseCommand is just "codex", handle specially
 	if baseCommand == "codex" {
 		// If we already have a session ID, use resume
 		if i.CodexSessionID != "" {
 			return envPrefix + fmt.Sprintf("tmux set-environment CODEX_SESSION_ID %s; codex%s resume %s",
-				i.CodexSessionID, yoloFlag, i.CodexSessionID)
+				i.CodexSessionID, flags, i.CodexSessionID)
 		}
 
 		// Start Codex fresh - session ID will be captured async after startup
-		return envPrefix + "codex" + yoloFlag
+		return envPrefix + "codex" + flags
 	}
 
 	// For custom commands (e.g., resume commands), preserve env propagation.
@@ -1096,6 +1204,7 @@ func (i *Instance) UpdateCodexSession(excludeIDs map[string]bool) {
 	if i.Tool != "codex" {
 		return
 	}
+	defer i.updateCodexAnalytics()
 
 	envSessionID := ""
 
@@ -1133,6 +1242,18 @@ func (i *Instance) UpdateCodexSession(excludeIDs map[string]bool) {
 	}
 }
 
+// updateCodexAnalytics refreshes token counts, cost, and model from the rollout file.
+func (i *Instance) updateCodexAnalytics() {
+	if i.CodexSessionID == "" {
+		return
+	}
+	if i.CodexAnalytics == nil {
+		i.CodexAnalytics = &CodexSessionAnalytics{}
+	}
+	// Best effort: a rollout may not be written yet
+	_ = UpdateCodexAnalyticsFromDisk(i.CodexSessionID, i.CodexAnalytics)
+}
+
 // buildGenericCommand builds commands for custom tools defined in [tools.*]
 // If the tool has session resume config, builds capture-resume command similar to Claude/Gemini
 // Otherwise returns the base command as-is
@@ -1153,9 +1274,15 @@ func (i *Instance) buildGenericCommand(baseCommand string) string {
 	if toolDef == nil {
 		return envPrefix + baseCommand // No custom config, return with env prefix
 	}
+	if !IsBuiltinTool(i.Tool) {
+		if baseCommand == "" {
+			baseCommand = toolDef.Command
+		}
+		baseCommand = i.expandToolTemplate(baseCommand, nil)
+	}
 
-	// Check if tool supports session resume (needs both resume_flag and session_id_env)
-	if toolDef.ResumeFlag == "" || toolDef.SessionIDEnv == "" {
+	// Check if tool supports session resume (needs resume_flag or resume_command, and session_id_env)
+	if (toolDef.ResumeFlag == "" && toolDef.ResumeCommand == "") || toolDef.SessionIDEnv == "" {
 		// No session resume support, just add dangerous flag if configured
 		if toolDef.DangerousMode && toolDef.DangerousFlag != "" {
 			return envPrefix + fmt.Sprintf("%s %s", baseCommand, toolDef.DangerousFlag)
@@ -1179,9 +1306,9 @@ func (i *Instance) buildGenericCommand(baseCommand string) string {
 
 	// If we have an existing session ID, just resume
 	if existingSessionID != "" {
-		return envPrefix + fmt.Sprintf("tmux set-environment %s %s && %s %s %s%s",
+		return envPrefix + fmt.Sprintf("tmux set-environment %s %s && %s",
 			toolDef.SessionIDEnv, existingSessionID,
-			baseCommand, toolDef.ResumeFlag, existingSessionID, dangerousFlag)
+			i.genericResumeCommand(toolDef, baseCommand, existingSessionID))
 	}
 
 	// No existing session ID - need to capture it on first run
@@ -1205,11 +1332,11 @@ func (i *Instance) buildGenericCommand(baseCommand string) string {
 		`session_id=$(%s %s "." 2>/dev/null | jq -r '%s' 2>/dev/null) || session_id=""; `+
 			`if [ -n "$session_id" ] && [ "$session_id" != "null" ]; then `+
 			`tmux set-environment %s "$session_id"; `+
-			`%s %s "$session_id"%s; `+
+			`%s; `+
 			`else %s%s; fi`,
 		baseCommand, toolDef.OutputFormatFlag, toolDef.SessionIDJsonPath,
 		toolDef.SessionIDEnv,
-		baseCommand, toolDef.ResumeFlag, dangerousFlag,
+		i.genericResumeCommand(toolDef, baseCommand, `"$session_id"`),
 		baseCommand, dangerousFlag)
 }
 
@@ -1237,7 +1364,7 @@ func (i *Instance) CanRestartGeneric() bool {
 		return false
 	}
 	// Can restart if we have resume support AND an existing session ID
-	if toolDef.ResumeFlag == "" || toolDef.SessionIDEnv == "" {
+	if (toolDef.ResumeFlag == "" && toolDef.ResumeCommand == "") || toolDef.SessionIDEnv == "" {
 		return false
 	}
 	return i.GetGenericSessionID() != ""
@@ -1250,25 +1377,27 @@ func (i *Instance) applyWrapper(command string) (string, error) {
 			wrapper = toolDef.Wrapper
 		}
 	}
-	if wrapper == "" {
-		return command, nil
-	}
-	if strings.Contains(wrapper, wrapperPlaceholder) {
-		return strings.ReplaceAll(wrapper, wrapperPlaceholder, command), nil
+	if wrapper != "" {
+		if strings.Contains(wrapper, wrapperPlaceholder) {
+			command = strings.ReplaceAll(wrapper, wrapperPlaceholder, command)
+		} else {
+			command = wrapper
+		}
 	}
-	return wrapper, nil
+	return i.applyNetworkPolicy(command), nil
 }
 
 // loadCustomPatternsFromConfig loads detection patterns from built-in defaults + config.toml
-// overrides, and sets them on the tmux session for status detection and tool auto-detection.
+// overrides + the project's .agent-deck/patterns.toml, and sets them on the tmux session for
+// status detection and tool auto-detection, along with the tool's ready probe.
 // Works for ALL tools: built-in (claude, gemini, opencode, codex) and custom.
 func (i *Instance) loadCustomPatternsFromConfig() {
 	if i.tmuxSession == nil {
 		return
 	}
 
-	// Merge built-in defaults with any user config overrides/extras
-	raw := MergeToolPatterns(i.Tool)
+	// Merge built-in defaults with any user config and project overrides/extras
+	raw := MergeProjectToolPatterns(i.Tool, i.ProjectPath)
 	if raw != nil {
 		resolved, err := tmux.CompilePatterns(raw)
 		if err != nil {
@@ -1283,39 +1412,40 @@ func (i *Instance) loadCustomPatternsFromConfig() {
 	if toolDef := GetToolDef(i.Tool); toolDef != nil {
 		i.tmuxSession.SetDetectPatterns(i.Tool, toolDef.DetectPatterns)
 	}
+	i.tmuxSession.SetReadyProbe(ToolReadyProbe(i.Tool))
+	i.tmuxSession.SetClassifier(ToolClassifier(i.Tool))
+	i.tmuxSession.SetStatusFusion(StatusFusion())
 }
 
-// Start starts the session in tmux
-func (i *Instance) Start() error {
-	if i.tmuxSession == nil {
-		return fmt.Errorf("tmux session not initialized")
+// buildStartCommand builds the command that starts the session's tool
+// Priority: tool adapters (claude, codex, aider, gemini) → built-in tools (opencode, ollama) → custom tools from config.toml → raw command
+func (i *Instance) buildStartCommand() string {
+	if adapter := toolAdapterFor(i.Tool); adapter != nil {
+		return adapter.BuildStartCommand(i, i.Command)
 	}
-
-	// Build command based on tool type
-	// Priority: built-in tools (claude, gemini, opencode, codex) → custom tools from config.toml → raw command
-	var command string
 	switch i.Tool {
-	case "claude":
-		command = i.buildClaudeCommand(i.Command)
-	case "gemini":
-		command = i.buildGeminiCommand(i.Command)
 	case "opencode":
-		command = i.buildOpenCodeCommand(i.Command)
 		// Record start time for session ID detection (Unix millis)
 		i.OpenCodeStartedAt = time.Now().UnixMilli()
-	case "codex":
-		command = i.buildCodexCommand(i.Command)
-		// Record start time for session ID detection (Unix millis)
-		i.CodexStartedAt = time.Now().UnixMilli()
-	default:
-		// Check if this is a custom tool with session resume config
-		if toolDef := GetToolDef(i.Tool); toolDef != nil {
-			command = i.buildGenericCommand(i.Command)
-		} else {
-			command = i.Command
-		}
+		return i.buildOpenCodeCommand(i.Command)
+	case "ollama":
+		return i.buildOllamaCommand(i.Command)
+	}
+	// Check if this is a custom tool with session resume config
+	if toolDef := GetToolDef(i.Tool); toolDef != nil {
+		return i.buildGenericCommand(i.Command)
+	}
+	return i.Command
+}
+
+// Start starts the session in tmux
+func (i *Instance) Start() error {
+	if i.tmuxSession == nil {
+		return fmt.Errorf("tmux session not initialized")
 	}
 
+	command := i.buildStartCommand()
+
 	var err error
 	command, err = i.applyWrapper(command)
 	if err != nil {
@@ -1324,6 +1454,7 @@ func (i *Instance) Start() error {
 
 	// Load custom patterns for status detection
 	i.loadCustomPatternsFromConfig()
+	i.applyToolWorkDir()
 
 	// Apply user tmux option overrides from config (e.g. allow-passthrough = "all")
 	if tmuxCfg := GetTmuxSettings(); len(tmuxCfg.Options) > 0 {
@@ -1340,6 +1471,7 @@ func (i *Instance) Start() error {
 	if err := i.tmuxSession.SetEnvironment("AGENTDECK_INSTANCE_ID", i.ID); err != nil {
 		sessionLog.Warn("set_instance_id_failed", slog.String("error", err.Error()))
 	}
+	i.startTranscript()
 
 	// Capture MCPs that are now loaded (for sync tracking)
 	i.CaptureLoadedMCPs()
@@ -1359,11 +1491,9 @@ func (i *Instance) Start() error {
 		go i.detectOpenCodeSessionAsync()
 	}
 
-	// Start async session ID detection for Codex
-	// This runs in background and captures the session ID once Codex creates it
-	if i.Tool == "codex" {
-		go i.detectCodexSessionAsync()
-	}
+	// Start async session ID detection for tools that pick their own ID (Codex)
+	// This runs in background and captures the session ID once the tool creates it
+	i.captureSessionID(false)
 
 	return nil
 }
@@ -1378,27 +1508,7 @@ func (i *Instance) StartWithMessage(message string) error {
 	}
 
 	// Start session normally (no embedded message logic)
-	// Priority: built-in tools (claude, gemini, opencode, codex) → custom tools from config.toml → raw command
-	var command string
-	switch i.Tool {
-	case "claude":
-		command = i.buildClaudeCommand(i.Command)
-	case "gemini":
-		command = i.buildGeminiCommand(i.Command)
-	case "opencode":
-		command = i.buildOpenCodeCommand(i.Command)
-		i.OpenCodeStartedAt = time.Now().UnixMilli()
-	case "codex":
-		command = i.buildCodexCommand(i.Command)
-		i.CodexStartedAt = time.Now().UnixMilli()
-	default:
-		// Check if this is a custom tool with session resume config
-		if toolDef := GetToolDef(i.Tool); toolDef != nil {
-			command = i.buildGenericCommand(i.Command)
-		} else {
-			command = i.Command
-		}
-	}
+	command := i.buildStartCommand()
 
 	var err error
 	command, err = i.applyWrapper(command)
@@ -1408,6 +1518,7 @@ func (i *Instance) StartWithMessage(message string) error {
 
 	// Load custom patterns for status detection
 	i.loadCustomPatternsFromConfig()
+	i.applyToolWorkDir()
 
 	// Apply user tmux option overrides from config (e.g. allow-passthrough = "all")
 	if tmuxCfg := GetTmuxSettings(); len(tmuxCfg.Options) > 0 {
@@ -1424,6 +1535,7 @@ func (i *Instance) StartWithMessage(message string) error {
 	if err := i.tmuxSession.SetEnvironment("AGENTDECK_INSTANCE_ID", i.ID); err != nil {
 		sessionLog.Warn("set_instance_id_failed", slog.String("error", err.Error()))
 	}
+	i.startTranscript()
 
 	// Capture MCPs that are now loaded (for sync tracking)
 	i.CaptureLoadedMCPs()
@@ -1438,9 +1550,7 @@ func (i *Instance) StartWithMessage(message string) error {
 	if i.Tool == "opencode" {
 		go i.detectOpenCodeSessionAsync()
 	}
-	if i.Tool == "codex" {
-		go i.detectCodexSessionAsync()
-	}
+	i.captureSessionID(false)
 
 	// Send message synchronously (CLI will wait)
 	if message != "" {
@@ -1539,9 +1649,21 @@ func hookFastPathFreshnessForTool(tool, hookStatus string) time.Duration {
 // UpdateStatus updates the session status by checking tmux.
 // Thread-safe: acquires write lock to protect Status, Tool, and internal cache fields.
 func (i *Instance) UpdateStatus() error {
+	defer StatusDetectionLatency.ObserveSince(time.Now())
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
+	prevStatus := i.Status
+	defer func() {
+		if i.Status != prevStatus || i.stateSince.IsZero() {
+			i.stateSince = time.Now()
+		}
+		// Score recent tool calls of busy sessions now and then, off the poll
+		if i.riskCheckDueLocked() {
+			go i.checkRisk()
+		}
+	}()
+
 	// Short grace period for tmux initialization (not Claude startup)
 	// Use lastStartTime for accuracy on restarts, fallback to CreatedAt
 	graceTime := i.lastStartTime
@@ -1660,24 +1782,40 @@ func (i *Instance) UpdateStatus() error {
 	}
 
 	// Map tmux status to instance status
+	var detected Status
 	switch status {
 	case "active":
-		i.Status = StatusRunning
+		detected = StatusRunning
 	case "waiting":
 		if i.Tool == "shell" {
-			i.Status = StatusIdle
+			detected = StatusIdle
 		} else {
-			i.Status = StatusWaiting
+			detected = StatusWaiting
 		}
 	case "idle":
-		i.Status = StatusIdle
+		detected = StatusIdle
 	case "starting":
-		i.Status = StatusStarting
+		detected = StatusStarting
 	case "inactive":
-		i.Status = StatusError
+		detected = StatusError
 	default:
-		i.Status = StatusError
+		detected = StatusError
+	}
+
+	// A tool backing off from a rate limit still shows its spinner or its
+	// prompt; its adapter tells that apart from real work
+	if detected == StatusRunning || detected == StatusWaiting {
+		if detector, ok := toolAdapterFor(i.Tool).(ToolThrottleDetector); ok {
+			i.mu.Unlock()
+			pane, err := i.tmuxSession.CapturePane()
+			i.mu.Lock()
+			if msg := detector.ThrottleMessage(pane); err == nil && msg != "" {
+				detected = StatusThrottled
+				i.throttleMessage = msg
+			}
+		}
 	}
+	i.Status = i.applyStatusDwellLocked(detected, GetStatusSettings())
 
 	// Update tool detection dynamically (enables fork when Claude starts)
 	if detectedTool := i.tmuxSession.DetectTool(); detectedTool != "" {
@@ -1685,7 +1823,7 @@ func (i *Instance) UpdateStatus() error {
 	}
 
 	// Update session tracking only for active/waiting sessions (skip idle - nothing changes)
-	if i.Status == StatusRunning || i.Status == StatusWaiting {
+	if i.Status == StatusRunning || i.Status == StatusWaiting || i.Status == StatusThrottled {
 		// Update Claude session tracking (non-blocking, best-effort)
 		i.UpdateClaudeSession(nil)
 
@@ -2195,6 +2333,7 @@ type ResponseOutput struct {
 // GetLastResponse returns the last assistant response from the session
 // For Claude: Parses the JSONL file for the last assistant message
 // For Gemini: Parses the JSON session file for the last assistant message
+// For aider: Parses the markdown chat history for the last reply
 // For Codex/Others: Attempts to parse terminal output
 func (i *Instance) GetLastResponse() (*ResponseOutput, error) {
 	if i.Tool == "claude" {
@@ -2203,6 +2342,9 @@ func (i *Instance) GetLastResponse() (*ResponseOutput, error) {
 	if i.Tool == "gemini" {
 		return i.getGeminiLastResponse()
 	}
+	if i.Tool == "aider" {
+		return i.getAiderLastResponse()
+	}
 	return i.getTerminalLastResponse()
 }
 
@@ -2765,6 +2907,15 @@ func (i *Instance) Kill() error {
 // For Claude sessions with known ID: sends Ctrl+C twice and resume command to existing session
 // For dead sessions or unknown ID: recreates the tmux session
 func (i *Instance) Restart() error {
+	if err := i.restart(); err != nil {
+		return err
+	}
+	// A respawned pane may have lost its pipe; a recreated one never had it
+	i.startTranscript()
+	return nil
+}
+
+func (i *Instance) restart() error {
 	mcpLog.Debug("restart_called", slog.String("tool", i.Tool), slog.String("claude_session_id", i.ClaudeSessionID), slog.Bool("tmux_session", i.tmuxSession != nil), slog.Bool("tmux_exists", i.tmuxSession != nil && i.tmuxSession.Exists()))
 
 	// Clear flag immediately to prevent it staying set if restart fails
@@ -2782,62 +2933,41 @@ func (i *Instance) Restart() error {
 		mcpLog.Debug("mcp_regen_skipped", slog.String("reason", "flag_set_by_apply"))
 	}
 
-	// Sync Claude session from disk before restart to pick up /clear session changes
-	if i.Tool == "claude" {
-		i.syncClaudeSessionFromDisk()
-	}
-
-	// If Claude session with known ID AND tmux session exists, use respawn-pane
-	if i.Tool == "claude" && i.ClaudeSessionID != "" && i.tmuxSession != nil && i.tmuxSession.Exists() {
-		// Build the resume command with proper config
-		resumeCmd := i.buildClaudeResumeCommand()
-		resumeCmd, err := i.applyWrapper(resumeCmd)
-		if err != nil {
-			return err
-		}
-		mcpLog.Debug("respawn_pane_claude", slog.String("command", resumeCmd))
-
-		// Use respawn-pane for atomic restart
-		// This is more reliable than Ctrl+C + wait for shell + send command
-		// respawn-pane -k kills the current process and starts the new command atomically
-		if err := i.tmuxSession.RespawnPane(resumeCmd); err != nil {
-			mcpLog.Debug("respawn_pane_claude_failed", slog.String("error", err.Error()))
-			return fmt.Errorf("failed to restart Claude session: %w", err)
-		}
+	// Sync the session ID from disk before restart to pick up /clear or new conversations
+	i.syncSessionID()
 
-		mcpLog.Debug("respawn_pane_claude_succeeded")
+	// Tools with an adapter resume their conversation in place with respawn-pane
+	// when there is one and the tmux session exists
+	if adapter := toolAdapterFor(i.Tool); adapter != nil && i.tmuxSession != nil && i.tmuxSession.Exists() {
+		if resumeCmd := adapter.BuildResumeCommand(i); resumeCmd != "" {
+			resumeCmd, err := i.applyWrapper(resumeCmd)
+			if err != nil {
+				return err
+			}
+			mcpLog.Debug("restart_adapter_respawn", slog.String("tool", i.Tool), slog.String("command", resumeCmd))
+
+			// Use respawn-pane for atomic restart
+			// This is more reliable than Ctrl+C + wait for shell + send command
+			// respawn-pane -k kills the current process and starts the new command atomically
+			if err := i.tmuxSession.RespawnPane(resumeCmd); err != nil {
+				mcpLog.Debug("restart_adapter_respawn_failed", slog.String("tool", i.Tool), slog.String("error", err.Error()))
+				return fmt.Errorf("failed to restart %s session: %w", i.Tool, err)
+			}
 
-		// Re-capture MCPs after restart (they may have changed since session started)
-		i.CaptureLoadedMCPs()
+			mcpLog.Debug("restart_adapter_respawn_succeeded", slog.String("tool", i.Tool))
 
-		// Start as WAITING - will go GREEN on next tick if Claude shows busy indicator
-		i.Status = StatusWaiting
-		return nil
-	}
+			// A fresh start (no ID yet) needs its session ID found again
+			i.captureSessionID(true)
 
-	// For Gemini: ALWAYS update session to get the most recent one
-	// Krudony fix: don't skip when we already have an ID - the user may have started a NEW session
-	if i.Tool == "gemini" {
-		i.UpdateGeminiSession(nil)
-	}
-
-	// If Gemini session with known ID AND tmux session exists, use respawn-pane
-	if i.Tool == "gemini" && i.GeminiSessionID != "" && i.tmuxSession != nil && i.tmuxSession.Exists() {
-		resumeCmd := i.buildGeminiCommand("gemini")
-		resumeCmd, err := i.applyWrapper(resumeCmd)
-		if err != nil {
-			return err
-		}
-		sessionLog.Info("restart_gemini_respawn", slog.String("command", resumeCmd))
+			// Re-capture MCPs after restart (they may have changed since session started)
+			if i.Tool == "claude" {
+				i.CaptureLoadedMCPs()
+			}
 
-		if err := i.tmuxSession.RespawnPane(resumeCmd); err != nil {
-			sessionLog.Info("restart_gemini_respawn_failed", slog.String("error", err.Error()))
-			return fmt.Errorf("failed to restart Gemini session: %w", err)
+			// Start as WAITING - will go GREEN on next tick if the tool shows busy indicator
+			i.Status = StatusWaiting
+			return nil
 		}
-
-		sessionLog.Info("restart_gemini_respawn_succeeded")
-		i.Status = StatusWaiting
-		return nil
 	}
 
 	// If OpenCode session AND tmux session exists, use respawn-pane
@@ -2884,46 +3014,21 @@ func (i *Instance) Restart() error {
 		return nil
 	}
 
-	// For Codex: ALWAYS update session to get the most recent one
-	// Krudony fix: don't skip when we already have an ID - the user may have started a NEW session
-	if i.Tool == "codex" {
-		i.UpdateCodexSession(nil)
-	}
-
-	// If Codex session AND tmux session exists, use respawn-pane
-	if i.Tool == "codex" && i.tmuxSession != nil && i.tmuxSession.Exists() {
-		// Try to get session ID from tmux environment if not already set
-		if i.CodexSessionID == "" {
-			if envID, err := i.tmuxSession.GetEnvironment("CODEX_SESSION_ID"); err == nil && envID != "" {
-				i.CodexSessionID = envID
-				i.CodexDetectedAt = time.Now()
-				sessionLog.Info("restart_codex_recovered_id", slog.String("session_id", envID))
-			}
-		}
-
-		resumeCmd := i.buildCodexCommand("codex")
-		if i.CodexSessionID == "" {
-			// No session ID yet, start fresh (will detect ID async)
-			// Re-record start time for async detection
-			i.CodexStartedAt = time.Now().UnixMilli()
-		}
-		resumeCmd, err := i.applyWrapper(resumeCmd)
+	// If ollama session AND tmux session exists, save the conversation and respawn on it
+	if i.Tool == "ollama" && i.tmuxSession != nil && i.tmuxSession.Exists() {
+		i.saveOllamaConversation()
+		resumeCmd, err := i.applyWrapper(i.buildOllamaCommand(i.Command))
 		if err != nil {
 			return err
 		}
-		sessionLog.Info("restart_codex_respawn", slog.String("command", resumeCmd))
+		sessionLog.Info("restart_ollama_respawn", slog.String("command", resumeCmd))
 
 		if err := i.tmuxSession.RespawnPane(resumeCmd); err != nil {
-			sessionLog.Info("restart_codex_respawn_failed", slog.String("error", err.Error()))
-			return fmt.Errorf("failed to restart Codex session: %w", err)
-		}
-
-		// If no session ID, start async detection
-		if i.CodexSessionID == "" {
-			go i.detectCodexSessionAsync()
+			sessionLog.Info("restart_ollama_respawn_failed", slog.String("error", err.Error()))
+			return fmt.Errorf("failed to restart ollama session: %w", err)
 		}
 
-		sessionLog.Info("restart_codex_respawn_succeeded")
+		sessionLog.Info("restart_ollama_respawn_succeeded")
 		i.Status = StatusWaiting
 		return nil
 	}
@@ -2934,16 +3039,13 @@ func (i *Instance) Restart() error {
 		sessionID := i.GetGenericSessionID()
 
 		// Build resume command for custom tool
-		var resumeCmd string
-		if toolDef.DangerousMode && toolDef.DangerousFlag != "" {
-			resumeCmd = fmt.Sprintf("tmux set-environment %s %s && %s %s %s %s",
-				toolDef.SessionIDEnv, sessionID,
-				i.Command, toolDef.ResumeFlag, sessionID, toolDef.DangerousFlag)
-		} else {
-			resumeCmd = fmt.Sprintf("tmux set-environment %s %s && %s %s %s",
-				toolDef.SessionIDEnv, sessionID,
-				i.Command, toolDef.ResumeFlag, sessionID)
+		baseCommand := i.Command
+		if baseCommand == "" {
+			baseCommand = toolDef.Command
 		}
+		resumeCmd := fmt.Sprintf("tmux set-environment %s %s && %s",
+			toolDef.SessionIDEnv, sessionID,
+			i.genericResumeCommand(toolDef, i.expandToolTemplate(baseCommand, nil), sessionID))
 		resumeCmd, err := i.applyWrapper(resumeCmd)
 		if err != nil {
 			return err
@@ -2978,38 +3080,17 @@ func (i *Instance) Restart() error {
 	i.tmuxSession.SetInjectStatusLine(GetTmuxSettings().GetInjectStatusLine())
 
 	var command string
-	if i.Tool == "claude" && i.ClaudeSessionID != "" {
-		command = i.buildClaudeResumeCommand()
-	} else if i.Tool == "gemini" && i.GeminiSessionID != "" {
-		command = i.buildGeminiCommand("gemini")
-	} else if i.Tool == "opencode" && i.OpenCodeSessionID != "" {
-		// Set OPENCODE_SESSION_ID in tmux env so detection works after restart
-		command = fmt.Sprintf("tmux set-environment OPENCODE_SESSION_ID %s && opencode -s %s",
-			i.OpenCodeSessionID, i.OpenCodeSessionID)
-	} else if i.Tool == "codex" && i.CodexSessionID != "" {
-		command = i.buildCodexCommand("codex")
-	} else {
-		// Route to appropriate command builder based on tool
-		switch i.Tool {
-		case "claude":
-			command = i.buildClaudeCommand(i.Command)
-		case "gemini":
-			command = i.buildGeminiCommand(i.Command)
-		case "opencode":
-			command = i.buildOpenCodeCommand(i.Command)
-			// Record start time for async session ID detection
-			i.OpenCodeStartedAt = time.Now().UnixMilli()
-		case "codex":
-			command = i.buildCodexCommand(i.Command)
-			// Record start time for async session ID detection
-			i.CodexStartedAt = time.Now().UnixMilli()
-		default:
-			// Check if this is a custom tool with session resume config
-			if toolDef := GetToolDef(i.Tool); toolDef != nil {
-				command = i.buildGenericCommand(i.Command)
-			} else {
-				command = i.Command
-			}
+	if adapter := toolAdapterFor(i.Tool); adapter != nil {
+		command = adapter.BuildResumeCommand(i)
+	}
+	if command == "" {
+		if i.Tool == "opencode" && i.OpenCodeSessionID != "" {
+			// Set OPENCODE_SESSION_ID in tmux env so detection works after restart
+			command = fmt.Sprintf("tmux set-environment OPENCODE_SESSION_ID %s && opencode -s %s",
+				i.OpenCodeSessionID, i.OpenCodeSessionID)
+		} else {
+			// Route to appropriate command builder based on tool
+			command = i.buildStartCommand()
 		}
 	}
 	command, err := i.applyWrapper(command)
@@ -3019,6 +3100,7 @@ func (i *Instance) Restart() error {
 
 	// Load custom patterns for status detection (for custom tools)
 	i.loadCustomPatternsFromConfig()
+	i.applyToolWorkDir()
 
 	// Apply user tmux option overrides from config (e.g. allow-passthrough = "all")
 	if tmuxCfg := GetTmuxSettings(); len(tmuxCfg.Options) > 0 {
@@ -3040,6 +3122,7 @@ func (i *Instance) Restart() error {
 	if err := i.tmuxSession.SetEnvironment("AGENTDECK_INSTANCE_ID", i.ID); err != nil {
 		sessionLog.Warn("set_instance_id_failed", slog.String("error", err.Error()))
 	}
+	i.startTranscript()
 
 	// Re-capture MCPs after restart
 	i.CaptureLoadedMCPs()
@@ -3050,9 +3133,7 @@ func (i *Instance) Restart() error {
 	}
 
 	// Start async session ID detection for Codex (if no ID yet)
-	if i.Tool == "codex" && i.CodexSessionID == "" {
-		go i.detectCodexSessionAsync()
-	}
+	i.captureSessionID(true)
 
 	// Start as WAITING - will go GREEN on next tick if Claude shows busy indicator
 	if command != "" {
@@ -3134,20 +3215,14 @@ func (i *Instance) SetGeminiModel(model string) error {
 }
 
 // CanRestart returns true if the session can be restarted
-// For Claude sessions with known ID: can always restart (interrupt and resume)
-// For Gemini sessions with known ID: can always restart (interrupt and resume)
+// For tools with an adapter (Claude, Codex, Gemini) and a conversation to resume: can always restart (interrupt and resume)
 // For OpenCode sessions with known ID: can always restart (interrupt and resume)
-// For Codex sessions with known ID: can always restart (interrupt and resume)
+// For Codex sessions without ID: can restart (starts fresh)
 // For custom tools with session resume config: can restart if session ID available
 // For other sessions: only if dead/error state
 func (i *Instance) CanRestart() bool {
-	// Gemini sessions with known session ID can always be restarted
-	if i.Tool == "gemini" && i.GeminiSessionID != "" {
-		return true
-	}
-
-	// Claude sessions with known session ID can always be restarted
-	if i.Tool == "claude" && i.ClaudeSessionID != "" {
+	// Tools with an adapter can always be restarted when they have a conversation to resume
+	if adapter := toolAdapterFor(i.Tool); adapter != nil && adapter.CanResume(i) {
 		return true
 	}
 
@@ -3162,17 +3237,17 @@ func (i *Instance) CanRestart() bool {
 		return true
 	}
 
-	// Codex sessions with known session ID can always be restarted
-	if i.Tool == "codex" && i.CodexSessionID != "" {
-		return true
-	}
-
 	// Codex sessions without ID can still restart (will start fresh)
 	// This allows restart even before session ID is detected
 	if i.Tool == "codex" {
 		return true
 	}
 
+	// ollama sessions save their conversation on restart and resume it
+	if i.Tool == "ollama" {
+		return true
+	}
+
 	// Custom tools: check if they have session resume support
 	if i.CanRestartGeneric() {
 		return true
@@ -3184,21 +3259,23 @@ func (i *Instance) CanRestart() bool {
 
 // CanFork returns true if this session can be forked
 func (i *Instance) CanFork() bool {
-	// Gemini CLI doesn't support forking
-	if i.Tool == "gemini" {
-		return false
-	}
-
 	// OpenCode sessions can fork if session ID is recent
 	if i.Tool == "opencode" {
 		return i.CanForkOpenCode()
 	}
 
-	// Claude sessions can fork if session ID is recent
-	if i.ClaudeSessionID == "" {
-		return false
+	// Codex, Gemini CLI and aider fork by copying their conversation
+	if strategy := forkStrategyFor(i.Tool); strategy != nil {
+		return strategy.CanFork(i)
+	}
+
+	// Tools with an adapter decide themselves (Claude: session ID is recent).
+	// Other sessions (e.g. a shell Claude was started in) fork as Claude.
+	adapter := toolAdapterFor(i.Tool)
+	if adapter == nil {
+		adapter = toolAdapterFor("claude")
 	}
-	return time.Since(i.ClaudeDetectedAt) < 5*time.Minute
+	return adapter != nil && adapter.SupportsFork(i)
 }
 
 // CanForkOpenCode returns true if this OpenCode session can be forked
@@ -3283,8 +3360,13 @@ func (i *Instance) CreateForkedInstance(newTitle, newGroupPath string) (*Instanc
 	return i.CreateForkedInstanceWithOptions(newTitle, newGroupPath, nil)
 }
 
-// CreateForkedInstanceWithOptions creates a new Instance configured for forking with custom options
+// CreateForkedInstanceWithOptions creates a new Instance configured for forking with custom options.
+// Tools with a ForkStrategy fork through it; opts then only supplies the worktree.
 func (i *Instance) CreateForkedInstanceWithOptions(newTitle, newGroupPath string, opts *ClaudeOptions) (*Instance, string, error) {
+	if strategy := forkStrategyFor(i.Tool); strategy != nil {
+		return i.createForkedInstanceWithStrategy(strategy, newTitle, newGroupPath, opts)
+	}
+
 	cmd, err := i.ForkWithOptions(newTitle, newGroupPath, opts)
 	if err != nil {
 		return nil, "", err
@@ -3303,6 +3385,10 @@ func (i *Instance) CreateForkedInstanceWithOptions(newTitle, newGroupPath string
 	}
 	forked.Command = cmd
 	forked.Tool = "claude"
+	forked.SetStartReason(StartReasonFork, i.ID)
+	countFork()
+	forked.NetworkPolicy = i.NetworkPolicy // a fork must not escape the parent's sandbox
+	forked.Features = maps.Clone(i.Features)
 
 	// Store options in the new instance for persistence
 	if opts != nil {
@@ -3433,6 +3519,10 @@ func (i *Instance) CreateForkedOpenCodeInstanceWithOptions(newTitle, newGroupPat
 	}
 	forked.Command = cmd
 	forked.Tool = "opencode"
+	forked.SetStartReason(StartReasonFork, i.ID)
+	countFork()
+	forked.NetworkPolicy = i.NetworkPolicy // a fork must not escape the parent's sandbox
+	forked.Features = maps.Clone(i.Features)
 
 	// Store options in the new instance for persistence
 	if opts != nil {
@@ -1395,19 +1395,29 @@ func TestInstance_RegenerateMCPConfig_WriteFailure(t *testing.T) {
 }
 
 func TestInstance_CanFork_Gemini(t *testing.T) {
-	inst := NewInstanceWithTool("test", "/tmp/test", "gemini")
-	inst.GeminiSessionID = "abc-123-def"
-	inst.GeminiDetectedAt = time.Now()
-
-	if inst.CanFork() {
-		t.Error("CanFork() should be false for Gemini (not supported by Gemini CLI)")
-	}
+	geminiConfigDirOverride = t.TempDir()
+	defer func() { geminiConfigDirOverride = "" }()
 
+	projectPath := t.TempDir()
+	inst := NewInstanceWithTool("test", projectPath, "gemini")
+	inst.GeminiSessionID = "abcd1234-0000-4000-8000-000000000000"
+	inst.GeminiDetectedAt = time.Now()
 	inst.ClaudeSessionID = "claude-session-xyz"
 	inst.ClaudeDetectedAt = time.Now()
 
 	if inst.CanFork() {
-		t.Error("CanFork() should be false for Gemini tool even with ClaudeSessionID set")
+		t.Error("CanFork() should be false for Gemini without a chat file, even with ClaudeSessionID set")
+	}
+
+	chats := GetGeminiSessionsDir(projectPath)
+	if err := os.MkdirAll(chats, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(chats, "session-2026-01-02T10-00-abcd1234.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !inst.CanFork() {
+		t.Error("CanFork() should be true for Gemini with its chat file on disk")
 	}
 }
 
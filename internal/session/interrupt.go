@@ -0,0 +1,62 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// InterruptSummaryPrompt is the follow-up sent after an interrupt when the
+// caller wants to know how far the agent got
+const InterruptSummaryPrompt = "Stop what you were doing. Do not continue the task. " +
+	"Summarize where you were: what is done, what is left, and anything you left half-finished."
+
+// interruptSettleTimeout bounds the wait for the tool to return to its
+// prompt before a follow-up is typed; it is sent anyway after that, since
+// the tools queue input typed while busy
+const interruptSettleTimeout = 15 * time.Second
+
+// Interrupt stops what the session's tool is doing with its "interrupt"
+// macro (Escape for Claude, Codex and Gemini, Ctrl-C for aider; see
+// builtinMacros), or Ctrl-C for tools without one. A non-empty followUp is
+// sent once the tool is back at its prompt.
+func (i *Instance) Interrupt(followUp string) error {
+	tmuxSess := i.GetTmuxSession()
+	if tmuxSess == nil || !i.Exists() {
+		return fmt.Errorf("session %s is not running", i.Title)
+	}
+	err := i.RunMacro("interrupt")
+	if errors.Is(err, ErrMacroNotFound) {
+		err = tmuxSess.SendCtrlC()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to interrupt %s: %w", i.Title, err)
+	}
+	sessionLog.Info("session_interrupted", slog.String("session_id", i.ID), slog.String("tool", i.Tool))
+
+	if followUp == "" {
+		return nil
+	}
+	return i.sendAfterInterrupt(followUp)
+}
+
+// sendAfterInterrupt waits for the tool to settle at its prompt, then types
+// text into it
+func (i *Instance) sendAfterInterrupt(text string) error {
+	tmuxSess := i.GetTmuxSession()
+	if tmuxSess == nil {
+		return fmt.Errorf("session %s is not running", i.Title)
+	}
+	deadline := time.Now().Add(interruptSettleTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(250 * time.Millisecond)
+		if status, err := tmuxSess.GetStatus(); err == nil && (status == "waiting" || status == "idle") {
+			break
+		}
+	}
+	if err := tmuxSess.SendKeysAndEnter(text); err != nil {
+		return fmt.Errorf("failed to send follow-up to %s: %w", i.Title, err)
+	}
+	return nil
+}
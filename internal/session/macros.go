@@ -0,0 +1,137 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrMacroNotFound is returned when a tool has no macro by the given name
+var ErrMacroNotFound = errors.New("macro not found")
+
+// macroStepDelay separates macro steps so the tool can react to each one
+const macroStepDelay = 150 * time.Millisecond
+
+// MacroDef is a named in-tool action, configured per tool under
+// [tools.<tool>.macros.<name>]. A macro either types a slash command (Text,
+// submitted with Enter) or presses keys (Keys, tmux key names such as
+// "S-Tab", "Escape" or "C-o"); when both are set the keys go first.
+type MacroDef struct {
+	// Description is shown in macro listings
+	Description string `toml:"description" json:"description,omitempty"`
+
+	// Keys are tmux key names pressed in order
+	Keys []string `toml:"keys" json:"keys,omitempty"`
+
+	// Text is typed literally and submitted with Enter
+	Text string `toml:"text" json:"text,omitempty"`
+}
+
+// Macro is a resolved macro for a tool
+type Macro struct {
+	Name string `json:"name"`
+	MacroDef
+	// Builtin is false for macros defined or overridden in config.toml
+	Builtin bool `json:"builtin"`
+}
+
+// builtinMacros are the default macros for each built-in tool
+var builtinMacros = map[string]map[string]MacroDef{
+	"claude": {
+		"compact":    {Description: "Compact the conversation", Text: "/compact"},
+		"clear":      {Description: "Clear the conversation", Text: "/clear"},
+		"model":      {Description: "Open the model picker", Text: "/model"},
+		"cycle-mode": {Description: "Cycle permission mode (default, accept edits, plan)", Keys: []string{"S-Tab"}},
+		"interrupt":  {Description: "Interrupt the current response", Keys: []string{"Escape"}},
+	},
+	"gemini": {
+		"compact":   {Description: "Compress the conversation", Text: "/compress"},
+		"clear":     {Description: "Clear the conversation", Text: "/clear"},
+		"model":     {Description: "Open the model picker", Text: "/model"},
+		"interrupt": {Description: "Interrupt the current response", Keys: []string{"Escape"}},
+	},
+	"codex": {
+		"compact":   {Description: "Compact the conversation", Text: "/compact"},
+		"clear":     {Description: "Start a new conversation", Text: "/new"},
+		"model":     {Description: "Open the model picker", Text: "/model"},
+		"interrupt": {Description: "Interrupt the current response", Keys: []string{"Escape"}},
+	},
+	"ollama": {
+		"clear":     {Description: "Clear the conversation context", Text: "/clear"},
+		"interrupt": {Description: "Interrupt the current response", Keys: []string{"C-c"}},
+	},
+	"aider": {
+		"clear":     {Description: "Clear the chat history", Text: "/clear"},
+		"undo":      {Description: "Undo the last commit aider made", Text: "/undo"},
+		"diff":      {Description: "Show the diff since the last message", Text: "/diff"},
+		"interrupt": {Description: "Interrupt the current response", Keys: []string{"C-c"}},
+	},
+	"opencode": {
+		"compact":   {Description: "Compact the conversation", Text: "/compact"},
+		"clear":     {Description: "Start a new conversation", Text: "/new"},
+		"model":     {Description: "Open the model picker", Text: "/models"},
+		"interrupt": {Description: "Interrupt the current response", Keys: []string{"Escape"}},
+	},
+}
+
+// ListMacros returns the macros available for a tool, sorted by name:
+// the built-in ones merged with [tools.<tool>.macros] from config.toml,
+// where a config entry replaces the built-in macro of the same name.
+func ListMacros(tool string) []Macro {
+	merged := make(map[string]Macro)
+	for name, def := range builtinMacros[tool] {
+		merged[name] = Macro{Name: name, MacroDef: def, Builtin: true}
+	}
+	if def := GetToolDef(tool); def != nil {
+		for name, m := range def.Macros {
+			merged[name] = Macro{Name: name, MacroDef: m}
+		}
+	}
+	macros := make([]Macro, 0, len(merged))
+	for _, m := range merged {
+		macros = append(macros, m)
+	}
+	sort.Slice(macros, func(i, j int) bool { return macros[i].Name < macros[j].Name })
+	return macros
+}
+
+// ResolveMacro returns the named macro for a tool
+func ResolveMacro(tool, name string) (Macro, error) {
+	for _, m := range ListMacros(tool) {
+		if m.Name == name {
+			if len(m.Keys) == 0 && m.Text == "" {
+				return Macro{}, fmt.Errorf("macro %q for %s has neither keys nor text", name, tool)
+			}
+			return m, nil
+		}
+	}
+	return Macro{}, fmt.Errorf("%w: %q for tool %s", ErrMacroNotFound, name, tool)
+}
+
+// RunMacro runs the named macro of the session's tool in its tmux pane
+func (inst *Instance) RunMacro(name string) error {
+	macro, err := ResolveMacro(inst.Tool, name)
+	if err != nil {
+		return err
+	}
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil || !inst.Exists() {
+		return fmt.Errorf("session %s is not running", inst.Title)
+	}
+	if len(macro.Keys) > 0 {
+		if err := tmuxSess.SendNamedKeys(macro.Keys...); err != nil {
+			return fmt.Errorf("failed to send keys %s: %w", strings.Join(macro.Keys, " "), err)
+		}
+		if macro.Text != "" {
+			time.Sleep(macroStepDelay)
+		}
+	}
+	if macro.Text != "" {
+		if err := tmuxSess.SendKeysAndEnter(macro.Text); err != nil {
+			return fmt.Errorf("failed to send %q: %w", macro.Text, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMacroConfig(t *testing.T, content string) {
+	t.Helper()
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".agent-deck")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+}
+
+func TestListMacros_BuiltinAndConfig(t *testing.T) {
+	writeMacroConfig(t, `
+[tools.claude.macros.compact]
+description = "Compact with focus"
+text = "/compact keep the test plan"
+
+[tools.claude.macros.plan]
+keys = ["S-Tab", "S-Tab"]
+`)
+
+	byName := make(map[string]Macro)
+	for _, m := range ListMacros("claude") {
+		byName[m.Name] = m
+	}
+	if m := byName["model"]; !m.Builtin || m.Text != "/model" {
+		t.Errorf("built-in model macro = %+v", m)
+	}
+	if m := byName["compact"]; m.Builtin || m.Text != "/compact keep the test plan" {
+		t.Errorf("config should replace built-in compact, got %+v", m)
+	}
+	if m := byName["plan"]; len(m.Keys) != 2 || m.Keys[0] != "S-Tab" {
+		t.Errorf("config macro plan = %+v", m)
+	}
+
+	macros := ListMacros("claude")
+	for i := 1; i < len(macros); i++ {
+		if macros[i-1].Name > macros[i].Name {
+			t.Fatalf("macros not sorted: %s before %s", macros[i-1].Name, macros[i].Name)
+		}
+	}
+}
+
+func TestResolveMacro(t *testing.T) {
+	writeMacroConfig(t, `
+[tools.mytool.macros.empty]
+description = "does nothing"
+`)
+
+	if m, err := ResolveMacro("gemini", "compact"); err != nil || m.Text != "/compress" {
+		t.Errorf("gemini compact = %+v, %v", m, err)
+	}
+	if _, err := ResolveMacro("shell", "compact"); !errors.Is(err, ErrMacroNotFound) {
+		t.Errorf("shell has no macros, got err = %v", err)
+	}
+	if _, err := ResolveMacro("mytool", "empty"); err == nil || errors.Is(err, ErrMacroNotFound) {
+		t.Errorf("macro without keys or text should be rejected, got %v", err)
+	}
+}
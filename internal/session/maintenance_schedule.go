@@ -0,0 +1,364 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maintenanceWindowsFile holds windows added from the CLI, next to config.toml
+const maintenanceWindowsFile = "maintenance-windows.json"
+
+// Maintenance window sources
+const (
+	MaintenanceSourceConfig    = "config"
+	MaintenanceSourceCLI       = "cli"
+	MaintenanceSourceEmergency = "emergency" // an emergency stop, which has no end
+)
+
+// ErrNoMaintenanceWindow is returned when ending maintenance while no CLI window is active
+var ErrNoMaintenanceWindow = errors.New("no maintenance window started from the CLI is active")
+
+// MaintenanceWindow is a period during which heartbeats, conductor task
+// dispatch and automatic conductor restarts are suspended. A window is either
+// one-off (Start/End) or recurring (From/To on Days).
+type MaintenanceWindow struct {
+	Name   string `toml:"name" json:"name"`
+	Reason string `toml:"reason" json:"reason,omitempty"`
+
+	// Start and End bound a one-off window: RFC3339 or "2006-01-02 15:04" in Timezone
+	Start string `toml:"start" json:"start,omitempty"`
+	End   string `toml:"end" json:"end,omitempty"`
+
+	// Days (mon..sun, empty = every day), From and To ("HH:MM") define a
+	// recurring window. To may be earlier than From for windows past midnight;
+	// Days refers to the day the window starts.
+	Days []string `toml:"days" json:"days,omitempty"`
+	From string   `toml:"from" json:"from,omitempty"`
+	To   string   `toml:"to" json:"to,omitempty"`
+
+	// Timezone is the IANA zone for the window's times (default: [time] timezone, then local)
+	Timezone string `toml:"timezone" json:"timezone,omitempty"`
+}
+
+// ActiveMaintenance describes the maintenance window in effect at a moment
+type ActiveMaintenance struct {
+	Window MaintenanceWindow `json:"window"`
+	Source string            `json:"source"`
+	Since  time.Time         `json:"since"`
+	Until  time.Time         `json:"until,omitzero"`
+
+	// EmergencyStop is set when the deck is emergency-stopped
+	EmergencyStop *EmergencyStopState `json:"emergency_stop,omitempty"`
+}
+
+// Describe renders the active window for banners
+func (a *ActiveMaintenance) Describe() string {
+	if a.EmergencyStop != nil {
+		return a.EmergencyStop.Describe()
+	}
+	s := fmt.Sprintf("Maintenance window %q active until %s", a.Window.Name, NewTimeFormatter(true).FormatAbsolute(a.Until))
+	if a.Window.Reason != "" {
+		s += ": " + a.Window.Reason
+	}
+	return s + " (heartbeats, task dispatch and auto-restarts paused)"
+}
+
+var maintenanceDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Recurring reports whether the window repeats (From/To) rather than being one-off
+func (w MaintenanceWindow) Recurring() bool {
+	return w.From != "" || w.To != ""
+}
+
+func (w MaintenanceWindow) location() *time.Location {
+	tz := w.Timezone
+	if tz == "" {
+		tz = GetTimeSettings().Timezone
+	}
+	if tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// parseMaintenanceTime accepts RFC3339 or "2006-01-02 15:04" in loc
+func parseMaintenanceTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected RFC3339 or YYYY-MM-DD HH:MM", s)
+	}
+	return t, nil
+}
+
+// Validate checks that the window is either a valid one-off or recurring window
+func (w MaintenanceWindow) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("maintenance window needs a name")
+	}
+	if w.Timezone != "" {
+		if err := ValidateTimezone(w.Timezone); err != nil {
+			return err
+		}
+	}
+	loc := w.location()
+	if w.Recurring() {
+		if w.Start != "" || w.End != "" {
+			return fmt.Errorf("maintenance window %q: use either start/end or from/to, not both", w.Name)
+		}
+		if _, _, err := ParseQuietHours(w.From + "-" + w.To); err != nil {
+			return fmt.Errorf("maintenance window %q: %w", w.Name, err)
+		}
+		for _, d := range w.Days {
+			if _, ok := maintenanceDays[strings.ToLower(d)]; !ok {
+				return fmt.Errorf("maintenance window %q: invalid day %q (use mon..sun)", w.Name, d)
+			}
+		}
+		return nil
+	}
+	start, err := parseMaintenanceTime(w.Start, loc)
+	if err != nil {
+		return fmt.Errorf("maintenance window %q: %w", w.Name, err)
+	}
+	end, err := parseMaintenanceTime(w.End, loc)
+	if err != nil {
+		return fmt.Errorf("maintenance window %q: %w", w.Name, err)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("maintenance window %q: end must be after start", w.Name)
+	}
+	return nil
+}
+
+// ActiveAt returns the bounds of the occurrence of w containing now, if any
+func (w MaintenanceWindow) ActiveAt(now time.Time) (since, until time.Time, ok bool) {
+	loc := w.location()
+	if !w.Recurring() {
+		start, err1 := parseMaintenanceTime(w.Start, loc)
+		end, err2 := parseMaintenanceTime(w.End, loc)
+		if err1 != nil || err2 != nil {
+			return time.Time{}, time.Time{}, false
+		}
+		return start, end, !now.Before(start) && now.Before(end)
+	}
+
+	from, to, err := ParseQuietHours(w.From + "-" + w.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	local := now.In(loc)
+	// An occurrence containing now started today or, past midnight, yesterday
+	for _, back := range []int{0, 1} {
+		day := time.Date(local.Year(), local.Month(), local.Day()-back, 0, 0, 0, 0, loc)
+		if !w.onDay(day.Weekday()) {
+			continue
+		}
+		start := day.Add(time.Duration(from) * time.Minute)
+		end := day.Add(time.Duration(to) * time.Minute)
+		if to <= from {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !now.Before(start) && now.Before(end) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func (w MaintenanceWindow) onDay(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, name := range w.Days {
+		if maintenanceDays[strings.ToLower(name)] == d {
+			return true
+		}
+	}
+	return false
+}
+
+// expired reports whether a one-off window has ended before now
+func (w MaintenanceWindow) expired(now time.Time) bool {
+	if w.Recurring() {
+		return false
+	}
+	end, err := parseMaintenanceTime(w.End, w.location())
+	return err == nil && !now.Before(end)
+}
+
+func maintenanceWindowsPath() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, maintenanceWindowsFile), nil
+}
+
+// LoadCLIMaintenanceWindows returns windows added with 'agent-deck maintenance'
+func LoadCLIMaintenanceWindows() ([]MaintenanceWindow, error) {
+	path, err := maintenanceWindowsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance windows: %w", err)
+	}
+	var windows []MaintenanceWindow
+	if err := json.Unmarshal(data, &windows); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", maintenanceWindowsFile, err)
+	}
+	return windows, nil
+}
+
+// saveCLIMaintenanceWindows writes windows, dropping one-offs that have ended
+func saveCLIMaintenanceWindows(windows []MaintenanceWindow, now time.Time) error {
+	path, err := maintenanceWindowsPath()
+	if err != nil {
+		return err
+	}
+	kept := make([]MaintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		if !w.expired(now) {
+			kept = append(kept, w)
+		}
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write maintenance windows: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write maintenance windows: %w", err)
+	}
+	return nil
+}
+
+// AddMaintenanceWindow validates and stores a CLI window. A window with the
+// same name is replaced.
+func AddMaintenanceWindow(w MaintenanceWindow, now time.Time) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	windows, err := LoadCLIMaintenanceWindows()
+	if err != nil {
+		return err
+	}
+	kept := windows[:0]
+	for _, existing := range windows {
+		if existing.Name != w.Name {
+			kept = append(kept, existing)
+		}
+	}
+	return saveCLIMaintenanceWindows(append(kept, w), now)
+}
+
+// RemoveMaintenanceWindow deletes a CLI window by name
+func RemoveMaintenanceWindow(name string, now time.Time) error {
+	windows, err := LoadCLIMaintenanceWindows()
+	if err != nil {
+		return err
+	}
+	kept := make([]MaintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		if w.Name != name {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == len(windows) {
+		return fmt.Errorf("maintenance window %q not found (windows from config.toml are removed there)", name)
+	}
+	return saveCLIMaintenanceWindows(kept, now)
+}
+
+// EndMaintenance ends active one-off CLI windows now and returns their names.
+// Recurring and config windows are left alone.
+func EndMaintenance(now time.Time) ([]string, error) {
+	windows, err := LoadCLIMaintenanceWindows()
+	if err != nil {
+		return nil, err
+	}
+	var ended []string
+	kept := make([]MaintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		if _, _, ok := w.ActiveAt(now); ok && !w.Recurring() {
+			ended = append(ended, w.Name)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	if len(ended) == 0 {
+		return nil, ErrNoMaintenanceWindow
+	}
+	return ended, saveCLIMaintenanceWindows(kept, now)
+}
+
+// MaintenanceWindowEntry is a configured window and where it came from
+type MaintenanceWindowEntry struct {
+	MaintenanceWindow
+	Source string `json:"source"`
+}
+
+// ListMaintenanceWindows returns windows from [maintenance] in config.toml
+// followed by those added from the CLI. Invalid config windows are skipped.
+func ListMaintenanceWindows() ([]MaintenanceWindowEntry, error) {
+	var entries []MaintenanceWindowEntry
+	for _, w := range GetMaintenanceSettings().Windows {
+		if w.Validate() == nil {
+			entries = append(entries, MaintenanceWindowEntry{w, MaintenanceSourceConfig})
+		}
+	}
+	cli, err := LoadCLIMaintenanceWindows()
+	for _, w := range cli {
+		entries = append(entries, MaintenanceWindowEntry{w, MaintenanceSourceCLI})
+	}
+	return entries, err
+}
+
+// ActiveMaintenanceWindow returns the window in effect at now, or nil. When
+// windows overlap the one ending last wins, so the deck resumes only when
+// all of them are over. An emergency stop outlasts every window.
+func ActiveMaintenanceWindow(now time.Time) *ActiveMaintenance {
+	if stop := LoadEmergencyStop(); stop != nil {
+		return &ActiveMaintenance{
+			Window:        MaintenanceWindow{Name: "emergency-stop", Reason: stop.Reason},
+			Source:        MaintenanceSourceEmergency,
+			Since:         stop.StoppedAt,
+			EmergencyStop: stop,
+		}
+	}
+	entries, _ := ListMaintenanceWindows()
+	var active []ActiveMaintenance
+	for _, e := range entries {
+		if since, until, ok := e.ActiveAt(now); ok {
+			active = append(active, ActiveMaintenance{Window: e.MaintenanceWindow, Source: e.Source, Since: since, Until: until})
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Until.After(active[j].Until) })
+	return &active[0]
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		wantErr bool
+	}{
+		{"one-off", MaintenanceWindow{Name: "a", Start: "2026-11-01 22:00", End: "2026-11-02 01:00"}, false},
+		{"rfc3339", MaintenanceWindow{Name: "a", Start: "2026-11-01T22:00:00Z", End: "2026-11-01T23:00:00Z"}, false},
+		{"recurring", MaintenanceWindow{Name: "a", From: "22:00", To: "02:00", Days: []string{"Sat"}}, false},
+		{"no name", MaintenanceWindow{From: "22:00", To: "02:00"}, true},
+		{"end before start", MaintenanceWindow{Name: "a", Start: "2026-11-02 01:00", End: "2026-11-01 22:00"}, true},
+		{"mixed", MaintenanceWindow{Name: "a", Start: "2026-11-01 22:00", End: "2026-11-02 01:00", From: "22:00", To: "23:00"}, true},
+		{"bad day", MaintenanceWindow{Name: "a", From: "22:00", To: "23:00", Days: []string{"someday"}}, true},
+		{"bad timezone", MaintenanceWindow{Name: "a", From: "22:00", To: "23:00", Timezone: "Mars/Base"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.window.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowActiveAt(t *testing.T) {
+	// Saturday 22:00-02:00 UTC, so it also covers early Sunday
+	w := MaintenanceWindow{Name: "weekly", Days: []string{"sat"}, From: "22:00", To: "02:00", Timezone: "UTC"}
+	sat := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC) // a Saturday
+
+	tests := []struct {
+		at     time.Time
+		active bool
+	}{
+		{sat.Add(21 * time.Hour), false},
+		{sat.Add(23 * time.Hour), true},
+		{sat.Add(25 * time.Hour), true}, // Sunday 01:00
+		{sat.Add(26 * time.Hour), false},
+		{sat.Add(-2 * time.Hour), false}, // Friday 22:00
+	}
+	for _, tt := range tests {
+		_, until, ok := w.ActiveAt(tt.at)
+		if ok != tt.active {
+			t.Errorf("ActiveAt(%s) = %v, want %v", tt.at.Format(time.RFC1123), ok, tt.active)
+		}
+		if ok && !until.Equal(sat.Add(26*time.Hour)) {
+			t.Errorf("ActiveAt(%s) until = %s, want Sunday 02:00", tt.at.Format(time.RFC1123), until)
+		}
+	}
+
+	oneOff := MaintenanceWindow{Name: "upgrade", Start: "2026-10-17T10:00:00Z", End: "2026-10-17T11:00:00Z"}
+	if _, _, ok := oneOff.ActiveAt(sat.Add(10*time.Hour + 30*time.Minute)); !ok {
+		t.Error("one-off window should be active inside its bounds")
+	}
+	if _, _, ok := oneOff.ActiveAt(sat.Add(11 * time.Hour)); ok {
+		t.Error("one-off window should end at its end time")
+	}
+}
+
+func TestCLIMaintenanceWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+
+	if active := ActiveMaintenanceWindow(now); active != nil {
+		t.Fatalf("unexpected active window: %+v", active)
+	}
+	if _, err := EndMaintenance(now); !errors.Is(err, ErrNoMaintenanceWindow) {
+		t.Fatalf("EndMaintenance with no window = %v, want ErrNoMaintenanceWindow", err)
+	}
+
+	short := MaintenanceWindow{Name: "short", Start: now.Add(-time.Minute).Format(time.RFC3339), End: now.Add(time.Hour).Format(time.RFC3339)}
+	long := MaintenanceWindow{Name: "long", Start: now.Add(-time.Minute).Format(time.RFC3339), End: now.Add(3 * time.Hour).Format(time.RFC3339), Reason: "migration"}
+	for _, w := range []MaintenanceWindow{short, long} {
+		if err := AddMaintenanceWindow(w, now); err != nil {
+			t.Fatalf("AddMaintenanceWindow(%s): %v", w.Name, err)
+		}
+	}
+
+	active := ActiveMaintenanceWindow(now)
+	if active == nil || active.Window.Name != "long" || active.Source != MaintenanceSourceCLI {
+		t.Fatalf("active window = %+v, want the one ending last", active)
+	}
+
+	ended, err := EndMaintenance(now)
+	if err != nil || len(ended) != 2 {
+		t.Fatalf("EndMaintenance = %v, %v", ended, err)
+	}
+	if active := ActiveMaintenanceWindow(now); active != nil {
+		t.Errorf("window still active after stop: %+v", active)
+	}
+
+	if err := RemoveMaintenanceWindow("missing", now); err == nil {
+		t.Error("removing an unknown window should fail")
+	}
+}
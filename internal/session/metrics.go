@@ -0,0 +1,28 @@
+package session
+
+import (
+	"github.com/asheshgoplani/agent-deck/internal/metrics"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// StatusDetectionLatency times UpdateStatus, in seconds, in this process
+var StatusDetectionLatency = metrics.NewHistogram(0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5)
+
+// countEvent bumps a counter in the profile's state database, shared by
+// every agent-deck process of the profile. Best effort: without a database
+// the event goes uncounted.
+func countEvent(name string) {
+	if db := statedb.GetGlobal(); db != nil {
+		_ = db.IncrCounter(name, 1)
+	}
+}
+
+// HeartbeatCounterName is the counter of a conductor's heartbeat results
+func HeartbeatCounterName(result, conductor string) string {
+	return statedb.CounterHeartbeatPrefix + result + "." + conductor
+}
+
+// countFork counts a forked session being created
+func countFork() {
+	countEvent(statedb.CounterForks)
+}
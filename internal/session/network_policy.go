@@ -0,0 +1,172 @@
+package session
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Network policy modes. A policy is stored as "offline" or
+// "restricted:host,host"; empty means open.
+const (
+	NetworkOpen       = "open"
+	NetworkOffline    = "offline"
+	NetworkRestricted = "restricted"
+)
+
+// Network policy enforcement mechanisms, best first
+const (
+	NetworkEnforceNamespace = "namespace"    // Linux user+network namespace (unshare)
+	NetworkEnforceSandbox   = "sandbox-exec" // macOS sandbox profile denying IP traffic
+	NetworkEnforceProxyEnv  = "proxy-env"    // proxy env vars pointing at a closed port, NO_PROXY allow-list
+)
+
+// networkBlackholeProxy is a proxy address nothing listens on: clients that
+// honor proxy env vars fail to connect anywhere not in NO_PROXY.
+const networkBlackholeProxy = "http://127.0.0.1:9"
+
+// networkSandboxProfile denies IP traffic but keeps unix sockets (MCP pool, tmux)
+const networkSandboxProfile = "(version 1)(allow default)(deny network-outbound (remote ip))"
+
+// toolNetworkHosts are the API hosts each agent needs to work at all; they are
+// always allowed under a restricted policy.
+var toolNetworkHosts = map[string][]string{
+	"claude":   {"api.anthropic.com", "statsig.anthropic.com"},
+	"gemini":   {"generativelanguage.googleapis.com", "oauth2.googleapis.com"},
+	"codex":    {"api.openai.com", "auth.openai.com"},
+	"opencode": {"api.anthropic.com", "api.openai.com"},
+}
+
+// ParseNetworkPolicy validates a --network mode and allow-list and returns the
+// stored policy ("" for open).
+func ParseNetworkPolicy(mode string, allow []string) (string, error) {
+	switch mode {
+	case "", NetworkOpen:
+		if len(allow) > 0 {
+			return "", fmt.Errorf("--allow-host requires --network %s", NetworkRestricted)
+		}
+		return "", nil
+	case NetworkOffline:
+		if len(allow) > 0 {
+			return "", fmt.Errorf("--allow-host requires --network %s (offline allows nothing)", NetworkRestricted)
+		}
+		return NetworkOffline, nil
+	case NetworkRestricted:
+		for _, host := range allow {
+			if host == "" || strings.ContainsAny(host, " ,/'\"") {
+				return "", fmt.Errorf("invalid allowed host %q", host)
+			}
+		}
+		return NetworkRestricted + ":" + strings.Join(allow, ","), nil
+	}
+	return "", fmt.Errorf("invalid network mode %q: use %s, %s or %s", mode, NetworkOpen, NetworkOffline, NetworkRestricted)
+}
+
+// NetworkMode returns the session's network mode and explicitly allowed hosts
+func (i *Instance) NetworkMode() (mode string, allow []string) {
+	if i.NetworkPolicy == "" {
+		return NetworkOpen, nil
+	}
+	mode, hosts, _ := strings.Cut(i.NetworkPolicy, ":")
+	if hosts != "" {
+		allow = strings.Split(hosts, ",")
+	}
+	return mode, allow
+}
+
+// NetworkAllowList returns every host reachable under a restricted policy:
+// loopback, the tool's API hosts and the explicit allow-list.
+func (i *Instance) NetworkAllowList() []string {
+	_, allow := i.NetworkMode()
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	hosts = append(hosts, toolNetworkHosts[i.Tool]...)
+	for _, host := range allow {
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// DescribeNetworkPolicy renders the policy for listings and the audit log
+func (i *Instance) DescribeNetworkPolicy() string {
+	mode, allow := i.NetworkMode()
+	switch mode {
+	case NetworkOpen:
+		return NetworkOpen
+	case NetworkOffline:
+		return fmt.Sprintf("offline (%s)", NetworkEnforcement(mode))
+	}
+	s := fmt.Sprintf("restricted (%s)", NetworkEnforcement(mode))
+	if len(allow) > 0 {
+		s += ", allow " + strings.Join(allow, ", ")
+	}
+	return s
+}
+
+var (
+	namespaceOnce      sync.Once
+	namespaceAvailable bool
+)
+
+// unshareArgs runs a command in a new user and network namespace as the
+// current user (so agents that refuse to run as root still start)
+var unshareArgs = []string{"unshare", "--user", "--map-current-user", "--net", "--"}
+
+// NetworkEnforcement returns how a policy mode is enforced on this machine.
+// Offline uses a network namespace or sandbox when one works and falls back to
+// proxy env vars, which only stop clients that honor them. Restricted always
+// uses proxy env vars, since an allow-list needs name-based filtering.
+func NetworkEnforcement(mode string) string {
+	if mode != NetworkOffline {
+		return NetworkEnforceProxyEnv
+	}
+	switch runtime.GOOS {
+	case "linux":
+		namespaceOnce.Do(func() {
+			// util-linux < 2.38 lacks --map-current-user and unprivileged
+			// user namespaces may be disabled, so probe once
+			args := append(append([]string{}, unshareArgs[1:]...), "true")
+			namespaceAvailable = exec.Command(unshareArgs[0], args...).Run() == nil
+		})
+		if namespaceAvailable {
+			return NetworkEnforceNamespace
+		}
+	case "darwin":
+		if _, err := exec.LookPath("sandbox-exec"); err == nil {
+			return NetworkEnforceSandbox
+		}
+	}
+	return NetworkEnforceProxyEnv
+}
+
+// applyNetworkPolicy wraps a session command so it runs under the session's
+// network policy. Open sessions are returned unchanged.
+func (i *Instance) applyNetworkPolicy(command string) string {
+	mode, _ := i.NetworkMode()
+	if mode == NetworkOpen || command == "" {
+		return command
+	}
+	quoted := "'" + strings.ReplaceAll(command, "'", "'\\''") + "'"
+
+	switch NetworkEnforcement(mode) {
+	case NetworkEnforceNamespace:
+		return strings.Join(unshareArgs, " ") + " bash -c " + quoted
+	case NetworkEnforceSandbox:
+		return fmt.Sprintf("sandbox-exec -p '%s' bash -c %s", networkSandboxProfile, quoted)
+	}
+
+	noProxy := ""
+	if mode == NetworkRestricted {
+		noProxy = strings.Join(i.NetworkAllowList(), ",")
+	}
+	var env []string
+	for _, key := range []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY"} {
+		env = append(env, key+"="+networkBlackholeProxy, strings.ToLower(key)+"="+networkBlackholeProxy)
+	}
+	env = append(env, "NO_PROXY="+noProxy, "no_proxy="+noProxy)
+	return "env " + strings.Join(env, " ") + " bash -c " + quoted
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNetworkPolicy(t *testing.T) {
+	tests := []struct {
+		mode    string
+		allow   []string
+		want    string
+		wantErr bool
+	}{
+		{"", nil, "", false},
+		{"open", nil, "", false},
+		{"offline", nil, "offline", false},
+		{"restricted", nil, "restricted:", false},
+		{"restricted", []string{"github.com", "proxy.golang.org"}, "restricted:github.com,proxy.golang.org", false},
+		{"offline", []string{"github.com"}, "", true},
+		{"open", []string{"github.com"}, "", true},
+		{"restricted", []string{"bad host"}, "", true},
+		{"airgap", nil, "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNetworkPolicy(tt.mode, tt.allow)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNetworkPolicy(%q, %v) error = %v, wantErr %v", tt.mode, tt.allow, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseNetworkPolicy(%q, %v) = %q, want %q", tt.mode, tt.allow, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkAllowList(t *testing.T) {
+	inst := &Instance{Tool: "claude", NetworkPolicy: "restricted:github.com,api.anthropic.com"}
+	mode, allow := inst.NetworkMode()
+	if mode != NetworkRestricted || len(allow) != 2 {
+		t.Fatalf("NetworkMode() = %q, %v", mode, allow)
+	}
+	got := strings.Join(inst.NetworkAllowList(), ",")
+	want := "localhost,127.0.0.1,::1,api.anthropic.com,statsig.anthropic.com,github.com"
+	if got != want {
+		t.Errorf("NetworkAllowList() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyNetworkPolicy(t *testing.T) {
+	open := &Instance{Tool: "claude"}
+	if got := open.applyNetworkPolicy("claude"); got != "claude" {
+		t.Errorf("open policy changed command: %q", got)
+	}
+
+	// Restricted sessions always use proxy env vars
+	inst := &Instance{Tool: "shell", NetworkPolicy: "restricted:github.com"}
+	got := inst.applyNetworkPolicy("echo 'hi' && make")
+	for _, want := range []string{
+		"env HTTP_PROXY=" + networkBlackholeProxy,
+		"https_proxy=" + networkBlackholeProxy,
+		"NO_PROXY=localhost,127.0.0.1,::1,github.com",
+		`bash -c 'echo '\''hi'\'' && make'`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("applyNetworkPolicy() = %q, missing %q", got, want)
+		}
+	}
+
+	offline := &Instance{Tool: "claude", NetworkPolicy: NetworkOffline}
+	got = offline.applyNetworkPolicy("claude")
+	switch NetworkEnforcement(NetworkOffline) {
+	case NetworkEnforceNamespace:
+		if !strings.HasPrefix(got, "unshare ") {
+			t.Errorf("namespace enforcement: %q", got)
+		}
+	case NetworkEnforceProxyEnv:
+		if !strings.Contains(got, "NO_PROXY= ") {
+			t.Errorf("offline proxy env must allow nothing: %q", got)
+		}
+	}
+}
@@ -186,6 +186,8 @@ func statusIcon(status Status) string {
 		return "○"
 	case StatusError:
 		return "✕"
+	case StatusThrottled:
+		return "⧖"
 	default:
 		return "○"
 	}
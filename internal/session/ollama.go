@@ -0,0 +1,162 @@
+package session
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// OllamaSettings defines ollama (local model) session configuration
+type OllamaSettings struct {
+	// Model is what new ollama sessions run when the command is just
+	// "ollama". Default: llama3.2
+	Model string `toml:"model"`
+
+	// Host points sessions at an ollama server other than the local
+	// default (exported as OLLAMA_HOST), e.g. "http://gpu-box:11434"
+	Host string `toml:"host"`
+
+	// EnvFile is a .env file specific to ollama sessions
+	// Sourced AFTER global [shell].env_files
+	EnvFile string `toml:"env_file"`
+}
+
+// GetOllamaSettings returns [ollama] from config.toml
+func GetOllamaSettings() OllamaSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return OllamaSettings{}
+	}
+	return config.Ollama
+}
+
+// GetModel returns the default model, defaulting to llama3.2
+func (s OllamaSettings) GetModel() string {
+	if s.Model == "" {
+		return "llama3.2"
+	}
+	return s.Model
+}
+
+// GetOllamaOptions returns ollama-specific options, or nil if not set
+func (i *Instance) GetOllamaOptions() *OllamaOptions {
+	if len(i.ToolOptionsJSON) == 0 {
+		return nil
+	}
+	opts, err := UnmarshalOllamaOptions(i.ToolOptionsJSON)
+	if err != nil {
+		return nil
+	}
+	return opts
+}
+
+// SetOllamaOptions stores ollama-specific options
+func (i *Instance) SetOllamaOptions(opts *OllamaOptions) error {
+	if opts == nil {
+		i.ToolOptionsJSON = nil
+		return nil
+	}
+	data, err := MarshalToolOptions(opts)
+	if err != nil {
+		return err
+	}
+	i.ToolOptionsJSON = data
+	return nil
+}
+
+// ollamaConversationName is the model a session's conversation is saved as.
+// ollama model names are lowercase letters, digits, '-', '_' and '.'.
+func ollamaConversationName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, id)
+	return "agent-deck-" + name
+}
+
+// buildOllamaCommand builds the command for an ollama chat session
+// ollama has no session IDs: a conversation is kept by saving it as a model
+// (/save <name> in the REPL) and resumed by running that model, which
+// restores its message history. Restart saves before respawning, and the
+// saved name is kept in the session's tool options.
+// Also sources .env files from [shell].env_files
+func (i *Instance) buildOllamaCommand(baseCommand string) string {
+	if i.Tool != "ollama" {
+		return baseCommand
+	}
+
+	envPrefix := i.buildEnvSourceCommand()
+	settings := GetOllamaSettings()
+	if settings.Host != "" {
+		envPrefix += fmt.Sprintf("OLLAMA_HOST=%q ", settings.Host)
+	}
+
+	// If baseCommand is just "ollama", run the saved conversation or the model
+	if baseCommand == "ollama" {
+		model := settings.GetModel()
+		if opts := i.GetOllamaOptions(); opts != nil {
+			if args := opts.ToArgs(); len(args) == 2 {
+				model = args[1]
+			}
+		}
+		return envPrefix + "ollama run " + model
+	}
+
+	// For custom commands (e.g., "ollama run qwen2.5-coder:7b"), return as-is
+	return envPrefix + baseCommand
+}
+
+// ollamaSaveTimeout bounds how long Restart waits for /save to finish
+const ollamaSaveTimeout = 10 * time.Second
+
+// saveOllamaConversation saves the running conversation with /save so the
+// restarted session resumes it. A response in progress is interrupted first
+// (Ctrl+C at an idle prompt only prints a hint). Returns false when the
+// pane never confirmed the save; the restart then starts a fresh chat with
+// whatever was saved before, if anything.
+func (i *Instance) saveOllamaConversation() bool {
+	if i.tmuxSession == nil || !i.tmuxSession.Exists() {
+		return false
+	}
+	name := ollamaConversationName(i.ID)
+	if err := i.tmuxSession.SendCtrlC(); err != nil {
+		return false
+	}
+	time.Sleep(300 * time.Millisecond)
+	if err := i.tmuxSession.SendKeysAndEnter("/save " + name); err != nil {
+		sessionLog.Warn("ollama_save_send_failed", slog.String("error", err.Error()))
+		return false
+	}
+
+	deadline := time.Now().Add(ollamaSaveTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+		content, err := i.tmuxSession.CapturePane()
+		if err != nil || !ollamaSaveSucceeded(content, name) {
+			continue
+		}
+		opts := i.GetOllamaOptions()
+		if opts == nil {
+			opts = &OllamaOptions{}
+		}
+		opts.Conversation = name
+		if err := i.SetOllamaOptions(opts); err != nil {
+			return false
+		}
+		sessionLog.Info("ollama_conversation_saved", slog.String("model", name))
+		return true
+	}
+	sessionLog.Warn("ollama_save_timeout", slog.String("model", name))
+	return false
+}
+
+// ollamaSaveSucceeded reports whether the pane shows /save's confirmation
+func ollamaSaveSucceeded(content, name string) bool {
+	return strings.Contains(content, fmt.Sprintf("Created new model '%s'", name))
+}
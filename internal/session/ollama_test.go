@@ -0,0 +1,54 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildOllamaCommand(t *testing.T) {
+	writeMacroConfig(t, "[ollama]\nmodel = \"qwen2.5-coder:7b\"\nhost = \"http://gpu-box:11434\"\n")
+	inst := &Instance{ID: "A1b2c3d4-1700000000", Tool: "ollama"}
+
+	got := inst.buildOllamaCommand("ollama")
+	if !strings.HasSuffix(got, `OLLAMA_HOST="http://gpu-box:11434" ollama run qwen2.5-coder:7b`) {
+		t.Errorf("fresh command = %q", got)
+	}
+
+	// A per-session model beats [ollama] model, and a saved conversation beats both
+	if err := inst.SetOllamaOptions(&OllamaOptions{Model: "llama3.1:8b"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := inst.buildOllamaCommand("ollama"); !strings.HasSuffix(got, "ollama run llama3.1:8b") {
+		t.Errorf("per-session model command = %q", got)
+	}
+	name := ollamaConversationName(inst.ID)
+	if name != "agent-deck-a1b2c3d4-1700000000" {
+		t.Errorf("conversation name = %q", name)
+	}
+	_ = inst.SetOllamaOptions(&OllamaOptions{Model: "llama3.1:8b", Conversation: name})
+	if got := inst.buildOllamaCommand("ollama"); !strings.HasSuffix(got, "ollama run "+name) {
+		t.Errorf("resume command = %q", got)
+	}
+	if opts := inst.GetOllamaOptions(); opts == nil || opts.Model != "llama3.1:8b" {
+		t.Errorf("options round trip = %+v", opts)
+	}
+
+	if got := inst.buildOllamaCommand("ollama run phi3"); !strings.HasSuffix(got, "ollama run phi3") {
+		t.Errorf("custom command = %q", got)
+	}
+	other := &Instance{Tool: "codex"}
+	if got := other.buildOllamaCommand("codex"); got != "codex" {
+		t.Errorf("non-ollama tool = %q", got)
+	}
+}
+
+func TestOllamaSaveSucceeded(t *testing.T) {
+	name := ollamaConversationName("abcd1234-1")
+	pane := ">>> /save " + name + "\nCreated new model '" + name + "'\n>>> Send a message (/? for help)"
+	if !ollamaSaveSucceeded(pane, name) {
+		t.Error("expected the save confirmation to be found")
+	}
+	if ollamaSaveSucceeded(">>> /save "+name+"\nerror: model name invalid", name) {
+		t.Error("a failed save should not count")
+	}
+}
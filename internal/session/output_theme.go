@@ -0,0 +1,80 @@
+package session
+
+import (
+	"os"
+	"strings"
+)
+
+// Output color modes (see OutputSettings.Color)
+const (
+	OutputColorAuto   = "auto"
+	OutputColorAlways = "always"
+	OutputColorNever  = "never"
+)
+
+// defaultStatusGlyphs are the standard status symbols used across CLI and TUI
+var defaultStatusGlyphs = map[string]string{
+	string(StatusRunning):   "●",
+	string(StatusWaiting):   "◐",
+	string(StatusIdle):      "○",
+	string(StatusError):     "✕",
+	string(StatusStarting):  "⟳",
+	string(StatusThrottled): "⧖",
+}
+
+// defaultStatusColors match the TUI's dark theme (green, yellow, dim, red, orange)
+var defaultStatusColors = map[string]string{
+	string(StatusRunning):   "#9ece6a",
+	string(StatusWaiting):   "#e0af68",
+	string(StatusIdle):      "#565f89",
+	string(StatusError):     "#f7768e",
+	string(StatusStarting):  "#e0af68",
+	string(StatusThrottled): "#ff9e64",
+}
+
+// NoColorRequested reports whether the NO_COLOR convention (no-color.org) is in effect
+func NoColorRequested() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// ColorEnabled decides whether to emit color. isTerminal is whether the
+// output goes to a terminal and only matters in auto mode.
+func (o OutputSettings) ColorEnabled(isTerminal bool) bool {
+	if NoColorRequested() || o.ScreenReader {
+		return false
+	}
+	switch strings.ToLower(o.Color) {
+	case OutputColorAlways:
+		return true
+	case OutputColorNever:
+		return false
+	default:
+		return isTerminal
+	}
+}
+
+// StatusGlyph returns the symbol for status. In screen-reader mode it returns
+// the status word so the line reads naturally.
+func (o OutputSettings) StatusGlyph(status string) string {
+	if o.ScreenReader {
+		if status == "" {
+			return "unknown"
+		}
+		return status
+	}
+	if g, ok := o.Glyphs[status]; ok && g != "" {
+		return g
+	}
+	if g, ok := defaultStatusGlyphs[status]; ok {
+		return g
+	}
+	return "?"
+}
+
+// StatusColor returns the configured color for status ("" if none)
+func (o OutputSettings) StatusColor(status string) string {
+	if c, ok := o.Colors[status]; ok && c != "" {
+		return c
+	}
+	return defaultStatusColors[status]
+}
@@ -0,0 +1,57 @@
+package session
+
+import "testing"
+
+func TestOutputSettingsColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	tests := []struct {
+		name     string
+		settings OutputSettings
+		terminal bool
+		want     bool
+	}{
+		{"auto on terminal", OutputSettings{}, true, true},
+		{"auto into pipe", OutputSettings{}, false, false},
+		{"always into pipe", OutputSettings{Color: "always"}, false, true},
+		{"never on terminal", OutputSettings{Color: "never"}, true, false},
+		{"screen reader", OutputSettings{Color: "always", ScreenReader: true}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.ColorEnabled(tt.terminal); got != tt.want {
+				t.Errorf("ColorEnabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if (OutputSettings{Color: "always"}).ColorEnabled(true) {
+		t.Error("NO_COLOR should override color = always")
+	}
+}
+
+func TestOutputSettingsStatusGlyph(t *testing.T) {
+	var def OutputSettings
+	if got := def.StatusGlyph("waiting"); got != "◐" {
+		t.Errorf("default waiting glyph = %q", got)
+	}
+	if got := def.StatusGlyph("bogus"); got != "?" {
+		t.Errorf("unknown glyph = %q, want ?", got)
+	}
+
+	custom := OutputSettings{Glyphs: map[string]string{"waiting": "W"}, Colors: map[string]string{"error": "1"}}
+	if got := custom.StatusGlyph("waiting"); got != "W" {
+		t.Errorf("custom waiting glyph = %q, want W", got)
+	}
+	if got := custom.StatusGlyph("running"); got != "●" {
+		t.Errorf("non-overridden glyph = %q, want default", got)
+	}
+	if got := custom.StatusColor("error"); got != "1" {
+		t.Errorf("custom error color = %q, want 1", got)
+	}
+
+	reader := OutputSettings{ScreenReader: true, Glyphs: map[string]string{"waiting": "W"}}
+	if got := reader.StatusGlyph("waiting"); got != "waiting" {
+		t.Errorf("screen reader glyph = %q, want the word", got)
+	}
+}
@@ -0,0 +1,468 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// Pattern packs are shareable status detection pattern sets for one tool.
+// Installed packs live in ~/.agent-deck/patterns/<tool>/<name>.toml and sit
+// between the built-in defaults and config.toml: a pack replaces the default
+// fields it sets, and [tools.<tool>] overrides and *_extra fields still apply
+// on top. A pack only applies while the installed tool's version is inside
+// the pack's tool_versions range.
+const (
+	patternPacksDirName = "patterns"
+	patternPackExt      = ".toml"
+	patternPackMaxBytes = 1 << 20
+)
+
+var (
+	ErrPatternPackNotFound = errors.New("pattern pack not found")
+	ErrPatternPackInvalid  = errors.New("invalid pattern pack")
+)
+
+var patternPackNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// PatternPack is a pattern pack file
+type PatternPack struct {
+	Name        string `toml:"name" json:"name"`
+	Tool        string `toml:"tool" json:"tool"`
+	Version     string `toml:"version" json:"version"`
+	Description string `toml:"description,omitempty" json:"description,omitempty"`
+	Author      string `toml:"author,omitempty" json:"author,omitempty"`
+	// ToolVersions is the range of tool versions the pack targets, as
+	// comparisons separated by spaces or commas: ">=2.1.25, <3". Empty
+	// targets every version.
+	ToolVersions string       `toml:"tool_versions,omitempty" json:"tool_versions,omitempty"`
+	Patterns     PackPatterns `toml:"patterns" json:"patterns"`
+
+	// Path of the installed pack file (not part of the file itself)
+	Path string `toml:"-" json:"path,omitempty"`
+}
+
+// PackPatterns are a pack's patterns, with the same meaning as the
+// [tools.<tool>] fields of the same names
+type PackPatterns struct {
+	BusyPatterns       []string `toml:"busy_patterns,omitempty" json:"busy_patterns,omitempty"`
+	PromptPatterns     []string `toml:"prompt_patterns,omitempty" json:"prompt_patterns,omitempty"`
+	SpinnerChars       []string `toml:"spinner_chars,omitempty" json:"spinner_chars,omitempty"`
+	WhimsicalWords     []string `toml:"whimsical_words,omitempty" json:"whimsical_words,omitempty"`
+	StatusLines        int      `toml:"status_lines,omitzero" json:"status_lines,omitempty"`
+	CaptureAltScreen   bool     `toml:"capture_alt_screen,omitempty" json:"capture_alt_screen,omitempty"`
+	MatchANSI          bool     `toml:"match_ansi,omitempty" json:"match_ansi,omitempty"`
+	StripSpinnerFrames bool     `toml:"strip_spinner_frames,omitempty" json:"strip_spinner_frames,omitempty"`
+	CollapseProgress   bool     `toml:"collapse_progress,omitempty" json:"collapse_progress,omitempty"`
+	BannerEnd          string   `toml:"banner_end,omitempty" json:"banner_end,omitempty"`
+}
+
+// raw returns the pack patterns as overrides for tmux.MergeRawPatterns
+func (p PackPatterns) raw() *tmux.RawPatterns {
+	return &tmux.RawPatterns{
+		BusyPatterns:   p.BusyPatterns,
+		PromptPatterns: p.PromptPatterns,
+		SpinnerChars:   p.SpinnerChars,
+		WhimsicalWords: p.WhimsicalWords,
+		Capture: tmux.CaptureOptions{
+			Lines:              p.StatusLines,
+			AltScreen:          p.CaptureAltScreen,
+			MatchANSI:          p.MatchANSI,
+			StripSpinnerFrames: p.StripSpinnerFrames,
+			CollapseProgress:   p.CollapseProgress,
+			BannerEnd:          p.BannerEnd,
+		},
+	}
+}
+
+func packPatternsFromRaw(raw *tmux.RawPatterns) PackPatterns {
+	return PackPatterns{
+		BusyPatterns:       raw.BusyPatterns,
+		PromptPatterns:     raw.PromptPatterns,
+		SpinnerChars:       raw.SpinnerChars,
+		WhimsicalWords:     raw.WhimsicalWords,
+		StatusLines:        raw.Capture.Lines,
+		CaptureAltScreen:   raw.Capture.AltScreen,
+		MatchANSI:          raw.Capture.MatchANSI,
+		StripSpinnerFrames: raw.Capture.StripSpinnerFrames,
+		CollapseProgress:   raw.Capture.CollapseProgress,
+		BannerEnd:          raw.Capture.BannerEnd,
+	}
+}
+
+// Validate checks the pack's fields and compiles every pattern, so a broken
+// pack is rejected at import instead of being skipped at detection time
+func (p *PatternPack) Validate() error {
+	if !patternPackNameRegex.MatchString(p.Name) {
+		return fmt.Errorf("%w: name %q must be letters, digits, '.', '_' or '-'", ErrPatternPackInvalid, p.Name)
+	}
+	if !patternPackNameRegex.MatchString(p.Tool) {
+		return fmt.Errorf("%w: tool %q is missing or invalid", ErrPatternPackInvalid, p.Tool)
+	}
+	if p.Version == "" {
+		return fmt.Errorf("%w: version is required", ErrPatternPackInvalid)
+	}
+	if _, err := ParseVersionRange(p.ToolVersions); err != nil {
+		return fmt.Errorf("%w: %v", ErrPatternPackInvalid, err)
+	}
+	pats := p.Patterns
+	if len(pats.BusyPatterns)+len(pats.PromptPatterns)+len(pats.SpinnerChars)+len(pats.WhimsicalWords) == 0 {
+		return fmt.Errorf("%w: no patterns", ErrPatternPackInvalid)
+	}
+	if err := validateBannerEnd(pats.BannerEnd); err != nil {
+		return fmt.Errorf("%w: %v", ErrPatternPackInvalid, err)
+	}
+	for _, list := range [][]string{pats.BusyPatterns, pats.PromptPatterns} {
+		for _, pattern := range list {
+			if err := validatePattern(pattern); err != nil {
+				return fmt.Errorf("%w: %v", ErrPatternPackInvalid, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePattern compiles a busy/prompt pattern the way CompilePatterns does
+func validatePattern(pattern string) error {
+	if pattern == "" {
+		return errors.New("empty pattern")
+	}
+	if strings.HasPrefix(pattern, "{") && strings.Contains(pattern, "}") {
+		_, err := tmux.ParsePatternRule(pattern)
+		return err
+	}
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid regex in %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// ParsePatternPack parses and validates a pack file
+func ParsePatternPack(data []byte) (*PatternPack, error) {
+	var pack PatternPack
+	if _, err := toml.Decode(string(data), &pack); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPatternPackInvalid, err)
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// Encode returns the pack in its file format
+func (p *PatternPack) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# agent-deck pattern pack; import with: agent-deck patterns import <file>\n")
+	if err := toml.NewEncoder(&buf).Encode(p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PatternPacksDir returns ~/.agent-deck/patterns
+func PatternPacksDir() (string, error) {
+	base, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, patternPacksDirName), nil
+}
+
+// ListPatternPacks returns the installed packs for tool ("" = all tools),
+// sorted by tool and name. Unreadable pack files are skipped.
+func ListPatternPacks(tool string) ([]*PatternPack, error) {
+	dir, err := PatternPacksDir()
+	if err != nil {
+		return nil, err
+	}
+	pattern := filepath.Join(dir, "*", "*"+patternPackExt)
+	if tool != "" {
+		pattern = filepath.Join(dir, tool, "*"+patternPackExt)
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var packs []*PatternPack
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		pack, err := ParsePatternPack(data)
+		if err != nil {
+			sessionLog.Warn("pattern_pack_invalid", slog.String("path", file), slog.String("error", err.Error()))
+			continue
+		}
+		pack.Path = file
+		packs = append(packs, pack)
+	}
+	slices.SortFunc(packs, func(a, b *PatternPack) int {
+		if c := strings.Compare(a.Tool, b.Tool); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return packs, nil
+}
+
+// readPatternPackSource reads a pack from a file path or an http(s) URL
+func readPatternPackSource(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.ReadFile(expandSkillPath(source))
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, patternPackMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > patternPackMaxBytes {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrPatternPackInvalid, patternPackMaxBytes)
+	}
+	return data, nil
+}
+
+// ImportPatternPack validates the pack at source (file or URL) and installs
+// it, replacing an installed pack of the same tool and name. It returns the
+// installed pack and the version it replaced ("" if none).
+func ImportPatternPack(source string) (*PatternPack, string, error) {
+	data, err := readPatternPackSource(source)
+	if err != nil {
+		return nil, "", err
+	}
+	pack, err := ParsePatternPack(data)
+	if err != nil {
+		return nil, "", err
+	}
+	dir, err := PatternPacksDir()
+	if err != nil {
+		return nil, "", err
+	}
+	path := filepath.Join(dir, pack.Tool, pack.Name+patternPackExt)
+	replaced := ""
+	if old, err := os.ReadFile(path); err == nil {
+		if oldPack, err := ParsePatternPack(old); err == nil {
+			replaced = oldPack.Version
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return nil, "", fmt.Errorf("failed to write pattern pack: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, "", fmt.Errorf("failed to write pattern pack: %w", err)
+	}
+	pack.Path = path
+	return pack, replaced, nil
+}
+
+// RemovePatternPack uninstalls a pack
+func RemovePatternPack(tool, name string) error {
+	dir, err := PatternPacksDir()
+	if err != nil {
+		return err
+	}
+	if !patternPackNameRegex.MatchString(tool) || !patternPackNameRegex.MatchString(name) {
+		return fmt.Errorf("%w: %s/%s", ErrPatternPackNotFound, tool, name)
+	}
+	err = os.Remove(filepath.Join(dir, tool, name+patternPackExt))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s/%s", ErrPatternPackNotFound, tool, name)
+	}
+	return err
+}
+
+// ExportPatternPack returns the effective patterns of a tool (built-in
+// defaults, active pack and config.toml overrides merged, plus the patterns
+// file of projectPath unless it is "") as a pack.
+// toolVersions "" targets the detected tool version and newer.
+func ExportPatternPack(tool, name, version, toolVersions, projectPath string) (*PatternPack, error) {
+	raw := MergeProjectToolPatterns(tool, projectPath)
+	if raw == nil {
+		return nil, fmt.Errorf("no patterns for tool %q (not a built-in tool or [tools.%s] entry)", tool, tool)
+	}
+	if name == "" {
+		name = tool + "-local"
+	}
+	if version == "" {
+		version = "1"
+	}
+	if toolVersions == "" {
+		if v := ToolVersion(tool); v != "" {
+			toolVersions = ">=" + v
+		}
+	}
+	pack := &PatternPack{
+		Name:         name,
+		Tool:         tool,
+		Version:      version,
+		ToolVersions: toolVersions,
+		Patterns:     packPatternsFromRaw(raw),
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// ActivePatternPack returns the installed pack applied to tool: among the
+// packs whose tool_versions contain the installed tool version (any pack
+// when the version cannot be detected), the highest pack version wins, then
+// the last name. Returns nil when none applies.
+func ActivePatternPack(tool string) *PatternPack {
+	packs, err := ListPatternPacks(tool)
+	if err != nil || len(packs) == 0 {
+		return nil
+	}
+	toolVersion := ToolVersion(tool)
+	var active *PatternPack
+	for _, pack := range packs {
+		r, _ := ParseVersionRange(pack.ToolVersions)
+		if toolVersion != "" && !r.Contains(toolVersion) {
+			continue
+		}
+		if active == nil || compareVersions(pack.Version, active.Version) >= 0 {
+			active = pack
+		}
+	}
+	return active
+}
+
+// VersionRange is a parsed tool_versions range
+type VersionRange []versionConstraint
+
+type versionConstraint struct {
+	op      string
+	version string
+}
+
+var versionConstraintRegex = regexp.MustCompile(`^(>=|<=|==|=|>|<)?v?(\d+(?:\.\d+){0,2})$`)
+
+// ParseVersionRange parses comparisons separated by spaces or commas, e.g.
+// ">=2.1.25, <3". A bare version means "=". Empty matches every version.
+func ParseVersionRange(s string) (VersionRange, error) {
+	var r VersionRange
+	for _, field := range strings.FieldsFunc(s, func(c rune) bool { return c == ',' || c == ' ' }) {
+		m := versionConstraintRegex.FindStringSubmatch(field)
+		if m == nil {
+			return nil, fmt.Errorf("invalid tool version constraint %q (want e.g. \">=1.2, <2\")", field)
+		}
+		op := m[1]
+		if op == "" || op == "==" {
+			op = "="
+		}
+		r = append(r, versionConstraint{op: op, version: m[2]})
+	}
+	return r, nil
+}
+
+// Contains reports whether version satisfies every comparison
+func (r VersionRange) Contains(version string) bool {
+	for _, c := range r {
+		cmp := compareVersions(version, c.version)
+		ok := false
+		switch c.op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares dotted numeric versions; missing parts are 0
+func compareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var na, nb int
+		if i < len(pa) {
+			_, _ = fmt.Sscanf(pa[i], "%d", &na)
+		}
+		if i < len(pb) {
+			_, _ = fmt.Sscanf(pb[i], "%d", &nb)
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+var (
+	toolVersionCache   sync.Map // tool -> detected version ("" when unknown)
+	toolVersionPattern = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?`)
+)
+
+// detectToolVersion runs "<tool> --version"; a variable so tests can stub it
+var detectToolVersion = func(tool string) string {
+	bin := tool
+	if def := GetToolDef(tool); def != nil {
+		if fields := strings.Fields(def.Command); len(fields) > 0 {
+			bin = fields[0]
+		}
+	}
+	if _, err := exec.LookPath(bin); err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, bin, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return toolVersionPattern.FindString(string(out))
+}
+
+// ToolVersion returns the installed version of tool ("" when it cannot be
+// detected). It is detected once per process.
+func ToolVersion(tool string) string {
+	if v, ok := toolVersionCache.Load(tool); ok {
+		return v.(string)
+	}
+	v := detectToolVersion(tool)
+	toolVersionCache.Store(tool, v)
+	return v
+}
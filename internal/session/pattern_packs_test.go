@@ -0,0 +1,140 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// stubToolVersion makes ToolVersion report version for every tool
+func stubToolVersion(t *testing.T, version string) {
+	t.Helper()
+	orig := detectToolVersion
+	detectToolVersion = func(string) string { return version }
+	toolVersionCache.Clear()
+	t.Cleanup(func() {
+		detectToolVersion = orig
+		toolVersionCache.Clear()
+	})
+}
+
+func writePackFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pack.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestVersionRange(t *testing.T) {
+	r, err := ParseVersionRange(">=2.1.25, <3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for version, want := range map[string]bool{"2.1.25": true, "2.9": true, "2.1.24": false, "3.0.0": false} {
+		if got := r.Contains(version); got != want {
+			t.Errorf("Contains(%s) = %v, want %v", version, got, want)
+		}
+	}
+	if r, _ := ParseVersionRange(""); !r.Contains("0.1") {
+		t.Error("empty range should contain every version")
+	}
+	if _, err := ParseVersionRange("~2.1"); err == nil {
+		t.Error("unsupported operators should be rejected")
+	}
+}
+
+func TestPatternPackImportAndMerge(t *testing.T) {
+	writeMacroConfig(t, `
+[tools.gemini]
+prompt_patterns_extra = ["custom>"]
+`)
+	stubToolVersion(t, "0.9.1")
+
+	path := writePackFile(t, `
+name = "gemini-next"
+tool = "gemini"
+version = "2"
+tool_versions = ">=0.9, <1"
+
+[patterns]
+busy_patterns = ["{last=3}esc to cancel", "re:working \\d+s"]
+`)
+	pack, replaced, err := ImportPatternPack(path)
+	if err != nil {
+		t.Fatalf("ImportPatternPack: %v", err)
+	}
+	if replaced != "" || pack.Path == "" {
+		t.Errorf("first import: replaced = %q, path = %q", replaced, pack.Path)
+	}
+	if active := ActivePatternPack("gemini"); active == nil || active.Name != "gemini-next" {
+		t.Fatalf("ActivePatternPack = %+v", active)
+	}
+
+	// The pack replaces the default busy patterns; defaults it does not set
+	// and config.toml extras still apply
+	raw := MergeToolPatterns("gemini")
+	if !slices.Equal(raw.BusyPatterns, []string{"{last=3}esc to cancel", `re:working \d+s`}) {
+		t.Errorf("BusyPatterns = %v", raw.BusyPatterns)
+	}
+	if !slices.Contains(raw.PromptPatterns, "gemini>") || !slices.Contains(raw.PromptPatterns, "custom>") {
+		t.Errorf("PromptPatterns = %v", raw.PromptPatterns)
+	}
+
+	// Export round-trips the effective set
+	exported, err := ExportPatternPack("gemini", "shared", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.ToolVersions != ">=0.9.1" || !slices.Contains(exported.Patterns.PromptPatterns, "custom>") {
+		t.Errorf("exported pack = %+v", exported)
+	}
+	data, err := exported.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParsePatternPack(data); err != nil {
+		t.Errorf("exported pack does not parse: %v", err)
+	}
+
+	// Outside its version range the pack no longer applies
+	stubToolVersion(t, "1.2.0")
+	if active := ActivePatternPack("gemini"); active != nil {
+		t.Errorf("pack should be inactive for gemini 1.2.0, got %s", active.Name)
+	}
+	if raw := MergeToolPatterns("gemini"); !slices.Equal(raw.BusyPatterns, tmux.DefaultRawPatterns("gemini").BusyPatterns) {
+		t.Errorf("BusyPatterns without pack = %v", raw.BusyPatterns)
+	}
+
+	if err := RemovePatternPack("gemini", "gemini-next"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemovePatternPack("gemini", "gemini-next"); !errors.Is(err, ErrPatternPackNotFound) {
+		t.Errorf("second remove = %v, want ErrPatternPackNotFound", err)
+	}
+}
+
+func TestPatternPackValidation(t *testing.T) {
+	writeMacroConfig(t, "")
+
+	tests := map[string]string{
+		"bad regex":   "name = \"x\"\ntool = \"claude\"\nversion = \"1\"\n[patterns]\nbusy_patterns = [\"re:(\"]\n",
+		"bad rule":    "name = \"x\"\ntool = \"claude\"\nversion = \"1\"\n[patterns]\nbusy_patterns = [\"{bogus}x\"]\n",
+		"bad range":   "name = \"x\"\ntool = \"claude\"\nversion = \"1\"\ntool_versions = \"^2\"\n[patterns]\nbusy_patterns = [\"x\"]\n",
+		"no patterns": "name = \"x\"\ntool = \"claude\"\nversion = \"1\"\n",
+		"bad name":    "name = \"../x\"\ntool = \"claude\"\nversion = \"1\"\n[patterns]\nbusy_patterns = [\"x\"]\n",
+	}
+	for name, content := range tests {
+		if _, _, err := ImportPatternPack(writePackFile(t, content)); !errors.Is(err, ErrPatternPackInvalid) {
+			t.Errorf("%s: err = %v, want ErrPatternPackInvalid", name, err)
+		}
+	}
+	if packs, _ := ListPatternPacks(""); len(packs) != 0 {
+		t.Errorf("invalid packs were installed: %v", packs)
+	}
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"context"
+	"log/slog"
+	"os/exec"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/power"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+var powerLog = logging.ForComponent(logging.CompPower)
+
+// Heartbeat-on-resume policies, set with [conductor] heartbeat_on_resume
+const (
+	ResumeHeartbeatMissed = "missed"
+	ResumeHeartbeatSkip   = "skip"
+)
+
+// resumeHeartbeatGrace lets timers that catch up on their own (launchd
+// fires a missed interval on wake) go first
+const resumeHeartbeatGrace = 30 * time.Second
+
+// WatchPower follows system suspend and resume for a long-lived process (the
+// TUI, web --headless) until ctx is done. Each sleep is recorded in the state
+// DB, so busy time excludes it, and onResume runs to reconcile statuses. The
+// first process to record a sleep also sends the heartbeats missed during
+// it, per [conductor] heartbeat_on_resume. It blocks; run it in a goroutine.
+func WatchPower(ctx context.Context, onResume func(power.Sleep)) {
+	power.Watch(ctx, func(sleep power.Sleep) {
+		powerLog.Info("resume",
+			slog.Time("suspended_at", sleep.Start),
+			slog.Duration("slept", sleep.Duration()),
+		)
+		claimed := false
+		if db := statedb.GetGlobal(); db != nil {
+			var err error
+			if claimed, err = db.RecordSleep(sleep.Start, sleep.End); err != nil {
+				powerLog.Warn("record_sleep_failed", slog.String("error", err.Error()))
+			}
+		}
+		if onResume != nil {
+			onResume(sleep)
+		}
+		settings := GetConductorSettings()
+		if claimed && settings.GetHeartbeatOnResume() == ResumeHeartbeatMissed {
+			go fireMissedHeartbeats(ctx, sleep)
+		}
+	})
+}
+
+// fireMissedHeartbeats runs the heartbeat of every conductor that missed
+// one during sleep, after a grace period
+func fireMissedHeartbeats(ctx context.Context, sleep power.Sleep) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(resumeHeartbeatGrace):
+	}
+	for _, name := range MissedHeartbeats(sleep, time.Now()) {
+		heartbeatLog.Info("missed_heartbeat_resent",
+			slog.String("conductor", name),
+			slog.Duration("slept", sleep.Duration()),
+		)
+		argv := append(HeartbeatCommand(name), "-q")
+		if err := exec.CommandContext(ctx, argv[0], argv[1:]...).Run(); err != nil {
+			heartbeatLog.Warn("missed_heartbeat_failed", slog.String("conductor", name), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// MissedHeartbeats returns the conductors whose heartbeat fell due while the
+// machine slept and has not run since
+func MissedHeartbeats(sleep power.Sleep, now time.Time) []string {
+	conductors, err := ListConductors()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for i := range conductors {
+		meta := &conductors[i]
+		var last time.Time
+		if result, err := ReadHeartbeatResult(meta.Name); err == nil && result != nil {
+			last = result.Time
+		}
+		if heartbeatMissed(meta, last, sleep, now) {
+			names = append(names, meta.Name)
+		}
+	}
+	return names
+}
+
+// heartbeatMissed reports whether a heartbeat last run at last fell due
+// during sleep: on a fixed interval, it came due after the sleep began (the
+// timer counts only awake time, so it runs late); on a cron schedule, the
+// schedule fired during the sleep. OnCalendar schedules are left to their
+// timer, which skips runs the machine slept through.
+func heartbeatMissed(meta *ConductorMeta, last time.Time, sleep power.Sleep, now time.Time) bool {
+	if !meta.HeartbeatEnabled || last.After(sleep.End) {
+		return false
+	}
+	if schedule := EffectiveHeartbeatSchedule(meta); schedule != "" {
+		sched, err := ParseHeartbeatSchedule(schedule)
+		if err != nil || sched.Kind != ScheduleCron {
+			return false
+		}
+		fired, err := sched.FiredBetween(sleep.Start, sleep.End)
+		return err == nil && fired
+	}
+	if last.IsZero() {
+		return false
+	}
+	due := last.Add(time.Duration(HeartbeatIntervalFor(meta)) * time.Minute)
+	return due.After(sleep.Start) && !due.After(now)
+}
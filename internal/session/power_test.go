@@ -0,0 +1,40 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/power"
+)
+
+func TestHeartbeatMissed(t *testing.T) {
+	sleep := power.Sleep{
+		Start: time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local),
+		End:   time.Date(2026, 3, 10, 13, 0, 0, 0, time.Local),
+	}
+	now := sleep.End.Add(time.Minute)
+	interval := &ConductorMeta{Name: "ops", HeartbeatEnabled: true, HeartbeatInterval: 15}
+	cron := &ConductorMeta{Name: "ops", HeartbeatEnabled: true, HeartbeatSchedule: "30 * * * *"}
+
+	tests := []struct {
+		name string
+		meta *ConductorMeta
+		last time.Time
+		want bool
+	}{
+		{"due during sleep", interval, sleep.Start.Add(-5 * time.Minute), true},
+		{"overdue before sleep", interval, sleep.Start.Add(-time.Hour), false},
+		{"ran after resume", interval, sleep.End.Add(30 * time.Second), false},
+		{"never ran", interval, time.Time{}, false},
+		{"disabled", &ConductorMeta{Name: "ops", HeartbeatInterval: 15}, sleep.Start.Add(-5 * time.Minute), false},
+		{"cron fired during sleep", cron, sleep.Start.Add(-5 * time.Minute), true},
+		{"oncalendar left to its timer", &ConductorMeta{Name: "ops", HeartbeatEnabled: true, HeartbeatSchedule: "*-*-* *:30:00"}, sleep.Start.Add(-5 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heartbeatMissed(tt.meta, tt.last, sleep, now); got != tt.want {
+				t.Errorf("heartbeatMissed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// ProjectPatternsFile is a project's own status detection patterns, relative
+// to the project directory. It has one table per tool, with the pattern
+// fields of [tools.<tool>]:
+//
+//	[claude]
+//	prompt_patterns_extra = ["re:(?m)^wrapper> $"]
+//
+// It is applied last, on top of the built-in defaults, the active pattern
+// pack and config.toml, so projects that wrap a tool in scripts can adjust
+// detection without changing it for every session.
+const ProjectPatternsFile = ".agent-deck/patterns.toml"
+
+// ProjectToolPatterns are one tool's overrides in a project patterns file.
+// The plain fields replace the patterns merged so far; *_extra fields are
+// appended to them.
+type ProjectToolPatterns struct {
+	BusyPatterns        []string `toml:"busy_patterns"`
+	PromptPatterns      []string `toml:"prompt_patterns"`
+	SpinnerChars        []string `toml:"spinner_chars"`
+	BusyPatternsExtra   []string `toml:"busy_patterns_extra"`
+	PromptPatternsExtra []string `toml:"prompt_patterns_extra"`
+	SpinnerCharsExtra   []string `toml:"spinner_chars_extra"`
+}
+
+// validate checks that every pattern compiles
+func (p *ProjectToolPatterns) validate() error {
+	for _, field := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"busy_patterns", p.BusyPatterns},
+		{"prompt_patterns", p.PromptPatterns},
+		{"busy_patterns_extra", p.BusyPatternsExtra},
+		{"prompt_patterns_extra", p.PromptPatternsExtra},
+	} {
+		for n, pattern := range field.patterns {
+			if err := validatePattern(pattern); err != nil {
+				return fmt.Errorf("%s[%d]: %w", field.name, n, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadProjectPatterns reads the project patterns file of projectPath, keyed
+// by tool. A missing file returns nil and no error; tool tables with an
+// invalid pattern are dropped and reported in the error, the rest still load.
+func LoadProjectPatterns(projectPath string) (map[string]*ProjectToolPatterns, error) {
+	if projectPath == "" {
+		return nil, nil
+	}
+	path := filepath.Join(projectPath, ProjectPatternsFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var patterns map[string]*ProjectToolPatterns
+	meta, err := toml.Decode(string(data), &patterns)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, key := range meta.Undecoded() {
+		sessionLog.Warn("project_patterns_unknown_key", slog.String("file", path), slog.String("key", key.String()))
+	}
+
+	var invalid error
+	tools := make([]string, 0, len(patterns))
+	for tool := range patterns {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		if err := patterns[tool].validate(); err != nil {
+			delete(patterns, tool)
+			if invalid == nil {
+				invalid = fmt.Errorf("%s [%s]: %w", path, tool, err)
+			}
+		}
+	}
+	return patterns, invalid
+}
+
+// MergeProjectToolPatterns returns the patterns of a tool for a session in
+// projectPath: MergeToolPatterns with the project's patterns file on top.
+// Problems with the file are logged and the config patterns used instead.
+func MergeProjectToolPatterns(toolName, projectPath string) *tmux.RawPatterns {
+	raw := MergeToolPatterns(toolName)
+	patterns, err := LoadProjectPatterns(projectPath)
+	if err != nil {
+		sessionLog.Warn("project_patterns_invalid", slog.String("tool", toolName), slog.String("error", err.Error()))
+	}
+	project := patterns[toolName]
+	if project == nil {
+		return raw
+	}
+	return tmux.MergeRawPatterns(raw,
+		&tmux.RawPatterns{
+			BusyPatterns:   project.BusyPatterns,
+			PromptPatterns: project.PromptPatterns,
+			SpinnerChars:   project.SpinnerChars,
+		},
+		&tmux.RawPatterns{
+			BusyPatterns:   project.BusyPatternsExtra,
+			PromptPatterns: project.PromptPatternsExtra,
+			SpinnerChars:   project.SpinnerCharsExtra,
+		})
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func writeProjectPatterns(t *testing.T, content string) string {
+	t.Helper()
+	project := t.TempDir()
+	path := filepath.Join(project, ProjectPatternsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func TestMergeProjectToolPatterns(t *testing.T) {
+	writeMacroConfig(t, `
+[tools.claude]
+prompt_patterns_extra = ["config>"]
+`)
+	project := writeProjectPatterns(t, `
+[claude]
+prompt_patterns_extra = ["re:(?m)^wrapper> $"]
+
+[gemini]
+busy_patterns = ["crunching"]
+`)
+
+	claude := MergeProjectToolPatterns("claude", project)
+	if !slices.Equal(claude.BusyPatterns, tmux.DefaultRawPatterns("claude").BusyPatterns) {
+		t.Errorf("claude busy patterns changed: %v", claude.BusyPatterns)
+	}
+	if !slices.Contains(claude.PromptPatterns, "config>") || !slices.Contains(claude.PromptPatterns, "re:(?m)^wrapper> $") {
+		t.Errorf("claude prompt patterns = %v, want config and project extras", claude.PromptPatterns)
+	}
+	if gemini := MergeProjectToolPatterns("gemini", project); !slices.Equal(gemini.BusyPatterns, []string{"crunching"}) {
+		t.Errorf("gemini busy patterns = %v, want the project's replacement", gemini.BusyPatterns)
+	}
+
+	// Without a patterns file the config patterns are used as is
+	if got := MergeProjectToolPatterns("claude", t.TempDir()); !slices.Equal(got.PromptPatterns, MergeToolPatterns("claude").PromptPatterns) {
+		t.Errorf("patterns without a project file = %v", got.PromptPatterns)
+	}
+}
+
+func TestLoadProjectPatternsDropsInvalidTools(t *testing.T) {
+	project := writeProjectPatterns(t, `
+[claude]
+busy_patterns_extra = ["re:([unclosed"]
+
+[codex]
+prompt_patterns = ["codex>"]
+`)
+	patterns, err := LoadProjectPatterns(project)
+	if err == nil || !strings.Contains(err.Error(), "[claude]") || !strings.Contains(err.Error(), "busy_patterns_extra[0]") {
+		t.Errorf("err = %v, want the invalid claude pattern reported", err)
+	}
+	if patterns["claude"] != nil {
+		t.Error("invalid claude table was kept")
+	}
+	if patterns["codex"] == nil {
+		t.Error("valid codex table was dropped")
+	}
+
+	if patterns, err := LoadProjectPatterns(t.TempDir()); patterns != nil || err != nil {
+		t.Errorf("missing file = %v, %v", patterns, err)
+	}
+}
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Start reason kinds. A start reason is stored as "kind" or "kind:ref".
+const (
+	StartReasonManual     = "manual"     // created by a user from the CLI or TUI
+	StartReasonFork       = "fork"       // ref: parent session ID
+	StartReasonHeartbeat  = "heartbeat"  // ref: heartbeat task (conductor/task id)
+	StartReasonWebhook    = "webhook"    // ref: webhook or delivery id
+	StartReasonTemplate   = "template"   // ref: template name
+	StartReasonConductor  = "conductor"  // ref: conductor that created or owns the session
+	StartReasonDiscovered = "discovered" // adopted from an existing tmux session
+	StartReasonReview     = "review"     // ref: reviewed branch
+)
+
+// ValidStartReasonKinds lists the kinds accepted by ParseStartReason
+var ValidStartReasonKinds = []string{
+	StartReasonManual, StartReasonFork, StartReasonHeartbeat, StartReasonWebhook,
+	StartReasonTemplate, StartReasonConductor, StartReasonDiscovered, StartReasonReview,
+}
+
+// FormatStartReason builds a stored start reason from a kind and optional ref
+func FormatStartReason(kind, ref string) string {
+	if ref == "" {
+		return kind
+	}
+	return kind + ":" + ref
+}
+
+// ParseStartReason validates a "kind[:ref]" start reason (e.g. from --reason)
+func ParseStartReason(s string) (kind, ref string, err error) {
+	kind, ref, _ = strings.Cut(strings.TrimSpace(s), ":")
+	for _, valid := range ValidStartReasonKinds {
+		if kind == valid {
+			return kind, strings.TrimSpace(ref), nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid start reason %q: kind must be one of %s", s, strings.Join(ValidStartReasonKinds, ", "))
+}
+
+// SetStartReason records why the session was created
+func (inst *Instance) SetStartReason(kind, ref string) {
+	inst.StartReason = FormatStartReason(kind, ref)
+}
+
+// StartReasonKind returns the kind part of the start reason ("" if unknown)
+func (inst *Instance) StartReasonKind() string {
+	kind, _, _ := strings.Cut(inst.StartReason, ":")
+	return kind
+}
+
+// DescribeStartReason renders a start reason for humans, resolving fork
+// parents to titles via lookup (which may be nil). Empty reasons predate
+// provenance tracking and render as "unknown".
+func DescribeStartReason(reason string, lookup func(id string) string) string {
+	if reason == "" {
+		return "unknown"
+	}
+	kind, ref, _ := strings.Cut(reason, ":")
+	switch kind {
+	case StartReasonFork:
+		if lookup != nil {
+			if title := lookup(ref); title != "" {
+				return "fork of " + title
+			}
+		}
+		return "fork of " + ref
+	case StartReasonHeartbeat:
+		return "heartbeat task " + ref
+	case StartReasonConductor:
+		return "conductor " + ref
+	case StartReasonDiscovered:
+		return "adopted tmux session"
+	case StartReasonReview:
+		return "review of " + ref
+	}
+	if ref == "" {
+		return kind
+	}
+	return kind + " " + ref
+}
+
+// DefaultStartReason is the reason for a session created from the CLI: a
+// command running inside a conductor session is attributed to that conductor,
+// anything else is manual.
+func DefaultStartReason() (kind, ref string) {
+	if name, ok := ConductorNameFromTitle(os.Getenv("AGENTDECK_TITLE")); ok {
+		return StartReasonConductor, name
+	}
+	return StartReasonManual, ""
+}
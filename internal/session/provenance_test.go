@@ -0,0 +1,76 @@
+package session
+
+import "testing"
+
+func TestParseStartReason(t *testing.T) {
+	tests := []struct {
+		in      string
+		kind    string
+		ref     string
+		wantErr bool
+	}{
+		{in: "manual", kind: StartReasonManual},
+		{in: "webhook:gh-1234", kind: StartReasonWebhook, ref: "gh-1234"},
+		{in: " template: review ", kind: StartReasonTemplate, ref: "review"},
+		{in: "heartbeat:ops/42", kind: StartReasonHeartbeat, ref: "ops/42"},
+		{in: "", wantErr: true},
+		{in: "cron:nightly", wantErr: true},
+	}
+	for _, tt := range tests {
+		kind, ref, err := ParseStartReason(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStartReason(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if kind != tt.kind || ref != tt.ref {
+			t.Errorf("ParseStartReason(%q) = %q, %q; want %q, %q", tt.in, kind, ref, tt.kind, tt.ref)
+		}
+	}
+}
+
+func TestDescribeStartReason(t *testing.T) {
+	lookup := func(id string) string {
+		if id == "abc" {
+			return "api-server"
+		}
+		return ""
+	}
+	tests := map[string]string{
+		"":                "unknown",
+		"manual":          "manual",
+		"fork:abc":        "fork of api-server",
+		"fork:gone":       "fork of gone",
+		"heartbeat:ops/7": "heartbeat task ops/7",
+		"webhook:gh-1":    "webhook gh-1",
+		"template:review": "template review",
+		"conductor:ops":   "conductor ops",
+		"discovered":      "adopted tmux session",
+	}
+	for in, want := range tests {
+		if got := DescribeStartReason(in, lookup); got != want {
+			t.Errorf("DescribeStartReason(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDefaultStartReason(t *testing.T) {
+	t.Setenv("AGENTDECK_TITLE", "conductor-ops")
+	if kind, ref := DefaultStartReason(); kind != StartReasonConductor || ref != "ops" {
+		t.Errorf("inside conductor: got %q, %q", kind, ref)
+	}
+	t.Setenv("AGENTDECK_TITLE", "my-project")
+	if kind, ref := DefaultStartReason(); kind != StartReasonManual || ref != "" {
+		t.Errorf("outside conductor: got %q, %q", kind, ref)
+	}
+}
+
+func TestInstanceStartReason(t *testing.T) {
+	parent := NewInstance("parent", "/tmp")
+	if parent.StartReason != StartReasonManual {
+		t.Fatalf("new instance start reason = %q, want manual", parent.StartReason)
+	}
+	parent.SetStartReason(StartReasonFork, parent.ID)
+	if parent.StartReasonKind() != StartReasonFork {
+		t.Errorf("StartReasonKind() = %q, want fork", parent.StartReasonKind())
+	}
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ProxySettings configures the HTTP(S) proxy and CA bundle injected into
+// sessions, for agents running behind corporate (TLS-intercepting) proxies.
+// Set globally in [proxy] or per profile in [profiles.<name>.proxy]; profile
+// values override global ones field by field.
+type ProxySettings struct {
+	// HTTPProxy is exported as HTTP_PROXY/http_proxy
+	HTTPProxy string `toml:"http_proxy"`
+
+	// HTTPSProxy is exported as HTTPS_PROXY/https_proxy (default: http_proxy)
+	HTTPSProxy string `toml:"https_proxy"`
+
+	// NoProxy lists hosts, domains or CIDRs that bypass the proxy (NO_PROXY)
+	NoProxy []string `toml:"no_proxy"`
+
+	// CABundle is a PEM file with the proxy's root CA. It is exported as
+	// NODE_EXTRA_CA_CERTS (added to Node's roots) and as SSL_CERT_FILE,
+	// REQUESTS_CA_BUNDLE, CURL_CA_BUNDLE and GIT_SSL_CAINFO, which replace the
+	// system roots, so it should hold the system roots plus the corporate CA.
+	CABundle string `toml:"ca_bundle"`
+}
+
+// IsZero reports whether no proxy or CA setting is configured
+func (p ProxySettings) IsZero() bool {
+	return p.HTTPProxy == "" && p.HTTPSProxy == "" && len(p.NoProxy) == 0 && p.CABundle == ""
+}
+
+// GetProxySettings returns the proxy settings for a profile: the profile's
+// [profiles.<name>.proxy] values layered over the global [proxy] section.
+func (c *UserConfig) GetProxySettings(profile string) ProxySettings {
+	if c == nil {
+		return ProxySettings{}
+	}
+	settings := c.Proxy
+	if profile == "" || c.Profiles == nil {
+		return settings
+	}
+	override := c.Profiles[profile].Proxy
+	if override.HTTPProxy != "" {
+		settings.HTTPProxy = override.HTTPProxy
+	}
+	if override.HTTPSProxy != "" {
+		settings.HTTPSProxy = override.HTTPSProxy
+	}
+	if len(override.NoProxy) > 0 {
+		settings.NoProxy = override.NoProxy
+	}
+	if override.CABundle != "" {
+		settings.CABundle = override.CABundle
+	}
+	return settings
+}
+
+// GetProfileProxySettings returns the proxy settings for the effective profile
+func GetProfileProxySettings() ProxySettings {
+	config, _ := LoadUserConfig()
+	return config.GetProxySettings(GetEffectiveProfile(""))
+}
+
+// ProxyEnv returns the environment variables for the proxy settings. Both
+// upper- and lower-case proxy variables are set since tools disagree on which
+// they read.
+func (p ProxySettings) ProxyEnv() map[string]string {
+	env := make(map[string]string)
+	httpsProxy := p.HTTPSProxy
+	if httpsProxy == "" {
+		httpsProxy = p.HTTPProxy
+	}
+	if p.HTTPProxy != "" {
+		env["HTTP_PROXY"] = p.HTTPProxy
+		env["http_proxy"] = p.HTTPProxy
+	}
+	if httpsProxy != "" {
+		env["HTTPS_PROXY"] = httpsProxy
+		env["https_proxy"] = httpsProxy
+	}
+	if len(p.NoProxy) > 0 {
+		noProxy := strings.Join(p.NoProxy, ",")
+		env["NO_PROXY"] = noProxy
+		env["no_proxy"] = noProxy
+	}
+	return env
+}
+
+// CAEnv returns the environment variables pointing tools at the CA bundle
+func (p ProxySettings) CAEnv() map[string]string {
+	if p.CABundle == "" {
+		return nil
+	}
+	bundle := ExpandPath(p.CABundle)
+	return map[string]string{
+		"NODE_EXTRA_CA_CERTS": bundle,
+		"SSL_CERT_FILE":       bundle,
+		"REQUESTS_CA_BUNDLE":  bundle,
+		"CURL_CA_BUNDLE":      bundle,
+		"GIT_SSL_CAINFO":      bundle,
+	}
+}
+
+// Validate checks that the CA bundle, if set, is a readable file
+func (p ProxySettings) Validate() error {
+	if p.CABundle == "" {
+		return nil
+	}
+	info, err := os.Stat(ExpandPath(p.CABundle))
+	if err != nil {
+		return fmt.Errorf("proxy ca_bundle: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("proxy ca_bundle %s is a directory", p.CABundle)
+	}
+	return nil
+}
+
+// getProxyEnv returns shell export commands for the profile's proxy and CA
+// settings. Sessions with a network policy keep the policy's proxy variables,
+// so only the CA bundle is exported for them.
+func (i *Instance) getProxyEnv() string {
+	settings := GetProfileProxySettings()
+	if settings.IsZero() {
+		return ""
+	}
+	env := settings.CAEnv()
+	if i.NetworkPolicy == "" {
+		if env == nil {
+			env = make(map[string]string)
+		}
+		for k, v := range settings.ProxyEnv() {
+			env[k] = v
+		}
+	}
+	return exportEnv(env)
+}
+
+// ApplyProxyEnv sets the effective profile's proxy and CA variables on the
+// current process, so agent-deck's own requests and headless runs (web
+// server, batch API, tmux servers it spawns) use them. Variables already set
+// in the environment win.
+func ApplyProxyEnv() {
+	settings := GetProfileProxySettings()
+	for _, env := range []map[string]string{settings.ProxyEnv(), settings.CAEnv()} {
+		for k, v := range env {
+			if _, ok := os.LookupEnv(k); !ok {
+				_ = os.Setenv(k, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestGetProxySettings_ProfileOverridesGlobal(t *testing.T) {
+	var config UserConfig
+	_, err := toml.Decode(`
+[proxy]
+http_proxy = "http://global:3128"
+no_proxy = ["localhost", ".corp"]
+ca_bundle = "/etc/corp/ca.pem"
+
+[profiles.work.proxy]
+http_proxy = "http://work:8080"
+`, &config)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	work := config.GetProxySettings("work")
+	if work.HTTPProxy != "http://work:8080" {
+		t.Errorf("work http_proxy = %q, want profile override", work.HTTPProxy)
+	}
+	if work.CABundle != "/etc/corp/ca.pem" || len(work.NoProxy) != 2 {
+		t.Errorf("work should inherit ca_bundle and no_proxy from [proxy], got %+v", work)
+	}
+
+	other := config.GetProxySettings("personal")
+	if other.HTTPProxy != "http://global:3128" {
+		t.Errorf("unknown profile http_proxy = %q, want global", other.HTTPProxy)
+	}
+
+	env := work.ProxyEnv()
+	if env["HTTPS_PROXY"] != "http://work:8080" || env["https_proxy"] != "http://work:8080" {
+		t.Errorf("https proxy should default to http_proxy, got %v", env)
+	}
+	if env["NO_PROXY"] != "localhost,.corp" {
+		t.Errorf("NO_PROXY = %q", env["NO_PROXY"])
+	}
+	if ca := work.CAEnv(); ca["NODE_EXTRA_CA_CERTS"] != "/etc/corp/ca.pem" || ca["SSL_CERT_FILE"] != "/etc/corp/ca.pem" {
+		t.Errorf("CAEnv() = %v", ca)
+	}
+}
+
+func TestExportEnv(t *testing.T) {
+	got := exportEnv(map[string]string{"B": "it's", "A": "1"})
+	want := `export A='1' && export B='it'\''s'`
+	if got != want {
+		t.Errorf("exportEnv() = %q, want %q", got, want)
+	}
+	if (ProxySettings{}).Validate() != nil {
+		t.Error("empty settings should validate")
+	}
+	if (ProxySettings{CABundle: "/nonexistent/ca.pem"}).Validate() == nil {
+		t.Error("missing ca_bundle should fail validation")
+	}
+}
@@ -0,0 +1,431 @@
+package session
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RegistryScheme prefixes registry references: registry://name[@version]
+const RegistryScheme = "registry://"
+
+// Registry entry kinds.
+const (
+	RegistryKindTemplate  = "template"
+	RegistryKindSkillPack = "skill-pack"
+)
+
+const (
+	registryDirName    = "registry"
+	registryIndexName  = "index.json"
+	registryRepoDir    = "repo"
+	registryMaxArchive = 32 << 20
+)
+
+var (
+	ErrRegistryNotConfigured = errors.New("no registry configured (set [registry] url in config.toml)")
+	ErrRegistryEntryNotFound = errors.New("registry entry not found")
+	ErrRegistryChecksum      = errors.New("registry archive checksum mismatch")
+	ErrRegistrySignature     = errors.New("registry archive signature invalid")
+)
+
+// RegistryRef is a parsed registry://name@version reference. An empty Version
+// means the latest version.
+type RegistryRef struct {
+	Name    string
+	Version string
+}
+
+func (r RegistryRef) String() string {
+	if r.Version == "" {
+		return RegistryScheme + r.Name
+	}
+	return RegistryScheme + r.Name + "@" + r.Version
+}
+
+// ParseRegistryRef parses "registry://name[@version]". ok is false for
+// anything that is not a registry reference.
+func ParseRegistryRef(s string) (RegistryRef, bool) {
+	rest, found := strings.CutPrefix(s, RegistryScheme)
+	if !found {
+		return RegistryRef{}, false
+	}
+	name, version, _ := strings.Cut(rest, "@")
+	if !conductorNameRegex.MatchString(name) {
+		return RegistryRef{}, false
+	}
+	return RegistryRef{Name: name, Version: version}, true
+}
+
+// RegistryVersion is one published version of an entry.
+type RegistryVersion struct {
+	Version string `json:"version"`
+	// Archive is a .tar.gz path relative to the registry root (or an absolute URL)
+	Archive string `json:"archive"`
+	SHA256  string `json:"sha256"`
+	// Signature is a base64 ed25519 signature over the archive bytes
+	Signature string `json:"signature,omitempty"`
+}
+
+// RegistryEntry is a template or skill pack in the registry index.
+// Versions are listed oldest first; the last one is the latest.
+type RegistryEntry struct {
+	Name        string            `json:"name"`
+	Kind        string            `json:"kind"`
+	Description string            `json:"description,omitempty"`
+	Versions    []RegistryVersion `json:"versions"`
+}
+
+// RegistryIndex is the registry's index.json.
+type RegistryIndex struct {
+	Entries []RegistryEntry `json:"entries"`
+}
+
+// Find returns the entry and version for ref.
+func (idx *RegistryIndex) Find(ref RegistryRef) (*RegistryEntry, *RegistryVersion, error) {
+	for i := range idx.Entries {
+		entry := &idx.Entries[i]
+		if entry.Name != ref.Name || len(entry.Versions) == 0 {
+			continue
+		}
+		if ref.Version == "" {
+			return entry, &entry.Versions[len(entry.Versions)-1], nil
+		}
+		for j := range entry.Versions {
+			if entry.Versions[j].Version == ref.Version {
+				return entry, &entry.Versions[j], nil
+			}
+		}
+		return nil, nil, fmt.Errorf("%w: %s has no version %q", ErrRegistryEntryNotFound, ref.Name, ref.Version)
+	}
+	return nil, nil, fmt.Errorf("%w: %s", ErrRegistryEntryNotFound, ref.Name)
+}
+
+// RegistryClient fetches and verifies entries from a git or HTTP registry.
+// Verified archives are extracted under CacheDir/<name>/<version>/.
+type RegistryClient struct {
+	URL       string
+	PublicKey ed25519.PublicKey
+	CacheDir  string
+	HTTP      *http.Client
+}
+
+// NewRegistryClient builds a client from [registry] in config.
+func NewRegistryClient() (*RegistryClient, error) {
+	settings := GetRegistrySettings()
+	if settings.URL == "" {
+		return nil, ErrRegistryNotConfigured
+	}
+	base, err := GetAgentDeckDir()
+	if err != nil {
+		return nil, err
+	}
+	client := &RegistryClient{
+		URL:      settings.URL,
+		CacheDir: filepath.Join(base, registryDirName),
+		HTTP:     &http.Client{Timeout: 60 * time.Second},
+	}
+	if settings.PublicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(settings.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid [registry] public_key: must be a base64 ed25519 public key")
+		}
+		client.PublicKey = key
+	}
+	return client, nil
+}
+
+// isGit reports whether the registry is a git repository rather than an HTTP base.
+func (c *RegistryClient) isGit() bool {
+	return strings.HasPrefix(c.URL, "git+") || strings.HasPrefix(c.URL, "git@") ||
+		strings.HasSuffix(c.URL, ".git")
+}
+
+// read returns a file relative to the registry root.
+func (c *RegistryClient) read(rel string) ([]byte, error) {
+	if c.isGit() {
+		repo := filepath.Join(c.CacheDir, registryRepoDir)
+		clean := filepath.Clean(filepath.FromSlash(rel))
+		if filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+			return nil, fmt.Errorf("registry path %q escapes the repository", rel)
+		}
+		return os.ReadFile(filepath.Join(repo, clean))
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(c.URL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry url: %w", err)
+	}
+	target, err := base.Parse(rel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry path %q: %w", rel, err)
+	}
+	resp, err := c.HTTP.Get(target.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", target, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, registryMaxArchive+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", target, err)
+	}
+	if len(data) > registryMaxArchive {
+		return nil, fmt.Errorf("%s exceeds %d bytes", target, registryMaxArchive)
+	}
+	return data, nil
+}
+
+// UpdateIndex refreshes the cached index: git registries are cloned or pulled,
+// HTTP registries re-download index.json.
+func (c *RegistryClient) UpdateIndex() (*RegistryIndex, error) {
+	if err := os.MkdirAll(c.CacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create registry cache: %w", err)
+	}
+	if c.isGit() {
+		repo := filepath.Join(c.CacheDir, registryRepoDir)
+		remote := strings.TrimPrefix(c.URL, "git+")
+		var cmd *exec.Cmd
+		if _, err := os.Stat(filepath.Join(repo, ".git")); err == nil {
+			cmd = exec.Command("git", "-C", repo, "pull", "--ff-only", "--quiet")
+		} else {
+			cmd = exec.Command("git", "clone", "--depth", "1", "--quiet", remote, repo)
+		}
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to sync registry repository: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	data, err := c.read(registryIndexName)
+	if err != nil {
+		return nil, err
+	}
+	var idx RegistryIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse registry index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(c.CacheDir, registryIndexName), data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to cache registry index: %w", err)
+	}
+	return &idx, nil
+}
+
+// Index returns the cached index, fetching it on first use.
+func (c *RegistryClient) Index() (*RegistryIndex, error) {
+	data, err := os.ReadFile(filepath.Join(c.CacheDir, registryIndexName))
+	if err != nil {
+		return c.UpdateIndex()
+	}
+	var idx RegistryIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return c.UpdateIndex()
+	}
+	return &idx, nil
+}
+
+// verifyArchive checks data against the index checksum and, when a public key
+// is configured, the signature.
+func (c *RegistryClient) verifyArchive(v *RegistryVersion, data []byte) error {
+	sum := sha256.Sum256(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), v.SHA256) {
+		return fmt.Errorf("%w: %s", ErrRegistryChecksum, v.Archive)
+	}
+	if c.PublicKey == nil {
+		return nil
+	}
+	sig, err := base64.StdEncoding.DecodeString(v.Signature)
+	if err != nil || !ed25519.Verify(c.PublicKey, data, sig) {
+		return fmt.Errorf("%w: %s", ErrRegistrySignature, v.Archive)
+	}
+	return nil
+}
+
+// entryDir is where a verified version is extracted.
+func (c *RegistryClient) entryDir(name, version string) string {
+	return filepath.Join(c.CacheDir, name, version)
+}
+
+// Fetch resolves ref, verifies the archive and returns the extracted
+// directory. Already-extracted versions are reused.
+func (c *RegistryClient) Fetch(ref RegistryRef) (string, *RegistryEntry, error) {
+	idx, err := c.Index()
+	if err != nil {
+		return "", nil, err
+	}
+	entry, version, err := idx.Find(ref)
+	if errors.Is(err, ErrRegistryEntryNotFound) {
+		// The cached index may predate the entry
+		if idx, err = c.UpdateIndex(); err != nil {
+			return "", nil, err
+		}
+		entry, version, err = idx.Find(ref)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir := c.entryDir(entry.Name, version.Version)
+	if _, err := os.Stat(dir); err == nil {
+		return dir, entry, nil
+	}
+
+	data, err := c.read(version.Archive)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := c.verifyArchive(version, data); err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", nil, err
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(dir), "."+version.Version+"-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(staging)
+	if err := extractRegistryArchive(data, staging); err != nil {
+		return "", nil, fmt.Errorf("failed to extract %s: %w", version.Archive, err)
+	}
+	if err := os.WriteFile(filepath.Join(staging, ".archive.tar.gz"), data, 0o644); err != nil {
+		return "", nil, err
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		return "", nil, fmt.Errorf("failed to store %s: %w", ref, err)
+	}
+	return dir, entry, nil
+}
+
+// Verify re-checks a fetched version: the stored archive must still match the
+// index checksum/signature and the extracted files must match the archive.
+func (c *RegistryClient) Verify(ref RegistryRef) error {
+	idx, err := c.Index()
+	if err != nil {
+		return err
+	}
+	entry, version, err := idx.Find(ref)
+	if err != nil {
+		return err
+	}
+	dir := c.entryDir(entry.Name, version.Version)
+	data, err := os.ReadFile(filepath.Join(dir, ".archive.tar.gz"))
+	if err != nil {
+		return fmt.Errorf("%s is not fetched: %w", ref, err)
+	}
+	if err := c.verifyArchive(version, data); err != nil {
+		return err
+	}
+	return compareRegistryArchive(data, dir)
+}
+
+// walkRegistryArchive calls fn for every regular file in a .tar.gz with a
+// cleaned, relative path. Links, devices and paths escaping the root are errors.
+func walkRegistryArchive(data []byte, fn func(rel string, mode os.FileMode, content []byte) error) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rel := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if rel == "." {
+			continue
+		}
+		if path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+			return fmt.Errorf("archive entry %q escapes the destination", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+			content, err := io.ReadAll(io.LimitReader(tr, registryMaxArchive))
+			if err != nil {
+				return err
+			}
+			if err := fn(rel, os.FileMode(hdr.Mode).Perm(), content); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("archive entry %q has unsupported type %c", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+func extractRegistryArchive(data []byte, dest string) error {
+	return walkRegistryArchive(data, func(rel string, mode os.FileMode, content []byte) error {
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, mode|0o600)
+	})
+}
+
+func compareRegistryArchive(data []byte, dir string) error {
+	return walkRegistryArchive(data, func(rel string, _ os.FileMode, content []byte) error {
+		onDisk, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if !bytes.Equal(onDisk, content) {
+			return fmt.Errorf("%s was modified after fetch", rel)
+		}
+		return nil
+	})
+}
+
+// FetchRegistryRef fetches ref with the configured registry and checks its kind.
+func FetchRegistryRef(ref RegistryRef, kind string) (string, error) {
+	client, err := NewRegistryClient()
+	if err != nil {
+		return "", err
+	}
+	dir, entry, err := client.Fetch(ref)
+	if err != nil {
+		return "", err
+	}
+	if entry.Kind != kind {
+		return "", fmt.Errorf("%s is a %s, not a %s", ref, entry.Kind, kind)
+	}
+	return dir, nil
+}
+
+// resolveRegistryTemplateFile returns the path of file (CLAUDE.md or
+// POLICY.md) inside a fetched template.
+func resolveRegistryTemplateFile(ref RegistryRef, file string) (string, error) {
+	dir, err := FetchRegistryRef(ref, RegistryKindTemplate)
+	if err != nil {
+		return "", err
+	}
+	p := filepath.Join(dir, file)
+	if _, err := os.Stat(p); err != nil {
+		return "", fmt.Errorf("template %s has no %s", ref, file)
+	}
+	return p, nil
+}
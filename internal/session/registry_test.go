@@ -0,0 +1,124 @@
+package session
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func buildTestArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+func newTestRegistry(t *testing.T, archive []byte, sign ed25519.PrivateKey) *httptest.Server {
+	t.Helper()
+	sum := sha256.Sum256(archive)
+	version := RegistryVersion{Version: "v2", Archive: "templates/sre-oncall-v2.tar.gz", SHA256: hex.EncodeToString(sum[:])}
+	if sign != nil {
+		version.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(sign, archive))
+	}
+	index, _ := json.Marshal(RegistryIndex{Entries: []RegistryEntry{{
+		Name: "sre-oncall", Kind: RegistryKindTemplate,
+		Versions: []RegistryVersion{{Version: "v1", Archive: "missing.tar.gz", SHA256: "00"}, version},
+	}}})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reg/index.json", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(index) })
+	mux.HandleFunc("/reg/templates/sre-oncall-v2.tar.gz", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(archive) })
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestParseRegistryRef(t *testing.T) {
+	tests := []struct {
+		in   string
+		want RegistryRef
+		ok   bool
+	}{
+		{"registry://sre-oncall@v2", RegistryRef{Name: "sre-oncall", Version: "v2"}, true},
+		{"registry://sre-oncall", RegistryRef{Name: "sre-oncall"}, true},
+		{"registry://../etc@v1", RegistryRef{}, false},
+		{"~/docs/claude.md", RegistryRef{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRegistryRef(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseRegistryRef(%q) = %+v, %v", tt.in, got, ok)
+		}
+	}
+}
+
+func TestRegistryFetchVerifiesSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	archive := buildTestArchive(t, map[string]string{"CLAUDE.md": "# SRE on-call\n", "POLICY.md": "Escalate sev1.\n"})
+	srv := newTestRegistry(t, archive, priv)
+
+	client := &RegistryClient{URL: srv.URL + "/reg", PublicKey: pub, CacheDir: t.TempDir(), HTTP: srv.Client()}
+	dir, entry, err := client.Fetch(RegistryRef{Name: "sre-oncall"})
+	if err != nil {
+		t.Fatalf("Fetch latest: %v", err)
+	}
+	if entry.Kind != RegistryKindTemplate || filepath.Base(dir) != "v2" {
+		t.Errorf("latest should resolve to v2, got %s (%s)", dir, entry.Kind)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "CLAUDE.md")); string(data) != "# SRE on-call\n" {
+		t.Errorf("CLAUDE.md = %q", data)
+	}
+	if err := client.Verify(RegistryRef{Name: "sre-oncall", Version: "v2"}); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	// Local tampering is caught by verify
+	_ = os.WriteFile(filepath.Join(dir, "POLICY.md"), []byte("Never escalate.\n"), 0o644)
+	if err := client.Verify(RegistryRef{Name: "sre-oncall", Version: "v2"}); err == nil {
+		t.Error("Verify should detect a modified file")
+	}
+
+	// A different key rejects the archive
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	strict := &RegistryClient{URL: srv.URL + "/reg", PublicKey: otherPub, CacheDir: t.TempDir(), HTTP: srv.Client()}
+	if _, _, err := strict.Fetch(RegistryRef{Name: "sre-oncall", Version: "v2"}); !errors.Is(err, ErrRegistrySignature) {
+		t.Errorf("expected ErrRegistrySignature, got %v", err)
+	}
+
+	if _, _, err := client.Fetch(RegistryRef{Name: "sre-oncall", Version: "v9"}); !errors.Is(err, ErrRegistryEntryNotFound) {
+		t.Errorf("expected ErrRegistryEntryNotFound, got %v", err)
+	}
+}
+
+func TestRegistryRejectsEscapingArchive(t *testing.T) {
+	archive := buildTestArchive(t, map[string]string{"../evil.md": "x"})
+	srv := newTestRegistry(t, archive, nil)
+	client := &RegistryClient{URL: srv.URL + "/reg", CacheDir: t.TempDir(), HTTP: srv.Client()}
+	if _, _, err := client.Fetch(RegistryRef{Name: "sre-oncall", Version: "v2"}); err == nil {
+		t.Fatal("archive escaping the destination should be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(client.CacheDir, "sre-oncall", "v2")); !os.IsNotExist(err) {
+		t.Error("rejected archive must not be cached")
+	}
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/remote"
+)
+
+// RemoteSettings defines a remote host whose sessions agent-deck manages
+// over ssh ([remotes.<name>]):
+//
+//	[remotes.box]
+//	host = "me@build-box"
+//	deck = "work"
+//
+// The host needs agent-deck and tmux installed.
+type RemoteSettings struct {
+	// Host is the ssh destination: a hostname, user@host or ~/.ssh/config alias
+	Host string `toml:"host"`
+
+	// Port is the ssh port. Default: ssh's (22 or ~/.ssh/config)
+	Port int `toml:"port"`
+
+	// IdentityFile is the private key to use. Default: ssh's
+	IdentityFile string `toml:"identity_file"`
+
+	// AgentDeck is the agent-deck binary on the host, for when it is not on
+	// the PATH of non-interactive ssh commands. Default: "agent-deck"
+	AgentDeck string `toml:"agent_deck"`
+
+	// Deck and Profile select the remote deck and profile. Default: the
+	// host's defaults
+	Deck    string `toml:"deck"`
+	Profile string `toml:"profile"`
+
+	// TmuxSocket is the host's tmux server for attaching: a socket name
+	// (tmux -L) or a path (tmux -S). Default: the remote deck's socket
+	TmuxSocket string `toml:"tmux_socket"`
+
+	// ControlPersist is how long, in seconds, an idle multiplexed connection
+	// stays open; -1 disables multiplexing. Default: 600
+	ControlPersist int `toml:"control_persist"`
+}
+
+// RemoteNames returns the configured remote hosts, sorted
+func RemoteNames() []string {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return nil
+	}
+	names := make([]string, 0, len(config.Remotes))
+	for name := range config.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetRemote returns the remote host configured as [remotes.<name>]
+func GetRemote(name string) (*remote.Host, error) {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	settings, ok := config.Remotes[name]
+	if !ok {
+		names := RemoteNames()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("remote %q is not configured: add [remotes.%s] with a host to config.toml", name, name)
+		}
+		return nil, fmt.Errorf("remote %q is not configured (configured: %s)", name, strings.Join(names, ", "))
+	}
+	if strings.TrimSpace(settings.Host) == "" {
+		return nil, fmt.Errorf("remote %q has no host", name)
+	}
+	host := &remote.Host{
+		Name:         name,
+		Dest:         settings.Host,
+		Port:         settings.Port,
+		IdentityFile: ExpandPath(settings.IdentityFile),
+		AgentDeck:    settings.AgentDeck,
+		Deck:         settings.Deck,
+		Profile:      settings.Profile,
+		TmuxSocket:   settings.TmuxSocket,
+	}
+	if settings.ControlPersist >= 0 {
+		host.ControlPersist = time.Duration(settings.ControlPersist) * time.Second
+		dir, err := GetAgentDeckDir()
+		if err != nil {
+			return nil, err
+		}
+		host.ControlDir = filepath.Join(dir, "ssh")
+	}
+	return host, nil
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetRemote(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	config := `
+[remotes.box]
+host = "me@build-box"
+port = 2222
+identity_file = "~/.ssh/box"
+deck = "work"
+
+[remotes.direct]
+host = "other"
+control_persist = -1
+`
+	dir := filepath.Join(home, ".agent-deck")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := RemoteNames(); strings.Join(names, ",") != "box,direct" {
+		t.Errorf("RemoteNames = %v", names)
+	}
+	box, err := GetRemote("box")
+	if err != nil {
+		t.Fatalf("GetRemote(box): %v", err)
+	}
+	if box.Dest != "me@build-box" || box.Port != 2222 || box.Deck != "work" ||
+		box.IdentityFile != filepath.Join(home, ".ssh/box") || box.ControlDir != filepath.Join(dir, "ssh") {
+		t.Errorf("box = %+v", box)
+	}
+	direct, err := GetRemote("direct")
+	if err != nil {
+		t.Fatalf("GetRemote(direct): %v", err)
+	}
+	if direct.ControlDir != "" || direct.ControlPersist != 0 {
+		t.Errorf("direct = %+v, want multiplexing disabled", direct)
+	}
+	if _, err := GetRemote("nope"); err == nil || !strings.Contains(err.Error(), "box, direct") {
+		t.Errorf("GetRemote(nope) err = %v, want the configured hosts listed", err)
+	}
+}
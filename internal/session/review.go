@@ -0,0 +1,189 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Review verdicts a review session ends with
+const (
+	ReviewApprove        = "approve"
+	ReviewRequestChanges = "request-changes"
+	ReviewComment        = "comment"
+)
+
+// ReviewSettings configures "agent-deck review" sessions ([review])
+type ReviewSettings struct {
+	// Tool runs the review. Default: claude
+	Tool string `toml:"tool"`
+
+	// BaseBranch is what changes are diffed against. Default: the repo's
+	// default branch
+	BaseBranch string `toml:"base_branch"`
+
+	// ChunkSize is the largest diff message sent to the reviewer, in bytes.
+	// Larger diffs are split at file and hunk boundaries. Default: 12000
+	ChunkSize int `toml:"chunk_size"`
+
+	// Rubric is a file whose contents replace the built-in review rubric.
+	// "~/" and $VARS are expanded.
+	Rubric string `toml:"rubric"`
+}
+
+// GetReviewSettings returns [review] from config.toml
+func GetReviewSettings() ReviewSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return ReviewSettings{}
+	}
+	return config.Review
+}
+
+// GetTool returns the review tool, defaulting to claude
+func (r ReviewSettings) GetTool() string {
+	if r.Tool == "" {
+		return "claude"
+	}
+	return r.Tool
+}
+
+// GetChunkSize returns the diff chunk size, defaulting to 12000 bytes
+func (r ReviewSettings) GetChunkSize() int {
+	if r.ChunkSize <= 0 {
+		return 12000
+	}
+	return r.ChunkSize
+}
+
+// LoadRubric returns the configured rubric, or the built-in one
+func (r ReviewSettings) LoadRubric() (string, error) {
+	if r.Rubric == "" {
+		return DefaultReviewRubric, nil
+	}
+	data, err := os.ReadFile(ExpandPath(r.Rubric))
+	if err != nil {
+		return "", fmt.Errorf("read review rubric: %w", err)
+	}
+	return string(data), nil
+}
+
+// DefaultReviewRubric is the review prompt sent after the diff
+const DefaultReviewRubric = `Review the change as a senior engineer would before merging. Cover:
+
+1. Correctness: bugs, unhandled errors, edge cases, races.
+2. Tests: are the changed paths tested, and do the tests assert the right thing?
+3. Design: does it fit the surrounding code, or add needless complexity?
+4. Security: injection, secrets, unsafe file or network access.
+5. Readability: naming, comments, dead code.
+
+Read the changed files in the working directory for context where the diff is not enough.
+Do not modify any files. List findings by severity (blocker, major, minor, nit) with file:line.`
+
+// ChunkDiff splits a unified diff into messages of at most maxBytes, keeping
+// each file together where it fits and otherwise splitting between hunks.
+// A single hunk larger than maxBytes is split between lines.
+func ChunkDiff(diff string, maxBytes int) []string {
+	if strings.TrimSpace(diff) == "" {
+		return nil
+	}
+	var chunks []string
+	var current strings.Builder
+	add := func(piece string) {
+		if current.Len() > 0 && current.Len()+len(piece) > maxBytes {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(piece)
+	}
+	for _, file := range splitBefore(diff, "diff --git ") {
+		if len(file) <= maxBytes {
+			add(file)
+			continue
+		}
+		for _, hunk := range splitBefore(file, "@@ ") {
+			if len(hunk) <= maxBytes {
+				add(hunk)
+				continue
+			}
+			for _, line := range strings.SplitAfter(hunk, "\n") {
+				add(line)
+			}
+		}
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// splitBefore splits text into pieces that each start at a line beginning
+// with prefix (the first piece may not)
+func splitBefore(text, prefix string) []string {
+	var pieces []string
+	start := 0
+	for i := 0; i < len(text); {
+		next := strings.Index(text[i:], "\n"+prefix)
+		if next < 0 {
+			break
+		}
+		cut := i + next + 1
+		if cut > start {
+			pieces = append(pieces, text[start:cut])
+		}
+		start = cut
+		i = cut
+	}
+	return append(pieces, text[start:])
+}
+
+// ReviewMessages builds the messages that preload a review session: one per
+// diff chunk, then the rubric with the tagged verdict format the review must
+// end with
+func ReviewMessages(ref, diff, rubric string, chunkSize int) []string {
+	chunks := ChunkDiff(diff, chunkSize)
+	messages := make([]string, 0, len(chunks)+1)
+	for n, chunk := range chunks {
+		messages = append(messages, fmt.Sprintf(
+			"Diff for review of %s, part %d of %d. Reply only \"ok\" until you receive the review instructions.\n\n```diff\n%s```",
+			ref, n+1, len(chunks), chunk))
+	}
+	tag := fmt.Sprintf("%s%s", reviewTagPrefix, ref)
+	messages = append(messages, fmt.Sprintf(
+		"%s\n\nStart your review with this line exactly, choosing one verdict:\n%s verdict: %s | %s | %s\nthen a one-line summary, then your findings.",
+		strings.TrimSpace(rubric), tag, ReviewApprove, ReviewRequestChanges, ReviewComment))
+	return messages
+}
+
+// reviewTagPrefix tags review output so digests can pick it out
+const reviewTagPrefix = "[review] "
+
+var reviewTagPattern = regexp.MustCompile(`(?m)^\[review\] (\S+) verdict: (approve|request-changes|comment)\s*$`)
+
+// ReviewVerdict is the tagged outcome of a review session
+type ReviewVerdict struct {
+	Ref     string `json:"ref"`
+	Verdict string `json:"verdict"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// ParseReviewVerdict finds the tagged verdict line in a review session's
+// output, with the summary line that follows it
+func ParseReviewVerdict(output string) (ReviewVerdict, bool) {
+	loc := reviewTagPattern.FindStringSubmatchIndex(output)
+	if loc == nil {
+		return ReviewVerdict{}, false
+	}
+	verdict := ReviewVerdict{
+		Ref:     output[loc[2]:loc[3]],
+		Verdict: output[loc[4]:loc[5]],
+	}
+	for _, line := range strings.Split(output[loc[1]:], "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			verdict.Summary = line
+			break
+		}
+	}
+	return verdict, true
+}
@@ -0,0 +1,111 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testDiff(files, hunkLines int) string {
+	var b strings.Builder
+	for f := 0; f < files; f++ {
+		fmt.Fprintf(&b, "diff --git a/f%d.go b/f%d.go\n--- a/f%d.go\n+++ b/f%d.go\n", f, f, f, f)
+		for h := 0; h < 2; h++ {
+			fmt.Fprintf(&b, "@@ -%d,3 +%d,3 @@\n", h*10, h*10)
+			for l := 0; l < hunkLines; l++ {
+				fmt.Fprintf(&b, "+line %d\n", l)
+			}
+		}
+	}
+	return b.String()
+}
+
+func TestChunkDiff_KeepsEverythingWithinLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		diff      string
+		maxBytes  int
+		minChunks int
+	}{
+		{"fits in one", testDiff(2, 2), 10000, 1},
+		{"split between files", testDiff(4, 5), 200, 4},
+		{"split between hunks", testDiff(1, 10), 150, 2},
+		{"split between lines", testDiff(1, 40), 100, 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			chunks := ChunkDiff(tc.diff, tc.maxBytes)
+			if len(chunks) < tc.minChunks {
+				t.Errorf("got %d chunks, want at least %d", len(chunks), tc.minChunks)
+			}
+			if joined := strings.Join(chunks, ""); joined != tc.diff {
+				t.Error("chunks do not reassemble into the diff")
+			}
+			for n, chunk := range chunks {
+				if len(chunk) > tc.maxBytes {
+					t.Errorf("chunk %d is %d bytes, over %d", n, len(chunk), tc.maxBytes)
+				}
+			}
+		})
+	}
+	if chunks := ChunkDiff("  \n", 100); chunks != nil {
+		t.Errorf("empty diff gave %d chunks", len(chunks))
+	}
+}
+
+func TestChunkDiff_SplitsAtFileBoundaries(t *testing.T) {
+	diff := testDiff(3, 2)
+	file := len(diff) / 3
+	for _, chunk := range ChunkDiff(diff, file+10) {
+		if !strings.HasPrefix(chunk, "diff --git ") {
+			t.Errorf("chunk does not start at a file: %q", chunk[:20])
+		}
+	}
+}
+
+func TestReviewMessages_AndVerdict(t *testing.T) {
+	messages := ReviewMessages("feature/login", testDiff(4, 5), "Check the tests.", 200)
+	if len(messages) < 3 {
+		t.Fatalf("got %d messages, want diff parts and the rubric", len(messages))
+	}
+	if !strings.Contains(messages[0], "part 1 of ") || !strings.Contains(messages[0], "```diff") {
+		t.Errorf("first message should be the first diff part: %s", messages[0])
+	}
+	last := messages[len(messages)-1]
+	if !strings.HasPrefix(last, "Check the tests.") || !strings.Contains(last, "[review] feature/login verdict:") {
+		t.Errorf("last message should be the rubric with the verdict tag: %s", last)
+	}
+
+	output := "Some preamble\n[review] feature/login verdict: request-changes\n\nMissing a test for logout.\n\n- blocker: ..."
+	verdict, ok := ParseReviewVerdict(output)
+	if !ok {
+		t.Fatal("verdict not found")
+	}
+	want := ReviewVerdict{Ref: "feature/login", Verdict: ReviewRequestChanges, Summary: "Missing a test for logout."}
+	if verdict != want {
+		t.Errorf("verdict = %+v, want %+v", verdict, want)
+	}
+	if _, ok := ParseReviewVerdict("[review] x verdict: maybe"); ok {
+		t.Error("unknown verdict should not parse")
+	}
+}
+
+func TestReviewSettings_Rubric(t *testing.T) {
+	rubric := filepath.Join(t.TempDir(), "rubric.md")
+	if err := os.WriteFile(rubric, []byte("Our rubric"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeMacroConfig(t, fmt.Sprintf("[review]\nrubric = %q\nchunk_size = 500\n", rubric))
+
+	settings := GetReviewSettings()
+	if got, err := settings.LoadRubric(); err != nil || got != "Our rubric" {
+		t.Errorf("LoadRubric() = %q, %v", got, err)
+	}
+	if settings.GetChunkSize() != 500 || settings.GetTool() != "claude" {
+		t.Errorf("settings = %+v", settings)
+	}
+	if got, _ := (ReviewSettings{}).LoadRubric(); got != DefaultReviewRubric {
+		t.Error("default rubric not used")
+	}
+}
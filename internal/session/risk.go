@@ -0,0 +1,435 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// RiskLevel rates how dangerous a session's recent tool calls are
+type RiskLevel string
+
+// Risk levels, lowest to highest
+const (
+	RiskNone     RiskLevel = ""
+	RiskLow      RiskLevel = "low"
+	RiskMedium   RiskLevel = "medium"
+	RiskHigh     RiskLevel = "high"
+	RiskCritical RiskLevel = "critical"
+)
+
+// riskWeights are what one match of each level adds to a session's score
+var riskWeights = map[RiskLevel]int{RiskLow: 5, RiskMedium: 15, RiskHigh: 40, RiskCritical: 100}
+
+// Rank orders levels: 0 for none up to 4 for critical
+func (l RiskLevel) Rank() int {
+	switch l {
+	case RiskLow:
+		return 1
+	case RiskMedium:
+		return 2
+	case RiskHigh:
+		return 3
+	case RiskCritical:
+		return 4
+	}
+	return 0
+}
+
+// ParseRiskLevel parses a level name; "off" disables a rule
+func ParseRiskLevel(s string) (RiskLevel, error) {
+	switch l := RiskLevel(strings.ToLower(strings.TrimSpace(s))); l {
+	case RiskLow, RiskMedium, RiskHigh, RiskCritical:
+		return l, nil
+	case "off":
+		return RiskNone, nil
+	}
+	return RiskNone, fmt.Errorf("invalid risk level %q (want low, medium, high, critical or off)", s)
+}
+
+// riskCheckInterval is how often a busy session's recent tool calls are
+// scored by UpdateStatus
+const riskCheckInterval = 15 * time.Second
+
+// riskPaneLines is how much of the pane is scored for tools without a
+// transcript agent-deck can read
+const riskPaneLines = 40
+
+// riskTranscriptTail is how much of the end of a Claude transcript is read
+// to find its recent tool calls
+const riskTranscriptTail int64 = 256 * 1024
+
+// defaultRiskRules are scored unless [risk] rules override them by name
+var defaultRiskRules = []RiskRuleDef{
+	{Name: "rm-rf-root", Pattern: `\brm\s+-[a-zA-Z]*(rf|fr)[a-zA-Z]*\s+(/|~|\$HOME)(\*|/\*)?(\s|$)`, Level: "critical"},
+	{Name: "rm-rf", Pattern: `\brm\s+(-[a-zA-Z]*(rf|fr)|-[a-zA-Z]*r[a-zA-Z]*\s+-[a-zA-Z]*f|--recursive\s+--force|--force\s+--recursive)`, Level: "high"},
+	{Name: "force-push", Pattern: `\bgit\s+push\b.*\s(--force(-with-lease)?|-f)\b`, Level: "high"},
+	{Name: "prod-kubeconfig", Pattern: `(?i)(KUBECONFIG=\S*prod|\b(kubectl|helm|kubectx|kubens)\b.*\b(prod|production)\b)`, Level: "critical"},
+	{Name: "drop-database", Pattern: `(?i)\b(drop\s+(database|schema|table)|truncate\s+table)\b`, Level: "high"},
+	{Name: "terraform-destroy", Pattern: `\bterraform\s+(destroy|apply\b.*-auto-approve)`, Level: "high"},
+	{Name: "git-reset-hard", Pattern: `\bgit\s+(reset\s+--hard|clean\s+-[a-zA-Z]*f)`, Level: "medium"},
+	{Name: "pipe-to-shell", Pattern: `\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z)?sh\b`, Level: "medium"},
+	{Name: "chmod-777", Pattern: `\bchmod\s+(-R\s+)?777\b`, Level: "medium"},
+	{Name: "sudo", Pattern: `(^|[;&|]\s*)sudo\s`, Level: "low"},
+}
+
+// RiskRule is a compiled risk rule
+type RiskRule struct {
+	Name  string
+	Level RiskLevel
+	re    *regexp.Regexp
+}
+
+// RiskRules returns the built-in rules merged with the [risk] rules of
+// config.toml, which replace built-ins of the same name (level "off" drops
+// one). Invalid rules are logged and skipped.
+func RiskRules() []RiskRule {
+	defs := append([]RiskRuleDef(nil), defaultRiskRules...)
+	for _, def := range GetRiskSettings().Rules {
+		replaced := false
+		for n := range defs {
+			if defs[n].Name == def.Name && def.Name != "" {
+				defs[n], replaced = def, true
+				break
+			}
+		}
+		if !replaced {
+			defs = append(defs, def)
+		}
+	}
+
+	rules := make([]RiskRule, 0, len(defs))
+	for _, def := range defs {
+		rule, err := compileRiskRule(def)
+		if err != nil {
+			sessionLog.Warn("risk_rule_invalid", slog.String("rule", def.Name), slog.String("error", err.Error()))
+			continue
+		}
+		if rule.Level != RiskNone {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func compileRiskRule(def RiskRuleDef) (RiskRule, error) {
+	level, err := ParseRiskLevel(def.Level)
+	if err != nil {
+		return RiskRule{}, err
+	}
+	if level != RiskNone && def.Pattern == "" {
+		return RiskRule{}, fmt.Errorf("pattern is required")
+	}
+	re, err := regexp.Compile(def.Pattern)
+	if err != nil {
+		return RiskRule{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return RiskRule{Name: def.Name, Level: level, re: re}, nil
+}
+
+// RiskMatch is a recent tool call that matched a rule
+type RiskMatch struct {
+	Rule  string    `json:"rule"`
+	Level RiskLevel `json:"level"`
+	Call  string    `json:"call"`
+	// Source is where the call was seen: "transcript" or "pane"
+	Source string `json:"source"`
+}
+
+// SessionRisk is a session's risk indicator: the highest level matched by
+// its recent tool calls and a 0-100 score summing every match
+type SessionRisk struct {
+	Level     RiskLevel   `json:"level"`
+	Score     int         `json:"score"`
+	Matches   []RiskMatch `json:"matches"`
+	CheckedAt time.Time   `json:"checked_at"`
+}
+
+// Describe summarizes the risk for banners and previews, "" when none
+func (r *SessionRisk) Describe() string {
+	if r == nil || r.Level == RiskNone {
+		return ""
+	}
+	top := r.Matches[0]
+	return fmt.Sprintf("%s risk (%d): %s: %s", r.Level, r.Score, top.Rule, top.Call)
+}
+
+// ScoreRisk matches tool calls against rules. Each call counts once, for
+// the highest-level rule it matches; matches are ordered highest first.
+func ScoreRisk(calls []string, source string, rules []RiskRule) *SessionRisk {
+	risk := &SessionRisk{Matches: []RiskMatch{}, CheckedAt: time.Now()}
+	for _, call := range calls {
+		var best *RiskRule
+		for n := range rules {
+			if rules[n].re.MatchString(call) && (best == nil || rules[n].Level.Rank() > best.Level.Rank()) {
+				best = &rules[n]
+			}
+		}
+		if best == nil {
+			continue
+		}
+		risk.Matches = append(risk.Matches, RiskMatch{Rule: best.Name, Level: best.Level, Call: truncateRiskCall(call), Source: source})
+		risk.Score += riskWeights[best.Level]
+		if best.Level.Rank() > risk.Level.Rank() {
+			risk.Level = best.Level
+		}
+	}
+	if risk.Score > 100 {
+		risk.Score = 100
+	}
+	// Highest level first, most recent first within a level
+	ordered := make([]RiskMatch, 0, len(risk.Matches))
+	for rank := RiskCritical.Rank(); rank > 0; rank-- {
+		for n := len(risk.Matches) - 1; n >= 0; n-- {
+			if risk.Matches[n].Level.Rank() == rank {
+				ordered = append(ordered, risk.Matches[n])
+			}
+		}
+	}
+	risk.Matches = ordered
+	return risk
+}
+
+func truncateRiskCall(call string) string {
+	call = strings.Join(strings.Fields(call), " ")
+	if len(call) > 200 {
+		return call[:197] + "..."
+	}
+	return call
+}
+
+// AssessRisk scores the session's recent tool calls: the Claude transcript
+// when there is one, else the bottom of the pane. The result is kept for
+// Risk. Returns nil when risk scoring is disabled.
+func (i *Instance) AssessRisk() *SessionRisk {
+	settings := GetRiskSettings()
+	if !settings.GetEnabled() {
+		return nil
+	}
+
+	i.mu.RLock()
+	jsonlPath := i.GetJSONLPath()
+	tmuxSess := i.tmuxSession
+	i.mu.RUnlock()
+
+	var risk *SessionRisk
+	if jsonlPath != "" {
+		if calls, err := recentTranscriptToolCalls(jsonlPath, settings.GetRecentCalls()); err == nil {
+			risk = ScoreRisk(calls, "transcript", RiskRules())
+		}
+	}
+	if risk == nil && tmuxSess != nil && tmuxSess.Exists() {
+		if pane, err := tmuxSess.CapturePane(); err == nil {
+			risk = ScoreRisk(recentPaneLines(pane, riskPaneLines), "pane", RiskRules())
+		}
+	}
+	if risk == nil {
+		risk = &SessionRisk{Matches: []RiskMatch{}, CheckedAt: time.Now()}
+	}
+
+	i.mu.Lock()
+	i.risk = risk
+	i.riskCheckedAt = risk.CheckedAt
+	i.mu.Unlock()
+	return risk
+}
+
+// Risk returns the session's last risk assessment, nil before the first
+func (i *Instance) Risk() *SessionRisk {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.risk
+}
+
+// riskCheckDueLocked reports whether UpdateStatus should score the session
+// again, and marks the check as started. Callers hold i.mu.
+func (i *Instance) riskCheckDueLocked() bool {
+	if !riskScoredStatus(i.Status) {
+		return false
+	}
+	if i.riskChecking || time.Since(i.riskCheckedAt) < riskCheckInterval {
+		return false
+	}
+	i.riskChecking = true
+	return true
+}
+
+// checkRisk is UpdateStatus's background risk check. A critical match
+// triggers the emergency stop when [risk] auto_stop is set, once per match:
+// resuming the deck does not stop it again for the same call.
+func (i *Instance) checkRisk() {
+	risk := i.AssessRisk()
+
+	i.mu.Lock()
+	i.riskChecking = false
+	i.mu.Unlock()
+
+	if risk == nil || risk.Level != RiskCritical || !GetRiskSettings().AutoStop || LoadEmergencyStop() != nil {
+		return
+	}
+	top := risk.Matches[0]
+	if !markRiskAutoStop(i.ID + "\x00" + top.Rule + "\x00" + top.Call) {
+		return
+	}
+	reason := fmt.Sprintf("critical risk in %s: %s: %s", i.Title, top.Rule, top.Call)
+	sessionLog.Warn("risk_auto_stop", slog.String("session_id", i.ID), slog.String("rule", top.Rule))
+	if _, err := EmergencyStop(reason, "risk"); err != nil {
+		sessionLog.Warn("risk_auto_stop_failed", slog.String("error", err.Error()))
+	}
+}
+
+// riskAutoStopsFile remembers which critical matches already stopped the
+// deck, next to config.toml, so every process polling statuses agrees
+const riskAutoStopsFile = "risk-auto-stops.json"
+
+// riskAutoStopMemory is how long a match that stopped the deck is remembered
+const riskAutoStopMemory = 7 * 24 * time.Hour
+
+var riskAutoStopsMu sync.Mutex
+
+// markRiskAutoStop records a critical match and reports whether it is new
+func markRiskAutoStop(key string) bool {
+	riskAutoStopsMu.Lock()
+	defer riskAutoStopsMu.Unlock()
+
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return false
+	}
+	path := filepath.Join(dir, riskAutoStopsFile)
+	seen := make(map[string]time.Time)
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &seen)
+	}
+	if _, ok := seen[key]; ok {
+		return false
+	}
+	now := time.Now()
+	for k, at := range seen {
+		if now.Sub(at) > riskAutoStopMemory {
+			delete(seen, k)
+		}
+	}
+	seen[key] = now
+	data, err := json.Marshal(seen)
+	if err != nil {
+		return false
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return false
+	}
+	return os.Rename(tmp, path) == nil
+}
+
+// AssessRisks scores the sessions that are or were just busy, for callers
+// that load fresh instances on every poll and so never see UpdateStatus's
+// background checks finish
+func AssessRisks(instances []*Instance) {
+	for _, inst := range instances {
+		if riskScoredStatus(inst.GetStatusThreadSafe()) {
+			inst.AssessRisk()
+		}
+	}
+}
+
+// riskScoredStatus reports whether a session in status has tool calls worth
+// scoring: it is working, backing off, or has just finished
+func riskScoredStatus(status Status) bool {
+	return status == StatusRunning || status == StatusWaiting || status == StatusThrottled
+}
+
+// recentTranscriptToolCalls returns up to limit of the last tool calls in a
+// Claude transcript, oldest first. A call with a command input (Bash) is
+// the command; other calls are the tool name and its input.
+func recentTranscriptToolCalls(path string, limit int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - riskTranscriptTail
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+			data = data[idx+1:]
+		}
+	}
+
+	var calls []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Type    string `json:"type"`
+			Message struct {
+				Content []struct {
+					Type  string          `json:"type"`
+					Name  string          `json:"name"`
+					Input json.RawMessage `json:"input"`
+				} `json:"content"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Type != "assistant" {
+			continue
+		}
+		for _, c := range entry.Message.Content {
+			if c.Type != "tool_use" {
+				continue
+			}
+			var input struct {
+				Command string `json:"command"`
+			}
+			if json.Unmarshal(c.Input, &input) == nil && input.Command != "" {
+				calls = append(calls, input.Command)
+			} else {
+				calls = append(calls, c.Name+" "+string(c.Input))
+			}
+		}
+	}
+	if len(calls) > limit {
+		calls = calls[len(calls)-limit:]
+	}
+	return calls, scanner.Err()
+}
+
+// recentPaneLines returns the last n non-blank lines of a pane
+func recentPaneLines(pane string, n int) []string {
+	lines := strings.Split(tmux.StripANSI(pane), "\n")
+	var out []string
+	for k := len(lines) - 1; k >= 0 && len(out) < n; k-- {
+		if line := strings.TrimSpace(lines[k]); line != "" {
+			out = append(out, line)
+		}
+	}
+	for a, b := 0, len(out)-1; a < b; a, b = a+1, b-1 {
+		out[a], out[b] = out[b], out[a]
+	}
+	return out
+}
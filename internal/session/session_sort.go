@@ -0,0 +1,135 @@
+package session
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// Session sort modes (see SortSettings.Mode)
+const (
+	SortModeManual   = "manual"
+	SortModeActivity = "activity"
+	SortModeSeverity = "severity"
+)
+
+// ValidSortModes lists the accepted values of [sort] mode
+var ValidSortModes = []string{SortModeManual, SortModeActivity, SortModeSeverity}
+
+// GetMode returns the sort mode, falling back to manual for unknown values
+func (s SortSettings) GetMode() string {
+	if slices.Contains(ValidSortModes, s.Mode) {
+		return s.Mode
+	}
+	return SortModeManual
+}
+
+// PinRank returns the position of inst in the pinned list, or -1 if it is not pinned
+func (s SortSettings) PinRank(inst *Instance) int {
+	for i, ref := range s.Pinned {
+		if ref != "" && (ref == inst.ID || ref == inst.Title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsPinned reports whether inst is pinned
+func (s SortSettings) IsPinned(inst *Instance) bool {
+	return s.PinRank(inst) >= 0
+}
+
+// statusSeverity ranks statuses for severity ordering (lower needs attention sooner)
+func statusSeverity(status Status) int {
+	switch status {
+	case StatusError:
+		return 0
+	case StatusWaiting:
+		return 1
+	case StatusThrottled:
+		return 2
+	case StatusRunning:
+		return 3
+	case StatusStarting:
+		return 4
+	case StatusIdle:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// lastActivity is the later of pane activity and the last attach
+func lastActivity(inst *Instance) time.Time {
+	t := inst.GetLastActivityTime()
+	if inst.LastAccessedAt.After(t) {
+		return inst.LastAccessedAt
+	}
+	return t
+}
+
+// SortInstances orders instances in place: pinned first (in pin order), then
+// by the configured mode. Manual mode keeps the incoming order, so with no
+// pins this is a no-op.
+func SortInstances(instances []*Instance, settings SortSettings) {
+	mode := settings.GetMode()
+	if mode == SortModeManual && len(settings.Pinned) == 0 {
+		return
+	}
+
+	// Snapshot sort keys once: status and activity read locks/tmux state
+	type key struct {
+		pin      int
+		severity int
+		activity time.Time
+	}
+	keys := make(map[*Instance]key, len(instances))
+	for _, inst := range instances {
+		k := key{pin: settings.PinRank(inst)}
+		switch mode {
+		case SortModeSeverity:
+			k.severity = statusSeverity(inst.GetStatusThreadSafe())
+		case SortModeActivity:
+			k.activity = lastActivity(inst)
+		}
+		keys[inst] = k
+	}
+
+	sort.SliceStable(instances, func(i, j int) bool {
+		ki, kj := keys[instances[i]], keys[instances[j]]
+		if (ki.pin >= 0) != (kj.pin >= 0) {
+			return ki.pin >= 0
+		}
+		if ki.pin >= 0 {
+			return ki.pin < kj.pin
+		}
+		switch mode {
+		case SortModeSeverity:
+			return ki.severity < kj.severity
+		case SortModeActivity:
+			return ki.activity.After(kj.activity)
+		}
+		return false
+	})
+}
+
+// SetPinned pins inst (by ID) or removes every pin entry matching its ID or
+// title, then saves the config. New pins sort after existing ones.
+func SetPinned(inst *Instance, pinned bool) error {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+	isPinned := config.Sort.IsPinned(inst)
+	if pinned == isPinned {
+		return nil
+	}
+	if pinned {
+		config.Sort.Pinned = append(config.Sort.Pinned, inst.ID)
+	} else {
+		config.Sort.Pinned = slices.DeleteFunc(config.Sort.Pinned, func(ref string) bool {
+			return ref == inst.ID || ref == inst.Title
+		})
+	}
+	return SaveUserConfig(config)
+}
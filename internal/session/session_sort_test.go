@@ -0,0 +1,80 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func sortedTitles(instances []*Instance) []string {
+	titles := make([]string, len(instances))
+	for i, inst := range instances {
+		titles[i] = inst.Title
+	}
+	return titles
+}
+
+func TestSortInstances(t *testing.T) {
+	now := time.Now()
+	newInstances := func() []*Instance {
+		return []*Instance{
+			{ID: "1", Title: "idle", Status: StatusIdle, CreatedAt: now.Add(-3 * time.Hour)},
+			{ID: "2", Title: "waiting", Status: StatusWaiting, CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: "3", Title: "error", Status: StatusError, CreatedAt: now.Add(-4 * time.Hour)},
+			{ID: "4", Title: "running", Status: StatusRunning, CreatedAt: now.Add(-5 * time.Hour), LastAccessedAt: now},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		settings SortSettings
+		want     []string
+	}{
+		{"manual keeps order", SortSettings{}, []string{"idle", "waiting", "error", "running"}},
+		{"unknown mode is manual", SortSettings{Mode: "bogus"}, []string{"idle", "waiting", "error", "running"}},
+		{"severity", SortSettings{Mode: SortModeSeverity}, []string{"error", "waiting", "running", "idle"}},
+		{"activity", SortSettings{Mode: SortModeActivity}, []string{"running", "waiting", "idle", "error"}},
+		{"pins first in pin order", SortSettings{Mode: SortModeSeverity, Pinned: []string{"running", "1"}}, []string{"running", "idle", "error", "waiting"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances := newInstances()
+			SortInstances(instances, tt.settings)
+			got := sortedTitles(instances)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("order = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetPinned(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	inst := &Instance{ID: "abc", Title: "api"}
+	if err := SetPinned(inst, true); err != nil {
+		t.Fatalf("SetPinned: %v", err)
+	}
+	if err := SetPinned(inst, true); err != nil {
+		t.Fatalf("SetPinned again: %v", err)
+	}
+	if got := GetSortSettings().Pinned; len(got) != 1 || got[0] != "abc" {
+		t.Fatalf("pinned = %v, want [abc]", got)
+	}
+
+	// A title pin added by hand is removed too
+	config, _ := LoadUserConfig()
+	config.Sort.Pinned = append(config.Sort.Pinned, "api")
+	if err := SaveUserConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetPinned(inst, false); err != nil {
+		t.Fatalf("unpin: %v", err)
+	}
+	if got := GetSortSettings().Pinned; len(got) != 0 {
+		t.Errorf("pinned = %v, want empty", got)
+	}
+}
@@ -0,0 +1,261 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// builtinToolBinaries maps built-in tool names to the executable looked up on PATH
+var builtinToolBinaries = map[string]string{
+	"claude":   "claude",
+	"gemini":   "gemini",
+	"opencode": "opencode",
+	"codex":    "codex",
+	"ollama":   "ollama",
+	"aider":    "aider",
+}
+
+// SetupEnvironment describes what the init flow detected on this machine.
+type SetupEnvironment struct {
+	// TmuxPath is the resolved tmux binary ("" when tmux is missing)
+	TmuxPath string `json:"tmux_path,omitempty"`
+
+	// TmuxVersion is the output of `tmux -V` (e.g. "tmux 3.4")
+	TmuxVersion string `json:"tmux_version,omitempty"`
+
+	// ServiceManager is "systemd", "launchd", "cron" (heartbeats only), or ""
+	// when none is usable. Conductor heartbeats and the bridge daemon need one.
+	ServiceManager string `json:"service_manager,omitempty"`
+
+	// Tools maps detected AI tool names to their binary path
+	Tools map[string]string `json:"tools"`
+
+	// BaseDir is the agent-deck data directory (~/.agent-deck)
+	BaseDir string `json:"base_dir"`
+
+	// ConfigExists reports whether config.toml was already present
+	ConfigExists bool `json:"config_exists"`
+
+	// Profiles lists profiles that already exist
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+// DetectedToolNames returns detected tool names in sorted order
+func (e *SetupEnvironment) DetectedToolNames() []string {
+	names := make([]string, 0, len(e.Tools))
+	for name := range e.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SuggestedDefaultTool picks the default tool to offer: claude when installed,
+// otherwise the first detected tool, otherwise "" (shell).
+func (e *SetupEnvironment) SuggestedDefaultTool() string {
+	if _, ok := e.Tools["claude"]; ok {
+		return "claude"
+	}
+	if names := e.DetectedToolNames(); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// DetectSetupEnvironment inspects the machine for tmux, a service manager,
+// installed AI tools, and existing agent-deck state. It never modifies anything.
+func DetectSetupEnvironment() *SetupEnvironment {
+	env := &SetupEnvironment{Tools: make(map[string]string)}
+
+	if path, err := exec.LookPath("tmux"); err == nil {
+		env.TmuxPath = path
+		if out, err := exec.Command(path, "-V").Output(); err == nil {
+			env.TmuxVersion = strings.TrimSpace(string(out))
+		}
+	}
+
+	switch {
+	case runtime.GOOS == "darwin":
+		env.ServiceManager = "launchd"
+	case systemdUserAvailable():
+		env.ServiceManager = "systemd"
+	case cronAvailable():
+		env.ServiceManager = "cron"
+	}
+
+	for tool, bin := range builtinToolBinaries {
+		if path, err := exec.LookPath(bin); err == nil {
+			env.Tools[tool] = path
+		}
+	}
+	if config, err := LoadUserConfig(); err == nil && config != nil {
+		for name, def := range config.Tools {
+			fields := strings.Fields(def.Command)
+			if len(fields) == 0 {
+				continue
+			}
+			if path, err := exec.LookPath(fields[0]); err == nil {
+				env.Tools[name] = path
+			}
+		}
+	}
+
+	if dir, err := GetAgentDeckDir(); err == nil {
+		env.BaseDir = dir
+	}
+	if configPath, err := GetUserConfigPath(); err == nil {
+		if _, err := os.Stat(configPath); err == nil {
+			env.ConfigExists = true
+		}
+	}
+	env.Profiles, _ = ListProfiles()
+
+	return env
+}
+
+// Problems returns blocking issues and warnings for the detected environment.
+// Blocking issues prevent agent-deck from working at all (missing tmux);
+// warnings only limit optional features.
+func (e *SetupEnvironment) Problems() (blocking []string, warnings []string) {
+	if e.TmuxPath == "" {
+		blocking = append(blocking, "tmux not found in PATH (install it with your package manager, e.g. 'brew install tmux' or 'apt install tmux')")
+	}
+	switch e.ServiceManager {
+	case "":
+		warnings = append(warnings, "no systemd user session, launchd or crontab found; conductor heartbeats and the bridge daemon must be run manually")
+	case "cron":
+		warnings = append(warnings, "no systemd user session; conductor heartbeats use cron and the bridge daemon must be run manually")
+	}
+	if len(e.Tools) == 0 {
+		warnings = append(warnings, "no AI tools found in PATH (claude, gemini, opencode, codex); sessions will default to a plain shell")
+	}
+	return blocking, warnings
+}
+
+// SetupOptions controls what RunSetup writes.
+type SetupOptions struct {
+	// Profile to initialize and make the default ("" = default profile)
+	Profile string
+
+	// DefaultTool is written to config.toml when non-empty
+	DefaultTool string
+
+	// Telegram configures the conductor bridge notification sink when Token is set
+	Telegram TelegramSettings
+
+	// Slack configures the conductor bridge notification sink when BotToken is set
+	Slack SlackSettings
+
+	// EnableConductor turns on the conductor system in config.toml
+	EnableConductor bool
+}
+
+// SetupResult reports what RunSetup created.
+type SetupResult struct {
+	BaseDir       string   `json:"base_dir"`
+	Profile       string   `json:"profile"`
+	CreatedDirs   []string `json:"created_dirs,omitempty"`
+	ConfigCreated bool     `json:"config_created"`
+	ConfigUpdated bool     `json:"config_updated"`
+}
+
+// RunSetup creates the base directory layout, initializes the profile,
+// and writes default tool and notification sink settings. It is idempotent:
+// existing directories, profiles, and unrelated config values are preserved.
+func RunSetup(opts SetupOptions) (*SetupResult, error) {
+	profile := opts.Profile
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	baseDir, err := GetAgentDeckDir()
+	if err != nil {
+		return nil, err
+	}
+	result := &SetupResult{BaseDir: baseDir, Profile: profile}
+
+	profileDir, err := GetProfileDir(profile)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{baseDir, filepath.Join(baseDir, ProfilesDirName), profileDir, filepath.Join(baseDir, "logs")}
+	if opts.EnableConductor {
+		dirs = append(dirs, filepath.Join(baseDir, "conductor"))
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		result.CreatedDirs = append(result.CreatedDirs, dir)
+	}
+
+	// Opening storage creates state.db so the profile shows up in ListProfiles
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize profile storage: %w", err)
+	}
+	_ = storage.Close()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DefaultProfile != profile {
+		cfg.DefaultProfile = profile
+		if err := SaveConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	configPath, err := GetUserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if err := CreateExampleConfig(); err != nil {
+			return nil, fmt.Errorf("failed to create config.toml: %w", err)
+		}
+		result.ConfigCreated = true
+	}
+
+	needsUpdate := opts.DefaultTool != "" || opts.Telegram.Token != "" ||
+		opts.Slack.BotToken != "" || opts.EnableConductor
+	if !needsUpdate {
+		return result, nil
+	}
+
+	ClearUserConfigCache()
+	userCfg, err := LoadUserConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config.toml: %w", err)
+	}
+	if opts.DefaultTool != "" {
+		userCfg.DefaultTool = opts.DefaultTool
+	}
+	if opts.Telegram.Token != "" {
+		userCfg.Conductor.Telegram = opts.Telegram
+	}
+	if opts.Slack.BotToken != "" {
+		userCfg.Conductor.Slack = opts.Slack
+	}
+	if opts.EnableConductor || opts.Telegram.Token != "" || opts.Slack.BotToken != "" {
+		userCfg.Conductor.Enabled = true
+		if userCfg.Conductor.HeartbeatInterval == 0 {
+			userCfg.Conductor.HeartbeatInterval = 15
+		}
+	}
+	if err := SaveUserConfig(userCfg); err != nil {
+		return nil, err
+	}
+	result.ConfigUpdated = true
+
+	return result, nil
+}
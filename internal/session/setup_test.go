@@ -0,0 +1,114 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunSetup_CreatesLayoutAndConfig(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	result, err := RunSetup(SetupOptions{
+		Profile:     "work",
+		DefaultTool: "codex",
+		Telegram:    TelegramSettings{Token: "123:abc", UserID: 42},
+	})
+	if err != nil {
+		t.Fatalf("RunSetup: %v", err)
+	}
+
+	base := filepath.Join(tmpHome, ".agent-deck")
+	if result.BaseDir != base {
+		t.Errorf("BaseDir = %q, want %q", result.BaseDir, base)
+	}
+	for _, dir := range []string{base, filepath.Join(base, "profiles", "work"), filepath.Join(base, "logs")} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("expected %s to exist: %v", dir, err)
+		}
+	}
+	if !result.ConfigCreated || !result.ConfigUpdated {
+		t.Errorf("expected config created and updated, got %+v", result)
+	}
+
+	exists, err := ProfileExists("work")
+	if err != nil || !exists {
+		t.Errorf("profile work should exist (err=%v)", err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.DefaultProfile != "work" {
+		t.Errorf("default profile = %q, want work", cfg.DefaultProfile)
+	}
+
+	ClearUserConfigCache()
+	userCfg, err := LoadUserConfig()
+	if err != nil {
+		t.Fatalf("LoadUserConfig: %v", err)
+	}
+	if userCfg.DefaultTool != "codex" {
+		t.Errorf("default_tool = %q, want codex", userCfg.DefaultTool)
+	}
+	if !userCfg.Conductor.Enabled || userCfg.Conductor.Telegram.UserID != 42 {
+		t.Errorf("telegram sink not configured: %+v", userCfg.Conductor)
+	}
+	if userCfg.Conductor.HeartbeatInterval != 15 {
+		t.Errorf("heartbeat interval = %d, want 15", userCfg.Conductor.HeartbeatInterval)
+	}
+}
+
+func TestRunSetup_Idempotent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	if _, err := RunSetup(SetupOptions{}); err != nil {
+		t.Fatalf("first RunSetup: %v", err)
+	}
+	result, err := RunSetup(SetupOptions{})
+	if err != nil {
+		t.Fatalf("second RunSetup: %v", err)
+	}
+	if len(result.CreatedDirs) != 0 {
+		t.Errorf("second run should not create directories, got %v", result.CreatedDirs)
+	}
+	if result.ConfigCreated || result.ConfigUpdated {
+		t.Errorf("second run should not touch config, got %+v", result)
+	}
+	if result.Profile != DefaultProfile {
+		t.Errorf("profile = %q, want %q", result.Profile, DefaultProfile)
+	}
+}
+
+func TestSetupEnvironmentProblems(t *testing.T) {
+	env := &SetupEnvironment{Tools: map[string]string{}}
+	blocking, warnings := env.Problems()
+	if len(blocking) != 1 {
+		t.Errorf("missing tmux should be blocking, got %v", blocking)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("expected service manager and tools warnings, got %v", warnings)
+	}
+
+	env = &SetupEnvironment{
+		TmuxPath:       "/usr/bin/tmux",
+		ServiceManager: "systemd",
+		Tools:          map[string]string{"gemini": "/bin/gemini", "codex": "/bin/codex"},
+	}
+	blocking, warnings = env.Problems()
+	if len(blocking) != 0 || len(warnings) != 0 {
+		t.Errorf("expected no problems, got blocking=%v warnings=%v", blocking, warnings)
+	}
+	if got := env.SuggestedDefaultTool(); got != "codex" {
+		t.Errorf("SuggestedDefaultTool = %q, want codex (first detected)", got)
+	}
+	env.Tools["claude"] = "/bin/claude"
+	if got := env.SuggestedDefaultTool(); got != "claude" {
+		t.Errorf("SuggestedDefaultTool = %q, want claude", got)
+	}
+}
@@ -0,0 +1,493 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Skill pack layout. A pack is a directory with an optional pack.toml manifest
+// and any of three subdirectories:
+//
+//	instructions/*.md  fragments imported into the conductor's CLAUDE.md
+//	prompts/*.md       canned prompts the conductor can send to sessions
+//	hooks/*            scripts the instructions may tell the conductor to run
+const (
+	skillPacksDirName        = "skill-packs"
+	skillPackManifestName    = "pack.toml"
+	skillPackInstallName     = ".installed.json"
+	skillPackInstructionsDir = "instructions"
+	skillPackPromptsDir      = "prompts"
+	skillPackHooksDir        = "hooks"
+	conductorSkillsFileName  = "SKILLS.md"
+	conductorSkillsStart     = "<!-- agent-deck:skill-packs -->"
+	conductorSkillsEnd       = "<!-- /agent-deck:skill-packs -->"
+)
+
+var (
+	ErrSkillPackNotFound       = errors.New("skill pack not found")
+	ErrSkillPackInvalid        = errors.New("not a skill pack")
+	ErrSkillPackInUse          = errors.New("skill pack is attached to conductors")
+	ErrSkillPackAttached       = errors.New("skill pack already attached")
+	ErrSkillPackDetached       = errors.New("skill pack not attached")
+	ErrConductorClaudeMDLinked = errors.New("conductor CLAUDE.md is a custom symlink")
+)
+
+// SkillPackManifest is the optional pack.toml at the root of a pack.
+type SkillPackManifest struct {
+	Name        string `toml:"name"`
+	Version     string `toml:"version"`
+	Description string `toml:"description"`
+}
+
+// skillPackInstall records where an installed pack came from so it can be updated.
+type skillPackInstall struct {
+	Source      string    `json:"source"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// SkillPack is an installed (or source) skill pack.
+type SkillPack struct {
+	Name         string    `json:"name"`
+	Version      string    `json:"version,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Path         string    `json:"path"`
+	Source       string    `json:"source,omitempty"`
+	InstalledAt  time.Time `json:"installed_at,omitempty"`
+	Instructions []string  `json:"instructions"`
+	Prompts      []string  `json:"prompts"`
+	Hooks        []string  `json:"hooks"`
+}
+
+// SkillPacksDir returns ~/.agent-deck/skill-packs.
+func SkillPacksDir() (string, error) {
+	base, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, skillPacksDirName), nil
+}
+
+// listPackFiles returns the regular file names in dir/sub, sorted. With ext
+// set, only files with that extension are returned.
+func listPackFiles(dir, sub, ext string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, sub))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if ext != "" && !strings.EqualFold(filepath.Ext(name), ext) {
+			continue
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// LoadSkillPack reads the pack at dir. The name comes from pack.toml, falling
+// back to the directory name.
+func LoadSkillPack(dir string) (*SkillPack, error) {
+	dir = expandSkillPath(dir)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSkillPackNotFound, dir)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s is not a directory", ErrSkillPackInvalid, dir)
+	}
+
+	pack := &SkillPack{Name: filepath.Base(dir), Path: dir}
+	manifestPath := filepath.Join(dir, skillPackManifestName)
+	if _, err := os.Stat(manifestPath); err == nil {
+		var manifest SkillPackManifest
+		if _, err := toml.DecodeFile(manifestPath, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+		}
+		if manifest.Name != "" {
+			pack.Name = manifest.Name
+		}
+		pack.Version = manifest.Version
+		pack.Description = manifest.Description
+	}
+
+	if pack.Instructions, err = listPackFiles(dir, skillPackInstructionsDir, ".md"); err != nil {
+		return nil, err
+	}
+	if pack.Prompts, err = listPackFiles(dir, skillPackPromptsDir, ".md"); err != nil {
+		return nil, err
+	}
+	if pack.Hooks, err = listPackFiles(dir, skillPackHooksDir, ""); err != nil {
+		return nil, err
+	}
+	if len(pack.Instructions)+len(pack.Prompts)+len(pack.Hooks) == 0 {
+		return nil, fmt.Errorf("%w: %s has no instructions/, prompts/ or hooks/ files", ErrSkillPackInvalid, dir)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, skillPackInstallName)); err == nil {
+		var install skillPackInstall
+		if json.Unmarshal(data, &install) == nil {
+			pack.Source = install.Source
+			pack.InstalledAt = install.InstalledAt
+		}
+	}
+	return pack, nil
+}
+
+// GetSkillPack loads an installed pack by name.
+func GetSkillPack(name string) (*SkillPack, error) {
+	root, err := SkillPacksDir()
+	if err != nil {
+		return nil, err
+	}
+	pack, err := LoadSkillPack(filepath.Join(root, name))
+	if err != nil {
+		if errors.Is(err, ErrSkillPackNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrSkillPackNotFound, name)
+		}
+		return nil, err
+	}
+	return pack, nil
+}
+
+// ListSkillPacks returns all installed packs sorted by name. Broken packs are skipped.
+func ListSkillPacks() ([]SkillPack, error) {
+	root, err := SkillPacksDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return []SkillPack{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read skill packs: %w", err)
+	}
+	packs := []SkillPack{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		pack, err := LoadSkillPack(filepath.Join(root, entry.Name()))
+		if err != nil {
+			continue
+		}
+		packs = append(packs, *pack)
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	return packs, nil
+}
+
+// InstallSkillPack copies the pack at srcDir (or a registry://name@version
+// reference) into the pack store, replacing any installed version, and
+// recomposes conductors that have it attached.
+func InstallSkillPack(srcDir string) (*SkillPack, error) {
+	source := ""
+	if ref, ok := ParseRegistryRef(srcDir); ok {
+		dir, err := FetchRegistryRef(ref, RegistryKindSkillPack)
+		if err != nil {
+			return nil, err
+		}
+		// Record the unpinned ref so updates pick up newer versions
+		source = RegistryRef{Name: ref.Name}.String()
+		srcDir = dir
+	}
+	src, err := LoadSkillPack(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	if !conductorNameRegex.MatchString(src.Name) {
+		return nil, fmt.Errorf("invalid skill pack name %q: must start with a letter or digit and contain only letters, digits, '.', '_' or '-'", src.Name)
+	}
+	srcAbs, err := filepath.Abs(src.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := SkillPacksDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create skill pack store: %w", err)
+	}
+	target := filepath.Join(root, src.Name)
+	if srcAbs == target {
+		return nil, fmt.Errorf("%s is already the installed copy; pass the pack's source directory", srcAbs)
+	}
+	if source == "" {
+		source = srcAbs
+	}
+
+	// Stage next to the target and swap, so a failed copy leaves the old version
+	staging, err := os.MkdirTemp(root, "."+src.Name+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+	if err := copyDir(srcAbs, staging); err != nil {
+		return nil, fmt.Errorf("failed to copy skill pack: %w", err)
+	}
+	record, err := json.MarshalIndent(skillPackInstall{Source: source, InstalledAt: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(staging, skillPackInstallName), record, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write install record: %w", err)
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return nil, fmt.Errorf("failed to remove previous version: %w", err)
+	}
+	if err := os.Rename(staging, target); err != nil {
+		return nil, fmt.Errorf("failed to install skill pack: %w", err)
+	}
+
+	if err := recomposeSkillPackUsers(src.Name); err != nil {
+		return nil, err
+	}
+	return LoadSkillPack(target)
+}
+
+// UpdateSkillPack reinstalls an installed pack from srcDir, or from where it
+// was originally installed when srcDir is empty (a directory, or the latest
+// registry version).
+func UpdateSkillPack(name, srcDir string) (*SkillPack, error) {
+	installed, err := GetSkillPack(name)
+	if err != nil {
+		return nil, err
+	}
+	if srcDir == "" {
+		srcDir = installed.Source
+	}
+	if srcDir == "" {
+		return nil, fmt.Errorf("skill pack %q has no recorded source; pass the source directory", name)
+	}
+	if ref, ok := ParseRegistryRef(srcDir); ok {
+		if ref.Name != name {
+			return nil, fmt.Errorf("%s does not refer to skill pack %q", srcDir, name)
+		}
+		return InstallSkillPack(srcDir)
+	}
+	pack, err := LoadSkillPack(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	if pack.Name != name {
+		return nil, fmt.Errorf("source %s contains skill pack %q, not %q", srcDir, pack.Name, name)
+	}
+	return InstallSkillPack(srcDir)
+}
+
+// RemoveSkillPack deletes an installed pack. It fails while any conductor has it attached.
+func RemoveSkillPack(name string) error {
+	pack, err := GetSkillPack(name)
+	if err != nil {
+		return err
+	}
+	if users := skillPackUsers(name); len(users) > 0 {
+		return fmt.Errorf("%w: %s (detach it from %s first)", ErrSkillPackInUse, name, strings.Join(users, ", "))
+	}
+	if err := os.RemoveAll(pack.Path); err != nil {
+		return fmt.Errorf("failed to remove skill pack: %w", err)
+	}
+	return nil
+}
+
+// skillPackUsers returns the conductors that have the pack attached.
+func skillPackUsers(name string) []string {
+	conductors, err := ListConductors()
+	if err != nil {
+		return nil
+	}
+	var users []string
+	for _, meta := range conductors {
+		if slices.Contains(meta.SkillPacks, name) {
+			users = append(users, meta.Name)
+		}
+	}
+	return users
+}
+
+// recomposeSkillPackUsers refreshes SKILLS.md for every conductor using the pack.
+func recomposeSkillPackUsers(name string) error {
+	for _, conductor := range skillPackUsers(name) {
+		if err := ComposeConductorSkills(conductor); err != nil && !errors.Is(err, ErrConductorClaudeMDLinked) {
+			return fmt.Errorf("failed to recompose conductor %s: %w", conductor, err)
+		}
+	}
+	return nil
+}
+
+// AttachSkillPack records the pack in the conductor's meta.json and composes
+// it into the conductor's CLAUDE.md.
+func AttachSkillPack(conductor, name string) error {
+	if _, err := GetSkillPack(name); err != nil {
+		return err
+	}
+	_, err := UpdateConductorMeta(conductor, func(meta *ConductorMeta) error {
+		if slices.Contains(meta.SkillPacks, name) {
+			return fmt.Errorf("%w: %s on %s", ErrSkillPackAttached, name, conductor)
+		}
+		meta.SkillPacks = append(meta.SkillPacks, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ComposeConductorSkills(conductor)
+}
+
+// DetachSkillPack removes the pack from the conductor and recomposes its CLAUDE.md.
+func DetachSkillPack(conductor, name string) error {
+	_, err := UpdateConductorMeta(conductor, func(meta *ConductorMeta) error {
+		idx := slices.Index(meta.SkillPacks, name)
+		if idx < 0 {
+			return fmt.Errorf("%w: %s on %s", ErrSkillPackDetached, name, conductor)
+		}
+		meta.SkillPacks = slices.Delete(meta.SkillPacks, idx, idx+1)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ComposeConductorSkills(conductor)
+}
+
+// renderConductorSkills builds SKILLS.md for the given packs. Instruction
+// fragments are pulled in with CLAUDE.md @imports so pack updates apply
+// without regenerating the file.
+func renderConductorSkills(packs []SkillPack) string {
+	var b strings.Builder
+	b.WriteString("# Skill Packs\n\n")
+	b.WriteString("Generated by agent-deck from the skill packs attached to this conductor. ")
+	b.WriteString("Do not edit; use 'agent-deck conductor skills attach/detach' instead.\n")
+	for _, pack := range packs {
+		b.WriteString("\n## " + pack.Name)
+		if pack.Version != "" {
+			b.WriteString(" (v" + strings.TrimPrefix(pack.Version, "v") + ")")
+		}
+		b.WriteString("\n\n")
+		if pack.Description != "" {
+			b.WriteString(pack.Description + "\n\n")
+		}
+		for _, file := range pack.Instructions {
+			b.WriteString("@" + filepath.Join(pack.Path, skillPackInstructionsDir, file) + "\n")
+		}
+		if len(pack.Prompts) > 0 {
+			b.WriteString("\nCanned prompts (read the file and send its contents when the instructions call for it):\n")
+			for _, file := range pack.Prompts {
+				b.WriteString("- " + filepath.Join(pack.Path, skillPackPromptsDir, file) + "\n")
+			}
+		}
+		if len(pack.Hooks) > 0 {
+			b.WriteString("\nHook scripts (run them when the instructions call for it):\n")
+			for _, file := range pack.Hooks {
+				b.WriteString("- " + filepath.Join(pack.Path, skillPackHooksDir, file) + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// setManagedBlock replaces the block between startMarker and endMarker in a
+// CLAUDE.md body with body (markers included), appending it when absent.
+// An empty body removes the block.
+func setManagedBlock(content, startMarker, endMarker, body string) string {
+	block := ""
+	if body != "" {
+		block = startMarker + "\n" + strings.TrimRight(body, "\n") + "\n" + endMarker + "\n"
+	}
+	if start := strings.Index(content, startMarker); start >= 0 {
+		if end := strings.Index(content[start:], endMarker); end >= 0 {
+			end += start + len(endMarker)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			if block != "" {
+				return content[:start] + block + content[end:]
+			}
+			return strings.TrimRight(content[:start], "\n") + "\n" + content[end:]
+		}
+	}
+	if block == "" {
+		return content
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + block
+}
+
+// ComposeConductorSkills writes the conductor's SKILLS.md from its attached
+// packs and imports it from CLAUDE.md via a managed block. With no packs the
+// file and block are removed. A symlinked (custom) CLAUDE.md is left alone and
+// ErrConductorClaudeMDLinked is returned after SKILLS.md is written.
+func ComposeConductorSkills(conductor string) error {
+	meta, err := LoadConductorMeta(conductor)
+	if err != nil {
+		return err
+	}
+	dir, err := ConductorNameDir(conductor)
+	if err != nil {
+		return err
+	}
+
+	var packs []SkillPack
+	for _, name := range meta.SkillPacks {
+		pack, err := GetSkillPack(name)
+		if err != nil {
+			return err
+		}
+		packs = append(packs, *pack)
+	}
+
+	skillsPath := filepath.Join(dir, conductorSkillsFileName)
+	if len(packs) == 0 {
+		if err := os.Remove(skillsPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", conductorSkillsFileName, err)
+		}
+	} else if err := os.WriteFile(skillsPath, []byte(renderConductorSkills(packs)), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", conductorSkillsFileName, err)
+	}
+
+	claudePath := filepath.Join(dir, "CLAUDE.md")
+	info, err := os.Lstat(claudePath)
+	if err != nil {
+		return fmt.Errorf("failed to read CLAUDE.md: %w", err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		if len(packs) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: add '@%s' to it to load skill packs", ErrConductorClaudeMDLinked, skillsPath)
+	}
+	data, err := os.ReadFile(claudePath)
+	if err != nil {
+		return fmt.Errorf("failed to read CLAUDE.md: %w", err)
+	}
+	body := ""
+	if len(packs) > 0 {
+		body = "## Skill Packs\n\n@" + conductorSkillsFileName
+	}
+	updated := setManagedBlock(string(data), conductorSkillsStart, conductorSkillsEnd, body)
+	if updated == string(data) {
+		return nil
+	}
+	if err := os.WriteFile(claudePath, []byte(updated), 0o644); err != nil {
+		return fmt.Errorf("failed to write CLAUDE.md: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestSkillPack(t *testing.T, dir, version, fragment string) {
+	t.Helper()
+	files := map[string]string{
+		"pack.toml":               "name = \"incident-response\"\nversion = \"" + version + "\"\ndescription = \"Triage and escalation\"\n",
+		"instructions/triage.md":  fragment,
+		"prompts/postmortem.md":   "Write a postmortem.",
+		"hooks/page-oncall.sh":    "#!/bin/sh\necho paged\n",
+		"instructions/notes.txt":  "ignored: not markdown",
+		"instructions/.hidden.md": "ignored: hidden",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSkillPackAttachComposesClaudeMD(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SetupConductor("ops", "work", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	src := filepath.Join(t.TempDir(), "ir")
+	writeTestSkillPack(t, src, "1.0.0", "Page on-call for sev1.")
+
+	pack, err := InstallSkillPack(src)
+	if err != nil {
+		t.Fatalf("InstallSkillPack: %v", err)
+	}
+	if pack.Name != "incident-response" || len(pack.Instructions) != 1 || len(pack.Prompts) != 1 || len(pack.Hooks) != 1 {
+		t.Fatalf("unexpected pack: %+v", pack)
+	}
+
+	dir, _ := ConductorNameDir("ops")
+	original, _ := os.ReadFile(filepath.Join(dir, "CLAUDE.md"))
+
+	if err := AttachSkillPack("ops", "incident-response"); err != nil {
+		t.Fatalf("AttachSkillPack: %v", err)
+	}
+	if err := AttachSkillPack("ops", "incident-response"); !errors.Is(err, ErrSkillPackAttached) {
+		t.Errorf("second attach should fail with ErrSkillPackAttached, got %v", err)
+	}
+
+	claude, _ := os.ReadFile(filepath.Join(dir, "CLAUDE.md"))
+	if strings.Count(string(claude), "@SKILLS.md") != 1 {
+		t.Errorf("CLAUDE.md should import SKILLS.md once:\n%s", claude)
+	}
+	skills, _ := os.ReadFile(filepath.Join(dir, "SKILLS.md"))
+	if !strings.Contains(string(skills), "@"+filepath.Join(pack.Path, "instructions", "triage.md")) {
+		t.Errorf("SKILLS.md should import the instruction fragment:\n%s", skills)
+	}
+
+	// Re-running setup rewrites the template but keeps the pack composed
+	if err := SetupConductor("ops", "work", true, "", "", ""); err != nil {
+		t.Fatalf("re-setup: %v", err)
+	}
+	claude, _ = os.ReadFile(filepath.Join(dir, "CLAUDE.md"))
+	if strings.Count(string(claude), "@SKILLS.md") != 1 {
+		t.Errorf("setup should keep the skills block:\n%s", claude)
+	}
+
+	if err := RemoveSkillPack("incident-response"); !errors.Is(err, ErrSkillPackInUse) {
+		t.Errorf("removing an attached pack should fail, got %v", err)
+	}
+
+	if err := DetachSkillPack("ops", "incident-response"); err != nil {
+		t.Fatalf("DetachSkillPack: %v", err)
+	}
+	claude, _ = os.ReadFile(filepath.Join(dir, "CLAUDE.md"))
+	if strings.Contains(string(claude), conductorSkillsStart) {
+		t.Errorf("detach should remove the skills block:\n%s", claude)
+	}
+	if string(claude) != string(original) {
+		t.Errorf("detach should restore CLAUDE.md:\n%s", claude)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "SKILLS.md")); !os.IsNotExist(err) {
+		t.Error("SKILLS.md should be removed when no packs are attached")
+	}
+	if err := RemoveSkillPack("incident-response"); err != nil {
+		t.Errorf("RemoveSkillPack: %v", err)
+	}
+}
+
+func TestUpdateSkillPackFromRecordedSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	src := filepath.Join(t.TempDir(), "ir")
+	writeTestSkillPack(t, src, "1.0.0", "v1")
+	if _, err := InstallSkillPack(src); err != nil {
+		t.Fatalf("InstallSkillPack: %v", err)
+	}
+
+	writeTestSkillPack(t, src, "1.1.0", "v2")
+	pack, err := UpdateSkillPack("incident-response", "")
+	if err != nil {
+		t.Fatalf("UpdateSkillPack: %v", err)
+	}
+	if pack.Version != "1.1.0" || pack.Source != src {
+		t.Errorf("got version %q source %q", pack.Version, pack.Source)
+	}
+	data, _ := os.ReadFile(filepath.Join(pack.Path, "instructions", "triage.md"))
+	if string(data) != "v2" {
+		t.Errorf("installed fragment = %q, want v2", data)
+	}
+
+	packs, err := ListSkillPacks()
+	if err != nil || len(packs) != 1 {
+		t.Fatalf("ListSkillPacks = %v, %v (staging dirs must not be listed)", packs, err)
+	}
+}
+
+func TestLoadSkillPackRejectsEmptyDir(t *testing.T) {
+	if _, err := LoadSkillPack(t.TempDir()); !errors.Is(err, ErrSkillPackInvalid) {
+		t.Errorf("expected ErrSkillPackInvalid, got %v", err)
+	}
+}
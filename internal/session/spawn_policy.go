@@ -0,0 +1,243 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// ErrSpawnDenied means the [spawn] policy refused a conductor's new session
+var ErrSpawnDenied = errors.New("spawn denied by policy")
+
+// SpawnLimits bound what a conductor may create. Zero values are unlimited.
+type SpawnLimits struct {
+	// MaxWorkers caps the conductor's live sessions (created by it and not removed)
+	MaxWorkers int `toml:"max_workers"`
+
+	// MaxPerHour caps sessions the conductor creates in any hour
+	MaxPerHour int `toml:"max_per_hour"`
+
+	// MaxPerDay caps sessions the conductor creates since midnight
+	MaxPerDay int `toml:"max_per_day"`
+
+	// MaxTokensPerDay caps the tokens the conductor's Claude workers have
+	// used since midnight, including workers removed since
+	MaxTokensPerDay int `toml:"max_tokens_per_day"`
+
+	// AllowedTools restricts the tools of created sessions (e.g. ["claude", "codex"])
+	AllowedTools []string `toml:"allowed_tools"`
+
+	// AllowedTemplates requires sessions to be created from one of these
+	// templates (--reason template:<name>)
+	AllowedTemplates []string `toml:"allowed_templates"`
+}
+
+// SpawnPolicySettings controls which conductors may create worker sessions
+// from their session (agent-deck add/launch/try/session fork) and how many.
+// Sessions created outside a conductor are never limited.
+type SpawnPolicySettings struct {
+	// AllowedConductors lists the conductors that may create sessions.
+	// Default: every conductor
+	AllowedConductors []string `toml:"allowed_conductors"`
+
+	// DeniedConductors may not create sessions, whatever AllowedConductors says
+	DeniedConductors []string `toml:"denied_conductors"`
+
+	// Limits for every conductor
+	MaxWorkers       int      `toml:"max_workers"`
+	MaxPerHour       int      `toml:"max_per_hour"`
+	MaxPerDay        int      `toml:"max_per_day"`
+	MaxTokensPerDay  int      `toml:"max_tokens_per_day"`
+	AllowedTools     []string `toml:"allowed_tools"`
+	AllowedTemplates []string `toml:"allowed_templates"`
+
+	// Conductors overrides the limits per conductor ([spawn.conductors.<name>])
+	Conductors map[string]SpawnLimits `toml:"conductors"`
+}
+
+// GetSpawnPolicySettings returns [spawn] from config.toml
+func GetSpawnPolicySettings() SpawnPolicySettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return SpawnPolicySettings{}
+	}
+	return config.Spawn
+}
+
+// LimitsFor returns a conductor's effective limits: its own settings where
+// set, the [spawn] defaults otherwise
+func (s SpawnPolicySettings) LimitsFor(conductor string) SpawnLimits {
+	limits := SpawnLimits{
+		MaxWorkers:       s.MaxWorkers,
+		MaxPerHour:       s.MaxPerHour,
+		MaxPerDay:        s.MaxPerDay,
+		MaxTokensPerDay:  s.MaxTokensPerDay,
+		AllowedTools:     s.AllowedTools,
+		AllowedTemplates: s.AllowedTemplates,
+	}
+	own, ok := s.Conductors[conductor]
+	if !ok {
+		return limits
+	}
+	if own.MaxWorkers != 0 {
+		limits.MaxWorkers = own.MaxWorkers
+	}
+	if own.MaxPerHour != 0 {
+		limits.MaxPerHour = own.MaxPerHour
+	}
+	if own.MaxPerDay != 0 {
+		limits.MaxPerDay = own.MaxPerDay
+	}
+	if own.MaxTokensPerDay != 0 {
+		limits.MaxTokensPerDay = own.MaxTokensPerDay
+	}
+	if own.AllowedTools != nil {
+		limits.AllowedTools = own.AllowedTools
+	}
+	if own.AllowedTemplates != nil {
+		limits.AllowedTemplates = own.AllowedTemplates
+	}
+	return limits
+}
+
+// SpawnRequest describes a session a conductor is about to create
+type SpawnRequest struct {
+	Conductor string // "" when not created from a conductor session
+	Tool      string
+	Template  string // template name from --reason template:<name>
+}
+
+// SpawningConductor returns the conductor whose session is running this
+// process, or "" outside a conductor session. The tmux session the process
+// runs in decides, matched against instances: environment variables can be
+// cleared, the process tree cannot, so a conductor cannot slip past the
+// policy with env -u. AGENTDECK_TITLE is only consulted when the caller is
+// in no known session.
+func SpawningConductor(instances []*Instance) string {
+	if tmuxName := callerTmuxSession(); tmuxName != "" {
+		for _, inst := range instances {
+			if ts := inst.GetTmuxSession(); ts != nil && ts.Name == tmuxName {
+				name, _ := ConductorNameFromTitle(inst.Title)
+				return name
+			}
+		}
+	}
+	name, _ := ConductorNameFromTitle(os.Getenv("AGENTDECK_TITLE"))
+	return name
+}
+
+// callerTmuxSession returns the tmux session this process runs in: the
+// session of the pane whose process is one of its ancestors, else that of
+// $TMUX_PANE, else ""
+var callerTmuxSession = func() string {
+	out, err := tmux.Command("list-panes", "-a", "-F", "#{pane_pid}\t#{pane_id}\t#{session_name}").Output()
+	if err != nil {
+		return ""
+	}
+	byPID := make(map[int]string)
+	byPane := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[0]); err == nil {
+			byPID[pid] = fields[2]
+		}
+		byPane[fields[1]] = fields[2]
+	}
+	// Bounded in case a pid is reused while walking
+	for pid, depth := os.Getppid(), 0; pid > 1 && depth < 64; pid, depth = parentPID(pid), depth+1 {
+		if name, ok := byPID[pid]; ok {
+			return name
+		}
+	}
+	return byPane[os.Getenv("TMUX_PANE")]
+}
+
+// parentPID returns pid's parent process, or 0 when it cannot be read
+func parentPID(pid int) int {
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		// The command name may contain spaces and parentheses, so fields are
+		// counted from its closing parenthesis: state, then the parent
+		stat := string(data)
+		if end := strings.LastIndexByte(stat, ')'); end >= 0 {
+			if fields := strings.Fields(stat[end+1:]); len(fields) > 1 {
+				ppid, _ := strconv.Atoi(fields[1])
+				return ppid
+			}
+		}
+		return 0
+	}
+	out, err := exec.Command("ps", "-o", "ppid=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return 0
+	}
+	ppid, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	return ppid
+}
+
+// CheckSpawn applies the [spawn] policy to a request. instances are the
+// profile's current sessions, used to count the conductor's live workers.
+// It returns an error wrapping ErrSpawnDenied when the spawn is refused, a
+// *QuotaError when a quota is used up.
+func CheckSpawn(db *statedb.StateDB, settings SpawnPolicySettings, req SpawnRequest, instances []*Instance, now time.Time) error {
+	if req.Conductor == "" {
+		return nil
+	}
+	deny := func(format string, args ...any) error {
+		return fmt.Errorf("%w: conductor %s %s", ErrSpawnDenied, req.Conductor, fmt.Sprintf(format, args...))
+	}
+
+	if slices.Contains(settings.DeniedConductors, req.Conductor) ||
+		(len(settings.AllowedConductors) > 0 && !slices.Contains(settings.AllowedConductors, req.Conductor)) {
+		return deny("may not create sessions")
+	}
+	limits := settings.LimitsFor(req.Conductor)
+	if len(limits.AllowedTools) > 0 && !slices.Contains(limits.AllowedTools, req.Tool) {
+		return deny("may not create %s sessions (allowed: %v)", req.Tool, limits.AllowedTools)
+	}
+	if len(limits.AllowedTemplates) > 0 && !slices.Contains(limits.AllowedTemplates, req.Template) {
+		if req.Template == "" {
+			return deny("must create sessions from a template (--reason template:<name>, allowed: %v)", limits.AllowedTemplates)
+		}
+		return deny("may not use template %s (allowed: %v)", req.Template, limits.AllowedTemplates)
+	}
+	if limits.MaxWorkers <= 0 && limits.MaxPerHour <= 0 && limits.MaxPerDay <= 0 && limits.MaxTokensPerDay <= 0 {
+		return nil
+	}
+	if db == nil {
+		return deny("has spawn quotas but the state database is unavailable")
+	}
+
+	usage, err := ConductorQuotaUsage(db, req.Conductor, instances, now, limits.MaxTokensPerDay > 0)
+	if err != nil {
+		return fmt.Errorf("spawn policy: %w", err)
+	}
+	return checkQuotas(req.Conductor, limits, usage)
+}
+
+// RecordSpawn notes that conductor created inst, for worker counts and the
+// audit log. Sessions not created by a conductor are ignored.
+func RecordSpawn(db *statedb.StateDB, conductor string, inst *Instance, now time.Time) error {
+	if db == nil || conductor == "" {
+		return nil
+	}
+	return db.AppendAudit(inst.ID, inst.Title, statedb.AuditSpawned, conductor, now)
+}
+
+// RecordSpawnDenied logs a refused spawn in the audit log
+func RecordSpawnDenied(db *statedb.StateDB, reason error, now time.Time) error {
+	if db == nil {
+		return nil
+	}
+	return db.AppendAudit("", "", statedb.AuditSpawnDenied, reason.Error(), now)
+}
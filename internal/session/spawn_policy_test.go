@@ -0,0 +1,245 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpawnPolicy_LimitsForOverridesDefaults(t *testing.T) {
+	writeMacroConfig(t, `
+[spawn]
+max_workers = 5
+allowed_tools = ["claude"]
+
+[spawn.conductors.ops]
+max_workers = 20
+max_per_hour = 10
+`)
+	settings := GetSpawnPolicySettings()
+
+	ops := settings.LimitsFor("ops")
+	if ops.MaxWorkers != 20 || ops.MaxPerHour != 10 {
+		t.Errorf("ops limits = %+v, want max_workers 20, max_per_hour 10", ops)
+	}
+	if len(ops.AllowedTools) != 1 || ops.AllowedTools[0] != "claude" {
+		t.Errorf("ops allowed_tools = %v, want inherited [claude]", ops.AllowedTools)
+	}
+	if other := settings.LimitsFor("triage"); other.MaxWorkers != 5 || other.MaxPerHour != 0 {
+		t.Errorf("triage limits = %+v, want defaults", other)
+	}
+}
+
+func TestCheckSpawn_ConductorToolAndTemplateRules(t *testing.T) {
+	settings := SpawnPolicySettings{
+		AllowedConductors: []string{"ops", "triage"},
+		DeniedConductors:  []string{"triage"},
+		AllowedTools:      []string{"claude"},
+		AllowedTemplates:  []string{"review"},
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		req     SpawnRequest
+		allowed bool
+	}{
+		{"not a conductor", SpawnRequest{Tool: "shell"}, true},
+		{"allowed", SpawnRequest{Conductor: "ops", Tool: "claude", Template: "review"}, true},
+		{"not listed", SpawnRequest{Conductor: "rogue", Tool: "claude", Template: "review"}, false},
+		{"denied", SpawnRequest{Conductor: "triage", Tool: "claude", Template: "review"}, false},
+		{"tool", SpawnRequest{Conductor: "ops", Tool: "codex", Template: "review"}, false},
+		{"no template", SpawnRequest{Conductor: "ops", Tool: "claude"}, false},
+		{"other template", SpawnRequest{Conductor: "ops", Tool: "claude", Template: "deploy"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSpawn(nil, settings, tt.req, nil, now)
+			if tt.allowed && err != nil {
+				t.Errorf("CheckSpawn() = %v, want allowed", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrSpawnDenied) {
+				t.Errorf("CheckSpawn() = %v, want ErrSpawnDenied", err)
+			}
+		})
+	}
+}
+
+func TestCheckSpawn_CountsLiveWorkersAndHourlyRate(t *testing.T) {
+	db := newTestStorage(t).GetDB()
+	now := time.Now()
+	req := SpawnRequest{Conductor: "ops", Tool: "claude"}
+
+	var instances []*Instance
+	for i := 0; i < 2; i++ {
+		inst := NewInstance("worker", t.TempDir())
+		instances = append(instances, inst)
+		if err := RecordSpawn(db, "ops", inst, now.Add(-2*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Another conductor's worker does not count against ops
+	other := NewInstance("other", t.TempDir())
+	instances = append(instances, other)
+	if err := RecordSpawn(db, "triage", other, now); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CheckSpawn(db, SpawnPolicySettings{MaxWorkers: 2}, req, instances, now)
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, ErrSpawnDenied) {
+		t.Fatalf("at max_workers: CheckSpawn() = %v, want a QuotaError", err)
+	}
+	if quotaErr.Quota != QuotaMaxWorkers || quotaErr.Limit != 2 || quotaErr.Used != 2 {
+		t.Errorf("quota error = %+v, want max_workers 2 of 2", quotaErr)
+	}
+	// Removing a worker frees a slot
+	if err := CheckSpawn(db, SpawnPolicySettings{MaxWorkers: 2}, req, instances[1:], now); err != nil {
+		t.Errorf("after removal: CheckSpawn() = %v, want allowed", err)
+	}
+
+	// Both workers were created more than an hour ago
+	if err := CheckSpawn(db, SpawnPolicySettings{MaxPerHour: 1}, req, instances, now); err != nil {
+		t.Errorf("old spawns: CheckSpawn() = %v, want allowed", err)
+	}
+	if err := RecordSpawn(db, "ops", NewInstance("recent", t.TempDir()), now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckSpawn(db, SpawnPolicySettings{MaxPerHour: 1}, req, instances, now); !errors.Is(err, ErrSpawnDenied) {
+		t.Errorf("at max_per_hour: CheckSpawn() = %v, want ErrSpawnDenied", err)
+	}
+}
+
+func TestSpawningConductor_ResolvedFromTmuxSession(t *testing.T) {
+	conductor := NewInstance(ConductorSessionTitle("ops"), t.TempDir())
+	worker := NewInstance("worker", t.TempDir())
+	instances := []*Instance{worker, conductor}
+
+	orig := callerTmuxSession
+	t.Cleanup(func() { callerTmuxSession = orig })
+	callerTmuxSession = func() string { return conductor.GetTmuxSession().Name }
+
+	// env -u TMUX_PANE -u AGENTDECK_TITLE agent-deck add ...
+	for _, name := range []string{"TMUX_PANE", "AGENTDECK_TITLE"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	name := SpawningConductor(instances)
+	if name != "ops" {
+		t.Fatalf("SpawningConductor() = %q, want ops", name)
+	}
+	req := SpawnRequest{Conductor: name, Tool: "claude"}
+	err := CheckSpawn(nil, SpawnPolicySettings{DeniedConductors: []string{"ops"}}, req, instances, time.Now())
+	if !errors.Is(err, ErrSpawnDenied) {
+		t.Errorf("CheckSpawn() = %v, want ErrSpawnDenied", err)
+	}
+
+	// A worker cannot pose as a conductor either
+	callerTmuxSession = func() string { return worker.GetTmuxSession().Name }
+	t.Setenv("AGENTDECK_TITLE", ConductorSessionTitle("triage"))
+	if name := SpawningConductor(instances); name != "" {
+		t.Errorf("worker session: SpawningConductor() = %q, want none", name)
+	}
+
+	// Outside agent-deck's sessions the environment decides
+	callerTmuxSession = func() string { return "" }
+	if name := SpawningConductor(instances); name != "triage" {
+		t.Errorf("outside agent-deck: SpawningConductor() = %q, want triage", name)
+	}
+}
+
+func TestParentPID(t *testing.T) {
+	if got := parentPID(os.Getpid()); got != os.Getppid() {
+		t.Errorf("parentPID(self) = %d, want %d", got, os.Getppid())
+	}
+}
+
+func TestConductorQuotaUsage_CountsToday(t *testing.T) {
+	db := newTestStorage(t).GetDB()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	for _, at := range []time.Time{
+		now.Add(-30 * time.Minute),
+		now.Add(-3 * time.Hour),
+		now.Add(-24 * time.Hour), // yesterday
+	} {
+		if err := RecordSpawn(db, "ops", NewInstance("worker", t.TempDir()), at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := ConductorQuotaUsage(db, "ops", nil, now, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Workers != 0 || usage.LastHour != 1 || usage.Today != 2 {
+		t.Errorf("usage = %+v, want 0 workers, 1 last hour, 2 today", usage)
+	}
+
+	req := SpawnRequest{Conductor: "ops", Tool: "claude"}
+	err = CheckSpawn(db, SpawnPolicySettings{MaxPerDay: 2}, req, nil, now)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("at max_per_day: CheckSpawn() = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestConductorQuotaUsage_RemovedWorkerTokensCountUntilMidnight(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	storage := newTestStorage(t)
+	db := storage.GetDB()
+	now := time.Now()
+
+	worker := NewInstance("worker", t.TempDir())
+	worker.Tool = "claude"
+	worker.ClaudeSessionID = "worker-sid"
+	projectPath, err := filepath.EvalSymlinks(worker.ProjectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transcript := filepath.Join(configDir, "projects", ConvertToClaudeDirName(projectPath), "worker-sid.jsonl")
+	line := fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"usage":{"output_tokens":700}}}`+"\n",
+		now.UTC().Format(time.RFC3339Nano))
+	if err := os.MkdirAll(filepath.Dir(transcript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(transcript, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Save([]*Instance{worker}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordSpawn(db, "ops", worker, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removed along with its transcript before any quota check read it
+	if err := storage.DeleteInstance(worker.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(transcript); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := ConductorQuotaUsage(db, "ops", nil, now, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Workers != 0 || usage.TokensToday != 700 {
+		t.Errorf("usage = %+v, want 0 workers and 700 tokens today", usage)
+	}
+}
+
+func TestCheckQuotas_Tokens(t *testing.T) {
+	limits := SpawnLimits{MaxTokensPerDay: 1000}
+	if err := checkQuotas("ops", limits, QuotaUsage{TokensToday: 999}); err != nil {
+		t.Errorf("under quota: %v", err)
+	}
+	err := checkQuotas("ops", limits, QuotaUsage{TokensToday: 1500})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || quotaErr.Quota != QuotaMaxTokensPerDay || quotaErr.Used != 1500 {
+		t.Errorf("over quota: %v, want max_tokens_per_day error", err)
+	}
+}
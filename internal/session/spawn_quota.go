@@ -0,0 +1,152 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// ErrQuotaExceeded means a conductor has used up one of its [spawn] quotas.
+// QuotaErrors match both it and ErrSpawnDenied.
+var ErrQuotaExceeded = errors.New("spawn quota exceeded")
+
+// Quota names, matching their config.toml keys
+const (
+	QuotaMaxWorkers      = "max_workers"
+	QuotaMaxPerHour      = "max_per_hour"
+	QuotaMaxPerDay       = "max_per_day"
+	QuotaMaxTokensPerDay = "max_tokens_per_day"
+)
+
+// QuotaError reports which quota refused a conductor's new session
+type QuotaError struct {
+	Conductor string `json:"conductor"`
+	Quota     string `json:"quota"`
+	Limit     int    `json:"limit"`
+	Used      int    `json:"used"`
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%v: conductor %s used %d of %s = %d", ErrQuotaExceeded, e.Conductor, e.Used, e.Quota, e.Limit)
+}
+
+// Is makes errors.Is match ErrQuotaExceeded and ErrSpawnDenied
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded || target == ErrSpawnDenied
+}
+
+// QuotaUsage is what a conductor has used of its quotas
+type QuotaUsage struct {
+	Workers     int `json:"workers"`      // live sessions it created
+	LastHour    int `json:"last_hour"`    // sessions created in the last hour
+	Today       int `json:"today"`        // sessions created since midnight
+	TokensToday int `json:"tokens_today"` // tokens its Claude workers used since midnight
+}
+
+// ConductorQuotaUsage counts a conductor's usage from the spawn audit log and
+// the profile's current instances. Tokens are only counted when countTokens
+// is set, as that reads every live worker's transcript; each reading is kept
+// in the daily token tally, so workers removed during the day keep counting
+// until midnight.
+func ConductorQuotaUsage(db *statedb.StateDB, conductor string, instances []*Instance, now time.Time, countTokens bool) (QuotaUsage, error) {
+	var usage QuotaUsage
+	spawned, err := db.ListAuditByAction(statedb.AuditSpawned, conductor, time.Time{})
+	if err != nil {
+		return usage, err
+	}
+	ids := make(map[string]bool, len(spawned))
+	hourAgo, midnight := now.Add(-time.Hour), startOfDay(now)
+	for _, row := range spawned {
+		ids[row.SessionID] = true
+		if !row.Time.Before(hourAgo) {
+			usage.LastHour++
+		}
+		if !row.Time.Before(midnight) {
+			usage.Today++
+		}
+	}
+	for _, inst := range instances {
+		if !ids[inst.ID] {
+			continue
+		}
+		usage.Workers++
+		if countTokens {
+			if err := recordWorkerTokens(db, inst, now); err != nil {
+				return usage, err
+			}
+		}
+	}
+	if !countTokens {
+		return usage, nil
+	}
+	tally, err := db.ReadTokensDaily(statedb.DayKey(now))
+	if err != nil {
+		return usage, err
+	}
+	for id := range ids {
+		usage.TokensToday += tally[id]
+	}
+	return usage, nil
+}
+
+// recordWorkerTokens stores the tokens inst used since midnight in the
+// daily tally. A transcript that cannot be read leaves the tally as it was.
+func recordWorkerTokens(db *statedb.StateDB, inst *Instance, now time.Time) error {
+	path := inst.GetJSONLPath()
+	if path == "" {
+		return nil
+	}
+	tokens, err := TokensSince(path, startOfDay(now))
+	if err != nil {
+		return nil
+	}
+	return db.RecordTokensDaily(inst.ID, statedb.DayKey(now), tokens)
+}
+
+// recordRemovedWorkerTokens brings a conductor-spawned worker's daily token
+// tally up to date before its row is deleted, so everything it used today
+// counts towards the conductor's max_tokens_per_day.
+func (s *Storage) recordRemovedWorkerTokens(id string, now time.Time) {
+	if s.db == nil {
+		return
+	}
+	entries, err := s.db.ListAudit(id, 0)
+	if err != nil || !slices.ContainsFunc(entries, func(e statedb.AuditRow) bool {
+		return e.Action == statedb.AuditSpawned
+	}) {
+		return
+	}
+	instances, _, err := s.LoadLite()
+	if err != nil {
+		return
+	}
+	for _, data := range instances {
+		if data.ID == id {
+			inst := &Instance{ID: data.ID, Tool: data.Tool, ProjectPath: data.ProjectPath, ClaudeSessionID: data.ClaudeSessionID}
+			_ = recordWorkerTokens(s.db, inst, now)
+			return
+		}
+	}
+}
+
+// checkQuotas returns a QuotaError for the first quota usage has reached
+func checkQuotas(conductor string, limits SpawnLimits, usage QuotaUsage) error {
+	quotas := []struct {
+		name        string
+		limit, used int
+	}{
+		{QuotaMaxWorkers, limits.MaxWorkers, usage.Workers},
+		{QuotaMaxPerHour, limits.MaxPerHour, usage.LastHour},
+		{QuotaMaxPerDay, limits.MaxPerDay, usage.Today},
+		{QuotaMaxTokensPerDay, limits.MaxTokensPerDay, usage.TokensToday},
+	}
+	for _, q := range quotas {
+		if q.limit > 0 && q.used >= q.limit {
+			return &QuotaError{Conductor: conductor, Quota: q.name, Limit: q.limit, Used: q.used}
+		}
+	}
+	return nil
+}
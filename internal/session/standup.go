@@ -0,0 +1,360 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StandupSettings configures the cross-conductor standup
+// ([conductor.standup]). It is compiled from each conductor's task-log.md and
+// state.json and posted to every configured sink.
+type StandupSettings struct {
+	// Schedule is a cron string for when the standup is posted, e.g.
+	// "0 9 * * 1-5". Heartbeats post it on their first run after each time,
+	// so it goes out up to one heartbeat interval late. Empty disables it.
+	Schedule string `toml:"schedule"`
+
+	// Hours is how far back task-log.md entries count as done. Default: 24
+	Hours int `toml:"hours"`
+
+	// WebhookURL receives a JSON POST {"text": "..."} (Slack incoming
+	// webhooks and most chat bridges accept this)
+	WebhookURL string `toml:"webhook_url"`
+
+	// Command is run with sh -c and the standup on stdin
+	Command string `toml:"command"`
+
+	// Slack and Telegram post with the bridge's [conductor.slack] and
+	// [conductor.telegram] credentials
+	Slack    bool `toml:"slack"`
+	Telegram bool `toml:"telegram"`
+
+	// Summarize posts a condensed standup from the [summarizer] backend
+	// instead of the full done/doing/blocked lists
+	Summarize bool `toml:"summarize"`
+}
+
+// GetHours returns the done window, defaulting to 24 hours
+func (s StandupSettings) GetHours() int {
+	if s.Hours <= 0 {
+		return 24
+	}
+	return s.Hours
+}
+
+// HasSink reports whether the standup has anywhere to go
+func (s StandupSettings) HasSink() bool {
+	return s.WebhookURL != "" || s.Command != "" || s.Slack || s.Telegram
+}
+
+// maxStandupItems caps each section per conductor
+const maxStandupItems = 10
+
+// ConductorStandup is one conductor's part of the standup
+type ConductorStandup struct {
+	Name    string   `json:"name"`
+	Profile string   `json:"profile"`
+	Done    []string `json:"done"`    // task-log.md bullets in the window
+	Doing   []string `json:"doing"`   // state.json session summaries
+	Blocked []string `json:"blocked"` // escalated sessions and NEED: lines
+}
+
+// Standup is the compiled cross-conductor standup
+type Standup struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Since       time.Time          `json:"since"`
+	Conductors  []ConductorStandup `json:"conductors"`
+}
+
+// BuildStandup compiles the standup for conductors from their memory files,
+// counting task-log.md entries since now minus hours as done
+func BuildStandup(conductors []ConductorMeta, hours int, now time.Time) Standup {
+	standup := Standup{
+		GeneratedAt: now,
+		Since:       now.Add(-time.Duration(hours) * time.Hour),
+		Conductors:  make([]ConductorStandup, 0, len(conductors)),
+	}
+	for _, meta := range conductors {
+		cs := ConductorStandup{Name: meta.Name, Profile: meta.Profile}
+		if dir, err := ConductorNameDir(meta.Name); err == nil {
+			if data, err := os.ReadFile(filepath.Join(dir, "task-log.md")); err == nil {
+				cs.Done, cs.Blocked = parseTaskLog(string(data), standup.Since, now)
+			}
+			if data, err := os.ReadFile(filepath.Join(dir, "state.json")); err == nil {
+				doing, blocked := parseConductorState(data)
+				cs.Doing = doing
+				cs.Blocked = append(blocked, cs.Blocked...)
+			}
+		}
+		cs.Done = capItems(cs.Done)
+		cs.Doing = capItems(cs.Doing)
+		cs.Blocked = capItems(cs.Blocked)
+		standup.Conductors = append(standup.Conductors, cs)
+	}
+	return standup
+}
+
+// parseTaskLog returns the bullets of "## YYYY-MM-DD HH:MM - Title" entries
+// logged in [since, now], and the NEED: lines among them
+func parseTaskLog(log string, since, now time.Time) (done, blocked []string) {
+	inWindow := false
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			stamp, _, _ := strings.Cut(heading, " - ")
+			at, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(stamp), now.Location())
+			inWindow = err == nil && !at.Before(since) && !at.After(now)
+			continue
+		}
+		if !inWindow {
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok || item == "" {
+			continue
+		}
+		if need, ok := strings.CutPrefix(item, "NEED:"); ok {
+			blocked = append(blocked, strings.TrimSpace(need))
+			continue
+		}
+		done = append(done, item)
+	}
+	return done, blocked
+}
+
+// parseConductorState reads the session summaries from a conductor's
+// state.json: escalated sessions are blocked, the rest in progress
+func parseConductorState(data []byte) (doing, blocked []string) {
+	var state struct {
+		Sessions map[string]struct {
+			Title     string `json:"title"`
+			Summary   string `json:"summary"`
+			Escalated bool   `json:"escalated"`
+		} `json:"sessions"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(state.Sessions))
+	for id := range state.Sessions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return state.Sessions[ids[i]].Title < state.Sessions[ids[j]].Title
+	})
+	for _, id := range ids {
+		s := state.Sessions[id]
+		if s.Summary == "" {
+			continue
+		}
+		title := s.Title
+		if title == "" {
+			title = id
+		}
+		item := title + ": " + s.Summary
+		if s.Escalated {
+			blocked = append(blocked, item)
+		} else {
+			doing = append(doing, item)
+		}
+	}
+	return doing, blocked
+}
+
+func capItems(items []string) []string {
+	if len(items) > maxStandupItems {
+		more := len(items) - maxStandupItems
+		items = append(items[:maxStandupItems:maxStandupItems], fmt.Sprintf("... and %d more", more))
+	}
+	return items
+}
+
+// Markdown renders the standup for chat sinks and the terminal
+func (s Standup) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Standup* %s\n", s.GeneratedAt.Format("Mon 2006-01-02"))
+	if len(s.Conductors) == 0 {
+		b.WriteString("\nNo conductors.\n")
+	}
+	for _, cs := range s.Conductors {
+		fmt.Fprintf(&b, "\n*%s* [%s]\n", cs.Name, cs.Profile)
+		if len(cs.Done)+len(cs.Doing)+len(cs.Blocked) == 0 {
+			b.WriteString("  nothing to report\n")
+			continue
+		}
+		for _, section := range []struct {
+			label string
+			items []string
+		}{{"Done", cs.Done}, {"Doing", cs.Doing}, {"Blocked", cs.Blocked}} {
+			if len(section.items) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "  %s:\n", section.label)
+			for _, item := range section.items {
+				fmt.Fprintf(&b, "  - %s\n", item)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Sink endpoints; tests point them at a local server
+var (
+	slackAPIURL    = "https://slack.com/api/chat.postMessage"
+	telegramAPIURL = "https://api.telegram.org"
+)
+
+// PostStandup sends text to every sink in settings and returns the errors
+// of those that failed
+func PostStandup(settings StandupSettings, conductor ConductorSettings, text string) error {
+	var errs []error
+	if settings.WebhookURL != "" {
+		if err := postJSON(settings.WebhookURL, "", map[string]string{"text": text}); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if settings.Slack {
+		if conductor.Slack.BotToken == "" || conductor.Slack.ChannelID == "" {
+			errs = append(errs, errors.New("slack: [conductor.slack] bot_token and channel_id are required"))
+		} else if err := postJSON(slackAPIURL, conductor.Slack.BotToken, map[string]string{
+			"channel": conductor.Slack.ChannelID,
+			"text":    text,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("slack: %w", err))
+		}
+	}
+	if settings.Telegram {
+		if conductor.Telegram.Token == "" || conductor.Telegram.UserID == 0 {
+			errs = append(errs, errors.New("telegram: [conductor.telegram] token and user_id are required"))
+		} else if err := postJSON(telegramAPIURL+"/bot"+conductor.Telegram.Token+"/sendMessage", "", map[string]string{
+			"chat_id": strconv.FormatInt(conductor.Telegram.UserID, 10),
+			"text":    text,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("telegram: %w", err))
+		}
+	}
+	if settings.Command != "" {
+		cmd := exec.Command("sh", "-c", settings.Command)
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Errorf("command: %w: %s", err, strings.TrimSpace(string(out))))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// postJSON POSTs body as JSON and fails on a non-2xx status or a Slack/
+// Telegram style {"ok": false} reply
+func postJSON(url, bearer string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var reply struct {
+		OK          *bool  `json:"ok"`
+		Error       string `json:"error"`
+		Description string `json:"description"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&reply)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if reply.OK != nil && !*reply.OK {
+		return fmt.Errorf("%s%s", reply.Error, reply.Description)
+	}
+	return nil
+}
+
+// standupStampPath records when the standup was last posted
+func standupStampPath() (string, error) {
+	dir, err := ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "standup.last"), nil
+}
+
+// LastStandup returns when the standup was last posted (zero if never)
+func LastStandup() time.Time {
+	path, err := standupStampPath()
+	if err != nil {
+		return time.Time{}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}
+	}
+	at, _ := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	return at
+}
+
+// StandupDue reports whether the schedule fired since the last standup.
+// Without a previous standup only the last hour is considered, so enabling
+// it does not post straight away for a time long past.
+func StandupDue(schedule string, last, now time.Time) (bool, error) {
+	sched, err := ParseHeartbeatSchedule(schedule)
+	if err != nil {
+		return false, err
+	}
+	if last.IsZero() {
+		last = now.Add(-time.Hour)
+	}
+	return sched.FiredBetween(last, now)
+}
+
+// ClaimStandup records now as the last standup unless another process
+// claimed one since last; heartbeats of several conductors may race to
+// post the same standup, and only the claim holder posts.
+func ClaimStandup(last, now time.Time) (bool, error) {
+	path, err := standupStampPath()
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, err
+	}
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		if os.IsExist(err) {
+			// A stale lock (a crashed post) is taken over after a minute
+			if info, statErr := os.Stat(path + ".lock"); statErr == nil && now.Sub(info.ModTime()) > time.Minute {
+				_ = os.Remove(path + ".lock")
+			}
+			return false, nil
+		}
+		return false, err
+	}
+	_ = lock.Close()
+	defer os.Remove(path + ".lock")
+
+	if !LastStandup().Equal(last) {
+		return false, nil
+	}
+	return true, os.WriteFile(path, []byte(now.UTC().Format(time.RFC3339)+"\n"), 0o644)
+}
@@ -0,0 +1,157 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildStandup_FromTaskLogAndState(t *testing.T) {
+	writeMacroConfig(t, "")
+	dir, err := ConductorNameDir("ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	taskLog := `# Task log
+
+## 2026-03-08 10:00 - Heartbeat
+- Too old to count
+
+## 2026-03-09 15:30 - Heartbeat
+- Auto-responded to frontend
+- NEED: decision on the staging database
+
+## 2026-03-10 08:45 - User Message
+- Summarised api-server for the user
+`
+	state := `{"sessions": {
+		"b": {"title": "frontend", "summary": "Building the auth flow"},
+		"a": {"title": "api-fix", "summary": "Waiting on test env", "escalated": true},
+		"c": {"title": "idle"}
+	}}`
+	if err := os.WriteFile(filepath.Join(dir, "task-log.md"), []byte(taskLog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "state.json"), []byte(state), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	standup := BuildStandup([]ConductorMeta{{Name: "ops", Profile: "work"}, {Name: "new", Profile: "default"}}, 24, now)
+	if len(standup.Conductors) != 2 {
+		t.Fatalf("got %d conductors, want 2", len(standup.Conductors))
+	}
+	ops := standup.Conductors[0]
+	wantDone := []string{"Auto-responded to frontend", "Summarised api-server for the user"}
+	if strings.Join(ops.Done, "|") != strings.Join(wantDone, "|") {
+		t.Errorf("done = %q, want %q", ops.Done, wantDone)
+	}
+	if len(ops.Doing) != 1 || ops.Doing[0] != "frontend: Building the auth flow" {
+		t.Errorf("doing = %q", ops.Doing)
+	}
+	wantBlocked := []string{"api-fix: Waiting on test env", "decision on the staging database"}
+	if strings.Join(ops.Blocked, "|") != strings.Join(wantBlocked, "|") {
+		t.Errorf("blocked = %q, want %q", ops.Blocked, wantBlocked)
+	}
+
+	text := standup.Markdown()
+	for _, want := range []string{"*ops* [work]", "  Blocked:\n  - api-fix", "*new* [default]\n  nothing to report"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("markdown missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestStandupDue_AndClaim(t *testing.T) {
+	writeMacroConfig(t, "")
+	monday9 := time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		last, now time.Time
+		want      bool
+	}{
+		{"first run just after", time.Time{}, monday9.Add(10 * time.Minute), true},
+		{"first run long after", time.Time{}, monday9.Add(3 * time.Hour), false},
+		{"already posted", monday9.Add(5 * time.Minute), monday9.Add(20 * time.Minute), false},
+		{"next day", monday9.Add(5 * time.Minute), monday9.Add(24*time.Hour + 15*time.Minute), true},
+		{"weekend", monday9.Add(4*24*time.Hour + 5*time.Minute), monday9.Add(6*24*time.Hour + 15*time.Minute), false},
+	}
+	for _, tt := range tests {
+		due, err := StandupDue("0 9 * * 1-5", tt.last, tt.now)
+		if err != nil || due != tt.want {
+			t.Errorf("%s: StandupDue() = %v, %v; want %v", tt.name, due, err, tt.want)
+		}
+	}
+
+	now := monday9.Add(10 * time.Minute)
+	if claimed, err := ClaimStandup(time.Time{}, now); err != nil || !claimed {
+		t.Fatalf("first claim = %v, %v", claimed, err)
+	}
+	// A heartbeat that read the old stamp loses
+	if claimed, _ := ClaimStandup(time.Time{}, now); claimed {
+		t.Error("second claim of the same standup should fail")
+	}
+	if !LastStandup().Equal(now.Truncate(time.Second)) {
+		t.Errorf("LastStandup() = %v, want %v", LastStandup(), now)
+	}
+}
+
+func TestPostStandup_Sinks(t *testing.T) {
+	var got []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		body["auth"] = r.Header.Get("Authorization")
+		body["path"] = r.URL.Path
+		got = append(got, body)
+		if body["channel"] == "C-bad" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+	oldSlack, oldTelegram := slackAPIURL, telegramAPIURL
+	slackAPIURL, telegramAPIURL = server.URL+"/slack", server.URL
+	defer func() { slackAPIURL, telegramAPIURL = oldSlack, oldTelegram }()
+
+	out := filepath.Join(t.TempDir(), "standup.txt")
+	settings := StandupSettings{WebhookURL: server.URL + "/hook", Slack: true, Telegram: true, Command: "cat > " + out}
+	conductor := ConductorSettings{
+		Slack:    SlackSettings{BotToken: "xoxb-1", ChannelID: "C1"},
+		Telegram: TelegramSettings{Token: "T", UserID: 42},
+	}
+	if err := PostStandup(settings, conductor, "standup text"); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d requests, want webhook, slack and telegram", len(got))
+	}
+	if got[0]["path"] != "/hook" || got[0]["text"] != "standup text" {
+		t.Errorf("webhook request = %v", got[0])
+	}
+	if got[1]["auth"] != "Bearer xoxb-1" || got[1]["channel"] != "C1" {
+		t.Errorf("slack request = %v", got[1])
+	}
+	if got[2]["path"] != "/botT/sendMessage" || got[2]["chat_id"] != "42" {
+		t.Errorf("telegram request = %v", got[2])
+	}
+	if data, _ := os.ReadFile(out); string(data) != "standup text" {
+		t.Errorf("command got %q", data)
+	}
+
+	conductor.Slack.ChannelID = "C-bad"
+	err := PostStandup(StandupSettings{Slack: true}, conductor, "x")
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("slack error = %v, want channel_not_found", err)
+	}
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// StateHistorySettings configures the periodic deck state snapshots behind
+// "agent-deck status --at" ([state_history])
+type StateHistorySettings struct {
+	// Interval is the minutes between snapshots while the TUI runs; an
+	// unchanged deck is only re-recorded daily. Default: 5. Negative disables.
+	Interval int `toml:"interval"`
+
+	// KeepDays is how long snapshots are kept. Default: 14
+	KeepDays int `toml:"keep_days"`
+}
+
+// GetStateHistorySettings returns [state_history] from config.toml
+func GetStateHistorySettings() StateHistorySettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return StateHistorySettings{}
+	}
+	return config.StateHistory
+}
+
+// GetInterval returns the snapshot interval, zero when disabled
+func (s StateHistorySettings) GetInterval() time.Duration {
+	switch {
+	case s.Interval < 0:
+		return 0
+	case s.Interval == 0:
+		return 5 * time.Minute
+	}
+	return time.Duration(s.Interval) * time.Minute
+}
+
+// GetKeepDays returns the snapshot retention, defaulting to 14 days
+func (s StateHistorySettings) GetKeepDays() int {
+	if s.KeepDays <= 0 {
+		return 14
+	}
+	return s.KeepDays
+}
+
+// unchangedSnapshotRefresh re-records an unchanged deck so the latest
+// snapshot is never pruned away
+const unchangedSnapshotRefresh = 24 * time.Hour
+
+// RecordStateSnapshot records the instances' statuses as the deck state at
+// now, unless they match the latest snapshot, and prunes snapshots past
+// [state_history] keep_days. It reports whether a snapshot was written.
+func RecordStateSnapshot(db *statedb.StateDB, instances []*Instance, now time.Time) (bool, error) {
+	sessions := make([]statedb.SnapshotSession, 0, len(instances))
+	for _, inst := range instances {
+		sessions = append(sessions, statedb.SnapshotSession{
+			SessionID: inst.ID,
+			Title:     inst.Title,
+			Tool:      inst.Tool,
+			Group:     inst.GroupPath,
+			Status:    string(inst.GetStatusThreadSafe()),
+		})
+	}
+	sortSnapshotSessions(sessions)
+
+	last, err := db.SnapshotAt(now)
+	if err != nil {
+		return false, err
+	}
+	if last != nil && now.Sub(last.Time) < unchangedSnapshotRefresh {
+		previous := slices.Clone(last.Sessions)
+		sortSnapshotSessions(previous)
+		if slices.Equal(previous, sessions) {
+			return false, nil
+		}
+	}
+	if err := db.WriteSnapshot(now, sessions); err != nil {
+		return false, err
+	}
+	settings := GetStateHistorySettings()
+	return true, db.PruneSnapshots(now.AddDate(0, 0, -settings.GetKeepDays()))
+}
+
+func sortSnapshotSessions(sessions []statedb.SnapshotSession) {
+	slices.SortFunc(sessions, func(a, b statedb.SnapshotSession) int {
+		return strings.Compare(a.SessionID, b.SessionID)
+	})
+}
+
+// DeckState answers what the deck looked like at a point in time: the latest
+// snapshot before it, and the audit entries (creations, removals) recorded
+// between that snapshot and the time asked about
+type DeckState struct {
+	At       time.Time          `json:"at"`
+	Snapshot *statedb.Snapshot  `json:"snapshot"`
+	Changes  []statedb.AuditRow `json:"changes,omitempty"`
+}
+
+// StateAt returns the deck state at at. Snapshot is nil when none was taken
+// before it.
+func StateAt(db *statedb.StateDB, at time.Time) (*DeckState, error) {
+	snapshot, err := db.SnapshotAt(at)
+	if err != nil {
+		return nil, err
+	}
+	state := &DeckState{At: at, Snapshot: snapshot}
+	if snapshot != nil {
+		if state.Changes, err = db.ListAuditBetween(snapshot.Time, at); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// ParseStateTime parses the time for a state query: RFC3339,
+// "YYYY-MM-DD HH:MM", "HH:MM" (its latest occurrence before now) or a
+// duration ago ("90m", "2h")
+func ParseStateTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if clock, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if t.After(now) {
+			t = t.AddDate(0, 0, -1)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected RFC3339, YYYY-MM-DD HH:MM, HH:MM or a duration ago (2h)", s)
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordStateSnapshot_AndStateAt(t *testing.T) {
+	db := newTestStorage(t).GetDB()
+	base := time.Date(2026, 3, 10, 2, 0, 0, 0, time.Local)
+
+	build := NewInstance("build", t.TempDir())
+	build.Status = StatusRunning
+	instances := []*Instance{build}
+
+	if wrote, err := RecordStateSnapshot(db, instances, base); err != nil || !wrote {
+		t.Fatalf("first snapshot = %v, %v", wrote, err)
+	}
+	// Unchanged deck: nothing new recorded
+	if wrote, _ := RecordStateSnapshot(db, instances, base.Add(5*time.Minute)); wrote {
+		t.Error("unchanged deck should not be re-recorded")
+	}
+	if err := db.AppendAudit("x", "hotfix", "created", "manual", base.Add(7*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	build.Status = StatusError
+	if wrote, _ := RecordStateSnapshot(db, instances, base.Add(10*time.Minute)); !wrote {
+		t.Error("status change should be recorded")
+	}
+
+	state, err := StateAt(db, base.Add(8*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Snapshot == nil || len(state.Snapshot.Sessions) != 1 || state.Snapshot.Sessions[0].Status != string(StatusRunning) {
+		t.Fatalf("state at 02:08 = %+v, want build running", state.Snapshot)
+	}
+	if len(state.Changes) != 1 || state.Changes[0].Title != "hotfix" {
+		t.Errorf("changes = %+v, want the hotfix creation", state.Changes)
+	}
+	if state, _ := StateAt(db, base.Add(11*time.Minute)); state.Snapshot.Sessions[0].Status != string(StatusError) {
+		t.Errorf("state at 02:11 = %+v, want build in error", state.Snapshot)
+	}
+}
+
+func TestParseStateTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 30, 0, 0, time.Local)
+	tests := map[string]time.Time{
+		"03:00":            time.Date(2026, 3, 10, 3, 0, 0, 0, time.Local),
+		"23:15":            time.Date(2026, 3, 9, 23, 15, 0, 0, time.Local),
+		"2h":               now.Add(-2 * time.Hour),
+		"2026-03-08 14:00": time.Date(2026, 3, 8, 14, 0, 0, 0, time.Local),
+	}
+	for in, want := range tests {
+		if got, err := ParseStateTime(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("ParseStateTime(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseStateTime("yesterday", now); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// ClassifierSettings configures an external status classifier for a tool
+// whose state busy/prompt patterns cannot detect:
+//
+//	[tools.mytool.classifier]
+//	command = "~/bin/mytool-status"
+//	mode = "augment"
+//
+// A profile can set or replace one per tool in
+// [profiles.<name>.classifiers.<tool>]. See tmux.StatusClassifier for the
+// program's input and output.
+type ClassifierSettings struct {
+	// Command runs with sh -c and reads the pane text on stdin
+	Command string `toml:"command"`
+
+	// Mode is "override" (the classifier decides whenever it has an opinion)
+	// or "augment" (asked only when no pattern matches). Default: override
+	Mode string `toml:"mode"`
+
+	// Timeout bounds each run, in milliseconds. Default: 1000
+	Timeout int `toml:"timeout"`
+}
+
+// validate checks the mode
+func (c ClassifierSettings) validate() error {
+	switch c.Mode {
+	case "", tmux.ClassifierOverride, tmux.ClassifierAugment:
+		return nil
+	}
+	return fmt.Errorf("classifier mode %q must be %q or %q", c.Mode, tmux.ClassifierOverride, tmux.ClassifierAugment)
+}
+
+// ToolClassifier returns the status classifier for a tool in the current
+// profile: its [profiles.<profile>.classifiers] entry, else the tool's
+// [tools.<name>.classifier], or nil if neither sets a command
+func ToolClassifier(toolName string) *tmux.StatusClassifier {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil || toolName == "" {
+		return nil
+	}
+	var settings ClassifierSettings
+	if profileCfg, ok := config.Profiles[GetEffectiveProfile("")]; ok && profileCfg.Classifiers[toolName].Command != "" {
+		settings = profileCfg.Classifiers[toolName]
+	} else if toolDef, ok := config.Tools[toolName]; ok {
+		settings = toolDef.Classifier
+	}
+	classifier := &tmux.StatusClassifier{
+		Command: settings.Command,
+		Mode:    settings.Mode,
+		Timeout: time.Duration(settings.Timeout) * time.Millisecond,
+		Tool:    toolName,
+	}
+	if classifier.IsZero() {
+		return nil
+	}
+	return classifier
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func TestToolClassifierProfileOverride(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENTDECK_PROFILE", "work")
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	config := `
+[tools.mytool]
+command = "mytool"
+
+[tools.mytool.classifier]
+command = "mytool-status"
+mode = "augment"
+
+[tools.other.classifier]
+command = "other-status"
+
+[profiles.work.classifiers.other]
+command = "work-status"
+timeout = 250
+`
+	dir := filepath.Join(home, ".agent-deck")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := ToolClassifier("mytool")
+	if c == nil || c.Command != "mytool-status" || c.Mode != tmux.ClassifierAugment {
+		t.Errorf("mytool classifier = %+v", c)
+	}
+	c = ToolClassifier("other")
+	if c == nil || c.Command != "work-status" || c.Timeout.Milliseconds() != 250 {
+		t.Errorf("profile classifier = %+v, want work-status", c)
+	}
+	if c := ToolClassifier("claude"); c != nil {
+		t.Errorf("unconfigured tool classifier = %+v, want nil", c)
+	}
+}
+
+func TestValidateClassifierMode(t *testing.T) {
+	if err := (ClassifierSettings{Command: "x", Mode: "sometimes"}).validate(); err == nil {
+		t.Error("unknown mode accepted")
+	}
+	if err := (ClassifierSettings{Command: "x", Mode: "override"}).validate(); err != nil {
+		t.Errorf("override rejected: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package session
+
+// applyStatusDwellLocked applies hysteresis to a status detected from pane
+// content. A change to running, waiting or idle is only reported once it has
+// been detected on DwellFor(status) consecutive polls; until then the previous
+// status is kept. Errors and startup are reported immediately, as are changes
+// away from them. Caller holds i.mu.
+func (i *Instance) applyStatusDwellLocked(detected Status, settings StatusSettings) Status {
+	if detected == i.Status || !dwellApplies(detected) || !dwellApplies(i.Status) {
+		i.pendingStatus = ""
+		i.pendingPolls = 0
+		return detected
+	}
+
+	required := settings.DwellFor(detected)
+	if required <= 1 {
+		i.pendingStatus = ""
+		i.pendingPolls = 0
+		return detected
+	}
+
+	if i.pendingStatus == detected {
+		i.pendingPolls++
+	} else {
+		i.pendingStatus = detected
+		i.pendingPolls = 1
+	}
+	if i.pendingPolls >= required {
+		i.pendingStatus = ""
+		i.pendingPolls = 0
+		return detected
+	}
+	return i.Status
+}
+
+// dwellApplies reports whether a status participates in hysteresis.
+func dwellApplies(s Status) bool {
+	return s == StatusRunning || s == StatusWaiting || s == StatusIdle || s == StatusThrottled
+}
@@ -0,0 +1,51 @@
+package session
+
+import "testing"
+
+func TestApplyStatusDwell(t *testing.T) {
+	settings := StatusSettings{DwellPolls: 3, StateDwellPolls: map[string]int{"running": 1}}
+	inst := &Instance{Status: StatusRunning}
+
+	// waiting needs three consecutive polls
+	for poll := 1; poll <= 2; poll++ {
+		if got := inst.applyStatusDwellLocked(StatusWaiting, settings); got != StatusRunning {
+			t.Fatalf("poll %d: status flipped early to %s", poll, got)
+		}
+	}
+	if got := inst.applyStatusDwellLocked(StatusWaiting, settings); got != StatusWaiting {
+		t.Fatalf("third poll should report waiting, got %s", got)
+	}
+	inst.Status = StatusWaiting
+
+	// A flap back resets the candidate
+	inst.applyStatusDwellLocked(StatusIdle, settings)
+	inst.applyStatusDwellLocked(StatusWaiting, settings)
+	if inst.pendingPolls != 0 {
+		t.Errorf("returning to the current status should clear the candidate")
+	}
+	inst.applyStatusDwellLocked(StatusIdle, settings)
+	if got := inst.applyStatusDwellLocked(StatusIdle, settings); got != StatusWaiting {
+		t.Errorf("idle should need a fresh run of 3 polls, got %s", got)
+	}
+
+	// running overrides to 1: immediate
+	if got := inst.applyStatusDwellLocked(StatusRunning, settings); got != StatusRunning {
+		t.Errorf("running should switch immediately, got %s", got)
+	}
+}
+
+func TestApplyStatusDwellSkipsErrorAndStartup(t *testing.T) {
+	settings := StatusSettings{DwellPolls: 5}
+
+	inst := &Instance{Status: StatusRunning}
+	if got := inst.applyStatusDwellLocked(StatusError, settings); got != StatusError {
+		t.Errorf("error should be reported immediately, got %s", got)
+	}
+	inst = &Instance{Status: StatusStarting}
+	if got := inst.applyStatusDwellLocked(StatusWaiting, settings); got != StatusWaiting {
+		t.Errorf("leaving startup should not dwell, got %s", got)
+	}
+	if got := (StatusSettings{}).DwellFor(StatusIdle); got != 1 {
+		t.Errorf("default dwell = %d, want 1", got)
+	}
+}
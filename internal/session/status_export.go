@@ -0,0 +1,148 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
+)
+
+// StatusExportSettings configures the static status snapshot written by
+// "agent-deck status export" and the conductor bridge
+type StatusExportSettings struct {
+	// Path is where the JSON snapshot is written, e.g. a directory served by
+	// a web server or synced by Dropbox. "~/" and $VARS are expanded.
+	// Empty disables it.
+	Path string `toml:"path"`
+
+	// Interval is how often the bridge rewrites the snapshot, in seconds.
+	// Default: 60
+	Interval int `toml:"interval"`
+
+	// HideSessions leaves out per-session rows (titles, tools), exporting only
+	// status counts. Default: false
+	HideSessions bool `toml:"hide_sessions"`
+}
+
+// GetInterval returns the export interval, defaulting to one minute
+func (s StatusExportSettings) GetInterval() time.Duration {
+	if s.Interval <= 0 {
+		return time.Minute
+	}
+	return time.Duration(s.Interval) * time.Second
+}
+
+// GetStatusExportSettings returns [status_export] from config.toml
+func GetStatusExportSettings() StatusExportSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return StatusExportSettings{}
+	}
+	config.StatusExport.Path = ExpandPath(config.StatusExport.Path)
+	return config.StatusExport
+}
+
+// StatusExport is the compact deck status snapshot
+type StatusExport struct {
+	GeneratedAt time.Time             `json:"generated_at"`
+	Deck        string                `json:"deck,omitempty"`
+	Host        string                `json:"host,omitempty"`
+	Totals      map[string]int        `json:"totals"`
+	Profiles    []StatusExportProfile `json:"profiles"`
+	Maintenance *ActiveMaintenance    `json:"maintenance,omitempty"`
+}
+
+// StatusExportProfile is one profile's share of the snapshot
+type StatusExportProfile struct {
+	Profile  string                `json:"profile"`
+	Counts   map[string]int        `json:"counts"`
+	Sessions []StatusExportSession `json:"sessions,omitempty"`
+}
+
+// StatusExportSession is one session row of the snapshot
+type StatusExportSession struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Tool      string    `json:"tool,omitempty"`
+	Group     string    `json:"group,omitempty"`
+	Status    string    `json:"status"`
+	Since     time.Time `json:"since,omitzero"`
+	Conductor bool      `json:"conductor,omitempty"`
+}
+
+// NewStatusExport starts an empty snapshot of the current deck
+func NewStatusExport(now time.Time) *StatusExport {
+	export := &StatusExport{
+		GeneratedAt: now.UTC(),
+		Deck:        deck.Name(),
+		Totals:      make(map[string]int),
+		Profiles:    []StatusExportProfile{},
+		Maintenance: ActiveMaintenanceWindow(now),
+	}
+	export.Host, _ = os.Hostname()
+	return export
+}
+
+// AddProfile adds a profile's instances, whose statuses must already be
+// refreshed. With hideSessions only the counts are kept.
+func (e *StatusExport) AddProfile(profile string, instances []*Instance, hideSessions bool) {
+	p := StatusExportProfile{Profile: profile, Counts: make(map[string]int)}
+	for _, inst := range instances {
+		status := string(inst.GetStatusThreadSafe())
+		p.Counts[status]++
+		e.Totals[status]++
+		if hideSessions {
+			continue
+		}
+		_, isConductor := ConductorNameFromTitle(inst.Title)
+		p.Sessions = append(p.Sessions, StatusExportSession{
+			ID:        inst.ID,
+			Title:     inst.Title,
+			Tool:      inst.Tool,
+			Group:     inst.GroupPath,
+			Status:    status,
+			Since:     inst.StateSince().UTC(),
+			Conductor: isConductor,
+		})
+	}
+	sort.Slice(p.Sessions, func(i, j int) bool {
+		return p.Sessions[i].Title < p.Sessions[j].Title
+	})
+	e.Profiles = append(e.Profiles, p)
+}
+
+// WriteStatusExport atomically writes the snapshot as compact JSON, so a web
+// server or sync client never picks up a half-written file
+func WriteStatusExport(path string, export *StatusExport) error {
+	if path == "" {
+		return fmt.Errorf("no status export path")
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		return fmt.Errorf("marshal status export: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create status export dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write status export: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write status export: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write status export: %w", err)
+	}
+	// CreateTemp makes the file 0600; the snapshot is meant to be shared
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("write status export: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatusExport(t *testing.T) {
+	writeMacroConfig(t, `
+[status_export]
+path = "~/public/deck.json"
+`)
+	settings := GetStatusExportSettings()
+	home, _ := os.UserHomeDir()
+	if want := filepath.Join(home, "public", "deck.json"); settings.Path != want {
+		t.Fatalf("Path = %q, want %q", settings.Path, want)
+	}
+	if settings.GetInterval() != time.Minute {
+		t.Errorf("default interval = %v", settings.GetInterval())
+	}
+
+	now := time.Now()
+	instances := []*Instance{
+		{ID: "c1", Title: ConductorSessionTitle("ops"), Tool: "claude", Status: StatusRunning},
+		{ID: "s1", Title: "api", Tool: "codex", Status: StatusWaiting, GroupPath: "work"},
+		{ID: "s2", Title: "web", Tool: "claude", Status: StatusWaiting},
+	}
+	export := NewStatusExport(now)
+	export.AddProfile("default", instances, false)
+	export.AddProfile("private", instances[1:], true)
+
+	if export.Totals["waiting"] != 4 || export.Totals["running"] != 1 {
+		t.Errorf("Totals = %v", export.Totals)
+	}
+	if rows := export.Profiles[0].Sessions; len(rows) != 3 || rows[0].Title != "api" || !rows[1].Conductor {
+		t.Errorf("sessions = %+v", rows)
+	}
+	if export.Profiles[1].Sessions != nil || export.Profiles[1].Counts["waiting"] != 2 {
+		t.Errorf("hidden profile = %+v", export.Profiles[1])
+	}
+
+	if err := WriteStatusExport(settings.Path, export); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(settings.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded StatusExport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("snapshot is not JSON: %v", err)
+	}
+	if len(decoded.Profiles) != 2 || !decoded.GeneratedAt.Equal(now.UTC().Truncate(0)) {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if info, _ := os.Stat(settings.Path); info.Mode().Perm() != 0o644 {
+		t.Errorf("mode = %v, want 0644", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(settings.Path)); len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,43 @@
+package session
+
+import "github.com/asheshgoplani/agent-deck/internal/tmux"
+
+// StatusFusionSettings configures status signal fusion:
+//
+//	[status.fusion]
+//	enabled = true
+//	cpu_busy_percent = 20
+//	weights = { cpu = 1.0, activity = 0 }
+//
+// See tmux.StatusFusion for how the signals are combined.
+type StatusFusionSettings struct {
+	// Enabled turns fusion on. Default: false
+	Enabled bool `toml:"enabled"`
+
+	// Threshold is the weighted score, from -1 (quiet) to 1 (busy), at which
+	// a session counts as running. Default: 0.3
+	Threshold float64 `toml:"threshold"`
+
+	// CPUBusyPercent is the CPU use of the pane's processes, in percent of
+	// one core, that counts as busy. Default: 10
+	CPUBusyPercent float64 `toml:"cpu_busy_percent"`
+
+	// Weights per signal: patterns, cpu, output, activity. Unset signals
+	// keep their defaults (1.0, 0.8, 0.4, 0.2); 0 disables one and other
+	// keys are ignored.
+	Weights map[string]float64 `toml:"weights"`
+}
+
+// StatusFusion returns the status fusion settings for tmux sessions, or nil
+// when fusion is disabled
+func StatusFusion() *tmux.StatusFusion {
+	settings := GetStatusSettings().Fusion
+	if !settings.Enabled {
+		return nil
+	}
+	return &tmux.StatusFusion{
+		Weights:        settings.Weights,
+		Threshold:      settings.Threshold,
+		CPUBusyPercent: settings.CPUBusyPercent,
+	}
+}
@@ -81,6 +81,13 @@ type InstanceData struct {
 
 	// MCP tracking (persisted for sync status display)
 	LoadedMCPNames []string `json:"loaded_mcp_names,omitempty"`
+
+	// Why the session was created (see StartReason)
+	StartReason string `json:"start_reason,omitempty"`
+	// Network policy for the agent process (see NetworkPolicy)
+	NetworkPolicy string `json:"network_policy,omitempty"`
+	// Feature flag overrides (see Features)
+	Features map[string]bool `json:"features,omitempty"`
 }
 
 // GroupData represents serializable group data
@@ -92,6 +99,24 @@ type GroupData struct {
 	DefaultPath string `json:"default_path,omitempty"`
 }
 
+// Store persists sessions and groups for one profile. The CLI, the TUI and
+// the web/conductor daemons each hold their own Store on the same backend,
+// so implementations must tolerate concurrent writers in other processes.
+type Store interface {
+	Profile() string
+	Path() string
+	Load() ([]*Instance, error)
+	LoadLite() ([]*InstanceData, []*GroupData, error)
+	LoadWithGroups() ([]*Instance, []*GroupData, error)
+	SaveWithGroups(instances []*Instance, groupTree *GroupTree) error
+	SaveGroupsOnly(groupTree *GroupTree) error
+	DeleteInstance(id string) error
+	GetUpdatedAt() (time.Time, error)
+	Close() error
+}
+
+var _ Store = (*Storage)(nil)
+
 // Storage handles persistence of session data via SQLite.
 // Thread-safe with mutex protection for concurrent access within a single process.
 // Multiple processes share data via SQLite WAL mode.
@@ -100,6 +125,11 @@ type Storage struct {
 	dbPath  string     // Path to state.db (for change detection)
 	profile string     // The profile this storage is for
 	mu      sync.Mutex // Protects operations during transition
+
+	// known holds the session IDs this process last loaded or saved. Saves
+	// only delete those, so sessions other processes added meanwhile survive.
+	// Nil until the first load or save, when a save replaces the table.
+	known map[string]bool
 }
 
 // NewStorageWithProfile creates a storage instance for a specific profile.
@@ -147,6 +177,12 @@ func NewStorageWithProfile(profile string) (*Storage, error) {
 		db.Close()
 		return nil, fmt.Errorf("failed to migrate state database: %w", err)
 	}
+	if v := db.NewerSchema(); v > 0 {
+		storageLog.Warn("state_db_schema_too_new",
+			slog.Int("schema_version", v),
+			slog.Int("supported", statedb.SchemaVersion),
+			slog.String("hint", "sessions are read-only until agent-deck is upgraded"))
+	}
 
 	// Auto-migrate from sessions.json if state.db is empty
 	jsonPath := filepath.Join(profileDir, "sessions.json")
@@ -231,7 +267,8 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 			inst.OpenCodeSessionID, inst.OpenCodeDetectedAt,
 			inst.CodexSessionID, inst.CodexDetectedAt,
 			inst.LatestPrompt, inst.LoadedMCPNames,
-			inst.ToolOptionsJSON,
+			inst.ToolOptionsJSON, inst.StartReason,
+			inst.NetworkPolicy, inst.Features,
 		)
 
 		rows[i] = &statedb.InstanceRow{
@@ -255,9 +292,16 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 		}
 	}
 
-	if err := s.db.SaveInstances(rows); err != nil {
+	var err error
+	if s.known == nil {
+		err = s.db.SaveInstances(rows)
+	} else {
+		err = s.db.SyncInstances(rows, s.known)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to save instances: %w", err)
 	}
+	s.rememberKnown(rows)
 
 	// Save groups (including empty ones)
 	if groupTree != nil {
@@ -284,7 +328,10 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 
 // DeleteInstance removes a single instance from the database by ID.
 // This ensures the row is immediately removed, preventing resurrection on reload.
+// A conductor's worker has its token tally brought up to date first.
 func (s *Storage) DeleteInstance(id string) error {
+	s.recordRemovedWorkerTokens(id, time.Now())
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -295,6 +342,7 @@ func (s *Storage) DeleteInstance(id string) error {
 	if err := s.db.DeleteInstance(id); err != nil {
 		return fmt.Errorf("failed to delete instance %s: %w", id, err)
 	}
+	delete(s.known, id)
 
 	_ = s.db.Touch()
 	return nil
@@ -333,6 +381,15 @@ func (s *Storage) SaveGroupsOnly(groupTree *GroupTree) error {
 	return nil
 }
 
+// rememberKnown records the IDs of rows just loaded or saved.
+// Caller must hold s.mu.
+func (s *Storage) rememberKnown(rows []*statedb.InstanceRow) {
+	s.known = make(map[string]bool, len(rows))
+	for _, r := range rows {
+		s.known[r.ID] = true
+	}
+}
+
 // Load reads instances from SQLite
 func (s *Storage) Load() ([]*Instance, error) {
 	instances, _, err := s.LoadWithGroups()
@@ -361,6 +418,7 @@ func (s *Storage) LoadLite() ([]*InstanceData, []*GroupData, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load groups: %w", err)
 	}
+	s.rememberKnown(dbRows)
 
 	// Convert to InstanceData format (for backward compat with CLI commands)
 	instances := make([]*InstanceData, len(dbRows))
@@ -371,7 +429,8 @@ func (s *Storage) LoadLite() ([]*InstanceData, []*GroupData, error) {
 			opencodeSID, opencodeAt,
 			codexSID, codexAt,
 			latestPrompt, loadedMCPs,
-			toolOpts := statedb.UnmarshalToolData(r.ToolData)
+			toolOpts, startReason,
+			networkPolicy, features := statedb.UnmarshalToolData(r.ToolData)
 
 		instances[i] = &InstanceData{
 			ID:                 r.ID,
@@ -403,6 +462,9 @@ func (s *Storage) LoadLite() ([]*InstanceData, []*GroupData, error) {
 			LatestPrompt:       latestPrompt,
 			ToolOptionsJSON:    toolOpts,
 			LoadedMCPNames:     loadedMCPs,
+			StartReason:        startReason,
+			NetworkPolicy:      networkPolicy,
+			Features:           features,
 		}
 	}
 
@@ -441,6 +503,7 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load groups: %w", err)
 	}
+	s.rememberKnown(dbRows)
 
 	// Convert to InstanceData for the existing convertToInstances pipeline
 	data := &StorageData{
@@ -453,7 +516,8 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 			opencodeSID, opencodeAt,
 			codexSID, codexAt,
 			latestPrompt, loadedMCPs,
-			toolOpts := statedb.UnmarshalToolData(r.ToolData)
+			toolOpts, startReason,
+			networkPolicy, features := statedb.UnmarshalToolData(r.ToolData)
 
 		data.Instances[i] = &InstanceData{
 			ID:                 r.ID,
@@ -485,6 +549,9 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 			LatestPrompt:       latestPrompt,
 			ToolOptionsJSON:    toolOpts,
 			LoadedMCPNames:     loadedMCPs,
+			StartReason:        startReason,
+			NetworkPolicy:      networkPolicy,
+			Features:           features,
 		}
 	}
 
@@ -500,7 +567,33 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 		}
 	}
 
-	return s.convertToInstances(data)
+	instances, groups, err := s.convertToInstances(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.applyStateDurations(instances, time.Now())
+	return instances, groups, nil
+}
+
+// applyStateDurations fills in how long each instance has been in its status
+// and how long it ran today, from the status_tracking/busy_daily tables.
+// Best-effort: durations stay zero if the tables can't be read.
+func (s *Storage) applyStateDurations(instances []*Instance, now time.Time) {
+	durations, err := s.db.ReadStatusDurations(statedb.DayKey(now))
+	if err != nil {
+		storageLog.Debug("load_state_durations_failed", slog.String("error", err.Error()))
+		return
+	}
+	for _, inst := range instances {
+		row, ok := durations[inst.ID]
+		if !ok {
+			continue
+		}
+		inst.busyToday = row.BusyToday
+		if row.Status == string(inst.Status) {
+			inst.stateSince = row.Since
+		}
+	}
 }
 
 // GetDBPathForProfile returns the path to the state.db file for a specific profile.
@@ -598,6 +691,9 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			// Pass instance ID for activity hooks (enables real-time status updates)
 			tmuxSess.InstanceID = instData.ID
 			tmuxSess.SetInjectStatusLine(GetTmuxSettings().GetInjectStatusLine())
+			tmuxSess.SetReadyProbe(ToolReadyProbe(instData.Tool))
+			tmuxSess.SetClassifier(ToolClassifier(instData.Tool))
+			tmuxSess.SetStatusFusion(StatusFusion())
 			// Note: EnableMouseMode is now deferred to EnsureConfigured()
 			// Called automatically when user attaches to session
 		}
@@ -642,6 +738,9 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			ToolOptionsJSON:    instData.ToolOptionsJSON,
 			LatestPrompt:       instData.LatestPrompt,
 			LoadedMCPNames:     instData.LoadedMCPNames,
+			StartReason:        instData.StartReason,
+			NetworkPolicy:      instData.NetworkPolicy,
+			Features:           instData.Features,
 			tmuxSession:        tmuxSess,
 		}
 
@@ -662,7 +761,7 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 // statusToString converts a Status enum to the string expected by tmux.ReconnectSessionWithStatus
 func statusToString(s Status) string {
 	switch s {
-	case StatusRunning:
+	case StatusRunning, StatusThrottled:
 		return "active"
 	case StatusWaiting:
 		return "waiting"
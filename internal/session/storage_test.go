@@ -184,3 +184,43 @@ func TestLoadLiteEmptyDB(t *testing.T) {
 		t.Errorf("Expected empty groups, got %d", len(groupData))
 	}
 }
+
+// TestSaveKeepsSessionsAddedByOtherProcess verifies that a save only removes
+// sessions this Storage loaded, not ones another process added since.
+func TestSaveKeepsSessionsAddedByOtherProcess(t *testing.T) {
+	tui := newTestStorage(t)
+	cli := &Storage{db: tui.db, dbPath: tui.dbPath, profile: "_test"}
+
+	now := time.Now()
+	a := &Instance{ID: "a", Title: "a", ProjectPath: "/tmp", GroupPath: "grp", Tool: "shell", Status: StatusIdle, CreatedAt: now}
+	b := &Instance{ID: "b", Title: "b", ProjectPath: "/tmp", GroupPath: "grp", Tool: "shell", Status: StatusIdle, CreatedAt: now}
+	if err := tui.SaveWithGroups([]*Instance{a, b}, nil); err != nil {
+		t.Fatalf("SaveWithGroups: %v", err)
+	}
+
+	// The CLI loads and adds "c" while the TUI still holds a and b
+	if _, _, err := cli.LoadLite(); err != nil {
+		t.Fatalf("LoadLite: %v", err)
+	}
+	c := &Instance{ID: "c", Title: "c", ProjectPath: "/tmp", GroupPath: "grp", Tool: "shell", Status: StatusIdle, CreatedAt: now}
+	if err := cli.SaveWithGroups([]*Instance{a, b, c}, nil); err != nil {
+		t.Fatalf("SaveWithGroups: %v", err)
+	}
+
+	// The TUI removes b from its stale list
+	if err := tui.SaveWithGroups([]*Instance{a}, nil); err != nil {
+		t.Fatalf("SaveWithGroups: %v", err)
+	}
+
+	rows, err := tui.db.LoadInstances()
+	if err != nil {
+		t.Fatalf("LoadInstances: %v", err)
+	}
+	ids := map[string]bool{}
+	for _, r := range rows {
+		ids[r.ID] = true
+	}
+	if len(ids) != 2 || !ids["a"] || !ids["c"] {
+		t.Errorf("ids = %v, want a and c", ids)
+	}
+}
@@ -0,0 +1,345 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// Summarizer backends ([summarizer] backend)
+const (
+	SummarizerHeuristic = "heuristic"
+	SummarizerOllama    = "ollama"
+	SummarizerAPI       = "api"
+)
+
+// SummarizerSettings picks what condenses text for standups and session
+// summaries ([summarizer]). The default, heuristic, costs nothing.
+type SummarizerSettings struct {
+	// Backend is heuristic (extract the salient lines locally), ollama (a
+	// local model) or api (the Anthropic Messages API). Default: heuristic
+	Backend string `toml:"backend"`
+
+	// Model for ollama or api. Defaults: llama3.2, claude-haiku-4-5
+	Model string `toml:"model"`
+
+	// Endpoint overrides the ollama or api base URL. Defaults:
+	// http://localhost:11434, https://api.anthropic.com
+	Endpoint string `toml:"endpoint"`
+
+	// APIKeyEnv names the environment variable holding the api key.
+	// Default: ANTHROPIC_API_KEY
+	APIKeyEnv string `toml:"api_key_env"`
+
+	// MaxLines caps a summary's length. Default: 8
+	MaxLines int `toml:"max_lines"`
+
+	// Timeout in seconds for ollama and api requests. Default: 60
+	Timeout int `toml:"timeout"`
+}
+
+// GetSummarizerSettings returns [summarizer] from config.toml
+func GetSummarizerSettings() SummarizerSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return SummarizerSettings{}
+	}
+	return config.Summarizer
+}
+
+// GetMaxLines returns the summary length cap, defaulting to 8 lines
+func (s SummarizerSettings) GetMaxLines() int {
+	if s.MaxLines <= 0 {
+		return 8
+	}
+	return s.MaxLines
+}
+
+// GetTimeout returns the model request timeout, defaulting to 60 seconds
+func (s SummarizerSettings) GetTimeout() time.Duration {
+	if s.Timeout <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(s.Timeout) * time.Second
+}
+
+// Summarizer condenses text (pane output, a standup) into a few lines
+type Summarizer interface {
+	// Name is the backend name, for reporting
+	Name() string
+	// Summarize condenses text; about is what the text is, e.g. "the
+	// output of session api-fix"
+	Summarize(ctx context.Context, about, text string) (string, error)
+}
+
+// NewSummarizer returns the backend configured in settings
+func NewSummarizer(settings SummarizerSettings) (Summarizer, error) {
+	maxLines := settings.GetMaxLines()
+	client := &http.Client{Timeout: settings.GetTimeout()}
+	switch settings.Backend {
+	case "", SummarizerHeuristic:
+		return HeuristicSummarizer{MaxLines: maxLines}, nil
+	case SummarizerOllama:
+		s := &OllamaSummarizer{Endpoint: settings.Endpoint, Model: settings.Model, MaxLines: maxLines, Client: client}
+		if s.Endpoint == "" {
+			s.Endpoint = "http://localhost:11434"
+		}
+		if s.Model == "" {
+			s.Model = "llama3.2"
+		}
+		return s, nil
+	case SummarizerAPI:
+		keyEnv := settings.APIKeyEnv
+		if keyEnv == "" {
+			keyEnv = "ANTHROPIC_API_KEY"
+		}
+		key := os.Getenv(keyEnv)
+		if key == "" {
+			return nil, fmt.Errorf("[summarizer] backend api needs an API key in $%s", keyEnv)
+		}
+		s := &APISummarizer{Endpoint: settings.Endpoint, Model: settings.Model, APIKey: key, MaxLines: maxLines, Client: client}
+		if s.Endpoint == "" {
+			s.Endpoint = "https://api.anthropic.com"
+		}
+		if s.Model == "" {
+			s.Model = "claude-haiku-4-5"
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown [summarizer] backend %q (want heuristic, ollama or api)", settings.Backend)
+}
+
+// Summarize condenses text with the configured backend. When the backend
+// fails (no model running, no key) the heuristic summary is returned along
+// with the error, so reporting never depends on a model being reachable.
+func Summarize(ctx context.Context, about, text string) (summary, backend string, err error) {
+	settings := GetSummarizerSettings()
+	s, err := NewSummarizer(settings)
+	if err == nil {
+		if summary, err = s.Summarize(ctx, about, text); err == nil {
+			return summary, s.Name(), nil
+		}
+		err = fmt.Errorf("%s summarizer: %w", s.Name(), err)
+	}
+	fallback := HeuristicSummarizer{MaxLines: settings.GetMaxLines()}
+	summary, _ = fallback.Summarize(ctx, about, text)
+	return summary, fallback.Name(), err
+}
+
+// summaryPrompt asks a model for a summary of at most maxLines lines
+func summaryPrompt(about, text string, maxLines int) string {
+	return fmt.Sprintf("Summarize %s in at most %d short lines for a status report. "+
+		"Say what was done, what is in progress, and anything blocked or needing a decision. "+
+		"Reply with the summary only.\n\n<text>\n%s\n</text>", about, maxLines, text)
+}
+
+// HeuristicSummarizer keeps the most telling lines of the text without any
+// model: errors, questions, decisions and completions, preferring recent
+// lines, shown in their original order
+type HeuristicSummarizer struct {
+	MaxLines int
+}
+
+// Name implements Summarizer
+func (HeuristicSummarizer) Name() string { return SummarizerHeuristic }
+
+// salientWords raise a line's score when they appear in it
+var salientWords = []string{
+	"error", "fail", "blocked", "need", "waiting", "question", "decide", "todo",
+	"done", "fixed", "merged", "passed", "created", "added", "updated", "?",
+}
+
+// Summarize implements Summarizer
+func (h HeuristicSummarizer) Summarize(_ context.Context, _, text string) (string, error) {
+	maxLines := h.MaxLines
+	if maxLines <= 0 {
+		maxLines = 8
+	}
+
+	var lines []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(tmux.StripANSI(text), "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		if !meaningfulLine(line) || seen[line] {
+			continue
+		}
+		seen[line] = true
+		lines = append(lines, line)
+	}
+
+	type scored struct {
+		index, score int
+	}
+	ranked := make([]scored, len(lines))
+	for i, line := range lines {
+		lower := strings.ToLower(line)
+		score := 0
+		for _, word := range salientWords {
+			if strings.Contains(lower, word) {
+				score += 2
+			}
+		}
+		// Later lines describe the current state better
+		if i >= len(lines)-maxLines {
+			score++
+		}
+		ranked[i] = scored{i, score}
+	}
+	keep := make([]bool, len(lines))
+	for n := 0; n < maxLines && n < len(ranked); n++ {
+		best := -1
+		for i, r := range ranked {
+			if keep[r.index] {
+				continue
+			}
+			if best < 0 || r.score > ranked[best].score || (r.score == ranked[best].score && r.index > ranked[best].index) {
+				best = i
+			}
+		}
+		keep[ranked[best].index] = true
+	}
+
+	var out []string
+	for i, line := range lines {
+		if keep[i] {
+			out = append(out, "- "+line)
+		}
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// meaningfulLine drops blank lines, box drawing, spinners and bare prompts
+func meaningfulLine(line string) bool {
+	letters := 0
+	for _, r := range line {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 3
+}
+
+// OllamaSummarizer summarizes with a local model through ollama's
+// /api/generate endpoint
+type OllamaSummarizer struct {
+	Endpoint string
+	Model    string
+	MaxLines int
+	Client   *http.Client
+}
+
+// Name implements Summarizer
+func (*OllamaSummarizer) Name() string { return SummarizerOllama }
+
+// Summarize implements Summarizer
+func (o *OllamaSummarizer) Summarize(ctx context.Context, about, text string) (string, error) {
+	var reply struct {
+		Response string `json:"response"`
+		Error    string `json:"error"`
+	}
+	err := doSummaryRequest(ctx, o.Client, strings.TrimRight(o.Endpoint, "/")+"/api/generate", nil, map[string]any{
+		"model":  o.Model,
+		"prompt": summaryPrompt(about, text, o.MaxLines),
+		"stream": false,
+	}, &reply)
+	if err != nil {
+		return "", err
+	}
+	if reply.Error != "" {
+		return "", fmt.Errorf("ollama: %s", reply.Error)
+	}
+	return strings.TrimSpace(reply.Response), nil
+}
+
+// APISummarizer summarizes with a hosted model through the Anthropic
+// Messages API
+type APISummarizer struct {
+	Endpoint string
+	Model    string
+	APIKey   string
+	MaxLines int
+	Client   *http.Client
+}
+
+// Name implements Summarizer
+func (*APISummarizer) Name() string { return SummarizerAPI }
+
+// Summarize implements Summarizer
+func (a *APISummarizer) Summarize(ctx context.Context, about, text string) (string, error) {
+	var reply struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	headers := map[string]string{
+		"x-api-key":         a.APIKey,
+		"anthropic-version": "2023-06-01",
+	}
+	err := doSummaryRequest(ctx, a.Client, strings.TrimRight(a.Endpoint, "/")+"/v1/messages", headers, map[string]any{
+		"model":      a.Model,
+		"max_tokens": 400,
+		"messages": []map[string]string{
+			{"role": "user", "content": summaryPrompt(about, text, a.MaxLines)},
+		},
+	}, &reply)
+	if err != nil {
+		if reply.Error != nil {
+			return "", fmt.Errorf("%w: %s", err, reply.Error.Message)
+		}
+		return "", err
+	}
+	var b strings.Builder
+	for _, block := range reply.Content {
+		if block.Type == "text" {
+			b.WriteString(block.Text)
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// doSummaryRequest POSTs body as JSON and decodes the reply into out, which
+// is also filled on a non-2xx status so callers can report the error body
+func doSummaryRequest(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	decodeErr := json.Unmarshal(respBody, out)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return decodeErr
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHeuristicSummarizer_KeepsSalientLines(t *testing.T) {
+	text := "\x1b[32m╭────────╮\x1b[0m\n" +
+		"Reading src/auth.go\n" +
+		"Reading src/auth.go\n" +
+		"- Added token refresh to the client\n" +
+		"Looking around the repository layout\n" +
+		"ERROR: integration tests failed on staging\n" +
+		"> \n" +
+		"Should I rotate the staging keys?\n"
+
+	got, err := HeuristicSummarizer{MaxLines: 3}.Summarize(context.Background(), "", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- Added token refresh to the client\n" +
+		"- ERROR: integration tests failed on staging\n" +
+		"- Should I rotate the staging keys?"
+	if got != want {
+		t.Errorf("summary:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestModelSummarizers(t *testing.T) {
+	var got map[string]any
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		switch r.URL.Path {
+		case "/api/generate":
+			_, _ = w.Write([]byte(`{"response": " - tests fixed\n"}`))
+		case "/v1/messages":
+			_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "- deploy blocked"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ollama, err := NewSummarizer(SummarizerSettings{Backend: SummarizerOllama, Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := ollama.Summarize(context.Background(), "the output", "long text")
+	if err != nil || summary != "- tests fixed" {
+		t.Errorf("ollama = %q, %v", summary, err)
+	}
+	if got["model"] != "llama3.2" || got["stream"] != false || !strings.Contains(got["prompt"].(string), "long text") {
+		t.Errorf("ollama request = %v", got)
+	}
+
+	t.Setenv("TEST_SUMMARY_KEY", "sk-test")
+	api, err := NewSummarizer(SummarizerSettings{Backend: SummarizerAPI, Endpoint: server.URL, APIKeyEnv: "TEST_SUMMARY_KEY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err = api.Summarize(context.Background(), "the output", "long text")
+	if err != nil || summary != "- deploy blocked" {
+		t.Errorf("api = %q, %v", summary, err)
+	}
+	if headers.Get("x-api-key") != "sk-test" || got["model"] != "claude-haiku-4-5" {
+		t.Errorf("api request = %v, key %q", got, headers.Get("x-api-key"))
+	}
+
+	if _, err := NewSummarizer(SummarizerSettings{Backend: SummarizerAPI, APIKeyEnv: "TEST_SUMMARY_MISSING"}); err == nil {
+		t.Error("api backend without a key should fail")
+	}
+	if _, err := NewSummarizer(SummarizerSettings{Backend: "gpt"}); err == nil {
+		t.Error("unknown backend should fail")
+	}
+}
+
+func TestSummarize_FallsBackToHeuristic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error": "model not found"}`))
+	}))
+	defer server.Close()
+	writeMacroConfig(t, "[summarizer]\nbackend = \"ollama\"\nendpoint = \""+server.URL+"\"\n")
+
+	summary, backend, err := Summarize(context.Background(), "the output", "Build failed on main")
+	if err == nil || !strings.Contains(err.Error(), "ollama") {
+		t.Errorf("err = %v, want the ollama failure", err)
+	}
+	if backend != SummarizerHeuristic || summary != "- Build failed on main" {
+		t.Errorf("fallback = %q from %s", summary, backend)
+	}
+}
@@ -0,0 +1,159 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Time display modes (see TimeSettings.Format)
+const (
+	TimeFormatRelative = "relative"
+	TimeFormatAbsolute = "absolute"
+)
+
+// localeLayouts maps a locale (language_REGION or language) to an absolute
+// date-time layout. Anything unmatched uses ISO-style defaultTimeLayout.
+var localeLayouts = map[string]string{
+	"en_US": "Jan 2, 2006 3:04 PM",
+	"en_CA": "Jan 2, 2006 3:04 PM",
+	"en":    "2 Jan 2006 15:04",
+	"de":    "02.01.2006 15:04",
+	"fr":    "02/01/2006 15:04",
+	"es":    "02/01/2006 15:04",
+	"it":    "02/01/2006 15:04",
+	"pt":    "02/01/2006 15:04",
+	"nl":    "02-01-2006 15:04",
+	"ru":    "02.01.2006 15:04",
+	"ja":    "2006/01/02 15:04",
+	"zh":    "2006/01/02 15:04",
+	"ko":    "2006. 01. 02. 15:04",
+}
+
+const defaultTimeLayout = "2006-01-02 15:04"
+
+// TimeFormatter renders timestamps relative to now ("2h ago") or as absolute
+// dates in a locale-specific layout and time zone.
+type TimeFormatter struct {
+	Absolute bool
+	Location *time.Location
+	Locale   string
+}
+
+// NewTimeFormatter builds a formatter from [time] in config and the
+// environment locale. absolute forces absolute output (e.g. --absolute).
+func NewTimeFormatter(absolute bool) TimeFormatter {
+	settings := GetTimeSettings()
+	f := TimeFormatter{
+		Absolute: absolute || settings.Format == TimeFormatAbsolute,
+		Location: time.Local,
+		Locale:   settings.Locale,
+	}
+	if settings.Timezone != "" {
+		if loc, err := time.LoadLocation(settings.Timezone); err == nil {
+			f.Location = loc
+		}
+	}
+	if f.Locale == "" {
+		f.Locale = EnvLocale()
+	}
+	return f
+}
+
+// InTimezone returns a copy that renders absolute times in tz (an IANA name).
+// An empty or unknown tz keeps the current location.
+func (f TimeFormatter) InTimezone(tz string) TimeFormatter {
+	if tz == "" {
+		return f
+	}
+	if loc, err := time.LoadLocation(tz); err == nil {
+		f.Location = loc
+	}
+	return f
+}
+
+// Format renders t according to the formatter mode ("" for zero times)
+func (f TimeFormatter) Format(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if f.Absolute {
+		return f.FormatAbsolute(t)
+	}
+	return FormatRelative(t, now)
+}
+
+// FormatAbsolute renders t in the formatter's zone and locale layout
+func (f TimeFormatter) FormatAbsolute(t time.Time) string {
+	loc := f.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format(LocaleTimeLayout(f.Locale))
+}
+
+// FormatRelative renders t relative to now: "just now", "5m ago", "2h ago",
+// "3d ago", or "in 4h" for future times.
+func FormatRelative(t, now time.Time) string {
+	d := now.Sub(t)
+	suffix := " ago"
+	prefix := ""
+	if d < 0 {
+		d = -d
+		prefix, suffix = "in ", ""
+	}
+	var amount string
+	switch {
+	case d < time.Minute:
+		if prefix != "" {
+			return "in <1m"
+		}
+		return "just now"
+	case d < time.Hour:
+		amount = fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		amount = fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 60*24*time.Hour:
+		amount = fmt.Sprintf("%dd", int(d.Hours()/24))
+	default:
+		amount = fmt.Sprintf("%dmo", int(d.Hours()/(24*30)))
+	}
+	return prefix + amount + suffix
+}
+
+// LocaleTimeLayout returns the absolute layout for a POSIX locale such as
+// "de_DE.UTF-8" (exact language_REGION first, then language).
+func LocaleTimeLayout(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	if layout, ok := localeLayouts[locale]; ok {
+		return layout
+	}
+	lang, _, _ := strings.Cut(locale, "_")
+	if layout, ok := localeLayouts[lang]; ok {
+		return layout
+	}
+	return defaultTimeLayout
+}
+
+// EnvLocale returns the time locale from LC_ALL, LC_TIME or LANG
+func EnvLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := os.Getenv(key); v != "" && v != "C" && v != "POSIX" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ValidateTimezone checks that tz is a loadable IANA time zone name
+func ValidateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("unknown timezone %q: %w", tz, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatRelative(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{now.Add(-10 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-2 * time.Hour), "2h ago"},
+		{now.Add(-3 * 24 * time.Hour), "3d ago"},
+		{now.Add(-90 * 24 * time.Hour), "3mo ago"},
+		{now.Add(4 * time.Hour), "in 4h"},
+		{now.Add(20 * time.Second), "in <1m"},
+	}
+	for _, tt := range tests {
+		if got := FormatRelative(tt.at, now); got != tt.want {
+			t.Errorf("FormatRelative(%v) = %q, want %q", now.Sub(tt.at), got, tt.want)
+		}
+	}
+}
+
+func TestTimeFormatterAbsolute(t *testing.T) {
+	at := time.Date(2026, 3, 10, 22, 30, 0, 0, time.UTC)
+	f := TimeFormatter{Absolute: true, Location: time.UTC, Locale: "de_DE.UTF-8"}
+	if got := f.Format(at, at); got != "10.03.2026 22:30" {
+		t.Errorf("de_DE format = %q", got)
+	}
+
+	tokyo := f.InTimezone("Asia/Tokyo")
+	tokyo.Locale = ""
+	if got := tokyo.Format(at, at); got != "2026-03-11 07:30" {
+		t.Errorf("Asia/Tokyo format = %q", got)
+	}
+	if got := f.InTimezone("Not/AZone").Location; got != time.UTC {
+		t.Errorf("unknown zone should keep location, got %v", got)
+	}
+	if got := f.Format(time.Time{}, at); got != "" {
+		t.Errorf("zero time should render empty, got %q", got)
+	}
+}
+
+func TestLocaleTimeLayout(t *testing.T) {
+	tests := map[string]string{
+		"en_US.UTF-8": "Jan 2, 2006 3:04 PM",
+		"en_GB.UTF-8": "2 Jan 2006 15:04",
+		"ja_JP":       "2006/01/02 15:04",
+		"":            defaultTimeLayout,
+		"xx_YY":       defaultTimeLayout,
+	}
+	for locale, want := range tests {
+		if got := LocaleTimeLayout(locale); got != want {
+			t.Errorf("LocaleTimeLayout(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	if err := ValidateTimezone("Europe/Berlin"); err != nil {
+		t.Errorf("Europe/Berlin should be valid: %v", err)
+	}
+	if err := ValidateTimezone("Mars/Olympus"); err == nil {
+		t.Error("Mars/Olympus should be rejected")
+	}
+}
@@ -0,0 +1,138 @@
+package session
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// ToolAdapter holds the tool-specific behavior of an AI tool: how its
+// sessions start, resume and fork, and what its pane looks like while busy
+// or ready. A tool with an adapter needs no special cases elsewhere; add one
+// in its own file and register it in toolAdapters (or with
+// RegisterToolAdapter).
+type ToolAdapter interface {
+	// BuildStartCommand returns the command that starts a session of the
+	// tool, from the instance's configured base command
+	BuildStartCommand(inst *Instance, baseCommand string) string
+
+	// BuildResumeCommand returns the command that resumes inst's
+	// conversation in place on restart, or "" when there is none to resume
+	// and the session should start fresh
+	BuildResumeCommand(inst *Instance) string
+
+	// CanResume reports whether inst has a conversation BuildResumeCommand
+	// can resume. Called while rendering, so it must be cheap.
+	CanResume(inst *Instance) bool
+
+	// BusyPatterns are the pane patterns (plain or "re:") that mean the
+	// tool is working; config.toml overrides and pattern packs apply on top
+	BusyPatterns() []string
+
+	// PromptReadyPatterns are the pane patterns that mean the tool is
+	// waiting for input
+	PromptReadyPatterns() []string
+
+	// SupportsFork reports whether inst's conversation can be forked now;
+	// for a nil inst, whether the tool can fork at all
+	SupportsFork(inst *Instance) bool
+}
+
+// ToolSessionTracker is implemented by adapters of tools with a session ID
+// that can change behind agent-deck's back or is only known once the tool
+// has started
+type ToolSessionTracker interface {
+	// SyncSessionID refreshes inst's session ID from the tool's files before
+	// a restart, picking up conversations begun inside the tool (/clear, /new)
+	SyncSessionID(inst *Instance)
+
+	// CaptureSessionID finds the session ID of a freshly started session.
+	// Runs in the background.
+	CaptureSessionID(inst *Instance)
+}
+
+// ToolThrottleDetector is implemented by adapters of tools that show when a
+// provider is rate-limiting them, so a session backing off is reported as
+// StatusThrottled rather than as working or waiting
+type ToolThrottleDetector interface {
+	// ThrottleMessage returns the rate-limit or backoff message at the
+	// bottom of the pane, or "" when the tool is not throttled
+	ThrottleMessage(pane string) string
+}
+
+var (
+	toolAdaptersMu sync.RWMutex
+	toolAdapters   = map[string]ToolAdapter{
+		"claude": claudeAdapter{},
+		"codex":  codexAdapter{},
+		"aider":  aiderAdapter{},
+		"gemini": geminiAdapter{},
+	}
+)
+
+// RegisterToolAdapter sets the adapter of a tool, replacing any existing
+// one. A nil adapter removes it.
+func RegisterToolAdapter(tool string, adapter ToolAdapter) {
+	toolAdaptersMu.Lock()
+	defer toolAdaptersMu.Unlock()
+	if adapter == nil {
+		delete(toolAdapters, tool)
+		return
+	}
+	toolAdapters[tool] = adapter
+}
+
+func toolAdapterFor(tool string) ToolAdapter {
+	toolAdaptersMu.RLock()
+	defer toolAdaptersMu.RUnlock()
+	return toolAdapters[tool]
+}
+
+// ToolAdapterNames returns the tools that have an adapter, sorted
+func ToolAdapterNames() []string {
+	toolAdaptersMu.RLock()
+	defer toolAdaptersMu.RUnlock()
+	names := make([]string, 0, len(toolAdapters))
+	for name := range toolAdapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// syncSessionID refreshes the session ID of a tool that tracks one
+func (i *Instance) syncSessionID() {
+	if tracker, ok := toolAdapterFor(i.Tool).(ToolSessionTracker); ok {
+		tracker.SyncSessionID(i)
+	}
+}
+
+// captureSessionID starts background session ID detection for a tool that
+// tracks one. With onlyIfUnknown set, a session that already has a
+// conversation to resume is left alone.
+func (i *Instance) captureSessionID(onlyIfUnknown bool) {
+	adapter := toolAdapterFor(i.Tool)
+	tracker, ok := adapter.(ToolSessionTracker)
+	if !ok || (onlyIfUnknown && adapter.CanResume(i)) {
+		return
+	}
+	go tracker.CaptureSessionID(i)
+}
+
+// defaultToolPatterns returns the built-in status patterns of a tool: its
+// adapter's busy and prompt patterns, with the spinner and capture settings
+// the tmux package keeps for it
+func defaultToolPatterns(tool string) *tmux.RawPatterns {
+	raw := tmux.DefaultRawPatterns(tool)
+	adapter := toolAdapterFor(tool)
+	if adapter == nil {
+		return raw
+	}
+	if raw == nil {
+		raw = &tmux.RawPatterns{}
+	}
+	raw.BusyPatterns = adapter.BusyPatterns()
+	raw.PromptPatterns = adapter.PromptReadyPatterns()
+	return raw
+}
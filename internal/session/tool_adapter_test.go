@@ -0,0 +1,191 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// fakeAdapter is a tool added as a single adapter, with no special cases
+type fakeAdapter struct{ conversation string }
+
+func (fakeAdapter) BuildStartCommand(_ *Instance, baseCommand string) string {
+	return baseCommand + " --fresh"
+}
+
+func (a fakeAdapter) BuildResumeCommand(*Instance) string {
+	if a.conversation == "" {
+		return ""
+	}
+	return "faketool --resume " + a.conversation
+}
+
+func (a fakeAdapter) CanResume(*Instance) bool    { return a.conversation != "" }
+func (fakeAdapter) BusyPatterns() []string        { return []string{"thinking…"} }
+func (fakeAdapter) PromptReadyPatterns() []string { return []string{"re:^fake> $"} }
+func (fakeAdapter) SupportsFork(*Instance) bool   { return false }
+
+func TestToolAdapter_Registry(t *testing.T) {
+	writeMacroConfig(t, "")
+	RegisterToolAdapter("faketool", fakeAdapter{})
+	defer RegisterToolAdapter("faketool", nil)
+
+	if !slices.Contains(ToolAdapterNames(), "faketool") || !slices.Contains(ToolAdapterNames(), "claude") {
+		t.Fatalf("adapters = %v", ToolAdapterNames())
+	}
+
+	inst := NewInstanceWithTool("fake", t.TempDir(), "faketool")
+	inst.Command = "faketool"
+	if got := inst.buildStartCommand(); got != "faketool --fresh" {
+		t.Errorf("start command = %q", got)
+	}
+	if inst.CanFork() || ToolSupportsFork("faketool") {
+		t.Error("faketool cannot fork")
+	}
+
+	raw := MergeToolPatterns("faketool")
+	if raw == nil || !slices.Equal(raw.BusyPatterns, []string{"thinking…"}) || !slices.Equal(raw.PromptPatterns, []string{"re:^fake> $"}) {
+		t.Errorf("patterns = %+v", raw)
+	}
+
+	RegisterToolAdapter("faketool", fakeAdapter{conversation: "c1"})
+	if !inst.CanRestart() {
+		t.Error("a resumable adapter session can restart")
+	}
+}
+
+func TestClaudeAdapter(t *testing.T) {
+	adapter := toolAdapterFor("claude")
+	if adapter == nil {
+		t.Fatal("claude has no adapter")
+	}
+	if !slices.Equal(adapter.BusyPatterns(), tmux.DefaultRawPatterns("claude").BusyPatterns) {
+		t.Errorf("busy patterns = %v", adapter.BusyPatterns())
+	}
+	raw := MergeToolPatterns("claude")
+	if raw == nil || len(raw.SpinnerChars) == 0 || len(raw.WhimsicalWords) == 0 {
+		t.Errorf("claude keeps its spinner settings: %+v", raw)
+	}
+
+	inst := NewInstanceWithTool("c", t.TempDir(), "claude")
+	if adapter.CanResume(inst) || adapter.BuildResumeCommand(inst) != "" {
+		t.Error("no session ID, nothing to resume")
+	}
+	if !ToolSupportsFork("claude") {
+		t.Error("claude supports fork")
+	}
+}
+
+func TestCodexAdapter(t *testing.T) {
+	writeMacroConfig(t, "")
+	adapter := toolAdapterFor("codex")
+	if adapter == nil {
+		t.Fatal("codex has no adapter")
+	}
+	if _, ok := adapter.(ToolSessionTracker); !ok {
+		t.Error("codex tracks its session ID")
+	}
+
+	inst := NewInstanceWithTool("cx", t.TempDir(), "codex")
+	inst.Command = "codex"
+	if got := inst.buildStartCommand(); !strings.HasSuffix(got, "codex") || inst.CodexStartedAt == 0 {
+		t.Errorf("start command = %q, started at %d", got, inst.CodexStartedAt)
+	}
+	if adapter.CanResume(inst) || !inst.CanRestart() {
+		t.Error("a codex session without ID restarts fresh")
+	}
+
+	inst.CodexSessionID = "0199a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b"
+	if got := adapter.BuildResumeCommand(inst); !strings.Contains(got, "codex resume "+inst.CodexSessionID) ||
+		!strings.Contains(got, "tmux set-environment CODEX_SESSION_ID "+inst.CodexSessionID) {
+		t.Errorf("resume command = %q", got)
+	}
+	if !adapter.CanResume(inst) {
+		t.Error("a known session ID can be resumed")
+	}
+	if !slices.Equal(MergeToolPatterns("codex").BusyPatterns, tmux.DefaultRawPatterns("codex").BusyPatterns) {
+		t.Error("codex keeps its busy patterns")
+	}
+}
+
+func TestAiderAdapter(t *testing.T) {
+	writeMacroConfig(t, "")
+	adapter := toolAdapterFor("aider")
+	if adapter == nil {
+		t.Fatal("aider has no adapter")
+	}
+
+	project := t.TempDir()
+	inst := NewInstanceWithTool("ai", project, "aider")
+	inst.Command = "aider"
+	inst.CreatedAt = time.Now().Add(-time.Minute)
+	if got := inst.buildStartCommand(); !strings.HasSuffix(got, "aider") {
+		t.Errorf("start command = %q", got)
+	}
+	if !inst.CanRestart() || adapter.SupportsFork(inst) {
+		t.Error("an aider session without history restarts fresh and cannot fork")
+	}
+
+	if err := os.WriteFile(filepath.Join(project, ".aider.chat.history.md"), []byte("#### hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := adapter.BuildResumeCommand(inst); !strings.HasSuffix(got, "aider --restore-chat-history") {
+		t.Errorf("resume command = %q", got)
+	}
+	if !adapter.SupportsFork(inst) || !adapter.SupportsFork(nil) {
+		t.Error("an aider session with history can fork")
+	}
+	if !slices.Equal(adapter.PromptReadyPatterns(), tmux.DefaultRawPatterns("aider").PromptPatterns) {
+		t.Error("aider keeps its prompt patterns")
+	}
+}
+
+func TestGeminiAdapter(t *testing.T) {
+	writeMacroConfig(t, "")
+	adapter := toolAdapterFor("gemini")
+	if adapter == nil {
+		t.Fatal("gemini has no adapter")
+	}
+
+	inst := NewInstanceWithTool("g", t.TempDir(), "gemini")
+	inst.Command = "gemini"
+	if got := inst.buildStartCommand(); !strings.Contains(got, "gemini") {
+		t.Errorf("start command = %q", got)
+	}
+	if adapter.CanResume(inst) || adapter.BuildResumeCommand(inst) != "" {
+		t.Error("no session ID, nothing to resume")
+	}
+	inst.GeminiSessionID = "abcdef12-3456-7890-abcd-ef1234567890"
+	if got := adapter.BuildResumeCommand(inst); !strings.Contains(got, "gemini --resume "+inst.GeminiSessionID) {
+		t.Errorf("resume command = %q", got)
+	}
+	if !inst.CanRestart() {
+		t.Error("a gemini session with an ID can restart")
+	}
+
+	detector, ok := adapter.(ToolThrottleDetector)
+	if !ok {
+		t.Fatal("gemini adapter should detect throttling")
+	}
+	for _, tc := range []struct {
+		pane string
+		want string
+	}{
+		{"⠧ Thinking (esc to cancel, 12s)\n│ >   Type your message │", ""},
+		{"Attempt 2 failed with status 429. Retrying with backoff... ApiError\n⠧ Thinking (esc to cancel, 40s)",
+			"Attempt 2 failed with status 429. Retrying with backoff... ApiError"},
+		{"│ ⚡ You have reached your daily gemini-2.5-pro quota limit. │\n│ >   Type your message │",
+			"⚡ You have reached your daily gemini-2.5-pro quota limit."},
+		// A rate limit the session has since moved past
+		{"Attempt 1 failed with status 429.\n" + strings.Repeat("all tests pass\n", geminiThrottleLines) + "│ >   Type your message │", ""},
+	} {
+		if got := detector.ThrottleMessage(tc.pane); got != tc.want {
+			t.Errorf("ThrottleMessage(%q) = %q, want %q", tc.pane, got, tc.want)
+		}
+	}
+}
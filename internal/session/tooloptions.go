@@ -110,6 +110,8 @@ type CodexOptions struct {
 	// YoloMode enables --yolo flag (bypass approvals and sandbox)
 	// nil = inherit from global config, true/false = explicit override
 	YoloMode *bool `json:"yolo_mode,omitempty"`
+	// Model overrides the model (e.g., "gpt-5-codex")
+	Model string `json:"model,omitempty"`
 }
 
 // ToolName returns "codex"
@@ -123,6 +125,9 @@ func (o *CodexOptions) ToArgs() []string {
 	if o.YoloMode != nil && *o.YoloMode {
 		args = append(args, "--yolo")
 	}
+	if o.Model != "" {
+		args = append(args, "-m", o.Model)
+	}
 	return args
 }
 
@@ -133,6 +138,9 @@ func NewCodexOptions(config *UserConfig) *CodexOptions {
 		yolo := true
 		opts.YoloMode = &yolo
 	}
+	if config != nil {
+		opts.Model = config.Codex.DefaultModel
+	}
 	return opts
 }
 
@@ -295,3 +303,51 @@ func UnmarshalClaudeOptions(data json.RawMessage) (*ClaudeOptions, error) {
 
 	return &opts, nil
 }
+
+// OllamaOptions holds launch options for ollama (local model) sessions
+type OllamaOptions struct {
+	// Model overrides [ollama] model for this session (e.g., "qwen2.5-coder:7b")
+	Model string `json:"model,omitempty"`
+	// Conversation is the model the session's conversation was last saved
+	// as with /save; restarts run it to pick the conversation back up
+	Conversation string `json:"conversation,omitempty"`
+}
+
+// ToolName returns "ollama"
+func (o *OllamaOptions) ToolName() string {
+	return "ollama"
+}
+
+// ToArgs returns command-line arguments based on options
+func (o *OllamaOptions) ToArgs() []string {
+	if o.Conversation != "" {
+		return []string{"run", o.Conversation}
+	}
+	if o.Model != "" {
+		return []string{"run", o.Model}
+	}
+	return nil
+}
+
+// UnmarshalOllamaOptions deserializes OllamaOptions from JSON wrapper
+func UnmarshalOllamaOptions(data json.RawMessage) (*OllamaOptions, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var wrapper ToolOptionsWrapper
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+
+	if wrapper.Tool != "ollama" {
+		return nil, nil
+	}
+
+	var opts OllamaOptions
+	if err := json.Unmarshal(wrapper.Options, &opts); err != nil {
+		return nil, err
+	}
+
+	return &opts, nil
+}
@@ -400,6 +400,11 @@ func TestCodexOptions_ToArgs(t *testing.T) {
 			opts:     CodexOptions{YoloMode: boolPtr(false)},
 			expected: nil,
 		},
+		{
+			name:     "yolo and model",
+			opts:     CodexOptions{YoloMode: boolPtr(true), Model: "gpt-5-codex"},
+			expected: []string{"--yolo", "-m", "gpt-5-codex"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,322 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// TranscriptSettings configures transcripts: each session's pane output,
+// captured continuously with tmux pipe-pane into
+// ~/.agent-deck/transcripts/<session-id>/ and read with "agent-deck logs".
+//
+//	[transcripts]
+//	enabled = true
+//	max_size_mb = 10
+type TranscriptSettings struct {
+	// Enabled turns capture on for sessions started from now on (default: false)
+	Enabled bool `toml:"enabled"`
+
+	// MaxSizeMB is the size at which the current transcript file is rotated.
+	// Default: 10
+	MaxSizeMB int `toml:"max_size_mb"`
+
+	// MaxFiles is how many rotated files are kept per session. Default: 5
+	MaxFiles int `toml:"max_files"`
+
+	// MaxAgeDays removes rotated files, and the transcripts of sessions that
+	// stopped writing, older than this. Default: 14
+	MaxAgeDays int `toml:"max_age_days"`
+
+	// Tools limits capture to sessions of these tools (default: all)
+	Tools []string `toml:"tools"`
+}
+
+// transcriptCurrent is the file a session's output is appended to; rotated
+// files are named after the time they were rotated, so they sort in order
+const (
+	transcriptCurrent    = "current.log"
+	transcriptRotatedFmt = "20060102-150405.000"
+)
+
+// GetTranscriptSettings returns [transcripts] with defaults applied
+func GetTranscriptSettings() TranscriptSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return TranscriptSettings{}.withDefaults()
+	}
+	return config.Transcripts.withDefaults()
+}
+
+func (s TranscriptSettings) withDefaults() TranscriptSettings {
+	if s.MaxSizeMB <= 0 {
+		s.MaxSizeMB = 10
+	}
+	if s.MaxFiles <= 0 {
+		s.MaxFiles = 5
+	}
+	if s.MaxAgeDays <= 0 {
+		s.MaxAgeDays = 14
+	}
+	return s
+}
+
+// capturesTool reports whether sessions of tool are captured
+func (s TranscriptSettings) capturesTool(tool string) bool {
+	return s.Enabled && (len(s.Tools) == 0 || slices.Contains(s.Tools, tool))
+}
+
+// TranscriptsDir returns the directory holding all transcripts
+func TranscriptsDir() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "transcripts"), nil
+}
+
+// TranscriptDir returns the transcript directory of a session
+func TranscriptDir(sessionID string) (string, error) {
+	if sessionID == "" || strings.ContainsAny(sessionID, `/\`) || sessionID == "." || sessionID == ".." {
+		return "", fmt.Errorf("invalid session ID %q", sessionID)
+	}
+	dir, err := TranscriptsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionID), nil
+}
+
+// TranscriptFiles returns a session's transcript files, oldest first, ending
+// with the current one. It returns nothing when the session has none.
+func TranscriptFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rotated := rotatedTranscripts(entries)
+	files := make([]string, 0, len(rotated)+1)
+	for _, name := range rotated {
+		files = append(files, filepath.Join(dir, name))
+	}
+	if _, err := os.Stat(filepath.Join(dir, transcriptCurrent)); err == nil {
+		files = append(files, filepath.Join(dir, transcriptCurrent))
+	}
+	return files, nil
+}
+
+// rotatedTranscripts returns the names of rotated files, oldest first
+func rotatedTranscripts(entries []os.DirEntry) []string {
+	var names []string
+	for _, e := range entries {
+		stamp, ok := strings.CutSuffix(e.Name(), ".log")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(transcriptRotatedFmt, stamp); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TranscriptWriter appends to a session's current transcript and rotates it
+// once it reaches MaxSizeMB
+type TranscriptWriter struct {
+	dir      string
+	settings TranscriptSettings
+	file     *os.File
+	size     int64
+	now      func() time.Time
+}
+
+// OpenTranscriptWriter opens the current transcript in dir for appending,
+// pruning old rotated files first
+func OpenTranscriptWriter(dir string, settings TranscriptSettings) (*TranscriptWriter, error) {
+	w := &TranscriptWriter{dir: dir, settings: settings.withDefaults(), now: time.Now}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create transcript dir: %w", err)
+	}
+	pruneTranscriptDir(dir, w.settings, w.now())
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *TranscriptWriter) open() error {
+	f, err := os.OpenFile(filepath.Join(w.dir, transcriptCurrent), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open transcript: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+// Write implements io.Writer. A write that would take the current file past
+// MaxSizeMB rotates it first.
+func (w *TranscriptWriter) Write(p []byte) (int, error) {
+	if w.size > 0 && w.size+int64(len(p)) > int64(w.settings.MaxSizeMB)*1024*1024 {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file after the current time, prunes the rotated
+// files and starts a new current file
+func (w *TranscriptWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	now := w.now()
+	rotated := filepath.Join(w.dir, now.Format(transcriptRotatedFmt)+".log")
+	if err := os.Rename(filepath.Join(w.dir, transcriptCurrent), rotated); err != nil {
+		return fmt.Errorf("failed to rotate transcript: %w", err)
+	}
+	pruneTranscriptDir(w.dir, w.settings, now)
+	return w.open()
+}
+
+// Close implements io.Closer
+func (w *TranscriptWriter) Close() error {
+	return w.file.Close()
+}
+
+// CopyTranscript writes r (a pane's output from pipe-pane) into the session
+// transcript in dir until r ends
+func CopyTranscript(r io.Reader, dir string, settings TranscriptSettings) error {
+	w, err := OpenTranscriptWriter(dir, settings)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// pruneTranscriptDir removes rotated files beyond MaxFiles or older than
+// MaxAgeDays
+func pruneTranscriptDir(dir string, settings TranscriptSettings, now time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	rotated := rotatedTranscripts(entries)
+	cutoff := now.AddDate(0, 0, -settings.MaxAgeDays)
+	for n, name := range rotated {
+		path := filepath.Join(dir, name)
+		tooMany := n < len(rotated)-settings.MaxFiles
+		if info, err := os.Stat(path); err == nil && (tooMany || info.ModTime().Before(cutoff)) {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// PruneTranscripts applies the rotation limits to every session's
+// transcripts and removes the transcripts of sessions not written to for
+// MaxAgeDays. It returns how many session directories were removed.
+func PruneTranscripts(settings TranscriptSettings, now time.Time) (int, error) {
+	settings = settings.withDefaults()
+	root, err := TranscriptsDir()
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	cutoff := now.AddDate(0, 0, -settings.MaxAgeDays)
+	removed := 0
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		pruneTranscriptDir(dir, settings, now)
+		files, _ := TranscriptFiles(dir)
+		stale := true
+		for _, file := range files {
+			if info, err := os.Stat(file); err == nil && !info.ModTime().Before(cutoff) {
+				stale = false
+				break
+			}
+		}
+		if stale {
+			if err := os.RemoveAll(dir); err == nil {
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+// startTranscript pipes the session's pane into its transcript when
+// [transcripts] captures the session's tool. Failures are logged only; a
+// session runs fine without a transcript.
+func (i *Instance) startTranscript() {
+	settings := GetTranscriptSettings()
+	if i.tmuxSession == nil || !settings.capturesTool(i.Tool) {
+		return
+	}
+	if err := i.pipeTranscript(); err != nil {
+		sessionLog.Warn("transcript_start_failed",
+			slog.String("session", i.Title),
+			slog.String("error", err.Error()))
+	}
+}
+
+// pipeTranscript starts "agent-deck transcript-pipe" on the session's pane,
+// unless a pipe is already open
+func (i *Instance) pipeTranscript() error {
+	dir, err := TranscriptDir(i.ID)
+	if err != nil {
+		return err
+	}
+	bin := agentDeckBinary()
+	if bin == "" {
+		return errors.New("agent-deck binary not found for transcripts")
+	}
+	return i.tmuxSession.PipePane(fmt.Sprintf("exec %s transcript-pipe %s", shellQuote(bin), shellQuote(dir)))
+}
+
+// PlainTranscript turns raw pane output into readable text: escape sequences
+// are removed and a line redrawn after a carriage return keeps its last
+// version only
+func PlainTranscript(raw string) string {
+	lines := strings.Split(tmux.StripANSI(raw), "\n")
+	for n, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if idx := strings.LastIndexByte(line, '\r'); idx >= 0 {
+			line = line[idx+1:]
+		}
+		lines[n] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranscriptWriterRotatesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	w, err := OpenTranscriptWriter(dir, TranscriptSettings{MaxSizeMB: 1, MaxFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	chunk := []byte(strings.Repeat("x", 600*1024))
+	for n := 0; n < 5; n++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("write %d: %v", n, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := TranscriptFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every write past 1MB rotated: 4 rotations, of which 2 are kept
+	if len(files) != 3 {
+		t.Fatalf("expected 2 rotated files and the current one, got %v", files)
+	}
+	if filepath.Base(files[0]) != "20261016-120003.000.log" || filepath.Base(files[2]) != transcriptCurrent {
+		t.Errorf("files not ordered oldest first: %v", files)
+	}
+	for _, file := range files {
+		if info, _ := os.Stat(file); info.Size() != int64(len(chunk)) {
+			t.Errorf("%s has %d bytes, want %d", file, info.Size(), len(chunk))
+		}
+	}
+}
+
+func TestTranscriptWriterAppendsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	for _, text := range []string{"first\n", "second\n"} {
+		if err := CopyTranscript(strings.NewReader(text), dir, TranscriptSettings{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, transcriptCurrent))
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("transcript = %q", data)
+	}
+}
+
+func TestPruneTranscriptsRemovesStaleSessions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	for id, age := range map[string]time.Duration{"old": 20 * 24 * time.Hour, "fresh": time.Hour} {
+		dir, _ := TranscriptDir(id)
+		if err := CopyTranscript(strings.NewReader("output\n"), dir, TranscriptSettings{}); err != nil {
+			t.Fatal(err)
+		}
+		stamp := now.Add(-age)
+		_ = os.Chtimes(filepath.Join(dir, transcriptCurrent), stamp, stamp)
+	}
+
+	removed, err := PruneTranscripts(TranscriptSettings{}, now)
+	if err != nil || removed != 1 {
+		t.Fatalf("PruneTranscripts = %d, %v; want 1 removed", removed, err)
+	}
+	for id, want := range map[string]bool{"old": false, "fresh": true} {
+		dir, _ := TranscriptDir(id)
+		if _, err := os.Stat(dir); (err == nil) != want {
+			t.Errorf("%s transcripts kept = %v, want %v", id, err == nil, want)
+		}
+	}
+}
+
+func TestTranscriptDirRejectsPaths(t *testing.T) {
+	for _, id := range []string{"", "..", "a/b", `a\b`} {
+		if _, err := TranscriptDir(id); err == nil {
+			t.Errorf("TranscriptDir(%q) should fail", id)
+		}
+	}
+}
+
+func TestPlainTranscript(t *testing.T) {
+	raw := "\x1b[32mok\x1b[0m\r\nDownloading 10%\rDownloading 100%\r\ndone"
+	want := "ok\nDownloading 100%\ndone"
+	if got := PlainTranscript(raw); got != want {
+		t.Errorf("PlainTranscript = %q, want %q", got, want)
+	}
+}
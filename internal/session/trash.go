@@ -0,0 +1,312 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+var trashLog = logging.ForComponent(logging.CompSession)
+
+// TrashDirName is the directory under ~/.agent-deck holding soft-deleted state
+const TrashDirName = "trash"
+
+// trashEntryFile is the metadata file written inside each trash entry directory
+const trashEntryFile = "entry.json"
+
+// trashPayloadDir holds moved files (e.g. a conductor directory) inside a trash entry
+const trashPayloadDir = "payload"
+
+// TrashKind identifies what a trash entry contains
+type TrashKind string
+
+const (
+	TrashKindInstance  TrashKind = "instance"
+	TrashKindConductor TrashKind = "conductor"
+)
+
+// TrashEntry describes one soft-deleted instance or conductor.
+// Entries live in ~/.agent-deck/trash/<id>/ until they expire or are restored.
+type TrashEntry struct {
+	ID        string    `json:"id"`
+	Kind      TrashKind `json:"kind"`
+	Name      string    `json:"name"`
+	Profile   string    `json:"profile,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Instance is the stored session row (kind=instance only)
+	Instance *statedb.InstanceRow `json:"instance,omitempty"`
+}
+
+// Expired reports whether the entry is past its retention window
+func (e *TrashEntry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+// TrashDir returns the trash directory (~/.agent-deck/trash)
+func TrashDir() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, TrashDirName), nil
+}
+
+// trashEntryDir returns the directory for a trash entry, rejecting path traversal
+func trashEntryDir(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid trash id: %q", id)
+	}
+	base, err := TrashDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, id), nil
+}
+
+// newTrashEntry builds an entry stamped with the configured retention window
+func newTrashEntry(id string, kind TrashKind, name, profile string) *TrashEntry {
+	now := time.Now()
+	return &TrashEntry{
+		ID:        id,
+		Kind:      kind,
+		Name:      name,
+		Profile:   profile,
+		DeletedAt: now,
+		ExpiresAt: now.Add(GetTrashSettings().Retention()),
+	}
+}
+
+// writeTrashEntry persists entry.json, creating the entry directory if needed
+func writeTrashEntry(entry *TrashEntry) error {
+	dir, err := trashEntryDir(entry.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trash entry: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, trashEntryFile), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write trash entry: %w", err)
+	}
+	return nil
+}
+
+// LoadTrashEntry reads a single trash entry by ID
+func LoadTrashEntry(id string) (*TrashEntry, error) {
+	dir, err := trashEntryDir(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, trashEntryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("trash entry %q not found", id)
+		}
+		return nil, fmt.Errorf("failed to read trash entry: %w", err)
+	}
+	var entry TrashEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse trash entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// ListTrash returns all trash entries, newest first
+func ListTrash() ([]*TrashEntry, error) {
+	base, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(base)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash directory: %w", err)
+	}
+
+	var entries []*TrashEntry
+	for _, de := range dirEntries {
+		if !de.IsDir() {
+			continue
+		}
+		entry, err := LoadTrashEntry(de.Name())
+		if err != nil {
+			trashLog.Warn("trash_entry_unreadable",
+				slog.String("id", de.Name()),
+				slog.String("error", err.Error()))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// PurgeExpiredTrash permanently removes entries past their retention window.
+// Returns the number of entries removed.
+func PurgeExpiredTrash() (int, error) {
+	entries, err := ListTrash()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	purged := 0
+	for _, entry := range entries {
+		if !entry.Expired(now) {
+			continue
+		}
+		if err := PurgeTrashEntry(entry.ID); err != nil {
+			trashLog.Warn("trash_purge_failed",
+				slog.String("id", entry.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// PurgeTrashEntry permanently removes a single trash entry
+func PurgeTrashEntry(id string) error {
+	dir, err := trashEntryDir(id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("trash entry %q not found", id)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove trash entry: %w", err)
+	}
+	return nil
+}
+
+// TrashInstance copies an instance row into the trash and then deletes it from
+// the database. When trash is disabled it behaves exactly like DeleteInstance.
+func (s *Storage) TrashInstance(id string) error {
+	if !GetTrashSettings().GetEnabled() {
+		return s.DeleteInstance(id)
+	}
+
+	s.mu.Lock()
+	if s.db == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("storage database not initialized")
+	}
+	rows, err := s.db.LoadInstances()
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to load instances: %w", err)
+	}
+
+	var row *statedb.InstanceRow
+	for _, r := range rows {
+		if r.ID == id {
+			row = r
+			break
+		}
+	}
+	if row != nil {
+		entry := newTrashEntry(row.ID, TrashKindInstance, row.Title, s.profile)
+		entry.Instance = row
+		if err := writeTrashEntry(entry); err != nil {
+			return err
+		}
+		_, _ = PurgeExpiredTrash()
+	}
+
+	return s.DeleteInstance(id)
+}
+
+// trashConductorDir moves a conductor directory into the trash
+func trashConductorDir(name, dir string) error {
+	profile := ""
+	if meta, err := LoadConductorMeta(name); err == nil {
+		profile = meta.Profile
+	}
+
+	// Conductor names are reusable, so suffix the entry ID to keep repeated
+	// teardowns of the same name from colliding.
+	id := "conductor-" + name + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	entry := newTrashEntry(id, TrashKindConductor, name, profile)
+	if err := writeTrashEntry(entry); err != nil {
+		return err
+	}
+	entryDir, _ := trashEntryDir(id)
+	if err := os.Rename(dir, filepath.Join(entryDir, trashPayloadDir)); err != nil {
+		_ = os.RemoveAll(entryDir)
+		return fmt.Errorf("failed to move conductor to trash: %w", err)
+	}
+	_, _ = PurgeExpiredTrash()
+	return nil
+}
+
+// Undelete restores a trash entry to its original location and removes it from
+// the trash. Instances are re-inserted into their profile's database; conductors
+// have their directory moved back. Heartbeat daemons are not reinstalled.
+func Undelete(id string) (*TrashEntry, error) {
+	entry, err := LoadTrashEntry(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch entry.Kind {
+	case TrashKindInstance:
+		if entry.Instance == nil {
+			return nil, fmt.Errorf("trash entry %q has no instance data", id)
+		}
+		storage, err := NewStorageWithProfile(entry.Profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open profile %q: %w", entry.Profile, err)
+		}
+		defer storage.Close()
+		if err := storage.db.SaveInstance(entry.Instance); err != nil {
+			return nil, fmt.Errorf("failed to restore instance: %w", err)
+		}
+		_ = storage.db.AppendAudit(entry.Instance.ID, entry.Instance.Title, statedb.AuditRestored, "from trash", time.Now())
+		_ = storage.db.Touch()
+
+	case TrashKindConductor:
+		target, err := ConductorNameDir(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(target); err == nil {
+			return nil, fmt.Errorf("conductor %q already exists; tear it down before restoring", entry.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create conductor directory: %w", err)
+		}
+		entryDir, _ := trashEntryDir(id)
+		if err := os.Rename(filepath.Join(entryDir, trashPayloadDir), target); err != nil {
+			return nil, fmt.Errorf("failed to restore conductor directory: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("trash entry %q has unknown kind %q", id, entry.Kind)
+	}
+
+	if err := PurgeTrashEntry(id); err != nil {
+		trashLog.Warn("trash_cleanup_after_restore_failed",
+			slog.String("id", id),
+			slog.String("error", err.Error()))
+	}
+	return entry, nil
+}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrashInstanceAndUndelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	storage, err := NewStorageWithProfile("_test")
+	if err != nil {
+		t.Fatalf("failed to open storage: %v", err)
+	}
+	defer storage.Close()
+
+	inst := NewInstance("trash-me", "/tmp/project")
+	if err := storage.SaveWithGroups([]*Instance{inst}, nil); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	if err := storage.TrashInstance(inst.ID); err != nil {
+		t.Fatalf("TrashInstance: %v", err)
+	}
+
+	loaded, _, err := storage.LoadWithGroups()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if len(loaded) != 0 {
+		t.Fatalf("expected instance to be removed from storage, got %d", len(loaded))
+	}
+
+	entries, err := ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 trash entry, got %d", len(entries))
+	}
+	if entries[0].Kind != TrashKindInstance || entries[0].Name != "trash-me" {
+		t.Errorf("unexpected entry: kind=%q name=%q", entries[0].Kind, entries[0].Name)
+	}
+	if got := entries[0].ExpiresAt.Sub(entries[0].DeletedAt); got != 7*24*time.Hour {
+		t.Errorf("retention = %v, want 7 days", got)
+	}
+
+	if _, err := Undelete(inst.ID); err != nil {
+		t.Fatalf("Undelete: %v", err)
+	}
+
+	loaded, _, err = storage.LoadWithGroups()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].ID != inst.ID {
+		t.Fatalf("expected restored instance %s, got %d instances", inst.ID, len(loaded))
+	}
+
+	entries, _ = ListTrash()
+	if len(entries) != 0 {
+		t.Errorf("expected trash to be empty after restore, got %d", len(entries))
+	}
+}
+
+func TestTeardownConductorMovesToTrash(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	name := "trash-conductor"
+	if err := SetupConductor(name, "work", true, "", "", ""); err != nil {
+		t.Fatalf("SetupConductor: %v", err)
+	}
+	dir, _ := ConductorNameDir(name)
+
+	if err := TeardownConductor(name); err != nil {
+		t.Fatalf("TeardownConductor: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("conductor dir should be gone after teardown")
+	}
+
+	entries, err := ListTrash()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 trash entry, got %d (err=%v)", len(entries), err)
+	}
+	entry := entries[0]
+	if entry.Kind != TrashKindConductor || entry.Name != name || entry.Profile != "work" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	if _, err := Undelete(entry.ID); err != nil {
+		t.Fatalf("Undelete: %v", err)
+	}
+	meta, err := LoadConductorMeta(name)
+	if err != nil {
+		t.Fatalf("meta should be restored: %v", err)
+	}
+	if meta.Profile != "work" {
+		t.Errorf("restored profile = %q, want work", meta.Profile)
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	fresh := newTrashEntry("fresh", TrashKindInstance, "fresh", "")
+	expired := newTrashEntry("expired", TrashKindInstance, "expired", "")
+	expired.ExpiresAt = time.Now().Add(-time.Hour)
+	for _, e := range []*TrashEntry{fresh, expired} {
+		if err := writeTrashEntry(e); err != nil {
+			t.Fatalf("writeTrashEntry: %v", err)
+		}
+	}
+
+	n, err := PurgeExpiredTrash()
+	if err != nil {
+		t.Fatalf("PurgeExpiredTrash: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("purged %d entries, want 1", n)
+	}
+	base, _ := TrashDir()
+	if _, err := os.Stat(filepath.Join(base, "expired")); !os.IsNotExist(err) {
+		t.Error("expired entry should be removed")
+	}
+	if _, err := LoadTrashEntry("fresh"); err != nil {
+		t.Errorf("fresh entry should remain: %v", err)
+	}
+}
+
+func TestTrashEntryDirRejectsTraversal(t *testing.T) {
+	for _, id := range []string{"", "..", "../x", "a/b"} {
+		if _, err := trashEntryDir(id); err == nil {
+			t.Errorf("trashEntryDir(%q) should fail", id)
+		}
+	}
+}
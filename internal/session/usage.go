@@ -0,0 +1,189 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// TokenUsage is token usage and estimated cost, read from session transcripts
+type TokenUsage struct {
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	CacheReadTokens  int     `json:"cache_read_tokens"`
+	CacheWriteTokens int     `json:"cache_write_tokens"`
+	Cost             float64 `json:"cost_usd"`
+}
+
+// TotalTokens returns the sum of all token types
+func (u *TokenUsage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheWriteTokens
+}
+
+// Add adds other to u
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheReadTokens += other.CacheReadTokens
+	u.CacheWriteTokens += other.CacheWriteTokens
+	u.Cost += other.Cost
+}
+
+// UsageStats returns the session's token usage and estimated cost in
+// [from, to), read from its transcript. Only Claude and Codex sessions log
+// usage; other tools report none. A zero from counts from the beginning.
+func (i *Instance) UsageStats(from, to time.Time) TokenUsage {
+	var total TokenUsage
+	for _, day := range instanceDailyUsage(i, from, to) {
+		total.Add(TokenUsage{
+			InputTokens:      day.InputTokens,
+			OutputTokens:     day.OutputTokens,
+			CacheReadTokens:  day.CacheReadTokens,
+			CacheWriteTokens: day.CacheWriteTokens,
+			Cost:             day.EstimatedCost,
+		})
+	}
+	return total
+}
+
+// conductorOf names the conductor a session works for: its own name for a
+// conductor session, else the conductor that created it according to its
+// start reason or the spawn audit log ("" for none). inst is nil for
+// sessions deleted since.
+func conductorOf(db *statedb.StateDB, id string, inst *Instance) string {
+	if inst != nil {
+		if name, ok := ConductorNameFromTitle(inst.Title); ok {
+			return name
+		}
+		if kind, ref, _ := strings.Cut(inst.StartReason, ":"); kind == StartReasonConductor && ref != "" {
+			return ref
+		}
+	}
+	if db == nil {
+		return ""
+	}
+	rows, err := db.ListAudit(id, 0)
+	if err != nil {
+		return ""
+	}
+	for _, row := range rows {
+		if row.Action == statedb.AuditSpawned {
+			return row.Detail
+		}
+	}
+	return ""
+}
+
+// Usage report periods
+const (
+	UsagePeriodDay  = "day"
+	UsagePeriodWeek = "week" // Monday to Sunday
+)
+
+// Usage report groupings
+const (
+	UsageBySession   = "session"
+	UsageByConductor = "conductor"
+	UsageByProfile   = "profile"
+)
+
+// UsageRow is the token usage of one group (a session, conductor or profile)
+// in one period
+type UsageRow struct {
+	// Period is the first day of the period (a Monday for weeks)
+	Period  string `json:"period"`
+	Profile string `json:"profile"`
+	// Key names the group: a session's title (its ID once deleted), a
+	// conductor's name ("" for sessions no conductor created) or the profile
+	Key string `json:"key"`
+	// Sessions counts the sessions that used tokens
+	Sessions int `json:"sessions"`
+	TokenUsage
+}
+
+// UsagePeriodStart returns the first day of the period containing day (a
+// DayKey)
+func UsagePeriodStart(day, period string) (string, error) {
+	if period == UsagePeriodDay {
+		return day, nil
+	}
+	if period != UsagePeriodWeek {
+		return "", fmt.Errorf("unknown period %q (valid: day, week)", period)
+	}
+	t, err := time.ParseInLocation("2006-01-02", day, time.Local)
+	if err != nil {
+		return "", err
+	}
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	return statedb.DayKey(t.AddDate(0, 0, -offset)), nil
+}
+
+// SummarizeUsage totals the token usage of CostReport rows per period and
+// group, ordered by period then by cost, highest first. Rows without token
+// usage (busy time or tasks only) are left out.
+func SummarizeUsage(rows []CostReportRow, period, by string) ([]UsageRow, error) {
+	if by != UsageBySession && by != UsageByConductor && by != UsageByProfile {
+		return nil, fmt.Errorf("unknown grouping %q (valid: session, conductor, profile)", by)
+	}
+	type groupKey struct{ period, profile, key string }
+	groups := make(map[groupKey]*UsageRow)
+	sessions := make(map[groupKey]map[string]bool)
+	for _, r := range rows {
+		if r.TotalTokens() == 0 && r.Cost == 0 {
+			continue
+		}
+		start, err := UsagePeriodStart(r.Day, period)
+		if err != nil {
+			return nil, err
+		}
+		k := groupKey{period: start, profile: r.Profile}
+		switch by {
+		case UsageBySession:
+			k.key = r.Title
+			if k.key == "" {
+				k.key = r.SessionID
+			}
+		case UsageByConductor:
+			k.key = r.Conductor
+		case UsageByProfile:
+			k.key = r.Profile
+		}
+		g := groups[k]
+		if g == nil {
+			g = &UsageRow{Period: k.period, Profile: k.profile, Key: k.key}
+			groups[k] = g
+			sessions[k] = make(map[string]bool)
+		}
+		g.Add(TokenUsage{
+			InputTokens:      r.InputTokens,
+			OutputTokens:     r.OutputTokens,
+			CacheReadTokens:  r.CacheReadTokens,
+			CacheWriteTokens: r.CacheWriteTokens,
+			Cost:             r.Cost,
+		})
+		sessions[k][r.SessionID] = true
+	}
+
+	result := make([]UsageRow, 0, len(groups))
+	for k, g := range groups {
+		g.Sessions = len(sessions[k])
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := &result[i], &result[j]
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		return a.Key < b.Key
+	})
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// usageStatsFile holds the local usage counters in ~/.agent-deck
+const usageStatsFile = "usage-stats.json"
+
+// UsageStatsSettings configures the opt-in usage counters. Nothing is ever
+// sent anywhere: counts stay in ~/.agent-deck/usage-stats.json until the user
+// exports and shares them (e.g. in an issue).
+type UsageStatsSettings struct {
+	// Enabled turns on counting of commands and features used
+	// Default: false
+	Enabled bool `toml:"enabled"`
+}
+
+// UsageStats is the on-disk usage counters file
+type UsageStats struct {
+	Since     time.Time      `json:"since"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Counts    map[string]int `json:"counts"`
+}
+
+// UsageStatsExport is the shareable form of the counters: feature names and
+// counts plus version and platform, nothing that identifies the user
+type UsageStatsExport struct {
+	Version string         `json:"version"`
+	OS      string         `json:"os"`
+	Arch    string         `json:"arch"`
+	Since   string         `json:"since"`
+	Days    int            `json:"days"`
+	Counts  map[string]int `json:"counts"`
+}
+
+// UsageStatsEnabled reports whether [usage_stats] enabled is set
+func UsageStatsEnabled() bool {
+	config, err := LoadUserConfig()
+	return err == nil && config != nil && config.UsageStats.Enabled
+}
+
+// SetUsageStatsEnabled turns usage counting on or off in config.toml
+func SetUsageStatsEnabled(enabled bool) error {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		config = &UserConfig{}
+	}
+	config.UsageStats.Enabled = enabled
+	return SaveUserConfig(config)
+}
+
+// UsageStatsPath returns the path of the usage counters file
+func UsageStatsPath() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, usageStatsFile), nil
+}
+
+// LoadUsageStats reads the usage counters (empty when none were recorded)
+func LoadUsageStats() (*UsageStats, error) {
+	path, err := UsageStatsPath()
+	if err != nil {
+		return nil, err
+	}
+	stats := &UsageStats{Counts: make(map[string]int)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage stats: %w", err)
+	}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", usageStatsFile, err)
+	}
+	if stats.Counts == nil {
+		stats.Counts = make(map[string]int)
+	}
+	return stats, nil
+}
+
+func saveUsageStats(stats *UsageStats) error {
+	path, err := UsageStatsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write usage stats: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write usage stats: %w", err)
+	}
+	return nil
+}
+
+// RecordUsage increments the counters for features when usage stats are
+// enabled. It is best effort: failures are ignored so counting never gets in
+// the way of the command being counted. Callers pass fixed feature names
+// only, never paths, titles or other user data.
+func RecordUsage(features ...string) {
+	if len(features) == 0 || !UsageStatsEnabled() {
+		return
+	}
+	stats, err := LoadUsageStats()
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	if stats.Since.IsZero() {
+		stats.Since = now
+	}
+	stats.UpdatedAt = now
+	for _, f := range features {
+		stats.Counts[f]++
+	}
+	_ = saveUsageStats(stats)
+}
+
+// ResetUsageStats deletes the usage counters
+func ResetUsageStats() error {
+	path, err := UsageStatsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove usage stats: %w", err)
+	}
+	return nil
+}
+
+// Export returns the shareable form of the counters
+func (s *UsageStats) Export(version string, now time.Time) UsageStatsExport {
+	export := UsageStatsExport{
+		Version: version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Counts:  s.Counts,
+	}
+	if !s.Since.IsZero() {
+		export.Since = s.Since.Format("2006-01-02")
+		export.Days = int(now.Sub(s.Since).Hours()/24) + 1
+	}
+	return export
+}
+
+// SortedFeatures returns feature names by descending count, then name
+func (s *UsageStats) SortedFeatures() []string {
+	names := make([]string, 0, len(s.Counts))
+	for name := range s.Counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		if s.Counts[names[a]] != s.Counts[names[b]] {
+			return s.Counts[names[a]] > s.Counts[names[b]]
+		}
+		return names[a] < names[b]
+	})
+	return names
+}
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordUsage_OnlyWhenEnabled(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	RecordUsage("list")
+	path, _ := UsageStatsPath()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("usage stats written while disabled (stat err = %v)", err)
+	}
+
+	configDir := filepath.Join(tmpHome, ".agent-deck")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("[usage_stats]\nenabled = true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+
+	RecordUsage("list")
+	RecordUsage("list", "session", "session restart")
+
+	stats, err := LoadUsageStats()
+	if err != nil {
+		t.Fatalf("LoadUsageStats: %v", err)
+	}
+	want := map[string]int{"list": 2, "session": 1, "session restart": 1}
+	for name, n := range want {
+		if stats.Counts[name] != n {
+			t.Errorf("Counts[%q] = %d, want %d", name, stats.Counts[name], n)
+		}
+	}
+	if got := stats.SortedFeatures()[0]; got != "list" {
+		t.Errorf("most used = %q, want list", got)
+	}
+
+	export := stats.Export("1.2.3", stats.Since.Add(36*time.Hour))
+	if export.Version != "1.2.3" || export.Days != 2 || export.Counts["list"] != 2 {
+		t.Errorf("unexpected export %+v", export)
+	}
+
+	if err := ResetUsageStats(); err != nil {
+		t.Fatalf("ResetUsageStats: %v", err)
+	}
+	if stats, _ := LoadUsageStats(); len(stats.Counts) != 0 {
+		t.Errorf("counts after reset = %v", stats.Counts)
+	}
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInstanceUsageStats(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	project := t.TempDir()
+	inst := &Instance{ID: "s1", Title: "api", Tool: "claude", ProjectPath: project, ClaudeSessionID: "claude-1"}
+
+	resolved, _ := filepath.EvalSymlinks(project)
+	dir := filepath.Join(configDir, "projects", ConvertToClaudeDirName(resolved))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"type":"assistant","timestamp":"2026-03-09T10:00:00Z","message":{"usage":{"input_tokens":100,"output_tokens":10}}}
+{"type":"assistant","timestamp":"2026-03-10T10:00:00Z","message":{"usage":{"input_tokens":200,"cache_read_input_tokens":5}}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "claude-1.jsonl"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	all := inst.UsageStats(time.Time{}, time.Now())
+	if all.InputTokens != 300 || all.OutputTokens != 10 || all.TotalTokens() != 315 || all.Cost <= 0 {
+		t.Errorf("lifetime usage = %+v", all)
+	}
+	since := inst.UsageStats(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), time.Now())
+	if since.TotalTokens() != 205 {
+		t.Errorf("usage since the 10th = %+v", since)
+	}
+	if got := (&Instance{Tool: "shell"}).UsageStats(time.Time{}, time.Now()); got.TotalTokens() != 0 {
+		t.Errorf("tools without usage logs should report none: %+v", got)
+	}
+}
+
+func TestUsagePeriodStart(t *testing.T) {
+	tests := map[string]string{
+		"2026-03-09": "2026-03-09", // Monday
+		"2026-03-12": "2026-03-09",
+		"2026-03-15": "2026-03-09", // Sunday
+		"2026-03-16": "2026-03-16",
+	}
+	for day, want := range tests {
+		if got, err := UsagePeriodStart(day, UsagePeriodWeek); err != nil || got != want {
+			t.Errorf("week of %s = %q (%v), want %s", day, got, err, want)
+		}
+	}
+	if got, _ := UsagePeriodStart("2026-03-12", UsagePeriodDay); got != "2026-03-12" {
+		t.Errorf("day period = %q", got)
+	}
+	if _, err := UsagePeriodStart("2026-03-12", "month"); err == nil {
+		t.Error("unknown period should fail")
+	}
+}
+
+func TestSummarizeUsageByConductorAndWeek(t *testing.T) {
+	rows := []CostReportRow{
+		{Day: "2026-03-09", Profile: "work", SessionID: "c1", Title: "conductor-ops", Conductor: "ops", InputTokens: 100, Cost: 1},
+		{Day: "2026-03-11", Profile: "work", SessionID: "w1", Title: "worker", Conductor: "ops", OutputTokens: 50, Cost: 2},
+		{Day: "2026-03-11", Profile: "work", SessionID: "m1", Title: "manual", InputTokens: 10, Cost: 0.5},
+		{Day: "2026-03-12", Profile: "work", SessionID: "w2", Conductor: "ops", BusyTime: time.Hour}, // no tokens
+		{Day: "2026-03-16", Profile: "work", SessionID: "w1", Title: "worker", Conductor: "ops", InputTokens: 7, Cost: 0.1},
+	}
+
+	got, err := SummarizeUsage(rows, UsagePeriodWeek, UsageByConductor)
+	if err != nil {
+		t.Fatalf("SummarizeUsage: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 rows, got %+v", got)
+	}
+	if got[0].Period != "2026-03-09" || got[0].Key != "ops" || got[0].Sessions != 2 || got[0].TotalTokens() != 150 || got[0].Cost != 3 {
+		t.Errorf("ops first week = %+v", got[0])
+	}
+	if got[1].Key != "" || got[1].Sessions != 1 {
+		t.Errorf("sessions without a conductor should be grouped under \"\": %+v", got[1])
+	}
+	if got[2].Period != "2026-03-16" || got[2].TotalTokens() != 7 {
+		t.Errorf("ops second week = %+v", got[2])
+	}
+
+	bySession, err := SummarizeUsage(rows, UsagePeriodDay, UsageBySession)
+	if err != nil || len(bySession) != 4 {
+		t.Fatalf("by session per day = %+v (%v)", bySession, err)
+	}
+	if _, err := SummarizeUsage(rows, UsagePeriodDay, "tool"); err == nil {
+		t.Error("unknown grouping should fail")
+	}
+}
+
+func TestConductorOf(t *testing.T) {
+	if got := conductorOf(nil, "c1", &Instance{Title: ConductorSessionTitle("ops")}); got != "ops" {
+		t.Errorf("conductor session = %q", got)
+	}
+	if got := conductorOf(nil, "w1", &Instance{Title: "worker", StartReason: StartReasonConductor + ":ops"}); got != "ops" {
+		t.Errorf("worker started by a conductor = %q", got)
+	}
+	if got := conductorOf(nil, "m1", &Instance{Title: "manual", StartReason: StartReasonManual}); got != "" {
+		t.Errorf("manual session = %q", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 
@@ -55,6 +56,14 @@ type UserConfig struct {
 	// config_dir = "~/.claude-work"
 	Profiles map[string]ProfileSettings `toml:"profiles"`
 
+	// Proxy defines the HTTP(S) proxy and CA bundle injected into sessions
+	// (overridable per profile in [profiles.<name>.proxy])
+	Proxy ProxySettings `toml:"proxy"`
+
+	// Guard defines the command guard shims put on sessions' PATH
+	// (overridable per profile in [profiles.<name>.guard])
+	Guard GuardSettings `toml:"guard"`
+
 	// Gemini defines Gemini CLI integration settings
 	Gemini GeminiSettings `toml:"gemini"`
 
@@ -64,6 +73,12 @@ type UserConfig struct {
 	// Codex defines Codex CLI integration settings
 	Codex CodexSettings `toml:"codex"`
 
+	// Ollama defines local-model (ollama) session settings
+	Ollama OllamaSettings `toml:"ollama"`
+
+	// Aider defines aider session settings
+	Aider AiderSettings `toml:"aider"`
+
 	// Worktree defines git worktree preferences
 	Worktree WorktreeSettings `toml:"worktree"`
 
@@ -79,6 +94,34 @@ type UserConfig struct {
 	// Updates defines auto-update settings
 	Updates UpdateSettings `toml:"updates"`
 
+	// UsageStats defines the opt-in, local-only usage counters
+	UsageStats UsageStatsSettings `toml:"usage_stats"`
+
+	// StatusExport writes a static deck status JSON snapshot for remote viewing
+	StatusExport StatusExportSettings `toml:"status_export"`
+
+	// Summarizer picks the backend for standup and session summaries
+	Summarizer SummarizerSettings `toml:"summarizer"`
+
+	// Audit configures the session audit log
+	Audit AuditSettings `toml:"audit"`
+
+	// StateHistory keeps periodic deck state snapshots for point-in-time queries
+	StateHistory StateHistorySettings `toml:"state_history"`
+
+	// Spawn limits which conductors may create worker sessions, and how many
+	Spawn SpawnPolicySettings `toml:"spawn"`
+
+	// Review configures "agent-deck review" sessions
+	Review ReviewSettings `toml:"review"`
+
+	// Features turns experimental subsystems on for the whole deck
+	// (see feature_flags.go); [profiles.<name>.features] overrides per profile
+	Features map[string]bool `toml:"features"`
+
+	// Chaos injects simulated failures for testing hooks, alerting and retries
+	Chaos ChaosSettings `toml:"chaos"`
+
 	// Preview defines preview pane display settings
 	Preview PreviewSettings `toml:"preview"`
 
@@ -97,20 +140,70 @@ type UserConfig struct {
 	// Maintenance defines automatic maintenance worker settings
 	Maintenance MaintenanceSettings `toml:"maintenance"`
 
+	// Battery slows heartbeats and pauses task dispatch on low battery
+	Battery BatterySettings `toml:"battery"`
+
+	// Remotes are hosts whose sessions are managed over ssh
+	// (agent-deck remote)
+	Remotes map[string]RemoteSettings `toml:"remotes"`
+
 	// Status defines session status detection settings
 	Status StatusSettings `toml:"status"`
 
+	// Sort defines session ordering and pinned sessions for every listing
+	Sort SortSettings `toml:"sort"`
+
+	// Output defines color and glyph theming for CLI status output
+	Output OutputSettings `toml:"output"`
+
+	// Time defines how timestamps are rendered (relative/absolute, zone, locale)
+	Time TimeSettings `toml:"time"`
+
+	// Registry defines where conductor templates and skill packs are fetched from
+	Registry RegistrySettings `toml:"registry"`
+
 	// Conductor defines conductor (meta-agent orchestration) settings
 	Conductor ConductorSettings `toml:"conductor"`
 
 	// Tmux defines tmux option overrides applied to every session
 	Tmux TmuxSettings `toml:"tmux"`
+
+	// Trash defines soft-delete settings for removed sessions and conductors
+	Trash TrashSettings `toml:"trash"`
+
+	// Risk defines risk scoring of sessions' recent tool calls
+	Risk RiskSettings `toml:"risk"`
+
+	// EventHooks defines commands and webhooks run on session events
+	EventHooks EventHooksSettings `toml:"event_hooks"`
+
+	// Transcripts defines continuous capture of session output
+	Transcripts TranscriptSettings `toml:"transcripts"`
+
+	// toolErrors holds the [tools] entries rejected at load, by name (see ToolDefError)
+	toolErrors map[string]error
 }
 
 // ProfileSettings defines per-profile configuration overrides.
 type ProfileSettings struct {
 	// Claude defines Claude Code overrides for a specific profile.
 	Claude ProfileClaudeSettings `toml:"claude"`
+
+	// Gemini defines Gemini CLI overrides for a specific profile.
+	Gemini ProfileGeminiSettings `toml:"gemini"`
+
+	// Proxy overrides [proxy] for a specific profile.
+	Proxy ProxySettings `toml:"proxy"`
+
+	// Guard overrides [guard] for a specific profile.
+	Guard GuardSettings `toml:"guard"`
+
+	// Features overrides [features] for a specific profile.
+	Features map[string]bool `toml:"features"`
+
+	// Classifiers sets status classifiers per tool for a specific profile,
+	// replacing [tools.<name>.classifier].
+	Classifiers map[string]ClassifierSettings `toml:"classifiers"`
 }
 
 // ProfileClaudeSettings defines profile-specific Claude overrides.
@@ -119,6 +212,18 @@ type ProfileClaudeSettings struct {
 	ConfigDir string `toml:"config_dir"`
 }
 
+// ProfileGeminiSettings defines profile-specific Gemini overrides.
+type ProfileGeminiSettings struct {
+	// DefaultModel overrides [gemini].default_model for this profile only.
+	DefaultModel string `toml:"default_model"`
+
+	// YoloMode overrides [gemini].yolo_mode for this profile only.
+	YoloMode *bool `toml:"yolo_mode"`
+
+	// Checkpointing overrides [gemini].checkpointing for this profile only.
+	Checkpointing *bool `toml:"checkpointing"`
+}
+
 // MCPPoolSettings defines HTTP MCP pool configuration
 type MCPPoolSettings struct {
 	// Enabled enables HTTP pool mode (default: false)
@@ -181,6 +286,12 @@ type LogSettings struct {
 	// DebugFormat sets the log format: "json" (default) or "text"
 	DebugFormat string `toml:"debug_format"`
 
+	// Output is where CLI commands and daemons (web --headless, conductor
+	// heartbeats) write structured logs: "stderr" or "file" (debug.log).
+	// The TUI always logs to debug.log. Default: "" (CLI logs only go to
+	// system_sink)
+	Output string `toml:"output"`
+
 	// DebugMaxMB is the max size in MB for debug.log before rotation
 	// Default: 10
 	DebugMaxMB int `toml:"debug_max_mb"`
@@ -208,6 +319,15 @@ type LogSettings struct {
 	// AggregateIntervalS is the event aggregation flush interval in seconds
 	// Default: 30
 	AggregateIntervalS int `toml:"aggregate_interval_secs"`
+
+	// SystemSink also routes structured logs to the host's log service:
+	// "journald" (with fields) or "syslog". Find entries with
+	// journalctl --user -t agent-deck. Default: "" (files only)
+	SystemSink string `toml:"system_sink"`
+
+	// SystemLevel is the minimum level sent to the system sink:
+	// "debug", "info", "warn", "error". Default: "info"
+	SystemLevel string `toml:"system_level"`
 }
 
 // UpdateSettings defines auto-update configuration
@@ -287,6 +407,9 @@ type NotificationsConfig struct {
 
 	// ShowAll displays all sessions (with status icons) instead of only waiting sessions (default: false)
 	ShowAll bool `toml:"show_all"`
+
+	// Desktop configures desktop notifications when sessions finish working
+	Desktop DesktopNotifySettings `toml:"desktop"`
 }
 
 // InstanceSettings configures multiple agent-deck instance behavior
@@ -448,6 +571,29 @@ func (c *UserConfig) GetProfileClaudeConfigDir(profile string) string {
 	return ExpandPath(profileCfg.Claude.ConfigDir)
 }
 
+// GetProfileGeminiSettings returns [gemini] with the profile's
+// [profiles.<profile>.gemini] overrides applied.
+func (c *UserConfig) GetProfileGeminiSettings(profile string) GeminiSettings {
+	if c == nil {
+		return GeminiSettings{}
+	}
+	settings := c.Gemini
+	profileCfg, ok := c.Profiles[profile]
+	if profile == "" || !ok {
+		return settings
+	}
+	if profileCfg.Gemini.DefaultModel != "" {
+		settings.DefaultModel = profileCfg.Gemini.DefaultModel
+	}
+	if profileCfg.Gemini.YoloMode != nil {
+		settings.YoloMode = *profileCfg.Gemini.YoloMode
+	}
+	if profileCfg.Gemini.Checkpointing != nil {
+		settings.Checkpointing = *profileCfg.Gemini.Checkpointing
+	}
+	return settings
+}
+
 // GetDangerousMode returns whether dangerous mode is enabled, defaulting to true
 // Power users (the primary audience) typically want this enabled for faster iteration
 func (c *ClaudeSettings) GetDangerousMode() bool {
@@ -475,6 +621,11 @@ type GeminiSettings struct {
 	// If empty, Gemini CLI uses its own default
 	DefaultModel string `toml:"default_model"`
 
+	// Checkpointing enables --checkpointing: Gemini snapshots the project
+	// before each file-modifying tool call, restorable with /restore
+	// Default: false
+	Checkpointing bool `toml:"checkpointing"`
+
 	// EnvFile is a .env file specific to Gemini sessions
 	// Sourced AFTER global [shell].env_files
 	// Path can be absolute, ~ for home, $HOME/${VAR} for env vars, or relative to session working directory
@@ -503,6 +654,10 @@ type CodexSettings struct {
 	// YoloMode enables --yolo flag for Codex sessions (bypass approvals and sandbox)
 	// Default: false
 	YoloMode bool `toml:"yolo_mode"`
+
+	// DefaultModel is passed as -m to new Codex sessions (e.g., "gpt-5-codex")
+	// If empty, Codex uses its own default (~/.codex/config.toml)
+	DefaultModel string `toml:"default_model"`
 }
 
 // WorktreeSettings contains git worktree preferences.
@@ -556,7 +711,9 @@ type GlobalSearchSettings struct {
 
 // ToolDef defines a custom AI tool
 type ToolDef struct {
-	// Command is the shell command to run
+	// Command is the shell command to run. It may use the placeholders
+	// {path} and {title} (shell-quoted) and {id} (the agent-deck session ID).
+	// Example: command = "mytool --project {path}"
 	Command string `toml:"command"`
 
 	// Wrapper is an optional command that wraps the tool command.
@@ -582,6 +739,16 @@ type ToolDef struct {
 	// SessionIDEnv is the tmux environment variable name storing the session ID
 	SessionIDEnv string `toml:"session_id_env"`
 
+	// ResumeCommand is the command that resumes a session, used instead of
+	// command + resume_flag. Placeholders: {session_id} (required), {command},
+	// {path}, {title}, {id}. Needs session_id_env.
+	// Example: resume_command = "mytool chat --continue {session_id}"
+	ResumeCommand string `toml:"resume_command"`
+
+	// WorkingDir is where sessions of the tool start: "project" (default),
+	// "git_root", "home", or a path (~ and $VAR expand; relative to the project)
+	WorkingDir string `toml:"working_dir"`
+
 	// DangerousMode enables dangerous mode flag for this tool
 	DangerousMode bool `toml:"dangerous_mode"`
 
@@ -606,6 +773,9 @@ type ToolDef struct {
 
 	// Pattern override fields (extend built-in defaults for claude/gemini/opencode/codex)
 	// Patterns prefixed with "re:" are compiled as regex; everything else uses strings.Contains.
+	// An option block narrows a pattern: "{start; last=3; unless=X; not; prio=N}pattern"
+	// (see tmux.PatternRule). An installed pattern pack ("agent-deck patterns")
+	// replaces the built-in defaults first; these fields apply on top of it.
 
 	// BusyPatternsExtra appends additional busy patterns to the built-in defaults
 	BusyPatternsExtra []string `toml:"busy_patterns_extra"`
@@ -618,6 +788,47 @@ type ToolDef struct {
 
 	// SpinnerCharsExtra appends additional spinner characters to the built-in defaults
 	SpinnerCharsExtra []string `toml:"spinner_chars_extra"`
+
+	// StatusLines is how many trailing pane lines busy/prompt patterns inspect (default: 25)
+	StatusLines int `toml:"status_lines"`
+
+	// CaptureAltScreen also captures the pane's inactive screen buffer for status
+	// detection, for tools whose state is hidden behind a full-screen TUI (default: false)
+	CaptureAltScreen bool `toml:"capture_alt_screen"`
+
+	// MatchANSI keeps ANSI escape sequences when matching busy/prompt patterns so
+	// "re:" patterns can match colors; by default they are stripped (default: false)
+	MatchANSI bool `toml:"match_ansi"`
+
+	// StripSpinnerFrames trims spinner characters off line ends, and drops
+	// lines holding only a spinner frame, before patterns match (default: false)
+	StripSpinnerFrames bool `toml:"strip_spinner_frames"`
+
+	// CollapseProgress keeps only the last of consecutive lines differing in
+	// numbers only, e.g. download progress (default: false)
+	CollapseProgress bool `toml:"collapse_progress"`
+
+	// BannerEnd marks the last line of the tool's startup banner (text, or
+	// "re:" regex); the screen above it is ignored by status detection
+	BannerEnd string `toml:"banner_end"`
+
+	// ReadyProcess is a process name that must be running inside the pane
+	// before a prompt match counts as ready (e.g. "node"). If it is gone, the
+	// session shows as errored instead of waiting on a stale screen.
+	ReadyProcess string `toml:"ready_process"`
+
+	// ReadyPort is a local TCP port the tool listens on once it is up; a
+	// prompt match only counts as ready while the port accepts connections
+	ReadyPort int `toml:"ready_port"`
+
+	// Classifier is an external program that classifies the tool's pane,
+	// for states patterns cannot detect (see ClassifierSettings)
+	Classifier ClassifierSettings `toml:"classifier"`
+
+	// Macros are named in-tool actions (slash commands or key presses) run via
+	// "agent-deck session macro", the API or the TUI; they extend and override
+	// the built-in ones. Example: [tools.claude.macros.review] text = "/review"
+	Macros map[string]MacroDef `toml:"macros"`
 }
 
 // HTTPServerConfig defines how to auto-start an HTTP MCP server
@@ -719,6 +930,23 @@ type TmuxSettings struct {
 	// Options is a map of tmux option names to values.
 	// These are passed to `tmux set-option -t <session>` after defaults.
 	Options map[string]string `toml:"options"`
+
+	// CheckOptions controls the startup check of tmux options agent-deck
+	// depends on (history-limit, escape-time, default-terminal, focus-events):
+	// "warn" reports values that break capture or input injection, "fix" also
+	// sets them (server options server-wide, session options on agent-deck's
+	// sessions only), "off" skips the check.
+	// Default: "warn"
+	CheckOptions string `toml:"check_options"`
+}
+
+// GetCheckOptions returns the tmux option check mode, defaulting to "warn"
+func (t TmuxSettings) GetCheckOptions() string {
+	switch t.CheckOptions {
+	case "fix", "off":
+		return t.CheckOptions
+	}
+	return "warn"
 }
 
 // GetInjectStatusLine returns whether to inject status line, defaulting to true
@@ -729,9 +957,87 @@ func (t TmuxSettings) GetInjectStatusLine() bool {
 	return *t.InjectStatusLine
 }
 
+// StatusSettings controls status detection.
+// Control mode pipes are always enabled (no longer configurable).
 type StatusSettings struct {
-	// Reserved for future status detection settings.
-	// Control mode pipes are always enabled (no longer configurable).
+	// DwellPolls is how many consecutive polls a newly detected running/waiting/idle
+	// status must hold before it is reported, to absorb flapping during redraws.
+	// Default: 1 (report immediately)
+	DwellPolls int `toml:"dwell_polls"`
+
+	// StateDwellPolls overrides DwellPolls for specific target states,
+	// e.g. { waiting = 3, idle = 2 }. Keys: running, waiting, idle.
+	StateDwellPolls map[string]int `toml:"state_dwell_polls"`
+
+	// Fusion weighs CPU, output, window activity and patterns together so
+	// silent work keeps a session running (see StatusFusionSettings)
+	Fusion StatusFusionSettings `toml:"fusion"`
+}
+
+// DwellFor returns how many consecutive polls are required before switching to status.
+func (s StatusSettings) DwellFor(status Status) int {
+	if n, ok := s.StateDwellPolls[string(status)]; ok && n > 0 {
+		return n
+	}
+	if s.DwellPolls > 0 {
+		return s.DwellPolls
+	}
+	return 1
+}
+
+// SortSettings controls how sessions are ordered within each group.
+type SortSettings struct {
+	// Mode is "manual" (reorder with the TUI, default), "activity" (most
+	// recently active first) or "severity" (error, waiting, running, idle).
+	Mode string `toml:"mode"`
+
+	// Pinned lists session IDs or titles shown first in their group, in this order.
+	// Manage with: agent-deck session pin/unpin <id|title>
+	Pinned []string `toml:"pinned"`
+}
+
+// OutputSettings controls how statuses are rendered in CLI output and TUI glyphs.
+// The NO_COLOR environment variable always disables color.
+type OutputSettings struct {
+	// Color is "auto" (color only on a terminal, default), "always" or "never"
+	Color string `toml:"color"`
+
+	// ScreenReader prints status words instead of glyphs and disables color
+	// Default: false
+	ScreenReader bool `toml:"screen_reader"`
+
+	// Glyphs overrides status glyphs, e.g. { running = "R", waiting = "?" }
+	// Keys: running, waiting, idle, error, starting
+	Glyphs map[string]string `toml:"glyphs"`
+
+	// Colors overrides status colors as hex ("#9ece6a") or ANSI numbers ("2")
+	Colors map[string]string `toml:"colors"`
+}
+
+// TimeSettings controls how timestamps are shown in listings and the TUI.
+type TimeSettings struct {
+	// Format is "relative" ("2h ago", default) or "absolute"
+	Format string `toml:"format"`
+
+	// Timezone is an IANA zone for absolute times (default: system local).
+	// Conductors can override it with their own timezone in meta.json.
+	Timezone string `toml:"timezone"`
+
+	// Locale selects the absolute date layout, e.g. "de_DE"
+	// Default: LC_ALL, LC_TIME or LANG
+	Locale string `toml:"locale"`
+}
+
+// RegistrySettings configures the template and skill pack registry used by
+// registry://name@version references.
+type RegistrySettings struct {
+	// URL is an HTTP(S) base URL serving index.json, or a git repository
+	// (ending in .git, or prefixed with git+) with index.json at its root
+	URL string `toml:"url"`
+
+	// PublicKey is a base64 ed25519 public key. When set, every archive must
+	// carry a valid signature in the index.
+	PublicKey string `toml:"public_key"`
 }
 
 // MaintenanceSettings controls the automatic maintenance worker
@@ -739,6 +1045,89 @@ type MaintenanceSettings struct {
 	// Enabled enables the maintenance worker (default: false)
 	// Prunes Gemini logs, cleans old backups, archives bloated sessions
 	Enabled bool `toml:"enabled"`
+
+	// Windows are scheduled maintenance windows that pause heartbeats, conductor
+	// task dispatch and automatic conductor restarts (see MaintenanceWindow).
+	// More can be added with 'agent-deck maintenance start|schedule'.
+	Windows []MaintenanceWindow `toml:"windows"`
+}
+
+// TrashSettings controls soft-delete of sessions and conductors.
+// Removed items are kept in ~/.agent-deck/trash until the retention window
+// expires, and can be restored with `agent-deck trash restore <id>`.
+type TrashSettings struct {
+	// Enabled keeps removed sessions and conductors in the trash
+	// Default: true (nil = use default true)
+	Enabled *bool `toml:"enabled"`
+
+	// RetentionDays is how long trashed items are kept before being purged
+	// Default: 7
+	RetentionDays int `toml:"retention_days"`
+}
+
+// RiskSettings configures the risk indicator computed from each busy
+// session's recent tool calls (see AssessRisk)
+type RiskSettings struct {
+	// Enabled scores recent tool calls of running sessions
+	// Default: true (nil = use default true)
+	Enabled *bool `toml:"enabled"`
+
+	// AutoStop triggers the emergency stop when a critical rule matches
+	// Default: false
+	AutoStop bool `toml:"auto_stop"`
+
+	// RecentCalls is how many recent transcript tool calls are scored
+	// Default: 20
+	RecentCalls int `toml:"recent_calls"`
+
+	// Rules add to the built-in rules, or replace one with the same name
+	Rules []RiskRuleDef `toml:"rules"`
+}
+
+// RiskRuleDef is a [[risk.rules]] entry
+type RiskRuleDef struct {
+	// Name identifies the rule in matches; reuse a built-in name to replace it
+	Name string `toml:"name"`
+
+	// Pattern is a regex matched against each tool call (a shell command for
+	// Bash calls, else the tool name and its JSON input) or pane line
+	Pattern string `toml:"pattern"`
+
+	// Level is "low", "medium", "high" or "critical"; "off" disables the rule
+	Level string `toml:"level"`
+}
+
+// GetEnabled returns whether risk scoring is enabled, defaulting to true
+func (r RiskSettings) GetEnabled() bool {
+	if r.Enabled == nil {
+		return true
+	}
+	return *r.Enabled
+}
+
+// GetRecentCalls returns how many recent tool calls are scored, defaulting to 20
+func (r RiskSettings) GetRecentCalls() int {
+	if r.RecentCalls <= 0 {
+		return 20
+	}
+	return r.RecentCalls
+}
+
+// GetEnabled returns whether soft-delete is enabled, defaulting to true
+func (t TrashSettings) GetEnabled() bool {
+	if t.Enabled == nil {
+		return true
+	}
+	return *t.Enabled
+}
+
+// Retention returns the retention window, defaulting to 7 days
+func (t TrashSettings) Retention() time.Duration {
+	days := t.RetentionDays
+	if days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
 // Default user config (empty maps)
@@ -809,6 +1198,7 @@ func LoadUserConfig() (*UserConfig, error) {
 	if config.MCPs == nil {
 		config.MCPs = make(map[string]MCPDef)
 	}
+	config.toolErrors = validateToolDefs(&config)
 
 	userConfigCache = &config
 	return userConfigCache, nil
@@ -920,7 +1310,7 @@ func GetToolDef(toolName string) *ToolDef {
 }
 
 // GetCustomToolNames returns sorted custom tool names from config.toml,
-// excluding names that shadow built-in tools (claude, gemini, opencode, codex, shell, cursor, aider).
+// excluding names that shadow built-in tools (claude, gemini, opencode, codex, ollama, shell, cursor, aider).
 // Returns nil if no custom tools are configured.
 func GetCustomToolNames() []string {
 	config, err := LoadUserConfig()
@@ -928,14 +1318,9 @@ func GetCustomToolNames() []string {
 		return nil
 	}
 
-	builtins := map[string]bool{
-		"claude": true, "gemini": true, "opencode": true,
-		"codex": true, "shell": true, "cursor": true, "aider": true,
-	}
-
 	var names []string
 	for name := range config.Tools {
-		if !builtins[name] {
+		if !IsBuiltinTool(name) {
 			names = append(names, name)
 		}
 	}
@@ -963,6 +1348,10 @@ func GetToolIcon(toolName string) string {
 		return "🌐"
 	case "codex":
 		return "💻"
+	case "ollama":
+		return "🦙"
+	case "aider":
+		return "🤝"
 	case "cursor":
 		return "📝"
 	case "shell":
@@ -986,11 +1375,15 @@ func GetToolBusyPatterns(toolName string) []string {
 }
 
 // MergeToolPatterns returns merged RawPatterns for a tool, combining built-in
-// defaults with any user overrides/extras from config.toml.
+// defaults, the active pattern pack (see ActivePatternPack) and any user
+// overrides/extras from config.toml, in that order.
 // Works for ALL tools: built-in (claude, gemini, etc.) and custom.
-// Returns nil only if there are no defaults AND no config entry.
+// Returns nil only if there are no defaults, no pack AND no config entry.
 func MergeToolPatterns(toolName string) *tmux.RawPatterns {
-	defaults := tmux.DefaultRawPatterns(toolName)
+	defaults := defaultToolPatterns(toolName)
+	if pack := ActivePatternPack(toolName); pack != nil {
+		defaults = tmux.MergeRawPatterns(defaults, pack.Patterns.raw(), nil)
+	}
 	toolDef := GetToolDef(toolName)
 
 	// No defaults and no config entry: nothing to do
@@ -999,12 +1392,24 @@ func MergeToolPatterns(toolName string) *tmux.RawPatterns {
 	}
 
 	// Build overrides from ToolDef's replace fields (BusyPatterns, PromptPatterns, SpinnerChars)
+	// and capture options
 	var overrides *tmux.RawPatterns
-	if toolDef != nil && (toolDef.BusyPatterns != nil || toolDef.PromptPatterns != nil || toolDef.SpinnerChars != nil) {
-		overrides = &tmux.RawPatterns{
-			BusyPatterns:   toolDef.BusyPatterns,
-			PromptPatterns: toolDef.PromptPatterns,
-			SpinnerChars:   toolDef.SpinnerChars,
+	if toolDef != nil {
+		capture := tmux.CaptureOptions{
+			Lines:              toolDef.StatusLines,
+			AltScreen:          toolDef.CaptureAltScreen,
+			MatchANSI:          toolDef.MatchANSI,
+			StripSpinnerFrames: toolDef.StripSpinnerFrames,
+			CollapseProgress:   toolDef.CollapseProgress,
+			BannerEnd:          toolDef.BannerEnd,
+		}
+		if toolDef.BusyPatterns != nil || toolDef.PromptPatterns != nil || toolDef.SpinnerChars != nil || !capture.IsZero() {
+			overrides = &tmux.RawPatterns{
+				BusyPatterns:   toolDef.BusyPatterns,
+				PromptPatterns: toolDef.PromptPatterns,
+				SpinnerChars:   toolDef.SpinnerChars,
+				Capture:        capture,
+			}
 		}
 	}
 
@@ -1022,6 +1427,20 @@ func MergeToolPatterns(toolName string) *tmux.RawPatterns {
 	return tmux.MergeRawPatterns(defaults, overrides, extras)
 }
 
+// ToolReadyProbe returns the process and port checks configured for a tool,
+// or nil if there are none
+func ToolReadyProbe(toolName string) *tmux.ReadyProbe {
+	toolDef := GetToolDef(toolName)
+	if toolDef == nil {
+		return nil
+	}
+	probe := &tmux.ReadyProbe{Process: toolDef.ReadyProcess, Port: toolDef.ReadyPort}
+	if probe.IsZero() {
+		return nil
+	}
+	return probe
+}
+
 // GetDefaultTool returns the user's preferred default tool for new sessions
 // Returns empty string if not configured (defaults to shell)
 func GetDefaultTool() string {
@@ -1240,6 +1659,42 @@ func GetStatusSettings() StatusSettings {
 	return config.Status
 }
 
+// GetSortSettings returns session ordering settings from config
+func GetSortSettings() SortSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return SortSettings{}
+	}
+	return config.Sort
+}
+
+// GetOutputSettings returns output theming settings from config
+func GetOutputSettings() OutputSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return OutputSettings{}
+	}
+	return config.Output
+}
+
+// GetRegistrySettings returns template/skill pack registry settings from config
+func GetRegistrySettings() RegistrySettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return RegistrySettings{}
+	}
+	return config.Registry
+}
+
+// GetTimeSettings returns timestamp display settings from config
+func GetTimeSettings() TimeSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return TimeSettings{}
+	}
+	return config.Time
+}
+
 // GetTmuxSettings returns tmux option overrides from config
 func GetTmuxSettings() TmuxSettings {
 	config, err := LoadUserConfig()
@@ -1249,6 +1704,24 @@ func GetTmuxSettings() TmuxSettings {
 	return config.Tmux
 }
 
+// GetTrashSettings returns soft-delete settings from config
+func GetTrashSettings() TrashSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return TrashSettings{} // Defaults applied via GetEnabled() and Retention()
+	}
+	return config.Trash
+}
+
+// GetRiskSettings returns risk scoring settings
+func GetRiskSettings() RiskSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return RiskSettings{} // Defaults applied via GetEnabled() and GetRecentCalls()
+	}
+	return config.Risk
+}
+
 // GetInstanceSettings returns instance behavior settings
 func GetInstanceSettings() InstanceSettings {
 	config, err := LoadUserConfig()
@@ -1342,10 +1815,39 @@ func CreateExampleConfig() error {
 # Enable --dangerously-skip-permissions by default (default: false)
 # dangerous_mode = true
 
+# Proxy and custom CA for agents behind a corporate (TLS-intercepting) proxy.
+# Exported into every session; [profiles.<name>.proxy] overrides per profile.
+# [proxy]
+# http_proxy = "http://proxy.corp.example:3128"
+# https_proxy = "http://proxy.corp.example:3128"   # default: http_proxy
+# no_proxy = ["localhost", "127.0.0.1", ".corp.example"]
+# PEM bundle with the system roots plus the corporate root CA
+# ca_bundle = "~/.config/corp/ca-bundle.pem"
+# [profiles.work.proxy]
+# http_proxy = "http://proxy.work.example:8080"
+
+# Command guard: shims for dangerous binaries first on sessions' PATH.
+# A guardrail, not a sandbox (the real binary is still reachable by full path).
+# [guard]
+# off (default), log (run and record in the audit log) or approve (wait for
+# 'agent-deck guard approve <id>')
+# mode = "log"
+# commands = ["kubectl", "terraform", "aws"]
+# Seconds an approve-mode command waits before it is denied (default: 300)
+# approval_timeout = 300
+# [profiles.work.guard]
+# mode = "approve"
+
 # Gemini CLI integration
 # [gemini]
 # Enable --yolo (auto-approve all actions) by default (default: false)
 # yolo_mode = true
+# Default model for new sessions (default: Gemini's own)
+# default_model = "gemini-2.5-pro"
+# Snapshot files before each edit so /restore can undo it (default: false)
+# checkpointing = true
+# [profiles.work.gemini]
+# default_model = "gemini-2.5-flash"
 
 # OpenCode CLI integration
 # [opencode]
@@ -1358,6 +1860,22 @@ func CreateExampleConfig() error {
 # [codex]
 # Enable --yolo (bypass approvals and sandbox) by default (default: false)
 # yolo_mode = true
+# Default model for new sessions (default: Codex's own)
+# default_model = "gpt-5-codex"
+
+# Local models via ollama (tool "ollama"); restarts save and resume the chat
+# [ollama]
+# Model run by new sessions (default: llama3.2)
+# model = "qwen2.5-coder:7b"
+# ollama server, exported as OLLAMA_HOST (default: ollama's own)
+# host = "http://127.0.0.1:11434"
+
+# aider (tool "aider"); restarts replay the session's chat history
+# [aider]
+# Model passed as --model (default: aider's choice from your API keys)
+# model = "sonnet"
+# Commit after every edit; these commits are the session's checkpoints (default: true)
+# auto_commits = false
 
 # Log file management
 # Agent-deck logs session output to ~/.agent-deck/logs/ for status detection
@@ -1369,6 +1887,11 @@ max_size_mb = 10
 max_lines = 10000
 # Remove log files for sessions that no longer exist (default: true)
 remove_orphans = true
+# Also send structured logs (conductor activity, heartbeats, status) to the
+# host's log service: "journald" or "syslog". View with:
+#   journalctl --user -t agent-deck
+# system_sink = "journald"
+# system_level = "info"
 
 # Update settings
 # Controls automatic update checking and installation
@@ -1382,6 +1905,84 @@ check_interval_hours = 24
 # Show update notification in CLI commands, not just TUI (default: true)
 notify_in_cli = true
 
+# Local usage counters (commands used), off by default. Nothing is sent
+# anywhere; share them in an issue with: agent-deck stats export
+# [usage_stats]
+# enabled = true
+
+# Limit the sessions conductors create from their session (add, launch, try,
+# session fork), so a misbehaving conductor cannot flood the deck. Refused
+# spawns show in 'agent-deck session audit'; with --json a used-up quota is
+# reported as code QUOTA_EXCEEDED. Sessions you create are never limited.
+# [spawn]
+# allowed_conductors = ["ops", "triage"]   # default: every conductor
+# max_workers = 10                         # live sessions per conductor
+# max_per_hour = 20
+# max_per_day = 100
+# max_tokens_per_day = 5000000             # tokens its Claude workers used today
+# allowed_tools = ["claude", "codex"]
+# allowed_templates = ["review"]           # require --reason template:<name>
+# [spawn.conductors.ops]
+# max_workers = 25
+
+# Review sessions ("agent-deck review <branch>") open the branch in a
+# worktree, send its diff in chunks, then the review rubric.
+# [review]
+# tool = "claude"
+# base_branch = "main"                     # default: the repo's default branch
+# chunk_size = 12000                       # bytes per diff message
+# rubric = "~/.agent-deck/review-rubric.md"
+
+# Static status snapshot for remote visibility without any infrastructure:
+# the conductor bridge and heartbeats rewrite a compact JSON file here, e.g.
+# in a directory served by a web server or synced by Dropbox. Write it once
+# with: agent-deck status export
+# [status_export]
+# path = "~/Dropbox/agent-deck-status.json"
+# interval = 60           # seconds between bridge rewrites
+# hide_sessions = false   # true exports only status counts
+
+# The TUI records the deck's sessions and statuses every interval minutes
+# (only when something changed) so you can ask what was running earlier:
+# agent-deck status --at 03:00
+# [state_history]
+# interval = 5      # negative disables
+# keep_days = 14
+
+# Summaries (session output --summarize, standups with summarize = true) use
+# the heuristic extractor by default, which needs no model. Point them at a
+# local ollama model or a cheap API model instead:
+# [summarizer]
+# backend = "heuristic"   # heuristic, ollama or api
+# model = "llama3.2"      # ollama default; api default: claude-haiku-4-5
+# endpoint = ""           # default http://localhost:11434 or https://api.anthropic.com
+# api_key_env = "ANTHROPIC_API_KEY"
+# max_lines = 8
+
+# Record every heartbeat and prompt delivered to a conductor in the audit log,
+# signed with the deck's ed25519 key, so the log proves what text reached the
+# conductor and when. Check with: agent-deck session audit --verify
+# [audit]
+# sign_deliveries = true
+# key_path = "~/.agent-deck/deck.key"   # created on first use
+
+# Experimental features, off by default. Turn them on for the deck here, for
+# one profile in [profiles.<name>.features], or for a single session with:
+# agent-deck features enable <name> --session <id>   (list: agent-deck features)
+# [features]
+# auto-restart = true   # restart sessions whose tmux session died
+
+# Chaos mode (testing only): randomly fail pane captures, delay tool startup
+# and fail heartbeat delivery, to check hooks, alerting and retries. Every
+# injected fault is logged as "chaos_injected". AGENTDECK_CHAOS overrides this,
+# e.g. AGENTDECK_CHAOS="capture=0.2,heartbeat=1,slow_start=0.5,delay=20s"
+# [chaos]
+# enabled = true
+# capture_failure_rate = 0.1
+# slow_start_rate = 0.25
+# slow_start_delay = "15s"
+# heartbeat_failure_rate = 0.5
+
 # Experiments (for 'agent-deck try' command)
 # Quick experiment folder management with auto-dated directories
 [experiments]
@@ -1423,6 +2024,193 @@ auto_cleanup = true
 # inject_status_line = false
 # Override tmux options applied to every session (applied after defaults)
 # options = { "allow-passthrough" = "all", "history-limit" = "50000" }
+# Startup check of history-limit, escape-time, default-terminal and focus-events:
+# "warn" (default), "fix" (set them) or "off"
+# check_options = "warn"
+
+# Trash settings
+# Removed sessions and torn-down conductors are kept in ~/.agent-deck/trash
+# and can be restored with: agent-deck trash restore <id>
+# [trash]
+# enabled = true
+# retention_days = 7
+
+# Risk scoring of sessions' recent tool calls (rm -rf, force pushes, prod
+# kubeconfigs, ...). High and critical matches raise escalations; with
+# auto_stop, a critical match triggers the emergency stop.
+# [risk]
+# enabled = true
+# auto_stop = false
+# recent_calls = 20
+# [[risk.rules]]
+# name = "prod-db"
+# pattern = "psql .*prod"
+# level = "critical"
+
+# Event hooks: shell commands or webhooks run when a session becomes idle or
+# busy, errors, gets a heartbeat or is forked. Commands see the event in
+# AGENTDECK_* variables (AGENTDECK_EVENT, AGENTDECK_TITLE, AGENTDECK_STATUS,
+# ...); webhooks receive it as a JSON POST.
+# [event_hooks]
+# timeout = 10
+# [[event_hooks.hook]]
+# event = "idle"
+# command = "notify-send agent-deck \"$AGENTDECK_TITLE is done\""
+# [[event_hooks.hook]]
+# event = "error"
+# url = "https://example.com/hooks/agent-deck"
+# profiles = ["work"]
+
+# Desktop notifications when a session finishes working: sent once it has
+# stayed waiting/idle for idle_seconds after running. backend: "auto",
+# "notify-send", "osascript", "terminal-notifier" or "none"; webhook_url also
+# receives each notification as JSON. Per-session overrides are keyed by ID or
+# title (agent-deck session notify <id|title> on|off|default).
+# [notifications.desktop]
+# enabled = true
+# idle_seconds = 30
+# tools = ["claude"]
+# [notifications.desktop.sessions.api-server]
+# idle_seconds = 120
+
+# Transcripts: continuous capture of each session's output (tmux pipe-pane)
+# into ~/.agent-deck/transcripts/<session-id>/, for sessions started after it
+# is enabled. Read them with: agent-deck logs <id|title> [--follow]
+# The current file is rotated at max_size_mb; max_files rotated files are
+# kept, and anything older than max_age_days is removed.
+# [transcripts]
+# enabled = true
+# max_size_mb = 10
+# max_files = 5
+# max_age_days = 14
+# tools = ["claude", "codex"]
+
+# Status detection settings
+# A new running/waiting/idle status must be detected on this many consecutive
+# polls before it is shown, which stops flapping during screen redraws.
+# [status]
+# dwell_polls = 2
+# state_dwell_polls = { waiting = 3 }
+
+# Signal fusion keeps a session running while its processes work silently
+# (a long tool call with nothing on screen). CPU use of the pane's processes,
+# output changes, tmux window activity and busy/prompt patterns vote with
+# these weights (0 disables a signal); a weighted score of at least threshold
+# (-1 to 1) means running. A visible prompt still wins with the defaults.
+# [status.fusion]
+# enabled = true
+# threshold = 0.3
+# cpu_busy_percent = 10
+# weights = { patterns = 1.0, cpu = 0.8, output = 0.4, activity = 0.2 }
+
+# Session ordering, applied within each group by the TUI, web UI and CLI.
+# mode: "manual" (default), "activity" (most recent first) or "severity"
+# (error, waiting, running, idle). Pinned sessions (IDs or titles) always
+# come first; manage them with: agent-deck session pin <id|title>
+# [sort]
+# mode = "severity"
+# pinned = ["conductor-ops", "api-server"]
+
+# Status output theming for the CLI (and status glyphs in the TUI).
+# color: "auto" (default), "always" or "never"; NO_COLOR always disables color.
+# screen_reader prints words like "waiting" instead of glyphs.
+# [output]
+# color = "auto"
+# screen_reader = false
+# glyphs = { running = "R", waiting = "W", idle = "-", error = "E" }
+# colors = { waiting = "#e0af68", error = "1" }
+
+# Timestamp display. Relative times ("2h ago") are the default; pass
+# --absolute to commands that show times, or set format = "absolute".
+# locale picks the date layout (default: LC_TIME/LANG).
+# [time]
+# format = "relative"
+# timezone = "Europe/Berlin"
+# locale = "de_DE"
+
+# Identical tasks sent to a conductor (same text, or same --dedupe-key) within
+# dedupe_window minutes are collapsed into one; merges show in conductor report.
+# Set a negative value to disable.
+# [conductor]
+# dedupe_window = 10
+# Run heartbeats on a schedule instead of every heartbeat_interval minutes:
+# a systemd OnCalendar expression or a cron string (cron also works with
+# launchd). Per conductor: agent-deck conductor schedule <name> <expression>
+# heartbeat_schedule = "5 9-17 * * 1-5"   # hourly at :05 during business hours
+# With ~/.agent-deck on storage shared by several machines (NFS home), every
+# machine's heartbeat timer fires. heartbeat_lock lets only the machine holding
+# a lease (renewed with each heartbeat, lasting heartbeat_lock_ttl minutes,
+# default twice the interval) deliver them.
+# heartbeat_lock = true
+# heartbeat_lock_ttl = 30
+# On Linux heartbeats run from a systemd user timer, or from a crontab line
+# where there is no systemd user session (containers, shared hosts). Force one:
+# heartbeat_backend = "cron"
+# Each conductor keeps its last heartbeat_history heartbeat runs (result,
+# prompt, status before/after) in heartbeats.jsonl; see 'conductor history'.
+# heartbeat_history = 500
+# Heartbeats due while the machine slept are sent once on resume ("missed"),
+# or left to the next timer run ("skip"). OnCalendar schedules always wait.
+# heartbeat_on_resume = "skip"
+# Per-profile heartbeat intervals in minutes (default: heartbeat_interval).
+# Installed timers pick up changes on the next 'agent-deck conductor status'.
+# [conductor.heartbeat_intervals]
+# work = 5
+# personal = 60
+
+# A standup across all conductors (done from task-log.md, doing and blocked
+# from state.json), posted by the first heartbeat after each scheduled time.
+# Preview it with: agent-deck conductor standup
+# [conductor.standup]
+# schedule = "0 9 * * 1-5"
+# hours = 24                                   # task-log window for "done"
+# webhook_url = "https://hooks.slack.com/services/..."
+# slack = true                                 # post with [conductor.slack]
+# telegram = true                              # post with [conductor.telegram]
+# command = "mail -s standup me@example.com"   # standup on stdin
+# summarize = true                            # post a [summarizer] condensed version
+
+# Maintenance windows pause heartbeats, conductor task dispatch and automatic
+# conductor restarts; everything resumes when the window ends. Windows are
+# one-off (start/end) or recurring (from/to on days, mon..sun). Add one-off
+# windows from the CLI with 'agent-deck maintenance start --for 2h'.
+# [[maintenance.windows]]
+# name = "weekly-upgrade"
+# days = ["sat"]
+# from = "22:00"
+# to = "02:00"
+# timezone = "Europe/Berlin"
+# reason = "host upgrades"
+
+# On a laptop running on battery below threshold percent, send only every
+# heartbeat_factor-th conductor heartbeat and hold queued conductor tasks
+# (pause_dispatch); both resume on AC power.
+# [battery]
+# enabled = true
+# threshold = 30
+# heartbeat_factor = 4
+# pause_dispatch = true
+
+# Remote hosts whose sessions "agent-deck remote" lists, creates, attaches
+# and monitors over ssh. Each needs agent-deck and tmux installed; commands to
+# a host share one multiplexed ssh connection (control_persist seconds, -1 to
+# disable). tmux_socket overrides the remote tmux server used for attaching
+# (a -L name or a -S path; default: the remote deck's).
+# [remotes.box]
+# host = "me@build-box"
+# port = 22
+# identity_file = "~/.ssh/id_ed25519"
+# agent_deck = "~/.local/bin/agent-deck"
+# deck = "work"
+# profile = "default"
+
+# Registry for conductor templates and skill packs, referenced as
+# registry://name@version (e.g. conductor setup ops --claude-md registry://sre-oncall@v2).
+# url is an HTTP(S) base serving index.json or a git repository. With
+# public_key set, archives must be ed25519-signed.
+# [registry]
+# url = "https://registry.example.com/agent-deck"
+# public_key = "base64-ed25519-public-key"
 
 # ============================================================================
 # MCP Server Definitions
@@ -1555,6 +2343,48 @@ auto_cleanup = true
 # Replace all defaults (use with caution):
 # [tools.claude]
 # busy_patterns = ["only-this-pattern"]
+#
+# Narrow patterns with an option block: {opt; opt=value}pattern
+#   start     only at the start of a line     last=N    only the last N lines
+#   unless=X  ignore if X is also present     not       a match vetoes busy/prompt
+#   prio=N    highest-priority matching pattern decides (plain patterns are 0)
+# [tools.mytool]
+# busy_patterns_extra = ["{start; last=5}esc to cancel", "{unless=Type your message}Working"]
+# prompt_patterns_extra = ["{not}Compacting conversation"]
+#
+# Control what the patterns are matched against (tools with heavy TUI chrome):
+# [tools.mytool]
+# status_lines = 40          # trailing lines inspected (default: 25)
+# capture_alt_screen = true  # also read the screen behind a full-screen TUI
+# match_ansi = true          # keep color codes so "re:" patterns can match them
+#
+# Clean up the pane before patterns match it:
+# [tools.claude]
+# strip_spinner_frames = true     # drop spinner frames at line ends
+# collapse_progress = true        # "Downloading 10%", "Downloading 20%" -> last only
+# banner_end = "re:^~/"           # ignore the banner down to this line
+#
+# Ready probes: a prompt match only means ready if these checks also pass
+# [tools.mytool]
+# ready_process = "node"     # process must be alive inside the pane
+# ready_port = 8080          # local port must accept connections
+#
+# Classifier script: for states patterns cannot detect, a program reads the
+# pane on stdin and prints {"state": "active|waiting|idle", ...}
+# [tools.mytool.classifier]
+# command = "~/bin/mytool-status"
+# mode = "augment"           # only when no pattern matches (default: override)
+# timeout = 1000             # milliseconds
+# A profile can set one per tool: [profiles.work.classifiers.mytool]
+#
+# Macros: named in-tool actions run with "agent-deck session macro <id> <name>",
+# the web API or Shift+X in the TUI. Built-in tools ship compact, clear, model
+# and interrupt (claude also cycle-mode); entries here add or replace macros.
+# [tools.claude.macros.review]
+# description = "Review the current diff"
+# text = "/review"          # typed and submitted with Enter
+# [tools.claude.macros.plan]
+# keys = ["S-Tab", "S-Tab"] # tmux key names, pressed in order
 `
 
 	// Add platform-aware MCP pool section
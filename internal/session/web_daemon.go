@@ -0,0 +1,156 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
+	"github.com/asheshgoplani/agent-deck/internal/platform"
+)
+
+// DefaultWebIdleExit is how long a socket-activated web daemon stays up with no requests
+const DefaultWebIdleExit = 10 * time.Minute
+
+// systemdWebSocketTemplate listens on behalf of the web daemon so it starts on first use
+const systemdWebSocketTemplate = `[Unit]
+Description=Agent Deck Web Socket (__PROFILE__)
+
+[Socket]
+ListenStream=__LISTEN__
+NoDelay=true
+
+[Install]
+WantedBy=sockets.target
+`
+
+// systemdWebServiceTemplate is started by the socket unit and exits when idle.
+// It has no [Install] section: the socket unit is what gets enabled.
+const systemdWebServiceTemplate = `[Unit]
+Description=Agent Deck Web (__PROFILE__)
+Requires=__SOCKET_NAME__
+After=__SOCKET_NAME__
+
+[Service]
+Type=simple
+ExecStart=__AGENT_DECK__ -p __PROFILE__ web --headless --idle-exit __IDLE__
+WorkingDirectory=__HOME__
+Environment=PATH=__PATH__
+Environment=HOME=__HOME__
+`
+
+// SystemdWebSocketName returns the socket unit name for a profile's web daemon
+func SystemdWebSocketName(profile string) string {
+	return fmt.Sprintf("%s-web-%s.socket", deck.UnitPrefix(), profile)
+}
+
+// SystemdWebServiceName returns the service unit name for a profile's web daemon
+func SystemdWebServiceName(profile string) string {
+	return fmt.Sprintf("%s-web-%s.service", deck.UnitPrefix(), profile)
+}
+
+// GenerateSystemdWebSocket returns the .socket unit for a profile's web daemon
+func GenerateSystemdWebSocket(profile, listenAddr string) string {
+	unit := strings.ReplaceAll(systemdWebSocketTemplate, "__PROFILE__", profile)
+	unit = strings.ReplaceAll(unit, "__LISTEN__", listenAddr)
+	return unit
+}
+
+// GenerateSystemdWebService returns the socket-activated .service unit for a profile's web daemon
+func GenerateSystemdWebService(profile string, idleExit time.Duration) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	agentDeckPath := findAgentDeck()
+	if agentDeckPath == "" {
+		return "", fmt.Errorf("agent-deck not found in PATH")
+	}
+	if idleExit <= 0 {
+		idleExit = DefaultWebIdleExit
+	}
+	unit := strings.ReplaceAll(withDeckEnv(systemdWebServiceTemplate), "__PROFILE__", profile)
+	unit = strings.ReplaceAll(unit, "__SOCKET_NAME__", SystemdWebSocketName(profile))
+	unit = strings.ReplaceAll(unit, "__AGENT_DECK__", agentDeckPath)
+	unit = strings.ReplaceAll(unit, "__IDLE__", idleExit.String())
+	unit = strings.ReplaceAll(unit, "__HOME__", homeDir)
+	unit = strings.ReplaceAll(unit, "__PATH__", buildDaemonPath(agentDeckPath))
+	return unit, nil
+}
+
+// InstallWebSocketDaemon writes the .socket/.service pair for a profile and
+// enables the socket. The web server starts on the first connection and exits
+// after idleExit without requests. Returns the socket unit path.
+func InstallWebSocketDaemon(profile, listenAddr string, idleExit time.Duration) (string, error) {
+	if plat := platform.Detect(); plat != platform.PlatformLinux && plat != platform.PlatformWSL2 {
+		return "", fmt.Errorf("socket activation requires systemd (platform %s); run 'agent-deck web --headless' instead", plat)
+	}
+	profile = GetEffectiveProfile(profile)
+
+	svcContent, err := GenerateSystemdWebService(profile, idleExit)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate web service: %w", err)
+	}
+	dir, err := SystemdUserDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create systemd user dir: %w", err)
+	}
+	socketPath := filepath.Join(dir, SystemdWebSocketName(profile))
+	if err := os.WriteFile(socketPath, []byte(GenerateSystemdWebSocket(profile, listenAddr)), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write socket unit: %w", err)
+	}
+	svcPath := filepath.Join(dir, SystemdWebServiceName(profile))
+	if err := os.WriteFile(svcPath, []byte(svcContent), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write service unit: %w", err)
+	}
+	// The socket owns the address, so record it without a PID: CLI commands
+	// can connect (and trigger activation) even while the server is stopped.
+	if err := WriteWebEndpoint(profile, WebEndpoint{Addr: listenAddr}); err != nil {
+		return "", err
+	}
+
+	if !systemdUserAvailable() {
+		return socketPath, fmt.Errorf("systemd user session not available; run manually: agent-deck -p %s web --headless", profile)
+	}
+	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+	// Stop a running instance so it picks up the new unit on next activation
+	_ = exec.Command("systemctl", "--user", "stop", SystemdWebServiceName(profile)).Run()
+	if err := exec.Command("systemctl", "--user", "enable", "--now", SystemdWebSocketName(profile)).Run(); err != nil {
+		return socketPath, fmt.Errorf("units written but enabling socket failed: %w", err)
+	}
+	return socketPath, nil
+}
+
+// UninstallWebSocketDaemon stops and removes a profile's web socket/service units
+func UninstallWebSocketDaemon(profile string) error {
+	profile = GetEffectiveProfile(profile)
+	_ = exec.Command("systemctl", "--user", "disable", "--now", SystemdWebSocketName(profile)).Run()
+	_ = exec.Command("systemctl", "--user", "stop", SystemdWebServiceName(profile)).Run()
+
+	dir, err := SystemdUserDir()
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{SystemdWebSocketName(profile), SystemdWebServiceName(profile)} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+	RemoveWebEndpoint(profile, 0)
+	return nil
+}
+
+// WebSocketDaemonState reports whether the socket is listening and the service is running
+func WebSocketDaemonState(profile string) (socketActive, serviceActive bool) {
+	profile = GetEffectiveProfile(profile)
+	socketActive = exec.Command("systemctl", "--user", "is-active", "--quiet", SystemdWebSocketName(profile)).Run() == nil
+	serviceActive = exec.Command("systemctl", "--user", "is-active", "--quiet", SystemdWebServiceName(profile)).Run() == nil
+	return socketActive, serviceActive
+}
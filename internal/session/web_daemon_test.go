@@ -0,0 +1,21 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateSystemdWebSocket(t *testing.T) {
+	unit := GenerateSystemdWebSocket("work", "127.0.0.1:9000")
+	for _, want := range []string{"ListenStream=127.0.0.1:9000", "WantedBy=sockets.target", "(work)"} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("socket unit should contain %q:\n%s", want, unit)
+		}
+	}
+	if SystemdWebServiceName("work") != "agent-deck-web-work.service" {
+		t.Errorf("unexpected service name %q", SystemdWebServiceName("work"))
+	}
+	if strings.TrimSuffix(SystemdWebSocketName("work"), ".socket") != strings.TrimSuffix(SystemdWebServiceName("work"), ".service") {
+		t.Error("socket and service must share a base name for systemd to pair them")
+	}
+}
@@ -0,0 +1,104 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// webEndpointFileName is written into the profile directory while a web
+// server (or its activation socket) is available for that profile.
+const webEndpointFileName = "web.endpoint.json"
+
+// WebEndpoint tells CLI commands where a profile's web server listens so they
+// can read its cached session status instead of capturing every pane again.
+type WebEndpoint struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token,omitempty"`
+	// PID is the serving process, or 0 when a systemd socket owns the address
+	// and the server may not be running yet.
+	PID int `json:"pid,omitempty"`
+}
+
+func webEndpointPath(profile string) (string, error) {
+	dir, err := GetProfileDir(GetEffectiveProfile(profile))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, webEndpointFileName), nil
+}
+
+// WriteWebEndpoint records the web endpoint for a profile. The file may hold a
+// bearer token, so it is written with owner-only permissions.
+func WriteWebEndpoint(profile string, ep WebEndpoint) error {
+	path, err := webEndpointPath(profile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create profile dir: %w", err)
+	}
+	data, err := json.MarshalIndent(ep, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write web endpoint: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadWebEndpoint returns the recorded web endpoint for a profile.
+// Endpoints owned by a process that has exited are treated as missing.
+func ReadWebEndpoint(profile string) (*WebEndpoint, error) {
+	path, err := webEndpointPath(profile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ep WebEndpoint
+	if err := json.Unmarshal(data, &ep); err != nil {
+		return nil, fmt.Errorf("failed to parse web endpoint: %w", err)
+	}
+	if ep.Addr == "" {
+		return nil, fmt.Errorf("web endpoint has no address")
+	}
+	if ep.PID > 0 && !processAlive(ep.PID) {
+		return nil, os.ErrNotExist
+	}
+	return &ep, nil
+}
+
+// RemoveWebEndpoint deletes the endpoint record if it belongs to pid
+// (pid 0 removes it unconditionally).
+func RemoveWebEndpoint(profile string, pid int) {
+	path, err := webEndpointPath(profile)
+	if err != nil {
+		return
+	}
+	if pid != 0 {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		var ep WebEndpoint
+		if json.Unmarshal(data, &ep) != nil || ep.PID != pid {
+			return
+		}
+	}
+	_ = os.Remove(path)
+}
+
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
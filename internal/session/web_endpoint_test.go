@@ -0,0 +1,54 @@
+package session
+
+import (
+	"os"
+	"testing"
+)
+
+func TestWebEndpointRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := WriteWebEndpoint("work", WebEndpoint{Addr: "127.0.0.1:9000", Token: "secret", PID: os.Getpid()}); err != nil {
+		t.Fatalf("WriteWebEndpoint: %v", err)
+	}
+	ep, err := ReadWebEndpoint("work")
+	if err != nil {
+		t.Fatalf("ReadWebEndpoint: %v", err)
+	}
+	if ep.Addr != "127.0.0.1:9000" || ep.Token != "secret" {
+		t.Errorf("unexpected endpoint: %+v", ep)
+	}
+	path, _ := webEndpointPath("work")
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("endpoint file should be 0600 (err=%v)", err)
+	}
+
+	// Another process's record must not be removed
+	RemoveWebEndpoint("work", os.Getpid()+1)
+	if _, err := ReadWebEndpoint("work"); err != nil {
+		t.Fatalf("endpoint removed by wrong pid: %v", err)
+	}
+	RemoveWebEndpoint("work", os.Getpid())
+	if _, err := ReadWebEndpoint("work"); err == nil {
+		t.Error("endpoint should be removed by owning pid")
+	}
+}
+
+func TestReadWebEndpointIgnoresDeadProcess(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// PID 0 means a socket unit owns the address and is always usable
+	if err := WriteWebEndpoint("", WebEndpoint{Addr: "127.0.0.1:8420"}); err != nil {
+		t.Fatalf("WriteWebEndpoint: %v", err)
+	}
+	if _, err := ReadWebEndpoint(""); err != nil {
+		t.Errorf("socket endpoint should be readable: %v", err)
+	}
+
+	if err := WriteWebEndpoint("", WebEndpoint{Addr: "127.0.0.1:8420", PID: 1 << 30}); err != nil {
+		t.Fatalf("WriteWebEndpoint: %v", err)
+	}
+	if _, err := ReadWebEndpoint(""); err == nil {
+		t.Error("endpoint of a dead process should be ignored")
+	}
+}
@@ -0,0 +1,131 @@
+package statedb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Audit log actions.
+const (
+	AuditCreated  = "created"
+	AuditRemoved  = "removed"
+	AuditRestored = "restored"
+
+	// AuditNetworkPolicy records a non-open network policy and how it is enforced
+	AuditNetworkPolicy = "network_policy"
+
+	// AuditSpawned records a session created by a conductor (detail: conductor name)
+	AuditSpawned = "spawned"
+	// AuditSpawnDenied records a spawn refused by the spawn policy
+	// (detail: "<conductor>: <reason>", session ID empty)
+	AuditSpawnDenied = "spawn_denied"
+
+	// AuditDelivered records a signed heartbeat or prompt delivered to a
+	// conductor (detail: the signed delivery record as JSON)
+	AuditDelivered = "delivered"
+
+	// AuditGuardedCommand records a command run through a guard shim
+	// (detail: "<logged|approved|denied|expired>: <command line>")
+	AuditGuardedCommand = "guarded_command"
+)
+
+// AuditRow is one entry of the session audit log.
+type AuditRow struct {
+	Seq       int64     `json:"seq"`
+	Time      time.Time `json:"time"`
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title"`
+	Action    string    `json:"action"`
+	// Detail is the start reason for created sessions
+	Detail string `json:"detail,omitempty"`
+}
+
+// AppendAudit adds an entry to the audit log.
+func (s *StateDB) AppendAudit(sessionID, title, action, detail string, at time.Time) error {
+	if err := appendAudit(s.db, sessionID, title, action, detail, at); err != nil {
+		return fmt.Errorf("statedb: append audit: %w", err)
+	}
+	return nil
+}
+
+// ListAudit returns audit entries, newest first, optionally narrowed to one
+// session. limit <= 0 returns all entries.
+func (s *StateDB) ListAudit(sessionID string, limit int) ([]AuditRow, error) {
+	query := `SELECT seq, ts, session_id, title, action, detail FROM audit_log`
+	var args []any
+	if sessionID != "" {
+		query += ` WHERE session_id = ?`
+		args = append(args, sessionID)
+	}
+	query += ` ORDER BY seq DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	return s.queryAudit(query, args...)
+}
+
+// ListAuditByAction returns entries with the given action and detail recorded
+// at or after since, newest first
+func (s *StateDB) ListAuditByAction(action, detail string, since time.Time) ([]AuditRow, error) {
+	return s.queryAudit(`
+		SELECT seq, ts, session_id, title, action, detail FROM audit_log
+		WHERE action = ? AND detail = ? AND ts >= ?
+		ORDER BY seq DESC
+	`, action, detail, since.UnixNano())
+}
+
+// ListAuditBetween returns audit entries recorded in (from, to], oldest first.
+func (s *StateDB) ListAuditBetween(from, to time.Time) ([]AuditRow, error) {
+	return s.queryAudit(`
+		SELECT seq, ts, session_id, title, action, detail FROM audit_log
+		WHERE ts > ? AND ts <= ?
+		ORDER BY seq
+	`, from.UnixNano(), to.UnixNano())
+}
+
+func (s *StateDB) queryAudit(query string, args ...any) ([]AuditRow, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []AuditRow
+	for rows.Next() {
+		var r AuditRow
+		var ts int64
+		if err := rows.Scan(&r.Seq, &ts, &r.SessionID, &r.Title, &r.Action, &r.Detail); err != nil {
+			return nil, err
+		}
+		r.Time = time.Unix(0, ts)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func appendAudit(db execer, sessionID, title, action, detail string, at time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO audit_log (ts, session_id, title, action, detail) VALUES (?, ?, ?, ?, ?)
+	`, at.UnixNano(), sessionID, title, action, detail)
+	return err
+}
+
+// startReasonOf extracts the start reason from an instance's tool_data blob.
+func startReasonOf(toolData json.RawMessage) string {
+	var blob struct {
+		StartReason string `json:"start_reason"`
+	}
+	if len(toolData) == 0 || json.Unmarshal(toolData, &blob) != nil {
+		return ""
+	}
+	return blob.StartReason
+}
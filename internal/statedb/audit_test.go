@@ -0,0 +1,58 @@
+package statedb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSaveInstancesRecordsAudit(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Now()
+	a := &InstanceRow{ID: "a", Title: "alpha", CreatedAt: now, ToolData: json.RawMessage(`{"start_reason":"webhook:gh-1"}`)}
+	b := &InstanceRow{ID: "b", Title: "beta", CreatedAt: now}
+
+	if err := db.SaveInstances([]*InstanceRow{a, b}); err != nil {
+		t.Fatalf("SaveInstances: %v", err)
+	}
+	// Re-saving unchanged instances must not add entries
+	if err := db.SaveInstances([]*InstanceRow{a, b}); err != nil {
+		t.Fatalf("SaveInstances: %v", err)
+	}
+	if err := db.SaveInstances([]*InstanceRow{a}); err != nil {
+		t.Fatalf("SaveInstances: %v", err)
+	}
+
+	entries, err := db.ListAudit("", 0)
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.SessionID != "b" || e.Action != AuditRemoved || e.Title != "beta" {
+		t.Errorf("newest entry = %+v, want removal of b", e)
+	}
+
+	alpha, err := db.ListAudit("a", 10)
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(alpha) != 1 || alpha[0].Action != AuditCreated || alpha[0].Detail != "webhook:gh-1" {
+		t.Errorf("alpha entries = %+v, want one creation with its start reason", alpha)
+	}
+
+	if err := db.DeleteInstance("a"); err != nil {
+		t.Fatalf("DeleteInstance: %v", err)
+	}
+	if err := db.DeleteInstance("missing"); err != nil {
+		t.Fatalf("DeleteInstance: %v", err)
+	}
+	latest, err := db.ListAudit("", 1)
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(latest) != 1 || latest[0].SessionID != "a" || latest[0].Action != AuditRemoved {
+		t.Errorf("latest entry = %+v, want removal of a", latest)
+	}
+}
@@ -0,0 +1,352 @@
+package statedb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Conductor task kinds.
+const (
+	ConductorTaskHeartbeat = "heartbeat"
+	ConductorTaskMessage   = "message"
+)
+
+// TaskStartGrace is how long an enqueued task may go without being seen
+// running before an idle conductor is assumed to have handled it between polls.
+const TaskStartGrace = 30 * time.Second
+
+// ConductorLoadRow is one conductor's throughput on one day. Tasks are
+// bucketed by the day they were enqueued.
+type ConductorLoadRow struct {
+	Conductor string `json:"conductor"`
+	Day       string `json:"day"`
+	Enqueued  int    `json:"enqueued"`
+	Started   int    `json:"started"`
+	Completed int    `json:"completed"`
+	// MedianLatency is the median time from enqueue to done over completed tasks
+	MedianLatency time.Duration `json:"median_latency_ns"`
+	// Heartbeats is the number of completed heartbeat tasks
+	Heartbeats int `json:"heartbeats"`
+	// MedianHeartbeatIdle is the median time from a heartbeat being sent to the
+	// conductor going idle again
+	MedianHeartbeatIdle time.Duration `json:"median_heartbeat_idle_ns"`
+	// Merged is the number of duplicate tasks collapsed instead of being sent
+	Merged int `json:"merged"`
+}
+
+// TaskDayRow is the number of conductor tasks a session finished on one day.
+type TaskDayRow struct {
+	ID   string
+	Day  string
+	Done int
+}
+
+// TaskMergeRow records a duplicate task that was collapsed into an earlier one.
+type TaskMergeRow struct {
+	Conductor string    `json:"conductor"`
+	SessionID string    `json:"session_id"`
+	DedupeKey string    `json:"dedupe_key"`
+	FirstAt   time.Time `json:"first_at"`
+	MergedAt  time.Time `json:"merged_at"`
+}
+
+// TaskDedupeResult is the outcome of DedupeConductorTask.
+type TaskDedupeResult struct {
+	// Duplicate is true when the task matched one sent within the window
+	Duplicate bool
+	// FirstAt is when the task the duplicate was merged into was sent
+	FirstAt time.Time
+	// Merged counts the duplicates collapsed into that task, including this one
+	Merged int
+}
+
+// EnqueueConductorTask records that a task (heartbeat or message) was sent to
+// the conductor's session.
+func (s *StateDB) EnqueueConductorTask(conductor, sessionID, kind string, at time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO conductor_tasks (conductor, session_id, kind, enqueued) VALUES (?, ?, ?, ?)
+	`, conductor, sessionID, kind, at.UnixNano())
+	if err != nil {
+		return fmt.Errorf("statedb: enqueue conductor task: %w", err)
+	}
+	return nil
+}
+
+// DedupeConductorTask checks whether a task with key was already sent to
+// conductor within window of at. A duplicate is recorded as a merge into the
+// first task; otherwise at becomes the key's first occurrence. The window
+// runs from the first occurrence, so a task repeated forever still goes out
+// once per window.
+func (s *StateDB) DedupeConductorTask(conductor, sessionID, key string, window time.Duration, at time.Time) (TaskDedupeResult, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return TaskDedupeResult{}, fmt.Errorf("statedb: begin dedupe task: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var firstAt int64
+	err = tx.QueryRow(`
+		SELECT first_at FROM conductor_task_keys WHERE conductor = ? AND dedupe_key = ?
+	`, conductor, key).Scan(&firstAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return TaskDedupeResult{}, err
+	}
+
+	if err == nil && at.Sub(time.Unix(0, firstAt)) < window {
+		if _, err := tx.Exec(`
+			INSERT INTO conductor_task_merges (conductor, session_id, dedupe_key, first_at, merged_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, conductor, sessionID, key, firstAt, at.UnixNano()); err != nil {
+			return TaskDedupeResult{}, err
+		}
+		var merged int
+		if err := tx.QueryRow(`
+			SELECT COUNT(*) FROM conductor_task_merges WHERE conductor = ? AND dedupe_key = ? AND first_at = ?
+		`, conductor, key, firstAt).Scan(&merged); err != nil {
+			return TaskDedupeResult{}, err
+		}
+		if err := tx.Commit(); err != nil {
+			return TaskDedupeResult{}, err
+		}
+		return TaskDedupeResult{Duplicate: true, FirstAt: time.Unix(0, firstAt), Merged: merged}, nil
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO conductor_task_keys (conductor, dedupe_key, first_at) VALUES (?, ?, ?)
+		ON CONFLICT(conductor, dedupe_key) DO UPDATE SET first_at = excluded.first_at
+	`, conductor, key, at.UnixNano()); err != nil {
+		return TaskDedupeResult{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return TaskDedupeResult{}, err
+	}
+	return TaskDedupeResult{FirstAt: at}, nil
+}
+
+// ListConductorTaskMerges returns merges recorded since the given time,
+// newest first, optionally narrowed to one conductor.
+func (s *StateDB) ListConductorTaskMerges(conductor string, since time.Time) ([]TaskMergeRow, error) {
+	query := `SELECT conductor, session_id, dedupe_key, first_at, merged_at FROM conductor_task_merges WHERE merged_at >= ?`
+	args := []any{since.UnixNano()}
+	if conductor != "" {
+		query += ` AND conductor = ?`
+		args = append(args, conductor)
+	}
+	rows, err := s.db.Query(query+` ORDER BY seq DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []TaskMergeRow
+	for rows.Next() {
+		var r TaskMergeRow
+		var firstAt, mergedAt int64
+		if err := rows.Scan(&r.Conductor, &r.SessionID, &r.DedupeKey, &firstAt, &mergedAt); err != nil {
+			return nil, err
+		}
+		r.FirstAt = time.Unix(0, firstAt)
+		r.MergedAt = time.Unix(0, mergedAt)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// advanceConductorTasks moves open tasks for sessionID forward on a status
+// observation: running marks them started, waiting/idle marks started tasks
+// done. Tasks never seen running are completed once TaskStartGrace has passed,
+// since a fast conductor can finish between two polls.
+func advanceConductorTasks(tx *sql.Tx, sessionID, status string, now time.Time) error {
+	switch status {
+	case busyStatus:
+		_, err := tx.Exec(`
+			UPDATE conductor_tasks SET started = ?
+			WHERE session_id = ? AND started = 0 AND done = 0
+		`, now.UnixNano(), sessionID)
+		return err
+	case "waiting", "idle":
+		_, err := tx.Exec(`
+			UPDATE conductor_tasks
+			SET started = CASE WHEN started = 0 THEN enqueued ELSE started END, done = ?
+			WHERE session_id = ? AND done = 0 AND (started > 0 OR enqueued < ?)
+		`, now.UnixNano(), sessionID, now.Add(-TaskStartGrace).UnixNano())
+		return err
+	}
+	return nil
+}
+
+// ConductorLoadReport aggregates conductor tasks enqueued on days in
+// [fromDay, toDay] (inclusive DayKeys), ordered by conductor then day.
+func (s *StateDB) ConductorLoadReport(fromDay, toDay string) ([]ConductorLoadRow, error) {
+	from, err := time.ParseInLocation("2006-01-02", fromDay, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("statedb: invalid from day %q: %w", fromDay, err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", toDay, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("statedb: invalid to day %q: %w", toDay, err)
+	}
+	to = to.AddDate(0, 0, 1)
+
+	rows, err := s.db.Query(`
+		SELECT conductor, kind, enqueued, started, done FROM conductor_tasks
+		WHERE enqueued >= ? AND enqueued < ?
+	`, from.UnixNano(), to.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type bucket struct {
+		row       ConductorLoadRow
+		latencies []time.Duration
+		heartbeat []time.Duration
+	}
+	buckets := make(map[[2]string]*bucket)
+	for rows.Next() {
+		var conductor, kind string
+		var enqueued, started, done int64
+		if err := rows.Scan(&conductor, &kind, &enqueued, &started, &done); err != nil {
+			return nil, err
+		}
+		day := DayKey(time.Unix(0, enqueued))
+		key := [2]string{conductor, day}
+		b := buckets[key]
+		if b == nil {
+			b = &bucket{row: ConductorLoadRow{Conductor: conductor, Day: day}}
+			buckets[key] = b
+		}
+		b.row.Enqueued++
+		if started > 0 {
+			b.row.Started++
+		}
+		if done > 0 {
+			b.row.Completed++
+			latency := time.Duration(done - enqueued)
+			b.latencies = append(b.latencies, latency)
+			if kind == ConductorTaskHeartbeat {
+				b.row.Heartbeats++
+				b.heartbeat = append(b.heartbeat, latency)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	merges, err := s.db.Query(`
+		SELECT conductor, merged_at FROM conductor_task_merges WHERE merged_at >= ? AND merged_at < ?
+	`, from.UnixNano(), to.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer merges.Close()
+	for merges.Next() {
+		var conductor string
+		var mergedAt int64
+		if err := merges.Scan(&conductor, &mergedAt); err != nil {
+			return nil, err
+		}
+		day := DayKey(time.Unix(0, mergedAt))
+		key := [2]string{conductor, day}
+		b := buckets[key]
+		if b == nil {
+			b = &bucket{row: ConductorLoadRow{Conductor: conductor, Day: day}}
+			buckets[key] = b
+		}
+		b.row.Merged++
+	}
+	if err := merges.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]ConductorLoadRow, 0, len(buckets))
+	for _, b := range buckets {
+		b.row.MedianLatency = medianDuration(b.latencies)
+		b.row.MedianHeartbeatIdle = medianDuration(b.heartbeat)
+		result = append(result, b.row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Conductor != result[j].Conductor {
+			return result[i].Conductor < result[j].Conductor
+		}
+		return result[i].Day < result[j].Day
+	})
+	return result, nil
+}
+
+// ReadTasksDoneDaily counts conductor tasks finished on days in [fromDay,
+// toDay] (inclusive DayKeys) per session and day, ordered by day then id.
+func (s *StateDB) ReadTasksDoneDaily(fromDay, toDay string) ([]TaskDayRow, error) {
+	from, err := time.ParseInLocation("2006-01-02", fromDay, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("statedb: invalid from day %q: %w", fromDay, err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", toDay, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("statedb: invalid to day %q: %w", toDay, err)
+	}
+	to = to.AddDate(0, 0, 1)
+
+	rows, err := s.db.Query(`
+		SELECT session_id, done FROM conductor_tasks WHERE done >= ? AND done < ?
+	`, from.UnixNano(), to.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[[2]string]int)
+	for rows.Next() {
+		var id string
+		var done int64
+		if err := rows.Scan(&id, &done); err != nil {
+			return nil, err
+		}
+		counts[[2]string{DayKey(time.Unix(0, done)), id}]++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]TaskDayRow, 0, len(counts))
+	for key, n := range counts {
+		result = append(result, TaskDayRow{ID: key[1], Day: key[0], Done: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Day != result[j].Day {
+			return result[i].Day < result[j].Day
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// PruneConductorTasks deletes tasks enqueued, dedupe keys first seen and
+// merges recorded before cutoff.
+func (s *StateDB) PruneConductorTasks(before time.Time) error {
+	if _, err := s.db.Exec(`DELETE FROM conductor_tasks WHERE enqueued < ?`, before.UnixNano()); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(`DELETE FROM conductor_task_keys WHERE first_at < ?`, before.UnixNano()); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM conductor_task_merges WHERE merged_at < ?`, before.UnixNano())
+	return err
+}
+
+// medianDuration returns the median of ds (0 when empty). ds is reordered.
+func medianDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	mid := len(ds) / 2
+	if len(ds)%2 == 1 {
+		return ds[mid]
+	}
+	return (ds[mid-1] + ds[mid]) / 2
+}
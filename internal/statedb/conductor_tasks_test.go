@@ -0,0 +1,142 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConductorTasksAdvanceAndReport(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+
+	// Heartbeat: enqueued, seen running at +10s, idle at +40s
+	if err := db.EnqueueConductorTask("ops", "sess-ops", ConductorTaskHeartbeat, base); err != nil {
+		t.Fatalf("EnqueueConductorTask: %v", err)
+	}
+	mustRecord(t, db, "sess-ops", "running", base.Add(10*time.Second))
+	mustRecord(t, db, "sess-ops", "idle", base.Add(40*time.Second))
+
+	// Message handled between polls: never seen running, completed after the grace
+	msgAt := base.Add(time.Minute)
+	if err := db.EnqueueConductorTask("ops", "sess-ops", ConductorTaskMessage, msgAt); err != nil {
+		t.Fatalf("EnqueueConductorTask: %v", err)
+	}
+	mustRecord(t, db, "sess-ops", "waiting", msgAt.Add(5*time.Second)) // within grace: still open
+	mustRecord(t, db, "sess-ops", "waiting", msgAt.Add(TaskStartGrace+10*time.Second))
+
+	// Still-open task on another conductor
+	if err := db.EnqueueConductorTask("infra", "sess-infra", ConductorTaskMessage, base); err != nil {
+		t.Fatalf("EnqueueConductorTask: %v", err)
+	}
+	mustRecord(t, db, "sess-infra", "running", base.Add(time.Second))
+
+	rows, err := db.ConductorLoadReport(DayKey(base), DayKey(base))
+	if err != nil {
+		t.Fatalf("ConductorLoadReport: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %+v", rows)
+	}
+
+	infra := rows[0]
+	if infra.Conductor != "infra" || infra.Enqueued != 1 || infra.Started != 1 || infra.Completed != 0 {
+		t.Errorf("unexpected infra row: %+v", infra)
+	}
+
+	ops := rows[1]
+	if ops.Conductor != "ops" || ops.Enqueued != 2 || ops.Started != 2 || ops.Completed != 2 {
+		t.Errorf("unexpected ops row: %+v", ops)
+	}
+	if ops.Heartbeats != 1 || ops.MedianHeartbeatIdle != 40*time.Second {
+		t.Errorf("heartbeat idle = %d/%v, want 1/40s", ops.Heartbeats, ops.MedianHeartbeatIdle)
+	}
+	// Latencies 40s and 40s after grace (30s+10s)
+	if ops.MedianLatency != 40*time.Second {
+		t.Errorf("median latency = %v, want 40s", ops.MedianLatency)
+	}
+
+	done, err := db.ReadTasksDoneDaily(DayKey(base), DayKey(base))
+	if err != nil || len(done) != 1 || done[0].ID != "sess-ops" || done[0].Done != 2 {
+		t.Errorf("ReadTasksDoneDaily = %+v (err=%v), want 2 for sess-ops", done, err)
+	}
+
+	if err := db.PruneConductorTasks(base.Add(time.Hour)); err != nil {
+		t.Fatalf("PruneConductorTasks: %v", err)
+	}
+	rows, err = db.ConductorLoadReport(DayKey(base), DayKey(base))
+	if err != nil || len(rows) != 0 {
+		t.Errorf("expected no rows after prune, got %+v (err=%v)", rows, err)
+	}
+}
+
+func TestMedianDuration(t *testing.T) {
+	if got := medianDuration(nil); got != 0 {
+		t.Errorf("empty median = %v, want 0", got)
+	}
+	if got := medianDuration([]time.Duration{3, 1, 2}); got != 2 {
+		t.Errorf("odd median = %v, want 2", got)
+	}
+	if got := medianDuration([]time.Duration{4, 1, 3, 2}); got != 2 {
+		t.Errorf("even median = %v, want 2 (integer mean of 2 and 3)", got)
+	}
+}
+
+func mustRecord(t *testing.T, db *StateDB, id, status string, at time.Time) {
+	t.Helper()
+	if err := db.RecordStatus(id, status, at); err != nil {
+		t.Fatalf("RecordStatus(%s, %s): %v", id, status, err)
+	}
+}
+
+func TestDedupeConductorTask(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	window := 10 * time.Minute
+
+	res, err := db.DedupeConductorTask("ops", "sess-ops", "ci-pr-12", window, base)
+	if err != nil {
+		t.Fatalf("DedupeConductorTask: %v", err)
+	}
+	if res.Duplicate {
+		t.Fatal("first task reported as duplicate")
+	}
+
+	for i, offset := range []time.Duration{time.Minute, 5 * time.Minute} {
+		res, err = db.DedupeConductorTask("ops", "sess-ops", "ci-pr-12", window, base.Add(offset))
+		if err != nil {
+			t.Fatalf("DedupeConductorTask: %v", err)
+		}
+		if !res.Duplicate || res.Merged != i+1 || !res.FirstAt.Equal(base) {
+			t.Errorf("repeat %d = %+v, want duplicate #%d of first task", i+1, res, i+1)
+		}
+	}
+
+	// Same key on another conductor is independent
+	res, err = db.DedupeConductorTask("infra", "sess-infra", "ci-pr-12", window, base.Add(time.Minute))
+	if err != nil || res.Duplicate {
+		t.Errorf("other conductor: %+v, %v", res, err)
+	}
+
+	// After the window the task goes out again and starts a new window
+	later := base.Add(window + time.Second)
+	res, err = db.DedupeConductorTask("ops", "sess-ops", "ci-pr-12", window, later)
+	if err != nil || res.Duplicate {
+		t.Errorf("after window: %+v, %v", res, err)
+	}
+
+	merges, err := db.ListConductorTaskMerges("ops", base)
+	if err != nil {
+		t.Fatalf("ListConductorTaskMerges: %v", err)
+	}
+	if len(merges) != 2 || merges[0].DedupeKey != "ci-pr-12" || !merges[0].MergedAt.Equal(base.Add(5*time.Minute)) {
+		t.Errorf("unexpected merges: %+v", merges)
+	}
+
+	rows, err := db.ConductorLoadReport(DayKey(base), DayKey(base))
+	if err != nil {
+		t.Fatalf("ConductorLoadReport: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Conductor != "ops" || rows[0].Merged != 2 {
+		t.Errorf("load report = %+v, want 2 merges for ops", rows)
+	}
+}
@@ -0,0 +1,41 @@
+package statedb
+
+import "fmt"
+
+// Counter names. Counters only increase, across every process using the
+// profile, and are exported as metrics.
+const (
+	// CounterHeartbeatPrefix + a heartbeat result (sent, skipped, failed)
+	CounterHeartbeatPrefix = "heartbeat."
+	CounterForks           = "forks"
+)
+
+// IncrCounter adds delta to a counter, creating it at zero.
+func (s *StateDB) IncrCounter(name string, delta int64) error {
+	if _, err := s.db.Exec(`
+		INSERT INTO counters (name, value) VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET value = value + excluded.value
+	`, name, delta); err != nil {
+		return fmt.Errorf("statedb: increment counter: %w", err)
+	}
+	return nil
+}
+
+// ReadCounters returns every counter by name.
+func (s *StateDB) ReadCounters() (map[string]int64, error) {
+	rows, err := s.db.Query(`SELECT name, value FROM counters`)
+	if err != nil {
+		return nil, fmt.Errorf("statedb: read counters: %w", err)
+	}
+	defer rows.Close()
+	counters := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var value int64
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("statedb: read counters: %w", err)
+		}
+		counters[name] = value
+	}
+	return counters, rows.Err()
+}
@@ -0,0 +1,24 @@
+package statedb
+
+import "testing"
+
+func TestCounters(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.IncrCounter(CounterForks, 1); err != nil {
+		t.Fatalf("IncrCounter: %v", err)
+	}
+	if err := db.IncrCounter(CounterForks, 2); err != nil {
+		t.Fatalf("IncrCounter: %v", err)
+	}
+	if err := db.IncrCounter(CounterHeartbeatPrefix+"failed", 1); err != nil {
+		t.Fatalf("IncrCounter: %v", err)
+	}
+	counters, err := db.ReadCounters()
+	if err != nil {
+		t.Fatalf("ReadCounters: %v", err)
+	}
+	if counters[CounterForks] != 3 || counters["heartbeat.failed"] != 1 || len(counters) != 2 {
+		t.Errorf("unexpected counters %v", counters)
+	}
+}
@@ -0,0 +1,196 @@
+package statedb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxObservationGap bounds how much time one RecordStatus call may attribute
+// to the previous status. Longer gaps mean nothing was polling (TUI closed,
+// machine asleep), so that time is not counted as busy. Shorter ones still
+// skip recorded sleeps (RecordSleep).
+const MaxObservationGap = 2 * time.Minute
+
+// busyStatus is the status whose time is accumulated into busy_daily.
+const busyStatus = "running"
+
+// DayKey returns the busy_daily key (local date) for t.
+func DayKey(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// StatusDurationRow describes how long an instance has been in its status.
+type StatusDurationRow struct {
+	Status    string
+	Since     time.Time
+	Observed  time.Time
+	BusyToday time.Duration
+}
+
+// BusyDayRow is one instance's busy time on one day.
+type BusyDayRow struct {
+	ID   string
+	Day  string
+	Busy time.Duration
+}
+
+// RecordStatus notes that instance id was observed in status at now. It keeps
+// the time the status was entered and adds time spent running to the per-day
+// busy total, splitting intervals that cross midnight. Open conductor tasks
+// and delivery receipts for id are advanced as well.
+func (s *StateDB) RecordStatus(id, status string, now time.Time) error {
+	_, err := s.RecordStatusTransition(id, status, now)
+	return err
+}
+
+// RecordStatusTransition is RecordStatus, also returning the status recorded
+// before ("" for the first observation). Recording is transactional, so when
+// several processes poll the same session only one of them sees a change.
+func (s *StateDB) RecordStatusTransition(id, status string, now time.Time) (string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("statedb: begin record status: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var prevStatus string
+	var since, observed int64
+	err = tx.QueryRow(`SELECT status, since, observed FROM status_tracking WHERE id = ?`, id).
+		Scan(&prevStatus, &since, &observed)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		since = now.UnixNano()
+	case err != nil:
+		return "", err
+	default:
+		last := time.Unix(0, observed)
+		if prevStatus == busyStatus && now.After(last) && now.Sub(last) <= MaxObservationGap {
+			if err := addBusy(tx, id, last, now); err != nil {
+				return "", err
+			}
+		}
+		if prevStatus != status {
+			since = now.UnixNano()
+		}
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO status_tracking (id, status, since, observed) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET status = excluded.status, since = excluded.since, observed = excluded.observed
+	`, id, status, since, now.UnixNano()); err != nil {
+		return "", err
+	}
+	if err := advanceConductorTasks(tx, id, status, now); err != nil {
+		return "", err
+	}
+	if err := advanceReceipts(tx, id, status, now); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return prevStatus, nil
+}
+
+// addBusy adds [from, to) to busy_daily, minus recorded sleeps, split at
+// local midnight.
+func addBusy(tx *sql.Tx, id string, from, to time.Time) error {
+	sleeps, err := readSleeps(tx, from, to)
+	if err != nil {
+		return err
+	}
+	for _, sleep := range sleeps {
+		if sleep.Start.After(from) {
+			if err := addBusySpan(tx, id, from, sleep.Start); err != nil {
+				return err
+			}
+		}
+		if sleep.End.After(from) {
+			from = sleep.End
+		}
+	}
+	return addBusySpan(tx, id, from, to)
+}
+
+// addBusySpan adds [from, to) to busy_daily, split at local midnight.
+func addBusySpan(tx *sql.Tx, id string, from, to time.Time) error {
+	for from.Before(to) {
+		y, m, d := from.Local().Date()
+		nextMidnight := time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+		end := to
+		if nextMidnight.Before(end) {
+			end = nextMidnight
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO busy_daily (id, day, busy_ms) VALUES (?, ?, ?)
+			ON CONFLICT(id, day) DO UPDATE SET busy_ms = busy_ms + excluded.busy_ms
+		`, id, DayKey(from), end.Sub(from).Milliseconds()); err != nil {
+			return err
+		}
+		from = end
+	}
+	return nil
+}
+
+// ReadStatusDurations returns the tracked status, its start time, and busy
+// time on day (a DayKey) for every tracked instance.
+func (s *StateDB) ReadStatusDurations(day string) (map[string]StatusDurationRow, error) {
+	rows, err := s.db.Query(`
+		SELECT t.id, t.status, t.since, t.observed, COALESCE(b.busy_ms, 0)
+		FROM status_tracking t
+		LEFT JOIN busy_daily b ON b.id = t.id AND b.day = ?
+	`, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]StatusDurationRow)
+	for rows.Next() {
+		var id string
+		var r StatusDurationRow
+		var since, observed, busyMs int64
+		if err := rows.Scan(&id, &r.Status, &since, &observed, &busyMs); err != nil {
+			return nil, err
+		}
+		r.Since = time.Unix(0, since)
+		r.Observed = time.Unix(0, observed)
+		r.BusyToday = time.Duration(busyMs) * time.Millisecond
+		result[id] = r
+	}
+	return result, rows.Err()
+}
+
+// ReadBusyDaily returns per-instance busy time for days in [fromDay, toDay]
+// (inclusive DayKeys), ordered by day then id.
+func (s *StateDB) ReadBusyDaily(fromDay, toDay string) ([]BusyDayRow, error) {
+	rows, err := s.db.Query(`
+		SELECT id, day, busy_ms FROM busy_daily
+		WHERE day >= ? AND day <= ?
+		ORDER BY day, id
+	`, fromDay, toDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []BusyDayRow
+	for rows.Next() {
+		var r BusyDayRow
+		var busyMs int64
+		if err := rows.Scan(&r.ID, &r.Day, &busyMs); err != nil {
+			return nil, err
+		}
+		r.Busy = time.Duration(busyMs) * time.Millisecond
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// PruneBusyDaily deletes busy_daily rows older than beforeDay (a DayKey).
+func (s *StateDB) PruneBusyDaily(beforeDay string) error {
+	_, err := s.db.Exec(`DELETE FROM busy_daily WHERE day < ?`, beforeDay)
+	return err
+}
@@ -0,0 +1,91 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordStatusTracksSinceAndBusy(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	steps := []struct {
+		offset time.Duration
+		status string
+	}{
+		{0, "running"},
+		{30 * time.Second, "running"},
+		{60 * time.Second, "waiting"}, // 60s running counted
+		{90 * time.Second, "running"},
+		{10 * time.Minute, "running"}, // gap > MaxObservationGap: not counted
+	}
+	for _, s := range steps {
+		if err := db.RecordStatus("a", s.status, base.Add(s.offset)); err != nil {
+			t.Fatalf("RecordStatus: %v", err)
+		}
+	}
+
+	durations, err := db.ReadStatusDurations(DayKey(base))
+	if err != nil {
+		t.Fatalf("ReadStatusDurations: %v", err)
+	}
+	row, ok := durations["a"]
+	if !ok {
+		t.Fatal("expected row for a")
+	}
+	if row.Status != "running" || !row.Since.Equal(base.Add(90*time.Second)) {
+		t.Errorf("status/since = %s/%v, want running since +90s", row.Status, row.Since)
+	}
+	if row.BusyToday != 60*time.Second {
+		t.Errorf("busy today = %v, want 60s", row.BusyToday)
+	}
+}
+
+func TestRecordStatusSplitsBusyAtMidnight(t *testing.T) {
+	db := newTestDB(t)
+	before := time.Date(2026, 3, 10, 23, 59, 30, 0, time.Local)
+	after := before.Add(90 * time.Second)
+
+	if err := db.RecordStatus("a", "running", before); err != nil {
+		t.Fatalf("RecordStatus: %v", err)
+	}
+	if err := db.RecordStatus("a", "running", after); err != nil {
+		t.Fatalf("RecordStatus: %v", err)
+	}
+
+	rows, err := db.ReadBusyDaily(DayKey(before), DayKey(after))
+	if err != nil {
+		t.Fatalf("ReadBusyDaily: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Busy != 30*time.Second || rows[1].Busy != 60*time.Second {
+		t.Errorf("unexpected split: %+v", rows)
+	}
+
+	if err := db.PruneBusyDaily(DayKey(after)); err != nil {
+		t.Fatalf("PruneBusyDaily: %v", err)
+	}
+	rows, _ = db.ReadBusyDaily(DayKey(before), DayKey(after))
+	if len(rows) != 1 {
+		t.Errorf("expected only the later day after prune, got %+v", rows)
+	}
+}
+
+func TestRecordStatusTransitionReturnsPrevious(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	for _, step := range []struct{ status, wantPrev string }{
+		{"running", ""},
+		{"running", "running"},
+		{"waiting", "running"},
+	} {
+		prev, err := db.RecordStatusTransition("a", step.status, now)
+		if err != nil {
+			t.Fatalf("RecordStatusTransition: %v", err)
+		}
+		if prev != step.wantPrev {
+			t.Errorf("recording %s: prev = %q, want %q", step.status, prev, step.wantPrev)
+		}
+		now = now.Add(time.Second)
+	}
+}
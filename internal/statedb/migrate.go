@@ -77,6 +77,9 @@ type toolDataBlob struct {
 	LatestPrompt       string          `json:"latest_prompt,omitempty"`
 	LoadedMCPNames     []string        `json:"loaded_mcp_names,omitempty"`
 	ToolOptions        json.RawMessage `json:"tool_options,omitempty"`
+	StartReason        string          `json:"start_reason,omitempty"`
+	NetworkPolicy      string          `json:"network_policy,omitempty"`
+	Features           map[string]bool `json:"features,omitempty"`
 }
 
 // MigrateFromJSON reads a sessions.json file and inserts all data into the StateDB.
@@ -179,7 +182,8 @@ func MarshalToolData(
 	openCodeSessionID string, openCodeDetectedAt time.Time,
 	codexSessionID string, codexDetectedAt time.Time,
 	latestPrompt string, loadedMCPNames []string,
-	toolOptionsJSON json.RawMessage,
+	toolOptionsJSON json.RawMessage, startReason string,
+	networkPolicy string, features map[string]bool,
 ) json.RawMessage {
 	td := toolDataBlob{
 		ClaudeSessionID:   claudeSessionID,
@@ -191,6 +195,9 @@ func MarshalToolData(
 		LatestPrompt:      latestPrompt,
 		LoadedMCPNames:    loadedMCPNames,
 		ToolOptions:       toolOptionsJSON,
+		StartReason:       startReason,
+		NetworkPolicy:     networkPolicy,
+		Features:          features,
 	}
 	if !claudeDetectedAt.IsZero() {
 		td.ClaudeDetectedAt = claudeDetectedAt.Unix()
@@ -217,7 +224,8 @@ func UnmarshalToolData(data json.RawMessage) (
 	openCodeSessionID string, openCodeDetectedAt time.Time,
 	codexSessionID string, codexDetectedAt time.Time,
 	latestPrompt string, loadedMCPNames []string,
-	toolOptionsJSON json.RawMessage,
+	toolOptionsJSON json.RawMessage, startReason string,
+	networkPolicy string, features map[string]bool,
 ) {
 	if len(data) == 0 {
 		return
@@ -247,5 +255,8 @@ func UnmarshalToolData(data json.RawMessage) (
 	latestPrompt = td.LatestPrompt
 	loadedMCPNames = td.LoadedMCPNames
 	toolOptionsJSON = td.ToolOptions
+	startReason = td.StartReason
+	networkPolicy = td.NetworkPolicy
+	features = td.Features
 	return
 }
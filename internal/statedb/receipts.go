@@ -0,0 +1,148 @@
+package statedb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Delivery receipt states, in the order a prompt moves through them.
+const (
+	ReceiptQueued       = "queued"       // accepted, waiting for the session to be ready
+	ReceiptDelivered    = "delivered"    // typed into the session
+	ReceiptAcknowledged = "acknowledged" // the session started working on it
+	ReceiptCompleted    = "completed"    // the session went idle again
+	ReceiptFailed       = "failed"       // the prompt never reached the session
+)
+
+// ErrReceiptNotFound means no receipt has the requested ID.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// DeliveryReceipt tracks one prompt sent to a session, so a caller that sent
+// it can later ask whether it arrived and whether the session has finished.
+type DeliveryReceipt struct {
+	ID           string    `json:"id"`
+	SessionID    string    `json:"session_id"`
+	Title        string    `json:"session_title"`
+	State        string    `json:"state"`
+	Queued       time.Time `json:"queued_at"`
+	Delivered    time.Time `json:"delivered_at,omitzero"`
+	Acknowledged time.Time `json:"acknowledged_at,omitzero"`
+	Completed    time.Time `json:"completed_at,omitzero"`
+	Failed       time.Time `json:"failed_at,omitzero"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Finished reports whether the receipt has reached a final state.
+func (r *DeliveryReceipt) Finished() bool {
+	return r.State == ReceiptCompleted || r.State == ReceiptFailed
+}
+
+// receiptState derives a receipt's state from its timestamps.
+func receiptState(r *DeliveryReceipt) string {
+	switch {
+	case !r.Failed.IsZero():
+		return ReceiptFailed
+	case !r.Completed.IsZero():
+		return ReceiptCompleted
+	case !r.Acknowledged.IsZero():
+		return ReceiptAcknowledged
+	case !r.Delivered.IsZero():
+		return ReceiptDelivered
+	}
+	return ReceiptQueued
+}
+
+// CreateReceipt records a queued prompt for sessionID under id.
+func (s *StateDB) CreateReceipt(id, sessionID, title string, at time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO delivery_receipts (id, session_id, title, queued) VALUES (?, ?, ?, ?)
+	`, id, sessionID, title, at.UnixNano())
+	if err != nil {
+		return fmt.Errorf("statedb: create receipt: %w", err)
+	}
+	return nil
+}
+
+// MarkReceiptDelivered notes that the receipt's prompt reached its session.
+func (s *StateDB) MarkReceiptDelivered(id string, at time.Time) error {
+	return s.updateReceipt(`
+		UPDATE delivery_receipts SET delivered = ? WHERE id = ? AND delivered = 0 AND failed = 0
+	`, at.UnixNano(), id)
+}
+
+// FailReceipt notes that the receipt's prompt could not be delivered.
+func (s *StateDB) FailReceipt(id string, cause error, at time.Time) error {
+	msg := ""
+	if cause != nil {
+		msg = cause.Error()
+	}
+	return s.updateReceipt(`
+		UPDATE delivery_receipts SET failed = ?, error = ? WHERE id = ? AND completed = 0 AND failed = 0
+	`, at.UnixNano(), msg, id)
+}
+
+func (s *StateDB) updateReceipt(query string, args ...any) error {
+	if _, err := s.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("statedb: update receipt: %w", err)
+	}
+	return nil
+}
+
+// GetReceipt returns the receipt with the given ID.
+func (s *StateDB) GetReceipt(id string) (*DeliveryReceipt, error) {
+	r := DeliveryReceipt{ID: id}
+	var queued, delivered, acknowledged, completed, failed int64
+	err := s.db.QueryRow(`
+		SELECT session_id, title, queued, delivered, acknowledged, completed, failed, error
+		FROM delivery_receipts WHERE id = ?
+	`, id).Scan(&r.SessionID, &r.Title, &queued, &delivered, &acknowledged, &completed, &failed, &r.Error)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrReceiptNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("statedb: get receipt: %w", err)
+	}
+	r.Queued = time.Unix(0, queued)
+	for _, f := range []struct {
+		ns  int64
+		dst *time.Time
+	}{{delivered, &r.Delivered}, {acknowledged, &r.Acknowledged}, {completed, &r.Completed}, {failed, &r.Failed}} {
+		if f.ns > 0 {
+			*f.dst = time.Unix(0, f.ns)
+		}
+	}
+	r.State = receiptState(&r)
+	return &r, nil
+}
+
+// advanceReceipts moves delivered receipts for sessionID forward on a status
+// observation, like advanceConductorTasks: running acknowledges them and
+// waiting/idle completes acknowledged ones, or delivered ones never seen
+// running once TaskStartGrace has passed.
+func advanceReceipts(tx *sql.Tx, sessionID, status string, now time.Time) error {
+	switch status {
+	case busyStatus:
+		_, err := tx.Exec(`
+			UPDATE delivery_receipts SET acknowledged = ?
+			WHERE session_id = ? AND delivered > 0 AND acknowledged = 0 AND completed = 0 AND failed = 0
+		`, now.UnixNano(), sessionID)
+		return err
+	case "waiting", "idle":
+		_, err := tx.Exec(`
+			UPDATE delivery_receipts
+			SET acknowledged = CASE WHEN acknowledged = 0 THEN delivered ELSE acknowledged END, completed = ?
+			WHERE session_id = ? AND delivered > 0 AND completed = 0 AND failed = 0
+				AND (acknowledged > 0 OR delivered < ?)
+		`, now.UnixNano(), sessionID, now.Add(-TaskStartGrace).UnixNano())
+		return err
+	}
+	return nil
+}
+
+// PruneReceipts deletes receipts queued before cutoff.
+func (s *StateDB) PruneReceipts(before time.Time) error {
+	_, err := s.db.Exec(`DELETE FROM delivery_receipts WHERE queued < ?`, before.UnixNano())
+	return err
+}
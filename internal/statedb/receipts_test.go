@@ -0,0 +1,86 @@
+package statedb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeliveryReceiptLifecycle(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+
+	if err := db.CreateReceipt("rcpt-a", "sess-1", "worker", base); err != nil {
+		t.Fatalf("CreateReceipt: %v", err)
+	}
+	// Status changes before delivery do not move a queued receipt
+	mustRecord(t, db, "sess-1", "running", base.Add(time.Second))
+	mustRecord(t, db, "sess-1", "idle", base.Add(time.Minute))
+	assertReceiptState(t, db, "rcpt-a", ReceiptQueued)
+
+	if err := db.MarkReceiptDelivered("rcpt-a", base.Add(2*time.Minute)); err != nil {
+		t.Fatalf("MarkReceiptDelivered: %v", err)
+	}
+	assertReceiptState(t, db, "rcpt-a", ReceiptDelivered)
+	mustRecord(t, db, "sess-1", "running", base.Add(2*time.Minute+time.Second))
+	assertReceiptState(t, db, "rcpt-a", ReceiptAcknowledged)
+	mustRecord(t, db, "sess-1", "waiting", base.Add(3*time.Minute))
+	r := assertReceiptState(t, db, "rcpt-a", ReceiptCompleted)
+	if !r.Finished() || r.Completed.Sub(r.Delivered) != time.Minute {
+		t.Errorf("unexpected completed receipt: %+v", r)
+	}
+
+	// Handled between polls: completed once the start grace has passed
+	deliveredAt := base.Add(10 * time.Minute)
+	if err := db.CreateReceipt("rcpt-b", "sess-1", "worker", deliveredAt); err != nil {
+		t.Fatalf("CreateReceipt: %v", err)
+	}
+	if err := db.MarkReceiptDelivered("rcpt-b", deliveredAt); err != nil {
+		t.Fatalf("MarkReceiptDelivered: %v", err)
+	}
+	mustRecord(t, db, "sess-1", "idle", deliveredAt.Add(5*time.Second))
+	assertReceiptState(t, db, "rcpt-b", ReceiptDelivered)
+	mustRecord(t, db, "sess-1", "idle", deliveredAt.Add(TaskStartGrace+time.Second))
+	r = assertReceiptState(t, db, "rcpt-b", ReceiptCompleted)
+	if !r.Acknowledged.Equal(r.Delivered) {
+		t.Errorf("acknowledged = %v, want delivery time %v", r.Acknowledged, r.Delivered)
+	}
+
+	// A failed receipt stays failed
+	if err := db.CreateReceipt("rcpt-c", "sess-2", "other", base); err != nil {
+		t.Fatalf("CreateReceipt: %v", err)
+	}
+	if err := db.FailReceipt("rcpt-c", errors.New("timeout waiting for agent"), base.Add(time.Minute)); err != nil {
+		t.Fatalf("FailReceipt: %v", err)
+	}
+	if err := db.MarkReceiptDelivered("rcpt-c", base.Add(2*time.Minute)); err != nil {
+		t.Fatalf("MarkReceiptDelivered: %v", err)
+	}
+	r = assertReceiptState(t, db, "rcpt-c", ReceiptFailed)
+	if r.Error != "timeout waiting for agent" || !r.Delivered.IsZero() {
+		t.Errorf("unexpected failed receipt: %+v", r)
+	}
+
+	if _, err := db.GetReceipt("rcpt-missing"); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("GetReceipt(missing) error = %v, want ErrReceiptNotFound", err)
+	}
+	if err := db.PruneReceipts(base.Add(5 * time.Minute)); err != nil {
+		t.Fatalf("PruneReceipts: %v", err)
+	}
+	if _, err := db.GetReceipt("rcpt-a"); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("rcpt-a should be pruned, got err=%v", err)
+	}
+	assertReceiptState(t, db, "rcpt-b", ReceiptCompleted)
+}
+
+func assertReceiptState(t *testing.T, db *StateDB, id, want string) *DeliveryReceipt {
+	t.Helper()
+	r, err := db.GetReceipt(id)
+	if err != nil {
+		t.Fatalf("GetReceipt(%s): %v", id, err)
+	}
+	if r.State != want {
+		t.Fatalf("receipt %s state = %s, want %s", id, r.State, want)
+	}
+	return r
+}
@@ -0,0 +1,42 @@
+package statedb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AppliedRequestTTL is how long the result of an applied request is kept.
+// A replay of a request older than this is applied again.
+const AppliedRequestTTL = 7 * 24 * time.Hour
+
+// AppliedRequest returns the recorded response of the request with this
+// idempotency ID, if it was applied.
+func (s *StateDB) AppliedRequest(id string) ([]byte, bool, error) {
+	var response []byte
+	err := s.db.QueryRow(`SELECT response FROM applied_requests WHERE id = ?`, id).Scan(&response)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("statedb: read applied request: %w", err)
+	}
+	return response, true, nil
+}
+
+// RecordAppliedRequest stores the response of an applied request and drops
+// records older than AppliedRequestTTL.
+func (s *StateDB) RecordAppliedRequest(id string, response []byte, at time.Time) error {
+	if _, err := s.db.Exec(`
+		INSERT OR REPLACE INTO applied_requests (id, ts, response) VALUES (?, ?, ?)
+	`, id, at.UnixNano(), response); err != nil {
+		return fmt.Errorf("statedb: record applied request: %w", err)
+	}
+	if _, err := s.db.Exec(`
+		DELETE FROM applied_requests WHERE ts < ?
+	`, at.Add(-AppliedRequestTTL).UnixNano()); err != nil {
+		return fmt.Errorf("statedb: prune applied requests: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppliedRequests(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+
+	if _, found, err := db.AppliedRequest("req-1"); err != nil || found {
+		t.Fatalf("unknown request: found=%v err=%v", found, err)
+	}
+	if err := db.RecordAppliedRequest("req-1", []byte(`{"ok":true}`), base); err != nil {
+		t.Fatalf("RecordAppliedRequest: %v", err)
+	}
+	response, found, err := db.AppliedRequest("req-1")
+	if err != nil || !found || string(response) != `{"ok":true}` {
+		t.Fatalf("AppliedRequest = %q %v %v", response, found, err)
+	}
+
+	// Recording later requests prunes the expired ones
+	if err := db.RecordAppliedRequest("req-2", []byte(`{}`), base.Add(AppliedRequestTTL+time.Minute)); err != nil {
+		t.Fatalf("RecordAppliedRequest: %v", err)
+	}
+	if _, found, _ := db.AppliedRequest("req-1"); found {
+		t.Error("expired request should be pruned")
+	}
+	if _, found, _ := db.AppliedRequest("req-2"); !found {
+		t.Error("recent request should be kept")
+	}
+}
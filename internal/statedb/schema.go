@@ -0,0 +1,94 @@
+package statedb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrSchemaTooNew means state was written by a newer agent-deck whose
+// schema this binary doesn't know. Writing it back could drop or mangle
+// fields the newer version added, so it is refused; reads still work.
+var ErrSchemaTooNew = errors.New("state was written by a newer agent-deck")
+
+// SchemaTooNewError reports a refused write to state at schema Version,
+// newer than the Supported one
+type SchemaTooNewError struct {
+	What      string
+	Version   int
+	Supported int
+}
+
+func (e *SchemaTooNewError) Error() string {
+	return fmt.Sprintf("%v: %s has schema version %d, this binary supports up to %d; upgrade agent-deck to modify it",
+		ErrSchemaTooNew, e.What, e.Version, e.Supported)
+}
+
+// Is makes errors.Is match ErrSchemaTooNew
+func (e *SchemaTooNewError) Is(target error) bool {
+	return target == ErrSchemaTooNew
+}
+
+// schemaMigration upgrades the database by one schema version
+type schemaMigration func(tx *sql.Tx) error
+
+// schemaMigrations upgrades existing databases, keyed by the version each
+// step produces. To change the schema, bump SchemaVersion, update the
+// CREATE statements in Migrate for new databases and add the step here for
+// existing ones.
+var schemaMigrations = map[int]schemaMigration{}
+
+// storedSchemaVersion returns the schema version recorded in metadata, 0
+// for a new database
+func storedSchemaVersion(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (int, error) {
+	var value string
+	err := q.QueryRow("SELECT value FROM metadata WHERE key = 'schema_version'").Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema_version %q", value)
+	}
+	return v, nil
+}
+
+// migrateSchema runs steps from (exclusive) up to to, in order. Every
+// version in between needs a step, or the database would be stamped with a
+// version it doesn't have.
+func migrateSchema(tx *sql.Tx, from, to int, steps map[int]schemaMigration) error {
+	for v := from + 1; v <= to; v++ {
+		step, ok := steps[v]
+		if !ok {
+			return fmt.Errorf("statedb: no migration to schema version %d", v)
+		}
+		if err := step(tx); err != nil {
+			return fmt.Errorf("statedb: migrate to schema version %d: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// checkWritable refuses session and group writes to a database from a newer
+// agent-deck
+func (s *StateDB) checkWritable() error {
+	if s.newerSchema > SchemaVersion {
+		return &SchemaTooNewError{What: "state.db", Version: s.newerSchema, Supported: SchemaVersion}
+	}
+	return nil
+}
+
+// NewerSchema returns the database's schema version when a newer
+// agent-deck wrote it, else 0. Sessions and groups are read-only then.
+func (s *StateDB) NewerSchema() int {
+	if s.newerSchema > SchemaVersion {
+		return s.newerSchema
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package statedb
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMigrateRefusesWritesToNewerSchema(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.SaveInstance(&InstanceRow{ID: "a", Title: "a", ProjectPath: "/tmp", GroupPath: "g", Tool: "shell", Status: "idle", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveInstance: %v", err)
+	}
+
+	// A newer agent-deck upgraded the database
+	newer := strconv.Itoa(SchemaVersion + 1)
+	if err := db.SetMeta("schema_version", newer); err != nil {
+		t.Fatalf("SetMeta: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if got, _ := db.GetMeta("schema_version"); got != newer {
+		t.Errorf("schema_version = %q after Migrate, want %q kept", got, newer)
+	}
+	if db.NewerSchema() != SchemaVersion+1 {
+		t.Errorf("NewerSchema() = %d, want %d", db.NewerSchema(), SchemaVersion+1)
+	}
+
+	rows, err := db.LoadInstances()
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("LoadInstances = %d rows, %v; reads must still work", len(rows), err)
+	}
+	err = db.SaveInstances(rows)
+	var tooNew *SchemaTooNewError
+	if !errors.As(err, &tooNew) || !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("SaveInstances err = %v, want SchemaTooNewError", err)
+	}
+	if err := db.SaveGroups(nil); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("SaveGroups err = %v, want ErrSchemaTooNew", err)
+	}
+	if err := db.DeleteInstance("a"); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("DeleteInstance err = %v, want ErrSchemaTooNew", err)
+	}
+}
+
+func TestMigrateSchemaRunsStepsInOrder(t *testing.T) {
+	db := newTestDB(t)
+	var ran []int
+	steps := map[int]schemaMigration{}
+	for v := 2; v <= 4; v++ {
+		steps[v] = func(tx *sql.Tx) error {
+			ran = append(ran, v)
+			return nil
+		}
+	}
+
+	tx, err := db.DB().Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := migrateSchema(tx, 1, 4, steps); err != nil {
+		t.Fatalf("migrateSchema: %v", err)
+	}
+	if len(ran) != 3 || ran[0] != 2 || ran[2] != 4 {
+		t.Errorf("ran steps %v, want [2 3 4]", ran)
+	}
+	if err := migrateSchema(tx, 1, 5, steps); err == nil {
+		t.Error("migrateSchema with a missing step succeeded")
+	}
+}
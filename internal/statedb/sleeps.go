@@ -0,0 +1,76 @@
+package statedb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Sleep is one recorded system suspend.
+type Sleep struct {
+	Start time.Time
+	End   time.Time
+}
+
+// RecordSleep notes that the machine slept from start to end, so busy time
+// skips it. Every agent-deck process watching for suspends reports the same
+// one; only the first to record it gets claimed, and acts on the resume for
+// all of them (e.g. re-firing missed heartbeats).
+func (s *StateDB) RecordSleep(start, end time.Time) (claimed bool, err error) {
+	if !end.After(start) {
+		return false, nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("statedb: begin record sleep: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var overlapping int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sleeps WHERE started < ? AND ended > ?`,
+		end.UnixNano(), start.UnixNano()).Scan(&overlapping); err != nil {
+		return false, fmt.Errorf("statedb: record sleep: %w", err)
+	}
+	if overlapping > 0 {
+		return false, nil
+	}
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO sleeps (started, ended) VALUES (?, ?)`,
+		start.UnixNano(), end.UnixNano()); err != nil {
+		return false, fmt.Errorf("statedb: record sleep: %w", err)
+	}
+	return true, tx.Commit()
+}
+
+// ReadSleeps returns the sleeps overlapping [from, to), oldest first.
+func (s *StateDB) ReadSleeps(from, to time.Time) ([]Sleep, error) {
+	return readSleeps(s.db, from, to)
+}
+
+// PruneSleeps deletes sleeps that ended before t.
+func (s *StateDB) PruneSleeps(before time.Time) error {
+	_, err := s.db.Exec(`DELETE FROM sleeps WHERE ended < ?`, before.UnixNano())
+	return err
+}
+
+// querier is what readSleeps needs from a *sql.DB or *sql.Tx.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func readSleeps(q querier, from, to time.Time) ([]Sleep, error) {
+	rows, err := q.Query(`SELECT started, ended FROM sleeps WHERE started < ? AND ended > ? ORDER BY started`,
+		to.UnixNano(), from.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("statedb: read sleeps: %w", err)
+	}
+	defer rows.Close()
+	var sleeps []Sleep
+	for rows.Next() {
+		var started, ended int64
+		if err := rows.Scan(&started, &ended); err != nil {
+			return nil, fmt.Errorf("statedb: read sleeps: %w", err)
+		}
+		sleeps = append(sleeps, Sleep{Start: time.Unix(0, started), End: time.Unix(0, ended)})
+	}
+	return sleeps, rows.Err()
+}
@@ -0,0 +1,60 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordSleepClaimsOnce(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	claimed, err := db.RecordSleep(base, base.Add(time.Hour))
+	if err != nil || !claimed {
+		t.Fatalf("first RecordSleep = %v, %v; want claimed", claimed, err)
+	}
+	// Another process saw the same suspend with slightly different bounds
+	claimed, err = db.RecordSleep(base.Add(3*time.Second), base.Add(time.Hour+2*time.Second))
+	if err != nil || claimed {
+		t.Fatalf("overlapping RecordSleep = %v, %v; want not claimed", claimed, err)
+	}
+
+	sleeps, err := db.ReadSleeps(base, base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("ReadSleeps: %v", err)
+	}
+	if len(sleeps) != 1 || !sleeps[0].Start.Equal(base) || !sleeps[0].End.Equal(base.Add(time.Hour)) {
+		t.Errorf("unexpected sleeps: %+v", sleeps)
+	}
+
+	if err := db.PruneSleeps(base.Add(2 * time.Hour)); err != nil {
+		t.Fatalf("PruneSleeps: %v", err)
+	}
+	if sleeps, _ := db.ReadSleeps(base, base.Add(2*time.Hour)); len(sleeps) != 0 {
+		t.Errorf("expected no sleeps after prune, got %+v", sleeps)
+	}
+}
+
+func TestRecordStatusSkipsSleep(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	// Running, then a 60s suspend inside a 90s observation gap
+	if _, err := db.RecordSleep(base.Add(20*time.Second), base.Add(80*time.Second)); err != nil {
+		t.Fatalf("RecordSleep: %v", err)
+	}
+	if err := db.RecordStatus("a", "running", base); err != nil {
+		t.Fatalf("RecordStatus: %v", err)
+	}
+	if err := db.RecordStatus("a", "running", base.Add(90*time.Second)); err != nil {
+		t.Fatalf("RecordStatus: %v", err)
+	}
+
+	durations, err := db.ReadStatusDurations(DayKey(base))
+	if err != nil {
+		t.Fatalf("ReadStatusDurations: %v", err)
+	}
+	if got := durations["a"].BusyToday; got != 30*time.Second {
+		t.Errorf("busy today = %v, want 30s (90s minus the 60s sleep)", got)
+	}
+}
@@ -0,0 +1,90 @@
+package statedb
+
+import (
+	"fmt"
+	"time"
+)
+
+// SnapshotSession is one session's row in a deck state snapshot.
+type SnapshotSession struct {
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	Tool      string `json:"tool,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Status    string `json:"status"`
+}
+
+// Snapshot is the deck's sessions and statuses at one point in time.
+type Snapshot struct {
+	Time     time.Time         `json:"time"`
+	Sessions []SnapshotSession `json:"sessions"`
+}
+
+// WriteSnapshot records the sessions as the deck state at at.
+func (s *StateDB) WriteSnapshot(at time.Time, sessions []SnapshotSession) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("statedb: begin snapshot: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	ts := at.UnixNano()
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO state_snapshot_times (ts) VALUES (?)`, ts); err != nil {
+		return fmt.Errorf("statedb: write snapshot: %w", err)
+	}
+	for _, row := range sessions {
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO state_snapshots (ts, session_id, title, tool, group_path, status)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, ts, row.SessionID, row.Title, row.Tool, row.Group, row.Status); err != nil {
+			return fmt.Errorf("statedb: write snapshot: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SnapshotAt returns the latest snapshot taken at or before at, or nil when
+// there is none.
+func (s *StateDB) SnapshotAt(at time.Time) (*Snapshot, error) {
+	var ts int64
+	err := s.db.QueryRow(`
+		SELECT COALESCE(MAX(ts), 0) FROM state_snapshot_times WHERE ts <= ?
+	`, at.UnixNano()).Scan(&ts)
+	if err != nil {
+		return nil, err
+	}
+	if ts == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT session_id, title, tool, group_path, status FROM state_snapshots
+		WHERE ts = ? ORDER BY group_path, title
+	`, ts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshot := &Snapshot{Time: time.Unix(0, ts), Sessions: []SnapshotSession{}}
+	for rows.Next() {
+		var r SnapshotSession
+		if err := rows.Scan(&r.SessionID, &r.Title, &r.Tool, &r.Group, &r.Status); err != nil {
+			return nil, err
+		}
+		snapshot.Sessions = append(snapshot.Sessions, r)
+	}
+	return snapshot, rows.Err()
+}
+
+// PruneSnapshots deletes snapshots taken before before.
+func (s *StateDB) PruneSnapshots(before time.Time) error {
+	ts := before.UnixNano()
+	if _, err := s.db.Exec(`DELETE FROM state_snapshots WHERE ts < ?`, ts); err != nil {
+		return fmt.Errorf("statedb: prune snapshots: %w", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM state_snapshot_times WHERE ts < ?`, ts); err != nil {
+		return fmt.Errorf("statedb: prune snapshots: %w", err)
+	}
+	return nil
+}
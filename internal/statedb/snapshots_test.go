@@ -0,0 +1,42 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotAtReturnsLatestBefore(t *testing.T) {
+	db := newTestDB(t)
+	base := time.Date(2026, 3, 10, 2, 0, 0, 0, time.Local)
+
+	if err := db.WriteSnapshot(base, []SnapshotSession{
+		{SessionID: "a", Title: "build", Tool: "claude", Status: "running"},
+	}); err != nil {
+		t.Fatalf("WriteSnapshot: %v", err)
+	}
+	if err := db.WriteSnapshot(base.Add(time.Hour), nil); err != nil {
+		t.Fatalf("WriteSnapshot (empty): %v", err)
+	}
+
+	if snap, err := db.SnapshotAt(base.Add(-time.Minute)); err != nil || snap != nil {
+		t.Fatalf("before any snapshot: %+v, %v", snap, err)
+	}
+	snap, err := db.SnapshotAt(base.Add(59 * time.Minute))
+	if err != nil || snap == nil {
+		t.Fatalf("SnapshotAt: %+v, %v", snap, err)
+	}
+	if !snap.Time.Equal(base) || len(snap.Sessions) != 1 || snap.Sessions[0].Status != "running" {
+		t.Errorf("snapshot = %+v, want the 02:00 one with build running", snap)
+	}
+	// An empty deck is a snapshot too
+	if snap, _ := db.SnapshotAt(base.Add(2 * time.Hour)); snap == nil || len(snap.Sessions) != 0 {
+		t.Errorf("empty snapshot = %+v", snap)
+	}
+
+	if err := db.PruneSnapshots(base.Add(time.Minute)); err != nil {
+		t.Fatalf("PruneSnapshots: %v", err)
+	}
+	if snap, _ := db.SnapshotAt(base.Add(30 * time.Minute)); snap != nil {
+		t.Errorf("pruned snapshot still returned: %+v", snap)
+	}
+}
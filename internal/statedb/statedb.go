@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 	"time"
 
@@ -14,7 +13,7 @@ import (
 )
 
 // SchemaVersion tracks the current database schema version.
-// Bump this when adding migrations.
+// Bump this when adding migrations (see schemaMigrations).
 const SchemaVersion = 1
 
 // StateDB wraps a SQLite database for session/group persistence.
@@ -23,6 +22,9 @@ const SchemaVersion = 1
 type StateDB struct {
 	db  *sql.DB
 	pid int
+	// newerSchema is the schema version of a database written by a newer
+	// agent-deck; session and group writes are refused while it is set
+	newerSchema int
 }
 
 // InstanceRow represents a session row in the database.
@@ -89,32 +91,30 @@ func Open(dbPath string) (*StateDB, error) {
 		return nil, fmt.Errorf("statedb: mkdir: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sql.Open("sqlite", dbPath+dsnParams)
 	if err != nil {
 		return nil, fmt.Errorf("statedb: open: %w", err)
 	}
 
-	// WAL mode: allows concurrent readers while writing
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+	// The pool opens connections lazily; ping so a bad path or a failing
+	// pragma surfaces here rather than on first use
+	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("statedb: wal mode: %w", err)
-	}
-
-	// Busy timeout: wait up to 5s if another process holds a lock
-	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("statedb: busy timeout: %w", err)
-	}
-
-	// Foreign keys (for future use)
-	if _, err := db.Exec("PRAGMA foreign_keys=ON"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("statedb: foreign keys: %w", err)
+		return nil, fmt.Errorf("statedb: open: %w", err)
 	}
 
 	return &StateDB{db: db, pid: os.Getpid()}, nil
 }
 
+// dsnParams configures every pooled connection, not just the first one:
+//   - busy_timeout: wait up to 5s if another process holds a lock
+//   - journal_mode=WAL: allows concurrent readers while writing
+//   - foreign_keys (for future use)
+//   - _txlock=immediate: transactions take the write lock at BEGIN, so a
+//     read-then-write transaction (SaveInstances) waits out a concurrent
+//     CLI or daemon writer instead of failing with SQLITE_BUSY on upgrade
+const dsnParams = "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate"
+
 // Close checkpoints WAL and closes the database.
 func (s *StateDB) Close() error {
 	// Checkpoint WAL to merge it back into the main database file
@@ -144,6 +144,14 @@ func (s *StateDB) Migrate() error {
 	`); err != nil {
 		return fmt.Errorf("statedb: create metadata: %w", err)
 	}
+	stored, err := storedSchemaVersion(tx)
+	if err != nil {
+		return fmt.Errorf("statedb: read schema version: %w", err)
+	}
+	s.newerSchema = 0
+	if stored > SchemaVersion {
+		s.newerSchema = stored
+	}
 
 	// instances table
 	if _, err := tx.Exec(`
@@ -196,6 +204,185 @@ func (s *StateDB) Migrate() error {
 		return fmt.Errorf("statedb: create heartbeats: %w", err)
 	}
 
+	// status durations: current state start + per-day busy time
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS status_tracking (
+			id       TEXT PRIMARY KEY,
+			status   TEXT NOT NULL,
+			since    INTEGER NOT NULL,
+			observed INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create status_tracking: %w", err)
+	}
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS busy_daily (
+			id      TEXT NOT NULL,
+			day     TEXT NOT NULL,
+			busy_ms INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (id, day)
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create busy_daily: %w", err)
+	}
+
+	// per-instance tokens used each day, kept after the instance is removed
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS tokens_daily (
+			id     TEXT NOT NULL,
+			day    TEXT NOT NULL,
+			tokens INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (id, day)
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create tokens_daily: %w", err)
+	}
+
+	// conductor task ledger for load reports
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS conductor_tasks (
+			seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+			conductor  TEXT NOT NULL,
+			session_id TEXT NOT NULL,
+			kind       TEXT NOT NULL,
+			enqueued   INTEGER NOT NULL,
+			started    INTEGER NOT NULL DEFAULT 0,
+			done       INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create conductor_tasks: %w", err)
+	}
+	if _, err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_conductor_tasks_open ON conductor_tasks (session_id, done)
+	`); err != nil {
+		return fmt.Errorf("statedb: create conductor_tasks index: %w", err)
+	}
+
+	// first occurrence of each conductor task dedupe key, and the duplicates merged into it
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS conductor_task_keys (
+			conductor  TEXT NOT NULL,
+			dedupe_key TEXT NOT NULL,
+			first_at   INTEGER NOT NULL,
+			PRIMARY KEY (conductor, dedupe_key)
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create conductor_task_keys: %w", err)
+	}
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS conductor_task_merges (
+			seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+			conductor  TEXT NOT NULL,
+			session_id TEXT NOT NULL,
+			dedupe_key TEXT NOT NULL,
+			first_at   INTEGER NOT NULL,
+			merged_at  INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create conductor_task_merges: %w", err)
+	}
+
+	// delivery receipts for prompts sent to sessions
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS delivery_receipts (
+			id           TEXT PRIMARY KEY,
+			session_id   TEXT NOT NULL,
+			title        TEXT NOT NULL DEFAULT '',
+			queued       INTEGER NOT NULL,
+			delivered    INTEGER NOT NULL DEFAULT 0,
+			acknowledged INTEGER NOT NULL DEFAULT 0,
+			completed    INTEGER NOT NULL DEFAULT 0,
+			failed       INTEGER NOT NULL DEFAULT 0,
+			error        TEXT NOT NULL DEFAULT ''
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create delivery_receipts: %w", err)
+	}
+	if _, err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_delivery_receipts_open ON delivery_receipts (session_id, completed)
+	`); err != nil {
+		return fmt.Errorf("statedb: create delivery_receipts index: %w", err)
+	}
+
+	// session audit log (creations with their start reason, removals)
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+			ts         INTEGER NOT NULL,
+			session_id TEXT NOT NULL,
+			title      TEXT NOT NULL DEFAULT '',
+			action     TEXT NOT NULL,
+			detail     TEXT NOT NULL DEFAULT ''
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create audit_log: %w", err)
+	}
+
+	// periodic deck state snapshots for point-in-time queries
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS state_snapshots (
+			ts         INTEGER NOT NULL,
+			session_id TEXT NOT NULL,
+			title      TEXT NOT NULL DEFAULT '',
+			tool       TEXT NOT NULL DEFAULT '',
+			group_path TEXT NOT NULL DEFAULT '',
+			status     TEXT NOT NULL,
+			PRIMARY KEY (ts, session_id)
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create state_snapshots: %w", err)
+	}
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS state_snapshot_times (
+			ts INTEGER PRIMARY KEY
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create state_snapshot_times: %w", err)
+	}
+
+	// results of client requests sent with an idempotency ID (offline replays)
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS applied_requests (
+			id       TEXT PRIMARY KEY,
+			ts       INTEGER NOT NULL,
+			response BLOB NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create applied_requests: %w", err)
+	}
+
+	// monotonic event counters exported as metrics (heartbeats, forks)
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS counters (
+			name  TEXT PRIMARY KEY,
+			value INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create counters: %w", err)
+	}
+
+	// system suspends, excluded from busy time
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS sleeps (
+			started INTEGER PRIMARY KEY,
+			ended   INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create sleeps: %w", err)
+	}
+
+	// A newer agent-deck's database keeps its version: stamping ours would
+	// hide that this binary doesn't know its schema
+	if s.newerSchema > 0 {
+		return tx.Commit()
+	}
+	// Upgrade existing databases; new ones were just created at SchemaVersion
+	if stored > 0 {
+		if err := migrateSchema(tx, stored, SchemaVersion, schemaMigrations); err != nil {
+			return err
+		}
+	}
+
 	// Set schema version
 	if _, err := tx.Exec(`
 		INSERT OR REPLACE INTO metadata (key, value) VALUES ('schema_version', ?)
@@ -220,6 +407,9 @@ func (s *StateDB) IsEmpty() (bool, error) {
 
 // SaveInstance inserts or replaces a single instance.
 func (s *StateDB) SaveInstance(inst *InstanceRow) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	toolData := inst.ToolData
 	if len(toolData) == 0 {
 		toolData = json.RawMessage("{}")
@@ -245,30 +435,55 @@ func (s *StateDB) SaveInstance(inst *InstanceRow) error {
 
 // SaveInstances inserts or replaces multiple instances in a single transaction.
 // It also removes any rows from the database that are not in the provided list,
-// ensuring deleted sessions don't reappear on reload.
+// ensuring deleted sessions don't reappear on reload. Sessions that appear or
+// disappear are recorded in the audit log.
 func (s *StateDB) SaveInstances(insts []*InstanceRow) error {
+	return s.saveInstances(insts, nil)
+}
+
+// SyncInstances upserts insts like SaveInstances, but only deletes rows
+// whose IDs are in known and missing from insts. A process passes the IDs
+// it last loaded, so sessions another process (CLI, web server, conductor)
+// added since then survive its save.
+func (s *StateDB) SyncInstances(insts []*InstanceRow, known map[string]bool) error {
+	if known == nil {
+		known = map[string]bool{}
+	}
+	return s.saveInstances(insts, known)
+}
+
+// saveInstances writes insts and deletes stored rows missing from them;
+// a non-nil known limits deletion to those IDs.
+func (s *StateDB) saveInstances(insts []*InstanceRow, known map[string]bool) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	// Delete rows not in the new list to prevent deleted sessions from reappearing.
-	if len(insts) == 0 {
-		if _, err := tx.Exec("DELETE FROM instances"); err != nil {
-			return err
-		}
-	} else {
-		placeholders := make([]string, len(insts))
-		args := make([]any, len(insts))
-		for i, inst := range insts {
-			placeholders[i] = "?"
-			args[i] = inst.ID
+	existing, err := instanceTitles(tx)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(insts))
+	for _, inst := range insts {
+		kept[inst.ID] = true
+	}
+
+	// Delete dropped rows to prevent deleted sessions from reappearing.
+	removed := make(map[string]string)
+	for id, title := range existing {
+		if kept[id] || (known != nil && !known[id]) {
+			continue
 		}
-		query := "DELETE FROM instances WHERE id NOT IN (" + strings.Join(placeholders, ",") + ")"
-		if _, err := tx.Exec(query, args...); err != nil {
+		if _, err := tx.Exec("DELETE FROM instances WHERE id = ?", id); err != nil {
 			return err
 		}
+		removed[id] = title
 	}
 
 	stmt, err := tx.Prepare(`
@@ -301,9 +516,41 @@ func (s *StateDB) SaveInstances(insts []*InstanceRow) error {
 		}
 	}
 
+	now := time.Now()
+	for _, inst := range insts {
+		if _, ok := existing[inst.ID]; !ok {
+			if err := appendAudit(tx, inst.ID, inst.Title, AuditCreated, startReasonOf(inst.ToolData), now); err != nil {
+				return err
+			}
+		}
+	}
+	for id, title := range removed {
+		if err := appendAudit(tx, id, title, AuditRemoved, "", now); err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit()
 }
 
+// instanceTitles returns the IDs and titles of all stored instances.
+func instanceTitles(tx *sql.Tx) (map[string]string, error) {
+	rows, err := tx.Query(`SELECT id, title FROM instances`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	titles := make(map[string]string)
+	for rows.Next() {
+		var id, title string
+		if err := rows.Scan(&id, &title); err != nil {
+			return nil, err
+		}
+		titles[id] = title
+	}
+	return titles, rows.Err()
+}
+
 // LoadInstances returns all instances ordered by sort_order.
 func (s *StateDB) LoadInstances() ([]*InstanceRow, error) {
 	rows, err := s.db.Query(`
@@ -345,13 +592,27 @@ func (s *StateDB) LoadInstances() ([]*InstanceRow, error) {
 
 // DeleteInstance removes an instance by ID.
 func (s *StateDB) DeleteInstance(id string) error {
-	_, err := s.db.Exec("DELETE FROM instances WHERE id = ?", id)
-	return err
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	var title string
+	found := s.db.QueryRow("SELECT title FROM instances WHERE id = ?", id).Scan(&title) == nil
+	if _, err := s.db.Exec("DELETE FROM instances WHERE id = ?", id); err != nil {
+		return err
+	}
+	if found {
+		_ = appendAudit(s.db, id, title, AuditRemoved, "", time.Now())
+	}
+	_, _ = s.db.Exec("DELETE FROM status_tracking WHERE id = ?", id)
+	return nil
 }
 
 // UpdateInstanceField updates a single column for a given instance.
 // field must be a valid column name (caller is responsible for safety).
 func (s *StateDB) UpdateInstanceField(id, field string, value any) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	query := fmt.Sprintf("UPDATE instances SET %s = ? WHERE id = ?", field)
 	_, err := s.db.Exec(query, value, id)
 	return err
@@ -361,6 +622,9 @@ func (s *StateDB) UpdateInstanceField(id, field string, value any) error {
 
 // SaveGroups replaces all groups in a single transaction.
 func (s *StateDB) SaveGroups(groups []*GroupRow) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -420,6 +684,9 @@ func (s *StateDB) LoadGroups() ([]*GroupRow, error) {
 
 // DeleteGroup removes a group by path.
 func (s *StateDB) DeleteGroup(path string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	_, err := s.db.Exec("DELETE FROM groups WHERE path = ?", path)
 	return err
 }
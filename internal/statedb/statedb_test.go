@@ -399,6 +399,89 @@ func TestConcurrentAccess(t *testing.T) {
 	wg.Wait()
 }
 
+func TestSyncInstancesOnlyDeletesKnown(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	row := func(id string) *InstanceRow {
+		return &InstanceRow{ID: id, Title: id, ProjectPath: "/tmp", GroupPath: "grp", Tool: "shell", Status: "idle", CreatedAt: now}
+	}
+	if err := db.SaveInstances([]*InstanceRow{row("a"), row("b")}); err != nil {
+		t.Fatalf("SaveInstances: %v", err)
+	}
+	// Another process adds "c" after this one loaded a and b
+	if err := db.SaveInstance(row("c")); err != nil {
+		t.Fatalf("SaveInstance: %v", err)
+	}
+
+	// This process dropped "b" and knows nothing of "c"
+	if err := db.SyncInstances([]*InstanceRow{row("a")}, map[string]bool{"a": true, "b": true}); err != nil {
+		t.Fatalf("SyncInstances: %v", err)
+	}
+
+	loaded, err := db.LoadInstances()
+	if err != nil {
+		t.Fatalf("LoadInstances: %v", err)
+	}
+	ids := map[string]bool{}
+	for _, r := range loaded {
+		ids[r.ID] = true
+	}
+	if len(ids) != 2 || !ids["a"] || !ids["c"] {
+		t.Errorf("ids = %v, want a and c", ids)
+	}
+}
+
+func TestConcurrentSavesAcrossConnections(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "state.db")
+	open := func() *StateDB {
+		db, err := Open(dbPath)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if err := db.Migrate(); err != nil {
+			t.Fatalf("Migrate: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+	// Two handles stand in for the CLI and a daemon
+	cli, daemon := open(), open()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i, db := range []*StateDB{cli, daemon} {
+		wg.Add(1)
+		go func(db *StateDB, prefix string) {
+			defer wg.Done()
+			known := map[string]bool{}
+			for j := 0; j < 20; j++ {
+				id := prefix + string(rune('a'+j))
+				known[id] = true
+				row := &InstanceRow{ID: id, Title: id, ProjectPath: "/tmp", GroupPath: "grp", Tool: "shell", Status: "idle", CreatedAt: time.Now()}
+				if err := db.SyncInstances([]*InstanceRow{row}, known); err != nil {
+					errs <- err
+				}
+				known = map[string]bool{id: true}
+			}
+		}(db, string(rune('p'+i)))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("SyncInstances: %v", err)
+	}
+
+	loaded, err := cli.LoadInstances()
+	if err != nil {
+		t.Fatalf("LoadInstances: %v", err)
+	}
+	// Each writer replaced its own row; neither deleted the other's
+	if len(loaded) != 2 {
+		t.Errorf("got %d instances, want one per writer", len(loaded))
+	}
+}
+
 func TestIsEmpty(t *testing.T) {
 	db := newTestDB(t)
 
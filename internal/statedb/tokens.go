@@ -0,0 +1,44 @@
+package statedb
+
+import "fmt"
+
+// RecordTokensDaily records that instance id has used tokens on day (a
+// DayKey). A day's count only grows: a lower reading, such as one taken
+// from a transcript that was since truncated, keeps the earlier one.
+func (s *StateDB) RecordTokensDaily(id, day string, tokens int) error {
+	_, err := s.db.Exec(`
+		INSERT INTO tokens_daily (id, day, tokens) VALUES (?, ?, ?)
+		ON CONFLICT(id, day) DO UPDATE SET tokens = MAX(tokens, excluded.tokens)
+	`, id, day, tokens)
+	if err != nil {
+		return fmt.Errorf("statedb: record tokens: %w", err)
+	}
+	return nil
+}
+
+// ReadTokensDaily returns the tokens each instance used on day, keyed by
+// instance ID. Removed instances keep their entries.
+func (s *StateDB) ReadTokensDaily(day string) (map[string]int, error) {
+	rows, err := s.db.Query(`SELECT id, tokens FROM tokens_daily WHERE day = ?`, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var tokens int
+		if err := rows.Scan(&id, &tokens); err != nil {
+			return nil, err
+		}
+		result[id] = tokens
+	}
+	return result, rows.Err()
+}
+
+// PruneTokensDaily deletes tokens_daily rows older than beforeDay (a DayKey).
+func (s *StateDB) PruneTokensDaily(beforeDay string) error {
+	_, err := s.db.Exec(`DELETE FROM tokens_daily WHERE day < ?`, beforeDay)
+	return err
+}
@@ -0,0 +1,36 @@
+package statedb
+
+import "testing"
+
+func TestTokensDailyKeepsHighestReading(t *testing.T) {
+	db := newTestDB(t)
+	for _, r := range []struct {
+		id, day string
+		tokens  int
+	}{
+		{"a", "2026-03-09", 900},
+		{"a", "2026-03-10", 100},
+		{"a", "2026-03-10", 400},
+		{"a", "2026-03-10", 250}, // lower reading keeps 400
+		{"b", "2026-03-10", 50},
+	} {
+		if err := db.RecordTokensDaily(r.id, r.day, r.tokens); err != nil {
+			t.Fatalf("RecordTokensDaily: %v", err)
+		}
+	}
+
+	got, err := db.ReadTokensDaily("2026-03-10")
+	if err != nil {
+		t.Fatalf("ReadTokensDaily: %v", err)
+	}
+	if len(got) != 2 || got["a"] != 400 || got["b"] != 50 {
+		t.Errorf("tokens = %v, want a=400 b=50", got)
+	}
+
+	if err := db.PruneTokensDaily("2026-03-10"); err != nil {
+		t.Fatalf("PruneTokensDaily: %v", err)
+	}
+	if old, _ := db.ReadTokensDaily("2026-03-09"); len(old) != 0 {
+		t.Errorf("after prune: %v, want none", old)
+	}
+}
@@ -0,0 +1,102 @@
+package tmux
+
+import "strings"
+
+// bellScanner finds terminal bells in a pane's control-mode %output data.
+// Agents ring the bell when they finish or need input, but BEL also ends
+// OSC sequences (window titles, hyperlinks), which Claude emits constantly,
+// so the scanner tracks escape sequences across chunks and only reports a
+// BEL outside of them.
+type bellScanner struct {
+	state bellState
+}
+
+type bellState int
+
+const (
+	bellNormal    bellState = iota
+	bellEsc                 // after ESC
+	bellOSC                 // inside ESC ] ... (BEL or ST)
+	bellOSCEsc              // ESC inside an OSC, maybe ST
+	bellString              // inside DCS/APC/PM/SOS ... ST
+	bellStringEsc           // ESC inside a string, maybe ST
+)
+
+// scan feeds one %output payload (tmux octal-escapes bytes below 0x20 and
+// backslashes as \ooo) and reports whether it rang the bell
+func (b *bellScanner) scan(data string) bool {
+	rang := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c == '\\' && i+3 < len(data) && isOctal(data[i+1]) && isOctal(data[i+2]) && isOctal(data[i+3]) {
+			c = (data[i+1]-'0')<<6 | (data[i+2]-'0')<<3 | (data[i+3] - '0')
+			i += 3
+		}
+		if b.feed(c) {
+			rang = true
+		}
+	}
+	return rang
+}
+
+// feed advances the escape-sequence state by one byte, reporting a bell
+func (b *bellScanner) feed(c byte) bool {
+	const (
+		bel = 0x07
+		esc = 0x1b
+	)
+	switch b.state {
+	case bellEsc:
+		switch c {
+		case ']':
+			b.state = bellOSC
+		case 'P', '_', '^', 'X':
+			b.state = bellString
+		default:
+			b.state = bellNormal
+		}
+	case bellOSC:
+		switch c {
+		case bel:
+			b.state = bellNormal
+		case esc:
+			b.state = bellOSCEsc
+		}
+	case bellOSCEsc:
+		// ESC \ is the string terminator; anything else aborts the OSC
+		b.state = bellNormal
+	case bellString:
+		if c == esc {
+			b.state = bellStringEsc
+		}
+	case bellStringEsc:
+		if c == '\\' {
+			b.state = bellNormal
+		} else {
+			b.state = bellString
+		}
+	default:
+		switch c {
+		case bel:
+			return true
+		case esc:
+			b.state = bellEsc
+		}
+	}
+	return false
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
+
+// parseOutputLine splits a "%output %<pane> <data>" line into the pane ID
+// and its data
+func parseOutputLine(raw string) (pane, data string, ok bool) {
+	rest, found := strings.CutPrefix(raw, "%output ")
+	if !found {
+		return "", "", false
+	}
+	pane, data, _ = strings.Cut(rest, " ")
+	return pane, data, pane != ""
+}
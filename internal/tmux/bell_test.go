@@ -0,0 +1,39 @@
+package tmux
+
+import "testing"
+
+func TestBellScanner(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []bool
+	}{
+		{"plain bell", []string{`done\015\012\007`}, []bool{true}},
+		{"no bell", []string{`hello \134 world`}, []bool{false}},
+		{"osc title ended by bel", []string{`\033]0;✳ Claude\007`}, []bool{false}},
+		{"osc ended by st then bell", []string{`\033]8;;http://x\033\134link\007`}, []bool{true}},
+		{"osc split across chunks", []string{`\033]2;work`, `ing\007`, `\007`}, []bool{false, false, true}},
+		{"dcs passthrough", []string{`\033Ptmux;\007\033\134`}, []bool{false}},
+		{"csi then bell", []string{`\033[2K\007`}, []bool{true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bellScanner
+			for i, chunk := range tt.chunks {
+				if got := b.scan(chunk); got != tt.want[i] {
+					t.Errorf("chunk %d %q: rang = %v, want %v", i, chunk, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseOutputLine(t *testing.T) {
+	pane, data, ok := parseOutputLine(`%output %3 ready\007`)
+	if !ok || pane != "%3" || data != `ready\007` {
+		t.Errorf("parseOutputLine = %q, %q, %v", pane, data, ok)
+	}
+	if _, _, ok := parseOutputLine("%begin 1 2 0"); ok {
+		t.Error("non-output line parsed")
+	}
+}
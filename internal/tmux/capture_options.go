@@ -0,0 +1,120 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultStatusLines is how many trailing lines busy/prompt patterns inspect.
+const defaultStatusLines = 25
+
+// CaptureOptions controls what pane content status detection classifies.
+// The zero value keeps the default behaviour: visible screen only, last 25
+// lines, ANSI stripped before matching.
+type CaptureOptions struct {
+	// Lines is how many trailing non-blank lines busy/prompt patterns see (0 = 25).
+	Lines int
+
+	// AltScreen also captures the pane's inactive screen (capture-pane -a) and
+	// places it above the visible content. For a full-screen TUI this is the
+	// normal screen behind it; for a plain CLI it is the TUI it last painted.
+	AltScreen bool
+
+	// MatchANSI captures with escape sequences (capture-pane -e) and matches
+	// busy/prompt patterns against them unstripped, so regexes can key on
+	// colors. Spinner and built-in prompt detection still see stripped text.
+	MatchANSI bool
+
+	// StripSpinnerFrames trims spinner characters off line ends and drops
+	// lines holding only a spinner frame before classification.
+	StripSpinnerFrames bool
+
+	// CollapseProgress keeps only the last of consecutive lines that differ
+	// in numbers only ("Downloading 10%", "Downloading 20%"), so progress
+	// output does not push other lines out of the Lines window.
+	CollapseProgress bool
+
+	// BannerEnd marks the last line of the tool's banner (plain text, or a
+	// regex with "re:"): the screen down to the first matching line is
+	// ignored, so banner text cannot match busy or prompt patterns.
+	BannerEnd string
+}
+
+// IsZero reports whether no capture option is set.
+func (o CaptureOptions) IsZero() bool {
+	return o == CaptureOptions{}
+}
+
+func (o CaptureOptions) lines() int {
+	if o.Lines > 0 {
+		return o.Lines
+	}
+	return defaultStatusLines
+}
+
+// matchText prepares content for busy/prompt pattern matching.
+func (o CaptureOptions) matchText(content string) string {
+	if o.MatchANSI {
+		return content
+	}
+	return StripANSI(content)
+}
+
+// plainText returns content for detectors that expect text without escapes.
+func (o CaptureOptions) plainText(content string) string {
+	if o.MatchANSI {
+		return StripANSI(content)
+	}
+	return content
+}
+
+// captureArgs builds the capture-pane invocation for status detection.
+func (o CaptureOptions) captureArgs(target string, alternate bool) []string {
+	args := []string{"capture-pane", "-t", target, "-p", "-J"}
+	if o.MatchANSI {
+		args = append(args, "-e")
+	}
+	if alternate {
+		// -q: no error when the pane has no alternate screen
+		args = append(args, "-a", "-q")
+	}
+	return args
+}
+
+// captureOptionsLocked returns the capture options for this session. Caller holds s.mu.
+func (s *Session) captureOptionsLocked() CaptureOptions {
+	if s.resolvedPatterns == nil {
+		return CaptureOptions{}
+	}
+	return s.resolvedPatterns.Capture
+}
+
+// captureForStatus captures pane content for status classification. Without
+// options it shares the cached CapturePane path; otherwise it runs a dedicated
+// capture with the requested flags.
+func (s *Session) captureForStatus(opts CaptureOptions) (string, error) {
+	if !opts.AltScreen && !opts.MatchANSI {
+		return s.CapturePane()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	output, err := CommandContext(ctx, opts.captureArgs(s.Name, false)...).Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", ErrCaptureTimeout
+		}
+		return "", fmt.Errorf("failed to capture pane: %w", err)
+	}
+	content := string(output)
+
+	if opts.AltScreen {
+		alt, err := CommandContext(ctx, opts.captureArgs(s.Name, true)...).Output()
+		if err == nil && strings.TrimSpace(string(alt)) != "" {
+			content = strings.TrimRight(string(alt), "\n") + "\n" + content
+		}
+	}
+	return content, nil
+}
@@ -0,0 +1,181 @@
+package tmux
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Classifier modes: an override classifier decides whenever it has an
+// opinion; an augment classifier is only asked when neither busy nor prompt
+// patterns match
+const (
+	ClassifierOverride = "override"
+	ClassifierAugment  = "augment"
+)
+
+// defaultClassifierTimeout bounds a classifier run; status polling waits on it
+const defaultClassifierTimeout = time.Second
+
+// StatusClassifier is an external program that reads a tool's pane for
+// status detection, for tools whose state patterns cannot express. It runs
+// with sh -c, gets the captured pane text on stdin and AGENTDECK_TOOL,
+// AGENTDECK_SESSION and AGENTDECK_TITLE in its environment, and prints a
+// ClassifierVerdict as JSON.
+type StatusClassifier struct {
+	Command string
+	Mode    string        // ClassifierOverride (default) or ClassifierAugment
+	Timeout time.Duration // default 1s
+	Tool    string
+}
+
+// IsZero reports whether no classifier is configured
+func (c *StatusClassifier) IsZero() bool {
+	return c == nil || strings.TrimSpace(c.Command) == ""
+}
+
+// augments reports whether the classifier only fills in for patterns
+func (c *StatusClassifier) augments() bool {
+	return c.Mode == ClassifierAugment
+}
+
+// ClassifierVerdict is a classifier's output. State is "active" (or
+// "busy"), "waiting" or "idle"; empty or "unknown" leaves the session to the
+// patterns.
+type ClassifierVerdict struct {
+	State      string   `json:"state"`
+	Confidence float64  `json:"confidence,omitempty"` // 0-1, default 0.9
+	Reason     string   `json:"reason,omitempty"`
+	Evidence   []string `json:"evidence,omitempty"`
+}
+
+// normalize maps the accepted state spellings onto active, waiting and idle
+// and defaults the confidence. It reports false when the verdict has no
+// opinion.
+func (v *ClassifierVerdict) normalize() bool {
+	switch strings.ToLower(strings.TrimSpace(v.State)) {
+	case "active", "busy", "running":
+		v.State = "active"
+	case "waiting", "prompt", "input":
+		v.State = "waiting"
+	case "idle", "done":
+		v.State = "idle"
+	default:
+		return false
+	}
+	if v.Confidence <= 0 || v.Confidence > 1 {
+		v.Confidence = 0.9
+	}
+	return true
+}
+
+// SetClassifier sets the external status classifier; nil or an empty
+// command disables it.
+func (s *Session) SetClassifier(c *StatusClassifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.IsZero() {
+		c = nil
+	}
+	s.classifier = c
+}
+
+// classifyPane runs the classifier on captured pane content, returning nil
+// when there is none, it fails, or it has no opinion. A failing classifier
+// leaves detection to the patterns. Must not be called with s.mu held.
+func (s *Session) classifyPane(c *StatusClassifier, content string) *ClassifierVerdict {
+	if c == nil {
+		return nil
+	}
+	verdict, err := runClassifier(c, content, s.Name, s.DisplayName)
+	if err != nil {
+		statusLog.Debug("classifier_failed",
+			slog.String("session", s.Name),
+			slog.String("command", c.Command),
+			slog.String("error", err.Error()))
+		return nil
+	}
+	return verdict
+}
+
+// runClassifier runs one classification of content
+func runClassifier(c *StatusClassifier, content, sessionName, title string) (*ClassifierVerdict, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultClassifierTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	// Kill the whole process group on timeout, or a child holding stdout
+	// would keep status polling waiting
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	cmd.WaitDelay = 100 * time.Millisecond
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Env = append(os.Environ(),
+		"AGENTDECK_TOOL="+c.Tool,
+		"AGENTDECK_SESSION="+sessionName,
+		"AGENTDECK_TITLE="+title,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	var verdict ClassifierVerdict
+	if err := json.Unmarshal(bytes.TrimSpace(out), &verdict); err != nil {
+		return nil, fmt.Errorf("invalid verdict %q: %w", strings.TrimSpace(string(out)), err)
+	}
+	if !verdict.normalize() {
+		return nil, nil
+	}
+	return &verdict, nil
+}
+
+// applyVerdictLocked records a classifier verdict as the session's status,
+// keeping the state tracker consistent with pattern detection: active
+// clears acknowledgment, and a waiting verdict the user has already seen is
+// idle. MUST be called with s.mu held.
+func (s *Session) applyVerdictLocked(v *ClassifierVerdict, currentTS int64) string {
+	s.ensureStateTrackerLocked()
+	s.resetPromptNoBusyHoldLocked()
+	s.startupAt = time.Time{}
+
+	evidence := v.Evidence
+	if v.Reason != "" {
+		evidence = append([]string{v.Reason}, evidence...)
+	}
+	state := v.State
+	switch state {
+	case "active":
+		s.noteStatusSignal(ReasonClassifier, v.Confidence, "active", evidence...)
+		s.stateTracker.lastChangeTime = time.Now()
+		s.stateTracker.acknowledged = false
+		s.stateTracker.lastActivityTimestamp = currentTS
+	case "waiting":
+		s.noteStatusSignal(ReasonClassifier, v.Confidence, "prompt", evidence...)
+		if s.stateTracker.acknowledged {
+			state = "idle"
+		} else if s.lastStableStatus != "waiting" {
+			s.stateTracker.waitingSince = time.Now()
+		}
+	case "idle":
+		s.noteStatusSignal(ReasonClassifier, v.Confidence, "prompt", evidence...)
+	}
+	s.lastStableStatus = state
+	return state
+}
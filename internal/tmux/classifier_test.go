@@ -0,0 +1,62 @@
+package tmux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunClassifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   string
+		wantState string
+		wantErr   bool
+	}{
+		{"busy spelling", `printf '{"state":"busy","reason":"compiling"}'`, "active", false},
+		{"reads stdin", `grep -q 'Continue?' && echo '{"state":"input","confidence":0.7}'`, "waiting", false},
+		{"no opinion", `echo '{"state":"unknown"}'`, "", false},
+		{"sees env", `test "$AGENTDECK_TOOL" = mytool && echo '{"state":"idle"}'`, "idle", false},
+		{"bad json", `echo nope`, "", true},
+		{"exit status", `echo broken >&2; exit 3`, "", true},
+		{"timeout", `sleep 5`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &StatusClassifier{Command: tt.command, Tool: "mytool", Timeout: 500 * time.Millisecond}
+			verdict, err := runClassifier(c, "Build done.\nContinue? [y/n]\n", "agentdeck_x", "x")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			state := ""
+			if verdict != nil {
+				state = verdict.State
+				if verdict.Confidence <= 0 || verdict.Confidence > 1 {
+					t.Errorf("confidence = %v", verdict.Confidence)
+				}
+			}
+			if state != tt.wantState {
+				t.Errorf("state = %q, want %q", state, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestApplyVerdictRespectsAcknowledgment(t *testing.T) {
+	s := NewSession("classifier-test", "/tmp")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if got := s.applyVerdictLocked(&ClassifierVerdict{State: "waiting", Confidence: 0.9}, 1); got != "waiting" {
+		t.Errorf("unacknowledged waiting = %q", got)
+	}
+	s.stateTracker.acknowledged = true
+	if got := s.applyVerdictLocked(&ClassifierVerdict{State: "waiting", Confidence: 0.9}, 2); got != "idle" {
+		t.Errorf("acknowledged waiting = %q, want idle", got)
+	}
+	if got := s.applyVerdictLocked(&ClassifierVerdict{State: "active", Confidence: 0.9}, 3); got != "active" || s.stateTracker.acknowledged {
+		t.Errorf("active = %q, acknowledged = %v", got, s.stateTracker.acknowledged)
+	}
+	if s.signal == nil || s.signal.reason != ReasonClassifier {
+		t.Errorf("signal = %+v, want classifier", s.signal)
+	}
+}
@@ -0,0 +1,27 @@
+package tmux
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/asheshgoplani/agent-deck/internal/deck"
+)
+
+// Command returns a tmux command against this deck's server: the default
+// server for the default deck, a socket of its own (tmux -L) for a named one
+func Command(args ...string) *exec.Cmd {
+	return exec.Command("tmux", withDeckSocket(args)...)
+}
+
+// CommandContext is Command bound to ctx
+func CommandContext(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "tmux", withDeckSocket(args)...)
+}
+
+func withDeckSocket(args []string) []string {
+	socket := deck.TmuxSocket()
+	if socket == "" {
+		return args
+	}
+	return append([]string{"-L", socket}, args...)
+}
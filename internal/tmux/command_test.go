@@ -0,0 +1,20 @@
+package tmux
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCommandDeckSocket(t *testing.T) {
+	t.Setenv("AGENT_DECK_HOME", "")
+	t.Setenv("AGENT_DECK_NAME", "")
+	if got := Command("list-sessions").Args; !slices.Equal(got, []string{"tmux", "list-sessions"}) {
+		t.Errorf("default deck args = %v, want the default server", got)
+	}
+
+	t.Setenv("AGENT_DECK_NAME", "v2")
+	want := []string{"tmux", "-L", "agent-deck-v2", "list-sessions"}
+	if got := Command("list-sessions").Args; !slices.Equal(got, want) {
+		t.Errorf("named deck args = %v, want %v", got, want)
+	}
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/asheshgoplani/agent-deck/internal/logging"
 )
 
-var pipeLog = logging.ForComponent("pipe")
+var pipeLog = logging.ForComponent(logging.CompPipe)
 
 // ControlPipe wraps a persistent `tmux -C attach-session -t <name>` process.
 // It provides event-driven output detection via %output events and
@@ -25,8 +25,10 @@ type ControlPipe struct {
 	stdin       io.WriteCloser
 	stdout      io.ReadCloser
 
-	// Event channel: fires when the session produces output
+	// Event channels: fire when the session produces output, and when that
+	// output rings the terminal bell
 	outputEvents chan struct{}
+	bellEvents   chan struct{}
 
 	// Command/response serialization
 	cmdMu      sync.Mutex
@@ -41,6 +43,7 @@ type ControlPipe struct {
 	mu         sync.RWMutex
 	alive      bool
 	lastOutput time.Time
+	lastBell   time.Time
 
 	// Lifecycle
 	done      chan struct{}
@@ -56,7 +59,7 @@ type commandResponse struct {
 // Blocks until the initial handshake completes (or 2s timeout), so the pipe is
 // ready for SendCommand immediately after return.
 func NewControlPipe(sessionName string) (*ControlPipe, error) {
-	cmd := exec.Command("tmux", "-C", "attach-session", "-t", sessionName)
+	cmd := Command("-C", "attach-session", "-t", sessionName)
 	// Put in own process group so we can kill the entire group on shutdown
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
@@ -81,6 +84,7 @@ func NewControlPipe(sessionName string) (*ControlPipe, error) {
 		stdin:        stdin,
 		stdout:       stdout,
 		outputEvents: make(chan struct{}, 64),
+		bellEvents:   make(chan struct{}, 1),
 		responseCh:   make(chan commandResponse, 1),
 		ready:        make(chan struct{}),
 		alive:        true,
@@ -130,7 +134,8 @@ func (cp *ControlPipe) reader() {
 	var (
 		inCapture bool
 		lines     []string
-		isReady   bool // tracks whether initial handshake has completed
+		isReady   bool                        // tracks whether initial handshake has completed
+		bells     = map[string]*bellScanner{} // pane ID -> escape state
 	)
 
 	for scanner.Scan() {
@@ -139,8 +144,20 @@ func (cp *ControlPipe) reader() {
 		// All %-prefixed lines are control mode protocol messages
 		if strings.HasPrefix(raw, "%") {
 			if strings.HasPrefix(raw, "%output") {
+				rang := false
+				if pane, data, ok := parseOutputLine(raw); ok {
+					if bells[pane] == nil {
+						bells[pane] = &bellScanner{}
+					}
+					rang = bells[pane].scan(data)
+				}
+
+				now := time.Now()
 				cp.mu.Lock()
-				cp.lastOutput = time.Now()
+				cp.lastOutput = now
+				if rang {
+					cp.lastBell = now
+				}
 				cp.mu.Unlock()
 
 				// Non-blocking send to output events channel
@@ -148,6 +165,13 @@ func (cp *ControlPipe) reader() {
 				case cp.outputEvents <- struct{}{}:
 				default:
 				}
+				if rang {
+					pipeLog.Debug("pipe_bell", slog.String("session", cp.sessionName))
+					select {
+					case cp.bellEvents <- struct{}{}:
+					default:
+					}
+				}
 			} else if strings.HasPrefix(raw, "%begin ") {
 				inCapture = true
 				lines = lines[:0]
@@ -267,6 +291,19 @@ func (cp *ControlPipe) LastOutputTime() time.Time {
 	return cp.lastOutput
 }
 
+// BellEvents returns a channel that fires when the session rings the
+// terminal bell. Bells rung before the last one was received coalesce.
+func (cp *ControlPipe) BellEvents() <-chan struct{} {
+	return cp.bellEvents
+}
+
+// LastBellTime returns when the session last rang the terminal bell.
+func (cp *ControlPipe) LastBellTime() time.Time {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	return cp.lastBell
+}
+
 // IsAlive returns true if the control mode process is still running.
 func (cp *ControlPipe) IsAlive() bool {
 	cp.mu.RLock()
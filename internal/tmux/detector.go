@@ -69,7 +69,20 @@ func (d *PromptDetector) HasPrompt(content string) bool {
 			return false
 		}
 		return strings.Contains(content, "codex>") ||
-			strings.Contains(content, "Continue?")
+			strings.Contains(content, "Continue?") ||
+			strings.Contains(content, "Ask Codex to do anything") ||
+			strings.Contains(content, "Would you like to") ||
+			strings.Contains(content, "You've hit your usage limit")
+
+	case "ollama":
+		// ollama run REPL: waiting when the last line is the ">>> " prompt
+		// ("... " while a """ multi-line message is being typed)
+		return d.hasOllamaPrompt(content)
+
+	case "aider":
+		// aider: waiting at a (Y)es/(N)o confirmation or an empty input
+		// prompt ("> ", "ask> ", "architect> ", "multi> ")
+		return d.hasAiderPrompt(content)
 
 	default:
 		// Generic shell - check for common prompts
@@ -323,7 +336,9 @@ func (d *PromptDetector) hasClaudePrompt(content string) bool {
 }
 
 // hasGeminiPrompt detects if Gemini CLI is waiting for input.
-// Checks last 10 non-blank lines for known Gemini prompt patterns.
+// Checks last 10 non-blank lines for known Gemini prompt patterns. The
+// input box stays on screen while a response streams, so the busy line
+// ("esc to cancel") takes priority over it.
 func (d *PromptDetector) hasGeminiPrompt(content string) bool {
 	lines := strings.Split(content, "\n")
 	var lastLines []string
@@ -334,12 +349,21 @@ func (d *PromptDetector) hasGeminiPrompt(content string) bool {
 		}
 	}
 
+	for _, line := range lastLines {
+		if strings.Contains(line, "esc to cancel") {
+			return false
+		}
+	}
+
 	for _, line := range lastLines {
 		// Direct prompt patterns
 		if line == "gemini>" || strings.Contains(line, "gemini>") {
 			return true
 		}
-		if strings.Contains(line, "Yes, allow once") {
+		if strings.Contains(line, "Yes, allow once") ||
+			strings.Contains(line, "Allow execution") ||
+			strings.Contains(line, "Apply this change?") ||
+			strings.Contains(line, "Waiting for user confirmation") {
 			return true
 		}
 		if strings.Contains(line, "Type your message") {
@@ -536,3 +560,26 @@ func StripANSI(content string) string {
 
 	return b.String()
 }
+
+// hasOllamaPrompt detects the ollama run REPL waiting for a message
+func (d *PromptDetector) hasOllamaPrompt(content string) bool {
+	lines := strings.Split(strings.TrimRight(content, " \t\n"), "\n")
+	last := strings.TrimSpace(StripANSI(lines[len(lines)-1]))
+	return last == ">>>" || last == "..." ||
+		strings.HasPrefix(last, ">>> ") || strings.HasPrefix(last, "... ")
+}
+
+// hasAiderPrompt detects aider waiting for input or a confirmation
+func (d *PromptDetector) hasAiderPrompt(content string) bool {
+	lines := strings.Split(strings.TrimRight(StripANSI(content), " \t\n"), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if strings.Contains(last, "Waiting for ") {
+		return false
+	}
+	if strings.Contains(last, "(Y)es/(N)o") {
+		return true
+	}
+	// The input prompt: an optional chat mode, then ">"
+	mode, ok := strings.CutSuffix(last, ">")
+	return ok && strings.Trim(mode, "abcdefghijklmnopqrstuvwxyz-") == ""
+}
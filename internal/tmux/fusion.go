@@ -0,0 +1,321 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fusion signals, the keys of StatusFusion.Weights
+const (
+	SignalPatterns = "patterns" // busy or prompt pattern on screen
+	SignalOutput   = "output"   // the pane's text changed since the last poll
+	SignalCPU      = "cpu"      // CPU used by the pane's process tree
+	SignalActivity = "activity" // tmux's window activity timestamp moved
+)
+
+// defaultFusionWeights rank the signals: patterns are the most specific,
+// CPU catches silent work, output and activity also move on redraws
+var defaultFusionWeights = map[string]float64{
+	SignalPatterns: 1.0,
+	SignalCPU:      0.8,
+	SignalOutput:   0.4,
+	SignalActivity: 0.2,
+}
+
+const (
+	defaultFusionThreshold = 0.3
+	defaultCPUBusyPercent  = 10
+	// fusionCPUSampleInterval spaces CPU samples; each walks the pane's
+	// process tree
+	fusionCPUSampleInterval = time.Second
+	// quietVote is how strongly "nothing changed" counts against busy: an
+	// agent waiting on a long tool call is quiet too
+	quietVote = -0.25
+)
+
+// StatusFusion weighs several status signals into one busy score, so a
+// session stays active where patterns alone would call it waiting, like an
+// agent blocked on a long, silent tool call while its child burns CPU. Each
+// signal votes from -1 (quiet) to 1 (busy); the weighted mean of the votes
+// reaching Threshold means active. Explicit busy patterns stay
+// authoritative, and with the default weights a visible prompt outweighs
+// every other signal.
+type StatusFusion struct {
+	// Weights per signal (see the Signal constants); a missing signal gets
+	// its default weight and 0 disables it
+	Weights map[string]float64
+	// Threshold is the score from -1 to 1 at which the session is active.
+	// Default 0.3
+	Threshold float64
+	// CPUBusyPercent is the pane's process tree CPU usage that counts as
+	// busy, in percent of one core. Default 10
+	CPUBusyPercent float64
+}
+
+func (f *StatusFusion) weight(signal string) float64 {
+	if w, ok := f.Weights[signal]; ok {
+		return max(w, 0)
+	}
+	return defaultFusionWeights[signal]
+}
+
+func (f *StatusFusion) threshold() float64 {
+	if f.Threshold <= 0 || f.Threshold > 1 {
+		return defaultFusionThreshold
+	}
+	return f.Threshold
+}
+
+func (f *StatusFusion) cpuBusyPercent() float64 {
+	if f.CPUBusyPercent <= 0 {
+		return defaultCPUBusyPercent
+	}
+	return f.CPUBusyPercent
+}
+
+// fusionVote is one signal's opinion: -1 quiet to 1 busy
+type fusionVote struct {
+	signal string
+	score  float64
+	note   string
+}
+
+// fuse returns the weighted mean of votes, false if no voting signal has
+// weight
+func (f *StatusFusion) fuse(votes []fusionVote) (float64, bool) {
+	var sum, total float64
+	for _, v := range votes {
+		w := f.weight(v.signal)
+		sum += w * v.score
+		total += w
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return sum / total, true
+}
+
+// fusionState is what fusion compares against between polls
+type fusionState struct {
+	hash       string
+	activityTS int64
+	cpu        cpuSample
+}
+
+// cpuSample is the pane's process tree CPU time at one moment, with the
+// usage since the previous sample
+type cpuSample struct {
+	at      time.Time
+	total   time.Duration
+	ok      bool
+	percent float64
+	known   bool // percent covers an interval
+}
+
+// fusionInput is one poll's observations
+type fusionInput struct {
+	captured   bool // content was read
+	content    string
+	busy       bool
+	prompt     bool
+	activityTS int64
+	cpuPercent float64
+	cpuKnown   bool
+}
+
+// SetStatusFusion enables signal fusion; nil disables it
+func (s *Session) SetStatusFusion(f *StatusFusion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fusion = f
+	s.fusionState = fusionState{}
+}
+
+// fusionCPULocked returns the fusion settings when they weigh CPU, for
+// sampling before a decision. MUST be called with s.mu held.
+func (s *Session) fusionCPULocked() *StatusFusion {
+	if s.fusion == nil || s.fusion.weight(SignalCPU) == 0 {
+		return nil
+	}
+	return s.fusion
+}
+
+// paneCPUPercent samples the CPU used by the pane's process tree since the
+// previous sample, reporting false until there are two samples. Samples are
+// rate-limited; must not be called with s.mu held.
+func (s *Session) paneCPUPercent() (float64, bool) {
+	s.mu.Lock()
+	prev := s.fusionState.cpu
+	s.mu.Unlock()
+	now := time.Now()
+	if !prev.at.IsZero() && now.Sub(prev.at) < fusionCPUSampleInterval {
+		return prev.percent, prev.known
+	}
+
+	_, pids := s.getPaneProcessTree()
+	total, err := processCPUTime(pids)
+	next := cpuSample{at: now, total: total, ok: err == nil && len(pids) > 0}
+	if next.ok && prev.ok {
+		// A child that exited takes its CPU time with it; count that as idle
+		delta := max(total-prev.total, 0)
+		next.percent = 100 * delta.Seconds() / now.Sub(prev.at).Seconds()
+		next.known = true
+	}
+	s.mu.Lock()
+	s.fusionState.cpu = next
+	s.mu.Unlock()
+	return next.percent, next.known
+}
+
+// fusedActiveLocked collects the votes for one poll, updates what the next
+// poll compares against and reports whether they add up to active, noting
+// the fusion signal if so. MUST be called with s.mu held.
+func (s *Session) fusedActiveLocked(in fusionInput) bool {
+	f := s.fusion
+	// Starting tools burn CPU too; startup has its own state
+	if f == nil || s.inStartupWindowLocked() {
+		return false
+	}
+	var votes []fusionVote
+	if in.captured {
+		switch {
+		case in.busy:
+			votes = append(votes, fusionVote{SignalPatterns, 1, "busy pattern"})
+		case in.prompt:
+			votes = append(votes, fusionVote{SignalPatterns, -1, "prompt"})
+		}
+		hash := s.hashContent(s.normalizeContent(in.content))
+		if s.fusionState.hash != "" {
+			if hash != s.fusionState.hash {
+				votes = append(votes, fusionVote{SignalOutput, 1, "output changed"})
+			} else {
+				votes = append(votes, fusionVote{SignalOutput, quietVote, "output unchanged"})
+			}
+		}
+		s.fusionState.hash = hash
+	}
+	if s.fusionState.activityTS != 0 {
+		if in.activityTS != s.fusionState.activityTS {
+			votes = append(votes, fusionVote{SignalActivity, 1, "window activity"})
+		} else {
+			votes = append(votes, fusionVote{SignalActivity, quietVote, "no window activity"})
+		}
+	}
+	s.fusionState.activityTS = in.activityTS
+	if in.cpuKnown {
+		if in.cpuPercent >= f.cpuBusyPercent() {
+			votes = append(votes, fusionVote{SignalCPU, 1, fmt.Sprintf("cpu %.0f%%", in.cpuPercent)})
+		} else {
+			votes = append(votes, fusionVote{SignalCPU, 2 * quietVote, fmt.Sprintf("cpu %.0f%%", in.cpuPercent)})
+		}
+	}
+
+	score, ok := f.fuse(votes)
+	if !ok || score < f.threshold() {
+		return false
+	}
+	evidence := make([]string, 0, len(votes))
+	for _, v := range votes {
+		if f.weight(v.signal) > 0 {
+			evidence = append(evidence, v.note)
+		}
+	}
+	s.noteStatusSignal(ReasonFusion, min(0.5+0.4*score, 0.9), "active", evidence...)
+	return true
+}
+
+// processCPUTime returns the CPU time pids have used, from /proc where
+// there is one and ps elsewhere
+func processCPUTime(pids []int) (time.Duration, error) {
+	if len(pids) == 0 {
+		return 0, fmt.Errorf("no processes")
+	}
+	if _, err := os.Stat("/proc/self/stat"); err == nil {
+		var total time.Duration
+		for _, pid := range pids {
+			data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+			if err != nil {
+				continue // exited since the tree was listed
+			}
+			total += procStatCPU(string(data))
+		}
+		return total, nil
+	}
+
+	list := make([]string, len(pids))
+	for i, pid := range pids {
+		list[i] = strconv.Itoa(pid)
+	}
+	out, err := exec.Command("ps", "-o", "time=", "-p", strings.Join(list, ",")).Output()
+	if err != nil && len(out) == 0 {
+		return 0, err
+	}
+	var total time.Duration
+	for _, line := range strings.Split(string(out), "\n") {
+		if d, ok := parsePSTime(strings.TrimSpace(line)); ok {
+			total += d
+		}
+	}
+	return total, nil
+}
+
+// clockTicks is USER_HZ, the unit of /proc/<pid>/stat times; Linux fixes it
+// at 100 on every architecture agent-deck runs on
+const clockTicks = 100
+
+// procStatCPU returns utime+stime from a /proc/<pid>/stat line. The command
+// name may contain spaces and parentheses, so fields are counted from its
+// closing parenthesis.
+func procStatCPU(stat string) time.Duration {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0
+	}
+	fields := strings.Fields(stat[end+1:])
+	// fields[0] is the state (field 3); utime and stime are fields 14 and 15
+	if len(fields) < 13 {
+		return 0
+	}
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	return time.Duration(utime+stime) * time.Second / clockTicks
+}
+
+// parsePSTime parses ps's cumulative CPU time: [[dd-]hh:]mm:ss on Linux,
+// mm:ss.cc on macOS
+func parsePSTime(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	var days int
+	if d, rest, found := strings.Cut(s, "-"); found {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, false
+		}
+		days, s = n, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	secs, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, false
+	}
+	total := time.Duration(days)*24*time.Hour + time.Duration(secs*float64(time.Second))
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n) * unit
+		unit *= 60
+	}
+	return total, true
+}
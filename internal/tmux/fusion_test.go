@@ -0,0 +1,112 @@
+package tmux
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFusedActiveLocked(t *testing.T) {
+	screen := "$ make test\nrunning integration suite\n"
+	tests := []struct {
+		name   string
+		fusion StatusFusion
+		second fusionInput
+		want   bool
+	}{
+		{
+			name:   "silent tool call burning cpu",
+			second: fusionInput{captured: true, content: screen, activityTS: 1, cpuPercent: 95, cpuKnown: true},
+			want:   true,
+		},
+		{
+			name:   "quiet and idle",
+			second: fusionInput{captured: true, content: screen, activityTS: 1, cpuPercent: 0, cpuKnown: true},
+		},
+		{
+			name:   "prompt outweighs cpu",
+			second: fusionInput{captured: true, content: screen, prompt: true, activityTS: 1, cpuPercent: 95, cpuKnown: true},
+		},
+		{
+			name:   "cpu disabled",
+			fusion: StatusFusion{Weights: map[string]float64{SignalCPU: 0}},
+			second: fusionInput{captured: true, content: screen, activityTS: 1, cpuPercent: 95, cpuKnown: true},
+		},
+		{
+			name:   "cpu below configured threshold",
+			fusion: StatusFusion{CPUBusyPercent: 50},
+			second: fusionInput{captured: true, content: screen, activityTS: 1, cpuPercent: 30, cpuKnown: true},
+		},
+		{
+			name:   "output and activity moving",
+			second: fusionInput{captured: true, content: screen + "ok 42\n", activityTS: 2},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession("fusion-test", "/tmp")
+			fusion := tt.fusion
+			s.SetStatusFusion(&fusion)
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.startupAt = time.Time{}
+
+			// The first poll only sets what later polls compare against
+			if s.fusedActiveLocked(fusionInput{captured: true, content: screen, activityTS: 1}) {
+				t.Fatal("first poll fused to active")
+			}
+			if got := s.fusedActiveLocked(tt.second); got != tt.want {
+				t.Errorf("fusedActiveLocked = %v, want %v", got, tt.want)
+			}
+			if tt.want && (s.signal == nil || s.signal.reason != ReasonFusion) {
+				t.Errorf("signal = %+v, want fusion", s.signal)
+			}
+		})
+	}
+}
+
+func TestFusionSkipsStartup(t *testing.T) {
+	s := NewSession("fusion-test", "/tmp")
+	s.SetStatusFusion(&StatusFusion{})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startupAt = time.Now()
+	s.fusionState.activityTS = 1
+	if s.fusedActiveLocked(fusionInput{activityTS: 1, cpuPercent: 100, cpuKnown: true}) {
+		t.Error("a starting tool's CPU use fused to active")
+	}
+}
+
+func TestParsePSTime(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00:03":   3 * time.Second,
+		"01:02:03":   time.Hour + 2*time.Minute + 3*time.Second,
+		"2-00:00:01": 48*time.Hour + time.Second,
+		"0:01.50":    1500 * time.Millisecond,
+		"12:34.00":   12*time.Minute + 34*time.Second,
+	}
+	for in, want := range tests {
+		if got, ok := parsePSTime(in); !ok || got != want {
+			t.Errorf("parsePSTime(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	for _, bad := range []string{"", "abc", "1:2:3:4"} {
+		if _, ok := parsePSTime(bad); ok {
+			t.Errorf("parsePSTime(%q) parsed", bad)
+		}
+	}
+}
+
+func TestProcStatCPU(t *testing.T) {
+	// The command name holds spaces and a parenthesis
+	stat := "4242 (tmux: client) x) S 1 4242 4242 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 1 0 100 0 0"
+	if got := procStatCPU(stat); got != 3*time.Second {
+		t.Errorf("procStatCPU = %v, want 3s", got)
+	}
+	if _, err := os.Stat("/proc/self/stat"); err == nil {
+		if d, err := processCPUTime([]int{os.Getpid()}); err != nil || d < 0 {
+			t.Errorf("processCPUTime(self) = %v, %v", d, err)
+		}
+	}
+}
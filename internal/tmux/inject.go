@@ -0,0 +1,98 @@
+package tmux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sendKeysMaxBytes is the largest single-line text sent with send-keys;
+	// anything longer or multi-line is pasted from a buffer
+	sendKeysMaxBytes = 4096
+
+	// pasteChunkBytes caps each buffer paste so slow TUIs keep up
+	pasteChunkBytes = 64 * 1024
+
+	// pasteChunkDelay separates consecutive buffer pastes
+	pasteChunkDelay = 50 * time.Millisecond
+)
+
+// sanitizePayload prepares text for injection: CRLF and lone CR become LF,
+// and control characters other than tab and newline are dropped. An ESC in
+// the payload could otherwise end bracketed paste early ("\x1b[201~") and
+// have the rest of the text interpreted as keystrokes.
+func sanitizePayload(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		if (r >= 32 && r != 127 && (r < 0x80 || r > 0x9f)) || r == '\t' || r == '\n' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// escapeTmuxArg keeps tmux from treating a trailing semicolon in an argument
+// as a command separator, which would silently drop it from send-keys text
+func escapeTmuxArg(arg string) string {
+	if strings.HasSuffix(arg, ";") {
+		return arg[:len(arg)-1] + `\;`
+	}
+	return arg
+}
+
+// pasteText delivers text through tmux buffers: each chunk is loaded with
+// load-buffer from stdin (no argument parsing at all), its size checked, then
+// pasted with paste-buffer -p, which wraps it in bracketed paste when the
+// application asked for it. Chunks split at newlines where possible.
+func (s *Session) pasteText(text string) error {
+	s.invalidateCache()
+	chunks := splitIntoChunks(text, pasteChunkBytes)
+	for i, chunk := range chunks {
+		buffer := fmt.Sprintf("agentdeck-inject-%s-%d", s.Name, i)
+		load := Command("load-buffer", "-b", buffer, "-")
+		load.Stdin = strings.NewReader(chunk)
+		if out, err := load.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to load chunk %d/%d into tmux buffer: %w (output: %s)", i+1, len(chunks), err, strings.TrimSpace(string(out)))
+		}
+		if err := verifyBufferSize(buffer, len(chunk)); err != nil {
+			_ = Command("delete-buffer", "-b", buffer).Run()
+			return fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		if err := Command("paste-buffer", "-d", "-p", "-b", buffer, "-t", s.Name).Run(); err != nil {
+			_ = Command("delete-buffer", "-b", buffer).Run()
+			return fmt.Errorf("failed to paste chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		if i < len(chunks)-1 {
+			time.Sleep(pasteChunkDelay)
+		}
+	}
+	return nil
+}
+
+// verifyBufferSize checks that a tmux buffer holds exactly want bytes
+func verifyBufferSize(buffer string, want int) error {
+	out, err := Command("list-buffers", "-F", "#{buffer_name}\t#{buffer_size}").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list tmux buffers: %w", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		name, size, ok := strings.Cut(line, "\t")
+		if !ok || name != buffer {
+			continue
+		}
+		got, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil {
+			return fmt.Errorf("unexpected buffer size %q", size)
+		}
+		if got != want {
+			return fmt.Errorf("tmux buffer holds %d bytes, expected %d; not delivered", got, want)
+		}
+		return nil
+	}
+	return fmt.Errorf("tmux buffer %s missing after load", buffer)
+}
@@ -0,0 +1,117 @@
+package tmux
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSanitizePayload(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "fix the bug; then run tests {quickly}", "fix the bug; then run tests {quickly}"},
+		{"crlf", "a\r\nb\rc", "a\nb\nc"},
+		{"tabs and newlines kept", "func() {\n\treturn\n}", "func() {\n\treturn\n}"},
+		{"bracketed paste end", "before\x1b[201~after", "before[201~after"},
+		{"c0 and del", "a\x00b\x07c\x7fd", "abcd"},
+		{"c1", "a\u009bb", "ab"},
+		{"unicode", "héllo → 世界", "héllo → 世界"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizePayload(tt.in); got != tt.want {
+				t.Errorf("sanitizePayload(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeTmuxArg(t *testing.T) {
+	tests := map[string]string{
+		"echo hi":      "echo hi",
+		"a; b":         "a; b",
+		"return x;":    `return x\;`,
+		";":            `\;`,
+		"{ a; b; }":    "{ a; b; }",
+		"":             "",
+		"trailing ;\n": "trailing ;\n",
+	}
+	for in, want := range tests {
+		if got := escapeTmuxArg(in); got != want {
+			t.Errorf("escapeTmuxArg(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSendKeysChunked_DeliversCodeVerbatim(t *testing.T) {
+	skipIfNoTmuxServer(t)
+
+	out := filepath.Join(t.TempDir(), "received.txt")
+	sess := NewSession("inject-test", t.TempDir())
+	// Raw mode, like agent TUIs: no line-buffer limit, no CR translation
+	if err := sess.Start("stty raw -echo; cat > " + out); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = sess.Kill() }()
+	time.Sleep(200 * time.Millisecond)
+
+	var b strings.Builder
+	for i := 0; i < 3000; i++ {
+		b.WriteString("if (x) { y(); };\n")
+	}
+	b.WriteString("last line;")
+	payload := b.String()
+
+	if err := sess.SendKeysChunked(payload); err != nil {
+		t.Fatalf("SendKeysChunked: %v", err)
+	}
+	if err := sess.SendEnter(); err != nil {
+		t.Fatalf("SendEnter: %v", err)
+	}
+
+	// paste-buffer sends newlines as CR, the same as the Enter key; the
+	// tty may or may not translate them back
+	want := payload + "\n"
+	var got []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		got, _ = os.ReadFile(out)
+		got = bytes.ReplaceAll(got, []byte("\r"), []byte("\n"))
+		if len(got) >= len(want) {
+			break
+		}
+	}
+	if string(got) != want {
+		t.Fatalf("received %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestSendKeys_TrailingSemicolon(t *testing.T) {
+	skipIfNoTmuxServer(t)
+
+	out := filepath.Join(t.TempDir(), "received.txt")
+	sess := NewSession("inject-semicolon-test", t.TempDir())
+	if err := sess.Start("cat > " + out); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = sess.Kill() }()
+	time.Sleep(200 * time.Millisecond)
+
+	if err := sess.SendKeysAndEnter("return x;"); err != nil {
+		t.Fatalf("SendKeysAndEnter: %v", err)
+	}
+	var got []byte
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if got, _ = os.ReadFile(out); len(got) > 0 {
+			break
+		}
+	}
+	if string(got) != "return x;\n" {
+		t.Fatalf("received %q", got)
+	}
+}
@@ -0,0 +1,193 @@
+package tmux
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatternRule is a busy/prompt pattern written in the option syntax:
+//
+//	{opt; opt=value; ...}pattern
+//
+// where pattern is a plain string (case-insensitive substring) or "re:" regex,
+// and the options are:
+//
+//	start        match only at the start of a line (after indentation)
+//	last=N       only look at the last N lines of the status window
+//	unless=X     ignore this match if X (plain or "re:") is also present; repeatable
+//	not          negative rule: a match vetoes the list instead of satisfying it
+//	prio=N       priority (default 0); the highest-priority matching rule decides
+//
+// Plain patterns without options behave as priority-0 positive rules, so a
+// "{not}X" rule vetoes them whenever X is visible, and "{not; prio=-1}X" only
+// vetoes other rules with lower priority.
+type PatternRule struct {
+	Source    string
+	LineStart bool
+	LastLines int
+	Negative  bool
+	Priority  int
+
+	literal string // lowercased; empty when re is set
+	re      *regexp.Regexp
+	unless  []*PatternRule
+}
+
+// isPatternRule reports whether a raw pattern uses the option syntax.
+func isPatternRule(p string) bool {
+	return strings.HasPrefix(p, "{") && strings.Contains(p, "}")
+}
+
+// ParsePatternRule parses a pattern in the option syntax. Patterns without an
+// option block are accepted too and behave like plain/regex patterns.
+func ParsePatternRule(p string) (*PatternRule, error) {
+	rule := &PatternRule{Source: p}
+	body := p
+	if isPatternRule(p) {
+		end := strings.Index(p, "}")
+		body = p[end+1:]
+		for _, opt := range strings.Split(p[1:end], ";") {
+			opt = strings.TrimSpace(opt)
+			if opt == "" {
+				continue
+			}
+			key, value, hasValue := strings.Cut(opt, "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			value = strings.TrimSpace(value)
+			switch key {
+			case "start":
+				rule.LineStart = true
+			case "not":
+				rule.Negative = true
+			case "last":
+				n, err := strconv.Atoi(value)
+				if !hasValue || err != nil || n <= 0 {
+					return nil, fmt.Errorf("last must be a positive integer in %q", p)
+				}
+				rule.LastLines = n
+			case "prio", "priority":
+				n, err := strconv.Atoi(value)
+				if !hasValue || err != nil {
+					return nil, fmt.Errorf("prio must be an integer in %q", p)
+				}
+				rule.Priority = n
+			case "unless":
+				if value == "" {
+					return nil, fmt.Errorf("unless needs a pattern in %q", p)
+				}
+				sub, err := ParsePatternRule(value)
+				if err != nil {
+					return nil, err
+				}
+				rule.unless = append(rule.unless, sub)
+			default:
+				return nil, fmt.Errorf("unknown pattern option %q in %q", key, p)
+			}
+		}
+	}
+
+	if body == "" {
+		return nil, fmt.Errorf("empty pattern in %q", p)
+	}
+	if strings.HasPrefix(body, "re:") {
+		expr := body[3:]
+		if rule.LineStart {
+			expr = `(?m)^\s*(?:` + expr + `)`
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex in %q: %w", p, err)
+		}
+		rule.re = re
+	} else {
+		rule.literal = strings.ToLower(body)
+	}
+	return rule, nil
+}
+
+// Matches reports whether the rule matches the given window of lines.
+// Lines should already be ANSI-stripped as configured by CaptureOptions.
+func (r *PatternRule) Matches(lines []string) bool {
+	if r.LastLines > 0 && len(lines) > r.LastLines {
+		lines = lines[len(lines)-r.LastLines:]
+	}
+	if !r.matchesLines(lines) {
+		return false
+	}
+	for _, u := range r.unless {
+		if u.matchesLines(lines) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *PatternRule) matchesLines(lines []string) bool {
+	if r.re != nil {
+		return r.re.MatchString(strings.Join(lines, "\n"))
+	}
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		if r.LineStart {
+			if strings.HasPrefix(strings.TrimLeft(lower, " \t"), r.literal) {
+				return true
+			}
+		} else if strings.Contains(lower, r.literal) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchedLine returns the line a positive rule matched, for status evidence
+func (r *PatternRule) matchedLine(lines []string) string {
+	if r.LastLines > 0 && len(lines) > r.LastLines {
+		lines = lines[len(lines)-r.LastLines:]
+	}
+	if r.re != nil {
+		content := strings.Join(lines, "\n")
+		if loc := r.re.FindStringIndex(content); loc != nil {
+			return lineAt(content, loc[0])
+		}
+		return ""
+	}
+	return lineContaining(lines, r.literal)
+}
+
+// sortRules orders rules by descending priority, negatives first on ties, so
+// the first match in a scan is the deciding one.
+func sortRules(rules []*PatternRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return rules[i].Negative && !rules[j].Negative
+	})
+}
+
+// decideRules evaluates rules (sorted by sortRules) against lines.
+// It returns the deciding rule, or nil when none matched.
+func decideRules(rules []*PatternRule, lines []string) *PatternRule {
+	for _, r := range rules {
+		if r.Matches(lines) {
+			return r
+		}
+	}
+	return nil
+}
+
+// ruleVerdict turns the deciding rule into a decision for the rule list:
+// matched=true means the list matched; vetoed=true means a negative rule at
+// priority >= 0 suppresses plain patterns and other signals too.
+func ruleVerdict(r *PatternRule) (matched, vetoed bool) {
+	if r == nil {
+		return false, false
+	}
+	if r.Negative {
+		return false, r.Priority >= 0
+	}
+	return true, false
+}
@@ -0,0 +1,105 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePatternRule(t *testing.T) {
+	rule, err := ParsePatternRule("{start; last=3; unless=re:^done; not; prio=5}Working")
+	if err != nil {
+		t.Fatalf("ParsePatternRule: %v", err)
+	}
+	if !rule.LineStart || rule.LastLines != 3 || !rule.Negative || rule.Priority != 5 || len(rule.unless) != 1 {
+		t.Errorf("options not parsed: %+v", rule)
+	}
+
+	for _, bad := range []string{"{last=0}x", "{prio=high}x", "{bogus}x", "{start}", "{unless=}x", "{start}re:("} {
+		if _, err := ParsePatternRule(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestPatternRuleMatching(t *testing.T) {
+	banner := []string{
+		"╭──────────────────────────╮",
+		"│ Tips · esc to interrupt  │",
+		"╰──────────────────────────╯",
+		"> ",
+	}
+	working := []string{"  esc to interrupt", "> "}
+
+	tests := []struct {
+		pattern string
+		lines   []string
+		want    bool
+	}{
+		{"esc to interrupt", banner, true},
+		{"{start}esc to interrupt", banner, false},
+		{"{start}esc to interrupt", working, true},
+		{"{start}re:esc to \\w+", working, true},
+		{"{last=1}esc to interrupt", working, false},
+		{"{last=2}ESC TO INTERRUPT", working, true},
+		{"{unless=Tips}esc to interrupt", banner, false},
+		{"{unless=Tips}esc to interrupt", working, true},
+	}
+	for _, tt := range tests {
+		rule, err := ParsePatternRule(tt.pattern)
+		if err != nil {
+			t.Fatalf("ParsePatternRule(%q): %v", tt.pattern, err)
+		}
+		if got := rule.Matches(tt.lines); got != tt.want {
+			t.Errorf("%q on %q = %v, want %v", tt.pattern, strings.Join(tt.lines, "|"), got, tt.want)
+		}
+	}
+}
+
+func TestPatternRulePriorities(t *testing.T) {
+	raw := &RawPatterns{BusyPatterns: []string{
+		"{prio=1}re:Thinking",
+		"{not}Press enter to continue",
+		"{not; prio=-1}Compacting",
+	}}
+	resolved, err := CompilePatterns(raw)
+	if err != nil {
+		t.Fatalf("CompilePatterns: %v", err)
+	}
+	if len(resolved.BusyRules) != 3 || resolved.BusyRules[0].Priority != 1 {
+		t.Fatalf("rules should be sorted by priority: %+v", resolved.BusyRules)
+	}
+
+	decide := func(lines ...string) (bool, bool) {
+		return ruleVerdict(decideRules(resolved.BusyRules, lines))
+	}
+	if matched, _ := decide("Thinking", "Press enter to continue"); !matched {
+		t.Error("higher-priority positive rule should win over the negative rule")
+	}
+	if matched, vetoed := decide("Press enter to continue"); matched || !vetoed {
+		t.Error("priority-0 negative rule should veto")
+	}
+	if matched, vetoed := decide("Compacting"); matched || vetoed {
+		t.Error("negative rule below priority 0 should not veto plain patterns")
+	}
+	if matched, vetoed := decide("nothing here"); matched || vetoed {
+		t.Error("no rule should decide when nothing matches")
+	}
+}
+
+func TestBusyRuleVetoesPlainPatterns(t *testing.T) {
+	raw := &RawPatterns{BusyPatterns: []string{"esc to cancel", "{not}Type your message"}}
+	resolved, err := CompilePatterns(raw)
+	if err != nil {
+		t.Fatalf("CompilePatterns: %v", err)
+	}
+	sess := NewSession("rule-veto", "/tmp")
+	sess.SetPatterns(resolved)
+	if sess.hasBusyIndicator("esc to cancel\nType your message") {
+		t.Error("negative rule should veto the plain busy pattern")
+	}
+	sess = NewSession("rule-veto-busy", "/tmp")
+	sess.SetPatterns(resolved)
+	if !sess.hasBusyIndicator("esc to cancel") {
+		t.Error("plain busy pattern should match without the vetoing text")
+	}
+}
@@ -13,11 +13,13 @@ var patternLog = logging.ForComponent(logging.CompStatus)
 
 // RawPatterns holds string-form patterns before compilation.
 // Patterns prefixed with "re:" are compiled as regex; everything else uses strings.Contains.
+// Patterns starting with an option block ("{start; last=3}...") become PatternRules.
 type RawPatterns struct {
 	BusyPatterns   []string // plain strings + "re:" prefixed regex
 	PromptPatterns []string
 	SpinnerChars   []string
 	WhimsicalWords []string
+	Capture        CaptureOptions // what pane content the patterns are matched against
 }
 
 // ResolvedPatterns holds the compiled, ready-to-use patterns for status detection.
@@ -27,11 +29,28 @@ type ResolvedPatterns struct {
 	PromptStrings []string
 	PromptRegexps []*regexp.Regexp
 	SpinnerChars  []string
+	Capture       CaptureOptions
+
+	// Patterns written with options, sorted by priority (see PatternRule)
+	BusyRules   []*PatternRule
+	PromptRules []*PatternRule
 
 	// Pre-built combo patterns (from WhimsicalWords + SpinnerChars)
 	ThinkingPattern         *regexp.Regexp
 	ThinkingPatternEllipsis *regexp.Regexp
 	SpinnerActivePattern    *regexp.Regexp
+
+	// sanitizer applies the Capture sanitization options (nil = none)
+	sanitizer *sanitizer
+}
+
+// Sanitize returns pane content as status detection classifies it, after the
+// Capture sanitization options (banner, spinner frames, progress lines)
+func (p *ResolvedPatterns) Sanitize(content string) string {
+	if p == nil {
+		return content
+	}
+	return p.sanitizer.apply(content)
 }
 
 // DefaultRawPatterns returns the built-in detection patterns for a known tool.
@@ -49,9 +68,24 @@ func DefaultRawPatterns(toolName string) *RawPatterns {
 			WhimsicalWords: defaultWhimsicalWords(),
 		}
 	case "gemini":
+		// Gemini CLI: "⠏ Reading files (esc to cancel, 4s)" while busy, its
+		// braille spinner in front of a phrase and an elapsed-time counter.
+		// Tool confirmations ("Allow execution of: 'npm'?", "Apply this
+		// change?") wait on the user like the input box does, with its
+		// "│ > " line empty.
 		return &RawPatterns{
-			BusyPatterns:   []string{"esc to cancel"},
-			PromptPatterns: []string{"gemini>", "Type your message"},
+			BusyPatterns: []string{
+				`re:[⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏] .+\(esc to cancel, \d+[hms]`,
+				"esc to cancel",
+			},
+			PromptPatterns: []string{
+				"gemini>", "Type your message",
+				`re:(?m)^│ >\s*│?\s*$`,
+				"Yes, allow once",
+				"Allow execution",
+				"Apply this change?",
+				"Waiting for user confirmation",
+			},
 		}
 	case "opencode":
 		return &RawPatterns{
@@ -59,13 +93,44 @@ func DefaultRawPatterns(toolName string) *RawPatterns {
 			PromptPatterns: []string{"Ask anything"},
 		}
 	case "codex":
+		// Codex TUI: "• Working (12s • esc to interrupt)" while busy. Approval
+		// requests and turns that ended on an error (usage limit, stream
+		// failure) also wait on the user, so they count as prompts.
 		return &RawPatterns{
 			BusyPatterns: []string{
+				`re:Working \(\d+[hms]`,
 				"ctrl+c to interrupt",
 				"esc to interrupt",
 				"press esc to interrupt",
 			},
-			PromptPatterns: []string{"How can I help", "codex>", "Continue?"},
+			PromptPatterns: []string{
+				"How can I help", "codex>", "Continue?",
+				"Ask Codex to do anything",
+				"Would you like to run the following command?",
+				"Would you like to make the following edits?",
+				"You've hit your usage limit",
+				"stream disconnected before completion",
+			},
+		}
+	case "ollama":
+		// ollama run: a braille spinner while the model loads or before the
+		// first token, then streamed text, then a fresh ">>> " prompt line
+		return &RawPatterns{
+			PromptPatterns: []string{"Send a message (/? for help)", `re:(?m)^>>> [^\n]*\s*\z`},
+			SpinnerChars:   []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		}
+	case "aider":
+		// aider: "Waiting for <model>" with a spinner until the reply
+		// streams, then the input prompt ("> ", or "ask> ", "architect> "
+		// and "multi> " by chat mode). Confirmations ("Add file to the
+		// chat? (Y)es/(N)o [Yes]:") wait on the user too.
+		return &RawPatterns{
+			BusyPatterns: []string{"Waiting for "},
+			PromptPatterns: []string{
+				"(Y)es/(N)o",
+				`re:(?m)^[a-z-]*> ?\s*\z`,
+			},
+			SpinnerChars: []string{"░", "█"},
 		}
 	case "shell":
 		return &RawPatterns{
@@ -131,9 +196,18 @@ func CompilePatterns(raw *RawPatterns) (*ResolvedPatterns, error) {
 
 	resolved := &ResolvedPatterns{}
 
-	// Split busy patterns into strings vs regex
+	// Split busy patterns into rules vs strings vs regex
 	for _, p := range raw.BusyPatterns {
-		if strings.HasPrefix(p, "re:") {
+		if isPatternRule(p) {
+			rule, err := ParsePatternRule(p)
+			if err != nil {
+				patternLog.Warn("invalid_busy_rule",
+					slog.String("pattern", p),
+					slog.String("error", err.Error()))
+				continue
+			}
+			resolved.BusyRules = append(resolved.BusyRules, rule)
+		} else if strings.HasPrefix(p, "re:") {
 			re, err := regexp.Compile(p[3:])
 			if err != nil {
 				patternLog.Warn("invalid_busy_regex",
@@ -147,9 +221,18 @@ func CompilePatterns(raw *RawPatterns) (*ResolvedPatterns, error) {
 		}
 	}
 
-	// Split prompt patterns into strings vs regex
+	// Split prompt patterns into rules vs strings vs regex
 	for _, p := range raw.PromptPatterns {
-		if strings.HasPrefix(p, "re:") {
+		if isPatternRule(p) {
+			rule, err := ParsePatternRule(p)
+			if err != nil {
+				patternLog.Warn("invalid_prompt_rule",
+					slog.String("pattern", p),
+					slog.String("error", err.Error()))
+				continue
+			}
+			resolved.PromptRules = append(resolved.PromptRules, rule)
+		} else if strings.HasPrefix(p, "re:") {
 			re, err := regexp.Compile(p[3:])
 			if err != nil {
 				patternLog.Warn("invalid_prompt_regex",
@@ -163,10 +246,25 @@ func CompilePatterns(raw *RawPatterns) (*ResolvedPatterns, error) {
 		}
 	}
 
+	resolved.Capture = raw.Capture
+	sortRules(resolved.BusyRules)
+	sortRules(resolved.PromptRules)
+
 	// Copy spinner chars
 	resolved.SpinnerChars = make([]string, len(raw.SpinnerChars))
 	copy(resolved.SpinnerChars, raw.SpinnerChars)
 
+	sanitizer, err := newSanitizer(raw.Capture, raw.SpinnerChars)
+	if err != nil {
+		patternLog.Warn("invalid_banner_end",
+			slog.String("pattern", raw.Capture.BannerEnd),
+			slog.String("error", err.Error()))
+		capture := raw.Capture
+		capture.BannerEnd = ""
+		sanitizer, _ = newSanitizer(capture, raw.SpinnerChars)
+	}
+	resolved.sanitizer = sanitizer
+
 	// Build combo regex patterns from WhimsicalWords + SpinnerChars
 	if len(raw.WhimsicalWords) > 0 && len(raw.SpinnerChars) > 0 {
 		spinnerCharClass := buildSpinnerCharClass(raw.SpinnerChars)
@@ -214,6 +312,7 @@ func buildSpinnerCharClass(chars []string) string {
 
 // MergeRawPatterns merges defaults with overrides and extras.
 //   - If overrides has a field set (non-nil slice, even if empty), it replaces the default.
+//   - Non-zero Capture options in overrides replace the matching default option.
 //   - extras fields are appended to the result (after defaults or overrides).
 //   - If defaults is nil, only overrides/extras are used.
 func MergeRawPatterns(defaults, overrides, extras *RawPatterns) *RawPatterns {
@@ -225,6 +324,7 @@ func MergeRawPatterns(defaults, overrides, extras *RawPatterns) *RawPatterns {
 		result.PromptPatterns = copySlice(defaults.PromptPatterns)
 		result.SpinnerChars = copySlice(defaults.SpinnerChars)
 		result.WhimsicalWords = copySlice(defaults.WhimsicalWords)
+		result.Capture = defaults.Capture
 	}
 
 	// Apply overrides (replace entire field if set)
@@ -241,6 +341,25 @@ func MergeRawPatterns(defaults, overrides, extras *RawPatterns) *RawPatterns {
 		if overrides.WhimsicalWords != nil {
 			result.WhimsicalWords = copySlice(overrides.WhimsicalWords)
 		}
+		// Capture options override field by field (zero values keep the default)
+		if overrides.Capture.Lines > 0 {
+			result.Capture.Lines = overrides.Capture.Lines
+		}
+		if overrides.Capture.AltScreen {
+			result.Capture.AltScreen = true
+		}
+		if overrides.Capture.MatchANSI {
+			result.Capture.MatchANSI = true
+		}
+		if overrides.Capture.StripSpinnerFrames {
+			result.Capture.StripSpinnerFrames = true
+		}
+		if overrides.Capture.CollapseProgress {
+			result.Capture.CollapseProgress = true
+		}
+		if overrides.Capture.BannerEnd != "" {
+			result.Capture.BannerEnd = overrides.Capture.BannerEnd
+		}
 	}
 
 	// Append extras
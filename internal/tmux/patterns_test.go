@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -57,6 +58,39 @@ func TestDefaultRawPatterns_Gemini(t *testing.T) {
 	if len(raw.PromptPatterns) == 0 {
 		t.Error("gemini should have prompt patterns")
 	}
+
+	// The input box stays visible while a response streams
+	d := NewPromptDetector("gemini")
+	busy := "⠏ Reading files (esc to cancel, 4s)\n╭──────╮\n│ >   Type your message or @path/to/file │\n╰──────╯"
+	if d.HasPrompt(busy) {
+		t.Error("detector should not see a prompt while gemini is busy")
+	}
+	confirm := "? Shell npm test\nAllow execution of: 'npm'?\n● 1. Yes, allow once\n  2. Yes, allow always\n  3. No"
+	if !d.HasPrompt(confirm) {
+		t.Error("detector should see the tool confirmation as a prompt")
+	}
+
+	resolved, err := CompilePatterns(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matchesAny := func(res []*regexp.Regexp, s string) bool {
+		for _, re := range res {
+			if re.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+	if !matchesAny(resolved.BusyRegexps, "⠼ Counting the tests (esc to cancel, 1m 3s)") {
+		t.Error("spinner line should match a busy regex")
+	}
+	if matchesAny(resolved.BusyRegexps, "I ran the tests; press esc to cancel the next step") {
+		t.Error("a reply mentioning esc should not match the spinner regex")
+	}
+	if !matchesAny(resolved.PromptRegexps, "╭────╮\n│ >  │\n╰────╯") {
+		t.Error("an empty input box should match a prompt regex")
+	}
 }
 
 func TestDefaultRawPatterns_Codex(t *testing.T) {
@@ -72,6 +106,71 @@ func TestDefaultRawPatterns_Codex(t *testing.T) {
 	}
 }
 
+func TestDefaultRawPatterns_Ollama(t *testing.T) {
+	resolved, err := CompilePatterns(DefaultRawPatterns("ollama"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved.SpinnerChars) == 0 {
+		t.Error("ollama should have spinner chars")
+	}
+
+	answered := ">>> what is 2+2?\n2 + 2 = 4.\n\n>>> Send a message (/? for help)\n\n"
+	streaming := ">>> what is 2+2?\n2 + 2"
+	matches := func(content string) bool {
+		for _, re := range resolved.PromptRegexps {
+			if re.MatchString(content) {
+				return true
+			}
+		}
+		return false
+	}
+	if !matches(answered) {
+		t.Error("prompt regexp should match the fresh >>> line")
+	}
+	if matches(streaming) {
+		t.Error("prompt regexp should not match an earlier >>> line while a reply streams")
+	}
+
+	d := NewPromptDetector("ollama")
+	if !d.HasPrompt(answered) || !d.HasPrompt(">>> \"\"\"\n... ") {
+		t.Error("detector should see the >>> and ... prompts")
+	}
+	if d.HasPrompt(streaming) || d.HasPrompt("⠙ ") {
+		t.Error("detector should not see a prompt while loading or streaming")
+	}
+}
+
+func TestDefaultRawPatterns_Aider(t *testing.T) {
+	resolved, err := CompilePatterns(DefaultRawPatterns("aider"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved.BusyStrings) == 0 || len(resolved.SpinnerChars) == 0 {
+		t.Error("aider should have busy patterns and spinner chars")
+	}
+
+	d := NewPromptDetector("aider")
+	for _, content := range []string{
+		"Applied edit to main.go\nCommit 1a2b3c4 feat: Add --verbose flag\n> ",
+		"ask> ",
+		"Add main.go to the chat? (Y)es/(N)o [Yes]: ",
+		"Run shell command? (Y)es/(N)o/(D)on't ask again [Yes]: ",
+	} {
+		if !d.HasPrompt(content) {
+			t.Errorf("detector should see a prompt in %q", content)
+		}
+	}
+	for _, content := range []string{
+		"░█        Waiting for sonnet",
+		"> Tokens: 2.1k sent, 120 received.",
+	} {
+		if d.HasPrompt(content) {
+			t.Errorf("detector should not see a prompt in %q", content)
+		}
+	}
+}
+
 func TestDefaultRawPatterns_Unknown(t *testing.T) {
 	raw := DefaultRawPatterns("unknowntool")
 	if raw != nil {
@@ -388,3 +487,77 @@ func TestSpinnerRuneSet(t *testing.T) {
 		t.Error("missing ✻ from normalization set")
 	}
 }
+
+func TestMergeRawPatterns_CaptureOptions(t *testing.T) {
+	defaults := &RawPatterns{Capture: CaptureOptions{Lines: 30}}
+	overrides := &RawPatterns{Capture: CaptureOptions{MatchANSI: true}}
+
+	result := MergeRawPatterns(defaults, overrides, nil)
+	if result.Capture.Lines != 30 {
+		t.Errorf("unset Lines override should keep default, got %d", result.Capture.Lines)
+	}
+	if !result.Capture.MatchANSI || result.Capture.AltScreen {
+		t.Errorf("unexpected capture options: %+v", result.Capture)
+	}
+
+	resolved, err := CompilePatterns(result)
+	if err != nil {
+		t.Fatalf("CompilePatterns: %v", err)
+	}
+	if resolved.Capture != result.Capture {
+		t.Errorf("capture options not carried through compile: %+v", resolved.Capture)
+	}
+}
+
+func TestCaptureOptions(t *testing.T) {
+	var zero CaptureOptions
+	if !zero.IsZero() || zero.lines() != defaultStatusLines {
+		t.Errorf("zero options should use defaults")
+	}
+	colored := "\x1b[31mError\x1b[0m"
+	if got := zero.matchText(colored); got != "Error" {
+		t.Errorf("default matching should strip ANSI, got %q", got)
+	}
+	ansi := CaptureOptions{MatchANSI: true}
+	if got := ansi.matchText(colored); got != colored {
+		t.Errorf("MatchANSI should keep escapes, got %q", got)
+	}
+	if got := ansi.plainText(colored); got != "Error" {
+		t.Errorf("plainText should strip escapes, got %q", got)
+	}
+
+	args := strings.Join(CaptureOptions{MatchANSI: true}.captureArgs("s", true), " ")
+	if !strings.Contains(args, "-e") || !strings.Contains(args, "-a -q") {
+		t.Errorf("unexpected capture args: %s", args)
+	}
+	if args := strings.Join(zero.captureArgs("s", false), " "); strings.Contains(args, "-e") || strings.Contains(args, "-a") {
+		t.Errorf("default capture args should not add -e/-a: %s", args)
+	}
+}
+
+func TestStatusLinesWidensPatternWindow(t *testing.T) {
+	lines := []string{"Working on it (press esc to cancel)"}
+	for i := 0; i < 30; i++ {
+		lines = append(lines, "output line")
+	}
+	content := strings.Join(lines, "\n")
+
+	raw := &RawPatterns{BusyPatterns: []string{"re:esc to cancel"}}
+	resolved, err := CompilePatterns(raw)
+	if err != nil {
+		t.Fatalf("CompilePatterns: %v", err)
+	}
+	sess := NewSession("capture-lines", "/tmp")
+	sess.SetPatterns(resolved)
+	if sess.hasBusyIndicator(content) {
+		t.Fatal("busy line outside the default 25-line window should not match")
+	}
+
+	raw.Capture.Lines = 40
+	resolved, _ = CompilePatterns(raw)
+	sess = NewSession("capture-lines-wide", "/tmp")
+	sess.SetPatterns(resolved)
+	if !sess.hasBusyIndicator(content) {
+		t.Error("busy line inside a 40-line window should match")
+	}
+}
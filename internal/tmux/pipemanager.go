@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
-	"os/exec"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +19,8 @@ type PipeManager struct {
 
 	// Callback for output events (invoked when %output detected from a session)
 	onOutput func(sessionName string)
+	// Callback for terminal bells, set with OnBell
+	onBell func(sessionName string)
 
 	// Reconnection tracking
 	reconnectMu  sync.Mutex
@@ -261,6 +262,27 @@ func (pm *PipeManager) LastOutputTime(sessionName string) time.Time {
 	return pipe.LastOutputTime()
 }
 
+// OnBell sets the callback invoked when a connected session rings the
+// terminal bell: agents do so when they finish or want input, so it is a
+// prompt for an immediate status check rather than waiting for the next poll.
+func (pm *PipeManager) OnBell(fn func(sessionName string)) {
+	pm.mu.Lock()
+	pm.onBell = fn
+	pm.mu.Unlock()
+}
+
+// LastBellTime returns when a session last rang the terminal bell, or the
+// zero time if it has not or is not connected.
+func (pm *PipeManager) LastBellTime(sessionName string) time.Time {
+	pm.mu.RLock()
+	pipe := pm.pipes[sessionName]
+	pm.mu.RUnlock()
+	if pipe == nil {
+		return time.Time{}
+	}
+	return pipe.LastBellTime()
+}
+
 // IsConnected returns true if a session has an alive pipe.
 func (pm *PipeManager) IsConnected(sessionName string) bool {
 	pm.mu.RLock()
@@ -312,6 +334,13 @@ func (pm *PipeManager) forwardOutputEvents(sessionName string, pipe *ControlPipe
 			if pm.onOutput != nil {
 				pm.onOutput(sessionName)
 			}
+		case <-pipe.BellEvents():
+			pm.mu.RLock()
+			onBell := pm.onBell
+			pm.mu.RUnlock()
+			if onBell != nil {
+				onBell(sessionName)
+			}
 		case <-pipe.Done():
 			return
 		}
@@ -393,7 +422,7 @@ func (pm *PipeManager) watchPipe(sessionName string, pipe *ControlPipe) {
 
 // tmuxSessionExists checks if a tmux session exists (lightweight subprocess).
 func tmuxSessionExists(name string) bool {
-	cmd := exec.Command("tmux", "has-session", "-t", name)
+	cmd := Command("has-session", "-t", name)
 	return cmd.Run() == nil
 }
 
@@ -0,0 +1,129 @@
+package tmux
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// readyProbeCacheTTL is how long a probe result is reused: status polling
+// runs every tick and each probe spawns ps or dials a socket
+const readyProbeCacheTTL = 2 * time.Second
+
+// readyProbeDialTimeout bounds a port probe; a local listener answers at once
+const readyProbeDialTimeout = 300 * time.Millisecond
+
+// ReadyProbe adds checks beyond pane patterns to a tool's readiness, for
+// tools whose TUI is hard to match: a prompt only counts as ready when the
+// tool's process is alive inside the pane and its local port (if any)
+// accepts connections.
+type ReadyProbe struct {
+	// Process is the name of a process that must be running in the pane's
+	// process tree (e.g. "node", "aider")
+	Process string
+	// Port is a local TCP port the tool listens on once it is up
+	Port int
+}
+
+// IsZero reports whether the probe checks nothing
+func (p *ReadyProbe) IsZero() bool {
+	return p == nil || (p.Process == "" && p.Port == 0)
+}
+
+// readyProbeResult caches the last probe outcome
+type readyProbeResult struct {
+	err error
+	at  time.Time
+}
+
+// SetReadyProbe sets the process and port checks combined with pattern
+// detection; nil or an empty probe disables them.
+func (s *Session) SetReadyProbe(p *ReadyProbe) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.IsZero() {
+		p = nil
+	}
+	s.readyProbe = p
+	s.readyProbeResult = readyProbeResult{}
+}
+
+// ProbeReady runs the session's ready probe. It returns nil when no probe is
+// set or every check passes, else an error naming the failed check.
+// Must not be called with s.mu held.
+func (s *Session) ProbeReady() error {
+	s.mu.Lock()
+	probe := s.readyProbe
+	cached := s.readyProbeResult
+	s.mu.Unlock()
+	if probe == nil {
+		return nil
+	}
+	if !cached.at.IsZero() && time.Since(cached.at) < readyProbeCacheTTL {
+		return cached.err
+	}
+
+	err := s.runReadyProbe(probe)
+	s.mu.Lock()
+	if s.readyProbe == probe {
+		s.readyProbeResult = readyProbeResult{err: err, at: time.Now()}
+	}
+	s.mu.Unlock()
+	return err
+}
+
+func (s *Session) runReadyProbe(probe *ReadyProbe) error {
+	if probe.Process != "" {
+		_, pids := s.getPaneProcessTree()
+		if len(pids) == 0 {
+			return fmt.Errorf("no pane process")
+		}
+		if !processTreeHas(pids, probe.Process) {
+			return fmt.Errorf("process %q is not running in the pane", probe.Process)
+		}
+	}
+	if probe.Port > 0 {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(probe.Port)), readyProbeDialTimeout)
+		if err != nil {
+			return fmt.Errorf("port %d is not accepting connections", probe.Port)
+		}
+		_ = conn.Close()
+	}
+	return nil
+}
+
+// processTreeHas reports whether any of pids runs a command named name
+func processTreeHas(pids []int, name string) bool {
+	list := make([]string, len(pids))
+	for i, pid := range pids {
+		list[i] = strconv.Itoa(pid)
+	}
+	out, err := exec.Command("ps", "-o", "comm=", "-p", strings.Join(list, ",")).Output()
+	if err != nil && len(out) == 0 {
+		return false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if processNameMatches(strings.TrimSpace(line), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// processNameMatches compares a ps command name with a configured process
+// name. Linux truncates comm to 15 characters and macOS reports a path, so
+// both are allowed for.
+func processNameMatches(comm, name string) bool {
+	if comm == "" {
+		return false
+	}
+	comm = filepath.Base(comm)
+	if strings.EqualFold(comm, name) {
+		return true
+	}
+	return len(comm) == 15 && strings.HasPrefix(strings.ToLower(name), strings.ToLower(comm))
+}
@@ -0,0 +1,80 @@
+package tmux
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestProcessNameMatches(t *testing.T) {
+	tests := []struct {
+		comm, name string
+		want       bool
+	}{
+		{"node", "node", true},
+		{"Node", "node", true},
+		{"/usr/local/bin/aider", "aider", true},
+		{"language-server", "language-server-go", true}, // truncated to 15 by Linux
+		{"nodejs", "node", false},
+		{"", "node", false},
+	}
+	for _, tt := range tests {
+		if got := processNameMatches(tt.comm, tt.name); got != tt.want {
+			t.Errorf("processNameMatches(%q, %q) = %v, want %v", tt.comm, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProbeReady_Port(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	s := NewSession("probe-test", t.TempDir())
+	if err := s.ProbeReady(); err != nil {
+		t.Errorf("no probe = %v", err)
+	}
+	s.SetReadyProbe(&ReadyProbe{Port: port})
+	if err := s.ProbeReady(); err != nil {
+		t.Errorf("open port = %v", err)
+	}
+
+	// Results are cached briefly, then the closed port is seen
+	_ = ln.Close()
+	if err := s.ProbeReady(); err != nil {
+		t.Errorf("cached result = %v", err)
+	}
+	s.mu.Lock()
+	s.readyProbeResult.at = time.Now().Add(-readyProbeCacheTTL)
+	s.mu.Unlock()
+	if err := s.ProbeReady(); err == nil {
+		t.Error("closed port should fail the probe")
+	}
+}
+
+func TestProbeReady_Process(t *testing.T) {
+	skipIfNoTmuxServer(t)
+
+	s := NewSession("probe-process-test", t.TempDir())
+	if err := s.Start("sleep 30"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Kill() }()
+
+	// The shell may take a while to start the command
+	probe := &ReadyProbe{Process: "sleep"}
+	err := s.runReadyProbe(probe)
+	for deadline := time.Now().Add(10 * time.Second); err != nil && time.Now().Before(deadline); {
+		time.Sleep(200 * time.Millisecond)
+		err = s.runReadyProbe(probe)
+	}
+	if err != nil {
+		t.Errorf("sleep is running in the pane: %v", err)
+	}
+	s.SetReadyProbe(&ReadyProbe{Process: "no-such-tool"})
+	if err := s.ProbeReady(); err == nil {
+		t.Error("missing process should fail the probe")
+	}
+}
@@ -0,0 +1,143 @@
+package tmux
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// sanitizer is the compiled form of a tool's pane sanitization options
+// (CaptureOptions.StripSpinnerFrames, CollapseProgress and BannerEnd)
+type sanitizer struct {
+	stripSpinners bool
+	spinnerRunes  map[rune]bool
+	collapse      bool
+	bannerString  string
+	bannerRegexp  *regexp.Regexp
+}
+
+// newSanitizer compiles the sanitization options of capture, or returns nil
+// when none is set. spinnerChars are the tool's spinner characters, stripped
+// in addition to SpinnerRuneSet.
+func newSanitizer(capture CaptureOptions, spinnerChars []string) (*sanitizer, error) {
+	if !capture.StripSpinnerFrames && !capture.CollapseProgress && capture.BannerEnd == "" {
+		return nil, nil
+	}
+	s := &sanitizer{
+		stripSpinners: capture.StripSpinnerFrames,
+		collapse:      capture.CollapseProgress,
+		spinnerRunes:  make(map[rune]bool),
+	}
+	for r := range spinnerRuneMap {
+		s.spinnerRunes[r] = true
+	}
+	for _, ch := range spinnerChars {
+		for _, r := range ch {
+			s.spinnerRunes[r] = true
+		}
+	}
+	if expr, ok := strings.CutPrefix(capture.BannerEnd, "re:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		s.bannerRegexp = re
+	} else {
+		s.bannerString = strings.ToLower(capture.BannerEnd)
+	}
+	return s, nil
+}
+
+// apply returns content without the banner region, with trailing spinner
+// frames stripped and runs of progress lines collapsed to their last line
+func (s *sanitizer) apply(content string) string {
+	if s == nil {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	if s.bannerRegexp != nil || s.bannerString != "" {
+		lines = s.dropBanner(lines)
+	}
+	if s.stripSpinners {
+		lines = s.stripSpinnerFrames(lines)
+	}
+	if s.collapse {
+		lines = collapseProgressLines(lines, s.spinnerRunes)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dropBanner removes everything down to and including the first line that
+// marks the end of the banner. Without such a line the banner has scrolled
+// away and nothing is removed.
+func (s *sanitizer) dropBanner(lines []string) []string {
+	for i, line := range lines {
+		plain := StripANSI(line)
+		if (s.bannerRegexp != nil && s.bannerRegexp.MatchString(plain)) ||
+			(s.bannerString != "" && strings.Contains(strings.ToLower(plain), s.bannerString)) {
+			return lines[i+1:]
+		}
+	}
+	return lines
+}
+
+// stripSpinnerFrames trims spinner characters off the end of each line and
+// drops lines that held nothing but spinner frames
+func (s *sanitizer) stripSpinnerFrames(lines []string) []string {
+	result := lines[:0:0]
+	for _, line := range lines {
+		trimmed := strings.TrimRightFunc(line, func(r rune) bool {
+			return unicode.IsSpace(r) || s.spinnerRunes[r]
+		})
+		if trimmed == "" && strings.TrimSpace(line) != "" {
+			continue
+		}
+		result = append(result, trimmed)
+	}
+	return result
+}
+
+// collapseProgressLines keeps only the last line of each run of consecutive
+// lines that differ in digits and spinner characters only, such as
+// "Downloading 10%", "Downloading 20%"
+func collapseProgressLines(lines []string, spinnerRunes map[rune]bool) []string {
+	result := lines[:0:0]
+	prevShape := ""
+	for _, line := range lines {
+		shape := progressShape(line, spinnerRunes)
+		if shape != "" && shape == prevShape {
+			result[len(result)-1] = line
+			continue
+		}
+		result = append(result, line)
+		prevShape = shape
+	}
+	return result
+}
+
+// progressShape is line without ANSI and spinner characters, with each run
+// of digits replaced by '#', or "" for lines without digits (which are never
+// collapsed)
+func progressShape(line string, spinnerRunes map[rune]bool) string {
+	var b strings.Builder
+	hasDigit, inNumber := false, false
+	for _, r := range StripANSI(line) {
+		switch {
+		case unicode.IsDigit(r):
+			hasDigit = true
+			if !inNumber {
+				b.WriteRune('#')
+			}
+			inNumber = true
+			continue
+		case spinnerRunes[r]:
+		default:
+			b.WriteRune(r)
+		}
+		inNumber = false
+	}
+	if !hasDigit {
+		return ""
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,98 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeBannerEnd(t *testing.T) {
+	raw := &RawPatterns{
+		BusyPatterns: []string{"free extra usage"},
+		Capture:      CaptureOptions{BannerEnd: "re:^~/"},
+	}
+	resolved, err := CompilePatterns(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withBanner := strings.Join([]string{
+		"Opus 4.6 is here · $50 free extra usage · Try fast mode or use i…",
+		"Opus 4.6 · Claude Max",
+		"~/.agent-deck/conductor/sre",
+		`❯ Try "create a util logging.py that..."`,
+	}, "\n")
+	got := resolved.Sanitize(withBanner)
+	if strings.Contains(got, "Claude Max") || !strings.HasPrefix(got, "❯") {
+		t.Errorf("banner not removed:\n%s", got)
+	}
+
+	// Banner text matches the busy pattern only while it is not ignored
+	plain, _ := CompilePatterns(&RawPatterns{BusyPatterns: raw.BusyPatterns})
+	s := &Session{Name: "banner", DisplayName: "banner", resolvedPatterns: plain}
+	if !s.hasBusyIndicator(withBanner) {
+		t.Fatal("expected the banner line to match without banner_end")
+	}
+	s = &Session{Name: "banner", DisplayName: "banner", resolvedPatterns: resolved}
+	if s.hasBusyIndicator(withBanner) {
+		t.Error("banner line should not count as busy once banner_end is set")
+	}
+
+	// Once the banner scrolled away, nothing is removed
+	noBanner := "output line\n❯ "
+	if got := resolved.Sanitize(noBanner); got != noBanner {
+		t.Errorf("Sanitize without banner = %q, want unchanged", got)
+	}
+}
+
+func TestSanitizeSpinnerFramesAndProgress(t *testing.T) {
+	resolved, err := CompilePatterns(&RawPatterns{
+		Capture: CaptureOptions{StripSpinnerFrames: true, CollapseProgress: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Join([]string{
+		"Installing deps ⠋",
+		"⠙",
+		"Downloading 9%",
+		"Downloading 45%",
+		"Downloading 100%",
+		"step 1 of 3",
+		"done",
+		"",
+		"$ ",
+	}, "\n")
+	want := strings.Join([]string{
+		"Installing deps",
+		"Downloading 100%",
+		"step 1 of 3",
+		"done",
+		"",
+		"$",
+	}, "\n")
+	if got := resolved.Sanitize(content); got != want {
+		t.Errorf("Sanitize =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSanitizeDisabledByDefault(t *testing.T) {
+	resolved, err := CompilePatterns(DefaultRawPatterns("claude"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "Downloading 1%\nDownloading 2% ⠋"
+	if got := resolved.Sanitize(content); got != content {
+		t.Errorf("Sanitize without options = %q, want unchanged", got)
+	}
+}
+
+func TestMergeRawPatternsSanitizeOptions(t *testing.T) {
+	merged := MergeRawPatterns(
+		&RawPatterns{Capture: CaptureOptions{BannerEnd: "default"}},
+		&RawPatterns{Capture: CaptureOptions{CollapseProgress: true}},
+		nil,
+	)
+	if merged.Capture.BannerEnd != "default" || !merged.Capture.CollapseProgress || merged.Capture.StripSpinnerFrames {
+		t.Errorf("merged capture = %+v", merged.Capture)
+	}
+}
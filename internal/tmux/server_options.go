@@ -0,0 +1,173 @@
+package tmux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Option scopes, matching tmux's set-option flags
+const (
+	OptionScopeServer  = "server"  // set-option -s
+	OptionScopeSession = "session" // set-option -g / -t <session>
+)
+
+// RequiredOption is a tmux option agent-deck depends on for capture and
+// input injection, with the value it sets and what breaks otherwise.
+type RequiredOption struct {
+	Name  string
+	Scope string
+	Want  string
+	Why   string
+	// SetByDeck means Start() sets the option on every agent-deck session
+	SetByDeck bool
+	ok        func(value string) bool
+}
+
+// OK reports whether value satisfies the requirement
+func (r RequiredOption) OK(value string) bool {
+	return r.ok(value)
+}
+
+// RequiredOptions are the tmux options checked at startup
+var RequiredOptions = []RequiredOption{
+	{
+		Name: "history-limit", Scope: OptionScopeSession, Want: "10000", SetByDeck: true,
+		Why: "session output and status capture read scrollback; long responses get cut off",
+		ok:  func(v string) bool { n, err := strconv.Atoi(v); return err == nil && n >= 2000 },
+	},
+	{
+		Name: "escape-time", Scope: OptionScopeServer, Want: "10", SetByDeck: true,
+		Why: "injected Escape keys are held back and may merge with the next key into an Alt sequence",
+		ok:  func(v string) bool { n, err := strconv.Atoi(v); return err == nil && n <= 100 },
+	},
+	{
+		Name: "default-terminal", Scope: OptionScopeSession, Want: "tmux-256color",
+		Why: "agents draw for the wrong terminal, so captured output carries stray escapes",
+		ok: func(v string) bool {
+			return strings.HasPrefix(v, "tmux") || strings.HasPrefix(v, "screen")
+		},
+	},
+	{
+		Name: "focus-events", Scope: OptionScopeServer, Want: "on", SetByDeck: true,
+		Why: "agents are not told when a pane regains focus and may not redraw after attach",
+		ok:  func(v string) bool { return v == "on" },
+	},
+}
+
+// OptionIssue is a required option whose effective value breaks agent-deck
+type OptionIssue struct {
+	Name   string `json:"name"`
+	Scope  string `json:"scope"`
+	Value  string `json:"value"`
+	Want   string `json:"want"`
+	Why    string `json:"why"`
+	Source string `json:"source"` // "tmux.conf" or "config.toml"
+}
+
+func (i OptionIssue) String() string {
+	return fmt.Sprintf("%s = %q in %s (want %s): %s", i.Name, i.Value, i.Source, i.Want, i.Why)
+}
+
+// parseShowOptions parses "show-options" output ("name value" per line)
+func parseShowOptions(out string) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		values[name] = strings.Trim(value, "\"")
+	}
+	return values
+}
+
+// GlobalOptions returns the server and global session option values. The
+// server is started for the query if needed (it exits again when it has no
+// sessions), so tmux.conf has always been applied.
+func GlobalOptions() (map[string]string, error) {
+	out, err := Command("start-server", ";", "show-options", "-s", ";", "show-options", "-g").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tmux options: %w", err)
+	}
+	return parseShowOptions(string(out)), nil
+}
+
+// CheckOptions compares the global option values and agent-deck's [tmux]
+// option overrides against RequiredOptions. Overrides are always checked,
+// since they are applied after agent-deck's own values. Options Start() sets
+// itself are only checked on the server once agent-deck sessions are running
+// (deckRunning): a wrong value then means tmux.conf hooks or another tool
+// reset it. Session-scope ones are set per session, so the global value of
+// those never matters.
+func CheckOptions(global, overrides map[string]string, deckRunning bool) []OptionIssue {
+	var issues []OptionIssue
+	for _, req := range RequiredOptions {
+		if value, ok := overrides[req.Name]; ok {
+			if !req.OK(value) {
+				issues = append(issues, OptionIssue{Name: req.Name, Scope: req.Scope, Value: value, Want: req.Want, Why: req.Why, Source: "config.toml"})
+			}
+			continue
+		}
+		if req.SetByDeck && (req.Scope == OptionScopeSession || !deckRunning) {
+			continue
+		}
+		value, ok := global[req.Name]
+		if !ok || req.OK(value) {
+			continue
+		}
+		issues = append(issues, OptionIssue{Name: req.Name, Scope: req.Scope, Value: value, Want: req.Want, Why: req.Why, Source: "tmux.conf"})
+	}
+	return issues
+}
+
+// DeckSessions returns the names of running agent-deck tmux sessions
+func DeckSessions() []string {
+	out, _ := Command("list-sessions", "-F", "#{session_name}").Output()
+	var sessions []string
+	for _, name := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if strings.HasPrefix(name, SessionPrefix) {
+			sessions = append(sessions, name)
+		}
+	}
+	return sessions
+}
+
+// FixOptions sets each issue's option to the required value: server options
+// on the server, session options on agent-deck's sessions only, so the
+// user's other sessions keep their settings. default-terminal only affects
+// panes created afterwards. Issues from config.toml are skipped; the override
+// would win again on the next session start, so it has to be edited there.
+func FixOptions(issues []OptionIssue) error {
+	var args []string
+	add := func(cmd ...string) {
+		if len(args) > 0 {
+			args = append(args, ";")
+		}
+		args = append(args, cmd...)
+	}
+	var sessions []string
+	listed := false
+	for _, issue := range issues {
+		if issue.Source == "config.toml" {
+			continue
+		}
+		if issue.Scope == OptionScopeServer {
+			add("set-option", "-s", issue.Name, issue.Want)
+			continue
+		}
+		if !listed {
+			sessions, listed = DeckSessions(), true
+		}
+		for _, name := range sessions {
+			add("set-option", "-t", name, issue.Name, issue.Want)
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	if out, err := Command(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set tmux options: %w (output: %s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package tmux
+
+import "testing"
+
+func TestParseShowOptions(t *testing.T) {
+	got := parseShowOptions("escape-time 500\ndefault-terminal \"xterm-256color\"\nterminal-overrides\n")
+	if got["escape-time"] != "500" || got["default-terminal"] != "xterm-256color" {
+		t.Errorf("parseShowOptions = %v", got)
+	}
+	if _, ok := got["terminal-overrides"]; ok {
+		t.Error("options without a value should be skipped")
+	}
+}
+
+func TestCheckOptions(t *testing.T) {
+	global := map[string]string{
+		"history-limit":    "500",
+		"escape-time":      "500",
+		"default-terminal": "xterm-256color",
+		"focus-events":     "off",
+	}
+
+	// Before agent-deck sessions run, only options it does not set itself count
+	issues := CheckOptions(global, nil, false)
+	if len(issues) != 1 || issues[0].Name != "default-terminal" || issues[0].Source != "tmux.conf" {
+		t.Fatalf("issues = %+v, want only default-terminal", issues)
+	}
+
+	// With agent-deck sessions running, reset server options are reported too
+	issues = CheckOptions(global, nil, true)
+	names := map[string]bool{}
+	for _, issue := range issues {
+		names[issue.Name] = true
+	}
+	if !names["escape-time"] || !names["focus-events"] || names["history-limit"] {
+		t.Errorf("issues = %+v, want escape-time and focus-events but not history-limit", issues)
+	}
+
+	// Overrides from config.toml are always checked and take precedence
+	issues = CheckOptions(map[string]string{"default-terminal": "tmux-256color"},
+		map[string]string{"history-limit": "100", "escape-time": "0"}, false)
+	if len(issues) != 1 || issues[0].Name != "history-limit" || issues[0].Source != "config.toml" {
+		t.Errorf("issues = %+v, want history-limit from config.toml", issues)
+	}
+}
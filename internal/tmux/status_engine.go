@@ -0,0 +1,223 @@
+package tmux
+
+import (
+	"strings"
+	"time"
+)
+
+// Reasons a status was detected, reported in StatusResult.Reason
+const (
+	ReasonTitle          = "title"           // Claude's pane title spinner
+	ReasonBusyPattern    = "busy_pattern"    // a busy pattern matched
+	ReasonSpinner        = "spinner"         // a spinner character on screen
+	ReasonBusyGrace      = "busy_grace"      // busy moments ago, between tool calls
+	ReasonPromptPattern  = "prompt_pattern"  // a prompt pattern matched
+	ReasonPrompt         = "prompt"          // the tool's built-in prompt detector matched
+	ReasonReadyProbe     = "ready_probe"     // a prompt is visible but the ready probe failed
+	ReasonNoSession      = "no_session"      // the tmux session does not exist
+	ReasonCaptureTimeout = "capture_timeout" // the pane could not be read; previous state kept
+	ReasonAcknowledged   = "acknowledged"    // idle because the user has seen the session
+	ReasonStartup        = "startup"         // still inside the startup window
+	ReasonActivity       = "activity"        // no explicit signal; window activity only
+	ReasonBell           = "bell"            // the pane rang the terminal bell (done or wants input)
+	ReasonClassifier     = "classifier"      // the tool's external classifier decided
+	ReasonFusion         = "fusion"          // weighted signals (CPU, output, activity, patterns) add up to busy
+	ReasonHeld           = "held"            // a signal for another state, overridden by hysteresis
+	ReasonDebounced      = "debounced"       // weak evidence for a change, not yet repeated
+)
+
+// Status engine tuning: a change between active and waiting/idle backed by
+// evidence weaker than weakConfidence is only reported once it has been
+// detected on flapPolls consecutive polls
+const (
+	weakConfidence = 0.6
+	flapPolls      = 2
+)
+
+// StatusResult is the status engine's verdict for a session
+type StatusResult struct {
+	// State is active, waiting, idle, starting or inactive (as GetStatus)
+	State string `json:"state"`
+	// Confidence in State, from 0 to 1
+	Confidence float64 `json:"confidence"`
+	// Reason names the signal State rests on (see the Reason constants)
+	Reason string `json:"reason"`
+	// Evidence holds the pane lines (or title) that matched, if any
+	Evidence []string `json:"evidence,omitempty"`
+	// Since is when the session entered State
+	Since time.Time `json:"since"`
+}
+
+// statusSignal is the strongest detection signal seen during one poll
+type statusSignal struct {
+	reason     string
+	confidence float64
+	// implies is the state family the signal supports: "active", "prompt"
+	// (waiting or idle), "inactive", or "" for any state
+	implies  string
+	evidence []string
+}
+
+// noteStatusSignal records the signal behind the current poll. Detection may
+// run without s.mu held, so signals have their own lock.
+func (s *Session) noteStatusSignal(reason string, confidence float64, implies string, evidence ...string) {
+	var lines []string
+	for _, line := range evidence {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	s.signalMu.Lock()
+	s.signal = &statusSignal{reason: reason, confidence: confidence, implies: implies, evidence: lines}
+	s.signalMu.Unlock()
+}
+
+// GetStatusResult runs one status poll and returns the debounced state with
+// its confidence and evidence
+func (s *Session) GetStatusResult() (StatusResult, error) {
+	s.signalMu.Lock()
+	s.signal = nil
+	s.signalMu.Unlock()
+
+	state, err := s.detectStatus()
+	if err != nil {
+		return StatusResult{}, err
+	}
+
+	s.signalMu.Lock()
+	signal := s.signal
+	s.signalMu.Unlock()
+	raw := classifyStatus(state, signal)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusMachine.observe(raw, time.Now()), nil
+}
+
+// GetStatus returns the current status of the session: active, waiting,
+// idle, starting or inactive. It is GetStatusResult without the details.
+func (s *Session) GetStatus() (string, error) {
+	result, err := s.GetStatusResult()
+	return result.State, err
+}
+
+// LastStatusResult returns the verdict of the last status poll without
+// polling again
+func (s *Session) LastStatusResult() StatusResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusMachine.last
+}
+
+// classifyStatus scores a raw detected state by the signal behind it
+func classifyStatus(state string, signal *statusSignal) StatusResult {
+	result := StatusResult{State: state}
+	if signal != nil {
+		result.Evidence = signal.evidence
+		if signalSupports(signal.implies, state) {
+			result.Reason = signal.reason
+			result.Confidence = signal.confidence
+			return result
+		}
+		result.Reason = ReasonHeld
+		result.Confidence = 0.5
+		return result
+	}
+	switch state {
+	case "idle":
+		result.Reason, result.Confidence = ReasonAcknowledged, 0.9
+	case "starting":
+		result.Reason, result.Confidence = ReasonStartup, 0.5
+	default:
+		result.Reason, result.Confidence = ReasonActivity, 0.5
+	}
+	return result
+}
+
+func signalSupports(implies, state string) bool {
+	switch implies {
+	case "":
+		return true
+	case "prompt":
+		return state == "waiting" || state == "idle"
+	}
+	return implies == state
+}
+
+// statusMachine debounces a session's detected states. Changes between
+// active and waiting/idle on weak evidence must repeat before they are
+// reported, which absorbs flapping during redraws and capture misses;
+// everything else is reported as detected.
+type statusMachine struct {
+	state        string
+	since        time.Time
+	pending      string
+	pendingPolls int
+	last         StatusResult
+}
+
+func (m *statusMachine) observe(raw StatusResult, now time.Time) StatusResult {
+	if m.state != "" && raw.State != m.state && isFlapTransition(m.state, raw.State) && raw.Confidence < weakConfidence {
+		if m.pending == raw.State {
+			m.pendingPolls++
+		} else {
+			m.pending, m.pendingPolls = raw.State, 1
+		}
+		if m.pendingPolls < flapPolls {
+			m.last = StatusResult{
+				State:      m.state,
+				Confidence: raw.Confidence,
+				Reason:     ReasonDebounced,
+				Evidence:   raw.Evidence,
+				Since:      m.since,
+			}
+			return m.last
+		}
+	}
+	m.pending, m.pendingPolls = "", 0
+	if raw.State != m.state {
+		m.state, m.since = raw.State, now
+	}
+	raw.Since = m.since
+	m.last = raw
+	return raw
+}
+
+// isFlapTransition reports whether a change is between busy (active) and
+// quiet (waiting, idle)
+func isFlapTransition(from, to string) bool {
+	quiet := func(s string) bool { return s == "waiting" || s == "idle" }
+	return (from == "active" && quiet(to)) || (quiet(from) && to == "active")
+}
+
+// lineAt returns the line of content containing byte offset idx
+func lineAt(content string, idx int) string {
+	if idx < 0 || idx > len(content) {
+		return ""
+	}
+	start := strings.LastIndex(content[:idx], "\n") + 1
+	end := strings.Index(content[idx:], "\n")
+	if end < 0 {
+		return content[start:]
+	}
+	return content[start : idx+end]
+}
+
+// lineContaining returns the first line containing lowerStr, case-insensitively
+func lineContaining(lines []string, lowerStr string) string {
+	for _, line := range lines {
+		if strings.Contains(strings.ToLower(line), lowerStr) {
+			return line
+		}
+	}
+	return ""
+}
+
+// lastNonEmptyLine returns the bottom non-blank line of content
+func lastNonEmptyLine(content string) string {
+	lines := lastNLines(content, 1)
+	if len(lines) == 0 {
+		return ""
+	}
+	return lines[0]
+}
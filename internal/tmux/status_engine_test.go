@@ -0,0 +1,73 @@
+package tmux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	busy := &statusSignal{reason: ReasonBusyPattern, confidence: 0.9, implies: "active", evidence: []string{"esc to interrupt"}}
+
+	if got := classifyStatus("active", busy); got.Reason != ReasonBusyPattern || got.Confidence != 0.9 || len(got.Evidence) != 1 {
+		t.Errorf("supported signal = %+v", got)
+	}
+	// Hysteresis kept the session active on a prompt signal
+	prompt := &statusSignal{reason: ReasonPrompt, confidence: 0.85, implies: "prompt"}
+	if got := classifyStatus("active", prompt); got.Reason != ReasonHeld || got.Confidence != 0.5 {
+		t.Errorf("contradicted signal = %+v", got)
+	}
+	if got := classifyStatus("idle", prompt); got.Reason != ReasonPrompt {
+		t.Errorf("prompt signal on idle = %+v", got)
+	}
+	if got := classifyStatus("idle", nil); got.Reason != ReasonAcknowledged {
+		t.Errorf("idle without signal = %+v", got)
+	}
+	if got := classifyStatus("waiting", nil); got.Reason != ReasonActivity || got.Confidence >= weakConfidence {
+		t.Errorf("waiting without signal = %+v", got)
+	}
+}
+
+func TestStatusMachineDebouncesWeakFlaps(t *testing.T) {
+	var m statusMachine
+	t0 := time.Now()
+	strong := func(state string) StatusResult { return StatusResult{State: state, Confidence: 0.9} }
+	weak := func(state string) StatusResult { return StatusResult{State: state, Confidence: 0.5} }
+
+	if got := m.observe(strong("active"), t0); got.State != "active" || !got.Since.Equal(t0) {
+		t.Fatalf("first poll = %+v", got)
+	}
+	// One weak poll does not flip the state
+	got := m.observe(weak("waiting"), t0.Add(time.Second))
+	if got.State != "active" || got.Reason != ReasonDebounced || !got.Since.Equal(t0) {
+		t.Errorf("weak flap = %+v, want active debounced", got)
+	}
+	// Strong evidence in between resets the count
+	m.observe(strong("active"), t0.Add(2*time.Second))
+	if got := m.observe(weak("waiting"), t0.Add(3*time.Second)); got.State != "active" {
+		t.Errorf("weak flap after reset = %+v", got)
+	}
+	// The second consecutive weak poll is reported
+	t4 := t0.Add(4 * time.Second)
+	if got := m.observe(weak("waiting"), t4); got.State != "waiting" || !got.Since.Equal(t4) {
+		t.Errorf("repeated weak change = %+v, want waiting since t4", got)
+	}
+	// Strong changes and changes outside busy/quiet apply at once
+	if got := m.observe(strong("active"), t0.Add(5*time.Second)); got.State != "active" {
+		t.Errorf("strong change = %+v", got)
+	}
+	if got := m.observe(weak("inactive"), t0.Add(6*time.Second)); got.State != "inactive" {
+		t.Errorf("change to inactive = %+v", got)
+	}
+	if m.last.State != "inactive" {
+		t.Errorf("last = %+v", m.last)
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	content := "one\ntwo words\nthree"
+	for idx, want := range map[int]string{0: "one", 4: "two words", 8: "two words", 15: "three", 99: ""} {
+		if got := lineAt(content, idx); got != want {
+			t.Errorf("lineAt(%d) = %q, want %q", idx, got, want)
+		}
+	}
+}
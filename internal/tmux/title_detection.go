@@ -1,7 +1,6 @@
 package tmux
 
 import (
-	"os/exec"
 	"strings"
 	"sync"
 	"time"
@@ -49,7 +48,7 @@ func RefreshPaneInfoCache() {
 	}
 
 	// Subprocess fallback: list-panes -a
-	cmd := exec.Command("tmux", "list-panes", "-a", "-F", "#{session_name}\t#{pane_title}\t#{pane_current_command}")
+	cmd := Command("list-panes", "-a", "-F", "#{session_name}\t#{pane_title}\t#{pane_current_command}")
 	output, err := cmd.Output()
 	if err != nil {
 		paneCacheMu.Lock()
@@ -18,12 +18,14 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/chaos"
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
 	"golang.org/x/sync/singleflight"
 )
 
 var statusLog = logging.ForComponent(logging.CompStatus)
-var respawnLog = logging.ForComponent(logging.CompSession)
+var respawnLog = logging.ForComponent(logging.CompTmux)
 var mcpLog = logging.ForComponent(logging.CompMCP)
 
 // ErrCaptureTimeout is returned when CapturePane exceeds its timeout.
@@ -65,7 +67,7 @@ func RefreshSessionCache() {
 	}
 
 	// Subprocess fallback: list-windows -a
-	cmd := exec.Command("tmux", "list-windows", "-a", "-F", "#{session_name}\t#{window_activity}")
+	cmd := Command("list-windows", "-a", "-F", "#{session_name}\t#{window_activity}")
 	output, err := cmd.Output()
 	if err != nil {
 		sessionCacheMu.Lock()
@@ -156,7 +158,7 @@ func sessionActivityFromCache(name string) (int64, bool) {
 // IsTmuxAvailable checks if tmux is installed and accessible
 // Returns nil if tmux is available, otherwise returns an error with details
 func IsTmuxAvailable() error {
-	cmd := exec.Command("tmux", "-V")
+	cmd := Command("-V")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("tmux not found or not working: %w (output: %s)", err, string(output))
@@ -337,6 +339,14 @@ var toolDetectionPatterns = map[string][]*regexp.Regexp{
 		regexp.MustCompile(`(?i)codex`),
 		regexp.MustCompile(`(?i)openai`),
 	},
+	"ollama": {
+		regexp.MustCompile(`(?i)ollama`),
+		regexp.MustCompile(`Send a message \(/\? for help\)`),
+	},
+	"aider": {
+		regexp.MustCompile(`(?i)aider v\d`),
+		regexp.MustCompile(`\(Y\)es/\(N\)o`),
+	},
 }
 
 // StateTracker tracks content changes for notification-style status detection
@@ -479,6 +489,14 @@ type Session struct {
 	// Example: {"allow-passthrough": "all", "history-limit": "50000"}
 	OptionOverrides map[string]string
 
+	// Status engine: the signal behind the current poll's detection and the
+	// debouncing state machine (see GetStatusResult)
+	signalMu      sync.Mutex
+	signal        *statusSignal
+	statusMachine statusMachine
+	// lastBellSeen is the pipe's bell time already taken into account
+	lastBellSeen time.Time
+
 	// Custom patterns for generic tool support
 	customToolName       string
 	customBusyPatterns   []string
@@ -489,6 +507,17 @@ type Session struct {
 	// When non-nil, hasBusyIndicator and normalizeContent use these instead of hardcoded values
 	resolvedPatterns *ResolvedPatterns
 
+	// Process and port checks a prompt must pass to count as ready (see ReadyProbe)
+	readyProbe       *ReadyProbe
+	readyProbeResult readyProbeResult
+
+	// External program consulted by status detection (see StatusClassifier)
+	classifier *StatusClassifier
+
+	// Weighted signals that can keep a session active (see StatusFusion)
+	fusion      *StatusFusion
+	fusionState fusionState
+
 	// Cached PromptDetector (avoids allocating a new one on every hasPromptIndicator call)
 	cachedPromptDetector     *PromptDetector
 	cachedPromptDetectorTool string
@@ -540,6 +569,10 @@ func (s *Session) ensureStateTrackerLocked() {
 	}
 }
 
+// promptNoBusyHoldPolls is how many polls a prompt without a busy indicator
+// keeps an active session active (see shouldHoldActiveOnPromptLocked)
+const promptNoBusyHoldPolls = 2
+
 // shouldHoldActiveOnPromptLocked applies a small hysteresis when a session was
 // recently active but current capture shows prompt with no busy signal.
 // This avoids active <-> waiting flicker from transient capture misses.
@@ -548,7 +581,6 @@ func (s *Session) shouldHoldActiveOnPromptLocked() bool {
 	if s.stateTracker == nil || s.lastStableStatus != "active" {
 		return false
 	}
-	const promptNoBusyHoldPolls = 2
 	if s.stateTracker.promptNoBusyCount < promptNoBusyHoldPolls {
 		s.stateTracker.promptNoBusyCount++
 		return true
@@ -608,21 +640,32 @@ func (s *Session) SetInjectStatusLine(inject bool) {
 // LogFile returns the path to this session's log file
 // Logs are stored in ~/.agent-deck/logs/<session-name>.log
 func (s *Session) LogFile() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		homeDir = "/tmp"
-	}
-	logDir := filepath.Join(homeDir, ".agent-deck", "logs")
-	return filepath.Join(logDir, s.Name+".log")
+	return filepath.Join(LogDir(), s.Name+".log")
 }
 
 // LogDir returns the directory containing all session logs
 func LogDir() string {
-	homeDir, err := os.UserHomeDir()
+	deckDir, err := deck.Home()
 	if err != nil {
-		homeDir = "/tmp"
+		deckDir = filepath.Join("/tmp", ".agent-deck")
 	}
-	return filepath.Join(homeDir, ".agent-deck", "logs")
+	return filepath.Join(deckDir, "logs")
+}
+
+// PipePane pipes the pane's output into command (run by sh), unless a pipe
+// is already open. (pipe-pane -o would close an open pipe instead.)
+func (s *Session) PipePane(command string) error {
+	out, err := Command("display-message", "-p", "-t", s.Name, "#{pane_pipe}").Output()
+	if err != nil {
+		return fmt.Errorf("failed to query pane pipe: %w", err)
+	}
+	if strings.TrimSpace(string(out)) == "1" {
+		return nil
+	}
+	if err := Command("pipe-pane", "-t", s.Name, command).Run(); err != nil {
+		return fmt.Errorf("failed to start pipe-pane: %w", err)
+	}
+	return nil
 }
 
 // NewSession creates a new Session instance with a unique name
@@ -798,7 +841,7 @@ func generateShortID() string {
 
 // SetEnvironment sets an environment variable for this tmux session
 func (s *Session) SetEnvironment(key, value string) error {
-	cmd := exec.Command("tmux", "set-environment", "-t", s.Name, key, value)
+	cmd := Command("set-environment", "-t", s.Name, key, value)
 	err := cmd.Run()
 	if err == nil {
 		// Invalidate cache entry so next GetEnvironment sees the new value
@@ -825,7 +868,7 @@ func (s *Session) GetEnvironment(key string) (string, error) {
 	}
 	s.envCacheMu.RUnlock()
 
-	cmd := exec.Command("tmux", "show-environment", "-t", s.Name, key)
+	cmd := Command("show-environment", "-t", s.Name, key)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("variable not found or session doesn't exist: %s", key)
@@ -889,7 +932,7 @@ func (s *Session) Start(command string) error {
 	}
 
 	// Create new tmux session in detached mode
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", s.Name, "-c", workDir)
+	cmd := Command("new-session", "-d", "-s", s.Name, "-c", workDir)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to create tmux session: %w (output: %s)", err, string(output))
@@ -910,8 +953,9 @@ func (s *Session) Start(command string) error {
 	// - set-clipboard on: Clipboard integration (Warp, iTerm2, kitty, etc.)
 	// - history-limit 10000: Large scrollback for AI agent output
 	// - escape-time 10: Fast Vim/editor responsiveness (default 500ms is too slow)
+	// - focus-events on: Agents redraw when a pane regains focus (server-wide)
 	// - terminal-features hyperlinks: Track hyperlinks like colors (tmux 3.4+, server-wide)
-	_ = exec.Command("tmux",
+	_ = Command(
 		"set-option", "-t", s.Name, "window-style", "default", ";",
 		"set-option", "-t", s.Name, "window-active-style", "default", ";",
 		"set-option", "-t", s.Name, "mouse", "on", ";",
@@ -919,6 +963,7 @@ func (s *Session) Start(command string) error {
 		"set-option", "-t", s.Name, "set-clipboard", "on", ";",
 		"set-option", "-t", s.Name, "history-limit", "10000", ";",
 		"set-option", "-t", s.Name, "escape-time", "10", ";",
+		"set-option", "-sq", "focus-events", "on", ";",
 		"set", "-asq", "terminal-features", ",*:hyperlinks").Run()
 
 	// Apply user-specified tmux option overrides from config (after defaults).
@@ -933,7 +978,7 @@ func (s *Session) Start(command string) error {
 			args = append(args, "set-option", "-t", s.Name, "-q", key, value)
 			first = false
 		}
-		_ = exec.Command("tmux", args...).Run()
+		_ = Command(args...).Run()
 	}
 
 	// Configure status bar with session info for easy identification
@@ -951,6 +996,9 @@ func (s *Session) Start(command string) error {
 			escapedCmd := strings.ReplaceAll(command, "'", "'\"'\"'")
 			cmdToSend = fmt.Sprintf("bash -c '%s'", escapedCmd)
 		}
+		if delay := chaos.StartDelay(); delay > 0 {
+			cmdToSend = fmt.Sprintf("sleep %d; %s", int(delay.Seconds()), cmdToSend)
+		}
 		if err := s.SendKeysAndEnter(cmdToSend); err != nil {
 			return fmt.Errorf("failed to send command: %w", err)
 		}
@@ -989,7 +1037,7 @@ func (s *Session) Exists() bool {
 	}
 
 	// Cache is stale and no live pipe: fall back to direct tmux check.
-	cmd := exec.Command("tmux", "has-session", "-t", s.Name)
+	cmd := Command("has-session", "-t", s.Name)
 	return cmd.Run() == nil
 }
 
@@ -1017,7 +1065,7 @@ func (s *Session) ConfigureStatusBar() {
 	// Uses tmux command chaining with \; separator (73% reduction in subprocess calls)
 	// Before: 5 separate exec.Command calls = 5 subprocess spawns
 	// After: 1 exec.Command call = 1 subprocess spawn
-	cmd := exec.Command("tmux",
+	cmd := Command(
 		"set-option", "-t", s.Name, "status", "on", ";",
 		"set-option", "-t", s.Name, "status-style", "bg=#1a1b26,fg=#a9b1d6", ";",
 		"set-option", "-t", s.Name, "status-left-length", "120", ";",
@@ -1046,7 +1094,7 @@ func (s *Session) ConfigureStatusBar() {
 func (s *Session) EnableMouseMode() error {
 	// CRITICAL: Mouse mode must succeed - keep as separate call for error handling
 	// This is the only essential feature; all others are enhancements
-	mouseCmd := exec.Command("tmux", "set-option", "-t", s.Name, "mouse", "on")
+	mouseCmd := Command("set-option", "-t", s.Name, "mouse", "on")
 	if err := mouseCmd.Run(); err != nil {
 		return err
 	}
@@ -1064,7 +1112,7 @@ func (s *Session) EnableMouseMode() error {
 	// - escape-time 10: Fast Vim/editor responsiveness (default 500ms is too slow)
 	//
 	// Uses -q flag where supported to silently ignore on older tmux versions
-	enhanceCmd := exec.Command("tmux",
+	enhanceCmd := Command(
 		"set-option", "-t", s.Name, "set-clipboard", "on", ";",
 		"set-option", "-t", s.Name, "-q", "allow-passthrough", "on", ";",
 		"set-option", "-t", s.Name, "history-limit", "10000", ";",
@@ -1098,7 +1146,7 @@ func (s *Session) Kill() error {
 	}
 
 	// Kill the tmux session
-	cmd := exec.Command("tmux", "kill-session", "-t", s.Name)
+	cmd := Command("kill-session", "-t", s.Name)
 	err := cmd.Run()
 
 	// Verify old processes are dead; escalate to SIGKILL if needed
@@ -1113,7 +1161,7 @@ func (s *Session) Kill() error {
 // Used before respawn to track processes that must die.
 func (s *Session) getPaneProcessTree() (panePID int, allPIDs []int) {
 	target := s.Name + ":"
-	out, err := exec.Command("tmux", "list-panes", "-t", target, "-F", "#{pane_pid}").Output()
+	out, err := Command("list-panes", "-t", target, "-F", "#{pane_pid}").Output()
 	if err != nil {
 		return 0, nil
 	}
@@ -1262,7 +1310,7 @@ func (s *Session) RespawnPane(command string) error {
 	// Clear scrollback buffer BEFORE respawn to prevent stale content
 	// from previous conversation appearing when user attaches (#138).
 	clearTarget := s.Name + ":"
-	clearCmd := exec.Command("tmux", "clear-history", "-t", clearTarget)
+	clearCmd := Command("clear-history", "-t", clearTarget)
 	if clearOut, clearErr := clearCmd.CombinedOutput(); clearErr != nil {
 		respawnLog.Debug("clear_history_failed", slog.String("error", clearErr.Error()), slog.String("output", string(clearOut)))
 	} else {
@@ -1298,7 +1346,7 @@ func (s *Session) RespawnPane(command string) error {
 	}
 
 	mcpLog.Debug("respawn_pane_executing", slog.Any("args", args))
-	cmd := exec.Command("tmux", args...)
+	cmd := Command(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		mcpLog.Debug("respawn_pane_error", slog.String("error", err.Error()), slog.String("output", string(output)))
@@ -1352,7 +1400,7 @@ func (s *Session) GetWindowActivity() (int64, error) {
 	// No PipeManager: fall back to direct check (spawns subprocess)
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, "tmux", "display-message", "-t", s.Name, "-p", "#{window_activity}")
+	cmd := CommandContext(ctx, "display-message", "-t", s.Name, "-p", "#{window_activity}")
 	output, err := cmd.Output()
 	if err != nil {
 		return 0, fmt.Errorf("failed to get window activity: %w", err)
@@ -1400,6 +1448,10 @@ func (s *Session) CapturePane() (string, error) {
 		}
 		s.cacheMu.RUnlock()
 
+		if err := chaos.Error(chaos.CaptureFailure); err != nil {
+			return "", fmt.Errorf("failed to capture pane: %w", err)
+		}
+
 		// Try control mode pipe first (zero subprocess)
 		if pm := GetPipeManager(); pm != nil {
 			if content, pipeErr := pm.CapturePane(s.Name); pipeErr == nil {
@@ -1416,7 +1468,7 @@ func (s *Session) CapturePane() (string, error) {
 		// Subprocess fallback: -J joins wrapped lines, 3s timeout
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
-		cmd := exec.CommandContext(ctx, "tmux", "capture-pane", "-t", s.Name, "-p", "-J")
+		cmd := CommandContext(ctx, "capture-pane", "-t", s.Name, "-p", "-J")
 		output, err := cmd.Output()
 		if err != nil {
 			if ctx.Err() == context.DeadlineExceeded {
@@ -1445,7 +1497,7 @@ func (s *Session) CaptureFullHistory() (string, error) {
 	// Limit to last 2000 lines to balance content availability with memory usage
 	// AI agent conversations can be long - 2000 lines captures ~40-80 screens of content
 	// -J joins wrapped lines and trims trailing spaces so hashes don't change on resize
-	cmd := exec.Command("tmux", "capture-pane", "-t", s.Name, "-p", "-J", "-S", "-2000")
+	cmd := Command("capture-pane", "-t", s.Name, "-p", "-J", "-S", "-2000")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to capture history: %w", err)
@@ -1521,6 +1573,10 @@ func (s *Session) DetectTool() string {
 			tool = "opencode"
 		} else if strings.Contains(cmdLower, "codex") {
 			tool = "codex"
+		} else if strings.Contains(cmdLower, "ollama") {
+			tool = "ollama"
+		} else if strings.Contains(cmdLower, "aider") {
+			tool = "aider"
 		}
 		if tool != "" {
 			s.mu.Lock()
@@ -1604,7 +1660,8 @@ func (s *Session) AcknowledgeWithSnapshot() {
 	statusLog.Debug("ack_snapshot", slog.String("session", shortName))
 }
 
-// GetStatus returns the current status of the session
+// detectStatus returns the raw status of the session for one poll; the
+// status engine (GetStatusResult) debounces it and adds the evidence
 //
 // Activity-based 3-state model with spike filtering:
 //
@@ -1626,13 +1683,14 @@ func (s *Session) AcknowledgeWithSnapshot() {
 // 4. Check cooldown → GREEN if within
 // 5. Cooldown expired → YELLOW or GRAY based on acknowledged
 
-func (s *Session) GetStatus() (string, error) {
+func (s *Session) detectStatus() (string, error) {
 	shortName := s.DisplayName
 	if len(shortName) > 12 {
 		shortName = shortName[:12]
 	}
 
 	if !s.Exists() {
+		s.noteStatusSignal(ReasonNoSession, 1, "inactive")
 		s.mu.Lock()
 		s.lastStableStatus = "inactive"
 		s.mu.Unlock()
@@ -1648,6 +1706,7 @@ func (s *Session) GetStatus() (string, error) {
 		switch titleState {
 		case TitleStateWorking:
 			// Braille spinner in title = actively working. Short-circuit completely.
+			s.noteStatusSignal(ReasonTitle, 0.95, "active", paneInfo.Title)
 			s.mu.Lock()
 			s.ensureStateTrackerLocked()
 			s.stateTracker.lastChangeTime = time.Now()
@@ -1680,6 +1739,15 @@ func (s *Session) GetStatus() (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// A bell since the last poll means the agent finished or wants input:
+	// it is a fresh reason to look, and a prompt need not wait out the hold
+	if s.takeBellLocked() && s.stateTracker != nil {
+		s.stateTracker.acknowledged = false
+		s.stateTracker.promptNoBusyCount = promptNoBusyHoldPolls
+		s.noteStatusSignal(ReasonBell, 0.7, "prompt")
+		statusLog.Debug("bell_rang", slog.String("session", shortName))
+	}
+
 	// Skip expensive busy indicator check if no activity change and not in active state
 	// This is the key optimization: only call CapturePane() when activity detected
 	needsBusyCheck := false
@@ -1700,13 +1768,27 @@ func (s *Session) GetStatus() (string, error) {
 
 	if needsBusyCheck {
 		// Release lock for slow CapturePane operation
+		capOpts := s.captureOptionsLocked()
+		classifier := s.classifier
+		fusionCPU := s.fusionCPULocked()
 		s.mu.Unlock()
-		content, err := s.CapturePane()
+		content, err := s.captureForStatus(capOpts)
+		probeErr := s.ProbeReady()
+		var verdict *ClassifierVerdict
+		if err == nil && classifier != nil && !classifier.augments() {
+			verdict = s.classifyPane(classifier, content)
+		}
+		var cpuPercent float64
+		var cpuKnown bool
+		if fusionCPU != nil {
+			cpuPercent, cpuKnown = s.paneCPUPercent()
+		}
 		s.mu.Lock()
 
 		if errors.Is(err, ErrCaptureTimeout) {
 			// Timeout: preserve previous state to avoid false RED flashing
 			if s.lastStableStatus != "" {
+				s.noteStatusSignal(ReasonCaptureTimeout, 0.4, "")
 				statusLog.Debug("capture_timeout_preserve", slog.String("session", shortName), slog.String("status", s.lastStableStatus))
 				return s.lastStableStatus, nil
 			}
@@ -1715,6 +1797,12 @@ func (s *Session) GetStatus() (string, error) {
 		} else if err == nil {
 			s.ensureStateTrackerLocked()
 
+			// An override classifier with an opinion decides over the patterns
+			if verdict != nil {
+				statusLog.Debug("classifier_verdict", slog.String("session", shortName), slog.String("state", verdict.State))
+				return s.applyVerdictLocked(verdict, currentTS), nil
+			}
+
 			// Check for explicit busy indicator (spinner, "ctrl+c to interrupt")
 			isExplicitlyBusy := s.hasBusyIndicator(content)
 			// Debug: show last line of content for this session
@@ -1762,6 +1850,27 @@ func (s *Session) GetStatus() (string, error) {
 
 			// Not busy. Check for prompt indicators to distinguish YELLOW vs fall-through.
 			hasPrompt := s.hasPromptIndicator(content)
+
+			// A prompt is only ready if the ready probe agrees: the tool is
+			// still coming up, or has died behind a stale screen
+			if hasPrompt && probeErr != nil {
+				s.noteStatusSignal(ReasonReadyProbe, 0.8, "", probeErr.Error())
+				s.resetPromptNoBusyHoldLocked()
+				statusLog.Debug("prompt_ready_probe_failed", slog.String("session", shortName), slog.String("error", probeErr.Error()))
+				if s.inStartupWindowLocked() {
+					s.lastStableStatus = "starting"
+					return "starting", nil
+				}
+				s.lastStableStatus = "inactive"
+				return "inactive", nil
+			}
+			if s.fusedActiveLocked(fusionInput{
+				captured: true, content: content, prompt: hasPrompt,
+				activityTS: currentTS, cpuPercent: cpuPercent, cpuKnown: cpuKnown,
+			}) {
+				statusLog.Debug("fusion_active", slog.String("session", shortName))
+				return s.setFusedActiveLocked(currentTS), nil
+			}
 			if hasPrompt {
 				// Respect acknowledgment: if user already acknowledged (e.g. by attaching),
 				// keep idle status. The prompt is still visible but the user is looking at it.
@@ -1789,6 +1898,17 @@ func (s *Session) GetStatus() (string, error) {
 				return "waiting", nil
 			}
 
+			// Neither busy nor prompt: ask an augment classifier
+			if classifier != nil && classifier.augments() {
+				s.mu.Unlock()
+				verdict = s.classifyPane(classifier, content)
+				s.mu.Lock()
+				if verdict != nil {
+					statusLog.Debug("classifier_verdict", slog.String("session", shortName), slog.String("state", verdict.State))
+					return s.applyVerdictLocked(verdict, currentTS), nil
+				}
+			}
+
 			// During startup there may be a long period with neither spinner nor prompt.
 			// Keep this as STARTING to avoid premature waiting/idle transitions.
 			if s.inStartupWindowLocked() {
@@ -1865,8 +1985,9 @@ func (s *Session) GetStatus() (string, error) {
 			// BUT we must confirm with content check (fixes cursor blink false positives)
 			if s.stateTracker.activityChangeCount >= 2 {
 				// Gate the spike: confirm with content check before setting GREEN
+				capOpts := s.captureOptionsLocked()
 				s.mu.Unlock()
-				content, captureErr := s.CapturePane()
+				content, captureErr := s.captureForStatus(capOpts)
 				s.mu.Lock()
 
 				if captureErr == nil {
@@ -1968,9 +2089,25 @@ func (s *Session) GetStatus() (string, error) {
 	// verify before transitioning away from GREEN - the session might still be busy
 	if s.lastStableStatus == "active" && !needsBusyCheck {
 		// Re-check busy indicator before dropping out of GREEN
+		capOpts := s.captureOptionsLocked()
+		classifier := s.classifier
+		fusionCPU := s.fusionCPULocked()
 		s.mu.Unlock()
-		content, captureErr := s.CapturePane()
+		content, captureErr := s.captureForStatus(capOpts)
+		var verdict *ClassifierVerdict
+		if captureErr == nil && classifier != nil && !classifier.augments() {
+			verdict = s.classifyPane(classifier, content)
+		}
+		var cpuPercent float64
+		var cpuKnown bool
+		if fusionCPU != nil {
+			cpuPercent, cpuKnown = s.paneCPUPercent()
+		}
 		s.mu.Lock()
+		if verdict != nil {
+			statusLog.Debug("classifier_recheck", slog.String("session", shortName), slog.String("state", verdict.State))
+			return s.applyVerdictLocked(verdict, currentTS), nil
+		}
 		if captureErr == nil && s.hasBusyIndicator(content) {
 			// Busy indicator is authoritative (includes spinner grace period).
 			s.resetPromptNoBusyHoldLocked()
@@ -1978,7 +2115,17 @@ func (s *Session) GetStatus() (string, error) {
 			statusLog.Debug("still_busy", slog.String("session", shortName))
 			return "active", nil
 		}
-		if captureErr == nil && s.hasPromptIndicator(content) {
+		hasPrompt := captureErr == nil && s.hasPromptIndicator(content)
+		// Silent work (a long tool call) shows no busy pattern; the other
+		// signals may still add up to active
+		if s.fusedActiveLocked(fusionInput{
+			captured: captureErr == nil, content: content, prompt: hasPrompt,
+			activityTS: currentTS, cpuPercent: cpuPercent, cpuKnown: cpuKnown,
+		}) {
+			statusLog.Debug("fusion_still_active", slog.String("session", shortName))
+			return s.setFusedActiveLocked(currentTS), nil
+		}
+		if hasPrompt {
 			// Not busy, but prompt visible. Transition to waiting/idle.
 			if !s.stateTracker.acknowledged {
 				if s.shouldHoldActiveOnPromptLocked() {
@@ -2004,6 +2151,25 @@ func (s *Session) GetStatus() (string, error) {
 			return "idle", nil
 		}
 		statusLog.Debug("no_longer_busy", slog.String("session", shortName))
+	} else if fusionCPU := s.fusionCPULocked(); fusionCPU != nil && !needsBusyCheck {
+		// Nothing moved on screen, but the pane's processes may be working:
+		// read the pane only when they are
+		capOpts := s.captureOptionsLocked()
+		s.mu.Unlock()
+		in := fusionInput{activityTS: currentTS}
+		in.cpuPercent, in.cpuKnown = s.paneCPUPercent()
+		if in.cpuKnown && in.cpuPercent >= fusionCPU.cpuBusyPercent() {
+			if content, err := s.captureForStatus(capOpts); err == nil {
+				in.captured, in.content = true, content
+				in.busy = s.hasBusyIndicator(content)
+				in.prompt = !in.busy && s.hasPromptIndicator(content)
+			}
+		}
+		s.mu.Lock()
+		if s.fusedActiveLocked(in) {
+			statusLog.Debug("fusion_quiet_active", slog.String("session", shortName))
+			return s.setFusedActiveLocked(currentTS), nil
+		}
 	}
 
 	// No busy indicator found - check acknowledged state
@@ -2031,6 +2197,34 @@ func (s *Session) GetStatus() (string, error) {
 	return "waiting", nil
 }
 
+// setFusedActiveLocked records an active verdict from signal fusion the way
+// a busy indicator is recorded. MUST be called with s.mu held.
+func (s *Session) setFusedActiveLocked(currentTS int64) string {
+	s.ensureStateTrackerLocked()
+	s.stateTracker.lastChangeTime = time.Now()
+	s.stateTracker.acknowledged = false
+	s.resetPromptNoBusyHoldLocked()
+	s.stateTracker.lastActivityTimestamp = currentTS
+	s.lastStableStatus = "active"
+	s.startupAt = time.Time{}
+	return "active"
+}
+
+// takeBellLocked reports whether the session rang the terminal bell since
+// the last call. MUST be called with s.mu held.
+func (s *Session) takeBellLocked() bool {
+	pm := GetPipeManager()
+	if pm == nil {
+		return false
+	}
+	rang := pm.LastBellTime(s.Name)
+	if !rang.After(s.lastBellSeen) {
+		return false
+	}
+	s.lastBellSeen = rang
+	return true
+}
+
 // getStatusFallback uses content-hash based detection as fallback
 // when activity timestamp detection fails
 func (s *Session) getStatusFallback() (string, error) {
@@ -2039,7 +2233,10 @@ func (s *Session) getStatusFallback() (string, error) {
 		shortName = shortName[:12]
 	}
 
-	content, err := s.CapturePane()
+	s.mu.Lock()
+	capOpts := s.captureOptionsLocked()
+	s.mu.Unlock()
+	content, err := s.captureForStatus(capOpts)
 	if err != nil {
 		if errors.Is(err, ErrCaptureTimeout) {
 			// Timeout: preserve previous state instead of going inactive
@@ -2047,6 +2244,7 @@ func (s *Session) getStatusFallback() (string, error) {
 			prev := s.lastStableStatus
 			s.mu.Unlock()
 			if prev != "" {
+				s.noteStatusSignal(ReasonCaptureTimeout, 0.4, "")
 				statusLog.Debug("fallback_timeout_preserve", slog.String("session", shortName), slog.String("status", prev))
 				return prev, nil
 			}
@@ -2294,6 +2492,10 @@ func inferToolFromSessionFields(detected, custom, command string) string {
 		return "opencode"
 	case strings.Contains(cmd, "codex"):
 		return "codex"
+	case strings.Contains(cmd, "ollama"):
+		return "ollama"
+	case strings.Contains(cmd, "aider"):
+		return "aider"
 	default:
 		return ""
 	}
@@ -2387,8 +2589,14 @@ func (s *Session) hasBusyIndicatorResolved(content string) bool {
 		spinnerChars = patterns.SpinnerChars
 	}
 
+	var capOpts CaptureOptions
+	if patterns != nil {
+		capOpts = patterns.Capture
+		content = patterns.Sanitize(content)
+	}
+
 	// Find spinner in terminal content
-	char, spinnerLine, found := findSpinnerInContent(content, spinnerChars)
+	char, spinnerLine, found := findSpinnerInContent(capOpts.plainText(content), spinnerChars)
 
 	// Get or create spinner tracker
 	s.ensureStateTrackerLocked()
@@ -2397,11 +2605,26 @@ func (s *Session) hasBusyIndicatorResolved(content string) bool {
 	// BusyPatterns (regex + string) are authoritative because they capture
 	// real active-line semantics for each tool.
 	if patterns != nil {
-		recentLines := lastNLines(content, 25)
-		recentContent := strings.Join(recentLines, "\n")
+		recentLines := lastNLines(content, capOpts.lines())
+		recentContent := capOpts.matchText(strings.Join(recentLines, "\n"))
+		if len(patterns.BusyRules) > 0 {
+			rule := decideRules(patterns.BusyRules, strings.Split(recentContent, "\n"))
+			matched, vetoed := ruleVerdict(rule)
+			if matched {
+				tracker.MarkBusy()
+				s.noteStatusSignal(ReasonBusyPattern, 0.9, "active", rule.matchedLine(strings.Split(recentContent, "\n")))
+				statusLog.Debug("busy_rule_match", slog.String("session", shortName), slog.String("pattern", rule.Source))
+				return true
+			}
+			if vetoed {
+				statusLog.Debug("busy_rule_veto", slog.String("session", shortName), slog.String("pattern", rule.Source))
+				return false
+			}
+		}
 		for _, re := range patterns.BusyRegexps {
-			if re.MatchString(recentContent) {
+			if loc := re.FindStringIndex(recentContent); loc != nil {
 				tracker.MarkBusy()
+				s.noteStatusSignal(ReasonBusyPattern, 0.9, "active", lineAt(recentContent, loc[0]))
 				statusLog.Debug("busy_pattern_match", slog.String("session", shortName), slog.String("pattern", re.String()))
 				return true
 			}
@@ -2420,6 +2643,7 @@ func (s *Session) hasBusyIndicatorResolved(content string) bool {
 				continue
 			}
 			tracker.MarkBusy()
+			s.noteStatusSignal(ReasonBusyPattern, 0.9, "active", lineContaining(recentLines, lowerStr))
 			statusLog.Debug("busy_string_match", slog.String("session", shortName), slog.String("pattern", str))
 			return true
 		}
@@ -2434,6 +2658,11 @@ func (s *Session) hasBusyIndicatorResolved(content string) bool {
 		hasActiveContext := strings.Contains(lineClean, "…") || strings.Contains(lineLower, "interrupt")
 		if !isClaude || isBrailleSpinnerChar(char) || hasActiveContext {
 			tracker.MarkBusy()
+			confidence := 0.8
+			if !isClaude && !isBrailleSpinnerChar(char) {
+				confidence = 0.7
+			}
+			s.noteStatusSignal(ReasonSpinner, confidence, "active", lineClean)
 			statusLog.Debug("busy_spinner_found", slog.String("session", shortName), slog.String("char", char))
 			return true
 		}
@@ -2445,6 +2674,7 @@ func (s *Session) hasBusyIndicatorResolved(content string) bool {
 	// No busy signal. Check grace period: between tool calls the spinner
 	// briefly disappears. If it was visible recently, stay busy.
 	if tracker.InGracePeriod() {
+		s.noteStatusSignal(ReasonBusyGrace, 0.6, "active")
 		statusLog.Debug("busy_spinner_grace", slog.String("session", shortName),
 			slog.Duration("since_busy", time.Since(tracker.lastBusyTime)))
 		return true
@@ -2468,19 +2698,38 @@ func (s *Session) hasPromptIndicator(content string) bool {
 		patterns = defaultResolvedPatternsForTool(tool)
 	}
 
+	var capOpts CaptureOptions
+	if patterns != nil {
+		capOpts = patterns.Capture
+		content = patterns.Sanitize(content)
+	}
+
 	// Configured prompt patterns are checked first so custom tool definitions and
 	// per-tool overrides can participate in waiting-state detection.
 	if patterns != nil {
-		recentLines := lastNLines(content, 25)
-		recentContent := strings.Join(recentLines, "\n")
+		recentLines := lastNLines(content, capOpts.lines())
+		recentContent := capOpts.matchText(strings.Join(recentLines, "\n"))
+		if len(patterns.PromptRules) > 0 {
+			rule := decideRules(patterns.PromptRules, strings.Split(recentContent, "\n"))
+			matched, vetoed := ruleVerdict(rule)
+			if matched {
+				s.noteStatusSignal(ReasonPromptPattern, 0.9, "prompt", rule.matchedLine(strings.Split(recentContent, "\n")))
+				return true
+			}
+			if vetoed {
+				return false
+			}
+		}
 		for _, re := range patterns.PromptRegexps {
-			if re.MatchString(recentContent) {
+			if loc := re.FindStringIndex(recentContent); loc != nil {
+				s.noteStatusSignal(ReasonPromptPattern, 0.9, "prompt", lineAt(recentContent, loc[0]))
 				return true
 			}
 		}
 		lowerContent := strings.ToLower(recentContent)
 		for _, str := range patterns.PromptStrings {
 			if strings.Contains(lowerContent, strings.ToLower(str)) {
+				s.noteStatusSignal(ReasonPromptPattern, 0.9, "prompt", lineContaining(recentLines, strings.ToLower(str)))
 				return true
 			}
 		}
@@ -2493,7 +2742,12 @@ func (s *Session) hasPromptIndicator(content string) bool {
 		s.cachedPromptDetector = NewPromptDetector(tool)
 		s.cachedPromptDetectorTool = tool
 	}
-	return s.cachedPromptDetector.HasPrompt(content)
+	plain := capOpts.plainText(content)
+	if !s.cachedPromptDetector.HasPrompt(plain) {
+		return false
+	}
+	s.noteStatusSignal(ReasonPrompt, 0.85, "prompt", lastNonEmptyLine(plain))
+	return true
 }
 
 // lastNLines splits content into lines, trims trailing blank lines, and returns
@@ -2714,14 +2968,14 @@ func (s *Session) SendKeys(keys string) error {
 	// The -l flag makes tmux treat the string as literal text, not key names
 	// This prevents issues like "Enter" being interpreted as the Enter key
 	// and provides a layer of safety against tmux special sequences
-	cmd := exec.Command("tmux", "send-keys", "-l", "-t", s.Name, "--", keys)
+	cmd := Command("send-keys", "-l", "-t", s.Name, "--", escapeTmuxArg(keys))
 	return cmd.Run()
 }
 
 // SendEnter sends an Enter key to the tmux session
 func (s *Session) SendEnter() error {
 	s.invalidateCache()
-	cmd := exec.Command("tmux", "send-keys", "-t", s.Name, "Enter")
+	cmd := Command("send-keys", "-t", s.Name, "Enter")
 	return cmd.Run()
 }
 
@@ -2743,27 +2997,16 @@ func (s *Session) SendKeysAndEnter(keys string) error {
 	return s.SendEnter()
 }
 
-// SendKeysChunked sends large content to the tmux session in chunks to avoid
-// tmux/OS buffer limits. Content ≤4KB is sent directly via SendKeys.
-// Larger content is split at newline boundaries with a short delay between chunks.
+// SendKeysChunked delivers a prompt to the tmux session. The text is
+// sanitized first (see sanitizePayload). Short single-line text goes through
+// send-keys; multi-line or large text is pasted from a tmux buffer in chunks
+// (see pasteText), so tmux never parses it as keys or commands.
 func (s *Session) SendKeysChunked(content string) error {
-	const chunkSize = 4096
-	const chunkDelay = 50 * time.Millisecond
-
-	if len(content) <= chunkSize {
+	content = sanitizePayload(content)
+	if len(content) <= sendKeysMaxBytes && !strings.Contains(content, "\n") {
 		return s.SendKeys(content)
 	}
-
-	chunks := splitIntoChunks(content, chunkSize)
-	for i, chunk := range chunks {
-		if err := s.SendKeys(chunk); err != nil {
-			return fmt.Errorf("failed to send chunk %d/%d: %w", i+1, len(chunks), err)
-		}
-		if i < len(chunks)-1 {
-			time.Sleep(chunkDelay)
-		}
-	}
-	return nil
+	return s.pasteText(content)
 }
 
 // splitIntoChunks splits content into chunks of at most maxSize bytes,
@@ -2802,17 +3045,28 @@ func splitIntoChunks(content string, maxSize int) []string {
 	return chunks
 }
 
+// SendNamedKeys presses keys given as tmux key names (e.g. "S-Tab",
+// "Escape", "C-o"), one send-keys call for all of them
+func (s *Session) SendNamedKeys(keys ...string) error {
+	s.invalidateCache()
+	args := append([]string{"send-keys", "-t", s.Name, "--"}, keys...)
+	if out, err := Command(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 // SendCtrlC sends Ctrl+C (interrupt signal) to the tmux session
 func (s *Session) SendCtrlC() error {
 	s.invalidateCache()
-	cmd := exec.Command("tmux", "send-keys", "-t", s.Name, "C-c")
+	cmd := Command("send-keys", "-t", s.Name, "C-c")
 	return cmd.Run()
 }
 
 // SendCtrlU sends Ctrl+U (clear line) to the tmux session
 func (s *Session) SendCtrlU() error {
 	s.invalidateCache()
-	cmd := exec.Command("tmux", "send-keys", "-t", s.Name, "C-u")
+	cmd := Command("send-keys", "-t", s.Name, "C-u")
 	return cmd.Run()
 }
 
@@ -2983,7 +3237,7 @@ func (s *Session) GetWorkDir() string {
 		return ""
 	}
 
-	cmd := exec.Command("tmux", "display-message", "-t", s.Name, "-p", "#{pane_current_path}")
+	cmd := Command("display-message", "-t", s.Name, "-p", "#{pane_current_path}")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -2993,7 +3247,7 @@ func (s *Session) GetWorkDir() string {
 
 // ListAllSessions returns all Agent Deck tmux sessions
 func ListAllSessions() ([]*Session, error) {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
+	cmd := Command("list-sessions", "-F", "#{session_name}")
 	output, err := cmd.Output()
 	if err != nil {
 		// No sessions exist
@@ -3016,7 +3270,7 @@ func ListAllSessions() ([]*Session, error) {
 				DisplayName: displayName,
 			}
 			// Try to get working directory
-			workDirCmd := exec.Command("tmux", "display-message", "-t", line, "-p", "#{pane_current_path}")
+			workDirCmd := Command("display-message", "-t", line, "-p", "#{pane_current_path}")
 			if workDirOutput, err := workDirCmd.Output(); err == nil {
 				sess.WorkDir = strings.TrimSpace(string(workDirOutput))
 			}
@@ -3200,7 +3454,7 @@ func RunLogMaintenance(maxSizeMB int, maxLines int, removeOrphans bool) {
 // those in the current profile. This ensures consistent notification bars
 // when users switch between sessions.
 func ListAgentDeckSessions() ([]string, error) {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
+	cmd := Command("list-sessions", "-F", "#{session_name}")
 	output, err := cmd.Output()
 	if err != nil {
 		// No sessions exist
@@ -3228,7 +3482,7 @@ func ListAgentDeckSessions() ([]string, error) {
 func SetStatusLeft(sessionName, text string) error {
 	// Escape single quotes for tmux by replacing ' with '\''
 	escaped := strings.ReplaceAll(text, "'", "'\\''")
-	cmd := exec.Command("tmux", "set-option", "-t", sessionName, "status-left", escaped)
+	cmd := Command("set-option", "-t", sessionName, "status-left", escaped)
 	return cmd.Run()
 }
 
@@ -3236,7 +3490,7 @@ func SetStatusLeft(sessionName, text string) error {
 // Called when notifications are cleared or acknowledged.
 func ClearStatusLeft(sessionName string) error {
 	// -u flag unsets the option, reverting to tmux default
-	cmd := exec.Command("tmux", "set-option", "-t", sessionName, "-u", "status-left")
+	cmd := Command("set-option", "-t", sessionName, "-u", "status-left")
 	return cmd.Run()
 }
 
@@ -3245,13 +3499,13 @@ func ClearStatusLeft(sessionName string) error {
 // All agentdeck sessions inherit this global setting.
 func SetStatusLeftGlobal(text string) error {
 	escaped := strings.ReplaceAll(text, "'", "'\\''")
-	cmd := exec.Command("tmux", "set-option", "-g", "status-left", escaped)
+	cmd := Command("set-option", "-g", "status-left", escaped)
 	return cmd.Run()
 }
 
 // ClearStatusLeftGlobal resets status-left to default globally.
 func ClearStatusLeftGlobal() error {
-	cmd := exec.Command("tmux", "set-option", "-gu", "status-left")
+	cmd := Command("set-option", "-gu", "status-left")
 	return cmd.Run()
 }
 
@@ -3261,7 +3515,7 @@ func ClearStatusLeftGlobal() error {
 func InitializeStatusBarOptions() error {
 	// Set adequate status-left-length globally (default is only 10 chars!)
 	// This ensures the notification bar content is not truncated
-	return exec.Command("tmux", "set-option", "-g", "status-left-length", "120").Run()
+	return Command("set-option", "-g", "status-left-length", "120").Run()
 }
 
 // RefreshStatusBarImmediate forces an immediate status bar redraw for ALL connected clients.
@@ -3270,7 +3524,7 @@ func InitializeStatusBarOptions() error {
 // Filters out control mode clients (from PipeManager) which don't have a visible status bar.
 func RefreshStatusBarImmediate() error {
 	// Get all connected clients, filtering out control mode clients
-	cmd := exec.Command("tmux", "list-clients", "-F", "#{client_name}\t#{client_control_mode}")
+	cmd := Command("list-clients", "-F", "#{client_name}\t#{client_control_mode}")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil
@@ -3285,7 +3539,7 @@ func RefreshStatusBarImmediate() error {
 		if parts[1] == "1" {
 			continue
 		}
-		_ = exec.Command("tmux", "refresh-client", "-S", "-t", parts[0]).Run()
+		_ = Command("refresh-client", "-S", "-t", parts[0]).Run()
 	}
 	return nil
 }
@@ -3294,7 +3548,7 @@ func RefreshStatusBarImmediate() error {
 // Used to detect which session the user is currently viewing.
 // Filters out control mode clients (from PipeManager) which are not real user sessions.
 func GetAttachedSessions() ([]string, error) {
-	cmd := exec.Command("tmux", "list-clients", "-F", "#{session_name}\t#{client_control_mode}")
+	cmd := Command("list-clients", "-F", "#{session_name}\t#{client_control_mode}")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -3320,7 +3574,7 @@ func GetAttachedSessions() ([]string, error) {
 // The key should be a single character like "1", "2", etc.
 // Deprecated: Use BindSwitchKeyWithAck for notification bar integration.
 func BindSwitchKey(key, targetSession string) error {
-	cmd := exec.Command("tmux", "bind-key", key, "switch-client", "-t", targetSession)
+	cmd := Command("bind-key", key, "switch-client", "-t", targetSession)
 	return cmd.Run()
 }
 
@@ -3340,17 +3594,17 @@ func BindSwitchKeyWithAck(key, targetSession, sessionID string) error {
 	// 2. Switches to the target session
 	script := fmt.Sprintf("echo '%s' > '%s' && tmux switch-client -t '%s'",
 		sessionID, signalFile, targetSession)
-	cmd := exec.Command("tmux", "bind-key", key, "run-shell", script)
+	cmd := Command("bind-key", key, "run-shell", script)
 	return cmd.Run()
 }
 
 // GetAckSignalPath returns the path to the acknowledgment signal file
 func GetAckSignalPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	deckDir, err := deck.Home()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(homeDir, ".agent-deck", "ack-signal"), nil
+	return filepath.Join(deckDir, "ack-signal"), nil
 }
 
 // ReadAndClearAckSignal reads the session ID from the signal file and deletes it.
@@ -3378,18 +3632,18 @@ func ReadAndClearAckSignal() string {
 // without windows (e.g., CI) and agent-deck rebinds keys every 2s anyway.
 func UnbindKey(key string) error {
 	// First unbind our custom binding
-	_ = exec.Command("tmux", "unbind-key", key).Run()
+	_ = Command("unbind-key", key).Run()
 
 	// Best-effort restore default: number keys select windows
 	// bind-key 1 select-window -t :1
-	_ = exec.Command("tmux", "bind-key", key, "select-window", "-t", ":"+key).Run()
+	_ = Command("bind-key", key, "select-window", "-t", ":"+key).Run()
 	return nil
 }
 
 // GetActiveSession returns the session name the user is currently attached to.
 // Returns empty string and error if not attached to any session.
 func GetActiveSession() (string, error) {
-	cmd := exec.Command("tmux", "display-message", "-p", "#{client_session}")
+	cmd := Command("display-message", "-p", "#{client_session}")
 	out, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -3401,7 +3655,7 @@ func GetActiveSession() (string, error) {
 
 // DiscoverAllTmuxSessions returns all tmux sessions (including non-Agent Deck ones)
 func DiscoverAllTmuxSessions() ([]*Session, error) {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}:#{pane_current_path}")
+	cmd := Command("list-sessions", "-F", "#{session_name}:#{pane_current_path}")
 	output, err := cmd.Output()
 	if err != nil {
 		// No sessions exist
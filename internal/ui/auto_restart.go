@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+const (
+	// autoRestartGrace is how long a dead session waits before it is restarted.
+	// A stop from the CLI saves storage, and the reload that follows replaces
+	// the instance, which cancels the restart.
+	autoRestartGrace = 5 * time.Second
+
+	// autoRestartBackoff is the minimum time between restarts of one session,
+	// so a tool that dies on startup is not restarted in a loop
+	autoRestartBackoff = 5 * time.Minute
+)
+
+// autoRestartTracker finds sessions the TUI saw die, for the experimental
+// auto-restart feature flag. Only a live -> error transition on the same
+// Instance counts: sessions loaded already stopped are never restarted.
+type autoRestartTracker struct {
+	live        map[*session.Instance]bool
+	diedAt      map[*session.Instance]time.Time
+	lastRestart map[string]time.Time
+}
+
+func newAutoRestartTracker() *autoRestartTracker {
+	return &autoRestartTracker{
+		live:        make(map[*session.Instance]bool),
+		diedAt:      make(map[*session.Instance]time.Time),
+		lastRestart: make(map[string]time.Time),
+	}
+}
+
+// observe records the current statuses and returns the sessions that died at
+// least autoRestartGrace ago, have auto-restart enabled and were not
+// restarted within autoRestartBackoff. When paused (maintenance window) dead
+// sessions are forgotten instead.
+func (t *autoRestartTracker) observe(instances []*session.Instance, now time.Time, paused bool) []*session.Instance {
+	seen := make(map[*session.Instance]bool, len(instances))
+	var due []*session.Instance
+	for _, inst := range instances {
+		seen[inst] = true
+		switch inst.GetStatusThreadSafe() {
+		case session.StatusRunning, session.StatusWaiting, session.StatusIdle, session.StatusThrottled:
+			t.live[inst] = true
+			delete(t.diedAt, inst)
+			continue
+		case session.StatusError:
+		default:
+			continue
+		}
+		if t.live[inst] {
+			delete(t.live, inst)
+			t.diedAt[inst] = now
+			continue
+		}
+		died, ok := t.diedAt[inst]
+		if !ok || now.Sub(died) < autoRestartGrace {
+			continue
+		}
+		delete(t.diedAt, inst)
+		if paused || now.Sub(t.lastRestart[inst.ID]) < autoRestartBackoff {
+			continue
+		}
+		if !inst.FeatureEnabled(session.FeatureAutoRestart) || !inst.CanRestart() {
+			continue
+		}
+		t.lastRestart[inst.ID] = now
+		due = append(due, inst)
+	}
+	// Instances replaced by a storage reload are gone for good
+	for inst := range t.live {
+		if !seen[inst] {
+			delete(t.live, inst)
+		}
+	}
+	for inst := range t.diedAt {
+		if !seen[inst] {
+			delete(t.diedAt, inst)
+		}
+	}
+	return due
+}
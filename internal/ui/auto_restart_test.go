@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestAutoRestartTracker(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".agent-deck")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("[features]\nauto-restart = true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	session.ClearUserConfigCache()
+	defer session.ClearUserConfigCache()
+
+	crashed := session.NewInstance("crashed", t.TempDir())
+	crashed.Tool = "opencode" // always restartable
+	crashed.SetStatusThreadSafe(session.StatusRunning)
+	stopped := session.NewInstance("stopped", t.TempDir())
+	stopped.Tool = "opencode"
+	stopped.SetStatusThreadSafe(session.StatusError)
+	instances := []*session.Instance{crashed, stopped}
+
+	tr := newAutoRestartTracker()
+	now := time.Now()
+	if due := tr.observe(instances, now, false); len(due) != 0 {
+		t.Fatalf("nothing died yet, got %d due", len(due))
+	}
+
+	crashed.SetStatusThreadSafe(session.StatusError)
+	if due := tr.observe(instances, now.Add(2*time.Second), false); len(due) != 0 {
+		t.Fatal("restart must wait for the grace period")
+	}
+	due := tr.observe(instances, now.Add(2*time.Second+autoRestartGrace), false)
+	if len(due) != 1 || due[0] != crashed {
+		t.Fatalf("expected only the crashed session, got %v", due)
+	}
+
+	// Dies again right after the restart: backoff applies
+	crashed.SetStatusThreadSafe(session.StatusRunning)
+	tr.observe(instances, now.Add(time.Minute), false)
+	crashed.SetStatusThreadSafe(session.StatusError)
+	tr.observe(instances, now.Add(time.Minute), false)
+	if due := tr.observe(instances, now.Add(2*time.Minute), false); len(due) != 0 {
+		t.Fatal("second restart within the backoff should be skipped")
+	}
+}
+
+func TestAutoRestartTracker_ReloadAndMaintenance(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".agent-deck")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("[features]\nauto-restart = true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	session.ClearUserConfigCache()
+	defer session.ClearUserConfigCache()
+
+	inst := session.NewInstance("a", t.TempDir())
+	inst.Tool = "opencode"
+	inst.SetStatusThreadSafe(session.StatusRunning)
+	tr := newAutoRestartTracker()
+	now := time.Now()
+	tr.observe([]*session.Instance{inst}, now, false)
+	inst.SetStatusThreadSafe(session.StatusError)
+	tr.observe([]*session.Instance{inst}, now, false)
+
+	// A CLI stop saves storage and the reload replaces the instance
+	reloaded := session.NewInstance("a", t.TempDir())
+	reloaded.ID = inst.ID
+	reloaded.Tool = "opencode"
+	reloaded.SetStatusThreadSafe(session.StatusError)
+	if due := tr.observe([]*session.Instance{reloaded}, now.Add(time.Minute), false); len(due) != 0 {
+		t.Fatal("a reloaded, already stopped session must not be restarted")
+	}
+
+	other := session.NewInstance("b", t.TempDir())
+	other.Tool = "opencode"
+	other.SetStatusThreadSafe(session.StatusRunning)
+	tr.observe([]*session.Instance{other}, now, false)
+	other.SetStatusThreadSafe(session.StatusError)
+	tr.observe([]*session.Instance{other}, now, false)
+	if due := tr.observe([]*session.Instance{other}, now.Add(time.Minute), true); len(due) != 0 {
+		t.Fatal("no restarts during a maintenance window")
+	}
+	if due := tr.observe([]*session.Instance{other}, now.Add(2*time.Minute), false); len(due) != 0 {
+		t.Fatal("sessions that died during maintenance are forgotten")
+	}
+}
@@ -18,6 +18,7 @@ const (
 	ConfirmQuitWithPool
 	ConfirmCreateDirectory
 	ConfirmInstallHooks
+	ConfirmEmergencyResume
 )
 
 // ConfirmDialog handles confirmation for destructive actions
@@ -89,6 +90,14 @@ func (c *ConfirmDialog) ShowInstallHooks() {
 	c.targetName = ""
 }
 
+// ShowEmergencyResume shows confirmation for lifting an emergency stop
+func (c *ConfirmDialog) ShowEmergencyResume() {
+	c.visible = true
+	c.confirmType = ConfirmEmergencyResume
+	c.targetID = ""
+	c.targetName = ""
+}
+
 // GetPendingSession returns the pending session creation data
 func (c *ConfirmDialog) GetPendingSession() (name, path, command, groupPath string, toolOptionsJSON json.RawMessage) {
 	return c.pendingSessionName, c.pendingSessionPath, c.pendingSessionCommand, c.pendingSessionGroupPath, c.pendingToolOptionsJSON
@@ -260,6 +269,29 @@ func (c *ConfirmDialog) View() string {
 			Foreground(ColorTextDim).
 			Render("(Esc to skip)")
 		buttons = lipgloss.JoinHorizontal(lipgloss.Center, buttonYes, "  ", buttonNo, "  ", escHint)
+
+	case ConfirmEmergencyResume:
+		title = "🛑  Resume the Deck?"
+		warning = "The deck is emergency-stopped."
+		details = "• Sessions will accept messages again\n• Heartbeats and queued conductor tasks resume\n• Auto-restarts resume"
+		borderColor = ColorRed
+
+		buttonYes := lipgloss.NewStyle().
+			Foreground(ColorBg).
+			Background(ColorGreen).
+			Padding(0, 2).
+			Bold(true).
+			Render("y Resume")
+		buttonNo := lipgloss.NewStyle().
+			Foreground(ColorBg).
+			Background(ColorRed).
+			Padding(0, 2).
+			Bold(true).
+			Render("n Stay stopped")
+		escHint := lipgloss.NewStyle().
+			Foreground(ColorTextDim).
+			Render("(Esc to cancel)")
+		buttons = lipgloss.JoinHorizontal(lipgloss.Center, buttonYes, "  ", buttonNo, "  ", escHint)
 	}
 
 	// Title style
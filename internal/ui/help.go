@@ -126,6 +126,7 @@ func (h *HelpOverlay) View() string {
 				{"F", "Fork with options (Claude only)"},
 				{"c", "Copy output to clipboard"},
 				{"x", "Send output to session"},
+				{"X", "Run tool macro (compact, model, ...)"},
 			},
 		},
 		{
@@ -148,6 +149,7 @@ func (h *HelpOverlay) View() string {
 			title: "SEARCH & FILTER",
 			items: [][2]string{
 				{"/", "Open search"},
+				{"G", "Search session history"},
 				{"/waiting", "Filter waiting"},
 				{"/running", "Filter running"},
 				{"/idle", "Filter idle"},
@@ -158,6 +160,7 @@ func (h *HelpOverlay) View() string {
 			items: [][2]string{
 				{"S", "Settings"},
 				{"Ctrl+R", "Reload from disk"},
+				{"Ctrl+X", "Emergency stop / resume"},
 				{"i", "Import tmux sessions"},
 				{"Ctrl+Q", "Detach from session"},
 				{"q", "Quit"},
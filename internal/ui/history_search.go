@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// historySearchLimit caps the matches a history search shows
+const historySearchLimit = 100
+
+// historySearchDebounceMsg fires after the debounce interval
+type historySearchDebounceMsg struct {
+	query string
+}
+
+// historySearchResultsMsg delivers async history search matches
+type historySearchResultsMsg struct {
+	query   string
+	matches []session.HistoryMatch
+}
+
+// HistorySearch is the overlay searching the history of every session:
+// transcripts and Claude conversations (see session.SearchHistory)
+type HistorySearch struct {
+	input     textinput.Model
+	files     []session.HistoryFile
+	matches   []session.HistoryMatch
+	cursor    int
+	width     int
+	height    int
+	visible   bool
+	searching bool
+	query     string // Query the matches are for
+	cancel    context.CancelFunc
+}
+
+// NewHistorySearch creates a new history search overlay
+func NewHistorySearch() *HistorySearch {
+	ti := textinput.New()
+	ti.Placeholder = "Search session output and conversations..."
+	ti.CharLimit = 100
+	ti.Width = 60
+
+	return &HistorySearch{input: ti}
+}
+
+// SetSize sets the dimensions of the overlay
+func (hs *HistorySearch) SetSize(width, height int) {
+	hs.width = width
+	hs.height = height
+}
+
+// Show makes the overlay visible, searching the history of instances
+func (hs *HistorySearch) Show(instances []*session.Instance) {
+	hs.visible = true
+	hs.files = session.HistoryFiles(instances)
+	hs.input.Focus()
+	hs.input.SetValue("")
+	hs.matches = nil
+	hs.cursor = 0
+	hs.query = ""
+	hs.searching = false
+}
+
+// Hide hides the overlay and stops a running search
+func (hs *HistorySearch) Hide() {
+	hs.visible = false
+	hs.input.Blur()
+	if hs.cancel != nil {
+		hs.cancel()
+		hs.cancel = nil
+	}
+}
+
+// IsVisible returns whether the overlay is visible
+func (hs *HistorySearch) IsVisible() bool {
+	return hs.visible
+}
+
+// Selected returns the selected match, or nil
+func (hs *HistorySearch) Selected() *session.HistoryMatch {
+	if hs.cursor >= len(hs.matches) {
+		return nil
+	}
+	return &hs.matches[hs.cursor]
+}
+
+// Update handles messages for the overlay
+func (hs *HistorySearch) Update(msg tea.Msg) (*HistorySearch, tea.Cmd) {
+	if !hs.visible {
+		return hs, nil
+	}
+
+	switch msg := msg.(type) {
+	case historySearchDebounceMsg:
+		if msg.query != hs.input.Value() {
+			return hs, nil
+		}
+		if hs.cancel != nil {
+			hs.cancel()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		hs.cancel = cancel
+		files := hs.files
+		return hs, func() tea.Msg {
+			matches := session.SearchHistory(ctx, files, session.HistoryQuery{Text: msg.query, Limit: historySearchLimit})
+			return historySearchResultsMsg{query: msg.query, matches: matches}
+		}
+
+	case historySearchResultsMsg:
+		if msg.query == hs.input.Value() {
+			hs.searching = false
+			hs.query = msg.query
+			hs.matches = msg.matches
+			hs.cursor = 0
+		}
+		return hs, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "ctrl+k":
+			if hs.cursor > 0 {
+				hs.cursor--
+			}
+			return hs, nil
+		case "down", "ctrl+j":
+			if hs.cursor < len(hs.matches)-1 {
+				hs.cursor++
+			}
+			return hs, nil
+		}
+
+		var cmd tea.Cmd
+		hs.input, cmd = hs.input.Update(msg)
+		query := hs.input.Value()
+		if strings.TrimSpace(query) == "" {
+			hs.matches = nil
+			hs.query = ""
+			hs.searching = false
+			return hs, cmd
+		}
+		// Debounce: each search reads every history file
+		hs.searching = true
+		debounce := tea.Tick(300*time.Millisecond, func(time.Time) tea.Msg {
+			return historySearchDebounceMsg{query: query}
+		})
+		return hs, tea.Batch(cmd, debounce)
+	}
+
+	return hs, nil
+}
+
+// View renders the overlay
+func (hs *HistorySearch) View() string {
+	if !hs.visible {
+		return ""
+	}
+
+	width := 100
+	if hs.width > 0 && hs.width < width+10 {
+		width = max(hs.width-10, 40)
+	}
+	rows := max((hs.height-14)/2, 3)
+
+	header := lipgloss.NewStyle().
+		Foreground(ColorAccent).
+		Bold(true).
+		Render("🔍 History Search (transcripts and Claude conversations)")
+	searchBox := searchBoxStyle.Render(hs.input.View())
+
+	dim := lipgloss.NewStyle().Foreground(ColorComment)
+	var body strings.Builder
+	switch {
+	case hs.searching && len(hs.matches) == 0:
+		body.WriteString(lipgloss.NewStyle().Foreground(ColorYellow).Render("  Searching..."))
+	case hs.input.Value() == "" && len(hs.files) == 0:
+		body.WriteString(dim.Italic(true).Render("  No history yet: enable [transcripts] in config.toml"))
+	case hs.input.Value() == "":
+		body.WriteString(dim.Italic(true).Render(fmt.Sprintf("  Type to search %d history files", len(hs.files))))
+	case len(hs.matches) == 0:
+		body.WriteString(dim.Render("  No matches"))
+	default:
+		start := 0
+		if hs.cursor >= rows {
+			start = hs.cursor - rows + 1
+		}
+		end := min(start+rows, len(hs.matches))
+		for i := start; i < end; i++ {
+			m := hs.matches[i]
+			name := m.Title
+			if name == "" {
+				name = m.SessionID
+			}
+			line := fmt.Sprintf("%s  %s  [%s]", name, m.Time.Local().Format("Jan 2 15:04"), m.Source)
+			line = truncateHistoryText(line, width-6)
+			if i == hs.cursor {
+				body.WriteString(selectedResultStyle.Render("› " + line))
+			} else {
+				body.WriteString(resultItemStyle.Render("  " + line))
+			}
+			body.WriteString("\n")
+			body.WriteString("      " + highlightHistoryMatch(truncateHistoryText(m.Snippet, width-8), hs.query))
+			if i < end-1 {
+				body.WriteString("\n")
+			}
+		}
+	}
+
+	count := dim.Render("  " + formatCount(len(hs.matches)))
+	keysHint := dim.Render("  [Enter] Go to session  [↑↓] Navigate  [Esc] Close")
+	content := header + "\n\n" + searchBox + "\n\n" + body.String() + "\n\n" + count + "\n" + keysHint
+
+	return centerInScreen(overlayStyle.Width(width).Render(content), hs.width, hs.height)
+}
+
+// truncateHistoryText cuts s to n runes
+func truncateHistoryText(s string, n int) string {
+	if r := []rune(s); len(r) > n && n > 3 {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
+
+// highlightHistoryMatch highlights the first case-insensitive match of query
+func highlightHistoryMatch(text, query string) string {
+	query = strings.TrimSpace(query)
+	lower, lowerQuery := strings.ToLower(text), strings.ToLower(query)
+	idx := strings.Index(lower, lowerQuery)
+	// Byte offsets only carry over when lowercasing kept the lengths
+	if query == "" || idx < 0 || len(lower) != len(text) || len(lowerQuery) != len(query) {
+		return text
+	}
+	end := idx + len(query)
+	return text[:idx] + highlightStyle.Render(text[idx:end]) + text[end:]
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHistorySearchAppliesCurrentResultsOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	hs := NewHistorySearch()
+	hs.SetSize(120, 40)
+	hs.Show(nil)
+
+	hs, _ = hs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("err")})
+	if !hs.searching {
+		t.Fatal("typing should start a (debounced) search")
+	}
+
+	hs, _ = hs.Update(historySearchResultsMsg{query: "e", matches: []session.HistoryMatch{{SessionID: "stale"}}})
+	if len(hs.matches) != 0 {
+		t.Fatal("results for an older query should be ignored")
+	}
+
+	hs, _ = hs.Update(historySearchResultsMsg{query: "err", matches: []session.HistoryMatch{
+		{SessionID: "s1", Title: "api", Source: session.HistorySourceTranscript, Time: time.Now(), Snippet: "Error: boom"},
+		{SessionID: "s2", Source: session.HistorySourceClaude, Time: time.Now(), Snippet: "no error here"},
+	}})
+	if hs.searching || len(hs.matches) != 2 {
+		t.Fatalf("expected 2 matches applied, got %d (searching=%v)", len(hs.matches), hs.searching)
+	}
+
+	hs, _ = hs.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if sel := hs.Selected(); sel == nil || sel.SessionID != "s2" {
+		t.Fatalf("down should select the second match, got %+v", sel)
+	}
+
+	view := hs.View()
+	for _, want := range []string{"api", "s2", "[claude]"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+}
@@ -22,8 +22,10 @@ import (
 	"github.com/mattn/go-runewidth"
 
 	"github.com/asheshgoplani/agent-deck/internal/clipboard"
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/power"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 	"github.com/asheshgoplani/agent-deck/internal/statedb"
 	"github.com/asheshgoplani/agent-deck/internal/tmux"
@@ -46,7 +48,7 @@ var (
 	notifLog  = logging.ForComponent(logging.CompNotif)
 	mcpUILog  = logging.ForComponent(logging.CompMCP)
 	statusLog = logging.ForComponent(logging.CompStatus)
-	pipeUILog = logging.ForComponent("pipe")
+	pipeUILog = logging.ForComponent(logging.CompPipe)
 )
 
 const (
@@ -63,9 +65,15 @@ const (
 	// Prevents runaway log growth that can crash the system
 	logMaintenanceInterval = 5 * time.Minute
 
+	// maintenanceWindowCheckInterval - how often to check for an active maintenance window
+	maintenanceWindowCheckInterval = 15 * time.Second
+
 	// analyticsCacheTTL - how long analytics data remains valid before refresh
 	// Analytics don't change frequently, so 5s is a good balance between freshness and performance
 	analyticsCacheTTL = 5 * time.Second
+
+	// busyHistoryDays - how many days of per-session busy time are kept in state.db
+	busyHistoryDays = 90
 )
 
 // UI spacing constants (2-char grid system)
@@ -140,6 +148,7 @@ type Home struct {
 	search               *Search
 	globalSearch         *GlobalSearch              // Global session search across all Claude conversations
 	globalSearchIndex    *session.GlobalSearchIndex // Search index (nil if disabled)
+	historySearch        *HistorySearch             // Search across session transcripts and conversations
 	newDialog            *NewDialog
 	groupDialog          *GroupDialog          // For creating/renaming groups
 	forkDialog           *ForkDialog           // For forking sessions
@@ -152,6 +161,7 @@ type Home struct {
 	analyticsPanel       *AnalyticsPanel       // For displaying session analytics
 	geminiModelDialog    *GeminiModelDialog    // For selecting Gemini model
 	sessionPickerDialog  *SessionPickerDialog  // For sending output to another session
+	macroDialog          *MacroDialog          // For running a tool macro in a session
 	worktreeFinishDialog *WorktreeFinishDialog // For finishing worktree sessions (merge + cleanup)
 
 	// Analytics cache (async fetching with TTL)
@@ -198,6 +208,7 @@ type Home struct {
 	// Moves status updates to a separate goroutine, completely decoupling from UI
 	statusTrigger    chan statusUpdateRequest // Triggers background status update
 	statusWorkerDone chan struct{}            // Signals worker has stopped
+	resumeTrigger    chan struct{}            // Machine woke from sleep: re-check every session
 
 	// PERFORMANCE: Worker pool for output-driven status updates (Priority 2)
 	// Caps the number of goroutines spawned for %output events from control pipes
@@ -256,6 +267,7 @@ type Home struct {
 
 	// SQLite heartbeat: tracks when we last cleaned dead instances
 	lastDeadInstanceCleanup time.Time
+	lastStateSnapshot       time.Time // when deck state history was last recorded
 
 	// User activity tracking for adaptive status updates
 	// PERFORMANCE: Only update statuses when user is actively interacting
@@ -293,6 +305,14 @@ type Home struct {
 	maintenanceMsg     string
 	maintenanceMsgTime time.Time
 
+	// Active maintenance window, checked every maintenanceWindowCheckInterval.
+	// Shown in the maintenance banner in place of maintenanceMsg.
+	maintenanceWindowMsg string
+	lastWindowCheck      time.Time
+
+	// Sessions seen dying, for the experimental auto-restart feature flag
+	autoRestart *autoRestartTracker
+
 	// Cursor sync: track last notification bar switch during attach
 	// When user switches sessions via Ctrl+b N while attached (tea.Exec),
 	// we record the target session ID so cursor can follow after detach
@@ -430,6 +450,20 @@ type copyResultMsg struct {
 	err          error
 }
 
+// macroResultMsg is sent when an async macro run completes
+type macroResultMsg struct {
+	targetTitle string
+	macro       string
+	err         error
+}
+
+// emergencyStopMsg is sent when an emergency stop or resume completes
+type emergencyStopMsg struct {
+	state   *session.EmergencyStopState
+	resumed bool
+	err     error
+}
+
 // sendOutputResultMsg is sent when async inter-session send completes
 type sendOutputResultMsg struct {
 	sourceTitle string
@@ -523,6 +557,8 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		analyticsPanel:       NewAnalyticsPanel(),
 		geminiModelDialog:    NewGeminiModelDialog(),
 		sessionPickerDialog:  NewSessionPickerDialog(),
+		macroDialog:          NewMacroDialog(),
+		autoRestart:          newAutoRestartTracker(),
 		worktreeFinishDialog: NewWorktreeFinishDialog(),
 		cursor:               0,
 		initialLoading:       true, // Show splash until sessions load
@@ -546,6 +582,7 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		worktreeDirtyCacheTs: make(map[string]time.Time),
 		statusTrigger:        make(chan statusUpdateRequest, 1), // Buffered to avoid blocking
 		statusWorkerDone:     make(chan struct{}),
+		resumeTrigger:        make(chan struct{}, 1),
 		logUpdateChan:        make(chan *session.Instance, 100), // Buffered to absorb bursts
 		boundKeys:            make(map[string]string),
 		undoStack:            make([]deletedSessionEntry, 0, 10),
@@ -600,6 +637,22 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 	pm := tmux.NewPipeManager(h.ctx, outputCallback)
 	tmux.SetPipeManager(pm)
 
+	// Bell callback: the agent finished or wants input, so re-check the
+	// session now, skipping the output debounce
+	pm.OnBell(func(sessionName string) {
+		h.instancesMu.RLock()
+		defer h.instancesMu.RUnlock()
+		for _, inst := range h.instances {
+			if ts := inst.GetTmuxSession(); ts != nil && ts.Name == sessionName {
+				select {
+				case h.logUpdateChan <- inst:
+				default:
+				}
+				return
+			}
+		}
+	})
+
 	// Connect pipes for all existing running sessions in background
 	go func() {
 		time.Sleep(500 * time.Millisecond) // Let TUI render first
@@ -623,6 +676,14 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 	// Start background status worker (Priority 1C)
 	go h.statusWorker()
 
+	// Reconcile statuses right after the machine wakes from sleep
+	go session.WatchPower(h.ctx, func(power.Sleep) {
+		select {
+		case h.resumeTrigger <- struct{}{}:
+		default:
+		}
+	})
+
 	// Start log worker pool (Priority 2)
 	h.startLogWorkers()
 
@@ -631,6 +692,7 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 	// content into memory, causing agent-deck to balloon to 6+ GB and get OOM-killed.
 	// TODO: Fix by limiting watched dirs and enforcing balanced tier for large datasets.
 	h.globalSearch = NewGlobalSearch()
+	h.historySearch = NewHistorySearch()
 	// claudeDir := session.GetClaudeConfigDir()
 	// userConfig, _ := session.LoadUserConfig()
 	// if userConfig != nil && userConfig.GlobalSearch.Enabled {
@@ -719,6 +781,7 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 	go func() {
 		logSettings := session.GetLogSettings()
 		tmux.RunLogMaintenance(logSettings.MaxSizeMB, logSettings.MaxLines, logSettings.RemoveOrphans)
+		_, _ = session.PruneTranscripts(session.GetTranscriptSettings(), time.Now())
 	}()
 
 	return h
@@ -977,7 +1040,7 @@ func (h *Home) syncViewport() {
 		updateBannerHeight = 1
 	}
 	maintenanceBannerHeight := 0
-	if h.maintenanceMsg != "" {
+	if h.maintenanceBanner() != "" {
 		maintenanceBannerHeight = 1
 	}
 
@@ -1106,6 +1169,15 @@ func (h *Home) cleanupNotifications() {
 	h.boundKeysMu.Unlock()
 }
 
+// maintenanceBanner returns the maintenance banner text: the active
+// maintenance window if there is one, else the last maintenance run result.
+func (h *Home) maintenanceBanner() string {
+	if h.maintenanceWindowMsg != "" {
+		return h.maintenanceWindowMsg
+	}
+	return h.maintenanceMsg
+}
+
 // getVisibleHeight returns the number of visible items in the session list
 // Used for vi-style pagination (Ctrl+u/d/f/b)
 func (h *Home) getVisibleHeight() int {
@@ -1117,7 +1189,7 @@ func (h *Home) getVisibleHeight() int {
 		updateBannerHeight = 1
 	}
 	maintenanceBannerHeight := 0
-	if h.maintenanceMsg != "" {
+	if h.maintenanceBanner() != "" {
 		maintenanceBannerHeight = 1
 	}
 
@@ -1644,6 +1716,9 @@ func (h *Home) statusWorker() {
 			// Self-triggered update - runs even when TUI is paused
 			h.backgroundStatusUpdate()
 
+		case <-h.resumeTrigger:
+			h.reconcileAfterResume()
+
 		case req := <-h.statusTrigger:
 			// Explicit trigger from TUI (for immediate updates)
 			// Panic recovery to prevent worker death from killing status updates
@@ -1693,6 +1768,35 @@ func (h *Home) logWorker() {
 	}
 }
 
+// reconcileAfterResume re-checks every session right after the machine
+// wakes, instead of waiting for the pipe-idle skip and hook freshness checks
+// to notice what changed while it slept
+func (h *Home) reconcileAfterResume() {
+	tmux.RefreshExistingSessions()
+	tmux.RefreshPaneInfoCache()
+
+	h.instancesMu.RLock()
+	instances := make([]*session.Instance, len(h.instances))
+	copy(instances, h.instances)
+	h.instancesMu.RUnlock()
+
+	g := new(errgroup.Group)
+	g.SetLimit(10)
+	for _, inst := range instances {
+		inst := inst
+		g.Go(func() error {
+			inst.ForceNextStatusCheck()
+			_ = inst.UpdateStatus()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	statusLog.Info("statuses_reconciled_after_resume", slog.Int("sessions", len(instances)))
+
+	// Record and publish the fresh statuses
+	h.backgroundStatusUpdate()
+}
+
 // backgroundStatusUpdate runs independently of the TUI
 // Updates session statuses and syncs notification bar directly to tmux
 // This is called by the internal ticker even when TUI is paused (tea.Exec)
@@ -1823,12 +1927,25 @@ func (h *Home) backgroundStatusUpdate() {
 		// Clean dead instances every ~20s (not every tick)
 		if time.Since(h.lastDeadInstanceCleanup) > 20*time.Second {
 			_ = db.CleanDeadInstances(30 * time.Second)
+			_ = db.PruneBusyDaily(statedb.DayKey(time.Now().AddDate(0, 0, -busyHistoryDays)))
+			_ = db.PruneTokensDaily(statedb.DayKey(time.Now()))
+			_ = db.PruneSleeps(time.Now().AddDate(0, 0, -busyHistoryDays))
 			h.lastDeadInstanceCleanup = time.Now()
 		}
 
-		// Write current status for each instance so other TUI instances stay in sync
+		// Write current status for each instance so other TUI instances stay in sync,
+		// and record it for state/busy duration tracking
+		now := time.Now()
 		for _, inst := range instances {
-			_ = db.WriteStatus(inst.ID, string(inst.GetStatusThreadSafe()), inst.Tool)
+			status := string(inst.GetStatusThreadSafe())
+			_ = db.WriteStatus(inst.ID, status, inst.Tool)
+			_ = session.RecordStatusWithHooks(db, h.profile, inst, now)
+		}
+
+		// Deck state history for 'agent-deck status --at'
+		if interval := session.GetStateHistorySettings().GetInterval(); interval > 0 && now.Sub(h.lastStateSnapshot) >= interval {
+			_, _ = session.RecordStateSnapshot(db, instances, now)
+			h.lastStateSnapshot = now
 		}
 
 		// Read acknowledgments from SQLite (picks up acks from other instances)
@@ -2508,8 +2625,9 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h.rebuildFlatItems()
 		// Update search items
 		h.search.SetItems(h.instances)
-		// Explicitly delete from database to prevent resurrection on reload
-		if err := h.storage.DeleteInstance(msg.deletedID); err != nil {
+		// Explicitly delete from database to prevent resurrection on reload.
+		// The row is kept in the trash so it survives beyond the Ctrl+Z window.
+		if err := h.storage.TrashInstance(msg.deletedID); err != nil {
 			uiLog.Warn("delete_instance_db_err", slog.String("id", msg.deletedID), slog.String("err", err.Error()))
 		}
 		// Save both instances AND groups (critical fix: was losing groups!)
@@ -2535,6 +2653,9 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return h, nil
 		}
 
+		// Undo supersedes the trash copy written on delete
+		_ = session.PurgeTrashEntry(msg.instance.ID)
+
 		// Re-add to instances (mirrors sessionCreatedMsg pattern)
 		h.instancesMu.Lock()
 		h.instances = append(h.instances, msg.instance)
@@ -3033,6 +3154,35 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case macroResultMsg:
+		if msg.err != nil {
+			h.setError(fmt.Errorf("macro '%s' failed in %s: %v", msg.macro, msg.targetTitle, msg.err))
+		} else {
+			h.setError(fmt.Errorf("Ran macro '%s' in '%s'", msg.macro, msg.targetTitle))
+		}
+		return h, nil
+
+	case emergencyStopMsg:
+		h.lastWindowCheck = time.Now()
+		h.maintenanceWindowMsg = ""
+		if active := session.ActiveMaintenanceWindow(h.lastWindowCheck); active != nil {
+			h.maintenanceWindowMsg = active.Describe()
+		}
+		switch {
+		case msg.state == nil:
+			h.setError(fmt.Errorf("emergency stop failed: %v", msg.err))
+		case msg.resumed:
+			h.setError(fmt.Errorf("Resumed the deck"))
+		case msg.err != nil:
+			h.setError(fmt.Errorf("Emergency stop active, but %v", msg.err))
+		case len(msg.state.Errors) > 0:
+			h.setError(fmt.Errorf("Emergency stop: interrupted %d session(s); could not interrupt %s",
+				len(msg.state.Interrupted), strings.Join(msg.state.Errors, ", ")))
+		default:
+			h.setError(fmt.Errorf("Emergency stop: interrupted %d session(s); Ctrl+X to resume", len(msg.state.Interrupted)))
+		}
+		return h, nil
+
 	case sendOutputResultMsg:
 		if msg.err != nil {
 			h.setError(fmt.Errorf("failed to send to %s: %v", msg.targetTitle, msg.err))
@@ -3112,12 +3262,35 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Maintenance windows start and end on their own; keep the banner current
+		if time.Since(h.lastWindowCheck) >= maintenanceWindowCheckInterval {
+			h.lastWindowCheck = time.Now()
+			h.maintenanceWindowMsg = ""
+			if active := session.ActiveMaintenanceWindow(h.lastWindowCheck); active != nil {
+				h.maintenanceWindowMsg = active.Describe()
+			}
+		}
+
+		// Experimental (auto-restart flag): restart sessions whose tmux session died
+		var restartCmds []tea.Cmd
+		h.instancesMu.RLock()
+		dead := h.autoRestart.observe(h.instances, time.Now(), h.maintenanceWindowMsg != "")
+		h.instancesMu.RUnlock()
+		for _, inst := range dead {
+			if h.hasActiveAnimation(inst.ID) {
+				continue
+			}
+			h.resumingSessions[inst.ID] = time.Now()
+			restartCmds = append(restartCmds, h.restartSession(inst))
+		}
+
 		// Full log maintenance (orphan cleanup, etc) every 5 minutes
 		if time.Since(h.lastLogMaintenance) >= logMaintenanceInterval {
 			h.lastLogMaintenance = time.Now()
 			go func() {
 				logSettings := session.GetLogSettings()
 				tmux.RunLogMaintenance(logSettings.MaxSizeMB, logSettings.MaxLines, logSettings.RemoveOrphans)
+				_, _ = session.PruneTranscripts(session.GetTranscriptSettings(), time.Now())
 			}()
 		}
 
@@ -3155,7 +3328,7 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			h.previewCacheMu.Unlock()
 		}
-		return h, tea.Batch(h.tick(), previewCmd)
+		return h, tea.Batch(append([]tea.Cmd{h.tick(), previewCmd}, restartCmds...)...)
 
 	case globalSearchDebounceMsg, globalSearchResultsMsg:
 		// Route async global search messages to the global search component
@@ -3166,6 +3339,14 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case historySearchDebounceMsg, historySearchResultsMsg:
+		if h.historySearch.IsVisible() {
+			var cmd tea.Cmd
+			h.historySearch, cmd = h.historySearch.Update(msg)
+			return h, cmd
+		}
+		return h, nil
+
 	case tea.KeyMsg:
 		// Track user activity for adaptive status updates
 		h.lastUserInputTime = time.Now()
@@ -3205,6 +3386,8 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					h.errTime = time.Now()
 				}
 				_, _ = session.ReloadUserConfig()
+				// Reinstall heartbeat timers whose interval changed
+				go func() { _, _ = session.SyncHeartbeatTimers() }()
 
 				// Apply theme changes live
 				h.stopThemeWatcher()
@@ -3240,6 +3423,9 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if h.globalSearch.IsVisible() {
 			return h.handleGlobalSearchKey(msg)
 		}
+		if h.historySearch.IsVisible() {
+			return h.handleHistorySearchKey(msg)
+		}
 		if h.newDialog.IsVisible() {
 			return h.handleNewDialogKey(msg)
 		}
@@ -3266,6 +3452,9 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if h.sessionPickerDialog.IsVisible() {
 			return h.handleSessionPickerDialogKey(msg)
 		}
+		if h.macroDialog.IsVisible() {
+			return h.handleMacroDialogKey(msg)
+		}
 		if h.worktreeFinishDialog.IsVisible() {
 			return h.handleWorktreeFinishDialogKey(msg)
 		}
@@ -3309,11 +3498,46 @@ func (h *Home) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	h.search, cmd = h.search.Update(msg)
 
 	// Check if user wants to switch to global search
-	if h.search.WantsSwitchToGlobal() && h.globalSearchIndex != nil {
+	if h.search.WantsSwitchToGlobal() {
+		h.showGlobalSearch()
+	}
+
+	return h, cmd
+}
+
+// showGlobalSearch opens the Claude-wide global search when its index is
+// available, otherwise the search across agent-deck sessions' history
+func (h *Home) showGlobalSearch() {
+	if h.globalSearchIndex != nil {
 		h.globalSearch.SetSize(h.width, h.height)
 		h.globalSearch.Show()
+		return
 	}
+	h.historySearch.SetSize(h.width, h.height)
+	h.historySearch.Show(h.instances)
+}
 
+// handleHistorySearchKey handles keys when history search is visible
+func (h *Home) handleHistorySearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if selected := h.historySearch.Selected(); selected != nil {
+			for _, inst := range h.instances {
+				if inst.ID == selected.SessionID {
+					h.jumpToSession(inst)
+					break
+				}
+			}
+		}
+		h.historySearch.Hide()
+		return h, nil
+	case "esc":
+		h.historySearch.Hide()
+		return h, nil
+	}
+
+	var cmd tea.Cmd
+	h.historySearch, cmd = h.historySearch.Update(msg)
 	return h, cmd
 }
 
@@ -3667,13 +3891,8 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
-	case "G": // Open global search (fall back to local search if index not available)
-		if h.globalSearchIndex != nil {
-			h.globalSearch.SetSize(h.width, h.height)
-			h.globalSearch.Show()
-		} else {
-			h.search.Show()
-		}
+	case "G": // Open global search (history search when the global index is off)
+		h.showGlobalSearch()
 		return h, nil
 
 	case "enter":
@@ -4151,6 +4370,10 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "x":
 		// Send session output to another session
+		if session.LoadEmergencyStop() != nil {
+			h.setError(fmt.Errorf("the deck is emergency-stopped; Ctrl+X to resume"))
+			return h, nil
+		}
 		if h.cursor < len(h.flatItems) {
 			item := h.flatItems[h.cursor]
 			if item.Type == session.ItemTypeSession && item.Session != nil {
@@ -4165,6 +4388,23 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case "X", "shift+x":
+		// Run a tool macro (compact, model picker, ...) in the selected session
+		if inst := h.getSelectedSession(); inst != nil {
+			if !inst.Exists() {
+				h.setError(fmt.Errorf("session '%s' is not running", inst.Title))
+				return h, nil
+			}
+			macros := session.ListMacros(inst.Tool)
+			if len(macros) == 0 {
+				h.setError(fmt.Errorf("no macros for %s (add them under [tools.%s.macros])", inst.Tool, inst.Tool))
+				return h, nil
+			}
+			h.macroDialog.SetSize(h.width, h.height)
+			h.macroDialog.Show(inst, macros)
+		}
+		return h, nil
+
 	case "ctrl+g":
 		// Open Gemini model selection dialog (only for Gemini sessions)
 		if inst := h.getSelectedSession(); inst != nil && inst.Tool == "gemini" {
@@ -4173,6 +4413,17 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case "ctrl+x":
+		// Emergency stop: stops at once, resuming asks first
+		if session.LoadEmergencyStop() != nil {
+			h.confirmDialog.ShowEmergencyResume()
+			return h, nil
+		}
+		return h, func() tea.Msg {
+			state, err := session.EmergencyStop("", "tui")
+			return emergencyStopMsg{state: state, err: err}
+		}
+
 	case "ctrl+z":
 		// Undo last session delete (Chrome-style: restores in reverse order)
 		if len(h.undoStack) == 0 {
@@ -4295,6 +4546,23 @@ func (h *Home) handleConfirmDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case ConfirmEmergencyResume:
+		switch msg.String() {
+		case "y", "Y":
+			h.confirmDialog.Hide()
+			return h, func() tea.Msg {
+				state, err := session.ResumeEmergencyStop()
+				if err != nil {
+					return emergencyStopMsg{err: err}
+				}
+				return emergencyStopMsg{state: state, resumed: true}
+			}
+		case "n", "N", "esc":
+			h.confirmDialog.Hide()
+			return h, nil
+		}
+		return h, nil
+
 	case ConfirmInstallHooks:
 		switch msg.String() {
 		case "y", "Y":
@@ -4941,6 +5209,8 @@ func (h *Home) createSessionInGroupWithWorktreeAndOptions(name, path, command, g
 			tool = "aider"
 		case "codex":
 			tool = "codex"
+		case "ollama":
+			tool = "ollama"
 		case "opencode":
 			tool = "opencode"
 		default:
@@ -5147,6 +5417,7 @@ func (h *Home) forkSessionCmdWithOptions(source *session.Instance, title, groupP
 	// This ensures we don't detect an already-used session ID
 	usedIDs := h.getUsedClaudeSessionIDs()
 	sourceID := source.ID // Capture for closure
+	profile := h.profile
 
 	return func() tea.Msg {
 		// Check tmux availability before forking
@@ -5178,6 +5449,10 @@ func (h *Home) forkSessionCmdWithOptions(source *session.Instance, title, groupP
 			go inst.DetectOpenCodeSession()
 		}
 
+		ev := session.NewHookEvent(session.HookEventFork, profile, inst, time.Now())
+		ev.ParentID = sourceID
+		go session.RunEventHooks(ev)
+
 		return sessionForkedMsg{instance: inst, sourceID: sourceID}
 	}
 }
@@ -5532,6 +5807,7 @@ func (h *Home) renderFilterBar() string {
 // updateSizes updates component sizes
 func (h *Home) updateSizes() {
 	h.search.SetSize(h.width, h.height)
+	h.historySearch.SetSize(h.width, h.height)
 	h.newDialog.SetSize(h.width, h.height)
 	h.groupDialog.SetSize(h.width, h.height)
 	h.confirmDialog.SetSize(h.width, h.height)
@@ -5596,6 +5872,9 @@ func (h *Home) View() string {
 	if h.globalSearch.IsVisible() {
 		return h.globalSearch.View()
 	}
+	if h.historySearch.IsVisible() {
+		return h.historySearch.View()
+	}
 	if h.newDialog.IsVisible() {
 		return h.newDialog.View()
 	}
@@ -5620,6 +5899,9 @@ func (h *Home) View() string {
 	if h.sessionPickerDialog.IsVisible() {
 		return h.sessionPickerDialog.View()
 	}
+	if h.macroDialog.IsVisible() {
+		return h.macroDialog.View()
+	}
 	if h.worktreeFinishDialog.IsVisible() {
 		return h.worktreeFinishDialog.View()
 	}
@@ -5640,13 +5922,19 @@ func (h *Home) View() string {
 		Bold(true).
 		Foreground(ColorAccent)
 
-	// Show profile in title if not default
+	// Show deck and profile in title if not default
 	titleText := "Agent Deck"
+	if name := deck.Name(); name != "" {
+		deckStyle := lipgloss.NewStyle().
+			Foreground(ColorPurple).
+			Bold(true)
+		titleText += " " + deckStyle.Render("<"+name+">")
+	}
 	if h.profile != "" && h.profile != session.DefaultProfile {
 		profileStyle := lipgloss.NewStyle().
 			Foreground(ColorCyan).
 			Bold(true)
-		titleText = "Agent Deck " + profileStyle.Render("["+h.profile+"]")
+		titleText += " " + profileStyle.Render("["+h.profile+"]")
 	}
 	title := titleStyle.Render(titleText)
 
@@ -5729,7 +6017,7 @@ func (h *Home) View() string {
 	// MAINTENANCE BANNER (if maintenance completed recently)
 	// ═══════════════════════════════════════════════════════════════════
 	maintenanceBannerHeight := 0
-	if h.maintenanceMsg != "" {
+	if banner := h.maintenanceBanner(); banner != "" {
 		maintenanceBannerHeight = 1
 		maintStyle := lipgloss.NewStyle().
 			Foreground(ColorBg).
@@ -5737,7 +6025,7 @@ func (h *Home) View() string {
 			Bold(true).
 			MaxWidth(h.width).
 			Align(lipgloss.Center)
-		b.WriteString(maintStyle.Render(" " + h.maintenanceMsg + " "))
+		b.WriteString(maintStyle.Render(" " + banner + " "))
 		b.WriteString("\n")
 	}
 
@@ -6997,17 +7285,20 @@ func (h *Home) renderSessionItem(b *strings.Builder, item session.Item, selected
 	var statusStyle lipgloss.Style
 	switch instStatus {
 	case session.StatusRunning:
-		statusIcon = "●"
+		statusIcon = statusGlyph(instStatus)
 		statusStyle = SessionStatusRunning
 	case session.StatusWaiting:
-		statusIcon = "◐"
+		statusIcon = statusGlyph(instStatus)
 		statusStyle = SessionStatusWaiting
 	case session.StatusIdle:
-		statusIcon = "○"
+		statusIcon = statusGlyph(instStatus)
 		statusStyle = SessionStatusIdle
 	case session.StatusError:
-		statusIcon = "✕"
+		statusIcon = statusGlyph(instStatus)
 		statusStyle = SessionStatusError
+	case session.StatusThrottled:
+		statusIcon = statusGlyph(instStatus)
+		statusStyle = SessionStatusThrottle
 	default:
 		statusIcon = "○"
 		statusStyle = SessionStatusIdle
@@ -7076,10 +7367,23 @@ func (h *Home) renderSessionItem(b *strings.Builder, item session.Item, selected
 		worktreeBadge = wtStyle.Render(" [" + branch + "]")
 	}
 
-	// Build row: [baseIndent][selection][tree][status] [title] [tool] [yolo] [worktree]
+	// Risk badge for sessions whose recent tool calls matched a high or critical rule
+	riskBadge := ""
+	if risk := inst.Risk(); risk != nil && risk.Level.Rank() >= session.RiskHigh.Rank() {
+		riskStyle := lipgloss.NewStyle().Foreground(ColorOrange)
+		if risk.Level == session.RiskCritical {
+			riskStyle = lipgloss.NewStyle().Foreground(ColorRed).Bold(true)
+		}
+		if selected {
+			riskStyle = SessionStatusSelStyle
+		}
+		riskBadge = riskStyle.Render(" [" + strings.ToUpper(string(risk.Level)) + " RISK]")
+	}
+
+	// Build row: [baseIndent][selection][tree][status] [title] [tool] [yolo] [worktree] [risk]
 	// Format: " ├─ ● session-name tool" or "▶└─ ● session-name tool"
 	// Sub-sessions get extra indent: "   ├─◐ sub-session tool"
-	row := fmt.Sprintf("%s%s%s %s %s%s%s%s", baseIndent, selectionPrefix, treeStyle.Render(treeConnector), status, title, tool, yoloBadge, worktreeBadge)
+	row := fmt.Sprintf("%s%s%s %s %s%s%s%s%s", baseIndent, selectionPrefix, treeStyle.Render(treeConnector), status, title, tool, yoloBadge, worktreeBadge, riskBadge)
 	b.WriteString(row)
 	b.WriteString("\n")
 }
@@ -7132,6 +7436,13 @@ func (h *Home) renderLaunchingState(inst *session.Instance, width int, startTime
 		} else {
 			toolDesc = "Starting Codex..."
 		}
+	case "ollama":
+		toolName = "Ollama"
+		if isResuming {
+			toolDesc = "Resuming saved conversation..."
+		} else {
+			toolDesc = "Loading model..."
+		}
 	case "opencode":
 		toolName = "OpenCode"
 		if isResuming {
@@ -7343,6 +7654,8 @@ func (h *Home) renderSessionInfoCard(inst *session.Instance, width, height int)
 		statusColor = ColorGreen
 	case session.StatusWaiting:
 		statusColor = ColorYellow
+	case session.StatusThrottled:
+		statusColor = ColorOrange
 	case session.StatusError:
 		statusColor = ColorRed
 	default:
@@ -7416,14 +7729,17 @@ func (h *Home) renderPreviewPane(width, height int) string {
 	statusColor := ColorTextDim
 	switch selected.Status {
 	case session.StatusRunning:
-		statusIcon = "●"
+		statusIcon = statusGlyph(selected.Status)
 		statusColor = ColorGreen
 	case session.StatusWaiting:
-		statusIcon = "◐"
+		statusIcon = statusGlyph(selected.Status)
 		statusColor = ColorYellow
 	case session.StatusError:
-		statusIcon = "✕"
+		statusIcon = statusGlyph(selected.Status)
 		statusColor = ColorRed
+	case session.StatusThrottled:
+		statusIcon = statusGlyph(selected.Status)
+		statusColor = ColorOrange
 	}
 
 	// Header with session name and status
@@ -7449,6 +7765,23 @@ func (h *Home) renderPreviewPane(width, height int) string {
 	b.WriteString(infoStyle.Render("⏱ " + activityStr))
 	b.WriteString("\n")
 
+	// Throttled: show what the provider said, so it isn't mistaken for work
+	if msg := selected.ThrottleMessage(); msg != "" {
+		throttleStyle := lipgloss.NewStyle().Foreground(ColorOrange)
+		b.WriteString(throttleStyle.Render("⧖ " + runewidth.Truncate(msg, width-6, "...")))
+		b.WriteString("\n")
+	}
+
+	// Risky recent tool calls: show the worst one
+	if risk := selected.Risk(); risk != nil && risk.Level.Rank() >= session.RiskMedium.Rank() {
+		riskStyle := lipgloss.NewStyle().Foreground(ColorYellow)
+		if risk.Level.Rank() >= session.RiskHigh.Rank() {
+			riskStyle = lipgloss.NewStyle().Foreground(ColorRed)
+		}
+		b.WriteString(riskStyle.Render("⚠ " + runewidth.Truncate(risk.Describe(), width-6, "...")))
+		b.WriteString("\n")
+	}
+
 	toolBadge := lipgloss.NewStyle().
 		Foreground(ColorBg).
 		Background(ColorPurple).
@@ -8260,36 +8593,12 @@ func truncatePath(path string, maxLen int) string {
 }
 
 // formatRelativeTime formats a time as a human-readable relative string
-// Examples: "just now", "2m ago", "1h ago", "3h ago", "1d ago"
+// Examples: "just now", "2m ago", "1h ago", "3d ago". Honors [time] format = "absolute".
 func formatRelativeTime(t time.Time) string {
 	if t.IsZero() {
 		return "unknown"
 	}
-
-	d := time.Since(t)
-
-	switch {
-	case d < time.Minute:
-		return "just now"
-	case d < time.Hour:
-		mins := int(d.Minutes())
-		if mins == 1 {
-			return "1m ago"
-		}
-		return fmt.Sprintf("%dm ago", mins)
-	case d < 24*time.Hour:
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1h ago"
-		}
-		return fmt.Sprintf("%dh ago", hours)
-	default:
-		days := int(d.Hours() / 24)
-		if days == 1 {
-			return "1d ago"
-		}
-		return fmt.Sprintf("%dd ago", days)
-	}
+	return session.NewTimeFormatter(false).Format(t, time.Now())
 }
 
 // renderGroupPreview renders the preview pane for a group
@@ -8590,6 +8899,29 @@ func (h *Home) handleSessionPickerDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	}
 }
 
+// handleMacroDialogKey handles key events when the macro dialog is visible.
+func (h *Home) handleMacroDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		selected := h.macroDialog.GetSelected()
+		target := h.macroDialog.GetTarget()
+		h.macroDialog.Hide()
+		if selected != nil && target != nil {
+			name := selected.Name
+			return h, func() tea.Msg {
+				return macroResultMsg{targetTitle: target.Title, macro: name, err: target.RunMacro(name)}
+			}
+		}
+		return h, nil
+	case "esc":
+		h.macroDialog.Hide()
+		return h, nil
+	default:
+		h.macroDialog.Update(msg)
+		return h, nil
+	}
+}
+
 // handleWorktreeFinishDialogKey processes key events for the worktree finish dialog
 func (h *Home) handleWorktreeFinishDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	action := h.worktreeFinishDialog.HandleKey(msg.String())
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// MacroDialog lists the macros of a session's tool so one can be run.
+// Used by the "X" (run macro) feature.
+type MacroDialog struct {
+	visible       bool
+	width, height int
+	macros        []session.Macro
+	cursor        int
+	target        *session.Instance
+}
+
+// NewMacroDialog creates a new macro dialog.
+func NewMacroDialog() *MacroDialog {
+	return &MacroDialog{}
+}
+
+// Show opens the dialog with the macros available for the target's tool.
+func (d *MacroDialog) Show(target *session.Instance, macros []session.Macro) {
+	d.visible = true
+	d.target = target
+	d.macros = macros
+	d.cursor = 0
+}
+
+// Hide closes the dialog and resets state.
+func (d *MacroDialog) Hide() {
+	d.visible = false
+	d.cursor = 0
+	d.target = nil
+	d.macros = nil
+}
+
+// IsVisible returns whether the dialog is currently shown.
+func (d *MacroDialog) IsVisible() bool {
+	return d.visible
+}
+
+// SetSize updates the dialog dimensions for centering.
+func (d *MacroDialog) SetSize(w, h int) {
+	d.width = w
+	d.height = h
+}
+
+// GetSelected returns the macro at the current cursor position, or nil.
+func (d *MacroDialog) GetSelected() *session.Macro {
+	if len(d.macros) == 0 || d.cursor >= len(d.macros) {
+		return nil
+	}
+	return &d.macros[d.cursor]
+}
+
+// GetTarget returns the session the macro runs in.
+func (d *MacroDialog) GetTarget() *session.Instance {
+	return d.target
+}
+
+// Update handles key events for the dialog.
+func (d *MacroDialog) Update(msg tea.KeyMsg) (*MacroDialog, tea.Cmd) {
+	if !d.visible {
+		return d, nil
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		if len(d.macros) > 0 {
+			d.cursor = (d.cursor + 1) % len(d.macros)
+		}
+	case "k", "up":
+		if len(d.macros) > 0 {
+			d.cursor = (d.cursor - 1 + len(d.macros)) % len(d.macros)
+		}
+	case "esc":
+		d.Hide()
+	}
+
+	return d, nil
+}
+
+// View renders the macro dialog.
+func (d *MacroDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorAccent)
+
+	targetStyle := lipgloss.NewStyle().
+		Foreground(ColorTextDim).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(ColorAccent).
+		Bold(true)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(ColorText)
+
+	descStyle := lipgloss.NewStyle().
+		Foreground(ColorTextDim)
+
+	footerStyle := lipgloss.NewStyle().
+		Foreground(ColorComment).
+		Italic(true)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("Run Macro"))
+
+	targetName, tool := "unknown", ""
+	if d.target != nil {
+		targetName, tool = d.target.Title, d.target.Tool
+	}
+	lines = append(lines, targetStyle.Render(fmt.Sprintf("Session: \"%s\" (%s)", targetName, tool)))
+	lines = append(lines, "")
+
+	for i, m := range d.macros {
+		label := fmt.Sprintf("%-12s", m.Name)
+		desc := descStyle.Render(m.Description)
+		if i == d.cursor {
+			lines = append(lines, "> "+selectedStyle.Render(label)+" "+desc)
+		} else {
+			lines = append(lines, "  "+normalStyle.Render(label)+" "+desc)
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, footerStyle.Render("Enter run | Esc cancel | j/k navigate"))
+
+	content := strings.Join(lines, "\n")
+
+	dialogWidth := 60
+	if d.width > 0 && d.width < dialogWidth+10 {
+		dialogWidth = d.width - 10
+		if dialogWidth < 30 {
+			dialogWidth = 30
+		}
+	}
+
+	box := DialogBoxStyle.
+		Width(dialogWidth).
+		Render(content)
+
+	return centerInScreen(box, d.width, d.height)
+}
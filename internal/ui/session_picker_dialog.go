@@ -187,6 +187,8 @@ func statusIndicator(status session.Status) string {
 		return lipgloss.NewStyle().Foreground(ColorYellow).Render("◐")
 	case session.StatusIdle:
 		return lipgloss.NewStyle().Foreground(ColorTextDim).Render("○")
+	case session.StatusThrottled:
+		return lipgloss.NewStyle().Foreground(ColorOrange).Render("⧖")
 	default:
 		return lipgloss.NewStyle().Foreground(ColorRed).Render("✕")
 	}
@@ -5,6 +5,8 @@ import (
 	"sync"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
 // Theme represents the current color scheme
@@ -190,6 +192,8 @@ const (
 	IconGemini   = "✨"
 	IconOpenCode = "🌐"
 	IconCodex    = "💻"
+	IconOllama   = "🦙"
+	IconAider    = "🤝"
 	IconShell    = "🐚"
 )
 
@@ -238,6 +242,7 @@ var (
 	SessionStatusWaiting  lipgloss.Style
 	SessionStatusIdle     lipgloss.Style
 	SessionStatusError    lipgloss.Style
+	SessionStatusThrottle lipgloss.Style
 	SessionStatusSelStyle lipgloss.Style
 
 	// Session title styles by state
@@ -489,6 +494,7 @@ func initStyles() {
 	SessionStatusWaiting = lipgloss.NewStyle().Foreground(ColorYellow)
 	SessionStatusIdle = lipgloss.NewStyle().Foreground(ColorTextDim)
 	SessionStatusError = lipgloss.NewStyle().Foreground(ColorRed)
+	SessionStatusThrottle = lipgloss.NewStyle().Foreground(ColorOrange)
 	SessionStatusSelStyle = lipgloss.NewStyle().Foreground(ColorBg).Background(ColorAccent)
 
 	// Session title styles by state
@@ -519,6 +525,7 @@ func initStyles() {
 		"gemini":   lipgloss.NewStyle().Foreground(ColorPurple),
 		"codex":    lipgloss.NewStyle().Foreground(ColorCyan),
 		"aider":    lipgloss.NewStyle().Foreground(ColorRed),
+		"ollama":   lipgloss.NewStyle().Foreground(ColorGreen),
 		"cursor":   lipgloss.NewStyle().Foreground(ColorAccent),
 		"shell":    lipgloss.NewStyle().Foreground(ColorText),
 		"opencode": lipgloss.NewStyle().Foreground(ColorText),
@@ -558,23 +565,33 @@ func MenuKey(key, description string) string {
 	)
 }
 
+// statusGlyph returns the symbol for status, honoring [output] glyphs overrides.
+// Screen-reader words are CLI-only: the TUI layout needs single-cell glyphs.
+func statusGlyph(status session.Status) string {
+	settings := session.GetOutputSettings()
+	settings.ScreenReader = false
+	return settings.StatusGlyph(string(status))
+}
+
 // StatusIndicator returns a styled status indicator.
 // Read-locked to protect against concurrent style access during live theme switches.
-// Standard symbols: ● running, ◐ waiting, ○ idle, ✕ error, ⟳ starting
+// Standard symbols: ● running, ◐ waiting, ○ idle, ✕ error, ⟳ starting, ⧖ throttled
 func StatusIndicator(status string) string {
 	themeMu.RLock()
 	defer themeMu.RUnlock()
 	switch status {
 	case "running":
-		return RunningStyle.Render("●")
+		return RunningStyle.Render(statusGlyph(session.StatusRunning))
 	case "waiting":
-		return WaitingStyle.Render("◐")
+		return WaitingStyle.Render(statusGlyph(session.StatusWaiting))
 	case "idle":
-		return IdleStyle.Render("○")
+		return IdleStyle.Render(statusGlyph(session.StatusIdle))
 	case "error":
-		return ErrorIndicatorStyle.Render("✕")
+		return ErrorIndicatorStyle.Render(statusGlyph(session.StatusError))
 	case "starting":
-		return WaitingStyle.Render("⟳") // Use yellow color, spinning arrow symbol
+		return WaitingStyle.Render(statusGlyph(session.StatusStarting)) // Use yellow color, spinning arrow symbol
+	case "throttled":
+		return SessionStatusThrottle.Render(statusGlyph(session.StatusThrottled))
 	default:
 		return IdleStyle.Render("○")
 	}
@@ -595,6 +612,10 @@ func ToolIcon(tool string) string {
 		return IconOpenCode
 	case "codex":
 		return IconCodex
+	case "ollama":
+		return IconOllama
+	case "aider":
+		return IconAider
 	case "cursor":
 		return "📝"
 	case "shell":
@@ -616,6 +637,8 @@ func ToolColor(tool string) lipgloss.Color {
 		return ColorCyan // Light blue for OpenAI
 	case "aider":
 		return ColorRed // Red for Aider
+	case "ollama":
+		return ColorGreen // Green for local models
 	case "cursor":
 		return ColorAccent // Blue for Cursor
 	default:
@@ -0,0 +1,322 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Self-update stages, in the order they run. Each stage only starts when the
+// previous one succeeded; a failure in the artifacts stage puts the old binary
+// back.
+const (
+	StageDownload  = "download"
+	StageVerify    = "verify"
+	StageSwap      = "swap"
+	StageArtifacts = "artifacts"
+)
+
+const (
+	// ChecksumsAssetName is the goreleaser checksum file published with each release
+	ChecksumsAssetName = "checksums.txt"
+
+	// SignatureAssetName is the detached ed25519 signature of checksums.txt
+	SignatureAssetName = "checksums.txt.sig"
+)
+
+// ReleasePublicKey is the base64 ed25519 public key release checksums are
+// signed with. Release builds set it with
+// -ldflags "-X github.com/asheshgoplani/agent-deck/internal/update.ReleasePublicKey=...".
+// When set, updates without a valid checksums.txt.sig are refused.
+var ReleasePublicKey = ""
+
+// refreshArtifacts has a binary regenerate artifacts from its own templates
+func refreshArtifacts(binaryPath string) ([]byte, error) {
+	return exec.Command(binaryPath, "update", "--refresh-artifacts").CombinedOutput()
+}
+
+// PackageManagerError reports that the running binary belongs to a package
+// manager, which must do the upgrade so its records stay correct.
+type PackageManagerError struct {
+	Manager string // homebrew, nix, dpkg, rpm
+	Path    string
+	Command string // how to upgrade instead
+}
+
+func (e *PackageManagerError) Error() string {
+	return fmt.Sprintf("%s is managed by %s; upgrade with: %s", e.Path, e.Manager, e.Command)
+}
+
+// DetectPackageManager returns a *PackageManagerError when execPath was
+// installed by a package manager, nil when agent-deck may replace it itself.
+func DetectPackageManager(execPath string) *PackageManagerError {
+	switch {
+	case strings.Contains(execPath, "/Cellar/") || strings.Contains(execPath, "/homebrew/") || strings.Contains(execPath, "/linuxbrew/"):
+		return &PackageManagerError{Manager: "homebrew", Path: execPath, Command: "brew upgrade agent-deck"}
+	case strings.HasPrefix(execPath, "/nix/store/"):
+		return &PackageManagerError{Manager: "nix", Path: execPath, Command: "update the agent-deck package in your nix configuration"}
+	case strings.HasPrefix(execPath, "/usr/bin/") || strings.HasPrefix(execPath, "/bin/"):
+		if _, err := exec.LookPath("dpkg"); err == nil && exec.Command("dpkg", "-S", execPath).Run() == nil {
+			return &PackageManagerError{Manager: "dpkg", Path: execPath, Command: "sudo apt install --only-upgrade agent-deck"}
+		}
+		if _, err := exec.LookPath("rpm"); err == nil && exec.Command("rpm", "-qf", execPath).Run() == nil {
+			return &PackageManagerError{Manager: "rpm", Path: execPath, Command: "sudo dnf upgrade agent-deck"}
+		}
+	}
+	return nil
+}
+
+// StageResult is the outcome of one self-update stage
+type StageResult struct {
+	Stage  string `json:"stage"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ApplyReport describes a self-update run
+type ApplyReport struct {
+	FromVersion string        `json:"from_version"`
+	ToVersion   string        `json:"to_version"`
+	BinaryPath  string        `json:"binary_path"`
+	Stages      []StageResult `json:"stages"`
+	RolledBack  bool          `json:"rolled_back,omitempty"`
+}
+
+func (r *ApplyReport) record(stage string, err error, detail string) error {
+	result := StageResult{Stage: stage, OK: err == nil, Detail: detail}
+	if err != nil {
+		result.Detail = err.Error()
+	}
+	r.Stages = append(r.Stages, result)
+	return err
+}
+
+// Apply installs the release described by info in stages: download the
+// archive, verify it against the release checksums (and signature when
+// ReleasePublicKey is set) and smoke-test the new binary, atomically swap it
+// in, then have the new binary regenerate its templated artifacts and restart
+// daemons. If the last stage fails, the previous binary is restored and asked
+// to regenerate its own artifacts again. Binaries owned by a package manager
+// are left alone and reported as a *PackageManagerError.
+func Apply(info *UpdateInfo) (*ApplyReport, error) {
+	report := &ApplyReport{FromVersion: info.CurrentVersion, ToVersion: info.LatestVersion}
+	if info.DownloadURL == "" {
+		return report, fmt.Errorf("no download URL available for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return report, fmt.Errorf("failed to get executable path: %w", err)
+	}
+	if execPath, err = filepath.EvalSymlinks(execPath); err != nil {
+		return report, fmt.Errorf("failed to resolve symlinks: %w", err)
+	}
+	report.BinaryPath = execPath
+	if pm := DetectPackageManager(execPath); pm != nil {
+		return report, pm
+	}
+
+	// Stage 1: download
+	archive, err := download(info.DownloadURL, 120*time.Second)
+	if report.record(StageDownload, err, fmt.Sprintf("%d bytes", len(archive))) != nil {
+		return report, err
+	}
+
+	// Stage 2: verify checksum, signature, and that the binary runs
+	var tmpPath string
+	detail, err := verifyArchive(info, archive)
+	if err == nil {
+		tmpPath, err = stageBinary(execPath, archive, info.LatestVersion)
+	}
+	if report.record(StageVerify, err, detail) != nil {
+		return report, err
+	}
+
+	// Stage 3: atomic swap, keeping the old binary for rollback
+	backupPath := execPath + ".old"
+	err = swapBinary(execPath, tmpPath, backupPath)
+	if report.record(StageSwap, err, execPath) != nil {
+		_ = os.Remove(tmpPath)
+		return report, err
+	}
+
+	// Stage 4: the new binary regenerates artifacts from its own templates
+	out, err := refreshArtifacts(execPath)
+	if err != nil {
+		err = fmt.Errorf("new binary failed to refresh artifacts: %w\n%s", err, strings.TrimSpace(string(out)))
+		_ = report.record(StageArtifacts, err, "")
+		if rbErr := os.Rename(backupPath, execPath); rbErr != nil {
+			return report, fmt.Errorf("%w; rollback failed, previous binary left at %s: %v", err, backupPath, rbErr)
+		}
+		report.RolledBack = true
+		_, _ = refreshArtifacts(execPath)
+		return report, err
+	}
+	_ = report.record(StageArtifacts, nil, strings.TrimSpace(string(out)))
+	_ = os.Remove(backupPath)
+	return report, nil
+}
+
+// download fetches a URL into memory
+func download(url string, timeout time.Duration) ([]byte, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download of %s failed with status %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// verifyArchive checks the archive against the release's checksums.txt and,
+// when ReleasePublicKey is set, checksums.txt against its signature
+func verifyArchive(info *UpdateInfo, archive []byte) (string, error) {
+	if info.ChecksumsURL == "" {
+		return "", fmt.Errorf("release has no %s; refusing to install an unverified binary", ChecksumsAssetName)
+	}
+	checksums, err := download(info.ChecksumsURL, 30*time.Second)
+	if err != nil {
+		return "", err
+	}
+
+	detail := "sha256 ok"
+	if ReleasePublicKey != "" {
+		if info.SignatureURL == "" {
+			return "", fmt.Errorf("release has no %s; refusing to install an unsigned binary", SignatureAssetName)
+		}
+		sig, err := download(info.SignatureURL, 30*time.Second)
+		if err != nil {
+			return "", err
+		}
+		if err := VerifySignature(checksums, sig, ReleasePublicKey); err != nil {
+			return "", err
+		}
+		detail += ", signature ok"
+	}
+
+	assetName := path.Base(info.DownloadURL)
+	if err := VerifyChecksum(archive, assetName, checksums); err != nil {
+		return "", err
+	}
+	return detail, nil
+}
+
+// VerifyChecksum checks data against the entry for name in a sha256sum-style
+// checksums file ("<hex>  <name>" per line)
+func VerifyChecksum(data []byte, name string, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, fields[0]) {
+			return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, fields[0])
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum for %s in %s", name, ChecksumsAssetName)
+}
+
+// VerifySignature checks an ed25519 signature (raw or base64) of data
+// against a base64 public key
+func VerifySignature(data, sig []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release public key")
+	}
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", SignatureAssetName, err)
+		}
+		sig = decoded
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), data, sig) {
+		return fmt.Errorf("signature verification of %s failed", ChecksumsAssetName)
+	}
+	return nil
+}
+
+// stageBinary extracts the new binary next to execPath (same filesystem, so
+// the swap is a rename) and checks it runs and reports the expected version
+func stageBinary(execPath string, archive []byte, version string) (string, error) {
+	tmpArchive, err := os.CreateTemp("", "agent-deck-update-*.tar.gz")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpArchive.Name())
+	_, err = tmpArchive.Write(archive)
+	tmpArchive.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to save download: %w", err)
+	}
+	binary, err := extractBinaryFromTarGz(tmpArchive.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to extract: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(execPath), ".agent-deck-new-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to stage new binary (is %s writable?): %w", filepath.Dir(execPath), err)
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(binary)
+	tmp.Close()
+	if err == nil {
+		err = os.Chmod(tmpPath, 0o755)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write new binary: %w", err)
+	}
+
+	out, err := exec.Command(tmpPath, "version").Output()
+	if err != nil || !strings.Contains(string(out), version) {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("new binary failed its smoke test (version output %q)", strings.TrimSpace(string(out)))
+	}
+	return tmpPath, nil
+}
+
+// swapBinary keeps a copy of the current binary at backupPath, then renames
+// the staged binary over execPath. The rename is atomic, so a running or
+// concurrently started agent-deck sees either the old or the new binary.
+func swapBinary(execPath, stagedPath, backupPath string) error {
+	_ = os.Remove(backupPath)
+	if err := os.Link(execPath, backupPath); err != nil {
+		data, readErr := os.ReadFile(execPath)
+		if readErr != nil {
+			return fmt.Errorf("failed to back up current binary: %w", readErr)
+		}
+		if err := os.WriteFile(backupPath, data, 0o755); err != nil {
+			return fmt.Errorf("failed to back up current binary: %w", err)
+		}
+	}
+	if err := os.Rename(stagedPath, execPath); err != nil {
+		_ = os.Remove(backupPath)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package update
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive bytes")
+	sum := sha256.Sum256(data)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  agent-deck_1.2.3_linux_amd64.tar.gz\n" +
+		"deadbeef  agent-deck_1.2.3_darwin_arm64.tar.gz\n")
+
+	assert.NoError(t, VerifyChecksum(data, "agent-deck_1.2.3_linux_amd64.tar.gz", checksums))
+	assert.ErrorContains(t, VerifyChecksum(data, "agent-deck_1.2.3_darwin_arm64.tar.gz", checksums), "checksum mismatch")
+	assert.ErrorContains(t, VerifyChecksum(data, "agent-deck_1.2.3_windows_amd64.tar.gz", checksums), "no checksum")
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	key := base64.StdEncoding.EncodeToString(pub)
+	checksums := []byte("abc  agent-deck.tar.gz\n")
+	sig := ed25519.Sign(priv, checksums)
+
+	assert.NoError(t, VerifySignature(checksums, sig, key))
+	assert.NoError(t, VerifySignature(checksums, []byte(base64.StdEncoding.EncodeToString(sig)+"\n"), key))
+	assert.Error(t, VerifySignature([]byte("tampered"), sig, key))
+	assert.Error(t, VerifySignature(checksums, sig, "not-a-key"))
+}
+
+func TestDetectPackageManager(t *testing.T) {
+	pm := DetectPackageManager("/opt/homebrew/Cellar/agent-deck/1.0.0/bin/agent-deck")
+	require.NotNil(t, pm)
+	assert.Equal(t, "homebrew", pm.Manager)
+	assert.Contains(t, pm.Error(), "brew upgrade agent-deck")
+
+	pm = DetectPackageManager("/nix/store/abc-agent-deck/bin/agent-deck")
+	require.NotNil(t, pm)
+	assert.Equal(t, "nix", pm.Manager)
+
+	assert.Nil(t, DetectPackageManager(filepath.Join(t.TempDir(), "agent-deck")))
+}
+
+func TestSwapBinary_KeepsBackup(t *testing.T) {
+	dir := t.TempDir()
+	execPath := filepath.Join(dir, "agent-deck")
+	staged := filepath.Join(dir, ".agent-deck-new")
+	backup := execPath + ".old"
+	require.NoError(t, os.WriteFile(execPath, []byte("old"), 0o755))
+	require.NoError(t, os.WriteFile(staged, []byte("new"), 0o755))
+
+	require.NoError(t, swapBinary(execPath, staged, backup))
+
+	got, _ := os.ReadFile(execPath)
+	assert.Equal(t, "new", string(got))
+	got, _ = os.ReadFile(backup)
+	assert.Equal(t, "old", string(got))
+	_, err := os.Stat(staged)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/deck"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
@@ -61,6 +62,8 @@ type UpdateCache struct {
 	LatestVersion  string    `json:"latest_version"`
 	CurrentVersion string    `json:"current_version"`
 	DownloadURL    string    `json:"download_url"`
+	ChecksumsURL   string    `json:"checksums_url,omitempty"`
+	SignatureURL   string    `json:"signature_url,omitempty"`
 	ReleaseURL     string    `json:"release_url"`
 }
 
@@ -70,16 +73,14 @@ type UpdateInfo struct {
 	CurrentVersion string
 	LatestVersion  string
 	DownloadURL    string
+	ChecksumsURL   string
+	SignatureURL   string
 	ReleaseURL     string
 }
 
 // getCacheDir returns the cache directory path
 func getCacheDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".agent-deck"), nil
+	return deck.Home()
 }
 
 // loadCache loads the update cache from disk
@@ -146,6 +147,16 @@ func fetchLatestRelease() (*Release, error) {
 	return &release, nil
 }
 
+// getNamedAssetURL returns the download URL of a release asset ("" if absent)
+func getNamedAssetURL(release *Release, name string) string {
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return asset.BrowserDownloadURL
+		}
+	}
+	return ""
+}
+
 // getAssetURL returns the download URL for the current platform
 func getAssetURL(release *Release) string {
 	goos := runtime.GOOS
@@ -213,6 +224,8 @@ func CheckForUpdate(currentVersion string, forceCheck bool) (*UpdateInfo, error)
 			// Cache is fresh, use it
 			info.LatestVersion = cache.LatestVersion
 			info.DownloadURL = cache.DownloadURL
+			info.ChecksumsURL = cache.ChecksumsURL
+			info.SignatureURL = cache.SignatureURL
 			info.ReleaseURL = cache.ReleaseURL
 			info.Available = CompareVersions(currentVersion, cache.LatestVersion) < 0
 			return info, nil
@@ -234,12 +247,16 @@ func CheckForUpdate(currentVersion string, forceCheck bool) (*UpdateInfo, error)
 		LatestVersion:  latestVersion,
 		CurrentVersion: currentVersion,
 		DownloadURL:    downloadURL,
+		ChecksumsURL:   getNamedAssetURL(release, ChecksumsAssetName),
+		SignatureURL:   getNamedAssetURL(release, SignatureAssetName),
 		ReleaseURL:     release.HTMLURL,
 	}
 	_ = saveCache(cache) // Ignore cache save errors
 
 	info.LatestVersion = latestVersion
 	info.DownloadURL = downloadURL
+	info.ChecksumsURL = cache.ChecksumsURL
+	info.SignatureURL = cache.SignatureURL
 	info.ReleaseURL = release.HTMLURL
 	info.Available = CompareVersions(currentVersion, latestVersion) < 0
 
@@ -265,87 +282,6 @@ func CheckForUpdateAsync(currentVersion string) <-chan *UpdateInfo {
 	return ch
 }
 
-// PerformUpdate downloads and installs the latest version
-func PerformUpdate(downloadURL string) error {
-	if downloadURL == "" {
-		return fmt.Errorf("no download URL available for %s/%s", runtime.GOOS, runtime.GOARCH)
-	}
-
-	// Get current executable path
-	execPath, err := os.Executable()
-	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
-	}
-
-	// Resolve symlinks to get actual binary location
-	execPath, err = filepath.EvalSymlinks(execPath)
-	if err != nil {
-		return fmt.Errorf("failed to resolve symlinks: %w", err)
-	}
-
-	// Download the release
-	fmt.Printf("Downloading from %s...\n", downloadURL)
-	client := &http.Client{Timeout: 120 * time.Second}
-	resp, err := client.Get(downloadURL)
-	if err != nil {
-		return fmt.Errorf("failed to download: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
-	}
-
-	// Create temp file for download
-	tmpFile, err := os.CreateTemp("", "agent-deck-update-*.tar.gz")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath)
-
-	// Copy download to temp file
-	fmt.Println("Downloading...")
-	_, err = io.Copy(tmpFile, resp.Body)
-	tmpFile.Close()
-	if err != nil {
-		return fmt.Errorf("failed to save download: %w", err)
-	}
-
-	// Extract the binary from tarball
-	fmt.Println("Extracting...")
-	binaryData, err := extractBinaryFromTarGz(tmpPath)
-	if err != nil {
-		return fmt.Errorf("failed to extract: %w", err)
-	}
-
-	// Create temp file for new binary
-	newBinaryPath := execPath + ".new"
-	if err := os.WriteFile(newBinaryPath, binaryData, 0755); err != nil {
-		return fmt.Errorf("failed to write new binary: %w", err)
-	}
-
-	// Backup old binary
-	oldBinaryPath := execPath + ".old"
-	if err := os.Rename(execPath, oldBinaryPath); err != nil {
-		os.Remove(newBinaryPath)
-		return fmt.Errorf("failed to backup old binary: %w", err)
-	}
-
-	// Move new binary into place
-	if err := os.Rename(newBinaryPath, execPath); err != nil {
-		// Try to restore old binary
-		_ = os.Rename(oldBinaryPath, execPath)
-		return fmt.Errorf("failed to install new binary: %w", err)
-	}
-
-	// Remove old binary
-	os.Remove(oldBinaryPath)
-
-	fmt.Println("✓ Update complete!")
-	return nil
-}
-
 // ChangelogEntry represents a single version's changelog
 type ChangelogEntry struct {
 	Version string
@@ -532,12 +468,12 @@ func extractBinaryFromTarGz(tarPath string) ([]byte, error) {
 // This keeps bridge behavior in sync with the currently running binary.
 func UpdateBridgePy() error {
 	// Get the conductor directory
-	home, err := os.UserHomeDir()
+	home, err := deck.Home()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	conductorDir := filepath.Join(home, ".agent-deck", "conductor")
+	conductorDir := filepath.Join(home, "conductor")
 	bridgePath := filepath.Join(conductorDir, "bridge.py")
 
 	// Check if conductor directory exists
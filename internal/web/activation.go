@@ -0,0 +1,109 @@
+package web
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket activation.
+const listenFDsStart = 3
+
+// activationFDCount parses the LISTEN_PID/LISTEN_FDS protocol (sd_listen_fds(3)).
+// It returns 0 when the process was not socket-activated or the variables were
+// meant for a different process.
+func activationFDCount(getenv func(string) string, pid int) (int, error) {
+	fdsStr := getenv("LISTEN_FDS")
+	if fdsStr == "" {
+		return 0, nil
+	}
+	if pidStr := getenv("LISTEN_PID"); pidStr != "" {
+		listenPID, err := strconv.Atoi(pidStr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid LISTEN_PID %q: %w", pidStr, err)
+		}
+		if listenPID != pid {
+			return 0, nil
+		}
+	}
+	n, err := strconv.Atoi(fdsStr)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid LISTEN_FDS %q", fdsStr)
+	}
+	return n, nil
+}
+
+// ActivationListeners returns listeners inherited via systemd socket activation.
+// It returns nil when the process was started normally. The LISTEN_* variables
+// are cleared so child processes (tmux, tools) don't try to reuse the sockets.
+func ActivationListeners() ([]net.Listener, error) {
+	n, err := activationFDCount(os.Getenv, os.Getpid())
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, n)
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		_ = f.Close() // FileListener dups the descriptor
+		if err != nil {
+			for _, prev := range listeners {
+				_ = prev.Close()
+			}
+			return nil, fmt.Errorf("activated fd %d is not a listening socket: %w", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// idleTracker records request activity so a socket-activated server can exit
+// once nothing has used it for a while. Long-lived SSE/WS connections count as
+// in flight for their whole lifetime, so an open dashboard keeps the server up.
+type idleTracker struct {
+	inflight     atomic.Int64
+	lastActivity atomic.Int64 // unix nanos
+}
+
+func newIdleTracker() *idleTracker {
+	t := &idleTracker{}
+	t.touch()
+	return t
+}
+
+func (t *idleTracker) touch() {
+	t.lastActivity.Store(time.Now().UnixNano())
+}
+
+// idleFor returns how long the server has been idle (0 while requests are in flight).
+func (t *idleTracker) idleFor(now time.Time) time.Duration {
+	if t.inflight.Load() > 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(0, t.lastActivity.Load()))
+}
+
+// begin marks a request in flight until the returned func is called.
+func (t *idleTracker) begin() func() {
+	t.inflight.Add(1)
+	t.touch()
+	return func() {
+		t.touch()
+		t.inflight.Add(-1)
+	}
+}
+
+func (t *idleTracker) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer t.begin()()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestActivationFDCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    int
+		wantErr bool
+	}{
+		{"not activated", map[string]string{}, 0, false},
+		{"activated", map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "2"}, 2, false},
+		{"no pid check", map[string]string{"LISTEN_FDS": "1"}, 1, false},
+		{"other process", map[string]string{"LISTEN_PID": "7", "LISTEN_FDS": "1"}, 0, false},
+		{"bad fds", map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "x"}, 0, true},
+		{"bad pid", map[string]string{"LISTEN_PID": "x", "LISTEN_FDS": "1"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(k string) string { return tt.env[k] }
+			got, err := activationFDCount(getenv, 42)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("count = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdleTrackerCountsInflightAsBusy(t *testing.T) {
+	tracker := newIdleTracker()
+	release := make(chan struct{})
+	entered := make(chan struct{})
+	handler := tracker.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		close(done)
+	}()
+	<-entered
+
+	future := time.Now().Add(time.Hour)
+	if idle := tracker.idleFor(future); idle != 0 {
+		t.Errorf("idleFor with request in flight = %v, want 0", idle)
+	}
+	close(release)
+	<-done
+	if idle := tracker.idleFor(future); idle < 59*time.Minute {
+		t.Errorf("idleFor after request finished = %v, want ~1h", idle)
+	}
+}
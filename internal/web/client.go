@@ -0,0 +1,135 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// UnsupportedCapabilityError is returned when a web server lacks a capability
+// a request needs, e.g. an older or newer server than the CLI.
+type UnsupportedCapabilityError struct {
+	Capabilities []string
+}
+
+func (e *UnsupportedCapabilityError) Error() string {
+	return "web server lacks capability: " + strings.Join(e.Capabilities, ", ")
+}
+
+// FetchMenuSnapshot asks a running web server for its menu snapshot, accepting
+// a cached copy up to maxAge old. Used by CLI commands to avoid re-capturing
+// every pane when a server for the profile is already polling.
+func FetchMenuSnapshot(ctx context.Context, ep *session.WebEndpoint, maxAge time.Duration) (*MenuSnapshot, error) {
+	query := url.Values{"maxAge": {maxAge.String()}}
+	req, err := newEndpointRequest(ctx, ep, http.MethodGet, "/api/menu", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(RequireHeader, CapMenuMaxAge)
+
+	var snapshot MenuSnapshot
+	if err := doEndpointRequest(req, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// SubmitBatch runs a batch on a running web server. Set batch.RequestID when
+// the batch may be submitted again (e.g. replayed from an OfflineQueue) so
+// the server applies it only once. Errors that mean the server could not be
+// reached are *UnreachableError.
+func SubmitBatch(ctx context.Context, ep *session.WebEndpoint, batch BatchRequest) (*BatchResponse, error) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+	req, err := newEndpointRequest(ctx, ep, http.MethodPost, "/api/batch", nil, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if batch.RequestID != "" {
+		req.Header.Set(RequireHeader, CapBatchRequest)
+	}
+
+	var resp BatchResponse
+	if err := doEndpointRequest(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UnreachableError is returned when no web server answered, as opposed to
+// one answering with an error.
+type UnreachableError struct {
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return "web server unreachable: " + e.Err.Error()
+}
+
+func (e *UnreachableError) Unwrap() error { return e.Err }
+
+// newEndpointRequest builds an authenticated request to a web endpoint.
+// Wildcard listen addresses are dialed on loopback.
+func newEndpointRequest(ctx context.Context, ep *session.WebEndpoint, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	host, port, err := net.SplitHostPort(ep.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid web endpoint %q: %w", ep.Addr, err)
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	u := url.URL{
+		Scheme:   "http",
+		Host:     net.JoinHostPort(host, port),
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if ep.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+ep.Token)
+	}
+	req.Header.Set(ProtocolHeader, strconv.Itoa(ProtocolVersion))
+	return req, nil
+}
+
+// doEndpointRequest sends req and decodes a 200 response into out.
+func doEndpointRequest(req *http.Request, out any) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return &UnreachableError{Err: err}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil {
+			if apiErr.Error.Code == ErrCodeUnsupportedCapability {
+				return &UnsupportedCapabilityError{Capabilities: apiErr.Error.Capabilities}
+			}
+			if apiErr.Error.Message != "" {
+				return fmt.Errorf("web server returned %s: %s", resp.Status, apiErr.Error.Message)
+			}
+		}
+		return fmt.Errorf("web server returned %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
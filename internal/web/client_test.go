@@ -0,0 +1,61 @@
+package web
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+type countingMenuDataLoader struct {
+	loads int
+}
+
+func (c *countingMenuDataLoader) LoadMenuSnapshot() (*MenuSnapshot, error) {
+	c.loads++
+	return &MenuSnapshot{
+		Profile:       "test-profile",
+		GeneratedAt:   time.Now(),
+		TotalSessions: 1,
+		Items: []MenuItem{
+			{Type: MenuItemTypeSession, Session: &MenuSession{ID: "sess-1", Status: session.StatusWaiting}},
+		},
+	}, nil
+}
+
+func TestFetchMenuSnapshotUsesServerCache(t *testing.T) {
+	loader := &countingMenuDataLoader{}
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Token: "secret"})
+	srv.menuData = loader
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	ep := &session.WebEndpoint{Addr: strings.TrimPrefix(ts.URL, "http://"), Token: "secret"}
+	for i := 0; i < 3; i++ {
+		snapshot, err := FetchMenuSnapshot(context.Background(), ep, time.Minute)
+		if err != nil {
+			t.Fatalf("FetchMenuSnapshot: %v", err)
+		}
+		if snapshot.Items[0].Session.Status != session.StatusWaiting {
+			t.Errorf("unexpected status %q", snapshot.Items[0].Session.Status)
+		}
+	}
+	if loader.loads != 1 {
+		t.Errorf("expected one load within maxAge, got %d", loader.loads)
+	}
+
+	if _, err := FetchMenuSnapshot(context.Background(), ep, 0); err != nil {
+		t.Fatalf("FetchMenuSnapshot: %v", err)
+	}
+	if loader.loads != 2 {
+		t.Errorf("maxAge 0 should force a reload, got %d loads", loader.loads)
+	}
+
+	ep.Token = "wrong"
+	if _, err := FetchMenuSnapshot(context.Background(), ep, time.Minute); err == nil {
+		t.Error("expected error with wrong token")
+	}
+}
@@ -0,0 +1,1432 @@
+// Agent Deck web API over gRPC.
+//
+// This is the same API as the JSON endpoints of "agent-deck web" (/healthz,
+// /api/menu, /api/session/{id}, /api/batch, /events/menu), for clients that
+// want typed, versioned stubs. Serve it with "agent-deck web --grpc-listen".
+//
+// Authentication: when the server has a token, send it as gRPC metadata
+// "authorization: Bearer <token>".
+//
+// Versioning: agentdeck.v1 only changes compatibly (new fields, methods and
+// enum values). Breaking changes get a new package, agentdeck.v2.
+//
+// Negotiation: Health advertises the server's protocol version and
+// capabilities. A call may send "x-agent-deck-require: cap1,cap2" metadata;
+// when the server lacks one it fails with UNIMPLEMENTED and an ErrorInfo
+// detail (reason UNSUPPORTED_CAPABILITY, metadata "capabilities").
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: agentdeck/v1/deck.proto
+
+package deckpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{0}
+}
+
+type HealthResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Ok       bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Profile  string                 `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	ReadOnly bool                   `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Time     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	// ProtocolVersion only increases on incompatible changes. Servers that
+	// predate negotiation leave it 0.
+	ProtocolVersion int32 `protobuf:"varint,5,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// Capabilities are what the server supports, e.g. "menu.events" or
+	// "batch.macro" (one per batch op).
+	Capabilities  []string `protobuf:"bytes,6,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HealthResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *HealthResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *HealthResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetProtocolVersion() int32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+type GetMenuRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMenuRequest) Reset() {
+	*x = GetMenuRequest{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMenuRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMenuRequest) ProtoMessage() {}
+
+func (x *GetMenuRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMenuRequest.ProtoReflect.Descriptor instead.
+func (*GetMenuRequest) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{2}
+}
+
+type GetMenuResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *MenuSnapshot          `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMenuResponse) Reset() {
+	*x = GetMenuResponse{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMenuResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMenuResponse) ProtoMessage() {}
+
+func (x *GetMenuResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMenuResponse.ProtoReflect.Descriptor instead.
+func (*GetMenuResponse) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetMenuResponse) GetSnapshot() *MenuSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type WatchMenuRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMenuRequest) Reset() {
+	*x = WatchMenuRequest{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMenuRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMenuRequest) ProtoMessage() {}
+
+func (x *WatchMenuRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMenuRequest.ProtoReflect.Descriptor instead.
+func (*WatchMenuRequest) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{4}
+}
+
+type WatchMenuResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *MenuSnapshot          `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMenuResponse) Reset() {
+	*x = WatchMenuResponse{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMenuResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMenuResponse) ProtoMessage() {}
+
+func (x *WatchMenuResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMenuResponse.ProtoReflect.Descriptor instead.
+func (*WatchMenuResponse) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WatchMenuResponse) GetSnapshot() *MenuSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type MenuSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	GeneratedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	TotalGroups   int32                  `protobuf:"varint,3,opt,name=total_groups,json=totalGroups,proto3" json:"total_groups,omitempty"`
+	TotalSessions int32                  `protobuf:"varint,4,opt,name=total_sessions,json=totalSessions,proto3" json:"total_sessions,omitempty"`
+	Items         []*MenuItem            `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MenuSnapshot) Reset() {
+	*x = MenuSnapshot{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MenuSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MenuSnapshot) ProtoMessage() {}
+
+func (x *MenuSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MenuSnapshot.ProtoReflect.Descriptor instead.
+func (*MenuSnapshot) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MenuSnapshot) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *MenuSnapshot) GetGeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return nil
+}
+
+func (x *MenuSnapshot) GetTotalGroups() int32 {
+	if x != nil {
+		return x.TotalGroups
+	}
+	return 0
+}
+
+func (x *MenuSnapshot) GetTotalSessions() int32 {
+	if x != nil {
+		return x.TotalSessions
+	}
+	return 0
+}
+
+func (x *MenuSnapshot) GetItems() []*MenuItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// MenuItem is one row of the flattened menu: a group or a session.
+type MenuItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Index int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Level int32                  `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Path  string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// Types that are valid to be assigned to Item:
+	//
+	//	*MenuItem_Group
+	//	*MenuItem_Session
+	Item                isMenuItem_Item `protobuf_oneof:"item"`
+	IsLastInGroup       bool            `protobuf:"varint,6,opt,name=is_last_in_group,json=isLastInGroup,proto3" json:"is_last_in_group,omitempty"`
+	IsSubSession        bool            `protobuf:"varint,7,opt,name=is_sub_session,json=isSubSession,proto3" json:"is_sub_session,omitempty"`
+	IsLastSubSession    bool            `protobuf:"varint,8,opt,name=is_last_sub_session,json=isLastSubSession,proto3" json:"is_last_sub_session,omitempty"`
+	ParentIsLastInGroup bool            `protobuf:"varint,9,opt,name=parent_is_last_in_group,json=parentIsLastInGroup,proto3" json:"parent_is_last_in_group,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *MenuItem) Reset() {
+	*x = MenuItem{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MenuItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MenuItem) ProtoMessage() {}
+
+func (x *MenuItem) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MenuItem.ProtoReflect.Descriptor instead.
+func (*MenuItem) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MenuItem) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *MenuItem) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *MenuItem) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MenuItem) GetItem() isMenuItem_Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *MenuItem) GetGroup() *MenuGroup {
+	if x != nil {
+		if x, ok := x.Item.(*MenuItem_Group); ok {
+			return x.Group
+		}
+	}
+	return nil
+}
+
+func (x *MenuItem) GetSession() *Session {
+	if x != nil {
+		if x, ok := x.Item.(*MenuItem_Session); ok {
+			return x.Session
+		}
+	}
+	return nil
+}
+
+func (x *MenuItem) GetIsLastInGroup() bool {
+	if x != nil {
+		return x.IsLastInGroup
+	}
+	return false
+}
+
+func (x *MenuItem) GetIsSubSession() bool {
+	if x != nil {
+		return x.IsSubSession
+	}
+	return false
+}
+
+func (x *MenuItem) GetIsLastSubSession() bool {
+	if x != nil {
+		return x.IsLastSubSession
+	}
+	return false
+}
+
+func (x *MenuItem) GetParentIsLastInGroup() bool {
+	if x != nil {
+		return x.ParentIsLastInGroup
+	}
+	return false
+}
+
+type isMenuItem_Item interface {
+	isMenuItem_Item()
+}
+
+type MenuItem_Group struct {
+	Group *MenuGroup `protobuf:"bytes,4,opt,name=group,proto3,oneof"`
+}
+
+type MenuItem_Session struct {
+	Session *Session `protobuf:"bytes,5,opt,name=session,proto3,oneof"`
+}
+
+func (*MenuItem_Group) isMenuItem_Item() {}
+
+func (*MenuItem_Session) isMenuItem_Item() {}
+
+type MenuGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Expanded      bool                   `protobuf:"varint,3,opt,name=expanded,proto3" json:"expanded,omitempty"`
+	Order         int32                  `protobuf:"varint,4,opt,name=order,proto3" json:"order,omitempty"`
+	SessionCount  int32                  `protobuf:"varint,5,opt,name=session_count,json=sessionCount,proto3" json:"session_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MenuGroup) Reset() {
+	*x = MenuGroup{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MenuGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MenuGroup) ProtoMessage() {}
+
+func (x *MenuGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MenuGroup.ProtoReflect.Descriptor instead.
+func (*MenuGroup) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MenuGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MenuGroup) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MenuGroup) GetExpanded() bool {
+	if x != nil {
+		return x.Expanded
+	}
+	return false
+}
+
+func (x *MenuGroup) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *MenuGroup) GetSessionCount() int32 {
+	if x != nil {
+		return x.SessionCount
+	}
+	return 0
+}
+
+type Session struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Tool  string                 `protobuf:"bytes,3,opt,name=tool,proto3" json:"tool,omitempty"`
+	// Status is one of running, waiting, idle, error, starting, throttled.
+	Status           string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	GroupPath        string                 `protobuf:"bytes,5,opt,name=group_path,json=groupPath,proto3" json:"group_path,omitempty"`
+	ProjectPath      string                 `protobuf:"bytes,6,opt,name=project_path,json=projectPath,proto3" json:"project_path,omitempty"`
+	ParentSessionId  string                 `protobuf:"bytes,7,opt,name=parent_session_id,json=parentSessionId,proto3" json:"parent_session_id,omitempty"`
+	Order            int32                  `protobuf:"varint,8,opt,name=order,proto3" json:"order,omitempty"`
+	TmuxSession      string                 `protobuf:"bytes,9,opt,name=tmux_session,json=tmuxSession,proto3" json:"tmux_session,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastAccessedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
+	StateSince       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=state_since,json=stateSince,proto3" json:"state_since,omitempty"`
+	BusyTodaySeconds int64                  `protobuf:"varint,13,opt,name=busy_today_seconds,json=busyTodaySeconds,proto3" json:"busy_today_seconds,omitempty"`
+	Pinned           bool                   `protobuf:"varint,14,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	// Features are the session's own feature flag overrides.
+	Features      map[string]bool `protobuf:"bytes,15,rep,name=features,proto3" json:"features,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Session) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *Session) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Session) GetGroupPath() string {
+	if x != nil {
+		return x.GroupPath
+	}
+	return ""
+}
+
+func (x *Session) GetProjectPath() string {
+	if x != nil {
+		return x.ProjectPath
+	}
+	return ""
+}
+
+func (x *Session) GetParentSessionId() string {
+	if x != nil {
+		return x.ParentSessionId
+	}
+	return ""
+}
+
+func (x *Session) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *Session) GetTmuxSession() string {
+	if x != nil {
+		return x.TmuxSession
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Session) GetLastAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessedAt
+	}
+	return nil
+}
+
+func (x *Session) GetStateSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StateSince
+	}
+	return nil
+}
+
+func (x *Session) GetBusyTodaySeconds() int64 {
+	if x != nil {
+		return x.BusyTodaySeconds
+	}
+	return 0
+}
+
+func (x *Session) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *Session) GetFeatures() map[string]bool {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type GetSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Session       *Session               `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Index         int32                  `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Level         int32                  `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
+	Path          string                 `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionResponse) Reset() {
+	*x = GetSessionResponse{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionResponse) ProtoMessage() {}
+
+func (x *GetSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionResponse.ProtoReflect.Descriptor instead.
+func (*GetSessionResponse) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetSessionResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *GetSessionResponse) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *GetSessionResponse) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *GetSessionResponse) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *GetSessionResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type BatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ops   []*SessionOp           `protobuf:"bytes,1,rep,name=ops,proto3" json:"ops,omitempty"`
+	// RequestId makes the batch idempotent: repeating the ID of a batch the
+	// server already applied returns that batch's response instead of running
+	// it again. Up to 128 bytes; clients replaying queued batches set it.
+	RequestId     string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRequest) Reset() {
+	*x = BatchRequest{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRequest) ProtoMessage() {}
+
+func (x *BatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchRequest) GetOps() []*SessionOp {
+	if x != nil {
+		return x.Ops
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// SessionOp is one operation of a batch.
+type SessionOp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Op is one of get, stop, restart, send, macro.
+	Op string `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Text is sent to the session by "send".
+	Text string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	// Macro names the tool macro run by "macro".
+	Macro         string `protobuf:"bytes,4,opt,name=macro,proto3" json:"macro,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionOp) Reset() {
+	*x = SessionOp{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionOp) ProtoMessage() {}
+
+func (x *SessionOp) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionOp.ProtoReflect.Descriptor instead.
+func (*SessionOp) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SessionOp) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *SessionOp) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SessionOp) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SessionOp) GetMacro() string {
+	if x != nil {
+		return x.Macro
+	}
+	return ""
+}
+
+type BatchResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Profile string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Results are in request order.
+	Results []*BatchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	// Replayed is set when request_id had already been applied; results are
+	// those of the first run.
+	Replayed      bool `protobuf:"varint,3,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *BatchResponse) GetResults() []*BatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Op            string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Ok            bool                   `protobuf:"varint,4,opt,name=ok,proto3" json:"ok,omitempty"`
+	Session       *Session               `protobuf:"bytes,5,opt,name=session,proto3" json:"session,omitempty"`
+	Error         *Error                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchResult) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *BatchResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchResult) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *BatchResult) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *BatchResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Error codes match the JSON API: INVALID_REQUEST, READ_ONLY, NOT_FOUND,
+// NOT_RUNNING, EMERGENCY_STOPPED, MACRO_NOT_FOUND, OPERATION_FAILED,
+// UNSUPPORTED_CAPABILITY.
+type Error struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Code    string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Capabilities lists what was missing, for UNSUPPORTED_CAPABILITY.
+	Capabilities  []string `protobuf:"bytes,3,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_agentdeck_v1_deck_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_agentdeck_v1_deck_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+var File_agentdeck_v1_deck_proto protoreflect.FileDescriptor
+
+const file_agentdeck_v1_deck_proto_rawDesc = "" +
+	"\n" +
+	"\x17agentdeck/v1/deck.proto\x12\fagentdeck.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x0f\n" +
+	"\rHealthRequest\"\xd6\x01\n" +
+	"\x0eHealthResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x18\n" +
+	"\aprofile\x18\x02 \x01(\tR\aprofile\x12\x1b\n" +
+	"\tread_only\x18\x03 \x01(\bR\breadOnly\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12)\n" +
+	"\x10protocol_version\x18\x05 \x01(\x05R\x0fprotocolVersion\x12\"\n" +
+	"\fcapabilities\x18\x06 \x03(\tR\fcapabilities\"\x10\n" +
+	"\x0eGetMenuRequest\"I\n" +
+	"\x0fGetMenuResponse\x126\n" +
+	"\bsnapshot\x18\x01 \x01(\v2\x1a.agentdeck.v1.MenuSnapshotR\bsnapshot\"\x12\n" +
+	"\x10WatchMenuRequest\"K\n" +
+	"\x11WatchMenuResponse\x126\n" +
+	"\bsnapshot\x18\x01 \x01(\v2\x1a.agentdeck.v1.MenuSnapshotR\bsnapshot\"\xdf\x01\n" +
+	"\fMenuSnapshot\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12=\n" +
+	"\fgenerated_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vgeneratedAt\x12!\n" +
+	"\ftotal_groups\x18\x03 \x01(\x05R\vtotalGroups\x12%\n" +
+	"\x0etotal_sessions\x18\x04 \x01(\x05R\rtotalSessions\x12,\n" +
+	"\x05items\x18\x05 \x03(\v2\x16.agentdeck.v1.MenuItemR\x05items\"\xea\x02\n" +
+	"\bMenuItem\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\x05R\x05level\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12/\n" +
+	"\x05group\x18\x04 \x01(\v2\x17.agentdeck.v1.MenuGroupH\x00R\x05group\x121\n" +
+	"\asession\x18\x05 \x01(\v2\x15.agentdeck.v1.SessionH\x00R\asession\x12'\n" +
+	"\x10is_last_in_group\x18\x06 \x01(\bR\risLastInGroup\x12$\n" +
+	"\x0eis_sub_session\x18\a \x01(\bR\fisSubSession\x12-\n" +
+	"\x13is_last_sub_session\x18\b \x01(\bR\x10isLastSubSession\x124\n" +
+	"\x17parent_is_last_in_group\x18\t \x01(\bR\x13parentIsLastInGroupB\x06\n" +
+	"\x04item\"\x8a\x01\n" +
+	"\tMenuGroup\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1a\n" +
+	"\bexpanded\x18\x03 \x01(\bR\bexpanded\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\x05R\x05order\x12#\n" +
+	"\rsession_count\x18\x05 \x01(\x05R\fsessionCount\"\x84\x05\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04tool\x18\x03 \x01(\tR\x04tool\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"group_path\x18\x05 \x01(\tR\tgroupPath\x12!\n" +
+	"\fproject_path\x18\x06 \x01(\tR\vprojectPath\x12*\n" +
+	"\x11parent_session_id\x18\a \x01(\tR\x0fparentSessionId\x12\x14\n" +
+	"\x05order\x18\b \x01(\x05R\x05order\x12!\n" +
+	"\ftmux_session\x18\t \x01(\tR\vtmuxSession\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12D\n" +
+	"\x10last_accessed_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x12;\n" +
+	"\vstate_since\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"stateSince\x12,\n" +
+	"\x12busy_today_seconds\x18\r \x01(\x03R\x10busyTodaySeconds\x12\x16\n" +
+	"\x06pinned\x18\x0e \x01(\bR\x06pinned\x12?\n" +
+	"\bfeatures\x18\x0f \x03(\v2#.agentdeck.v1.Session.FeaturesEntryR\bfeatures\x1a;\n" +
+	"\rFeaturesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\"#\n" +
+	"\x11GetSessionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x9f\x01\n" +
+	"\x12GetSessionResponse\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12/\n" +
+	"\asession\x18\x02 \x01(\v2\x15.agentdeck.v1.SessionR\asession\x12\x14\n" +
+	"\x05index\x18\x03 \x01(\x05R\x05index\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\x05R\x05level\x12\x12\n" +
+	"\x04path\x18\x05 \x01(\tR\x04path\"X\n" +
+	"\fBatchRequest\x12)\n" +
+	"\x03ops\x18\x01 \x03(\v2\x17.agentdeck.v1.SessionOpR\x03ops\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"U\n" +
+	"\tSessionOp\x12\x0e\n" +
+	"\x02op\x18\x01 \x01(\tR\x02op\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12\x14\n" +
+	"\x05macro\x18\x04 \x01(\tR\x05macro\"z\n" +
+	"\rBatchResponse\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x123\n" +
+	"\aresults\x18\x02 \x03(\v2\x19.agentdeck.v1.BatchResultR\aresults\x12\x1a\n" +
+	"\breplayed\x18\x03 \x01(\bR\breplayed\"\xaf\x01\n" +
+	"\vBatchResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x0e\n" +
+	"\x02ok\x18\x04 \x01(\bR\x02ok\x12/\n" +
+	"\asession\x18\x05 \x01(\v2\x15.agentdeck.v1.SessionR\asession\x12)\n" +
+	"\x05error\x18\x06 \x01(\v2\x13.agentdeck.v1.ErrorR\x05error\"Y\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\"\n" +
+	"\fcapabilities\x18\x03 \x03(\tR\fcapabilities2\xfd\x02\n" +
+	"\vDeckService\x12C\n" +
+	"\x06Health\x12\x1b.agentdeck.v1.HealthRequest\x1a\x1c.agentdeck.v1.HealthResponse\x12F\n" +
+	"\aGetMenu\x12\x1c.agentdeck.v1.GetMenuRequest\x1a\x1d.agentdeck.v1.GetMenuResponse\x12O\n" +
+	"\n" +
+	"GetSession\x12\x1f.agentdeck.v1.GetSessionRequest\x1a .agentdeck.v1.GetSessionResponse\x12N\n" +
+	"\tWatchMenu\x12\x1e.agentdeck.v1.WatchMenuRequest\x1a\x1f.agentdeck.v1.WatchMenuResponse0\x01\x12@\n" +
+	"\x05Batch\x12\x1a.agentdeck.v1.BatchRequest\x1a\x1b.agentdeck.v1.BatchResponseB@Z>github.com/asheshgoplani/agent-deck/internal/web/deckpb;deckpbb\x06proto3"
+
+var (
+	file_agentdeck_v1_deck_proto_rawDescOnce sync.Once
+	file_agentdeck_v1_deck_proto_rawDescData []byte
+)
+
+func file_agentdeck_v1_deck_proto_rawDescGZIP() []byte {
+	file_agentdeck_v1_deck_proto_rawDescOnce.Do(func() {
+		file_agentdeck_v1_deck_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_agentdeck_v1_deck_proto_rawDesc), len(file_agentdeck_v1_deck_proto_rawDesc)))
+	})
+	return file_agentdeck_v1_deck_proto_rawDescData
+}
+
+var file_agentdeck_v1_deck_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_agentdeck_v1_deck_proto_goTypes = []any{
+	(*HealthRequest)(nil),         // 0: agentdeck.v1.HealthRequest
+	(*HealthResponse)(nil),        // 1: agentdeck.v1.HealthResponse
+	(*GetMenuRequest)(nil),        // 2: agentdeck.v1.GetMenuRequest
+	(*GetMenuResponse)(nil),       // 3: agentdeck.v1.GetMenuResponse
+	(*WatchMenuRequest)(nil),      // 4: agentdeck.v1.WatchMenuRequest
+	(*WatchMenuResponse)(nil),     // 5: agentdeck.v1.WatchMenuResponse
+	(*MenuSnapshot)(nil),          // 6: agentdeck.v1.MenuSnapshot
+	(*MenuItem)(nil),              // 7: agentdeck.v1.MenuItem
+	(*MenuGroup)(nil),             // 8: agentdeck.v1.MenuGroup
+	(*Session)(nil),               // 9: agentdeck.v1.Session
+	(*GetSessionRequest)(nil),     // 10: agentdeck.v1.GetSessionRequest
+	(*GetSessionResponse)(nil),    // 11: agentdeck.v1.GetSessionResponse
+	(*BatchRequest)(nil),          // 12: agentdeck.v1.BatchRequest
+	(*SessionOp)(nil),             // 13: agentdeck.v1.SessionOp
+	(*BatchResponse)(nil),         // 14: agentdeck.v1.BatchResponse
+	(*BatchResult)(nil),           // 15: agentdeck.v1.BatchResult
+	(*Error)(nil),                 // 16: agentdeck.v1.Error
+	nil,                           // 17: agentdeck.v1.Session.FeaturesEntry
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+}
+var file_agentdeck_v1_deck_proto_depIdxs = []int32{
+	18, // 0: agentdeck.v1.HealthResponse.time:type_name -> google.protobuf.Timestamp
+	6,  // 1: agentdeck.v1.GetMenuResponse.snapshot:type_name -> agentdeck.v1.MenuSnapshot
+	6,  // 2: agentdeck.v1.WatchMenuResponse.snapshot:type_name -> agentdeck.v1.MenuSnapshot
+	18, // 3: agentdeck.v1.MenuSnapshot.generated_at:type_name -> google.protobuf.Timestamp
+	7,  // 4: agentdeck.v1.MenuSnapshot.items:type_name -> agentdeck.v1.MenuItem
+	8,  // 5: agentdeck.v1.MenuItem.group:type_name -> agentdeck.v1.MenuGroup
+	9,  // 6: agentdeck.v1.MenuItem.session:type_name -> agentdeck.v1.Session
+	18, // 7: agentdeck.v1.Session.created_at:type_name -> google.protobuf.Timestamp
+	18, // 8: agentdeck.v1.Session.last_accessed_at:type_name -> google.protobuf.Timestamp
+	18, // 9: agentdeck.v1.Session.state_since:type_name -> google.protobuf.Timestamp
+	17, // 10: agentdeck.v1.Session.features:type_name -> agentdeck.v1.Session.FeaturesEntry
+	9,  // 11: agentdeck.v1.GetSessionResponse.session:type_name -> agentdeck.v1.Session
+	13, // 12: agentdeck.v1.BatchRequest.ops:type_name -> agentdeck.v1.SessionOp
+	15, // 13: agentdeck.v1.BatchResponse.results:type_name -> agentdeck.v1.BatchResult
+	9,  // 14: agentdeck.v1.BatchResult.session:type_name -> agentdeck.v1.Session
+	16, // 15: agentdeck.v1.BatchResult.error:type_name -> agentdeck.v1.Error
+	0,  // 16: agentdeck.v1.DeckService.Health:input_type -> agentdeck.v1.HealthRequest
+	2,  // 17: agentdeck.v1.DeckService.GetMenu:input_type -> agentdeck.v1.GetMenuRequest
+	10, // 18: agentdeck.v1.DeckService.GetSession:input_type -> agentdeck.v1.GetSessionRequest
+	4,  // 19: agentdeck.v1.DeckService.WatchMenu:input_type -> agentdeck.v1.WatchMenuRequest
+	12, // 20: agentdeck.v1.DeckService.Batch:input_type -> agentdeck.v1.BatchRequest
+	1,  // 21: agentdeck.v1.DeckService.Health:output_type -> agentdeck.v1.HealthResponse
+	3,  // 22: agentdeck.v1.DeckService.GetMenu:output_type -> agentdeck.v1.GetMenuResponse
+	11, // 23: agentdeck.v1.DeckService.GetSession:output_type -> agentdeck.v1.GetSessionResponse
+	5,  // 24: agentdeck.v1.DeckService.WatchMenu:output_type -> agentdeck.v1.WatchMenuResponse
+	14, // 25: agentdeck.v1.DeckService.Batch:output_type -> agentdeck.v1.BatchResponse
+	21, // [21:26] is the sub-list for method output_type
+	16, // [16:21] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_agentdeck_v1_deck_proto_init() }
+func file_agentdeck_v1_deck_proto_init() {
+	if File_agentdeck_v1_deck_proto != nil {
+		return
+	}
+	file_agentdeck_v1_deck_proto_msgTypes[7].OneofWrappers = []any{
+		(*MenuItem_Group)(nil),
+		(*MenuItem_Session)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_agentdeck_v1_deck_proto_rawDesc), len(file_agentdeck_v1_deck_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_agentdeck_v1_deck_proto_goTypes,
+		DependencyIndexes: file_agentdeck_v1_deck_proto_depIdxs,
+		MessageInfos:      file_agentdeck_v1_deck_proto_msgTypes,
+	}.Build()
+	File_agentdeck_v1_deck_proto = out.File
+	file_agentdeck_v1_deck_proto_goTypes = nil
+	file_agentdeck_v1_deck_proto_depIdxs = nil
+}
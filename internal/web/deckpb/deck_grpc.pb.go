@@ -0,0 +1,308 @@
+// Agent Deck web API over gRPC.
+//
+// This is the same API as the JSON endpoints of "agent-deck web" (/healthz,
+// /api/menu, /api/session/{id}, /api/batch, /events/menu), for clients that
+// want typed, versioned stubs. Serve it with "agent-deck web --grpc-listen".
+//
+// Authentication: when the server has a token, send it as gRPC metadata
+// "authorization: Bearer <token>".
+//
+// Versioning: agentdeck.v1 only changes compatibly (new fields, methods and
+// enum values). Breaking changes get a new package, agentdeck.v2.
+//
+// Negotiation: Health advertises the server's protocol version and
+// capabilities. A call may send "x-agent-deck-require: cap1,cap2" metadata;
+// when the server lacks one it fails with UNIMPLEMENTED and an ErrorInfo
+// detail (reason UNSUPPORTED_CAPABILITY, metadata "capabilities").
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: agentdeck/v1/deck.proto
+
+package deckpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DeckService_Health_FullMethodName     = "/agentdeck.v1.DeckService/Health"
+	DeckService_GetMenu_FullMethodName    = "/agentdeck.v1.DeckService/GetMenu"
+	DeckService_GetSession_FullMethodName = "/agentdeck.v1.DeckService/GetSession"
+	DeckService_WatchMenu_FullMethodName  = "/agentdeck.v1.DeckService/WatchMenu"
+	DeckService_Batch_FullMethodName      = "/agentdeck.v1.DeckService/Batch"
+)
+
+// DeckServiceClient is the client API for DeckService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DeckServiceClient interface {
+	// Health reports the server's profile and mode (GET /healthz).
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	// GetMenu returns the current menu snapshot (GET /api/menu).
+	GetMenu(ctx context.Context, in *GetMenuRequest, opts ...grpc.CallOption) (*GetMenuResponse, error)
+	// GetSession returns one session (GET /api/session/{id}).
+	GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*GetSessionResponse, error)
+	// WatchMenu sends the menu snapshot, then a new one whenever it changes
+	// (GET /events/menu).
+	WatchMenu(ctx context.Context, in *WatchMenuRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchMenuResponse], error)
+	// Batch runs several session reads and mutations in one call
+	// (POST /api/batch). One failing op does not abort the rest.
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+}
+
+type deckServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeckServiceClient(cc grpc.ClientConnInterface) DeckServiceClient {
+	return &deckServiceClient{cc}
+}
+
+func (c *deckServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, DeckService_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deckServiceClient) GetMenu(ctx context.Context, in *GetMenuRequest, opts ...grpc.CallOption) (*GetMenuResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMenuResponse)
+	err := c.cc.Invoke(ctx, DeckService_GetMenu_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deckServiceClient) GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*GetSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSessionResponse)
+	err := c.cc.Invoke(ctx, DeckService_GetSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deckServiceClient) WatchMenu(ctx context.Context, in *WatchMenuRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchMenuResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DeckService_ServiceDesc.Streams[0], DeckService_WatchMenu_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchMenuRequest, WatchMenuResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeckService_WatchMenuClient = grpc.ServerStreamingClient[WatchMenuResponse]
+
+func (c *deckServiceClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, DeckService_Batch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeckServiceServer is the server API for DeckService service.
+// All implementations must embed UnimplementedDeckServiceServer
+// for forward compatibility.
+type DeckServiceServer interface {
+	// Health reports the server's profile and mode (GET /healthz).
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	// GetMenu returns the current menu snapshot (GET /api/menu).
+	GetMenu(context.Context, *GetMenuRequest) (*GetMenuResponse, error)
+	// GetSession returns one session (GET /api/session/{id}).
+	GetSession(context.Context, *GetSessionRequest) (*GetSessionResponse, error)
+	// WatchMenu sends the menu snapshot, then a new one whenever it changes
+	// (GET /events/menu).
+	WatchMenu(*WatchMenuRequest, grpc.ServerStreamingServer[WatchMenuResponse]) error
+	// Batch runs several session reads and mutations in one call
+	// (POST /api/batch). One failing op does not abort the rest.
+	Batch(context.Context, *BatchRequest) (*BatchResponse, error)
+	mustEmbedUnimplementedDeckServiceServer()
+}
+
+// UnimplementedDeckServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDeckServiceServer struct{}
+
+func (UnimplementedDeckServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedDeckServiceServer) GetMenu(context.Context, *GetMenuRequest) (*GetMenuResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMenu not implemented")
+}
+func (UnimplementedDeckServiceServer) GetSession(context.Context, *GetSessionRequest) (*GetSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (UnimplementedDeckServiceServer) WatchMenu(*WatchMenuRequest, grpc.ServerStreamingServer[WatchMenuResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMenu not implemented")
+}
+func (UnimplementedDeckServiceServer) Batch(context.Context, *BatchRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Batch not implemented")
+}
+func (UnimplementedDeckServiceServer) mustEmbedUnimplementedDeckServiceServer() {}
+func (UnimplementedDeckServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeDeckServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeckServiceServer will
+// result in compilation errors.
+type UnsafeDeckServiceServer interface {
+	mustEmbedUnimplementedDeckServiceServer()
+}
+
+func RegisterDeckServiceServer(s grpc.ServiceRegistrar, srv DeckServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDeckServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DeckService_ServiceDesc, srv)
+}
+
+func _DeckService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeckServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeckService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeckServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeckService_GetMenu_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMenuRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeckServiceServer).GetMenu(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeckService_GetMenu_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeckServiceServer).GetMenu(ctx, req.(*GetMenuRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeckService_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeckServiceServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeckService_GetSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeckServiceServer).GetSession(ctx, req.(*GetSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeckService_WatchMenu_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchMenuRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeckServiceServer).WatchMenu(m, &grpc.GenericServerStream[WatchMenuRequest, WatchMenuResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeckService_WatchMenuServer = grpc.ServerStreamingServer[WatchMenuResponse]
+
+func _DeckService_Batch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeckServiceServer).Batch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeckService_Batch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeckServiceServer).Batch(ctx, req.(*BatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DeckService_ServiceDesc is the grpc.ServiceDesc for DeckService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeckService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "agentdeck.v1.DeckService",
+	HandlerType: (*DeckServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Health",
+			Handler:    _DeckService_Health_Handler,
+		},
+		{
+			MethodName: "GetMenu",
+			Handler:    _DeckService_GetMenu_Handler,
+		},
+		{
+			MethodName: "GetSession",
+			Handler:    _DeckService_GetSession_Handler,
+		},
+		{
+			MethodName: "Batch",
+			Handler:    _DeckService_Batch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchMenu",
+			Handler:       _DeckService_WatchMenu_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "agentdeck/v1/deck.proto",
+}
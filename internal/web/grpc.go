@@ -0,0 +1,334 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/web/deckpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// deckService serves the JSON API's data over gRPC (proto/agentdeck/v1).
+type deckService struct {
+	deckpb.UnimplementedDeckServiceServer
+	s *Server
+}
+
+// newGRPCServer returns a gRPC server for the deck service, guarded by the
+// server's token and counted as activity for --idle-exit.
+func (s *Server) newGRPCServer() *grpc.Server {
+	g := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.grpcUnaryInterceptor),
+		grpc.ChainStreamInterceptor(s.grpcStreamInterceptor),
+	)
+	deckpb.RegisterDeckServiceServer(g, &deckService{s: s})
+	return g
+}
+
+// serveGRPC listens on GRPCListenAddr and serves gRPC until Shutdown.
+func (s *Server) serveGRPC() error {
+	lis, err := net.Listen("tcp", s.cfg.GRPCListenAddr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.grpcServer.Serve(lis); err != nil {
+			logging.ForComponent(logging.CompWeb).Error("grpc_server_error",
+				slog.String("error", err.Error()))
+		}
+	}()
+	return nil
+}
+
+// stopGRPC stops the gRPC server gracefully, or forcibly once ctx ends.
+func (s *Server) stopGRPC(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+}
+
+func (s *Server) grpcUnaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !s.authorizeGRPC(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	_ = grpc.SetHeader(ctx, protocolMetadata())
+	if err := s.negotiateGRPC(ctx); err != nil {
+		return nil, err
+	}
+	defer s.idle.begin()()
+	return handler(ctx, req)
+}
+
+func (s *Server) grpcStreamInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !s.authorizeGRPC(ss.Context()) {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	_ = ss.SetHeader(protocolMetadata())
+	if err := s.negotiateGRPC(ss.Context()); err != nil {
+		return err
+	}
+	defer s.idle.begin()()
+	return handler(srv, ss)
+}
+
+func protocolMetadata() metadata.MD {
+	return metadata.Pairs(strings.ToLower(ProtocolHeader), strconv.Itoa(ProtocolVersion))
+}
+
+// negotiateGRPC checks the protocol metadata of a call (see negotiate).
+// Missing capabilities fail with Unimplemented and an ErrorInfo detail.
+func (s *Server) negotiateGRPC(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var version string
+	if values := md.Get(ProtocolHeader); len(values) > 0 {
+		version = values[0]
+	}
+	nerr := s.negotiate(version, md.Get(RequireHeader))
+	if nerr == nil {
+		return nil
+	}
+	code := codes.InvalidArgument
+	switch nerr.code {
+	case ErrCodeUnsupportedCapability:
+		code = codes.Unimplemented
+	case ErrCodeUnsupportedProtocol:
+		code = codes.FailedPrecondition
+	}
+	st := status.New(code, nerr.message)
+	info := &errdetails.ErrorInfo{Reason: nerr.code, Domain: "agentdeck.v1"}
+	if len(nerr.missing) > 0 {
+		info.Metadata = map[string]string{"capabilities": strings.Join(nerr.missing, ",")}
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// authorizeGRPC checks the "authorization: Bearer <token>" metadata.
+func (s *Server) authorizeGRPC(ctx context.Context) bool {
+	if s.cfg.Token == "" {
+		return true
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if token := bearerToken(value); token != "" && secureEqual(token, s.cfg.Token) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *deckService) Health(context.Context, *deckpb.HealthRequest) (*deckpb.HealthResponse, error) {
+	return &deckpb.HealthResponse{
+		Ok:              true,
+		Profile:         d.s.cfg.Profile,
+		ReadOnly:        d.s.cfg.ReadOnly,
+		Time:            timestamppb.New(time.Now().UTC()),
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    d.s.Capabilities(),
+	}, nil
+}
+
+func (d *deckService) GetMenu(context.Context, *deckpb.GetMenuRequest) (*deckpb.GetMenuResponse, error) {
+	snapshot, err := d.s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to load menu data")
+	}
+	return &deckpb.GetMenuResponse{Snapshot: menuSnapshotProto(snapshot)}, nil
+}
+
+func (d *deckService) GetSession(_ context.Context, req *deckpb.GetSessionRequest) (*deckpb.GetSessionResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "session id is required")
+	}
+	snapshot, err := d.s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to load session data")
+	}
+	for _, item := range snapshot.Items {
+		if item.Type != MenuItemTypeSession || item.Session == nil || item.Session.ID != req.GetId() {
+			continue
+		}
+		return &deckpb.GetSessionResponse{
+			Profile: snapshot.Profile,
+			Session: menuSessionProto(item.Session),
+			Index:   int32(item.Index),
+			Level:   int32(item.Level),
+			Path:    item.Path,
+		}, nil
+	}
+	return nil, status.Error(codes.NotFound, "session not found")
+}
+
+// WatchMenu mirrors /events/menu: the current snapshot first, then each
+// changed one, checked on change notifications and every poll interval.
+func (d *deckService) WatchMenu(_ *deckpb.WatchMenuRequest, stream deckpb.DeckService_WatchMenuServer) error {
+	s := d.s
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		return status.Error(codes.Internal, "failed to load menu data")
+	}
+	lastFingerprint := menuSnapshotFingerprint(snapshot)
+	if err := stream.Send(&deckpb.WatchMenuResponse{Snapshot: menuSnapshotProto(snapshot)}); err != nil {
+		return err
+	}
+
+	menuChanges := s.subscribeMenuChanges()
+	defer s.unsubscribeMenuChanges(menuChanges)
+
+	pollTicker := time.NewTicker(menuEventsPollInterval)
+	defer pollTicker.Stop()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.baseCtx.Done():
+			return nil
+		case <-menuChanges:
+		case <-pollTicker.C:
+		}
+		next, err := s.menuData.LoadMenuSnapshot()
+		if err != nil {
+			logging.ForComponent(logging.CompWeb).Error("menu_stream_refresh_failed",
+				slog.String("error", err.Error()))
+			continue
+		}
+		fingerprint := menuSnapshotFingerprint(next)
+		if fingerprint == lastFingerprint {
+			continue
+		}
+		if err := stream.Send(&deckpb.WatchMenuResponse{Snapshot: menuSnapshotProto(next)}); err != nil {
+			return err
+		}
+		lastFingerprint = fingerprint
+	}
+}
+
+func (d *deckService) Batch(_ context.Context, req *deckpb.BatchRequest) (*deckpb.BatchResponse, error) {
+	if len(req.GetOps()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ops is required")
+	}
+	if len(req.GetOps()) > maxBatchOps {
+		return nil, status.Error(codes.ResourceExhausted, "too many ops in batch")
+	}
+	if len(req.GetRequestId()) > maxRequestIDLength {
+		return nil, status.Error(codes.InvalidArgument, "request_id is too long")
+	}
+	ops := make([]SessionOp, len(req.GetOps()))
+	for i, op := range req.GetOps() {
+		ops[i] = SessionOp{Op: op.GetOp(), ID: op.GetId(), Text: op.GetText(), Macro: op.GetMacro()}
+	}
+	batch, err := d.s.runBatchOnce(req.GetRequestId(), ops)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to load session data")
+	}
+	resp := &deckpb.BatchResponse{
+		Profile:  batch.Profile,
+		Results:  make([]*deckpb.BatchResult, len(batch.Results)),
+		Replayed: batch.Replayed,
+	}
+	for i, result := range batch.Results {
+		out := &deckpb.BatchResult{
+			Index:   int32(result.Index),
+			Op:      result.Op,
+			Id:      result.ID,
+			Ok:      result.OK,
+			Session: menuSessionProto(result.Session),
+		}
+		if result.Error != nil {
+			out.Error = &deckpb.Error{
+				Code:         result.Error.Code,
+				Message:      result.Error.Message,
+				Capabilities: result.Error.Capabilities,
+			}
+		}
+		resp.Results[i] = out
+	}
+	return resp, nil
+}
+
+func menuSnapshotProto(snapshot *MenuSnapshot) *deckpb.MenuSnapshot {
+	out := &deckpb.MenuSnapshot{
+		Profile:       snapshot.Profile,
+		GeneratedAt:   timestampProto(snapshot.GeneratedAt),
+		TotalGroups:   int32(snapshot.TotalGroups),
+		TotalSessions: int32(snapshot.TotalSessions),
+		Items:         make([]*deckpb.MenuItem, len(snapshot.Items)),
+	}
+	for i, item := range snapshot.Items {
+		row := &deckpb.MenuItem{
+			Index:               int32(item.Index),
+			Level:               int32(item.Level),
+			Path:                item.Path,
+			IsLastInGroup:       item.IsLastInGroup,
+			IsSubSession:        item.IsSubSession,
+			IsLastSubSession:    item.IsLastSubSession,
+			ParentIsLastInGroup: item.ParentIsLastInGroup,
+		}
+		switch {
+		case item.Group != nil:
+			row.Item = &deckpb.MenuItem_Group{Group: &deckpb.MenuGroup{
+				Name:         item.Group.Name,
+				Path:         item.Group.Path,
+				Expanded:     item.Group.Expanded,
+				Order:        int32(item.Group.Order),
+				SessionCount: int32(item.Group.SessionCount),
+			}}
+		case item.Session != nil:
+			row.Item = &deckpb.MenuItem_Session{Session: menuSessionProto(item.Session)}
+		}
+		out.Items[i] = row
+	}
+	return out
+}
+
+func menuSessionProto(sess *MenuSession) *deckpb.Session {
+	if sess == nil {
+		return nil
+	}
+	return &deckpb.Session{
+		Id:               sess.ID,
+		Title:            sess.Title,
+		Tool:             sess.Tool,
+		Status:           string(sess.Status),
+		GroupPath:        sess.GroupPath,
+		ProjectPath:      sess.ProjectPath,
+		ParentSessionId:  sess.ParentSessionID,
+		Order:            int32(sess.Order),
+		TmuxSession:      sess.TmuxSession,
+		CreatedAt:        timestampProto(sess.CreatedAt),
+		LastAccessedAt:   timestampProto(sess.LastAccessedAt),
+		StateSince:       timestampProto(sess.StateSince),
+		BusyTodaySeconds: sess.BusyTodaySecs,
+		Pinned:           sess.Pinned,
+		Features:         sess.Features,
+	}
+}
+
+// timestampProto leaves unset times unset rather than sending year 1.
+func timestampProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
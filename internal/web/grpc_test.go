@@ -0,0 +1,173 @@
+package web
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/web/deckpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// lockedMenuData is a MenuDataLoader whose snapshot tests can swap while a
+// stream is reading it.
+type lockedMenuData struct {
+	mu       sync.Mutex
+	snapshot *MenuSnapshot
+}
+
+func (l *lockedMenuData) LoadMenuSnapshot() (*MenuSnapshot, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.snapshot, nil
+}
+
+func (l *lockedMenuData) set(snapshot *MenuSnapshot) {
+	l.mu.Lock()
+	l.snapshot = snapshot
+	l.mu.Unlock()
+}
+
+// grpcTestClient serves srv's gRPC service in memory and returns a client.
+func grpcTestClient(t *testing.T, srv *Server) deckpb.DeckServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.grpcServer.Serve(lis) }()
+	t.Cleanup(srv.grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return deckpb.NewDeckServiceClient(conn)
+}
+
+func TestGRPCRequiresToken(t *testing.T) {
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile", Token: "secret", GRPCListenAddr: "127.0.0.1:0"})
+	client := grpcTestClient(t, srv)
+
+	_, err := client.Health(context.Background(), &deckpb.HealthRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without a token, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	resp, err := client.Health(ctx, &deckpb.HealthRequest{})
+	if err != nil {
+		t.Fatalf("health with token: %v", err)
+	}
+	if !resp.GetOk() || resp.GetProfile() != "test-profile" {
+		t.Errorf("unexpected health response: %v", resp)
+	}
+}
+
+func TestGRPCGetSessionAndBatch(t *testing.T) {
+	mutator := &fakeSessionMutator{errs: map[string]error{"sess-2": errSessionNotRunning}}
+	srv := batchTestServer(false, mutator)
+	srv.grpcServer = srv.newGRPCServer()
+	client := grpcTestClient(t, srv)
+	ctx := context.Background()
+
+	got, err := client.GetSession(ctx, &deckpb.GetSessionRequest{Id: "sess-2"})
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if got.GetSession().GetTitle() != "two" || got.GetIndex() != 2 {
+		t.Errorf("unexpected session: %v", got)
+	}
+	if _, err := client.GetSession(ctx, &deckpb.GetSessionRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	resp, err := client.Batch(ctx, &deckpb.BatchRequest{Ops: []*deckpb.SessionOp{
+		{Op: SessionOpGet, Id: "sess-1"},
+		{Op: SessionOpSend, Id: "sess-2", Text: "hi"},
+	}})
+	if err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if len(resp.GetResults()) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(resp.GetResults()))
+	}
+	if r := resp.GetResults()[0]; !r.GetOk() || r.GetSession().GetTitle() != "one" {
+		t.Errorf("get should return the session: %v", r)
+	}
+	if r := resp.GetResults()[1]; r.GetOk() || r.GetError().GetCode() != "NOT_RUNNING" {
+		t.Errorf("send should report NOT_RUNNING: %v", r)
+	}
+	if len(mutator.calls) != 1 || mutator.calls[0][0].Text != "hi" {
+		t.Errorf("send should reach the mutator with its text: %v", mutator.calls)
+	}
+
+	if _, err := client.Batch(ctx, &deckpb.BatchRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an empty batch, got %v", err)
+	}
+
+	idempotent := &deckpb.BatchRequest{RequestId: "req-1", Ops: []*deckpb.SessionOp{{Op: SessionOpStop, Id: "sess-1"}}}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Batch(ctx, idempotent)
+		if err != nil {
+			t.Fatalf("batch: %v", err)
+		}
+		if resp.GetReplayed() != (i == 1) || !resp.GetResults()[0].GetOk() {
+			t.Errorf("run %d: unexpected response %v", i, resp)
+		}
+	}
+	if len(mutator.calls) != 2 {
+		t.Errorf("a repeated request_id should not be applied again, got %d calls", len(mutator.calls))
+	}
+}
+
+func TestGRPCWatchMenuSendsChanges(t *testing.T) {
+	data := &lockedMenuData{snapshot: &MenuSnapshot{Profile: "test-profile"}}
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile", MenuData: data, GRPCListenAddr: "127.0.0.1:0"})
+	client := grpcTestClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchMenu(ctx, &deckpb.WatchMenuRequest{})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("first snapshot: %v", err)
+	}
+	if first.GetSnapshot().GetTotalSessions() != 0 {
+		t.Fatalf("unexpected first snapshot: %v", first)
+	}
+
+	data.set(&MenuSnapshot{
+		Profile:       "test-profile",
+		TotalSessions: 1,
+		Items: []MenuItem{{Type: MenuItemTypeSession, Session: &MenuSession{
+			ID: "sess-1", Title: "one", Status: "running", CreatedAt: time.Unix(1700000000, 0),
+		}}},
+	})
+	srv.notifyMenuChanged()
+
+	next, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("changed snapshot: %v", err)
+	}
+	items := next.GetSnapshot().GetItems()
+	if len(items) != 1 || items[0].GetSession().GetStatus() != "running" {
+		t.Fatalf("unexpected changed snapshot: %v", next)
+	}
+	if got := items[0].GetSession().GetCreatedAt().AsTime(); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("created_at = %v", got)
+	}
+	if items[0].GetSession().GetLastAccessedAt() != nil {
+		t.Error("unset times should stay unset")
+	}
+}
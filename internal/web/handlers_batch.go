@@ -0,0 +1,199 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// maxBatchOps caps a single /api/batch request so one call can't pin the server.
+const maxBatchOps = 500
+
+// maxRequestIDLength caps BatchRequest.RequestID.
+const maxRequestIDLength = 128
+
+// BatchRequest is the body of POST /api/batch.
+type BatchRequest struct {
+	// RequestID makes the batch idempotent: a batch repeating the ID of one
+	// already applied gets that batch's response instead of running again.
+	// Set by clients that may replay it (see OfflineQueue).
+	RequestID string      `json:"requestId,omitempty"`
+	Ops       []SessionOp `json:"ops"`
+}
+
+// BatchResult is the outcome of one op of a batch.
+type BatchResult struct {
+	Index   int          `json:"index"`
+	Op      string       `json:"op"`
+	ID      string       `json:"id"`
+	OK      bool         `json:"ok"`
+	Session *MenuSession `json:"session,omitempty"`
+	Error   *apiError    `json:"error,omitempty"`
+}
+
+// BatchResponse is the response of POST /api/batch.
+type BatchResponse struct {
+	Profile string        `json:"profile"`
+	Results []BatchResult `json:"results"`
+	// Replayed is set when RequestID had already been applied; Results are
+	// those of the first run.
+	Replayed bool `json:"replayed,omitempty"`
+}
+
+// handleBatch runs several session reads/mutations in one round trip.
+// Results are returned in request order; one failing op does not abort the rest.
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+		return
+	}
+
+	var req BatchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid batch body")
+		return
+	}
+	if len(req.Ops) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "ops is required")
+		return
+	}
+	if len(req.Ops) > maxBatchOps {
+		writeAPIError(w, http.StatusRequestEntityTooLarge, "TOO_MANY_OPS", "too many ops in batch")
+		return
+	}
+	if len(req.RequestID) > maxRequestIDLength {
+		writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "requestId is too long")
+		return
+	}
+
+	resp, err := s.runBatchOnce(req.RequestID, req.Ops)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load session data")
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runBatchOnce runs ops once per request ID: repeating the ID of a batch
+// already applied returns its recorded response. Batches without an ID
+// always run. Shared by /api/batch and gRPC Batch.
+func (s *Server) runBatchOnce(requestID string, ops []SessionOp) (*BatchResponse, error) {
+	if requestID != "" {
+		// Serialize ID'd batches so a replay racing its original waits for it
+		s.requestMu.Lock()
+		defer s.requestMu.Unlock()
+		if data, ok := s.requests.Lookup(requestID); ok {
+			var resp BatchResponse
+			if err := json.Unmarshal(data, &resp); err == nil {
+				resp.Replayed = true
+				return &resp, nil
+			}
+		}
+	}
+
+	snapshot, results, err := s.runBatch(ops)
+	if err != nil {
+		return nil, err
+	}
+	resp := &BatchResponse{Profile: snapshot.Profile, Results: results}
+	if requestID != "" {
+		if data, err := json.Marshal(resp); err == nil {
+			s.requests.Record(requestID, data)
+		}
+	}
+	return resp, nil
+}
+
+// runBatch applies ops and returns a result per op, in order, with the
+// snapshot the results were read from.
+func (s *Server) runBatch(ops []SessionOp) (*MenuSnapshot, []BatchResult, error) {
+	results := make([]BatchResult, len(ops))
+	var mutations []SessionOp
+	var mutationIdx []int
+	for i, op := range ops {
+		results[i] = BatchResult{Index: i, Op: op.Op, ID: op.ID}
+		switch {
+		case op.ID == "":
+			results[i].Error = &apiError{Code: "INVALID_REQUEST", Message: "id is required"}
+		case op.Op == SessionOpMacro && op.Macro == "":
+			results[i].Error = &apiError{Code: "INVALID_REQUEST", Message: "macro is required"}
+		case op.Op == SessionOpGet:
+			// Filled from the snapshot below
+		case op.Op == SessionOpStop || op.Op == SessionOpRestart || op.Op == SessionOpSend || op.Op == SessionOpMacro:
+			if s.cfg.ReadOnly {
+				results[i].Error = &apiError{Code: "READ_ONLY", Message: "server is read-only"}
+				continue
+			}
+			mutations = append(mutations, op)
+			mutationIdx = append(mutationIdx, i)
+		default:
+			results[i].Error = &apiError{
+				Code:         ErrCodeUnsupportedCapability,
+				Message:      "unsupported op " + strconv.Quote(op.Op),
+				Capabilities: []string{batchOpCapability(op.Op)},
+			}
+		}
+	}
+
+	if len(mutations) > 0 {
+		errs := s.mutator.Mutate(mutations)
+		for j, idx := range mutationIdx {
+			var err error
+			if j < len(errs) {
+				err = errs[j]
+			}
+			if err != nil {
+				results[idx].Error = mutationError(err)
+			}
+		}
+		s.notifyMenuChanged()
+	}
+
+	// Load the snapshot after mutations so returned sessions reflect them.
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	sessions := make(map[string]*MenuSession, snapshot.TotalSessions)
+	for _, item := range snapshot.Items {
+		if item.Type == MenuItemTypeSession && item.Session != nil {
+			sessions[item.Session.ID] = item.Session
+		}
+	}
+
+	for i := range results {
+		if results[i].Error != nil {
+			continue
+		}
+		sess := sessions[results[i].ID]
+		if sess == nil && results[i].Op == SessionOpGet {
+			results[i].Error = &apiError{Code: "NOT_FOUND", Message: "session not found"}
+			continue
+		}
+		results[i].OK = true
+		results[i].Session = sess
+	}
+	return snapshot, results, nil
+}
+
+func mutationError(err error) *apiError {
+	switch {
+	case errors.Is(err, errSessionNotFound):
+		return &apiError{Code: "NOT_FOUND", Message: err.Error()}
+	case errors.Is(err, errSessionNotRunning):
+		return &apiError{Code: "NOT_RUNNING", Message: err.Error()}
+	case errors.Is(err, errDeckStopped):
+		return &apiError{Code: "EMERGENCY_STOPPED", Message: err.Error()}
+	case errors.Is(err, session.ErrMacroNotFound):
+		return &apiError{Code: "MACRO_NOT_FOUND", Message: err.Error()}
+	default:
+		return &apiError{Code: "OPERATION_FAILED", Message: err.Error()}
+	}
+}
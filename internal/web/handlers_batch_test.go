@@ -0,0 +1,218 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+type fakeSessionMutator struct {
+	calls [][]SessionOp
+	errs  map[string]error
+}
+
+func (f *fakeSessionMutator) Mutate(ops []SessionOp) []error {
+	f.calls = append(f.calls, ops)
+	errs := make([]error, len(ops))
+	for i, op := range ops {
+		errs[i] = f.errs[op.ID]
+	}
+	return errs
+}
+
+func batchTestServer(readOnly bool, mutator *fakeSessionMutator) *Server {
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile", ReadOnly: readOnly, Mutator: mutator})
+	srv.menuData = &fakeMenuDataLoader{
+		snapshot: &MenuSnapshot{
+			Profile:       "test-profile",
+			TotalSessions: 2,
+			Items: []MenuItem{
+				{Index: 0, Type: MenuItemTypeGroup, Group: &MenuGroup{Name: "work", Path: "work"}},
+				{Index: 1, Type: MenuItemTypeSession, Session: &MenuSession{ID: "sess-1", Title: "one"}},
+				{Index: 2, Type: MenuItemTypeSession, Session: &MenuSession{ID: "sess-2", Title: "two"}},
+			},
+		},
+	}
+	return srv
+}
+
+func postBatch(t *testing.T, srv *Server, body string) BatchResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var resp BatchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func TestBatchEndpointMixedOps(t *testing.T) {
+	mutator := &fakeSessionMutator{errs: map[string]error{"sess-2": errSessionNotRunning}}
+	srv := batchTestServer(false, mutator)
+
+	resp := postBatch(t, srv, `{"ops":[
+		{"op":"get","id":"sess-1"},
+		{"op":"stop","id":"sess-1"},
+		{"op":"send","id":"sess-2","text":"hi"},
+		{"op":"get","id":"missing"},
+		{"op":"explode","id":"sess-1"}
+	]}`)
+
+	if len(mutator.calls) != 1 || len(mutator.calls[0]) != 2 {
+		t.Fatalf("mutations should be applied in a single call, got %v", mutator.calls)
+	}
+	if len(resp.Results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(resp.Results))
+	}
+	if !resp.Results[0].OK || resp.Results[0].Session == nil || resp.Results[0].Session.Title != "one" {
+		t.Errorf("get should return the session: %+v", resp.Results[0])
+	}
+	if !resp.Results[1].OK {
+		t.Errorf("stop should succeed: %+v", resp.Results[1])
+	}
+	if resp.Results[2].OK || resp.Results[2].Error == nil || resp.Results[2].Error.Code != "NOT_RUNNING" {
+		t.Errorf("send to stopped session should fail with NOT_RUNNING: %+v", resp.Results[2])
+	}
+	if resp.Results[3].Error == nil || resp.Results[3].Error.Code != "NOT_FOUND" {
+		t.Errorf("get of unknown id should be NOT_FOUND: %+v", resp.Results[3])
+	}
+	if resp.Results[4].Error == nil || resp.Results[4].Error.Code != ErrCodeUnsupportedCapability {
+		t.Errorf("unknown op should be UNSUPPORTED_CAPABILITY: %+v", resp.Results[4])
+	}
+}
+
+func TestBatchEndpointMacroOp(t *testing.T) {
+	mutator := &fakeSessionMutator{errs: map[string]error{
+		"sess-2": fmt.Errorf("%w: %q for tool shell", session.ErrMacroNotFound, "compact"),
+	}}
+	srv := batchTestServer(false, mutator)
+
+	resp := postBatch(t, srv, `{"ops":[
+		{"op":"macro","id":"sess-1","macro":"compact"},
+		{"op":"macro","id":"sess-2","macro":"compact"},
+		{"op":"macro","id":"sess-1"}
+	]}`)
+
+	if len(mutator.calls) != 1 || len(mutator.calls[0]) != 2 {
+		t.Fatalf("expected one mutator call with 2 ops, got %v", mutator.calls)
+	}
+	if got := mutator.calls[0][0].Macro; got != "compact" {
+		t.Errorf("macro name should reach the mutator, got %q", got)
+	}
+	if !resp.Results[0].OK {
+		t.Errorf("macro should succeed: %+v", resp.Results[0])
+	}
+	if resp.Results[1].Error == nil || resp.Results[1].Error.Code != "MACRO_NOT_FOUND" {
+		t.Errorf("unknown macro should be MACRO_NOT_FOUND: %+v", resp.Results[1])
+	}
+	if resp.Results[2].Error == nil || resp.Results[2].Error.Code != "INVALID_REQUEST" {
+		t.Errorf("macro without a name should be INVALID_REQUEST: %+v", resp.Results[2])
+	}
+}
+
+func TestBatchEndpointReadOnlyRejectsMutations(t *testing.T) {
+	mutator := &fakeSessionMutator{}
+	srv := batchTestServer(true, mutator)
+
+	resp := postBatch(t, srv, `{"ops":[{"op":"stop","id":"sess-1"},{"op":"get","id":"sess-2"}]}`)
+	if len(mutator.calls) != 0 {
+		t.Fatalf("read-only server must not call mutator")
+	}
+	if resp.Results[0].Error == nil || resp.Results[0].Error.Code != "READ_ONLY" {
+		t.Errorf("expected READ_ONLY, got %+v", resp.Results[0])
+	}
+	if !resp.Results[1].OK {
+		t.Errorf("reads should still work in read-only mode: %+v", resp.Results[1])
+	}
+}
+
+func TestBatchEndpointRejectsEmptyAndOversized(t *testing.T) {
+	srv := batchTestServer(false, &fakeSessionMutator{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(`{"ops":[]}`))
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("empty batch: expected %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	ops := make([]string, maxBatchOps+1)
+	for i := range ops {
+		ops[i] = `{"op":"get","id":"sess-1"}`
+	}
+	req = httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(`{"ops":[`+strings.Join(ops, ",")+`]}`))
+	rr = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized batch: expected %d, got %d", http.StatusRequestEntityTooLarge, rr.Code)
+	}
+}
+
+func TestMenuStreamEndpoint(t *testing.T) {
+	srv := batchTestServer(false, &fakeSessionMutator{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/menu/stream?type=session", nil)
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("content type = %q", ct)
+	}
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 session lines, got %d: %q", len(lines), rr.Body.String())
+	}
+	var item MenuItem
+	if err := json.Unmarshal([]byte(lines[1]), &item); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	if item.Session == nil || item.Session.ID != "sess-2" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/menu/stream?type=bogus", nil)
+	rr = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid type: expected %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestBatchEndpointRequestIDAppliesOnce(t *testing.T) {
+	mutator := &fakeSessionMutator{}
+	srv := batchTestServer(false, mutator)
+
+	body := `{"requestId":"req-1","ops":[{"op":"stop","id":"sess-1"}]}`
+	first := postBatch(t, srv, body)
+	second := postBatch(t, srv, body)
+
+	if len(mutator.calls) != 1 {
+		t.Fatalf("a repeated request ID should not be applied again, got %d calls", len(mutator.calls))
+	}
+	if first.Replayed || !second.Replayed {
+		t.Errorf("expected only the repeat to be marked replayed: first=%v second=%v", first.Replayed, second.Replayed)
+	}
+	if len(second.Results) != 1 || !second.Results[0].OK {
+		t.Errorf("replay should return the first results: %+v", second.Results)
+	}
+
+	postBatch(t, srv, `{"requestId":"req-2","ops":[{"op":"stop","id":"sess-1"}]}`)
+	postBatch(t, srv, `{"ops":[{"op":"stop","id":"sess-1"}]}`)
+	postBatch(t, srv, `{"ops":[{"op":"stop","id":"sess-1"}]}`)
+	if len(mutator.calls) != 4 {
+		t.Errorf("new and ID-less batches should always run, got %d calls", len(mutator.calls))
+	}
+}
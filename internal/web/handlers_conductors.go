@@ -0,0 +1,109 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// maxConductorLoadDays caps the ?days window for /api/conductors/load.
+const maxConductorLoadDays = 90
+
+// ConductorLoadReporter provides per-day conductor throughput for the dashboard.
+type ConductorLoadReporter interface {
+	ConductorLoadReport(days int) ([]statedb.ConductorLoadRow, error)
+}
+
+// profileConductorLoad reports on the conductors that belong to one profile.
+type profileConductorLoad struct {
+	profile string
+}
+
+// NewProfileConductorLoad returns a reporter for the conductors of profile.
+func NewProfileConductorLoad(profile string) ConductorLoadReporter {
+	return &profileConductorLoad{profile: profile}
+}
+
+func (p *profileConductorLoad) ConductorLoadReport(days int) ([]statedb.ConductorLoadRow, error) {
+	conductors, err := session.ListConductorsForProfile(session.GetEffectiveProfile(p.profile))
+	if err != nil {
+		return nil, err
+	}
+	return session.ConductorLoadReportFor(conductors, days, time.Now())
+}
+
+type conductorLoadResponse struct {
+	Profile string                     `json:"profile"`
+	Days    int                        `json:"days"`
+	Rows    []statedb.ConductorLoadRow `json:"rows"`
+}
+
+// handleConductorLoad returns tasks/day and latency medians per conductor.
+// Optional ?days=N (default 7) selects the window, including today.
+func (s *Server) handleConductorLoad(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+		return
+	}
+
+	days := 7
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxConductorLoadDays {
+			writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "days must be between 1 and 90")
+			return
+		}
+		days = n
+	}
+
+	rows, err := s.conductorLoad.ConductorLoadReport(days)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load conductor report")
+		return
+	}
+	if rows == nil {
+		rows = []statedb.ConductorLoadRow{}
+	}
+	writeJSON(w, http.StatusOK, conductorLoadResponse{Profile: s.cfg.Profile, Days: days, Rows: rows})
+}
+
+// FleetSummaryLoader provides the cross-profile conductor rollup.
+type FleetSummaryLoader interface {
+	LoadFleetSummary() (*session.FleetSummary, error)
+}
+
+type sessionFleetLoader struct{}
+
+func (sessionFleetLoader) LoadFleetSummary() (*session.FleetSummary, error) {
+	return session.BuildFleetSummary(time.Now())
+}
+
+// handleFleet returns conductor state counts, oldest un-acked escalation and
+// spend today for every profile that has conductors.
+func (s *Server) handleFleet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+		return
+	}
+
+	summary, err := s.fleet.LoadFleetSummary()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load fleet summary")
+		return
+	}
+	if summary.Profiles == nil {
+		summary.Profiles = []session.FleetProfile{}
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+type fakeConductorLoad struct {
+	days int
+	rows []statedb.ConductorLoadRow
+}
+
+func (f *fakeConductorLoad) ConductorLoadReport(days int) ([]statedb.ConductorLoadRow, error) {
+	f.days = days
+	return f.rows, nil
+}
+
+func TestConductorLoadEndpoint(t *testing.T) {
+	load := &fakeConductorLoad{rows: []statedb.ConductorLoadRow{
+		{Conductor: "ops", Day: "2026-03-10", Enqueued: 3, Completed: 2, MedianLatency: 40 * time.Second},
+	}}
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile", ConductorLoad: load})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/conductors/load?days=14", nil)
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if load.days != 14 {
+		t.Errorf("days = %d, want 14", load.days)
+	}
+	var resp conductorLoadResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Profile != "test-profile" || len(resp.Rows) != 1 || resp.Rows[0].MedianLatency != 40*time.Second {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	for _, bad := range []string{"0", "91", "x"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/conductors/load?days="+bad, nil)
+		rr = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("days=%s: expected 400, got %d", bad, rr.Code)
+		}
+	}
+}
+
+type fakeFleetLoader struct {
+	summary *session.FleetSummary
+}
+
+func (f *fakeFleetLoader) LoadFleetSummary() (*session.FleetSummary, error) {
+	return f.summary, nil
+}
+
+func TestFleetEndpoint(t *testing.T) {
+	fleet := &fakeFleetLoader{summary: &session.FleetSummary{
+		Profiles: []session.FleetProfile{{Profile: "work", Conductors: 2, States: map[string]int{"running": 2}, SpendToday: 3.5}},
+	}}
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile", Fleet: fleet})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/fleet", nil)
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp session.FleetSummary
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Profiles) != 1 || resp.Profiles[0].States["running"] != 2 || resp.Profiles[0].SpendToday != 3.5 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
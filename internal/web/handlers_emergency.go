@@ -0,0 +1,73 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+type emergencyStopRequest struct {
+	Reason string `json:"reason"`
+}
+
+type emergencyStopResponse struct {
+	Stopped       bool                        `json:"stopped"`
+	EmergencyStop *session.EmergencyStopState `json:"emergencyStop,omitempty"`
+	Error         string                      `json:"error,omitempty"`
+}
+
+// handleEmergencyStop is the deck's big red button: GET reports the stop,
+// POST stops the deck (interrupting every busy session) and DELETE resumes it.
+func (s *Server) handleEmergencyStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+		return
+	}
+	if r.Method != http.MethodGet && s.cfg.ReadOnly {
+		writeAPIError(w, http.StatusForbidden, "READ_ONLY", "server is read-only")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req emergencyStopRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+				writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid emergency stop body")
+				return
+			}
+		}
+		state, err := session.EmergencyStop(req.Reason, "web")
+		if state == nil {
+			writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+			return
+		}
+		resp := emergencyStopResponse{Stopped: true, EmergencyStop: state}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		s.notifyMenuChanged()
+		writeJSON(w, http.StatusOK, resp)
+	case http.MethodDelete:
+		state, err := session.ResumeEmergencyStop()
+		if errors.Is(err, session.ErrNotEmergencyStopped) {
+			writeAPIError(w, http.StatusConflict, "NOT_STOPPED", err.Error())
+			return
+		}
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+			return
+		}
+		s.notifyMenuChanged()
+		writeJSON(w, http.StatusOK, emergencyStopResponse{EmergencyStop: state})
+	default:
+		state := session.LoadEmergencyStop()
+		writeJSON(w, http.StatusOK, emergencyStopResponse{Stopped: state != nil, EmergencyStop: state})
+	}
+}
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEmergencyStopEndpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile"})
+	do := func(method, body string) (int, emergencyStopResponse) {
+		req := httptest.NewRequest(method, "/api/emergency-stop", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rr, req)
+		var resp emergencyStopResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+		return rr.Code, resp
+	}
+
+	if code, resp := do(http.MethodGet, ""); code != http.StatusOK || resp.Stopped {
+		t.Fatalf("initial status: code %d, %+v", code, resp)
+	}
+	code, resp := do(http.MethodPost, `{"reason":"runaway agent"}`)
+	if code != http.StatusOK || !resp.Stopped || resp.EmergencyStop.Reason != "runaway agent" || resp.EmergencyStop.By != "web" {
+		t.Fatalf("stop: code %d, %+v", code, resp)
+	}
+	if code, resp := do(http.MethodGet, ""); code != http.StatusOK || !resp.Stopped {
+		t.Fatalf("status after stop: code %d, %+v", code, resp)
+	}
+	if code, resp := do(http.MethodDelete, ""); code != http.StatusOK || resp.Stopped {
+		t.Fatalf("resume: code %d, %+v", code, resp)
+	}
+	if code, _ := do(http.MethodDelete, ""); code != http.StatusConflict {
+		t.Errorf("resuming a running deck: expected 409, got %d", code)
+	}
+	if code, _ := do(http.MethodPut, ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("PUT: expected 405, got %d", code)
+	}
+
+	readOnly := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile", ReadOnly: true})
+	rr := httptest.NewRecorder()
+	readOnly.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/emergency-stop", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("read-only stop: expected 403, got %d", rr.Code)
+	}
+}
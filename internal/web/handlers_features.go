@@ -0,0 +1,51 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+type featuresResponse struct {
+	Profile   string                 `json:"profile"`
+	SessionID string                 `json:"sessionId,omitempty"`
+	Features  []session.FeatureState `json:"features"`
+}
+
+// handleFeatures returns the effective feature flags of the deck, or of one
+// session with ?session=<id> (its own overrides applied on top).
+func (s *Server) handleFeatures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+		return
+	}
+
+	resp := featuresResponse{Profile: s.cfg.Profile}
+	var overrides map[string]bool
+	if id := r.URL.Query().Get("session"); id != "" {
+		snapshot, err := s.menuData.LoadMenuSnapshot()
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load session data")
+			return
+		}
+		var found *MenuSession
+		for _, item := range snapshot.Items {
+			if item.Type == MenuItemTypeSession && item.Session != nil && item.Session.ID == id {
+				found = item.Session
+				break
+			}
+		}
+		if found == nil {
+			writeAPIError(w, http.StatusNotFound, "NOT_FOUND", "session not found")
+			return
+		}
+		resp.SessionID = id
+		overrides = found.Features
+	}
+	resp.Features = session.ResolveFeatures(s.cfg.Profile, overrides)
+	writeJSON(w, http.StatusOK, resp)
+}
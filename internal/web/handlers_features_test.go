@@ -0,0 +1,59 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestFeaturesEndpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	session.ClearUserConfigCache()
+	defer session.ClearUserConfigCache()
+
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile"})
+	srv.menuData = &fakeMenuDataLoader{
+		snapshot: &MenuSnapshot{
+			Profile: "test-profile",
+			Items: []MenuItem{
+				{Index: 0, Type: MenuItemTypeSession, Session: &MenuSession{ID: "sess-1", Features: map[string]bool{session.FeatureAutoRestart: true}}},
+			},
+		},
+	}
+
+	get := func(path string) (int, featuresResponse) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rr := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rr, req)
+		var resp featuresResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+		return rr.Code, resp
+	}
+
+	code, resp := get("/api/features")
+	if code != http.StatusOK || len(resp.Features) != len(session.FeatureFlags) {
+		t.Fatalf("deck features: code %d, %+v", code, resp)
+	}
+	for _, f := range resp.Features {
+		if f.Enabled || f.Source != session.FeatureSourceDefault {
+			t.Errorf("%s should default to off: %+v", f.Name, f)
+		}
+	}
+
+	code, resp = get("/api/features?session=sess-1")
+	if code != http.StatusOK || resp.SessionID != "sess-1" {
+		t.Fatalf("session features: code %d, %+v", code, resp)
+	}
+	for _, f := range resp.Features {
+		if f.Name == session.FeatureAutoRestart && (!f.Enabled || f.Source != session.FeatureSourceSession) {
+			t.Errorf("session override not applied: %+v", f)
+		}
+	}
+
+	if code, _ := get("/api/features?session=missing"); code != http.StatusNotFound {
+		t.Errorf("unknown session: expected 404, got %d", code)
+	}
+}
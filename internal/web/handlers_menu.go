@@ -3,12 +3,16 @@ package web
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type apiError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// Capabilities lists what was missing, for UNSUPPORTED_CAPABILITY
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 type apiErrorResponse struct {
@@ -33,7 +37,18 @@ func (s *Server) handleMenu(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	snapshot, err := s.menuData.LoadMenuSnapshot()
+	var snapshot *MenuSnapshot
+	var err error
+	if raw := r.URL.Query().Get("maxAge"); raw != "" {
+		maxAge, perr := time.ParseDuration(raw)
+		if perr != nil || maxAge < 0 {
+			writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "maxAge must be a non-negative duration")
+			return
+		}
+		snapshot, err = s.cachedMenuSnapshot(maxAge)
+	} else {
+		snapshot, err = s.menuData.LoadMenuSnapshot()
+	}
 	if err != nil {
 		writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load menu data")
 		return
@@ -42,6 +57,81 @@ func (s *Server) handleMenu(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, snapshot)
 }
 
+// cachedMenuSnapshot returns the last snapshot if it was generated within
+// maxAge, otherwise loads a fresh one. CLI commands pass a freshness bound so
+// several terminals polling status share one round of pane captures.
+func (s *Server) cachedMenuSnapshot(maxAge time.Duration) (*MenuSnapshot, error) {
+	s.snapshotCacheMu.Lock()
+	defer s.snapshotCacheMu.Unlock()
+
+	if cached := s.snapshotCache; cached != nil && time.Since(cached.GeneratedAt) <= maxAge {
+		return cached, nil
+	}
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	s.snapshotCache = snapshot
+	return snapshot, nil
+}
+
+// menuStreamFlushEvery is how many JSONL lines are written between flushes.
+const menuStreamFlushEvery = 64
+
+// handleMenuStream writes the menu as JSON Lines, one MenuItem per line, so
+// large decks can be rendered incrementally instead of parsed as one document.
+// Optional ?type=session|group filters the items.
+func (s *Server) handleMenuStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+		return
+	}
+
+	itemType := r.URL.Query().Get("type")
+	if itemType != "" && itemType != MenuItemTypeSession && itemType != MenuItemTypeGroup {
+		writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "type must be session or group")
+		return
+	}
+
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load menu data")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Agent-Deck-Profile", snapshot.Profile)
+	w.Header().Set("X-Agent-Deck-Total-Sessions", strconv.Itoa(snapshot.TotalSessions))
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	written := 0
+	for _, item := range snapshot.Items {
+		if itemType != "" && item.Type != itemType {
+			continue
+		}
+		if r.Context().Err() != nil {
+			return
+		}
+		if err := enc.Encode(item); err != nil {
+			return
+		}
+		written++
+		if flusher != nil && written%menuStreamFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
 func (s *Server) handleSessionByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
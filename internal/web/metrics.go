@@ -0,0 +1,137 @@
+package web
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/metrics"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// metricsSnapshotMaxAge lets scrapes reuse a recent menu snapshot instead of
+// re-capturing every pane
+const metricsSnapshotMaxAge = 5 * time.Second
+
+// metricsStatuses are always exported, at zero when no session has them, so
+// alerts on a status don't see it vanish
+var metricsStatuses = []session.Status{
+	session.StatusRunning, session.StatusWaiting, session.StatusIdle,
+	session.StatusError, session.StatusStarting, session.StatusThrottled,
+}
+
+// newMetricsServer returns the HTTP server of Config.MetricsListenAddr. It
+// is separate from the API so scrapes need no token and don't keep an
+// idle-exit server alive.
+func (s *Server) newMetricsServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(s.collectMetrics))
+	return &http.Server{
+		Addr:              s.cfg.MetricsListenAddr,
+		Handler:           withRecover(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
+// serveMetrics starts the metrics server in the background.
+func (s *Server) serveMetrics() error {
+	lis, err := net.Listen("tcp", s.cfg.MetricsListenAddr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.metricsServer.Serve(lis); err != nil && err != http.ErrServerClosed {
+			logging.ForComponent(logging.CompWeb).Error("metrics_server_error",
+				slog.String("error", err.Error()))
+		}
+	}()
+	return nil
+}
+
+// collectMetrics writes the server's metrics. Counters come from the
+// profile's state database, so they include events of every agent-deck
+// process of the profile (e.g. heartbeat timers), not just this one.
+func (s *Server) collectMetrics(w *metrics.Writer) {
+	profile := metrics.Label{Name: "profile", Value: s.cfg.Profile}
+
+	if snapshot, err := s.cachedMenuSnapshot(metricsSnapshotMaxAge); err == nil {
+		counts := make(map[session.Status]int)
+		for _, item := range snapshot.Items {
+			if item.Type == MenuItemTypeSession && item.Session != nil {
+				counts[item.Session.Status]++
+			}
+		}
+		statuses := append([]session.Status(nil), metricsStatuses...)
+		for status := range counts {
+			if !containsStatus(statuses, status) {
+				statuses = append(statuses, status)
+			}
+		}
+		samples := make([]metrics.Sample, len(statuses))
+		for i, status := range statuses {
+			samples[i] = metrics.Sample{
+				Labels: []metrics.Label{profile, {Name: "status", Value: string(status)}},
+				Value:  float64(counts[status]),
+			}
+		}
+		w.Gauge("agent_deck_sessions", "Sessions by status.", samples...)
+	}
+
+	var counters map[string]int64
+	if db := statedb.GetGlobal(); db != nil {
+		counters, _ = db.ReadCounters()
+	}
+	var heartbeats []metrics.Sample
+	for name, value := range counters {
+		rest, ok := strings.CutPrefix(name, statedb.CounterHeartbeatPrefix)
+		if !ok {
+			continue
+		}
+		result, conductor, _ := strings.Cut(rest, ".")
+		heartbeats = append(heartbeats, metrics.Sample{
+			Labels: []metrics.Label{profile, {Name: "conductor", Value: conductor}, {Name: "result", Value: result}},
+			Value:  float64(value),
+		})
+	}
+	sort.Slice(heartbeats, func(i, j int) bool {
+		a, b := heartbeats[i].Labels, heartbeats[j].Labels
+		if a[1].Value != b[1].Value {
+			return a[1].Value < b[1].Value
+		}
+		return a[2].Value < b[2].Value
+	})
+	w.Counter("agent_deck_heartbeats_total", "Conductor heartbeats by result (sent, skipped, failed).", heartbeats...)
+	w.Counter("agent_deck_forks_total", "Forked sessions created.",
+		metrics.Sample{Labels: []metrics.Label{profile}, Value: float64(counters[statedb.CounterForks])})
+
+	session.StatusDetectionLatency.Write(w, "agent_deck_status_detection_seconds",
+		"Time to detect a session's status, in this process.")
+
+	w.Gauge("agent_deck_web_uptime_seconds", "Seconds since this web server started.",
+		metrics.Sample{Labels: []metrics.Label{profile}, Value: time.Since(s.startedAt).Seconds()})
+	uptime, up := s.bridgeUptime()
+	bridgeUp := 0.0
+	if up {
+		bridgeUp = 1
+	}
+	w.Gauge("agent_deck_bridge_up", "Whether the conductor bridge daemon is running.",
+		metrics.Sample{Value: bridgeUp})
+	if up {
+		w.Gauge("agent_deck_bridge_uptime_seconds", "Seconds since the conductor bridge daemon started.",
+			metrics.Sample{Value: uptime.Seconds()})
+	}
+}
+
+func containsStatus(statuses []session.Status, status session.Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
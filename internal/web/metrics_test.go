@@ -0,0 +1,77 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	db, err := statedb.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	prev := statedb.GetGlobal()
+	statedb.SetGlobal(db)
+	t.Cleanup(func() {
+		statedb.SetGlobal(prev)
+		db.Close()
+	})
+	_ = db.IncrCounter(session.HeartbeatCounterName(session.HeartbeatSent, "ops"), 4)
+	_ = db.IncrCounter(session.HeartbeatCounterName(session.HeartbeatFailed, "ops"), 1)
+	_ = db.IncrCounter(statedb.CounterForks, 2)
+
+	srv := NewServer(Config{
+		ListenAddr:        "127.0.0.1:0",
+		Profile:           "work",
+		MetricsListenAddr: "127.0.0.1:0",
+		BridgeUptime:      func() (time.Duration, bool) { return 90 * time.Second, true },
+	})
+	srv.menuData = &fakeMenuDataLoader{snapshot: &MenuSnapshot{
+		Profile: "work",
+		Items: []MenuItem{
+			{Type: MenuItemTypeSession, Session: &MenuSession{ID: "a", Status: session.StatusRunning}},
+			{Type: MenuItemTypeSession, Session: &MenuSession{ID: "b", Status: session.StatusRunning}},
+			{Type: MenuItemTypeSession, Session: &MenuSession{ID: "c", Status: session.StatusWaiting}},
+		},
+	}}
+
+	rr := httptest.NewRecorder()
+	srv.metricsServer.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		`agent_deck_sessions{profile="work",status="running"} 2`,
+		`agent_deck_sessions{profile="work",status="waiting"} 1`,
+		`agent_deck_sessions{profile="work",status="error"} 0`,
+		`agent_deck_heartbeats_total{profile="work",conductor="ops",result="failed"} 1`,
+		`agent_deck_heartbeats_total{profile="work",conductor="ops",result="sent"} 4`,
+		`agent_deck_forks_total{profile="work"} 2`,
+		`# TYPE agent_deck_status_detection_seconds histogram`,
+		`agent_deck_bridge_up 1`,
+		`agent_deck_bridge_uptime_seconds 90`,
+		`# TYPE agent_deck_web_uptime_seconds gauge`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+
+	// The API handler doesn't serve metrics, and the metrics one nothing else
+	rr = httptest.NewRecorder()
+	srv.metricsServer.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/menu", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for the API on the metrics server, got %d", rr.Code)
+	}
+}
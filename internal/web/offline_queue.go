@@ -0,0 +1,213 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// offlineQueueDirName is the profile subdirectory holding queued batches.
+const offlineQueueDirName = "offline-queue"
+
+// QueuedBatch is a batch waiting for the web server to come back.
+type QueuedBatch struct {
+	ID       string      `json:"id"`
+	QueuedAt time.Time   `json:"queuedAt"`
+	Ops      []SessionOp `json:"ops"`
+}
+
+// OfflineQueue holds mutating batches submitted while a profile's web server
+// was unreachable (e.g. restarting across suspend/resume), one file per
+// batch. Each batch keeps its request ID, so replaying it from several
+// clients, or after a replay whose response was lost, applies it only once.
+type OfflineQueue struct {
+	dir string
+}
+
+// NewOfflineQueue returns the offline queue of profile.
+func NewOfflineQueue(profile string) (*OfflineQueue, error) {
+	dir, err := session.GetProfileDir(session.GetEffectiveProfile(profile))
+	if err != nil {
+		return nil, err
+	}
+	return &OfflineQueue{dir: filepath.Join(dir, offlineQueueDirName)}, nil
+}
+
+// NewRequestID returns a batch request ID that sorts by creation time and
+// won't collide across processes or machines: a nanosecond timestamp and
+// 8 random bytes.
+func NewRequestID() string {
+	var suffix [8]byte
+	_, _ = rand.Read(suffix[:])
+	return fmt.Sprintf("%016x-%s", time.Now().UnixNano(), hex.EncodeToString(suffix[:]))
+}
+
+// Enqueue adds a batch to the queue, assigning it a request ID unless it
+// has one, and returns the ID.
+func (q *OfflineQueue) Enqueue(batch BatchRequest) (string, error) {
+	if batch.RequestID == "" {
+		batch.RequestID = NewRequestID()
+	}
+	if !validQueueID(batch.RequestID) {
+		return "", fmt.Errorf("invalid request id %q", batch.RequestID)
+	}
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create offline queue: %w", err)
+	}
+	data, err := json.MarshalIndent(QueuedBatch{ID: batch.RequestID, QueuedAt: time.Now(), Ops: batch.Ops}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	// Written aside and renamed so concurrent replays never read half a file
+	path := filepath.Join(q.dir, batch.RequestID+".json")
+	tmp := path + ".tmp." + strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to queue batch: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("failed to queue batch: %w", err)
+	}
+	return batch.RequestID, nil
+}
+
+// List returns the queued batches, oldest first. Unreadable entries are
+// skipped.
+func (q *OfflineQueue) List() ([]QueuedBatch, error) {
+	entries, err := os.ReadDir(q.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var batches []QueuedBatch
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || !validQueueID(id) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(q.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var batch QueuedBatch
+		if json.Unmarshal(data, &batch) != nil || batch.ID != id {
+			continue
+		}
+		batches = append(batches, batch)
+	}
+	sort.Slice(batches, func(i, j int) bool {
+		if !batches[i].QueuedAt.Equal(batches[j].QueuedAt) {
+			return batches[i].QueuedAt.Before(batches[j].QueuedAt)
+		}
+		return batches[i].ID < batches[j].ID
+	})
+	return batches, nil
+}
+
+// Remove drops a batch from the queue. Removing one already gone is not an
+// error: another client replayed it first.
+func (q *OfflineQueue) Remove(id string) error {
+	if !validQueueID(id) {
+		return fmt.Errorf("invalid request id %q", id)
+	}
+	err := os.Remove(filepath.Join(q.dir, id+".json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Clear drops every queued batch and returns how many there were.
+func (q *OfflineQueue) Clear() (int, error) {
+	batches, err := q.List()
+	if err != nil {
+		return 0, err
+	}
+	for _, batch := range batches {
+		if err := q.Remove(batch.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(batches), nil
+}
+
+// ReplayedBatch is the outcome of replaying one queued batch.
+type ReplayedBatch struct {
+	QueuedBatch
+	// Response is nil when the server rejected the batch (Error is set)
+	Response *BatchResponse `json:"response,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// Replay submits the queued batches to ep, oldest first, dropping each one
+// the server answered, including with an error: a rejected batch would be
+// rejected again. It stops at the first batch the server can't be reached
+// for, leaving it and later ones queued, and returns that *UnreachableError.
+func (q *OfflineQueue) Replay(ctx context.Context, ep *session.WebEndpoint) ([]ReplayedBatch, error) {
+	batches, err := q.List()
+	if err != nil {
+		return nil, err
+	}
+	return q.replay(batches, func(batch QueuedBatch) (*BatchResponse, error) {
+		return SubmitBatch(ctx, ep, BatchRequest{RequestID: batch.ID, Ops: batch.Ops})
+	})
+}
+
+// ReplayLocal applies the queued batches on s itself, for a server picking
+// up what clients queued while it was down.
+func (q *OfflineQueue) ReplayLocal(s *Server) ([]ReplayedBatch, error) {
+	batches, err := q.List()
+	if err != nil {
+		return nil, err
+	}
+	return q.replay(batches, func(batch QueuedBatch) (*BatchResponse, error) {
+		return s.runBatchOnce(batch.ID, batch.Ops)
+	})
+}
+
+// replay runs queued batches through submit, removing each one submitted.
+func (q *OfflineQueue) replay(batches []QueuedBatch, submit func(QueuedBatch) (*BatchResponse, error)) ([]ReplayedBatch, error) {
+	var done []ReplayedBatch
+	for _, batch := range batches {
+		resp, err := submit(batch)
+		var unreachable *UnreachableError
+		if errors.As(err, &unreachable) {
+			return done, err
+		}
+		replayed := ReplayedBatch{QueuedBatch: batch, Response: resp}
+		if err != nil {
+			replayed.Error = err.Error()
+		}
+		if rerr := q.Remove(batch.ID); rerr != nil {
+			return done, rerr
+		}
+		done = append(done, replayed)
+	}
+	return done, nil
+}
+
+// validQueueID reports whether id is safe as a queue file name.
+func validQueueID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength || id[0] == '.' {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
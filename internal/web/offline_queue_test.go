@@ -0,0 +1,113 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestOfflineQueueReplay(t *testing.T) {
+	mutator := &fakeSessionMutator{}
+	srv := batchTestServer(false, mutator)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	ep := &session.WebEndpoint{Addr: strings.TrimPrefix(ts.URL, "http://")}
+
+	queue := &OfflineQueue{dir: t.TempDir()}
+	first, err := queue.Enqueue(BatchRequest{Ops: []SessionOp{{Op: SessionOpStop, ID: "sess-1"}}})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	second, err := queue.Enqueue(BatchRequest{Ops: []SessionOp{{Op: SessionOpSend, ID: "sess-2", Text: "hi"}}})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if first == second {
+		t.Fatalf("request IDs should be unique, got %q twice", first)
+	}
+	batches, err := queue.List()
+	if err != nil || len(batches) != 2 || batches[0].ID != first {
+		t.Fatalf("expected both batches oldest first, got %+v (%v)", batches, err)
+	}
+
+	replayed, err := queue.Replay(context.Background(), ep)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(replayed) != 2 || replayed[0].Response == nil || !replayed[0].Response.Results[0].OK {
+		t.Fatalf("expected both batches replayed, got %+v", replayed)
+	}
+	if len(mutator.calls) != 2 || mutator.calls[0][0].Op != SessionOpStop || mutator.calls[1][0].Op != SessionOpSend {
+		t.Errorf("batches should be applied in queue order, got %v", mutator.calls)
+	}
+	if batches, _ := queue.List(); len(batches) != 0 {
+		t.Errorf("replayed batches should leave the queue, %d left", len(batches))
+	}
+
+	// A batch replayed again (e.g. its response was lost) is not reapplied
+	if _, err := queue.Enqueue(BatchRequest{RequestID: first, Ops: []SessionOp{{Op: SessionOpStop, ID: "sess-1"}}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	replayed, err = queue.Replay(context.Background(), ep)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(replayed) != 1 || !replayed[0].Response.Replayed {
+		t.Errorf("expected the repeat to be answered from the first run, got %+v", replayed)
+	}
+	if len(mutator.calls) != 2 {
+		t.Errorf("a replayed request ID should not be applied again, got %d calls", len(mutator.calls))
+	}
+}
+
+func TestOfflineQueueReplayKeepsBatchesWhenUnreachable(t *testing.T) {
+	ts := httptest.NewServer(nil)
+	ep := &session.WebEndpoint{Addr: strings.TrimPrefix(ts.URL, "http://")}
+	ts.Close()
+
+	queue := &OfflineQueue{dir: t.TempDir()}
+	if _, err := queue.Enqueue(BatchRequest{Ops: []SessionOp{{Op: SessionOpStop, ID: "sess-1"}}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	replayed, err := queue.Replay(context.Background(), ep)
+	var unreachable *UnreachableError
+	if !errors.As(err, &unreachable) {
+		t.Fatalf("expected UnreachableError, got %v", err)
+	}
+	if len(replayed) != 0 {
+		t.Errorf("nothing should be replayed, got %+v", replayed)
+	}
+	if batches, _ := queue.List(); len(batches) != 1 {
+		t.Errorf("the batch should stay queued, got %d", len(batches))
+	}
+}
+
+func TestOfflineQueueReplayLocal(t *testing.T) {
+	mutator := &fakeSessionMutator{}
+	srv := batchTestServer(false, mutator)
+
+	queue := &OfflineQueue{dir: t.TempDir()}
+	if _, err := queue.Enqueue(BatchRequest{Ops: []SessionOp{{Op: SessionOpRestart, ID: "sess-2"}}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	replayed, err := queue.ReplayLocal(srv)
+	if err != nil || len(replayed) != 1 {
+		t.Fatalf("expected one batch replayed, got %+v (%v)", replayed, err)
+	}
+	if len(mutator.calls) != 1 || mutator.calls[0][0].ID != "sess-2" {
+		t.Errorf("unexpected mutations %v", mutator.calls)
+	}
+}
+
+func TestOfflineQueueRejectsUnsafeIDs(t *testing.T) {
+	queue := &OfflineQueue{dir: t.TempDir()}
+	for _, id := range []string{"../escape", ".hidden", "a/b", strings.Repeat("x", maxRequestIDLength+1)} {
+		if _, err := queue.Enqueue(BatchRequest{RequestID: id}); err == nil {
+			t.Errorf("Enqueue(%q) should fail", id)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersion is the version of the web API (JSON and gRPC). It only
+// increases when something is removed or changes incompatibly; additions are
+// advertised as capabilities instead, so clients check for those.
+const ProtocolVersion = 1
+
+// MinProtocolVersion is the oldest client protocol version still served.
+const MinProtocolVersion = 1
+
+// Negotiation headers. Both are also read as gRPC metadata (lowercased).
+const (
+	// ProtocolHeader carries the protocol version: the server's on every
+	// response, the client's (optional) on requests.
+	ProtocolHeader = "X-Agent-Deck-Protocol"
+	// RequireHeader lists the capabilities a request needs, comma-separated.
+	// Requests needing one the server lacks fail with UNSUPPORTED_CAPABILITY.
+	RequireHeader = "X-Agent-Deck-Require"
+)
+
+// Error codes of failed negotiation.
+const (
+	ErrCodeUnsupportedCapability = "UNSUPPORTED_CAPABILITY"
+	ErrCodeUnsupportedProtocol   = "UNSUPPORTED_PROTOCOL"
+)
+
+// Capabilities advertised by the server. Batch ops are "batch.<op>".
+const (
+	CapMenu          = "menu"
+	CapMenuMaxAge    = "menu.max_age"
+	CapMenuEvents    = "menu.events"
+	CapSession       = "session"
+	CapBatch         = "batch"
+	CapBatchRequest  = "batch.request_id"
+	CapFeatures      = "features"
+	CapFleet         = "fleet"
+	CapConductorLoad = "conductors.load"
+	CapEmergencyStop = "emergency_stop"
+	CapTerminal      = "terminal"
+	CapPush          = "push"
+	CapGRPC          = "grpc"
+)
+
+// batchOpCapability names the capability of a batch op.
+func batchOpCapability(op string) string {
+	return CapBatch + "." + op
+}
+
+// Capabilities returns what this server supports, sorted.
+func (s *Server) Capabilities() []string {
+	caps := []string{
+		CapMenu, CapMenuMaxAge, CapMenuEvents, CapSession, CapBatch, CapBatchRequest,
+		CapFeatures, CapFleet, CapConductorLoad, CapEmergencyStop, CapTerminal,
+	}
+	for _, op := range []string{SessionOpGet, SessionOpStop, SessionOpRestart, SessionOpSend, SessionOpMacro} {
+		caps = append(caps, batchOpCapability(op))
+	}
+	if s.push != nil {
+		caps = append(caps, CapPush)
+	}
+	if s.grpcServer != nil {
+		caps = append(caps, CapGRPC)
+	}
+	sort.Strings(caps)
+	return caps
+}
+
+// negotiationError is why a request's protocol requirements can't be met.
+type negotiationError struct {
+	code    string
+	message string
+	missing []string
+}
+
+// negotiate checks a client's protocol version and required capabilities
+// (the values of ProtocolHeader and RequireHeader). Clients sending neither
+// are served as they always were.
+func (s *Server) negotiate(version string, require []string) *negotiationError {
+	if version = strings.TrimSpace(version); version != "" {
+		v, err := strconv.Atoi(version)
+		if err != nil {
+			return &negotiationError{code: "INVALID_REQUEST", message: "invalid protocol version " + strconv.Quote(version)}
+		}
+		if v < MinProtocolVersion {
+			return &negotiationError{
+				code:    ErrCodeUnsupportedProtocol,
+				message: "protocol version " + version + " is no longer supported (minimum " + strconv.Itoa(MinProtocolVersion) + ")",
+			}
+		}
+	}
+
+	var missing []string
+	if len(require) > 0 {
+		supported := make(map[string]bool)
+		for _, c := range s.Capabilities() {
+			supported[c] = true
+		}
+		for _, value := range require {
+			for _, c := range strings.Split(value, ",") {
+				if c = strings.TrimSpace(c); c != "" && !supported[c] {
+					missing = append(missing, c)
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return &negotiationError{
+			code:    ErrCodeUnsupportedCapability,
+			message: "unsupported capability: " + strings.Join(missing, ", "),
+			missing: missing,
+		}
+	}
+	return nil
+}
+
+// withNegotiation advertises the protocol version on every response and
+// rejects requests whose requirements the server can't meet.
+func (s *Server) withNegotiation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ProtocolHeader, strconv.Itoa(ProtocolVersion))
+		if nerr := s.negotiate(r.Header.Get(ProtocolHeader), r.Header.Values(RequireHeader)); nerr != nil {
+			writeJSON(w, http.StatusBadRequest, apiErrorResponse{Error: apiError{
+				Code:         nerr.code,
+				Message:      nerr.message,
+				Capabilities: nerr.missing,
+			}})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,142 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/web/deckpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealthzAdvertisesCapabilities(t *testing.T) {
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile"})
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if got := rr.Header().Get(ProtocolHeader); got != "1" {
+		t.Errorf("%s = %q, want 1", ProtocolHeader, got)
+	}
+	var body struct {
+		ProtocolVersion int      `json:"protocolVersion"`
+		Capabilities    []string `json:"capabilities"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.ProtocolVersion != ProtocolVersion {
+		t.Errorf("protocolVersion = %d", body.ProtocolVersion)
+	}
+	caps := strings.Join(body.Capabilities, " ")
+	for _, want := range []string{CapMenu, CapMenuEvents, "batch.send", "batch.macro"} {
+		if !strings.Contains(caps, want) {
+			t.Errorf("capabilities %v should include %q", body.Capabilities, want)
+		}
+	}
+	if strings.Contains(caps, CapGRPC) {
+		t.Errorf("grpc should only be advertised when served: %v", body.Capabilities)
+	}
+}
+
+func TestRequiredCapabilities(t *testing.T) {
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile"})
+	srv.menuData = &countingMenuDataLoader{}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		wantCode string
+		wantCaps []string
+	}{
+		{name: "no requirements"},
+		{name: "supported", headers: map[string]string{RequireHeader: "menu, menu.max_age", ProtocolHeader: "2"}},
+		{name: "missing", headers: map[string]string{RequireHeader: "menu,menu.filters,batch.pause"},
+			wantCode: ErrCodeUnsupportedCapability, wantCaps: []string{"menu.filters", "batch.pause"}},
+		{name: "old protocol", headers: map[string]string{ProtocolHeader: "0"}, wantCode: ErrCodeUnsupportedProtocol},
+		{name: "bad protocol", headers: map[string]string{ProtocolHeader: "v1"}, wantCode: "INVALID_REQUEST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/menu", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rr, req)
+
+			if tt.wantCode == "" {
+				if rr.Code != http.StatusOK {
+					t.Fatalf("status = %d, body %s", rr.Code, rr.Body.String())
+				}
+				return
+			}
+			var resp apiErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if rr.Code != http.StatusBadRequest || resp.Error.Code != tt.wantCode {
+				t.Fatalf("got %d %+v, want 400 %s", rr.Code, resp.Error, tt.wantCode)
+			}
+			if strings.Join(resp.Error.Capabilities, ",") != strings.Join(tt.wantCaps, ",") {
+				t.Errorf("capabilities = %v, want %v", resp.Error.Capabilities, tt.wantCaps)
+			}
+		})
+	}
+}
+
+func TestFetchMenuSnapshotUnsupportedCapability(t *testing.T) {
+	// A server that dropped max_age answers the CLI's requirement with a typed error
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(RequireHeader) != CapMenuMaxAge {
+			t.Errorf("%s = %q", RequireHeader, r.Header.Get(RequireHeader))
+		}
+		writeJSON(w, http.StatusBadRequest, apiErrorResponse{Error: apiError{
+			Code: ErrCodeUnsupportedCapability, Message: "unsupported", Capabilities: []string{CapMenuMaxAge},
+		}})
+	}))
+	defer ts.Close()
+
+	ep := &session.WebEndpoint{Addr: strings.TrimPrefix(ts.URL, "http://")}
+	_, err := FetchMenuSnapshot(context.Background(), ep, time.Second)
+	var capErr *UnsupportedCapabilityError
+	if !errors.As(err, &capErr) || len(capErr.Capabilities) != 1 || capErr.Capabilities[0] != CapMenuMaxAge {
+		t.Fatalf("expected UnsupportedCapabilityError for menu.max_age, got %v", err)
+	}
+}
+
+func TestGRPCNegotiation(t *testing.T) {
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "test-profile", GRPCListenAddr: "127.0.0.1:0"})
+	client := grpcTestClient(t, srv)
+
+	health, err := client.Health(context.Background(), &deckpb.HealthRequest{})
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if health.GetProtocolVersion() != ProtocolVersion || !strings.Contains(strings.Join(health.GetCapabilities(), " "), CapGRPC) {
+		t.Errorf("unexpected negotiation fields: %d %v", health.GetProtocolVersion(), health.GetCapabilities())
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-agent-deck-require", "menu,menu.filters")
+	_, err = client.GetMenu(ctx, &deckpb.GetMenuRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", err)
+	}
+	var info *errdetails.ErrorInfo
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	if info == nil || info.GetReason() != ErrCodeUnsupportedCapability || info.GetMetadata()["capabilities"] != "menu.filters" {
+		t.Errorf("expected UNSUPPORTED_CAPABILITY ErrorInfo for menu.filters, got %+v", info)
+	}
+}
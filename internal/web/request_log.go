@@ -0,0 +1,84 @@
+package web
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// RequestLog remembers the responses of batches sent with a request ID, so a
+// replayed batch gets its first response instead of being applied twice.
+type RequestLog interface {
+	Lookup(id string) ([]byte, bool)
+	Record(id string, response []byte)
+}
+
+// maxMemoryRequests bounds memoryRequestLog; the oldest half is dropped
+// when it fills up.
+const maxMemoryRequests = 1024
+
+// stateRequestLog keeps responses in the profile's state database, so they
+// survive server restarts, and in memory when there is none (tests, or a
+// database that failed to open).
+type stateRequestLog struct {
+	memory memoryRequestLog
+}
+
+func (l *stateRequestLog) Lookup(id string) ([]byte, bool) {
+	if db := statedb.GetGlobal(); db != nil {
+		response, found, err := db.AppliedRequest(id)
+		if err == nil {
+			return response, found
+		}
+		logging.ForComponent(logging.CompWeb).Warn("request_log_read_failed",
+			slog.String("error", err.Error()))
+	}
+	return l.memory.Lookup(id)
+}
+
+func (l *stateRequestLog) Record(id string, response []byte) {
+	if db := statedb.GetGlobal(); db != nil {
+		err := db.RecordAppliedRequest(id, response, time.Now())
+		if err == nil {
+			return
+		}
+		logging.ForComponent(logging.CompWeb).Warn("request_log_write_failed",
+			slog.String("error", err.Error()))
+	}
+	l.memory.Record(id, response)
+}
+
+type memoryRequestLog struct {
+	mu        sync.Mutex
+	responses map[string][]byte
+	order     []string
+}
+
+func (l *memoryRequestLog) Lookup(id string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	response, ok := l.responses[id]
+	return response, ok
+}
+
+func (l *memoryRequestLog) Record(id string, response []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.responses == nil {
+		l.responses = make(map[string][]byte)
+	}
+	if _, ok := l.responses[id]; !ok {
+		l.order = append(l.order, id)
+	}
+	l.responses[id] = response
+	if len(l.order) > maxMemoryRequests {
+		drop := len(l.order) / 2
+		for _, old := range l.order[:drop] {
+			delete(l.responses, old)
+		}
+		l.order = append([]string(nil), l.order[drop:]...)
+	}
+}
@@ -8,24 +8,57 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/power"
 	"github.com/asheshgoplani/agent-deck/internal/session"
+	"google.golang.org/grpc"
 )
 
 // Config defines runtime options for the web server.
 type Config struct {
-	ListenAddr          string
-	Profile             string
-	ReadOnly            bool
-	Token               string
-	MenuData            MenuDataLoader
+	ListenAddr    string
+	Profile       string
+	ReadOnly      bool
+	Token         string
+	MenuData      MenuDataLoader
+	Mutator       SessionMutator
+	ConductorLoad ConductorLoadReporter
+	Fleet         FleetSummaryLoader
+	// Requests records the responses of batches sent with a request ID
+	// (default: the profile's state database)
+	Requests            RequestLog
 	PushVAPIDPublicKey  string
 	PushVAPIDPrivateKey string
 	PushVAPIDSubject    string
 	PushTestInterval    time.Duration
+
+	// Listener, when set, is served instead of listening on ListenAddr.
+	// Used for systemd socket activation (see ActivationListeners).
+	Listener net.Listener
+
+	// IdleTimeout stops the server after no requests for this long (0 = never).
+	// Intended for socket-activated daemons that systemd restarts on demand.
+	IdleTimeout time.Duration
+
+	// GRPCListenAddr, when set, also serves the API over gRPC on this address
+	// (see proto/agentdeck/v1/deck.proto).
+	GRPCListenAddr string
+
+	// MetricsListenAddr, when set, serves Prometheus metrics at /metrics on
+	// this address.
+	MetricsListenAddr string
+	// BridgeUptime reports the conductor bridge daemon's uptime for metrics
+	// (default: session.BridgeDaemonUptime).
+	BridgeUptime func() (time.Duration, bool)
+
+	// WatchPower follows system suspend and resume (see session.WatchPower)
+	// and pushes a fresh menu on resume. Set for web --headless; the TUI
+	// watches on its own.
+	WatchPower bool
 }
 
 // MenuDataLoader provides menu snapshots for web APIs and push notifications.
@@ -35,13 +68,26 @@ type MenuDataLoader interface {
 
 // Server wraps an HTTP server for Agent Deck web mode.
 type Server struct {
-	cfg         Config
-	httpServer  *http.Server
-	menuData    MenuDataLoader
-	push        pushServiceAPI
-	baseCtx     context.Context
-	cancelBase  context.CancelFunc
-	hookWatcher *session.StatusFileWatcher
+	cfg           Config
+	httpServer    *http.Server
+	grpcServer    *grpc.Server
+	metricsServer *http.Server
+	bridgeUptime  func() (time.Duration, bool)
+	startedAt     time.Time
+	menuData      MenuDataLoader
+	mutator       SessionMutator
+	conductorLoad ConductorLoadReporter
+	fleet         FleetSummaryLoader
+	push          pushServiceAPI
+	baseCtx       context.Context
+	cancelBase    context.CancelFunc
+	hookWatcher   *session.StatusFileWatcher
+	idle          *idleTracker
+	requests      RequestLog
+	requestMu     sync.Mutex
+
+	snapshotCacheMu sync.Mutex
+	snapshotCache   *MenuSnapshot
 
 	menuSubscribersMu sync.Mutex
 	menuSubscribers   map[chan struct{}]struct{}
@@ -57,10 +103,37 @@ func NewServer(cfg Config) *Server {
 	if menuData == nil {
 		menuData = NewSessionDataService(cfg.Profile)
 	}
+	mutator := cfg.Mutator
+	if mutator == nil {
+		mutator = NewStorageSessionMutator(cfg.Profile)
+	}
+	conductorLoad := cfg.ConductorLoad
+	if conductorLoad == nil {
+		conductorLoad = NewProfileConductorLoad(cfg.Profile)
+	}
+	fleet := cfg.Fleet
+	if fleet == nil {
+		fleet = sessionFleetLoader{}
+	}
+	requests := cfg.Requests
+	if requests == nil {
+		requests = &stateRequestLog{}
+	}
+	bridgeUptime := cfg.BridgeUptime
+	if bridgeUptime == nil {
+		bridgeUptime = session.BridgeDaemonUptime
+	}
 
 	s := &Server{
 		cfg:             cfg,
 		menuData:        menuData,
+		mutator:         mutator,
+		conductorLoad:   conductorLoad,
+		fleet:           fleet,
+		requests:        requests,
+		bridgeUptime:    bridgeUptime,
+		startedAt:       time.Now(),
+		idle:            newIdleTracker(),
 		menuSubscribers: make(map[chan struct{}]struct{}),
 	}
 	s.baseCtx, s.cancelBase = context.WithCancel(context.Background())
@@ -88,12 +161,21 @@ func NewServer(cfg Config) *Server {
 			"profile":  cfg.Profile,
 			"readOnly": cfg.ReadOnly,
 			"time":     time.Now().UTC().Format(time.RFC3339),
+
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    s.Capabilities(),
 		}
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(resp)
 	})
 	mux.HandleFunc("/api/menu", s.handleMenu)
+	mux.HandleFunc("/api/menu/stream", s.handleMenuStream)
 	mux.HandleFunc("/api/session/", s.handleSessionByID)
+	mux.HandleFunc("/api/batch", s.handleBatch)
+	mux.HandleFunc("/api/conductors/load", s.handleConductorLoad)
+	mux.HandleFunc("/api/fleet", s.handleFleet)
+	mux.HandleFunc("/api/features", s.handleFeatures)
+	mux.HandleFunc("/api/emergency-stop", s.handleEmergencyStop)
 	mux.HandleFunc("/api/push/config", s.handlePushConfig)
 	mux.HandleFunc("/api/push/subscribe", s.handlePushSubscribe)
 	mux.HandleFunc("/api/push/unsubscribe", s.handlePushUnsubscribe)
@@ -101,7 +183,13 @@ func NewServer(cfg Config) *Server {
 	mux.HandleFunc("/events/menu", s.handleMenuEvents)
 	mux.HandleFunc("/ws/session/", s.handleSessionWS)
 
-	handler := withRecover(mux)
+	handler := s.idle.wrap(withRecover(s.withNegotiation(mux)))
+	if cfg.GRPCListenAddr != "" {
+		s.grpcServer = s.newGRPCServer()
+	}
+	if cfg.MetricsListenAddr != "" {
+		s.metricsServer = s.newMetricsServer()
+	}
 
 	s.httpServer = &http.Server{
 		Addr:              cfg.ListenAddr,
@@ -116,9 +204,22 @@ func NewServer(cfg Config) *Server {
 
 // Addr returns the listen address.
 func (s *Server) Addr() string {
+	if s.cfg.Listener != nil {
+		return s.cfg.Listener.Addr().String()
+	}
 	return s.httpServer.Addr
 }
 
+// GRPCAddr returns the gRPC listen address, or "" when gRPC is off.
+func (s *Server) GRPCAddr() string {
+	return s.cfg.GRPCListenAddr
+}
+
+// MetricsAddr returns the metrics listen address, or "" when metrics are off.
+func (s *Server) MetricsAddr() string {
+	return s.cfg.MetricsListenAddr
+}
+
 // Handler returns the configured HTTP handler (used by tests).
 func (s *Server) Handler() http.Handler {
 	return s.httpServer.Handler
@@ -128,6 +229,16 @@ func (s *Server) Handler() http.Handler {
 // Returns nil on graceful shutdown.
 func (s *Server) Start() error {
 	webLog := logging.ForComponent(logging.CompWeb)
+	if s.grpcServer != nil {
+		if err := s.serveGRPC(); err != nil {
+			return fmt.Errorf("grpc listen: %w", err)
+		}
+	}
+	if s.metricsServer != nil {
+		if err := s.serveMetrics(); err != nil {
+			return fmt.Errorf("metrics listen: %w", err)
+		}
+	}
 	if watcher, err := session.NewStatusFileWatcher(func() {
 		s.notifyMenuChanged()
 		if s.push != nil {
@@ -143,7 +254,36 @@ func (s *Server) Start() error {
 	if s.push != nil {
 		s.push.Start(s.baseCtx)
 	}
-	err := s.httpServer.ListenAndServe()
+	if s.cfg.IdleTimeout > 0 {
+		go s.exitWhenIdle(s.cfg.IdleTimeout)
+	}
+	if !s.cfg.ReadOnly {
+		go s.replayOfflineQueue()
+	}
+	if s.cfg.WatchPower {
+		go session.WatchPower(s.baseCtx, func(power.Sleep) { s.notifyMenuChanged() })
+	}
+
+	var err error
+	if s.cfg.Listener != nil {
+		// Socket-activated: the endpoint record belongs to the socket unit.
+		err = s.httpServer.Serve(s.cfg.Listener)
+	} else if ln, lerr := net.Listen("tcp", s.cfg.ListenAddr); lerr != nil {
+		// Bind before recording the endpoint: a server that cannot bind must
+		// not replace the record of the one already listening.
+		err = lerr
+	} else {
+		pid := os.Getpid()
+		if werr := session.WriteWebEndpoint(s.cfg.Profile, session.WebEndpoint{
+			Addr:  s.cfg.ListenAddr,
+			Token: s.cfg.Token,
+			PID:   pid,
+		}); werr != nil {
+			webLog.Warn("web_endpoint_write_failed", slog.String("error", werr.Error()))
+		}
+		err = s.httpServer.Serve(ln)
+		session.RemoveWebEndpoint(s.cfg.Profile, pid)
+	}
 	if s.hookWatcher != nil {
 		s.hookWatcher.Stop()
 		s.hookWatcher = nil
@@ -154,6 +294,25 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// replayOfflineQueue applies batches clients queued while the server was
+// down. Clients replaying the same batches concurrently are harmless: each
+// batch carries its request ID.
+func (s *Server) replayOfflineQueue() {
+	webLog := logging.ForComponent(logging.CompWeb)
+	queue, err := NewOfflineQueue(s.cfg.Profile)
+	if err != nil {
+		return
+	}
+	replayed, err := queue.ReplayLocal(s)
+	if err != nil {
+		webLog.Warn("offline_queue_replay_failed", slog.String("error", err.Error()))
+	}
+	if len(replayed) > 0 {
+		webLog.Info("offline_queue_replayed", slog.Int("batches", len(replayed)))
+		s.notifyMenuChanged()
+	}
+}
+
 // Shutdown gracefully stops the server.
 func (s *Server) Shutdown(ctx context.Context) error {
 	if s.cancelBase != nil {
@@ -164,6 +323,12 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.hookWatcher.Stop()
 		s.hookWatcher = nil
 	}
+	if s.grpcServer != nil {
+		s.stopGRPC(ctx)
+	}
+	if s.metricsServer != nil {
+		_ = s.metricsServer.Shutdown(ctx)
+	}
 
 	err := s.httpServer.Shutdown(ctx)
 	if err == nil {
@@ -183,6 +348,33 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return err
 }
 
+// exitWhenIdle shuts the server down once it has been idle for timeout.
+// Start then returns nil, letting a socket-activated daemon exit cleanly.
+func (s *Server) exitWhenIdle(timeout time.Duration) {
+	interval := timeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.baseCtx.Done():
+			return
+		case now := <-ticker.C:
+			if s.idle.idleFor(now) < timeout {
+				continue
+			}
+			logging.ForComponent(logging.CompWeb).Info("idle_exit",
+				slog.Duration("idle_timeout", timeout))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_ = s.Shutdown(ctx)
+			cancel()
+			return
+		}
+	}
+}
+
 func withRecover(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
@@ -1,11 +1,15 @@
 package web
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
 func TestHealthzEndpoint(t *testing.T) {
@@ -32,6 +36,33 @@ func TestHealthzEndpoint(t *testing.T) {
 	}
 }
 
+func TestStartBindFailureKeepsEndpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Another server already listens and owns the endpoint record
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	live := session.WebEndpoint{Addr: ln.Addr().String(), Token: "live", PID: os.Getppid()}
+	if err := session.WriteWebEndpoint("test", live); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(Config{ListenAddr: ln.Addr().String(), Profile: "test", Token: "new"})
+	if err := srv.Start(); err == nil {
+		t.Fatal("Start() on a taken port should fail")
+	}
+	ep, err := session.ReadWebEndpoint("test")
+	if err != nil {
+		t.Fatalf("endpoint record lost: %v", err)
+	}
+	if *ep != live {
+		t.Errorf("endpoint = %+v, want the live server's %+v", *ep, live)
+	}
+}
+
 func TestHealthzMethodNotAllowed(t *testing.T) {
 	srv := NewServer(Config{
 		ListenAddr: "127.0.0.1:0",
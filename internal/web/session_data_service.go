@@ -62,6 +62,11 @@ type MenuSession struct {
 	TmuxSession     string         `json:"tmuxSession,omitempty"`
 	CreatedAt       time.Time      `json:"createdAt"`
 	LastAccessedAt  time.Time      `json:"lastAccessedAt,omitempty"`
+	StateSince      time.Time      `json:"stateSince,omitempty"`
+	BusyTodaySecs   int64          `json:"busyTodaySeconds,omitempty"`
+	Pinned          bool           `json:"pinned,omitempty"`
+	// Features are the session's own feature flag overrides
+	Features map[string]bool `json:"features,omitempty"`
 }
 
 type storageLoader interface {
@@ -147,6 +152,10 @@ func toMenuSession(inst *session.Instance) *MenuSession {
 		TmuxSession:     tmuxName,
 		CreatedAt:       inst.CreatedAt,
 		LastAccessedAt:  inst.LastAccessedAt,
+		StateSince:      inst.StateSince(),
+		BusyTodaySecs:   int64(inst.BusyToday().Seconds()),
+		Pinned:          session.GetSortSettings().IsPinned(inst),
+		Features:        inst.Features,
 	}
 }
 
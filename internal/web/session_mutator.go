@@ -0,0 +1,127 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// Batch operation names accepted by /api/batch.
+const (
+	SessionOpGet     = "get"
+	SessionOpStop    = "stop"
+	SessionOpRestart = "restart"
+	SessionOpSend    = "send"
+	SessionOpMacro   = "macro"
+)
+
+var (
+	errSessionNotFound   = errors.New("session not found")
+	errSessionNotRunning = errors.New("session is not running")
+	errDeckStopped       = errors.New("the deck is emergency-stopped")
+)
+
+// SessionOp is one state-changing request in a batch.
+type SessionOp struct {
+	Op   string `json:"op"`
+	ID   string `json:"id"`
+	Text string `json:"text,omitempty"`
+	// Macro names the tool macro to run for SessionOpMacro
+	Macro string `json:"macro,omitempty"`
+}
+
+// SessionMutator applies batched session mutations. Implementations should
+// load and save storage once per call rather than once per op.
+type SessionMutator interface {
+	// Mutate applies ops in order and returns one error (or nil) per op.
+	Mutate(ops []SessionOp) []error
+}
+
+type storageSessionMutator struct {
+	profile string
+}
+
+// NewStorageSessionMutator returns a SessionMutator backed by the profile's storage.
+func NewStorageSessionMutator(profile string) SessionMutator {
+	return &storageSessionMutator{profile: session.GetEffectiveProfile(profile)}
+}
+
+func (m *storageSessionMutator) Mutate(ops []SessionOp) []error {
+	errs := make([]error, len(ops))
+	fail := func(err error) []error {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	storage, err := session.NewStorageWithProfile(m.profile)
+	if err != nil {
+		return fail(fmt.Errorf("open storage for profile %q: %w", m.profile, err))
+	}
+	defer func() { _ = storage.Close() }()
+
+	instances, _, err := storage.LoadWithGroups()
+	if err != nil {
+		return fail(fmt.Errorf("load sessions: %w", err))
+	}
+	byID := make(map[string]*session.Instance, len(instances))
+	for _, inst := range instances {
+		byID[inst.ID] = inst
+	}
+
+	stopped := session.LoadEmergencyStop() != nil
+	changed := false
+	for i, op := range ops {
+		inst := byID[op.ID]
+		if inst == nil {
+			errs[i] = errSessionNotFound
+			continue
+		}
+		// Stopping sessions stays allowed; nothing may set them working again
+		if stopped && op.Op != SessionOpStop {
+			errs[i] = errDeckStopped
+			continue
+		}
+		switch op.Op {
+		case SessionOpStop:
+			if !inst.Exists() {
+				errs[i] = errSessionNotRunning
+				continue
+			}
+			errs[i] = inst.Kill()
+			changed = true
+		case SessionOpRestart:
+			errs[i] = inst.Restart()
+			changed = true
+		case SessionOpSend:
+			tmuxSess := inst.GetTmuxSession()
+			if tmuxSess == nil || !inst.Exists() {
+				errs[i] = errSessionNotRunning
+				continue
+			}
+			errs[i] = tmuxSess.SendKeysAndEnter(op.Text)
+		case SessionOpMacro:
+			if !inst.Exists() {
+				errs[i] = errSessionNotRunning
+				continue
+			}
+			errs[i] = inst.RunMacro(op.Macro)
+		default:
+			errs[i] = fmt.Errorf("unsupported op %q", op.Op)
+		}
+	}
+
+	if changed {
+		if err := storage.SaveWithGroups(instances, session.NewGroupTree(instances)); err != nil {
+			saveErr := fmt.Errorf("save session state: %w", err)
+			for i := range errs {
+				if errs[i] == nil && ops[i].Op != SessionOpSend && ops[i].Op != SessionOpMacro {
+					errs[i] = saveErr
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -11,6 +11,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/deck"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
 	"github.com/creack/pty"
 	"github.com/gorilla/websocket"
 )
@@ -191,6 +193,12 @@ func tmuxSessionExists(name string) (bool, error) {
 }
 
 func tmuxCommand(args ...string) *exec.Cmd {
+	// A named deck runs its own tmux server, whatever $TMUX points at
+	if deck.TmuxSocket() != "" {
+		cmd := tmux.Command(args...)
+		cmd.Env = environWithoutTMUX(os.Environ())
+		return cmd
+	}
 	socketPath, hasSocket := tmuxSocketFromEnv()
 
 	finalArgs := args